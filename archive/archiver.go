@@ -0,0 +1,297 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package archive moves the fired one time schedules and the runs of the recurring schedules out of Cassandra into
+// object storage once their time bucket is old enough, and reads them back by id.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/logger"
+	p "github.com/myntra/goscheduler/monitoring"
+	"github.com/myntra/goscheduler/store"
+)
+
+const secondsPerDay = 60 * 60 * 24
+
+// ValidateConfig checks the archive configuration of the node
+func ValidateConfig(config conf.ArchiveConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	switch {
+	case config.AfterDays < 1:
+		return fmt.Errorf("archive after %d days must be at least a day", config.AfterDays)
+	case config.Store == FileStore && config.Directory == "":
+		return errors.New("archive directory is required for the file store")
+	case config.Store == HttpStore && config.Url == "":
+		return errors.New("archive url is required for the http store")
+	case config.Routines < 1 || config.QueueSize < 1:
+		return errors.New("archive routines and queue size must be at least 1")
+	case config.IndexRetentionDays < 0:
+		return errors.New("archive index retention cannot be negative")
+	}
+	_, err := NewObjectStore(config)
+	return err
+}
+
+// bucket is a time bucket of a partition of an app
+type bucket struct {
+	appId       string
+	partitionId int
+	timeBucket  time.Time
+}
+
+// Archiver archives the time buckets AfterDays old of the partitions polled by the node: the schedules of a bucket
+// are written with their status to a single object, indexed by id and deleted from Cassandra.
+type Archiver struct {
+	config      conf.ArchiveConfig
+	appConfig   conf.AppLevelConfiguration
+	objects     ObjectStore
+	clusterDao  dao.ClusterDao
+	scheduleDao dao.ScheduleDao
+	monitor     p.Monitor
+	buckets     chan bucket
+
+	mu       sync.Mutex
+	archived map[string]time.Time // latest time bucket queued by app partition
+}
+
+var (
+	mu       sync.RWMutex
+	archiver *Archiver
+)
+
+// Default returns the archiver of the node, nil unless archiving is enabled
+func Default() *Archiver {
+	mu.RLock()
+	defer mu.RUnlock()
+	return archiver
+}
+
+// SetDefault replaces the archiver of the node
+func SetDefault(a *Archiver) {
+	mu.Lock()
+	defer mu.Unlock()
+	archiver = a
+}
+
+func NewArchiver(config *conf.Configuration, objects ObjectStore, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor p.Monitor) *Archiver {
+	return &Archiver{
+		config:      config.ArchiveConfig,
+		appConfig:   config.AppLevelConfiguration,
+		objects:     objects,
+		clusterDao:  clusterDao,
+		scheduleDao: scheduleDao,
+		monitor:     monitor,
+		buckets:     make(chan bucket, config.ArchiveConfig.QueueSize),
+		archived:    make(map[string]time.Time),
+	}
+}
+
+// Start starts the routines archiving the queued time buckets
+func (a *Archiver) Start() {
+	for i := 0; i < a.config.Routines; i++ {
+		go func() {
+			for b := range a.buckets {
+				if _, err := a.Archive(b.appId, b.partitionId, b.timeBucket); err != nil {
+					logger.Errorf("Archiving time bucket %v of app %s partition %d failed with error %s", b.timeBucket, b.appId, b.partitionId, err.Error())
+					a.recordFailure(b.appId, "error")
+				}
+			}
+		}()
+	}
+}
+
+// Polled queues the time bucket AfterDays older than the polled one. A bucket is queued once however often its
+// partition is polled, and is left to expire with its TTL when the queue is full.
+func (a *Archiver) Polled(appId string, partitionId int, timeBucket time.Time) {
+	if a == nil {
+		return
+	}
+
+	old := timeBucket.Add(-time.Duration(a.config.AfterDays) * secondsPerDay * time.Second)
+	key := appId + constants.PollerKeySep + strconv.Itoa(partitionId)
+
+	a.mu.Lock()
+	if last, ok := a.archived[key]; ok && !old.After(last) {
+		a.mu.Unlock()
+		return
+	}
+	a.archived[key] = old
+	a.mu.Unlock()
+
+	select {
+	case a.buckets <- bucket{appId: appId, partitionId: partitionId, timeBucket: old}:
+	default:
+		logger.Errorf("Archive queue full, time bucket %v of app %s partition %d is left to expire", old, appId, partitionId)
+		a.recordFailure(appId, "queue_full")
+	}
+}
+
+// Archive moves the schedules of a time bucket of a partition to the object store and returns how many were moved.
+// The schedules of apps keeping their fired schedules for AfterDays or less expire before they are archived and are
+// left alone.
+func (a *Archiver) Archive(appId string, partitionId int, timeBucket time.Time) (int, error) {
+	app, err := a.clusterDao.GetApp(appId)
+	if err != nil {
+		return 0, err
+	}
+	if app.GetBufferTTL(a.appConfig.FiredScheduleRetentionPeriod) <= a.config.AfterDays*secondsPerDay {
+		return 0, nil
+	}
+
+	schedules, err := a.readBucket(appId, partitionId, timeBucket)
+	if err != nil || len(schedules) == 0 {
+		return 0, err
+	}
+	if schedules, err = a.scheduleDao.OptimizedEnrichSchedule(schedules); err != nil {
+		return 0, err
+	}
+
+	data, err := store.EncodeArchive(schedules, clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	key := store.ArchiveObjectKey(appId, partitionId, timeBucket)
+	if err = a.objects.Put(key, data); err != nil {
+		return 0, err
+	}
+	if err = a.scheduleDao.IndexArchivedSchedules(store.IndexArchive(schedules, key), a.config.IndexRetentionDays*secondsPerDay); err != nil {
+		return 0, err
+	}
+	if err = a.scheduleDao.DeleteArchivedBucket(appId, partitionId, timeBucket, schedules); err != nil {
+		return 0, err
+	}
+
+	logger.Infof("Archived %d schedules of time bucket %v of app %s partition %d to %s", len(schedules), timeBucket, appId, partitionId, key)
+	if a.monitor != nil {
+		a.monitor.IncCounter(constants.ArchivedScheduleCount, map[string]string{"appId": appId}, len(schedules))
+	}
+	return len(schedules), nil
+}
+
+// readBucket reads all the schedules of a time bucket of a partition page by page
+func (a *Archiver) readBucket(appId string, partitionId int, timeBucket time.Time) ([]store.Schedule, error) {
+	var schedules []store.Schedule
+	var pageState []byte
+
+	for {
+		iter := a.scheduleDao.GetSchedulesForEntity(appId, partitionId, timeBucket, pageState)
+		_map := make(map[string]interface{})
+		for iter.MapScan(_map) {
+			var schedule store.Schedule
+			if err := schedule.CreateScheduleFromCassandraMap(_map); err != nil {
+				iter.Close()
+				return nil, err
+			}
+			schedules = append(schedules, schedule)
+			_map = make(map[string]interface{})
+		}
+
+		pageState = iter.PageState()
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+		if len(pageState) == 0 {
+			return schedules, nil
+		}
+	}
+}
+
+// Lookup reads an archived schedule back from the object store.
+// Returns gocql.ErrNotFound if the schedule was not archived
+func (a *Archiver) Lookup(uuid gocql.UUID) (store.Schedule, error) {
+	if a == nil {
+		return store.Schedule{}, gocql.ErrNotFound
+	}
+
+	archived, err := a.scheduleDao.GetArchivedSchedule(uuid)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	records, err := a.read(archived.ObjectKey)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+	schedule, ok := store.FindArchived(records, uuid)
+	if !ok {
+		return store.Schedule{}, gocql.ErrNotFound
+	}
+	return schedule, nil
+}
+
+// LookupRuns reads the latest limit archived runs of a recurring schedule back from the object store, latest first.
+// The runs archived to the same object are read with a single request.
+func (a *Archiver) LookupRuns(parentScheduleId gocql.UUID, limit int) ([]store.Schedule, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	archived, err := a.scheduleDao.GetArchivedRuns(parentScheduleId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string][]store.ArchiveRecord)
+	var runs []store.Schedule
+	for _, run := range archived {
+		records, ok := objects[run.ObjectKey]
+		if !ok {
+			if records, err = a.read(run.ObjectKey); err != nil {
+				return nil, err
+			}
+			objects[run.ObjectKey] = records
+		}
+
+		if schedule, ok := store.FindArchived(records, run.ScheduleId); ok {
+			runs = append(runs, schedule)
+		}
+	}
+	return runs, nil
+}
+
+func (a *Archiver) read(key string) ([]store.ArchiveRecord, error) {
+	data, err := a.objects.Get(key)
+	if err == ErrObjectNotFound {
+		return nil, gocql.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return store.DecodeArchive(data)
+}
+
+func (a *Archiver) recordFailure(appId string, reason string) {
+	if a.monitor != nil {
+		a.monitor.IncCounter(constants.ArchiveFailureCount, map[string]string{"appId": appId, "reason": reason}, 1)
+	}
+}
@@ -0,0 +1,225 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/store"
+)
+
+type fakeIter struct {
+	rows []map[string]interface{}
+}
+
+func (i *fakeIter) Close() error { return nil }
+
+func (i *fakeIter) Scan(...interface{}) bool { return false }
+
+func (i *fakeIter) PageState() []byte { return nil }
+
+func (i *fakeIter) MapScan(m map[string]interface{}) bool {
+	if len(i.rows) == 0 {
+		return false
+	}
+	for key, value := range i.rows[0] {
+		m[key] = value
+	}
+	i.rows = i.rows[1:]
+	return true
+}
+
+// fakeScheduleDao keeps a time bucket of schedules and the archive index in memory
+type fakeScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	rows    []map[string]interface{}
+	index   map[gocql.UUID]store.ArchivedSchedule
+	deleted bool
+}
+
+func (d *fakeScheduleDao) GetSchedulesForEntity(appId string, partitionId int, timeBucket time.Time, pageState []byte) db_wrapper.IterInterface {
+	if d.deleted {
+		return &fakeIter{}
+	}
+	return &fakeIter{rows: d.rows}
+}
+
+func (d *fakeScheduleDao) OptimizedEnrichSchedule(schedules []store.Schedule) ([]store.Schedule, error) {
+	for i := range schedules {
+		schedules[i].Status = store.Success
+	}
+	return schedules, nil
+}
+
+func (d *fakeScheduleDao) IndexArchivedSchedules(archived []store.ArchivedSchedule, ttl int) error {
+	for _, schedule := range archived {
+		d.index[schedule.ScheduleId] = schedule
+	}
+	return nil
+}
+
+func (d *fakeScheduleDao) DeleteArchivedBucket(appId string, partitionId int, timeBucket time.Time, schedules []store.Schedule) error {
+	d.deleted = true
+	return nil
+}
+
+func (d *fakeScheduleDao) GetArchivedSchedule(uuid gocql.UUID) (store.ArchivedSchedule, error) {
+	if archived, ok := d.index[uuid]; ok {
+		return archived, nil
+	}
+	return store.ArchivedSchedule{}, gocql.ErrNotFound
+}
+
+func (d *fakeScheduleDao) GetArchivedRuns(parentScheduleId gocql.UUID, limit int) ([]store.ArchivedSchedule, error) {
+	var runs []store.ArchivedSchedule
+	for _, archived := range d.index {
+		if archived.ParentScheduleId == parentScheduleId && len(runs) < limit {
+			runs = append(runs, archived)
+		}
+	}
+	return runs, nil
+}
+
+func (d *fakeScheduleDao) add(scheduleTime time.Time, parentScheduleId gocql.UUID) gocql.UUID {
+	id := gocql.TimeUUID()
+	d.rows = append(d.rows, map[string]interface{}{
+		"app_id":              "test",
+		"partition_id":        0,
+		"callback_type":       constants.DefaultCallback,
+		"callback_details":    `{"url":"http://127.0.0.1/cb","method":"POST"}`,
+		"payload":             "{}",
+		"schedule_time_group": scheduleTime.Truncate(time.Minute),
+		"schedule_time":       scheduleTime,
+		"schedule_id":         id,
+		"parent_schedule_id":  parentScheduleId,
+	})
+	return id
+}
+
+// fakeClusterDao keeps the fired schedules of the apps for the retention of their name
+type fakeClusterDao struct {
+	dao.DummyClusterDaoImpl
+	retention map[string]int
+}
+
+func (d fakeClusterDao) GetApp(appName string) (store.App, error) {
+	return store.App{AppId: appName, Partitions: 1, Active: true, Configuration: store.Configuration{FiredScheduleRetentionPeriod: d.retention[appName]}}, nil
+}
+
+func newTestArchiver(t *testing.T, scheduleDao dao.ScheduleDao, retention map[string]int) *Archiver {
+	config := &conf.Configuration{
+		ArchiveConfig: conf.ArchiveConfig{
+			Enabled:            true,
+			AfterDays:          7,
+			Store:              FileStore,
+			Directory:          t.TempDir(),
+			IndexRetentionDays: 365,
+			Routines:           1,
+			QueueSize:          2,
+		},
+		AppLevelConfiguration: conf.AppLevelConfiguration{FiredScheduleRetentionPeriod: 30},
+	}
+	objects, err := NewObjectStore(config.ArchiveConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewArchiver(config, objects, fakeClusterDao{retention: retention}, scheduleDao, nil)
+}
+
+func TestArchiver_ArchiveAndLookup(t *testing.T) {
+	store.InitializeCallbackRegistry(nil)
+
+	timeBucket := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	scheduleDao := &fakeScheduleDao{index: make(map[gocql.UUID]store.ArchivedSchedule)}
+	parent := gocql.TimeUUID()
+	once := scheduleDao.add(timeBucket, gocql.UUID{})
+	run := scheduleDao.add(timeBucket.Add(10*time.Second), parent)
+
+	archiver := newTestArchiver(t, scheduleDao, nil)
+	archived, err := archiver.Archive("test", 0, timeBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived != 2 || !scheduleDao.deleted {
+		t.Fatalf("Expected 2 schedules archived and deleted, got %d", archived)
+	}
+
+	schedule, err := archiver.Lookup(once)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.ScheduleId != once || schedule.Status != store.Success || !schedule.Archived {
+		t.Errorf("Expected the archived schedule %s, got %+v", once, schedule)
+	}
+
+	runs, err := archiver.LookupRuns(parent, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].ScheduleId != run || runs[0].ParentScheduleId != parent {
+		t.Errorf("Expected the archived run %s, got %+v", run, runs)
+	}
+
+	if _, err = archiver.Lookup(gocql.TimeUUID()); err != gocql.ErrNotFound {
+		t.Errorf("Expected %v, got %v", gocql.ErrNotFound, err)
+	}
+}
+
+func TestArchiver_ArchiveSkipsShortRetention(t *testing.T) {
+	store.InitializeCallbackRegistry(nil)
+
+	timeBucket := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	scheduleDao := &fakeScheduleDao{index: make(map[gocql.UUID]store.ArchivedSchedule)}
+	scheduleDao.add(timeBucket, gocql.UUID{})
+
+	archiver := newTestArchiver(t, scheduleDao, map[string]int{"test": 7})
+	archived, err := archiver.Archive("test", 0, timeBucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived != 0 || scheduleDao.deleted {
+		t.Errorf("Expected the schedules expiring within 7 days to be left alone, got %d archived", archived)
+	}
+}
+
+func TestArchiver_Polled(t *testing.T) {
+	archiver := newTestArchiver(t, &fakeScheduleDao{}, nil)
+	timeBucket := time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	archiver.Polled("test", 0, timeBucket)
+	archiver.Polled("test", 0, timeBucket)
+	archiver.Polled("test", 1, timeBucket)
+
+	if len(archiver.buckets) != 2 {
+		t.Fatalf("Expected a bucket queued per partition, got %d", len(archiver.buckets))
+	}
+	if b := <-archiver.buckets; !b.timeBucket.Equal(timeBucket.Add(-7*24*time.Hour)) {
+		t.Errorf("Expected the bucket 7 days before %v, got %v", timeBucket, b.timeBucket)
+	}
+
+	var disabled *Archiver
+	disabled.Polled("test", 0, timeBucket)
+}
+
+func TestValidateConfig(t *testing.T) {
+	for _, test := range []struct {
+		config conf.ArchiveConfig
+		valid  bool
+	}{
+		{conf.ArchiveConfig{}, true},
+		{conf.ArchiveConfig{Enabled: true, AfterDays: 7, Store: FileStore, Directory: "/tmp", Routines: 1, QueueSize: 1}, true},
+		{conf.ArchiveConfig{Enabled: true, AfterDays: 0, Store: FileStore, Directory: "/tmp", Routines: 1, QueueSize: 1}, false},
+		{conf.ArchiveConfig{Enabled: true, AfterDays: 7, Store: FileStore, Routines: 1, QueueSize: 1}, false},
+		{conf.ArchiveConfig{Enabled: true, AfterDays: 7, Store: HttpStore, Routines: 1, QueueSize: 1}, false},
+		{conf.ArchiveConfig{Enabled: true, AfterDays: 7, Store: "s3", Routines: 1, QueueSize: 1}, false},
+		{conf.ArchiveConfig{Enabled: true, AfterDays: 7, Store: FileStore, Directory: "/tmp"}, false},
+	} {
+		if err := ValidateConfig(test.config); (err == nil) != test.valid {
+			t.Errorf("Expected %+v valid %v, got %v", test.config, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// Object stores the archives can be written to
+const (
+	FileStore = "file"
+	HttpStore = "http"
+)
+
+// ErrObjectNotFound is returned when the object store has no object with the key
+var ErrObjectNotFound = errors.New("archive object not found")
+
+// ObjectStore keeps the archive objects by key, e.g. in a bucket of an object storage
+type ObjectStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+}
+
+// NewObjectStore creates the object store of the configuration
+func NewObjectStore(config conf.ArchiveConfig) (ObjectStore, error) {
+	switch config.Store {
+	case FileStore:
+		return &fileStore{directory: config.Directory}, nil
+	case HttpStore:
+		return &httpStore{
+			url:     strings.TrimRight(config.Url, "/"),
+			headers: config.Headers,
+			client:  &http.Client{Timeout: time.Duration(config.TimeoutMillis) * time.Millisecond},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive store %s, expected %s or %s", config.Store, FileStore, HttpStore)
+	}
+}
+
+// fileStore keeps the objects as files under a directory, e.g. a mounted bucket
+type fileStore struct {
+	directory string
+}
+
+// path returns the file of the key, which must stay under the directory of the store
+func (f *fileStore) path(key string) (string, error) {
+	path := filepath.Join(f.directory, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(f.directory)+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive key %s is outside of the archive directory", key)
+	}
+	return path, nil
+}
+
+// Put writes the object to a temporary file renamed to the file of the key, so that readers never see a partial object
+func (f *fileStore) Put(key string, data []byte) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (f *fileStore) Get(key string) ([]byte, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+// httpStore PUTs and GETs the objects at the url of the store followed by their key, e.g. a bucket behind a gateway
+type httpStore struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (h *httpStore) Put(key string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, h.url+"/"+key, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	_, err = h.do(req)
+	return err
+}
+
+func (h *httpStore) Get(key string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, h.url+"/"+key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.do(req)
+}
+
+func (h *httpStore) do(req *http.Request) ([]byte, error) {
+	for header, value := range h.headers {
+		req.Header.Set(header, value)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("%s of archive object %s failed with status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestFileStore(t *testing.T) {
+	objects, err := NewObjectStore(conf.ArchiveConfig{Store: FileStore, Directory: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = objects.Get("app/2026/01/01/0000/0.ndjson.gz"); err != ErrObjectNotFound {
+		t.Errorf("Expected %v, got %v", ErrObjectNotFound, err)
+	}
+	if err = objects.Put("app/2026/01/01/0000/0.ndjson.gz", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := objects.Get("app/2026/01/01/0000/0.ndjson.gz")
+	if err != nil || string(data) != "data" {
+		t.Errorf("Expected data, got %s with error %v", data, err)
+	}
+
+	if err = objects.Put("../outside", []byte("data")); err == nil {
+		t.Error("Expected a key outside of the directory to be rejected")
+	}
+}
+
+func TestHttpStore(t *testing.T) {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewObjectStore(conf.ArchiveConfig{
+		Store:         HttpStore,
+		Url:           server.URL + "/bucket/",
+		Headers:       map[string]string{"Authorization": "token"},
+		TimeoutMillis: 1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = store.Get("app/0.ndjson.gz"); err != ErrObjectNotFound {
+		t.Errorf("Expected %v, got %v", ErrObjectNotFound, err)
+	}
+	if err = store.Put("app/0.ndjson.gz", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := objects["/bucket/app/0.ndjson.gz"]; !ok {
+		t.Errorf("Expected the object to be put under the url, got %v", objects)
+	}
+	data, err := store.Get("app/0.ndjson.gz")
+	if err != nil || string(data) != "data" {
+		t.Errorf("Expected data, got %s with error %v", data, err)
+	}
+
+	unauthorized, _ := NewObjectStore(conf.ArchiveConfig{Store: HttpStore, Url: server.URL, TimeoutMillis: 1000})
+	if err = unauthorized.Put("app/0.ndjson.gz", []byte("data")); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the put to fail with 403, got %v", err)
+	}
+}
+
+func TestNewObjectStoreUnknown(t *testing.T) {
+	if _, err := NewObjectStore(conf.ArchiveConfig{Store: "s3"}); err == nil {
+		t.Error("Expected an unknown store to be rejected")
+	}
+}
@@ -22,9 +22,12 @@ package cassandra
 import (
 	"errors"
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
+	p "github.com/myntra/goscheduler/monitoring"
 	"io/ioutil"
 	"strings"
 	"time"
@@ -59,12 +62,16 @@ func CassandraInit(cassandraConfig conf.CassandraConfig, cassandraInitialisation
 		if cqlStmt == "" {
 			continue
 		}
-		glog.Info("Here " + cqlStmt)
+		logger.Info("Here " + cqlStmt)
 		err = createSession.Query(cqlStmt).Exec()
 		if err != nil {
 			panic(errors.New("CQL execution failed with  " + err.Error()))
 		}
 	}
+
+	if err = migrate(createSession, cqlStmts); err != nil {
+		panic(errors.New("Schema migration failed with " + err.Error()))
+	}
 	createSession.Close()
 }
 
@@ -85,7 +92,7 @@ func withPool(cluster *gocql.ClusterConfig, config conf.CassandraConfig) *gocql.
 // In order to get the ability to mock methods we are using GetSessionInterface which provides wrapper over gocql.Session
 func GetSession(cassandraConfig conf.CassandraConfig, keyspace string) (*gocql.Session, error) {
 	hosts := getCassandraHosts(cassandraConfig.Hosts)
-	glog.Infof("Cassandra hosts to connnect %s ", hosts)
+	logger.Infof("Cassandra hosts to connnect %s ", hosts)
 
 	cluster := gocql.NewCluster(hosts...)
 	cluster.Keyspace = keyspace
@@ -99,7 +106,7 @@ func GetSession(cassandraConfig conf.CassandraConfig, keyspace string) (*gocql.S
 	session, err := cluster.CreateSession()
 
 	if err != nil {
-		glog.Error("ERROR CONNECTING TO CASSANDRA", err)
+		logger.Error("ERROR CONNECTING TO CASSANDRA", err)
 		return nil, err
 	}
 	return session, nil
@@ -113,8 +120,15 @@ func GetSession(cassandraConfig conf.CassandraConfig, keyspace string) (*gocql.S
 // the withPool and withAuthenticator functions. Finally, the function creates a
 // new session and returns a wrapper object that implements the db_wrapper.SessionInterface
 func GetSessionInterface(cassandraConfig conf.CassandraConfig, keyspace string) (db_wrapper.SessionInterface, error) {
+	return GetMonitoredSessionInterface(cassandraConfig, keyspace, nil)
+}
+
+// GetMonitoredSessionInterface returns a new Cassandra session interface like GetSessionInterface, whose queries go
+// through a middleware recording their latency to the monitor and retrying with backoff the ones timing out. Reads
+// are executed speculatively when configured.
+func GetMonitoredSessionInterface(cassandraConfig conf.CassandraConfig, keyspace string, monitor p.Monitor) (db_wrapper.SessionInterface, error) {
 	hosts := getCassandraHosts(cassandraConfig.Hosts)
-	glog.Infof("Cassandra hosts to connect to %s for keyspace %s", hosts, keyspace)
+	logger.Infof("Cassandra hosts to connect to %s for keyspace %s", hosts, keyspace)
 
 	cluster := gocql.NewCluster(hosts...)
 	cluster.Keyspace = keyspace
@@ -122,12 +136,33 @@ func GetSessionInterface(cassandraConfig conf.CassandraConfig, keyspace string)
 	cluster.Timeout = time.Duration(cassandraConfig.ConnectionPool.InitialConnectTimeout) * time.Millisecond
 	cluster.ConnectTimeout = time.Duration(cassandraConfig.ConnectionPool.ConnectTimeout) * time.Millisecond
 	cluster.NumConns = cassandraConfig.ConnectionPool.MaxNumConnections
+	cluster.QueryObserver = diagnostics.Default()
+	cluster.BatchObserver = diagnostics.Default()
 	withPool(cluster, cassandraConfig)
 
 	session, err := cluster.CreateSession()
 	if err != nil {
-		glog.Error("ERROR CONNECTING TO CASSANDRA", err)
+		logger.Error("ERROR CONNECTING TO CASSANDRA", err)
 		return nil, err
 	}
-	return db_wrapper.NewSession(session), nil
+	return db_wrapper.NewMonitoredSession(session, middleware(cassandraConfig, keyspace, monitor)), nil
+}
+
+// middleware returns the middleware of the queries of a keyspace
+func middleware(cassandraConfig conf.CassandraConfig, keyspace string, monitor p.Monitor) db_wrapper.Middleware {
+	m := db_wrapper.Middleware{
+		Keyspace:   keyspace,
+		Monitor:    monitor,
+		MaxRetries: cassandraConfig.QueryRetry.MaxRetries,
+		MinBackoff: time.Duration(cassandraConfig.QueryRetry.MinBackoffMillis) * time.Millisecond,
+		MaxBackoff: time.Duration(cassandraConfig.QueryRetry.MaxBackoffMillis) * time.Millisecond,
+		Faults:     faults.Default(),
+	}
+	if cassandraConfig.Speculative.Attempts > 0 {
+		m.Speculative = &gocql.SimpleSpeculativeExecution{
+			NumAttempts:  cassandraConfig.Speculative.Attempts,
+			TimeoutDelay: time.Duration(cassandraConfig.Speculative.DelayMillis) * time.Millisecond,
+		}
+	}
+	return m
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cassandra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/logger"
+)
+
+// columnMigration adds a column to a table created before the column was part of the schema.
+// CREATE TABLE IF NOT EXISTS leaves an existing table as it is, so the columns added since have to be altered in.
+type columnMigration struct {
+	Keyspace string
+	Table    string
+	Column   string
+	Type     string
+}
+
+// viewMigration recreates a materialized view created before some of its columns were selected,
+// with the statement of the schema creating it.
+type viewMigration struct {
+	Keyspace string
+	View     string
+	Columns  []string
+}
+
+// columnMigrations are the columns added to the tables since they were first created, in the order they were added
+var columnMigrations = []columnMigration{
+	{"schedule_management", "recurring_schedules_by_id", "every", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "anchor", "timestamp"},
+	{"schedule_management", "recurring_schedules_by_partition", "every", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "anchor", "timestamp"},
+	{"schedule_management", "recurring_schedules_by_id", "rrule", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "rrule", "text"},
+	{"schedule_management", "schedules", "status_callback", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "status_callback", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "status_callback", "text"},
+	{"schedule_management", "recurring_schedule_runs", "status_callback", "text"},
+	{"schedule_management", "schedules", "payload_encoding", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "payload_encoding", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "payload_encoding", "text"},
+	{"schedule_management", "recurring_schedule_runs", "payload_encoding", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "pause_policy", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "paused_at", "timestamp"},
+	{"schedule_management", "recurring_schedules_by_partition", "pause_policy", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "paused_at", "timestamp"},
+	{"schedule_management", "schedules", "priority", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "priority", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "priority", "text"},
+	{"schedule_management", "recurring_schedule_runs", "priority", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "deleted_at", "timestamp"},
+	{"schedule_management", "recurring_schedules_by_partition", "deleted_at", "timestamp"},
+	{"schedule_management", "delivery_receipts", "node", "text"},
+	{"schedule_management", "delivery_receipts", "reconciliation", "boolean"},
+	{"schedule_management", "status", "response_snippet", "text"},
+	{"schedule_management", "schedule_versions", "version", "int"},
+	{"schedule_management", "jobs", "cancel_requested", "boolean"},
+	{"cluster", "apps", "offboarding", "text"},
+	{"schedule_management", "schedules", "region", "text"},
+	{"schedule_management", "parked_schedules", "region", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "region", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "region", "text"},
+	{"schedule_management", "recurring_schedule_runs", "region", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "concurrency_policy", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "concurrency_policy", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "group_name", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "group_name", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "keep_paused", "boolean"},
+	{"schedule_management", "recurring_schedules_by_partition", "keep_paused", "boolean"},
+	{"schedule_management", "schedules", "trace_id", "text"},
+	{"schedule_management", "parked_schedules", "trace_id", "text"},
+	{"schedule_management", "recurring_schedules_by_id", "trace_id", "text"},
+	{"schedule_management", "recurring_schedules_by_partition", "trace_id", "text"},
+	{"schedule_management", "recurring_schedule_runs", "trace_id", "text"},
+	{"schedule_management", "schedules", "deferred_from", "timestamp"},
+	{"schedule_management", "parked_schedules", "deferred_from", "timestamp"},
+	{"schedule_management", "schedules", "request_id", "text"},
+	{"schedule_management", "parked_schedules", "request_id", "text"},
+	{"schedule_management", "recurring_schedule_runs", "request_id", "text"},
+}
+
+// viewMigrations are the materialized views whose selected columns changed since they were first created
+var viewMigrations = []viewMigration{
+	{"schedule_management", "view_schedules", []string{"status_callback", "payload_encoding", "priority", "region", "trace_id", "deferred_from", "request_id"}},
+	{"schedule_management", "view_parked_schedules", []string{"region", "trace_id", "deferred_from", "request_id"}},
+}
+
+// migrate adds the missing columns to the existing tables and recreates the views missing some of their columns.
+// Only what is missing is applied, so it is safe to run on every start.
+func migrate(session db_wrapper.SessionInterface, statements []string) error {
+	for _, m := range columnMigrations {
+		exists, err := columnExists(session, m.Keyspace, m.Table, m.Column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		logger.Infof("Adding column %s to %s.%s", m.Column, m.Keyspace, m.Table)
+		if err = session.Query(fmt.Sprintf("ALTER TABLE %s.%s ADD %s %s", m.Keyspace, m.Table, m.Column, m.Type)).Exec(); err != nil {
+			return fmt.Errorf("adding column %s to %s.%s: %w", m.Column, m.Keyspace, m.Table, err)
+		}
+	}
+
+	for _, m := range viewMigrations {
+		complete, err := viewComplete(session, m)
+		if err != nil {
+			return err
+		}
+		if complete {
+			continue
+		}
+
+		create, ok := viewStatement(statements, m.Keyspace, m.View)
+		if !ok {
+			return fmt.Errorf("no statement of the schema creates view %s.%s", m.Keyspace, m.View)
+		}
+
+		// The view is rebuilt from its base table in the background once recreated
+		logger.Infof("Recreating view %s.%s", m.Keyspace, m.View)
+		if err = session.Query(fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s.%s", m.Keyspace, m.View)).Exec(); err != nil {
+			return fmt.Errorf("dropping view %s.%s: %w", m.Keyspace, m.View, err)
+		}
+		if err = session.Query(create).Exec(); err != nil {
+			return fmt.Errorf("recreating view %s.%s: %w", m.Keyspace, m.View, err)
+		}
+	}
+
+	return nil
+}
+
+// viewComplete tells whether the view selects all the columns of the migration
+func viewComplete(session db_wrapper.SessionInterface, m viewMigration) (bool, error) {
+	for _, column := range m.Columns {
+		exists, err := columnExists(session, m.Keyspace, m.View, column)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// columnExists tells whether a table or a view has a column
+func columnExists(session db_wrapper.SessionInterface, keyspace, table, column string) (bool, error) {
+	var name string
+	err := session.Query("SELECT column_name FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ? AND column_name = ?",
+		keyspace, table, column).Scan(&name)
+	switch {
+	case err == gocql.ErrNotFound:
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("checking column %s of %s.%s: %w", column, keyspace, table, err)
+	default:
+		return true, nil
+	}
+}
+
+// viewStatement returns the statement of the schema creating the view
+func viewStatement(statements []string, keyspace, view string) (string, bool) {
+	name := fmt.Sprintf("MATERIALIZED VIEW IF NOT EXISTS %s.%s ", keyspace, view)
+	for _, statement := range statements {
+		if strings.Contains(statement, name) {
+			return strings.Trim(statement, "\r\n"), true
+		}
+	}
+	return "", false
+}
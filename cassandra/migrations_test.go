@@ -0,0 +1,121 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cassandra
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/db_wrapper"
+)
+
+// schemaSession answers the column lookups from a set of existing columns and records the statements executed
+type schemaSession struct {
+	columns  map[string]bool
+	executed []string
+}
+
+func (s *schemaSession) Query(stmt string, values ...interface{}) db_wrapper.QueryInterface {
+	return &schemaQuery{session: s, stmt: stmt, values: values}
+}
+
+func (s *schemaSession) ExecuteBatch(batch *gocql.Batch) error { return nil }
+
+func (s *schemaSession) Close() {}
+
+type schemaQuery struct {
+	session *schemaSession
+	stmt    string
+	values  []interface{}
+}
+
+func (q *schemaQuery) Bind(...interface{}) db_wrapper.QueryInterface { return q }
+
+func (q *schemaQuery) Exec() error {
+	q.session.executed = append(q.session.executed, q.stmt)
+	return nil
+}
+
+func (q *schemaQuery) Iter() db_wrapper.IterInterface { return nil }
+
+func (q *schemaQuery) Scan(dest ...interface{}) error {
+	if !q.session.columns[fmt.Sprintf("%s.%s.%s", q.values...)] {
+		return gocql.ErrNotFound
+	}
+	*dest[0].(*string) = q.values[2].(string)
+	return nil
+}
+
+func (q *schemaQuery) MapScan(m map[string]interface{}) error { return nil }
+
+func (q *schemaQuery) ScanCAS(dest ...interface{}) (bool, error) { return false, nil }
+
+func (q *schemaQuery) Consistency(c gocql.Consistency) db_wrapper.QueryInterface { return q }
+
+func (q *schemaQuery) PageState(state []byte) db_wrapper.QueryInterface { return q }
+
+func (q *schemaQuery) PageSize(n int) db_wrapper.QueryInterface { return q }
+
+func (q *schemaQuery) RetryPolicy(policy gocql.RetryPolicy) db_wrapper.QueryInterface { return q }
+
+func TestMigrate_AddsOnlyMissingColumns(t *testing.T) {
+	session := &schemaSession{columns: map[string]bool{}}
+	for _, m := range columnMigrations {
+		session.columns[fmt.Sprintf("%s.%s.%s", m.Keyspace, m.Table, m.Column)] = true
+	}
+	for _, m := range viewMigrations {
+		for _, column := range m.Columns {
+			session.columns[fmt.Sprintf("%s.%s.%s", m.Keyspace, m.View, column)] = true
+		}
+	}
+	delete(session.columns, "schedule_management.recurring_schedules_by_id.every")
+
+	if err := migrate(session, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(session.executed) != 1 || session.executed[0] != "ALTER TABLE schedule_management.recurring_schedules_by_id ADD every text" {
+		t.Errorf("expected only the missing column to be added, got %v", session.executed)
+	}
+}
+
+func TestMigrate_RecreatesIncompleteView(t *testing.T) {
+	create := "\nCREATE MATERIALIZED VIEW IF NOT EXISTS ks.view_items AS\nSELECT id, name, size\nFROM ks.items;"
+	defer func(migrations []viewMigration) { viewMigrations = migrations }(viewMigrations)
+	viewMigrations = []viewMigration{{"ks", "view_items", []string{"name", "size"}}}
+
+	session := &schemaSession{columns: map[string]bool{"ks.view_items.name": true}}
+	for _, m := range columnMigrations {
+		session.columns[fmt.Sprintf("%s.%s.%s", m.Keyspace, m.Table, m.Column)] = true
+	}
+
+	if err := migrate(session, strings.SplitAfter("CREATE TABLE IF NOT EXISTS ks.items (id int);"+create, ";")); err != nil {
+		t.Fatal(err)
+	}
+	if len(session.executed) != 2 || session.executed[0] != "DROP MATERIALIZED VIEW IF EXISTS ks.view_items" ||
+		session.executed[1] != strings.Trim(create, "\n") {
+		t.Errorf("expected the view to be dropped and recreated, got %v", session.executed)
+	}
+
+	if err := migrate(session, nil); err == nil {
+		t.Error("expected a view without creating statement to fail the migration")
+	}
+}
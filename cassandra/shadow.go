@@ -0,0 +1,51 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cassandra
+
+import (
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/db_wrapper"
+	p "github.com/myntra/goscheduler/monitoring"
+)
+
+// WithShadow returns a session mirroring the writes of the given session to the keyspace of the shadow cluster, the
+// session itself when the shadow writes are disabled. The shadow cluster is connected to with the configuration of the
+// primary one, but for its hosts and data center, and its keyspace defaults to the primary keyspace.
+func WithShadow(session db_wrapper.SessionInterface, cassandraConfig conf.CassandraConfig, keyspace string, shadowConfig conf.ShadowWriteConfig, shadowKeyspace string, monitor p.Monitor) (db_wrapper.SessionInterface, error) {
+	if !shadowConfig.Enabled {
+		return session, nil
+	}
+
+	shadowCassandraConfig := cassandraConfig
+	shadowCassandraConfig.Hosts = shadowConfig.Hosts
+	shadowCassandraConfig.DataCenter = shadowConfig.DataCenter
+	if shadowKeyspace == "" {
+		shadowKeyspace = keyspace
+	}
+
+	shadow, err := GetMonitoredSessionInterface(shadowCassandraConfig, shadowKeyspace, monitor)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror := db_wrapper.DefaultMirror()
+	mirror.Configure(monitor, shadowConfig.CompareRate, shadowConfig.CompareRoutines, shadowConfig.MaxRecorded)
+	return db_wrapper.NewShadowSession(session, shadow, keyspace, mirror), nil
+}
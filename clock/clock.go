@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package clock tells the time to the pollers, the cron expansion and the executors of the callbacks, so that it can
+// be replaced with a virtual clock advanced on demand by the integration tests.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates the timers and tickers running on it
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer delivers the time on its channel once its duration has passed
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers the time on its channel every time its period has passed
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+var current = struct {
+	sync.RWMutex
+	clock Clock
+}{clock: Real{}}
+
+// Default returns the clock of the scheduler, the wall clock unless replaced with SetDefault
+func Default() Clock {
+	current.RLock()
+	defer current.RUnlock()
+	return current.clock
+}
+
+// SetDefault replaces the clock of the scheduler. It is set before the scheduler is started.
+func SetDefault(clock Clock) {
+	current.Lock()
+	defer current.Unlock()
+	current.clock = clock
+}
+
+// Now returns the current time of the default clock
+func Now() time.Time {
+	return Default().Now()
+}
+
+// Since returns the time elapsed on the default clock since t
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the time left on the default clock until t
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
+
+// NewTimer creates a timer running on the default clock
+func NewTimer(d time.Duration) Timer {
+	return Default().NewTimer(d)
+}
+
+// NewTicker creates a ticker running on the default clock
+func NewTicker(d time.Duration) Ticker {
+	return Default().NewTicker(d)
+}
+
+// Real is the wall clock
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,100 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, time.February, 28, 23, 59, 0, 0, time.UTC)
+
+func TestVirtual_StandsStillUntilAdvanced(t *testing.T) {
+	clock := NewVirtual(start)
+	time.Sleep(10 * time.Millisecond)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("expected the clock standing still at %v, got %v", start, clock.Now())
+	}
+
+	if err := clock.Advance(2 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if now := clock.Now(); now.Month() != time.February || now.Day() != 29 || now.Hour() != 0 || now.Minute() != 1 {
+		t.Errorf("expected the clock at 00:01 on the 29th of Feb, got %v", now)
+	}
+
+	if err := clock.AdvanceTo(start); err != ErrBackwards {
+		t.Errorf("expected %v, got %v", ErrBackwards, err)
+	}
+	if err := clock.Advance(-time.Second); err != ErrBackwards {
+		t.Errorf("expected %v, got %v", ErrBackwards, err)
+	}
+}
+
+func TestVirtual_FiresTimersInOrder(t *testing.T) {
+	clock := NewVirtual(start)
+	late := clock.NewTimer(2 * time.Hour)
+	early := clock.NewTimer(time.Hour)
+	stopped := clock.NewTimer(time.Minute)
+	if !stopped.Stop() {
+		t.Error("expected a waiting timer to be stopped")
+	}
+
+	if err := clock.Advance(90 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case at := <-early.C():
+		if !at.Equal(start.Add(time.Hour)) {
+			t.Errorf("expected the timer fired at its time, got %v", at)
+		}
+	default:
+		t.Error("expected the due timer fired")
+	}
+	select {
+	case <-late.C():
+		t.Error("expected the timer not yet due left waiting")
+	case <-stopped.C():
+		t.Error("expected the stopped timer never fired")
+	default:
+	}
+
+	late.Reset(-time.Second)
+	select {
+	case <-late.C():
+	default:
+		t.Error("expected the timer reset to the past fired right away")
+	}
+}
+
+func TestVirtual_DeliversEveryTick(t *testing.T) {
+	clock := NewVirtual(start)
+	ticker := clock.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	advanced := make(chan error)
+	go func() {
+		advanced <- clock.Advance(time.Hour)
+	}()
+
+	for i := 1; i <= 60; i++ {
+		if tick := <-ticker.C(); !tick.Equal(start.Add(time.Duration(i) * time.Minute)) {
+			t.Fatalf("expected tick %d at %v, got %v", i, start.Add(time.Duration(i)*time.Minute), tick)
+		}
+	}
+	if err := <-advanced; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("expected a tick for every minute only, got another at %v", tick)
+	default:
+	}
+}
+
+func TestReal_TellsTheWallClock(t *testing.T) {
+	if since := time.Since(Real{}.Now()); since < 0 || since > time.Second {
+		t.Errorf("expected the wall clock, got %v apart", since)
+	}
+
+	timer := Real{}.NewTimer(time.Millisecond)
+	<-timer.C()
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package clock
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// tickDeliveryTimeout bounds the wait for a ticker to be read while advancing, for tickers nobody reads anymore
+const tickDeliveryTimeout = time.Second
+
+// ErrBackwards is returned when a virtual clock is asked to go back in time
+var ErrBackwards = errors.New("a virtual clock cannot go back in time")
+
+// Virtual is a clock which stands still until it is advanced. Advancing it fires the timers and ticks the tickers due
+// on the way in order, so that a day of schedules can be fired in an instant.
+type Virtual struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters map[*waiter]bool
+}
+
+// NewVirtual creates a virtual clock set to the supplied time
+func NewVirtual(now time.Time) *Virtual {
+	return &Virtual{now: now, waiters: make(map[*waiter]bool)}
+}
+
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+func (v *Virtual) NewTimer(d time.Duration) Timer {
+	w := &waiter{clock: v, c: make(chan time.Time, 1)}
+	w.Reset(d)
+	return w
+}
+
+func (v *Virtual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	w := &waiter{clock: v, c: make(chan time.Time, 1), period: d, stopped: make(chan struct{})}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	w.at = v.now.Add(d)
+	v.waiters[w] = true
+	return virtualTicker{w}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker due until then
+func (v *Virtual) Advance(d time.Duration) error {
+	if d < 0 {
+		return ErrBackwards
+	}
+	return v.AdvanceTo(v.Now().Add(d))
+}
+
+// AdvanceTo moves the clock forward to t, firing every timer and ticker due until then.
+// Ticks are handed to their readers one by one so that no tick is lost, however far the clock is advanced.
+func (v *Virtual) AdvanceTo(t time.Time) error {
+	for {
+		v.mu.Lock()
+		if t.Before(v.now) {
+			v.mu.Unlock()
+			return ErrBackwards
+		}
+
+		next := v.next(t)
+		if next == nil {
+			v.now = t
+			v.mu.Unlock()
+			return nil
+		}
+
+		v.now = next.at
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+		} else {
+			delete(v.waiters, next)
+		}
+		now := v.now
+		v.mu.Unlock()
+
+		next.fire(now)
+	}
+}
+
+// next returns the waiter due first until t, nil when none is due. It is called with the lock held.
+func (v *Virtual) next(t time.Time) *waiter {
+	var next *waiter
+	for w := range v.waiters {
+		if !w.at.After(t) && (next == nil || w.at.Before(next.at)) {
+			next = w
+		}
+	}
+	return next
+}
+
+// waiter is a timer or, with a period, a ticker of a virtual clock
+type waiter struct {
+	clock   *Virtual
+	c       chan time.Time
+	at      time.Time
+	period  time.Duration
+	stopped chan struct{}
+}
+
+func (w *waiter) C() <-chan time.Time {
+	return w.c
+}
+
+// fire delivers the time to the timer, or waits for the ticker to be read so that the tick is not lost
+func (w *waiter) fire(now time.Time) {
+	if w.period == 0 {
+		select {
+		case w.c <- now:
+		default:
+		}
+		return
+	}
+
+	timeout := time.NewTimer(tickDeliveryTimeout)
+	defer timeout.Stop()
+	select {
+	case w.c <- now:
+	case <-w.stopped:
+	case <-timeout.C:
+	}
+}
+
+// Stop stops the timer or ticker, telling whether it was still waiting
+func (w *waiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	waiting := w.clock.waiters[w]
+	delete(w.clock.waiters, w)
+	if w.stopped != nil && waiting {
+		close(w.stopped)
+	}
+	return waiting
+}
+
+// virtualTicker is the ticker of a virtual clock
+type virtualTicker struct {
+	*waiter
+}
+
+func (t virtualTicker) Stop() {
+	t.waiter.Stop()
+}
+
+// Reset makes the timer fire once d has passed on the clock, right away for a non-positive d
+func (w *waiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	waiting := w.clock.waiters[w]
+	if d > 0 {
+		w.at = w.clock.now.Add(d)
+		w.clock.waiters[w] = true
+		w.clock.mu.Unlock()
+		return waiting
+	}
+
+	delete(w.clock.waiters, w)
+	now := w.clock.now
+	w.clock.mu.Unlock()
+	w.fire(now)
+	return waiting
+}
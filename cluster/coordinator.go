@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cluster
+
+import (
+	"errors"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/uber/ringpop-go"
+)
+
+// Coordinator is the backend of the membership of the cluster and of the assignment of the entities to its nodes.
+type Coordinator interface {
+	// WhoAmI returns the address of the node.
+	WhoAmI() (string, error)
+	// Lookup returns the address of the node owning the key.
+	Lookup(key string) (string, error)
+	// LookupN returns the addresses of the n nodes next in line to own the key, the owner first.
+	LookupN(key string, n int) ([]string, error)
+	// GetReachableMembers returns the addresses of the members of the cluster.
+	GetReachableMembers() ([]string, error)
+}
+
+// CoordinationListener is notified by a PluggableCoordinator of the changes of the cluster.
+type CoordinationListener interface {
+	// MembersRemoved is called with the nodes which left the cluster.
+	MembersRemoved(nodes []string)
+	// LeaseLost is called when the node could not renew its membership in time and was dropped from the cluster.
+	LeaseLost()
+	// Rejoined is called when the node joined the cluster again after losing its lease.
+	Rejoined()
+}
+
+// PluggableCoordinator is a coordination backend used in place of ringpop. On top of the membership it hands out
+// fencing tokens for the entities, so that a node cut off from the cluster stops polling an entity once another
+// node took it over.
+type PluggableCoordinator interface {
+	Coordinator
+	// Join registers the node in the cluster and starts notifying the listener.
+	Join(listener CoordinationListener) error
+	// Leave removes the node from the cluster, releasing every entity it owns.
+	Leave()
+	// Acquire takes the ownership of the key and returns its fencing token.
+	Acquire(key string) (int64, error)
+	// Release gives up the ownership of the key taken with the token.
+	Release(key string, token int64) error
+	// Owns tells whether the token is still the current one of the key.
+	Owns(key string, token int64) (bool, error)
+	// FencingInterval returns the period at which the ownership of the running entities is checked.
+	FencingInterval() time.Duration
+}
+
+// ringpopCoordinator adapts ringpop to a Coordinator.
+type ringpopCoordinator struct {
+	*ringpop.Ringpop
+}
+
+func (r ringpopCoordinator) GetReachableMembers() ([]string, error) {
+	return r.Ringpop.GetReachableMembers()
+}
+
+var errNoMembers = errors.New("no reachable member in the cluster")
+
+// rendezvous returns the member owning the key by highest random weight hashing, which moves only the keys of a
+// member joining or leaving the cluster.
+func rendezvous(key string, members []string) (string, error) {
+	ranked := rank(key, members)
+	if len(ranked) == 0 {
+		return "", errNoMembers
+	}
+	return ranked[0], nil
+}
+
+// rank orders the members by decreasing weight for the key, the owner of the key first
+func rank(key string, members []string) []string {
+	weights := make(map[string]uint64, len(members))
+	for _, member := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(key))
+		weights[member] = h.Sum64()
+	}
+
+	ranked := append([]string{}, members...)
+	sort.Slice(ranked, func(i, j int) bool {
+		if weights[ranked[i]] != weights[ranked[j]] {
+			return weights[ranked[i]] > weights[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
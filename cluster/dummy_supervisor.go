@@ -20,6 +20,9 @@
 package cluster
 
 import (
+	"errors"
+	"fmt"
+
 	e "github.com/myntra/goscheduler/cluster_entity"
 	"github.com/myntra/goscheduler/store"
 )
@@ -39,3 +42,41 @@ func (d *DummySupervisor) DeactivateApp(app store.App) {
 // Implement if required
 func (d *DummySupervisor) ActivateApp(app store.App) {
 }
+
+// Implement if required
+func (d *DummySupervisor) UpdateApp(appName string) {
+}
+
+// Implement if required
+func (d *DummySupervisor) Nodes() []NodeState {
+	return []NodeState{{Address: "127.0.0.1:9091", Healthy: true, Self: true, Partitions: []string{"test.0"}}}
+}
+
+// Implement if required
+func (d *DummySupervisor) Partitions() []PartitionAssignment {
+	return []PartitionAssignment{
+		{Id: "test.0", AppId: "test", PartitionId: 0, Node: "127.0.0.1:9091", Status: "RUNNING", Owner: "127.0.0.1:9091", Reachable: true},
+		{Id: "other.0", AppId: "other", PartitionId: 0, Node: "127.0.0.1:9091", Status: "STOPPED", Owner: "127.0.0.1:9091", Reachable: true},
+	}
+}
+
+// Implement if required
+func (d *DummySupervisor) Cordon(node string) error {
+	if node == "testCordonError" {
+		return errors.New("error while cordoning node")
+	}
+	return nil
+}
+
+// Implement if required
+func (d *DummySupervisor) Uncordon(node string) error {
+	return nil
+}
+
+// Implement if required
+func (d *DummySupervisor) Drain(node string) ([]DrainedPartition, error) {
+	if node == "testDrainPropagationError" {
+		return nil, fmt.Errorf("%w to 127.0.0.1:9092", ErrCordonNotPropagated)
+	}
+	return []DrainedPartition{{Id: "test.0", Node: "127.0.0.1:9092"}}, nil
+}
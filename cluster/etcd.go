@@ -0,0 +1,422 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const CoordinationEtcd = "etcd"
+
+var errLeaseExpired = errors.New("etcd lease expired")
+
+// etcdCoordinator coordinates the cluster through etcd with its v3 client. Every node keeps a member key alive under
+// a lease; a node which stops renewing it is dropped after the TTL of the lease, along with the ownership keys of its
+// entities. The fencing token of an entity is the revision at which its ownership key was last written.
+type etcdCoordinator struct {
+	client  *clientv3.Client
+	prefix  string
+	address string
+	ttl     time.Duration
+	timeout time.Duration
+	stop    chan struct{}
+	leave   sync.Once
+
+	mu       sync.RWMutex
+	lease    clientv3.LeaseID
+	renewed  time.Time
+	members  []string
+	listener CoordinationListener
+}
+
+// NewEtcdCoordinator returns a coordinator registering the node at the address in the cluster through etcd.
+func NewEtcdCoordinator(config conf.Etcd, clusterName, address string) (PluggableCoordinator, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("no etcd endpoint configured")
+	}
+	if config.LeaseTTLSeconds <= 0 {
+		return nil, fmt.Errorf("invalid etcd lease TTL %d", config.LeaseTTLSeconds)
+	}
+
+	timeout := time.Duration(config.TimeoutMillis) * time.Millisecond
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		DialTimeout: timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating the etcd client: %w", err)
+	}
+
+	return &etcdCoordinator{
+		client:  client,
+		prefix:  strings.TrimSuffix(config.Prefix, "/") + "/" + clusterName,
+		address: address,
+		ttl:     time.Duration(config.LeaseTTLSeconds) * time.Second,
+		timeout: timeout,
+		stop:    make(chan struct{}),
+	}, nil
+}
+
+// requestContext bounds a request to etcd by the configured timeout
+func (c *etcdCoordinator) requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+func (c *etcdCoordinator) memberPrefix() string {
+	return c.prefix + "/members/"
+}
+
+func (c *etcdCoordinator) ownerKey(key string) string {
+	return c.prefix + "/owners/" + key
+}
+
+// Join grants the lease of the node, registers its member key and starts renewing the lease and watching the
+// members of the cluster.
+func (c *etcdCoordinator) Join(listener CoordinationListener) error {
+	c.mu.Lock()
+	c.listener = listener
+	c.mu.Unlock()
+
+	if err := c.register(); err != nil {
+		return err
+	}
+	if _, err := c.refresh(); err != nil {
+		return err
+	}
+
+	go c.keepAlive()
+	go c.watch()
+	return nil
+}
+
+// Leave revokes the lease of the node, which removes at once its member key and the ownership keys of its entities.
+// It is safe to call more than once, only the first call leaves the cluster.
+func (c *etcdCoordinator) Leave() {
+	c.leave.Do(func() {
+		close(c.stop)
+
+		ctx, cancel := c.requestContext()
+		defer cancel()
+		if _, err := c.client.Revoke(ctx, c.currentLease()); err != nil {
+			logger.Errorf("Revoking the etcd lease of %s failed: %v", c.address, err)
+		}
+		if err := c.client.Close(); err != nil {
+			logger.Errorf("Closing the etcd client of %s failed: %v", c.address, err)
+		}
+	})
+}
+
+func (c *etcdCoordinator) currentLease() clientv3.LeaseID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lease
+}
+
+// register grants a new lease and puts the member key of the node under it
+func (c *etcdCoordinator) register() error {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	lease, err := c.client.Grant(ctx, int64(c.ttl/time.Second))
+	if err != nil {
+		return err
+	}
+	if _, err = c.client.Put(ctx, c.memberPrefix()+c.address, c.address, clientv3.WithLease(lease.ID)); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.lease = lease.ID
+	c.renewed = time.Now()
+	c.mu.Unlock()
+
+	logger.Infof("Registered %s in etcd with lease %d", c.address, lease.ID)
+	return nil
+}
+
+func (c *etcdCoordinator) renew() error {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	response, err := c.client.KeepAliveOnce(ctx, c.currentLease())
+	if err == rpctypes.ErrLeaseNotFound {
+		return errLeaseExpired
+	}
+	if err != nil {
+		return err
+	}
+	if response.TTL <= 0 {
+		return errLeaseExpired
+	}
+
+	c.mu.Lock()
+	c.renewed = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// keepAlive renews the lease three times per TTL. Once the lease could not be renewed for a whole TTL the node is
+// considered out of the cluster: the listener is told to stop the entities and the node registers again.
+func (c *etcdCoordinator) keepAlive() {
+	ticker := time.NewTicker(c.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+
+		faults.Default().WaitWhilePaused()
+		err := c.renew()
+		if err == nil {
+			continue
+		}
+		logger.Errorf("Renewing the etcd lease of %s failed: %v", c.address, err)
+
+		c.mu.RLock()
+		expired := err == errLeaseExpired || time.Since(c.renewed) >= c.ttl
+		listener := c.listener
+		c.mu.RUnlock()
+		if !expired {
+			continue
+		}
+
+		listener.LeaseLost()
+		for c.register() != nil {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+		if _, err := c.refresh(); err != nil {
+			logger.Errorf("Listing the members of the cluster failed: %v", err)
+		}
+		listener.Rejoined()
+	}
+}
+
+// refresh lists the members of the cluster and notifies the listener of the removed ones, returning the revision
+// of the listing
+func (c *etcdCoordinator) refresh() (int64, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	response, err := c.client.Get(ctx, c.memberPrefix(), clientv3.WithPrefix())
+	if err != nil {
+		return 0, err
+	}
+
+	members := make([]string, 0, len(response.Kvs))
+	for _, kv := range response.Kvs {
+		members = append(members, string(kv.Value))
+	}
+	sort.Strings(members)
+
+	c.mu.Lock()
+	previous := c.members
+	c.members = members
+	listener := c.listener
+	c.mu.Unlock()
+
+	if removed := difference(previous, members); len(removed) > 0 && listener != nil {
+		logger.Infof("Members %v left the cluster", removed)
+		listener.MembersRemoved(removed)
+	}
+	return response.Header.Revision, nil
+}
+
+// difference returns the members of the sorted before missing from the sorted after
+func difference(before, after []string) []string {
+	var removed []string
+	for _, member := range before {
+		if i := sort.SearchStrings(after, member); i == len(after) || after[i] != member {
+			removed = append(removed, member)
+		}
+	}
+	return removed
+}
+
+// watch lists the members of the cluster again on every change of the member keys, so that a node dropped by etcd
+// is offloaded as soon as its lease expires
+func (c *etcdCoordinator) watch() {
+	for {
+		revision, err := c.refresh()
+		if err == nil {
+			err = c.watchFrom(revision + 1)
+		}
+
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+		logger.Errorf("Watching the members of the cluster failed: %v", err)
+
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(c.ttl / 3):
+		}
+	}
+}
+
+func (c *etcdCoordinator) watchFrom(revision int64) error {
+	ctx, cancel := context.WithCancel(clientv3.WithRequireLeader(context.Background()))
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for response := range c.client.Watch(ctx, c.memberPrefix(), clientv3.WithPrefix(), clientv3.WithRev(revision)) {
+		if err := response.Err(); err != nil {
+			return err
+		}
+		if len(response.Events) > 0 {
+			if _, err := c.refresh(); err != nil {
+				return err
+			}
+		}
+	}
+	return errors.New("etcd closed the watch of the members")
+}
+
+func (c *etcdCoordinator) WhoAmI() (string, error) {
+	return c.address, nil
+}
+
+func (c *etcdCoordinator) Lookup(key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return rendezvous(key, c.members)
+}
+
+func (c *etcdCoordinator) LookupN(key string, n int) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranked := rank(key, c.members)
+	if len(ranked) == 0 {
+		return nil, errNoMembers
+	}
+	if n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked, nil
+}
+
+func (c *etcdCoordinator) GetReachableMembers() ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string{}, c.members...), nil
+}
+
+func (c *etcdCoordinator) FencingInterval() time.Duration {
+	return c.ttl / 2
+}
+
+// Acquire creates the ownership key of the entity under the lease of the node. A key already owned by the node,
+// under the lease of before a rejoin, is written again under the current lease.
+func (c *etcdCoordinator) Acquire(key string) (int64, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	owner := c.ownerKey(key)
+	put := clientv3.OpPut(owner, c.address, clientv3.WithLease(c.currentLease()))
+
+	response, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(owner), "=", 0)).
+		Then(put).
+		Else(clientv3.OpGet(owner)).
+		Commit()
+	if err != nil {
+		return 0, err
+	}
+	if response.Succeeded {
+		return response.Header.Revision, nil
+	}
+
+	ranged := response.Responses[0].GetResponseRange()
+	if ranged == nil || len(ranged.Kvs) == 0 {
+		return 0, fmt.Errorf("acquiring %s: the ownership key disappeared", key)
+	}
+	current := ranged.Kvs[0]
+	if string(current.Value) != c.address {
+		return 0, fmt.Errorf("%s is owned by %s", key, current.Value)
+	}
+
+	response, err = c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(owner), "=", current.ModRevision)).
+		Then(put).
+		Commit()
+	if err != nil {
+		return 0, err
+	}
+	if !response.Succeeded {
+		return 0, fmt.Errorf("the ownership of %s changed while acquiring it", key)
+	}
+	return response.Header.Revision, nil
+}
+
+// Release deletes the ownership key of the entity unless another node took it over since.
+func (c *etcdCoordinator) Release(key string, token int64) error {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	owner := c.ownerKey(key)
+	_, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(owner), "=", token)).
+		Then(clientv3.OpDelete(owner)).
+		Commit()
+	return err
+}
+
+func (c *etcdCoordinator) Owns(key string, token int64) (bool, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	response, err := c.client.Get(ctx, c.ownerKey(key))
+	if err != nil {
+		return false, err
+	}
+	if len(response.Kvs) == 0 {
+		return false, nil
+	}
+	kv := response.Kvs[0]
+	return string(kv.Value) == c.address && kv.ModRevision == token, nil
+}
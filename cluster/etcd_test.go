@@ -0,0 +1,178 @@
+package cluster
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/stretchr/testify/assert"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// startEtcd runs an embedded single node etcd for the duration of the test
+func startEtcd(t *testing.T) string {
+	config := embed.NewConfig()
+	config.Dir = t.TempDir()
+	config.LogLevel = "error"
+	config.ListenClientUrls = []url.URL{freeURL(t)}
+	config.AdvertiseClientUrls = config.ListenClientUrls
+	config.ListenPeerUrls = []url.URL{freeURL(t)}
+	config.AdvertisePeerUrls = config.ListenPeerUrls
+	config.InitialCluster = config.InitialClusterFromName(config.Name)
+
+	server, err := embed.StartEtcd(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("etcd did not start")
+	}
+	return config.AdvertiseClientUrls[0].String()
+}
+
+func freeURL(t *testing.T) url.URL {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return url.URL{Scheme: "http", Host: listener.Addr().String()}
+}
+
+type recordingListener struct {
+	mu      sync.Mutex
+	removed []string
+}
+
+func (l *recordingListener) MembersRemoved(nodes []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removed = append(l.removed, nodes...)
+}
+
+func (l *recordingListener) removedMembers() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.removed...)
+}
+
+func (l *recordingListener) LeaseLost() {}
+
+func (l *recordingListener) Rejoined() {}
+
+func newTestEtcdCoordinator(t *testing.T, endpoint, address string) *etcdCoordinator {
+	coordinator, err := NewEtcdCoordinator(conf.Etcd{
+		Endpoints:       []string{endpoint},
+		Prefix:          "/goscheduler/",
+		LeaseTTLSeconds: 5,
+		TimeoutMillis:   1000,
+	}, "test", address)
+	assert.Nil(t, err)
+	return coordinator.(*etcdCoordinator)
+}
+
+func TestNewEtcdCoordinator_InvalidConfig(t *testing.T) {
+	_, err := NewEtcdCoordinator(conf.Etcd{LeaseTTLSeconds: 5}, "test", "127.0.0.1:9091")
+	assert.NotNil(t, err)
+
+	_, err = NewEtcdCoordinator(conf.Etcd{Endpoints: []string{"http://127.0.0.1:2379"}}, "test", "127.0.0.1:9091")
+	assert.NotNil(t, err)
+}
+
+func TestEtcdCoordinator_Membership(t *testing.T) {
+	endpoint := startEtcd(t)
+
+	first := newTestEtcdCoordinator(t, endpoint, "127.0.0.1:9091")
+	second := newTestEtcdCoordinator(t, endpoint, "127.0.0.1:9092")
+	listener := &recordingListener{}
+
+	assert.Nil(t, first.Join(listener))
+	assert.Nil(t, second.Join(&recordingListener{}))
+	_, err := first.refresh()
+	assert.Nil(t, err)
+
+	members, _ := first.GetReachableMembers()
+	assert.Equal(t, []string{"127.0.0.1:9091", "127.0.0.1:9092"}, members)
+
+	owner, err := first.Lookup("app.0")
+	assert.Nil(t, err)
+	other, _ := second.Lookup("app.0")
+	assert.Equal(t, owner, other)
+
+	// the watch of the first member reports the departure of the second
+	second.Leave()
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"127.0.0.1:9092"}, listener.removedMembers())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	members, _ = first.GetReachableMembers()
+	assert.Equal(t, []string{"127.0.0.1:9091"}, members)
+	first.Leave()
+
+	// leaving again is a noop
+	assert.NotPanics(t, first.Leave)
+}
+
+func TestEtcdCoordinator_Fencing(t *testing.T) {
+	endpoint := startEtcd(t)
+
+	first := newTestEtcdCoordinator(t, endpoint, "127.0.0.1:9091")
+	second := newTestEtcdCoordinator(t, endpoint, "127.0.0.1:9092")
+	assert.Nil(t, first.Join(&recordingListener{}))
+	assert.Nil(t, second.Join(&recordingListener{}))
+
+	token, err := first.Acquire("app.0")
+	assert.Nil(t, err)
+	owns, err := first.Owns("app.0", token)
+	assert.Nil(t, err)
+	assert.True(t, owns)
+
+	_, err = second.Acquire("app.0")
+	assert.True(t, strings.Contains(err.Error(), "owned by 127.0.0.1:9091"))
+
+	// acquiring again supersedes the previous token
+	newToken, err := first.Acquire("app.0")
+	assert.Nil(t, err)
+	assert.True(t, newToken > token)
+	owns, _ = first.Owns("app.0", token)
+	assert.False(t, owns)
+
+	// the expiry of the lease of the owner hands the key over
+	first.Leave()
+	secondToken, err := second.Acquire("app.0")
+	assert.Nil(t, err)
+	owns, _ = first.Owns("app.0", newToken)
+	assert.False(t, owns)
+
+	assert.Nil(t, second.Release("app.0", secondToken))
+	owns, _ = second.Owns("app.0", secondToken)
+	assert.False(t, owns)
+	second.Leave()
+}
+
+func TestRendezvous(t *testing.T) {
+	_, err := rendezvous("app.0", nil)
+	assert.Equal(t, errNoMembers, err)
+
+	members := []string{"a:1", "b:1", "c:1"}
+	owners := make(map[string]string)
+	for _, key := range []string{"app.0", "app.1", "app.2", "app.3", "app.4", "app.5"} {
+		owners[key], _ = rendezvous(key, members)
+	}
+
+	// removing a member only moves its own keys
+	for key, owner := range owners {
+		moved, _ := rendezvous(key, []string{"a:1", "c:1"})
+		if owner != "b:1" {
+			assert.Equal(t, owner, moved)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	e "github.com/myntra/goscheduler/cluster_entity"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/uber/tchannel-go/json"
+)
+
+// ErrCordonNotPropagated is returned when some nodes could not be told about a change of the cordoned nodes, which
+// they pick up on their next boot
+var ErrCordonNotPropagated = errors.New("cordoned nodes not propagated")
+
+// cordons is the set of the nodes of the cluster to which no partition is assigned
+type cordons struct {
+	mu    sync.RWMutex
+	nodes map[string]bool
+}
+
+func newCordons() *cordons {
+	return &cordons{nodes: make(map[string]bool)}
+}
+
+func (c *cordons) set(nodes []store.CordonedNode) {
+	cordoned := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		cordoned[node.Node] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes = cordoned
+}
+
+func (c *cordons) get() map[string]bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nodes
+}
+
+// DrainedPartition is a partition handed over by a drained node, to Node or failing with Error
+type DrainedPartition struct {
+	Id    string `json:"id"`
+	Node  string `json:"node,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// lookup returns the node owning the entity, skipping the cordoned nodes unless every member is cordoned
+func (s *Supervisor) lookup(id string) (string, error) {
+	cordoned := s.cordoned.get()
+	if len(cordoned) == 0 {
+		return s.coordinator.Lookup(id)
+	}
+
+	members, err := s.coordinator.GetReachableMembers()
+	if err != nil {
+		return "", err
+	}
+	candidates, err := s.coordinator.LookupN(id, len(members))
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range candidates {
+		if !cordoned[candidate] {
+			return candidate, nil
+		}
+	}
+	return s.coordinator.Lookup(id)
+}
+
+// refreshCordons loads the cordoned nodes of the cluster from DB, keeping the known ones on failure
+func (s *Supervisor) refreshCordons() error {
+	nodes, err := s.clusterDao.GetCordonedNodes(s.clusterName)
+	if err != nil {
+		logger.Errorf("Error getting the cordoned nodes of cluster %s: %v", s.clusterName, err)
+		return err
+	}
+	s.cordoned.set(nodes)
+	return nil
+}
+
+// CordonUpdateHandler reloads the cordoned nodes after one was cordoned or uncordoned through another node
+func (s *Supervisor) CordonUpdateHandler(ctx json.Context, request *StatusRequest) (*Response, error) {
+	response := Response{ServerAddress: s.address, Status: SUCCESS}
+	if err := s.refreshCordons(); err != nil {
+		response.Error = err.Error()
+		response.Status = FAILED
+	}
+	return &response, nil
+}
+
+// cordonBroadcast tells every reachable member of the cluster to reload the cordoned nodes
+func (s *Supervisor) cordonBroadcast() error {
+	if err := s.refreshCordons(); err != nil {
+		return err
+	}
+
+	members, err := s.coordinator.GetReachableMembers()
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, member := range members {
+		if member == s.address {
+			continue
+		}
+
+		ctx, cancel := json.NewContext(nodeStatusTimeout)
+		var response Response
+		client := json.NewClient(s.channel, s.clusterName, &json.ClientOptions{HostPort: member})
+		err := client.Call(ctx, CordonUpdate, &StatusRequest{}, &response)
+		cancel()
+		if err == nil && response.Status == FAILED {
+			err = errors.New(response.Error)
+		}
+		if err != nil {
+			logger.Errorf("Broadcasting the cordoned nodes to %s failed with error %v", member, err)
+			failed = append(failed, member)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%w to %s", ErrCordonNotPropagated, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Cordon stops assigning partitions to the node. The partitions it runs stay on it until it is drained.
+func (s *Supervisor) Cordon(node string) error {
+	logger.Infof("Cordoning node %s", node)
+	if err := s.clusterDao.CordonNode(s.clusterName, store.CordonedNode{Node: node, CordonedAt: time.Now()}); err != nil {
+		return err
+	}
+	return s.cordonBroadcast()
+}
+
+// Uncordon lets partitions be assigned to the node again. No partition is moved back to it until the next change
+// of the members of the cluster.
+func (s *Supervisor) Uncordon(node string) error {
+	logger.Infof("Uncordoning node %s", node)
+	if err := s.clusterDao.UncordonNode(s.clusterName, node); err != nil {
+		return err
+	}
+	return s.cordonBroadcast()
+}
+
+// CordonedNodes returns the cordoned nodes of the cluster
+func (s *Supervisor) CordonedNodes() []string {
+	var nodes []string
+	for node := range s.cordoned.get() {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Drain cordons the node and hands every partition it runs over to the node now owning the partition, one at a
+// time, stopping the partition before starting it on its new node
+func (s *Supervisor) Drain(node string) ([]DrainedPartition, error) {
+	if err := s.Cordon(node); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Draining node %s", node)
+	drained := make([]DrainedPartition, 0)
+	for _, entity := range s.clusterDao.GetAllEntitiesInfoOfNode(node) {
+		if entity.Status != RUNNING {
+			continue
+		}
+		drained = append(drained, s.handOver(entity))
+	}
+	return drained, nil
+}
+
+// handOver stops the entity on the node running it and starts it on the node owning it
+func (s *Supervisor) handOver(entity e.EntityInfo) (drained DrainedPartition) {
+	drained.Id = entity.Id
+	defer func() {
+		if r := recover(); r != nil {
+			drained.Node = ""
+			drained.Error = fmt.Sprintf("%v", r)
+			logger.Errorf("Handing entity %s over from node %s failed with error %v", entity.Id, entity.Node, r)
+		}
+	}()
+
+	destNode, err := s.lookup(entity.Id)
+	if err != nil {
+		drained.Error = err.Error()
+		return
+	}
+	if destNode == entity.Node {
+		drained.Error = "no uncordoned node to hand the partition over to"
+		return
+	}
+
+	if entity.Node == s.address {
+		if _, err := s.StopEntity(entity.Id); err != nil {
+			panic(err)
+		}
+	} else {
+		s.forwardOrPanic(entity, StopEntities)
+	}
+
+	if destNode == s.address {
+		if _, err := s.StartEntity(entity.Id); err != nil {
+			panic(err)
+		}
+	} else {
+		s.forwardOrPanicIfRequired(nil, Request{
+			entity:   EntityIDs{Ids: []string{entity.Id}},
+			method:   StartEntities,
+			destNode: destNode,
+		})
+	}
+
+	logger.Infof("Handed entity %s over from node %s to node %s", entity.Id, entity.Node, destNode)
+	drained.Node = destNode
+	return
+}
@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/myntra/goscheduler/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticCoordinator ranks the members of a fixed cluster by rendezvous hashing
+type staticCoordinator struct {
+	members []string
+}
+
+func (c staticCoordinator) WhoAmI() (string, error) {
+	return c.members[0], nil
+}
+
+func (c staticCoordinator) Lookup(key string) (string, error) {
+	return rendezvous(key, c.members)
+}
+
+func (c staticCoordinator) LookupN(key string, n int) ([]string, error) {
+	return rank(key, c.members)[:n], nil
+}
+
+func (c staticCoordinator) GetReachableMembers() ([]string, error) {
+	return c.members, nil
+}
+
+func TestSupervisor_LookupSkipsCordonedNodes(t *testing.T) {
+	members := []string{"127.0.0.1:9091", "127.0.0.1:9092", "127.0.0.1:9093"}
+	supervisor := &Supervisor{coordinator: staticCoordinator{members}, cordoned: newCordons()}
+
+	owner, err := supervisor.lookup("app.0")
+	assert.Nil(t, err)
+
+	supervisor.cordoned.set([]store.CordonedNode{{Node: owner}})
+	next, err := supervisor.lookup("app.0")
+	assert.Nil(t, err)
+	assert.True(t, next != owner)
+	assert.Equal(t, rank("app.0", members)[1], next)
+
+	// with every member cordoned the partitions stay on their owner
+	supervisor.cordoned.set([]store.CordonedNode{{Node: members[0]}, {Node: members[1]}, {Node: members[2]}})
+	fallback, err := supervisor.lookup("app.0")
+	assert.Nil(t, err)
+	assert.Equal(t, owner, fallback)
+}
+
+func TestSupervisor_CordonedNodes(t *testing.T) {
+	supervisor := &Supervisor{cordoned: newCordons()}
+	supervisor.cordoned.set([]store.CordonedNode{{Node: "127.0.0.1:9092"}, {Node: "127.0.0.1:9091"}})
+	assert.Equal(t, []string{"127.0.0.1:9091", "127.0.0.1:9092"}, supervisor.CordonedNodes())
+}
@@ -29,6 +29,9 @@ type AppNames struct {
 	Names []string
 }
 
+// StatusRequest asks a node for its status.
+type StatusRequest struct{}
+
 // Request represents a request to be sent to a remote node.
 type Request struct {
 	entity   interface{} // The entity to be sent.
@@ -0,0 +1,229 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	e "github.com/myntra/goscheduler/cluster_entity"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/uber/tchannel-go/json"
+)
+
+// nodeStatusTimeout bounds the wait for the status of a node, which is then reported unhealthy
+const nodeStatusTimeout = 2 * time.Second
+
+// NodeState is the status of a node of the cluster, as reported by the node itself
+type NodeState struct {
+	Address          string     `json:"address"`
+	Healthy          bool       `json:"healthy"`
+	Cordoned         bool       `json:"cordoned"`
+	Error            string     `json:"error,omitempty"`
+	Self             bool       `json:"self"`
+	Version          string     `json:"version,omitempty"`
+	StartedAt        *time.Time `json:"startedAt,omitempty"`
+	Partitions       []string   `json:"partitions"`
+	MaxPollLagMillis int64      `json:"maxPollLagMillis"`
+	Backlog          int        `json:"backlog"`
+}
+
+// PartitionAssignment is the node running a partition of an app, as recorded in DB, next to the node owning it
+// according to the coordinator
+type PartitionAssignment struct {
+	Id          string `json:"id"`
+	AppId       string `json:"appId"`
+	PartitionId int    `json:"partitionId"`
+	Node        string `json:"node"`
+	Status      string `json:"status"`
+	Owner       string `json:"owner"`
+	Reachable   bool   `json:"reachable"`
+}
+
+// NodeStatusHandler returns the status of this node to the node serving the cluster API
+func (s *Supervisor) NodeStatusHandler(ctx json.Context, request *StatusRequest) (*NodeState, error) {
+	status := s.localStatus()
+	return &status, nil
+}
+
+// localStatus returns the partitions polled by this node, their largest poll lag and the callbacks waiting for a
+// worker
+func (s *Supervisor) localStatus() NodeState {
+	partitions := s.entities.Keys()
+	sort.Strings(partitions)
+
+	owned := make(map[string]bool, len(partitions))
+	for _, id := range partitions {
+		owned[id] = true
+	}
+
+	var maxLag int64
+	for _, lag := range diagnostics.Default().PollLags(time.Now()) {
+		id := lag.AppId + constants.PollerKeySep + strconv.Itoa(lag.PartitionId)
+		if owned[id] && lag.LagMillis > maxLag {
+			maxLag = lag.LagMillis
+		}
+	}
+
+	return NodeState{
+		Address:          s.address,
+		Healthy:          true,
+		Version:          constants.Version,
+		StartedAt:        &s.started,
+		Partitions:       partitions,
+		MaxPollLagMillis: maxLag,
+		Backlog:          store.CallbackBacklog(),
+	}
+}
+
+// remoteStatus asks a member of the cluster for its status
+func (s *Supervisor) remoteStatus(node string) NodeState {
+	ctx, cancel := json.NewContext(nodeStatusTimeout)
+	defer cancel()
+
+	var status NodeState
+	client := json.NewClient(s.channel, s.clusterName, &json.ClientOptions{HostPort: node})
+	if err := client.Call(ctx, NodeStatus, &StatusRequest{}, &status); err != nil {
+		logger.Errorf("Getting the status of node %s failed with error %v", node, err)
+		return NodeState{Address: node, Error: err.Error()}
+	}
+	return status
+}
+
+// Nodes returns the status of every reachable member of the cluster, along with the unreachable nodes still
+// recorded in DB as running partitions
+func (s *Supervisor) Nodes() []NodeState {
+	var members []string
+	if s.coordinator != nil {
+		var err error
+		if members, err = s.coordinator.GetReachableMembers(); err != nil {
+			logger.Errorf("Error getting reachable members %+v", err)
+		}
+	}
+
+	statuses := make([]NodeState, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		if member == s.address {
+			statuses[i] = s.localStatus()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, member string) {
+			defer wg.Done()
+			statuses[i] = s.remoteStatus(member)
+		}(i, member)
+	}
+	wg.Wait()
+
+	statuses = append(statuses, unreachableNodes(s.clusterDao.GetAllEntitiesInfo(), members)...)
+	cordoned := s.cordoned.get()
+	for i := range statuses {
+		statuses[i].Self = statuses[i].Address == s.address
+		statuses[i].Cordoned = cordoned[statuses[i].Address]
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Address < statuses[j].Address
+	})
+	return statuses
+}
+
+// unreachableNodes returns the nodes recorded as running entities which are not members of the cluster
+func unreachableNodes(entities []e.EntityInfo, members []string) []NodeState {
+	reachable := make(map[string]bool, len(members))
+	for _, member := range members {
+		reachable[member] = true
+	}
+
+	nodes := make(map[string]*NodeState)
+	var addresses []string
+	for _, entity := range entities {
+		if entity.Status != RUNNING || reachable[entity.Node] {
+			continue
+		}
+		node, exists := nodes[entity.Node]
+		if !exists {
+			node = &NodeState{Address: entity.Node, Error: "not a reachable member of the cluster"}
+			nodes[entity.Node] = node
+			addresses = append(addresses, entity.Node)
+		}
+		node.Partitions = append(node.Partitions, entity.Id)
+	}
+
+	unreachable := make([]NodeState, 0, len(addresses))
+	for _, address := range addresses {
+		sort.Strings(nodes[address].Partitions)
+		unreachable = append(unreachable, *nodes[address])
+	}
+	return unreachable
+}
+
+// Partitions returns every partition recorded in DB with the node running it and the node owning it according to
+// the coordinator, which differ while the partition is handed over
+func (s *Supervisor) Partitions() []PartitionAssignment {
+	reachable := make(map[string]bool)
+	if s.coordinator != nil {
+		members, err := s.coordinator.GetReachableMembers()
+		if err != nil {
+			logger.Errorf("Error getting reachable members %+v", err)
+		}
+		for _, member := range members {
+			reachable[member] = true
+		}
+	}
+
+	entities := s.clusterDao.GetAllEntitiesInfo()
+	partitions := make([]PartitionAssignment, 0, len(entities))
+	for _, entity := range entities {
+		partition := PartitionAssignment{
+			Id:          entity.Id,
+			AppId:       entity.GetAppName(),
+			PartitionId: entity.GetPartitionId(),
+			Node:        entity.Node,
+			Status:      entityStatus(entity.Status),
+			Reachable:   reachable[entity.Node],
+		}
+		if s.coordinator != nil {
+			partition.Owner, _ = s.lookup(entity.Id)
+		}
+		partitions = append(partitions, partition)
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].AppId != partitions[j].AppId {
+			return partitions[i].AppId < partitions[j].AppId
+		}
+		return partitions[i].PartitionId < partitions[j].PartitionId
+	})
+	return partitions
+}
+
+func entityStatus(status int) string {
+	if status == RUNNING {
+		return "RUNNING"
+	}
+	return "STOPPED"
+}
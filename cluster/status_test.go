@@ -0,0 +1,23 @@
+package cluster
+
+import (
+	"testing"
+
+	e "github.com/myntra/goscheduler/cluster_entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnreachableNodes(t *testing.T) {
+	entities := []e.EntityInfo{
+		{Id: "app.1", Node: "127.0.0.1:9092", Status: RUNNING},
+		{Id: "app.0", Node: "127.0.0.1:9092", Status: RUNNING},
+		{Id: "app.2", Node: "127.0.0.1:9091", Status: RUNNING},
+		{Id: "app.3", Node: "127.0.0.1:9093", Status: STOPPED},
+	}
+
+	nodes := unreachableNodes(entities, []string{"127.0.0.1:9091"})
+	assert.Equal(t, 1, len(nodes))
+	assert.Equal(t, "127.0.0.1:9092", nodes[0].Address)
+	assert.False(t, nodes[0].Healthy)
+	assert.Equal(t, []string{"app.0", "app.1"}, nodes[0].Partitions)
+}
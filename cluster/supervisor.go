@@ -23,10 +23,10 @@ import (
 	json2 "encoding/json"
 	"errors"
 	"fmt"
-	"github.com/golang/glog"
 	e "github.com/myntra/goscheduler/cluster_entity"
 	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
 	"github.com/myntra/goscheduler/store"
 	cmap "github.com/orcaman/concurrent-map"
@@ -54,6 +54,8 @@ const (
 	StartEntities    = "StartEntities"
 	StopEntities     = "StopEntities"
 	AppDetailsUpdate = "AppDetailsUpdate"
+	NodeStatus       = "NodeStatus"
+	CordonUpdate     = "CordonUpdate"
 )
 
 const (
@@ -67,7 +69,12 @@ type Supervisor struct {
 	clusterName   string
 	address       string
 	ringpop       *ringpop.Ringpop
+	coordinator   Coordinator
 	channel       *tchannel.Channel
+	tokens        cmap.ConcurrentMap
+	stop          chan struct{}
+	started       time.Time
+	cordoned      *cordons
 	entities      cmap.ConcurrentMap
 	entityFactory e.EntityFactory
 	clusterDao    dao.ClusterDao
@@ -86,6 +93,7 @@ type options struct {
 	statsD                bark.StatsReporter
 	reconciliationEnabled bool
 	reconciliationOffset  int
+	coordinator           PluggableCoordinator
 }
 
 var defaultOptions = options{
@@ -170,18 +178,29 @@ func WithReconciliationOffset(reconciliationOffset int) Option {
 	}
 }
 
+// WithCoordinator coordinates the cluster through the coordinator instead of ringpop
+func WithCoordinator(coordinator PluggableCoordinator) Option {
+	return func(o *options) {
+		o.coordinator = coordinator
+	}
+}
+
 func NewSupervisor(entityFactory e.EntityFactory, clusterDao dao.ClusterDao, monitor p.Monitor, opt ...Option) *Supervisor {
 	opts := defaultOptions
 	for _, o := range opt {
 		o(&opts)
 	}
 
-	glog.Infof("Starting supervisor with options : %+v", opts)
+	logger.Infof("Starting supervisor with options : %+v", opts)
 
 	supervisor := &Supervisor{
 		opt:           opts,
 		clusterName:   opts.clusterName,
 		entities:      cmap.New(),
+		tokens:        cmap.New(),
+		stop:          make(chan struct{}),
+		started:       time.Now(),
+		cordoned:      newCordons(),
 		entityFactory: entityFactory,
 		clusterDao:    clusterDao,
 		monitor:       monitor,
@@ -194,12 +213,12 @@ func NewSupervisor(entityFactory e.EntityFactory, clusterDao dao.ClusterDao, mon
 func (s *Supervisor) InitRingPop() {
 	var err error
 
-	glog.Infof("Supervisor: %+v", s)
+	logger.Infof("Supervisor: %+v", s)
 	s.channel, err = tchannel.NewChannel(s.opt.clusterName, nil)
 	if err != nil {
 		panic("channel did not create successfully")
 	}
-	glog.Infof("TChannel created %+v", s.channel)
+	logger.Infof("TChannel created %+v", s.channel)
 
 	options := []ringpop.Option{
 		ringpop.Channel(s.channel),
@@ -209,34 +228,35 @@ func (s *Supervisor) InitRingPop() {
 		})}
 
 	if s.opt.logEnabled {
-		logger := logrus.New()
-		logger.Out = os.Stdout
+		ringpopLogger := logrus.New()
+		ringpopLogger.Out = os.Stdout
 		options = append(options,
-			ringpop.Logger(bark.NewLoggerFromLogrus(logger)),
+			ringpop.Logger(bark.NewLoggerFromLogrus(ringpopLogger)),
 			ringpop.LogLevels(s.opt.logLevels),
 			ringpop.Statter(s.opt.statsD))
 	}
 
-	glog.Infof("ringpop options: %+v", options)
+	logger.Infof("ringpop options: %+v", options)
 	s.ringpop, err = ringpop.New(s.opt.clusterName, options...)
 	if err != nil {
 		panic(fmt.Sprintf("Ringpop cluster creation failed with error %v", err))
 	}
 
-	glog.Infof("Ringpop cluster created %+v", s.ringpop)
+	logger.Infof("Ringpop cluster created %+v", s.ringpop)
 
 	if err = s.channel.ListenAndServe(s.opt.address); err != nil {
 		panic(fmt.Sprintf("Could not listen on given host port: %v", err))
 	}
 
 	s.ringpop.AddListener(s)
+	s.coordinator = ringpopCoordinator{s.ringpop}
 
 	bootstrapOpts := &swim.BootstrapOptions{
 		DiscoverProvider: statichosts.New(s.opt.bootStrapServers...),
 		JoinSize:         s.opt.joinSize,
 	}
 
-	glog.Infof("Supervisor: %+v", s.ringpop)
+	logger.Infof("Supervisor: %+v", s.ringpop)
 	if _, err = s.ringpop.Bootstrap(bootstrapOpts); err != nil {
 		panic(fmt.Sprintf("Ringpop bootstrap failed: %v", err))
 	}
@@ -257,10 +277,140 @@ func (s *Supervisor) CloseRingPop() {
 	s.channel.Close()
 }
 
+// InitCoordination joins the cluster through the coordinator of the options, or through ringpop when there is none
+func (s *Supervisor) InitCoordination() {
+	if s.opt.coordinator == nil {
+		s.InitRingPop()
+		return
+	}
+
+	var err error
+	s.channel, err = tchannel.NewChannel(s.opt.clusterName, nil)
+	if err != nil {
+		panic("channel did not create successfully")
+	}
+	if err = s.channel.ListenAndServe(s.opt.address); err != nil {
+		panic(fmt.Sprintf("Could not listen on given host port: %v", err))
+	}
+	if err = s.RegisterHandler(); err != nil {
+		panic(fmt.Sprintf("Error while registering handler %+v", err))
+	}
+
+	if err = s.opt.coordinator.Join(s); err != nil {
+		panic(fmt.Sprintf("Joining the cluster failed with error %v", err))
+	}
+	s.coordinator = s.opt.coordinator
+	if s.address, err = s.coordinator.WhoAmI(); err != nil {
+		panic(fmt.Sprintf("Error initializing coordinator %v", err))
+	}
+
+	go s.fence(s.opt.coordinator)
+}
+
+// CloseCoordination leaves the cluster and closes TChannel gracefully
+func (s *Supervisor) CloseCoordination() {
+	if s.opt.coordinator == nil {
+		s.CloseRingPop()
+		return
+	}
+	close(s.stop)
+	s.opt.coordinator.Leave()
+	s.channel.Close()
+}
+
+// acquire takes the fencing token of the entity when the cluster is coordinated by a pluggable coordinator
+func (s *Supervisor) acquire(id string) error {
+	coordinator, ok := s.coordinator.(PluggableCoordinator)
+	if !ok {
+		return nil
+	}
+	token, err := coordinator.Acquire(id)
+	if err != nil {
+		return err
+	}
+	s.tokens.Set(id, token)
+	return nil
+}
+
+// release gives up the fencing token of the entity
+func (s *Supervisor) release(id string) {
+	token, exists := s.tokens.Get(id)
+	if !exists {
+		return
+	}
+	s.tokens.Remove(id)
+	if err := s.coordinator.(PluggableCoordinator).Release(id, token.(int64)); err != nil {
+		logger.Errorf("Releasing entity %s failed with error %v", id, err)
+	}
+}
+
+// fence stops on this node the entities whose fencing token was superseded, so that a node cut off from the
+// coordinator does not keep polling them next to their new owner
+func (s *Supervisor) fence(coordinator PluggableCoordinator) {
+	ticker := time.NewTicker(coordinator.FencingInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		for id, token := range s.tokens.Items() {
+			owns, err := coordinator.Owns(id, token.(int64))
+			if err != nil {
+				logger.Errorf("Checking the ownership of entity %s failed with error %v", id, err)
+				continue
+			}
+			if !owns {
+				logger.Errorf("Entity %s was taken over by another node, stopping it on %s", id, s.address)
+				s.stopLocally(id)
+			}
+		}
+	}
+}
+
+// stopLocally stops the entity on this node without recording it in DB, as the entity belongs to another node
+func (s *Supervisor) stopLocally(id string) {
+	if value, exists := s.entities.Get(id); exists {
+		value.(RecoverableEntity).Stop()
+		s.entities.Remove(id)
+	}
+	s.tokens.Remove(id)
+}
+
+// MembersRemoved offloads the nodes which left the cluster coordinated by a pluggable coordinator
+func (s *Supervisor) MembersRemoved(nodes []string) {
+	for _, node := range nodes {
+		if node != s.address {
+			s.OffloadOrPanic(node)
+		}
+	}
+}
+
+// LeaseLost stops every entity of this node, as the other nodes are taking them over
+func (s *Supervisor) LeaseLost() {
+	logger.Errorf("%s lost its membership of the cluster, stopping its entities", s.address)
+	for _, id := range s.entities.Keys() {
+		s.stopLocally(id)
+	}
+}
+
+// Rejoined boots the entities again once this node is back in the cluster
+func (s *Supervisor) Rejoined() {
+	logger.Infof("%s joined the cluster again", s.address)
+	s.Boot()
+}
+
 // StartEntity starts an entity if it is not already running.
 // Adds entity in in-memory concurrent map if the entity does not exist.
 // Updates DB with entity status as Running.
 func (s *Supervisor) StartEntity(id string) (bool, error) {
+	if err := s.acquire(id); err != nil {
+		return false, err
+	}
+
 	value, exists := s.entities.Get(id)
 	if exists == false {
 		entity := s.entityFactory.CreateEntity(id)
@@ -285,6 +435,7 @@ func (s *Supervisor) StopEntity(id string) (bool, error) {
 		recoverableEntity := value.(RecoverableEntity)
 		recoverableEntity.Stop()
 		s.entities.Remove(id)
+		s.release(id)
 		err = s.clusterDao.UpdateEntityStatus(id, s.address, STOPPED)
 	}
 	return exists, err
@@ -294,7 +445,7 @@ func (s *Supervisor) StopEntity(id string) (bool, error) {
 // Starts the entities on current node if destination node is same as own address
 // or forward the entities to respective destination node
 func (s *Supervisor) StartEntities(ctx json.Context, request *EntityIDs) (res *Response, err error) {
-	glog.Infof("StartEntities called with arg %+v", request)
+	logger.Infof("StartEntities called with arg %+v", request)
 	res = &Response{
 		ServerAddress: s.address,
 		Error:         "",
@@ -309,7 +460,7 @@ func (s *Supervisor) StartEntities(ctx json.Context, request *EntityIDs) (res *R
 	}()
 
 	for _, id := range request.Ids {
-		destNode, err := s.ringpop.Lookup(id)
+		destNode, err := s.lookup(id)
 		if err != nil {
 			panic(err)
 		}
@@ -335,7 +486,7 @@ func (s *Supervisor) StartEntities(ctx json.Context, request *EntityIDs) (res *R
 // Sops the entities on current node if destination node is same as own address
 // or forward the entities to respective destination node
 func (s *Supervisor) StopEntities(ctx json.Context, request *EntityIDs) (res *Response, err error) {
-	glog.Infof("StopEntities called with arg %+v", request)
+	logger.Infof("StopEntities called with arg %+v", request)
 	res = &Response{
 		ServerAddress: s.address,
 		Error:         "",
@@ -355,7 +506,7 @@ func (s *Supervisor) StopEntities(ctx json.Context, request *EntityIDs) (res *Re
 			panic(err)
 		}
 
-		destNode, err := s.ringpop.Lookup(id)
+		destNode, err := s.lookup(id)
 		if err != nil {
 			panic(err)
 		}
@@ -385,14 +536,14 @@ func (s *Supervisor) StopEntities(ctx json.Context, request *EntityIDs) (res *Re
 func (s *Supervisor) forwardEntity(ctx json.Context, r Request) ([]byte, error) {
 	var newRequest interface{}
 	var ids []string
-	headers, _ := json2.Marshal(make(map[string]string))
-
+	headerMap := make(map[string]string)
 	if ctx != nil {
-		var err error
-		headers, err = json2.Marshal(ctx.Headers())
-		if err != nil {
-			return nil, err
-		}
+		headerMap = ctx.Headers()
+	}
+
+	headers, err := json2.Marshal(headerMap)
+	if err != nil {
+		return nil, err
 	}
 
 	forwardOptions := &forward.Options{
@@ -419,11 +570,37 @@ func (s *Supervisor) forwardEntity(ctx json.Context, r Request) ([]byte, error)
 		return nil, errors.New(fmt.Sprintf("Exception while marshalling %+v", newRequest))
 	}
 
+	if s.ringpop == nil {
+		return s.call(r.destNode, r.method, newRequest, headerMap, forwardOptions)
+	}
+
 	handle, err := s.ringpop.Forward(r.destNode, ids, bytes, s.clusterName, r.method, tchannel.JSON, forwardOptions)
-	glog.Info(r.destNode, ids, bytes, s.clusterName, r.method, tchannel.JSON, forwardOptions)
+	logger.Info(r.destNode, ids, bytes, s.clusterName, r.method, tchannel.JSON, forwardOptions)
 	return handle, err
 }
 
+// call calls the method on destNode over TChannel with the retries of the forward options, as forwarding is only
+// available to the members of a ringpop ring
+func (s *Supervisor) call(destNode, method string, request interface{}, headers map[string]string, opts *forward.Options) ([]byte, error) {
+	client := json.NewClient(s.channel, s.clusterName, &json.ClientOptions{HostPort: destNode})
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var response Response
+		ctx, cancel := json.NewContext(opts.Timeout)
+		err = client.Call(json.WithHeaders(ctx, headers), method, request, &response)
+		cancel()
+		if err == nil {
+			return json2.Marshal(response)
+		}
+		if attempt >= opts.MaxRetries || attempt >= len(opts.RetrySchedule) {
+			return nil, err
+		}
+		logger.Errorf("Calling %s on node %s failed with error %v, retrying", method, destNode, err)
+		time.Sleep(opts.RetrySchedule[attempt])
+	}
+}
+
 // forward clusterEntity with registered method to entity node
 func (s *Supervisor) forward(ctx json.Context, entity e.EntityInfo, method string) ([]byte, error) {
 	request := Request{
@@ -448,7 +625,7 @@ func (s *Supervisor) forwardOrPanic(entity e.EntityInfo, method string) {
 // TODO: Change function name
 func (s *Supervisor) forwardOrPanicIfRequired(ctx json.Context, r Request) {
 	var response Response
-	glog.Infof("Forwarding entity %+v to the node %s from node %s", r.entity, r.destNode, s.address)
+	logger.Infof("Forwarding entity %+v to the node %s from node %s", r.entity, r.destNode, s.address)
 	handle, err := s.forwardEntity(ctx, r)
 	if handle != nil {
 		if err = json2.Unmarshal(handle, &response); err != nil {
@@ -457,21 +634,21 @@ func (s *Supervisor) forwardOrPanicIfRequired(ctx json.Context, r Request) {
 	} else if err != nil {
 		panic(errors.New(fmt.Sprintf("Forwarding entity %+v to node %s from node %s failed with error : %+v", r.entity, r.destNode, s.address, err.Error())))
 	}
-	glog.Infof("Forwarding entity %+v to node %s from node %s succeeded with response %+v", r.entity, r.destNode, s.address, response)
+	logger.Infof("Forwarding entity %+v to node %s from node %s succeeded with response %+v", r.entity, r.destNode, s.address, response)
 }
 
 // appDetailsUpdateBroadcast broadcasts app update message to all other reachable nodes
 func (s *Supervisor) appDetailsUpdateBroadcast(appName string) {
-	glog.Infof("Broadcasting app update event for app %s", appName)
+	logger.Infof("Broadcasting app update event for app %s", appName)
 
-	reachableNodes, err := s.ringpop.GetReachableMembers()
+	reachableNodes, err := s.coordinator.GetReachableMembers()
 	if err != nil {
-		glog.Errorf("Error getting reachable members %+v", err)
+		logger.Errorf("Error getting reachable members %+v", err)
 		return
 	}
 
 	for _, node := range reachableNodes {
-		glog.Infof("Broadcasting app update event for app %s to %s", appName, node)
+		logger.Infof("Broadcasting app update event for app %s to %s", appName, node)
 		if node == s.address {
 			s.AppDetailsUpdateHandler(appName)
 			continue
@@ -489,7 +666,7 @@ func (s *Supervisor) appDetailsUpdateBroadcast(appName string) {
 // AppDetailsUpdateEventHandler receives app update event
 // Invalidates cache based on appName
 func (s *Supervisor) AppDetailsUpdateEventHandler(ctx json.Context, request *AppNames) (*Response, error) {
-	glog.Infof("Called handler for appDetails update broadcast")
+	logger.Infof("Called handler for appDetails update broadcast")
 	response := Response{
 		ServerAddress: s.address,
 		Error:         "",
@@ -513,6 +690,7 @@ func (s *Supervisor) AppDetailsUpdateEventHandler(ctx json.Context, request *App
 // Boot fetch all the entities from DB and starts them one by one
 // panics and stops the process in case there is any issue in starting any entity
 func (s *Supervisor) Boot() {
+	_ = s.refreshCordons()
 	for _, entity := range s.clusterDao.GetAllEntitiesInfo() {
 		if err := s.BootEntity(entity, false); err != nil {
 			panic(err)
@@ -522,7 +700,7 @@ func (s *Supervisor) Boot() {
 
 // StopNode stops all the entities assigned to that node before it is brought down
 func (s *Supervisor) StopNode() {
-	glog.Info("!!!!!!Inside StopNode!!!!!!")
+	logger.Info("!!!!!!Inside StopNode!!!!!!")
 	for _, id := range s.entities.Keys() {
 		if _, err := s.StopEntity(id); err != nil {
 			panic(errors.New(fmt.Sprintf("Error while stopping key: %s, error: %+v", id, err)))
@@ -538,7 +716,7 @@ func (s *Supervisor) BootEntity(entity e.EntityInfo, forward bool) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = r.(error)
-			glog.Errorf("Boot entity %+v failed with error %+v", entity, err)
+			logger.Errorf("Boot entity %+v failed with error %+v", entity, err)
 		}
 	}()
 
@@ -548,16 +726,16 @@ func (s *Supervisor) BootEntity(entity e.EntityInfo, forward bool) (err error) {
 		panic(errors.New(fmt.Sprintf("Error: %+v, while getting app status while booting", err)))
 	}
 	if app.Active == false {
-		glog.Infof("Not activating poller %s for deactivated app %s", entity.Id, app.AppId)
+		logger.Infof("Not activating poller %s for deactivated app %s", entity.Id, app.AppId)
 		return nil
 	}
 
-	members, err := s.ringpop.GetReachableMembers()
+	members, err := s.coordinator.GetReachableMembers()
 	if err != nil {
 		panic(errors.New(fmt.Sprintf("Error %s on node %s while querying for reachable mebers", err.Error(), s.address)))
 	}
 
-	glog.Infof("reachableMembers %+v", members)
+	logger.Infof("reachableMembers %+v", members)
 
 	// Create a map of reachable nodes
 	// A self node is marked as false as we are not required to forward requests to them
@@ -568,12 +746,12 @@ func (s *Supervisor) BootEntity(entity e.EntityInfo, forward bool) (err error) {
 	reachableMembers[s.address] = false
 
 	// Check which node the current entity belongs to
-	destNode, err := s.ringpop.Lookup(entity.Id)
+	destNode, err := s.lookup(entity.Id)
 	if err != nil {
 		panic(errors.New(fmt.Sprintf("Lookup failed for entity %s with error %+v", entity.Id, err)))
 	}
 
-	glog.Infof("destination node %s for entity %s", destNode, entity.Id)
+	logger.Infof("destination node %s for entity %s", destNode, entity.Id)
 
 	// If destination node is own address start the entity on current node
 	// else forward the entity to destination node if required
@@ -608,7 +786,7 @@ func (s *Supervisor) BootEntity(entity e.EntityInfo, forward bool) (err error) {
 // 3. Start the entity if the current node is the destination node
 // 4. Reconcile schedules if there was any miss during offloading
 func (s *Supervisor) OffloadOrPanic(nodeName string) {
-	glog.Infof("%s offloading %s", s.address, nodeName)
+	logger.Infof("%s offloading %s", s.address, nodeName)
 
 	//Note: Assuming MembersRemoved will be called only if the node is down, which means all entities are stopped on that node
 	for _, entity := range s.clusterDao.GetAllEntitiesInfoOfNode(nodeName) {
@@ -618,17 +796,17 @@ func (s *Supervisor) OffloadOrPanic(nodeName string) {
 			panic(errors.New(fmt.Sprintf("Could not get app: %s", appName)))
 		}
 		if !app.Active {
-			glog.Infof("Not starting the entity %+v as its app is not active", entity)
+			logger.Infof("Not starting the entity %+v as its app is not active", entity)
 			continue
 		}
 
-		destNode, err := s.ringpop.Lookup(entity.Id)
+		destNode, err := s.lookup(entity.Id)
 		if err != nil {
 			panic(errors.New(fmt.Sprintf("Lookup failed with error %s", err)))
 		}
 
 		if destNode == s.address {
-			glog.Infof("Starting entity %s on %s from old node %s", entity.Id, s.address, nodeName)
+			logger.Infof("Starting entity %s on %s from old node %s", entity.Id, s.address, nodeName)
 			if _, err := s.StartEntity(entity.Id); err != nil {
 				panic(errors.New(fmt.Sprintf("Error starting entity: %s", entity.Id)))
 			}
@@ -647,10 +825,10 @@ func (s *Supervisor) AppDetailsUpdateHandler(appName string) {
 
 // RegisterHandler registers actions against respective methods
 func (s *Supervisor) RegisterHandler() error {
-	hmap := map[string]interface{}{StartEntities: s.StartEntities, StopEntities: s.StopEntities, AppDetailsUpdate: s.AppDetailsUpdateEventHandler}
+	hmap := map[string]interface{}{StartEntities: s.StartEntities, StopEntities: s.StopEntities, AppDetailsUpdate: s.AppDetailsUpdateEventHandler, NodeStatus: s.NodeStatusHandler, CordonUpdate: s.CordonUpdateHandler}
 
 	return json.Register(s.channel, hmap, func(ctx context.Context, err error) {
-		glog.Errorf("error occurred: %v %+v", err, ctx)
+		logger.Errorf("error occurred: %v %+v", err, ctx)
 	})
 }
 
@@ -660,7 +838,7 @@ func (s *Supervisor) HandleEvent(event events.Event) {
 	switch v := event.(type) {
 	case events.RingChangedEvent:
 		change := event.(events.RingChangedEvent)
-		glog.Infof("Ring updated %+v", v)
+		logger.Infof("Ring updated %+v", v)
 
 		for _, server := range change.ServersRemoved {
 			if s.address == server {
@@ -670,8 +848,8 @@ func (s *Supervisor) HandleEvent(event events.Event) {
 		}
 		break
 	case swim.MemberlistChangesReceivedEvent:
-		members, _ := s.ringpop.GetReachableMembers()
-		glog.Infof("Ring updated %+v", members)
+		members, _ := s.coordinator.GetReachableMembers()
+		logger.Infof("Ring updated %+v", members)
 		break
 	case membership.ChangeEvent:
 	case swim.MakeNodeStatusEvent:
@@ -694,22 +872,26 @@ func (s *Supervisor) HandleEvent(event events.Event) {
 	case forward.RequestForwardedEvent:
 	case forward.SuccessEvent:
 	default:
-		glog.Errorf("Received unhandled type %T", v)
+		logger.Errorf("Received unhandled type %T", v)
 	}
 }
 
 // DeactivateApp stops all the pollers for the app
 // Forwards the pollers to stop in case destination node is different
 func (s *Supervisor) DeactivateApp(app store.App) {
-	glog.Infof("Disabling app %s", app.AppId)
+	logger.Infof("Disabling app %s", app.AppId)
 	var partition uint32 = 0
 	for ; partition < app.Partitions; partition++ {
 		entity := e.EntityInfo{Id: app.AppId + constants.PollerKeySep + strconv.Itoa(int(partition))}
-		glog.Infof("Disabling entity %s", entity.Id)
-		destNode, err := s.ringpop.Lookup(entity.Id)
+		logger.Infof("Disabling entity %s", entity.Id)
+		destNode, err := s.lookup(entity.Id)
 		if err != nil {
 			panic(errors.New(fmt.Sprintf("Lookup failed with error %s", err)))
 		}
+		// A cordoned node keeps running its partitions although it no longer owns them
+		if recorded := s.clusterDao.GetEntityInfo(entity.Id); recorded.Status == RUNNING && recorded.Node != "" {
+			destNode = recorded.Node
+		}
 		if destNode != s.address {
 			entity.Node = destNode
 			s.forwardOrPanic(entity, StopEntities)
@@ -727,12 +909,12 @@ func (s *Supervisor) DeactivateApp(app store.App) {
 // Forwards the pollers to start in case destination node is different
 // TODO: Try to combine above methods
 func (s Supervisor) ActivateApp(app store.App) {
-	glog.Infof("Enabling app %s", app.AppId)
+	logger.Infof("Enabling app %s", app.AppId)
 	var partition uint32 = 0
 	for ; partition < app.Partitions; partition++ {
 		entity := e.EntityInfo{Id: app.AppId + constants.PollerKeySep + strconv.Itoa(int(partition))}
-		glog.Infof("Enabling entity %s", entity.Id)
-		destNode, err := s.ringpop.Lookup(entity.Id)
+		logger.Infof("Enabling entity %s", entity.Id)
+		destNode, err := s.lookup(entity.Id)
 		if err != nil {
 			panic(errors.New(fmt.Sprintf("Lookup failed with error %s", err)))
 		}
@@ -750,10 +932,15 @@ func (s Supervisor) ActivateApp(app store.App) {
 	s.appDetailsUpdateBroadcast(app.AppId)
 }
 
+// UpdateApp broadcasts the update of an app so that every node reloads it
+func (s *Supervisor) UpdateApp(appName string) {
+	s.appDetailsUpdateBroadcast(appName)
+}
+
 // Retry a missed schedule based on app, partitionId and timeOffset
 func (s *Supervisor) fetchAndRetrySchedule(app store.App, partitionId int, timeOffset int) {
-	glog.Infof("Retrying for App:-> %+v", app)
-	glog.Infof("App reconcile offset:-> %d", timeOffset)
+	logger.Infof("Retrying for App:-> %+v", app)
+	logger.Infof("App reconcile offset:-> %d", timeOffset)
 
 	year, month, day := time.Now().Date()
 	hr, min, _ := time.Now().Clock()
@@ -763,7 +950,7 @@ func (s *Supervisor) fetchAndRetrySchedule(app store.App, partitionId int, timeO
 
 		scheduleRetrieverImpl := s.entityFactory.GetEntityRetriever(app.AppId)
 		if err := scheduleRetrieverImpl.BulkAction(app, partitionId, timestamp, []store.Status{store.Scheduled, store.Miss}, store.Reconcile); err != nil {
-			glog.Infof("Error while reconciling for appId: %s, partitionId: %d, timestamp: %+v, err: %s",
+			logger.Infof("Error while reconciling for appId: %s, partitionId: %d, timestamp: %+v, err: %s",
 				app.AppId,
 				partitionId,
 				timestamp,
@@ -780,14 +967,14 @@ func (s *Supervisor) WaitForTermination() {
 	exit := make(chan bool, 1)
 
 	go func() {
-		glog.Info("Shutting down with Signal:", <-c)
-		glog.Flush()
+		logger.Info("Shutting down with Signal:", <-c)
+		logger.Flush()
 		s.StopNode()
-		s.CloseRingPop()
+		s.CloseCoordination()
 		exit <- true
 	}()
 
-	glog.Info("This is before end")
+	logger.Info("This is before end")
 	<-exit
-	glog.Info("This is end")
+	logger.Info("This is end")
 }
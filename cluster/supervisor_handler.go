@@ -32,4 +32,16 @@ type SupervisorHandler interface {
 	DeactivateApp(app store.App)
 	// ActivateApp activates the specified application.
 	ActivateApp(app store.App)
+	// UpdateApp notifies every node that the details of the specified application changed.
+	UpdateApp(appName string)
+	// Nodes returns the status of every node of the cluster.
+	Nodes() []NodeState
+	// Partitions returns the assignment of every partition to the nodes of the cluster.
+	Partitions() []PartitionAssignment
+	// Cordon stops assigning partitions to the node.
+	Cordon(node string) error
+	// Uncordon lets partitions be assigned to the node again.
+	Uncordon(node string) error
+	// Drain cordons the node and hands its partitions over to the other nodes.
+	Drain(node string) ([]DrainedPartition, error)
 }
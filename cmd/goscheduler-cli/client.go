@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// client is a thin wrapper over the goscheduler HTTP API
+type client struct {
+	addr       string
+	httpClient *http.Client
+}
+
+func newClient(addr string, timeout time.Duration) *client {
+	return &client{
+		addr:       strings.TrimRight(addr, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// apiError is returned when the server responds with a non 2xx status
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e apiError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// do sends a request to the given path and returns the raw response body
+func (c *client) do(method, path string, body interface{}) ([]byte, error) {
+	if body == nil {
+		return c.doRaw(method, path, "", nil)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.doRaw(method, path, "application/json", bytes.NewReader(b))
+}
+
+// doRaw sends a request with an already encoded body of the given content type
+func (c *client) doRaw(method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, c.addr+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, apiError{StatusCode: resp.StatusCode, Message: statusMessage(b)}
+	}
+	return b, nil
+}
+
+// statusMessage extracts the status message from an error response, falling back to the raw body
+func statusMessage(b []byte) string {
+	var resp struct {
+		Status struct {
+			StatusMessage string `json:"statusMessage"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(b, &resp); err == nil && resp.Status.StatusMessage != "" {
+		return resp.Status.StatusMessage
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// printJSON writes the response body to w as indented JSON
+func printJSON(w io.Writer, b []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "  "); err != nil {
+		_, err = w.Write(b)
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(w)
+	return err
+}
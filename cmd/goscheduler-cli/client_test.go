@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/goscheduler/schedules/1":
+			_, _ = w.Write([]byte(`{"status":{"statusCode":200},"data":{}}`))
+		case "/goscheduler/schedules/2":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":{"statusType":"FAIL","statusMessage":"schedule not found","statusCode":4001}}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("bad gateway"))
+		}
+	}))
+	defer server.Close()
+
+	c := newClient(server.URL+"/", time.Second)
+
+	if _, err := c.do(http.MethodGet, "/goscheduler/schedules/1", nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	_, err := c.do(http.MethodGet, "/goscheduler/schedules/2", nil)
+	if apiErr, ok := err.(apiError); !ok || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "schedule not found" {
+		t.Errorf("Expected not found apiError, got %v", err)
+	}
+
+	_, err = c.do(http.MethodGet, "/goscheduler/schedules/3", nil)
+	if apiErr, ok := err.(apiError); !ok || apiErr.Message != "bad gateway" {
+		t.Errorf("Expected raw body as message, got %v", err)
+	}
+}
+
+func TestPrintNewRuns(t *testing.T) {
+	seen := map[string]string{}
+	runs := []run{
+		{ScheduleId: "b", ScheduleTime: 120, Status: "SCHEDULED"},
+		{ScheduleId: "a", ScheduleTime: 60, Status: "SUCCESS"},
+	}
+
+	var out bytes.Buffer
+	printNewRuns(&out, runs, seen)
+	if lines := bytes.Count(out.Bytes(), []byte("\n")); lines != 2 {
+		t.Fatalf("Expected 2 lines, got %d", lines)
+	}
+	if bytes.Index(out.Bytes(), []byte(" a ")) > bytes.Index(out.Bytes(), []byte(" b ")) {
+		t.Errorf("Expected runs in schedule time order, got %s", out.String())
+	}
+
+	out.Reset()
+	printNewRuns(&out, []run{
+		{ScheduleId: "b", ScheduleTime: 120, Status: "SUCCESS"},
+		{ScheduleId: "a", ScheduleTime: 60, Status: "SUCCESS"},
+	}, seen)
+	if lines := bytes.Count(out.Bytes(), []byte("\n")); lines != 1 {
+		t.Errorf("Expected only the updated run, got %s", out.String())
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+type app struct {
+	AppId      string `json:"appId"`
+	Partitions uint32 `json:"partitions"`
+	Active     bool   `json:"active"`
+}
+
+type appsResponse struct {
+	Data struct {
+		Apps []app `json:"apps"`
+	} `json:"data"`
+}
+
+func newClusterCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Inspect the goscheduler cluster",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the health of the node and the registered apps",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := apiClient()
+			w := cmd.OutOrStdout()
+
+			if _, err := c.do(http.MethodGet, "/goscheduler/healthcheck", nil); err != nil {
+				fmt.Fprintf(w, "node %s: unhealthy (%s)\n", c.addr, err)
+				return err
+			}
+			fmt.Fprintf(w, "node %s: healthy\n", c.addr)
+
+			b, err := c.do(http.MethodGet, "/goscheduler/apps", nil)
+			if err != nil {
+				return err
+			}
+			var resp appsResponse
+			if err := json.Unmarshal(b, &resp); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(w, "\n%-30s %-10s %s\n", "APP", "PARTITIONS", "ACTIVE")
+			for _, a := range resp.Data.Apps {
+				fmt.Fprintf(w, "%-30s %-10d %t\n", a.AppId, a.Partitions, a.Active)
+			}
+			return nil
+		},
+	})
+	return cmd
+}
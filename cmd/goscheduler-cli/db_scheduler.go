@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dbSchedulerRecurring is the instance of the rows of the recurring tasks of db-scheduler
+const dbSchedulerRecurring = "recurring"
+
+// The layouts of the execution times of db-scheduler, as exported by the databases it supports
+var dbSchedulerTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// dbSchedulerMigrations translates the rows of the scheduled_tasks table to one-time schedule creation requests
+func dbSchedulerMigrations(appId string, tasks []map[string]string, callbacks callbacks) []migration {
+	migrations := make([]migration, 0, len(tasks))
+	for _, task := range tasks {
+		m := migration{Source: task["TASK_NAME"] + "/" + task["TASK_INSTANCE"]}
+		dbSchedulerMigration(&m, appId, task, callbacks)
+		migrations = append(migrations, m)
+	}
+	return migrations
+}
+
+func dbSchedulerMigration(m *migration, appId string, task map[string]string, callbacks callbacks) {
+	if task["TASK_INSTANCE"] == dbSchedulerRecurring {
+		m.Status = migrationSkipped
+		m.warn("recurring task defined in the application code, create it with a cronExpression or an every interval")
+		return
+	}
+
+	callback, ok := callbacks.lookup(task["TASK_NAME"])
+	if !ok {
+		m.reject(migrationInvalid, "no callback for the task %s, set --callback or map it in --callbacks", task["TASK_NAME"])
+		return
+	}
+
+	at, err := dbSchedulerTime(task["EXECUTION_TIME"])
+	if err != nil {
+		m.reject(migrationInvalid, "cannot parse the execution time %q", task["EXECUTION_TIME"])
+		return
+	}
+
+	fields := map[string]string{"task": task["TASK_NAME"], "instance": task["TASK_INSTANCE"]}
+	switch data := task["TASK_DATA"]; {
+	case strings.HasPrefix(data, `\x`):
+		m.warn("task data is binary and is not migrated")
+	case data != "":
+		fields["data"] = data
+	}
+	payload, _ := json.Marshal(fields)
+
+	if picked, _ := strconv.ParseBool(task["PICKED"]); picked {
+		m.warn("task was picked for execution by %s when exported", task["PICKED_BY"])
+	}
+
+	m.Schedule = map[string]interface{}{
+		"appId":        appId,
+		"payload":      string(payload),
+		"callback":     callback,
+		"scheduleTime": at.Unix(),
+	}
+}
+
+// dbSchedulerTime parses an execution time, in epoch milliseconds or as a timestamp which is in UTC unless it has an
+// offset
+func dbSchedulerTime(s string) (time.Time, error) {
+	if value, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(value/1000, 0), nil
+	}
+
+	var err error
+	for _, layout := range dbSchedulerTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+func newImportCommand() *cobra.Command {
+	var (
+		file   string
+		format string
+		dryRun bool
+	)
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Bulk create schedules from a CSV or JSON file",
+		Long: `Bulk create schedules from a CSV or JSON file.
+
+A JSON file holds an array of schedule creation requests. A CSV file has a header row naming
+the columns appId, payload, scheduleTime, cronExpression, every, rrule, statusCallback and
+pausePolicy, plus either a callback column holding the callback JSON or the callbackType,
+callbackUrl, callbackMethod and callbackHeaders columns for an http callback.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+			}
+
+			b, err := readInput(file)
+			if err != nil {
+				return err
+			}
+			schedules, err := parseSchedules(bytes.NewReader(b), format)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if dryRun {
+				out, err := json.Marshal(schedules)
+				if err != nil {
+					return err
+				}
+				return printJSON(w, out)
+			}
+
+			c := apiClient()
+			failed := 0
+			for i, schedule := range schedules {
+				if _, err := c.do(http.MethodPost, schedulesPath, schedule); err != nil {
+					failed++
+					fmt.Fprintf(w, "schedule %d: %s\n", i+1, err)
+				}
+			}
+			fmt.Fprintf(w, "created %d of %d schedules\n", len(schedules)-failed, len(schedules))
+			if failed > 0 {
+				return fmt.Errorf("%d schedules failed to import", failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "CSV or JSON file to import, - reads from stdin")
+	cmd.Flags().StringVar(&format, "format", "", "csv or json, defaults to the file extension")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the parsed schedules without creating them")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// parseSchedules parses the schedule creation requests of a CSV or JSON import file
+func parseSchedules(r io.Reader, format string) ([]map[string]interface{}, error) {
+	switch format {
+	case formatJSON:
+		var schedules []map[string]interface{}
+		if err := json.NewDecoder(r).Decode(&schedules); err != nil {
+			return nil, fmt.Errorf("invalid JSON import file, expected an array of schedules: %w", err)
+		}
+		return schedules, nil
+	case formatCSV:
+		return parseCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q, use --format csv or json", format)
+	}
+}
+
+func parseCSV(r io.Reader) ([]map[string]interface{}, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for _, column := range header {
+		if !csvColumns[column] {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+	}
+
+	var schedules []map[string]interface{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return schedules, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		schedule, err := csvSchedule(header, record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		schedules = append(schedules, schedule)
+	}
+}
+
+var csvColumns = map[string]bool{
+	"appId":           true,
+	"payload":         true,
+	"scheduleTime":    true,
+	"cronExpression":  true,
+	"every":           true,
+	"rrule":           true,
+	"statusCallback":  true,
+	"pausePolicy":     true,
+	"callback":        true,
+	"callbackType":    true,
+	"callbackUrl":     true,
+	"callbackMethod":  true,
+	"callbackHeaders": true,
+}
+
+// csvSchedule converts a CSV record into a schedule creation request, skipping empty cells
+func csvSchedule(header, record []string) (map[string]interface{}, error) {
+	schedule := map[string]interface{}{}
+	details := map[string]interface{}{}
+	callbackType := "http"
+
+	for i, column := range header {
+		value := strings.TrimSpace(record[i])
+		if value == "" {
+			continue
+		}
+
+		switch column {
+		case "scheduleTime":
+			scheduleTime, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid scheduleTime %q", value)
+			}
+			schedule[column] = scheduleTime
+		case "callback":
+			var callback json.RawMessage
+			if err := json.Unmarshal([]byte(value), &callback); err != nil {
+				return nil, fmt.Errorf("invalid callback JSON: %w", err)
+			}
+			schedule[column] = callback
+		case "callbackType":
+			callbackType = value
+		case "callbackUrl":
+			details["url"] = value
+		case "callbackMethod":
+			details["method"] = value
+		case "callbackHeaders":
+			var headers map[string]string
+			if err := json.Unmarshal([]byte(value), &headers); err != nil {
+				return nil, fmt.Errorf("invalid callbackHeaders JSON: %w", err)
+			}
+			details["headers"] = headers
+		default:
+			schedule[column] = value
+		}
+	}
+
+	if len(details) > 0 {
+		if _, ok := schedule["callback"]; ok {
+			return nil, errors.New("callback and callbackUrl, callbackMethod or callbackHeaders are mutually exclusive")
+		}
+		schedule["callback"] = map[string]interface{}{"type": callbackType, "details": details}
+	}
+	return schedule, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseSchedulesCSV(t *testing.T) {
+	input := `appId,payload,scheduleTime,cronExpression,callbackUrl,callbackMethod,callbackHeaders
+test,{},1700000000,,http://example.com,POST,"{""Accept"":""application/json""}"
+test,,,0 * * * *,http://example.com,GET,
+`
+	schedules, err := parseSchedules(strings.NewReader(input), formatCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(schedules) != 2 {
+		t.Fatalf("Expected 2 schedules, got %d", len(schedules))
+	}
+
+	b, _ := json.Marshal(schedules[0])
+	expected := `{"appId":"test","callback":{"details":{"headers":{"Accept":"application/json"},"method":"POST","url":"http://example.com"},"type":"http"},"payload":"{}","scheduleTime":1700000000}`
+	if string(b) != expected {
+		t.Errorf("Expected %s, got %s", expected, b)
+	}
+
+	if _, ok := schedules[1]["scheduleTime"]; ok {
+		t.Errorf("Expected empty scheduleTime to be skipped")
+	}
+	if schedules[1]["cronExpression"] != "0 * * * *" {
+		t.Errorf("Expected cron expression, got %v", schedules[1]["cronExpression"])
+	}
+}
+
+func TestParseSchedulesCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"unknown column", "appId,foo\ntest,bar\n", `unknown CSV column "foo"`},
+		{"invalid schedule time", "appId,scheduleTime\ntest,tomorrow\n", `line 2: invalid scheduleTime "tomorrow"`},
+		{"invalid callback", "appId,callback\ntest,{\n", "line 2: invalid callback JSON"},
+		{"callback conflict", "appId,callback,callbackUrl\ntest,\"{\"\"type\"\":\"\"http\"\"}\",http://example.com\n", "mutually exclusive"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseSchedules(strings.NewReader(test.input), formatCSV)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestParseSchedulesJSON(t *testing.T) {
+	input := `[{"appId":"test","payload":"{}","cronExpression":"0 * * * *","callback":{"type":"http","details":{"url":"http://example.com","method":"GET"}}}]`
+	schedules, err := parseSchedules(strings.NewReader(input), formatJSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(schedules) != 1 || schedules[0]["appId"] != "test" {
+		t.Errorf("Unexpected schedules %v", schedules)
+	}
+
+	if _, err := parseSchedules(strings.NewReader(`{"appId":"test"}`), formatJSON); err == nil {
+		t.Errorf("Expected an error for a JSON object instead of an array")
+	}
+	if _, err := parseSchedules(strings.NewReader(input), "yaml"); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+}
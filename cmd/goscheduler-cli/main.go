@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Command goscheduler-cli manages schedules of a goscheduler cluster through its HTTP API.
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultAddr = "http://localhost:8080"
+
+var (
+	addr    string
+	timeout time.Duration
+)
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "goscheduler-cli",
+		Short:         "Manage goscheduler schedules from the command line",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&addr, "addr", envOrDefault("GOSCHEDULER_ADDR", defaultAddr), "goscheduler base URL (env GOSCHEDULER_ADDR)")
+	root.PersistentFlags().DurationVar(&timeout, "timeout", 10*time.Second, "timeout of each HTTP request")
+
+	root.AddCommand(
+		newScheduleCommand(),
+		newRunsCommand(),
+		newImportCommand(),
+		newMigrateCommand(),
+		newSnapshotCommand(),
+		newClusterCommand(),
+	)
+	return root
+}
+
+func apiClient() *client {
+	return newClient(addr, timeout)
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
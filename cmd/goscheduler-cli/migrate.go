@@ -0,0 +1,371 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// The statuses of the schedules in a migration report
+const (
+	migrationCreated = "created" // created in goscheduler
+	migrationValid   = "valid"   // accepted by the validation of a dry run
+	migrationInvalid = "invalid" // cannot be translated or rejected by the validation
+	migrationSkipped = "skipped" // not to be migrated, e.g. a completed trigger
+	migrationFailed  = "failed"  // valid but its creation failed
+)
+
+// migration is a schedule of a legacy scheduler, translated to a schedule creation request
+type migration struct {
+	Source     string                 `json:"source"`
+	Status     string                 `json:"status"`
+	ScheduleId string                 `json:"scheduleId,omitempty"`
+	Schedule   map[string]interface{} `json:"schedule,omitempty"`
+	Warnings   []string               `json:"warnings,omitempty"`
+	Errors     []string               `json:"errors,omitempty"`
+	paused     bool
+}
+
+func (m *migration) warn(format string, args ...interface{}) {
+	m.Warnings = append(m.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (m *migration) reject(status, format string, args ...interface{}) {
+	m.Status = status
+	m.Errors = append(m.Errors, fmt.Sprintf(format, args...))
+}
+
+// rejectRequest rejects the schedule as invalid when the server found it so, as failed otherwise
+func (m *migration) rejectRequest(err error) {
+	var apiErr apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		m.reject(migrationInvalid, "%s", err)
+		return
+	}
+	m.reject(migrationFailed, "%s", err)
+}
+
+// migrationReport is the validation report of a migration, written once all the schedules are processed
+type migrationReport struct {
+	DryRun    bool        `json:"dryRun"`
+	Total     int         `json:"total"`
+	Created   int         `json:"created"`
+	Valid     int         `json:"valid"`
+	Invalid   int         `json:"invalid"`
+	Skipped   int         `json:"skipped"`
+	Failed    int         `json:"failed"`
+	Schedules []migration `json:"schedules"`
+}
+
+// migrateOptions are the flags shared by the sources of a migration
+type migrateOptions struct {
+	appId     string
+	callback  string
+	callbacks string
+	dryRun    bool
+	report    string
+}
+
+func (o *migrateOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.appId, "app", "", "app of the created schedules")
+	cmd.Flags().StringVar(&o.callback, "callback", "", "callback JSON of the schedules without a mapping in --callbacks")
+	cmd.Flags().StringVar(&o.callbacks, "callbacks", "", "JSON file mapping job or task names to their callback JSON")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "validate the translated schedules without creating them")
+	cmd.Flags().StringVarP(&o.report, "report", "o", "", "file to write the report to instead of stdout")
+	_ = cmd.MarkFlagRequired("app")
+}
+
+// run validates or creates the translated schedules and writes the report, failing if any schedule is invalid or
+// could not be created
+func (o *migrateOptions) run(cmd *cobra.Command, migrations []migration) error {
+	report := runMigration(apiClient(), migrations, o.dryRun)
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	if o.report != "" {
+		f, err := os.Create(o.report)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := printJSON(w, b); err != nil {
+		return err
+	}
+
+	if report.Invalid+report.Failed > 0 {
+		return fmt.Errorf("%d schedules are invalid and %d failed to migrate", report.Invalid, report.Failed)
+	}
+	return nil
+}
+
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the schedules of a legacy scheduler from CSV exports of its tables",
+	}
+	cmd.AddCommand(newQuartzCommand(), newDBSchedulerCommand())
+	return cmd
+}
+
+func newQuartzCommand() *cobra.Command {
+	var (
+		options                                migrateOptions
+		triggers, cronTriggers, simpleTriggers string
+	)
+	cmd := &cobra.Command{
+		Use:   "quartz",
+		Short: "Migrate the triggers of a Quartz JDBC job store",
+		Long: `Migrate the triggers of a Quartz JDBC job store.
+
+The tables are read from CSV exports with a header row, e.g. from psql:
+
+  \copy qrtz_triggers to 'triggers.csv' csv header
+
+Cron triggers become cron schedules, their expression translated from the Quartz syntax, simple
+triggers repeating forever become interval schedules and the ones firing once one-time schedules.
+The callback of a trigger is looked up in --callbacks by JOB_GROUP.JOB_NAME then by JOB_GROUP, and
+falls back to --callback. Paused triggers are paused once created, completed ones are skipped.
+
+With --dry-run each schedule is only validated by the server. The report lists the status, the
+warnings and the errors of every trigger.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			callbacks, err := loadCallbacks(options.callback, options.callbacks)
+			if err != nil {
+				return err
+			}
+
+			tables := make([][]map[string]string, 3)
+			for i, file := range []string{triggers, cronTriggers, simpleTriggers} {
+				if tables[i], err = readTable(file); err != nil {
+					return err
+				}
+			}
+			return options.run(cmd, quartzMigrations(options.appId, tables[0], tables[1], tables[2], callbacks))
+		},
+	}
+	options.addFlags(cmd)
+	cmd.Flags().StringVar(&triggers, "triggers", "", "CSV export of QRTZ_TRIGGERS")
+	cmd.Flags().StringVar(&cronTriggers, "cron-triggers", "", "CSV export of QRTZ_CRON_TRIGGERS")
+	cmd.Flags().StringVar(&simpleTriggers, "simple-triggers", "", "CSV export of QRTZ_SIMPLE_TRIGGERS")
+	_ = cmd.MarkFlagRequired("triggers")
+	return cmd
+}
+
+func newDBSchedulerCommand() *cobra.Command {
+	var (
+		options migrateOptions
+		tasks   string
+	)
+	cmd := &cobra.Command{
+		Use:   "db-scheduler",
+		Short: "Migrate the one-time tasks of a db-scheduler table",
+		Long: `Migrate the one-time tasks of a db-scheduler table.
+
+The scheduled_tasks table is read from a CSV export with a header row. Every one-time task becomes
+a one-time schedule at its execution time, with a payload naming the task and its instance along
+with its data when it is text. Recurring tasks are defined in the application code of db-scheduler
+and are skipped, they are to be created with a cronExpression or an every interval. The callback
+of a task is looked up in --callbacks by its task name and falls back to --callback.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			callbacks, err := loadCallbacks(options.callback, options.callbacks)
+			if err != nil {
+				return err
+			}
+			rows, err := readTable(tasks)
+			if err != nil {
+				return err
+			}
+			return options.run(cmd, dbSchedulerMigrations(options.appId, rows, callbacks))
+		},
+	}
+	options.addFlags(cmd)
+	cmd.Flags().StringVar(&tasks, "tasks", "", "CSV export of scheduled_tasks")
+	_ = cmd.MarkFlagRequired("tasks")
+	return cmd
+}
+
+// runMigration validates the translated schedules with a dry run, creates them otherwise, and pauses the ones paused
+// in the legacy scheduler once created
+func runMigration(c *client, migrations []migration, dryRun bool) migrationReport {
+	report := migrationReport{DryRun: dryRun, Total: len(migrations), Schedules: migrations}
+
+	for i := range migrations {
+		m := &migrations[i]
+		if m.Status == "" {
+			if dryRun {
+				migrateDryRun(c, m)
+			} else {
+				migrate(c, m)
+			}
+		}
+
+		switch m.Status {
+		case migrationCreated:
+			report.Created++
+		case migrationValid:
+			report.Valid++
+		case migrationInvalid:
+			report.Invalid++
+		case migrationSkipped:
+			report.Skipped++
+		case migrationFailed:
+			report.Failed++
+		}
+	}
+	return report
+}
+
+func migrateDryRun(c *client, m *migration) {
+	if _, err := c.do(http.MethodPost, schedulesPath+"/validate", m.Schedule); err != nil {
+		m.rejectRequest(err)
+		return
+	}
+	m.Status = migrationValid
+}
+
+func migrate(c *client, m *migration) {
+	b, err := c.do(http.MethodPost, schedulesPath, m.Schedule)
+	if err != nil {
+		m.rejectRequest(err)
+		return
+	}
+
+	var resp struct {
+		Data struct {
+			Schedule struct {
+				ScheduleId string `json:"scheduleId"`
+			} `json:"schedule"`
+			Warnings []string `json:"warnings"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		m.reject(migrationFailed, "cannot read the created schedule: %s", err)
+		return
+	}
+	m.Status, m.ScheduleId = migrationCreated, resp.Data.Schedule.ScheduleId
+	m.Warnings = append(m.Warnings, resp.Data.Warnings...)
+
+	if m.paused {
+		if _, err := c.do(http.MethodPut, schedulesPath+"/"+m.ScheduleId+"/pause", nil); err != nil {
+			m.warn("created but not paused: %s", err)
+		}
+	}
+}
+
+// callbacks looks up the callback of a job or a task by the first of its keys mapped, falling back to the default
+type callbacks struct {
+	fallback json.RawMessage
+	mapped   map[string]json.RawMessage
+}
+
+func (c callbacks) lookup(keys ...string) (json.RawMessage, bool) {
+	for _, key := range keys {
+		if callback, ok := c.mapped[key]; ok {
+			return callback, true
+		}
+	}
+	return c.fallback, c.fallback != nil
+}
+
+func loadCallbacks(fallback, file string) (callbacks, error) {
+	var c callbacks
+	if fallback != "" {
+		if err := json.Unmarshal([]byte(fallback), &c.fallback); err != nil {
+			return c, fmt.Errorf("invalid --callback JSON: %w", err)
+		}
+	}
+	if file != "" {
+		b, err := readInput(file)
+		if err != nil {
+			return c, err
+		}
+		if err := json.Unmarshal(b, &c.mapped); err != nil {
+			return c, fmt.Errorf("invalid callbacks file, expected an object of callbacks: %w", err)
+		}
+	}
+	if c.fallback == nil && len(c.mapped) == 0 {
+		return c, errors.New("either --callback or --callbacks is required")
+	}
+	return c, nil
+}
+
+// readTable reads the rows of a CSV export with a header row, keyed by the upper cased column names
+func readTable(file string) ([]map[string]string, error) {
+	if file == "" {
+		return nil, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := parseTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return rows, nil
+}
+
+func parseTable(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i, column := range header {
+		header[i] = strings.ToUpper(strings.TrimSpace(column))
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := map[string]string{}
+		for i, column := range header {
+			row[column] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, row)
+	}
+}
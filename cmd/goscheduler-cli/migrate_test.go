@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const quartzTriggers = `SCHED_NAME,TRIGGER_NAME,TRIGGER_GROUP,JOB_NAME,JOB_GROUP,NEXT_FIRE_TIME,PRIORITY,TRIGGER_STATE,TRIGGER_TYPE,START_TIME,END_TIME,CALENDAR_NAME
+sched,nightly,reports,report,billing,1700000000000,5,WAITING,CRON,1690000000000,0,
+sched,poll,sync,poller,sync,1700000000000,7,PAUSED,SIMPLE,1690000000000,0,holidays
+sched,once,sync,poller,sync,1700000000000,5,WAITING,SIMPLE,1690000000000,0,
+sched,done,sync,poller,sync,0,5,COMPLETE,SIMPLE,1690000000000,0,
+sched,seconds,reports,report,billing,1700000000000,5,WAITING,CRON,1690000000000,0,
+sched,calendar,reports,report,billing,1700000000000,5,WAITING,CAL_INT,1690000000000,0,
+sched,orphan,other,job,other,1700000000000,5,WAITING,CRON,1690000000000,0,
+`
+
+const quartzCronTriggers = `SCHED_NAME,TRIGGER_NAME,TRIGGER_GROUP,CRON_EXPRESSION,TIME_ZONE_ID
+sched,nightly,reports,0 30 2 ? * MON-FRI,Asia/Kolkata
+sched,seconds,reports,*/10 * * * * ?,
+`
+
+const quartzSimpleTriggers = `SCHED_NAME,TRIGGER_NAME,TRIGGER_GROUP,REPEAT_COUNT,REPEAT_INTERVAL,TIMES_TRIGGERED
+sched,poll,sync,-1,900000,12
+sched,once,sync,0,0,0
+`
+
+func table(t *testing.T, s string) []map[string]string {
+	rows, err := parseTable(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return rows
+}
+
+func TestQuartzMigrations(t *testing.T) {
+	callbacks := callbacks{mapped: map[string]json.RawMessage{
+		"billing.report": json.RawMessage(`{"type":"http","details":{"url":"http://billing"}}`),
+		"sync":           json.RawMessage(`{"type":"http","details":{"url":"http://sync"}}`),
+	}}
+	migrations := quartzMigrations("test", table(t, quartzTriggers), table(t, quartzCronTriggers), table(t, quartzSimpleTriggers), callbacks)
+	if len(migrations) != 7 {
+		t.Fatalf("Expected 7 migrations, got %d", len(migrations))
+	}
+
+	nightly := migrations[0]
+	if nightly.Status != "" || nightly.Schedule["cronExpression"] != "30 2 * * 1-5" || len(nightly.Warnings) != 1 {
+		t.Errorf("Unexpected cron migration %+v", nightly)
+	}
+	if string(nightly.Schedule["callback"].(json.RawMessage)) != `{"type":"http","details":{"url":"http://billing"}}` {
+		t.Errorf("Expected the callback of the job, got %s", nightly.Schedule["callback"])
+	}
+
+	poll := migrations[1]
+	if poll.Schedule["every"] != "15m0s" || poll.Schedule["anchor"] != int64(1690000000) || poll.Schedule["priority"] != "high" {
+		t.Errorf("Unexpected interval migration %+v", poll)
+	}
+	if !poll.paused || len(poll.Warnings) != 2 {
+		t.Errorf("Expected a paused migration with the calendar warning, got %+v", poll)
+	}
+
+	if once := migrations[2]; once.Schedule["scheduleTime"] != int64(1700000000) {
+		t.Errorf("Unexpected one-time migration %+v", once)
+	}
+	if done := migrations[3]; done.Status != migrationSkipped {
+		t.Errorf("Expected the completed trigger to be skipped, got %+v", done)
+	}
+	for _, m := range migrations[4:] {
+		if m.Status != migrationInvalid || len(m.Errors) == 0 {
+			t.Errorf("Expected %s to be invalid, got %+v", m.Source, m)
+		}
+	}
+}
+
+func TestDBSchedulerMigrations(t *testing.T) {
+	tasks := table(t, `task_name,task_instance,task_data,execution_time,picked,picked_by
+send-email,42,hello,2023-11-14 22:13:20+00,false,
+send-email,43,\x aced,1700000000000,true,node-1
+cleanup,recurring,,2023-11-14 22:13:20,false,
+send-email,44,,tomorrow,false,
+`)
+	callbacks := callbacks{fallback: json.RawMessage(`{"type":"http","details":{"url":"http://tasks"}}`)}
+	migrations := dbSchedulerMigrations("test", tasks, callbacks)
+
+	if m := migrations[0]; m.Schedule["scheduleTime"] != int64(1700000000) || m.Schedule["payload"] != `{"data":"hello","instance":"42","task":"send-email"}` {
+		t.Errorf("Unexpected migration %+v", m)
+	}
+	if m := migrations[1]; m.Schedule["scheduleTime"] != int64(1700000000) || len(m.Warnings) != 2 {
+		t.Errorf("Expected the binary data and picked warnings, got %+v", m)
+	}
+	if m := migrations[2]; m.Status != migrationSkipped {
+		t.Errorf("Expected the recurring task to be skipped, got %+v", m)
+	}
+	if m := migrations[3]; m.Status != migrationInvalid {
+		t.Errorf("Expected an invalid execution time, got %+v", m)
+	}
+}
+
+func TestRunMigration(t *testing.T) {
+	var paused []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var schedule map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&schedule)
+		switch {
+		case r.URL.Path == "/goscheduler/schedules/1/pause":
+			paused = append(paused, "1")
+		case schedule["payload"] == "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":{"statusMessage":"invalid callback"}}`))
+		case schedule["payload"] == "down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/goscheduler/schedules":
+			_, _ = w.Write([]byte(`{"data":{"schedule":{"scheduleId":"1"}}}`))
+		}
+	}))
+	defer server.Close()
+	c := newClient(server.URL, time.Second)
+
+	migrations := func() []migration {
+		return []migration{
+			{Source: "ok", Schedule: map[string]interface{}{"payload": "ok"}, paused: true},
+			{Source: "bad", Schedule: map[string]interface{}{"payload": "bad"}},
+			{Source: "down", Schedule: map[string]interface{}{"payload": "down"}},
+			{Source: "done", Status: migrationSkipped},
+		}
+	}
+
+	report := runMigration(c, migrations(), false)
+	if report.Total != 4 || report.Created != 1 || report.Invalid != 1 || report.Failed != 1 || report.Skipped != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if report.Schedules[0].ScheduleId != "1" || len(paused) != 1 {
+		t.Errorf("Expected the created schedule to be paused, got %+v", report.Schedules[0])
+	}
+
+	report = runMigration(c, migrations(), true)
+	if report.Valid != 1 || report.Invalid != 1 || report.Failed != 1 || report.Created != 0 || len(paused) != 1 {
+		t.Errorf("Unexpected dry run report %+v", report)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/myntra/goscheduler/cron"
+)
+
+// The default priority of a Quartz trigger, the higher ones are migrated as high and the lower ones as low
+const quartzDefaultPriority = 5
+
+// quartzMigrations translates the rows of the QRTZ_TRIGGERS table to schedule creation requests, with the details of
+// the cron and simple triggers from their own tables
+func quartzMigrations(appId string, triggers, cronTriggers, simpleTriggers []map[string]string, callbacks callbacks) []migration {
+	key := func(row map[string]string) string {
+		return row["SCHED_NAME"] + "\x00" + row["TRIGGER_GROUP"] + "\x00" + row["TRIGGER_NAME"]
+	}
+	index := func(rows []map[string]string) map[string]map[string]string {
+		indexed := make(map[string]map[string]string, len(rows))
+		for _, row := range rows {
+			indexed[key(row)] = row
+		}
+		return indexed
+	}
+	crons, simples := index(cronTriggers), index(simpleTriggers)
+
+	migrations := make([]migration, 0, len(triggers))
+	for _, trigger := range triggers {
+		m := migration{Source: trigger["TRIGGER_GROUP"] + "." + trigger["TRIGGER_NAME"]}
+		quartzMigration(&m, appId, trigger, crons[key(trigger)], simples[key(trigger)], callbacks)
+		migrations = append(migrations, m)
+	}
+	return migrations
+}
+
+func quartzMigration(m *migration, appId string, trigger, cronTrigger, simpleTrigger map[string]string, callbacks callbacks) {
+	switch state := trigger["TRIGGER_STATE"]; state {
+	case "COMPLETE", "ERROR", "DELETED":
+		m.Status = migrationSkipped
+		m.warn("trigger is %s", state)
+		return
+	case "PAUSED", "PAUSED_BLOCKED":
+		m.paused = true
+		m.warn("trigger is paused, the schedule is paused once created")
+	}
+
+	job := trigger["JOB_GROUP"] + "." + trigger["JOB_NAME"]
+	callback, ok := callbacks.lookup(job, trigger["JOB_GROUP"])
+	if !ok {
+		m.reject(migrationInvalid, "no callback for the job %s, set --callback or map it in --callbacks", job)
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"scheduler": trigger["SCHED_NAME"],
+		"trigger":   m.Source,
+		"job":       job,
+	})
+	m.Schedule = map[string]interface{}{
+		"appId":    appId,
+		"payload":  string(payload),
+		"callback": callback,
+	}
+
+	if priority, err := strconv.Atoi(trigger["PRIORITY"]); err == nil && priority > quartzDefaultPriority {
+		m.Schedule["priority"] = "high"
+	} else if err == nil && priority < quartzDefaultPriority {
+		m.Schedule["priority"] = "low"
+	}
+	if calendar := trigger["CALENDAR_NAME"]; calendar != "" {
+		m.warn("calendar %s is not migrated, its exclusions can be set up as blackout windows", calendar)
+	}
+	if end := millis(trigger["END_TIME"]); end > 0 {
+		m.warn("end time %s is not migrated", time.Unix(end/1000, 0).UTC().Format(time.RFC3339))
+	}
+
+	switch trigger["TRIGGER_TYPE"] {
+	case "CRON":
+		quartzCron(m, cronTrigger)
+	case "SIMPLE":
+		quartzSimple(m, trigger, simpleTrigger)
+	default:
+		m.reject(migrationInvalid, "%s triggers are not supported", trigger["TRIGGER_TYPE"])
+	}
+}
+
+func quartzCron(m *migration, cronTrigger map[string]string) {
+	if cronTrigger == nil {
+		m.reject(migrationInvalid, "trigger not found in the cron triggers")
+		return
+	}
+
+	expression, warnings, errs := cron.FromQuartz(cronTrigger["CRON_EXPRESSION"])
+	m.Warnings = append(m.Warnings, warnings...)
+	if len(errs) != 0 {
+		for _, err := range errs {
+			m.reject(migrationInvalid, "%s: %s", cronTrigger["CRON_EXPRESSION"], err)
+		}
+		return
+	}
+	m.Schedule["cronExpression"] = expression
+
+	if zone := cronTrigger["TIME_ZONE_ID"]; zone != "" {
+		m.warn("evaluated in the time zone of the goscheduler nodes instead of %s", zone)
+	}
+}
+
+func quartzSimple(m *migration, trigger, simpleTrigger map[string]string) {
+	if simpleTrigger == nil {
+		m.reject(migrationInvalid, "trigger not found in the simple triggers")
+		return
+	}
+
+	switch count, interval := millis(simpleTrigger["REPEAT_COUNT"]), millis(simpleTrigger["REPEAT_INTERVAL"]); {
+	case count == 0:
+		at := millis(trigger["NEXT_FIRE_TIME"])
+		if at <= 0 {
+			at = millis(trigger["START_TIME"])
+		}
+		m.Schedule["scheduleTime"] = at / 1000
+	case count < 0:
+		every := time.Duration(interval) * time.Millisecond
+		if every < time.Minute || every%time.Minute != 0 {
+			m.reject(migrationInvalid, "repeat interval of %s is not a whole number of minutes", every)
+			return
+		}
+		m.Schedule["every"] = every.String()
+		m.Schedule["anchor"] = millis(trigger["START_TIME"]) / 1000
+	default:
+		m.reject(migrationInvalid, "repeat count of %d is not supported, only triggers firing once or forever are", count)
+	}
+}
+
+// millis parses a number of milliseconds of a Quartz table, 0 if empty
+func millis(s string) int64 {
+	value, _ := strconv.ParseInt(s, 10, 64)
+	return value
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// run is the subset of a schedule run printed by the runs command
+type run struct {
+	ScheduleId   string `json:"scheduleId"`
+	ScheduleTime int64  `json:"scheduleTime"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type runsResponse struct {
+	Data struct {
+		Schedules []run `json:"schedules"`
+	} `json:"data"`
+}
+
+func newRunsCommand() *cobra.Command {
+	var (
+		when     string
+		size     int
+		follow   bool
+		interval time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "runs <scheduleId>",
+		Short: "Show the run history of a recurring schedule, -f keeps polling for new runs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := apiClient()
+			seen := map[string]string{}
+			for {
+				runs, err := fetchRuns(c, args[0], when, size)
+				if err != nil {
+					return err
+				}
+				printNewRuns(cmd.OutOrStdout(), runs, seen)
+
+				if !follow {
+					return nil
+				}
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVar(&when, "when", "past", "past or future runs")
+	cmd.Flags().IntVar(&size, "size", 20, "number of runs to fetch")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep polling and print new or updated runs")
+	cmd.Flags().DurationVar(&interval, "interval", 10*time.Second, "poll interval with --follow")
+	return cmd
+}
+
+func fetchRuns(c *client, scheduleId, when string, size int) ([]run, error) {
+	query := url.Values{}
+	query.Set("when", when)
+	query.Set("size", strconv.Itoa(size))
+
+	b, err := c.do(http.MethodGet, schedulesPath+"/"+scheduleId+"/runs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp runsResponse
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.Schedules, nil
+}
+
+// printNewRuns prints the runs in schedule time order that are new or whose status changed since the last poll
+func printNewRuns(w io.Writer, runs []run, seen map[string]string) {
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].ScheduleTime < runs[j].ScheduleTime
+	})
+
+	for _, r := range runs {
+		if status, ok := seen[r.ScheduleId]; ok && status == r.Status {
+			continue
+		}
+		seen[r.ScheduleId] = r.Status
+		fmt.Fprintf(w, "%s  %s  %-9s %s\n",
+			time.Unix(r.ScheduleTime, 0).Format(time.RFC3339), r.ScheduleId, r.Status, r.ErrorMessage)
+	}
+}
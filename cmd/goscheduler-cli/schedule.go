@@ -0,0 +1,165 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const schedulesPath = "/goscheduler/schedules"
+
+func newScheduleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule",
+		Aliases: []string{"schedules"},
+		Short:   "Create, get, delete, pause, resume and activate schedules",
+	}
+	cmd.AddCommand(
+		newCreateCommand(),
+		newGetCommand(),
+		newDeleteCommand(),
+		newLifecycleCommand("pause", "Pause a recurring schedule now or at a future time"),
+		newLifecycleCommand("resume", "Resume a paused recurring schedule now or at a future time"),
+		newActivateCommand(),
+	)
+	return cmd
+}
+
+func newCreateCommand() *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a schedule from a JSON file, - reads from stdin",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := readInput(file)
+			if err != nil {
+				return err
+			}
+
+			var schedule json.RawMessage
+			if err := json.Unmarshal(b, &schedule); err != nil {
+				return fmt.Errorf("invalid schedule JSON: %w", err)
+			}
+
+			resp, err := apiClient().do(http.MethodPost, schedulesPath, schedule)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), resp)
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "-", "schedule JSON file")
+	return cmd
+}
+
+func newGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <scheduleId>",
+		Short: "Get a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiClient().do(http.MethodGet, schedulesPath+"/"+args[0], nil)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), resp)
+		},
+	}
+}
+
+func newDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <scheduleId>",
+		Short: "Delete a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiClient().do(http.MethodDelete, schedulesPath+"/"+args[0], nil)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), resp)
+		},
+	}
+}
+
+func newActivateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "activate <scheduleId>",
+		Short: "Activate a recurring schedule created as a draft",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := apiClient().do(http.MethodPut, schedulesPath+"/"+args[0]+"/activate", nil)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), resp)
+		},
+	}
+}
+
+// newLifecycleCommand builds the pause and resume commands, which only differ in the action path
+func newLifecycleCommand(action, short string) *cobra.Command {
+	var at, resumeAt int64
+	cmd := &cobra.Command{
+		Use:   action + " <scheduleId>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			times := map[string]int64{}
+			if at != 0 {
+				times[action+"At"] = at
+			}
+			if resumeAt != 0 {
+				times["resumeAt"] = resumeAt
+			}
+
+			var body interface{}
+			if len(times) > 0 {
+				body = times
+			}
+
+			resp, err := apiClient().do(http.MethodPut, schedulesPath+"/"+args[0]+"/"+action, body)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), resp)
+		},
+	}
+	cmd.Flags().Int64Var(&at, "at", 0, "epoch seconds to "+action+" at instead of now")
+	if action == "pause" {
+		cmd.Flags().Int64Var(&resumeAt, "resume-at", 0, "epoch seconds to resume at")
+	}
+	return cmd
+}
+
+// readInput reads the named file, or stdin when the name is -
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(file)
+}
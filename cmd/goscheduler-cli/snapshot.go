@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+const formatNDJSON = "ndjson"
+
+func newSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export and import all the schedules of an app",
+		Long: `Export and import all the schedules of an app, e.g. for disaster recovery or to promote
+schedules between environments. Large apps take a while to export, raise --timeout accordingly.`,
+	}
+	cmd.AddCommand(newSnapshotExportCommand(), newSnapshotImportCommand())
+	return cmd
+}
+
+func newSnapshotExportCommand() *cobra.Command {
+	var (
+		output string
+		format string
+		days   int
+	)
+	cmd := &cobra.Command{
+		Use:   "export <appId>",
+		Short: "Export the recurring and pending one time schedules of an app",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{"format": {format}}
+			if days >= 0 {
+				query.Set("days", strconv.Itoa(days))
+			}
+
+			resp, err := apiClient().do(http.MethodGet, "/goscheduler/apps/"+url.PathEscape(args[0])+"/export?"+query.Encode(), nil)
+			if err != nil {
+				return err
+			}
+
+			if output == "-" {
+				_, err = cmd.OutOrStdout().Write(resp)
+				return err
+			}
+			return ioutil.WriteFile(output, resp, 0644)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "file to write the snapshot to, - writes to stdout")
+	cmd.Flags().StringVar(&format, "format", formatJSON, "json or ndjson")
+	cmd.Flags().IntVar(&days, "days", -1, "days ahead to export the one time schedules of, defaults to all")
+	return cmd
+}
+
+func newSnapshotImportCommand() *cobra.Command {
+	var (
+		file     string
+		format   string
+		conflict string
+		remapIds bool
+	)
+	cmd := &cobra.Command{
+		Use:   "import <appId>",
+		Short: "Import a snapshot into an app",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := readInput(file)
+			if err != nil {
+				return err
+			}
+
+			contentType := "application/json"
+			if format == formatNDJSON {
+				contentType = "application/x-ndjson"
+			}
+
+			query := url.Values{
+				"format":    {format},
+				"conflict":  {conflict},
+				"remap_ids": {strconv.FormatBool(remapIds)},
+			}
+			resp, err := apiClient().doRaw(http.MethodPost, "/goscheduler/apps/"+url.PathEscape(args[0])+"/import?"+query.Encode(), contentType, bytes.NewReader(b))
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), resp)
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "-", "snapshot file, - reads from stdin")
+	cmd.Flags().StringVar(&format, "format", formatJSON, "json or ndjson")
+	cmd.Flags().StringVar(&conflict, "conflict", "skip", "skip, overwrite or fail when a schedule already exists")
+	cmd.Flags().BoolVar(&remapIds, "remap-ids", false, "create the schedules with new ids")
+	return cmd
+}
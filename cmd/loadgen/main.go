@@ -0,0 +1,307 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Command loadgen drives a goscheduler cluster with synthetic apps and schedules created at a configurable rate and
+// shape, and reports the latency of the creates, the firing lag of the callbacks and the error rates.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultAddr   = "http://localhost:8080"
+	appsPath      = "/goscheduler/apps"
+	schedulesPath = "/goscheduler/schedules"
+)
+
+// options of a load generation run
+type options struct {
+	addr          string
+	listen        string
+	callbackURL   string
+	apps          int
+	appPrefix     string
+	partitions    int
+	shape         string
+	rate          float64
+	duration      time.Duration
+	burstInterval time.Duration
+	lead          time.Duration
+	concurrency   int
+	grace         time.Duration
+	timeout       time.Duration
+	failRate      float64
+	json          bool
+}
+
+func newRootCommand() *cobra.Command {
+	var o options
+	cmd := &cobra.Command{
+		Use:           "loadgen",
+		Short:         "Create synthetic schedules at a given rate and shape and measure their firing latency",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := run(o)
+			if err != nil {
+				return err
+			}
+			if o.json {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(r)
+			}
+			r.print(cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&o.addr, "addr", envOrDefault("GOSCHEDULER_ADDR", defaultAddr), "goscheduler base URL (env GOSCHEDULER_ADDR)")
+	flags.StringVar(&o.listen, "listen", ":9999", "address on which the callbacks are received")
+	flags.StringVar(&o.callbackURL, "callback-url", "", "URL of the callbacks as seen by the cluster, defaults to http://localhost<listen>/callback")
+	flags.IntVar(&o.apps, "apps", 1, "number of synthetic apps the schedules are spread over")
+	flags.StringVar(&o.appPrefix, "app-prefix", "loadgen", "prefix of the ids of the synthetic apps")
+	flags.IntVar(&o.partitions, "partitions", 0, "partitions of the synthetic apps, 0 uses the default of the cluster")
+	flags.StringVar(&o.shape, "shape", steadyShape, "shape of the load: steady, ramp or burst")
+	flags.Float64Var(&o.rate, "rate", 10, "schedules created per second, the peak rate of a ramp")
+	flags.DurationVar(&o.duration, "duration", time.Minute, "duration over which the schedules are created")
+	flags.DurationVar(&o.burstInterval, "burst-interval", 10*time.Second, "interval between the bursts of the burst shape")
+	flags.DurationVar(&o.lead, "lead", 2*time.Minute, "how far in the future of their creation the schedules are due")
+	flags.IntVar(&o.concurrency, "concurrency", 20, "number of concurrent creates")
+	flags.DurationVar(&o.grace, "grace", 2*time.Minute, "how long to wait for the callbacks past the last schedule time")
+	flags.DurationVar(&o.timeout, "timeout", 10*time.Second, "timeout of each HTTP request")
+	flags.Float64Var(&o.failRate, "fail-rate", 0, "fraction of the callbacks answered with a 503 to exercise the retries")
+	flags.BoolVar(&o.json, "json", false, "print the report as JSON")
+	return cmd
+}
+
+// report is the outcome of a run
+type report struct {
+	RunId         string  `json:"runId"`
+	Shape         string  `json:"shape"`
+	Created       int64   `json:"created"`
+	CreateErrors  int64   `json:"createErrors"`
+	CreateLatency summary `json:"createLatency"`
+	Fired         int     `json:"fired"`
+	Missing       int     `json:"missing"`
+	Duplicates    int     `json:"duplicates"`
+	Failed        int     `json:"failedCallbacks"`
+	FiringLag     summary `json:"firingLag"`
+}
+
+func (r report) print(w io.Writer) {
+	fmt.Fprintf(w, "run %s (%s)\n", r.RunId, r.Shape)
+	fmt.Fprintf(w, "created:    %d, errors: %d (%.2f%%)\n", r.Created, r.CreateErrors, percentage(r.CreateErrors, r.Created+r.CreateErrors))
+	printSummary(w, "create:", r.CreateLatency)
+	fmt.Fprintf(w, "fired:      %d, missing: %d (%.2f%%), duplicates: %d, failed callbacks: %d\n", r.Fired, r.Missing, percentage(int64(r.Missing), r.Created), r.Duplicates, r.Failed)
+	printSummary(w, "firing lag:", r.FiringLag)
+}
+
+func printSummary(w io.Writer, name string, s summary) {
+	fmt.Fprintf(w, "%-11s p50 %.1fms, p95 %.1fms, p99 %.1fms, max %.1fms\n", name, s.P50, s.P95, s.P99, s.Max)
+}
+
+func percentage(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// run registers the synthetic apps, creates the schedules following the shape and waits for their callbacks
+func run(o options) (report, error) {
+	s, err := newShape(o.shape, o.rate, o.duration, o.burstInterval)
+	if err != nil {
+		return report{}, err
+	}
+	if o.apps <= 0 || o.concurrency <= 0 {
+		return report{}, fmt.Errorf("apps and concurrency must be positive")
+	}
+
+	runId := strconv.FormatInt(time.Now().UnixNano(), 36)
+	listener, err := net.Listen("tcp", o.listen)
+	if err != nil {
+		return report{}, fmt.Errorf("listening for callbacks: %w", err)
+	}
+	callbackURL := o.callbackURL
+	if callbackURL == "" {
+		callbackURL = fmt.Sprintf("http://localhost:%d/callback", listener.Addr().(*net.TCPAddr).Port)
+	}
+
+	recv := newReceiver(runId, o.failRate)
+	mux := http.NewServeMux()
+	mux.Handle("/callback", recv)
+	server := &http.Server{Handler: mux}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Serve(listener) }()
+	defer server.Close()
+
+	c := &http.Client{Timeout: o.timeout}
+	apps := make([]string, o.apps)
+	for i := range apps {
+		apps[i] = fmt.Sprintf("%s-%d", o.appPrefix, i)
+		body := map[string]interface{}{"appId": apps[i], "partitions": o.partitions, "active": true}
+		if _, err = post(c, o.addr+appsPath, body); err != nil {
+			return report{}, fmt.Errorf("registering app %s: %w", apps[i], err)
+		}
+	}
+
+	var (
+		created, createErrors int64
+		createLatency         latencies
+		lastScheduleTime      int64
+		wg                    sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for i := 0; i < o.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				scheduleTime := time.Now().Add(o.lead).Unix()
+				start := time.Now()
+				_, err := post(c, o.addr+schedulesPath, newSchedule(apps[n%len(apps)], runId, scheduleTime, callbackURL))
+				createLatency.add(time.Since(start))
+				if err != nil {
+					atomic.AddInt64(&createErrors, 1)
+					continue
+				}
+				atomic.AddInt64(&created, 1)
+				for {
+					last := atomic.LoadInt64(&lastScheduleTime)
+					if scheduleTime <= last || atomic.CompareAndSwapInt64(&lastScheduleTime, last, scheduleTime) {
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	for sent := 0; ; {
+		elapsed := time.Since(start)
+		for due := s.total(elapsed); sent < due; sent++ {
+			jobs <- sent
+		}
+		if elapsed >= o.duration {
+			break
+		}
+		select {
+		case err = <-serveErr:
+			ticker.Stop()
+			close(jobs)
+			return report{}, fmt.Errorf("receiving callbacks: %w", err)
+		case <-ticker.C:
+		}
+	}
+	ticker.Stop()
+	close(jobs)
+	wg.Wait()
+
+	deadline := time.Unix(atomic.LoadInt64(&lastScheduleTime), 0).Add(o.grace)
+	for recv.receivedCount() < int(created) && time.Now().Before(deadline) {
+		time.Sleep(time.Second)
+	}
+
+	// schedules whose create timed out may still fire, so fired can exceed created
+	fired := recv.receivedCount()
+	missing := int(created) - fired
+	if missing < 0 {
+		missing = 0
+	}
+	duplicates, failed := recv.counts()
+	return report{
+		RunId:         runId,
+		Shape:         s.name,
+		Created:       created,
+		CreateErrors:  createErrors,
+		CreateLatency: createLatency.summary(),
+		Fired:         fired,
+		Missing:       missing,
+		Duplicates:    duplicates,
+		Failed:        failed,
+		FiringLag:     recv.lag.summary(),
+	}, nil
+}
+
+// newSchedule builds a one time schedule of the run calling back the receiver
+func newSchedule(appId, runId string, scheduleTime int64, callbackURL string) map[string]interface{} {
+	p, _ := json.Marshal(payload{RunId: runId, ScheduleTime: scheduleTime})
+	return map[string]interface{}{
+		"appId":        appId,
+		"payload":      string(p),
+		"scheduleTime": scheduleTime,
+		"callback": map[string]interface{}{
+			"type":    "http",
+			"details": map[string]interface{}{"url": callbackURL, "method": http.MethodPost},
+		},
+	}
+}
+
+// post sends body as JSON to url and fails on a non 2xx status
+func post(c *http.Client, url string, body interface{}) ([]byte, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return b, nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScheduler registers any app and fires every schedule twice as soon as it is created
+type fakeScheduler struct {
+	mu   sync.Mutex
+	apps []string
+	n    int
+}
+
+func (f *fakeScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	switch r.URL.Path {
+	case appsPath:
+		f.mu.Lock()
+		f.apps = append(f.apps, body["appId"].(string))
+		f.mu.Unlock()
+	case schedulesPath:
+		f.mu.Lock()
+		f.n++
+		id := strconv.Itoa(f.n)
+		f.mu.Unlock()
+
+		url := body["callback"].(map[string]interface{})["details"].(map[string]interface{})["url"].(string)
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body["payload"].(string))))
+			req.Header.Set("Schedule-Id", id)
+			resp, err := http.DefaultClient.Do(req)
+			if err == nil {
+				_, _ = ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+			}
+		}
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestRun(t *testing.T) {
+	scheduler := &fakeScheduler{}
+	server := httptest.NewServer(scheduler)
+	defer server.Close()
+
+	r, err := run(options{
+		addr:        server.URL,
+		listen:      "127.0.0.1:0",
+		apps:        2,
+		appPrefix:   "loadgen",
+		shape:       steadyShape,
+		rate:        50,
+		duration:    200 * time.Millisecond,
+		concurrency: 4,
+		grace:       time.Second,
+		timeout:     time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(scheduler.apps) != 2 || scheduler.apps[0] != "loadgen-0" || scheduler.apps[1] != "loadgen-1" {
+		t.Errorf("Expected the apps loadgen-0 and loadgen-1 to be registered, got %v", scheduler.apps)
+	}
+	if r.Created != 10 || r.CreateErrors != 0 {
+		t.Errorf("Expected 10 schedules created without errors, got %d created and %d errors", r.Created, r.CreateErrors)
+	}
+	if r.Fired != 10 || r.Missing != 0 || r.Duplicates != 10 {
+		t.Errorf("Expected 10 fired with 10 duplicates, got %d fired, %d missing and %d duplicates", r.Fired, r.Missing, r.Duplicates)
+	}
+	if r.CreateLatency.Count != 10 || r.FiringLag.Count != 10 {
+		t.Errorf("Expected 10 latencies of each kind, got %+v and %+v", r.CreateLatency, r.FiringLag)
+	}
+}
+
+func TestRunCreateErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == schedulesPath {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	r, err := run(options{addr: server.URL, listen: "127.0.0.1:0", apps: 1, shape: burstShape, rate: 20,
+		duration: 100 * time.Millisecond, burstInterval: time.Second, concurrency: 2, timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.Created != 0 || r.CreateErrors != 20 || r.Missing != 0 {
+		t.Errorf("Expected 20 create errors, got %+v", r)
+	}
+}
+
+func TestRunInvalidShape(t *testing.T) {
+	if _, err := run(options{shape: "sine", rate: 1, duration: time.Second, apps: 1, concurrency: 1}); err == nil {
+		t.Error("Expected an error for an unknown shape")
+	}
+}
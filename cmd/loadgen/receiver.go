@@ -0,0 +1,115 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+)
+
+// payload is the body of the callbacks of the synthetic schedules
+type payload struct {
+	RunId        string `json:"loadgenRunId"`
+	ScheduleTime int64  `json:"scheduleTime"`
+}
+
+// receiver receives the callbacks of the synthetic schedules of a run and measures their firing lag
+type receiver struct {
+	runId    string
+	failRate float64
+	now      func() time.Time
+
+	mu         sync.Mutex
+	random     *rand.Rand
+	received   map[string]bool
+	duplicates int
+	failed     int
+	lag        latencies
+}
+
+func newReceiver(runId string, failRate float64) *receiver {
+	return &receiver{
+		runId:    runId,
+		failRate: failRate,
+		now:      time.Now,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		received: map[string]bool{},
+	}
+}
+
+// ServeHTTP records the callback of a schedule of the run, failing failRate of the callbacks with a 503 so that the
+// retries of the scheduler are exercised
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	receivedAt := r.now()
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var p payload
+	if err = json.Unmarshal(body, &p); err != nil || p.RunId != r.runId {
+		// callbacks of the previous runs are acknowledged without being counted
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	r.mu.Lock()
+	if r.failRate > 0 && r.random.Float64() < r.failRate {
+		r.failed++
+		r.mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	id := req.Header.Get(constants.ScheduleIdHeader)
+	duplicate := r.received[id]
+	if duplicate {
+		r.duplicates++
+	} else {
+		r.received[id] = true
+	}
+	r.mu.Unlock()
+
+	if !duplicate {
+		r.lag.add(receivedAt.Sub(time.Unix(p.ScheduleTime, 0)))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// receivedCount returns the number of distinct schedules received
+func (r *receiver) receivedCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.received)
+}
+
+// counts returns the number of duplicate and failed callbacks
+func (r *receiver) counts() (duplicates int, failed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.duplicates, r.failed
+}
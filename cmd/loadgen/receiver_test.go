@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReceiver(t *testing.T) {
+	recv := newReceiver("run", 0)
+	recv.now = func() time.Time { return time.Unix(102, 0) }
+
+	for _, tt := range []struct {
+		id   string
+		body string
+	}{
+		{"1", `{"loadgenRunId":"run","scheduleTime":100}`},
+		{"2", `{"loadgenRunId":"run","scheduleTime":101}`},
+		{"1", `{"loadgenRunId":"run","scheduleTime":100}`},
+		{"3", `{"loadgenRunId":"previous","scheduleTime":100}`},
+		{"4", `not json`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(tt.body))
+		req.Header.Set("Schedule-Id", tt.id)
+		w := httptest.NewRecorder()
+		recv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for %s, got %d", tt.body, w.Code)
+		}
+	}
+
+	if got := recv.receivedCount(); got != 2 {
+		t.Errorf("Expected 2 schedules received, got %d", got)
+	}
+	if duplicates, failed := recv.counts(); duplicates != 1 || failed != 0 {
+		t.Errorf("Expected 1 duplicate and no failure, got %d and %d", duplicates, failed)
+	}
+	if got := recv.lag.summary(); got.Count != 2 || got.Max != 2000 || got.P50 != 1000 {
+		t.Errorf("Expected the lags of 1s and 2s, got %+v", got)
+	}
+}
+
+func TestReceiverFailRate(t *testing.T) {
+	recv := newReceiver("run", 1)
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`{"loadgenRunId":"run","scheduleTime":100}`))
+	req.Header.Set("Schedule-Id", "1")
+	w := httptest.NewRecorder()
+	recv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+	if _, failed := recv.counts(); failed != 1 || recv.receivedCount() != 0 {
+		t.Errorf("Expected the callback to be failed and not received")
+	}
+}
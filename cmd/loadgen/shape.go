@@ -0,0 +1,88 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Shapes of the load, the rate at which the schedules are created over the run
+const (
+	steadyShape = "steady"
+	rampShape   = "ramp"
+	burstShape  = "burst"
+)
+
+// shape tells how many schedules are created by the elapsed time of a run lasting duration
+type shape struct {
+	name          string
+	rate          float64
+	duration      time.Duration
+	burstInterval time.Duration
+}
+
+func newShape(name string, rate float64, duration time.Duration, burstInterval time.Duration) (shape, error) {
+	if rate <= 0 {
+		return shape{}, fmt.Errorf("rate must be positive, got %v", rate)
+	}
+	if duration <= 0 {
+		return shape{}, fmt.Errorf("duration must be positive, got %v", duration)
+	}
+
+	switch name {
+	case steadyShape, rampShape:
+	case burstShape:
+		if burstInterval <= 0 {
+			return shape{}, fmt.Errorf("burst interval must be positive, got %v", burstInterval)
+		}
+	default:
+		return shape{}, fmt.Errorf("unknown shape %s, expected one of %s, %s or %s", name, steadyShape, rampShape, burstShape)
+	}
+	return shape{name: name, rate: rate, duration: duration, burstInterval: burstInterval}, nil
+}
+
+// total returns the number of schedules created by the elapsed time:
+// - steady creates rate schedules every second
+// - ramp grows the rate linearly from 0 to rate over the run
+// - burst creates the schedules of a burst interval at once at the start of every interval
+func (s shape) total(elapsed time.Duration) int {
+	if elapsed < 0 {
+		return 0
+	}
+	if elapsed > s.duration {
+		elapsed = s.duration
+	}
+
+	switch s.name {
+	case rampShape:
+		seconds := elapsed.Seconds()
+		return int(s.rate * seconds * seconds / (2 * s.duration.Seconds()))
+	case burstShape:
+		if elapsed == s.duration {
+			elapsed = s.duration - 1
+		}
+		bursts := math.Floor(float64(elapsed)/float64(s.burstInterval)) + 1
+		return int(bursts * s.rate * s.burstInterval.Seconds())
+	default:
+		return int(s.rate * elapsed.Seconds())
+	}
+}
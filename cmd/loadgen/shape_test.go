@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewShape(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		rate          float64
+		duration      time.Duration
+		burstInterval time.Duration
+		wantErr       bool
+	}{
+		{name: steadyShape, rate: 10, duration: time.Minute},
+		{name: rampShape, rate: 10, duration: time.Minute},
+		{name: burstShape, rate: 10, duration: time.Minute, burstInterval: 10 * time.Second},
+		{name: burstShape, rate: 10, duration: time.Minute, wantErr: true},
+		{name: steadyShape, rate: 0, duration: time.Minute, wantErr: true},
+		{name: steadyShape, rate: 10, wantErr: true},
+		{name: "sine", rate: 10, duration: time.Minute, wantErr: true},
+	} {
+		if _, err := newShape(tt.name, tt.rate, tt.duration, tt.burstInterval); (err != nil) != tt.wantErr {
+			t.Errorf("newShape(%s, %v, %v, %v) error = %v, wantErr %v", tt.name, tt.rate, tt.duration, tt.burstInterval, err, tt.wantErr)
+		}
+	}
+}
+
+func TestShapeTotal(t *testing.T) {
+	steady, _ := newShape(steadyShape, 10, 10*time.Second, 0)
+	ramp, _ := newShape(rampShape, 10, 10*time.Second, 0)
+	burst, _ := newShape(burstShape, 10, 10*time.Second, 5*time.Second)
+
+	for _, tt := range []struct {
+		shape   shape
+		elapsed time.Duration
+		want    int
+	}{
+		{steady, -time.Second, 0},
+		{steady, 0, 0},
+		{steady, 5 * time.Second, 50},
+		{steady, 10 * time.Second, 100},
+		{steady, time.Minute, 100},
+		{ramp, 0, 0},
+		{ramp, 5 * time.Second, 12},
+		{ramp, 10 * time.Second, 50},
+		{burst, 0, 50},
+		{burst, 4 * time.Second, 50},
+		{burst, 5 * time.Second, 100},
+		{burst, 10 * time.Second, 100},
+		{burst, time.Minute, 100},
+	} {
+		if got := tt.shape.total(tt.elapsed); got != tt.want {
+			t.Errorf("%s total(%v) = %d, want %d", tt.shape.name, tt.elapsed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencies records durations to summarise them in percentiles
+type latencies struct {
+	mu     sync.Mutex
+	values []time.Duration
+}
+
+func (l *latencies) add(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.values = append(l.values, d)
+}
+
+// summary is the distribution of the recorded durations
+type summary struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50Millis"`
+	P95   float64 `json:"p95Millis"`
+	P99   float64 `json:"p99Millis"`
+	Max   float64 `json:"maxMillis"`
+}
+
+func (l *latencies) summary() summary {
+	l.mu.Lock()
+	values := append([]time.Duration(nil), l.values...)
+	l.mu.Unlock()
+
+	if len(values) == 0 {
+		return summary{}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return summary{
+		Count: len(values),
+		P50:   millis(percentile(values, 50)),
+		P95:   millis(percentile(values, 95)),
+		P99:   millis(percentile(values, 99)),
+		Max:   millis(values[len(values)-1]),
+	}
+}
+
+// percentile returns the nearest rank percentile of the sorted values
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatenciesSummary(t *testing.T) {
+	var l latencies
+	if got := l.summary(); got != (summary{}) {
+		t.Errorf("Expected empty summary, got %+v", got)
+	}
+
+	for i := 100; i >= 1; i-- {
+		l.add(time.Duration(i) * time.Millisecond)
+	}
+	want := summary{Count: 100, P50: 50, P95: 95, P99: 99, Max: 100}
+	if got := l.summary(); got != want {
+		t.Errorf("summary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3}
+	for p, want := range map[int]time.Duration{0: 1, 33: 1, 34: 2, 50: 2, 99: 3, 100: 3} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(%d) = %d, want %d", p, got, want)
+		}
+	}
+}
@@ -27,20 +27,36 @@ import (
 	"github.com/myntra/goscheduler/constants"
 
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/jinzhu/configor"
+	"github.com/myntra/goscheduler/logger"
 )
 
 // ClusterConfig represents the configuration of a ringpop cluster, including its name,
 // address, TChannel port, bootstrap servers, and other settings.
 type ClusterConfig struct {
-	ClusterName      string   // Name of the cluster
-	Address          string   // Address of the cluster
-	TChannelPort     string   // TChannel port for inter-node communication
-	BootStrapServers []string // List of bootstrap servers for cluster formation
-	JoinSize         int      // Number of nodes required to form a cluster
-	Log              Log      // Logging configuration
-	ReplicaPoints    int      // Number of replica points for data replication
+	ClusterName      string       // Name of the cluster
+	Address          string       // Address of the cluster
+	TChannelPort     string       // TChannel port for inter-node communication
+	BootStrapServers []string     // List of bootstrap servers for cluster formation
+	JoinSize         int          // Number of nodes required to form a cluster
+	Log              Log          // Logging configuration
+	ReplicaPoints    int          // Number of replica points for data replication
+	Coordination     Coordination // Backend of the membership of the cluster and of the assignment of the partitions
+}
+
+// Coordination represents the backend of the membership of the cluster and of the assignment of the partitions to
+// its nodes.
+type Coordination struct {
+	Backend string // ringpop (default), gossiping between the nodes, or etcd
+	Etcd    Etcd   // Configuration of the etcd backend
+}
+
+// Etcd represents the configuration of the etcd coordination backend, reached through its v3 JSON gateway.
+type Etcd struct {
+	Endpoints       []string // Urls of the etcd members, e.g. http://etcd:2379
+	Prefix          string   // Prefix of the keys of the cluster
+	LeaseTTLSeconds int      // Seconds after which a node which stopped renewing its lease leaves the cluster
+	TimeoutMillis   int      // Timeout of a request to etcd
 }
 
 // Log represents the logging configuration, including whether logging is enabled.
@@ -57,6 +73,23 @@ type CassandraConfig struct {
 	Consistency    gocql.Consistency // Consistency level for Cassandra operations
 	DataCenter     string            // Name of the data center to connect to
 	ConnectionPool ConnectionPool    // Connection pool configuration
+	QueryRetry     QueryRetry        // Retries with backoff of the queries timing out
+	Speculative    Speculative       // Speculative executions of the slow reads
+}
+
+// QueryRetry represents the retries of the Cassandra queries timing out, on top of the retries of gocql.
+// The backoff doubles after every retry, from MinBackoffMillis up to MaxBackoffMillis.
+type QueryRetry struct {
+	MaxRetries       int // Retries of a query timing out before its timeout is returned, 0 disables the retries
+	MinBackoffMillis int // Backoff before the first retry
+	MaxBackoffMillis int // Upper bound of the backoff between retries
+}
+
+// Speculative represents the speculative execution of the reads, which runs a read still running after a delay
+// again on another host and takes the first response.
+type Speculative struct {
+	Attempts    int // Additional executions of a slow read, 0 disables the speculative execution
+	DelayMillis int // Delay after which a read still running is executed again
 }
 
 // ClusterDBConfig represents the configuration for a cluster database, including
@@ -70,17 +103,56 @@ type ClusterDBConfig struct {
 // ScheduleDBConfig represents the configuration for a schedule database, including
 // keyspace, table name, database configuration, and TTL settings.
 type ScheduleDBConfig struct {
-	ScheduleKeySpace  string          // Keyspace for the schedule
-	ScheduleTableName string          // Table name for the schedule
-	DBConfig          CassandraConfig // Cassandra configuration for the schedule
+	ScheduleKeySpace     string               // Keyspace for the schedule
+	ScheduleTableName    string               // Table name for the schedule
+	DBConfig             CassandraConfig      // Cassandra configuration for the schedule
+	OperationConsistency OperationConsistency // Consistency levels of the classes of operations on the schedules
+}
+
+// OperationConsistency represents the consistency levels of the classes of operations on the schedules, which trade
+// the latency of the poll path against the durability of the write path. An empty level falls back to the
+// consistency of the schedule database.
+type OperationConsistency struct {
+	Create       string // Consistency level of creating schedules and the runs of the recurring schedules, e.g. LOCAL_QUORUM
+	StatusUpdate string // Consistency level of writing the statuses of the fired schedules
+	PollRead     string // Consistency level of reading the schedules to fire
 }
 
 // PollerConfig represents the configuration for a poller, including interval,
 // buffer size, and default count.
 type PollerConfig struct {
-	Interval      int    // Polling interval in seconds
-	DefaultCount  uint32 // Default number of items to be polled
-	MaxQueryLimit int    // Maximum number to query to Cassandra for getting Schedules
+	Interval         int    // Polling interval in seconds
+	DefaultCount     uint32 // Default number of items to be polled
+	MaxQueryLimit    int    // Maximum number to query to Cassandra for getting Schedules
+	StreamBufferSize int    // Schedules a poll reads ahead of the callback workers, bounding the memory held by a poll
+
+	// Adaptive polling tightens or loosens the poll interval of every partition based on its upcoming load
+	Adaptive            bool // Adapt the poll interval to the number of schedules due in the next minutes
+	MinIntervalSeconds  int  // Poll interval of a busy partition in seconds
+	BusyThreshold       int  // Schedules due in a minute from which a partition is busy
+	MaxLookaheadMinutes int  // Upper bound of the minutes an idle partition is left without polling
+
+	// Second precision fires every one time schedule at the second it is due rather than when its minute is polled
+	SecondPrecision     bool // Poll every minute as it begins and hold each of its schedules until it is due
+	MaxPendingSchedules int  // Schedules a poll holds until they are due, the read of the minute pauses once reached
+
+	// Prefetch reads every minute ahead of time and holds its schedules until they are due, spreading the reads of
+	// the partitions over the seconds before the minute
+	PrefetchSeconds int // Upper bound of how early a minute is read, below 60, 0 reads it once it begins
+
+	// The intent log claims the schedules of a poll before their callbacks are dispatched, so that the fires lost with a
+	// node crashing before their outcome is written are fired again by the poller of their partition
+	IntentLog             bool // Claim the schedules before dispatching them and recover the claims left without an outcome
+	IntentGraceSeconds    int  // Age from which a claim is recovered, above the longest callback with its retries
+	IntentMaxAttempts     int  // Claims of a schedule after which it is left to the missed schedule reconciliation
+	IntentLookbackMinutes int  // Minutes of claims older than the grace scanned when a poller of the partition starts
+}
+
+// LastPolledBucket returns the start of the latest minute bucket the pollers may have read by the time t, the minute
+// of t or, with prefetch, a minute beginning within PrefetchSeconds of t. A schedule written into that bucket or an
+// earlier one would not be fired.
+func (c PollerConfig) LastPolledBucket(t time.Time) time.Time {
+	return t.Add(time.Duration(c.PrefetchSeconds) * time.Second).Truncate(time.Minute)
 }
 
 // ConnectionPool represents the configuration for a connection pool, including
@@ -112,6 +184,12 @@ type HttpConnectorConfig struct {
 	Routines      int           // Number of concurrent routines for processing
 	MaxRetry      int           // Maximum number of retries for failed requests
 	TimeoutMillis time.Duration // Timeout for HTTP requests in milliseconds
+	QueueSize     int           // Maximum number of schedules waiting for a worker, 0 to wait for a free worker instead
+	// Bytes of the callback response bodies kept with the status of the runs, 0 to keep none
+	ResponseSnippetSize int
+	// Upper bound of the wait before a retry asked for by the Retry-After header of a 429 or 503 response,
+	// 0 to ignore the header
+	MaxRetryAfterMillis int
 }
 
 // EventListener represents the configuration for an event listener, including
@@ -145,6 +223,15 @@ type AggregateSchedulesConfig struct {
 	Routines    int // Number of workers aggregating schedules
 	BatchSize   int // Batchsize of schedules for bulk update
 	FlushPeriod int // Flush period in seconds after which schedule batches are pushed to db (even if they are not full)
+	FlushMillis int // Flush window in milliseconds, takes precedence over FlushPeriod when set, unset by default
+}
+
+// FlushWindow returns the time after which the pending status batches are pushed to db
+func (a AggregateSchedulesConfig) FlushWindow() time.Duration {
+	if a.FlushMillis > 0 {
+		return time.Duration(a.FlushMillis) * time.Millisecond
+	}
+	return time.Duration(a.FlushPeriod) * time.Second
 }
 
 // StatusUpdateConfig represents the configuration options for status updates of schedules.
@@ -168,7 +255,395 @@ type BulkActionConfig struct {
 // MonitoringConfig represents the configuration options for monitoring, including
 // Statsd configuration.
 type MonitoringConfig struct {
-	Statsd *StatsdConfig // Configuration options for Statsd
+	Statsd *StatsdConfig      // Configuration options for Statsd
+	Labels MetricLabelsConfig // Cardinality controls of the labels of the metrics
+}
+
+// MetricLabelsConfig represents the cardinality controls of the labels of the metrics. The metrics of the apps outside
+// the allowlist, or over the cap of distinct apps, are aggregated under a single appId label value.
+type MetricLabelsConfig struct {
+	AppIds         []string            // Apps whose appId label is kept, all apps up to MaxApps if empty
+	MaxApps        int                 // Distinct apps labelled by a node when there is no allowlist, 0 for unlimited
+	AggregateLabel string              // appId label value of the aggregated apps, other if empty
+	DropLabels     map[string][]string // Labels dropped from a metric, by metric name, e.g. partitionId from callback_duration
+}
+
+// DeliveryReceiptConfig represents the configuration options for signed delivery receipts of callbacks.
+type DeliveryReceiptConfig struct {
+	Enabled    bool   // Indicates if delivery receipts are recorded for callbacks
+	KeyId      string // Identifier of the signing key, recorded in the receipts to support key rotation
+	SigningKey string // Secret key used to compute the HMAC-SHA256 signature of the receipts
+}
+
+// StatusCallbackConfig represents the configuration options for notifying run outcomes to status callback urls.
+type StatusCallbackConfig struct {
+	BufferSize    int           // Channel buffer size, notifications are dropped when the buffer is full
+	Routines      int           // Number of workers posting the notifications
+	TimeoutMillis time.Duration // Timeout for the status callback requests in milliseconds
+}
+
+// EventPublisherConfig represents the configuration options for publishing the lifecycle events of schedules.
+type EventPublisherConfig struct {
+	Type          string        // Publisher of the events, one of noop, kafka or nats
+	Address       string        // Comma separated host:port of the brokers for kafka, urls or host:port of the servers for nats
+	Topic         string        // Kafka topic or NATS subject the events are published to
+	BufferSize    int           // Channel buffer size, events are dropped when the buffer is full
+	Routines      int           // Number of workers publishing the events
+	TimeoutMillis time.Duration // Timeout for publishing an event in milliseconds
+}
+
+// CallbackPluginConfig represents the configuration options for callback plugins.
+type CallbackPluginConfig struct {
+	Plugins       []string                      // Paths of Go plugins exporting callback plugins, loaded at startup
+	Sidecars      map[string]string             // Callback types delivered by sidecar executors, mapped to the url of the executor
+	Routines      int                           // Number of workers executing the callbacks of each plugin type
+	QueueSize     int                           // Maximum number of schedules of each plugin type waiting for a worker, 0 to wait for a free worker instead
+	TimeoutMillis time.Duration                 // Timeout for the requests to sidecar executors in milliseconds
+	Pools         map[string]CallbackPoolConfig // Worker pools of plugin types sized apart from the defaults above, by callback type
+}
+
+// CallbackPoolConfig sizes the worker pool of a callback type.
+type CallbackPoolConfig struct {
+	Routines  int // Number of workers executing the callbacks of the type
+	QueueSize int // Maximum number of schedules waiting for a worker, 0 to wait for a free worker instead
+}
+
+// GetPool returns the sizing of the worker pool of the plugin callback type
+func (c CallbackPluginConfig) GetPool(callbackType string) CallbackPoolConfig {
+	if pool, ok := c.Pools[callbackType]; ok {
+		return pool
+	}
+	return CallbackPoolConfig{Routines: c.Routines, QueueSize: c.QueueSize}
+}
+
+// AdminUIConfig represents the configuration options for the admin dashboard.
+type AdminUIConfig struct {
+	Enabled bool // Serve the admin dashboard under /goscheduler/ui/
+}
+
+// LogConfig represents the configuration options for logging.
+type LogConfig struct {
+	Format string // Log line format, json or text
+	Level  string // Minimum level logged, one of debug, info, warning or error
+}
+
+// DiagnosticsConfig represents the configuration options for the diagnostics endpoint.
+type DiagnosticsConfig struct {
+	SlowQueryThresholdMillis int // Cassandra queries slower than this are recorded
+	SlowQueryLimit           int // Number of slowest queries kept in memory
+}
+
+// ReplicationConfig represents the configuration options for active-passive replication across datacenters.
+type ReplicationConfig struct {
+	Role                  string        // Role of the cluster, primary or standby, empty disables replication
+	Type                  string        // Source of the lifecycle events tailed by a standby, kafka or nats
+	Address               string        // Kafka REST proxy url for kafka, host:port of the server for nats
+	Topic                 string        // Kafka topic or NATS subject the primary publishes its events to
+	Group                 string        // Kafka consumer group or NATS queue group of the standby nodes
+	PollIntervalMillis    time.Duration // Interval between polls of the event stream when it is idle in milliseconds
+	TimeoutMillis         time.Duration // Timeout for the requests to the event stream in milliseconds
+	RoleRefreshSeconds    int           // Interval at which a standby checks whether it has been promoted
+	PrimaryAddress        string        // Base url of the primary cluster the standby resyncs its schedules from
+	ResyncIntervalSeconds int           // Interval between resyncs of the standby from the primary, 0 disables it
+	ResyncTimeoutSeconds  time.Duration // Timeout for the requests to the primary during a resync in seconds
+}
+
+// SLAConfig represents the configuration options for alerting on the firing lag of the callbacks.
+type SLAConfig struct {
+	Enabled            bool          // Evaluates the firing lag of the apps at the end of every minute
+	LagThresholdMillis int64         // Firing lag above which a minute breaches the SLA of an app
+	ConsecutiveMinutes int           // Number of consecutive breaching minutes which raise an alert
+	AlertType          string        // Receiver of the alerts, one of noop, webhook, slack or pagerduty
+	AlertUrl           string        // Url the alerts are posted to, defaults to the PagerDuty events API for pagerduty
+	RoutingKey         string        // Integration key of the PagerDuty service
+	TimeoutMillis      time.Duration // Timeout for posting an alert in milliseconds
+}
+
+// AnomalyConfig represents the configuration options for alerting on the creation and callback failure rates of
+// the apps which deviate from their baseline. The alerts are delivered by the receiver of the SLAConfig.
+type AnomalyConfig struct {
+	Enabled         bool    // Compares the rates of the apps with their baseline at the end of every minute
+	Sensitivity     float64 // Number of standard deviations above the baseline at which a rate is anomalous
+	BaselineMinutes int     // Span in minutes of the exponentially weighted baseline of every rate
+	WarmupMinutes   int     // Number of minutes observed before the baseline of an app is trusted
+	MinCreations    int     // Creations in a minute below which a creation spike is ignored
+	MinCallbacks    int     // Callbacks in a minute below which the failure rate is not evaluated
+}
+
+// UsageConfig represents the configuration options for the daily usage rollups of the apps.
+type UsageConfig struct {
+	Enabled              bool // Rolls up the schedules created, callbacks fired, bytes delivered and retries of every app per day
+	FlushIntervalSeconds int  // Interval at which the usage counted by a node is added to the rollup table
+}
+
+// RunStatsConfig represents the configuration options for the hourly rollups of the outcome of the runs of the apps.
+type RunStatsConfig struct {
+	Enabled              bool // Rolls up the successful, failed and retried runs of every app per hour
+	FlushIntervalSeconds int  // Interval at which the run stats counted by a node are added to the rollup table
+}
+
+// GraphQLConfig represents the configuration options for the GraphQL queries.
+type GraphQLConfig struct {
+	MaxDepth    int // Deepest nesting of fields a query can select, 0 for unlimited
+	MaxListSize int // Largest number of schedules or runs a list field of a query can select, 0 for unlimited
+}
+
+// UrlVerificationConfig represents the configuration options for the verification handshake of the callback urls.
+type UrlVerificationConfig struct {
+	Required           bool // Requires every app to verify its callback urls, apps can also require it in their configuration
+	TimeoutMillis      int  // Timeout of the challenge request sent to a callback url being verified
+	ProbeTimeoutMillis int  // Timeout of the reachability probe of the callback urls of a schedule created with a probe
+}
+
+// EgressConfig represents the destinations the callbacks of the cluster are restricted to.
+type EgressConfig struct {
+	Enabled        bool     // Checks the callback urls when schedules are created and the addresses they connect to when fired
+	AllowedCIDRs   []string // Address ranges the callbacks can be delivered to
+	AllowedDomains []string // Domains the callbacks can be delivered to whatever they resolve to, *.<domain> for the subdomains
+	DeniedCIDRs    []string // Address ranges the callbacks are never delivered to, even for allowed domains
+}
+
+// SheddingConfig represents the configuration options for shedding the low priority and overdue callbacks when the
+// callback workers of a node cannot keep up.
+type SheddingConfig struct {
+	Enabled        bool   // Sheds the callbacks of the low and normal priority schedules under overload, never the high priority ones
+	MaxBacklog     int    // Callbacks waiting for a worker from which the node is overloaded, 0 disables the check
+	OverdueSeconds int    // Lateness from which a callback is far overdue, 0 disables the check
+	Policy         string // What happens to the shed schedules, defer (default) or drop
+	DeferSeconds   int    // Delay after which a deferred schedule fires again
+}
+
+// ParkingConfig represents the configuration options for parking the one time schedules which are far in the future.
+type ParkingConfig struct {
+	Enabled       bool // Parks the one time schedules beyond the horizon instead of writing them to the time bucketed tables
+	HorizonDays   int  // Days ahead from which a one time schedule is parked
+	PromotionDays int  // Days ahead from which a parked schedule is promoted, must be less than the horizon
+	Shards        int  // Number of shards of a day of parked schedules, spread over the partitions of the cron app
+}
+
+// SecretsConfig represents the provider the {{secret:NAME}} references of the http callbacks are resolved from.
+type SecretsConfig struct {
+	Provider      string // Provider of the secrets, one of env, file or vault, empty when references are not resolved
+	EnvPrefix     string // Prefix of the environment variables of the env provider, NAME is read from <prefix>NAME
+	Directory     string // Directory of the file provider, NAME is read from the file <directory>/NAME
+	VaultAddress  string // Address of the vault server of the vault provider
+	VaultPath     string // Path of the vault secret holding the secrets as its keys, e.g. secret/data/goscheduler
+	VaultTokenEnv string // Environment variable holding the vault token
+	CacheSeconds  int    // Seconds a resolved secret is reused before it is read again from the provider
+}
+
+// CallbackLogConfig represents the configuration options for logging every attempt of the http callbacks.
+type CallbackLogConfig struct {
+	Enabled     bool // Indicates if the request and response of every callback attempt are kept
+	SnippetSize int  // Bytes of the request and response bodies kept with each attempt, after the redaction of the app
+}
+
+// PullDeliveryConfig represents the configuration options for the runs of the pull callbacks, which are kept for
+// their consumers to poll for and acknowledge instead of being called back.
+type PullDeliveryConfig struct {
+	QueueSize          int // Maximum number of due runs waiting to be stored for their consumers, 0 for no limit
+	Routines           int // Number of workers storing the due runs and completing the acknowledged ones
+	BufferSize         int // Channel buffer size of the acknowledged runs waiting to be completed
+	MaxDeliveries      int // Deliveries of a run left unacknowledged before the run fails
+	LeaseSeconds       int // Time a consumer has to acknowledge the runs it polled, unless it asks for another one
+	MaxLeaseSeconds    int // Upper bound of the lease a consumer can ask for
+	MaxWaitSeconds     int // Upper bound of the wait of a long poll for due runs
+	PollIntervalMillis int // Interval at which a long poll looks for due runs
+}
+
+// EventStreamConfig represents the configuration options for streaming the fire events of the apps to the clients
+// subscribed to them on a node.
+type EventStreamConfig struct {
+	MaxSubscribers   int // Maximum number of clients streaming events from the node at once
+	BufferSize       int // Events buffered for each client, events are dropped for a client falling further behind
+	HeartbeatSeconds int // Interval at which a comment is sent to keep idle streams open
+}
+
+// ClockConfig represents the clock the schedules are fired on. A virtual clock stands still until it is advanced
+// through the clock API, for the integration tests of the time a schedule fires at. It is never used in production.
+type ClockConfig struct {
+	Virtual   bool   // Fires the schedules on a virtual clock instead of the wall clock
+	StartTime string // RFC3339 time the virtual clock starts at, the current time when empty
+}
+
+// FaultInjectionConfig represents the faults injected into the node for the game days of a staging cluster, which
+// verify that the retries, the fencing and the lag alerts behave. Rates are between 0 and 1. It is never enabled in
+// production.
+type FaultInjectionConfig struct {
+	Enabled               bool     // Injects the faults below, nothing is injected otherwise
+	CassandraTimeoutRate  float64  // Share of the Cassandra queries failing with a timeout
+	CassandraQueries      []string // Names of the queries timing out, e.g. select_schedules, every query when empty
+	CallbackLatencyRate   float64  // Share of the http callbacks delayed
+	CallbackLatencyMillis int      // Delay added to the delayed http callbacks, counted in their timeout
+	NodePauseRate         float64  // Chance every minute that the node pauses, its pollers and lease renewals stalling
+	NodePauseSeconds      int      // Duration of a pause of the node
+}
+
+// ArchiveConfig represents the configuration options for moving the fired one time schedules and the runs of the
+// recurring schedules out of Cassandra into object storage once their time bucket is AfterDays old.
+type ArchiveConfig struct {
+	Enabled            bool              // Archives the time buckets AfterDays old of the partitions polled by the node
+	AfterDays          int               // Age in days of the archived time buckets, below the fired schedule retention of the apps
+	Store              string            // Object store the archives are written to: file or http
+	Directory          string            // Directory of the file store, e.g. a mounted bucket
+	Url                string            // Base url of the http store, the objects are PUT to and GET from url/key
+	Headers            map[string]string // Headers of the requests of the http store, e.g. an authorization
+	TimeoutMillis      int               // Timeout of the requests of the http store
+	IndexRetentionDays int               // Days the archived schedules can be looked up by id for, 0 keeps them
+	Routines           int               // Routines archiving the time buckets
+	QueueSize          int               // Time buckets waiting to be archived, the ones past it are left to expire
+}
+
+// HedgedReadConfig represents the configuration options for the reads of a schedule or an app on the API path, which
+// are sent again when slow, bounded by a deadline, and shared by the concurrent requests reading the same row.
+type HedgedReadConfig struct {
+	DelayMillis    int  // Delay after which a read still running is sent again, 0 disables the hedging
+	DeadlineMillis int  // Deadline of a read, after which the request fails with a timeout, 0 disables the deadline
+	SingleFlight   bool // Concurrent reads of the same schedule or app share a single read
+}
+
+// ShadowWriteConfig represents the configuration options for mirroring the writes to a shadow cluster, to migrate to a
+// new cluster or schema without downtime. The reads stay on the primary cluster.
+type ShadowWriteConfig struct {
+	Enabled          bool    // Mirrors the writes of the cluster and schedule keyspaces to the shadow cluster
+	Hosts            string  // Comma-separated list of the hosts of the shadow cluster
+	DataCenter       string  // Data center of the shadow cluster to connect to
+	ClusterKeySpace  string  // Keyspace of the cluster tables in the shadow cluster, the primary one when empty
+	ScheduleKeySpace string  // Keyspace of the schedule tables in the shadow cluster, the primary one when empty
+	CompareRate      float64 // Share of the single row reads compared with the shadow cluster, 0 compares none
+	CompareRoutines  int     // Reads compared at once, the reads sampled past it are not compared
+	MaxRecorded      int     // Failed writes and mismatches kept for the report and the reconciliation
+}
+
+// GovernanceConfig represents the configuration options for the governance hook, a service the schedules are posted to
+// before their creation which approves, mutates or rejects them. The timeout and the failure policy can be overridden
+// in the configuration of an app.
+type GovernanceConfig struct {
+	Url           string            // Url of the governance service, empty disables the hook
+	Headers       map[string]string // Headers of the requests to the governance service, e.g. an authorization
+	TimeoutMillis int               // Timeout of the requests to the governance service
+	FailurePolicy string            // open creates the schedules when the governance service fails, closed rejects them
+}
+
+// RegionConfig represents the configuration options for the regions of the callbacks. A schedule or an app set to
+// another region than the one of the cluster has its http callbacks delivered through the egress proxy of its region,
+// so that they leave from the region of their target.
+type RegionConfig struct {
+	Region        string            // Region of the cluster, empty when the cluster is not region aware
+	EgressProxies map[string]string // Forward proxy of the callbacks of each other region, an http, https or socks5 url
+	Strict        bool              // Fails the callbacks of a region without an egress proxy instead of delivering them from the cluster
+}
+
+// ApprovalConfig represents the configuration options for the two person rule of the destructive operations: deleting
+// an app, deleting schedules in bulk and resizing the partitions of an app are only executed once a second principal
+// approves them.
+type ApprovalConfig struct {
+	Enabled         bool     // Holds the destructive operations for approval
+	PrincipalHeader string   // Header identifying the principal requesting, approving or rejecting an operation
+	Approvers       []string // Principals allowed to approve the operations requested by other principals
+	ExpiryPeriod    int      // Seconds a request waits for its approval before expiring
+	RetentionPeriod int      // Seconds the requests and their audit trail are kept for, 0 keeps them
+}
+
+// HttpServerConfig represents the limits of the requests of the HTTP APIs, so that a slow or oversized request is
+// answered with 408 or 413 instead of holding its goroutine. The routes are the names of the OpenAPI operations.
+type HttpServerConfig struct {
+	ReadHeaderTimeoutMillis int                    // Time allowed to read the headers of a request
+	IdleTimeoutMillis       int                    // Time a keep-alive connection is kept open waiting for the next request
+	Default                 RouteLimits            // Limits of every route
+	Routes                  map[string]RouteLimits // Limits of a route overriding the non zero limits of the default
+}
+
+// RouteLimits represents the limits of the requests of a route, a negative limit disables it
+type RouteLimits struct {
+	ReadTimeoutMillis    int   // Time allowed to read the body of a request
+	HandlerTimeoutMillis int   // Time allowed to handle a request once its body is read
+	MaxBodyBytes         int64 // Size of the largest body accepted
+}
+
+// GetLimits returns the limits of the route, the ones of the default for its zero limits
+func (c HttpServerConfig) GetLimits(route string) RouteLimits {
+	limits := c.Default
+	override := c.Routes[route]
+	if override.ReadTimeoutMillis != 0 {
+		limits.ReadTimeoutMillis = override.ReadTimeoutMillis
+	}
+	if override.HandlerTimeoutMillis != 0 {
+		limits.HandlerTimeoutMillis = override.HandlerTimeoutMillis
+	}
+	if override.MaxBodyBytes != 0 {
+		limits.MaxBodyBytes = override.MaxBodyBytes
+	}
+	return limits
+}
+
+// NotificationConfig represents the configuration options for delivering the failure notifications of the
+// recurring schedules, set per app in its configuration. The smtp settings are only needed by the email channel.
+type NotificationConfig struct {
+	BufferSize    int           // Channel buffer size, failed runs are not checked when the buffer is full
+	Routines      int           // Number of workers checking the failed runs and delivering the notifications
+	TimeoutMillis time.Duration // Timeout for the webhook requests in milliseconds
+	SmtpAddress   string        // host:port of the smtp server sending the emails
+	SmtpUsername  string        // Username of the plain auth with the smtp server, no auth if empty
+	SmtpPassword  string        // Password of the plain auth with the smtp server
+	From          string        // Sender of the emails
+}
+
+// SuspensionConfig represents the configuration options for suspending the recurring schedules whose callback host
+// is not found by the dns or refuses the connections over a long window, instead of retrying them forever.
+type SuspensionConfig struct {
+	Enabled       bool // Suspends the recurring schedules of the unreachable hosts
+	WindowMinutes int  // Minutes a host has to stay unreachable before its schedules are suspended
+	MinFailures   int  // Minimum number of unreachable callbacks to the host over the window
+}
+
+// FeatureFlagConfig represents the flag provider the firing of the callbacks is gated on, so that firing can be turned
+// off during an incident by flipping a flag instead of pausing the schedules.
+type FeatureFlagConfig struct {
+	Provider        string            // Provider of the flags, one of http or file, empty when firing is not gated on flags
+	Url             string            // Flag endpoint of the http provider, answering a JSON object of flag names to booleans
+	Headers         map[string]string // Headers of the requests to the flag endpoint, such as its authorization
+	Path            string            // JSON file of the file provider, holding an object of flag names to booleans
+	RefreshSeconds  int               // Seconds between two reads of the flags from the provider
+	TimeoutMillis   time.Duration     // Timeout of a read of the flag endpoint in milliseconds
+	KillSwitch      string            // Flag turning off the firing of every app while it is false, none if empty
+	Policy          string            // What happens to the runs due while their flag is off, one of skip or defer
+	DeferSeconds    int               // Seconds a run due while its flag is off is deferred by with the defer policy
+	MaxDeferSeconds int               // Seconds past its first due time a run is deferred for at most, skipped after
+}
+
+// RetentionConfig represents the configuration options for purging the deleted recurring schedules and expiring
+// the versions of their definitions.
+type RetentionConfig struct {
+	PurgeEnabled           bool // Purges the deleted recurring schedules past the retention of their app
+	PurgeLimit             int  // Maximum number of schedules purged per partition poll of the cron app
+	StalePauseLimit        int  // Maximum number of stale paused schedules deleted per partition poll of the cron app
+	VersionRetentionPeriod int  // Seconds the replaced definitions of the recurring schedules are kept for, 0 keeps them
+	JobRetentionPeriod     int  // Seconds the asynchronous jobs and the results of their items are kept for, 0 keeps them
+}
+
+// BlackoutWindow is a period during which no callbacks fire, either from StartTime to EndTime in epoch seconds
+// or for DurationMinutes from every minute matching Cron in UTC.
+type BlackoutWindow struct {
+	Cron            string `json:"cron,omitempty"`
+	DurationMinutes int    `json:"durationMinutes,omitempty"`
+	StartTime       int64  `json:"startTime,omitempty"`
+	EndTime         int64  `json:"endTime,omitempty"`
+}
+
+// BlackoutConfig represents the blackout windows applying to every app.
+type BlackoutConfig struct {
+	Windows []BlackoutWindow // Windows during which no callbacks fire for any app
+	Policy  string           // What happens to the schedules due within a window, skip (default) or queue
+}
+
+// BackpressureConfig represents the thresholds from which the create APIs are rejected with 429.
+type BackpressureConfig struct {
+	Enabled               bool  // Enables the rejection of new schedules when the node is overloaded
+	MaxBacklog            int   // Callbacks waiting for a worker from which the node is overloaded, 0 disables the check
+	MaxQueryLatencyMillis int64 // Recent Cassandra query latency from which the node is overloaded, 0 disables the check
+	RetryAfterSeconds     int   // Retry-After returned when a threshold is just crossed
+	MaxRetryAfterSeconds  int   // Upper bound of the Retry-After, which grows with the overload
 }
 
 type AppLevelConfiguration struct {
@@ -186,6 +661,9 @@ type AppLevelConfiguration struct {
 
 	// HTTP Timeout in milliseconds for requests
 	HttpTimeout int
+
+	// Period in days for which deleted recurring schedules are kept in DB before they are purged
+	DeletedScheduleRetentionPeriod int
 }
 
 type DCConfig struct {
@@ -221,6 +699,42 @@ type Configuration struct {
 	BulkActionConfig         BulkActionConfig         // Configuration options for bulk actions
 	AppLevelConfiguration    AppLevelConfiguration    // Configuration options for app level configuration
 	DCConfig                 DCConfig                 // Configuration options for DC configuration
+	DeliveryReceiptConfig    DeliveryReceiptConfig    // Configuration options for delivery receipts
+	StatusCallbackConfig     StatusCallbackConfig     // Configuration options for status callbacks
+	EventPublisherConfig     EventPublisherConfig     // Configuration options for lifecycle event publishing
+	CallbackPluginConfig     CallbackPluginConfig     // Configuration options for callback plugins
+	AdminUIConfig            AdminUIConfig            // Configuration options for the admin dashboard
+	LogConfig                LogConfig                // Configuration options for logging
+	DiagnosticsConfig        DiagnosticsConfig        // Configuration options for diagnostics
+	ReplicationConfig        ReplicationConfig        // Configuration options for cross datacenter replication
+	BackpressureConfig       BackpressureConfig       // Configuration options for shedding the create APIs under overload
+	RetentionConfig          RetentionConfig          // Configuration options for purging deleted schedules
+	BlackoutConfig           BlackoutConfig           // Configuration options for the blackout windows of every app
+	SLAConfig                SLAConfig                // Configuration options for alerting on the firing lag of the callbacks
+	AnomalyConfig            AnomalyConfig            // Configuration options for alerting on creation spikes and failure rate jumps
+	UsageConfig              UsageConfig              // Configuration options for the daily usage rollups of the apps
+	RunStatsConfig           RunStatsConfig           // Configuration options for the hourly rollups of the outcome of the runs
+	GraphQLConfig            GraphQLConfig            // Configuration options for the GraphQL queries
+	UrlVerificationConfig    UrlVerificationConfig    // Configuration options for the verification of the callback urls
+	EgressConfig             EgressConfig             // Configuration options for restricting the destinations of the callbacks
+	SheddingConfig           SheddingConfig           // Configuration options for shedding callbacks when the workers are overloaded
+	ParkingConfig            ParkingConfig            // Configuration options for parking the far future one time schedules
+	SecretsConfig            SecretsConfig            // Configuration options for resolving the secret references of the callbacks
+	CallbackLogConfig        CallbackLogConfig        // Configuration options for the execution logs of the callbacks
+	PullDeliveryConfig       PullDeliveryConfig       // Configuration options for the runs polled by their consumers
+	EventStreamConfig        EventStreamConfig        // Configuration options for streaming the fire events of the apps
+	ClockConfig              ClockConfig              // Configuration options for the clock the schedules are fired on
+	FaultInjectionConfig     FaultInjectionConfig     // Configuration options for injecting faults in resilience tests
+	ArchiveConfig            ArchiveConfig            // Configuration options for archiving the fired schedules to object storage
+	HedgedReadConfig         HedgedReadConfig         // Configuration options for the hedged reads of the schedules and apps on the API path
+	ShadowWriteConfig        ShadowWriteConfig        // Configuration options for mirroring the writes to a shadow cluster
+	GovernanceConfig         GovernanceConfig         // Configuration options for the governance hook of the creation of the schedules
+	RegionConfig             RegionConfig             // Configuration options for routing the callbacks of the schedules of other regions
+	ApprovalConfig           ApprovalConfig           // Configuration options for the approval of the destructive operations
+	HttpServerConfig         HttpServerConfig         // Configuration options for the timeouts and body size limits of the HTTP requests
+	NotificationConfig       NotificationConfig       // Configuration options for notifying the consecutive failures of the recurring schedules
+	SuspensionConfig         SuspensionConfig         // Configuration options for suspending the recurring schedules of unreachable callback hosts
+	FeatureFlagConfig        FeatureFlagConfig        // Configuration options for gating the firing of the callbacks on feature flags
 }
 
 var defaultConfig = Configuration{
@@ -234,6 +748,14 @@ var defaultConfig = Configuration{
 		BootStrapServers: []string{"127.0.0.1:9091"},
 		JoinSize:         1,
 		ReplicaPoints:    2,
+		Coordination: Coordination{
+			Backend: "ringpop",
+			Etcd: Etcd{
+				Prefix:          "/goscheduler",
+				LeaseTTLSeconds: 5,
+				TimeoutMillis:   2000,
+			},
+		},
 	},
 	ClusterDB: ClusterDBConfig{
 		ClusterKeySpace: "cluster",
@@ -248,6 +770,14 @@ var defaultConfig = Configuration{
 				ConnectTimeout:        5000,
 				MaxNumConnections:     4,
 			},
+			QueryRetry: QueryRetry{
+				MaxRetries:       2,
+				MinBackoffMillis: 50,
+				MaxBackoffMillis: 1000,
+			},
+			Speculative: Speculative{
+				DelayMillis: 100,
+			},
 		},
 		EntityHistorySize: 5,
 	},
@@ -264,18 +794,36 @@ var defaultConfig = Configuration{
 				ConnectTimeout:        5000,
 				MaxNumConnections:     4,
 			},
+			QueryRetry: QueryRetry{
+				MaxRetries:       2,
+				MinBackoffMillis: 50,
+				MaxBackoffMillis: 1000,
+			},
+			Speculative: Speculative{
+				DelayMillis: 100,
+			},
 		},
 	},
 	Poller: PollerConfig{
-		Interval:      60,
-		DefaultCount:  5,
-		MaxQueryLimit: 4,
+		Interval:              60,
+		DefaultCount:          5,
+		MaxQueryLimit:         4,
+		StreamBufferSize:      1000,
+		MinIntervalSeconds:    10,
+		BusyThreshold:         1000,
+		MaxLookaheadMinutes:   5,
+		MaxPendingSchedules:   10000,
+		IntentGraceSeconds:    600,
+		IntentMaxAttempts:     3,
+		IntentLookbackMinutes: 60,
 	},
 	MonitoringConfig: MonitoringConfig{Statsd: nil},
 	HttpConnector: HttpConnectorConfig{
-		Routines:      10,
-		MaxRetry:      3,
-		TimeoutMillis: 1000,
+		Routines:            10,
+		MaxRetry:            3,
+		TimeoutMillis:       1000,
+		ResponseSnippetSize: 256,
+		MaxRetryAfterMillis: 10000,
 	},
 	CronConfig: CronConfig{
 		App:      "Athena",
@@ -298,16 +846,214 @@ var defaultConfig = Configuration{
 		Routines:   10,
 	},
 	AppLevelConfiguration: AppLevelConfiguration{
-		FutureScheduleCreationPeriod: 7,
-		FiredScheduleRetentionPeriod: 1,
-		PayloadSize:                  1024,
-		HttpRetries:                  1,
-		HttpTimeout:                  1000,
+		FutureScheduleCreationPeriod:   7,
+		FiredScheduleRetentionPeriod:   1,
+		PayloadSize:                    1024,
+		HttpRetries:                    1,
+		HttpTimeout:                    1000,
+		DeletedScheduleRetentionPeriod: 7,
 	},
 	DCConfig: DCConfig{
 		Prefix:   "",
 		Location: "Local",
 	},
+	DeliveryReceiptConfig: DeliveryReceiptConfig{
+		Enabled: false,
+		KeyId:   "v1",
+	},
+	StatusCallbackConfig: StatusCallbackConfig{
+		BufferSize:    1000,
+		Routines:      5,
+		TimeoutMillis: 1000,
+	},
+	EventPublisherConfig: EventPublisherConfig{
+		Type:          "noop",
+		Topic:         "goscheduler.events",
+		BufferSize:    1000,
+		Routines:      2,
+		TimeoutMillis: 1000,
+	},
+	CallbackPluginConfig: CallbackPluginConfig{
+		Routines:      10,
+		TimeoutMillis: 1000,
+	},
+	AdminUIConfig: AdminUIConfig{
+		Enabled: true,
+	},
+	LogConfig: LogConfig{
+		Format: "json",
+		Level:  "info",
+	},
+	DiagnosticsConfig: DiagnosticsConfig{
+		SlowQueryThresholdMillis: 100,
+		SlowQueryLimit:           50,
+	},
+	ReplicationConfig: ReplicationConfig{
+		Type:                  "kafka",
+		Topic:                 "goscheduler.events",
+		Group:                 "goscheduler-standby",
+		PollIntervalMillis:    1000,
+		TimeoutMillis:         5000,
+		RoleRefreshSeconds:    10,
+		ResyncIntervalSeconds: 3600,
+		ResyncTimeoutSeconds:  60,
+	},
+	BackpressureConfig: BackpressureConfig{
+		MaxBacklog:            5000,
+		MaxQueryLatencyMillis: 500,
+		RetryAfterSeconds:     5,
+		MaxRetryAfterSeconds:  60,
+	},
+	RetentionConfig: RetentionConfig{
+		PurgeEnabled:           true,
+		PurgeLimit:             100,
+		StalePauseLimit:        100,
+		VersionRetentionPeriod: 90 * 24 * 60 * 60,
+		JobRetentionPeriod:     7 * 24 * 60 * 60,
+	},
+	BlackoutConfig: BlackoutConfig{
+		Policy: "skip",
+	},
+	SLAConfig: SLAConfig{
+		LagThresholdMillis: 5000,
+		ConsecutiveMinutes: 3,
+		AlertType:          "noop",
+		TimeoutMillis:      2000,
+	},
+	AnomalyConfig: AnomalyConfig{
+		Sensitivity:     4,
+		BaselineMinutes: 60,
+		WarmupMinutes:   30,
+		MinCreations:    100,
+		MinCallbacks:    50,
+	},
+	UsageConfig: UsageConfig{
+		Enabled:              true,
+		FlushIntervalSeconds: 60,
+	},
+	RunStatsConfig: RunStatsConfig{
+		Enabled:              true,
+		FlushIntervalSeconds: 60,
+	},
+	GraphQLConfig: GraphQLConfig{
+		MaxDepth:    6,
+		MaxListSize: 100,
+	},
+	UrlVerificationConfig: UrlVerificationConfig{
+		TimeoutMillis:      5000,
+		ProbeTimeoutMillis: 2000,
+	},
+	EgressConfig: EgressConfig{
+		DeniedCIDRs: []string{"169.254.0.0/16", "fe80::/10"},
+	},
+	SheddingConfig: SheddingConfig{
+		MaxBacklog:     1000,
+		OverdueSeconds: 600,
+		Policy:         "defer",
+		DeferSeconds:   300,
+	},
+	ParkingConfig: ParkingConfig{
+		HorizonDays:   30,
+		PromotionDays: 7,
+		Shards:        16,
+	},
+	SecretsConfig: SecretsConfig{
+		VaultTokenEnv: "VAULT_TOKEN",
+		CacheSeconds:  60,
+	},
+	CallbackLogConfig: CallbackLogConfig{
+		Enabled:     false,
+		SnippetSize: 1024,
+	},
+	PullDeliveryConfig: PullDeliveryConfig{
+		QueueSize:          1000,
+		Routines:           5,
+		BufferSize:         1000,
+		MaxDeliveries:      3,
+		LeaseSeconds:       30,
+		MaxLeaseSeconds:    300,
+		MaxWaitSeconds:     30,
+		PollIntervalMillis: 500,
+	},
+	EventStreamConfig: EventStreamConfig{
+		MaxSubscribers:   100,
+		BufferSize:       100,
+		HeartbeatSeconds: 15,
+	},
+	ClockConfig: ClockConfig{
+		Virtual: false,
+	},
+	FaultInjectionConfig: FaultInjectionConfig{
+		Enabled: false,
+	},
+	ArchiveConfig: ArchiveConfig{
+		Enabled:            false,
+		AfterDays:          7,
+		Store:              "file",
+		Directory:          "/var/lib/goscheduler/archive",
+		TimeoutMillis:      10000,
+		IndexRetentionDays: 365,
+		Routines:           2,
+		QueueSize:          1000,
+	},
+	HedgedReadConfig: HedgedReadConfig{
+		DelayMillis:    50,
+		DeadlineMillis: 5000,
+		SingleFlight:   true,
+	},
+	ShadowWriteConfig: ShadowWriteConfig{
+		Enabled:         false,
+		CompareRate:     0.01,
+		CompareRoutines: 10,
+		MaxRecorded:     1000,
+	},
+	GovernanceConfig: GovernanceConfig{
+		TimeoutMillis: 500,
+		FailurePolicy: "open",
+	},
+	ApprovalConfig: ApprovalConfig{
+		Enabled:         false,
+		PrincipalHeader: "X-Principal",
+		ExpiryPeriod:    86400,
+		RetentionPeriod: 7776000,
+	},
+	HttpServerConfig: HttpServerConfig{
+		ReadHeaderTimeoutMillis: 10000,
+		IdleTimeoutMillis:       120000,
+		Default: RouteLimits{
+			ReadTimeoutMillis:    30000,
+			HandlerTimeoutMillis: 30000,
+			MaxBodyBytes:         1 << 20,
+		},
+		Routes: map[string]RouteLimits{
+			"BulkAction":            {ReadTimeoutMillis: 120000, HandlerTimeoutMillis: 120000, MaxBodyBytes: 32 << 20},
+			"bulk_update_schedules": {ReadTimeoutMillis: 120000, HandlerTimeoutMillis: 120000, MaxBodyBytes: 32 << 20},
+			"import_schedules":      {ReadTimeoutMillis: 300000, HandlerTimeoutMillis: 300000, MaxBodyBytes: 256 << 20},
+			"apply_schedules":       {ReadTimeoutMillis: 120000, HandlerTimeoutMillis: 120000, MaxBodyBytes: 32 << 20},
+			"PauseSchedule":         {ReadTimeoutMillis: 5000, HandlerTimeoutMillis: 10000, MaxBodyBytes: 4 << 10},
+			"ResumeSchedule":        {ReadTimeoutMillis: 5000, HandlerTimeoutMillis: 10000, MaxBodyBytes: 4 << 10},
+			"export_schedules":      {HandlerTimeoutMillis: -1},
+			"stream_events":         {HandlerTimeoutMillis: -1},
+		},
+	},
+	NotificationConfig: NotificationConfig{
+		BufferSize:    1000,
+		Routines:      2,
+		TimeoutMillis: 5000,
+		From:          "goscheduler@localhost",
+	},
+	SuspensionConfig: SuspensionConfig{
+		Enabled:       true,
+		WindowMinutes: 1440,
+		MinFailures:   10,
+	},
+	FeatureFlagConfig: FeatureFlagConfig{
+		RefreshSeconds:  10,
+		TimeoutMillis:   2000,
+		Policy:          "skip",
+		DeferSeconds:    300,
+		MaxDeferSeconds: 86400,
+	},
 }
 
 type Option func(*Configuration)
@@ -414,6 +1160,222 @@ func WithDCConfiguration(dcConfig DCConfig) Option {
 	}
 }
 
+func WithDeliveryReceiptConfig(deliveryReceiptConfig DeliveryReceiptConfig) Option {
+	return func(c *Configuration) {
+		c.DeliveryReceiptConfig = deliveryReceiptConfig
+	}
+}
+
+func WithStatusCallbackConfig(statusCallbackConfig StatusCallbackConfig) Option {
+	return func(c *Configuration) {
+		c.StatusCallbackConfig = statusCallbackConfig
+	}
+}
+
+func WithEventPublisherConfig(eventPublisherConfig EventPublisherConfig) Option {
+	return func(c *Configuration) {
+		c.EventPublisherConfig = eventPublisherConfig
+	}
+}
+
+func WithCallbackPluginConfig(callbackPluginConfig CallbackPluginConfig) Option {
+	return func(c *Configuration) {
+		c.CallbackPluginConfig = callbackPluginConfig
+	}
+}
+
+func WithAdminUIConfig(adminUIConfig AdminUIConfig) Option {
+	return func(c *Configuration) {
+		c.AdminUIConfig = adminUIConfig
+	}
+}
+
+func WithLogConfig(logConfig LogConfig) Option {
+	return func(c *Configuration) {
+		c.LogConfig = logConfig
+	}
+}
+
+func WithDiagnosticsConfig(diagnosticsConfig DiagnosticsConfig) Option {
+	return func(c *Configuration) {
+		c.DiagnosticsConfig = diagnosticsConfig
+	}
+}
+
+func WithReplicationConfig(replicationConfig ReplicationConfig) Option {
+	return func(c *Configuration) {
+		c.ReplicationConfig = replicationConfig
+	}
+}
+
+func WithBackpressureConfig(backpressureConfig BackpressureConfig) Option {
+	return func(c *Configuration) {
+		c.BackpressureConfig = backpressureConfig
+	}
+}
+
+func WithRetentionConfig(retentionConfig RetentionConfig) Option {
+	return func(c *Configuration) {
+		c.RetentionConfig = retentionConfig
+	}
+}
+
+func WithBlackoutConfig(blackoutConfig BlackoutConfig) Option {
+	return func(c *Configuration) {
+		c.BlackoutConfig = blackoutConfig
+	}
+}
+
+func WithSLAConfig(slaConfig SLAConfig) Option {
+	return func(c *Configuration) {
+		c.SLAConfig = slaConfig
+	}
+}
+
+func WithAnomalyConfig(anomalyConfig AnomalyConfig) Option {
+	return func(c *Configuration) {
+		c.AnomalyConfig = anomalyConfig
+	}
+}
+
+func WithUsageConfig(usageConfig UsageConfig) Option {
+	return func(c *Configuration) {
+		c.UsageConfig = usageConfig
+	}
+}
+
+func WithRunStatsConfig(runStatsConfig RunStatsConfig) Option {
+	return func(c *Configuration) {
+		c.RunStatsConfig = runStatsConfig
+	}
+}
+
+func WithGraphQLConfig(graphQLConfig GraphQLConfig) Option {
+	return func(c *Configuration) {
+		c.GraphQLConfig = graphQLConfig
+	}
+}
+
+func WithUrlVerificationConfig(urlVerificationConfig UrlVerificationConfig) Option {
+	return func(c *Configuration) {
+		c.UrlVerificationConfig = urlVerificationConfig
+	}
+}
+
+func WithEgressConfig(egressConfig EgressConfig) Option {
+	return func(c *Configuration) {
+		c.EgressConfig = egressConfig
+	}
+}
+
+func WithSheddingConfig(sheddingConfig SheddingConfig) Option {
+	return func(c *Configuration) {
+		c.SheddingConfig = sheddingConfig
+	}
+}
+
+func WithParkingConfig(parkingConfig ParkingConfig) Option {
+	return func(c *Configuration) {
+		c.ParkingConfig = parkingConfig
+	}
+}
+
+func WithSecretsConfig(secretsConfig SecretsConfig) Option {
+	return func(c *Configuration) {
+		c.SecretsConfig = secretsConfig
+	}
+}
+
+func WithCallbackLogConfig(callbackLogConfig CallbackLogConfig) Option {
+	return func(c *Configuration) {
+		c.CallbackLogConfig = callbackLogConfig
+	}
+}
+
+func WithPullDeliveryConfig(pullDeliveryConfig PullDeliveryConfig) Option {
+	return func(c *Configuration) {
+		c.PullDeliveryConfig = pullDeliveryConfig
+	}
+}
+
+func WithEventStreamConfig(eventStreamConfig EventStreamConfig) Option {
+	return func(c *Configuration) {
+		c.EventStreamConfig = eventStreamConfig
+	}
+}
+
+func WithClockConfig(clockConfig ClockConfig) Option {
+	return func(c *Configuration) {
+		c.ClockConfig = clockConfig
+	}
+}
+
+func WithFaultInjectionConfig(faultInjectionConfig FaultInjectionConfig) Option {
+	return func(c *Configuration) {
+		c.FaultInjectionConfig = faultInjectionConfig
+	}
+}
+
+func WithArchiveConfig(archiveConfig ArchiveConfig) Option {
+	return func(c *Configuration) {
+		c.ArchiveConfig = archiveConfig
+	}
+}
+
+func WithHedgedReadConfig(hedgedReadConfig HedgedReadConfig) Option {
+	return func(c *Configuration) {
+		c.HedgedReadConfig = hedgedReadConfig
+	}
+}
+
+func WithShadowWriteConfig(shadowWriteConfig ShadowWriteConfig) Option {
+	return func(c *Configuration) {
+		c.ShadowWriteConfig = shadowWriteConfig
+	}
+}
+
+func WithGovernanceConfig(governanceConfig GovernanceConfig) Option {
+	return func(c *Configuration) {
+		c.GovernanceConfig = governanceConfig
+	}
+}
+
+func WithRegionConfig(regionConfig RegionConfig) Option {
+	return func(c *Configuration) {
+		c.RegionConfig = regionConfig
+	}
+}
+
+func WithApprovalConfig(approvalConfig ApprovalConfig) Option {
+	return func(c *Configuration) {
+		c.ApprovalConfig = approvalConfig
+	}
+}
+
+func WithHttpServerConfig(httpServerConfig HttpServerConfig) Option {
+	return func(c *Configuration) {
+		c.HttpServerConfig = httpServerConfig
+	}
+}
+
+func WithNotificationConfig(notificationConfig NotificationConfig) Option {
+	return func(c *Configuration) {
+		c.NotificationConfig = notificationConfig
+	}
+}
+
+func WithSuspensionConfig(suspensionConfig SuspensionConfig) Option {
+	return func(c *Configuration) {
+		c.SuspensionConfig = suspensionConfig
+	}
+}
+
+func WithFeatureFlagConfig(featureFlagConfig FeatureFlagConfig) Option {
+	return func(c *Configuration) {
+		c.FeatureFlagConfig = featureFlagConfig
+	}
+}
+
 func NewConfig(opts ...Option) *Configuration {
 	config := defaultConfig
 	for _, opt := range opts {
@@ -468,8 +1430,8 @@ func InitConfig(confFile, port, address string) *Configuration {
 func LoadConfig(confFile string) *Configuration {
 	var GlobalConfig Configuration
 	if err := configor.Load(&GlobalConfig, confFile); err != nil {
-		glog.Fatalln(constants.ErrorConfig, err)
+		logger.Fatal(constants.ErrorConfig, err)
 	}
-	glog.Infoln("Config:", GlobalConfig)
+	logger.Info("Config:", GlobalConfig)
 	return &GlobalConfig
 }
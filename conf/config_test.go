@@ -0,0 +1,54 @@
+package conf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAggregateSchedulesConfig_FlushWindow(t *testing.T) {
+	if window := NewConfig().AggregateSchedulesConfig.FlushWindow(); window != 60*time.Second {
+		t.Errorf("expected the default flush window of FlushPeriod, got %s", window)
+	}
+
+	for _, test := range []struct {
+		config AggregateSchedulesConfig
+		window time.Duration
+	}{
+		{AggregateSchedulesConfig{FlushPeriod: 10}, 10 * time.Second},
+		{AggregateSchedulesConfig{FlushPeriod: 10, FlushMillis: 200}, 200 * time.Millisecond},
+	} {
+		if window := NewConfig(WithAggregateSchedulesConfig(test.config)).AggregateSchedulesConfig.FlushWindow(); window != test.window {
+			t.Errorf("config %+v: expected a flush window of %s, got %s", test.config, test.window, window)
+		}
+	}
+}
+
+func TestLoadConfig_FlushPeriodWithoutFlushMillis(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conf.json")
+	if err := ioutil.WriteFile(path, []byte(`{"AggregateSchedulesConfig": {"BatchSize": 50, "FlushPeriod": 5}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if window := LoadConfig(path).AggregateSchedulesConfig.FlushWindow(); window != 5*time.Second {
+		t.Errorf("expected the FlushPeriod of a config without FlushMillis to apply, got %s", window)
+	}
+}
+
+func TestPollerConfig_LastPolledBucket(t *testing.T) {
+	minute := time.Date(2024, time.February, 28, 23, 59, 0, 0, time.UTC)
+	for _, test := range []struct {
+		config PollerConfig
+		at     time.Time
+		bucket time.Time
+	}{
+		{PollerConfig{}, minute.Add(55 * time.Second), minute},
+		{PollerConfig{PrefetchSeconds: 10}, minute.Add(45 * time.Second), minute},
+		{PollerConfig{PrefetchSeconds: 10}, minute.Add(50 * time.Second), minute.Add(time.Minute)},
+	} {
+		if bucket := test.config.LastPolledBucket(test.at); !bucket.Equal(test.bucket) {
+			t.Errorf("config %+v at %s: expected the last polled bucket %s, got %s", test.config, test.at, test.bucket, bucket)
+		}
+	}
+}
@@ -20,92 +20,130 @@
 package connectors
 
 import (
-	"fmt"
-	"sync"
+	"hash/fnv"
+	"strconv"
 	"time"
 
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 )
 
+// Triggers of a status batch write
+const (
+	fullBatch    = "full"
+	flushedBatch = "flush"
+	drainedBatch = "drain"
+)
+
+// statusKey identifies the partition of the status table the statuses of a run are written to
+type statusKey struct {
+	appId       string
+	partitionId int
+}
+
+// statusBatch holds the statuses of a partition waiting to be written, the latest status of every run
+type statusBatch struct {
+	app       store.App
+	schedules []store.Schedule
+	index     map[gocql.UUID]int
+}
+
+// add puts the status of a run in the batch, replacing an earlier status of the same run
+func (b *statusBatch) add(schedule store.Schedule) {
+	if i, ok := b.index[schedule.ScheduleId]; ok {
+		b.schedules[i] = schedule
+		return
+	}
+	b.index[schedule.ScheduleId] = len(b.schedules)
+	b.schedules = append(b.schedules, schedule)
+}
+
 // aggregate schedules based on appId, partitionId
-// forward messages to status update channel once batch is full
-// schedules are flushed to db periodically as well
+// forward a batch to status update channel once it is full
+// batches are flushed to db at the end of every flush window as well
 func (c *Connector) aggregateSchedules(buf <-chan store.ScheduleWrapper) {
-	schedules := make(map[string]map[int][]store.Schedule)
-	var lock sync.RWMutex
-
-	go func() {
-		var app store.App
-		var reuse bool
-
-		for range time.Tick(time.Duration(c.Config.AggregateSchedulesConfig.FlushPeriod) * time.Second) {
-
-			lock.Lock()
-
-			for appId := range schedules {
-				reuse = false
-				for partitionId := range schedules[appId] {
-					if len(schedules[appId][partitionId]) > 0 {
-						if !reuse {
-							app, _ = c.ClusterDao.GetApp(appId)
-							reuse = true
-						}
-
-						store.StatusTaskQueue <- store.StatusTask{
-							Schedules: schedules[appId][partitionId],
-							App:       app,
-						}
-					}
-				}
+	batches := make(map[statusKey]*statusBatch)
+	ticker := time.NewTicker(c.Config.AggregateSchedulesConfig.FlushWindow())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case sw, ok := <-buf:
+			if !ok {
+				c.flushStatusBatches(batches, drainedBatch)
+				return
 			}
-			//flush the contents of the map
-			if len(schedules) > 0 {
-				schedules = map[string]map[int][]store.Schedule{}
+
+			key := statusKey{appId: sw.Schedule.AppId, partitionId: sw.Schedule.PartitionId}
+			batch, ok := batches[key]
+			if !ok {
+				batch = &statusBatch{app: sw.App, index: make(map[gocql.UUID]int)}
+				batches[key] = batch
 			}
+			batch.add(sw.Schedule)
 
-			lock.Unlock()
+			if len(batch.schedules) >= c.Config.AggregateSchedulesConfig.BatchSize {
+				c.writeStatusBatch(batch, fullBatch)
+				delete(batches, key)
+			}
+		case <-ticker.C:
+			c.flushStatusBatches(batches, flushedBatch)
 		}
-	}()
-
-	for sw := range buf {
-		result := sw.Schedule
-		app := sw.App
+	}
+}
 
-		appId := result.AppId
-		partitionId := result.PartitionId
+// flushStatusBatches forwards every pending batch to the status update channel
+func (c *Connector) flushStatusBatches(batches map[statusKey]*statusBatch, trigger string) {
+	for key, batch := range batches {
+		c.writeStatusBatch(batch, trigger)
+		delete(batches, key)
+	}
+}
 
-		lock.Lock()
+// writeStatusBatch forwards a batch to the status update channel for a single write to its partition
+func (c *Connector) writeStatusBatch(batch *statusBatch, trigger string) {
+	store.StatusTaskQueue <- store.StatusTask{
+		Schedules: batch.schedules,
+		App:       batch.app,
+	}
 
-		if schedules[appId] == nil {
-			schedules[appId] = map[int][]store.Schedule{}
-		}
-		if schedules[appId][partitionId] == nil {
-			schedules[appId][partitionId] = []store.Schedule{}
-		}
+	if c.Monitor != nil {
+		labels := map[string]string{"appId": batch.app.AppId, "trigger": trigger}
+		c.Monitor.IncCounter(constants.StatusBatchCount, labels, 1)
+		c.Monitor.IncCounter(constants.BatchedStatusCount, labels, len(batch.schedules))
+	}
+}
 
-		schedules[appId][partitionId] = append(schedules[appId][partitionId], result)
+// statusWorker picks the aggregation worker of the partition a status belongs to
+func statusWorker(schedule store.Schedule, workers int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(schedule.AppId + ":" + strconv.Itoa(schedule.PartitionId)))
+	return int(h.Sum32() % uint32(workers))
+}
 
-		if len(schedules[appId][partitionId]) == c.Config.AggregateSchedulesConfig.BatchSize {
-			//forward to status update channel for batch update
-			store.StatusTaskQueue <- store.StatusTask{
-				Schedules: schedules[appId][partitionId],
-				App:       app,
-			}
+// create status aggregation work pool
+// statuses are routed by partition, so that all the statuses of a partition are batched by the same worker
+func (c *Connector) CreateAggregateSchedulesPool(buf chan store.ScheduleWrapper) {
+	noOfWorkers := c.Config.AggregateSchedulesConfig.Routines
+	if noOfWorkers <= 0 {
+		return
+	}
 
-			//delete the key from map
-			delete(schedules[appId], partitionId)
-		}
+	workers := make([]chan store.ScheduleWrapper, noOfWorkers)
+	for i := range workers {
+		logger.Debugf("Initializing aggregation worker %d", i)
+		workers[i] = make(chan store.ScheduleWrapper, c.Config.AggregateSchedulesConfig.BatchSize)
+		go c.aggregateSchedules(workers[i])
+	}
 
-		lock.Unlock()
+	for sw := range buf {
+		workers[statusWorker(sw.Schedule, noOfWorkers)] <- sw
 	}
-}
 
-// create status update work pool
-func (c *Connector) CreateAggregateSchedulesPool(buf chan store.ScheduleWrapper) {
-	noOfWorkers := c.Config.AggregateSchedulesConfig.Routines
-	for i := 0; i < noOfWorkers; i++ {
-		fmt.Printf("Initializing aggregation workers %d \n", i)
-		go c.aggregateSchedules(buf)
+	for _, worker := range workers {
+		close(worker)
 	}
 }
 
@@ -0,0 +1,85 @@
+package connectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+func statusOf(appId string, partitionId int, scheduleId gocql.UUID, status store.Status) store.ScheduleWrapper {
+	return store.ScheduleWrapper{
+		Schedule: store.Schedule{AppId: appId, PartitionId: partitionId, ScheduleId: scheduleId, Status: status},
+		App:      store.App{AppId: appId},
+	}
+}
+
+func TestConnector_AggregateSchedules(t *testing.T) {
+	store.StatusTaskQueue = make(chan store.StatusTask, 10)
+	c := &Connector{Config: &conf.Configuration{
+		AggregateSchedulesConfig: conf.AggregateSchedulesConfig{Routines: 3, BatchSize: 3, FlushMillis: 200},
+	}}
+
+	buf := make(chan store.ScheduleWrapper)
+	go c.CreateAggregateSchedulesPool(buf)
+
+	first, second, third := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	buf <- statusOf("a", 0, first, store.Failure)
+	buf <- statusOf("b", 0, gocql.TimeUUID(), store.Success)
+	buf <- statusOf("a", 1, gocql.TimeUUID(), store.Success)
+	buf <- statusOf("a", 0, second, store.Success)
+	buf <- statusOf("a", 0, first, store.Success)
+	buf <- statusOf("a", 0, third, store.Success)
+
+	var full store.StatusTask
+	select {
+	case full = <-store.StatusTaskQueue:
+	case <-time.After(150 * time.Millisecond):
+		t.Fatal("expected the full batch of a/0 to be written before the flush window")
+	}
+	if full.App.AppId != "a" || len(full.Schedules) != 3 {
+		t.Fatalf("expected a batch of 3 statuses of a, got %+v", full)
+	}
+	for _, schedule := range full.Schedules {
+		if schedule.PartitionId != 0 {
+			t.Errorf("expected only statuses of partition 0, got %d", schedule.PartitionId)
+		}
+		if schedule.ScheduleId == first && schedule.Status != store.Success {
+			t.Errorf("expected the latest status of a run, got %s", schedule.Status)
+		}
+	}
+
+	flushed := map[string]int{}
+	for i := 0; i < 2; i++ {
+		select {
+		case task := <-store.StatusTaskQueue:
+			for _, schedule := range task.Schedules {
+				if schedule.AppId != task.App.AppId {
+					t.Errorf("expected statuses of %s only, got %s", task.App.AppId, schedule.AppId)
+				}
+			}
+			flushed[task.App.AppId] += len(task.Schedules)
+		case <-time.After(time.Second):
+			t.Fatal("expected the pending batches to be flushed at the end of the window")
+		}
+	}
+	if flushed["a"] != 1 || flushed["b"] != 1 {
+		t.Errorf("expected one pending status of a/1 and b/0, got %v", flushed)
+	}
+	close(buf)
+}
+
+func TestStatusWorker(t *testing.T) {
+	schedule := store.Schedule{AppId: "a", PartitionId: 7}
+	worker := statusWorker(schedule, 5)
+	for i := 0; i < 10; i++ {
+		if got := statusWorker(schedule, 5); got != worker {
+			t.Fatalf("expected the statuses of a partition to go to worker %d, got %d", worker, got)
+		}
+	}
+	if worker < 0 || worker >= 5 {
+		t.Errorf("expected a worker in [0, 5), got %d", worker)
+	}
+}
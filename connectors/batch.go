@@ -0,0 +1,280 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// callbackBatch is a batch of runs waiting to be delivered in a single http callback
+type callbackBatch struct {
+	app      store.App
+	region   string
+	wrappers []store.ScheduleWrapper
+}
+
+// callbackBatches holds the batches of the apps with callback batching which are waiting for more runs, by key
+type callbackBatches struct {
+	mu      sync.Mutex
+	pending map[string]*callbackBatch
+}
+
+// isBatched tells whether the run of the wrapper is delivered in a batch. Shadow, canary and reconciled runs and
+// the callbacks asserting or acting on their own response or bound by a deadline are delivered on their own
+func isBatched(wrapper store.ScheduleWrapper) bool {
+	if wrapper.App.Configuration.CallbackBatching == nil || wrapper.Shadow || wrapper.Canary != "" || wrapper.IsReconciliation {
+		return false
+	}
+	callback, ok := wrapper.Schedule.Callback.(*store.HttpCallback)
+	return ok && callback.Details.Assertion == nil && !callback.Details.RescheduleFromResponse && callback.Details.DeadlineMillis == 0
+}
+
+// batchCallback adds the run of the wrapper to the batch of its target. A full batch is delivered right away, the
+// others when the first run of the batch has waited for the linger of the app
+func (c *Connector) batchCallback(wrapper store.ScheduleWrapper) {
+	batching := wrapper.App.Configuration.CallbackBatching
+	key := store.BatchKey(wrapper.Schedule)
+
+	c.batches.mu.Lock()
+	if c.batches.pending == nil {
+		c.batches.pending = make(map[string]*callbackBatch)
+	}
+	batch, ok := c.batches.pending[key]
+	if !ok {
+		batch = &callbackBatch{app: wrapper.App, region: wrapper.Schedule.GetRegion(wrapper.App)}
+		c.batches.pending[key] = batch
+		time.AfterFunc(batching.Linger(), func() {
+			c.flushBatch(key, batch)
+		})
+	}
+	batch.wrappers = append(batch.wrappers, wrapper)
+	full := len(batch.wrappers) >= batching.MaxItems
+	if full {
+		delete(c.batches.pending, key)
+	}
+	c.batches.mu.Unlock()
+
+	if full {
+		c.deliverBatch(batch)
+	}
+}
+
+// flushBatch delivers the batch if it is still waiting, a batch which filled up has been delivered already
+func (c *Connector) flushBatch(key string, batch *callbackBatch) {
+	c.batches.mu.Lock()
+	if c.batches.pending[key] != batch {
+		c.batches.mu.Unlock()
+		return
+	}
+	delete(c.batches.pending, key)
+	c.batches.mu.Unlock()
+
+	c.deliverBatch(batch)
+}
+
+// deliverBatch delivers the runs of the batch in a single http callback and completes each run with the outcome
+// acknowledged for it by the response. The runs a 2xx response does not acknowledge one by one succeed, and every
+// run fails with the request when it fails
+func (c *Connector) deliverBatch(batch *callbackBatch) {
+	dispatchedAt, start := clock.Now(), time.Now()
+	for _, wrapper := range batch.wrappers {
+		c.recordFiringLag(wrapper.Schedule, dispatchedAt, wrapper.IsReconciliation)
+	}
+
+	response, attempts, stop, err := c.retryPostBatch(batch)
+	latency := time.Since(start)
+
+	var acknowledgements map[string]store.BatchAcknowledgement
+	if err == nil && isSuccess(response) {
+		acknowledgements = store.ParseBatchAcknowledgements(peekBody(response, maxAssertedBodySize))
+	}
+
+	for _, wrapper := range batch.wrappers {
+		c.recordUsage(wrapper.Schedule, attempts)
+		run := c.completeBatchedRun(wrapper, response, err, acknowledgements, dispatchedAt)
+		if run.Status == store.Failure && stop.deadLettered() {
+			store.PublishEvent(store.ScheduleDeadLettered, run)
+		}
+		c.recordRunStats(run, attempts, dispatchedAt)
+		c.notifyStatusCallback(run, response, attempts, dispatchedAt, latency)
+		c.notifyFailure(run, wrapper.App)
+	}
+}
+
+// completeBatchedRun sets the status of a run of a batch from the outcome of the batch and its acknowledgement
+// Returns the completed run
+func (c *Connector) completeBatchedRun(wrapper store.ScheduleWrapper, response *http.Response, err error, acknowledgements map[string]store.BatchAcknowledgement, dispatchedAt time.Time) store.Schedule {
+	result := wrapper.Schedule
+	acknowledgement, acknowledged := acknowledgements[result.ScheduleId.String()]
+
+	switch {
+	case err != nil:
+		result.Status = store.Failure
+		result.ErrorMessage = trim(err.Error())
+	case !isSuccess(response):
+		result.Status = store.Failure
+		result.ErrorMessage = trim(response.Status)
+	case acknowledged && acknowledgement.Status == store.Failure:
+		result.Status = store.Failure
+		result.ErrorMessage = trim(acknowledgement.Error)
+		if result.ErrorMessage == "" {
+			result.ErrorMessage = "acknowledged as failed by the batch response"
+		}
+	default:
+		result.Status = store.Success
+		result.ErrorMessage = ""
+	}
+	result.ResponseSnippet = redactedSnippet(response, wrapper.App.Configuration.Redaction, c.Config.HttpConnector.ResponseSnippetSize)
+
+	if result.Status == store.Success {
+		c.recordHTTPCallback(result, constants.Success)
+		result.Logger().Infof("Batched callback success for schedule id %s", result.ScheduleId.String())
+	} else {
+		c.recordHTTPCallback(result, constants.Fail)
+		result.Logger().Errorf("Batched callback failed for schedule id %s with error %s", result.ScheduleId.String(), result.ErrorMessage)
+	}
+
+	return c.completeRun(result, wrapper.App, wrapper.IsReconciliation, dispatchedAt, statusCode(response))
+}
+
+// retryPostBatch attempts the http callback of the batch, retrying it as the callback of a single run is retried
+// Returns the last response along with the number of attempts made and why they stopped
+func (c *Connector) retryPostBatch(batch *callbackBatch) (response *http.Response, attempts int, stop retryStop, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Recovered in retryPostBatch from error %s with stacktrace %s", r, string(debug.Stack()))
+			err = fmt.Errorf("batched callback failed: %v", r)
+		}
+	}()
+
+	policy := batch.app.Configuration.RetryPolicy
+	for {
+		attempts++
+		attemptedAt := time.Now()
+
+		var req *http.Request
+		req, err = createBatchRequest(batch)
+		if err != nil {
+			c.logBatchAttempt(batch, attempts, attemptedAt, nil, err)
+			return nil, attempts, stopNotRetryable, err
+		}
+
+		var client *http.Client
+		if client, err = c.callbackClient(batch.app, batch.region); err != nil {
+			c.logBatchAttempt(batch, attempts, attemptedAt, nil, err)
+			return nil, attempts, stopNotRetryable, err
+		}
+
+		response, err = client.Do(req)
+		if err != nil {
+			logger.Errorf("Batched callback of %d runs of app %s failed during attempt: %d with error %s", len(batch.wrappers), batch.app.AppId, attempts, err.Error())
+		} else {
+			bufferBody(response, maxAssertedBodySize)
+			logger.Infof("Batched callback of %d runs of app %s received response %s", len(batch.wrappers), batch.app.AppId, response.Status)
+		}
+		c.logBatchAttempt(batch, attempts, attemptedAt, response, err)
+
+		if stop = stopAttempts(maxCallbackAttempts, attempts, policy, response, err); stop != "" {
+			return response, attempts, stop, err
+		}
+		for _, wrapper := range batch.wrappers {
+			c.recordHTTPCallback(wrapper.Schedule, constants.Retry)
+		}
+		time.Sleep(c.retryWait(policy, response, attempts))
+	}
+}
+
+// bufferBody reads up to limit bytes of the response body and closes it, the bytes read are left for the next reader
+func bufferBody(response *http.Response, limit int64) {
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, limit))
+	_ = response.Body.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+}
+
+// logBatchAttempt logs the attempt of a batched callback for each of its runs
+func (c *Connector) logBatchAttempt(batch *callbackBatch, attempt int, attemptedAt time.Time, response *http.Response, err error) {
+	for _, wrapper := range batch.wrappers {
+		c.logCallbackAttempt(wrapper.Schedule, batch.app, attempt, attemptedAt, response, err)
+	}
+}
+
+// createBatchRequest creates the http request carrying the payloads of the runs of the batch to their shared target.
+// The request has the headers of a single run, less the ids of the schedule which are in the body
+func createBatchRequest(batch *callbackBatch) (*http.Request, error) {
+	first := batch.wrappers[0].Schedule
+	details := first.Callback.(*store.HttpCallback).Details
+
+	items := make([]store.BatchedCallback, 0, len(batch.wrappers))
+	for _, wrapper := range batch.wrappers {
+		item := store.BatchedCallback{
+			ScheduleId: wrapper.Schedule.ScheduleId.String(),
+			Payload:    wrapper.Schedule.Payload,
+		}
+		if !util.IsZeroUUID(wrapper.Schedule.ParentScheduleId) {
+			item.ParentScheduleId = wrapper.Schedule.ParentScheduleId.String()
+		}
+		items = append(items, item)
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	body, contentEncoding, err := encodeBody(body, batch.app)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedUrl, err := store.ResolveSecrets(details.Url)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(details.Method, resolvedUrl, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	setRequestHeaders(req, first, batch.app)
+	req.Header.Del(constants.ScheduleIdHeader)
+	req.Header.Del(constants.ParentScheduleId)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	if _, err = resolveHeaderSecrets(req); err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("Batched request fired for %d runs of app %s ==> %s %s", len(items), batch.app.AppId, req.Method, details.Url))
+	return req, nil
+}
@@ -20,8 +20,7 @@
 package connectors
 
 import (
-	"fmt"
-	"github.com/golang/glog"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 )
 
@@ -29,7 +28,7 @@ import (
 // the specified action on the schedule.
 func (c *Connector) bulkAction(buf chan store.BulkActionTask) {
 	for q := range buf {
-		glog.Infof("consumed %+v", q)
+		logger.Infof("consumed %+v", q)
 		_ = c.ScheduleDao.BulkAction(q.App, q.PartitionId, q.ScheduleTimeGroup, []store.Status{q.Status}, q.ActionType)
 	}
 }
@@ -39,7 +38,7 @@ func (c *Connector) bulkAction(buf chan store.BulkActionTask) {
 func (c *Connector) createBulkActionPool(buf chan store.BulkActionTask) {
 	noOfWorkers := c.Config.BulkActionConfig.Routines
 	for i := 0; i < noOfWorkers; i++ {
-		fmt.Printf("\nInitializing worker for BulkAction connector %d", i)
+		logger.Debugf("Initializing worker for BulkAction connector %d", i)
 		go c.bulkAction(buf)
 	}
 }
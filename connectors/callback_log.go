@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/myntra/goscheduler/store"
+)
+
+// maxRedactedBodySize bounds the part of a response body read to be redacted. The body is redacted before it is cut
+// to the snippet size, as a JSON body cut in half can no longer be searched for the redacted fields
+const maxRedactedBodySize = 64 << 10
+
+// redactedSnippet returns up to size bytes of the response body with the redaction of the app applied
+func redactedSnippet(response *http.Response, redaction *store.Redaction, size int) string {
+	if redaction == nil {
+		return responseSnippet(response, size)
+	}
+	if response == nil || size <= 0 {
+		return ""
+	}
+	return store.Snippet(redaction.Apply(string(peekBody(response, maxRedactedBodySize))), size)
+}
+
+// logCallbackAttempt persists the execution log of an attempt of the http callback of a schedule, with its bodies
+// redacted by the rules of the app, if the execution logs are enabled in the configuration
+func (c *Connector) logCallbackAttempt(input store.Schedule, app store.App, attempt int, attemptedAt time.Time, response *http.Response, err error) {
+	logConfig := c.Config.CallbackLogConfig
+	if !logConfig.Enabled {
+		return
+	}
+
+	redaction := app.Configuration.Redaction
+	details := input.Callback.(*store.HttpCallback).Details
+	callbackAttempt := store.CallbackAttempt{
+		ScheduleId:     input.ScheduleId,
+		AppId:          input.AppId,
+		Attempt:        attempt,
+		AttemptedAt:    attemptedAt.UnixNano() / int64(time.Millisecond),
+		Method:         details.Method,
+		Url:            redaction.Apply(details.Url),
+		RequestSnippet: store.Snippet(redaction.Apply(input.Payload), logConfig.SnippetSize),
+		LatencyMillis:  time.Since(attemptedAt).Milliseconds(),
+	}
+	if response != nil {
+		callbackAttempt.ResponseStatus = response.StatusCode
+		callbackAttempt.ResponseSnippet = redactedSnippet(response, redaction, logConfig.SnippetSize)
+	}
+	if err != nil {
+		callbackAttempt.Error = redaction.Apply(trim(err.Error()))
+	}
+
+	ttl := input.GetTTL(app, c.Config.AppLevelConfiguration.FiredScheduleRetentionPeriod)
+	if err := c.ScheduleDao.CreateCallbackAttempt(callbackAttempt, ttl); err != nil {
+		input.Logger().Errorf("Callback attempt log creation failed for schedule id %s with error %s", input.ScheduleId.String(), err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// callbackCanary fetches the canary of the callback of a recurring schedule.
+// A schedule without one gets an empty canary, which never applies.
+func (c *Connector) callbackCanary(parent store.Schedule) *store.CallbackCanary {
+	canary, err := c.ScheduleDao.GetCallbackCanary(parent.ScheduleId)
+	switch {
+	case err == gocql.ErrNotFound:
+		return &store.CallbackCanary{}
+	case err != nil:
+		logger.Errorf("Fetching the callback canary of cron %s failed with error %s", parent.ScheduleId, err.Error())
+		return &store.CallbackCanary{}
+	default:
+		return &canary
+	}
+}
+
+// fireShadow fires the copy of a mirrored canary run to the updated callback.
+// Only the outcome is recorded, the status of the run is the one of the previous callback
+func (c *Connector) fireShadow(wrapper store.ScheduleWrapper) {
+	dispatchedAt, start := clock.Now(), time.Now()
+	ctx, cancel := executionContext(wrapper.Schedule)
+	defer cancel()
+	response, _, _, err := c.retryPost(ctx, wrapper.Schedule, wrapper.App)
+	c.recordCanaryResult(wrapper, response, err, dispatchedAt, time.Since(start))
+}
+
+// recordCanaryResult persists the outcome of a run of a callback canary for the comparison of its callbacks
+func (c *Connector) recordCanaryResult(wrapper store.ScheduleWrapper, response *http.Response, err error, dispatchedAt time.Time, latency time.Duration) {
+	run := wrapper.Schedule
+	result := store.CanaryResult{
+		ScheduleId:    run.ParentScheduleId,
+		RunId:         run.ScheduleId,
+		ScheduleTime:  run.ScheduleTime,
+		Target:        wrapper.Canary,
+		Status:        store.Success,
+		LatencyMillis: latency.Milliseconds(),
+		FiredAt:       dispatchedAt.UnixNano() / int64(time.Millisecond),
+	}
+	if response != nil {
+		result.ResponseStatus = response.StatusCode
+	}
+
+	switch {
+	case err != nil:
+		result.Status = store.Failure
+		result.ErrorMessage = trim(err.Error())
+	case !isSuccess(response):
+		result.Status = store.Failure
+		result.ErrorMessage = trim(response.Status)
+	}
+
+	ttl := run.GetTTL(wrapper.App, c.Config.AppLevelConfiguration.FiredScheduleRetentionPeriod)
+	if err := c.ScheduleDao.CreateCanaryResult(result, ttl); err != nil {
+		run.Logger().Errorf("Canary result creation failed for schedule id %s with error %s", run.ScheduleId.String(), err.Error())
+	}
+}
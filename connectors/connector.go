@@ -22,7 +22,11 @@ package connectors
 import (
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/events"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/monitoring"
+	"github.com/myntra/goscheduler/store"
 	"net/http"
 	"time"
 )
@@ -33,20 +37,47 @@ type Connector struct {
 	ClusterDao  dao.ClusterDao
 	ScheduleDao dao.ScheduleDao
 	HttpClient  *http.Client
-	Monitor     monitoring.Monitor
+	// appClients holds the http clients of the apps with a callback transport of their own
+	appClients appClients
+	// batches holds the batches of runs waiting to be delivered in a single http callback
+	batches callbackBatches
+	// StatusCallbackClient posts run outcomes to the status callback urls of schedules
+	StatusCallbackClient *http.Client
+	// NotificationClient posts the failure notifications of recurring schedules to their webhooks
+	NotificationClient *http.Client
+	// Publisher publishes the lifecycle events of schedules
+	Publisher events.Publisher
+	Monitor   monitoring.Monitor
 }
 
 // NewConnector creates a new Connector instance with the given configuration, DAOs, and monitoring.
 func NewConnector(config *conf.Configuration, clusterDao dao.ClusterDao, scheduleDAO dao.ScheduleDao, monitor monitoring.Monitor) *Connector {
 	client := &http.Client{
-		Timeout: config.HttpConnector.TimeoutMillis * time.Millisecond,
+		Transport: faults.Default().Transport(store.Egress().Transport(http.DefaultTransport.(*http.Transport))),
+		Timeout:   config.HttpConnector.TimeoutMillis * time.Millisecond,
+	}
+	statusCallbackClient := &http.Client{
+		Transport: store.Egress().Transport(http.DefaultTransport.(*http.Transport)),
+		Timeout:   config.StatusCallbackConfig.TimeoutMillis * time.Millisecond,
+	}
+	notificationClient := &http.Client{
+		Transport: store.Egress().Transport(http.DefaultTransport.(*http.Transport)),
+		Timeout:   config.NotificationConfig.TimeoutMillis * time.Millisecond,
+	}
+	publisher, err := events.NewPublisher(config.EventPublisherConfig)
+	if err != nil {
+		logger.Errorf("Event publisher creation failed with error %s, lifecycle events will not be published", err.Error())
+		publisher = events.NoopPublisher{}
 	}
 	return &Connector{
-		Config:      config,
-		ClusterDao:  clusterDao,
-		ScheduleDao: scheduleDAO,
-		HttpClient:  client,
-		Monitor:     monitor,
+		Config:               config,
+		ClusterDao:           clusterDao,
+		ScheduleDao:          scheduleDAO,
+		HttpClient:           client,
+		StatusCallbackClient: statusCallbackClient,
+		NotificationClient:   notificationClient,
+		Publisher:            publisher,
+		Monitor:              monitor,
 	}
 }
 
@@ -54,9 +85,16 @@ func NewConnector(config *conf.Configuration, clusterDao dao.ClusterDao, schedul
 func (c *Connector) InitConnectors(callbackWorkers bool) {
 	if callbackWorkers {
 		c.initHttpWorkers()
+		c.initStatusCallbackWorkers()
+		c.initPluginWorkers()
 	}
 	c.initAggregateWorkers()
 	c.initStatusUpdatePool()
 	c.initCronRetriever()
 	c.initBulkActionWorkers()
+	c.initEventPublisherWorkers()
+	c.initFailureNotificationWorkers()
+	c.initUsageFlusher()
+	c.initRunStatsFlusher()
+	c.initPullWorkers(callbackWorkers)
 }
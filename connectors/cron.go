@@ -20,15 +20,15 @@
 package connectors
 
 import (
+	"context"
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
-	"github.com/myntra/goscheduler/cron"
+	"github.com/myntra/goscheduler/logger"
 	s "github.com/myntra/goscheduler/store"
 	"time"
 )
 
 // Creates one time schedules for a recurring schedule.
-// Listens for a create task event on the channel. And creates the schedule if the cron expression matches
+// Listens for a create task event on the channel. And creates the schedule if the recurrence matches
 // any time within the duration window.
 // If the time doesn't match or if a schedule already exists at time then the creation will be skipped.
 // The method records any errors occurred during execution and recovers.
@@ -40,19 +40,19 @@ func (c *Connector) createSchedules(tasks <-chan s.CreateScheduleTask) {
 		parent := task.Cron
 
 		if !parent.IsRecurring() {
-			glog.Errorf("Schedule %v is not a cron", parent)
+			logger.Errorf("Schedule %v is not a cron", parent)
 			continue
 		}
 
-		var _cron cron.Expression
-		if _cron, errs = cron.Parse(parent.CronExpression); len(errs) != 0 {
-			glog.Errorf("Parsing cron expression for schedule %s failed with errors %v", parent.ScheduleId, errs)
+		var recurrence s.Recurrence
+		if recurrence, errs = parent.GetRecurrence(); len(errs) != 0 {
+			logger.Errorf("Parsing recurrence for schedule %s failed with errors %v", parent.ScheduleId, errs)
 			continue
 		}
 
 		var app s.App
 		if app, err = c.ClusterDao.GetApp(parent.AppId); err != nil || !app.Active {
-			glog.Errorf("App %s is not active", parent.AppId)
+			logger.Errorf("App %s is not active", parent.AppId)
 			continue
 		}
 
@@ -63,29 +63,49 @@ func (c *Connector) createSchedules(tasks <-chan s.CreateScheduleTask) {
 				existing[time.Unix(run.ScheduleGroup, 0)] = true
 			}
 		default:
-			glog.Errorf("Error getting future runs for %s", parent.ScheduleId)
+			logger.Errorf("Error getting future runs for %s", parent.ScheduleId)
 			continue
 		}
 
-		for _time := task.From.Add(task.Duration); _time.After(task.From); _time = _time.Add(time.Minute * -1) {
+		// Runs are created earliest first so that a callback canary fires the next runs of the schedule
+		var canary *s.CallbackCanary
+		fired := 0
+		for _time := task.From.Add(time.Minute); !_time.After(task.From.Add(task.Duration)); _time = _time.Add(time.Minute) {
 
-			if _, found := existing[_time]; !found && _cron.Match(_time) {
+			if _, found := existing[_time]; !found && recurrence.Match(_time) {
 
 				clone := parent.CloneAsOneTime(_time)
 				clone.SetFields(app)
+
+				if canary == nil {
+					canary = c.callbackCanary(parent)
+					fired = canary.Fired
+				}
+				next := *canary
+				if next.Applies(parent) {
+					next.Wrap(&clone, next.Next())
+				}
+
 				if errs := clone.ValidateSchedule(app, c.Config.AppLevelConfiguration); len(errs) != 0 {
-					glog.Errorf(
+					logger.Errorf(
 						"Validation failed for one time schedule %v of cron %s with errors %v",
 						clone, parent.ScheduleId, errs)
 					continue
 				}
 
-				if clone, err = c.ScheduleDao.CreateRun(clone, app); err != nil {
-					glog.Errorf(
+				if clone, err = c.ScheduleDao.CreateRun(context.Background(), clone, app); err != nil {
+					logger.Errorf(
 						"Creation failed for one time schedule %v of cron %s with errors %s",
 						clone, parent.ScheduleId, err.Error())
 					continue
 				}
+				*canary = next
+			}
+		}
+
+		if canary != nil && canary.Fired != fired {
+			if err = c.ScheduleDao.UpdateCallbackCanary(*canary); err != nil {
+				logger.Errorf("Updating the callback canary of cron %s failed with error %s", parent.ScheduleId, err.Error())
 			}
 		}
 	}
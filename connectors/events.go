@@ -0,0 +1,59 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+func (c *Connector) recordEventPublish(eventType store.EventType, status string) {
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.EventPublishCount, map[string]string{"type": string(eventType), "status": status}, 1)
+	}
+}
+
+// publishEvents publishes the lifecycle events received on the provided channel
+func (c *Connector) publishEvents(buf <-chan store.Event) {
+	for event := range buf {
+		if err := c.Publisher.Publish(event); err != nil {
+			c.recordEventPublish(event.Type, constants.Fail)
+			logger.Errorf("Publishing event %s of type %s failed for schedule id %s with error %s", event.EventId, event.Type, event.ScheduleId, err.Error())
+		} else {
+			c.recordEventPublish(event.Type, constants.Success)
+		}
+	}
+}
+
+func (c *Connector) createEventPublisherPool(buf chan store.Event) {
+	noOfWorkers := c.Config.EventPublisherConfig.Routines
+	for i := 0; i < noOfWorkers; i++ {
+		logger.Debugf("Initializing worker for event publisher %d", i)
+		go c.publishEvents(buf)
+	}
+}
+
+func (c *Connector) initEventPublisherWorkers() {
+	store.SetEventDropRecorder(func(eventType store.EventType) {
+		c.recordEventPublish(eventType, constants.Dropped)
+	})
+	go c.createEventPublisherPool(store.EventTaskQueue)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// notifyFailure queues a failed run of a recurring schedule to be checked against the failure notification of its app.
+// Failure notifications are best effort, the run is dropped if the queue is full so that callbacks are never delayed.
+func (c *Connector) notifyFailure(run store.Schedule, app store.App) {
+	if app.Configuration.FailureNotification == nil || util.IsZeroUUID(run.ParentScheduleId) || run.Status == store.Success {
+		return
+	}
+
+	select {
+	case store.FailureNotificationTaskQueue <- store.FailureNotificationTask{Run: run, App: app}:
+	default:
+		c.recordFailureNotification(app.AppId, constants.Dropped)
+		run.Logger().Errorf("Failure notification queue full, dropping failed run of schedule id %s", run.ParentScheduleId.String())
+	}
+}
+
+// checkFailure notifies the failure notification of the app, and pauses the schedule if asked to, when the failed
+// run completes a streak of failures of its schedule
+// Returns true if the run completes a streak
+func (c *Connector) checkFailure(task store.FailureNotificationTask) (bool, error) {
+	notification := task.App.Configuration.FailureNotification
+	runs, _, err := c.ScheduleDao.GetScheduleRuns(task.Run.ParentScheduleId, notification.Runs(), "past", nil)
+	if err != nil {
+		return false, err
+	}
+	if !notification.Notifies(task.Run, runs) {
+		return false, nil
+	}
+
+	paused := false
+	if notification.AutoPause {
+		if paused, err = c.pauseFailingSchedule(task.Run); err != nil {
+			logger.Errorf("Pausing schedule id %s after %d failed runs failed with error %s", task.Run.ParentScheduleId.String(), notification.ConsecutiveFailures, err.Error())
+		}
+	}
+
+	alert := store.NewFailureAlert(task.Run, notification.ConsecutiveFailures, paused, time.Now())
+	switch notification.Channel {
+	case store.NotifyWebhook:
+		return true, c.postFailureAlert(notification.Url, alert)
+	case store.NotifyEmail:
+		return true, c.mailFailureAlert(notification.Emails, alert)
+	default:
+		return true, fmt.Errorf("invalid failure notification channel: %s", notification.Channel)
+	}
+}
+
+// pauseFailingSchedule pauses the recurring schedule of the run, unless it is no longer scheduled
+// Returns true if the schedule was paused
+func (c *Connector) pauseFailingSchedule(run store.Schedule) (bool, error) {
+	schedule, err := c.ScheduleDao.GetSchedule(run.ParentScheduleId)
+	if err != nil {
+		return false, err
+	}
+	if schedule.Status != store.Scheduled {
+		return false, nil
+	}
+
+	schedule.PausedAt = time.Now().Unix()
+	updatedSchedule, err := c.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Paused)
+	if err != nil {
+		return false, err
+	}
+
+	store.PublishEvent(store.SchedulePaused, updatedSchedule)
+	return true, nil
+}
+
+// postFailureAlert posts the alert to the url of the webhook channel
+// Returns a non nil error if the request fails or a non 2xx response is received
+func (c *Connector) postFailureAlert(url string, alert store.FailureAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(constants.ContentType, constants.ApplicationJson)
+	req.Header.Set(constants.ScheduleIdHeader, alert.ScheduleId)
+
+	response, err := c.NotificationClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if !isSuccess(response) {
+		return fmt.Errorf("failure notification webhook responded with %s", response.Status)
+	}
+	return nil
+}
+
+// mailFailureAlert mails the alert to the recipients of the email channel through the configured smtp server
+func (c *Connector) mailFailureAlert(emails []string, alert store.FailureAlert) error {
+	config := c.Config.NotificationConfig
+	if config.SmtpAddress == "" {
+		return errors.New("no smtp server configured for the failure notification emails")
+	}
+
+	var auth smtp.Auth
+	if config.SmtpUsername != "" {
+		host := strings.Split(config.SmtpAddress, ":")[0]
+		auth = smtp.PlainAuth("", config.SmtpUsername, config.SmtpPassword, host)
+	}
+	return smtp.SendMail(config.SmtpAddress, auth, config.From, emails, failureAlertMail(config.From, emails, alert))
+}
+
+// failureAlertMail formats the alert as a plain text email
+func failureAlertMail(from string, emails []string, alert store.FailureAlert) []byte {
+	var mail bytes.Buffer
+	fmt.Fprintf(&mail, "From: %s\r\n", from)
+	fmt.Fprintf(&mail, "To: %s\r\n", strings.Join(emails, ", "))
+	fmt.Fprintf(&mail, "Subject: Schedule %s of app %s failed %d times in a row\r\n", alert.ScheduleId, alert.AppId, alert.ConsecutiveFailures)
+	fmt.Fprintf(&mail, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&mail, "The last %d runs of schedule %s of app %s failed.\r\n\r\n", alert.ConsecutiveFailures, alert.ScheduleId, alert.AppId)
+	fmt.Fprintf(&mail, "Last run: %s at %s\r\n", alert.LastRunId, time.Unix(alert.LastRunTime, 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(&mail, "Status: %s\r\n", alert.LastStatus)
+	if alert.ErrorMessage != "" {
+		fmt.Fprintf(&mail, "Error: %s\r\n", alert.ErrorMessage)
+	}
+	if alert.Paused {
+		fmt.Fprintf(&mail, "\r\nThe schedule has been paused, resume it once its callback is fixed.\r\n")
+	}
+	return mail.Bytes()
+}
+
+func (c *Connector) recordFailureNotification(appId string, status string) {
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.FailureNotificationCount, map[string]string{"appId": appId, "status": status}, 1)
+	}
+}
+
+// listenFailures checks the failed runs received on the provided channel
+func (c *Connector) listenFailures(buf <-chan store.FailureNotificationTask) {
+	for task := range buf {
+		if notified, err := c.checkFailure(task); err != nil {
+			c.recordFailureNotification(task.App.AppId, constants.Fail)
+			logger.Errorf("Failure notification failed for schedule id %s with error %s", task.Run.ParentScheduleId.String(), err.Error())
+		} else if notified {
+			c.recordFailureNotification(task.App.AppId, constants.Success)
+		}
+	}
+}
+
+func (c *Connector) createFailureNotificationPool(buf chan store.FailureNotificationTask) {
+	noOfWorkers := c.Config.NotificationConfig.Routines
+	for i := 0; i < noOfWorkers; i++ {
+		logger.Debugf("Initializing worker for failure notifications %d", i)
+		go c.listenFailures(buf)
+	}
+}
+
+func (c *Connector) initFailureNotificationWorkers() {
+	go c.createFailureNotificationPool(store.FailureNotificationTaskQueue)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// scheduleFollowUp creates a one time schedule like the run after the delay asked for by its response, when the
+// run is a successful one time schedule whose callback reschedules from its response. Runs of recurring schedules
+// follow their recurrence and are never rescheduled.
+func (c *Connector) scheduleFollowUp(run store.Schedule, app store.App, response *http.Response) {
+	callback, ok := run.Callback.(*store.HttpCallback)
+	if !ok || !callback.Details.RescheduleFromResponse || run.Status != store.Success || response == nil {
+		return
+	}
+	if !util.IsZeroUUID(run.ParentScheduleId) {
+		return
+	}
+
+	delay, ok := store.ParseReRunAfter(peekBody(response, maxAssertedBodySize))
+	if !ok {
+		return
+	}
+
+	followUp := run.CloneAsOneTime(clock.Now().Add(delay))
+	followUp.ParentScheduleId = gocql.UUID{}
+	followUp.PayloadEncoding = app.Configuration.PayloadCompression
+	followUp.SetFields(app)
+
+	if errs := followUp.ValidateSchedule(app, c.Config.AppLevelConfiguration); len(errs) != 0 {
+		run.Logger().Errorf("Follow-up of schedule %s after %s is invalid: %v", run.ScheduleId.String(), delay, errs)
+		c.recordFollowUp(run, constants.Fail)
+		return
+	}
+
+	followUp, err := c.ScheduleDao.CreateSchedule(context.Background(), followUp, app)
+	if err != nil {
+		run.Logger().Errorf("Follow-up of schedule %s after %s failed with error %s", run.ScheduleId.String(), delay, err.Error())
+		c.recordFollowUp(run, constants.Fail)
+		return
+	}
+
+	run.Logger().Infof("Created follow-up schedule %s of schedule %s at %d", followUp.ScheduleId.String(), run.ScheduleId.String(), followUp.ScheduleTime)
+	c.recordFollowUp(run, constants.Success)
+	store.PublishEvent(store.ScheduleCreated, followUp)
+}
+
+func (c *Connector) recordFollowUp(run store.Schedule, status string) {
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.FollowUpScheduleCount, map[string]string{
+			"appId":  run.AppId,
+			"status": status,
+		}, 1)
+	}
+}
@@ -21,16 +21,24 @@ package connectors
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"github.com/gocql/gocql"
-	"github.com/golang/glog"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/monitoring"
+	"github.com/myntra/goscheduler/sla"
 	"github.com/myntra/goscheduler/store"
 	"github.com/myntra/goscheduler/util"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -43,15 +51,79 @@ func trim(message string) string {
 	}
 }
 
-// shouldRetry checks if the request should be retried based on maxAttempts, attempts, and response
-func shouldRetry(maxAttempts int, attempts int, response *http.Response) bool {
+// maxCallbackAttempts is the number of times a failing http callback is attempted for a run
+const maxCallbackAttempts = 3
+
+// retryStop tells why the attempts of a callback stopped, empty while they go on
+type retryStop string
+
+const (
+	stopSucceeded    retryStop = "succeeded"     // the attempt succeeded
+	stopExhausted    retryStop = "exhausted"     // the attempts of the failing callback reached the maximum
+	stopNotRetryable retryStop = "not retryable" // the failure is not retried by the retry policy, or fails on every attempt
+	stopOverrun      retryStop = "overrun"       // the callback overran its deadline
+	stopReplaced     retryStop = "replaced"      // the run was replaced by a newer run of its schedule
+)
+
+// deadLettered tells whether a failed run whose attempts stopped is dead-lettered: its failure is final unless the run
+// was replaced by a newer one
+func (s retryStop) deadLettered() bool {
+	return s != stopReplaced
+}
+
+// stopAttempts tells why the attempts of a callback stop after the given attempts, based on maxAttempts, the retry
+// policy of the app, the response and the error of the attempt, which includes a failed response assertion.
+// Returns an empty stop when the callback is retried
+func stopAttempts(maxAttempts int, attempts int, policy *store.RetryPolicy, response *http.Response, err error) retryStop {
+	if err == nil && isSuccess(response) {
+		return stopSucceeded
+	}
+	// a destination outside the egress policy is denied again on every attempt
+	if errors.Is(err, store.ErrEgressDenied) || !policy.Retryable(statusCode(response), err) {
+		return stopNotRetryable
+	}
 	if attempts >= maxAttempts {
-		return false
+		return stopExhausted
 	}
-	if isSuccess(response) {
-		return false
+	return ""
+}
+
+// cancelledStop tells why the attempts of a callback cancelled with the error of cancelled stopped
+func cancelledStop(err error) retryStop {
+	if errors.Is(err, store.ErrReplaced) {
+		return stopReplaced
 	}
-	return true
+	return stopOverrun
+}
+
+// retryWait returns the wait before the retry following the given attempts: the backoff of the retry policy, or the
+// wait asked for by the Retry-After header of a 429 or 503 response when it is longer, up to MaxRetryAfterMillis
+func (c *Connector) retryWait(policy *store.RetryPolicy, response *http.Response, attempts int) time.Duration {
+	wait := policy.Backoff(statusCode(response), attempts)
+
+	maxRetryAfter := time.Duration(c.Config.HttpConnector.MaxRetryAfterMillis) * time.Millisecond
+	if maxRetryAfter <= 0 || response == nil ||
+		(response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusServiceUnavailable) {
+		return wait
+	}
+
+	if retryAfter, ok := store.ParseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+		if retryAfter > maxRetryAfter {
+			retryAfter = maxRetryAfter
+		}
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+	}
+	return wait
+}
+
+// statusCode returns the status code of the response, 0 without a response
+func statusCode(response *http.Response) int {
+	if response == nil {
+		return 0
+	}
+	return response.StatusCode
 }
 
 // isSuccess checks if the response is considered successful
@@ -60,34 +132,141 @@ func isSuccess(response *http.Response) bool {
 		response.StatusCode <= constants.HttpResponseSuccessStatusCodeHigherBound)
 }
 
+// maxAssertedBodySize bounds the part of a response body read for its assertion
+const maxAssertedBodySize = 1 << 20
+
+// assertResponse checks the body of a 2xx response against the assertion of the callback, if it has one
+func assertResponse(input store.Schedule, response *http.Response) error {
+	assertion := input.Callback.(*store.HttpCallback).Details.Assertion
+	if assertion == nil || !isSuccess(response) {
+		return nil
+	}
+
+	if err := assertion.Check(peekBody(response, maxAssertedBodySize)); err != nil {
+		return fmt.Errorf("%w: %s", store.ErrAssertionFailed, err.Error())
+	}
+	return nil
+}
+
+// responseSnippet returns up to size bytes of the response body
+func responseSnippet(response *http.Response, size int) string {
+	if response == nil || size <= 0 {
+		return ""
+	}
+	return strings.ToValidUTF8(string(peekBody(response, int64(size))), "")
+}
+
+// peekBody reads up to limit bytes of the response body and puts them back for the next reader
+func peekBody(response *http.Response, limit int64) []byte {
+	if response.Body == nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, limit))
+	if err != nil {
+		return nil
+	}
+	response.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), response.Body))
+	return body
+}
+
+// encodeBody compresses the body of a callback if the app delivers compressed callbacks.
+// Returns the body along with its content encoding, empty when it is left uncompressed
+func encodeBody(body []byte, app store.App) ([]byte, string, error) {
+	if !app.Configuration.CompressCallbacks || app.Configuration.PayloadCompression == "" {
+		return body, "", nil
+	}
+	codec, err := store.GetCodec(app.Configuration.PayloadCompression)
+	if err != nil {
+		return nil, "", err
+	}
+	if body, err = codec.Compress(body); err != nil {
+		return nil, "", err
+	}
+	return body, app.Configuration.PayloadCompression, nil
+}
+
 // createRequest creates a new HTTP request from a given input schedule
-func createRequest(input store.Schedule) (*http.Request, error) {
-	glog.Infof("Method: %s, URL: %s, Headers: %+v", input.Callback.(*store.HttpCallback).Details.Method, input.Callback.(*store.HttpCallback).Details.Url, input.Callback.(*store.HttpCallback).Details.Headers)
-	jsonStr := []byte(input.Payload)
-	req, err := http.NewRequest(input.Callback.(*store.HttpCallback).Details.Method, input.Callback.(*store.HttpCallback).Details.Url, bytes.NewBuffer(jsonStr))
+// The payload is compressed if the app delivers compressed callbacks, and the secrets referenced by the url and
+// the headers are resolved last so that they are never logged
+func createRequest(input store.Schedule, app store.App) (*http.Request, error) {
+	input.Logger().Infof("Method: %s, URL: %s, Headers: %+v", input.Callback.(*store.HttpCallback).Details.Method, input.Callback.(*store.HttpCallback).Details.Url, input.Callback.(*store.HttpCallback).Details.Headers)
+	jsonStr, contentEncoding, err := encodeBody([]byte(input.Payload), app)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUrl := input.Callback.(*store.HttpCallback).Details.Url
+	resolvedUrl, err := store.ResolveSecrets(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(input.Callback.(*store.HttpCallback).Details.Method, resolvedUrl, bytes.NewBuffer(jsonStr))
 	if err != nil {
 		return nil, err
 	}
 
-	setRequestHeaders(req, input)
-	handleRequestDump(req, input.ScheduleId)
+	setRequestHeaders(req, input, app)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+
+	resolvedHeaders, err := resolveHeaderSecrets(req)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedHeaders || resolvedUrl != rawUrl {
+		input.Logger().Infof("Request fired for schedule id: %s ==> %s %s, dump left out as the request carries secrets", input.ScheduleId, req.Method, rawUrl)
+	} else {
+		handleRequestDump(req, input, app)
+	}
 
 	return req, nil
 }
 
+// resolveHeaderSecrets replaces the secret references of the request headers with their secrets.
+// Returns whether any header referenced a secret
+func resolveHeaderSecrets(req *http.Request) (bool, error) {
+	resolved := false
+	for header, values := range req.Header {
+		for i, value := range values {
+			if !store.HasSecretRefs(value) {
+				continue
+			}
+			secret, err := store.ResolveSecrets(value)
+			if err != nil {
+				return false, fmt.Errorf("header %s: %w", header, err)
+			}
+			values[i] = secret
+			resolved = true
+		}
+	}
+	return resolved, nil
+}
+
 // handleRequestDump logs the request dump or error if it occurs
-func handleRequestDump(req *http.Request, scheduleID gocql.UUID) {
-	requestDump, err := httputil.DumpRequest(req, true)
+// The payload of an app with redaction rules is logged redacted in place of the body of the request
+func handleRequestDump(req *http.Request, input store.Schedule, app store.App) {
+	redaction := app.Configuration.Redaction
+	requestDump, err := httputil.DumpRequest(req, redaction == nil)
 	if err != nil {
-		glog.Error("Request dump failed with error for schedule id : " + scheduleID.String() + " ==> " + err.Error())
+		input.Logger().Errorf("Request dump failed with error for schedule id : %s ==> %s", input.ScheduleId, err.Error())
+	} else if redaction != nil {
+		input.Logger().Infof("Request fired for schedule id: %s ==> %s%s", input.ScheduleId, requestDump, redaction.Apply(input.Payload))
 	} else {
-		glog.Info("Request fired for schedule id: " + scheduleID.String() + " ==> " + string(requestDump))
+		input.Logger().Infof("Request fired for schedule id: %s ==> %s", input.ScheduleId, requestDump)
 	}
 }
 
 // setRequestHeaders sets the required headers for the request
-func setRequestHeaders(req *http.Request, input store.Schedule) {
+// The headers of the app are set first so that the ones of the schedule take precedence.
+func setRequestHeaders(req *http.Request, input store.Schedule, app store.App) {
 	req.Header.Set("Content-Type", "application/json")
+	if app.Configuration.CallbackHeaders != nil {
+		for header, value := range *app.Configuration.CallbackHeaders {
+			req.Header.Set(header, value)
+		}
+	}
 	for header, value := range input.Callback.(*store.HttpCallback).Details.Headers {
 		req.Header.Set(header, value)
 	}
@@ -97,35 +276,72 @@ func setRequestHeaders(req *http.Request, input store.Schedule) {
 
 	// ParentScheduleId needed for cron-schedule callbacks
 	if !util.IsZeroUUID(input.ParentScheduleId) {
-		glog.Infof("Adding ParentScheduleId: %s in http callback header for scheduleId: %s", input.ParentScheduleId.String(), input.ScheduleId.String())
+		input.Logger().Infof("Adding ParentScheduleId: %s in http callback header for scheduleId: %s", input.ParentScheduleId.String(), input.ScheduleId.String())
 		req.Header.Set(constants.ParentScheduleId, input.ParentScheduleId.String())
 	}
 
-	glog.Infof("http callback headers: %v for scheduleId: %s", req.Header, input.ScheduleId.String())
+	// every request of a callback is a new span of the trace of the schedule
+	if traceParent := store.TraceParent(input.TraceId); traceParent != "" {
+		req.Header.Set(constants.TraceParentHeader, traceParent)
+	}
+
+	input.Logger().Infof("http callback headers: %v for scheduleId: %s", req.Header, input.ScheduleId.String())
 }
 
 // handleResponseDump logs the response dump or error if it occurs, and logs the callback failure if an error exists
-func handleResponseDump(input store.Schedule, response *http.Response, attempts int, err error) {
+// The body of the response is redacted by the rules of the app
+func handleResponseDump(input store.Schedule, app store.App, response *http.Response, attempts int, err error) {
 	if err != nil {
-		glog.Errorf("Callback failed schedule id: %s during attempt: %d with error %s", input.ScheduleId.String(), attempts, err.Error())
+		input.Logger().Errorf("Callback failed schedule id: %s during attempt: %d with error %s", input.ScheduleId.String(), attempts, err.Error())
 	} else {
 		defer response.Body.Close()
 		body, er := httputil.DumpResponse(response, true)
 		if er != nil {
-			glog.Errorf("Response dump failed with error for schedule id: %s ==> %s", input.ScheduleId.String(), er.Error())
+			input.Logger().Errorf("Response dump failed with error for schedule id: %s ==> %s", input.ScheduleId.String(), er.Error())
 		} else {
-			glog.Infof("Response received for schedule id: %s ==> %s", input.ScheduleId.String(), string(body))
+			input.Logger().Infof("Response received for schedule id: %s ==> %s", input.ScheduleId.String(), redactDump(string(body), app.Configuration.Redaction))
 		}
 	}
 }
 
-func (c *Connector) recordHTTPCallback(appId string, partitionId int, status string) {
+// redactDump applies the redaction to the body of an http dump, which follows the first empty line
+func redactDump(dump string, redaction *store.Redaction) string {
+	if redaction == nil {
+		return dump
+	}
+	parts := strings.SplitN(dump, "\r\n\r\n", 2)
+	if len(parts) < 2 {
+		return dump
+	}
+	return parts[0] + "\r\n\r\n" + redaction.Apply(parts[1])
+}
+
+// callbackLabels returns the labels of the callback metrics of a schedule
+func callbackLabels(schedule store.Schedule) map[string]string {
+	callbackType := ""
+	if schedule.Callback != nil {
+		callbackType = schedule.GetCallBackType()
+	}
+	return map[string]string{
+		"appId":        schedule.AppId,
+		"callbackType": callbackType,
+		"partitionId":  strconv.Itoa(schedule.PartitionId),
+		"priority":     string(schedule.GetPriority()),
+	}
+}
+
+func (c *Connector) recordHTTPCallback(schedule store.Schedule, status string) {
+	if status != constants.Retry {
+		sla.Anomalies().RecordCallback(schedule.AppId, status == constants.Fail, time.Now())
+	}
 	if c.Monitor != nil {
-		c.Monitor.IncCounter(constants.CallbackStatusCount, map[string]string{"appId": appId, "partitionId": strconv.Itoa(partitionId), "status": status}, 1)
+		labels := callbackLabels(schedule)
+		labels["status"] = status
+		c.Monitor.IncCounter(constants.CallbackStatusCount, labels, 1)
 	}
 }
 
-func (c *Connector) recordTiming(do func() (*http.Response, error), appId string, partitionId int) (*http.Response, error) {
+func (c *Connector) recordTiming(do func() (*http.Response, error), schedule store.Schedule) (*http.Response, error) {
 	startTime := time.Now()
 
 	response, err := do()
@@ -133,104 +349,251 @@ func (c *Connector) recordTiming(do func() (*http.Response, error), appId string
 	// Record timing
 	if c.Monitor != nil {
 		duration := time.Since(startTime)
-		c.Monitor.RecordTiming(constants.CallbackDuration, map[string]string{"appId": appId, "partitionId": strconv.Itoa(partitionId)}, duration)
+		monitoring.RecordTimingWithExemplar(c.Monitor, constants.CallbackDuration, callbackLabels(schedule), duration, monitoring.TraceExemplar(schedule.TraceId))
 	}
 
 	return response, err
 }
 
+// recordQueueWait records the time a schedule waited for a callback worker
+func (c *Connector) recordQueueWait(wrapper store.ScheduleWrapper) {
+	if c.Monitor != nil && !wrapper.EnqueuedAt.IsZero() {
+		c.Monitor.RecordTiming(constants.CallbackQueueWait, map[string]string{
+			"appId":    wrapper.Schedule.AppId,
+			"priority": string(wrapper.Schedule.GetPriority()),
+		}, time.Since(wrapper.EnqueuedAt))
+	}
+}
+
+// recordFiringLag records how late the callback of the schedule fired for the SLA of its app.
+// Reconciled runs are late on purpose and are left out.
+func (c *Connector) recordFiringLag(schedule store.Schedule, firedAt time.Time, isReconciliation bool) {
+	if isReconciliation {
+		return
+	}
+
+	scheduledAt := time.Unix(schedule.ScheduleTime, 0)
+	sla.Default().RecordFire(schedule.AppId, schedule.PartitionId, scheduledAt, firedAt)
+	if c.Monitor != nil {
+		monitoring.RecordTimingWithExemplar(c.Monitor, constants.FiringLag, map[string]string{"appId": schedule.AppId}, firedAt.Sub(scheduledAt), monitoring.TraceExemplar(schedule.TraceId))
+	}
+}
+
 // processSchedule processes a single ScheduleWrapper, executing the retryPost function and handling the callback result
 func (c *Connector) processSchedule(scheduleWrapper store.ScheduleWrapper) {
 	result := scheduleWrapper.Schedule
 	app := scheduleWrapper.App
 	isReconciliation := scheduleWrapper.IsReconciliation
 
-	glog.Infof("Callback fired for schedule with schedule id %s and schedule entity %+v", result.ScheduleId.String(), result)
-	response, err := c.recordTiming(func() (response *http.Response, err error) {
-		return c.retryPost(result, app)
-	}, result.AppId, result.PartitionId)
+	if scheduleWrapper.Shadow {
+		c.fireShadow(scheduleWrapper)
+		return
+	}
+	if isBatched(scheduleWrapper) {
+		c.batchCallback(scheduleWrapper)
+		return
+	}
 
-	c.handleCallbackResult(response, err, result, app, isReconciliation)
+	result.Logger().Infof("Callback fired for schedule with schedule id %s and schedule entity %+v", result.ScheduleId.String(), result)
+	dispatchedAt, start := clock.Now(), time.Now()
+	c.recordFiringLag(result, dispatchedAt, isReconciliation)
+	ctx, cancel := executionContext(result)
+	defer cancel()
+	store.Running().Executing(result, cancel)
+	attempts, stop := 0, retryStop("")
+	response, err := c.recordTiming(func() (response *http.Response, err error) {
+		response, attempts, stop, err = c.retryPost(ctx, result, app)
+		return response, err
+	}, result)
+	latency := time.Since(start)
+	c.recordUsage(result, attempts)
+
+	if scheduleWrapper.Canary != "" {
+		c.recordCanaryResult(scheduleWrapper, response, err, dispatchedAt, latency)
+	}
+	run := c.handleCallbackResult(response, err, result, app, isReconciliation, dispatchedAt)
+	c.checkUnreachable(run, err)
+	if run.Status == store.Failure && stop.deadLettered() {
+		store.PublishEvent(store.ScheduleDeadLettered, run)
+	}
+	c.recordRunStats(run, attempts, dispatchedAt)
+	c.notifyStatusCallback(run, response, attempts, dispatchedAt, latency)
+	c.notifyFailure(run, app)
+	c.scheduleFollowUp(run, app, response)
 }
 
 // handleCallbackResult processes the result of a callback, updating the schedule status and sending the updated ScheduleWrapper to the AggregationTaskQueue
-func (c *Connector) handleCallbackResult(response *http.Response, err error, result store.Schedule, app store.App, isReconciliation bool) {
+// Returns the schedule with the updated status
+func (c *Connector) handleCallbackResult(response *http.Response, err error, result store.Schedule, app store.App, isReconciliation bool, dispatchedAt time.Time) store.Schedule {
 	if err != nil {
-		c.recordHTTPCallback(result.AppId, result.PartitionId, constants.Fail)
-		glog.Errorf("Callback failed for schedule id %s with error %s", result.ScheduleId.String(), err.Error())
+		c.recordHTTPCallback(result, constants.Fail)
+		result.Logger().Errorf("Callback failed for schedule id %s with error %s", result.ScheduleId.String(), err.Error())
 
 		result.Status = store.Failure
+		if errors.Is(err, store.ErrOverrun) {
+			result.Status = store.Overrun
+			c.recordOverrun(result)
+		}
 		result.ErrorMessage = trim(err.Error())
+		result.ResponseSnippet = redactedSnippet(response, app.Configuration.Redaction, c.Config.HttpConnector.ResponseSnippetSize)
 	} else if !isSuccess(response) {
-		c.recordHTTPCallback(result.AppId, result.PartitionId, constants.Fail)
-		glog.Errorf("Callback failed for schedule id %s with response %+v", result.ScheduleId.String(), response)
+		c.recordHTTPCallback(result, constants.Fail)
+		result.Logger().Errorf("Callback failed for schedule id %s with response %+v", result.ScheduleId.String(), response)
 
 		result.Status = store.Failure
 		result.ErrorMessage = trim(response.Status)
+		result.ResponseSnippet = redactedSnippet(response, app.Configuration.Redaction, c.Config.HttpConnector.ResponseSnippetSize)
 	} else {
-		c.recordHTTPCallback(result.AppId, result.PartitionId, constants.Success)
-		glog.Infof("Callback success for schedule id %s with response %+v", result.ScheduleId.String(), response)
+		c.recordHTTPCallback(result, constants.Success)
+		result.Logger().Infof("Callback success for schedule id %s with response %+v", result.ScheduleId.String(), response)
 
 		result.Status = store.Success
 		result.ErrorMessage = ""
+		result.ResponseSnippet = redactedSnippet(response, app.Configuration.Redaction, c.Config.HttpConnector.ResponseSnippetSize)
+	}
+
+	responseStatus := 0
+	if response != nil {
+		responseStatus = response.StatusCode
 	}
+	return c.completeRun(result, app, isReconciliation, dispatchedAt, responseStatus)
+}
+
+// completeRun records the delivery receipt, the reconciliation history and the lifecycle event of a run with its
+// status set and sends it to the AggregationTaskQueue. Every callback type completes its runs here, so each run
+// gets a receipt whatever its callback. Returns the completed run
+func (c *Connector) completeRun(result store.Schedule, app store.App, isReconciliation bool, dispatchedAt time.Time, responseStatus int) store.Schedule {
+	store.Running().Finish(result)
+	c.createDeliveryReceipt(result, app, dispatchedAt, responseStatus, isReconciliation)
 
 	if isReconciliation {
 		result.UpdateReconciliationHistory(result.Status, result.ErrorMessage)
 	}
 
+	if result.Status == store.Success {
+		store.PublishEvent(store.ScheduleFired, result)
+	} else {
+		store.PublishEvent(store.ScheduleFailed, result)
+	}
+
 	store.AggregationTaskQueue <- store.ScheduleWrapper{
 		Schedule: result,
 		App:      app,
 	}
+
+	return result
 }
 
-// listen processes ScheduleWrapper items from the provided channel
-func (c *Connector) listen(buf chan store.ScheduleWrapper) {
-	for sw := range buf {
+// listen processes ScheduleWrapper items from the provided queue, higher priorities first
+func (c *Connector) listen(queue *store.PriorityQueue) {
+	for {
+		sw := queue.Pop()
+		c.recordQueueWait(sw)
 		c.processSchedule(sw)
 	}
 }
 
+// executionContext returns the context of the execution of the callback of the schedule, cancelled at its deadline.
+// The caller cancels it once done with the response
+func executionContext(schedule store.Schedule) (context.Context, context.CancelFunc) {
+	if deadline := schedule.ExecutionDeadline(); deadline > 0 {
+		return context.WithTimeout(context.Background(), deadline)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// cancelled wraps the error of a callback whose execution was cancelled, at the deadline of its schedule or by a
+// newer run replacing it
+func cancelled(ctx context.Context, input store.Schedule, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return overrun(input, err)
+	}
+	if err == nil {
+		return fmt.Errorf("%w while waiting to retry", store.ErrReplaced)
+	}
+	return fmt.Errorf("%w: %s", store.ErrReplaced, err.Error())
+}
+
+// overrun wraps the error of a callback whose execution was cancelled at the deadline of its schedule
+func overrun(input store.Schedule, err error) error {
+	if err == nil {
+		return fmt.Errorf("%w of %dms while waiting to retry", store.ErrOverrun, input.ExecutionDeadline().Milliseconds())
+	}
+	return fmt.Errorf("%w of %dms: %s", store.ErrOverrun, input.ExecutionDeadline().Milliseconds(), err.Error())
+}
+
+// recordOverrun counts a callback cancelled at its deadline and reports it for the SLA alerts when the schedule asks for it
+func (c *Connector) recordOverrun(schedule store.Schedule) {
+	if schedule.AlertsOnOverrun() {
+		sla.Default().RecordOverrun(schedule.AppId, schedule.ScheduleId.String(), time.Now())
+	}
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.CallbackOverrunCount, callbackLabels(schedule), 1)
+	}
+}
+
 // retryPost attempts to execute an HTTP request according to the schedule and app provided, retrying up to the specified maximum number of attempts
-func (c *Connector) retryPost(input store.Schedule, app store.App) (*http.Response, error) {
+// The attempts stop when ctx is done, the error then wraps store.ErrOverrun or store.ErrReplaced
+// Returns the last response along with the number of attempts made and why they stopped
+func (c *Connector) retryPost(ctx context.Context, input store.Schedule, app store.App) (*http.Response, int, retryStop, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			glog.Errorf("Recovered in RetryPost from error %s with stacktrace %s", r, string(debug.Stack()))
+			input.Logger().Errorf("Recovered in RetryPost from error %s with stacktrace %s", r, string(debug.Stack()))
 		}
 	}()
 
 	attempts := 0
-	maxAttempts := 3
 
 	for {
 		attempts++
-		glog.Infof("POSTING SCHEDULE %s\nATTEMPT %d ", input.ScheduleId, attempts)
+		attemptedAt := time.Now()
+		input.Logger().Infof("POSTING SCHEDULE %s\nATTEMPT %d ", input.ScheduleId, attempts)
 		url := input.Callback.(*store.HttpCallback).Details.Url
-		glog.Infof("URL: %s", url)
+		input.Logger().Infof("URL: %s", url)
 
-		req, err := createRequest(input)
+		req, err := createRequest(input, app)
 		if err != nil {
-			return nil, err
+			c.logCallbackAttempt(input, app, attempts, attemptedAt, nil, err)
+			return nil, attempts, stopNotRetryable, err
 		}
 
-		response, err := c.HttpClient.Do(req)
-		handleResponseDump(input, response, attempts, err)
+		client, err := c.callbackClient(app, input.GetRegion(app))
+		if err != nil {
+			c.logCallbackAttempt(input, app, attempts, attemptedAt, nil, err)
+			return nil, attempts, stopNotRetryable, err
+		}
 
-		retry := shouldRetry(maxAttempts, attempts, response)
-		if retry {
-			c.recordHTTPCallback(input.AppId, input.PartitionId, constants.Retry)
-		} else {
-			return response, err
+		response, err := client.Do(req.WithContext(diagnostics.Connections().Trace(ctx, app.AppId, req.URL.Host)))
+		if err != nil && ctx.Err() != nil {
+			c.logCallbackAttempt(input, app, attempts, attemptedAt, nil, err)
+			err = cancelled(ctx, input, err)
+			return nil, attempts, cancelledStop(err), err
+		}
+		handleResponseDump(input, app, response, attempts, err)
+		if err == nil {
+			err = assertResponse(input, response)
+		}
+		c.logCallbackAttempt(input, app, attempts, attemptedAt, response, err)
+
+		policy := app.Configuration.RetryPolicy
+		if stop := stopAttempts(maxCallbackAttempts, attempts, policy, response, err); stop != "" {
+			return response, attempts, stop, err
+		}
+		c.recordHTTPCallback(input, constants.Retry)
+		select {
+		case <-time.After(c.retryWait(policy, response, attempts)):
+		case <-ctx.Done():
+			err = cancelled(ctx, input, err)
+			return response, attempts, cancelledStop(err), err
 		}
 	}
 }
 
-func (c *Connector) createWorkerPool(buf chan store.ScheduleWrapper) {
+func (c *Connector) createWorkerPool(queue *store.PriorityQueue) {
 	noOfWorkers := c.Config.HttpConnector.Routines
 	for i := 0; i < noOfWorkers; i++ {
-		fmt.Printf("\nInitializing worker for *HTTP* connector %d", i)
-		go c.listen(buf)
+		logger.Debugf("Initializing worker for HTTP connector %d", i)
+		go c.listen(queue)
 	}
 }
 
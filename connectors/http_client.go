@@ -0,0 +1,143 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// appClient is the http client built for the transport of an app, or of an app and a region
+type appClient struct {
+	transport store.HttpTransport
+	client    *http.Client
+}
+
+// appClients caches the http clients of the apps tuning their callback transport and of the apps and regions whose
+// callbacks go through the egress proxy of their region, by app or by app and region
+type appClients struct {
+	mu      sync.Mutex
+	clients map[string]appClient
+}
+
+// callbackClient returns the client delivering the http callbacks of the app in a region. Apps without a transport of
+// their own share the default client, the others get a client built once and rebuilt when their transport changes.
+// The callbacks of another region than the one of the cluster go through the egress proxy of their region, in place
+// of the proxy of the transport of the app, and fail in strict mode when the region has no egress proxy.
+func (c *Connector) callbackClient(app store.App, region string) (*http.Client, error) {
+	proxy, err := store.GetRegions().Route(region)
+	if err != nil {
+		c.recordRegionRoute(app.AppId, region, constants.Fail)
+		return nil, err
+	}
+	if proxy == "" && app.Configuration.HttpTransport == nil {
+		return c.HttpClient, nil
+	}
+
+	var transport store.HttpTransport
+	if app.Configuration.HttpTransport != nil {
+		transport = *app.Configuration.HttpTransport
+	}
+	key := app.AppId
+	if proxy != "" {
+		c.recordRegionRoute(app.AppId, region, constants.Success)
+		transport.ProxyUrl = proxy
+		key += "@" + region
+	}
+
+	c.appClients.mu.Lock()
+	defer c.appClients.mu.Unlock()
+
+	if cached, ok := c.appClients.clients[key]; ok {
+		if cached.transport == transport {
+			return cached.client, nil
+		}
+		cached.client.CloseIdleConnections()
+		// the statistics of the connections describe the pool sizing in use
+		diagnostics.Connections().Reset(app.AppId)
+	}
+
+	client, err := newHttpClient(transport, c.HttpClient.Timeout)
+	if err != nil {
+		logger.Errorf("Invalid http transport %+v of app %s, using the default client: %s", transport, app.AppId, err.Error())
+		return c.HttpClient, nil
+	}
+
+	if c.appClients.clients == nil {
+		c.appClients.clients = make(map[string]appClient)
+	}
+	c.appClients.clients[key] = appClient{transport: transport, client: client}
+	return client, nil
+}
+
+// recordRegionRoute counts the callbacks routed through the egress proxy of their region and the ones failed for
+// want of an egress proxy
+func (c *Connector) recordRegionRoute(appId, region, status string) {
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.RegionRouteCount, map[string]string{"appId": appId, "region": region, "status": status}, 1)
+	}
+}
+
+// newHttpClient builds a client on a copy of the default transport tuned by the app transport
+func newHttpClient(transport store.HttpTransport, timeout time.Duration) (*http.Client, error) {
+	tuned := http.DefaultTransport.(*http.Transport).Clone()
+
+	if transport.ProxyUrl != "" {
+		proxy, err := url.Parse(transport.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+		tuned.Proxy = http.ProxyURL(proxy)
+	}
+
+	minVersion, err := transport.GetTLSMinVersion()
+	if err != nil {
+		return nil, err
+	}
+	if minVersion != 0 {
+		tuned.TLSClientConfig = &tls.Config{MinVersion: minVersion}
+	}
+
+	if transport.MaxIdleConnsPerHost > 0 {
+		tuned.MaxIdleConnsPerHost = transport.MaxIdleConnsPerHost
+	}
+	tuned.MaxConnsPerHost = transport.MaxConnsPerHost
+	if transport.IdleConnTimeoutMillis > 0 {
+		tuned.IdleConnTimeout = time.Duration(transport.IdleConnTimeoutMillis) * time.Millisecond
+	}
+	tuned.DisableKeepAlives = transport.DisableKeepAlives
+
+	if transport.DisableHttp2 {
+		// a non nil empty TLSNextProto keeps the transport from negotiating HTTP/2
+		tuned.ForceAttemptHTTP2 = false
+		tuned.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return &http.Client{Transport: faults.Default().Transport(store.Egress().Transport(tuned)), Timeout: timeout}, nil
+}
@@ -0,0 +1,46 @@
+package connectors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestCallbackClientRegions(t *testing.T) {
+	regions, err := store.NewRegions(conf.RegionConfig{
+		Region:        "ap-south-1",
+		EgressProxies: map[string]string{"eu-west-1": "http://proxy.eu:3128"},
+		Strict:        true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store.SetRegions(regions)
+	defer store.SetRegions(nil)
+
+	c := &Connector{HttpClient: &http.Client{Timeout: time.Second}}
+	app := store.App{AppId: "test"}
+
+	if client, err := c.callbackClient(app, "ap-south-1"); err != nil || client != c.HttpClient {
+		t.Errorf("Expected the default client in the region of the cluster, got %v", err)
+	}
+
+	client, err := c.callbackClient(app, "eu-west-1")
+	if err != nil || client == c.HttpClient {
+		t.Fatalf("Expected a client of the region, got %v", err)
+	}
+	if cached := c.appClients.clients["test@eu-west-1"]; cached.transport.ProxyUrl != "http://proxy.eu:3128" {
+		t.Errorf("Expected the egress proxy of the region, got %+v", cached.transport)
+	}
+	if again, _ := c.callbackClient(app, "eu-west-1"); again != client {
+		t.Errorf("Expected the client of the region to be reused")
+	}
+
+	if _, err := c.callbackClient(app, "us-east-1"); !errors.Is(err, store.ErrRegionUnroutable) {
+		t.Errorf("Expected ErrRegionUnroutable, got %v", err)
+	}
+}
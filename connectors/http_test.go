@@ -0,0 +1,123 @@
+package connectors
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestRetryPostDeadline(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	c := &Connector{Config: &conf.Configuration{}, HttpClient: &http.Client{Timeout: 5 * time.Second}}
+	schedule := func(url string, deadlineMillis int64) store.Schedule {
+		return store.Schedule{AppId: "test", Callback: &store.HttpCallback{Type: "http", Details: store.Details{
+			Url: url, Method: http.MethodPost, DeadlineMillis: deadlineMillis,
+		}}}
+	}
+
+	tests := []struct {
+		name          string
+		schedule      store.Schedule
+		backoffMillis int
+		overrun       bool
+		attempts      int
+	}{
+		{"slow callback", schedule(slow.URL, 50), 1000, true, 1},
+		{"waiting to retry", schedule(failing.URL, 50), 1000, true, 1},
+		{"without a deadline", schedule(failing.URL, 0), 1, false, maxCallbackAttempts},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := store.App{AppId: "test", Configuration: store.Configuration{
+				RetryPolicy: &store.RetryPolicy{StatusCodes: []string{"5xx"}, BackoffMillis: test.backoffMillis},
+			}}
+			ctx, cancel := executionContext(test.schedule)
+			defer cancel()
+
+			start := time.Now()
+			_, attempts, _, err := c.retryPost(ctx, test.schedule, app)
+			if errors.Is(err, store.ErrOverrun) != test.overrun {
+				t.Errorf("Expected overrun %v, got %v", test.overrun, err)
+			}
+			if attempts != test.attempts {
+				t.Errorf("Expected %d attempts, got %d", test.attempts, attempts)
+			}
+			if test.overrun && time.Since(start) > 500*time.Millisecond {
+				t.Errorf("Expected the callback to be cancelled at its deadline, took %s", time.Since(start))
+			}
+		})
+	}
+}
+
+func TestRetryPostReplaced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := &Connector{Config: &conf.Configuration{}, HttpClient: &http.Client{Timeout: 5 * time.Second}}
+	run := store.Schedule{AppId: "test", Callback: &store.HttpCallback{Type: "http", Details: store.Details{
+		Url: server.URL, Method: http.MethodPost, DeadlineMillis: 5000,
+	}}}
+
+	ctx, cancel := executionContext(run)
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	if _, _, stop, err := c.retryPost(ctx, run, store.App{AppId: "test"}); !errors.Is(err, store.ErrReplaced) || stop.deadLettered() {
+		t.Errorf("Expected the cancelled callback to be replaced, got %v", err)
+	}
+}
+
+func TestProcessScheduleDeadLettersFinalFailures(t *testing.T) {
+	defer func(queue chan store.Event) { store.EventTaskQueue = queue }(store.EventTaskQueue)
+	defer func(queue chan store.ScheduleWrapper) { store.AggregationTaskQueue = queue }(store.AggregationTaskQueue)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := &Connector{Config: &conf.Configuration{}, HttpClient: &http.Client{Timeout: 5 * time.Second}}
+	app := store.App{AppId: "test", Configuration: store.Configuration{
+		RetryPolicy: &store.RetryPolicy{StatusCodes: []string{"5xx"}, BackoffMillis: 1},
+	}}
+	run := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Callback: &store.HttpCallback{Type: "http", Details: store.Details{
+		Url: server.URL, Method: http.MethodPost,
+	}}}
+
+	store.EventTaskQueue = make(chan store.Event, 10)
+	store.AggregationTaskQueue = make(chan store.ScheduleWrapper, 10)
+	c.processSchedule(store.ScheduleWrapper{Schedule: run, App: app})
+
+	if requests != 1 {
+		t.Errorf("Expected the 400 response not to be retried, got %d requests", requests)
+	}
+	var types []store.EventType
+	for len(store.EventTaskQueue) > 0 {
+		types = append(types, (<-store.EventTaskQueue).Type)
+	}
+	if len(types) != 2 || types[0] != store.ScheduleFailed || types[1] != store.ScheduleDeadLettered {
+		t.Errorf("Expected the run failing on a non retryable response to be dead-lettered, got events %v", types)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/monitoring"
+	"github.com/myntra/goscheduler/store"
+)
+
+// processPluginSchedule executes the plugin callback of a schedule and completes the run with the outcome
+func (c *Connector) processPluginSchedule(scheduleWrapper store.ScheduleWrapper) {
+	result := scheduleWrapper.Schedule
+	plugin, ok := result.Callback.(store.Plugin)
+	if !ok {
+		result.Logger().Errorf("Callback of type %s for schedule id %s is not a plugin", result.GetCallBackType(), result.ScheduleId.String())
+		return
+	}
+
+	result.Logger().Infof("Plugin callback fired for schedule with schedule id %s and schedule entity %+v", result.ScheduleId.String(), result)
+	firedAt, start := clock.Now(), time.Now()
+	c.recordFiringLag(result, firedAt, scheduleWrapper.IsReconciliation)
+	err := executePlugin(plugin, result, scheduleWrapper.App)
+	latency := time.Since(start)
+	c.recordUsage(result, 1)
+
+	if c.Monitor != nil {
+		monitoring.RecordTimingWithExemplar(c.Monitor, constants.CallbackDuration, callbackLabels(result), latency, monitoring.TraceExemplar(result.TraceId))
+	}
+
+	if err != nil {
+		c.recordHTTPCallback(result, constants.Fail)
+		result.Logger().Errorf("Plugin callback failed for schedule id %s with error %s", result.ScheduleId.String(), err.Error())
+
+		result.Status = store.Failure
+		result.ErrorMessage = trim(err.Error())
+	} else {
+		c.recordHTTPCallback(result, constants.Success)
+
+		result.Status = store.Success
+		result.ErrorMessage = ""
+	}
+
+	run := c.completeRun(result, scheduleWrapper.App, scheduleWrapper.IsReconciliation, firedAt, 0)
+	c.recordRunStats(run, 1, firedAt)
+	c.notifyStatusCallback(run, nil, 1, firedAt, latency)
+	c.notifyFailure(run, scheduleWrapper.App)
+}
+
+// executePlugin executes the plugin, a panic in the plugin is returned as an error
+func executePlugin(plugin store.Plugin, schedule store.Schedule, app store.App) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Recovered in plugin %s from error %v with stacktrace %s", plugin.GetType(), r, string(debug.Stack()))
+			err = fmt.Errorf("plugin %s panicked: %v", plugin.GetType(), r)
+		}
+	}()
+
+	if appPlugin, ok := plugin.(store.AppPlugin); ok {
+		return appPlugin.ExecuteFor(schedule, app)
+	}
+	return plugin.Execute(schedule)
+}
+
+// listenPlugins processes ScheduleWrapper items of plugin callbacks from the provided queue, higher priorities first
+func (c *Connector) listenPlugins(queue *store.PriorityQueue) {
+	for {
+		sw := queue.Pop()
+		c.recordQueueWait(sw)
+		c.processPluginSchedule(sw)
+	}
+}
+
+func (c *Connector) createPluginWorkerPool(callbackType string, queue *store.PriorityQueue, noOfWorkers int) {
+	for i := 0; i < noOfWorkers; i++ {
+		logger.Debugf("Initializing worker for %s plugin callbacks %d", callbackType, i)
+		go c.listenPlugins(queue)
+	}
+}
+
+// initPluginWorkers starts the worker pool of each plugin callback type, and the shared pool of the plugin types
+// registered after the queues were created
+func (c *Connector) initPluginWorkers() {
+	go c.createPluginWorkerPool("shared", store.PluginTaskQueue, c.Config.CallbackPluginConfig.Routines)
+	for callbackType, queue := range store.PluginTaskQueues {
+		go c.createPluginWorkerPool(callbackType, queue, c.Config.CallbackPluginConfig.GetPool(callbackType).Routines)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// storeDueRun keeps the run of a pull callback for the consumers of its app to poll for.
+// A run which cannot be stored fails right away
+func (c *Connector) storeDueRun(wrapper store.ScheduleWrapper) {
+	result := wrapper.Schedule
+	firedAt := clock.Now()
+	c.recordFiringLag(result, firedAt, wrapper.IsReconciliation)
+
+	ttl := result.GetTTL(wrapper.App, c.Config.AppLevelConfiguration.FiredScheduleRetentionPeriod)
+	if err := c.ScheduleDao.CreateDueRun(store.NewDueRun(result), ttl); err != nil {
+		c.recordHTTPCallback(result, constants.Fail)
+		result.Logger().Errorf("Due run could not be stored for schedule id %s with error %s", result.ScheduleId.String(), err.Error())
+
+		result.Status = store.Failure
+		result.ErrorMessage = trim(err.Error())
+		run := c.completeRun(result, wrapper.App, wrapper.IsReconciliation, firedAt, 0)
+		c.recordRunStats(run, 0, firedAt)
+		c.notifyStatusCallback(run, nil, 0, firedAt, 0)
+		c.notifyFailure(run, wrapper.App)
+		return
+	}
+
+	result.Logger().Infof("Due run stored for schedule id %s", result.ScheduleId.String())
+	// the stored run is delivered to the consumers whichever node serves them, so its fire is acknowledged
+	c.acknowledgeIntents([]store.Schedule{result})
+}
+
+// completeAcknowledgedRun completes a run of a pull callback with the outcome acknowledged by its consumer, or the
+// failure of a run which ran out of deliveries
+func (c *Connector) completeAcknowledgedRun(task store.PullAckTask) {
+	result := task.Schedule
+	result.Status = task.Status
+	result.ErrorMessage = trim(task.ErrorMessage)
+
+	if result.Status == store.Success {
+		c.recordHTTPCallback(result, constants.Success)
+	} else {
+		c.recordHTTPCallback(result, constants.Fail)
+		result.Logger().Errorf("Pull callback failed for schedule id %s with error %s", result.ScheduleId.String(), result.ErrorMessage)
+	}
+	c.recordUsage(result, task.Deliveries)
+
+	// a run is acknowledged once, a failed run is not delivered again
+	run := c.completeRun(result, task.App, false, task.AcknowledgedAt, 0)
+	if run.Status == store.Failure {
+		store.PublishEvent(store.ScheduleDeadLettered, run)
+	}
+	c.recordRunStats(run, task.Deliveries, task.AcknowledgedAt)
+	c.notifyStatusCallback(run, nil, task.Deliveries, task.AcknowledgedAt, 0)
+	c.notifyFailure(run, task.App)
+}
+
+// listenPullAcks completes the acknowledged runs of the tasks received on the channel
+func (c *Connector) listenPullAcks(buf <-chan store.PullAckTask) {
+	for task := range buf {
+		c.completeAcknowledgedRun(task)
+	}
+}
+
+// initPullWorkers starts the workers completing the acknowledged runs, and the ones storing the due runs on the
+// nodes firing the callbacks
+func (c *Connector) initPullWorkers(callbackWorkers bool) {
+	for i := 0; i < c.Config.PullDeliveryConfig.Routines; i++ {
+		logger.Debugf("Initializing worker for pull delivery %d", i)
+		go c.listenPullAcks(store.PullAckTaskQueue)
+		if callbackWorkers {
+			go func() {
+				for {
+					c.storeDueRun(store.PullTaskQueue.Pop())
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// createDeliveryReceipt signs and persists a delivery receipt for a dispatched callback
+// if delivery receipts are enabled in the configuration.
+// The response status is 0 for the callbacks which are not http requests.
+func (c *Connector) createDeliveryReceipt(schedule store.Schedule, app store.App, dispatchedAt time.Time, responseStatus int, isReconciliation bool) {
+	receiptConfig := c.Config.DeliveryReceiptConfig
+	if !receiptConfig.Enabled {
+		return
+	}
+
+	receipt := store.NewDeliveryReceipt(schedule, dispatchedAt, responseStatus)
+	receipt.Sign(receiptConfig.KeyId, []byte(receiptConfig.SigningKey))
+	receipt.Node = c.Config.Cluster.Address
+	receipt.Reconciliation = isReconciliation
+
+	ttl := schedule.GetTTL(app, c.Config.AppLevelConfiguration.FiredScheduleRetentionPeriod)
+	if err := c.ScheduleDao.CreateDeliveryReceipt(receipt, ttl); err != nil {
+		logger.Errorf("Delivery receipt creation failed for schedule id %s with error %s", schedule.ScheduleId.String(), err.Error())
+	}
+}
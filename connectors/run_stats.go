@@ -0,0 +1,80 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// recordRunStats counts the outcome of a run in the hour it was fired of its app.
+// The attempts after the first one are counted as retries.
+func (c *Connector) recordRunStats(run store.Schedule, attempts int, firedAt time.Time) {
+	if !c.Config.RunStatsConfig.Enabled {
+		return
+	}
+
+	stats := store.RunStats{}
+	if run.Status == store.Success {
+		stats.Successes = 1
+	} else {
+		stats.Failures = 1
+	}
+	if attempts > 1 {
+		stats.Retries = int64(attempts - 1)
+	}
+	store.RunStatistics().Record(run.AppId, firedAt, stats)
+}
+
+// flushRunStats adds the run stats counted by the node since the last flush to the rollup table.
+// The stats which could not be added are counted again to be retried on the next flush.
+func (c *Connector) flushRunStats() {
+	for _, stats := range store.RunStatistics().Drain() {
+		status := constants.Success
+		if err := c.ClusterDao.IncrementRunStats(stats); err != nil {
+			status = constants.Fail
+			logger.Errorf("Run stats flush failed for app %s with error %s", stats.AppId, err.Error())
+			store.RunStatistics().Record(stats.AppId, stats.Hour, stats)
+		}
+
+		if c.Monitor != nil {
+			c.Monitor.IncCounter(constants.RunStatsFlushCount, map[string]string{"appId": stats.AppId, "status": status}, 1)
+		}
+	}
+}
+
+func (c *Connector) initRunStatsFlusher() {
+	if !c.Config.RunStatsConfig.Enabled {
+		return
+	}
+
+	interval := time.Duration(c.Config.RunStatsConfig.FlushIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	go func() {
+		for range time.Tick(interval) {
+			c.flushRunStats()
+		}
+	}()
+}
@@ -20,8 +20,7 @@
 package connectors
 
 import (
-	"fmt"
-	"github.com/golang/glog"
+	"github.com/myntra/goscheduler/logger"
 	s "github.com/myntra/goscheduler/store"
 )
 
@@ -32,7 +31,9 @@ func (c *Connector) updateStatus(buf <-chan s.StatusTask) {
 		if len(batch) > 0 {
 			err := c.ScheduleDao.UpdateStatus(batch, statusTask.App)
 			if err != nil {
-				glog.Errorf("status update failed for appId: %s, partitionId: %d with error %s", batch[0].AppId, batch[0].PartitionId, err.Error())
+				logger.Errorf("status update failed for appId: %s, partitionId: %d with error %s", batch[0].AppId, batch[0].PartitionId, err.Error())
+			} else {
+				c.acknowledgeIntents(batch)
 			}
 		}
 	}
@@ -42,7 +43,7 @@ func (c *Connector) updateStatus(buf <-chan s.StatusTask) {
 func (c *Connector) CreateStatusUpdatePool(buf chan s.StatusTask) {
 	noOfWorkers := c.Config.StatusUpdateConfig.Routines
 	for i := 0; i < noOfWorkers; i++ {
-		fmt.Printf("Initializing status update workers %d \n", i)
+		logger.Debugf("Initializing status update worker %d", i)
 		go c.updateStatus(buf)
 	}
 }
@@ -50,3 +51,21 @@ func (c *Connector) CreateStatusUpdatePool(buf chan s.StatusTask) {
 func (c *Connector) initStatusUpdatePool() {
 	go c.CreateStatusUpdatePool(s.StatusTaskQueue)
 }
+
+// acknowledgeIntents removes the claims of the runs whose outcome was written from the intent log, the runs fired
+// unclaimed have none
+func (c *Connector) acknowledgeIntents(runs []s.Schedule) {
+	var intents []s.FireIntent
+	for _, run := range runs {
+		if intent, ok := s.IntentOf(run); ok {
+			intents = append(intents, intent)
+		}
+	}
+	if len(intents) == 0 {
+		return
+	}
+
+	if err := c.ScheduleDao.AcknowledgeSchedules(intents); err != nil {
+		logger.Errorf("Acknowledging %d claims of appId: %s, partitionId: %d failed with error %s", len(intents), intents[0].AppId, intents[0].PartitionId, err.Error())
+	}
+}
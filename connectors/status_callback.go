@@ -0,0 +1,120 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// notifyStatusCallback queues the outcome of a run to be posted to the status callback url of the schedule.
+// Status callbacks are best effort, the notification is dropped if the queue is full so that callbacks are never delayed.
+func (c *Connector) notifyStatusCallback(run store.Schedule, response *http.Response, attempts int, firedAt time.Time, latency time.Duration) {
+	if run.StatusCallback == "" {
+		return
+	}
+
+	statusCode := 0
+	if response != nil {
+		statusCode = response.StatusCode
+	}
+
+	task := store.StatusCallbackTask{
+		Url:   run.StatusCallback,
+		Event: store.NewStatusCallbackEvent(run, statusCode, attempts, firedAt, latency),
+	}
+
+	select {
+	case store.StatusCallbackTaskQueue <- task:
+	default:
+		c.recordStatusCallback(run.AppId, constants.Fail)
+		run.Logger().Errorf("Status callback queue full, dropping status callback for schedule id %s", run.ScheduleId.String())
+	}
+}
+
+// postStatusCallback posts the event of the task to its url
+// Returns a non nil error if the request fails or a non 2xx response is received
+func (c *Connector) postStatusCallback(task store.StatusCallbackTask) error {
+	body, err := json.Marshal(task.Event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, task.Url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(constants.ContentType, constants.ApplicationJson)
+	req.Header.Set(constants.ScheduleIdHeader, task.Event.ScheduleId)
+	if traceParent := store.TraceParent(task.Event.TraceId); traceParent != "" {
+		req.Header.Set(constants.TraceParentHeader, traceParent)
+	}
+
+	response, err := c.StatusCallbackClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if !isSuccess(response) {
+		return fmt.Errorf("status callback responded with %s", response.Status)
+	}
+	return nil
+}
+
+func (c *Connector) recordStatusCallback(appId string, status string) {
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.StatusCallbackCount, map[string]string{"appId": appId, "status": status}, 1)
+	}
+}
+
+// listenStatusCallbacks posts the status callbacks received on the provided channel
+func (c *Connector) listenStatusCallbacks(buf <-chan store.StatusCallbackTask) {
+	for task := range buf {
+		if err := c.postStatusCallback(task); err != nil {
+			c.recordStatusCallback(task.Event.AppId, constants.Fail)
+			logger.Errorf("Status callback to %s failed for schedule id %s with error %s", task.Url, task.Event.ScheduleId, err.Error())
+		} else {
+			c.recordStatusCallback(task.Event.AppId, constants.Success)
+		}
+	}
+}
+
+func (c *Connector) createStatusCallbackPool(buf chan store.StatusCallbackTask) {
+	noOfWorkers := c.Config.StatusCallbackConfig.Routines
+	for i := 0; i < noOfWorkers; i++ {
+		logger.Debugf("Initializing worker for status callbacks %d", i)
+		go c.listenStatusCallbacks(buf)
+	}
+}
+
+func (c *Connector) initStatusCallbackWorkers() {
+	go c.createStatusCallbackPool(store.StatusCallbackTaskQueue)
+}
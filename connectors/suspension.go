@@ -0,0 +1,79 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// checkUnreachable counts the outcome of the callback of the run towards the streak of its host, and suspends the
+// recurring schedule of the run once the host has stayed unreachable over the configured window
+func (c *Connector) checkUnreachable(run store.Schedule, err error) {
+	config := c.Config.SuspensionConfig
+	if !config.Enabled {
+		return
+	}
+
+	window := time.Duration(config.WindowMinutes) * time.Minute
+	unreachable := store.Unreachable(err)
+	if !store.Unreachables().Record(store.CallbackHost(run), unreachable, time.Now(), window, config.MinFailures) {
+		return
+	}
+	if !unreachable || util.IsZeroUUID(run.ParentScheduleId) {
+		return
+	}
+
+	if err = c.suspendSchedule(run, err); err != nil {
+		c.recordSuspension(run.AppId, constants.Fail)
+		run.Logger().Errorf("Suspending schedule id %s of unreachable callback failed with error %s", run.ParentScheduleId.String(), err.Error())
+	}
+}
+
+// suspendSchedule suspends the recurring schedule of the run, unless it is no longer scheduled
+func (c *Connector) suspendSchedule(run store.Schedule, cause error) error {
+	schedule, err := c.ScheduleDao.GetSchedule(run.ParentScheduleId)
+	if err != nil {
+		return err
+	}
+	if schedule.Status != store.Scheduled {
+		return nil
+	}
+
+	updatedSchedule, err := c.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Suspended)
+	if err != nil {
+		return err
+	}
+
+	run.Logger().Infof("Schedule id %s suspended, its callback host %s is unreachable", schedule.ScheduleId.String(), store.CallbackHost(run))
+	c.recordSuspension(run.AppId, constants.Success)
+	updatedSchedule.ErrorMessage = trim(cause.Error())
+	store.PublishEvent(store.ScheduleSuspended, updatedSchedule)
+	return nil
+}
+
+func (c *Connector) recordSuspension(appId string, status string) {
+	if c.Monitor != nil {
+		c.Monitor.IncCounter(constants.SuspendedScheduleCount, map[string]string{"appId": appId, "status": status}, 1)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package connectors
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// recordUsage counts a fired callback of the schedule in the usage of its app.
+// The payload is counted once per callback, the attempts after the first one are counted as retries.
+func (c *Connector) recordUsage(schedule store.Schedule, attempts int) {
+	if !c.Config.UsageConfig.Enabled {
+		return
+	}
+
+	retries := attempts - 1
+	if retries < 0 {
+		retries = 0
+	}
+	store.Usages().Record(schedule.AppId, time.Now(), store.Usage{
+		CallbacksFired: 1,
+		BytesDelivered: int64(len(schedule.Payload)),
+		Retries:        int64(retries),
+	})
+}
+
+// flushUsage adds the usage counted by the node since the last flush to the rollup table.
+// The usage which could not be added is counted again to be retried on the next flush.
+func (c *Connector) flushUsage() {
+	for _, usage := range store.Usages().Drain() {
+		status := constants.Success
+		if err := c.ClusterDao.IncrementUsage(usage); err != nil {
+			status = constants.Fail
+			logger.Errorf("Usage flush failed for app %s with error %s", usage.AppId, err.Error())
+			store.Usages().Record(usage.AppId, usage.Day, usage)
+		}
+
+		if c.Monitor != nil {
+			c.Monitor.IncCounter(constants.UsageFlushCount, map[string]string{"appId": usage.AppId, "status": status}, 1)
+		}
+	}
+}
+
+func (c *Connector) initUsageFlusher() {
+	if !c.Config.UsageConfig.Enabled {
+		return
+	}
+
+	interval := time.Duration(c.Config.UsageConfig.FlushIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	go func() {
+		for range time.Tick(interval) {
+			c.flushUsage()
+		}
+	}()
+}
@@ -31,8 +31,15 @@ const (
 	DeleteSchedule                           = "DeleteSchedule"
 	PauseSchedule                            = "PauseSchedule"
 	ResumeSchedule                           = "ResumeSchedule"
+	ActivateSchedule                         = "ActivateSchedule"
 	GetSchedule                              = "GetSchedule"
 	GetScheduleRuns                          = "GetScheduleRuns"
+	GetScheduleReceipts                      = "GetScheduleReceipts"
+	GetCallbackCanary                        = "GetCallbackCanary"
+	GetScheduleVersions                      = "GetScheduleVersions"
+	RollbackSchedule                         = "RollbackSchedule"
+	ValidateSchedule                         = "ValidateSchedule"
+	SimulateSchedule                         = "SimulateSchedule"
 	GetAppSchedule                           = "GetAppSchedule"
 	GetCronSchedule                          = "GetCronSchedule"
 	Success                                  = "Success"
@@ -40,6 +47,7 @@ const (
 	StatusCode                               = "statusCode"
 	Fail                                     = "Fail"
 	Retry                                    = "Retry"
+	Dropped                                  = "Dropped"
 	StatusMessage                            = "statusMessage"
 	GetApps                                  = "GetApps"
 	DOT                                      = "."
@@ -51,12 +59,20 @@ const (
 	SecondsToMillis                          = 1000
 	SuccessCode200                           = 200
 	SuccessCode201                           = 201
+	SuccessCode202                           = 202
 	ScheduleIdHeader                         = "Schedule-Id"
 	ParentScheduleId                         = "Parent-Schedule-Id"
+	TraceParentHeader                        = "traceparent"
 	INFO                                     = 2 // This log level is used for Create and Delete happy flows to avoid excessive latency
 	PollerKeySep                             = "."
 	BulkAction                               = "BulkAction"
 	DefaultCallback                          = "http"
+	TemplateCallback                         = "template"
+	CanaryCallback                           = "canary"
+	LifecycleCallback                        = "lifecycle"
+	PullCallback                             = "pull"
+	PubSubCallback                           = "pubsub"
+	ServiceBusCallback                       = "servicebus"
 	HttpResponseSuccessStatusCodeLowerBound  = 200
 	HttpResponseSuccessStatusCodeHigherBound = 299
 	CreateConfiguration                      = "CreateConfiguration"
@@ -71,10 +87,52 @@ const (
 	HttpRequestsDuration              = "http_requests_duration"
 	CallbackStatusCount               = "callback_status_count"
 	CallbackDuration                  = "callback_duration"
+	CallbackQueueWait                 = "callback_queue_wait"
+	StatusCallbackCount               = "status_callback_count"
+	FailureNotificationCount          = "failure_notification_count"
+	SuspendedScheduleCount            = "suspended_schedule_count"
+	StalePausedScheduleCount          = "stale_paused_schedule_count"
+	FireIntentCount                   = "fire_intent_count"
+	EventPublishCount                 = "event_publish_count"
+	ReplicationEventCount             = "replication_event_count"
+	ReplicationLag                    = "replication_lag"
+	ReplicationDivergenceCount        = "replication_divergence_count"
+	CallbackBacklog                   = "callback_backlog"
+	CassandraQueryLatency             = "cassandra_query_latency"
+	CassandraQueryDuration            = "cassandra_query_duration"
+	CassandraQueryRetryCount          = "cassandra_query_retry_count"
+	ShedRequestCount                  = "shed_request_count"
+	PurgedScheduleCount               = "purged_schedule_count"
+	RejectedCallbackCount             = "rejected_callback_count"
+	FollowUpScheduleCount             = "follow_up_schedule_count"
+	BlackoutScheduleCount             = "blackout_schedule_count"
+	DisabledScheduleCount             = "disabled_schedule_count"
+	ShedScheduleCount                 = "shed_schedule_count"
+	PromotedScheduleCount             = "promoted_schedule_count"
+	FiringLag                         = "firing_lag"
+	SLAAlertCount                     = "sla_alert_count"
+	AnomalyCount                      = "anomaly_count"
+	UsageFlushCount                   = "usage_flush_count"
+	InjectedFaultCount                = "injected_fault_count"
+	ArchivedScheduleCount             = "archived_schedule_count"
+	ArchiveFailureCount               = "archive_failure_count"
+	RunStatsFlushCount                = "run_stats_flush_count"
+	StatusBatchCount                  = "status_batch_count"
+	BatchedStatusCount                = "batched_status_count"
+	HedgedReadCount                   = "hedged_read_count"
+	SharedReadCount                   = "shared_read_count"
+	ShadowWriteCount                  = "shadow_write_count"
+	ShadowCompareCount                = "shadow_compare_count"
+	GovernanceDecisionCount           = "governance_decision_count"
+	RegionRouteCount                  = "region_route_count"
+	CallbackOverrunCount              = "callback_overrun_count"
+	ConcurrentRunCount                = "concurrent_run_count"
+	GroupLimitedRunCount              = "group_limited_run_count"
 	CreateSchedule                    = "create_schedule"
 	CreateRecurringSchedule           = "create_recurring_schedule"
 	CreateOneTimeSchedule             = "create_one_time_schedule"
 	PollerLifeCycle                   = "poller_life_cycle"
+	PollerInterval                    = "poller_interval"
 	RequestStatus                     = "request_status"
 	RequestAppStatus                  = "request_app_status"
 	RegisterApp                       = "register_app"
@@ -83,5 +141,73 @@ const (
 	GetSchedulesByEntity              = "get_schedules_by_entity"
 	GetSchedulesByEntityDuration      = "get_schedules_by_entity_duration"
 	GetSchedulesByEntityMaxQueryCount = "get_schedules_by_entity_max_query_count"
+	PendingSchedulesFull              = "pending_schedules_full"
 	UpdateRecurringSchedule           = "update_recurring_schedule"
+	ReplaceSchedule                   = "replace_schedule"
+	PatchSchedule                     = "patch_schedule"
+	HealthCheck                       = "health_check"
+	Readiness                         = "readiness"
+	GetOpenAPISpec                    = "get_openapi_spec"
+	GetDiagnostics                    = "get_diagnostics"
+	ResizeAppPartitions               = "resize_app_partitions"
+	GetPartitionMigration             = "get_partition_migration"
+	ExportSchedules                   = "export_schedules"
+	ImportSchedules                   = "import_schedules"
+	GetReplicationStatus              = "get_replication_status"
+	PromoteReplica                    = "promote_replica"
+	ReconcileMissedSchedules          = "reconcile_missed_schedules"
+	GetDuplicateFires                 = "get_duplicate_fires"
+	GetAppUsage                       = "get_app_usage"
+	CreateCallbackTemplate            = "create_callback_template"
+	UpdateCallbackTemplate            = "update_callback_template"
+	GetCallbackTemplate               = "get_callback_template"
+	GetCallbackTemplates              = "get_callback_templates"
+	VerifyCallbackUrl                 = "verify_callback_url"
+	GetVerifiedUrls                   = "get_verified_urls"
+	DeleteVerifiedUrl                 = "delete_verified_url"
+	GetClusterNodes                   = "get_cluster_nodes"
+	GetClusterPartitions              = "get_cluster_partitions"
+	CordonNode                        = "cordon_node"
+	UncordonNode                      = "uncordon_node"
+	DrainNode                         = "drain_node"
+	BulkUpdateSchedules               = "bulk_update_schedules"
+	GetJob                            = "get_job"
+	CancelJob                         = "cancel_job"
+	RetryJob                          = "retry_job"
+	GetScheduleAttempts               = "get_schedule_attempts"
+	GetDueRuns                        = "get_due_runs"
+	AckDueRun                         = "ack_due_run"
+	AcquireLock                       = "acquire_lock"
+	ReleaseLock                       = "release_lock"
+	StreamEvents                      = "stream_events"
+	GetClock                          = "get_clock"
+	AdvanceClock                      = "advance_clock"
+	GetAppRunStats                    = "get_app_run_stats"
+	GraphQLQuery                      = "graphql_query"
+	GetGraphQLSchema                  = "get_graphql_schema"
+	OffboardApp                       = "offboard_app"
+	CloneApp                          = "clone_app"
+	GetShadowReport                   = "get_shadow_report"
+	ReconcileShadow                   = "reconcile_shadow"
+	ApplySchedules                    = "apply_schedules"
+	CreateScheduleGroup               = "create_schedule_group"
+	GetScheduleGroups                 = "get_schedule_groups"
+	GetScheduleGroup                  = "get_schedule_group"
+	UpdateScheduleGroup               = "update_schedule_group"
+	DeleteScheduleGroup               = "delete_schedule_group"
+	PauseScheduleGroup                = "pause_schedule_group"
+	ResumeScheduleGroup               = "resume_schedule_group"
+	GetSuspendedSchedules             = "get_suspended_schedules"
+	ResumeSuspendedSchedules          = "resume_suspended_schedules"
+	HoldForApproval                   = "hold_for_approval"
+	GetApprovals                      = "get_approvals"
+	GetApproval                       = "get_approval"
+	ApproveRequest                    = "approve_request"
+	RejectRequest                     = "reject_request"
+	GetAppConnections                 = "get_app_connections"
+	TuneAppConnections                = "tune_app_connections"
 )
+
+// Version of the build reported by the nodes of the cluster, set with
+// -ldflags "-X github.com/myntra/goscheduler/constants.Version=<version>"
+var Version = "dev"
@@ -295,40 +295,18 @@ type Month int64
 //
 // Allowed values for Month are,
 //	- [1-12]
-//	- Short names such as JAN/FEB etc in either cases.
+//	- Short names such as JAN/FEB etc or full names such as JANUARY in either cases.
 //
 // These values can be either be,
 //	- single
 //	- comma separated, indicating distinct values.
-//	- dash(-) separated indicating a range of values(Both inclusive).
+//	- dash(-) separated indicating a range of values(Both inclusive), e.g. JAN-MAR.
 func ParseMonth(s string) ([]Month, string) {
-	shortNames := []string{
-		"JAN",
-		"FEB",
-		"MAR",
-		"APR",
-		"MAY",
-		"JUN",
-		"JUL",
-		"AUG",
-		"SEP",
-		"OCT",
-		"NOV",
-		"DEC",
-	}
-
 	if s != "*" {
 		var months []Month
 
-	Parts:
 		for _, part := range strings.Split(s, ",") {
-			for i, val := range shortNames {
-				if val == strings.ToUpper(part) {
-					months = append(months, Month(i+1))
-					continue Parts
-				}
-			}
-
+			part = replaceNames(part, monthNames, 1)
 			switch steps, message := ParseRange(part, 1, 12); {
 			case len(message) > 0:
 				return []Month{}, message
@@ -363,35 +341,18 @@ type Weekday int64
 //
 // Allowed values for Weekday are,
 //	- [0-6]
-//	- Short names such as SUN/MON etc in either cases.
+//	- Short names such as SUN/MON etc or full names such as MONDAY in either cases.
 //
 // These values can be either be,
 //	- single
 //	- comma separated, indicating distinct values.
-//	- dash(-) separated indicating a range of values(Both inclusive).
+//	- dash(-) separated indicating a range of values(Both inclusive), e.g. MON-FRI.
 func ParseWeekday(s string) ([]Weekday, string) {
-	shortNames := []string{
-		"SUN",
-		"MON",
-		"TUE",
-		"WED",
-		"THU",
-		"FRI",
-		"SAT",
-	}
-
 	if s != "*" {
 		var weekdays []Weekday
 
-	Parts:
 		for _, part := range strings.Split(s, ",") {
-			for i, val := range shortNames {
-				if val == strings.ToUpper(part) {
-					weekdays = append(weekdays, Weekday(i))
-					continue Parts
-				}
-			}
-
+			part = replaceNames(part, weekdayNames, 0)
 			switch steps, message := ParseRange(part, 0, 6); {
 			case len(message) > 0:
 				return []Weekday{}, message
@@ -417,6 +378,28 @@ func ParseWeekday(s string) ([]Weekday, string) {
 	return []Weekday{}, ""
 }
 
+var monthNames = []string{
+	"JANUARY", "FEBRUARY", "MARCH", "APRIL", "MAY", "JUNE",
+	"JULY", "AUGUST", "SEPTEMBER", "OCTOBER", "NOVEMBER", "DECEMBER",
+}
+
+var weekdayNames = []string{"SUNDAY", "MONDAY", "TUESDAY", "WEDNESDAY", "THURSDAY", "FRIDAY", "SATURDAY"}
+
+// replaceNames replaces the names of a single value or of the bounds of a range by their numbers, the first name
+// being numbered first. A name matches in either case by its first three letters or in full.
+func replaceNames(part string, names []string, first int) string {
+	bounds := strings.Split(part, "-")
+	for i, bound := range bounds {
+		upper := strings.ToUpper(bound)
+		for j, name := range names {
+			if upper == name[:3] || upper == name {
+				bounds[i] = strconv.Itoa(j + first)
+			}
+		}
+	}
+	return strings.Join(bounds, "-")
+}
+
 // Convert a list of type to int64 values.
 func toInt64(list interface{}) []int64 {
 	var output []int64
@@ -451,53 +434,107 @@ func toInt64(list interface{}) []int64 {
 
 // Expression represents a cron expression.
 // Each filed is a list of types. Each value in the fields corresponds to the time field where it's active.
+// The Quartz style tokens of the day and weekday fields are kept apart from their plain values.
 type Expression struct {
 	Minute  []Minute
 	Hour    []Hour
 	Day     []Day
 	Month   []Month
 	Weekday []Weekday
+
+	LastDay            bool         // L in the day field, the last day of the month
+	LastBusinessDay    bool         // LW in the day field, the last day from Monday to Friday of the month
+	NearestBusinessDay []Day        // 15W in the day field, the day from Monday to Friday nearest to the 15th
+	LastWeekday        []Weekday    // 5L in the weekday field, the last Friday of the month
+	NthWeekday         []NthWeekday // 5#3 in the weekday field, the third Friday of the month
+}
+
+// The fields of a cron expression as named in the field errors of the API
+const (
+	MinuteField     = "minute"
+	HourField       = "hour"
+	DayOfMonthField = "dayOfMonth"
+	DayOfWeekField  = "dayOfWeek"
+	MonthField      = "month"
+)
+
+// fieldNames are the names of the fields in the error messages returned by Parse
+var fieldNames = map[string]string{
+	MinuteField:     "minute",
+	HourField:       "hour",
+	DayOfMonthField: "day of month",
+	MonthField:      "month",
+	DayOfWeekField:  "day of week",
+}
+
+// Field returns the field of the cron expression an error message returned by Parse is about,
+// an empty string if the message is not about a single field
+func Field(message string) string {
+	for field, name := range fieldNames {
+		if strings.HasPrefix(message, name+" field '") {
+			return field
+		}
+	}
+	return ""
+}
+
+// Macros are the aliases of the common expressions
+var Macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
 }
 
 // Parse a string to a cron expression of type Expresion.
-// A non empty list of error messages is returned if the supplied string cannot be parsed to Expression.
+// Besides the standard syntax, the day field accepts the Quartz style L, LW and 15W tokens and the weekday field the
+// 5L and 5#3 tokens, while ? stands for * in both. The macros such as @daily are expanded to their expression.
+// A non empty list of error messages, each naming the offending field, is returned if the supplied string cannot be
+// parsed to Expression.
 func Parse(s string) (Expression, []string) {
 	var expression Expression
 	var errors []string
 
+	if expanded, ok := Macros[strings.ToLower(s)]; ok {
+		s = expanded
+	}
+
 	parts := strings.Split(s, " ")
 	if len(parts) != 5 {
 		return expression, []string{"String doesn't match valid cron format, \"* * * * *\""}
 	}
 
+	fieldError := func(field, value, err string) string {
+		return fmt.Sprintf("%s field '%s': %s", fieldNames[field], value, err)
+	}
+
 	if minutes, err := ParseMinute(parts[0]); len(err) != 0 {
-		errors = append(errors, err)
+		errors = append(errors, fieldError(MinuteField, parts[0], err))
 	} else {
 		expression.Minute = minutes
 	}
 
 	if hours, err := ParseHour(parts[1]); len(err) != 0 {
-		errors = append(errors, err)
+		errors = append(errors, fieldError(HourField, parts[1], err))
 	} else {
 		expression.Hour = hours
 	}
 
-	if days, err := ParseDay(parts[2]); len(err) != 0 {
-		errors = append(errors, err)
-	} else {
-		expression.Day = days
+	if err := expression.parseDayField(parts[2]); len(err) != 0 {
+		errors = append(errors, fieldError(DayOfMonthField, parts[2], err))
 	}
 
 	if months, err := ParseMonth(parts[3]); len(err) != 0 {
-		errors = append(errors, err)
+		errors = append(errors, fieldError(MonthField, parts[3], err))
 	} else {
 		expression.Month = months
 	}
 
-	if weekdays, err := ParseWeekday(parts[4]); len(err) != 0 {
-		errors = append(errors, err)
-	} else {
-		expression.Weekday = weekdays
+	if err := expression.parseWeekdayField(parts[4]); len(err) != 0 {
+		errors = append(errors, fieldError(DayOfWeekField, parts[4], err))
 	}
 
 	return expression, errors
@@ -522,7 +559,7 @@ func (expression Expression) Match(time time.Time) bool {
 
 	return contains(toInt64(expression.Minute), int64(time.Minute())) &&
 		contains(toInt64(expression.Hour), int64(time.Hour())) &&
-		contains(toInt64(expression.Day), int64(time.Day())) &&
+		expression.matchDay(time, contains) &&
 		contains(toInt64(expression.Month), int64(time.Month())) &&
-		contains(toInt64(expression.Weekday), int64(time.Weekday()))
+		expression.matchWeekday(time, contains)
 }
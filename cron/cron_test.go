@@ -283,6 +283,9 @@ func TestParseMonth(t *testing.T) {
 		{"JAN,JUN,DEC", []Month{1, 6, 12}},
 		{"9-12", []Month{9, 10, 11, 12}},
 		{"JAN,3,9-12", []Month{1, 3, 9, 10, 11, 12}},
+		{"January", []Month{1}},
+		{"JAN-MAR", []Month{1, 2, 3}},
+		{"oct-December", []Month{10, 11, 12}},
 	} {
 		if months, err := ParseMonth(test.Input); !assertEquals(toInt64(months), toInt64(test.Expected)) || len(err) != 0 {
 			t.Errorf("Got result \"%v\", error \"%v\" for input \"%s\"", months, err, test.Input)
@@ -327,6 +330,10 @@ func TestParseWeekday(t *testing.T) {
 		{"SUN,WED,SAT", []Weekday{0, 3, 6}},
 		{"0-3", []Weekday{0, 1, 2, 3}},
 		{"SUN,1-3", []Weekday{0, 1, 2, 3}},
+		{"Monday", []Weekday{1}},
+		{"MON-FRI", []Weekday{1, 2, 3, 4, 5}},
+		{"sat,sun", []Weekday{6, 0}},
+		{"1-FRI", []Weekday{1, 2, 3, 4, 5}},
 	} {
 		if weekday, err := ParseWeekday(test.Input); !assertEquals(toInt64(weekday), toInt64(test.Expected)) || len(err) != 0 {
 			t.Errorf("Got result \"%v\" error \"%v\" for input \"%s\"", weekday, err, test.Input)
@@ -360,19 +367,27 @@ func TestParse(t *testing.T) {
 		{"* * * * * *", []string{
 			"String doesn't match valid cron format, \"* * * * *\""}},
 		{"abc * * * *", []string{
-			"Cannot parse abc to int"}},
+			"minute field 'abc': Cannot parse abc to int"}},
 		{"10 abc * xyc 10", []string{
-			"Cannot parse abc to int",
-			"Cannot parse xyc to int",
-			"Weekday should be between 0 and 6"}},
+			"hour field 'abc': Cannot parse abc to int",
+			"month field 'xyc': Cannot parse xyc to int",
+			"day of week field '10': Weekday should be between 0 and 6"}},
 		{"100 50 * 25 *", []string{
-			"Minute should be between 0 and 59",
-			"Hour should be between 0 and 24",
-			"Month should be between 1 and 12"}},
+			"minute field '100': Minute should be between 0 and 59",
+			"hour field '50': Hour should be between 0 and 24",
+			"month field '25': Month should be between 1 and 12"}},
 		{"10,100 0,50 * 1,25 *", []string{
-			"Minute should be between 0 and 59",
-			"Hour should be between 0 and 24",
-			"Month should be between 1 and 12"}},
+			"minute field '10,100': Minute should be between 0 and 59",
+			"hour field '0,50': Hour should be between 0 and 24",
+			"month field '1,25': Month should be between 1 and 12"}},
+		{"0 0 32W * *", []string{
+			"day of month field '32W': Day should be between 1 and 31"}},
+		{"0 0 L,* * *", []string{
+			"day of month field 'L,*': * cannot be combined with L or W"}},
+		{"0 0 * * 5#6", []string{
+			"day of week field '5#6': Occurrence of the weekday in 5#6 should be between 1 and 5"}},
+		{"0 0 * * L", []string{
+			"day of week field 'L': L must follow a weekday, e.g. 5L for the last Friday"}},
 	} {
 		if _, errors := Parse(test.Input); !assertErrorEquals(errors, test.Expected) {
 			t.Errorf("Got error \"%v\" for input \"%s\"", errors, test.Input)
@@ -567,3 +582,88 @@ func TestMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchQuartzTokens(t *testing.T) {
+	date := func(value string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", value)
+		return t
+	}
+
+	for _, test := range []struct {
+		Cron     string
+		Time     string
+		Expected bool
+	}{
+		// last day of the month, with leap years
+		{"0 0 L * *", "2024-02-29 00:00", true},
+		{"0 0 L * *", "2023-02-28 00:00", true},
+		{"0 0 L * *", "2024-02-28 00:00", false},
+		// last weekday of the month, 2023-09-30 is a Saturday
+		{"0 0 LW * *", "2023-09-29 00:00", true},
+		{"0 0 LW * *", "2023-09-30 00:00", false},
+		// nearest weekday, 2023-07-15 is a Saturday and 2023-07-01 a Saturday too
+		{"0 0 15W * *", "2023-07-14 00:00", true},
+		{"0 0 15W * *", "2023-07-15 00:00", false},
+		{"0 0 1W * *", "2023-07-03 00:00", true},
+		// the nearest weekday of the 30th does not leave April, 2023-04-30 is a Sunday
+		{"0 0 30W * *", "2023-04-28 00:00", true},
+		// 31W never matches a month of 30 days
+		{"0 0 31W * *", "2023-04-30 00:00", false},
+		// plain days combine with the tokens
+		{"0 0 1,L * ?", "2023-04-01 00:00", true},
+		// last Friday of the month
+		{"0 0 ? * 5L", "2023-06-30 00:00", true},
+		{"0 0 ? * FRIL", "2023-06-23 00:00", false},
+		// third Monday of the month
+		{"0 0 ? * MON#3", "2023-01-16 00:00", true},
+		{"0 0 ? * 1#3", "2023-01-09 00:00", false},
+		{"0 0 ? * 1#3,5", "2023-01-06 00:00", true},
+	} {
+		expression, errs := Parse(test.Cron)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected errors %v for %s", errs, test.Cron)
+		}
+		if got := expression.Match(date(test.Time)); got != test.Expected {
+			t.Errorf("expected %s to match %s: %t, got %t", test.Cron, test.Time, test.Expected, got)
+		}
+	}
+}
+
+func TestSetParser(t *testing.T) {
+	defer SetParser(nil)
+
+	SetParser(fixedParser{})
+	if _, errs := Default().Parse("anything"); len(errs) != 0 {
+		t.Errorf("expected the replaced parser to be used, got %v", errs)
+	}
+
+	SetParser(nil)
+	if _, errs := Default().Parse("anything"); len(errs) == 0 {
+		t.Error("expected the standard parser to be restored")
+	}
+}
+
+// fixedParser matches every minute whatever the expression
+type fixedParser struct{}
+
+func (fixedParser) Parse(string) (Matcher, []string) {
+	return Expression{}, nil
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Parse("*/5 9-17 * 1,6,12 MON-FRI")
+	}
+}
+
+func BenchmarkExpression_Match(b *testing.B) {
+	expression, _ := Parse("*/5 9-17 * 1,6,12 MON-FRI")
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		expression.Match(start.Add(time.Duration(i%525600) * time.Minute))
+	}
+}
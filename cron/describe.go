@@ -0,0 +1,188 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Describer is implemented by the recurrences which can describe when they run in plain words
+type Describer interface {
+	Describe() string
+}
+
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// Describe returns a human readable description of the expression, e.g. "At 02:30 on weekdays".
+func (expression Expression) Describe() string {
+	description := expression.describeTime()
+	for _, part := range []string{expression.describeDays(), expression.describeMonths()} {
+		if part != "" {
+			description += " " + part
+		}
+	}
+	return description
+}
+
+func (expression Expression) describeTime() string {
+	minutes, hours := toInt64(expression.Minute), toInt64(expression.Hour)
+	switch {
+	case len(minutes) == 0 && len(hours) == 0:
+		return "Every minute"
+	case len(hours) == 0:
+		return describeMinutes(minutes) + " past every hour"
+	case len(minutes) == 0 && stepOf(hours, 24) > 1:
+		return "Every minute of " + describeHours(hours)
+	case len(minutes) == 0:
+		return "Every minute during " + describeHours(hours)
+	case len(minutes) == 1 && len(hours) <= 3:
+		var times []string
+		for _, hour := range hours {
+			times = append(times, fmt.Sprintf("%02d:%02d", hour, minutes[0]))
+		}
+		return "At " + join(times)
+	default:
+		return describeMinutes(minutes) + " past " + describeHours(hours)
+	}
+}
+
+func describeMinutes(minutes []int64) string {
+	if step := stepOf(minutes, 60); step > 1 {
+		return fmt.Sprintf("Every %d minutes", step)
+	}
+	if len(minutes) == 1 {
+		return fmt.Sprintf("At minute %d", minutes[0])
+	}
+	return "At minutes " + join(numbers(minutes))
+}
+
+func describeHours(hours []int64) string {
+	if step := stepOf(hours, 24); step > 1 {
+		return fmt.Sprintf("every %d hours", step)
+	}
+	if len(hours) == 1 {
+		return fmt.Sprintf("hour %d", hours[0])
+	}
+	return "hours " + join(numbers(hours))
+}
+
+func (expression Expression) describeDays() string {
+	var days []string
+	if len(expression.Day) > 0 {
+		days = append(days, "day "+join(numbers(toInt64(expression.Day))))
+	}
+	if expression.LastDay {
+		days = append(days, "the last day")
+	}
+	if expression.LastBusinessDay {
+		days = append(days, "the last weekday")
+	}
+	for _, day := range expression.NearestBusinessDay {
+		days = append(days, fmt.Sprintf("the weekday nearest to day %d", day))
+	}
+
+	var weekdays []string
+	switch plain := toInt64(expression.Weekday); {
+	case isRange(plain, 1, 5):
+		weekdays = append(weekdays, "weekdays")
+	case len(plain) == 2 && plain[0] == 0 && plain[1] == 6, len(plain) == 2 && plain[0] == 6 && plain[1] == 0:
+		weekdays = append(weekdays, "weekends")
+	default:
+		for _, weekday := range plain {
+			weekdays = append(weekdays, time.Weekday(weekday).String())
+		}
+	}
+	for _, nth := range expression.NthWeekday {
+		weekdays = append(weekdays, fmt.Sprintf("the %s %s", ordinals[nth.N], time.Weekday(nth.Weekday)))
+	}
+	for _, weekday := range expression.LastWeekday {
+		weekdays = append(weekdays, "the last "+time.Weekday(weekday).String())
+	}
+
+	switch {
+	case len(days) > 0 && len(weekdays) > 0:
+		return "on " + join(days) + " of the month if it falls on " + join(weekdays)
+	case len(days) > 0:
+		return "on " + join(days) + " of the month"
+	case len(weekdays) > 0:
+		return "on " + join(weekdays)
+	default:
+		return ""
+	}
+}
+
+func (expression Expression) describeMonths() string {
+	if len(expression.Month) == 0 {
+		return ""
+	}
+	var months []string
+	for _, month := range toInt64(expression.Month) {
+		months = append(months, time.Month(month).String())
+	}
+	return "in " + join(months)
+}
+
+// stepOf returns the step of values starting at 0 and evenly spaced upto the range, 0 for other values
+func stepOf(values []int64, _range int64) int64 {
+	if len(values) < 2 || values[0] != 0 {
+		return 0
+	}
+	step := values[1] - values[0]
+	for i := 1; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0
+		}
+	}
+	if values[len(values)-1]+step < _range {
+		return 0
+	}
+	return step
+}
+
+// isRange tells whether the values are exactly the ones from start to end
+func isRange(values []int64, start, end int64) bool {
+	if int64(len(values)) != end-start+1 {
+		return false
+	}
+	for i, value := range values {
+		if value != start+int64(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func numbers(values []int64) []string {
+	var list []string
+	for _, value := range values {
+		list = append(list, fmt.Sprint(value))
+	}
+	return list
+}
+
+// join joins the items of a list as in "a, b and c"
+func join(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
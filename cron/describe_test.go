@@ -0,0 +1,53 @@
+package cron
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	for _, test := range []struct {
+		expression  string
+		description string
+	}{
+		{"* * * * *", "Every minute"},
+		{"*/15 * * * *", "Every 15 minutes past every hour"},
+		{"@hourly", "At minute 0 past every hour"},
+		{"@daily", "At 00:00"},
+		{"@weekly", "At 00:00 on Sunday"},
+		{"@yearly", "At 00:00 on day 1 of the month in January"},
+		{"30 2 * * MON-FRI", "At 02:30 on weekdays"},
+		{"0 9,17 * * sat,sun", "At 09:00 and 17:00 on weekends"},
+		{"0 */2 * * *", "At minute 0 past every 2 hours"},
+		{"* 9 * * *", "Every minute during hour 9"},
+		{"0,20 9-12 * * *", "At minutes 0 and 20 past hours 9, 10, 11 and 12"},
+		{"0,30 9-12 * * *", "Every 30 minutes past hours 9, 10, 11 and 12"},
+		{"0 8 L * *", "At 08:00 on the last day of the month"},
+		{"0 8 15W * *", "At 08:00 on the weekday nearest to day 15 of the month"},
+		{"0 8 * * 5#3", "At 08:00 on the third Friday"},
+		{"0 8 * * 5L", "At 08:00 on the last Friday"},
+		{"0 8 13 * FRI", "At 08:00 on day 13 of the month if it falls on Friday"},
+		{"0 0 1 JAN,JUL *", "At 00:00 on day 1 of the month in January and July"},
+	} {
+		expression, errs := Parse(test.expression)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected errors %v for %s", errs, test.expression)
+		}
+		if description := expression.Describe(); description != test.description {
+			t.Errorf("expected %q for %s, got %q", test.description, test.expression, description)
+		}
+	}
+}
+
+func TestParseMacros(t *testing.T) {
+	for macro, expanded := range Macros {
+		expression, errs := Parse(macro)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected errors %v for %s", errs, macro)
+		}
+		expected, _ := Parse(expanded)
+		if expression.Describe() != expected.Describe() {
+			t.Errorf("expected %s to parse as %s", macro, expanded)
+		}
+	}
+	if _, errs := Parse("@sometimes"); len(errs) == 0 {
+		t.Errorf("expected an unknown macro to fail")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Interval represents a recurrence which is active every fixed duration starting from an anchor time.
+// Unlike a cron Expression, an Interval can express periods such as "every 90 minutes" which don't divide an hour or a day.
+type Interval struct {
+	Every  time.Duration
+	Anchor time.Time
+}
+
+// Parse a duration string such as "90m" or "2h30m" to an Interval starting at the anchor time.
+// The anchor is truncated to the minute since schedules are created with minute granularity.
+//
+// Returns a non empty list of error messages if,
+//   - the string cannot be parsed to a duration.
+//   - the duration is less than a minute.
+//   - the duration is not a whole number of minutes.
+func ParseInterval(every string, anchor time.Time) (Interval, []string) {
+	duration, err := time.ParseDuration(every)
+	if err != nil {
+		return Interval{}, []string{fmt.Sprintf("Cannot parse interval %s, expected a duration such as \"90m\" or \"2h\"", every)}
+	}
+
+	var errors []string
+	if duration < time.Minute {
+		errors = append(errors, fmt.Sprintf("Interval %s should be at least a minute", every))
+	}
+
+	if duration%time.Minute != 0 {
+		errors = append(errors, fmt.Sprintf("Interval %s should be a whole number of minutes", every))
+	}
+
+	if len(errors) != 0 {
+		return Interval{}, errors
+	}
+
+	return Interval{Every: duration, Anchor: anchor.Truncate(time.Minute)}, nil
+}
+
+// Check if the interval is active at the time provided.
+// The match is true if the time is at or after the anchor and is a whole number of intervals away from it.
+func (interval Interval) Match(time time.Time) bool {
+	if time.Before(interval.Anchor) {
+		return false
+	}
+
+	return time.Sub(interval.Anchor)%interval.Every == 0
+}
+
+// Next returns the first time strictly after the time provided at which the interval is active.
+func (interval Interval) Next(after time.Time) (time.Time, bool) {
+	if after.Before(interval.Anchor) {
+		return interval.Anchor, true
+	}
+
+	return interval.Anchor.Add((after.Sub(interval.Anchor)/interval.Every + 1) * interval.Every), true
+}
+
+// Describe returns a human readable description of the interval, e.g. "Every 1h30m from 2024-01-01 09:00 UTC".
+func (interval Interval) Describe() string {
+	every := strings.TrimSuffix(interval.Every.String(), "0s")
+	if strings.HasSuffix(every, "h0m") {
+		every = strings.TrimSuffix(every, "0m")
+	}
+	return fmt.Sprintf("Every %s from %s", every, interval.Anchor.UTC().Format("2006-01-02 15:04 UTC"))
+}
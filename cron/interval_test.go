@@ -0,0 +1,93 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	anchor := time.Date(2023, 6, 1, 10, 0, 30, 0, time.UTC)
+
+	for _, test := range []struct {
+		Input    string
+		Every    time.Duration
+		HasError bool
+	}{
+		{"90m", 90 * time.Minute, false},
+		{"2h", 2 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"30s", 0, true},
+		{"90s", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	} {
+		interval, errs := ParseInterval(test.Input, anchor)
+
+		if test.HasError != (len(errs) != 0) {
+			t.Errorf("Input: %s, expected error: %t, got errors: %v", test.Input, test.HasError, errs)
+			continue
+		}
+
+		if interval.Every != test.Every {
+			t.Errorf("Input: %s, expected every: %v, got: %v", test.Input, test.Every, interval.Every)
+		}
+
+		if !test.HasError && !interval.Anchor.Equal(anchor.Truncate(time.Minute)) {
+			t.Errorf("Input: %s, expected anchor truncated to the minute, got: %v", test.Input, interval.Anchor)
+		}
+	}
+}
+
+func TestIntervalMatch(t *testing.T) {
+	anchor := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	interval := Interval{Every: 90 * time.Minute, Anchor: anchor}
+
+	for _, test := range []struct {
+		Time     time.Time
+		Expected bool
+	}{
+		{anchor, true},
+		{anchor.Add(90 * time.Minute), true},
+		{anchor.Add(180 * time.Minute), true},
+		{anchor.Add(60 * time.Minute), false},
+		{anchor.Add(-90 * time.Minute), false},
+		{anchor.Add(24 * time.Hour), true},
+	} {
+		if output := interval.Match(test.Time); output != test.Expected {
+			t.Errorf("Time: %v, expected: %t, got: %t", test.Time, test.Expected, output)
+		}
+	}
+}
+
+func TestIntervalDescribe(t *testing.T) {
+	anchor := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	for every, description := range map[string]string{
+		"90m": "Every 1h30m from 2023-06-01 10:00 UTC",
+		"2h":  "Every 2h from 2023-06-01 10:00 UTC",
+		"15m": "Every 15m from 2023-06-01 10:00 UTC",
+	} {
+		interval, _ := ParseInterval(every, anchor)
+		if got := interval.Describe(); got != description {
+			t.Errorf("expected %q for %s, got %q", description, every, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// Matcher tells whether a parsed cron expression is active at the minute of the time provided
+type Matcher interface {
+	Match(time time.Time) bool
+}
+
+// Parser parses the cron expressions of the recurring schedules and the blackout windows.
+// A non empty list of error messages is returned if the expression cannot be parsed.
+type Parser interface {
+	Parse(expression string) (Matcher, []string)
+}
+
+// StandardParser parses five field cron expressions along with the Quartz style tokens supported by Parse
+type StandardParser struct{}
+
+func (StandardParser) Parse(s string) (Matcher, []string) {
+	expression, errs := Parse(s)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+	return expression, nil
+}
+
+var parser = struct {
+	sync.RWMutex
+	parser Parser
+}{parser: StandardParser{}}
+
+// SetParser replaces the parser of the cron expressions, nil restores the standard parser.
+// It is meant to be called before the scheduler starts, as the expressions stored earlier are parsed again by it.
+func SetParser(p Parser) {
+	if p == nil {
+		p = StandardParser{}
+	}
+	parser.Lock()
+	defer parser.Unlock()
+	parser.parser = p
+}
+
+// Default returns the parser of the cron expressions
+func Default() Parser {
+	parser.RLock()
+	defer parser.RUnlock()
+	return parser.parser
+}
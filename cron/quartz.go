@@ -0,0 +1,228 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NthWeekday represents the nth occurrence of a weekday within a month, e.g. the third Friday.
+// Allowed value of N should be within range [1-5]
+type NthWeekday struct {
+	Weekday Weekday
+	N       int
+}
+
+// parseDayField parses the day field of an expression, with the L, LW and 15W tokens along with the plain values.
+// Returns a non empty string error message if the field cannot be parsed.
+func (expression *Expression) parseDayField(s string) string {
+	if s == "?" {
+		s = "*"
+	}
+
+	var plain []string
+	for _, part := range strings.Split(s, ",") {
+		switch upper := strings.ToUpper(part); {
+		case upper == "L":
+			expression.LastDay = true
+		case upper == "LW":
+			expression.LastBusinessDay = true
+		case len(upper) > 1 && strings.HasSuffix(upper, "W"):
+			switch day, err := strconv.ParseInt(upper[:len(upper)-1], 10, 64); {
+			case err != nil:
+				return fmt.Sprintf("Cannot parse %s to a nearest weekday", part)
+			case day < 1 || day > 31:
+				return "Day should be between 1 and 31"
+			default:
+				expression.NearestBusinessDay = append(expression.NearestBusinessDay, Day(day))
+			}
+		default:
+			plain = append(plain, part)
+		}
+	}
+
+	if expression.hasDayTokens() && containsWildcard(plain) {
+		return "* cannot be combined with L or W"
+	}
+	if len(plain) == 0 {
+		return ""
+	}
+
+	days, err := ParseDay(strings.Join(plain, ","))
+	expression.Day = days
+	return err
+}
+
+// parseWeekdayField parses the weekday field of an expression, with the 5L and 5#3 tokens along with the plain
+// values. Returns a non empty string error message if the field cannot be parsed.
+func (expression *Expression) parseWeekdayField(s string) string {
+	if s == "?" {
+		s = "*"
+	}
+
+	single := func(s string) (Weekday, string) {
+		weekdays, err := ParseWeekday(s)
+		switch {
+		case len(err) != 0:
+			return 0, err
+		case len(weekdays) != 1:
+			return 0, fmt.Sprintf("Cannot parse %s to a single weekday", s)
+		default:
+			return weekdays[0], ""
+		}
+	}
+
+	var plain []string
+	for _, part := range strings.Split(s, ",") {
+		switch upper := strings.ToUpper(part); {
+		case upper == "L":
+			return "L must follow a weekday, e.g. 5L for the last Friday"
+		case strings.Contains(upper, "#"):
+			tokens := strings.Split(upper, "#")
+			if len(tokens) != 2 {
+				return fmt.Sprintf("Invalid cron format %s", part)
+			}
+			weekday, err := single(tokens[0])
+			if len(err) != 0 {
+				return err
+			}
+			n, parseErr := strconv.Atoi(tokens[1])
+			if parseErr != nil || n < 1 || n > 5 {
+				return fmt.Sprintf("Occurrence of the weekday in %s should be between 1 and 5", part)
+			}
+			expression.NthWeekday = append(expression.NthWeekday, NthWeekday{Weekday: weekday, N: n})
+		case len(upper) > 1 && strings.HasSuffix(upper, "L"):
+			weekday, err := single(upper[:len(upper)-1])
+			if len(err) != 0 {
+				return err
+			}
+			expression.LastWeekday = append(expression.LastWeekday, weekday)
+		default:
+			plain = append(plain, part)
+		}
+	}
+
+	if expression.hasWeekdayTokens() && containsWildcard(plain) {
+		return "* cannot be combined with L or #"
+	}
+	if len(plain) == 0 {
+		return ""
+	}
+
+	weekdays, err := ParseWeekday(strings.Join(plain, ","))
+	expression.Weekday = weekdays
+	return err
+}
+
+func containsWildcard(parts []string) bool {
+	for _, part := range parts {
+		if part == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (expression Expression) hasDayTokens() bool {
+	return expression.LastDay || expression.LastBusinessDay || len(expression.NearestBusinessDay) > 0
+}
+
+func (expression Expression) hasWeekdayTokens() bool {
+	return len(expression.LastWeekday) > 0 || len(expression.NthWeekday) > 0
+}
+
+// matchDay checks the day of the time against the plain values and the tokens of the day field
+func (expression Expression) matchDay(t time.Time, contains func([]int64, int64) bool) bool {
+	if !expression.hasDayTokens() {
+		return contains(toInt64(expression.Day), int64(t.Day()))
+	}
+
+	day, last := t.Day(), daysIn(t)
+	if expression.LastDay && day == last {
+		return true
+	}
+	if expression.LastBusinessDay && day == nearestBusinessDay(t, last, last) {
+		return true
+	}
+	for _, nearest := range expression.NearestBusinessDay {
+		if int(nearest) <= last && day == nearestBusinessDay(t, int(nearest), last) {
+			return true
+		}
+	}
+	for _, value := range expression.Day {
+		if int(value) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWeekday checks the weekday of the time against the plain values and the tokens of the weekday field
+func (expression Expression) matchWeekday(t time.Time, contains func([]int64, int64) bool) bool {
+	if !expression.hasWeekdayTokens() {
+		return contains(toInt64(expression.Weekday), int64(t.Weekday()))
+	}
+
+	weekday := Weekday(t.Weekday())
+	for _, last := range expression.LastWeekday {
+		if last == weekday && t.Day()+7 > daysIn(t) {
+			return true
+		}
+	}
+	for _, nth := range expression.NthWeekday {
+		if nth.Weekday == weekday && (t.Day()-1)/7+1 == nth.N {
+			return true
+		}
+	}
+	for _, value := range expression.Weekday {
+		if value == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// daysIn returns the number of days in the month of the time
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// nearestBusinessDay returns the day from Monday to Friday nearest to the day of the month of the time, without
+// leaving the month: a Saturday moves to the Friday before unless it is the 1st, and a Sunday to the Monday after
+// unless it is the last day.
+func nearestBusinessDay(t time.Time, day int, last int) int {
+	switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, t.Location()).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
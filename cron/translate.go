@@ -0,0 +1,260 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FromQuartz translates a Quartz cron expression, whose fields are the seconds, minute, hour, day of month, month,
+// day of week and an optional year, to the five field expression of the schedules. Quartz numbers the weekdays from 1
+// (Sunday) to 7 (Saturday) and starts the steps such as 5/15 from their first value, both are rewritten to the
+// numbering and the steps of Parse. The warnings tell what the translation changes in the firing times, the non empty
+// list of error messages what cannot be translated.
+func FromQuartz(s string) (string, []string, []string) {
+	parts := strings.Fields(s)
+	if len(parts) != 6 && len(parts) != 7 {
+		return "", nil, []string{"Quartz expression should have 6 or 7 fields, \"s m h dom mon dow [year]\""}
+	}
+
+	var warnings, errors []string
+	switch second, err := strconv.Atoi(parts[0]); {
+	case err != nil || second < 0 || second > 59:
+		errors = append(errors, fmt.Sprintf("seconds field '%s': schedules fire once a minute at most, expected a single second", parts[0]))
+	case second != 0:
+		warnings = append(warnings, fmt.Sprintf("fires at the start of the minute instead of second %d", second))
+	}
+	if len(parts) == 7 && parts[6] != "*" && parts[6] != "?" {
+		errors = append(errors, fmt.Sprintf("year field '%s': years are not supported", parts[6]))
+	}
+
+	fieldError := func(field, value, err string) string {
+		return fmt.Sprintf("%s field '%s': %s", fieldNames[field], value, err)
+	}
+	translate := func(field, value string, translate func(string) (string, string)) string {
+		translated, err := translate(value)
+		if len(err) != 0 {
+			errors = append(errors, fieldError(field, value, err))
+		}
+		return translated
+	}
+
+	fields := []string{
+		translate(MinuteField, parts[1], func(s string) (string, string) { return quartzField(s, 0, 59, nil, true) }),
+		translate(HourField, parts[2], func(s string) (string, string) { return quartzField(s, 0, 23, nil, true) }),
+		translate(DayOfMonthField, parts[3], quartzDayField),
+		translate(MonthField, parts[4], func(s string) (string, string) { return quartzField(s, 1, 12, monthNames, false) }),
+		translate(DayOfWeekField, parts[5], quartzWeekdayField),
+	}
+	if len(errors) != 0 {
+		return "", warnings, errors
+	}
+
+	expression := strings.Join(fields, " ")
+	if _, errs := Parse(expression); len(errs) != 0 {
+		return "", warnings, errs
+	}
+	return expression, warnings, nil
+}
+
+// quartzField translates the values, ranges and steps of a field. A step over the whole field such as */15 is kept
+// when Parse starts it from the same value, the other steps are expanded to their values.
+func quartzField(s string, min, max int, names []string, keepStep bool) (string, string) {
+	if s == "*" || s == "?" {
+		return "*", ""
+	}
+	if keepStep && strings.HasPrefix(s, "*/") && !strings.Contains(s, ",") {
+		return s, ""
+	}
+
+	var translated []string
+	for _, part := range strings.Split(s, ",") {
+		if !strings.Contains(part, "/") {
+			translated = append(translated, part)
+			continue
+		}
+		values, err := quartzValues(quartzNames(part, names, min), min, max)
+		if len(err) != 0 {
+			return "", err
+		}
+		translated = append(translated, joinValues(values, 0))
+	}
+	return strings.Join(translated, ","), ""
+}
+
+// quartzDayField translates the day of month field, whose L, LW and 15W tokens are the same as the ones of Parse
+func quartzDayField(s string) (string, string) {
+	if strings.Contains(strings.ToUpper(s), "L-") {
+		return "", "offsets from the last day of the month such as L-3 are not supported"
+	}
+
+	var translated []string
+	for _, part := range strings.Split(s, ",") {
+		if upper := strings.ToUpper(part); upper == "L" || upper == "LW" || strings.HasSuffix(upper, "W") {
+			translated = append(translated, part)
+			continue
+		}
+		field, err := quartzField(part, 1, 31, nil, false)
+		if len(err) != 0 {
+			return "", err
+		}
+		translated = append(translated, field)
+	}
+	return strings.Join(translated, ","), ""
+}
+
+// quartzWeekdayField translates the day of week field from the 1 (Sunday) to 7 (Saturday) numbering of Quartz, along
+// with its 6L and 6#3 tokens, while a lone L stands for Saturday
+func quartzWeekdayField(s string) (string, string) {
+	if s == "*" || s == "?" {
+		return "*", ""
+	}
+
+	weekday := func(s string) (int, string) {
+		switch values, err := quartzValues(quartzNames(s, weekdayNames, 1), 1, 7); {
+		case len(err) != 0:
+			return 0, err
+		case len(values) != 1:
+			return 0, fmt.Sprintf("Cannot parse %s to a single weekday", s)
+		default:
+			return values[0] - 1, ""
+		}
+	}
+
+	var translated []string
+	for _, part := range strings.Split(s, ",") {
+		switch upper := strings.ToUpper(part); {
+		case upper == "L":
+			translated = append(translated, "6")
+		case strings.Contains(upper, "#"):
+			tokens := strings.SplitN(upper, "#", 2)
+			value, err := weekday(tokens[0])
+			if len(err) != 0 {
+				return "", err
+			}
+			translated = append(translated, fmt.Sprintf("%d#%s", value, tokens[1]))
+		case len(upper) > 1 && strings.HasSuffix(upper, "L"):
+			value, err := weekday(upper[:len(upper)-1])
+			if len(err) != 0 {
+				return "", err
+			}
+			translated = append(translated, fmt.Sprintf("%dL", value))
+		default:
+			values, err := quartzValues(quartzNames(part, weekdayNames, 1), 1, 7)
+			if len(err) != 0 {
+				return "", err
+			}
+			translated = append(translated, joinValues(values, -1))
+		}
+	}
+	return strings.Join(translated, ","), ""
+}
+
+// quartzNames replaces the names of a value, a range or the start of a step by their numbers
+func quartzNames(part string, names []string, first int) string {
+	if names == nil {
+		return part
+	}
+	tokens := strings.SplitN(part, "/", 2)
+	tokens[0] = replaceNames(tokens[0], names, first)
+	return strings.Join(tokens, "/")
+}
+
+// quartzValues returns the values of a single value, a range or a step of a field, a range such as FRI-MON wrapping
+// around the end of the field. A step such as 5/15 starts from its first value and * stands for the first value of
+// the field.
+func quartzValues(s string, min, max int) ([]int, string) {
+	parse := func(s string) (int, string) {
+		value, err := strconv.Atoi(s)
+		switch {
+		case err != nil:
+			return 0, fmt.Sprintf("Cannot parse %s to int", s)
+		case value < min || value > max:
+			return 0, fmt.Sprintf("Value %d should be between %d and %d", value, min, max)
+		default:
+			return value, ""
+		}
+	}
+
+	increment, start, end, err := 1, min, max, ""
+	tokens := strings.Split(s, "/")
+	if len(tokens) == 2 {
+		if increment, _ = strconv.Atoi(tokens[1]); increment < 1 {
+			return nil, fmt.Sprintf("Cannot parse step value from %s", s)
+		}
+		s = tokens[0]
+	} else if len(tokens) > 2 {
+		return nil, fmt.Sprintf("Invalid cron format %s", s)
+	}
+
+	switch bounds := strings.Split(s, "-"); {
+	case s == "*":
+	case len(bounds) == 2:
+		if start, err = parse(bounds[0]); len(err) != 0 {
+			return nil, err
+		}
+		if end, err = parse(bounds[1]); len(err) != 0 {
+			return nil, err
+		}
+	case len(bounds) == 1:
+		if start, err = parse(s); len(err) != 0 {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			end = start
+		}
+	default:
+		return nil, fmt.Sprintf("Invalid cron format %s", s)
+	}
+
+	size, length := max-min+1, end-start+1
+	if start > end {
+		length += size
+	}
+
+	var values []int
+	for i := 0; i < length; i += increment {
+		value := start + i
+		if value > max {
+			value -= size
+		}
+		values = append(values, value)
+	}
+	return values, ""
+}
+
+// joinValues renders the values shifted by the offset, as a range when they are consecutive
+func joinValues(values []int, offset int) string {
+	consecutive := len(values) > 2
+	for i := 1; i < len(values); i++ {
+		consecutive = consecutive && values[i] == values[i-1]+1
+	}
+	if consecutive {
+		return fmt.Sprintf("%d-%d", values[0]+offset, values[len(values)-1]+offset)
+	}
+
+	rendered := make([]string, len(values))
+	for i, value := range values {
+		rendered[i] = strconv.Itoa(value + offset)
+	}
+	return strings.Join(rendered, ",")
+}
@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromQuartz(t *testing.T) {
+	for _, test := range []struct {
+		quartz     string
+		expression string
+		warnings   int
+	}{
+		{"0 0 12 * * ?", "0 12 * * *", 0},
+		{"0 */15 * ? * *", "*/15 * * * *", 0},
+		{"0 5/15 * * * ?", "5,20,35,50 * * * *", 0},
+		{"0 0 8 ? * MON-FRI", "0 8 * * 1-5", 0},
+		{"0 0 8 ? * 2-6", "0 8 * * 1-5", 0},
+		{"0 0 8 ? * 1,7", "0 8 * * 0,6", 0},
+		{"0 0 8 ? * FRI-MON", "0 8 * * 5,6,0,1", 0},
+		{"0 0 8 ? * 6#3", "0 8 * * 5#3", 0},
+		{"0 0 8 ? * 6L", "0 8 * * 5L", 0},
+		{"0 0 8 ? * L", "0 8 * * 6", 0},
+		{"0 0 8 L * ?", "0 8 L * *", 0},
+		{"0 0 8 15W * ?", "0 8 15W * *", 0},
+		{"0 0 0 1/10 * ?", "0 0 1,11,21,31 * *", 0},
+		{"0 0 0 1 1/3 ?", "0 0 1 1,4,7,10 *", 0},
+		{"0 0 0 1 JAN/6 ?", "0 0 1 1,7 *", 0},
+		{"0 0 0 * * ? *", "0 0 * * *", 0},
+		{"30 0 0 * * ?", "0 0 * * *", 1},
+	} {
+		expression, warnings, errs := FromQuartz(test.quartz)
+		if len(errs) != 0 {
+			t.Fatalf("unexpected errors %v for %s", errs, test.quartz)
+		}
+		if expression != test.expression {
+			t.Errorf("expected %q for %s, got %q", test.expression, test.quartz, expression)
+		}
+		if len(warnings) != test.warnings {
+			t.Errorf("expected %d warnings for %s, got %v", test.warnings, test.quartz, warnings)
+		}
+	}
+}
+
+func TestFromQuartzErrors(t *testing.T) {
+	for _, test := range []struct {
+		quartz string
+		err    string
+	}{
+		{"0 12 * * *", "6 or 7 fields"},
+		{"*/5 * * * * ?", "seconds field '*/5'"},
+		{"0 0 0 * * ? 2030", "year field '2030'"},
+		{"0 0 0 L-3 * ?", "day of month field 'L-3'"},
+		{"0 0 0 ? * 8", "day of week field '8'"},
+		{"0 61 0 * * ?", "minute field '61'"},
+		{"0 0 0 ? * 6#6", "day of week field '5#6'"},
+	} {
+		_, _, errs := FromQuartz(test.quartz)
+		if len(errs) == 0 || !strings.Contains(strings.Join(errs, ";"), test.err) {
+			t.Errorf("expected an error containing %q for %s, got %v", test.err, test.quartz, errs)
+		}
+	}
+}
@@ -20,6 +20,9 @@
 package dao
 
 import (
+	"time"
+
+	"github.com/gocql/gocql"
 	e "github.com/myntra/goscheduler/cluster_entity"
 	"github.com/myntra/goscheduler/store"
 )
@@ -35,10 +38,41 @@ type ClusterDao interface {
 	InsertApp(app store.App) error
 	CreateEntity(info e.EntityInfo) error
 	UpdateAppActiveStatus(appName string, activeStatus bool) error
+	UpdateAppOffboarding(appName string, offboarding *store.Offboarding) error
+	DeleteApp(app store.App) error
 	GetApps(appId string) ([]store.App, error)
 	GetDCAwareApp(appName string) (store.App, error)
 	CreateConfigurations(appId string, configuration store.Configuration) (store.Configuration, error)
 	GetConfiguration(appId string) (store.Configuration, error)
 	UpdateConfiguration(appId string, configuration store.Configuration) (store.Configuration, error)
 	DeleteConfiguration(appId string) (store.Configuration, error)
+	UpdateAppPartitions(appName string, partitions uint32) error
+	UpsertPartitionMigration(migration store.PartitionMigration) error
+	GetPartitionMigration(appName string) (store.PartitionMigration, error)
+	GetReplicationRole(clusterName string) (string, error)
+	UpdateReplicationRole(clusterName string, role string) error
+	IncrementUsage(usage store.Usage) error
+	GetUsage(appId string, from time.Time, to time.Time) ([]store.Usage, error)
+	IncrementRunStats(stats store.RunStats) error
+	GetRunStats(appId string, from time.Time, to time.Time) ([]store.RunStats, error)
+	CreateCallbackTemplate(template store.CallbackTemplate) error
+	GetCallbackTemplate(appId string, name string, version int) (store.CallbackTemplate, error)
+	GetCallbackTemplates(appId string) ([]store.CallbackTemplate, error)
+	CreateScheduleGroup(group store.ScheduleGroup) (bool, error)
+	UpdateScheduleGroup(group store.ScheduleGroup) (bool, error)
+	GetScheduleGroup(appId string, name string) (store.ScheduleGroup, error)
+	GetScheduleGroups(appId string) ([]store.ScheduleGroup, error)
+	DeleteScheduleGroup(appId string, name string) error
+	IncrementGroupRuns(appId string, name string, minute time.Time) (int64, error)
+	CreateApproval(approval store.Approval, ttl int) error
+	GetApproval(appId string, approvalId gocql.UUID) (store.Approval, error)
+	GetApprovals(appId string) ([]store.Approval, error)
+	UpdateApproval(approval store.Approval, from store.ApprovalStatus, ttl int) (bool, error)
+	CreateVerifiedUrl(verified store.VerifiedUrl) error
+	GetVerifiedUrl(appId string, url string) (store.VerifiedUrl, error)
+	GetVerifiedUrls(appId string) ([]store.VerifiedUrl, error)
+	DeleteVerifiedUrl(appId string, url string) error
+	CordonNode(clusterName string, node store.CordonedNode) error
+	GetCordonedNodes(clusterName string) ([]store.CordonedNode, error)
+	UncordonNode(clusterName string, node string) error
 }
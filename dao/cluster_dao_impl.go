@@ -26,14 +26,16 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/golang/glog"
+	"github.com/gocql/gocql"
 	"github.com/imdario/mergo"
 	"github.com/myntra/goscheduler/cassandra"
 	e "github.com/myntra/goscheduler/cluster_entity"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
 	"github.com/myntra/goscheduler/store"
 )
@@ -62,22 +64,87 @@ var (
 	KeyUpdateEntityInfo     = "UPDATE " + KeyEntityTable + " SET nodename='%s', status=%d, history='%s' WHERE id='%s';"
 	QueryInsertEntity       = "INSERT INTO " + KeyEntityTable + " (id, nodename, status) VALUES (?, ?, ?)"
 	QueryInsertApp          = "INSERT INTO " + KeyAppTable + " (id, partitions, active, configuration) VALUES (?, ?, ?, ?)"
-	KeyAppById              = "SELECT id, partitions, active, configuration FROM " + KeyAppTable + " WHERE id='%s';"
-	KeyAppByIds             = "SELECT id, partitions, active, configuration FROM " + KeyAppTable + " WHERE id in (?, ?);"
-	KeyGelAllApps           = "SELECT id, partitions, active, configuration FROM " + KeyAppTable + ";"
+	KeyAppById              = "SELECT id, partitions, active, configuration, offboarding FROM " + KeyAppTable + " WHERE id='%s';"
+	KeyAppByIds             = "SELECT id, partitions, active, configuration, offboarding FROM " + KeyAppTable + " WHERE id in (?, ?);"
+	KeyGelAllApps           = "SELECT id, partitions, active, configuration, offboarding FROM " + KeyAppTable + ";"
 	QueryUpdateAppStatus    = "UPDATE " + KeyAppTable + " set active = %s where id='%s'"
 	QueryGetConfig          = "SELECT configuration FROM " + KeyAppTable + " WHERE id='%s';"
 	QueryUpdateConfig       = "UPDATE " + KeyAppTable + " SET configuration='%s' WHERE id='%s';"
 	KeyGetAllEntitiesForApp = "SELECT id, nodename, status, history FROM " + KeyEntityTable + " WHERE id in %s;"
+	QueryUpdateOffboarding  = "UPDATE " + KeyAppTable + " SET offboarding = ? WHERE id = ?"
+	QueryDeleteApp          = "DELETE FROM " + KeyAppTable + " WHERE id = ?"
+	QueryDeleteEntity       = "DELETE FROM " + KeyEntityTable + " WHERE id = ?"
+
+	KeyMigrationTable        = "partition_migrations"
+	QueryUpdateAppPartitions = "UPDATE " + KeyAppTable + " SET partitions = ? WHERE id = ?"
+	QueryUpsertMigration     = "INSERT INTO " + KeyMigrationTable + " (app_id, from_partitions, to_partitions, status, migrated, cursor, started_at, updated_at, error) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	KeyMigrationByApp        = "SELECT app_id, from_partitions, to_partitions, status, migrated, cursor, started_at, updated_at, error FROM " + KeyMigrationTable + " WHERE app_id = ?"
+	QueryDeleteMigration     = "DELETE FROM " + KeyMigrationTable + " WHERE app_id = ?"
+
+	KeyReplicationTable         = "replication_state"
+	QueryUpdateReplicationRole  = "INSERT INTO " + KeyReplicationTable + " (cluster_name, role, updated_at) VALUES (?, ?, ?)"
+	KeyReplicationRoleByCluster = "SELECT role FROM " + KeyReplicationTable + " WHERE cluster_name = ?"
+
+	KeyUsageTable       = "app_usage"
+	QueryIncrementUsage = "UPDATE " + KeyUsageTable + " SET schedules_created = schedules_created + ?, callbacks_fired = callbacks_fired + ?, bytes_delivered = bytes_delivered + ?, retries = retries + ? WHERE app_id = ? AND day = ?"
+	KeyUsageByAppAndDay = "SELECT day, schedules_created, callbacks_fired, bytes_delivered, retries FROM " + KeyUsageTable + " WHERE app_id = ? AND day >= ? AND day <= ?"
+	QueryDeleteUsage    = "DELETE FROM " + KeyUsageTable + " WHERE app_id = ?"
+
+	KeyRunStatsTable        = "app_run_stats"
+	QueryIncrementRunStats  = "UPDATE " + KeyRunStatsTable + " SET successes = successes + ?, failures = failures + ?, retries = retries + ? WHERE app_id = ? AND hour = ?"
+	KeyRunStatsByAppAndHour = "SELECT hour, successes, failures, retries FROM " + KeyRunStatsTable + " WHERE app_id = ? AND hour >= ? AND hour <= ?"
+	QueryDeleteRunStats     = "DELETE FROM " + KeyRunStatsTable + " WHERE app_id = ?"
+
+	KeyTemplateTable        = "callback_templates"
+	QueryInsertTemplate     = "INSERT INTO " + KeyTemplateTable + " (app_id, name, version, callback, created_at) VALUES (?, ?, ?, ?, ?)"
+	KeyLatestTemplateByName = "SELECT app_id, name, version, callback, created_at FROM " + KeyTemplateTable + " WHERE app_id = ? AND name = ? LIMIT 1"
+	KeyTemplateByVersion    = "SELECT app_id, name, version, callback, created_at FROM " + KeyTemplateTable + " WHERE app_id = ? AND name = ? AND version = ?"
+	KeyTemplatesByApp       = "SELECT app_id, name, version, callback, created_at FROM " + KeyTemplateTable + " WHERE app_id = ?"
+	QueryDeleteTemplates    = "DELETE FROM " + KeyTemplateTable + " WHERE app_id = ?"
+
+	KeyGroupTable          = "schedule_groups"
+	QueryInsertGroup       = "INSERT INTO " + KeyGroupTable + " (app_id, name, description, max_runs_per_minute, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?) IF NOT EXISTS"
+	QueryUpdateGroup       = "UPDATE " + KeyGroupTable + " SET description = ?, max_runs_per_minute = ?, updated_at = ? WHERE app_id = ? AND name = ? IF EXISTS"
+	KeyGroupByName         = "SELECT app_id, name, description, max_runs_per_minute, created_at, updated_at FROM " + KeyGroupTable + " WHERE app_id = ? AND name = ?"
+	KeyGroupsByApp         = "SELECT app_id, name, description, max_runs_per_minute, created_at, updated_at FROM " + KeyGroupTable + " WHERE app_id = ?"
+	QueryDeleteGroup       = "DELETE FROM " + KeyGroupTable + " WHERE app_id = ? AND name = ?"
+	QueryDeleteGroups      = "DELETE FROM " + KeyGroupTable + " WHERE app_id = ?"
+	KeyGroupRunsTable      = "schedule_group_runs"
+	QueryIncrementGroupRun = "UPDATE " + KeyGroupRunsTable + " SET runs = runs + 1 WHERE app_id = ? AND name = ? AND minute = ?"
+	KeyGroupRunsByMinute   = "SELECT runs FROM " + KeyGroupRunsTable + " WHERE app_id = ? AND name = ? AND minute = ?"
+	QueryDeleteGroupRuns   = "DELETE FROM " + KeyGroupRunsTable + " WHERE app_id = ? AND name = ?"
+
+	KeyApprovalTable    = "approvals"
+	approvalColumns     = "app_id, approval_id, operation, request, status, requested_by, requested_at, expires_at, decided_by, decided_at, result, events"
+	QueryInsertApproval = "INSERT INTO " + KeyApprovalTable + " (" + approvalColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+	QueryUpdateApproval = "UPDATE " + KeyApprovalTable + " USING TTL ? SET status = ?, decided_by = ?, decided_at = ?, result = ?, events = ? WHERE app_id = ? AND approval_id = ? IF status = ?"
+	KeyApprovalById     = "SELECT " + approvalColumns + " FROM " + KeyApprovalTable + " WHERE app_id = ? AND approval_id = ?"
+	KeyApprovalsByApp   = "SELECT " + approvalColumns + " FROM " + KeyApprovalTable + " WHERE app_id = ?"
+
+	KeyVerifiedUrlTable     = "verified_urls"
+	QueryInsertVerifiedUrl  = "INSERT INTO " + KeyVerifiedUrlTable + " (app_id, url, verified_at) VALUES (?, ?, ?)"
+	KeyVerifiedUrl          = "SELECT app_id, url, verified_at FROM " + KeyVerifiedUrlTable + " WHERE app_id = ? AND url = ?"
+	KeyVerifiedUrlsByApp    = "SELECT app_id, url, verified_at FROM " + KeyVerifiedUrlTable + " WHERE app_id = ?"
+	QueryDeleteVerifiedUrl  = "DELETE FROM " + KeyVerifiedUrlTable + " WHERE app_id = ? AND url = ?"
+	QueryDeleteVerifiedUrls = "DELETE FROM " + KeyVerifiedUrlTable + " WHERE app_id = ?"
+
+	KeyNodeMaintenanceTable   = "node_maintenance"
+	QueryInsertCordonedNode   = "INSERT INTO " + KeyNodeMaintenanceTable + " (cluster_name, node, cordoned_at) VALUES (?, ?, ?)"
+	KeyCordonedNodesByCluster = "SELECT node, cordoned_at FROM " + KeyNodeMaintenanceTable + " WHERE cluster_name = ?"
+	QueryDeleteCordonedNode   = "DELETE FROM " + KeyNodeMaintenanceTable + " WHERE cluster_name = ? AND node = ?"
 )
 
 // TODO: Should we make it singleton?
 func GetClusterDaoImpl(conf *conf.Configuration, monitor p.Monitor) *ClusterDaoImplCassandra {
-	session, err := cassandra.GetSessionInterface(conf.ClusterDB.DBConfig, conf.ClusterDB.ClusterKeySpace)
+	session, err := cassandra.GetMonitoredSessionInterface(conf.ClusterDB.DBConfig, conf.ClusterDB.ClusterKeySpace, monitor)
 	if err != nil {
 		err = errors.New(fmt.Sprintf("Cassandra initialisation failed for configuration: %+v with error %s", conf.ClusterDB.DBConfig, err.Error()))
 		panic(err)
 	}
+	session, err = cassandra.WithShadow(session, conf.ClusterDB.DBConfig, conf.ClusterDB.ClusterKeySpace, conf.ShadowWriteConfig, conf.ShadowWriteConfig.ClusterKeySpace, monitor)
+	if err != nil {
+		panic(errors.New(fmt.Sprintf("Shadow cluster initialisation failed for configuration: %+v with error %s", conf.ShadowWriteConfig, err.Error())))
+	}
 	return &ClusterDaoImplCassandra{
 		Session: session,
 		Conf:    conf,
@@ -93,6 +160,7 @@ func (c *ClusterDaoImplCassandra) getDefaultApps() (map[string]bool, error) {
 	var appId string
 	var active bool
 	var config string
+	var offboarding string
 	var partitions uint32
 	apps := make(map[string]bool)
 
@@ -101,12 +169,12 @@ func (c *ClusterDaoImplCassandra) getDefaultApps() (map[string]bool, error) {
 		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
 		Iter()
 
-	for iter.Scan(&appId, &partitions, &active, &config) {
+	for iter.Scan(&appId, &partitions, &active, &config, &offboarding) {
 		apps[appId] = true
 	}
 
 	if err := iter.Close(); err != nil {
-		glog.Errorf("Error occurred while getting default apps: %s", err.Error())
+		logger.Errorf("Error occurred while getting default apps: %s", err.Error())
 		return apps, err
 	}
 
@@ -117,16 +185,17 @@ func (c *ClusterDaoImplCassandra) createDefaultAppsIfRequired() {
 	apps, err := c.getDefaultApps()
 
 	if err != nil {
-		glog.Fatal(err)
+		logger.Fatal(err)
 	}
 
 	if _, ok := apps[MaxConfigApp]; !ok {
 		configuration := store.Configuration{
-			FutureScheduleCreationPeriod: c.Conf.AppLevelConfiguration.FutureScheduleCreationPeriod,
-			FiredScheduleRetentionPeriod: c.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod,
-			PayloadSize:                  c.Conf.AppLevelConfiguration.PayloadSize,
-			HttpRetries:                  c.Conf.AppLevelConfiguration.HttpRetries,
-			HttpTimeout:                  c.Conf.AppLevelConfiguration.HttpTimeout,
+			FutureScheduleCreationPeriod:   c.Conf.AppLevelConfiguration.FutureScheduleCreationPeriod,
+			FiredScheduleRetentionPeriod:   c.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod,
+			PayloadSize:                    c.Conf.AppLevelConfiguration.PayloadSize,
+			HttpRetries:                    c.Conf.AppLevelConfiguration.HttpRetries,
+			HttpTimeout:                    c.Conf.AppLevelConfiguration.HttpTimeout,
+			DeletedScheduleRetentionPeriod: c.Conf.AppLevelConfiguration.DeletedScheduleRetentionPeriod,
 		}
 
 		maxConfigApp := store.App{
@@ -137,10 +206,10 @@ func (c *ClusterDaoImplCassandra) createDefaultAppsIfRequired() {
 		}
 
 		if err := c.InsertApp(maxConfigApp); err != nil {
-			glog.Fatal(err)
+			logger.Fatal(err)
 		}
 
-		glog.Info("maxConfig app created!")
+		logger.Info("maxConfig app created!")
 	}
 
 	if _, ok := apps[c.Conf.CronConfig.App]; !ok {
@@ -152,7 +221,7 @@ func (c *ClusterDaoImplCassandra) createDefaultAppsIfRequired() {
 		}
 
 		if err := c.InsertApp(cronApp); err != nil {
-			glog.Fatal(err)
+			logger.Fatal(err)
 		}
 
 		var partition uint32 = 0
@@ -165,10 +234,10 @@ func (c *ClusterDaoImplCassandra) createDefaultAppsIfRequired() {
 			}
 
 			if err := c.CreateEntity(entity); err != nil {
-				glog.Fatal(err)
+				logger.Fatal(err)
 			}
 		}
-		glog.Info("Cron app created!")
+		logger.Info("Cron app created!")
 	}
 }
 
@@ -190,10 +259,10 @@ func (c *ClusterDaoImplCassandra) GetAllEntitiesInfoOfNode(nodeName string) []e.
 	}
 
 	if err := iter.Close(); err != nil {
-		glog.Fatal(err)
+		logger.Fatal(err)
 	}
 
-	glog.Infof("GetAllEntitiesInfoOfNode result is : %+v", entities)
+	logger.Infof("GetAllEntitiesInfoOfNode result is : %+v", entities)
 	return entities
 }
 
@@ -218,10 +287,10 @@ func (c *ClusterDaoImplCassandra) GetAllEntitiesInfo() []e.EntityInfo {
 	}
 
 	if err := iter.Close(); err != nil {
-		glog.Fatal(err)
+		logger.Fatal(err)
 	}
 
-	glog.Infof("GetAllEntitiesInfo result is : %+v", entities)
+	logger.Infof("GetAllEntitiesInfo result is : %+v", entities)
 	return entities
 }
 
@@ -235,7 +304,7 @@ func (c *ClusterDaoImplCassandra) GetAllEntitiesForApp(appId string) ([]e.Entity
 	//getting app info
 	app, err := c.GetApp(appId)
 	if err != nil {
-		glog.Errorf("Error: %s while getting app info for GetAllEntitiesForApp: %+v for app: %s", err.Error(), appId)
+		logger.Errorf("Error: %s while getting app info for GetAllEntitiesForApp: %+v for app: %s", err.Error(), appId)
 		return nil, err
 	}
 
@@ -258,11 +327,11 @@ func (c *ClusterDaoImplCassandra) GetAllEntitiesForApp(appId string) ([]e.Entity
 	}
 
 	if err := iter.Close(); err != nil {
-		glog.Errorf("Error: %s while getting poller info for GetAllEntitiesForApp: %+v for app: %s", err.Error(), appId)
+		logger.Errorf("Error: %s while getting poller info for GetAllEntitiesForApp: %+v for app: %s", err.Error(), appId)
 		return nil, err
 	}
 
-	glog.Infof("GetAllEntitiesInfo result is : %+v", entities)
+	logger.Infof("GetAllEntitiesInfo result is : %+v", entities)
 	return entities, nil
 }
 
@@ -274,10 +343,10 @@ func (c *ClusterDaoImplCassandra) GetEntityInfo(id string) e.EntityInfo {
 	var history string
 
 	query := fmt.Sprintf(KeyGetEntity, id)
-	glog.Info(query)
+	logger.Info(query)
 
 	if err := c.Session.Query(query).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Scan(&id, &nodeName, &status, &history); err != nil {
-		glog.Errorf("Error %s while querying %s", err.Error(), query)
+		logger.Errorf("Error %s while querying %s", err.Error(), query)
 		return e.EntityInfo{}
 	}
 
@@ -288,7 +357,7 @@ func (c *ClusterDaoImplCassandra) GetEntityInfo(id string) e.EntityInfo {
 		History: history,
 	}
 
-	glog.Infof("GetEntityInfo result for %s is : %+v", id, entity)
+	logger.Infof("GetEntityInfo result for %s is : %+v", id, entity)
 	return entity
 }
 
@@ -325,16 +394,17 @@ func (c *ClusterDaoImplCassandra) getApp(appName string) (store.App, error) {
 	var active bool
 	var partitions uint32
 	var config string
+	var offboarding string
 	var configuration store.Configuration
 
 	query := fmt.Sprintf(KeyAppById, appName)
-	if err := c.Session.Query(query).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Scan(&id, &partitions, &active, &config); err != nil {
-		glog.Errorf("Error %s while querying %s", err.Error(), query)
+	if err := c.Session.Query(query).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Scan(&id, &partitions, &active, &config, &offboarding); err != nil {
+		logger.Errorf("Error %s while querying %s", err.Error(), query)
 		return store.App{}, err
 	}
 
 	if err := json.Unmarshal([]byte(config), &configuration); err != nil {
-		glog.Errorf("Error: %s while unmarshalling config: %+v for app: %s", err.Error(), config, id)
+		logger.Errorf("Error: %s while unmarshalling config: %+v for app: %s", err.Error(), config, id)
 		configuration = store.Configuration{}
 	}
 
@@ -344,9 +414,24 @@ func (c *ClusterDaoImplCassandra) getApp(appName string) (store.App, error) {
 			Partitions:    partitions,
 			Active:        active,
 			Configuration: configuration,
+			Offboarding:   unmarshalOffboarding(offboarding, id),
 		}), nil
 }
 
+// unmarshalOffboarding returns the offboarding of an app, nil when the app is not being offboarded
+func unmarshalOffboarding(offboarding string, appId string) *store.Offboarding {
+	if offboarding == "" {
+		return nil
+	}
+
+	var o store.Offboarding
+	if err := json.Unmarshal([]byte(offboarding), &o); err != nil {
+		logger.Errorf("Error: %s while unmarshalling offboarding: %s for app: %s", err.Error(), offboarding, appId)
+		return nil
+	}
+	return &o
+}
+
 // Checks whether app is found in in-memory cache or not
 // Return app and the flag (found/ not found)
 func (c *ClusterDaoImplCassandra) check(appName string) (store.App, bool) {
@@ -382,7 +467,7 @@ func (c *ClusterDaoImplCassandra) UpdateEntityStatus(id string, nodename string,
 		history = history[historyLen-c.Conf.ClusterDB.EntityHistorySize : historyLen]
 	}
 	query := fmt.Sprintf(KeyUpdateEntityInfo, nodename, status, history, id)
-	glog.Info(query)
+	logger.Info(query)
 	return c.Session.Query(query).Exec()
 }
 
@@ -400,6 +485,7 @@ func (c *ClusterDaoImplCassandra) GetApps(appId string) ([]store.App, error) {
 	var id string
 	var active bool
 	var config string
+	var offboarding string
 	var partitions uint32
 	var configuration store.Configuration
 	var apps []store.App
@@ -414,19 +500,19 @@ func (c *ClusterDaoImplCassandra) GetApps(appId string) ([]store.App, error) {
 	}
 
 	iter := c.Session.Query(KeyGelAllApps).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).PageSize(c.Conf.ClusterDB.DBConfig.PageSize).Iter()
-	for iter.Scan(&id, &partitions, &active, &config) {
+	for iter.Scan(&id, &partitions, &active, &config, &offboarding) {
 		configuration = store.Configuration{}
 		if err := json.Unmarshal([]byte(config), &configuration); err != nil {
-			glog.Errorf("Error: %s while unmarshalling config: %+v for app: %s", err.Error(), config, id)
+			logger.Errorf("Error: %s while unmarshalling config: %+v for app: %s", err.Error(), config, id)
 		}
-		apps = append(apps, store.App{AppId: id, Partitions: partitions, Active: active, Configuration: configuration})
+		apps = append(apps, store.App{AppId: id, Partitions: partitions, Active: active, Configuration: configuration, Offboarding: unmarshalOffboarding(offboarding, id)})
 	}
 
 	if err := iter.Close(); err != nil {
 		return apps, err
 	}
 
-	glog.V(constants.INFO).Infof("Get all apps result is : +%v", apps)
+	logger.Debugf("Get all apps result is : +%v", apps)
 	return apps, nil
 }
 
@@ -439,10 +525,59 @@ func (c *ClusterDaoImplCassandra) UpdateAppActiveStatus(appName string, activeSt
 		query = fmt.Sprintf(QueryUpdateAppStatus, "False", appName)
 	}
 
-	glog.Info(query)
+	logger.Info(query)
 	return c.Session.Query(query).Exec()
 }
 
+// UpdateAppOffboarding records the offboarding of an app, a nil offboarding clears it.
+func (c *ClusterDaoImplCassandra) UpdateAppOffboarding(appName string, offboarding *store.Offboarding) error {
+	var value interface{}
+	if offboarding != nil {
+		b, err := json.Marshal(offboarding)
+		if err != nil {
+			return err
+		}
+		value = string(b)
+	}
+
+	return c.Session.Query(QueryUpdateOffboarding, value, appName).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// DeleteApp removes an app along with the entities of its partitions, its partition migration, usage and run stats
+// rollups, callback templates, schedule groups and verified urls.
+// The app itself is removed last, so that a failed delete can be run again.
+func (c *ClusterDaoImplCassandra) DeleteApp(app store.App) error {
+	var queries []string
+	var values [][]interface{}
+	for partition := 0; partition < int(app.Partitions); partition++ {
+		queries = append(queries, QueryDeleteEntity)
+		values = append(values, []interface{}{app.AppId + constants.PollerKeySep + strconv.Itoa(partition)})
+	}
+	groups, err := c.GetScheduleGroups(app.AppId)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		queries = append(queries, QueryDeleteGroupRuns)
+		values = append(values, []interface{}{app.AppId, group.Name})
+	}
+	for _, query := range []string{QueryDeleteMigration, QueryDeleteUsage, QueryDeleteRunStats, QueryDeleteTemplates, QueryDeleteGroups, QueryDeleteVerifiedUrls, QueryDeleteApp} {
+		queries = append(queries, query)
+		values = append(values, []interface{}{app.AppId})
+	}
+
+	for i, query := range queries {
+		if err := c.Session.Query(query, values[i]...).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Exec(); err != nil {
+			return err
+		}
+	}
+
+	c.InvalidateSingleAppCache(app.AppId)
+	return nil
+}
+
 // InvalidateSingleAppCache removes a specific app from the AppMap cache.
 func (c *ClusterDaoImplCassandra) InvalidateSingleAppCache(appName string) {
 	c.AppMap.lock.Lock()
@@ -475,6 +610,7 @@ func (c *ClusterDaoImplCassandra) getDCAwareApp(appName string) (store.App, erro
 	var partitions uint32
 	var active bool
 	var config string
+	var offboarding string
 	var configuration store.Configuration
 
 	// add dc prefix to app name
@@ -483,21 +619,22 @@ func (c *ClusterDaoImplCassandra) getDCAwareApp(appName string) (store.App, erro
 	appIdToApp := make(map[string]store.App)
 	iter := c.Session.Query(KeyAppByIds, dcPrefixedAppName, appName).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Iter()
 
-	for iter.Scan(&appId, &partitions, &active, &config) {
+	for iter.Scan(&appId, &partitions, &active, &config, &offboarding) {
 		configuration = store.Configuration{}
 		if err := json.Unmarshal([]byte(config), &configuration); err != nil {
-			glog.Errorf("Error: %s while unmarshalling config: %+v for app: %s", err.Error(), config, appId)
+			logger.Errorf("Error: %s while unmarshalling config: %+v for app: %s", err.Error(), config, appId)
 		}
 		appIdToApp[appId] = store.App{
 			AppId:         appId,
 			Partitions:    partitions,
 			Active:        active,
 			Configuration: configuration,
+			Offboarding:   unmarshalOffboarding(offboarding, appId),
 		}
 	}
 
 	if err := iter.Close(); err != nil {
-		glog.Infof("Error occurred while fetching apps: %+v", err)
+		logger.Infof("Error occurred while fetching apps: %+v", err)
 		return store.App{}, err
 	}
 
@@ -530,7 +667,7 @@ func (c *ClusterDaoImplCassandra) CreateConfigurations(appId string, configurati
 	}
 
 	query = fmt.Sprintf(QueryUpdateConfig, string(config), appId)
-	glog.Info(query)
+	logger.Info(query)
 
 	return configuration, c.Session.Query(query).Exec()
 }
@@ -542,7 +679,7 @@ func (c *ClusterDaoImplCassandra) GetConfiguration(appId string) (store.Configur
 	var configuration store.Configuration
 
 	query = fmt.Sprintf(QueryGetConfig, appId)
-	glog.Info(query)
+	logger.Info(query)
 
 	if err := c.Session.Query(query).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Scan(&config); err != nil {
 		return configuration, err
@@ -581,7 +718,7 @@ func (c *ClusterDaoImplCassandra) UpdateConfiguration(appId string, configuratio
 	}
 
 	query = fmt.Sprintf(QueryUpdateConfig, string(config), appId)
-	glog.Info(query)
+	logger.Info(query)
 
 	return configuration, c.Session.Query(query).Exec()
 }
@@ -594,7 +731,7 @@ func (c *ClusterDaoImplCassandra) DeleteConfiguration(appId string) (store.Confi
 	config, _ := json.Marshal(store.Configuration{})
 
 	query = fmt.Sprintf(QueryUpdateConfig, string(config), appId)
-	glog.Info(query)
+	logger.Info(query)
 
 	return store.Configuration{}, c.Session.Query(query).Exec()
 }
@@ -608,6 +745,90 @@ func (c *ClusterDaoImplCassandra) ValidateConfigurations(config store.Configurat
 		return nil
 	}
 
+	if config.PayloadCompression != "" {
+		if _, err = store.GetCodec(config.PayloadCompression); err != nil {
+			return err
+		}
+	}
+
+	if config.HttpTransport != nil {
+		if err = config.HttpTransport.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.Blackout != nil {
+		if err = config.Blackout.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.CallbackHeaders != nil {
+		if err = config.CallbackHeaders.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.Redaction != nil {
+		if err = config.Redaction.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.RetryPolicy != nil {
+		if err = config.RetryPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.CallbackBatching != nil {
+		if err = config.CallbackBatching.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.Governance != nil {
+		if err = config.Governance.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.PubSub != nil {
+		if err = config.PubSub.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.ServiceBus != nil {
+		if err = config.ServiceBus.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.FailureNotification != nil {
+		if err = config.FailureNotification.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if config.StalePause != nil {
+		if err = config.StalePause.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err = config.LoadShedding.Validate(); err != nil {
+		return err
+	}
+
+	if err = store.ValidateFlagName(config.FeatureFlag); err != nil {
+		return err
+	}
+
+	if message := store.GetRegions().Validate(config.Region); message != "" {
+		return errors.New(message)
+	}
+
 	if app, err = c.GetApp(MaxConfigApp); err != nil {
 		return err
 	}
@@ -622,7 +843,521 @@ func (c *ClusterDaoImplCassandra) ValidateConfigurations(config store.Configurat
 		return errors.New(fmt.Sprintf("provided fired schedule retention period: %d, max fired schedule retention period: %d", config.FiredScheduleRetentionPeriod, app.Configuration.FiredScheduleRetentionPeriod))
 	} else if config.FutureScheduleCreationPeriod > app.Configuration.FutureScheduleCreationPeriod {
 		return errors.New(fmt.Sprintf("provided schedule retention period: %d, max future schedule creation period: %d", config.FutureScheduleCreationPeriod, app.Configuration.FutureScheduleCreationPeriod))
+	} else if maxDeleted := app.GetDeletedRetention(c.Conf.AppLevelConfiguration.DeletedScheduleRetentionPeriod) / (24 * 60 * 60); config.DeletedScheduleRetentionPeriod > maxDeleted {
+		return errors.New(fmt.Sprintf("provided deleted schedule retention period: %d, max deleted schedule retention period: %d", config.DeletedScheduleRetentionPeriod, maxDeleted))
+	}
+
+	return nil
+}
+
+// UpdateAppPartitions sets the partition count of an app.
+// The cached app is dropped so that the next read picks up the new count.
+func (c *ClusterDaoImplCassandra) UpdateAppPartitions(appName string, partitions uint32) error {
+	if err := c.Session.Query(QueryUpdateAppPartitions, partitions, appName).Exec(); err != nil {
+		return err
+	}
+
+	c.InvalidateSingleAppCache(appName)
+	return nil
+}
+
+// UpsertPartitionMigration persists the state of the partition migration of an app.
+func (c *ClusterDaoImplCassandra) UpsertPartitionMigration(migration store.PartitionMigration) error {
+	return c.Session.Query(QueryUpsertMigration,
+		migration.AppId,
+		migration.FromPartitions,
+		migration.ToPartitions,
+		string(migration.Status),
+		migration.Migrated,
+		migration.Cursor,
+		migration.StartedAt,
+		migration.UpdatedAt,
+		migration.Error).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// GetPartitionMigration returns the latest partition migration of an app.
+// Returns gocql.ErrNotFound if the app was never resized.
+func (c *ClusterDaoImplCassandra) GetPartitionMigration(appName string) (store.PartitionMigration, error) {
+	var migration store.PartitionMigration
+	var status string
+
+	if err := c.Session.Query(KeyMigrationByApp, appName).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(
+			&migration.AppId,
+			&migration.FromPartitions,
+			&migration.ToPartitions,
+			&status,
+			&migration.Migrated,
+			&migration.Cursor,
+			&migration.StartedAt,
+			&migration.UpdatedAt,
+			&migration.Error); err != nil {
+		return store.PartitionMigration{}, err
+	}
+
+	migration.Status = store.MigrationStatus(status)
+	return migration, nil
+}
+
+// GetReplicationRole returns the replication role persisted for a cluster.
+// Returns gocql.ErrNotFound if the role of the cluster was never changed.
+func (c *ClusterDaoImplCassandra) GetReplicationRole(clusterName string) (string, error) {
+	var role string
+	if err := c.Session.Query(KeyReplicationRoleByCluster, clusterName).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(&role); err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// UpdateReplicationRole persists the replication role of a cluster so that it survives restarts of the nodes.
+func (c *ClusterDaoImplCassandra) UpdateReplicationRole(clusterName string, role string) error {
+	return c.Session.Query(QueryUpdateReplicationRole, clusterName, role, time.Now()).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// IncrementUsage adds the usage to the rollup of its app and day.
+// Counter updates are not idempotent, so a retried update may count the usage twice.
+func (c *ClusterDaoImplCassandra) IncrementUsage(usage store.Usage) error {
+	return c.Session.Query(QueryIncrementUsage,
+		usage.SchedulesCreated,
+		usage.CallbacksFired,
+		usage.BytesDelivered,
+		usage.Retries,
+		usage.AppId,
+		usage.Day).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// GetUsage returns the daily usage of an app between the days of from and to, both included, oldest first.
+// Days without any usage are left out.
+func (c *ClusterDaoImplCassandra) GetUsage(appId string, from time.Time, to time.Time) ([]store.Usage, error) {
+	iter := c.Session.Query(KeyUsageByAppAndDay, appId, store.UsageDay(from), store.UsageDay(to)).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var usages []store.Usage
+	usage := store.Usage{AppId: appId}
+	for iter.Scan(&usage.Day, &usage.SchedulesCreated, &usage.CallbacksFired, &usage.BytesDelivered, &usage.Retries) {
+		usage.Day = usage.Day.UTC()
+		usages = append(usages, usage)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// IncrementRunStats adds the run stats to the rollup of their app and hour.
+// Counter updates are not idempotent, so a retried update may count the runs twice.
+func (c *ClusterDaoImplCassandra) IncrementRunStats(stats store.RunStats) error {
+	return c.Session.Query(QueryIncrementRunStats,
+		stats.Successes,
+		stats.Failures,
+		stats.Retries,
+		stats.AppId,
+		stats.Hour).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// GetRunStats returns the hourly run stats of an app between the hours of from and to, both included, oldest first.
+// Hours without any run are left out.
+func (c *ClusterDaoImplCassandra) GetRunStats(appId string, from time.Time, to time.Time) ([]store.RunStats, error) {
+	iter := c.Session.Query(KeyRunStatsByAppAndHour, appId, store.RunStatsHour(from), store.RunStatsHour(to)).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var hours []store.RunStats
+	stats := store.RunStats{AppId: appId}
+	for iter.Scan(&stats.Hour, &stats.Successes, &stats.Failures, &stats.Retries) {
+		stats.Hour = stats.Hour.UTC()
+		hours = append(hours, stats)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+// CreateCallbackTemplate persists a version of a template.
+func (c *ClusterDaoImplCassandra) CreateCallbackTemplate(template store.CallbackTemplate) error {
+	return c.Session.Query(QueryInsertTemplate,
+		template.AppId,
+		template.Name,
+		template.Version,
+		string(template.Callback),
+		template.CreatedAt).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// GetCallbackTemplate returns a version of a template of an app, the latest one if the version is 0.
+// Returns gocql.ErrNotFound if the template or the version does not exist.
+func (c *ClusterDaoImplCassandra) GetCallbackTemplate(appId string, name string, version int) (store.CallbackTemplate, error) {
+	query := c.Session.Query(KeyLatestTemplateByName, appId, name)
+	if version != 0 {
+		query = c.Session.Query(KeyTemplateByVersion, appId, name, version)
+	}
+
+	var template store.CallbackTemplate
+	var callback string
+	if err := query.
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(&template.AppId, &template.Name, &template.Version, &callback, &template.CreatedAt); err != nil {
+		return store.CallbackTemplate{}, err
+	}
+
+	template.Callback = []byte(callback)
+	return template, nil
+}
+
+// GetCallbackTemplates returns the latest version of every template of an app, ordered by name.
+func (c *ClusterDaoImplCassandra) GetCallbackTemplates(appId string) ([]store.CallbackTemplate, error) {
+	iter := c.Session.Query(KeyTemplatesByApp, appId).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var templates []store.CallbackTemplate
+	var template store.CallbackTemplate
+	var callback string
+	for iter.Scan(&template.AppId, &template.Name, &template.Version, &callback, &template.CreatedAt) {
+		// versions are clustered latest first
+		if n := len(templates); n > 0 && templates[n-1].Name == template.Name {
+			continue
+		}
+		template.Callback = []byte(callback)
+		templates = append(templates, template)
 	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// CreateScheduleGroup persists a new group of an app.
+// Returns false if a group of the same name already exists.
+func (c *ClusterDaoImplCassandra) CreateScheduleGroup(group store.ScheduleGroup) (bool, error) {
+	// the columns of the existing group are read back, in the order of the table
+	var existing store.ScheduleGroup
+	return c.Session.Query(QueryInsertGroup,
+		group.AppId,
+		group.Name,
+		group.Description,
+		group.MaxRunsPerMinute,
+		group.CreatedAt,
+		group.UpdatedAt).
+		ScanCAS(&existing.AppId, &existing.Name, &existing.CreatedAt, &existing.Description, &existing.MaxRunsPerMinute, &existing.UpdatedAt)
+}
+
+// UpdateScheduleGroup updates the description and the run limit of a group.
+// Returns false if the group does not exist.
+func (c *ClusterDaoImplCassandra) UpdateScheduleGroup(group store.ScheduleGroup) (bool, error) {
+	return c.Session.Query(QueryUpdateGroup,
+		group.Description,
+		group.MaxRunsPerMinute,
+		group.UpdatedAt,
+		group.AppId,
+		group.Name).
+		ScanCAS()
+}
 
+// GetScheduleGroup returns a group of an app.
+// Returns gocql.ErrNotFound if the group does not exist.
+func (c *ClusterDaoImplCassandra) GetScheduleGroup(appId string, name string) (store.ScheduleGroup, error) {
+	var group store.ScheduleGroup
+	if err := c.Session.Query(KeyGroupByName, appId, name).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(&group.AppId, &group.Name, &group.Description, &group.MaxRunsPerMinute, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return store.ScheduleGroup{}, err
+	}
+	return group, nil
+}
+
+// GetScheduleGroups returns the groups of an app, ordered by name.
+func (c *ClusterDaoImplCassandra) GetScheduleGroups(appId string) ([]store.ScheduleGroup, error) {
+	iter := c.Session.Query(KeyGroupsByApp, appId).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var groups []store.ScheduleGroup
+	var group store.ScheduleGroup
+	for iter.Scan(&group.AppId, &group.Name, &group.Description, &group.MaxRunsPerMinute, &group.CreatedAt, &group.UpdatedAt) {
+		groups = append(groups, group)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// DeleteScheduleGroup removes a group of an app along with the counts of its runs.
+func (c *ClusterDaoImplCassandra) DeleteScheduleGroup(appId string, name string) error {
+	for _, query := range []string{QueryDeleteGroupRuns, QueryDeleteGroup} {
+		if err := c.Session.Query(query, appId, name).Consistency(c.Conf.ClusterDB.DBConfig.Consistency).Exec(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// IncrementGroupRuns counts a run of a group fired in the minute and returns the runs of the group counted in it.
+// Counter updates are not idempotent, so a retried update may count the run twice.
+func (c *ClusterDaoImplCassandra) IncrementGroupRuns(appId string, name string, minute time.Time) (int64, error) {
+	if err := c.Session.Query(QueryIncrementGroupRun, appId, name, minute).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec(); err != nil {
+		return 0, err
+	}
+
+	var runs int64
+	if err := c.Session.Query(KeyGroupRunsByMinute, appId, name, minute).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(&runs); err != nil {
+		return 0, err
+	}
+	return runs, nil
+}
+
+// CreateApproval records a request waiting for its approval, expiring it along with its audit trail after the ttl
+// in seconds. The approvals of an app are kept when the app is deleted, as they record who deleted it.
+func (c *ClusterDaoImplCassandra) CreateApproval(approval store.Approval, ttl int) error {
+	request, result, events, err := encodeApproval(approval)
+	if err != nil {
+		return err
+	}
+	return c.Session.Query(QueryInsertApproval,
+		approval.AppId,
+		approval.ApprovalId,
+		string(approval.Operation),
+		request,
+		string(approval.Status),
+		approval.RequestedBy,
+		millisToTime(approval.RequestedAt),
+		millisToTime(approval.ExpiresAt),
+		approval.DecidedBy,
+		millisToTime(approval.DecidedAt),
+		result,
+		events,
+		ttl).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// UpdateApproval records the decision on a request, or the result of its execution, if the request is still in the
+// from status, so that a request is decided on, and executed, only once.
+// Returns false if the request is no longer in the from status.
+func (c *ClusterDaoImplCassandra) UpdateApproval(approval store.Approval, from store.ApprovalStatus, ttl int) (bool, error) {
+	_, result, events, err := encodeApproval(approval)
+	if err != nil {
+		return false, err
+	}
+
+	var status string
+	return c.Session.Query(QueryUpdateApproval,
+		ttl,
+		string(approval.Status),
+		approval.DecidedBy,
+		millisToTime(approval.DecidedAt),
+		result,
+		events,
+		approval.AppId,
+		approval.ApprovalId,
+		string(from)).
+		ScanCAS(&status)
+}
+
+// GetApproval returns a request of an app waiting for, or given, its approval.
+// Returns gocql.ErrNotFound if the request does not exist or has expired.
+func (c *ClusterDaoImplCassandra) GetApproval(appId string, approvalId gocql.UUID) (store.Approval, error) {
+	var row approvalRow
+	if err := c.Session.Query(KeyApprovalById, appId, approvalId).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(row.dest()...); err != nil {
+		return store.Approval{}, err
+	}
+	return row.approval()
+}
+
+// GetApprovals returns the requests of an app waiting for, or given, their approval, latest first.
+func (c *ClusterDaoImplCassandra) GetApprovals(appId string) ([]store.Approval, error) {
+	iter := c.Session.Query(KeyApprovalsByApp, appId).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var approvals []store.Approval
+	var row approvalRow
+	for iter.Scan(row.dest()...) {
+		approval, err := row.approval()
+		if err != nil {
+			_ = iter.Close()
+			return nil, err
+		}
+		approvals = append(approvals, approval)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// encodeApproval returns the request, the result and the audit trail of an approval as stored in their columns
+func encodeApproval(approval store.Approval) (string, string, string, error) {
+	request, err := json.Marshal(approval.Request)
+	if err != nil {
+		return "", "", "", err
+	}
+	events, err := json.Marshal(approval.Events)
+	if err != nil {
+		return "", "", "", err
+	}
+	var result []byte
+	if approval.Result != nil {
+		if result, err = json.Marshal(approval.Result); err != nil {
+			return "", "", "", err
+		}
+	}
+	return string(request), string(result), string(events), nil
+}
+
+// approvalRow is a row of the approvals table
+type approvalRow struct {
+	fields                                     store.Approval
+	operation, request, status, result, events string
+	requestedAt, expiresAt, decidedAt          time.Time
+}
+
+func (r *approvalRow) dest() []interface{} {
+	return []interface{}{
+		&r.fields.AppId,
+		&r.fields.ApprovalId,
+		&r.operation,
+		&r.request,
+		&r.status,
+		&r.fields.RequestedBy,
+		&r.requestedAt,
+		&r.expiresAt,
+		&r.fields.DecidedBy,
+		&r.decidedAt,
+		&r.result,
+		&r.events,
+	}
+}
+
+func (r *approvalRow) approval() (store.Approval, error) {
+	approval := r.fields
+	approval.Operation = store.ApprovalOperation(r.operation)
+	approval.Status = store.ApprovalStatus(r.status)
+	approval.RequestedAt = timeToMillis(r.requestedAt)
+	approval.ExpiresAt = timeToMillis(r.expiresAt)
+	approval.DecidedAt = timeToMillis(r.decidedAt)
+	approval.Events = nil
+	approval.Result = nil
+
+	if err := json.Unmarshal([]byte(r.request), &approval.Request); err != nil {
+		return store.Approval{}, err
+	}
+	if r.events != "" {
+		if err := json.Unmarshal([]byte(r.events), &approval.Events); err != nil {
+			return store.Approval{}, err
+		}
+	}
+	if r.result != "" {
+		approval.Result = &store.ApprovalResult{}
+		if err := json.Unmarshal([]byte(r.result), approval.Result); err != nil {
+			return store.Approval{}, err
+		}
+	}
+	return approval, nil
+}
+
+func millisToTime(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond))
+}
+
+func timeToMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// CreateVerifiedUrl records a callback url which passed the verification handshake.
+func (c *ClusterDaoImplCassandra) CreateVerifiedUrl(verified store.VerifiedUrl) error {
+	return c.Session.Query(QueryInsertVerifiedUrl, verified.AppId, verified.Url, verified.VerifiedAt).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// GetVerifiedUrl returns a verified callback url of an app.
+// Returns gocql.ErrNotFound if the url is not verified.
+func (c *ClusterDaoImplCassandra) GetVerifiedUrl(appId string, url string) (store.VerifiedUrl, error) {
+	var verified store.VerifiedUrl
+	if err := c.Session.Query(KeyVerifiedUrl, appId, url).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Scan(&verified.AppId, &verified.Url, &verified.VerifiedAt); err != nil {
+		return store.VerifiedUrl{}, err
+	}
+	return verified, nil
+}
+
+// GetVerifiedUrls returns the verified callback urls of an app, ordered by url.
+func (c *ClusterDaoImplCassandra) GetVerifiedUrls(appId string) ([]store.VerifiedUrl, error) {
+	iter := c.Session.Query(KeyVerifiedUrlsByApp, appId).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var urls []store.VerifiedUrl
+	var verified store.VerifiedUrl
+	for iter.Scan(&verified.AppId, &verified.Url, &verified.VerifiedAt) {
+		urls = append(urls, verified)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// DeleteVerifiedUrl revokes the verification of a callback url of an app.
+func (c *ClusterDaoImplCassandra) DeleteVerifiedUrl(appId string, url string) error {
+	return c.Session.Query(QueryDeleteVerifiedUrl, appId, url).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// CordonNode records a node of a cluster as cordoned, so that no partition is assigned to it until it is uncordoned.
+func (c *ClusterDaoImplCassandra) CordonNode(clusterName string, node store.CordonedNode) error {
+	return c.Session.Query(QueryInsertCordonedNode, clusterName, node.Node, node.CordonedAt).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
+
+// GetCordonedNodes returns the cordoned nodes of a cluster, ordered by node.
+func (c *ClusterDaoImplCassandra) GetCordonedNodes(clusterName string) ([]store.CordonedNode, error) {
+	iter := c.Session.Query(KeyCordonedNodesByCluster, clusterName).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Iter()
+
+	var nodes []store.CordonedNode
+	var node store.CordonedNode
+	for iter.Scan(&node.Node, &node.CordonedAt) {
+		nodes = append(nodes, node)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// UncordonNode lets partitions be assigned again to a node of a cluster.
+func (c *ClusterDaoImplCassandra) UncordonNode(clusterName string, node string) error {
+	return c.Session.Query(QueryDeleteCordonedNode, clusterName, node).
+		Consistency(c.Conf.ClusterDB.DBConfig.Consistency).
+		Exec()
+}
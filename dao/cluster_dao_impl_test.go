@@ -33,13 +33,12 @@ import (
 func setupClusterDaoMocks(t *testing.T) (*ClusterDaoImplCassandra, *mocks.MockSessionInterface, *mocks.MockQueryInterface, *mocks.MockIterInterface, *gomock.Controller) {
 	dao := &ClusterDaoImplCassandra{
 		Conf: &conf.Configuration{
-			Cluster: conf.ClusterConfig{
-				PageSize: 10,
-				NumRetry: 2,
-			},
 			ClusterDB: conf.ClusterDBConfig{
-				ClusterKeySpace:   "",
-				DBConfig:          conf.CassandraConfig{},
+				ClusterKeySpace: "",
+				DBConfig: conf.CassandraConfig{
+					PageSize: 10,
+					NumRetry: 2,
+				},
 				EntityHistorySize: 0,
 			},
 			Poller: conf.PollerConfig{
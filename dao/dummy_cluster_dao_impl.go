@@ -22,6 +22,7 @@ package dao
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gocql/gocql"
 	e "github.com/myntra/goscheduler/cluster_entity"
@@ -87,6 +88,13 @@ func (d DummyClusterDaoImpl) GetApp(appName string) (store.App, error) {
 		return store.App{}, nil
 	case "testAppNotActive":
 		return store.App{Active: false}, nil
+	case "testVerifyUrls":
+		return store.App{
+			AppId:         appName,
+			Partitions:    1,
+			Active:        true,
+			Configuration: store.Configuration{FutureScheduleCreationPeriod: 1000, VerifyCallbackUrls: true},
+		}, nil
 	default:
 		return store.App{
 			AppId:         appName,
@@ -127,6 +135,24 @@ func (d DummyClusterDaoImpl) UpdateAppActiveStatus(appName string, activeStatus
 	}
 }
 
+func (d DummyClusterDaoImpl) UpdateAppOffboarding(appName string, offboarding *store.Offboarding) error {
+	switch appName {
+	case "testUpdateAppOffboardingError":
+		return errors.New(fmt.Sprintf("Error while updating offboarding for app %s", appName))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) DeleteApp(app store.App) error {
+	switch app.AppId {
+	case "testDeleteAppError":
+		return errors.New(fmt.Sprintf("Error while deleting app %s", app.AppId))
+	default:
+		return nil
+	}
+}
+
 func (d DummyClusterDaoImpl) CreateConfigurations(appId string, configuration store.Configuration) (store.Configuration, error) {
 	switch appId {
 	case "testCreateConfigurationsError":
@@ -190,7 +216,7 @@ func (d DummyClusterDaoImpl) GetApps(appId string) ([]store.App, error) {
 				AppId:         "test2",
 				Partitions:    1,
 				Active:        false,
-				Configuration: store.Configuration{},
+				Configuration: store.Configuration{PayloadSize: 2048},
 			},
 		}, nil
 	}
@@ -199,3 +225,290 @@ func (d DummyClusterDaoImpl) GetApps(appId string) ([]store.App, error) {
 func (d DummyClusterDaoImpl) GetDCAwareApp(appName string) (store.App, error) {
 	return store.App{}, nil
 }
+
+func (d DummyClusterDaoImpl) UpdateAppPartitions(appName string, partitions uint32) error {
+	switch appName {
+	case "testUpdateAppPartitionsError":
+		return errors.New(fmt.Sprintf("Error while updating partitions for app %s", appName))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) UpsertPartitionMigration(migration store.PartitionMigration) error {
+	switch migration.AppId {
+	case "testUpsertMigrationError":
+		return errors.New(fmt.Sprintf("Error while saving migration for app %s", migration.AppId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetPartitionMigration(appName string) (store.PartitionMigration, error) {
+	switch appName {
+	case "testGetMigrationError":
+		return store.PartitionMigration{}, errors.New(fmt.Sprintf("Error while getting migration for app %s", appName))
+	case "testMigrationRunning":
+		return store.PartitionMigration{
+			AppId:          appName,
+			FromPartitions: 1,
+			ToPartitions:   4,
+			Status:         store.MigrationRunning,
+		}, nil
+	case "testMigrationCompleted":
+		return store.PartitionMigration{
+			AppId:          appName,
+			FromPartitions: 1,
+			ToPartitions:   2,
+			Status:         store.MigrationCompleted,
+			Migrated:       10,
+		}, nil
+	default:
+		return store.PartitionMigration{}, gocql.ErrNotFound
+	}
+}
+
+func (d DummyClusterDaoImpl) GetReplicationRole(clusterName string) (string, error) {
+	switch clusterName {
+	case "testGetReplicationRoleError":
+		return "", errors.New(fmt.Sprintf("Error while getting replication role for cluster %s", clusterName))
+	case "testStandbyCluster":
+		return "standby", nil
+	default:
+		return "", gocql.ErrNotFound
+	}
+}
+
+func (d DummyClusterDaoImpl) UpdateReplicationRole(clusterName string, role string) error {
+	switch clusterName {
+	case "testUpdateReplicationRoleError":
+		return errors.New(fmt.Sprintf("Error while updating replication role for cluster %s", clusterName))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) IncrementUsage(usage store.Usage) error {
+	switch usage.AppId {
+	case "testIncrementUsageError":
+		return errors.New(fmt.Sprintf("Error while incrementing usage for app %s", usage.AppId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetUsage(appId string, from time.Time, to time.Time) ([]store.Usage, error) {
+	switch appId {
+	case "testGetUsageError":
+		return nil, errors.New(fmt.Sprintf("Error while getting usage for app %s", appId))
+	default:
+		return []store.Usage{}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) IncrementRunStats(stats store.RunStats) error {
+	switch stats.AppId {
+	case "testIncrementRunStatsError":
+		return errors.New(fmt.Sprintf("Error while incrementing run stats for app %s", stats.AppId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetRunStats(appId string, from time.Time, to time.Time) ([]store.RunStats, error) {
+	switch appId {
+	case "testGetRunStatsError":
+		return nil, errors.New(fmt.Sprintf("Error while getting run stats for app %s", appId))
+	case "testRunStats":
+		hour := store.RunStatsHour(from)
+		return []store.RunStats{
+			{AppId: appId, Hour: hour, Successes: 3, Failures: 1, Retries: 2},
+			{AppId: appId, Hour: hour.Add(2 * time.Hour), Successes: 1},
+		}, nil
+	default:
+		return []store.RunStats{}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) CreateCallbackTemplate(template store.CallbackTemplate) error {
+	switch template.AppId {
+	case "testCreateTemplateError":
+		return errors.New(fmt.Sprintf("Error while creating template %s for app %s", template.Name, template.AppId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetCallbackTemplate(appId string, name string, version int) (store.CallbackTemplate, error) {
+	switch {
+	case appId == "testGetTemplateError":
+		return store.CallbackTemplate{}, errors.New(fmt.Sprintf("Error while getting template %s for app %s", name, appId))
+	case name == "testTemplate" && version <= 2:
+		if version == 0 {
+			version = 2
+		}
+		return store.CallbackTemplate{
+			AppId:    appId,
+			Name:     name,
+			Version:  version,
+			Callback: []byte(fmt.Sprintf(`{"type":"http","details":{"url":"http://localhost:8080/v%d","method":"POST","headers":{}}}`, version)),
+		}, nil
+	default:
+		return store.CallbackTemplate{}, gocql.ErrNotFound
+	}
+}
+
+func (d DummyClusterDaoImpl) GetCallbackTemplates(appId string) ([]store.CallbackTemplate, error) {
+	switch appId {
+	case "testGetTemplateError":
+		return nil, errors.New(fmt.Sprintf("Error while getting templates for app %s", appId))
+	default:
+		template, _ := d.GetCallbackTemplate(appId, "testTemplate", 0)
+		return []store.CallbackTemplate{template}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) CreateScheduleGroup(group store.ScheduleGroup) (bool, error) {
+	switch {
+	case group.AppId == "testCreateGroupError":
+		return false, errors.New(fmt.Sprintf("Error while creating group %s for app %s", group.Name, group.AppId))
+	case group.Name == "testGroup":
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) UpdateScheduleGroup(group store.ScheduleGroup) (bool, error) {
+	switch {
+	case group.AppId == "testUpdateGroupError":
+		return false, errors.New(fmt.Sprintf("Error while updating group %s for app %s", group.Name, group.AppId))
+	default:
+		return group.Name == "testGroup", nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetScheduleGroup(appId string, name string) (store.ScheduleGroup, error) {
+	switch {
+	case appId == "testGetGroupError":
+		return store.ScheduleGroup{}, errors.New(fmt.Sprintf("Error while getting group %s for app %s", name, appId))
+	case name == "testGroup":
+		return store.ScheduleGroup{AppId: appId, Name: name, Description: "campaign", MaxRunsPerMinute: 10}, nil
+	default:
+		return store.ScheduleGroup{}, gocql.ErrNotFound
+	}
+}
+
+func (d DummyClusterDaoImpl) GetScheduleGroups(appId string) ([]store.ScheduleGroup, error) {
+	switch appId {
+	case "testGetGroupError":
+		return nil, errors.New(fmt.Sprintf("Error while getting groups for app %s", appId))
+	default:
+		group, _ := d.GetScheduleGroup(appId, "testGroup")
+		return []store.ScheduleGroup{group}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) DeleteScheduleGroup(appId string, name string) error {
+	switch appId {
+	case "testDeleteGroupError":
+		return errors.New(fmt.Sprintf("Error while deleting group %s for app %s", name, appId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) IncrementGroupRuns(appId string, name string, minute time.Time) (int64, error) {
+	return 1, nil
+}
+
+func (d DummyClusterDaoImpl) CreateApproval(approval store.Approval, ttl int) error {
+	switch approval.AppId {
+	case "testCreateApprovalError":
+		return errors.New(fmt.Sprintf("Error while creating approval for app %s", approval.AppId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetApproval(appId string, approvalId gocql.UUID) (store.Approval, error) {
+	return store.Approval{}, gocql.ErrNotFound
+}
+
+func (d DummyClusterDaoImpl) GetApprovals(appId string) ([]store.Approval, error) {
+	switch appId {
+	case "testGetApprovalsError":
+		return nil, errors.New(fmt.Sprintf("Error while getting approvals for app %s", appId))
+	default:
+		return []store.Approval{}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) UpdateApproval(approval store.Approval, from store.ApprovalStatus, ttl int) (bool, error) {
+	return true, nil
+}
+
+func (d DummyClusterDaoImpl) CreateVerifiedUrl(verified store.VerifiedUrl) error {
+	switch verified.AppId {
+	case "testCreateVerifiedUrlError":
+		return errors.New(fmt.Sprintf("Error while verifying url %s for app %s", verified.Url, verified.AppId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetVerifiedUrl(appId string, url string) (store.VerifiedUrl, error) {
+	switch {
+	case appId == "testGetVerifiedUrlError":
+		return store.VerifiedUrl{}, errors.New(fmt.Sprintf("Error while getting verified url %s for app %s", url, appId))
+	case url == "http://localhost:8080/verified":
+		return store.VerifiedUrl{AppId: appId, Url: url}, nil
+	default:
+		return store.VerifiedUrl{}, gocql.ErrNotFound
+	}
+}
+
+func (d DummyClusterDaoImpl) GetVerifiedUrls(appId string) ([]store.VerifiedUrl, error) {
+	switch appId {
+	case "testGetVerifiedUrlError":
+		return nil, errors.New(fmt.Sprintf("Error while getting verified urls for app %s", appId))
+	default:
+		return []store.VerifiedUrl{{AppId: appId, Url: "http://localhost:8080/verified"}}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) DeleteVerifiedUrl(appId string, url string) error {
+	switch appId {
+	case "testDeleteVerifiedUrlError":
+		return errors.New(fmt.Sprintf("Error while deleting verified url %s for app %s", url, appId))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) CordonNode(clusterName string, node store.CordonedNode) error {
+	switch node.Node {
+	case "testCordonNodeError":
+		return errors.New(fmt.Sprintf("Error while cordoning node %s", node.Node))
+	default:
+		return nil
+	}
+}
+
+func (d DummyClusterDaoImpl) GetCordonedNodes(clusterName string) ([]store.CordonedNode, error) {
+	switch clusterName {
+	case "testGetCordonedNodesError":
+		return nil, errors.New(fmt.Sprintf("Error while getting cordoned nodes of cluster %s", clusterName))
+	default:
+		return []store.CordonedNode{{Node: "127.0.0.1:9092"}}, nil
+	}
+}
+
+func (d DummyClusterDaoImpl) UncordonNode(clusterName string, node string) error {
+	switch node {
+	case "testUncordonNodeError":
+		return errors.New(fmt.Sprintf("Error while uncordoning node %s", node))
+	default:
+		return nil
+	}
+}
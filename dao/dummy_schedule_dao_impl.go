@@ -20,6 +20,8 @@
 package dao
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -30,7 +32,7 @@ import (
 
 type DummyScheduleDaoImpl struct{}
 
-func (d *DummyScheduleDaoImpl) CreateSchedule(schedule s.Schedule, app s.App) (s.Schedule, error) {
+func (d *DummyScheduleDaoImpl) CreateSchedule(_ context.Context, schedule s.Schedule, app s.App) (s.Schedule, error) {
 	switch schedule.AppId {
 	case "createScheduleFailureApp":
 		return schedule, errors.New("error")
@@ -97,11 +99,7 @@ func (d *DummyScheduleDaoImpl) GetScheduleRuns(uuid gocql.UUID, size int64, when
 					ScheduleGroup: 1,
 					Callback: &s.HttpCallback{
 						Type: "exampleType",
-						Details: struct {
-							Url     string            `json:"url"`
-							Method  string            `json:"method"`
-							Headers map[string]string `json:"headers"`
-						}{
+						Details: s.Details{
 							Url:    "http://example.com/callback",
 							Method: "TEST",
 							Headers: map[string]string{
@@ -122,7 +120,7 @@ func (d *DummyScheduleDaoImpl) GetScheduleRuns(uuid gocql.UUID, size int64, when
 	return []s.Schedule{}, nil, nil
 }
 
-func (d *DummyScheduleDaoImpl) CreateRun(schedule s.Schedule, app s.App) (s.Schedule, error) {
+func (d *DummyScheduleDaoImpl) CreateRun(_ context.Context, schedule s.Schedule, app s.App) (s.Schedule, error) {
 	return schedule, nil
 }
 
@@ -155,11 +153,7 @@ func (d *DummyScheduleDaoImpl) GetCronSchedulesByApp(appId string, status s.Stat
 				ScheduleGroup: 1,
 				Callback: &s.HttpCallback{
 					Type: "exampleType",
-					Details: struct {
-						Url     string            `json:"url"`
-						Method  string            `json:"method"`
-						Headers map[string]string `json:"headers"`
-					}{
+					Details: s.Details{
 						Url:    "http://example.com/callback",
 						Method: "TEST",
 						Headers: map[string]string{
@@ -187,11 +181,7 @@ func (d *DummyScheduleDaoImpl) GetCronSchedulesByApp(appId string, status s.Stat
 				ScheduleGroup: 1,
 				Callback: &s.HttpCallback{
 					Type: "exampleType",
-					Details: struct {
-						Url     string            `json:"url"`
-						Method  string            `json:"method"`
-						Headers map[string]string `json:"headers"`
-					}{
+					Details: s.Details{
 						Url:    "http://example.com/callback",
 						Method: "TEST",
 						Headers: map[string]string{
@@ -219,7 +209,379 @@ func (d *DummyScheduleDaoImpl) UpdateRecurringSchedule(schedule s.Schedule) (s.S
 	return schedule, nil
 }
 
+// UpdateOneTimeSchedule is a no-op stub to satisfy the ScheduleDao interface during tests.
+func (d *DummyScheduleDaoImpl) UpdateOneTimeSchedule(existing s.Schedule, schedule s.Schedule, app s.App) (s.Schedule, error) {
+	return schedule, nil
+}
+
 func (d *DummyScheduleDaoImpl) UpdateRecurringScheduleStatus(schedule s.Schedule, status s.Status) (s.Schedule, error) {
 	schedule.Status = status
 	return schedule, nil
 }
+
+func (d *DummyScheduleDaoImpl) CreateDeliveryReceipt(receipt s.DeliveryReceipt, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetDeliveryReceipts(uuid gocql.UUID) ([]s.DeliveryReceipt, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000000":
+		return nil, errors.New("error")
+	case "00000000-0000-0000-0000-000000000001":
+		return []s.DeliveryReceipt{}, nil
+	default:
+		return []s.DeliveryReceipt{
+			{
+				ScheduleId:     uuid,
+				AppId:          "dummy app id",
+				CallbackType:   "http",
+				DispatchedAt:   time.Now().UnixNano() / int64(time.Millisecond),
+				PayloadHash:    s.HashPayload("dummy payload"),
+				ResponseStatus: 200,
+				KeyId:          "v1",
+				Signature:      "signature",
+			},
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) GetBulkDeliveryReceipts(uuids []gocql.UUID) ([]s.DeliveryReceipt, error) {
+	var receipts []s.DeliveryReceipt
+	for _, uuid := range uuids {
+		scheduleReceipts, err := d.GetDeliveryReceipts(uuid)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, scheduleReceipts...)
+	}
+	return receipts, nil
+}
+
+func (d *DummyScheduleDaoImpl) CreateCallbackCanary(canary s.CallbackCanary) error {
+	switch canary.AppId {
+	case "error":
+		return errors.New("error")
+	default:
+		return nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) GetCallbackCanary(uuid gocql.UUID) (s.CallbackCanary, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000000":
+		return s.CallbackCanary{}, errors.New("error")
+	case "00000000-0000-0000-0000-000000000001":
+		return s.CallbackCanary{}, gocql.ErrNotFound
+	default:
+		return s.CallbackCanary{
+			ScheduleId: uuid,
+			AppId:      "dummy app id",
+			Runs:       3,
+			Fired:      1,
+			Baseline:   json.RawMessage(`{"type":"http","details":{"url":"http://127.0.0.1:8080/old","method":"POST","headers":null}}`),
+			Candidate:  json.RawMessage(`{"type":"http","details":{"url":"http://127.0.0.1:8080/new","method":"POST","headers":null}}`),
+			CreatedAt:  time.Now().UnixNano() / int64(time.Millisecond),
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) UpdateCallbackCanary(canary s.CallbackCanary) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) CreateCanaryResult(result s.CanaryResult, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetCanaryResults(uuid gocql.UUID) ([]s.CanaryResult, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000002":
+		return nil, errors.New("error")
+	default:
+		runId := gocql.TimeUUID()
+		return []s.CanaryResult{
+			{ScheduleId: uuid, RunId: runId, Target: s.CanaryBaseline, Status: s.Success, ResponseStatus: 200, LatencyMillis: 10},
+			{ScheduleId: uuid, RunId: runId, Target: s.CanaryCandidate, Status: s.Failure, ResponseStatus: 404, LatencyMillis: 20},
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) UpdateVersionedRecurringSchedule(schedule s.Schedule, version s.ScheduleVersion) (s.Schedule, error) {
+	switch version.ScheduleId.String() {
+	case "88888888-8888-8888-8888-888888888888":
+		return s.Schedule{}, errors.New("error")
+	default:
+		return d.UpdateRecurringSchedule(schedule)
+	}
+}
+
+func (d *DummyScheduleDaoImpl) GetScheduleVersions(uuid gocql.UUID) ([]s.ScheduleVersion, error) {
+	switch uuid.String() {
+	case "77777777-7777-7777-7777-777777777777":
+		return nil, errors.New("error")
+	default:
+		versionId := gocql.TimeUUID()
+		return []s.ScheduleVersion{
+			{
+				ScheduleId: uuid,
+				Version:    1,
+				VersionId:  versionId,
+				ReplacedAt: versionId.Time().UnixNano() / int64(time.Millisecond),
+				Definition: s.ScheduleDefinition{
+					Payload:        `{"foo":"baz"}`,
+					Callback:       json.RawMessage(`{"type":"http","details":{"url":"http://example.com/v1","method":"POST"}}`),
+					CronExpression: "*/5 * * * *",
+				},
+			},
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) CountSchedules(appId string, partitionId int, timeBucket time.Time) (int, error) {
+	switch appId {
+	case "error":
+		return 0, errors.New("error")
+	default:
+		return 1, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) CountSchedulesInBuckets(appId string, partitionId int, timeBuckets []time.Time) ([]int, error) {
+	switch appId {
+	case "error":
+		return nil, errors.New("error")
+	default:
+		counts := make([]int, len(timeBuckets))
+		for i := range counts {
+			counts[i] = 1
+		}
+		return counts, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) MoveSchedule(schedule s.Schedule, partitionId int, app s.App) (s.Schedule, error) {
+	switch schedule.AppId {
+	case "error":
+		return schedule, errors.New("error")
+	default:
+		schedule.PartitionId = partitionId
+		return schedule, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) PurgeRecurringSchedule(schedule s.Schedule) error {
+	switch schedule.AppId {
+	case "error":
+		return errors.New("error")
+	default:
+		return nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) GetParkedSchedules(day time.Time, shard int) ([]s.Schedule, []error) {
+	return nil, nil
+}
+
+func (d *DummyScheduleDaoImpl) PromoteSchedule(schedule s.Schedule, app s.App) (s.Schedule, error) {
+	switch schedule.AppId {
+	case "error":
+		return schedule, errors.New("error")
+	default:
+		schedule.PartitionId = schedule.PartitionFor(app.Partitions)
+		schedule.Parked = false
+		return schedule, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) UpsertJob(job s.Job, ttl int) error {
+	switch job.AppId {
+	case "error":
+		return errors.New("error")
+	default:
+		return nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) GetJob(uuid gocql.UUID) (s.Job, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000000":
+		return s.Job{}, errors.New("error")
+	case "00000000-0000-0000-0000-000000000001":
+		return s.Job{}, gocql.ErrNotFound
+	default:
+		now := time.Now().UnixNano() / int64(time.Millisecond)
+		return s.Job{
+			JobId:     uuid,
+			Type:      s.BulkUpdateJob,
+			AppId:     "dummy app id",
+			Status:    s.JobRunning,
+			Total:     2,
+			Succeeded: 1,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) SetJobCancelRequested(uuid gocql.UUID, cancelRequested bool, ttl int) error {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000003":
+		return errors.New("error")
+	default:
+		return nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) CreateJobItem(item s.JobItem, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetJobItems(uuid gocql.UUID) ([]s.JobItem, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000002":
+		return nil, errors.New("error")
+	default:
+		return []s.JobItem{{JobId: uuid, ItemId: gocql.TimeUUID().String(), Status: s.Success}}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) CreateDueRun(run s.DueRun, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetDueRuns(appId string, limit int) ([]s.DueRun, error) {
+	switch appId {
+	case "error":
+		return nil, errors.New("error")
+	case "empty":
+		return []s.DueRun{}, nil
+	default:
+		return []s.DueRun{
+			{
+				AppId:        appId,
+				ScheduleId:   gocql.TimeUUID(),
+				Payload:      "dummy payload",
+				ScheduleTime: time.Now().Unix(),
+			},
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) LeaseDueRun(run s.DueRun, leasedUntil time.Time, ttl int) (bool, error) {
+	return true, nil
+}
+
+func (d *DummyScheduleDaoImpl) GetDueRun(appId string, uuid gocql.UUID) (s.DueRun, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000000":
+		return s.DueRun{}, errors.New("error")
+	case "00000000-0000-0000-0000-000000000001":
+		return s.DueRun{}, gocql.ErrNotFound
+	default:
+		return s.DueRun{
+			AppId:        appId,
+			ScheduleId:   uuid,
+			Payload:      "dummy payload",
+			ScheduleTime: time.Now().Unix(),
+			Deliveries:   1,
+			LeasedUntil:  time.Now().Add(time.Minute).UnixNano() / int64(time.Millisecond),
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) DeleteDueRun(appId string, uuid gocql.UUID) (bool, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000002":
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) ClaimSchedules(intents []s.FireIntent, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) AcknowledgeSchedules(intents []s.FireIntent) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetFireIntents(appId string, partitionId int, claimBucket time.Time) ([]s.FireIntent, error) {
+	return []s.FireIntent{}, nil
+}
+
+func (d *DummyScheduleDaoImpl) AcquireLock(lock s.Lock, now time.Time) (s.Lock, bool, error) {
+	switch lock.Name {
+	case "error":
+		return s.Lock{}, false, errors.New("error")
+	case "held":
+		return s.Lock{AppId: lock.AppId, Name: lock.Name, Owner: "other", AcquiredAt: now.Unix() * 1000}, false, nil
+	default:
+		return lock.Lease(now), true, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) ReleaseLock(lock s.Lock) (s.Lock, bool, error) {
+	switch lock.Name {
+	case "error":
+		return s.Lock{}, false, errors.New("error")
+	case "held":
+		return s.Lock{AppId: lock.AppId, Name: lock.Name, Owner: "other"}, false, nil
+	case "missing":
+		return s.Lock{AppId: lock.AppId, Name: lock.Name}, false, nil
+	default:
+		return lock, true, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) CreateCallbackAttempt(attempt s.CallbackAttempt, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetCallbackAttempts(uuid gocql.UUID) ([]s.CallbackAttempt, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000000":
+		return nil, errors.New("error")
+	case "00000000-0000-0000-0000-000000000001":
+		return []s.CallbackAttempt{}, nil
+	default:
+		return []s.CallbackAttempt{
+			{
+				ScheduleId:      uuid,
+				AppId:           "dummy app id",
+				Attempt:         1,
+				AttemptedAt:     time.Now().UnixNano() / int64(time.Millisecond),
+				Method:          "POST",
+				Url:             "http://localhost:8080/test",
+				RequestSnippet:  "dummy payload",
+				ResponseStatus:  200,
+				ResponseSnippet: "OK",
+				LatencyMillis:   10,
+			},
+		}, nil
+	}
+}
+
+func (d *DummyScheduleDaoImpl) IndexArchivedSchedules(archived []s.ArchivedSchedule, ttl int) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) DeleteArchivedBucket(appId string, partitionId int, timeBucket time.Time, schedules []s.Schedule) error {
+	return nil
+}
+
+func (d *DummyScheduleDaoImpl) GetArchivedSchedule(uuid gocql.UUID) (s.ArchivedSchedule, error) {
+	return s.ArchivedSchedule{}, gocql.ErrNotFound
+}
+
+func (d *DummyScheduleDaoImpl) GetArchivedRuns(parentScheduleId gocql.UUID, limit int) ([]s.ArchivedSchedule, error) {
+	return nil, nil
+}
+
+func (d *DummyScheduleDaoImpl) PurgeBucket(appId string, partitionId int, timeBucket time.Time) (int, error) {
+	return 0, nil
+}
+
+func (d *DummyScheduleDaoImpl) PurgeAppRecords(app s.App) error {
+	return nil
+}
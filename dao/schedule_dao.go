@@ -20,6 +20,7 @@
 package dao
 
 import (
+	"context"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -28,14 +29,14 @@ import (
 )
 
 type ScheduleDao interface {
-	CreateSchedule(schedule s.Schedule, app s.App) (s.Schedule, error)
+	CreateSchedule(ctx context.Context, schedule s.Schedule, app s.App) (s.Schedule, error)
 	GetRecurringScheduleByPartition(partitionId int) ([]s.Schedule, []error)
 	GetSchedule(uuid gocql.UUID) (s.Schedule, error)
 	GetEnrichedSchedule(uuid gocql.UUID) (s.Schedule, error)
 	EnrichSchedule(schedule *s.Schedule) error
 	DeleteSchedule(uuid gocql.UUID) (s.Schedule, error)
 	GetScheduleRuns(uuid gocql.UUID, size int64, when string, pageState []byte) ([]s.Schedule, []byte, error)
-	CreateRun(schedule s.Schedule, app s.App) (s.Schedule, error)
+	CreateRun(ctx context.Context, schedule s.Schedule, app s.App) (s.Schedule, error)
 	UpdateStatus(schedules []s.Schedule, app s.App) error
 	GetPaginatedSchedules(appId string, partitions int, timeRange Range, size int64, status s.Status, pageState []byte, continuationStartTime time.Time) ([]s.Schedule, []byte, time.Time, error)
 	GetSchedulesForEntity(appId string, partitionId int, timeBucket time.Time, pageState []byte) db_wrapper.IterInterface
@@ -44,4 +45,44 @@ type ScheduleDao interface {
 	BulkAction(app s.App, partitionId int, scheduleTimeGroup time.Time, status []s.Status, actionType s.ActionType) error
 	UpdateRecurringScheduleStatus(schedule s.Schedule, status s.Status) (s.Schedule, error)
 	UpdateRecurringSchedule(schedule s.Schedule) (s.Schedule, error)
+	UpdateVersionedRecurringSchedule(schedule s.Schedule, version s.ScheduleVersion) (s.Schedule, error)
+	UpdateOneTimeSchedule(existing s.Schedule, schedule s.Schedule, app s.App) (s.Schedule, error)
+	CreateDeliveryReceipt(receipt s.DeliveryReceipt, ttl int) error
+	GetDeliveryReceipts(uuid gocql.UUID) ([]s.DeliveryReceipt, error)
+	GetBulkDeliveryReceipts(uuids []gocql.UUID) ([]s.DeliveryReceipt, error)
+	CreateCallbackCanary(canary s.CallbackCanary) error
+	GetCallbackCanary(uuid gocql.UUID) (s.CallbackCanary, error)
+	UpdateCallbackCanary(canary s.CallbackCanary) error
+	CreateCanaryResult(result s.CanaryResult, ttl int) error
+	GetCanaryResults(uuid gocql.UUID) ([]s.CanaryResult, error)
+	GetScheduleVersions(uuid gocql.UUID) ([]s.ScheduleVersion, error)
+	CountSchedules(appId string, partitionId int, timeBucket time.Time) (int, error)
+	CountSchedulesInBuckets(appId string, partitionId int, timeBuckets []time.Time) ([]int, error)
+	MoveSchedule(schedule s.Schedule, partitionId int, app s.App) (s.Schedule, error)
+	PurgeRecurringSchedule(schedule s.Schedule) error
+	GetParkedSchedules(day time.Time, shard int) ([]s.Schedule, []error)
+	PromoteSchedule(schedule s.Schedule, app s.App) (s.Schedule, error)
+	UpsertJob(job s.Job, ttl int) error
+	GetJob(uuid gocql.UUID) (s.Job, error)
+	SetJobCancelRequested(uuid gocql.UUID, cancelRequested bool, ttl int) error
+	CreateJobItem(item s.JobItem, ttl int) error
+	GetJobItems(uuid gocql.UUID) ([]s.JobItem, error)
+	CreateCallbackAttempt(attempt s.CallbackAttempt, ttl int) error
+	GetCallbackAttempts(uuid gocql.UUID) ([]s.CallbackAttempt, error)
+	CreateDueRun(run s.DueRun, ttl int) error
+	GetDueRuns(appId string, limit int) ([]s.DueRun, error)
+	GetDueRun(appId string, uuid gocql.UUID) (s.DueRun, error)
+	LeaseDueRun(run s.DueRun, leasedUntil time.Time, ttl int) (bool, error)
+	DeleteDueRun(appId string, uuid gocql.UUID) (bool, error)
+	ClaimSchedules(intents []s.FireIntent, ttl int) error
+	AcknowledgeSchedules(intents []s.FireIntent) error
+	GetFireIntents(appId string, partitionId int, claimBucket time.Time) ([]s.FireIntent, error)
+	AcquireLock(lock s.Lock, now time.Time) (s.Lock, bool, error)
+	ReleaseLock(lock s.Lock) (s.Lock, bool, error)
+	IndexArchivedSchedules(archived []s.ArchivedSchedule, ttl int) error
+	DeleteArchivedBucket(appId string, partitionId int, timeBucket time.Time, schedules []s.Schedule) error
+	GetArchivedSchedule(uuid gocql.UUID) (s.ArchivedSchedule, error)
+	GetArchivedRuns(parentScheduleId gocql.UUID, limit int) ([]s.ArchivedSchedule, error)
+	PurgeBucket(appId string, partitionId int, timeBucket time.Time) (int, error)
+	PurgeAppRecords(app s.App) error
 }
@@ -20,20 +20,24 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/cassandra"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
 	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
 )
 
 const BatchSize = 50
@@ -66,11 +70,19 @@ type Range struct {
 }
 
 func GetScheduleDaoImpl(conf *conf.Configuration, monitor p.Monitor) *ScheduleDaoImpl {
-	session, err := cassandra.GetSessionInterface(conf.ScheduleDB.DBConfig, conf.ScheduleDB.ScheduleKeySpace)
+	if err := ValidateConsistency(conf.ScheduleDB.OperationConsistency); err != nil {
+		panic(err)
+	}
+
+	session, err := cassandra.GetMonitoredSessionInterface(conf.ScheduleDB.DBConfig, conf.ScheduleDB.ScheduleKeySpace, monitor)
 	if err != nil {
 		err = errors.New(fmt.Sprintf("Cassandra initialisation failed for configuration: %+v with error %s", conf.ScheduleDB.DBConfig, err.Error()))
 		panic(err)
 	}
+	session, err = cassandra.WithShadow(session, conf.ScheduleDB.DBConfig, conf.ScheduleDB.ScheduleKeySpace, conf.ShadowWriteConfig, conf.ShadowWriteConfig.ScheduleKeySpace, monitor)
+	if err != nil {
+		panic(errors.New(fmt.Sprintf("Shadow cluster initialisation failed for configuration: %+v with error %s", conf.ShadowWriteConfig, err.Error())))
+	}
 	return &ScheduleDaoImpl{
 		Session: session,
 		Conf:    conf,
@@ -78,12 +90,52 @@ func GetScheduleDaoImpl(conf *conf.Configuration, monitor p.Monitor) *ScheduleDa
 	}
 }
 
+// ValidateConsistency checks the consistency levels of the classes of operations on the schedules
+func ValidateConsistency(levels conf.OperationConsistency) error {
+	for _, level := range []string{levels.Create, levels.StatusUpdate, levels.PollRead} {
+		if level == "" {
+			continue
+		}
+		if _, err := gocql.ParseConsistencyWrapper(level); err != nil {
+			return fmt.Errorf("invalid consistency level %s: %w", level, err)
+		}
+	}
+
+	if strings.EqualFold(levels.PollRead, gocql.Any.String()) {
+		return errors.New("consistency level ANY cannot be used for poll reads")
+	}
+	return nil
+}
+
+// consistency returns the consistency level of a class of operations, the one of the schedule database when the
+// class has none
+func (s *ScheduleDaoImpl) consistency(level string) gocql.Consistency {
+	if level != "" {
+		if consistency, err := gocql.ParseConsistencyWrapper(level); err == nil {
+			return consistency
+		}
+	}
+	return s.Conf.ScheduleDB.DBConfig.Consistency
+}
+
 // Persist a cron schedule in Cassandra.
 // The data is denormalized across two different tables.
-// The schedules are created with status as Scheduled.
+// The schedules are created with status as Scheduled, or as Draft when requested.
 // Throws error in writing data to the schedule fails.
 func (s *ScheduleDaoImpl) createRecurringSchedule(schedule store.Schedule) (store.Schedule, error) {
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return schedule, err
+	}
+
+	// A draft is stored inactive until it is activated
+	status := store.Scheduled
+	if schedule.IsDraft() {
+		status = store.Draft
+	}
+
 	batch := gocql.NewBatch(gocql.LoggedBatch)
+	batch.SetConsistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.Create))
 
 	for _, query := range []string{
 		"INSERT INTO recurring_schedules_by_id (" +
@@ -94,7 +146,19 @@ func (s *ScheduleDaoImpl) createRecurringSchedule(schedule store.Schedule) (stor
 			"callback_type," +
 			"callback_details," +
 			"cron_expression, " +
-			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			"every, " +
+			"rrule, " +
+			"anchor, " +
+			"status_callback, " +
+			"payload_encoding, " +
+			"priority, " +
+			"region, " +
+			"trace_id, " +
+			"pause_policy, " +
+			"concurrency_policy, " +
+			"group_name, " +
+			"keep_paused, " +
+			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 
 		"INSERT INTO recurring_schedules_by_partition (" +
 			"app_id," +
@@ -104,29 +168,67 @@ func (s *ScheduleDaoImpl) createRecurringSchedule(schedule store.Schedule) (stor
 			"callback_type," +
 			"callback_details," +
 			"cron_expression, " +
-			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			"every, " +
+			"rrule, " +
+			"anchor, " +
+			"status_callback, " +
+			"payload_encoding, " +
+			"priority, " +
+			"region, " +
+			"trace_id, " +
+			"pause_policy, " +
+			"concurrency_policy, " +
+			"group_name, " +
+			"keep_paused, " +
+			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 	} {
 		batch.Query(
 			query,
 			schedule.AppId,
 			schedule.PartitionId,
 			schedule.ScheduleId,
-			schedule.Payload,
+			payload,
 			schedule.GetCallBackType(),
 			schedule.GetCallbackDetails(),
 			schedule.CronExpression,
-			store.Scheduled)
+			schedule.Every,
+			schedule.RRule,
+			schedule.Anchor*constants.SecondsToMillis,
+			schedule.StatusCallback,
+			schedule.PayloadEncoding,
+			string(schedule.Priority),
+			schedule.Region,
+			schedule.TraceId,
+			string(schedule.PausePolicy),
+			string(schedule.ConcurrencyPolicy),
+			schedule.Group,
+			schedule.KeepPaused,
+			status)
 	}
 
-	err := s.Session.ExecuteBatch(batch)
+	err = s.Session.ExecuteBatch(batch)
 
-	schedule.Status = store.Scheduled
+	schedule.Status = status
 	return schedule, err
 }
 
 // Persist a one time schedule in Cassandra.
+// Schedules beyond the parking horizon are written to the parking table until they are promoted.
 // Throws error if writing data to schedule fails.
 func (s *ScheduleDaoImpl) createOneTimeSchedule(schedule store.Schedule, app store.App) (store.Schedule, error) {
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return schedule, err
+	}
+
+	if store.ShouldPark(s.Conf.ParkingConfig, schedule.ScheduleTime, clock.Now()) {
+		query, values := s.parkScheduleQuery(schedule, payload, app)
+		schedule.Parked = true
+		return schedule, s.Session.Query(query, values...).
+			Consistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.Create)).
+			Exec()
+	}
+
 	query := "INSERT INTO schedules (" +
 		"app_id," +
 		"partition_id," +
@@ -135,27 +237,48 @@ func (s *ScheduleDaoImpl) createOneTimeSchedule(schedule store.Schedule, app sto
 		"schedule_time," +
 		"payload," +
 		"callback_type," +
-		"callback_details) VALUES (?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
-
-	err := s.Session.Query(
+		"callback_details," +
+		"status_callback," +
+		"payload_encoding," +
+		"priority," +
+		"region," +
+		"trace_id," +
+		"deferred_from," +
+		"request_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	err = s.Session.Query(
 		query,
 		schedule.AppId,
 		schedule.PartitionId,
 		schedule.ScheduleGroup*constants.SecondsToMillis,
 		schedule.ScheduleId,
 		schedule.ScheduleTime*constants.SecondsToMillis,
-		schedule.Payload,
+		payload,
 		schedule.GetCallBackType(),
 		schedule.GetCallbackDetails(),
-		schedule.GetTTL(app, s.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod)).Exec()
+		schedule.StatusCallback,
+		schedule.PayloadEncoding,
+		string(schedule.Priority),
+		schedule.Region,
+		schedule.TraceId,
+		schedule.DeferredFrom*constants.SecondsToMillis,
+		schedule.RequestId,
+		schedule.GetTTL(app, s.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod)).
+		Consistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.Create)).
+		Exec()
 
 	return schedule, err
 }
 
 // Persist the schedule details in cassandra.
 // The tables to which the schedule is written to is determined based on it being a recurring schedule or not.
+// The request id of the context is persisted with a one time schedule, so the workers firing it log it.
 // Throws error if the writing to the schedule fails.
-func (s *ScheduleDaoImpl) CreateSchedule(schedule store.Schedule, app store.App) (store.Schedule, error) {
+func (s *ScheduleDaoImpl) CreateSchedule(ctx context.Context, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	if requestId := logger.RequestID(ctx); requestId != "" {
+		schedule.RequestId = requestId
+	}
+
 	if schedule.IsRecurring() {
 		return s.profile(func() (store.Schedule, error) {
 			return s.createRecurringSchedule(schedule)
@@ -178,6 +301,20 @@ func (s *ScheduleDaoImpl) GetRecurringScheduleByPartition(partitionId int) ([]st
 		"app_id," +
 		"partition_id, " +
 		"cron_expression, " +
+		"every, " +
+		"rrule, " +
+		"anchor, " +
+		"status_callback, " +
+		"payload_encoding, " +
+		"priority, " +
+		"region, " +
+		"trace_id, " +
+		"pause_policy, " +
+		"concurrency_policy, " +
+		"group_name, " +
+		"keep_paused, " +
+		"paused_at, " +
+		"deleted_at, " +
 		"status " +
 		"FROM recurring_schedules_by_partition " +
 		"WHERE partition_id = ?"
@@ -187,6 +324,7 @@ func (s *ScheduleDaoImpl) GetRecurringScheduleByPartition(partitionId int) ([]st
 
 	_map := make(map[string]interface{})
 	iter := s.Session.Query(query, partitionId).
+		Consistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.PollRead)).
 		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
 		Iter()
 
@@ -219,6 +357,20 @@ func (s *ScheduleDaoImpl) getRecurringSchedule(uuid gocql.UUID) (store.Schedule,
 		"app_id," +
 		"partition_id, " +
 		"cron_expression, " +
+		"every, " +
+		"rrule, " +
+		"anchor, " +
+		"status_callback, " +
+		"payload_encoding, " +
+		"priority, " +
+		"region, " +
+		"trace_id, " +
+		"pause_policy, " +
+		"concurrency_policy, " +
+		"group_name, " +
+		"keep_paused, " +
+		"paused_at, " +
+		"deleted_at, " +
 		"status " +
 		"FROM recurring_schedules_by_id " +
 		"WHERE schedule_id= ? LIMIT 1"
@@ -236,9 +388,20 @@ func (s *ScheduleDaoImpl) getRecurringSchedule(uuid gocql.UUID) (store.Schedule,
 	return schedule, err
 }
 
-// Find a one time schedule with the supplied id.
+// Find a one time schedule with the supplied id, falling back to the parked schedules.
 // Returns a non nil error if fetching the details failed or if no row with the id is found.
 func (s *ScheduleDaoImpl) getOneTimeSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	schedule, err := s.getTimeBucketedSchedule(uuid)
+	if err == gocql.ErrNotFound && s.Conf.ParkingConfig.Enabled {
+		return s.getParkedSchedule(uuid)
+	}
+
+	return schedule, err
+}
+
+// Find a one time schedule with the supplied id in the time bucketed tables.
+// Returns a non nil error if fetching the details failed or if no row with the id is found.
+func (s *ScheduleDaoImpl) getTimeBucketedSchedule(uuid gocql.UUID) (store.Schedule, error) {
 	query := "SELECT " +
 		"schedule_id," +
 		"payload," +
@@ -247,7 +410,14 @@ func (s *ScheduleDaoImpl) getOneTimeSchedule(uuid gocql.UUID) (store.Schedule, e
 		"callback_type," +
 		"callback_details," +
 		"app_id," +
-		"partition_id " +
+		"partition_id," +
+		"status_callback," +
+		"payload_encoding, " +
+		"priority, " +
+		"region, " +
+		"trace_id, " +
+		"deferred_from, " +
+		"request_id " +
 		"FROM view_schedules " +
 		"WHERE schedule_id= ? LIMIT 1"
 
@@ -320,17 +490,20 @@ func (s *ScheduleDaoImpl) EnrichSchedule(schedule *store.Schedule) error {
 func (s *ScheduleDaoImpl) deleteRecurringSchedule(schedule store.Schedule) (store.Schedule, error) {
 	batch := gocql.NewBatch(gocql.LoggedBatch)
 
+	// The deletion time tells the purge when the retention of the deleted schedule is over
+	deletedAt := clock.Now().Unix()
+
 	deleteById := "UPDATE recurring_schedules_by_id " +
-		"SET status = ? " +
+		"SET status = ?, deleted_at = ? " +
 		"WHERE schedule_id = ?"
-	batch.Query(deleteById, store.Deleted, schedule.ScheduleId)
+	batch.Query(deleteById, store.Deleted, deletedAt*constants.SecondsToMillis, schedule.ScheduleId)
 
 	deleteByPartition := "UPDATE recurring_schedules_by_partition " +
-		"SET status = ? " +
+		"SET status = ?, deleted_at = ? " +
 		"WHERE partition_id = ? " +
 		"AND schedule_id = ? " +
 		"AND app_id = ?"
-	batch.Query(deleteByPartition, store.Deleted, schedule.PartitionId, schedule.ScheduleId, schedule.AppId)
+	batch.Query(deleteByPartition, store.Deleted, deletedAt*constants.SecondsToMillis, schedule.PartitionId, schedule.ScheduleId, schedule.AppId)
 
 	runs, _, err := s.getFutureRuns(schedule.ScheduleId, -1, nil)
 	if err != nil {
@@ -348,20 +521,60 @@ func (s *ScheduleDaoImpl) deleteRecurringSchedule(schedule store.Schedule) (stor
 
 	err = s.Session.ExecuteBatch(batch)
 	schedule.Status = store.Deleted
+	schedule.DeletedAt = deletedAt
 
 	return schedule, err
 }
 
+// PurgeRecurringSchedule removes a deleted recurring schedule and its runs from Cassandra.
+// The schedule and its runs are the only rows of their partitions, so apart from the row of the partition table
+// they are removed with partition tombstones, which compaction drops at once.
+func (s *ScheduleDaoImpl) PurgeRecurringSchedule(schedule store.Schedule) error {
+	batch := gocql.NewBatch(gocql.LoggedBatch)
+
+	batch.Query("DELETE FROM recurring_schedules_by_id "+
+		"WHERE schedule_id = ?",
+		schedule.ScheduleId)
+
+	batch.Query("DELETE FROM recurring_schedules_by_partition "+
+		"WHERE partition_id = ? "+
+		"AND schedule_id = ? "+
+		"AND app_id = ?",
+		schedule.PartitionId, schedule.ScheduleId, schedule.AppId)
+
+	batch.Query("DELETE FROM recurring_schedule_runs "+
+		"WHERE parent_schedule_id = ?",
+		schedule.ScheduleId)
+
+	batch.Query("DELETE FROM schedule_versions "+
+		"WHERE schedule_id = ?",
+		schedule.ScheduleId)
+
+	batch.Query("DELETE FROM callback_canaries "+
+		"WHERE schedule_id = ?",
+		schedule.ScheduleId)
+
+	return s.Session.ExecuteBatch(batch)
+}
+
 const deleteFromSchedule string = "DELETE from schedules " +
 	"WHERE app_id = ? " +
 	"AND partition_id = ? " +
 	"AND schedule_time_group = ? " +
 	"AND schedule_id = ?"
 
-// Deletes a given schedule from the schedule table.
+// Deletes a given schedule from the schedule table, or from the parking table if it is parked.
 // The rows are removed from the Cassandra.
 // Return a non nil error in case the delete fails.
 func (s *ScheduleDaoImpl) deleteOneTimeSchedule(schedule store.Schedule) (store.Schedule, error) {
+	if schedule.Parked {
+		return schedule, s.Session.Query(
+			deleteFromParkedSchedules,
+			store.ParkingDay(schedule.ScheduleTime)*constants.SecondsToMillis,
+			schedule.ParkingShard(s.Conf.ParkingConfig.Shards),
+			schedule.ScheduleId).Exec()
+	}
+
 	err := s.Session.Query(
 		deleteFromSchedule,
 		schedule.AppId,
@@ -399,7 +612,12 @@ func (s *ScheduleDaoImpl) getRuns(uuid gocql.UUID, pageState []byte, size int64)
 		"callback_type, " +
 		"callback_details, " +
 		"payload, " +
-		"schedule_time " +
+		"schedule_time, " +
+		"status_callback, " +
+		"payload_encoding, " +
+		"priority, " +
+		"region, " +
+		"trace_id " +
 		"FROM recurring_schedule_runs " +
 		"WHERE parent_schedule_id = ? "
 
@@ -463,11 +681,11 @@ type paginatedScheduleWriter struct {
 func (s *ScheduleDaoImpl) getFilteredRuns(uuid gocql.UUID, writer paginatedScheduleWriter, size int64) {
 	_map := make(map[string]interface{})
 	pageState := *writer.pageState
-	glog.V(constants.INFO).Infof("Initial pageState %+v", pageState)
+	logger.Debugf("Initial pageState %+v", pageState)
 	for {
 		schedulesLeft := int64(int(size) - len(*writer.schedules))
 		iter := s.getRuns(uuid, pageState, schedulesLeft)
-		glog.V(constants.INFO).Infof("uuid: %+v, PageState: %+v, schedulesLeft: %d", uuid, iter.PageState(), schedulesLeft)
+		logger.Debugf("uuid: %+v, PageState: %+v, schedulesLeft: %d", uuid, iter.PageState(), schedulesLeft)
 		var items int
 		for !writer.terminate() && iter.MapScan(_map) {
 			var schedule store.Schedule
@@ -476,13 +694,13 @@ func (s *ScheduleDaoImpl) getFilteredRuns(uuid gocql.UUID, writer paginatedSched
 				return
 			}
 
-			glog.V(constants.INFO).Infof("Got schedule here %s", schedule.ScheduleId.String())
+			logger.Debugf("Got schedule here %s", schedule.ScheduleId.String())
 
 			//enrich schedule with status data
 			if err := s.setStatus(&schedule); err != nil {
 				//log and suppress
 				//We will not stop the process due to failed enrichment
-				glog.Errorf("Error occurred while enriching with status %s", err.Error())
+				logger.Errorf("Error occurred while enriching with status %s", err.Error())
 			}
 
 			if writer.filter(schedule) {
@@ -501,7 +719,7 @@ func (s *ScheduleDaoImpl) getFilteredRuns(uuid gocql.UUID, writer paginatedSched
 
 		pageState = iter.PageState()
 		if len(pageState) == 0 || writer.terminate() {
-			glog.V(constants.INFO).Infof("Reached end %+v", iter.PageState())
+			logger.Debugf("Reached end %+v", iter.PageState())
 			return
 		}
 	}
@@ -538,7 +756,7 @@ func (s *ScheduleDaoImpl) getPastRuns(uuid gocql.UUID, size int64, pageState []b
 	var schedules []store.Schedule
 	var lastPageState int
 	var err error
-	now := time.Now()
+	now := clock.Now()
 
 	writer := paginatedScheduleWriter{
 		schedules:     &schedules,
@@ -565,7 +783,7 @@ func (s *ScheduleDaoImpl) getFutureRuns(uuid gocql.UUID, size int64, pageState [
 	var schedules []store.Schedule
 	lastPageState := -1
 	var err error
-	now := time.Now()
+	now := clock.Now()
 
 	writer := paginatedScheduleWriter{
 		schedules:     &schedules,
@@ -603,11 +821,20 @@ func (s *ScheduleDaoImpl) GetScheduleRuns(uuid gocql.UUID, size int64, when stri
 }
 
 // Create a one time schedule for a recurring schedule.
-// The schedule will be persisted in schedule and runs tables.
+// The schedule will be persisted in schedule and runs tables, with the request id of the context if it has one.
 // Returns a non nil error in case persisting the data fails.
-func (s *ScheduleDaoImpl) CreateRun(schedule store.Schedule, app store.App) (store.Schedule, error) {
+func (s *ScheduleDaoImpl) CreateRun(ctx context.Context, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	if requestId := logger.RequestID(ctx); requestId != "" {
+		schedule.RequestId = requestId
+	}
+
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return schedule, err
+	}
 
 	batch := gocql.NewBatch(gocql.LoggedBatch)
+	batch.SetConsistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.Create))
 
 	for _, query := range []string{"INSERT INTO schedules (" +
 		"app_id," +
@@ -618,7 +845,13 @@ func (s *ScheduleDaoImpl) CreateRun(schedule store.Schedule, app store.App) (sto
 		"payload," +
 		"callback_type," +
 		"callback_details," +
-		"parent_schedule_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
+		"status_callback," +
+		"payload_encoding," +
+		"priority," +
+		"region," +
+		"trace_id," +
+		"request_id," +
+		"parent_schedule_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
 
 		"INSERT INTO recurring_schedule_runs (" +
 			"app_id," +
@@ -629,7 +862,13 @@ func (s *ScheduleDaoImpl) CreateRun(schedule store.Schedule, app store.App) (sto
 			"payload," +
 			"callback_type," +
 			"callback_details," +
-			"parent_schedule_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
+			"status_callback," +
+			"payload_encoding," +
+			"priority," +
+			"region," +
+			"trace_id," +
+			"request_id," +
+			"parent_schedule_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
 	} {
 		batch.
 			RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
@@ -640,9 +879,15 @@ func (s *ScheduleDaoImpl) CreateRun(schedule store.Schedule, app store.App) (sto
 				schedule.ScheduleGroup*constants.SecondsToMillis,
 				schedule.ScheduleId,
 				schedule.ScheduleTime*constants.SecondsToMillis,
-				schedule.Payload,
+				payload,
 				schedule.GetCallBackType(),
 				schedule.GetCallbackDetails(),
+				schedule.StatusCallback,
+				schedule.PayloadEncoding,
+				string(schedule.Priority),
+				schedule.Region,
+				schedule.TraceId,
+				schedule.RequestId,
 				schedule.ParentScheduleId,
 				schedule.GetTTL(app, s.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod))
 	}
@@ -655,7 +900,7 @@ func (s *ScheduleDaoImpl) CreateRun(schedule store.Schedule, app store.App) (sto
 func (s *ScheduleDaoImpl) UpdateStatus(schedules []store.Schedule, app store.App) error {
 	//log schedule status update
 	for _, schedule := range schedules {
-		glog.Infof("update status for schedule: %s", schedule.ScheduleId.String())
+		schedule.Logger().Infof("update status for schedule: %s", schedule.ScheduleId.String())
 	}
 
 	const insertStatusQuery string = "INSERT INTO status (" +
@@ -665,9 +910,11 @@ func (s *ScheduleDaoImpl) UpdateStatus(schedules []store.Schedule, app store.App
 		"schedule_id," +
 		"schedule_status," +
 		"error_msg," +
-		"reconciliation_history) VALUES (?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+		"response_snippet," +
+		"reconciliation_history) VALUES (?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
 
 	batch := gocql.NewBatch(gocql.UnloggedBatch)
+	batch.SetConsistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.StatusUpdate))
 
 	for _, query := range schedules {
 		reconciliationHistory, _ := json.Marshal(query.ReconciliationHistory)
@@ -682,6 +929,7 @@ func (s *ScheduleDaoImpl) UpdateStatus(schedules []store.Schedule, app store.App
 				query.ScheduleId,
 				query.Status,
 				query.ErrorMessage,
+				query.ResponseSnippet,
 				reconciliationHistory,
 				query.GetTTL(app, s.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod))
 	}
@@ -722,7 +970,7 @@ func (s *ScheduleDaoImpl) getPaginatedSchedulesByStatus(appId string, partitions
 
 func (s *ScheduleDaoImpl) GetPaginatedSchedules(appId string, partitions int, timeRange Range, size int64, status store.Status, pageState []byte, continuationStartTime time.Time) ([]store.Schedule, []byte, time.Time, error) {
 	switch status {
-	case store.Success, store.Failure, store.Miss, store.Scheduled:
+	case store.Success, store.Failure, store.Overrun, store.Miss, store.Scheduled, store.Skipped:
 		return s.getPaginatedSchedulesByStatus(appId, partitions, timeRange, size, status, pageState, continuationStartTime)
 	default:
 		return s.getPaginatedSchedulesByStatus(appId, partitions, timeRange, size, "", pageState, continuationStartTime)
@@ -742,7 +990,7 @@ func (s *ScheduleDaoImpl) getFilteredSchedules(appId string, partitions int, tim
 	startTime := timeRange.StartTime
 	endTime := timeRange.EndTime
 
-	glog.V(constants.INFO).Infof("continuationStartTime: %+v", writer.continuationStartTime)
+	logger.Debugf("continuationStartTime: %+v", writer.continuationStartTime)
 
 	// override the start time if resume start time is present
 	if writer.continuationStartTime.Unix() != 0 {
@@ -761,8 +1009,8 @@ func (s *ScheduleDaoImpl) getFilteredSchedules(appId string, partitions int, tim
 	var interval = Range{StartTime: startTime, EndTime: next(startTime)}
 
 	for {
-		glog.V(constants.INFO).Infof("startTime: %+v, endTime: %+v", interval.StartTime, interval.EndTime)
-		glog.V(constants.INFO).Infof("pageState: %+v", pageState)
+		logger.Debugf("startTime: %+v, endTime: %+v", interval.StartTime, interval.EndTime)
+		logger.Debugf("pageState: %+v", pageState)
 		//return if the start time equal end time
 		if interval.StartTime == interval.EndTime {
 			return
@@ -781,16 +1029,16 @@ func (s *ScheduleDaoImpl) getFilteredSchedules(appId string, partitions int, tim
 				return
 			}
 
-			glog.V(constants.INFO).Infof("Found scheduleId: %s", schedule.ScheduleId)
+			logger.Debugf("Found scheduleId: %s", schedule.ScheduleId)
 			//enrich schedule with status data
 			if err := s.setStatus(&schedule); err != nil {
 				//log and suppress
 				//We will not stop the process due to failed enrichment
-				glog.Errorf("Error occurred while enriching with status %s", err.Error())
+				logger.Errorf("Error occurred while enriching with status %s", err.Error())
 			}
 
 			if writer.filter(schedule) {
-				glog.V(constants.INFO).Infof("Accepted scheduleId: %s", schedule.ScheduleId)
+				logger.Debugf("Accepted scheduleId: %s", schedule.ScheduleId)
 				*writer.schedules = append(*writer.schedules, schedule)
 				items++
 			}
@@ -811,7 +1059,7 @@ func (s *ScheduleDaoImpl) getFilteredSchedules(appId string, partitions int, tim
 
 		pageState = iter.PageState()
 		if len(pageState) == 0 {
-			glog.V(constants.INFO).Info("Reached end of page")
+			logger.Debug("Reached end of page")
 			newStartTime := interval.EndTime
 
 			//set start and end of interval
@@ -840,7 +1088,14 @@ func (s *ScheduleDaoImpl) getSchedules(appId string, partitions []int, timeRange
 		"callback_type," +
 		"callback_details," +
 		"app_id," +
-		"partition_id " +
+		"partition_id," +
+		"status_callback," +
+		"payload_encoding, " +
+		"priority, " +
+		"region, " +
+		"trace_id, " +
+		"deferred_from, " +
+		"request_id " +
 		"FROM schedules " +
 		"WHERE app_id = ? " +
 		"AND partition_id IN ? " +
@@ -870,19 +1125,27 @@ func (s *ScheduleDaoImpl) GetSchedulesForEntity(appId string, partitionId int, t
 		"callback_details," +
 		"payload," +
 		"schedule_time," +
+		"status_callback," +
+		"payload_encoding," +
+		"priority," +
+		"region," +
+		"trace_id," +
+		"deferred_from," +
+		"request_id," +
 		"parent_schedule_id " +
 		"FROM schedules " +
 		"WHERE app_id = ? " +
 		"AND partition_id = ? " +
 		"AND schedule_time_group = ?"
 
-	glog.Infof("Getting schedules from db with Page size : %v and Num retries : %v", s.Conf.ScheduleDB.DBConfig.PageSize, s.Conf.ScheduleDB.DBConfig.NumRetry)
+	logger.Infof("Getting schedules from db with Page size : %v and Num retries : %v", s.Conf.ScheduleDB.DBConfig.PageSize, s.Conf.ScheduleDB.DBConfig.NumRetry)
 
 	iter := s.Session.Query(
 		query,
 		appId,
 		partitionId,
 		timeBucket).
+		Consistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.PollRead)).
 		PageState(pageState).
 		PageSize(s.Conf.ScheduleDB.DBConfig.PageSize).
 		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
@@ -908,6 +1171,7 @@ func (s *ScheduleDaoImpl) setStatus(schedule *store.Schedule) error {
 	query := "SELECT " +
 		"schedule_status," +
 		"error_msg," +
+		"response_snippet," +
 		"reconciliation_history " +
 		"FROM status " +
 		"WHERE app_id= ? " +
@@ -955,6 +1219,7 @@ func (s *ScheduleDaoImpl) getBulkStatus(schedules []store.Schedule) db_wrapper.I
 		"schedule_id," +
 		"schedule_status," +
 		"error_msg," +
+		"response_snippet," +
 		"reconciliation_history " +
 		"FROM status " +
 		"WHERE app_id= ? " +
@@ -990,7 +1255,7 @@ func (s *ScheduleDaoImpl) OptimizedEnrichSchedule(schedules []store.Schedule) ([
 		idToSchedule[schedule.ScheduleId] = schedule
 	}
 
-	glog.Infof("idToSchedule: %+v", idToSchedule)
+	logger.Infof("idToSchedule: %+v", idToSchedule)
 
 	// Set status of schedules which are present in status table
 	iter := s.getBulkStatus(schedules)
@@ -1003,13 +1268,13 @@ func (s *ScheduleDaoImpl) OptimizedEnrichSchedule(schedules []store.Schedule) ([
 			return enrichedSchedules, err
 		}
 
-		glog.V(constants.INFO).Infof("Enriched Schedule: %+v", schedule)
+		logger.Debugf("Enriched Schedule: %+v", schedule)
 
 		enrichedSchedules = append(enrichedSchedules, schedule)
 		_map = make(map[string]interface{})
 	}
 	if err := iter.Close(); err != nil {
-		glog.Errorf("Error: %s while calling status query for schedules: %+v", err.Error(), schedules)
+		logger.Errorf("Error: %s while calling status query for schedules: %+v", err.Error(), schedules)
 		return enrichedSchedules, err
 	}
 
@@ -1018,7 +1283,7 @@ func (s *ScheduleDaoImpl) OptimizedEnrichSchedule(schedules []store.Schedule) ([
 		if _, ok := found[uuid]; !ok {
 			schedule.SetUnknownStatus(s.Conf.AggregateSchedulesConfig.FlushPeriod)
 
-			glog.V(constants.INFO).Infof("Enriched Schedule: %+v", schedule)
+			logger.Debugf("Enriched Schedule: %+v", schedule)
 			enrichedSchedules = append(enrichedSchedules, schedule)
 		}
 	}
@@ -1035,6 +1300,20 @@ func (s *ScheduleDaoImpl) GetCronSchedulesByApp(appId string, status store.Statu
 		"app_id," +
 		"partition_id, " +
 		"cron_expression, " +
+		"every, " +
+		"rrule, " +
+		"anchor, " +
+		"status_callback, " +
+		"payload_encoding, " +
+		"priority, " +
+		"region, " +
+		"trace_id, " +
+		"pause_policy, " +
+		"concurrency_policy, " +
+		"group_name, " +
+		"keep_paused, " +
+		"paused_at, " +
+		"deleted_at, " +
 		"status " +
 		"FROM recurring_schedules_by_id"
 
@@ -1068,7 +1347,7 @@ func (s *ScheduleDaoImpl) GetCronSchedulesByApp(appId string, status store.Statu
 func (s *ScheduleDaoImpl) BulkAction(app store.App, partitionId int, scheduleTimeGroup time.Time, status []store.Status, actionType store.ActionType) error {
 	defer func() {
 		if r := recover(); r != nil {
-			glog.Errorf("Recovered in %s from error %+v with stacktrace %s", string(actionType), r, string(debug.Stack()))
+			logger.Errorf("Recovered in %s from error %+v with stacktrace %s", string(actionType), r, string(debug.Stack()))
 		}
 	}()
 
@@ -1082,11 +1361,11 @@ func (s *ScheduleDaoImpl) BulkAction(app store.App, partitionId int, scheduleTim
 	iter := s.GetSchedulesForEntity(app.AppId, partitionId, scheduleTimeGroup, pageState)
 	for iter.MapScan(_map) {
 		if err := _sch.CreateScheduleFromCassandraMap(_map); err != nil {
-			glog.Infof("Error while forming schedule from cassandra map: %+v, error: %s", _map, err.Error())
+			logger.Infof("Error while forming schedule from cassandra map: %+v, error: %s", _map, err.Error())
 			return err
 		}
 
-		glog.V(constants.INFO).Infof("Got schedule: %+v, pageState: %+v", _sch, iter.PageState())
+		logger.Debugf("Got schedule: %+v, pageState: %+v", _sch, iter.PageState())
 
 		batch = append(batch, _sch)
 		counter++
@@ -1108,7 +1387,7 @@ func (s *ScheduleDaoImpl) BulkAction(app store.App, partitionId int, scheduleTim
 	}
 
 	if err = iter.Close(); err != nil {
-		glog.Errorf("Error: %s while making query for app: %s, partitionId: %d, scheduleTimeGroup: %+v",
+		logger.Errorf("Error: %s while making query for app: %s, partitionId: %d, scheduleTimeGroup: %+v",
 			err.Error(),
 			app.AppId,
 			partitionId,
@@ -1126,7 +1405,7 @@ func (s *ScheduleDaoImpl) BulkAction(app store.App, partitionId int, scheduleTim
 func contains(status []store.Status, _sch store.Schedule) bool {
 	for _, v := range status {
 		switch v {
-		case store.Success, store.Failure, store.Miss, store.Scheduled:
+		case store.Success, store.Failure, store.Overrun, store.Miss, store.Scheduled, store.Skipped:
 			if v == _sch.Status {
 				return true
 			}
@@ -1146,7 +1425,7 @@ func (s *ScheduleDaoImpl) actionIfRequired(app store.App, schedules []store.Sche
 
 	enrichedSchedules, err := s.OptimizedEnrichSchedule(schedules)
 	if err != nil {
-		glog.Errorf("Schedule enrichment failed for appId: %s, partitionId: %d with error %s", schedules[0].AppId, schedules[0].PartitionId, err.Error())
+		logger.Errorf("Schedule enrichment failed for appId: %s, partitionId: %d with error %s", schedules[0].AppId, schedules[0].PartitionId, err.Error())
 	}
 
 	// we already logged the error, so no need to log it
@@ -1154,7 +1433,7 @@ func (s *ScheduleDaoImpl) actionIfRequired(app store.App, schedules []store.Sche
 		return err
 	}
 
-	glog.V(constants.INFO).Infof("Enriched schedules: %+v", enrichedSchedules)
+	logger.Debugf("Enriched schedules: %+v", enrichedSchedules)
 
 	for _, _sch := range enrichedSchedules {
 		if contains(status, _sch) {
@@ -1176,26 +1455,31 @@ func (s *ScheduleDaoImpl) actionIfRequired(app store.App, schedules []store.Sche
 }
 
 // UpdateRecurringScheduleStatus updates the status of a recurring schedule
-// If status is Paused, it also deletes all future executions similar to deleteRecurringSchedule
+// If status is Paused or Suspended, it also deletes all future executions similar to deleteRecurringSchedule
 func (sdi *ScheduleDaoImpl) UpdateRecurringScheduleStatus(schedule store.Schedule, status store.Status) (store.Schedule, error) {
 	batch := gocql.NewBatch(gocql.LoggedBatch)
 
+	// The pause time is only kept while the schedule is paused
+	if status != store.Paused {
+		schedule.PausedAt = 0
+	}
+
 	updateById := "UPDATE recurring_schedules_by_id " +
-		"SET status = ? " +
+		"SET status = ?, paused_at = ? " +
 		"WHERE schedule_id = ?"
-	batch.Query(updateById, status, schedule.ScheduleId)
+	batch.Query(updateById, status, pausedAt(schedule), schedule.ScheduleId)
 
 	updateByPartition := "UPDATE recurring_schedules_by_partition " +
-		"SET status = ? " +
+		"SET status = ?, paused_at = ? " +
 		"WHERE partition_id = ? " +
 		"AND schedule_id = ? " +
 		"AND app_id = ?"
-	batch.Query(updateByPartition, status, schedule.PartitionId, schedule.ScheduleId, schedule.AppId)
+	batch.Query(updateByPartition, status, pausedAt(schedule), schedule.PartitionId, schedule.ScheduleId, schedule.AppId)
 
-	// If pausing, delete all future executions
-	if status == store.Paused {
+	// If pausing or suspending, delete all future executions
+	if status == store.Paused || status == store.Suspended {
 		runs, _, err := sdi.getFutureRuns(schedule.ScheduleId, -1, nil)
-		glog.Infof("future runs for schedule id : %s  %+v", schedule.ScheduleId, runs)
+		schedule.Logger().Infof("future runs for schedule id : %s  %+v", schedule.ScheduleId, runs)
 		if err != nil {
 			return schedule, err
 		}
@@ -1222,15 +1506,63 @@ func (sdi *ScheduleDaoImpl) UpdateRecurringScheduleStatus(schedule store.Schedul
 
 	err := sdi.Session.ExecuteBatch(batch)
 
-	glog.Infof("updated status of schedule: %+v", schedule)
+	schedule.Logger().Infof("updated status of schedule: %+v", schedule)
 	schedule.Status = status
 
 	return schedule, err
 }
 
+// pausedAt returns the pause time of the schedule in millis, or nil to clear it if the schedule is not paused
+func pausedAt(schedule store.Schedule) interface{} {
+	if schedule.PausedAt == 0 {
+		return nil
+	}
+	return schedule.PausedAt * constants.SecondsToMillis
+}
+
 // UpdateRecurringSchedule updates a recurring schedule with new values like cron expression, payload,
 // headers, callback_type, and call_back_url. It also deletes all future runs.
 func (sdi *ScheduleDaoImpl) UpdateRecurringSchedule(schedule store.Schedule) (store.Schedule, error) {
+	batch, err := sdi.updateRecurringScheduleBatch(schedule)
+	if err != nil {
+		return schedule, err
+	}
+
+	return sdi.executeRecurringScheduleUpdate(batch, schedule)
+}
+
+// UpdateVersionedRecurringSchedule updates a recurring schedule like UpdateRecurringSchedule and keeps the definition
+// it replaces as a version in the same batch, so that a failed update leaves no version behind.
+func (sdi *ScheduleDaoImpl) UpdateVersionedRecurringSchedule(schedule store.Schedule, version store.ScheduleVersion) (store.Schedule, error) {
+	definition, err := json.Marshal(version.Definition)
+	if err != nil {
+		return schedule, err
+	}
+
+	batch, err := sdi.updateRecurringScheduleBatch(schedule)
+	if err != nil {
+		return schedule, err
+	}
+
+	batch.Query(
+		"INSERT INTO schedule_versions (schedule_id, version_id, version, definition, request_id) VALUES (?, ?, ?, ?, ?) USING TTL ?",
+		version.ScheduleId,
+		version.VersionId,
+		version.Version,
+		string(definition),
+		version.RequestId,
+		sdi.Conf.RetentionConfig.VersionRetentionPeriod)
+
+	return sdi.executeRecurringScheduleUpdate(batch, schedule)
+}
+
+// updateRecurringScheduleBatch builds the batch writing the updated recurring schedule and deleting its future runs
+func (sdi *ScheduleDaoImpl) updateRecurringScheduleBatch(schedule store.Schedule) (*gocql.Batch, error) {
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return nil, err
+	}
+
 	batch := gocql.NewBatch(gocql.LoggedBatch)
 
 	for _, query := range []string{
@@ -1242,7 +1574,20 @@ func (sdi *ScheduleDaoImpl) UpdateRecurringSchedule(schedule store.Schedule) (st
 			"callback_type," +
 			"callback_details," +
 			"cron_expression, " +
-			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			"every, " +
+			"rrule, " +
+			"anchor, " +
+			"status_callback, " +
+			"payload_encoding, " +
+			"priority, " +
+			"region, " +
+			"trace_id, " +
+			"pause_policy, " +
+			"concurrency_policy, " +
+			"group_name, " +
+			"keep_paused, " +
+			"paused_at, " +
+			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 
 		"INSERT INTO recurring_schedules_by_partition (" +
 			"app_id," +
@@ -1252,24 +1597,50 @@ func (sdi *ScheduleDaoImpl) UpdateRecurringSchedule(schedule store.Schedule) (st
 			"callback_type," +
 			"callback_details," +
 			"cron_expression, " +
-			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			"every, " +
+			"rrule, " +
+			"anchor, " +
+			"status_callback, " +
+			"payload_encoding, " +
+			"priority, " +
+			"region, " +
+			"trace_id, " +
+			"pause_policy, " +
+			"concurrency_policy, " +
+			"group_name, " +
+			"keep_paused, " +
+			"paused_at, " +
+			"status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 	} {
 		batch.Query(
 			query,
 			schedule.AppId,
 			schedule.PartitionId,
 			schedule.ScheduleId,
-			schedule.Payload,
+			payload,
 			schedule.GetCallBackType(),
 			schedule.GetCallbackDetails(),
 			schedule.CronExpression,
+			schedule.Every,
+			schedule.RRule,
+			schedule.Anchor*constants.SecondsToMillis,
+			schedule.StatusCallback,
+			schedule.PayloadEncoding,
+			string(schedule.Priority),
+			schedule.Region,
+			schedule.TraceId,
+			string(schedule.PausePolicy),
+			string(schedule.ConcurrencyPolicy),
+			schedule.Group,
+			schedule.KeepPaused,
+			pausedAt(schedule),
 			schedule.Status)
 	}
 
 	// Delete all future runs
 	runs, _, err := sdi.getFutureRuns(schedule.ScheduleId, -1, nil)
 	if err != nil {
-		return schedule, err
+		return nil, err
 	}
 
 	deleteFromRuns := "DELETE FROM recurring_schedule_runs " +
@@ -1291,12 +1662,1350 @@ func (sdi *ScheduleDaoImpl) UpdateRecurringSchedule(schedule store.Schedule) (st
 			run.ScheduleId)
 	}
 
+	return batch, nil
+}
+
+func (sdi *ScheduleDaoImpl) executeRecurringScheduleUpdate(batch *gocql.Batch, schedule store.Schedule) (store.Schedule, error) {
+	err := sdi.Session.ExecuteBatch(batch)
+	if err != nil {
+		schedule.Logger().Errorf("Error: %s while updating recurring schedule: %+v", err.Error(), schedule)
+	} else {
+		schedule.Logger().Infof("Updated schedule with id: %s, updated schedule: %+v", schedule.ScheduleId, schedule)
+	}
+
+	return schedule, err
+}
+
+// UpdateOneTimeSchedule updates a pending one time schedule with new values like schedule time, payload and callback.
+// The row is moved to the time bucket of the new schedule time by deleting the existing row in the same batch.
+// A schedule moved beyond the parking horizon is parked, and a parked schedule moved within it is no longer parked.
+func (sdi *ScheduleDaoImpl) UpdateOneTimeSchedule(existing store.Schedule, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return schedule, err
+	}
+
+	batch := gocql.NewBatch(gocql.LoggedBatch)
+	park := store.ShouldPark(sdi.Conf.ParkingConfig, schedule.ScheduleTime, clock.Now())
+
+	switch {
+	case existing.Parked:
+		batch.Query(
+			deleteFromParkedSchedules,
+			store.ParkingDay(existing.ScheduleTime)*constants.SecondsToMillis,
+			existing.ParkingShard(sdi.Conf.ParkingConfig.Shards),
+			existing.ScheduleId)
+	case park || existing.ScheduleGroup != schedule.ScheduleGroup:
+		batch.Query(
+			deleteFromSchedule,
+			existing.AppId,
+			existing.PartitionId,
+			existing.ScheduleGroup*constants.SecondsToMillis,
+			existing.ScheduleId)
+	}
+
+	if park {
+		query, values := sdi.parkScheduleQuery(schedule, payload, app)
+		batch.Query(query, values...)
+		schedule.Parked = true
+	} else {
+		sdi.insertScheduleQuery(batch, schedule, payload, app)
+		schedule.Parked = false
+	}
+
 	err = sdi.Session.ExecuteBatch(batch)
 	if err != nil {
-		glog.Errorf("Error: %s while updating recurring schedule: %+v", err.Error(), schedule)
+		schedule.Logger().Errorf("Error: %s while updating one time schedule: %+v", err.Error(), schedule)
 	} else {
-		glog.Infof("Updated schedule with id: %s, updated schedule: %+v", schedule.ScheduleId, schedule)
+		schedule.Logger().Infof("Updated schedule with id: %s, updated schedule: %+v", schedule.ScheduleId, schedule)
 	}
 
 	return schedule, err
 }
+
+// insertScheduleQuery adds the insert of a one time schedule to the time bucketed table to the batch
+func (sdi *ScheduleDaoImpl) insertScheduleQuery(batch *gocql.Batch, schedule store.Schedule, payload string, app store.App) {
+	batch.Query(
+		"INSERT INTO schedules ("+
+			"app_id,"+
+			"partition_id,"+
+			"schedule_time_group,"+
+			"schedule_id,"+
+			"schedule_time,"+
+			"payload,"+
+			"callback_type,"+
+			"callback_details,"+
+			"status_callback,"+
+			"payload_encoding,"+
+			"priority,"+
+			"region,"+
+			"trace_id,"+
+			"deferred_from,"+
+			"request_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
+		schedule.AppId,
+		schedule.PartitionId,
+		schedule.ScheduleGroup*constants.SecondsToMillis,
+		schedule.ScheduleId,
+		schedule.ScheduleTime*constants.SecondsToMillis,
+		payload,
+		schedule.GetCallBackType(),
+		schedule.GetCallbackDetails(),
+		schedule.StatusCallback,
+		schedule.PayloadEncoding,
+		string(schedule.Priority),
+		schedule.Region,
+		schedule.TraceId,
+		schedule.DeferredFrom*constants.SecondsToMillis,
+		schedule.RequestId,
+		schedule.GetTTL(app, sdi.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod))
+}
+
+// CreateDeliveryReceipt persists a signed delivery receipt of a callback.
+// The receipt is retained for the same duration as the status of the fired schedule.
+func (s *ScheduleDaoImpl) CreateDeliveryReceipt(receipt store.DeliveryReceipt, ttl int) error {
+	query := "INSERT INTO delivery_receipts (" +
+		"schedule_id," +
+		"dispatched_at," +
+		"app_id," +
+		"callback_type," +
+		"payload_hash," +
+		"response_status," +
+		"key_id," +
+		"signature," +
+		"node," +
+		"reconciliation) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	return s.Session.Query(
+		query,
+		receipt.ScheduleId,
+		receipt.DispatchedAt,
+		receipt.AppId,
+		receipt.CallbackType,
+		receipt.PayloadHash,
+		receipt.ResponseStatus,
+		receipt.KeyId,
+		receipt.Signature,
+		receipt.Node,
+		receipt.Reconciliation,
+		ttl).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+const selectDeliveryReceipts = "SELECT " +
+	"schedule_id," +
+	"dispatched_at," +
+	"app_id," +
+	"callback_type," +
+	"payload_hash," +
+	"response_status," +
+	"key_id," +
+	"signature," +
+	"node," +
+	"reconciliation " +
+	"FROM delivery_receipts "
+
+// GetDeliveryReceipts fetches all the delivery receipts of a schedule, latest first.
+func (s *ScheduleDaoImpl) GetDeliveryReceipts(uuid gocql.UUID) ([]store.DeliveryReceipt, error) {
+	iter := s.Session.Query(selectDeliveryReceipts+"WHERE schedule_id = ?", uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	receipts, err := scanDeliveryReceipts(iter)
+	if err != nil {
+		logger.Errorf("Error: %s while fetching delivery receipts for schedule: %s", err.Error(), uuid.String())
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// GetBulkDeliveryReceipts fetches the delivery receipts of several schedules in a single query,
+// latest first for each schedule.
+func (s *ScheduleDaoImpl) GetBulkDeliveryReceipts(uuids []gocql.UUID) ([]store.DeliveryReceipt, error) {
+	if len(uuids) == 0 {
+		return nil, nil
+	}
+
+	iter := s.Session.Query(selectDeliveryReceipts+"WHERE schedule_id IN ?", uuids).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	receipts, err := scanDeliveryReceipts(iter)
+	if err != nil {
+		logger.Errorf("Error: %s while fetching delivery receipts for %d schedules", err.Error(), len(uuids))
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+func scanDeliveryReceipts(iter db_wrapper.IterInterface) ([]store.DeliveryReceipt, error) {
+	var receipts []store.DeliveryReceipt
+	var receipt store.DeliveryReceipt
+	var dispatchedAt time.Time
+
+	for iter.Scan(
+		&receipt.ScheduleId,
+		&dispatchedAt,
+		&receipt.AppId,
+		&receipt.CallbackType,
+		&receipt.PayloadHash,
+		&receipt.ResponseStatus,
+		&receipt.KeyId,
+		&receipt.Signature,
+		&receipt.Node,
+		&receipt.Reconciliation) {
+		receipt.DispatchedAt = dispatchedAt.UnixNano() / int64(time.Millisecond)
+		receipts = append(receipts, receipt)
+		receipt = store.DeliveryReceipt{}
+	}
+
+	return receipts, iter.Close()
+}
+
+// CreateCallbackCanary persists the canary of an updated callback, replacing any previous canary of the schedule.
+func (s *ScheduleDaoImpl) CreateCallbackCanary(canary store.CallbackCanary) error {
+	query := "INSERT INTO callback_canaries (" +
+		"schedule_id," +
+		"app_id," +
+		"runs," +
+		"percent," +
+		"fired," +
+		"baseline," +
+		"candidate," +
+		"created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+
+	return s.Session.Query(
+		query,
+		canary.ScheduleId,
+		canary.AppId,
+		canary.Runs,
+		canary.Percent,
+		canary.Fired,
+		string(canary.Baseline),
+		string(canary.Candidate),
+		canary.CreatedAt).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetCallbackCanary fetches the canary of the callback of a schedule.
+// Returns gocql.ErrNotFound if the callback of the schedule never had a canary.
+func (s *ScheduleDaoImpl) GetCallbackCanary(uuid gocql.UUID) (store.CallbackCanary, error) {
+	query := "SELECT " +
+		"schedule_id," +
+		"app_id," +
+		"runs," +
+		"percent," +
+		"fired," +
+		"baseline," +
+		"candidate," +
+		"created_at " +
+		"FROM callback_canaries WHERE schedule_id = ?"
+
+	var canary store.CallbackCanary
+	var baseline, candidate string
+	var createdAt time.Time
+
+	err := s.Session.Query(query, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Scan(
+			&canary.ScheduleId,
+			&canary.AppId,
+			&canary.Runs,
+			&canary.Percent,
+			&canary.Fired,
+			&baseline,
+			&candidate,
+			&createdAt)
+	if err != nil {
+		return canary, err
+	}
+
+	canary.Baseline = json.RawMessage(baseline)
+	canary.Candidate = json.RawMessage(candidate)
+	canary.CreatedAt = createdAt.UnixNano() / int64(time.Millisecond)
+	return canary, nil
+}
+
+// UpdateCallbackCanary records the number of runs a canary has fired.
+func (s *ScheduleDaoImpl) UpdateCallbackCanary(canary store.CallbackCanary) error {
+	return s.Session.Query("UPDATE callback_canaries SET fired = ? WHERE schedule_id = ?", canary.Fired, canary.ScheduleId).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// CreateCanaryResult persists the outcome of a run of a canary fired to one of its callbacks.
+// The result is retained for the same duration as the status of the fired run.
+func (s *ScheduleDaoImpl) CreateCanaryResult(result store.CanaryResult, ttl int) error {
+	query := "INSERT INTO canary_results (" +
+		"schedule_id," +
+		"schedule_time," +
+		"target," +
+		"run_id," +
+		"status," +
+		"response_status," +
+		"error_msg," +
+		"latency_ms," +
+		"fired_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	return s.Session.Query(
+		query,
+		result.ScheduleId,
+		result.ScheduleTime*constants.SecondsToMillis,
+		string(result.Target),
+		result.RunId,
+		string(result.Status),
+		result.ResponseStatus,
+		result.ErrorMessage,
+		result.LatencyMillis,
+		result.FiredAt,
+		ttl).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetCanaryResults fetches the results of the canary of the callback of a schedule, latest run first.
+func (s *ScheduleDaoImpl) GetCanaryResults(uuid gocql.UUID) ([]store.CanaryResult, error) {
+	query := "SELECT " +
+		"schedule_id," +
+		"schedule_time," +
+		"target," +
+		"run_id," +
+		"status," +
+		"response_status," +
+		"error_msg," +
+		"latency_ms," +
+		"fired_at " +
+		"FROM canary_results WHERE schedule_id = ?"
+
+	iter := s.Session.Query(query, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var results []store.CanaryResult
+	var result store.CanaryResult
+	var scheduleTime, firedAt time.Time
+	var target, status string
+
+	for iter.Scan(
+		&result.ScheduleId,
+		&scheduleTime,
+		&target,
+		&result.RunId,
+		&status,
+		&result.ResponseStatus,
+		&result.ErrorMessage,
+		&result.LatencyMillis,
+		&firedAt) {
+		result.ScheduleTime = scheduleTime.Unix()
+		result.Target = store.CanaryTarget(target)
+		result.Status = store.Status(status)
+		result.FiredAt = firedAt.UnixNano() / int64(time.Millisecond)
+		results = append(results, result)
+		result = store.CanaryResult{}
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching canary results for schedule: %s", err.Error(), uuid.String())
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetScheduleVersions fetches the replaced definitions of a recurring schedule which are still retained, oldest first.
+// Versions kept before their number was stored are numbered after the version preceding them.
+func (s *ScheduleDaoImpl) GetScheduleVersions(uuid gocql.UUID) ([]store.ScheduleVersion, error) {
+	iter := s.Session.Query("SELECT version_id, version, definition, request_id FROM schedule_versions WHERE schedule_id = ?", uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var versions []store.ScheduleVersion
+	var version store.ScheduleVersion
+	var definition string
+
+	for iter.Scan(&version.VersionId, &version.Version, &definition, &version.RequestId) {
+		if err := json.Unmarshal([]byte(definition), &version.Definition); err != nil {
+			logger.Errorf("Error: %s while decoding version %s of schedule: %s", err.Error(), version.VersionId.String(), uuid.String())
+		}
+		version.ScheduleId = uuid
+		if version.Version == 0 {
+			version.Version = store.NextVersion(versions)
+		}
+		version.ReplacedAt = version.VersionId.Time().UnixNano() / int64(time.Millisecond)
+		versions = append(versions, version)
+		version = store.ScheduleVersion{}
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching versions of schedule: %s", err.Error(), uuid.String())
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// CountSchedules returns the number of schedules stored in a single partition bucket.
+func (s *ScheduleDaoImpl) CountSchedules(appId string, partitionId int, timeBucket time.Time) (int, error) {
+	query := "SELECT COUNT(*) FROM schedules WHERE app_id = ? AND partition_id = ? AND schedule_time_group = ?"
+
+	var count int64
+	if err := s.Session.Query(query, appId, partitionId, timeBucket).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Scan(&count); err != nil {
+		logger.Errorf("Error: %s while counting schedules for app: %s, partition: %d, bucket: %v", err.Error(), appId, partitionId, timeBucket)
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// CountSchedulesInBuckets counts the schedules of several time buckets of a partition in a single query.
+// The counts are returned in the order of the buckets, a bucket without schedules counts zero.
+func (s *ScheduleDaoImpl) CountSchedulesInBuckets(appId string, partitionId int, timeBuckets []time.Time) ([]int, error) {
+	query := "SELECT schedule_time_group, COUNT(*) FROM schedules WHERE app_id = ? AND partition_id = ? AND schedule_time_group IN ? " +
+		"GROUP BY app_id, partition_id, schedule_time_group"
+
+	iter := s.Session.Query(query, appId, partitionId, timeBuckets).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var timeBucket time.Time
+	var count int64
+	countByBucket := make(map[int64]int, len(timeBuckets))
+	for iter.Scan(&timeBucket, &count) {
+		countByBucket[timeBucket.Unix()] = int(count)
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while counting schedules for app: %s, partition: %d, buckets: %v", err.Error(), appId, partitionId, timeBuckets)
+		return nil, err
+	}
+
+	counts := make([]int, len(timeBuckets))
+	for i, bucket := range timeBuckets {
+		counts[i] = countByBucket[bucket.Unix()]
+	}
+	return counts, nil
+}
+
+// MoveSchedule moves a pending one time schedule to another partition of its time bucket.
+// The existing row is deleted in the same batch and, for a run of a recurring schedule,
+// the partition recorded against the run is updated so that deleting the parent still finds it.
+func (s *ScheduleDaoImpl) MoveSchedule(schedule store.Schedule, partitionId int, app store.App) (store.Schedule, error) {
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return schedule, err
+	}
+
+	moved := schedule
+	moved.PartitionId = partitionId
+	ttl := moved.GetTTL(app, s.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod)
+
+	batch := gocql.NewBatch(gocql.LoggedBatch)
+	batch.Query(
+		deleteFromSchedule,
+		schedule.AppId,
+		schedule.PartitionId,
+		schedule.ScheduleGroup*constants.SecondsToMillis,
+		schedule.ScheduleId)
+
+	batch.Query(
+		"INSERT INTO schedules ("+
+			"app_id,"+
+			"partition_id,"+
+			"schedule_time_group,"+
+			"schedule_id,"+
+			"schedule_time,"+
+			"payload,"+
+			"callback_type,"+
+			"callback_details,"+
+			"status_callback,"+
+			"payload_encoding,"+
+			"priority,"+
+			"region,"+
+			"trace_id,"+
+			"deferred_from,"+
+			"request_id,"+
+			"parent_schedule_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?",
+		moved.AppId,
+		moved.PartitionId,
+		moved.ScheduleGroup*constants.SecondsToMillis,
+		moved.ScheduleId,
+		moved.ScheduleTime*constants.SecondsToMillis,
+		payload,
+		moved.GetCallBackType(),
+		moved.GetCallbackDetails(),
+		moved.StatusCallback,
+		moved.PayloadEncoding,
+		string(moved.Priority),
+		moved.Region,
+		moved.TraceId,
+		moved.DeferredFrom*constants.SecondsToMillis,
+		moved.RequestId,
+		moved.ParentScheduleId,
+		ttl)
+
+	if !util.IsZeroUUID(moved.ParentScheduleId) {
+		batch.Query(
+			"UPDATE recurring_schedule_runs USING TTL ? "+
+				"SET partition_id = ? "+
+				"WHERE parent_schedule_id = ? "+
+				"AND schedule_time_group = ?",
+			ttl,
+			moved.PartitionId,
+			moved.ParentScheduleId,
+			moved.ScheduleGroup*constants.SecondsToMillis)
+	}
+
+	if err = s.Session.ExecuteBatch(batch); err != nil {
+		moved.Logger().Errorf("Error: %s while moving schedule from partition %d to %d", err.Error(), schedule.PartitionId, partitionId)
+		return schedule, err
+	}
+
+	return moved, nil
+}
+
+const deleteFromParkedSchedules string = "DELETE FROM parked_schedules " +
+	"WHERE parking_day = ? " +
+	"AND shard = ? " +
+	"AND schedule_id = ?"
+
+const selectParkedSchedule string = "SELECT " +
+	"schedule_id," +
+	"app_id," +
+	"partition_id," +
+	"payload," +
+	"schedule_time," +
+	"callback_type," +
+	"callback_details," +
+	"status_callback," +
+	"payload_encoding," +
+	"priority, " +
+	"region, " +
+	"trace_id, " +
+	"deferred_from, " +
+	"request_id "
+
+// parkScheduleQuery returns the insert of a one time schedule to the parking table, bucketed by the day of its
+// schedule time. The row expires with the retention of the fired schedules like the row of the promoted schedule.
+func (s *ScheduleDaoImpl) parkScheduleQuery(schedule store.Schedule, payload string, app store.App) (string, []interface{}) {
+	query := "INSERT INTO parked_schedules (" +
+		"parking_day," +
+		"shard," +
+		"schedule_id," +
+		"app_id," +
+		"partition_id," +
+		"schedule_time," +
+		"payload," +
+		"callback_type," +
+		"callback_details," +
+		"status_callback," +
+		"payload_encoding," +
+		"priority," +
+		"region," +
+		"trace_id," +
+		"deferred_from," +
+		"request_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	return query, []interface{}{
+		store.ParkingDay(schedule.ScheduleTime) * constants.SecondsToMillis,
+		schedule.ParkingShard(s.Conf.ParkingConfig.Shards),
+		schedule.ScheduleId,
+		schedule.AppId,
+		schedule.PartitionId,
+		schedule.ScheduleTime * constants.SecondsToMillis,
+		payload,
+		schedule.GetCallBackType(),
+		schedule.GetCallbackDetails(),
+		schedule.StatusCallback,
+		schedule.PayloadEncoding,
+		string(schedule.Priority),
+		schedule.Region,
+		schedule.TraceId,
+		schedule.DeferredFrom * constants.SecondsToMillis,
+		schedule.RequestId,
+		schedule.GetTTL(app, s.Conf.AppLevelConfiguration.FiredScheduleRetentionPeriod),
+	}
+}
+
+// parkedScheduleFromMap creates a parked schedule from a row of the parking table.
+// The time bucket is derived from the schedule time as for the schedules which are not parked.
+func parkedScheduleFromMap(m map[string]interface{}) (store.Schedule, error) {
+	if scheduleTime, ok := m["schedule_time"].(time.Time); ok {
+		m["schedule_time_group"] = time.Unix(60*(scheduleTime.Unix()/60), 0)
+	}
+
+	var schedule store.Schedule
+	if err := schedule.CreateScheduleFromCassandraMap(m); err != nil {
+		return schedule, err
+	}
+	schedule.Parked = true
+	return schedule, nil
+}
+
+// Find a parked schedule with the supplied id.
+// Returns a non nil error if fetching the details failed or if no row with the id is found.
+func (s *ScheduleDaoImpl) getParkedSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	query := selectParkedSchedule +
+		"FROM view_parked_schedules " +
+		"WHERE schedule_id = ? LIMIT 1"
+
+	_map := make(map[string]interface{})
+	err := s.Session.Query(query, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		MapScan(_map)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	return parkedScheduleFromMap(_map)
+}
+
+// GetParkedSchedules gets the schedules parked in a shard of a day.
+// Returns the schedules read along with the errors of the rows which could not be read.
+func (s *ScheduleDaoImpl) GetParkedSchedules(day time.Time, shard int) ([]store.Schedule, []error) {
+	query := selectParkedSchedule +
+		"FROM parked_schedules " +
+		"WHERE parking_day = ? " +
+		"AND shard = ?"
+
+	var schedules []store.Schedule
+	var errs []error
+
+	_map := make(map[string]interface{})
+	iter := s.Session.Query(query, day.Unix()*constants.SecondsToMillis, shard).
+		Consistency(s.consistency(s.Conf.ScheduleDB.OperationConsistency.PollRead)).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	for iter.MapScan(_map) {
+		if schedule, err := parkedScheduleFromMap(_map); err != nil {
+			errs = append(errs, err)
+		} else {
+			schedules = append(schedules, schedule)
+		}
+
+		_map = make(map[string]interface{})
+	}
+
+	if err := iter.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return schedules, errs
+}
+
+// PromoteSchedule moves a parked schedule to the time bucketed tables, from where it fires like any other schedule.
+// The partition is derived again as the partitions of the app may have been resized since the schedule was parked.
+func (s *ScheduleDaoImpl) PromoteSchedule(schedule store.Schedule, app store.App) (store.Schedule, error) {
+	payload, err := store.EncodePayload(schedule.Payload, schedule.PayloadEncoding)
+	if err != nil {
+		return schedule, err
+	}
+
+	promoted := schedule
+	promoted.PartitionId = promoted.PartitionFor(app.Partitions)
+	promoted.ScheduleGroup = 60 * (promoted.ScheduleTime / 60)
+	promoted.Parked = false
+
+	batch := gocql.NewBatch(gocql.LoggedBatch)
+	batch.Query(
+		deleteFromParkedSchedules,
+		store.ParkingDay(schedule.ScheduleTime)*constants.SecondsToMillis,
+		schedule.ParkingShard(s.Conf.ParkingConfig.Shards),
+		schedule.ScheduleId)
+	s.insertScheduleQuery(batch, promoted, payload, app)
+
+	if err = s.Session.ExecuteBatch(batch); err != nil {
+		schedule.Logger().Errorf("Error: %s while promoting parked schedule", err.Error())
+		return schedule, err
+	}
+
+	return promoted, nil
+}
+
+// UpsertJob persists the state of an asynchronous job, expiring it after the ttl in seconds.
+func (s *ScheduleDaoImpl) UpsertJob(job store.Job, ttl int) error {
+	query := "INSERT INTO jobs (" +
+		"job_id," +
+		"type," +
+		"app_id," +
+		"status," +
+		"request," +
+		"total," +
+		"succeeded," +
+		"failed," +
+		"created_at," +
+		"updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	return s.Session.Query(
+		query,
+		job.JobId,
+		string(job.Type),
+		job.AppId,
+		string(job.Status),
+		string(job.Request),
+		job.Total,
+		job.Succeeded,
+		job.Failed,
+		job.CreatedAt,
+		job.UpdatedAt,
+		ttl).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetJob fetches an asynchronous job.
+// Returns gocql.ErrNotFound if the job does not exist or has expired.
+func (s *ScheduleDaoImpl) GetJob(uuid gocql.UUID) (store.Job, error) {
+	query := "SELECT " +
+		"job_id," +
+		"type," +
+		"app_id," +
+		"status," +
+		"request," +
+		"total," +
+		"succeeded," +
+		"failed," +
+		"created_at," +
+		"updated_at," +
+		"cancel_requested " +
+		"FROM jobs WHERE job_id = ?"
+
+	var job store.Job
+	var jobType, status, request string
+	var createdAt, updatedAt time.Time
+
+	err := s.Session.Query(query, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Scan(
+			&job.JobId,
+			&jobType,
+			&job.AppId,
+			&status,
+			&request,
+			&job.Total,
+			&job.Succeeded,
+			&job.Failed,
+			&createdAt,
+			&updatedAt,
+			&job.CancelRequested)
+	if err != nil {
+		return job, err
+	}
+
+	job.Type = store.JobType(jobType)
+	job.Status = store.JobStatus(status)
+	if request != "" {
+		job.Request = json.RawMessage(request)
+	}
+	job.CreatedAt = createdAt.UnixNano() / int64(time.Millisecond)
+	job.UpdatedAt = updatedAt.UnixNano() / int64(time.Millisecond)
+	return job, nil
+}
+
+// SetJobCancelRequested flags an asynchronous job to be cancelled, or clears the flag.
+// The flag is written on its own so that the progress saved by the node running the job does not overwrite it.
+func (s *ScheduleDaoImpl) SetJobCancelRequested(uuid gocql.UUID, cancelRequested bool, ttl int) error {
+	return s.Session.Query("UPDATE jobs USING TTL ? SET cancel_requested = ? WHERE job_id = ?", ttl, cancelRequested, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// CreateJobItem persists the result of a job for one of its items, expiring it after the ttl in seconds.
+func (s *ScheduleDaoImpl) CreateJobItem(item store.JobItem, ttl int) error {
+	return s.Session.Query(
+		"INSERT INTO job_items (job_id, item_id, status, error) VALUES (?, ?, ?, ?) USING TTL ?",
+		item.JobId,
+		item.ItemId,
+		string(item.Status),
+		item.Error,
+		ttl).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetJobItems fetches the results of the items a job has processed so far.
+func (s *ScheduleDaoImpl) GetJobItems(uuid gocql.UUID) ([]store.JobItem, error) {
+	iter := s.Session.Query("SELECT item_id, status, error FROM job_items WHERE job_id = ?", uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var items []store.JobItem
+	var item store.JobItem
+	var status string
+
+	for iter.Scan(&item.ItemId, &status, &item.Error) {
+		item.JobId = uuid
+		item.Status = store.Status(status)
+		items = append(items, item)
+		item = store.JobItem{}
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching items of job: %s", err.Error(), uuid.String())
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CreateCallbackAttempt persists the execution log of an attempt of a callback.
+// The attempt is retained for the same duration as the status of the fired schedule.
+func (s *ScheduleDaoImpl) CreateCallbackAttempt(attempt store.CallbackAttempt, ttl int) error {
+	query := "INSERT INTO callback_attempts (" +
+		"schedule_id," +
+		"attempted_at," +
+		"attempt," +
+		"app_id," +
+		"method," +
+		"url," +
+		"request_snippet," +
+		"response_status," +
+		"response_snippet," +
+		"error," +
+		"latency) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	return s.Session.Query(
+		query,
+		attempt.ScheduleId,
+		attempt.AttemptedAt,
+		attempt.Attempt,
+		attempt.AppId,
+		attempt.Method,
+		attempt.Url,
+		attempt.RequestSnippet,
+		attempt.ResponseStatus,
+		attempt.ResponseSnippet,
+		attempt.Error,
+		attempt.LatencyMillis,
+		ttl).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetCallbackAttempts fetches the execution logs of the callback attempts of a schedule, oldest first.
+func (s *ScheduleDaoImpl) GetCallbackAttempts(uuid gocql.UUID) ([]store.CallbackAttempt, error) {
+	query := "SELECT " +
+		"schedule_id," +
+		"attempted_at," +
+		"attempt," +
+		"app_id," +
+		"method," +
+		"url," +
+		"request_snippet," +
+		"response_status," +
+		"response_snippet," +
+		"error," +
+		"latency " +
+		"FROM callback_attempts WHERE schedule_id = ?"
+
+	iter := s.Session.Query(query, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var attempts []store.CallbackAttempt
+	var attempt store.CallbackAttempt
+	var attemptedAt time.Time
+
+	for iter.Scan(
+		&attempt.ScheduleId,
+		&attemptedAt,
+		&attempt.Attempt,
+		&attempt.AppId,
+		&attempt.Method,
+		&attempt.Url,
+		&attempt.RequestSnippet,
+		&attempt.ResponseStatus,
+		&attempt.ResponseSnippet,
+		&attempt.Error,
+		&attempt.LatencyMillis) {
+		attempt.AttemptedAt = attemptedAt.UnixNano() / int64(time.Millisecond)
+		attempts = append(attempts, attempt)
+		attempt = store.CallbackAttempt{}
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching callback attempts for schedule: %s", err.Error(), uuid.String())
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// CreateDueRun stores a run of a pull callback for the consumers of its app to poll for.
+func (s *ScheduleDaoImpl) CreateDueRun(run store.DueRun, ttl int) error {
+	query := "INSERT INTO due_runs (" +
+		"app_id," +
+		"schedule_id," +
+		"parent_schedule_id," +
+		"payload," +
+		"schedule_time," +
+		"deliveries) VALUES (?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	var parentScheduleId *gocql.UUID
+	if run.ParentScheduleId != "" {
+		if parsed, err := gocql.ParseUUID(run.ParentScheduleId); err == nil {
+			parentScheduleId = &parsed
+		}
+	}
+
+	return s.Session.Query(
+		query,
+		run.AppId,
+		run.ScheduleId,
+		parentScheduleId,
+		run.Payload,
+		time.Unix(run.ScheduleTime, 0),
+		run.Deliveries,
+		ttl).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetDueRuns fetches up to limit due runs of an app, whether they are leased or not.
+func (s *ScheduleDaoImpl) GetDueRuns(appId string, limit int) ([]store.DueRun, error) {
+	iter := s.Session.Query(selectDueRuns+"WHERE app_id = ? LIMIT ?", appId, limit).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	runs, err := scanDueRuns(iter)
+	if err != nil {
+		logger.Errorf("Error: %s while fetching due runs of app: %s", err.Error(), appId)
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// GetDueRun fetches a due run of an app.
+// Returns gocql.ErrNotFound if the run is not due or was acknowledged already
+func (s *ScheduleDaoImpl) GetDueRun(appId string, uuid gocql.UUID) (store.DueRun, error) {
+	iter := s.Session.Query(selectDueRuns+"WHERE app_id = ? AND schedule_id = ?", appId, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	runs, err := scanDueRuns(iter)
+	switch {
+	case err != nil:
+		logger.Errorf("Error: %s while fetching due run: %s of app: %s", err.Error(), uuid.String(), appId)
+		return store.DueRun{}, err
+	case len(runs) == 0:
+		return store.DueRun{}, gocql.ErrNotFound
+	default:
+		return runs[0], nil
+	}
+}
+
+const selectDueRuns = "SELECT " +
+	"app_id," +
+	"schedule_id," +
+	"parent_schedule_id," +
+	"payload," +
+	"schedule_time," +
+	"deliveries," +
+	"leased_until " +
+	"FROM due_runs "
+
+func scanDueRuns(iter db_wrapper.IterInterface) ([]store.DueRun, error) {
+	var runs []store.DueRun
+	var run store.DueRun
+	var parentScheduleId gocql.UUID
+	var scheduleTime, leasedUntil time.Time
+
+	for iter.Scan(
+		&run.AppId,
+		&run.ScheduleId,
+		&parentScheduleId,
+		&run.Payload,
+		&scheduleTime,
+		&run.Deliveries,
+		&leasedUntil) {
+		if !util.IsZeroUUID(parentScheduleId) {
+			run.ParentScheduleId = parentScheduleId.String()
+		}
+		run.ScheduleTime = scheduleTime.Unix()
+		if !leasedUntil.IsZero() {
+			run.LeasedUntil = leasedUntil.UnixNano() / int64(time.Millisecond)
+		}
+		runs = append(runs, run)
+		run = store.DueRun{}
+		parentScheduleId = gocql.UUID{}
+		scheduleTime, leasedUntil = time.Time{}, time.Time{}
+	}
+
+	return runs, iter.Close()
+}
+
+// LeaseDueRun hands a due run to a consumer until the supplied time. The run is leased only if no other consumer
+// leased it since it was fetched, which is told by its deliveries.
+// Returns whether the run was leased
+func (s *ScheduleDaoImpl) LeaseDueRun(run store.DueRun, leasedUntil time.Time, ttl int) (bool, error) {
+	query := "UPDATE due_runs USING TTL ? SET leased_until = ?, deliveries = ? WHERE app_id = ? AND schedule_id = ? IF deliveries = ?"
+
+	// the deliveries of the run are read back when another consumer leased it first
+	var deliveries int
+	applied, err := s.Session.Query(query, ttl, leasedUntil, run.Deliveries+1, run.AppId, run.ScheduleId, run.Deliveries).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		ScanCAS(&deliveries)
+	if err != nil {
+		logger.Errorf("Error: %s while leasing due run: %s of app: %s", err.Error(), run.ScheduleId.String(), run.AppId)
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// DeleteDueRun removes a due run once it is acknowledged or out of deliveries.
+// Returns whether the run was there to be removed, so that it is completed once
+func (s *ScheduleDaoImpl) DeleteDueRun(appId string, uuid gocql.UUID) (bool, error) {
+	applied, err := s.Session.Query("DELETE FROM due_runs WHERE app_id = ? AND schedule_id = ? IF EXISTS", appId, uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		ScanCAS()
+	if err != nil {
+		logger.Errorf("Error: %s while deleting due run: %s of app: %s", err.Error(), uuid.String(), appId)
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// ClaimSchedules writes the claims of the fires of schedules of a partition to the intent log in a single batch.
+func (s *ScheduleDaoImpl) ClaimSchedules(intents []store.FireIntent, ttl int) error {
+	if len(intents) == 0 {
+		return nil
+	}
+
+	const query = "INSERT INTO fire_intents (" +
+		"app_id," +
+		"partition_id," +
+		"claim_bucket," +
+		"schedule_id," +
+		"node," +
+		"attempt," +
+		"claimed_at) VALUES (?, ?, ?, ?, ?, ?, ?) USING TTL ?"
+
+	batch := gocql.NewBatch(gocql.UnloggedBatch)
+	for _, intent := range intents {
+		batch.
+			RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+			Query(
+				query,
+				intent.AppId,
+				intent.PartitionId,
+				intent.Bucket(),
+				intent.ScheduleId,
+				intent.Node,
+				intent.Attempt,
+				time.Unix(intent.ClaimedAt, 0),
+				ttl)
+	}
+
+	return s.Session.ExecuteBatch(batch)
+}
+
+// AcknowledgeSchedules removes the claims of the fires whose outcome was written from the intent log in a single batch.
+func (s *ScheduleDaoImpl) AcknowledgeSchedules(intents []store.FireIntent) error {
+	if len(intents) == 0 {
+		return nil
+	}
+
+	const query = "DELETE FROM fire_intents WHERE app_id = ? AND partition_id = ? AND claim_bucket = ? AND schedule_id = ?"
+
+	batch := gocql.NewBatch(gocql.UnloggedBatch)
+	for _, intent := range intents {
+		batch.
+			RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+			Query(query, intent.AppId, intent.PartitionId, intent.Bucket(), intent.ScheduleId)
+	}
+
+	return s.Session.ExecuteBatch(batch)
+}
+
+// GetFireIntents fetches the claims of the fires of a partition made within the minute of the claim bucket.
+func (s *ScheduleDaoImpl) GetFireIntents(appId string, partitionId int, claimBucket time.Time) ([]store.FireIntent, error) {
+	query := "SELECT " +
+		"app_id," +
+		"partition_id," +
+		"schedule_id," +
+		"node," +
+		"attempt," +
+		"claimed_at " +
+		"FROM fire_intents " +
+		"WHERE app_id = ? AND partition_id = ? AND claim_bucket = ?"
+
+	iter := s.Session.Query(query, appId, partitionId, claimBucket).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var intents []store.FireIntent
+	var intent store.FireIntent
+	var claimedAt time.Time
+
+	for iter.Scan(
+		&intent.AppId,
+		&intent.PartitionId,
+		&intent.ScheduleId,
+		&intent.Node,
+		&intent.Attempt,
+		&claimedAt) {
+		intent.ClaimedAt = claimedAt.Unix()
+		intents = append(intents, intent)
+		intent = store.FireIntent{}
+		claimedAt = time.Time{}
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching fire intents of app: %s, partitionId: %d, claimBucket: %v", err.Error(), appId, partitionId, claimBucket)
+		return nil, err
+	}
+
+	return intents, nil
+}
+
+// AcquireLock leases the lock to its owner for its lease. The lock is acquired if no one holds it, and renewed if its
+// owner holds it already, keeping the time it was first acquired at.
+// Returns the acquired lock, or the lock as held by its current owner when it was not acquired
+func (s *ScheduleDaoImpl) AcquireLock(lock store.Lock, now time.Time) (store.Lock, bool, error) {
+	acquired := lock.Lease(now)
+
+	// the columns of the lock are read back when it is held already, in the order of the table
+	var current store.Lock
+	var acquiredAt time.Time
+	applied, err := s.Session.Query("INSERT INTO locks (app_id, name, owner, acquired_at) VALUES (?, ?, ?, ?) IF NOT EXISTS USING TTL ?",
+		lock.AppId, lock.Name, lock.Owner, time.Unix(0, acquired.AcquiredAt*int64(time.Millisecond)), lock.LeaseSeconds).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		ScanCAS(&current.AppId, &current.Name, &acquiredAt, &current.Owner)
+	if err != nil {
+		logger.Errorf("Error: %s while acquiring lock: %s of app: %s", err.Error(), lock.Name, lock.AppId)
+		return store.Lock{}, false, err
+	}
+	if applied {
+		return acquired, true, nil
+	}
+
+	current.AcquiredAt = acquiredAt.UnixNano() / int64(time.Millisecond)
+	if current.Owner != lock.Owner {
+		return current, false, nil
+	}
+
+	lock.AcquiredAt = current.AcquiredAt
+	renewed := lock.Lease(now)
+	applied, err = s.Session.Query("UPDATE locks USING TTL ? SET owner = ?, acquired_at = ? WHERE app_id = ? AND name = ? IF owner = ?",
+		lock.LeaseSeconds, lock.Owner, acquiredAt, lock.AppId, lock.Name, lock.Owner).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		ScanCAS(&current.Owner)
+	if err != nil {
+		logger.Errorf("Error: %s while renewing lock: %s of app: %s", err.Error(), lock.Name, lock.AppId)
+		return store.Lock{}, false, err
+	}
+	if !applied {
+		// the lease ran out and the lock changed hands in the meantime
+		return current, false, nil
+	}
+	return renewed, true, nil
+}
+
+// ReleaseLock releases the lock if its owner holds it.
+// Returns whether it was released, along with its current owner when it was not
+func (s *ScheduleDaoImpl) ReleaseLock(lock store.Lock) (store.Lock, bool, error) {
+	current := store.Lock{AppId: lock.AppId, Name: lock.Name}
+	applied, err := s.Session.Query("DELETE FROM locks WHERE app_id = ? AND name = ? IF owner = ?", lock.AppId, lock.Name, lock.Owner).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		ScanCAS(&current.Owner)
+	if err != nil {
+		logger.Errorf("Error: %s while releasing lock: %s of app: %s", err.Error(), lock.Name, lock.AppId)
+		return store.Lock{}, false, err
+	}
+
+	return current, applied, nil
+}
+
+// archiveBatchSize is the number of schedules indexed or deleted per batch when a time bucket is archived
+const archiveBatchSize = 50
+
+// IndexArchivedSchedules records the objects the schedules were archived to, by schedule id and by the recurring
+// schedule of the runs, so that they can be looked up once their rows are deleted.
+// The entries expire after ttl seconds, 0 keeps them.
+func (s *ScheduleDaoImpl) IndexArchivedSchedules(archived []store.ArchivedSchedule, ttl int) error {
+	for start := 0; start < len(archived); start += archiveBatchSize {
+		end := start + archiveBatchSize
+		if end > len(archived) {
+			end = len(archived)
+		}
+
+		batch := gocql.NewBatch(gocql.UnloggedBatch)
+		for _, entry := range archived[start:end] {
+			var parentScheduleId *gocql.UUID
+			if !util.IsZeroUUID(entry.ParentScheduleId) {
+				parentScheduleId = &entry.ParentScheduleId
+			}
+			batch.Query("INSERT INTO archived_schedules (schedule_id, app_id, parent_schedule_id, schedule_time, object_key) "+
+				"VALUES (?, ?, ?, ?, ?) USING TTL ?",
+				entry.ScheduleId, entry.AppId, parentScheduleId, time.Unix(entry.ScheduleTime, 0), entry.ObjectKey, ttl)
+
+			if parentScheduleId != nil {
+				batch.Query("INSERT INTO archived_runs (parent_schedule_id, schedule_time, schedule_id, app_id, object_key) "+
+					"VALUES (?, ?, ?, ?, ?) USING TTL ?",
+					entry.ParentScheduleId, time.Unix(entry.ScheduleTime, 0), entry.ScheduleId, entry.AppId, entry.ObjectKey, ttl)
+			}
+		}
+
+		if err := s.Session.ExecuteBatch(batch); err != nil {
+			logger.Errorf("Error: %s while indexing %d archived schedules", err.Error(), end-start)
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteArchivedBucket removes the archived schedules of a time bucket of a partition along with their statuses and,
+// for the runs, their rows in the runs of their recurring schedule.
+// The time bucket is a partition of its own and is removed with a single partition tombstone.
+func (s *ScheduleDaoImpl) DeleteArchivedBucket(appId string, partitionId int, timeBucket time.Time, schedules []store.Schedule) error {
+	for start := 0; start < len(schedules); start += archiveBatchSize {
+		end := start + archiveBatchSize
+		if end > len(schedules) {
+			end = len(schedules)
+		}
+
+		batch := gocql.NewBatch(gocql.UnloggedBatch)
+		for _, schedule := range schedules[start:end] {
+			batch.Query("DELETE FROM status WHERE app_id = ? AND partition_id = ? AND schedule_id = ?",
+				schedule.AppId, schedule.PartitionId, schedule.ScheduleId)
+
+			if !util.IsZeroUUID(schedule.ParentScheduleId) {
+				batch.Query("DELETE FROM recurring_schedule_runs WHERE parent_schedule_id = ? AND schedule_time_group = ?",
+					schedule.ParentScheduleId, schedule.ScheduleGroup*constants.SecondsToMillis)
+			}
+		}
+
+		if err := s.Session.ExecuteBatch(batch); err != nil {
+			logger.Errorf("Error: %s while deleting the statuses of %d archived schedules of app: %s", err.Error(), end-start, appId)
+			return err
+		}
+	}
+
+	return s.Session.Query("DELETE FROM schedules WHERE app_id = ? AND partition_id = ? AND schedule_time_group = ?",
+		appId, partitionId, timeBucket).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+}
+
+// GetArchivedSchedule finds the object a schedule was archived to.
+// Returns gocql.ErrNotFound if the schedule was not archived or its entry expired
+func (s *ScheduleDaoImpl) GetArchivedSchedule(uuid gocql.UUID) (store.ArchivedSchedule, error) {
+	iter := s.Session.Query("SELECT schedule_id, app_id, parent_schedule_id, schedule_time, object_key "+
+		"FROM archived_schedules WHERE schedule_id = ?", uuid).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var archived store.ArchivedSchedule
+	var parentScheduleId gocql.UUID
+	var scheduleTime time.Time
+	found := iter.Scan(&archived.ScheduleId, &archived.AppId, &parentScheduleId, &scheduleTime, &archived.ObjectKey)
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching archived schedule: %s", err.Error(), uuid.String())
+		return store.ArchivedSchedule{}, err
+	}
+	if !found {
+		return store.ArchivedSchedule{}, gocql.ErrNotFound
+	}
+
+	archived.ParentScheduleId = parentScheduleId
+	archived.ScheduleTime = scheduleTime.Unix()
+	return archived, nil
+}
+
+// GetArchivedRuns finds the objects the latest limit archived runs of a recurring schedule were archived to,
+// latest first
+func (s *ScheduleDaoImpl) GetArchivedRuns(parentScheduleId gocql.UUID, limit int) ([]store.ArchivedSchedule, error) {
+	iter := s.Session.Query("SELECT schedule_id, app_id, schedule_time, object_key "+
+		"FROM archived_runs WHERE parent_schedule_id = ? LIMIT ?", parentScheduleId, limit).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var runs []store.ArchivedSchedule
+	var run store.ArchivedSchedule
+	var scheduleTime time.Time
+	for iter.Scan(&run.ScheduleId, &run.AppId, &scheduleTime, &run.ObjectKey) {
+		run.ParentScheduleId = parentScheduleId
+		run.ScheduleTime = scheduleTime.Unix()
+		runs = append(runs, run)
+		run = store.ArchivedSchedule{}
+	}
+
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching archived runs of schedule: %s", err.Error(), parentScheduleId.String())
+		return nil, err
+	}
+	return runs, nil
+}
+
+// purgeBatchSize is the number of schedules whose history is deleted per batch when a time bucket is purged
+const purgeBatchSize = 50
+
+// PurgeBucket removes a time bucket of a partition of an app along with the delivery receipts, callback attempts,
+// versions and archive entries of its schedules. Returns the number of schedules removed.
+// The bucket itself is removed last, so that a failed purge can be run again.
+func (s *ScheduleDaoImpl) PurgeBucket(appId string, partitionId int, timeBucket time.Time) (int, error) {
+	iter := s.Session.Query("SELECT schedule_id FROM schedules WHERE app_id = ? AND partition_id = ? AND schedule_time_group = ?",
+		appId, partitionId, timeBucket).
+		PageSize(s.Conf.ScheduleDB.DBConfig.PageSize).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Iter()
+
+	var scheduleIds []gocql.UUID
+	var scheduleId gocql.UUID
+	for iter.Scan(&scheduleId) {
+		scheduleIds = append(scheduleIds, scheduleId)
+	}
+	if err := iter.Close(); err != nil {
+		logger.Errorf("Error: %s while fetching the schedules to purge of app: %s, partition: %d, bucket: %v", err.Error(), appId, partitionId, timeBucket)
+		return 0, err
+	}
+
+	for start := 0; start < len(scheduleIds); start += purgeBatchSize {
+		end := start + purgeBatchSize
+		if end > len(scheduleIds) {
+			end = len(scheduleIds)
+		}
+
+		batch := gocql.NewBatch(gocql.UnloggedBatch)
+		for _, id := range scheduleIds[start:end] {
+			batch.Query("DELETE FROM delivery_receipts WHERE schedule_id = ?", id)
+			batch.Query("DELETE FROM callback_attempts WHERE schedule_id = ?", id)
+			batch.Query("DELETE FROM schedule_versions WHERE schedule_id = ?", id)
+			batch.Query("DELETE FROM archived_schedules WHERE schedule_id = ?", id)
+		}
+
+		if err := s.Session.ExecuteBatch(batch); err != nil {
+			logger.Errorf("Error: %s while purging the history of %d schedules of app: %s", err.Error(), end-start, appId)
+			return 0, err
+		}
+	}
+
+	err := s.Session.Query("DELETE FROM schedules WHERE app_id = ? AND partition_id = ? AND schedule_time_group = ?",
+		appId, partitionId, timeBucket).
+		RetryPolicy(&gocql.SimpleRetryPolicy{NumRetries: s.Conf.ScheduleDB.DBConfig.NumRetry}).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return len(scheduleIds), nil
+}
+
+// PurgeAppRecords removes the statuses of the schedules of every partition of an app and its due runs
+func (s *ScheduleDaoImpl) PurgeAppRecords(app store.App) error {
+	batch := gocql.NewBatch(gocql.LoggedBatch)
+	for partition := 0; partition < int(app.Partitions); partition++ {
+		batch.Query("DELETE FROM status WHERE app_id = ? AND partition_id = ?", app.AppId, partition)
+	}
+	batch.Query("DELETE FROM due_runs WHERE app_id = ?", app.AppId)
+	batch.Query("DELETE FROM locks WHERE app_id = ?", app.AppId)
+
+	return s.Session.ExecuteBatch(batch)
+}
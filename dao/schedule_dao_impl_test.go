@@ -20,11 +20,14 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/gocql/gocql"
 	"github.com/golang/mock/gomock"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/mocks"
 	s "github.com/myntra/goscheduler/store"
 	"github.com/stretchr/testify/assert"
@@ -36,9 +39,11 @@ func setupMocks(t *testing.T) (*ScheduleDaoImpl, *mocks.MockSessionInterface, *m
 	s.Registry[constants.DefaultCallback] = func() s.Callback { return &s.HttpCallback{} }
 	dao := &ScheduleDaoImpl{
 		Conf: &conf.Configuration{
-			Cluster: conf.ClusterConfig{
-				PageSize: 10,
-				NumRetry: 2,
+			ScheduleDB: conf.ScheduleDBConfig{
+				DBConfig: conf.CassandraConfig{
+					PageSize: 10,
+					NumRetry: 2,
+				},
 			},
 			AggregateSchedulesConfig: conf.AggregateSchedulesConfig{
 				FlushPeriod: 60,
@@ -94,24 +99,52 @@ func TestScheduleDaoImpl_CreateSchedule(t *testing.T) {
 	}
 
 	// Test for recurring schedule
-	createdSchedule, err := dao.CreateSchedule(schedule, app)
+	createdSchedule, err := dao.CreateSchedule(context.Background(), schedule, app)
 	assert.NoError(t, err)
 	assert.Equal(t, schedule.Payload, createdSchedule.Payload)
 	assert.True(t, createdSchedule.IsRecurring())
 
 	// Test for one-time schedule
 	schedule.CronExpression = ""
-	createdSchedule, err = dao.CreateSchedule(schedule, app)
+	createdSchedule, err = dao.CreateSchedule(context.Background(), schedule, app)
 	assert.NoError(t, err)
 	assert.Equal(t, schedule.Payload, createdSchedule.Payload)
 	assert.False(t, createdSchedule.IsRecurring())
 }
 
+func TestScheduleDaoImpl_CreateSchedulePersistsRequestId(t *testing.T) {
+	dao, m, mq, _, ctrl := setupMocks(t)
+	defer ctrl.Finish()
+
+	var values []interface{}
+	m.EXPECT().Query(gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, v ...interface{}) db_wrapper.QueryInterface {
+		values = v
+		return mq
+	})
+	mq.EXPECT().Consistency(gomock.Any()).Return(mq)
+	mq.EXPECT().Exec().Return(nil)
+
+	schedule := s.Schedule{
+		ScheduleId:   gocql.TimeUUID(),
+		AppId:        "Test",
+		Payload:      "Test Payload",
+		ScheduleTime: time.Now().Add(time.Hour).Unix(),
+		Callback:     &s.HttpCallback{Type: "http", Details: s.Details{Url: "http://example.com/callback", Method: "POST"}},
+	}
+
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	created, err := dao.CreateSchedule(ctx, schedule, s.App{AppId: "Test", Partitions: 2, Active: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "req-1", created.RequestId)
+	assert.Contains(t, values, "req-1")
+}
+
 func TestScheduleDaoImpl_GetRecurringScheduleByPartition(t *testing.T) {
 	dao, m, mq, mItr, ctrl := setupMocks(t)
 	defer ctrl.Finish()
 
 	m.EXPECT().Query(gomock.Any(), gomock.Any()).Return(mq).AnyTimes()
+	mq.EXPECT().Consistency(gomock.Any()).Return(mq).AnyTimes()
 	mq.EXPECT().RetryPolicy(gomock.Any()).Return(mq).AnyTimes()
 	mq.EXPECT().Iter().Return(mItr).AnyTimes()
 	mItr.EXPECT().MapScan(gomock.Any()).Return(false).AnyTimes()
@@ -280,7 +313,11 @@ func TestScheduleDaoImpl_UpdateStatus(t *testing.T) {
 		},
 	}
 
-	m.EXPECT().ExecuteBatch(gomock.Any()).Return(nil).AnyTimes()
+	dao.Conf.ScheduleDB.DBConfig.Consistency = gocql.One
+	dao.Conf.ScheduleDB.OperationConsistency.StatusUpdate = "QUORUM"
+	m.EXPECT().ExecuteBatch(gomock.Any()).Do(func(batch *gocql.Batch) {
+		assert.Equal(t, gocql.Quorum, batch.GetConsistency())
+	}).Return(nil).Times(1)
 
 	err := dao.UpdateStatus(schedules, app)
 	if err != nil {
@@ -338,8 +375,10 @@ func TestScheduleDaoImpl_GetSchedulesForEntity(t *testing.T) {
 	partitionId := 1
 	timeBucket := time.Now()
 	pageState := []byte("initial-page-state")
+	dao.Conf.ScheduleDB.OperationConsistency.PollRead = "local_quorum"
 
 	m.EXPECT().Query(gomock.Any(), gomock.Any()).Return(mq).AnyTimes()
+	mq.EXPECT().Consistency(gocql.LocalQuorum).Return(mq).Times(1)
 	mq.EXPECT().RetryPolicy(gomock.Any()).Return(mq).AnyTimes()
 	mq.EXPECT().Iter().Return(mItr).AnyTimes()
 	mq.EXPECT().PageState(gomock.Any()).Return(mq).AnyTimes()
@@ -414,3 +453,73 @@ func TestScheduleDaoImpl_GetCronSchedulesByApp(t *testing.T) {
 		t.Errorf("Expected 0 errors, got %d", len(errs))
 	}
 }
+
+func TestScheduleDaoImpl_CreateParkedSchedule(t *testing.T) {
+	dao, m, mq, _, ctrl := setupMocks(t)
+	defer ctrl.Finish()
+	dao.Conf.ParkingConfig = conf.ParkingConfig{Enabled: true, HorizonDays: 30, PromotionDays: 7, Shards: 4}
+
+	m.EXPECT().ExecuteBatch(gomock.Any()).Return(nil).AnyTimes()
+	m.EXPECT().Query(gomock.Any(), gomock.Any()).Return(mq).AnyTimes()
+	mq.EXPECT().Consistency(gomock.Any()).Return(mq).AnyTimes()
+	mq.EXPECT().Exec().Return(nil).AnyTimes()
+
+	app := s.App{AppId: "Test", Partitions: 2, Active: true}
+	callback := &s.HttpCallback{Type: "http", Details: s.Details{Url: "http://example.com/callback", Method: "POST"}}
+	schedule := s.Schedule{
+		ScheduleId: gocql.TimeUUID(),
+		AppId:      "Test",
+		Payload:    "Test Payload",
+		Callback:   callback,
+	}
+
+	schedule.ScheduleTime = time.Now().Add(24 * time.Hour).Unix()
+	created, err := dao.CreateSchedule(context.Background(), schedule, app)
+	assert.NoError(t, err)
+	assert.False(t, created.Parked)
+
+	schedule.ScheduleTime = time.Now().Add(60 * 24 * time.Hour).Unix()
+	created, err = dao.CreateSchedule(context.Background(), schedule, app)
+	assert.NoError(t, err)
+	assert.True(t, created.Parked)
+
+	// moving a parked schedule within the horizon takes it out of the parking table
+	updated := created
+	updated.ScheduleTime = time.Now().Add(24 * time.Hour).Unix()
+	updated, err = dao.UpdateOneTimeSchedule(created, updated, app)
+	assert.NoError(t, err)
+	assert.False(t, updated.Parked)
+
+	promoted, err := dao.PromoteSchedule(created, app)
+	assert.NoError(t, err)
+	assert.False(t, promoted.Parked)
+	assert.Equal(t, created.PartitionFor(app.Partitions), promoted.PartitionId)
+}
+
+func TestValidateConsistency(t *testing.T) {
+	for _, test := range []struct {
+		levels conf.OperationConsistency
+		valid  bool
+	}{
+		{conf.OperationConsistency{}, true},
+		{conf.OperationConsistency{Create: "LOCAL_QUORUM", StatusUpdate: "one", PollRead: "LOCAL_ONE"}, true},
+		{conf.OperationConsistency{Create: "ANY"}, true},
+		{conf.OperationConsistency{PollRead: "ANY"}, false},
+		{conf.OperationConsistency{StatusUpdate: "MOST"}, false},
+	} {
+		if err := ValidateConsistency(test.levels); (err == nil) != test.valid {
+			t.Errorf("levels %+v: expected valid %v, got %v", test.levels, test.valid, err)
+		}
+	}
+}
+
+func TestScheduleDaoImpl_ConsistencyFallback(t *testing.T) {
+	dao, _, _, _, ctrl := setupMocks(t)
+	defer ctrl.Finish()
+
+	dao.Conf.ScheduleDB.DBConfig.Consistency = gocql.LocalOne
+	assert.Equal(t, gocql.LocalOne, dao.consistency(dao.Conf.ScheduleDB.OperationConsistency.Create))
+
+	dao.Conf.ScheduleDB.OperationConsistency.Create = "EACH_QUORUM"
+	assert.Equal(t, gocql.EachQuorum, dao.consistency(dao.Conf.ScheduleDB.OperationConsistency.Create))
+}
@@ -20,6 +20,8 @@
 package db_wrapper
 
 import (
+	"time"
+
 	"github.com/gocql/gocql"
 )
 
@@ -43,6 +45,7 @@ type QueryInterface interface {
 	Iter() IterInterface
 	Scan(...interface{}) error
 	MapScan(m map[string]interface{}) error
+	ScanCAS(...interface{}) (bool, error)
 	Consistency(c gocql.Consistency) QueryInterface
 	PageState(state []byte) QueryInterface
 	PageSize(n int) QueryInterface
@@ -59,48 +62,79 @@ type IterInterface interface {
 
 // Session is a wrapper for a session for mockability.
 type Session struct {
-	session *gocql.Session
+	session    *gocql.Session
+	middleware *Middleware
 }
 
 // Query is a wrapper for a query for mockability.
 type Query struct {
-	query *gocql.Query
+	query      *gocql.Query
+	name       string
+	middleware *Middleware
 }
 
 // Iter is a wrapper for an iter for mockability.
 type Iter struct {
 	iter *gocql.Iter
+	// query is set when the session has a middleware, to run the query again when its first page times out
+	query   *Query
+	start   time.Time
+	rows    int
+	retries int
+	closed  bool
+	err     error
+	// fault is the timeout injected into the first page, reported in place of its rows
+	fault error
 }
 
 // NewSession instantiates a new Session
 func NewSession(session *gocql.Session) SessionInterface {
 	return &Session{
-		session,
+		session: session,
 	}
 }
 
 // NewQuery instantiates a new Query
 func NewQuery(query *gocql.Query) QueryInterface {
 	return &Query{
-		query,
+		query: query,
 	}
 }
 
 // NewIter instantiates a new Iter
 func NewIter(iter *gocql.Iter) IterInterface {
 	return &Iter{
-		iter,
+		iter: iter,
 	}
 }
 
 // ExecuteBatch wraps the session's ExecuteBatch method
 func (s *Session) ExecuteBatch(batch *gocql.Batch) error {
-	return s.session.ExecuteBatch(batch)
+	return s.middleware.run(batchName(batch), func() error {
+		return s.session.ExecuteBatch(batch)
+	})
 }
 
 // Query wraps the session's Query method
 func (s *Session) Query(stmt string, values ...interface{}) QueryInterface {
-	return NewQuery(s.session.Query(stmt, values...))
+	query := s.session.Query(stmt, values...)
+	if s.middleware == nil {
+		return NewQuery(query)
+	}
+	return &Query{
+		query:      s.middleware.prepare(stmt, query),
+		name:       queryName(stmt),
+		middleware: s.middleware,
+	}
+}
+
+// with returns the wrapper of the query, keeping the middleware of the session
+func (q *Query) with(query *gocql.Query) QueryInterface {
+	return &Query{
+		query:      query,
+		name:       q.name,
+		middleware: q.middleware,
+	}
 }
 
 // Close wraps the session's Close method
@@ -110,57 +144,106 @@ func (s *Session) Close() {
 
 // Bind wraps the query's Bind method
 func (q *Query) Bind(v ...interface{}) QueryInterface {
-	return NewQuery(q.query.Bind(v...))
+	return q.with(q.query.Bind(v...))
 }
 
 // Exec wraps the query's Exec method
 func (q *Query) Exec() error {
-	return q.query.Exec()
+	return q.middleware.run(q.name, q.query.Exec)
 }
 
 // Iter wraps the query's Iter method
 func (q *Query) Iter() IterInterface {
-	return NewIter(q.query.Iter())
+	if q.middleware == nil {
+		return NewIter(q.query.Iter())
+	}
+	return &Iter{
+		iter:  q.query.Iter(),
+		query: q,
+		start: time.Now(),
+		fault: q.middleware.Faults.QueryFault(q.name),
+	}
 }
 
 // Scan wraps the query's Scan method
 func (q *Query) Scan(dest ...interface{}) error {
-	return q.query.Scan(dest...)
+	return q.middleware.run(q.name, func() error {
+		return q.query.Scan(dest...)
+	})
 }
 
 // MapScan wraps the query's MapScan method
 func (q *Query) MapScan(m map[string]interface{}) error {
-	return q.query.MapScan(m)
+	return q.middleware.run(q.name, func() error {
+		return q.query.MapScan(m)
+	})
+}
+
+// ScanCAS wraps the query's ScanCAS method
+func (q *Query) ScanCAS(dest ...interface{}) (applied bool, err error) {
+	err = q.middleware.run(q.name, func() error {
+		applied, err = q.query.ScanCAS(dest...)
+		return err
+	})
+	return applied, err
 }
 
 // Consistency wraps the query's Consistency method
 func (q *Query) Consistency(c gocql.Consistency) QueryInterface {
-	return NewQuery(q.query.Consistency(c))
+	return q.with(q.query.Consistency(c))
 }
 
 // PageState wraps the query's PageState method
 func (q *Query) PageState(state []byte) QueryInterface {
-	return NewQuery(q.query.PageState(state))
+	return q.with(q.query.PageState(state))
 }
 
 // PageSize wraps the query's PageSize method
 func (q *Query) PageSize(n int) QueryInterface {
-	return NewQuery(q.query.PageSize(n))
+	return q.with(q.query.PageSize(n))
 }
 
 // RetryPolicy wraps the query's RetryPolicy method
 func (q *Query) RetryPolicy(policy gocql.RetryPolicy) QueryInterface {
-	return NewQuery(q.query.RetryPolicy(policy))
+	return q.with(q.query.RetryPolicy(policy))
 }
 
 // Scan wraps iter's Scan method
 func (i *Iter) Scan(dest ...interface{}) bool {
-	return i.iter.Scan(dest...)
+	return i.next(func() bool {
+		return i.iter.Scan(dest...)
+	})
 }
 
 // MapScan wraps iter's MapScan method
 func (i *Iter) MapScan(m map[string]interface{}) bool {
-	return i.iter.MapScan(m)
+	return i.next(func() bool {
+		return i.iter.MapScan(m)
+	})
+}
+
+// next scans the next row. With a middleware, a query timing out before its first row is run again.
+func (i *Iter) next(scan func() bool) bool {
+	for {
+		if i.fault == nil && scan() {
+			i.rows++
+			return true
+		}
+		if i.query == nil || i.rows > 0 || i.closed {
+			return false
+		}
+
+		i.err, i.closed = i.iter.Close(), true
+		if i.fault != nil {
+			i.err, i.fault = i.fault, nil
+		}
+		if !i.query.middleware.retry(i.query.name, i.err, i.retries) {
+			return false
+		}
+		i.retries++
+		i.iter, i.closed, i.err = i.query.query.Iter(), false, nil
+		i.fault = i.query.middleware.Faults.QueryFault(i.query.name)
+	}
 }
 
 // PageState wraps iter's PageState method
@@ -170,5 +253,16 @@ func (i *Iter) PageState() []byte {
 
 // Close wraps iter's Close method
 func (i *Iter) Close() error {
-	return i.iter.Close()
+	if i.query == nil {
+		return i.iter.Close()
+	}
+
+	err := i.err
+	if !i.closed {
+		err, i.closed = i.iter.Close(), true
+		if i.fault != nil {
+			err = i.fault
+		}
+	}
+	return i.query.middleware.observe(i.query.name, i.start, i.retries, err)
 }
\ No newline at end of file
@@ -0,0 +1,215 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db_wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/faults"
+	p "github.com/myntra/goscheduler/monitoring"
+)
+
+// Middleware observes the queries of a session, and retries with backoff the queries timing out
+type Middleware struct {
+	Keyspace    string                           // Keyspace of the session, a label of the metrics
+	Monitor     p.Monitor                        // Records the latency and the retries of the queries, nil records nothing
+	MaxRetries  int                              // Retries of a query timing out, 0 disables the retries
+	MinBackoff  time.Duration                    // Backoff before the first retry, doubled after every retry
+	MaxBackoff  time.Duration                    // Upper bound of the backoff between retries
+	Speculative gocql.SpeculativeExecutionPolicy // Speculative execution of the reads, nil disables it
+	Faults      *faults.Injector                 // Fails queries on purpose in resilience tests, nil injects nothing
+	sleep       func(time.Duration)
+}
+
+// NewMonitoredSession instantiates a new Session whose queries go through the middleware
+func NewMonitoredSession(session *gocql.Session, middleware Middleware) SessionInterface {
+	return &Session{
+		session:    session,
+		middleware: &middleware,
+	}
+}
+
+// TimeoutError is the error of a query still timing out after its retries
+type TimeoutError struct {
+	Query   string
+	Retries int
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("cassandra query %s timed out after %d retries: %s", e.Query, e.Retries, e.Err.Error())
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout tells that the error is a timeout, for the callers checking it without importing this package
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// IsTimeout tells whether the error is a query timing out, on the client or on the coordinator
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var readTimeout *gocql.RequestErrReadTimeout
+	var writeTimeout *gocql.RequestErrWriteTimeout
+	return errors.Is(err, gocql.ErrTimeoutNoResponse) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &readTimeout) ||
+		errors.As(err, &writeTimeout)
+}
+
+// prepare applies the speculative execution to the reads, which are the only queries safe to run twice at once
+func (m *Middleware) prepare(stmt string, query *gocql.Query) *gocql.Query {
+	if m.Speculative == nil || !isRead(stmt) {
+		return query
+	}
+	return query.Idempotent(true).SetSpeculativeExecutionPolicy(m.Speculative)
+}
+
+// run executes do, retrying it while it times out, and records the query
+func (m *Middleware) run(name string, do func() error) error {
+	if m == nil {
+		return do()
+	}
+
+	start := time.Now()
+	for retries := 0; ; retries++ {
+		err := m.Faults.QueryFault(name)
+		if err == nil {
+			err = do()
+		}
+		if !m.retry(name, err, retries) {
+			return m.observe(name, start, retries, err)
+		}
+	}
+}
+
+// retry tells whether a query failing with err after the given retries is retried, after waiting for its backoff
+func (m *Middleware) retry(name string, err error, retries int) bool {
+	if m == nil || retries >= m.MaxRetries || !IsTimeout(err) {
+		return false
+	}
+
+	if m.Monitor != nil {
+		m.Monitor.IncCounter(constants.CassandraQueryRetryCount, map[string]string{"keyspace": m.Keyspace, "query": name}, 1)
+	}
+	sleep := m.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	sleep(m.backoff(retries))
+	return true
+}
+
+// backoff returns the wait before the retry following the given retries
+func (m *Middleware) backoff(retries int) time.Duration {
+	backoff := m.MinBackoff
+	for i := 0; i < retries && backoff < m.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if m.MaxBackoff > 0 && backoff > m.MaxBackoff {
+		return m.MaxBackoff
+	}
+	return backoff
+}
+
+// observe records the latency and the outcome of a query, and returns its error.
+// A timeout left after the retries is returned as a TimeoutError.
+func (m *Middleware) observe(name string, start time.Time, retries int, err error) error {
+	if m == nil {
+		return err
+	}
+
+	status := "success"
+	switch {
+	case IsTimeout(err):
+		status = "timeout"
+		err = &TimeoutError{Query: name, Retries: retries, Err: err}
+	case err != nil && err != gocql.ErrNotFound:
+		status = "error"
+	}
+
+	if m.Monitor != nil {
+		m.Monitor.RecordTiming(constants.CassandraQueryDuration, map[string]string{
+			"keyspace": m.Keyspace,
+			"query":    name,
+			"status":   status,
+		}, time.Since(start))
+	}
+	return err
+}
+
+// isRead tells whether the statement is a read
+func isRead(stmt string) bool {
+	return strings.EqualFold(firstWord(stmt), "SELECT")
+}
+
+// queryName names a statement by its kind and its table, e.g. select_schedules, keeping the metrics to a label per
+// statement shape rather than per query
+func queryName(stmt string) string {
+	words := strings.Fields(stmt)
+	if len(words) == 0 {
+		return "unknown"
+	}
+
+	kind := strings.ToLower(words[0])
+	marker := map[string]string{"select": "from", "delete": "from", "insert": "into", "update": "update"}[kind]
+	if marker == "" {
+		return kind
+	}
+	for i, word := range words[:len(words)-1] {
+		if strings.EqualFold(word, marker) {
+			table := strings.ToLower(strings.Trim(words[i+1], `"();`))
+			if dot := strings.LastIndex(table, "."); dot >= 0 {
+				table = table[dot+1:]
+			}
+			return kind + "_" + table
+		}
+	}
+	return kind
+}
+
+// batchName names a batch by its first statement
+func batchName(batch *gocql.Batch) string {
+	if batch == nil || len(batch.Entries) == 0 {
+		return "batch"
+	}
+	return "batch_" + queryName(batch.Entries[0].Stmt)
+}
+
+// firstWord returns the first word of the statement
+func firstWord(stmt string) string {
+	words := strings.Fields(stmt)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
@@ -0,0 +1,136 @@
+package db_wrapper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/faults"
+)
+
+type recordingMonitor struct {
+	counters map[string]int
+	timings  []map[string]string
+}
+
+func (m *recordingMonitor) IncCounter(name string, _ map[string]string, value int) {
+	m.counters[name] += value
+}
+
+func (m *recordingMonitor) RecordTiming(_ string, labels map[string]string, _ time.Duration) {
+	m.timings = append(m.timings, labels)
+}
+
+func (m *recordingMonitor) SetGauge(string, map[string]string, float64) {}
+
+func newTestMiddleware(maxRetries int) (*Middleware, *recordingMonitor, *[]time.Duration) {
+	monitor := &recordingMonitor{counters: map[string]int{}}
+	var waits []time.Duration
+	return &Middleware{
+		Keyspace:   "schedule_management",
+		Monitor:    monitor,
+		MaxRetries: maxRetries,
+		MinBackoff: 50 * time.Millisecond,
+		MaxBackoff: 120 * time.Millisecond,
+		sleep:      func(d time.Duration) { waits = append(waits, d) },
+	}, monitor, &waits
+}
+
+func TestMiddlewareRetriesTimeouts(t *testing.T) {
+	m, monitor, waits := newTestMiddleware(3)
+
+	calls := 0
+	err := m.run("select_schedules", func() error {
+		calls++
+		if calls < 3 {
+			return gocql.ErrTimeoutNoResponse
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on the third call, got %v after %d calls", err, calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 50*time.Millisecond || (*waits)[1] != 100*time.Millisecond {
+		t.Errorf("expected backoffs of 50ms and 100ms, got %v", *waits)
+	}
+	if len(monitor.timings) != 1 || monitor.timings[0]["status"] != "success" || monitor.timings[0]["query"] != "select_schedules" {
+		t.Errorf("expected one successful query recorded, got %v", monitor.timings)
+	}
+}
+
+func TestMiddlewareReturnsTimeoutError(t *testing.T) {
+	m, monitor, waits := newTestMiddleware(3)
+
+	err := m.run("insert_status", func() error { return &gocql.RequestErrWriteTimeout{} })
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.Retries != 3 {
+		t.Fatalf("expected a timeout after 3 retries, got %v", err)
+	}
+	if (*waits)[2] != 120*time.Millisecond {
+		t.Errorf("expected the backoff capped at 120ms, got %v", *waits)
+	}
+	if monitor.timings[0]["status"] != "timeout" {
+		t.Errorf("expected the timeout recorded, got %v", monitor.timings)
+	}
+}
+
+func TestMiddlewareDoesNotRetryOtherErrors(t *testing.T) {
+	m, monitor, _ := newTestMiddleware(3)
+
+	calls := 0
+	err := m.run("select_apps", func() error {
+		calls++
+		return gocql.ErrNotFound
+	})
+	if err != gocql.ErrNotFound || calls != 1 {
+		t.Errorf("expected not found without retries, got %v after %d calls", err, calls)
+	}
+	if monitor.timings[0]["status"] != "success" {
+		t.Errorf("expected not found recorded as a success, got %v", monitor.timings)
+	}
+
+	var nilMiddleware *Middleware
+	if err = nilMiddleware.run("select_apps", func() error { return gocql.ErrTimeoutNoResponse }); err != gocql.ErrTimeoutNoResponse {
+		t.Errorf("expected the error unchanged without a middleware, got %v", err)
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	for stmt, expected := range map[string]string{
+		"SELECT schedule_id FROM schedules WHERE app_id = ?":               "select_schedules",
+		"INSERT INTO status (app_id) VALUES (?) USING TTL ?":               "insert_status",
+		"UPDATE cluster.apps SET active = ? WHERE id = ?":                  "update_apps",
+		"DELETE FROM parked_schedules WHERE parking_day = ? AND shard = ?": "delete_parked_schedules",
+		"CREATE TABLE IF NOT EXISTS apps (id text PRIMARY KEY)":            "create",
+		"": "unknown",
+	} {
+		if name := queryName(stmt); name != expected {
+			t.Errorf("statement %q: expected %s, got %s", stmt, expected, name)
+		}
+	}
+}
+
+func TestMiddlewareInjectsTimeouts(t *testing.T) {
+	m, monitor, waits := newTestMiddleware(2)
+	m.Faults = faults.NewInjector()
+	m.Faults.Configure(conf.FaultInjectionConfig{Enabled: true, CassandraTimeoutRate: 1, CassandraQueries: []string{"select_schedules"}}, nil)
+
+	calls := 0
+	err := m.run("select_schedules", func() error {
+		calls++
+		return nil
+	})
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || calls != 0 || len(*waits) != 2 {
+		t.Fatalf("expected the injected timeout retried and returned without running the query, got %v after %d calls", err, calls)
+	}
+	if monitor.timings[0]["status"] != "timeout" {
+		t.Errorf("expected the timeout recorded, got %v", monitor.timings)
+	}
+
+	if err = m.run("insert_status", func() error { calls++; return nil }); err != nil || calls != 1 {
+		t.Errorf("expected the queries left out of the faults run, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package db_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	p "github.com/myntra/goscheduler/monitoring"
+)
+
+// ErrNotApplied is the error of a lightweight transaction applied on the primary session but not on the shadow
+var ErrNotApplied = errors.New("lightweight transaction not applied on the shadow")
+
+// ShadowSession is a session whose writes are mirrored to a shadow session, to migrate to a new cluster or schema
+// without downtime. The reads stay on the primary session, a sample of the single row reads is compared with the
+// shadow session. A write failing on the shadow session never fails the write, it is recorded by the mirror to be
+// replayed. The shadow session can be any backend implementing SessionInterface.
+type ShadowSession struct {
+	primary  SessionInterface
+	shadow   SessionInterface
+	keyspace string
+	mirror   *Mirror
+}
+
+// NewShadowSession instantiates a session mirroring the writes of the primary session to the shadow session
+func NewShadowSession(primary, shadow SessionInterface, keyspace string, mirror *Mirror) SessionInterface {
+	return &ShadowSession{
+		primary:  primary,
+		shadow:   shadow,
+		keyspace: keyspace,
+		mirror:   mirror,
+	}
+}
+
+// shadowQuery is a query run on the primary session, and on the shadow session for the writes
+type shadowQuery struct {
+	session *ShadowSession
+	stmt    string
+	name    string
+	read    bool
+	primary QueryInterface
+	shadow  QueryInterface
+}
+
+// Query returns the query of the statement on both sessions
+func (s *ShadowSession) Query(stmt string, values ...interface{}) QueryInterface {
+	return &shadowQuery{
+		session: s,
+		stmt:    stmt,
+		name:    queryName(stmt),
+		read:    isRead(stmt),
+		primary: s.primary.Query(stmt, values...),
+		shadow:  s.shadow.Query(stmt, values...),
+	}
+}
+
+// ExecuteBatch executes the batch on the primary session, then on the shadow session once it succeeded
+func (s *ShadowSession) ExecuteBatch(batch *gocql.Batch) error {
+	err := s.primary.ExecuteBatch(batch)
+	if err == nil {
+		s.mirror.write(s.keyspace, batchName(batch), func() error {
+			return s.shadow.ExecuteBatch(batch)
+		})
+	}
+	return err
+}
+
+// Close closes both sessions
+func (s *ShadowSession) Close() {
+	s.primary.Close()
+	s.shadow.Close()
+}
+
+// with returns the query of the given primary and shadow queries
+func (q *shadowQuery) with(primary, shadow QueryInterface) QueryInterface {
+	return &shadowQuery{
+		session: q.session,
+		stmt:    q.stmt,
+		name:    q.name,
+		read:    q.read,
+		primary: primary,
+		shadow:  shadow,
+	}
+}
+
+func (q *shadowQuery) Bind(v ...interface{}) QueryInterface {
+	return q.with(q.primary.Bind(v...), q.shadow.Bind(v...))
+}
+
+func (q *shadowQuery) Consistency(c gocql.Consistency) QueryInterface {
+	return q.with(q.primary.Consistency(c), q.shadow.Consistency(c))
+}
+
+func (q *shadowQuery) PageState(state []byte) QueryInterface {
+	return q.with(q.primary.PageState(state), q.shadow.PageState(state))
+}
+
+func (q *shadowQuery) PageSize(n int) QueryInterface {
+	return q.with(q.primary.PageSize(n), q.shadow.PageSize(n))
+}
+
+func (q *shadowQuery) RetryPolicy(policy gocql.RetryPolicy) QueryInterface {
+	return q.with(q.primary.RetryPolicy(policy), q.shadow.RetryPolicy(policy))
+}
+
+// Exec executes the query on the primary session, then on the shadow session once it succeeded
+func (q *shadowQuery) Exec() error {
+	err := q.primary.Exec()
+	if err == nil && !q.read {
+		q.session.mirror.write(q.session.keyspace, q.name, q.shadow.Exec)
+	}
+	return err
+}
+
+// Iter reads the rows from the primary session only
+func (q *shadowQuery) Iter() IterInterface {
+	return q.primary.Iter()
+}
+
+// Scan reads the row from the primary session, and compares it with the shadow session when sampled
+func (q *shadowQuery) Scan(dest ...interface{}) error {
+	err := q.primary.Scan(dest...)
+	switch {
+	case q.read:
+		primary := values(dest)
+		q.session.mirror.compare(q.session.keyspace, q.name, q.stmt, primary, err, func() ([]interface{}, error) {
+			shadowDest := destinations(dest)
+			err := q.shadow.Scan(shadowDest...)
+			return values(shadowDest), err
+		})
+	case err == nil:
+		q.session.mirror.write(q.session.keyspace, q.name, func() error {
+			return q.shadow.Scan(destinations(dest)...)
+		})
+	}
+	return err
+}
+
+// MapScan reads the row from the primary session, and compares it with the shadow session when sampled
+func (q *shadowQuery) MapScan(m map[string]interface{}) error {
+	err := q.primary.MapScan(m)
+	if !q.read {
+		if err == nil {
+			q.session.mirror.write(q.session.keyspace, q.name, func() error {
+				return q.shadow.MapScan(map[string]interface{}{})
+			})
+		}
+		return err
+	}
+
+	columns := make([]string, 0, len(m))
+	for column := range m {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	primary := make([]interface{}, len(columns))
+	for i, column := range columns {
+		primary[i] = m[column]
+	}
+
+	q.session.mirror.compare(q.session.keyspace, q.name, q.stmt, primary, err, func() ([]interface{}, error) {
+		row := map[string]interface{}{}
+		err := q.shadow.MapScan(row)
+		shadow := make([]interface{}, len(columns))
+		for i, column := range columns {
+			shadow[i] = row[column]
+		}
+		return shadow, err
+	})
+	return err
+}
+
+// ScanCAS applies the lightweight transaction on the primary session, then on the shadow session once it was applied
+func (q *shadowQuery) ScanCAS(dest ...interface{}) (bool, error) {
+	applied, err := q.primary.ScanCAS(dest...)
+	if err == nil && applied {
+		q.session.mirror.write(q.session.keyspace, q.name, func() error {
+			applied, err := q.shadow.ScanCAS(destinations(dest)...)
+			if err == nil && !applied {
+				return ErrNotApplied
+			}
+			return err
+		})
+	}
+	return applied, err
+}
+
+// values returns the values scanned into the destinations
+func values(dest []interface{}) []interface{} {
+	scanned := make([]interface{}, len(dest))
+	for i, d := range dest {
+		if v := reflect.ValueOf(d); v.Kind() == reflect.Ptr && !v.IsNil() {
+			scanned[i] = v.Elem().Interface()
+		}
+	}
+	return scanned
+}
+
+// destinations returns new destinations of the types of the given ones
+func destinations(dest []interface{}) []interface{} {
+	fresh := make([]interface{}, len(dest))
+	for i, d := range dest {
+		if t := reflect.TypeOf(d); t != nil && t.Kind() == reflect.Ptr {
+			fresh[i] = reflect.New(t.Elem()).Interface()
+		}
+	}
+	return fresh
+}
+
+// MirrorStats counts the writes mirrored and the reads compared of a query of a keyspace
+type MirrorStats struct {
+	Keyspace   string `json:"keyspace"`
+	Query      string `json:"query"`
+	Mirrored   int64  `json:"mirrored"`
+	Failed     int64  `json:"failed"`
+	Compared   int64  `json:"compared"`
+	Mismatched int64  `json:"mismatched"`
+}
+
+// FailedWrite is a write which failed on the shadow session, replayed by a reconciliation
+type FailedWrite struct {
+	Keyspace string       `json:"keyspace"`
+	Query    string       `json:"query"`
+	Error    string       `json:"error"`
+	FailedAt time.Time    `json:"failedAt"`
+	replay   func() error `json:"-"`
+}
+
+// Mismatch is a row read differently from the primary and the shadow session
+type Mismatch struct {
+	Keyspace  string    `json:"keyspace"`
+	Query     string    `json:"query"`
+	Statement string    `json:"statement"`
+	Detail    string    `json:"detail"`
+	FoundAt   time.Time `json:"foundAt"`
+}
+
+// MirrorReport is the comparison of the shadow sessions with the primary ones
+type MirrorReport struct {
+	Queries         []MirrorStats `json:"queries"`
+	Failures        []FailedWrite `json:"failures"`
+	DroppedFailures int64         `json:"droppedFailures"`
+	Mismatches      []Mismatch    `json:"mismatches"`
+}
+
+// mirrorKey identifies a query of a keyspace
+type mirrorKey struct {
+	keyspace string
+	query    string
+}
+
+// Mirror records the writes mirrored to the shadow sessions and the reads compared with them. The failed writes and
+// the mismatches are kept up to a limit, the oldest failed writes past it are dropped and counted.
+type Mirror struct {
+	lock            sync.Mutex
+	monitor         p.Monitor
+	compareRate     float64
+	maxRecorded     int
+	slots           chan struct{}
+	stats           map[mirrorKey]*MirrorStats
+	failures        []FailedWrite
+	droppedFailures int64
+	mismatches      []Mismatch
+}
+
+var mirror = NewMirror(nil, 0, 0, 0)
+
+// DefaultMirror returns the mirror of the shadow sessions of the node
+func DefaultMirror() *Mirror {
+	return mirror
+}
+
+// NewMirror instantiates a mirror comparing the given share of the reads, at most compareRoutines at once
+func NewMirror(monitor p.Monitor, compareRate float64, compareRoutines, maxRecorded int) *Mirror {
+	m := &Mirror{stats: map[mirrorKey]*MirrorStats{}}
+	m.Configure(monitor, compareRate, compareRoutines, maxRecorded)
+	return m
+}
+
+// Configure sets the monitor, the share of the reads compared and the number of failed writes and mismatches kept
+func (m *Mirror) Configure(monitor p.Monitor, compareRate float64, compareRoutines, maxRecorded int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.monitor = monitor
+	m.compareRate = compareRate
+	m.maxRecorded = maxRecorded
+	if compareRoutines > 0 {
+		m.slots = make(chan struct{}, compareRoutines)
+	}
+}
+
+// write mirrors a write to the shadow session, and records it
+func (m *Mirror) write(keyspace, query string, do func() error) {
+	err := do()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	stats := m.statsOf(keyspace, query)
+	stats.Mirrored++
+	status := constants.Success
+	if err != nil {
+		stats.Failed++
+		status = constants.Fail
+		m.failures = append(m.failures, FailedWrite{Keyspace: keyspace, Query: query, Error: err.Error(), FailedAt: time.Now(), replay: do})
+		if m.maxRecorded > 0 && len(m.failures) > m.maxRecorded {
+			m.droppedFailures += int64(len(m.failures) - m.maxRecorded)
+			m.failures = m.failures[len(m.failures)-m.maxRecorded:]
+		}
+	}
+
+	if m.monitor != nil {
+		m.monitor.IncCounter(constants.ShadowWriteCount, map[string]string{"keyspace": keyspace, "query": query, "status": status}, 1)
+	}
+}
+
+// compare reads a sampled row from the shadow session in the background, and records whether it differs from the
+// row read from the primary session. Rows failing to be read from either session are not compared.
+func (m *Mirror) compare(keyspace, query, stmt string, primary []interface{}, primaryErr error, read func() ([]interface{}, error)) {
+	if primaryErr != nil && primaryErr != gocql.ErrNotFound {
+		return
+	}
+
+	m.lock.Lock()
+	slots, sampled := m.slots, m.compareRate > 0 && rand.Float64() < m.compareRate
+	m.lock.Unlock()
+	if !sampled || slots == nil {
+		return
+	}
+
+	// reads sampled while all the slots are taken are not compared, rather than piling up
+	select {
+	case slots <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-slots }()
+
+		shadow, shadowErr := read()
+		if shadowErr != nil && shadowErr != gocql.ErrNotFound {
+			return
+		}
+		m.recordComparison(keyspace, query, stmt, difference(primary, primaryErr, shadow, shadowErr))
+	}()
+}
+
+// recordComparison records a read compared, and the mismatch found when detail is not empty
+func (m *Mirror) recordComparison(keyspace, query, stmt, detail string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	stats := m.statsOf(keyspace, query)
+	stats.Compared++
+	status := "match"
+	if detail != "" {
+		stats.Mismatched++
+		status = "mismatch"
+		m.mismatches = append(m.mismatches, Mismatch{Keyspace: keyspace, Query: query, Statement: stmt, Detail: detail, FoundAt: time.Now()})
+		if m.maxRecorded > 0 && len(m.mismatches) > m.maxRecorded {
+			m.mismatches = m.mismatches[len(m.mismatches)-m.maxRecorded:]
+		}
+	}
+
+	if m.monitor != nil {
+		m.monitor.IncCounter(constants.ShadowCompareCount, map[string]string{"keyspace": keyspace, "query": query, "status": status}, 1)
+	}
+}
+
+// difference describes how the row read from the shadow session differs from the primary one, empty when equal
+func difference(primary []interface{}, primaryErr error, shadow []interface{}, shadowErr error) string {
+	switch {
+	case primaryErr == nil && shadowErr == gocql.ErrNotFound:
+		return "row missing from the shadow"
+	case primaryErr == gocql.ErrNotFound && shadowErr == nil:
+		return "row missing from the primary"
+	case primaryErr != nil:
+		return ""
+	}
+
+	for i := range primary {
+		if i >= len(shadow) || !reflect.DeepEqual(primary[i], shadow[i]) {
+			return fmt.Sprintf("column %d differs", i)
+		}
+	}
+	return ""
+}
+
+// statsOf returns the stats of a query of a keyspace, the lock being held
+func (m *Mirror) statsOf(keyspace, query string) *MirrorStats {
+	key := mirrorKey{keyspace: keyspace, query: query}
+	stats, ok := m.stats[key]
+	if !ok {
+		stats = &MirrorStats{Keyspace: keyspace, Query: query}
+		m.stats[key] = stats
+	}
+	return stats
+}
+
+// Report returns the stats of the queries, ordered by keyspace and query, with the failed writes and the mismatches
+func (m *Mirror) Report() MirrorReport {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	report := MirrorReport{
+		Queries:         make([]MirrorStats, 0, len(m.stats)),
+		Failures:        append([]FailedWrite{}, m.failures...),
+		DroppedFailures: m.droppedFailures,
+		Mismatches:      append([]Mismatch{}, m.mismatches...),
+	}
+	for _, stats := range m.stats {
+		report.Queries = append(report.Queries, *stats)
+	}
+	sort.Slice(report.Queries, func(i, j int) bool {
+		if report.Queries[i].Keyspace != report.Queries[j].Keyspace {
+			return report.Queries[i].Keyspace < report.Queries[j].Keyspace
+		}
+		return report.Queries[i].Query < report.Queries[j].Query
+	})
+	return report
+}
+
+// Reconcile replays the failed writes on the shadow sessions in the order they failed. The writes failing again are
+// kept for the next reconciliation.
+func (m *Mirror) Reconcile() (replayed int, failed int) {
+	m.lock.Lock()
+	failures := m.failures
+	m.failures = nil
+	m.lock.Unlock()
+
+	var remaining []FailedWrite
+	for _, failure := range failures {
+		if err := failure.replay(); err != nil {
+			failure.Error, failure.FailedAt = err.Error(), time.Now()
+			remaining = append(remaining, failure)
+			continue
+		}
+		replayed++
+	}
+
+	m.lock.Lock()
+	m.failures = append(remaining, m.failures...)
+	m.lock.Unlock()
+	return replayed, len(remaining)
+}
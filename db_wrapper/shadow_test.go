@@ -0,0 +1,204 @@
+package db_wrapper
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// fakeSession executes the writes in memory and reads the rows set per statement
+type fakeSession struct {
+	lock  sync.Mutex
+	rows  map[string][]interface{}
+	execs []string
+	err   error
+}
+
+type fakeQuery struct {
+	session *fakeSession
+	stmt    string
+}
+
+func newFakeSession() *fakeSession {
+	return &fakeSession{rows: map[string][]interface{}{}}
+}
+
+func (s *fakeSession) Query(stmt string, _ ...interface{}) QueryInterface {
+	return &fakeQuery{session: s, stmt: stmt}
+}
+
+func (s *fakeSession) ExecuteBatch(batch *gocql.Batch) error {
+	return s.exec(batchName(batch))
+}
+
+func (s *fakeSession) Close() {}
+
+func (s *fakeSession) exec(stmt string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.execs = append(s.execs, stmt)
+	return nil
+}
+
+func (s *fakeSession) executed() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string{}, s.execs...)
+}
+
+func (s *fakeSession) fail(err error) {
+	s.lock.Lock()
+	s.err = err
+	s.lock.Unlock()
+}
+
+func (q *fakeQuery) Bind(...interface{}) QueryInterface           { return q }
+func (q *fakeQuery) Exec() error                                  { return q.session.exec(q.stmt) }
+func (q *fakeQuery) Iter() IterInterface                          { return nil }
+func (q *fakeQuery) MapScan(map[string]interface{}) error         { return nil }
+func (q *fakeQuery) ScanCAS(...interface{}) (bool, error)         { return true, q.session.exec(q.stmt) }
+func (q *fakeQuery) Consistency(gocql.Consistency) QueryInterface { return q }
+func (q *fakeQuery) PageState([]byte) QueryInterface              { return q }
+func (q *fakeQuery) PageSize(int) QueryInterface                  { return q }
+func (q *fakeQuery) RetryPolicy(gocql.RetryPolicy) QueryInterface { return q }
+
+func (q *fakeQuery) Scan(dest ...interface{}) error {
+	row, ok := q.session.rows[q.stmt]
+	if !ok {
+		return gocql.ErrNotFound
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(row[i]))
+	}
+	return nil
+}
+
+const (
+	insertSchedule = "INSERT INTO schedules (id, payload) VALUES (?, ?)"
+	selectSchedule = "SELECT id, payload FROM schedules WHERE id = ?"
+)
+
+func TestShadowSession_MirrorsWrites(t *testing.T) {
+	primary, shadow := newFakeSession(), newFakeSession()
+	mirror := NewMirror(nil, 0, 0, 10)
+	session := NewShadowSession(primary, shadow, "schedule_management", mirror)
+
+	if err := session.Query(insertSchedule, "1", "payload").Exec(); err != nil {
+		t.Fatalf("expected the write to succeed, got %v", err)
+	}
+	if err := session.Query(selectSchedule, "1").Exec(); err != nil {
+		t.Fatalf("expected the read to succeed, got %v", err)
+	}
+	if _, err := session.Query(insertSchedule+" IF NOT EXISTS", "1", "payload").ScanCAS(); err != nil {
+		t.Fatalf("expected the transaction to succeed, got %v", err)
+	}
+
+	expected := []string{insertSchedule, insertSchedule + " IF NOT EXISTS"}
+	if got := shadow.executed(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the writes %v to be mirrored, got %v", expected, got)
+	}
+
+	report := mirror.Report()
+	if len(report.Queries) != 1 || report.Queries[0].Query != "insert_schedules" || report.Queries[0].Mirrored != 2 {
+		t.Errorf("expected 2 mirrored writes of insert_schedules, got %+v", report.Queries)
+	}
+}
+
+func TestShadowSession_FailedWritesAreReconciled(t *testing.T) {
+	primary, shadow := newFakeSession(), newFakeSession()
+	mirror := NewMirror(nil, 0, 0, 10)
+	session := NewShadowSession(primary, shadow, "schedule_management", mirror)
+
+	shadow.fail(errors.New("shadow unavailable"))
+	if err := session.Query(insertSchedule, "1", "payload").Exec(); err != nil {
+		t.Fatalf("expected a shadow failure not to fail the write, got %v", err)
+	}
+	if err := session.ExecuteBatch(gocql.NewBatch(gocql.UnloggedBatch)); err != nil {
+		t.Fatalf("expected a shadow failure not to fail the batch, got %v", err)
+	}
+
+	report := mirror.Report()
+	if len(report.Failures) != 2 || report.Failures[0].Error != "shadow unavailable" {
+		t.Fatalf("expected 2 failed writes, got %+v", report.Failures)
+	}
+
+	if replayed, failed := mirror.Reconcile(); replayed != 0 || failed != 2 {
+		t.Errorf("expected the writes to fail again, got %d replayed and %d failed", replayed, failed)
+	}
+
+	shadow.fail(nil)
+	if replayed, failed := mirror.Reconcile(); replayed != 2 || failed != 0 {
+		t.Errorf("expected the writes to be replayed, got %d replayed and %d failed", replayed, failed)
+	}
+	if got := shadow.executed(); !reflect.DeepEqual(got, []string{insertSchedule, "batch"}) {
+		t.Errorf("expected the writes to be replayed in order, got %v", got)
+	}
+	if report := mirror.Report(); len(report.Failures) != 0 {
+		t.Errorf("expected no failed writes left, got %+v", report.Failures)
+	}
+}
+
+func TestMirror_DropsOldestFailures(t *testing.T) {
+	mirror := NewMirror(nil, 0, 0, 2)
+	for i := 0; i < 5; i++ {
+		mirror.write("schedule_management", "insert_schedules", func() error { return errors.New("failed") })
+	}
+
+	report := mirror.Report()
+	if len(report.Failures) != 2 || report.DroppedFailures != 3 {
+		t.Errorf("expected 2 failed writes kept and 3 dropped, got %d and %d", len(report.Failures), report.DroppedFailures)
+	}
+}
+
+func TestShadowSession_ComparesReads(t *testing.T) {
+	tests := []struct {
+		name   string
+		shadow []interface{}
+		detail string
+	}{
+		{name: "same row", shadow: []interface{}{"1", "payload"}},
+		{name: "different column", shadow: []interface{}{"1", "stale"}, detail: "column 1 differs"},
+		{name: "missing row", detail: "row missing from the shadow"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primary, shadow := newFakeSession(), newFakeSession()
+			primary.rows[selectSchedule] = []interface{}{"1", "payload"}
+			if test.shadow != nil {
+				shadow.rows[selectSchedule] = test.shadow
+			}
+			mirror := NewMirror(nil, 1, 1, 10)
+			session := NewShadowSession(primary, shadow, "schedule_management", mirror)
+
+			var id, payload string
+			if err := session.Query(selectSchedule, "1").Scan(&id, &payload); err != nil || payload != "payload" {
+				t.Fatalf("expected the row of the primary, got %s, %v", payload, err)
+			}
+
+			var report MirrorReport
+			for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+				if report = mirror.Report(); len(report.Queries) > 0 && report.Queries[0].Compared == 1 {
+					break
+				}
+			}
+			if len(report.Queries) == 0 || report.Queries[0].Compared != 1 {
+				t.Fatalf("expected the read to be compared, got %+v", report.Queries)
+			}
+
+			switch {
+			case test.detail == "" && len(report.Mismatches) != 0:
+				t.Errorf("expected no mismatch, got %+v", report.Mismatches)
+			case test.detail != "" && (len(report.Mismatches) != 1 || report.Mismatches[0].Detail != test.detail):
+				t.Errorf("expected the mismatch %s, got %+v", test.detail, report.Mismatches)
+			}
+		})
+	}
+}
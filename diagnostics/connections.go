@@ -0,0 +1,189 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package diagnostics
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxConnectionHosts bounds the callback hosts tracked per app, the connections of the further hosts being
+// counted under otherHosts
+const (
+	maxConnectionHosts = 100
+	otherHosts         = "other"
+)
+
+// ConnectionStats is the use of the pooled connections of the callbacks of an app to a host
+type ConnectionStats struct {
+	Host                  string    `json:"host"`
+	Requests              int64     `json:"requests"`
+	ReusedConns           int64     `json:"reusedConns"`
+	ReuseRatio            float64   `json:"reuseRatio"` // Share of the requests sent on a pooled connection
+	Dials                 int64     `json:"dials"`
+	DialErrors            int64     `json:"dialErrors"`
+	AvgDialMillis         float64   `json:"avgDialMillis"`
+	TLSHandshakes         int64     `json:"tlsHandshakes"`
+	TLSHandshakeErrors    int64     `json:"tlsHandshakeErrors"`
+	AvgTLSHandshakeMillis float64   `json:"avgTlsHandshakeMillis"`
+	Since                 time.Time `json:"since"`
+	LastUsedAt            time.Time `json:"lastUsedAt"`
+}
+
+type connectionCounters struct {
+	stats         ConnectionStats
+	dialTime      time.Duration
+	handshakeTime time.Duration
+}
+
+// ConnectionRecorder counts the requests, dials and TLS handshakes of the callbacks of every app by host,
+// to tell how well the connections of the callback clients are reused
+type ConnectionRecorder struct {
+	mu    sync.Mutex
+	apps  map[string]map[string]*connectionCounters
+	clock func() time.Time
+}
+
+var connections = NewConnectionRecorder()
+
+// Connections returns the recorder of the connections of the callbacks of the node
+func Connections() *ConnectionRecorder {
+	return connections
+}
+
+func NewConnectionRecorder() *ConnectionRecorder {
+	return &ConnectionRecorder{apps: map[string]map[string]*connectionCounters{}, clock: time.Now}
+}
+
+// Trace returns the context of a callback request of an app to a host, recording the use of its connection
+func (c *ConnectionRecorder) Trace(ctx context.Context, appId, host string) context.Context {
+	var mu sync.Mutex
+	dials := map[string]time.Time{}
+	var handshakeStart time.Time
+
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			c.record(appId, host, func(counters *connectionCounters) {
+				counters.stats.Requests++
+				if info.Reused {
+					counters.stats.ReusedConns++
+				}
+			})
+		},
+		// several addresses of a host may be dialed concurrently
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			defer mu.Unlock()
+			dials[network+addr] = c.clock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			elapsed := c.clock().Sub(dials[network+addr])
+			mu.Unlock()
+			c.record(appId, host, func(counters *connectionCounters) {
+				counters.stats.Dials++
+				counters.dialTime += elapsed
+				if err != nil {
+					counters.stats.DialErrors++
+				}
+			})
+		},
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			defer mu.Unlock()
+			handshakeStart = c.clock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			mu.Lock()
+			elapsed := c.clock().Sub(handshakeStart)
+			mu.Unlock()
+			c.record(appId, host, func(counters *connectionCounters) {
+				counters.stats.TLSHandshakes++
+				counters.handshakeTime += elapsed
+				if err != nil {
+					counters.stats.TLSHandshakeErrors++
+				}
+			})
+		},
+	})
+}
+
+func (c *ConnectionRecorder) record(appId, host string, update func(*connectionCounters)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hosts, ok := c.apps[appId]
+	if !ok {
+		hosts = map[string]*connectionCounters{}
+		c.apps[appId] = hosts
+	}
+	if _, ok = hosts[host]; !ok && len(hosts) >= maxConnectionHosts {
+		host = otherHosts
+	}
+	counters, ok := hosts[host]
+	if !ok {
+		counters = &connectionCounters{stats: ConnectionStats{Host: host, Since: c.clock()}}
+		hosts[host] = counters
+	}
+
+	update(counters)
+	counters.stats.LastUsedAt = c.clock()
+}
+
+// Stats returns the use of the connections of the callbacks of an app by host, most requested first
+func (c *ConnectionRecorder) Stats(appId string) []ConnectionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]ConnectionStats, 0, len(c.apps[appId]))
+	for _, counters := range c.apps[appId] {
+		s := counters.stats
+		if s.Requests > 0 {
+			s.ReuseRatio = float64(s.ReusedConns) / float64(s.Requests)
+		}
+		if s.Dials > 0 {
+			s.AvgDialMillis = float64(counters.dialTime.Microseconds()) / float64(s.Dials) / 1000
+		}
+		if s.TLSHandshakes > 0 {
+			s.AvgTLSHandshakeMillis = float64(counters.handshakeTime.Microseconds()) / float64(s.TLSHandshakes) / 1000
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Requests != stats[j].Requests {
+			return stats[i].Requests > stats[j].Requests
+		}
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
+
+// Reset drops the statistics of an app, e.g. once its callback client is rebuilt with another pool sizing
+func (c *ConnectionRecorder) Reset(appId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.apps, appId)
+}
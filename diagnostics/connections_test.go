@@ -0,0 +1,74 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestConnectionRecorderTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := server.Client()
+	host := mustHost(t, server.URL)
+
+	recorder := NewConnectionRecorder()
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		response, err := client.Do(req.WithContext(recorder.Trace(context.Background(), "test", host)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = ioutil.ReadAll(response.Body)
+		_ = response.Body.Close()
+	}
+
+	stats := recorder.Stats("test")
+	if len(stats) != 1 {
+		t.Fatalf("expected the stats of 1 host, got %+v", stats)
+	}
+	s := stats[0]
+	if s.Host != host || s.Requests != 3 || s.ReusedConns != 2 || s.Dials != 1 || s.TLSHandshakes != 1 || s.DialErrors != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if s.ReuseRatio < 0.66 || s.ReuseRatio > 0.67 {
+		t.Errorf("expected a reuse ratio of 2/3, got %f", s.ReuseRatio)
+	}
+	if len(recorder.Stats("other")) != 0 {
+		t.Errorf("expected no stats for another app")
+	}
+
+	recorder.Reset("test")
+	if len(recorder.Stats("test")) != 0 {
+		t.Errorf("expected the stats to be reset")
+	}
+}
+
+func TestConnectionRecorderCapsHosts(t *testing.T) {
+	recorder := NewConnectionRecorder()
+	for i := 0; i < maxConnectionHosts+5; i++ {
+		recorder.record("test", fmt.Sprintf("host-%d", i), func(counters *connectionCounters) {
+			counters.stats.Requests++
+		})
+	}
+
+	stats := recorder.Stats("test")
+	if len(stats) != maxConnectionHosts+1 {
+		t.Fatalf("expected %d hosts, got %d", maxConnectionHosts+1, len(stats))
+	}
+	if stats[0].Host != otherHosts || stats[0].Requests != 5 {
+		t.Errorf("expected the further hosts to be counted together, got %+v", stats[0])
+	}
+}
+
+func mustHost(t *testing.T, rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Host
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package diagnostics keeps the slowest Cassandra queries and the poll lag of every partition polled by the node
+// for capacity planning without direct access to the Cassandra nodes.
+package diagnostics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const (
+	defaultSlowQueryThreshold = 100 * time.Millisecond
+	defaultSlowQueryLimit     = 50
+
+	// latencyWeight is the weight of the latest query in the recent query latency
+	latencyWeight = 0.1
+
+	// pollLagRetention drops the poll lag of partitions which are no longer polled by the node
+	pollLagRetention = 10 * time.Minute
+)
+
+// SlowQuery is a Cassandra query or batch which took at least the slow query threshold
+type SlowQuery struct {
+	Statement      string    `json:"statement"`
+	Keyspace       string    `json:"keyspace"`
+	DurationMillis int64     `json:"durationMillis"`
+	Rows           int       `json:"rows"`
+	Attempt        int       `json:"attempt"`
+	Error          string    `json:"error,omitempty"`
+	StartedAt      time.Time `json:"startedAt"`
+}
+
+// PollLag is the outcome of the latest poll of a partition
+type PollLag struct {
+	AppId       string    `json:"appId"`
+	PartitionId int       `json:"partitionId"`
+	Bucket      time.Time `json:"bucket"`
+	PolledAt    time.Time `json:"polledAt"`
+	LagMillis   int64     `json:"lagMillis"`
+	Schedules   int       `json:"schedules"`
+}
+
+type partitionKey struct {
+	appId       string
+	partitionId int
+}
+
+// Recorder observes Cassandra queries through the gocql QueryObserver and BatchObserver interfaces and
+// records the polls of the partitions
+type Recorder struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	limit     int
+	slow      []SlowQuery
+	polls     map[partitionKey]PollLag
+	// latency is the exponentially weighted moving average of the query durations
+	latency time.Duration
+}
+
+var recorder = NewRecorder(defaultSlowQueryThreshold, defaultSlowQueryLimit)
+
+// Default returns the recorder observing the Cassandra sessions and pollers of the node
+func Default() *Recorder {
+	return recorder
+}
+
+func NewRecorder(threshold time.Duration, limit int) *Recorder {
+	return &Recorder{
+		threshold: threshold,
+		limit:     limit,
+		polls:     map[partitionKey]PollLag{},
+	}
+}
+
+// Configure sets the duration from which queries are recorded and the number of slowest queries kept
+func (r *Recorder) Configure(threshold time.Duration, limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.threshold = threshold
+	r.limit = limit
+	r.slow = r.slowest(r.slow)
+}
+
+func (r *Recorder) ObserveQuery(_ context.Context, q gocql.ObservedQuery) {
+	r.recordQuery(SlowQuery{
+		Statement:      q.Statement,
+		Keyspace:       q.Keyspace,
+		DurationMillis: q.End.Sub(q.Start).Milliseconds(),
+		Rows:           q.Rows,
+		Attempt:        q.Attempt,
+		Error:          errorString(q.Err),
+		StartedAt:      q.Start,
+	}, q.End.Sub(q.Start))
+}
+
+func (r *Recorder) ObserveBatch(_ context.Context, b gocql.ObservedBatch) {
+	r.recordQuery(SlowQuery{
+		Statement:      "BEGIN BATCH " + strings.Join(b.Statements, "; ") + " APPLY BATCH",
+		Keyspace:       b.Keyspace,
+		DurationMillis: b.End.Sub(b.Start).Milliseconds(),
+		Attempt:        b.Attempt,
+		Error:          errorString(b.Err),
+		StartedAt:      b.Start,
+	}, b.End.Sub(b.Start))
+}
+
+func (r *Recorder) recordQuery(query SlowQuery, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.latency == 0 {
+		r.latency = duration
+	} else {
+		r.latency += time.Duration(latencyWeight * float64(duration-r.latency))
+	}
+
+	if duration < r.threshold || r.limit <= 0 {
+		return
+	}
+	r.slow = r.slowest(append(r.slow, query))
+}
+
+// slowest sorts the queries by descending duration and keeps the limit slowest
+func (r *Recorder) slowest(queries []SlowQuery) []SlowQuery {
+	sort.SliceStable(queries, func(i, j int) bool {
+		return queries[i].DurationMillis > queries[j].DurationMillis
+	})
+	if len(queries) > r.limit {
+		queries = queries[:r.limit]
+	}
+	return queries
+}
+
+// SlowQueries returns the recorded queries, slowest first
+func (r *Recorder) SlowQueries() []SlowQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]SlowQuery{}, r.slow...)
+}
+
+// QueryLatency returns the moving average of the latency of the recent queries
+func (r *Recorder) QueryLatency() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.latency
+}
+
+// RecordPoll records the poll of the schedules of a partition in the bucket, finished at polledAt.
+// The lag is the delay between the start of the bucket and the end of its poll.
+func (r *Recorder) RecordPoll(appId string, partitionId int, bucket time.Time, schedules int, polledAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.polls[partitionKey{appId, partitionId}] = PollLag{
+		AppId:       appId,
+		PartitionId: partitionId,
+		Bucket:      bucket,
+		PolledAt:    polledAt,
+		LagMillis:   polledAt.Sub(bucket).Milliseconds(),
+		Schedules:   schedules,
+	}
+}
+
+// PollLags returns the latest poll of the partitions polled in the retention period, most lagging first
+func (r *Recorder) PollLags(now time.Time) []PollLag {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lags := make([]PollLag, 0, len(r.polls))
+	for key, lag := range r.polls {
+		if now.Sub(lag.PolledAt) > pollLagRetention {
+			delete(r.polls, key)
+			continue
+		}
+		lags = append(lags, lag)
+	}
+
+	sort.Slice(lags, func(i, j int) bool {
+		if lags[i].LagMillis != lags[j].LagMillis {
+			return lags[i].LagMillis > lags[j].LagMillis
+		}
+		if lags[i].AppId != lags[j].AppId {
+			return lags[i].AppId < lags[j].AppId
+		}
+		return lags[i].PartitionId < lags[j].PartitionId
+	})
+	return lags
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,85 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestRecorderKeepsSlowestQueries(t *testing.T) {
+	r := NewRecorder(10*time.Millisecond, 2)
+	start := time.Unix(1700000000, 0)
+
+	for _, d := range []time.Duration{5, 20, 50, 30} {
+		r.ObserveQuery(context.Background(), gocql.ObservedQuery{
+			Statement: "SELECT",
+			Start:     start,
+			End:       start.Add(d * time.Millisecond),
+		})
+	}
+	r.ObserveBatch(context.Background(), gocql.ObservedBatch{
+		Statements: []string{"INSERT a", "INSERT b"},
+		Start:      start,
+		End:        start.Add(40 * time.Millisecond),
+		Err:        errors.New("timeout"),
+	})
+
+	slow := r.SlowQueries()
+	if len(slow) != 2 {
+		t.Fatalf("Expected 2 slow queries, got %d", len(slow))
+	}
+	if slow[0].DurationMillis != 50 || slow[1].DurationMillis != 40 {
+		t.Errorf("Expected the 50ms query and the 40ms batch, got %+v", slow)
+	}
+	if slow[1].Statement != "BEGIN BATCH INSERT a; INSERT b APPLY BATCH" || slow[1].Error != "timeout" {
+		t.Errorf("Unexpected batch %+v", slow[1])
+	}
+
+	r.Configure(10*time.Millisecond, 1)
+	if slow = r.SlowQueries(); len(slow) != 1 || slow[0].DurationMillis != 50 {
+		t.Errorf("Expected only the slowest query after lowering the limit, got %+v", slow)
+	}
+}
+
+func TestRecorderPollLags(t *testing.T) {
+	r := NewRecorder(time.Second, 1)
+	bucket := time.Unix(1700000000, 0)
+
+	r.RecordPoll("app", 0, bucket, 10, bucket.Add(2*time.Second))
+	r.RecordPoll("app", 1, bucket, 500, bucket.Add(9*time.Second))
+	r.RecordPoll("app", 0, bucket.Add(time.Minute), 3, bucket.Add(time.Minute+time.Second))
+	r.RecordPoll("stale", 0, bucket.Add(-time.Hour), 1, bucket.Add(-time.Hour))
+
+	lags := r.PollLags(bucket.Add(2 * time.Minute))
+	if len(lags) != 2 {
+		t.Fatalf("Expected the stale partition to be dropped, got %+v", lags)
+	}
+	if lags[0].PartitionId != 1 || lags[0].LagMillis != 9000 || lags[0].Schedules != 500 {
+		t.Errorf("Expected partition 1 to lag the most, got %+v", lags[0])
+	}
+	if lags[1].PartitionId != 0 || lags[1].LagMillis != 1000 || lags[1].Schedules != 3 {
+		t.Errorf("Expected the latest poll of partition 0, got %+v", lags[1])
+	}
+}
+
+func TestRecorderQueryLatency(t *testing.T) {
+	r := NewRecorder(time.Second, 1)
+	start := time.Unix(1700000000, 0)
+
+	if latency := r.QueryLatency(); latency != 0 {
+		t.Fatalf("Expected no latency before any query, got %s", latency)
+	}
+
+	r.ObserveQuery(context.Background(), gocql.ObservedQuery{Start: start, End: start.Add(100 * time.Millisecond)})
+	if latency := r.QueryLatency(); latency != 100*time.Millisecond {
+		t.Errorf("Expected the first query to set the latency, got %s", latency)
+	}
+
+	r.ObserveQuery(context.Background(), gocql.ObservedQuery{Start: start, End: start.Add(1100 * time.Millisecond)})
+	if latency := r.QueryLatency(); latency != 200*time.Millisecond {
+		t.Errorf("Expected a slow query to move the average by a tenth of the difference, got %s", latency)
+	}
+}
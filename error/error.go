@@ -21,15 +21,19 @@ package error
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"sort"
+	"strings"
 
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
 )
 
 type AppError struct {
-	Code int
-	Err  error
+	Code    int
+	Err     error
+	Details []FieldError
 }
 
 func (err AppError) Error() string {
@@ -40,10 +44,38 @@ func NewError(code int, err error) AppError {
 	return AppError{Code: code, Err: err}
 }
 
+// FieldError tells which field of the request failed its validation and why
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (err FieldError) Error() string {
+	return err.Message
+}
+
+// NewValidationError returns an error carrying the fields failing the validation,
+// its message joins the messages of the fields as the earlier comma separated errors did
+func NewValidationError(code int, details []FieldError) AppError {
+	return AppError{Code: code, Err: errors.New(strings.Join(Messages(details), ",")), Details: details}
+}
+
+// Messages returns the messages of the field errors
+func Messages(details []FieldError) []string {
+	var messages []string
+	for _, detail := range details {
+		messages = append(messages, detail.Message)
+	}
+	return messages
+}
+
 const (
 	InvalidDataCode        = 400
+	Forbidden              = 403
 	DataNotFound           = 404
+	RequestTimeout         = 408
 	Conflict               = 409
+	PayloadTooLarge        = 413
 	UnprocessableEntity    = 422
 	TooManyRequests        = 429
 	InvalidAppId           = 4001
@@ -57,45 +89,128 @@ const (
 	InvalidCallbackType    = 5005
 	DataFetchFailure       = 5006
 	EntityBootFailed       = 5007
+	DataStoreTimeout       = 5008
+	GovernanceUnavailable  = 5009
 )
 
-func Handle(w http.ResponseWriter, r *http.Request, err AppError) {
-	glog.Errorf(err.Error())
-	responseStatus := make(map[string]interface{})
-	responseStatus[constants.StatusType] = constants.Fail
-	responseStatus[constants.StatusMessage] = err.Error()
-	responseStatus[constants.StatusCode] = err.Code
-	response := make(map[string]interface{})
-	response["status"] = responseStatus
-	switch err.Code {
+// names are the machine readable codes of the errors, they are part of the API and must not change
+var names = map[int]string{
+	InvalidDataCode:        "INVALID_DATA",
+	Forbidden:              "FORBIDDEN",
+	DataNotFound:           "NOT_FOUND",
+	RequestTimeout:         "REQUEST_TIMEOUT",
+	Conflict:               "CONFLICT",
+	PayloadTooLarge:        "PAYLOAD_TOO_LARGE",
+	UnprocessableEntity:    "UNPROCESSABLE_ENTITY",
+	TooManyRequests:        "TOO_MANY_REQUESTS",
+	InvalidAppId:           "INVALID_APP_ID",
+	DeactivatedApp:         "DEACTIVATED_APP",
+	ActivatedApp:           "ACTIVATED_APP",
+	BulkActionPushFailure:  "BULK_ACTION_PUSH_FAILURE",
+	InvalidBulkActionType:  "INVALID_BULK_ACTION_TYPE",
+	UnmarshalErrorCode:     "MALFORMED_REQUEST",
+	ValidationFailCode:     "VALIDATION_FAILED",
+	DataPersistenceFailure: "DATA_PERSISTENCE_FAILURE",
+	InvalidCallbackType:    "INVALID_CALLBACK_TYPE",
+	DataFetchFailure:       "DATA_FETCH_FAILURE",
+	EntityBootFailed:       "ENTITY_BOOT_FAILED",
+	DataStoreTimeout:       "DATA_STORE_TIMEOUT",
+	GovernanceUnavailable:  "GOVERNANCE_UNAVAILABLE",
+}
+
+// Name returns the machine readable code of the error code, INTERNAL_ERROR for an unknown code
+func Name(code int) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return "INTERNAL_ERROR"
+}
+
+// Names returns the machine readable codes of the errors in order
+func Names() []string {
+	list := []string{Name(0)}
+	for _, name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// HTTPStatus returns the http status written for the error code
+func HTTPStatus(code int) int {
+	switch code {
 	case DataNotFound:
-		w.WriteHeader(http.StatusNotFound)
-	case InvalidDataCode:
-		w.WriteHeader(http.StatusBadRequest)
-	case ValidationFailCode:
-		w.WriteHeader(http.StatusBadRequest)
-	case InvalidAppId:
-		w.WriteHeader(http.StatusBadRequest)
-	case DeactivatedApp:
-		w.WriteHeader(http.StatusBadRequest)
-	case ActivatedApp:
-		w.WriteHeader(http.StatusBadRequest)
-	case DataPersistenceFailure:
-		w.WriteHeader(http.StatusInternalServerError)
-	case InvalidCallbackType:
-		w.WriteHeader(http.StatusInternalServerError)
-	case UnmarshalErrorCode:
-		w.WriteHeader(http.StatusBadRequest)
+		return http.StatusNotFound
+	case Forbidden:
+		return http.StatusForbidden
+	case InvalidDataCode, ValidationFailCode, InvalidAppId, DeactivatedApp, ActivatedApp, UnmarshalErrorCode:
+		return http.StatusBadRequest
 	case TooManyRequests:
-		w.WriteHeader(http.StatusTooManyRequests)
+		return http.StatusTooManyRequests
 	case UnprocessableEntity:
-		w.WriteHeader(http.StatusUnprocessableEntity)
+		return http.StatusUnprocessableEntity
+	case RequestTimeout:
+		return http.StatusRequestTimeout
 	case Conflict:
-		w.WriteHeader(http.StatusConflict)
+		return http.StatusConflict
+	case PayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case DataStoreTimeout, GovernanceUnavailable:
+		return http.StatusServiceUnavailable
 	default:
-		w.WriteHeader(http.StatusInternalServerError)
+		return http.StatusInternalServerError
 	}
-	jsonStr, _ := json.Marshal(response)
-	_, _ = w.Write(jsonStr)
+}
+
+// ErrorResponse is the body written for a failed request.
+// The status is kept for the older clients, the error carries the machine readable code and the failing fields.
+type ErrorResponse struct {
+	Status ErrorStatus `json:"status"`
+	Error  ErrorDetail `json:"error"`
+}
+
+type ErrorStatus struct {
+	StatusCode    int    `json:"statusCode"`
+	StatusMessage string `json:"statusMessage"`
+	StatusType    string `json:"statusType"`
+}
+
+type ErrorDetail struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// NewErrorResponse returns the body written for the error
+func NewErrorResponse(err AppError) ErrorResponse {
+	return ErrorResponse{
+		Status: ErrorStatus{
+			StatusCode:    err.Code,
+			StatusMessage: err.Error(),
+			StatusType:    constants.Fail,
+		},
+		Error: ErrorDetail{
+			Code:    Name(err.Code),
+			Message: err.Error(),
+			Details: err.Details,
+		},
+	}
+}
+
+// isTimeout tells whether the error is a data store query still timing out after its retries
+func isTimeout(err error) bool {
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+func Handle(w http.ResponseWriter, r *http.Request, err AppError) {
+	// a read or write timing out is reported as temporary rather than as a failure of the request
+	if (err.Code == DataFetchFailure || err.Code == DataPersistenceFailure) && isTimeout(err.Err) {
+		err.Code = DataStoreTimeout
+	}
+	logger.FromContext(r.Context()).WithFields(logger.Fields{"errorCode": err.Code}).Errorf("%s", err.Error())
+	jsonStr, _ := json.Marshal(NewErrorResponse(err))
 	w.Header().Set(constants.ContentType, constants.ApplicationJson)
+	w.WriteHeader(HTTPStatus(err.Code))
+	_, _ = w.Write(jsonStr)
 }
@@ -0,0 +1,88 @@
+package error
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHandle(t *testing.T) {
+	details := []FieldError{
+		{Field: "payload", Message: "Missing 'payload' parameter, cannot continue"},
+		{Field: "cronExpression.minute", Message: "minute field '61': out of range"},
+	}
+
+	for _, test := range []struct {
+		name    string
+		err     AppError
+		status  int
+		code    string
+		message string
+		details []FieldError
+	}{
+		{
+			name:    "error without details",
+			err:     NewError(DataNotFound, errors.New("schedule not found")),
+			status:  http.StatusNotFound,
+			code:    "NOT_FOUND",
+			message: "schedule not found",
+		},
+		{
+			name:    "validation error",
+			err:     NewValidationError(InvalidDataCode, details),
+			status:  http.StatusBadRequest,
+			code:    "INVALID_DATA",
+			message: "Missing 'payload' parameter, cannot continue,minute field '61': out of range",
+			details: details,
+		},
+		{
+			name:    "unknown code",
+			err:     NewError(42, errors.New("failed")),
+			status:  http.StatusInternalServerError,
+			code:    "INTERNAL_ERROR",
+			message: "failed",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handle(w, httptest.NewRequest(http.MethodGet, "/", nil), test.err)
+
+			if w.Code != test.status {
+				t.Errorf("expected status %d, got %d", test.status, w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("expected a json response, got %s", contentType)
+			}
+
+			var response ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if response.Status.StatusCode != test.err.Code || response.Status.StatusMessage != test.message {
+				t.Errorf("unexpected status %+v", response.Status)
+			}
+			if response.Error.Code != test.code || response.Error.Message != test.message {
+				t.Errorf("unexpected error %+v", response.Error)
+			}
+			if !reflect.DeepEqual(response.Error.Details, test.details) {
+				t.Errorf("expected details %+v, got %+v", test.details, response.Error.Details)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	seen := map[string]bool{}
+	for _, name := range Names() {
+		if seen[name] {
+			t.Errorf("duplicate code %s", name)
+		}
+		seen[name] = true
+	}
+	if !seen["INTERNAL_ERROR"] || !seen["DATA_STORE_TIMEOUT"] {
+		t.Errorf("missing codes in %v", Names())
+	}
+}
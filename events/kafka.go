@@ -0,0 +1,77 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher publishes events to a Kafka topic with the Kafka client.
+// Events are keyed by the schedule id so that the events of a schedule land on the same partition in order.
+type KafkaPublisher struct {
+	writer  *kafka.Writer
+	timeout time.Duration
+}
+
+// NewKafkaPublisher creates a publisher for the configured topic, the address is the comma separated host:port of
+// the Kafka brokers
+func NewKafkaPublisher(config conf.EventPublisherConfig) (*KafkaPublisher, error) {
+	if config.Address == "" || config.Topic == "" {
+		return nil, errors.New("kafka event publisher requires an address and a topic")
+	}
+
+	timeout := config.TimeoutMillis * time.Millisecond
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(strings.Split(config.Address, ",")...),
+			Topic:        config.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchSize:    1,
+			WriteTimeout: timeout,
+			ReadTimeout:  timeout,
+		},
+		timeout: timeout,
+	}, nil
+}
+
+// Publish writes the event and waits for the brokers to acknowledge it
+func (k *KafkaPublisher) Publish(event store.Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), k.timeout)
+	defer cancel()
+	return k.writer.WriteMessages(ctx, kafka.Message{Key: []byte(event.ScheduleId), Value: value})
+}
+
+func (k *KafkaPublisher) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/nats-io/nats.go"
+)
+
+// NatsPublisher publishes events to a NATS subject with the NATS client, which reconnects to the server on its own
+// and buffers the events published while it is reconnecting.
+type NatsPublisher struct {
+	subject string
+	timeout time.Duration
+	conn    *nats.Conn
+}
+
+// NewNatsPublisher creates a publisher for the configured subject, the address is the comma separated urls or
+// host:port of the NATS servers
+func NewNatsPublisher(config conf.EventPublisherConfig) (*NatsPublisher, error) {
+	if config.Address == "" || config.Topic == "" {
+		return nil, errors.New("nats event publisher requires an address and a topic")
+	}
+
+	if strings.ContainsAny(config.Topic, " \t\r\n") {
+		return nil, fmt.Errorf("invalid nats subject: %s", config.Topic)
+	}
+
+	timeout := config.TimeoutMillis * time.Millisecond
+	conn, err := nats.Connect(
+		config.Address,
+		nats.Name("goscheduler"),
+		nats.Timeout(timeout),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+			logger.Errorf("NATS server %s responded with %s", config.Address, err.Error())
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("connecting to nats %s: %w", config.Address, err)
+	}
+
+	return &NatsPublisher{subject: config.Topic, timeout: timeout, conn: conn}, nil
+}
+
+// Publish publishes the event and waits for the server to have processed it
+func (n *NatsPublisher) Publish(event store.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err = n.conn.Publish(n.subject, payload); err != nil {
+		return err
+	}
+	return n.conn.FlushTimeout(n.timeout)
+}
+
+// Close publishes the buffered events before closing the connection
+func (n *NatsPublisher) Close() error {
+	return n.conn.Drain()
+}
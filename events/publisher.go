@@ -0,0 +1,81 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"fmt"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	Noop  = "noop"
+	Kafka = "kafka"
+	Nats  = "nats"
+)
+
+// Publisher publishes the lifecycle events of schedules to a message broker
+type Publisher interface {
+	Publish(event store.Event) error
+	Close() error
+}
+
+// Factory creates a publisher from the event publisher configuration
+type Factory func(config conf.EventPublisherConfig) (Publisher, error)
+
+// Registry holds the publisher factories by publisher type.
+// Custom publishers can be added with Register before the scheduler is created.
+var Registry = map[string]Factory{
+	Noop:  func(config conf.EventPublisherConfig) (Publisher, error) { return NoopPublisher{}, nil },
+	Kafka: func(config conf.EventPublisherConfig) (Publisher, error) { return NewKafkaPublisher(config) },
+	Nats:  func(config conf.EventPublisherConfig) (Publisher, error) { return NewNatsPublisher(config) },
+}
+
+// Register adds or replaces the factory of a publisher type
+func Register(publisherType string, factory Factory) {
+	Registry[publisherType] = factory
+}
+
+// NewPublisher creates the publisher configured in the supplied configuration.
+// An empty type creates a noop publisher.
+func NewPublisher(config conf.EventPublisherConfig) (Publisher, error) {
+	publisherType := config.Type
+	if publisherType == "" {
+		publisherType = Noop
+	}
+
+	factory, ok := Registry[publisherType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event publisher type: %s", publisherType)
+	}
+	return factory(config)
+}
+
+// NoopPublisher discards all the events
+type NoopPublisher struct{}
+
+func (NoopPublisher) Publish(event store.Event) error {
+	return nil
+}
+
+func (NoopPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+	"github.com/segmentio/kafka-go"
+)
+
+func testEvent() store.Event {
+	return store.NewEvent(store.ScheduleCreated, store.Schedule{
+		ScheduleId: gocql.TimeUUID(),
+		AppId:      "test",
+		Payload:    "{}",
+		Status:     store.Scheduled,
+	})
+}
+
+func TestNewPublisher(t *testing.T) {
+	publisher, err := NewPublisher(conf.EventPublisherConfig{})
+	if err != nil {
+		t.Fatalf("Expected noop publisher, got error %v", err)
+	}
+	if _, ok := publisher.(NoopPublisher); !ok {
+		t.Errorf("Expected NoopPublisher, got %T", publisher)
+	}
+
+	if _, err = NewPublisher(conf.EventPublisherConfig{Type: "unknown"}); err == nil {
+		t.Errorf("Expected error for unknown publisher type")
+	}
+
+	if _, err = NewPublisher(conf.EventPublisherConfig{Type: Kafka}); err == nil {
+		t.Errorf("Expected error for kafka publisher without address")
+	}
+
+	Register("custom", func(config conf.EventPublisherConfig) (Publisher, error) { return NoopPublisher{}, nil })
+	defer delete(Registry, "custom")
+	if _, err = NewPublisher(conf.EventPublisherConfig{Type: "custom"}); err != nil {
+		t.Errorf("Expected registered publisher to be created, got error %v", err)
+	}
+}
+
+func TestNewKafkaPublisher(t *testing.T) {
+	publisher, err := NewKafkaPublisher(conf.EventPublisherConfig{Address: "kafka-1:9092,kafka-2:9092", Topic: "events", TimeoutMillis: 1000})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer publisher.Close()
+
+	if publisher.writer.Topic != "events" || publisher.writer.Addr.String() != "kafka-1:9092,kafka-2:9092" {
+		t.Errorf("Unexpected writer of topic %s to %s", publisher.writer.Topic, publisher.writer.Addr)
+	}
+	if _, ok := publisher.writer.Balancer.(*kafka.Hash); !ok {
+		t.Errorf("Expected the events to be partitioned by key, got %T", publisher.writer.Balancer)
+	}
+}
+
+func TestKafkaPublisher_PublishFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	publisher, _ := NewKafkaPublisher(conf.EventPublisherConfig{Address: address, Topic: "events", TimeoutMillis: 200})
+	defer publisher.Close()
+
+	start := time.Now()
+	if err = publisher.Publish(testEvent()); err == nil {
+		t.Errorf("Expected error for an unreachable broker")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the publish to give up after its timeout, took %s", elapsed)
+	}
+}
+
+// serveNats accepts a single client, acknowledges its pings and sends the messages it publishes to the channel
+func serveNats(listener net.Listener, published chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	_, _ = conn.Write([]byte("INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n"))
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		case strings.HasPrefix(line, "PUB "):
+			payload, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			published <- []string{line, strings.TrimRight(payload, "\r\n")}
+		}
+	}
+}
+
+func TestNatsPublisher_Publish(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer listener.Close()
+
+	published := make(chan []string, 1)
+	go serveNats(listener, published)
+
+	publisher, err := NewNatsPublisher(conf.EventPublisherConfig{Address: listener.Addr().String(), Topic: "goscheduler.events", TimeoutMillis: 1000})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer publisher.Close()
+
+	event := testEvent()
+	if err = publisher.Publish(event); err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+
+	lines := <-published
+	if !strings.HasPrefix(lines[0], "PUB goscheduler.events ") {
+		t.Errorf("Unexpected protocol message %s", lines[0])
+	}
+
+	var received store.Event
+	if err = json.Unmarshal([]byte(lines[1]), &received); err != nil || received.EventId != event.EventId {
+		t.Errorf("Unexpected payload %s", lines[1])
+	}
+}
+
+func TestNewNatsPublisher_InvalidSubject(t *testing.T) {
+	if _, err := NewNatsPublisher(conf.EventPublisherConfig{Address: "127.0.0.1:4222", Topic: "bad subject"}); err == nil {
+		t.Errorf("Expected error for subject with whitespace")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package faults injects Cassandra timeouts, callback latency and node pauses at the configured rates, for the game
+// days verifying that the retries, the fencing and the lag alerts of a staging cluster behave. Nothing is injected
+// unless fault injection is enabled.
+package faults
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	p "github.com/myntra/goscheduler/monitoring"
+)
+
+// Kinds of the injected faults, the label of their counter
+const (
+	CassandraTimeout = "cassandra_timeout"
+	CallbackLatency  = "callback_latency"
+	NodePause        = "node_pause"
+)
+
+// pauseCheckInterval is the interval at which the node decides whether to pause
+const pauseCheckInterval = time.Minute
+
+// ErrInjectedTimeout is the timeout of the Cassandra queries failed on purpose. It is reported as a timeout so that
+// it is retried like the timeouts of the cluster.
+var ErrInjectedTimeout = fmt.Errorf("fault injected: %w", gocql.ErrTimeoutNoResponse)
+
+// ValidateConfig checks the rates and durations of the faults when fault injection is enabled
+func ValidateConfig(config conf.FaultInjectionConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	for name, rate := range map[string]float64{
+		"CassandraTimeoutRate": config.CassandraTimeoutRate,
+		"CallbackLatencyRate":  config.CallbackLatencyRate,
+		"NodePauseRate":        config.NodePauseRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("fault injection %s %v must be between 0 and 1", name, rate)
+		}
+	}
+	if config.CallbackLatencyMillis < 0 || config.NodePauseSeconds < 0 {
+		return errors.New("fault injection durations cannot be negative")
+	}
+	return nil
+}
+
+// Injector decides which operations of the node fail or stall
+type Injector struct {
+	mu          sync.Mutex
+	config      conf.FaultInjectionConfig
+	queries     map[string]bool
+	monitor     p.Monitor
+	random      *rand.Rand
+	pausedUntil time.Time
+	stop        chan struct{}
+}
+
+var injector = NewInjector()
+
+// Default returns the injector of the node, which injects nothing until it is configured
+func Default() *Injector {
+	return injector
+}
+
+func NewInjector() *Injector {
+	return &Injector{random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Configure sets the faults to inject and starts pausing the node at the configured rate
+func (i *Injector) Configure(config conf.FaultInjectionConfig, monitor p.Monitor) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.config = config
+	i.monitor = monitor
+	i.queries = make(map[string]bool, len(config.CassandraQueries))
+	for _, query := range config.CassandraQueries {
+		i.queries[query] = true
+	}
+
+	if i.stop != nil {
+		close(i.stop)
+		i.stop = nil
+	}
+	if config.Enabled && config.NodePauseRate > 0 && config.NodePauseSeconds > 0 {
+		logger.Warningf("Fault injection pauses the node for %ds with a chance of %v every minute", config.NodePauseSeconds, config.NodePauseRate)
+		i.stop = make(chan struct{})
+		go i.pauseRandomly(i.stop)
+	}
+}
+
+// QueryFault returns the error failing the Cassandra query, nil for the queries left to run
+func (i *Injector) QueryFault(query string) error {
+	if i == nil {
+		return nil
+	}
+
+	i.mu.Lock()
+	inject := i.config.Enabled && (len(i.queries) == 0 || i.queries[query]) && i.hit(i.config.CassandraTimeoutRate)
+	i.mu.Unlock()
+
+	if !inject {
+		return nil
+	}
+	i.record(CassandraTimeout)
+	return ErrInjectedTimeout
+}
+
+// Transport delays the requests of the http callbacks sent through the transport at the configured rate.
+// The delay is counted in the timeout of the request, so a delay longer than the timeout fails the request.
+func (i *Injector) Transport(next http.RoundTripper) http.RoundTripper {
+	i.mu.Lock()
+	enabled := i.config.Enabled && i.config.CallbackLatencyRate > 0
+	i.mu.Unlock()
+
+	if !enabled {
+		return next
+	}
+	return latencyRoundTripper{injector: i, next: next}
+}
+
+// WaitWhilePaused blocks while the node is paused
+func (i *Injector) WaitWhilePaused() {
+	i.mu.Lock()
+	pause := time.Until(i.pausedUntil)
+	i.mu.Unlock()
+
+	if pause > 0 {
+		time.Sleep(pause)
+	}
+}
+
+// Paused tells whether the node is paused
+func (i *Injector) Paused() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Now().Before(i.pausedUntil)
+}
+
+// pauseRandomly decides every minute whether to pause the node, until stopped
+func (i *Injector) pauseRandomly(stop <-chan struct{}) {
+	ticker := time.NewTicker(pauseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			i.maybePause(time.Now())
+		}
+	}
+}
+
+// maybePause pauses the node at the configured rate, unless it is already paused
+func (i *Injector) maybePause(now time.Time) {
+	i.mu.Lock()
+	pause := i.config.Enabled && !now.Before(i.pausedUntil) && i.hit(i.config.NodePauseRate)
+	seconds := i.config.NodePauseSeconds
+	if pause {
+		i.pausedUntil = now.Add(time.Duration(seconds) * time.Second)
+	}
+	i.mu.Unlock()
+
+	if pause {
+		logger.Warningf("Fault injection pauses the node for %ds", seconds)
+		i.record(NodePause)
+	}
+}
+
+// callbackLatency returns the delay added to a callback, 0 for the callbacks left alone
+func (i *Injector) callbackLatency() time.Duration {
+	i.mu.Lock()
+	inject := i.config.Enabled && i.config.CallbackLatencyMillis > 0 && i.hit(i.config.CallbackLatencyRate)
+	latency := time.Duration(i.config.CallbackLatencyMillis) * time.Millisecond
+	i.mu.Unlock()
+
+	if !inject {
+		return 0
+	}
+	i.record(CallbackLatency)
+	return latency
+}
+
+// hit draws whether a fault of the rate is injected. It is called with the lock held.
+func (i *Injector) hit(rate float64) bool {
+	return rate > 0 && i.random.Float64() < rate
+}
+
+func (i *Injector) record(fault string) {
+	i.mu.Lock()
+	monitor := i.monitor
+	i.mu.Unlock()
+
+	if monitor != nil {
+		monitor.IncCounter(constants.InjectedFaultCount, map[string]string{"fault": fault}, 1)
+	}
+}
+
+// latencyRoundTripper delays the requests before sending them
+type latencyRoundTripper struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+func (l latencyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if latency := l.injector.callbackLatency(); latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("fault injected: %w", req.Context().Err())
+		}
+	}
+	return l.next.RoundTrip(req)
+}
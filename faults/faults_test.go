@@ -0,0 +1,110 @@
+package faults
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+)
+
+type countingMonitor map[string]int
+
+func (m countingMonitor) IncCounter(_ string, labels map[string]string, value int) {
+	m[labels["fault"]] += value
+}
+
+func (m countingMonitor) RecordTiming(string, map[string]string, time.Duration) {}
+
+func (m countingMonitor) SetGauge(string, map[string]string, float64) {}
+
+func TestValidateConfig(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config conf.FaultInjectionConfig
+		valid  bool
+	}{
+		{"Disabled", conf.FaultInjectionConfig{CassandraTimeoutRate: 2}, true},
+		{"Valid", conf.FaultInjectionConfig{Enabled: true, CassandraTimeoutRate: 0.1, NodePauseRate: 1, NodePauseSeconds: 30}, true},
+		{"RateAboveOne", conf.FaultInjectionConfig{Enabled: true, CallbackLatencyRate: 1.5}, false},
+		{"NegativeRate", conf.FaultInjectionConfig{Enabled: true, NodePauseRate: -0.1}, false},
+		{"NegativeLatency", conf.FaultInjectionConfig{Enabled: true, CallbackLatencyMillis: -1}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if err := ValidateConfig(test.config); (err == nil) != test.valid {
+				t.Errorf("expected valid %v, got %v", test.valid, err)
+			}
+		})
+	}
+}
+
+func TestInjector_QueryFault(t *testing.T) {
+	var nilInjector *Injector
+	if err := nilInjector.QueryFault("select_schedules"); err != nil {
+		t.Errorf("expected no fault without an injector, got %v", err)
+	}
+
+	injector := NewInjector()
+	if err := injector.QueryFault("select_schedules"); err != nil {
+		t.Errorf("expected no fault until configured, got %v", err)
+	}
+
+	monitor := countingMonitor{}
+	injector.Configure(conf.FaultInjectionConfig{Enabled: true, CassandraTimeoutRate: 1}, monitor)
+	if err := injector.QueryFault("select_schedules"); !errors.Is(err, gocql.ErrTimeoutNoResponse) {
+		t.Errorf("expected an injected timeout, got %v", err)
+	}
+	if monitor[CassandraTimeout] != 1 {
+		t.Errorf("expected the injected timeout counted, got %v", monitor)
+	}
+}
+
+func TestInjector_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	injector := NewInjector()
+	injector.Configure(conf.FaultInjectionConfig{Enabled: true, CallbackLatencyRate: 1, CallbackLatencyMillis: 50}, nil)
+	client := &http.Client{Transport: injector.Transport(http.DefaultTransport)}
+
+	start := time.Now()
+	response, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the callback delayed by 50ms, took %v", elapsed)
+	}
+
+	client.Timeout = 10 * time.Millisecond
+	if _, err = client.Get(server.URL); err == nil {
+		t.Error("expected the delay counted in the timeout of the callback")
+	}
+}
+
+func TestInjector_PausesTheNode(t *testing.T) {
+	injector := NewInjector()
+	monitor := countingMonitor{}
+	injector.Configure(conf.FaultInjectionConfig{Enabled: true, NodePauseRate: 1, NodePauseSeconds: 60}, monitor)
+	defer injector.Configure(conf.FaultInjectionConfig{}, nil)
+
+	now := time.Now()
+	injector.maybePause(now)
+	injector.maybePause(now.Add(time.Second))
+	if !injector.Paused() || monitor[NodePause] != 1 {
+		t.Errorf("expected a single pause of the node, got %v", monitor)
+	}
+
+	injector.mu.Lock()
+	injector.pausedUntil = time.Now().Add(20 * time.Millisecond)
+	injector.mu.Unlock()
+	start := time.Now()
+	injector.WaitWhilePaused()
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond || injector.Paused() {
+		t.Errorf("expected to wait for the end of the pause, waited %v", elapsed)
+	}
+}
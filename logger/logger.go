@@ -0,0 +1,127 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package logger provides structured logging behind the Logger interface. The package level
+// functions log through the default logger, which writes JSON lines through zap unless replaced
+// with SetDefault.
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Fields are the structured key value pairs attached to a log line
+type Fields map[string]interface{}
+
+// Common field names, so the lines of a request or a schedule can be filtered by the same key
+const (
+	RequestIdField  = "requestId"
+	ScheduleIdField = "scheduleId"
+	AppIdField      = "appId"
+	TraceIdField    = "traceId"
+)
+
+// Logger writes leveled log lines with structured fields
+type Logger interface {
+	// WithFields returns a logger that adds the fields to every line
+	WithFields(fields Fields) Logger
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warningf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	// Fatalf logs the line and exits the process
+	Fatalf(format string, args ...interface{})
+}
+
+// flusher is implemented by loggers that buffer lines
+type flusher interface {
+	Flush()
+}
+
+var (
+	mu  sync.RWMutex
+	std Logger = newDefaultZap()
+)
+
+// SetDefault replaces the logger used by the package level functions
+func SetDefault(l Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	std = l
+}
+
+// Default returns the logger used by the package level functions
+func Default() Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return std
+}
+
+// WithFields returns the default logger with the fields added to every line
+func WithFields(fields Fields) Logger {
+	return Default().WithFields(fields)
+}
+
+func Debug(args ...interface{}) {
+	Default().Debugf("%s", fmt.Sprint(args...))
+}
+
+func Debugf(format string, args ...interface{}) {
+	Default().Debugf(format, args...)
+}
+
+func Info(args ...interface{}) {
+	Default().Infof("%s", fmt.Sprint(args...))
+}
+
+func Infof(format string, args ...interface{}) {
+	Default().Infof(format, args...)
+}
+
+func Warning(args ...interface{}) {
+	Default().Warningf("%s", fmt.Sprint(args...))
+}
+
+func Warningf(format string, args ...interface{}) {
+	Default().Warningf(format, args...)
+}
+
+func Error(args ...interface{}) {
+	Default().Errorf("%s", fmt.Sprint(args...))
+}
+
+func Errorf(format string, args ...interface{}) {
+	Default().Errorf(format, args...)
+}
+
+func Fatal(args ...interface{}) {
+	Default().Fatalf("%s", fmt.Sprint(args...))
+}
+
+func Fatalf(format string, args ...interface{}) {
+	Default().Fatalf(format, args...)
+}
+
+// Flush flushes the default logger if it buffers lines
+func Flush() {
+	if f, ok := Default().(flusher); ok {
+		f.Flush()
+	}
+}
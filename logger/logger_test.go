@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+type recordingLogger struct {
+	fields Fields
+	lines  *[]string
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{fields: Fields{}, lines: &[]string{}}
+}
+
+func (r *recordingLogger) WithFields(fields Fields) Logger {
+	merged := Fields{}
+	for k, v := range r.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &recordingLogger{fields: merged, lines: r.lines}
+}
+
+func (r *recordingLogger) log(level, format string, args ...interface{}) {
+	*r.lines = append(*r.lines, fmt.Sprintf("%s %v %s", level, r.fields, fmt.Sprintf(format, args...)))
+}
+
+func (r *recordingLogger) Debugf(format string, args ...interface{}) { r.log("debug", format, args...) }
+func (r *recordingLogger) Infof(format string, args ...interface{})  { r.log("info", format, args...) }
+func (r *recordingLogger) Warningf(format string, args ...interface{}) {
+	r.log("warning", format, args...)
+}
+func (r *recordingLogger) Errorf(format string, args ...interface{}) { r.log("error", format, args...) }
+func (r *recordingLogger) Fatalf(format string, args ...interface{}) { r.log("fatal", format, args...) }
+
+func TestPackageFunctionsUseDefault(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	recorder := newRecordingLogger()
+	SetDefault(recorder)
+
+	Info("schedule ", 1, " created")
+	Errorf("schedule %d failed", 2)
+	WithFields(Fields{AppIdField: "test"}).Warningf("app deactivated")
+
+	expected := []string{
+		"info map[] schedule 1 created",
+		"error map[] schedule 2 failed",
+		"warning map[appId:test] app deactivated",
+	}
+	if fmt.Sprint(*recorder.lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, *recorder.lines)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	recorder := newRecordingLogger()
+	SetDefault(recorder)
+
+	if RequestID(context.Background()) != "" {
+		t.Errorf("Expected no request id on an empty context")
+	}
+	if FromContext(context.Background()) != Default() {
+		t.Errorf("Expected the default logger on an empty context")
+	}
+
+	ctx := WithRequestID(context.Background(), "abc")
+	if RequestID(ctx) != "abc" {
+		t.Errorf("Expected request id abc, got %s", RequestID(ctx))
+	}
+
+	FromContext(ctx).WithFields(Fields{ScheduleIdField: "1"}).Infof("created")
+	if line := (*recorder.lines)[0]; line != "info map[requestId:abc scheduleId:1] created" {
+		t.Errorf("Unexpected line %s", line)
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	a, b := NewRequestID(), NewRequestID()
+	if len(a) != 32 || a == b {
+		t.Errorf("Expected distinct 32 character ids, got %s and %s", a, b)
+	}
+}
+
+func TestNewZap(t *testing.T) {
+	var out bytes.Buffer
+	l, err := NewZap(&out, JSONFormat, "info")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	l.Debugf("hidden")
+	l.WithFields(Fields{RequestIdField: "abc"}).Infof("schedule %s created", "1")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a single JSON line, got %s", out.String())
+	}
+	if line["msg"] != "schedule 1 created" || line["level"] != "info" || line[RequestIdField] != "abc" {
+		t.Errorf("Unexpected line %v", line)
+	}
+
+	if _, err := NewZap(&out, "xml", "info"); err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+	if _, err := NewZap(&out, JSONFormat, "loud"); err == nil {
+		t.Errorf("Expected an error for an unsupported level")
+	}
+}
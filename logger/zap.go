@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Supported log formats
+const (
+	JSONFormat = "json"
+	TextFormat = "text"
+)
+
+// zapLogger adapts a sugared zap logger to the Logger interface
+type zapLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// NewZap creates a logger writing to out in the given format at the given level,
+// e.g. debug, info, warning or error
+func NewZap(out io.Writer, format, level string) (Logger, error) {
+	var lvl zapcore.Level
+	if level == "warning" {
+		level = "warn"
+	}
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, err
+	}
+
+	config := zap.NewProductionEncoderConfig()
+	config.TimeKey = "time"
+	config.EncodeTime = zapcore.RFC3339TimeEncoder
+
+	var encoder zapcore.Encoder
+	switch format {
+	case JSONFormat:
+		encoder = zapcore.NewJSONEncoder(config)
+	case TextFormat:
+		config.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(config)
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, expected %s or %s", format, JSONFormat, TextFormat)
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.AddSync(out), lvl)
+	return &zapLogger{sugar: zap.New(core).Sugar()}, nil
+}
+
+func newDefaultZap() Logger {
+	l, _ := NewZap(os.Stderr, JSONFormat, zapcore.InfoLevel.String())
+	return l
+}
+
+// WithFields adds the fields sorted by key, so the lines carry them in the same order
+func (l *zapLogger) WithFields(fields Fields) Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		args = append(args, key, fields[key])
+	}
+	return &zapLogger{sugar: l.sugar.With(args...)}
+}
+
+func (l *zapLogger) Debugf(format string, args ...interface{}) {
+	l.sugar.Debugf(format, args...)
+}
+
+func (l *zapLogger) Infof(format string, args ...interface{}) {
+	l.sugar.Infof(format, args...)
+}
+
+func (l *zapLogger) Warningf(format string, args ...interface{}) {
+	l.sugar.Warnf(format, args...)
+}
+
+func (l *zapLogger) Errorf(format string, args ...interface{}) {
+	l.sugar.Errorf(format, args...)
+}
+
+func (l *zapLogger) Fatalf(format string, args ...interface{}) {
+	l.sugar.Fatalf(format, args...)
+}
+
+// Flush syncs the lines buffered by the writer
+func (l *zapLogger) Flush() {
+	_ = l.sugar.Sync()
+}
@@ -237,6 +237,25 @@ func (mr *MockQueryInterfaceMockRecorder) Scan(arg0 ...interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockQueryInterface)(nil).Scan), arg0...)
 }
 
+// ScanCAS mocks base method.
+func (m *MockQueryInterface) ScanCAS(arg0 ...interface{}) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScanCAS", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanCAS indicates an expected call of ScanCAS.
+func (mr *MockQueryInterfaceMockRecorder) ScanCAS(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanCAS", reflect.TypeOf((*MockQueryInterface)(nil).ScanCAS), arg0...)
+}
+
 // MockIterInterface is a mock of IterInterface interface.
 type MockIterInterface struct {
 	ctrl     *gomock.Controller
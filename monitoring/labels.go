@@ -0,0 +1,125 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// AppIdLabel is the label carrying the app of a metric, whose values are bounded by the label config
+const AppIdLabel = "appId"
+
+// defaultAggregateLabel is the value the apps over the allowlist or the cap are aggregated under
+const defaultAggregateLabel = "other"
+
+// LabelFilter bounds the cardinality of the metrics of a monitor: the labels configured for a metric are dropped and
+// the apps outside the allowlist, or over the cap of distinct apps, are aggregated under a single appId value.
+type LabelFilter struct {
+	Monitor
+	config    conf.MetricLabelsConfig
+	allowed   map[string]bool
+	aggregate string
+	mu        sync.Mutex
+	seen      map[string]bool
+}
+
+// NewLabelFilter wraps the monitor with the cardinality controls of the config
+func NewLabelFilter(monitor Monitor, config conf.MetricLabelsConfig) *LabelFilter {
+	allowed := make(map[string]bool)
+	for _, appId := range config.AppIds {
+		allowed[appId] = true
+	}
+
+	aggregate := config.AggregateLabel
+	if aggregate == "" {
+		aggregate = defaultAggregateLabel
+	}
+
+	return &LabelFilter{
+		Monitor:   monitor,
+		config:    config,
+		allowed:   allowed,
+		aggregate: aggregate,
+		seen:      make(map[string]bool),
+	}
+}
+
+func (f *LabelFilter) IncCounter(name string, labels map[string]string, value int) {
+	f.Monitor.IncCounter(name, f.filter(name, labels), value)
+}
+
+func (f *LabelFilter) RecordTiming(name string, labels map[string]string, duration time.Duration) {
+	f.Monitor.RecordTiming(name, f.filter(name, labels), duration)
+}
+
+func (f *LabelFilter) RecordTimingWithExemplar(name string, labels map[string]string, duration time.Duration, exemplar map[string]string) {
+	RecordTimingWithExemplar(f.Monitor, name, f.filter(name, labels), duration, exemplar)
+}
+
+func (f *LabelFilter) SetGauge(name string, labels map[string]string, value float64) {
+	f.Monitor.SetGauge(name, f.filter(name, labels), value)
+}
+
+// filter returns a copy of the labels of the metric without its dropped labels and with its app bounded.
+// The same labels are dropped on every call for a metric, so that its label names stay the same.
+func (f *LabelFilter) filter(name string, labels map[string]string) map[string]string {
+	filtered := make(map[string]string, len(labels))
+	for label, value := range labels {
+		filtered[label] = value
+	}
+	for _, label := range f.config.DropLabels[name] {
+		delete(filtered, label)
+	}
+	if appId, ok := filtered[AppIdLabel]; ok {
+		filtered[AppIdLabel] = f.appLabel(appId)
+	}
+	return filtered
+}
+
+// appLabel returns the value of the appId label of the app: its id if it is allowed, the aggregate label otherwise.
+// Without an allowlist the first MaxApps distinct apps seen by the node are allowed, all of them if there is no cap.
+func (f *LabelFilter) appLabel(appId string) string {
+	if appId == "" {
+		return appId
+	}
+	if len(f.allowed) != 0 {
+		if f.allowed[appId] {
+			return appId
+		}
+		return f.aggregate
+	}
+	if f.config.MaxApps <= 0 {
+		return appId
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.seen[appId] {
+		if len(f.seen) >= f.config.MaxApps {
+			return f.aggregate
+		}
+		f.seen[appId] = true
+	}
+	return appId
+}
@@ -0,0 +1,108 @@
+package monitoring
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// recordingMonitor records the labels of the last metric of each name
+type recordingMonitor struct {
+	labels map[string]map[string]string
+}
+
+func (m *recordingMonitor) IncCounter(name string, labels map[string]string, value int) {
+	m.labels[name] = labels
+}
+
+func (m *recordingMonitor) RecordTiming(name string, labels map[string]string, duration time.Duration) {
+	m.labels[name] = labels
+}
+
+func (m *recordingMonitor) SetGauge(name string, labels map[string]string, value float64) {
+	m.labels[name] = labels
+}
+
+func TestLabelFilter(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config conf.MetricLabelsConfig
+		apps   []string
+		want   []string
+	}{
+		{"NoControls", conf.MetricLabelsConfig{}, []string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{"Allowlist", conf.MetricLabelsConfig{AppIds: []string{"b"}}, []string{"a", "b", "c"}, []string{"other", "b", "other"}},
+		{"Cap", conf.MetricLabelsConfig{MaxApps: 2}, []string{"a", "b", "c", "a"}, []string{"a", "b", "rest", "a"}},
+		{"NoApp", conf.MetricLabelsConfig{AppIds: []string{"b"}}, []string{""}, []string{""}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.name == "Cap" {
+				test.config.AggregateLabel = "rest"
+			}
+			recorder := &recordingMonitor{labels: map[string]map[string]string{}}
+			filter := NewLabelFilter(recorder, test.config)
+
+			for i, app := range test.apps {
+				filter.IncCounter("count", map[string]string{AppIdLabel: app, "status": "Success"}, 1)
+				if got := recorder.labels["count"][AppIdLabel]; got != test.want[i] {
+					t.Errorf("appId label of %q = %q, want %q", app, got, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLabelFilterDropLabels(t *testing.T) {
+	recorder := &recordingMonitor{labels: map[string]map[string]string{}}
+	filter := NewLabelFilter(recorder, conf.MetricLabelsConfig{DropLabels: map[string][]string{"duration": {"partitionId"}}})
+	labels := map[string]string{AppIdLabel: "a", "partitionId": "7"}
+
+	filter.RecordTiming("duration", labels, time.Second)
+	filter.SetGauge("backlog", labels, 1)
+
+	if want := map[string]string{AppIdLabel: "a"}; !reflect.DeepEqual(recorder.labels["duration"], want) {
+		t.Errorf("labels of duration = %v, want %v", recorder.labels["duration"], want)
+	}
+	if !reflect.DeepEqual(recorder.labels["backlog"], labels) {
+		t.Errorf("labels of backlog = %v, want %v", recorder.labels["backlog"], labels)
+	}
+	if labels["partitionId"] != "7" {
+		t.Errorf("the labels of the caller should not be changed")
+	}
+}
+
+// exemplarMonitor records the exemplar of the last timing of each name
+type exemplarMonitor struct {
+	recordingMonitor
+	exemplars map[string]map[string]string
+}
+
+func (m *exemplarMonitor) RecordTimingWithExemplar(name string, labels map[string]string, duration time.Duration, exemplar map[string]string) {
+	m.labels[name] = labels
+	m.exemplars[name] = exemplar
+}
+
+func TestLabelFilterExemplars(t *testing.T) {
+	config := conf.MetricLabelsConfig{AppIds: []string{"b"}}
+	exemplar := TraceExemplar("4bf92f3577b34da6a3ce929d0e0e4736")
+
+	recorder := &exemplarMonitor{recordingMonitor{labels: map[string]map[string]string{}}, map[string]map[string]string{}}
+	RecordTimingWithExemplar(NewLabelFilter(recorder, config), "duration", map[string]string{AppIdLabel: "a"}, time.Second, exemplar)
+	if got := recorder.labels["duration"][AppIdLabel]; got != "other" {
+		t.Errorf("appId label = %q, want other", got)
+	}
+	if !reflect.DeepEqual(recorder.exemplars["duration"], exemplar) {
+		t.Errorf("exemplar = %v, want %v", recorder.exemplars["duration"], exemplar)
+	}
+
+	plain := &recordingMonitor{labels: map[string]map[string]string{}}
+	RecordTimingWithExemplar(NewLabelFilter(plain, config), "duration", map[string]string{AppIdLabel: "b"}, time.Second, exemplar)
+	if got := plain.labels["duration"][AppIdLabel]; got != "b" {
+		t.Errorf("timing of a monitor without exemplars was not recorded, appId label = %q", got)
+	}
+	if TraceExemplar("") != nil {
+		t.Errorf("exemplar of a schedule without a trace id should be empty")
+	}
+}
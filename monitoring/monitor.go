@@ -5,4 +5,28 @@ import "time"
 type Monitor interface {
 	IncCounter(name string, labels map[string]string, value int)
 	RecordTiming(name string, labels map[string]string, duration time.Duration)
+	SetGauge(name string, labels map[string]string, value float64)
+}
+
+// ExemplarMonitor is a monitor recording the timings along with an exemplar, such as the trace id of the timed
+// operation, which links the metric to the logs of the operation without being a label of the metric
+type ExemplarMonitor interface {
+	RecordTimingWithExemplar(name string, labels map[string]string, duration time.Duration, exemplar map[string]string)
+}
+
+// TraceExemplar returns the exemplar of an operation of the trace, none without a trace id
+func TraceExemplar(traceId string) map[string]string {
+	if traceId == "" {
+		return nil
+	}
+	return map[string]string{"traceId": traceId}
+}
+
+// RecordTimingWithExemplar records the timing with the exemplar if the monitor supports exemplars, without it otherwise
+func RecordTimingWithExemplar(monitor Monitor, name string, labels map[string]string, duration time.Duration, exemplar map[string]string) {
+	if m, ok := monitor.(ExemplarMonitor); ok && len(exemplar) > 0 {
+		m.RecordTimingWithExemplar(name, labels, duration, exemplar)
+		return
+	}
+	monitor.RecordTiming(name, labels, duration)
 }
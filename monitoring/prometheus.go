@@ -10,6 +10,7 @@ import (
 type PrometheusMonitor struct {
 	Counters   map[string]*prometheus.CounterVec
 	Histograms map[string]*prometheus.HistogramVec
+	Gauges     map[string]*prometheus.GaugeVec
 	Mu         sync.RWMutex
 }
 
@@ -17,6 +18,7 @@ func NewPrometheusMonitor() *PrometheusMonitor {
 	return &PrometheusMonitor{
 		Counters:   make(map[string]*prometheus.CounterVec),
 		Histograms: make(map[string]*prometheus.HistogramVec),
+		Gauges:     make(map[string]*prometheus.GaugeVec),
 	}
 }
 
@@ -41,6 +43,21 @@ func (p *PrometheusMonitor) IncCounter(name string, labels map[string]string, va
 }
 
 func (p *PrometheusMonitor) RecordTiming(name string, labels map[string]string, duration time.Duration) {
+	p.histogram(name, labels).With(labels).Observe(duration.Seconds())
+}
+
+// RecordTimingWithExemplar records the timing with the exemplar, exposed to the scrapers asking for OpenMetrics
+func (p *PrometheusMonitor) RecordTimingWithExemplar(name string, labels map[string]string, duration time.Duration, exemplar map[string]string) {
+	observer := p.histogram(name, labels).With(labels)
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+		exemplarObserver.ObserveWithExemplar(duration.Seconds(), exemplar)
+		return
+	}
+	observer.Observe(duration.Seconds())
+}
+
+// histogram returns the histogram of the timing, created with the label names of its first record
+func (p *PrometheusMonitor) histogram(name string, labels map[string]string) *prometheus.HistogramVec {
 	p.Mu.RLock()
 	histogram, ok := p.Histograms[name]
 	p.Mu.RUnlock()
@@ -57,7 +74,27 @@ func (p *PrometheusMonitor) RecordTiming(name string, labels map[string]string,
 		p.Mu.Unlock()
 	}
 
-	histogram.With(labels).Observe(duration.Seconds())
+	return histogram
+}
+
+func (p *PrometheusMonitor) SetGauge(name string, labels map[string]string, value float64) {
+	p.Mu.RLock()
+	gauge, ok := p.Gauges[name]
+	p.Mu.RUnlock()
+
+	if !ok {
+		p.Mu.Lock()
+		if gauge, ok = p.Gauges[name]; !ok {
+			gauge = promauto.NewGaugeVec(
+				prometheus.GaugeOpts{Name: name},
+				getLabelNames(labels),
+			)
+			p.Gauges[name] = gauge
+		}
+		p.Mu.Unlock()
+	}
+
+	gauge.With(labels).Set(value)
 }
 
 func getLabelNames(labels map[string]string) []string {
@@ -21,8 +21,8 @@ package monitoring
 
 import (
 	gstatsd "github.com/cactus/go-statsd-client/statsd"
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/uber-common/bark"
 	statsd "gopkg.in/alexcesaro/statsd.v2"
 )
@@ -34,7 +34,7 @@ func initStatsd(conf *conf.StatsdConfig) (*statsd.Client, error) {
 			statsd.Prefix(conf.Prefix),
 		)
 		if err != nil {
-			glog.Fatalf("Error while creating statsd client %+v", err)
+			logger.Fatalf("Error while creating statsd client %+v", err)
 			return statsdClient, err
 		}
 		return statsdClient, nil
@@ -47,7 +47,7 @@ func initStatsd(conf *conf.StatsdConfig) (*statsd.Client, error) {
 func GetRingPopStatsD(conf *conf.StatsdConfig) bark.StatsReporter {
 	RingPopStatsDClient, err := gstatsd.New(conf.Address, conf.Prefix)
 	if err != nil {
-		glog.Errorf("Error while creating statsd client %+v", err)
+		logger.Errorf("Error while creating statsd client %+v", err)
 		return nil
 	}
 	return bark.NewStatsReporterFromCactus(RingPopStatsDClient)
@@ -0,0 +1,164 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package poller
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
+	r "github.com/myntra/goscheduler/retrieveriface"
+)
+
+// Modes of an adaptively polled partition, recorded with the interval chosen by every poll
+const (
+	busyMode   = "busy"
+	normalMode = "normal"
+	idleMode   = "idle"
+)
+
+// adaptiveState tracks the progress of an adaptively polled partition
+type adaptiveState struct {
+	polledUpTo time.Time // schedules due before this time have been fired
+	lookahead  int       // number of upcoming minutes counted by the next poll
+}
+
+// startAdaptive polls the partition until the poller is stopped, each poll deciding when the next one happens
+func (p *Poller) startAdaptive(retriever r.LoadAwareRetriever, stop <-chan struct{}) {
+	state := &adaptiveState{}
+	timer := clock.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case currentTime := <-timer.C():
+			faults.Default().WaitWhilePaused()
+			p.recordPollerLifeCycle(constants.Running)
+			interval, mode := p.pollAdaptive(retriever, state, currentTime)
+			p.recordPollInterval(mode, interval)
+			timer.Reset(interval)
+		}
+	}
+}
+
+// pollAdaptive fires the schedules which became due since the previous poll and returns the interval until the
+// next poll along with the mode of the partition.
+//
+// A single count query covers the minute buckets elapsed since the previous poll and the upcoming ones; elapsed
+// buckets without schedules are not read. A partition with BusyThreshold schedules due in the current or the next
+// minute is polled every MinIntervalSeconds, a partition with nothing due over the counted minutes is left alone
+// until they have passed and the next poll counts twice as many minutes ahead, up to MaxLookaheadMinutes. Any other
+// partition is polled right after every minute.
+func (p *Poller) pollAdaptive(retriever r.LoadAwareRetriever, state *adaptiveState, now time.Time) (time.Duration, string) {
+	current := now.Truncate(time.Minute)
+	upTo := now.Truncate(time.Second).Add(time.Second)
+	if state.polledUpTo.IsZero() {
+		state.polledUpTo = current
+	}
+	if state.lookahead < 1 {
+		state.lookahead = 1
+	}
+
+	first := state.polledUpTo.Truncate(time.Minute)
+	if first.After(current) {
+		first = current
+	}
+
+	var buckets []time.Time
+	last := current.Add(time.Duration(state.lookahead) * time.Minute)
+	for bucket := first; !bucket.After(last); bucket = bucket.Add(time.Minute) {
+		buckets = append(buckets, bucket)
+	}
+	elapsed := len(buckets) - state.lookahead
+
+	counts, err := retriever.CountSchedules(p.AppName, p.PartitionId, buckets)
+	if err != nil {
+		logger.Errorf("Counting upcoming schedules failed for %s.%d with error %s", p.AppName, p.PartitionId, err.Error())
+		counts = nil
+	}
+
+	for i := 0; i < elapsed; i++ {
+		if counts != nil && counts[i] == 0 {
+			continue
+		}
+		if err = retriever.GetSchedulesInWindow(p.AppName, p.PartitionId, buckets[i], state.polledUpTo, upTo); err != nil {
+			logger.Errorf("Polling %s.%d failed for bucket %v with error %s", p.AppName, p.PartitionId, buckets[i], err.Error())
+		}
+	}
+	state.polledUpTo = upTo
+
+	nextMinute := current.Add(time.Minute)
+	if counts == nil {
+		state.lookahead = 1
+		return p.normalInterval(now, nextMinute), normalMode
+	}
+
+	due := counts[elapsed-1:]
+	busy, idle := false, true
+	for i, count := range due {
+		if i < 2 && count >= p.config.BusyThreshold && p.config.BusyThreshold > 0 {
+			busy = true
+		}
+		if count > 0 {
+			idle = false
+		}
+	}
+
+	switch {
+	case busy:
+		state.lookahead = 1
+		return intervalOrDefault(p.config.MinIntervalSeconds, p.config.Interval), busyMode
+	case idle:
+		wakeUp := nextMinute.Add(time.Duration(state.lookahead) * time.Minute)
+		state.lookahead *= 2
+		if state.lookahead > p.config.MaxLookaheadMinutes {
+			state.lookahead = p.config.MaxLookaheadMinutes
+		}
+		return wakeUp.Sub(now), idleMode
+	default:
+		state.lookahead = 1
+		return p.normalInterval(now, nextMinute), normalMode
+	}
+}
+
+// normalInterval polls right after the current minute, or earlier if the configured interval is shorter
+func (p *Poller) normalInterval(now time.Time, nextMinute time.Time) time.Duration {
+	interval := nextMinute.Sub(now)
+	if configured := intervalOrDefault(p.config.Interval, 60); configured < interval {
+		return configured
+	}
+	return interval
+}
+
+func intervalOrDefault(seconds int, fallback int) time.Duration {
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (p *Poller) recordPollInterval(mode string, interval time.Duration) {
+	if p.monitor != nil {
+		p.monitor.RecordTiming(constants.PollerInterval, map[string]string{"appId": p.AppName, "mode": mode}, interval)
+	}
+}
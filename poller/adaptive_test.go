@@ -0,0 +1,148 @@
+package poller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+type window struct {
+	bucket, from, to time.Time
+}
+
+type fakeRetriever struct {
+	counts  map[time.Time]int
+	windows []window
+}
+
+func (f *fakeRetriever) GetSchedules(appName string, partitionID int, timeBucket time.Time) error {
+	return nil
+}
+
+func (f *fakeRetriever) BulkAction(app store.App, partitionId int, timeBucket time.Time, status []store.Status, actionType store.ActionType) error {
+	return nil
+}
+
+func (f *fakeRetriever) CountSchedules(appName string, partitionID int, timeBuckets []time.Time) ([]int, error) {
+	counts := make([]int, len(timeBuckets))
+	for i, bucket := range timeBuckets {
+		counts[i] = f.counts[bucket]
+	}
+	return counts, nil
+}
+
+func (f *fakeRetriever) GetSchedulesInWindow(appName string, partitionID int, timeBucket time.Time, from time.Time, to time.Time) error {
+	f.windows = append(f.windows, window{timeBucket, from, to})
+	return nil
+}
+
+func newAdaptivePoller() *Poller {
+	return &Poller{
+		AppName:     "test",
+		PartitionId: 0,
+		config: conf.PollerConfig{
+			Interval:            60,
+			Adaptive:            true,
+			MinIntervalSeconds:  10,
+			BusyThreshold:       100,
+			MaxLookaheadMinutes: 4,
+		},
+	}
+}
+
+func TestPollAdaptive_Modes(t *testing.T) {
+	minute := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := minute.Add(20 * time.Second)
+
+	for _, test := range []struct {
+		name     string
+		counts   map[time.Time]int
+		interval time.Duration
+		mode     string
+		windows  int
+	}{
+		{"busy next minute", map[time.Time]int{minute: 5, minute.Add(time.Minute): 150}, 10 * time.Second, busyMode, 1},
+		{"normal", map[time.Time]int{minute: 5}, 40 * time.Second, normalMode, 1},
+		{"idle", map[time.Time]int{}, 100 * time.Second, idleMode, 0},
+	} {
+		retriever := &fakeRetriever{counts: test.counts}
+		state := &adaptiveState{}
+
+		interval, mode := newAdaptivePoller().pollAdaptive(retriever, state, now)
+		if interval != test.interval || mode != test.mode {
+			t.Errorf("%s: expected %v in %s mode, got %v in %s mode", test.name, test.interval, test.mode, interval, mode)
+		}
+		if len(retriever.windows) != test.windows {
+			t.Errorf("%s: expected %d reads, got %d", test.name, test.windows, len(retriever.windows))
+		}
+		if !state.polledUpTo.Equal(now.Add(time.Second)) {
+			t.Errorf("%s: expected schedules to be fired up to %v, got %v", test.name, now.Add(time.Second), state.polledUpTo)
+		}
+	}
+}
+
+func TestPollAdaptive_IdleBackoff(t *testing.T) {
+	minute := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	retriever := &fakeRetriever{counts: map[time.Time]int{}}
+	state := &adaptiveState{}
+	poller := newAdaptivePoller()
+
+	now := minute
+	for _, expected := range []time.Duration{2 * time.Minute, 3 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		interval, mode := poller.pollAdaptive(retriever, state, now)
+		if interval != expected || mode != idleMode {
+			t.Fatalf("expected idle interval of %v, got %v in %s mode", expected, interval, mode)
+		}
+		now = now.Add(interval)
+	}
+
+	// a schedule created for an idle minute after it was counted is fired by the poll following that minute
+	retriever.counts[now.Add(-time.Minute)] = 1
+	poller.pollAdaptive(retriever, state, now)
+	if len(retriever.windows) != 1 || !retriever.windows[0].bucket.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected the late schedule to be fired, got %+v", retriever.windows)
+	}
+}
+
+func TestPollAdaptive_WindowsDoNotOverlap(t *testing.T) {
+	minute := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	retriever := &fakeRetriever{counts: map[time.Time]int{minute: 500, minute.Add(time.Minute): 500}}
+	state := &adaptiveState{}
+	poller := newAdaptivePoller()
+
+	now := minute.Add(45 * time.Second)
+	for i := 0; i < 3; i++ {
+		interval, _ := poller.pollAdaptive(retriever, state, now)
+		now = now.Add(interval)
+	}
+
+	expected := []window{
+		{minute, minute, minute.Add(46 * time.Second)},
+		{minute, minute.Add(46 * time.Second), minute.Add(56 * time.Second)},
+		{minute, minute.Add(56 * time.Second), minute.Add(66 * time.Second)},
+		{minute.Add(time.Minute), minute.Add(56 * time.Second), minute.Add(66 * time.Second)},
+	}
+	if len(retriever.windows) != len(expected) {
+		t.Fatalf("expected %d reads, got %+v", len(expected), retriever.windows)
+	}
+	for i, w := range expected {
+		got := retriever.windows[i]
+		if !got.bucket.Equal(w.bucket) || !got.from.Equal(w.from) || !got.to.Equal(w.to) {
+			t.Errorf("read %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	if err := ValidateConfig(conf.PollerConfig{Adaptive: true}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := ValidateConfig(conf.PollerConfig{SecondPrecision: true}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := ValidateConfig(conf.PollerConfig{Adaptive: true, SecondPrecision: true}); err == nil {
+		t.Errorf("Expected adaptive polling with second precision to be rejected")
+	}
+}
@@ -20,9 +20,13 @@
 package poller
 
 import (
-	"github.com/golang/glog"
+	"errors"
+
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
 	r "github.com/myntra/goscheduler/retrieveriface"
 	"strconv"
@@ -33,11 +37,31 @@ type Poller struct {
 	AppName               string
 	PartitionId           int
 	scheduleRetrievalImpl r.Retriever
-	ticker                *time.Ticker
+	ticker                clock.Ticker
+	stop                  chan struct{}
 	config                conf.PollerConfig
 	monitor               p.Monitor
 }
 
+// ValidateConfig checks that the polling modes configured can be used together.
+// An adaptive poll only fires the schedules which are already due, so it cannot hold them until their second nor
+// read them ahead of their minute.
+func ValidateConfig(config conf.PollerConfig) error {
+	switch {
+	case config.Adaptive && config.SecondPrecision:
+		return errors.New("adaptive polling and second precision cannot be enabled together")
+	case config.Adaptive && config.PrefetchSeconds > 0:
+		return errors.New("adaptive polling and prefetch cannot be enabled together")
+	case config.PrefetchSeconds < 0 || config.PrefetchSeconds >= 60:
+		return errors.New("prefetch seconds should be between 0 and 59")
+	case config.IntentLog && config.IntentGraceSeconds < 60:
+		return errors.New("the intent grace seconds should be at least 60")
+	case config.IntentLog && config.IntentMaxAttempts < 1:
+		return errors.New("the intent max attempts should be at least 1")
+	}
+	return nil
+}
+
 func (p *Poller) recordPollerLifeCycle(lifeCycleMethod string) {
 	if p.monitor != nil {
 		p.monitor.IncCounter(constants.PollerLifeCycle, map[string]string{"lifeCycleMethod": lifeCycleMethod, "appId": p.AppName, "partitionId": strconv.Itoa(p.PartitionId)}, 1)
@@ -48,14 +72,35 @@ func (p *Poller) Init() error {
 	if p.ticker != nil {
 		p.ticker.Stop()
 	}
-	p.ticker = time.NewTicker(time.Duration(p.config.Interval) * time.Second)
+	p.ticker = clock.NewTicker(time.Duration(p.config.Interval) * time.Second)
+	p.stop = make(chan struct{})
 
 	return nil
 }
 
 func (p *Poller) Start() {
 	p.recordPollerLifeCycle(constants.Start)
-	for currentTime := range p.ticker.C {
+	if retriever, ok := p.scheduleRetrievalImpl.(r.RecoveringRetriever); ok && p.config.IntentLog {
+		go p.startRecovery(retriever, p.stop)
+	}
+	if retriever, ok := p.scheduleRetrievalImpl.(r.LoadAwareRetriever); ok && p.config.Adaptive {
+		p.ticker.Stop()
+		p.startAdaptive(retriever, p.stop)
+		return
+	}
+	if retriever, ok := p.scheduleRetrievalImpl.(r.PrefetchingRetriever); ok && p.config.PrefetchSeconds > 0 {
+		p.ticker.Stop()
+		p.startPrefetch(retriever, p.stop)
+		return
+	}
+	if _, ok := p.scheduleRetrievalImpl.(r.LoadAwareRetriever); ok && p.config.SecondPrecision {
+		p.ticker.Stop()
+		p.startPrecise(p.stop)
+		return
+	}
+
+	for currentTime := range p.ticker.C() {
+		faults.Default().WaitWhilePaused()
 		p.recordPollerLifeCycle(constants.Running)
 		timeBucket := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), currentTime.Hour(), currentTime.Minute(), 0, 0, currentTime.Location())
 		go p.scheduleRetrievalImpl.GetSchedules(p.AppName, p.PartitionId, timeBucket)
@@ -64,6 +109,10 @@ func (p *Poller) Start() {
 
 func (p *Poller) Stop() {
 	p.recordPollerLifeCycle(constants.Stop)
-	glog.Infof("Stopping poller for %s.%d", p.AppName, p.PartitionId)
+	logger.Infof("Stopping poller for %s.%d", p.AppName, p.PartitionId)
 	p.ticker.Stop()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
 }
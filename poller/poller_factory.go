@@ -22,10 +22,10 @@ package poller
 import (
 	"errors"
 	"fmt"
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/cluster_entity"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
 	riface "github.com/myntra/goscheduler/retrieveriface"
 	r "github.com/myntra/goscheduler/retrievers"
@@ -40,13 +40,13 @@ type PollerFactory struct {
 }
 
 func (p PollerFactory) CreateEntity(pollerId string) cluster_entity.Entity {
-	glog.Infof(" *** Starting poller for entity  ***  %s ", pollerId)
+	logger.Infof(" *** Starting poller for entity  ***  %s ", pollerId)
 	seq := strings.Split(pollerId, constants.PollerKeySep)
 	appName := seq[0]
 	partitionId := seq[1]
 
 	scheduleRetrievalImpl := p.GetEntityRetriever(appName)
-	glog.Infof("Got schedule retriever %v for app %s", scheduleRetrievalImpl, appName)
+	logger.Infof("Got schedule retriever %v for app %s", scheduleRetrievalImpl, appName)
 
 	id, err := strconv.Atoi(partitionId)
 	if err != nil {
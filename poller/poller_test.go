@@ -0,0 +1,143 @@
+package poller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+type bucketRetriever struct {
+	buckets chan time.Time
+}
+
+func (b bucketRetriever) GetSchedules(appName string, partitionID int, timeBucket time.Time) error {
+	b.buckets <- timeBucket
+	return nil
+}
+
+func (b bucketRetriever) BulkAction(app store.App, partitionId int, timeBucket time.Time, status []store.Status, actionType store.ActionType) error {
+	return nil
+}
+
+func TestPoller_PollsEveryBucketOfVirtualTime(t *testing.T) {
+	defer clock.SetDefault(clock.Default())
+	virtual := clock.NewVirtual(time.Date(2024, time.February, 28, 23, 59, 30, 0, time.UTC))
+	clock.SetDefault(virtual)
+
+	retriever := bucketRetriever{buckets: make(chan time.Time, 10)}
+	p := &Poller{AppName: "test", scheduleRetrievalImpl: retriever, config: conf.PollerConfig{Interval: 60}}
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+	go p.Start()
+	defer p.Stop()
+
+	if err := virtual.Advance(3 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	polled := map[time.Time]bool{}
+	for i := 0; i < 3; i++ {
+		select {
+		case bucket := <-retriever.buckets:
+			polled[bucket] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected a poll for every minute, got %d", len(polled))
+		}
+	}
+	for minute := 0; minute < 3; minute++ {
+		if bucket := time.Date(2024, time.February, 29, 0, minute, 0, 0, time.UTC); !polled[bucket] {
+			t.Errorf("expected the bucket %v polled, got %v", bucket, polled)
+		}
+	}
+}
+
+type prefetchRetriever struct {
+	bucketRetriever
+}
+
+func (p prefetchRetriever) PrefetchSchedules(appName string, partitionID int, timeBucket time.Time) error {
+	return p.GetSchedules(appName, partitionID, timeBucket)
+}
+
+func TestPoller_PrefetchesEveryBucketOfVirtualTime(t *testing.T) {
+	defer clock.SetDefault(clock.Default())
+	virtual := clock.NewVirtual(time.Date(2024, time.February, 28, 23, 59, 30, 0, time.UTC))
+	clock.SetDefault(virtual)
+
+	retriever := prefetchRetriever{bucketRetriever{buckets: make(chan time.Time, 10)}}
+	p := &Poller{AppName: "test", scheduleRetrievalImpl: retriever, config: conf.PollerConfig{Interval: 60, PrefetchSeconds: 10}}
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+	go p.Start()
+	defer p.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	// every bucket is read by the time it begins
+	for minute := 0; minute < 3; minute++ {
+		if err := virtual.Advance(30 * time.Second); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case bucket := <-retriever.buckets:
+			if expected := time.Date(2024, time.February, 29, 0, minute, 0, 0, time.UTC); !bucket.Equal(expected) {
+				t.Errorf("expected the bucket %v prefetched, got %v", expected, bucket)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected a prefetch for every minute, got %d", minute)
+		}
+		time.Sleep(50 * time.Millisecond)
+		if err := virtual.Advance(30 * time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPoller_PrefetchLead(t *testing.T) {
+	for partition := 0; partition < 50; partition++ {
+		p := &Poller{AppName: "test", PartitionId: partition, config: conf.PollerConfig{PrefetchSeconds: 10}}
+		lead := p.prefetchLead()
+		if lead < time.Second || lead > 10*time.Second {
+			t.Errorf("expected a lead between 1 and 10 seconds, got %v", lead)
+		}
+		if p.prefetchLead() != lead {
+			t.Errorf("expected the lead of a partition to stay the same")
+		}
+	}
+}
+
+func TestValidateConfig_Prefetch(t *testing.T) {
+	for _, test := range []struct {
+		config conf.PollerConfig
+		valid  bool
+	}{
+		{conf.PollerConfig{PrefetchSeconds: 10, SecondPrecision: true}, true},
+		{conf.PollerConfig{Adaptive: true, PrefetchSeconds: 10}, false},
+		{conf.PollerConfig{PrefetchSeconds: -1}, false},
+		{conf.PollerConfig{PrefetchSeconds: 60}, false},
+	} {
+		if err := ValidateConfig(test.config); (err == nil) != test.valid {
+			t.Errorf("unexpected validation %v of %+v", err, test.config)
+		}
+	}
+}
+
+func TestValidateConfig_IntentLog(t *testing.T) {
+	for _, test := range []struct {
+		config conf.PollerConfig
+		valid  bool
+	}{
+		{conf.PollerConfig{IntentLog: true, IntentGraceSeconds: 600, IntentMaxAttempts: 3}, true},
+		{conf.PollerConfig{IntentLog: true, IntentGraceSeconds: 30, IntentMaxAttempts: 3}, false},
+		{conf.PollerConfig{IntentLog: true, IntentGraceSeconds: 600}, false},
+		{conf.PollerConfig{IntentGraceSeconds: 30}, true},
+	} {
+		if err := ValidateConfig(test.config); (err == nil) != test.valid {
+			t.Errorf("unexpected validation %v of %+v", err, test.config)
+		}
+	}
+}
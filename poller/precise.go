@@ -0,0 +1,50 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package poller
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/faults"
+)
+
+// startPrecise polls every minute bucket of the partition as the minute begins, until the poller is stopped.
+// The retriever holds the schedules of the bucket until the second they are due, so the poll of a bucket lasts for
+// the minute. Buckets which began while the node was paused are polled right away rather than skipped.
+func (p *Poller) startPrecise(stop <-chan struct{}) {
+	next := clock.Now().Truncate(time.Minute).Add(time.Minute)
+	timer := clock.NewTimer(clock.Until(next))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C():
+			faults.Default().WaitWhilePaused()
+			p.recordPollerLifeCycle(constants.Running)
+			go p.scheduleRetrievalImpl.GetSchedules(p.AppName, p.PartitionId, next)
+			next = next.Add(time.Minute)
+			timer.Reset(clock.Until(next))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package poller
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/faults"
+	r "github.com/myntra/goscheduler/retrieveriface"
+)
+
+// startPrefetch reads every minute bucket of the partition ahead of the minute, until the poller is stopped.
+// The retriever holds the schedules read until they are due, so nothing fires earlier than without the prefetch,
+// while the reads of the partitions are spread over the seconds before the minute instead of all starting with it.
+func (p *Poller) startPrefetch(retriever r.PrefetchingRetriever, stop <-chan struct{}) {
+	lead := p.prefetchLead()
+	next := clock.Now().Truncate(time.Minute).Add(time.Minute)
+	timer := clock.NewTimer(clock.Until(next.Add(-lead)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timer.C():
+			faults.Default().WaitWhilePaused()
+			p.recordPollerLifeCycle(constants.Running)
+			go retriever.PrefetchSchedules(p.AppName, p.PartitionId, next)
+			next = next.Add(time.Minute)
+			timer.Reset(clock.Until(next.Add(-lead)))
+		}
+	}
+}
+
+// prefetchLead returns how long before its minute a bucket of the partition is read, between a second and
+// PrefetchSeconds. The lead is derived from the partition so that it stays the same across its polls.
+func (p *Poller) prefetchLead() time.Duration {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(p.AppName + "." + strconv.Itoa(p.PartitionId)))
+	return time.Duration(1+hash.Sum32()%uint32(p.config.PrefetchSeconds)) * time.Second
+}
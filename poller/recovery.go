@@ -0,0 +1,62 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package poller
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
+	r "github.com/myntra/goscheduler/retrieveriface"
+)
+
+// startRecovery recovers the claims of the intent log of the partition minute by minute, each minute once all its
+// claims are older than the grace, until the poller is stopped. A starting poller first recovers the minutes of the
+// lookback, which hold the claims of a node which crashed while it owned the partition.
+func (p *Poller) startRecovery(retriever r.RecoveringRetriever, stop <-chan struct{}) {
+	grace := time.Duration(p.config.IntentGraceSeconds) * time.Second
+	lookback := time.Duration(p.config.IntentLookbackMinutes) * time.Minute
+	next := clock.Now().Add(-grace).Truncate(time.Minute).Add(-lookback)
+
+	ticker := clock.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		// a minute is recovered once its last claim is older than the grace
+		for last := clock.Now().Add(-grace).Truncate(time.Minute).Add(-time.Minute); !next.After(last); next = next.Add(time.Minute) {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			faults.Default().WaitWhilePaused()
+			if err := retriever.RecoverSchedules(p.AppName, p.PartitionId, next); err != nil {
+				logger.Errorf("Recovering the claims of %s.%d at %v failed with error %s", p.AppName, p.PartitionId, next, err.Error())
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C():
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Applier applies the lifecycle events of the primary cluster to the schedules of the standby.
+// Events are applied through the DAOs rather than the service, so that the standby does not publish them again.
+// Every event is applied as an upsert of the schedule it carries, replaying an event has no further effect.
+type Applier struct {
+	Config      *conf.Configuration
+	ClusterDao  dao.ClusterDao
+	ScheduleDao dao.ScheduleDao
+}
+
+func NewApplier(config *conf.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao) *Applier {
+	return &Applier{
+		Config:      config,
+		ClusterDao:  clusterDao,
+		ScheduleDao: scheduleDao,
+	}
+}
+
+// Apply applies a single event to the standby
+func (a *Applier) Apply(event store.Event) error {
+	switch event.Type {
+	case store.ScheduleDeleted:
+		return a.delete(event.ScheduleId)
+	case store.ScheduleFired, store.ScheduleFailed, store.ScheduleShed:
+		// runs of recurring schedules are created by the cron of the standby itself,
+		// a fired one time schedule is removed so that it is not fired again after a promotion
+		if event.ParentScheduleId != "" {
+			return nil
+		}
+		return a.delete(event.ScheduleId)
+	default:
+		if event.Schedule == nil {
+			return nil
+		}
+		return a.upsert(*event.Schedule)
+	}
+}
+
+func (a *Applier) delete(scheduleId string) error {
+	uuid, err := gocql.ParseUUID(scheduleId)
+	if err != nil {
+		return err
+	}
+
+	if _, err = a.ScheduleDao.DeleteSchedule(uuid); err != nil && err != gocql.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (a *Applier) upsert(schedule store.Schedule) error {
+	app, err := a.ClusterDao.GetApp(schedule.AppId)
+	if err != nil {
+		return fmt.Errorf("app %s of schedule %s is not registered in the standby: %w", schedule.AppId, schedule.ScheduleId, err)
+	}
+	schedule.PayloadEncoding = app.Configuration.PayloadCompression
+
+	if schedule.IsRecurring() {
+		cronApp, err := a.ClusterDao.GetApp(a.Config.CronConfig.App)
+		if err != nil {
+			return err
+		}
+
+		schedule.PartitionId = schedule.PartitionFor(cronApp.Partitions)
+		if schedule.Status == "" {
+			schedule.Status = store.Scheduled
+		}
+		_, err = a.ScheduleDao.UpdateRecurringSchedule(schedule)
+		return err
+	}
+
+	schedule.PartitionId = schedule.PartitionFor(app.Partitions)
+	schedule.ScheduleGroup = 60 * (schedule.ScheduleTime / 60)
+
+	existing, err := a.ScheduleDao.GetSchedule(schedule.ScheduleId)
+	switch {
+	case err == gocql.ErrNotFound:
+		_, err = a.ScheduleDao.CreateSchedule(context.Background(), schedule, app)
+	case err == nil:
+		_, err = a.ScheduleDao.UpdateOneTimeSchedule(existing, schedule, app)
+	}
+	return err
+}
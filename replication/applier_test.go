@@ -0,0 +1,143 @@
+package replication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+type mockScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	schedules map[gocql.UUID]store.Schedule
+	created   []store.Schedule
+	updated   []store.Schedule
+	recurring []store.Schedule
+	deleted   []gocql.UUID
+}
+
+func newMockScheduleDao() *mockScheduleDao {
+	return &mockScheduleDao{schedules: map[gocql.UUID]store.Schedule{}}
+}
+
+func (m *mockScheduleDao) CreateSchedule(_ context.Context, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	m.created = append(m.created, schedule)
+	m.schedules[schedule.ScheduleId] = schedule
+	return schedule, nil
+}
+
+func (m *mockScheduleDao) GetSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	schedule, ok := m.schedules[uuid]
+	if !ok {
+		return store.Schedule{}, gocql.ErrNotFound
+	}
+	return schedule, nil
+}
+
+func (m *mockScheduleDao) UpdateOneTimeSchedule(existing store.Schedule, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	m.updated = append(m.updated, schedule)
+	m.schedules[schedule.ScheduleId] = schedule
+	return schedule, nil
+}
+
+func (m *mockScheduleDao) UpdateRecurringSchedule(schedule store.Schedule) (store.Schedule, error) {
+	m.recurring = append(m.recurring, schedule)
+	return schedule, nil
+}
+
+func (m *mockScheduleDao) GetCronSchedulesByApp(appId string, status store.Status) ([]store.Schedule, []string) {
+	var schedules []store.Schedule
+	for _, schedule := range m.schedules {
+		if schedule.AppId == appId && schedule.IsRecurring() {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *mockScheduleDao) DeleteSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	schedule, ok := m.schedules[uuid]
+	if !ok {
+		return store.Schedule{}, gocql.ErrNotFound
+	}
+	delete(m.schedules, uuid)
+	m.deleted = append(m.deleted, uuid)
+	return schedule, nil
+}
+
+func newTestApplier(scheduleDao *mockScheduleDao) *Applier {
+	config := &conf.Configuration{CronConfig: conf.CronConfig{App: "cron"}}
+	return NewApplier(config, dao.DummyClusterDaoImpl{}, scheduleDao)
+}
+
+func TestApplier_OneTimeSchedule(t *testing.T) {
+	scheduleDao := newMockScheduleDao()
+	applier := newTestApplier(scheduleDao)
+
+	schedule := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "{}", ScheduleTime: 1700000090}
+	if err := applier.Apply(store.NewEvent(store.ScheduleCreated, schedule)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(scheduleDao.created) != 1 || scheduleDao.created[0].ScheduleGroup != 1700000040 {
+		t.Fatalf("Expected the schedule to be created with its id, got %+v", scheduleDao.created)
+	}
+
+	schedule.ScheduleTime = 1700000200
+	if err := applier.Apply(store.NewEvent(store.ScheduleUpdated, schedule)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(scheduleDao.created) != 1 || len(scheduleDao.updated) != 1 {
+		t.Errorf("Expected the existing schedule to be updated, got %d creates and %d updates", len(scheduleDao.created), len(scheduleDao.updated))
+	}
+
+	if err := applier.Apply(store.NewEvent(store.ScheduleFired, schedule)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(scheduleDao.deleted) != 1 {
+		t.Errorf("Expected the fired schedule to be removed from the standby")
+	}
+
+	// replaying the fired event has no further effect
+	if err := applier.Apply(store.NewEvent(store.ScheduleFired, schedule)); err != nil {
+		t.Errorf("Expected a replayed event to be ignored, got error %v", err)
+	}
+}
+
+func TestApplier_RecurringSchedule(t *testing.T) {
+	scheduleDao := newMockScheduleDao()
+	applier := newTestApplier(scheduleDao)
+
+	schedule := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "{}", CronExpression: "* * * * *"}
+	if err := applier.Apply(store.NewEvent(store.ScheduleCreated, schedule)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	schedule.Status = store.Paused
+	if err := applier.Apply(store.NewEvent(store.SchedulePaused, schedule)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if len(scheduleDao.recurring) != 2 || len(scheduleDao.created) != 0 {
+		t.Fatalf("Expected recurring schedules to be upserted, got %+v", scheduleDao.recurring)
+	}
+	if scheduleDao.recurring[0].Status != store.Scheduled || scheduleDao.recurring[1].Status != store.Paused {
+		t.Errorf("Unexpected statuses %s and %s", scheduleDao.recurring[0].Status, scheduleDao.recurring[1].Status)
+	}
+
+	run := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", ParentScheduleId: schedule.ScheduleId}
+	if err := applier.Apply(store.NewEvent(store.ScheduleFired, run)); err != nil || len(scheduleDao.deleted) != 0 {
+		t.Errorf("Expected runs of recurring schedules to be ignored")
+	}
+}
+
+func TestApplier_UnregisteredApp(t *testing.T) {
+	applier := newTestApplier(newMockScheduleDao())
+
+	schedule := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "testGetAppError", Payload: "{}", ScheduleTime: 1700000090}
+	if err := applier.Apply(store.NewEvent(store.ScheduleCreated, schedule)); err == nil {
+		t.Errorf("Expected error for an app which is not registered in the standby")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"fmt"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/events"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Consumer reads the lifecycle events published by the primary cluster
+type Consumer interface {
+	// Poll returns the next events of the stream, an empty batch when no event arrived within the poll interval
+	Poll() ([]store.Event, error)
+	// Commit acknowledges the events returned by the previous poll
+	Commit() error
+	Close() error
+}
+
+// Factory creates a consumer from the replication configuration
+type Factory func(config conf.ReplicationConfig) (Consumer, error)
+
+// Registry holds the consumer factories by event stream type.
+// Custom consumers can be added with Register before the scheduler is created.
+var Registry = map[string]Factory{
+	events.Kafka: func(config conf.ReplicationConfig) (Consumer, error) { return NewKafkaConsumer(config) },
+	events.Nats:  func(config conf.ReplicationConfig) (Consumer, error) { return NewNatsConsumer(config) },
+}
+
+// Register adds or replaces the factory of a consumer type
+func Register(consumerType string, factory Factory) {
+	Registry[consumerType] = factory
+}
+
+// NewConsumer creates the consumer configured in the supplied configuration
+func NewConsumer(config conf.ReplicationConfig) (Consumer, error) {
+	factory, ok := Registry[config.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown replication consumer type: %s", config.Type)
+	}
+	return factory(config)
+}
@@ -0,0 +1,147 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/events"
+	"github.com/myntra/goscheduler/store"
+)
+
+func testEvent() store.Event {
+	return store.NewEvent(store.ScheduleDeleted, store.Schedule{
+		ScheduleId: gocql.TimeUUID(),
+		AppId:      "test",
+		Payload:    "{}",
+		Status:     store.Deleted,
+	})
+}
+
+func TestNewConsumer(t *testing.T) {
+	if _, err := NewConsumer(conf.ReplicationConfig{Type: "unknown"}); err == nil {
+		t.Errorf("Expected error for unknown consumer type")
+	}
+
+	if _, err := NewConsumer(conf.ReplicationConfig{Type: events.Kafka, Topic: "events"}); err == nil {
+		t.Errorf("Expected error for kafka consumer without address")
+	}
+
+	if _, err := NewConsumer(conf.ReplicationConfig{Type: events.Nats, Address: "127.0.0.1:4222", Topic: "events", Group: "bad group"}); err == nil {
+		t.Errorf("Expected error for queue group with whitespace")
+	}
+}
+
+func TestKafkaConsumer(t *testing.T) {
+	event := testEvent()
+	var requests []string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/consumers/standby":
+			_ = json.NewEncoder(w).Encode(kafkaInstance{InstanceId: "node", BaseUri: server.URL + "/consumers/standby/instances/node"})
+		case r.Method == http.MethodGet:
+			value, _ := json.Marshal(event)
+			_ = json.NewEncoder(w).Encode([]kafkaConsumedRecord{
+				{Topic: "events", Offset: 1, Value: value},
+				{Topic: "events", Offset: 2, Value: json.RawMessage(`"not an event"`)},
+			})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	consumer, err := NewKafkaConsumer(conf.ReplicationConfig{Address: server.URL, Topic: "events", Group: "standby", TimeoutMillis: 1000})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	batch, err := consumer.Poll()
+	if err != nil {
+		t.Fatalf("Unexpected poll error %v", err)
+	}
+	if len(batch) != 1 || batch[0].EventId != event.EventId {
+		t.Errorf("Expected the decodable event only, got %+v", batch)
+	}
+
+	if err = consumer.Commit(); err != nil {
+		t.Errorf("Unexpected commit error %v", err)
+	}
+	if err = consumer.Close(); err != nil {
+		t.Errorf("Unexpected close error %v", err)
+	}
+
+	expected := []string{
+		"POST /consumers/standby",
+		"POST /consumers/standby/instances/node/subscription",
+		"GET /consumers/standby/instances/node/records",
+		"POST /consumers/standby/instances/node/offsets",
+		"DELETE /consumers/standby/instances/node",
+	}
+	if strings.Join(requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("Unexpected requests %v", requests)
+	}
+}
+
+func TestNatsConsumer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer listener.Close()
+
+	event := testEvent()
+	subscribed := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		reader := bufio.NewReader(conn)
+		var lines []string
+		for i := 0; i < 2; i++ {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+		subscribed <- lines
+
+		payload, _ := json.Marshal(event)
+		_, _ = conn.Write([]byte(fmt.Sprintf("PING\r\nMSG goscheduler.events 1 %d\r\n%s\r\n", len(payload), payload)))
+		_, _ = reader.ReadString('\n')
+	}()
+
+	consumer, err := NewNatsConsumer(conf.ReplicationConfig{Address: listener.Addr().String(), Topic: "goscheduler.events", Group: "standby", TimeoutMillis: 1000, PollIntervalMillis: 200})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer consumer.Close()
+
+	batch, err := consumer.Poll()
+	if err != nil {
+		t.Fatalf("Unexpected poll error %v", err)
+	}
+
+	lines := <-subscribed
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "CONNECT ") || lines[1] != "SUB goscheduler.events standby 1" {
+		t.Errorf("Unexpected protocol messages %v", lines)
+	}
+	if len(batch) != 1 || batch[0].EventId != event.EventId {
+		t.Errorf("Expected the published event, got %+v", batch)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	kafkaContentType     = "application/vnd.kafka.v2+json"
+	kafkaJsonContentType = "application/vnd.kafka.json.v2+json"
+)
+
+// KafkaConsumer reads the events from a Kafka topic through the consumer API of the Kafka REST proxy.
+// Offsets are committed explicitly after the events are applied, so an event is replayed rather than lost when
+// the standby stops in between.
+type KafkaConsumer struct {
+	address  string
+	group    string
+	topic    string
+	client   *http.Client
+	instance string
+}
+
+type kafkaInstance struct {
+	InstanceId string `json:"instance_id"`
+	BaseUri    string `json:"base_uri"`
+}
+
+type kafkaConsumedRecord struct {
+	Topic     string          `json:"topic"`
+	Partition int             `json:"partition"`
+	Offset    int64           `json:"offset"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// NewKafkaConsumer creates a consumer of the configured topic, the address is the url of the REST proxy
+func NewKafkaConsumer(config conf.ReplicationConfig) (*KafkaConsumer, error) {
+	if config.Address == "" || config.Topic == "" || config.Group == "" {
+		return nil, errors.New("kafka replication consumer requires an address, a topic and a group")
+	}
+
+	if _, err := url.ParseRequestURI(config.Address); err != nil {
+		return nil, fmt.Errorf("invalid kafka rest proxy address %s: %w", config.Address, err)
+	}
+
+	return &KafkaConsumer{
+		address: strings.TrimRight(config.Address, "/"),
+		group:   config.Group,
+		topic:   config.Topic,
+		client:  &http.Client{Timeout: config.TimeoutMillis * time.Millisecond},
+	}, nil
+}
+
+// subscribe creates a consumer instance in the group and subscribes it to the topic
+func (k *KafkaConsumer) subscribe() error {
+	var instance kafkaInstance
+	err := k.request(http.MethodPost, k.address+"/consumers/"+url.PathEscape(k.group), map[string]string{
+		"format":             "json",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}, &instance)
+	if err != nil {
+		return err
+	}
+
+	if err = k.request(http.MethodPost, instance.BaseUri+"/subscription", map[string][]string{"topics": {k.topic}}, nil); err != nil {
+		_ = k.request(http.MethodDelete, instance.BaseUri, nil, nil)
+		return err
+	}
+
+	k.instance = instance.BaseUri
+	return nil
+}
+
+func (k *KafkaConsumer) Poll() ([]store.Event, error) {
+	if k.instance == "" {
+		if err := k.subscribe(); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, k.instance+"/records", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", kafkaJsonContentType)
+
+	response, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// the proxy drops idle consumer instances, a new one is created on the next poll
+	if response.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		k.instance = ""
+		return nil, errors.New("kafka consumer instance expired")
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		return nil, fmt.Errorf("kafka rest proxy responded with %s", response.Status)
+	}
+
+	var records []kafkaConsumedRecord
+	if err = json.NewDecoder(response.Body).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	result := make([]store.Event, 0, len(records))
+	for _, record := range records {
+		var event store.Event
+		if err = json.Unmarshal(record.Value, &event); err != nil {
+			logger.Errorf("Skipping undecodable event at offset %d of partition %d of %s: %s", record.Offset, record.Partition, record.Topic, err.Error())
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+// Commit commits the offsets of all the records fetched by the consumer instance
+func (k *KafkaConsumer) Commit() error {
+	if k.instance == "" {
+		return nil
+	}
+	return k.request(http.MethodPost, k.instance+"/offsets", nil, nil)
+}
+
+// Close removes the consumer instance from the group so that its partitions are reassigned right away
+func (k *KafkaConsumer) Close() error {
+	defer k.client.CloseIdleConnections()
+
+	if k.instance == "" {
+		return nil
+	}
+	err := k.request(http.MethodDelete, k.instance, nil, nil)
+	k.instance = ""
+	return err
+}
+
+func (k *KafkaConsumer) request(method string, uri string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequest(method, uri, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+
+	response, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		return fmt.Errorf("kafka rest proxy responded with %s to %s %s", response.Status, method, uri)
+	}
+
+	if result == nil {
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// natsMaxBatch bounds the number of events returned by a single poll
+const natsMaxBatch = 100
+
+// NatsConsumer subscribes to a NATS subject with a queue group using the NATS text protocol, so that every
+// event is delivered to a single standby node. NATS core has no acknowledgements, events published while no
+// standby node is connected are lost.
+type NatsConsumer struct {
+	address      string
+	subject      string
+	group        string
+	timeout      time.Duration
+	pollInterval time.Duration
+	conn         net.Conn
+	reader       *bufio.Reader
+	pending      string // part of a control line read before the poll interval elapsed
+}
+
+// NewNatsConsumer creates a consumer of the configured subject, the address is the host:port of the NATS server
+func NewNatsConsumer(config conf.ReplicationConfig) (*NatsConsumer, error) {
+	if config.Address == "" || config.Topic == "" {
+		return nil, errors.New("nats replication consumer requires an address and a topic")
+	}
+
+	if strings.ContainsAny(config.Topic+config.Group, " \t\r\n") {
+		return nil, fmt.Errorf("invalid nats subject %s or queue group %s", config.Topic, config.Group)
+	}
+
+	return &NatsConsumer{
+		address:      config.Address,
+		subject:      config.Topic,
+		group:        config.Group,
+		timeout:      config.TimeoutMillis * time.Millisecond,
+		pollInterval: config.PollIntervalMillis * time.Millisecond,
+	}, nil
+}
+
+// connect dials the server, reads its INFO and subscribes to the subject
+func (n *NatsConsumer) connect() error {
+	conn, err := net.DialTimeout("tcp", n.address, n.timeout)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(n.timeout))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO") {
+		_ = conn.Close()
+		return fmt.Errorf("unexpected nats greeting %q: %v", info, err)
+	}
+
+	message := "CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"goscheduler-standby\"}\r\n" +
+		fmt.Sprintf("SUB %s %s 1\r\n", n.subject, n.group)
+	if err = n.write(conn, message); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	n.conn = conn
+	n.reader = reader
+	return nil
+}
+
+func (n *NatsConsumer) write(conn net.Conn, message string) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(n.timeout))
+	_, err := conn.Write([]byte(message))
+	return err
+}
+
+// Poll returns the messages received within the poll interval
+func (n *NatsConsumer) Poll() ([]store.Event, error) {
+	if n.conn == nil {
+		if err := n.connect(); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []store.Event
+	deadline := time.Now().Add(n.pollInterval)
+	_ = n.conn.SetReadDeadline(deadline)
+
+	for len(result) < natsMaxBatch {
+		line, err := n.reader.ReadString('\n')
+		line = n.pending + line
+		n.pending = ""
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				n.pending = line
+				return result, nil
+			}
+			n.reset()
+			if len(result) > 0 {
+				// the events received before the connection was lost are returned, the next poll reconnects
+				return result, nil
+			}
+			return nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			payload, err := n.readPayload(line)
+			if err != nil {
+				n.reset()
+				return result, err
+			}
+			_ = n.conn.SetReadDeadline(deadline)
+
+			var event store.Event
+			if err = json.Unmarshal(payload, &event); err != nil {
+				logger.Errorf("Skipping undecodable event on %s: %s", n.subject, err.Error())
+				continue
+			}
+			result = append(result, event)
+		case strings.HasPrefix(line, "PING"):
+			if err = n.write(n.conn, "PONG\r\n"); err != nil {
+				n.reset()
+				return result, err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			logger.Errorf("NATS server %s responded with %s", n.address, strings.TrimSpace(line))
+		}
+	}
+
+	return result, nil
+}
+
+// readPayload reads the payload of a MSG whose size is the last field of the control line
+func (n *NatsConsumer) readPayload(line string) ([]byte, error) {
+	fields := strings.Fields(line)
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("invalid nats message %q", strings.TrimSpace(line))
+	}
+
+	// a message is read completely even if it arrives after the poll interval
+	_ = n.conn.SetReadDeadline(time.Now().Add(n.timeout))
+	payload := make([]byte, size+2)
+	if _, err = io.ReadFull(n.reader, payload); err != nil {
+		return nil, err
+	}
+	return payload[:size], nil
+}
+
+func (n *NatsConsumer) reset() {
+	if n.conn != nil {
+		_ = n.conn.Close()
+	}
+	n.conn = nil
+	n.reader = nil
+	n.pending = ""
+}
+
+// Commit is a noop, NATS core does not acknowledge messages
+func (n *NatsConsumer) Commit() error {
+	return nil
+}
+
+func (n *NatsConsumer) Close() error {
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	n.reader = nil
+	n.pending = ""
+	return err
+}
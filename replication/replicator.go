@@ -0,0 +1,155 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/monitoring"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Replicator tails the event stream of the primary cluster and applies it to the standby until the standby is
+// promoted, either through the promote API of any node of the standby or by the role persisted for the cluster.
+type Replicator struct {
+	config      conf.ReplicationConfig
+	clusterName string
+	state       *State
+	applier     *Applier
+	clusterDao  dao.ClusterDao
+	monitor     monitoring.Monitor
+}
+
+func NewReplicator(config *conf.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor monitoring.Monitor) *Replicator {
+	return &Replicator{
+		config:      config.ReplicationConfig,
+		clusterName: config.Cluster.ClusterName,
+		state:       Default(),
+		applier:     NewApplier(config, clusterDao, scheduleDao),
+		clusterDao:  clusterDao,
+		monitor:     monitor,
+	}
+}
+
+// Start resolves the role of the node and starts tailing the event stream if the node is a standby.
+// The role persisted for the cluster takes precedence over the configured one, so that a promoted cluster stays
+// the primary across restarts.
+func (r *Replicator) Start() {
+	role := r.config.Role
+	if persisted, err := r.clusterDao.GetReplicationRole(r.clusterName); err == nil && persisted != "" {
+		role = persisted
+	}
+	r.state.SetRole(role)
+
+	if role == "" {
+		return
+	}
+	logger.Infof("Replication role of cluster %s is %s", r.clusterName, role)
+
+	if role != RoleStandby {
+		return
+	}
+
+	consumer, err := NewConsumer(r.config)
+	if err != nil {
+		logger.Fatal("Invalid replication configuration", err)
+	}
+
+	go r.refreshRole()
+	go r.run(consumer)
+	if r.config.PrimaryAddress != "" && r.config.ResyncIntervalSeconds > 0 {
+		go r.resync()
+	}
+}
+
+// run applies the events of the stream until the node stops being a standby.
+// Events are committed after they are applied; an event which cannot be applied is counted and skipped so that
+// it does not block the stream. An idle stream is polled again after the poll interval.
+func (r *Replicator) run(consumer Consumer) {
+	defer consumer.Close()
+
+	pollInterval := r.config.PollIntervalMillis * time.Millisecond
+	for r.state.IsStandby() {
+		batch, err := consumer.Poll()
+		if err != nil {
+			r.state.RecordError(err)
+			logger.Errorf("Polling the replication stream failed with error %s", err.Error())
+			time.Sleep(pollInterval)
+			continue
+		}
+
+		if len(batch) == 0 {
+			time.Sleep(pollInterval)
+			continue
+		}
+
+		for _, event := range batch {
+			r.apply(event)
+		}
+
+		if err = consumer.Commit(); err != nil {
+			r.state.RecordError(err)
+			logger.Errorf("Committing the replication stream failed with error %s", err.Error())
+		}
+	}
+
+	logger.Infof("Stopped replicating schedules, cluster %s is now %s", r.clusterName, r.state.Role())
+}
+
+func (r *Replicator) apply(event store.Event) {
+	err := r.applier.Apply(event)
+	now := time.Now()
+	r.state.RecordApplied(event.OccurredAt, now, err)
+
+	status := constants.Success
+	if err != nil {
+		status = constants.Fail
+		logger.Errorf("Replicating event %s of type %s failed for schedule id %s with error %s", event.EventId, event.Type, event.ScheduleId, err.Error())
+	}
+
+	if r.monitor != nil {
+		r.monitor.IncCounter(constants.ReplicationEventCount, map[string]string{"type": string(event.Type), "status": status}, 1)
+		if err == nil {
+			r.monitor.RecordTiming(constants.ReplicationLag, map[string]string{}, now.Sub(time.Unix(0, event.OccurredAt*int64(time.Millisecond))))
+		}
+	}
+}
+
+// refreshRole promotes the node once the role persisted for the cluster is primary, so that a promotion through
+// any node of the standby reaches all of its nodes
+func (r *Replicator) refreshRole() {
+	ticker := time.NewTicker(time.Duration(r.config.RoleRefreshSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !r.state.IsStandby() {
+			return
+		}
+
+		role, err := r.clusterDao.GetReplicationRole(r.clusterName)
+		if err == nil && role == RolePrimary && r.state.Promote(time.Now()) {
+			logger.Infof("Cluster %s was promoted to primary", r.clusterName)
+			return
+		}
+	}
+}
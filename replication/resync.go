@@ -0,0 +1,177 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// resync periodically pulls the export of every app from the primary and applies it to the standby.
+// The event stream is best effort, so the resync repairs the schedules whose events were dropped by the primary or
+// missed by the standby and removes the recurring schedules which no longer exist in the primary.
+func (r *Replicator) resync() {
+	ticker := time.NewTicker(time.Duration(r.config.ResyncIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	client := &http.Client{Timeout: r.config.ResyncTimeoutSeconds * time.Second}
+	for range ticker.C {
+		if !r.state.IsStandby() {
+			return
+		}
+
+		apps, err := r.clusterDao.GetApps("")
+		if err != nil {
+			r.state.RecordError(err)
+			logger.Errorf("Fetching the apps to resync failed with error %s", err.Error())
+			continue
+		}
+
+		for _, app := range apps {
+			if app.AppId == r.applier.Config.CronConfig.App {
+				continue
+			}
+
+			diverged, err := r.resyncApp(client, app.AppId)
+			r.state.RecordResync(diverged, time.Now(), err)
+			if err != nil {
+				logger.Errorf("Resyncing app %s from the primary failed with error %s", app.AppId, err.Error())
+			}
+			if diverged > 0 {
+				logger.Infof("Repaired %d diverged schedules of app %s from the primary", diverged, app.AppId)
+			}
+			if r.monitor != nil && diverged > 0 {
+				r.monitor.IncCounter(constants.ReplicationDivergenceCount, map[string]string{"appId": app.AppId}, diverged)
+			}
+		}
+	}
+}
+
+// resyncApp applies the export of an app from the primary to the standby and returns the number of schedules
+// which had diverged, those missing in the standby and the recurring ones missing in the primary
+func (r *Replicator) resyncApp(client *http.Client, appId string) (int, error) {
+	standby, errs := r.applier.ScheduleDao.GetCronSchedulesByApp(appId, "")
+	if len(errs) != 0 {
+		return 0, fmt.Errorf("fetching recurring schedules failed: %s", strings.Join(errs, ","))
+	}
+
+	stale := make(map[gocql.UUID]bool)
+	for _, schedule := range standby {
+		if schedule.Status != store.Deleted {
+			stale[schedule.ScheduleId] = true
+		}
+	}
+
+	exportUrl := strings.TrimRight(r.config.PrimaryAddress, "/") + "/goscheduler/apps/" + url.PathEscape(appId) + "/export?format=ndjson"
+	response, err := client.Get(exportUrl)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		// the app is not registered in the primary
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		return 0, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return 0, fmt.Errorf("export of the primary responded with %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	diverged := 0
+	decoder := json.NewDecoder(response.Body)
+	for decoder.More() {
+		var schedule store.Schedule
+		if err = decoder.Decode(&schedule); err != nil {
+			return diverged, err
+		}
+
+		if schedule.IsRecurring() {
+			if !stale[schedule.ScheduleId] {
+				diverged++
+			}
+			delete(stale, schedule.ScheduleId)
+		} else if _, err = r.applier.ScheduleDao.GetSchedule(schedule.ScheduleId); err == gocql.ErrNotFound {
+			diverged++
+		}
+
+		if err = r.applier.upsert(schedule); err != nil {
+			return diverged, err
+		}
+	}
+
+	// an export is not terminated in the ndjson format, so a schedule left out of it is only removed once the
+	// primary confirms that it does not exist anymore
+	for scheduleId := range stale {
+		exists, err := r.existsInPrimary(client, scheduleId)
+		if err != nil {
+			return diverged, err
+		}
+		if exists {
+			continue
+		}
+
+		if err = r.applier.delete(scheduleId.String()); err != nil {
+			return diverged, err
+		}
+		diverged++
+	}
+	return diverged, nil
+}
+
+// primarySchedule is the response of the get schedule API of the primary
+type primarySchedule struct {
+	Data struct {
+		Schedule store.Schedule `json:"schedule"`
+	} `json:"data"`
+}
+
+// existsInPrimary reports whether a schedule exists in the primary and is not deleted
+func (r *Replicator) existsInPrimary(client *http.Client, scheduleId gocql.UUID) (bool, error) {
+	response, err := client.Get(strings.TrimRight(r.config.PrimaryAddress, "/") + "/goscheduler/schedules/" + scheduleId.String())
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		var schedule primarySchedule
+		if err = json.NewDecoder(response.Body).Decode(&schedule); err != nil {
+			return false, err
+		}
+		return schedule.Data.Schedule.Status != store.Deleted, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("get schedule %s of the primary responded with %d", scheduleId, response.StatusCode)
+	}
+}
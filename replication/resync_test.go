@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package replication
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestReplicator_ResyncApp(t *testing.T) {
+	scheduleDao := newMockScheduleDao()
+
+	kept := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "{}", CronExpression: "* * * * *"}
+	removed := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "{}", CronExpression: "0 * * * *"}
+	missing := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "{}", ScheduleTime: 1700000090}
+	scheduleDao.schedules[kept.ScheduleId] = kept
+	scheduleDao.schedules[removed.ScheduleId] = removed
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/goscheduler/apps/test/export":
+			encoder := json.NewEncoder(w)
+			_ = encoder.Encode(kept)
+			_ = encoder.Encode(missing)
+		case "/goscheduler/schedules/" + removed.ScheduleId.String():
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("Unexpected request to the primary %s", r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer primary.Close()
+
+	replicator := &Replicator{
+		config:  conf.ReplicationConfig{PrimaryAddress: primary.URL},
+		state:   &State{},
+		applier: newTestApplier(scheduleDao),
+	}
+
+	diverged, err := replicator.resyncApp(primary.Client(), "test")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if diverged != 2 {
+		t.Errorf("Expected the missing and the removed schedules to diverge, got %d", diverged)
+	}
+	if len(scheduleDao.created) != 1 || scheduleDao.created[0].ScheduleId != missing.ScheduleId {
+		t.Errorf("Expected the missing one time schedule to be created, got %+v", scheduleDao.created)
+	}
+	if len(scheduleDao.recurring) != 1 || scheduleDao.recurring[0].ScheduleId != kept.ScheduleId {
+		t.Errorf("Expected the recurring schedule of the primary to be upserted, got %+v", scheduleDao.recurring)
+	}
+	if len(scheduleDao.deleted) != 1 || scheduleDao.deleted[0] != removed.ScheduleId {
+		t.Errorf("Expected the recurring schedule removed from the primary to be deleted, got %+v", scheduleDao.deleted)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+// Package replication keeps a standby cluster in a warm copy of the schedules of the primary datacenter by tailing
+// its lifecycle event stream. A standby does not fire any schedule until it is promoted.
+package replication
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	RolePrimary = "primary"
+	RoleStandby = "standby"
+
+	// roleDisabled is reported when replication is not configured for the cluster
+	roleDisabled = "disabled"
+)
+
+// Status is a snapshot of the replication state of the node
+type Status struct {
+	Role          string `json:"role"`
+	Applied       int64  `json:"applied"`
+	Failed        int64  `json:"failed"`
+	LastEventAt   int64  `json:"lastEventAt,omitempty"`
+	LastAppliedAt int64  `json:"lastAppliedAt,omitempty"`
+	LagMillis     int64  `json:"lagMillis"`
+	LastError     string `json:"lastError,omitempty"`
+	PromotedAt    int64  `json:"promotedAt,omitempty"`
+	Diverged      int64  `json:"diverged"`
+	LastResyncAt  int64  `json:"lastResyncAt,omitempty"`
+}
+
+// State holds the replication role of the node and the progress of the replicated event stream
+type State struct {
+	mu            sync.RWMutex
+	role          string
+	applied       int64
+	failed        int64
+	lastEventAt   int64
+	lastAppliedAt time.Time
+	lastError     string
+	promotedAt    time.Time
+	diverged      int64
+	lastResyncAt  time.Time
+}
+
+var state = &State{}
+
+// Default returns the replication state consulted by the pollers of the node
+func Default() *State {
+	return state
+}
+
+// Role returns the replication role of the node, empty when replication is disabled
+func (s *State) Role() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.role
+}
+
+// SetRole changes the replication role of the node
+func (s *State) SetRole(role string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.role = role
+}
+
+// IsStandby reports whether the node only replicates schedules without firing them
+func (s *State) IsStandby() bool {
+	return s.Role() == RoleStandby
+}
+
+// Promote turns a standby into a primary, returns false if the node was not a standby
+func (s *State) Promote(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.role != RoleStandby {
+		return false
+	}
+	s.role = RolePrimary
+	s.promotedAt = now
+	return true
+}
+
+// RecordApplied records the outcome of applying an event which occurred at the given epoch millis
+func (s *State) RecordApplied(occurredAt int64, now time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.failed++
+		s.lastError = err.Error()
+		return
+	}
+
+	s.applied++
+	s.lastAppliedAt = now
+	if occurredAt > s.lastEventAt {
+		s.lastEventAt = occurredAt
+	}
+}
+
+// RecordError records a failure to read the event stream
+func (s *State) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = err.Error()
+}
+
+// RecordResync records the outcome of resyncing an app from the primary, diverged is the number of schedules
+// which were repaired
+func (s *State) RecordResync(diverged int, now time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.diverged += int64(diverged)
+	if err != nil {
+		s.lastError = err.Error()
+		return
+	}
+	s.lastResyncAt = now
+}
+
+// Status returns a snapshot of the replication state.
+// The lag is the age of the latest applied event when it was applied.
+func (s *State) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := Status{
+		Role:        s.role,
+		Applied:     s.applied,
+		Failed:      s.failed,
+		LastEventAt: s.lastEventAt,
+		LastError:   s.lastError,
+		Diverged:    s.diverged,
+	}
+
+	if status.Role == "" {
+		status.Role = roleDisabled
+	}
+
+	if !s.lastAppliedAt.IsZero() {
+		status.LastAppliedAt = toMillis(s.lastAppliedAt)
+		if lag := status.LastAppliedAt - s.lastEventAt; lag > 0 {
+			status.LagMillis = lag
+		}
+	}
+
+	if !s.promotedAt.IsZero() {
+		status.PromotedAt = toMillis(s.promotedAt)
+	}
+
+	if !s.lastResyncAt.IsZero() {
+		status.LastResyncAt = toMillis(s.lastResyncAt)
+	}
+
+	return status
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,77 @@
+package replication
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestState_Promote(t *testing.T) {
+	state := &State{}
+	if state.Status().Role != roleDisabled {
+		t.Errorf("Expected disabled role, got %s", state.Status().Role)
+	}
+
+	if state.Promote(time.Now()) {
+		t.Errorf("Expected a node without replication not to be promoted")
+	}
+
+	state.SetRole(RoleStandby)
+	if !state.IsStandby() {
+		t.Fatalf("Expected standby role")
+	}
+
+	promotedAt := time.Unix(1700000000, 0)
+	if !state.Promote(promotedAt) {
+		t.Fatalf("Expected standby to be promoted")
+	}
+	if state.IsStandby() || state.Role() != RolePrimary {
+		t.Errorf("Expected primary role after promotion, got %s", state.Role())
+	}
+	if state.Status().PromotedAt != 1700000000000 {
+		t.Errorf("Unexpected promotion time %d", state.Status().PromotedAt)
+	}
+	if state.Promote(time.Now()) {
+		t.Errorf("Expected a primary not to be promoted again")
+	}
+}
+
+func TestState_RecordApplied(t *testing.T) {
+	state := &State{}
+	now := time.Unix(1700000000, 0)
+	occurredAt := toMillis(now) - 1500
+
+	state.RecordApplied(occurredAt, now, nil)
+	state.RecordApplied(occurredAt-1000, now, nil)
+	state.RecordApplied(occurredAt, now, errors.New("app test is not registered"))
+
+	status := state.Status()
+	if status.Applied != 2 || status.Failed != 1 {
+		t.Errorf("Expected 2 applied and 1 failed events, got %+v", status)
+	}
+	if status.LastEventAt != occurredAt || status.LagMillis != 1500 {
+		t.Errorf("Expected lag of the latest event, got %+v", status)
+	}
+	if status.LastError != "app test is not registered" {
+		t.Errorf("Unexpected last error %s", status.LastError)
+	}
+}
+
+func TestState_RecordResync(t *testing.T) {
+	state := &State{}
+	now := time.Unix(1700000000, 0)
+
+	state.RecordResync(2, now, nil)
+	state.RecordResync(1, now.Add(time.Minute), errors.New("export of the primary responded with 500"))
+
+	status := state.Status()
+	if status.Diverged != 3 {
+		t.Errorf("Expected 3 diverged schedules, got %d", status.Diverged)
+	}
+	if status.LastResyncAt != 1700000000000 {
+		t.Errorf("Expected the last successful resync, got %d", status.LastResyncAt)
+	}
+	if status.LastError != "export of the primary responded with 500" {
+		t.Errorf("Unexpected last error %s", status.LastError)
+	}
+}
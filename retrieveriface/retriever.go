@@ -28,3 +28,23 @@ type Retriever interface {
 	GetSchedules(appName string, partitionID int, timeBucket time.Time) error
 	BulkAction(app store.App, partitionId int, timeBucket time.Time, status []store.Status, actionType store.ActionType) error
 }
+
+// LoadAwareRetriever is a retriever which supports adaptive polling: it can count the schedules due in upcoming
+// time buckets and fire only the schedules of a bucket which are due within a window.
+type LoadAwareRetriever interface {
+	Retriever
+	CountSchedules(appName string, partitionID int, timeBuckets []time.Time) ([]int, error)
+	GetSchedulesInWindow(appName string, partitionID int, timeBucket time.Time, from time.Time, to time.Time) error
+}
+
+// PrefetchingRetriever is a retriever which can read a time bucket before it begins, holding its schedules in memory
+// so that none of them fires before it is due.
+type PrefetchingRetriever interface {
+	Retriever
+	PrefetchSchedules(appName string, partitionID int, timeBucket time.Time) error
+}
+
+type RecoveringRetriever interface {
+	Retriever
+	RecoverSchedules(appName string, partitionID int, claimBucket time.Time) error
+}
@@ -20,18 +20,26 @@
 package retrievers
 
 import (
-	"github.com/golang/glog"
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
 	s "github.com/myntra/goscheduler/store"
 	"time"
 )
 
 type CronRetriever struct {
-	scheduleDao dao.ScheduleDao
-	cronConfig  *conf.CronConfig
-	monitor     p.Monitor
+	clusterDao      dao.ClusterDao
+	scheduleDao     dao.ScheduleDao
+	cronConfig      *conf.CronConfig
+	retentionConfig *conf.RetentionConfig
+	parkingConfig   *conf.ParkingConfig
+	appConfig       *conf.AppLevelConfiguration
+	monitor         p.Monitor
 }
 
 // GetSchedules Get recurring schedules with partition id and pushes them on the channel for creating one time schedules for them.
@@ -42,8 +50,8 @@ func (r CronRetriever) GetSchedules(app string, partitionId int, _time time.Time
 	var schedules []s.Schedule
 	var errs []error
 	if schedules, errs = r.scheduleDao.GetRecurringScheduleByPartition(partitionId); len(errs) != 0 {
-		glog.Errorf("%d errors occurred in retrieving for %s %d", len(errs), app, partitionId)
-		glog.Errorf("%v", errs)
+		logger.Errorf("%d errors occurred in retrieving for %s %d", len(errs), app, partitionId)
+		logger.Errorf("%v", errs)
 	}
 
 	for _, schedule := range schedules {
@@ -58,9 +66,175 @@ func (r CronRetriever) GetSchedules(app string, partitionId int, _time time.Time
 		}
 	}
 
+	now := clock.Now()
+	diagnostics.Default().RecordPoll(app, partitionId, _time, len(schedules), now)
+	r.purge(schedules, now)
+	r.expire(schedules, now)
+	r.promote(app, partitionId, now)
 	return nil
 }
 
+// promote moves the parked schedules which are within the promotion lead to the time bucketed tables.
+// The shards of parked schedules are spread over the partitions of the cron app, so that every shard is promoted by
+// the node owning its partition.
+func (r CronRetriever) promote(cronApp string, partitionId int, now time.Time) {
+	if r.parkingConfig == nil || !r.parkingConfig.Enabled {
+		return
+	}
+
+	cron, err := r.clusterDao.GetApp(cronApp)
+	if err != nil {
+		logger.Errorf("Error getting app %s to promote parked schedules: %+v", cronApp, err)
+		return
+	}
+
+	apps := make(map[string]s.App)
+	for _, shard := range s.ParkingShards(r.parkingConfig.Shards, partitionId, cron.Partitions) {
+		for _, day := range s.PromotionDays(*r.parkingConfig, now) {
+			schedules, errs := r.scheduleDao.GetParkedSchedules(day, shard)
+			if len(errs) != 0 {
+				logger.Errorf("%d errors occurred in retrieving parked schedules of shard %d on %s: %v", len(errs), shard, day.UTC().Format("2006-01-02"), errs)
+			}
+
+			for _, schedule := range schedules {
+				if !s.ShouldPromote(*r.parkingConfig, schedule.ScheduleTime, now) {
+					continue
+				}
+
+				app, ok := apps[schedule.AppId]
+				if !ok {
+					if app, err = r.clusterDao.GetApp(schedule.AppId); err != nil {
+						logger.Errorf("Error getting app %s to promote parked schedule %s: %+v", schedule.AppId, schedule.ScheduleId, err)
+						continue
+					}
+					apps[schedule.AppId] = app
+				}
+
+				if _, err := r.scheduleDao.PromoteSchedule(schedule, app); err != nil {
+					continue
+				}
+
+				schedule.Logger().Infof("Promoted parked schedule %s due at %d", schedule.ScheduleId, schedule.ScheduleTime)
+				r.recordPromotion(schedule.AppId)
+			}
+		}
+	}
+}
+
+func (r CronRetriever) recordPromotion(appId string) {
+	if r.monitor != nil {
+		r.monitor.IncCounter(constants.PromotedScheduleCount, map[string]string{"appId": appId}, 1)
+	}
+}
+
+// purge removes the deleted recurring schedules of the partition which are past the deleted schedule retention of
+// their app. At most PurgeLimit schedules are purged per poll so that the tombstones are spread over time.
+func (r CronRetriever) purge(schedules []s.Schedule, now time.Time) {
+	if r.retentionConfig == nil || !r.retentionConfig.PurgeEnabled {
+		return
+	}
+
+	apps := make(map[string]s.App)
+	purged := 0
+	for _, schedule := range schedules {
+		if purged >= r.retentionConfig.PurgeLimit {
+			return
+		}
+		if schedule.Status != s.Deleted {
+			continue
+		}
+
+		app, ok := apps[schedule.AppId]
+		if !ok {
+			var err error
+			// the schedules of an app which is gone are kept for the default retention
+			if app, err = r.clusterDao.GetApp(schedule.AppId); err != nil && err != gocql.ErrNotFound {
+				logger.Errorf("Error getting app %s to purge schedule %s: %+v", schedule.AppId, schedule.ScheduleId, err)
+				continue
+			}
+			apps[schedule.AppId] = app
+		}
+
+		if !schedule.IsPurgeable(app, r.appConfig.DeletedScheduleRetentionPeriod, now) {
+			continue
+		}
+		if err := r.scheduleDao.PurgeRecurringSchedule(schedule); err != nil {
+			schedule.Logger().Errorf("Error purging deleted schedule %s: %+v", schedule.ScheduleId, err)
+			continue
+		}
+
+		schedule.Logger().Infof("Purged schedule %s deleted at %d", schedule.ScheduleId, schedule.DeletedAt)
+		r.recordPurge(schedule.AppId)
+		purged++
+	}
+}
+
+func (r CronRetriever) recordPurge(appId string) {
+	if r.monitor != nil {
+		r.monitor.IncCounter(constants.PurgedScheduleCount, map[string]string{"appId": appId}, 1)
+	}
+}
+
+// expire deletes, or alerts on, the paused recurring schedules of the partition which are stale as per the stale
+// pause policy of their app. At most StalePauseLimit schedules are deleted per poll, and a schedule is alerted on once
+// per pause by the node.
+func (r CronRetriever) expire(schedules []s.Schedule, now time.Time) {
+	if r.retentionConfig == nil {
+		return
+	}
+
+	apps := make(map[string]s.App)
+	deleted := 0
+	for _, schedule := range schedules {
+		if schedule.Status != s.Paused || schedule.KeepPaused {
+			continue
+		}
+
+		app, ok := apps[schedule.AppId]
+		if !ok {
+			var err error
+			if app, err = r.clusterDao.GetApp(schedule.AppId); err != nil {
+				logger.Errorf("Error getting app %s to expire schedule %s: %+v", schedule.AppId, schedule.ScheduleId, err)
+				continue
+			}
+			apps[schedule.AppId] = app
+		}
+
+		policy := app.Configuration.StalePause
+		if policy == nil || !policy.Stale(schedule, now) {
+			continue
+		}
+
+		switch policy.Action {
+		case s.StaleAlert:
+			if !s.StalePauseAlerts().Alert(schedule) {
+				continue
+			}
+			schedule.Logger().Infof("Schedule %s paused at %d is stale", schedule.ScheduleId, schedule.PausedAt)
+			s.PublishEvent(s.ScheduleStale, schedule)
+		case s.StaleDelete:
+			if deleted >= r.retentionConfig.StalePauseLimit {
+				continue
+			}
+			updated, err := r.scheduleDao.DeleteSchedule(schedule.ScheduleId)
+			if err != nil {
+				schedule.Logger().Errorf("Error deleting stale schedule %s: %+v", schedule.ScheduleId, err)
+				continue
+			}
+			schedule.Logger().Infof("Deleted stale schedule %s paused at %d", schedule.ScheduleId, schedule.PausedAt)
+			s.PublishEvent(s.ScheduleDeleted, updated)
+			deleted++
+		}
+		r.recordStalePause(schedule.AppId, policy.Action)
+	}
+}
+
+func (r CronRetriever) recordStalePause(appId string, action string) {
+	if r.monitor != nil {
+		r.monitor.IncCounter(constants.StalePausedScheduleCount, map[string]string{"appId": appId, "action": action}, 1)
+	}
+}
+
 // BulkAction Implement BulkAction for Cron if required
 func (r CronRetriever) BulkAction(app s.App, partitionId int, timeBucket time.Time, status []s.Status, actionType s.ActionType) error {
 	return nil
@@ -0,0 +1,197 @@
+package retrievers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+// purgingScheduleDao returns the recurring schedules of a partition and records the purged ones
+type purgingScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	schedules []store.Schedule
+	purged    []gocql.UUID
+}
+
+func (d *purgingScheduleDao) GetRecurringScheduleByPartition(partitionId int) ([]store.Schedule, []error) {
+	return d.schedules, nil
+}
+
+func (d *purgingScheduleDao) PurgeRecurringSchedule(schedule store.Schedule) error {
+	d.purged = append(d.purged, schedule.ScheduleId)
+	return nil
+}
+
+func TestCronRetrieverPurgesExpiredDeletedSchedules(t *testing.T) {
+	now := time.Now()
+	day := int64(60 * 60 * 24)
+
+	expired := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Deleted, DeletedAt: now.Unix() - 8*day}
+	legacy := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Deleted}
+	recent := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Deleted, DeletedAt: now.Unix() - day}
+	lookupFailed := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "testGetAppError", Status: store.Deleted}
+	paused := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Paused}
+
+	for _, test := range []struct {
+		config conf.RetentionConfig
+		purged []gocql.UUID
+	}{
+		{conf.RetentionConfig{PurgeEnabled: false, PurgeLimit: 10}, nil},
+		{conf.RetentionConfig{PurgeEnabled: true, PurgeLimit: 10}, []gocql.UUID{expired.ScheduleId, legacy.ScheduleId}},
+		{conf.RetentionConfig{PurgeEnabled: true, PurgeLimit: 1}, []gocql.UUID{expired.ScheduleId}},
+	} {
+		scheduleDao := &purgingScheduleDao{schedules: []store.Schedule{paused, lookupFailed, recent, expired, legacy}}
+		retriever := CronRetriever{
+			clusterDao:      dao.DummyClusterDaoImpl{},
+			scheduleDao:     scheduleDao,
+			cronConfig:      &conf.CronConfig{Window: 1},
+			retentionConfig: &test.config,
+			appConfig:       &conf.AppLevelConfiguration{DeletedScheduleRetentionPeriod: 7},
+		}
+
+		if err := retriever.GetSchedules("cron", 0, now); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(scheduleDao.purged) != len(test.purged) {
+			t.Fatalf("expected %d purged schedules with %+v, got %v", len(test.purged), test.config, scheduleDao.purged)
+		}
+		for i, id := range test.purged {
+			if scheduleDao.purged[i] != id {
+				t.Errorf("expected schedule %s to be purged with %+v, got %s", id, test.config, scheduleDao.purged[i])
+			}
+		}
+	}
+}
+
+// parkingScheduleDao returns the same parked schedules for every shard and day and records the promoted ones
+type parkingScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	parked   []store.Schedule
+	promoted map[gocql.UUID]bool
+}
+
+func (d *parkingScheduleDao) GetParkedSchedules(day time.Time, shard int) ([]store.Schedule, []error) {
+	if day.Unix() != store.ParkingDay(time.Now().Unix()) || shard != 0 {
+		return nil, nil
+	}
+	return d.parked, nil
+}
+
+func (d *parkingScheduleDao) PromoteSchedule(schedule store.Schedule, app store.App) (store.Schedule, error) {
+	d.promoted[schedule.ScheduleId] = true
+	return schedule, nil
+}
+
+func TestCronRetrieverPromotesParkedSchedules(t *testing.T) {
+	now := time.Now()
+	day := int64(60 * 60 * 24)
+
+	due := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", ScheduleTime: now.Unix() + day, Parked: true}
+	later := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", ScheduleTime: now.Unix() + 10*day, Parked: true}
+	lookupFailed := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "testGetAppError", ScheduleTime: now.Unix() + day, Parked: true}
+
+	for _, test := range []struct {
+		config   conf.ParkingConfig
+		promoted []gocql.UUID
+	}{
+		{conf.ParkingConfig{Enabled: false, HorizonDays: 30, PromotionDays: 7, Shards: 1}, nil},
+		{conf.ParkingConfig{Enabled: true, HorizonDays: 30, PromotionDays: 7, Shards: 1}, []gocql.UUID{due.ScheduleId}},
+	} {
+		scheduleDao := &parkingScheduleDao{parked: []store.Schedule{due, later, lookupFailed}, promoted: make(map[gocql.UUID]bool)}
+		retriever := CronRetriever{
+			clusterDao:      dao.DummyClusterDaoImpl{},
+			scheduleDao:     scheduleDao,
+			cronConfig:      &conf.CronConfig{Window: 1},
+			retentionConfig: &conf.RetentionConfig{},
+			parkingConfig:   &test.config,
+			appConfig:       &conf.AppLevelConfiguration{},
+		}
+
+		if err := retriever.GetSchedules("cron", 0, now); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(scheduleDao.promoted) != len(test.promoted) {
+			t.Fatalf("expected %d promoted schedules with %+v, got %v", len(test.promoted), test.config, scheduleDao.promoted)
+		}
+		for _, id := range test.promoted {
+			if !scheduleDao.promoted[id] {
+				t.Errorf("expected schedule %s to be promoted with %+v", id, test.config)
+			}
+		}
+	}
+}
+
+// stalePauseClusterDao returns the apps with the same stale pause policy
+type stalePauseClusterDao struct {
+	dao.DummyClusterDaoImpl
+	policy *store.StalePausePolicy
+}
+
+func (d stalePauseClusterDao) GetApp(appName string) (store.App, error) {
+	return store.App{AppId: appName, Active: true, Configuration: store.Configuration{StalePause: d.policy}}, nil
+}
+
+// expiringScheduleDao returns the recurring schedules of a partition and records the deleted ones
+type expiringScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	schedules []store.Schedule
+	deleted   []gocql.UUID
+}
+
+func (d *expiringScheduleDao) GetRecurringScheduleByPartition(partitionId int) ([]store.Schedule, []error) {
+	return d.schedules, nil
+}
+
+func (d *expiringScheduleDao) DeleteSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	d.deleted = append(d.deleted, uuid)
+	return store.Schedule{ScheduleId: uuid, Status: store.Deleted}, nil
+}
+
+func TestCronRetrieverExpiresStalePausedSchedules(t *testing.T) {
+	now := time.Now()
+	day := int64(60 * 60 * 24)
+
+	stale := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Paused, PausedAt: now.Unix() - 10*day}
+	older := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Paused, PausedAt: now.Unix() - 20*day}
+	exempt := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Paused, PausedAt: now.Unix() - 10*day, KeepPaused: true}
+	recent := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Paused, PausedAt: now.Unix() - day}
+	legacy := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Paused}
+	active := store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: store.Scheduled}
+
+	for _, test := range []struct {
+		policy  *store.StalePausePolicy
+		limit   int
+		deleted []gocql.UUID
+	}{
+		{nil, 10, nil},
+		{&store.StalePausePolicy{PausedDays: 7, Action: store.StaleAlert}, 10, nil},
+		{&store.StalePausePolicy{PausedDays: 7, Action: store.StaleDelete}, 10, []gocql.UUID{stale.ScheduleId, older.ScheduleId}},
+		{&store.StalePausePolicy{PausedDays: 7, Action: store.StaleDelete}, 1, []gocql.UUID{stale.ScheduleId}},
+		{&store.StalePausePolicy{PausedDays: 15, Action: store.StaleDelete}, 10, []gocql.UUID{older.ScheduleId}},
+	} {
+		scheduleDao := &expiringScheduleDao{schedules: []store.Schedule{active, stale, exempt, recent, legacy, older}}
+		retriever := CronRetriever{
+			clusterDao:      stalePauseClusterDao{policy: test.policy},
+			scheduleDao:     scheduleDao,
+			cronConfig:      &conf.CronConfig{Window: 1},
+			retentionConfig: &conf.RetentionConfig{StalePauseLimit: test.limit},
+			appConfig:       &conf.AppLevelConfiguration{},
+		}
+		retriever.expire(scheduleDao.schedules, now)
+
+		if len(scheduleDao.deleted) != len(test.deleted) {
+			t.Fatalf("expected %d deleted schedules with %+v, got %v", len(test.deleted), test.policy, scheduleDao.deleted)
+		}
+		for i, id := range test.deleted {
+			if scheduleDao.deleted[i] != id {
+				t.Errorf("expected schedule %s to be deleted with %+v, got %s", id, test.policy, scheduleDao.deleted[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package retrievers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// groupCacheTTL bounds how long a node applies an old run limit, or an old membership, after a group or a schedule
+// is updated
+const groupCacheTTL = 30 * time.Second
+
+type cachedLimits struct {
+	limits   map[string]int
+	cachedAt time.Time
+}
+
+type cachedGroup struct {
+	group    string
+	cachedAt time.Time
+}
+
+// groupLimits caches the run limits of the groups of the apps, and the groups of the recurring schedules of the apps
+// having a group with a limit, so that the runs of the other apps are dispatched without a lookup
+var groupLimits = struct {
+	sync.Mutex
+	apps      map[string]cachedLimits
+	schedules map[gocql.UUID]cachedGroup
+}{apps: map[string]cachedLimits{}, schedules: map[gocql.UUID]cachedGroup{}}
+
+// limitsOf returns the run limits of the groups of the app having one, by group
+func (s ScheduleRetriever) limitsOf(appId string, now time.Time) (map[string]int, error) {
+	groupLimits.Lock()
+	cached, ok := groupLimits.apps[appId]
+	groupLimits.Unlock()
+	if ok && now.Sub(cached.cachedAt) < groupCacheTTL {
+		return cached.limits, nil
+	}
+
+	groups, err := s.clusterDao.GetScheduleGroups(appId)
+	if err != nil {
+		return nil, err
+	}
+	limits := make(map[string]int)
+	for _, group := range groups {
+		if group.MaxRunsPerMinute > 0 {
+			limits[group.Name] = group.MaxRunsPerMinute
+		}
+	}
+
+	groupLimits.Lock()
+	groupLimits.apps[appId] = cachedLimits{limits: limits, cachedAt: now}
+	groupLimits.Unlock()
+	return limits, nil
+}
+
+// groupOf returns the group of the recurring schedule of a run
+func (s ScheduleRetriever) groupOf(parentScheduleId gocql.UUID, now time.Time) (string, error) {
+	groupLimits.Lock()
+	cached, ok := groupLimits.schedules[parentScheduleId]
+	groupLimits.Unlock()
+	if ok && now.Sub(cached.cachedAt) < groupCacheTTL {
+		return cached.group, nil
+	}
+
+	parent, err := s.scheduleDao.GetSchedule(parentScheduleId)
+	if err != nil {
+		return "", err
+	}
+
+	groupLimits.Lock()
+	for id, expired := range groupLimits.schedules {
+		if now.Sub(expired.cachedAt) >= groupCacheTTL {
+			delete(groupLimits.schedules, id)
+		}
+	}
+	groupLimits.schedules[parentScheduleId] = cachedGroup{group: parent.Group, cachedAt: now}
+	groupLimits.Unlock()
+	return parent.Group, nil
+}
+
+// admitGroupRun counts a run of a recurring schedule which is a member of a group with a run limit against the runs
+// of the group fired in the current minute across the cluster, and skips it once the group is over its limit.
+// A run whose group cannot be looked up or counted is dispatched.
+// Returns false when the run is skipped.
+func (s ScheduleRetriever) admitGroupRun(app store.App, run store.Schedule) bool {
+	if util.IsZeroUUID(run.ParentScheduleId) {
+		return true
+	}
+
+	now := clock.Now()
+	limits, err := s.limitsOf(app.AppId, now)
+	if err != nil {
+		run.Logger().Errorf("Fetching the groups of app %s failed with error %s", app.AppId, err.Error())
+		return true
+	}
+	if len(limits) == 0 {
+		return true
+	}
+
+	group, err := s.groupOf(run.ParentScheduleId, now)
+	if err != nil {
+		run.Logger().Errorf("Fetching the group of cron %s failed with error %s", run.ParentScheduleId.String(), err.Error())
+		return true
+	}
+	limit, ok := limits[group]
+	if !ok {
+		return true
+	}
+
+	runs, err := s.clusterDao.IncrementGroupRuns(app.AppId, group, now.Truncate(time.Minute))
+	if err != nil {
+		run.Logger().Errorf("Counting the runs of group %s of app %s failed with error %s", group, app.AppId, err.Error())
+		return true
+	}
+	if runs <= int64(limit) {
+		return true
+	}
+
+	run.Status = store.Skipped
+	run.ErrorMessage = fmt.Sprintf("skipped as group %s reached its limit of %d runs per minute", group, limit)
+	run.Logger().Infof("Schedule %s skipped by the run limit of its group: %s", run.ScheduleId.String(), run.ErrorMessage)
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.GroupLimitedRunCount, map[string]string{
+			"appId": app.AppId,
+			"group": group,
+		}, 1)
+	}
+	store.AggregationTaskQueue <- store.ScheduleWrapper{Schedule: run, App: app}
+	return false
+}
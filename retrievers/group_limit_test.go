@@ -0,0 +1,72 @@
+package retrievers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+// countingClusterDao reports the runs of the groups as counted by the test
+type countingClusterDao struct {
+	dao.DummyClusterDaoImpl
+	runs int64
+}
+
+func (d *countingClusterDao) IncrementGroupRuns(appId string, name string, minute time.Time) (int64, error) {
+	d.runs++
+	return d.runs, nil
+}
+
+// groupScheduleDao returns recurring schedules which are members of the group
+type groupScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	group string
+}
+
+func (d *groupScheduleDao) GetSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	return store.Schedule{ScheduleId: uuid, Group: d.group}, nil
+}
+
+func TestScheduleRetriever_AdmitGroupRun(t *testing.T) {
+	aggregation := store.AggregationTaskQueue
+	defer func() { store.AggregationTaskQueue = aggregation }()
+	store.AggregationTaskQueue = make(chan store.ScheduleWrapper, 20)
+
+	for _, test := range []struct {
+		name     string
+		group    string
+		admitted int
+	}{
+		// testGroup of the dummy cluster dao is limited to 10 runs per minute
+		{"Limited", "testGroup", 10},
+		{"Unlimited", "otherGroup", 15},
+	} {
+		groupLimits.apps = map[string]cachedLimits{}
+		groupLimits.schedules = map[gocql.UUID]cachedGroup{}
+		retriever := ScheduleRetriever{clusterDao: &countingClusterDao{}, scheduleDao: &groupScheduleDao{group: test.group}}
+		app := store.App{AppId: "testApp"}
+		parent := gocql.TimeUUID()
+
+		admitted := 0
+		for i := 0; i < 15; i++ {
+			if retriever.admitGroupRun(app, store.Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: parent}) {
+				admitted++
+			}
+		}
+		if admitted != test.admitted {
+			t.Errorf("%s: expected %d runs admitted, got %d", test.name, test.admitted, admitted)
+		}
+	}
+
+	if len(store.AggregationTaskQueue) != 5 {
+		t.Fatalf("expected the 5 skipped runs to be aggregated, got %d", len(store.AggregationTaskQueue))
+	}
+	skipped := <-store.AggregationTaskQueue
+	if skipped.Schedule.Status != store.Skipped || !strings.Contains(skipped.Schedule.ErrorMessage, "testGroup") {
+		t.Errorf("unexpected skipped run %+v", skipped.Schedule)
+	}
+}
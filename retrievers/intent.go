@@ -0,0 +1,170 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package retrievers
+
+import (
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/replication"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Claims of the intent log which are not the outcome of a recovery
+const (
+	intentClaimed  = "claimed"
+	intentFailed   = "failed"
+	intentReleased = "released"
+)
+
+// intentLog tells whether the schedules are claimed in the intent log before they are dispatched
+func (s ScheduleRetriever) intentLog() bool {
+	return s.config != nil && s.config.IntentLog
+}
+
+// intentTTL returns the TTL of a claim: it is not read anymore once its minute is older than the grace and the
+// lookback of a starting poller
+func (s ScheduleRetriever) intentTTL() int {
+	return s.config.IntentGraceSeconds + (s.config.IntentLookbackMinutes+1)*60
+}
+
+// claimSchedules claims the fires of the schedules of a poll in the intent log before their callbacks are
+// dispatched, so that the fires lost with the node are recovered by the next poller of the partition.
+// The schedules are fired unclaimed when the claims cannot be written.
+func (s ScheduleRetriever) claimSchedules(app store.App, schedules []store.Schedule) {
+	if !s.intentLog() || len(schedules) == 0 {
+		return
+	}
+
+	now := clock.Now()
+	intents := make([]store.FireIntent, len(schedules))
+	for i, schedule := range schedules {
+		intents[i] = store.NewFireIntent(schedule, s.node, 1, now)
+	}
+
+	if err := s.scheduleDao.ClaimSchedules(intents, s.intentTTL()); err != nil {
+		logger.Errorf("Claiming %d schedules of app: %s, partitionId: %d failed with error %s, firing them unclaimed", len(intents), app.AppId, schedules[0].PartitionId, err.Error())
+		s.recordIntents(app.AppId, intentFailed, len(intents))
+		return
+	}
+
+	for i := range schedules {
+		schedules[i].ClaimedAt = now.Unix()
+	}
+	s.recordIntents(app.AppId, intentClaimed, len(intents))
+}
+
+// releaseClaim removes the claim of a schedule whose callback was rejected, which is left to be reported as missed
+func (s ScheduleRetriever) releaseClaim(schedule store.Schedule) {
+	intent, ok := store.IntentOf(schedule)
+	if !ok {
+		return
+	}
+
+	if err := s.scheduleDao.AcknowledgeSchedules([]store.FireIntent{intent}); err != nil {
+		schedule.Logger().Errorf("Releasing the claim of schedule %s failed with error %s", schedule.ScheduleId.String(), err.Error())
+		return
+	}
+	s.recordIntents(schedule.AppId, intentReleased, 1)
+}
+
+// RecoverSchedules recovers the claims of the fires of the partition made within the minute of the claim bucket.
+// The schedules still without an outcome were read by a node which crashed before writing it, and are fired again as
+// reconciliations under a new claim, until they were claimed IntentMaxAttempts times. The claims of the schedules
+// whose outcome was written, or which are fired again, are dropped. A recovered schedule whose firing is turned off
+// by a feature flag is skipped or deferred instead of being fired again.
+func (s ScheduleRetriever) RecoverSchedules(appName string, partitionId int, claimBucket time.Time) error {
+	// a standby fires nothing, the claims are recovered by the poller of the partition once it is promoted
+	if !s.intentLog() || replication.Default().IsStandby() {
+		return nil
+	}
+
+	intents, err := s.scheduleDao.GetFireIntents(appName, partitionId, claimBucket)
+	if err != nil || len(intents) == 0 {
+		return err
+	}
+
+	app, err := s.clusterDao.GetApp(appName)
+	if err != nil {
+		return err
+	}
+
+	flags := store.GetFeatureFlags()
+	var done []store.FireIntent
+	for _, intent := range intents {
+		schedule, err := s.scheduleDao.GetEnrichedSchedule(intent.ScheduleId)
+		switch {
+		case err == gocql.ErrNotFound:
+			done = append(done, intent)
+			continue
+		case err != nil:
+			logger.Errorf("Recovering the claim of schedule %s failed with error %s", intent.ScheduleId.String(), err.Error())
+			continue
+		}
+
+		outcome := intent.Recover(schedule, s.config.IntentMaxAttempts)
+		if reason, off := flags.Disabled(app); off && outcome == store.IntentRecovered {
+			s.holdDisabledSchedule(app, schedule, flags, reason)
+		} else if outcome == store.IntentRecovered && !s.refire(app, schedule, intent) {
+			continue
+		}
+		if outcome == store.IntentAbandoned {
+			schedule.Logger().Errorf("Schedule %s claimed %d times by the intent log without an outcome, last by node %s, left to the reconciliation",
+				schedule.ScheduleId.String(), intent.Attempt, intent.Node)
+		}
+		done = append(done, intent)
+		s.recordIntents(appName, string(outcome), 1)
+	}
+
+	return s.scheduleDao.AcknowledgeSchedules(done)
+}
+
+// refire fires a recovered schedule again as a reconciliation under a new claim.
+// A schedule rejected by its callback keeps the new claim, to be recovered again.
+// Returns false if the new claim could not be written, the schedule is left unfired with its claim.
+func (s ScheduleRetriever) refire(app store.App, schedule store.Schedule, intent store.FireIntent) bool {
+	now := clock.Now()
+	claim := store.NewFireIntent(schedule, s.node, intent.Attempt+1, now)
+	if err := s.scheduleDao.ClaimSchedules([]store.FireIntent{claim}, s.intentTTL()); err != nil {
+		schedule.Logger().Errorf("Claiming recovered schedule %s failed with error %s", schedule.ScheduleId.String(), err.Error())
+		return false
+	}
+
+	schedule.Logger().Infof("Recovering schedule %s claimed by node %s at %d, attempt %d",
+		schedule.ScheduleId.String(), intent.Node, intent.ClaimedAt, claim.Attempt)
+
+	schedule.ClaimedAt = now.Unix()
+	if err := schedule.Callback.Invoke(store.ScheduleWrapper{Schedule: schedule, App: app, IsReconciliation: true}); err != nil {
+		s.recordRejectedCallback(schedule, err)
+	}
+	return true
+}
+
+func (s ScheduleRetriever) recordIntents(appId string, action string, count int) {
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.FireIntentCount, map[string]string{
+			"appId":  appId,
+			"action": action,
+		}, count)
+	}
+}
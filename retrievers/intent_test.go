@@ -0,0 +1,118 @@
+package retrievers
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/store"
+)
+
+// intentScheduleDao records the claims and the acknowledgements of the intent log
+type intentScheduleDao struct {
+	*pagedScheduleDao
+	intents      []store.FireIntent
+	schedules    map[gocql.UUID]store.Schedule
+	claimed      []store.FireIntent
+	acknowledged []store.FireIntent
+}
+
+func (d *intentScheduleDao) ClaimSchedules(intents []store.FireIntent, ttl int) error {
+	d.claimed = append(d.claimed, intents...)
+	return nil
+}
+
+func (d *intentScheduleDao) AcknowledgeSchedules(intents []store.FireIntent) error {
+	d.acknowledged = append(d.acknowledged, intents...)
+	return nil
+}
+
+func (d *intentScheduleDao) GetFireIntents(appId string, partitionId int, claimBucket time.Time) ([]store.FireIntent, error) {
+	return d.intents, nil
+}
+
+func (d *intentScheduleDao) GetEnrichedSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	schedule, ok := d.schedules[uuid]
+	if !ok {
+		return store.Schedule{}, gocql.ErrNotFound
+	}
+	return schedule, nil
+}
+
+func TestScheduleRetriever_ClaimsSchedulesBeforeDispatch(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	bucket := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	retriever, paged := setupRetriever(testRows(bucket, 5), 4)
+	scheduleDao := &intentScheduleDao{pagedScheduleDao: paged}
+	retriever.scheduleDao = scheduleDao
+	retriever.config.IntentLog = true
+	retriever.node = "127.0.0.1:9091"
+	close(release)
+
+	if err := retriever.GetSchedules("test", 0, bucket); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(invoked) != 5 || len(scheduleDao.claimed) != 5 {
+		t.Fatalf("expected the 5 schedules to be claimed and dispatched, got %d claimed and %d dispatched", len(scheduleDao.claimed), len(invoked))
+	}
+	for _, intent := range scheduleDao.claimed {
+		if intent.Node != "127.0.0.1:9091" || intent.Attempt != 1 || intent.ClaimedAt == 0 {
+			t.Errorf("unexpected claim %+v", intent)
+		}
+	}
+}
+
+func TestScheduleRetriever_RecoverSchedules(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	retriever, paged := setupRetriever(nil, 4)
+	close(release)
+
+	claimedAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC).Unix()
+	fired, lost, exhausted, deleted := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	scheduleDao := &intentScheduleDao{
+		pagedScheduleDao: paged,
+		intents: []store.FireIntent{
+			{AppId: "test", ScheduleId: fired, Node: "crashed", Attempt: 1, ClaimedAt: claimedAt},
+			{AppId: "test", ScheduleId: lost, Node: "crashed", Attempt: 1, ClaimedAt: claimedAt},
+			{AppId: "test", ScheduleId: exhausted, Node: "crashed", Attempt: 3, ClaimedAt: claimedAt},
+			{AppId: "test", ScheduleId: deleted, Node: "crashed", Attempt: 1, ClaimedAt: claimedAt},
+		},
+		schedules: map[gocql.UUID]store.Schedule{
+			fired:     {ScheduleId: fired, AppId: "test", Payload: "fired", Status: store.Success, Callback: &recordingCallback{}},
+			lost:      {ScheduleId: lost, AppId: "test", Payload: "lost", Status: store.Miss, Callback: &recordingCallback{}},
+			exhausted: {ScheduleId: exhausted, AppId: "test", Payload: "exhausted", Status: store.Miss, Callback: &recordingCallback{}},
+		},
+	}
+	retriever.scheduleDao = scheduleDao
+	retriever.config.IntentLog = true
+	retriever.config.IntentMaxAttempts = 3
+	retriever.node = "127.0.0.1:9091"
+
+	if err := retriever.RecoverSchedules("test", 0, time.Unix(claimedAt, 0)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(invoked) != 1 || invoked[0] != "lost" {
+		t.Errorf("expected only the schedule without an outcome to fire again, got %v", invoked)
+	}
+	if len(scheduleDao.claimed) != 1 || scheduleDao.claimed[0].ScheduleId != lost || scheduleDao.claimed[0].Attempt != 2 ||
+		scheduleDao.claimed[0].Node != "127.0.0.1:9091" {
+		t.Errorf("expected the recovered schedule to be claimed again by the node, got %+v", scheduleDao.claimed)
+	}
+
+	var acknowledged []string
+	for _, intent := range scheduleDao.acknowledged {
+		acknowledged = append(acknowledged, intent.ScheduleId.String())
+	}
+	sort.Strings(acknowledged)
+	want := []string{fired.String(), lost.String(), exhausted.String(), deleted.String()}
+	sort.Strings(want)
+	if len(acknowledged) != len(want) {
+		t.Fatalf("expected all the recovered claims to be dropped, got %v", acknowledged)
+	}
+	for i := range want {
+		if acknowledged[i] != want[i] {
+			t.Errorf("expected all the recovered claims to be dropped, got %v", acknowledged)
+		}
+	}
+}
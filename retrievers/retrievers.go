@@ -45,7 +45,15 @@ func (retriever Retrievers) Get(app string) retrieveriface.Retriever {
 func InitRetrievers(conf *c.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor p.Monitor) Retrievers {
 	cronApp := conf.CronConfig.App
 	return Retrievers{
-		_default: ScheduleRetriever{config: &conf.Poller, clusterDao: clusterDao, scheduleDao: scheduleDao, monitor: monitor},
-		cronApp:  CronRetriever{scheduleDao: scheduleDao, cronConfig: &conf.CronConfig, monitor: monitor},
+		_default: ScheduleRetriever{config: &conf.Poller, blackout: &conf.BlackoutConfig, shedding: &conf.SheddingConfig, clusterDao: clusterDao, scheduleDao: scheduleDao, monitor: monitor, node: conf.Cluster.Address},
+		cronApp: CronRetriever{
+			clusterDao:      clusterDao,
+			scheduleDao:     scheduleDao,
+			cronConfig:      &conf.CronConfig,
+			retentionConfig: &conf.RetentionConfig,
+			parkingConfig:   &conf.ParkingConfig,
+			appConfig:       &conf.AppLevelConfiguration,
+			monitor:         monitor,
+		},
 	}
 }
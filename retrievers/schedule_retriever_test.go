@@ -0,0 +1,368 @@
+package retrievers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/store"
+)
+
+const testCallbackType = "retriever_test"
+
+// recordingCallback records the payloads of the invoked schedules and blocks until released
+type recordingCallback struct {
+	Type string `json:"type"`
+}
+
+var (
+	invokedMu sync.Mutex
+	invoked   []string
+	release   chan struct{}
+)
+
+func (c *recordingCallback) GetType() string {
+	return testCallbackType
+}
+
+func (c *recordingCallback) GetDetails() (string, error) {
+	return "{}", nil
+}
+
+func (c *recordingCallback) Marshal(m map[string]interface{}) error {
+	c.Type = testCallbackType
+	return nil
+}
+
+func (c *recordingCallback) Validate() error {
+	return nil
+}
+
+func (c *recordingCallback) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &struct{}{})
+}
+
+func (c *recordingCallback) Invoke(wrapper store.ScheduleWrapper) error {
+	<-release
+	invokedMu.Lock()
+	defer invokedMu.Unlock()
+	invoked = append(invoked, wrapper.Schedule.Payload)
+	return nil
+}
+
+// pagedIter returns a page of rows and counts the rows read
+type pagedIter struct {
+	rows      []map[string]interface{}
+	pageState []byte
+	read      *int32
+}
+
+func (i *pagedIter) Close() error             { return nil }
+func (i *pagedIter) Scan(...interface{}) bool { return false }
+func (i *pagedIter) PageState() []byte        { return i.pageState }
+func (i *pagedIter) MapScan(m map[string]interface{}) bool {
+	if len(i.rows) == 0 {
+		return false
+	}
+	for key, value := range i.rows[0] {
+		m[key] = value
+	}
+	i.rows = i.rows[1:]
+	atomic.AddInt32(i.read, 1)
+	return true
+}
+
+type pagedScheduleDao struct {
+	dao.DummyScheduleDaoImpl
+	rows     []map[string]interface{}
+	pageSize int
+	read     int32
+}
+
+func (d *pagedScheduleDao) GetSchedulesForEntity(appId string, partitionId int, timeBucket time.Time, pageState []byte) db_wrapper.IterInterface {
+	start := 0
+	if len(pageState) > 0 {
+		start, _ = strconv.Atoi(string(pageState))
+	}
+
+	end := start + d.pageSize
+	var next []byte
+	if end < len(d.rows) {
+		next = []byte(strconv.Itoa(end))
+	} else {
+		end = len(d.rows)
+	}
+	return &pagedIter{rows: d.rows[start:end], pageState: next, read: &d.read}
+}
+
+func testRows(bucket time.Time, count int) []map[string]interface{} {
+	var rows []map[string]interface{}
+	for i := 0; i < count; i++ {
+		rows = append(rows, map[string]interface{}{
+			"app_id":              "test",
+			"partition_id":        0,
+			"callback_type":       testCallbackType,
+			"payload":             strconv.Itoa(i),
+			"schedule_time_group": bucket,
+			"schedule_time":       bucket.Add(time.Duration(i) * time.Second),
+			"schedule_id":         gocql.TimeUUID(),
+		})
+	}
+	return rows
+}
+
+func setupRetriever(rows []map[string]interface{}, bufferSize int) (ScheduleRetriever, *pagedScheduleDao) {
+	store.Registry[testCallbackType] = func() store.Callback { return &recordingCallback{} }
+	invoked = nil
+	release = make(chan struct{})
+
+	scheduleDao := &pagedScheduleDao{rows: rows, pageSize: 3}
+	return ScheduleRetriever{
+		clusterDao:  dao.DummyClusterDaoImpl{},
+		scheduleDao: scheduleDao,
+		config:      &conf.PollerConfig{MaxQueryLimit: 10, StreamBufferSize: bufferSize},
+	}, scheduleDao
+}
+
+func TestScheduleRetriever_GetSchedulesBoundsReadAhead(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	bucket := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	retriever, scheduleDao := setupRetriever(testRows(bucket, 20), 2)
+
+	result := make(chan error, 1)
+	go func() { result <- retriever.GetSchedules("test", 0, bucket) }()
+
+	// with the callbacks blocked, the reader stops once the buffer and the batch being dispatched are full
+	time.Sleep(100 * time.Millisecond)
+	if read := atomic.LoadInt32(&scheduleDao.read); read > 7 {
+		t.Errorf("expected at most 7 schedules read ahead of blocked callbacks, got %d", read)
+	}
+
+	close(release)
+	if err := <-result; err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(invoked) != 20 || scheduleDao.read != 20 {
+		t.Errorf("expected all the 20 schedules to be read and dispatched, got %d read and %d dispatched", scheduleDao.read, len(invoked))
+	}
+}
+
+func TestScheduleRetriever_GetSchedulesInWindow(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	bucket := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	retriever, _ := setupRetriever(testRows(bucket, 10), 4)
+	close(release)
+
+	if err := retriever.GetSchedulesInWindow("test", 0, bucket, bucket.Add(2*time.Second), bucket.Add(5*time.Second)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(invoked) != 3 || invoked[0] != "2" || invoked[2] != "4" {
+		t.Errorf("expected the schedules due within the window only, got %v", invoked)
+	}
+}
+
+func TestScheduleRetriever_ShedsOverdueLowPriorityCallbacks(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	aggregation := store.AggregationTaskQueue
+	defer func() { store.AggregationTaskQueue = aggregation }()
+
+	for _, policy := range []string{"drop", "defer"} {
+		bucket := time.Now().Add(-time.Hour).Truncate(time.Minute)
+		rows := testRows(bucket, 3)
+		for i, priority := range []string{"low", "normal", "high"} {
+			rows[i]["priority"] = priority
+		}
+
+		retriever, _ := setupRetriever(rows, 4)
+		retriever.shedding = &conf.SheddingConfig{Enabled: true, OverdueSeconds: 600, Policy: policy}
+		store.AggregationTaskQueue = make(chan store.ScheduleWrapper, 10)
+		close(release)
+
+		if err := retriever.GetSchedules("test", 0, bucket); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		sort.Strings(invoked)
+		if len(invoked) != 2 || invoked[0] != "1" || invoked[1] != "2" {
+			t.Errorf("%s: expected the high and normal priority callbacks to fire, got %v", policy, invoked)
+		}
+		if len(store.AggregationTaskQueue) != 1 {
+			t.Fatalf("%s: expected the low priority run to be recorded, got %d runs", policy, len(store.AggregationTaskQueue))
+		}
+		if shed := <-store.AggregationTaskQueue; shed.Schedule.Payload != "0" || shed.Schedule.Status != store.Skipped ||
+			!strings.Contains(shed.Schedule.ErrorMessage, map[string]string{"drop": "dropped", "defer": "deferred as schedule"}[policy]) {
+			t.Errorf("%s: unexpected shed run %+v", policy, shed.Schedule)
+		}
+	}
+}
+
+func TestScheduleRetriever_SkipsSchedulesTurnedOffByKillSwitch(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	aggregation := store.AggregationTaskQueue
+	defer func() { store.AggregationTaskQueue = aggregation }()
+	defer store.SetFeatureFlags(nil)
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	flags, err := store.NewFeatureFlags(conf.FeatureFlagConfig{Provider: "file", Path: path, RefreshSeconds: 10, KillSwitch: "firing"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	store.SetFeatureFlags(flags)
+
+	for _, on := range []bool{false, true} {
+		if err = ioutil.WriteFile(path, []byte(`{"firing": `+strconv.FormatBool(on)+`}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err = flags.Refresh(); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		bucket := time.Now().Add(-time.Minute).Truncate(time.Minute)
+		retriever, _ := setupRetriever(testRows(bucket, 3), 4)
+		store.AggregationTaskQueue = make(chan store.ScheduleWrapper, 10)
+		close(release)
+
+		if err = retriever.GetSchedules("test", 0, bucket); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if fired, skipped := len(invoked), len(store.AggregationTaskQueue); on && (fired != 3 || skipped != 0) || !on && (fired != 0 || skipped != 3) {
+			t.Fatalf("kill switch on %v: expected the schedules to fire only while it is on, got %d fired and %d skipped", on, fired, skipped)
+		}
+		if !on {
+			if run := <-store.AggregationTaskQueue; run.Schedule.Status != store.Skipped || !strings.Contains(run.Schedule.ErrorMessage, "kill switch firing") {
+				t.Errorf("unexpected skipped run %+v", run.Schedule)
+			}
+		}
+	}
+}
+
+func TestScheduleRetriever_DefersSchedulesTurnedOffUntilMaxDefer(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	aggregation := store.AggregationTaskQueue
+	defer func() { store.AggregationTaskQueue = aggregation }()
+	defer store.SetFeatureFlags(nil)
+
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := ioutil.WriteFile(path, []byte(`{"firing": false}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	flags, err := store.NewFeatureFlags(conf.FeatureFlagConfig{Provider: "file", Path: path, RefreshSeconds: 10, KillSwitch: "firing",
+		Policy: "defer", DeferSeconds: 300, MaxDeferSeconds: 3600})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err = flags.Refresh(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	store.SetFeatureFlags(flags)
+
+	bucket := time.Now().Add(-time.Minute).Truncate(time.Minute)
+	rows := testRows(bucket, 2)
+	rows[1]["deferred_from"] = bucket.Add(-2 * time.Hour)
+	retriever, _ := setupRetriever(rows, 4)
+	store.AggregationTaskQueue = make(chan store.ScheduleWrapper, 10)
+	close(release)
+
+	if err = retriever.GetSchedules("test", 0, bucket); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(invoked) != 0 || len(store.AggregationTaskQueue) != 2 {
+		t.Fatalf("expected both runs held, got %d fired and %d held", len(invoked), len(store.AggregationTaskQueue))
+	}
+	for i := 0; i < 2; i++ {
+		run := (<-store.AggregationTaskQueue).Schedule
+		expected := map[string]string{"0": "deferred as schedule", "1": "skipped after being deferred for 1h0m0s"}[run.Payload]
+		if run.Status != store.Skipped || !strings.Contains(run.ErrorMessage, expected) {
+			t.Errorf("run %s: expected %q, got %+v", run.Payload, expected, run)
+		}
+	}
+}
+
+func TestScheduleRetriever_PrefetchSchedules(t *testing.T) {
+	defer delete(store.Registry, testCallbackType)
+	bucket := time.Unix(time.Now().Unix()+2, 0)
+	retriever, scheduleDao := setupRetriever(testRows(bucket, 3), 4)
+	close(release)
+
+	result := make(chan error, 1)
+	go func() { result <- retriever.PrefetchSchedules("test", 0, bucket) }()
+
+	// the bucket is read right away and held until it begins
+	time.Sleep(200 * time.Millisecond)
+	invokedMu.Lock()
+	early := len(invoked)
+	invokedMu.Unlock()
+	if read := atomic.LoadInt32(&scheduleDao.read); read != 3 || early != 0 {
+		t.Errorf("expected the 3 schedules read ahead without firing, got %d read and %d fired", read, early)
+	}
+
+	if err := <-result; err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if time.Now().Before(bucket) || len(invoked) != 3 {
+		t.Errorf("expected the 3 schedules to fire once the bucket began, got %v at %v", invoked, time.Now())
+	}
+}
+
+func scheduleTime(schedule store.Schedule) int64 {
+	return schedule.ScheduleTime
+}
+
+func TestReleaseWhenDue(t *testing.T) {
+	now := time.Now()
+	schedules := make(chan store.Schedule, 4)
+	schedules <- store.Schedule{Payload: "later", ScheduleTime: now.Unix() + 2}
+	schedules <- store.Schedule{Payload: "overdue", ScheduleTime: now.Unix() - 10}
+	schedules <- store.Schedule{Payload: "next", ScheduleTime: now.Unix() + 1}
+	close(schedules)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	var released []string
+	for schedule := range releaseWhenDue(schedules, done, 10, scheduleTime, func() { t.Errorf("expected the pending schedules to stay below the limit") }) {
+		if late := time.Since(time.Unix(schedule.ScheduleTime, 0)); schedule.Payload != "overdue" && (late < 0 || late > time.Second) {
+			t.Errorf("expected %s to be released within a second of being due, got %s", schedule.Payload, late)
+		}
+		released = append(released, schedule.Payload)
+	}
+
+	if len(released) != 3 || released[0] != "overdue" || released[1] != "next" || released[2] != "later" {
+		t.Errorf("expected the schedules to be released as they become due, got %v", released)
+	}
+}
+
+func TestReleaseWhenDue_MaxPending(t *testing.T) {
+	now := time.Now()
+	schedules := make(chan store.Schedule, 2)
+	schedules <- store.Schedule{Payload: "next", ScheduleTime: now.Unix() + 1}
+	schedules <- store.Schedule{Payload: "overdue", ScheduleTime: now.Unix() - 10}
+	close(schedules)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	full := 0
+	var released []string
+	for schedule := range releaseWhenDue(schedules, done, 1, scheduleTime, func() { full++ }) {
+		released = append(released, schedule.Payload)
+	}
+
+	// the overdue schedule is only read once the held one is released
+	if len(released) != 2 || released[0] != "next" || released[1] != "overdue" {
+		t.Errorf("expected the stream to be read once the held schedule is due, got %v", released)
+	}
+	if full != 1 {
+		t.Errorf("expected the limit to be reported once, got %d", full)
+	}
+}
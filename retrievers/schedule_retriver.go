@@ -20,28 +20,76 @@
 package retrievers
 
 import (
+	"container/heap"
+	"context"
+	"fmt"
 	"runtime/debug"
 	"strconv"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/archive"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/logger"
 	p "github.com/myntra/goscheduler/monitoring"
+	"github.com/myntra/goscheduler/replication"
 	"github.com/myntra/goscheduler/store"
 )
 
 const BatchSize = 50
 
+// defaultStreamBufferSize is the number of schedules read ahead of the callbacks by a poll when not configured
+const defaultStreamBufferSize = 1000
+
+// defaultMaxPendingSchedules is the number of schedules a second precision poll holds until they are due when not
+// configured
+const defaultMaxPendingSchedules = 10000
+
 type ScheduleRetriever struct {
 	clusterDao  dao.ClusterDao
 	scheduleDao dao.ScheduleDao
 	monitor     p.Monitor
 	config      *conf.PollerConfig
+	blackout    *conf.BlackoutConfig
+	shedding    *conf.SheddingConfig
+	node        string // Address of the node, recorded in the claims of the intent log
 }
 
 func (s ScheduleRetriever) GetSchedules(appName string, partitionId int, timeBucket time.Time) (err error) {
+	return s.getSchedules(appName, partitionId, timeBucket, nil, false)
+}
+
+// PrefetchSchedules reads the schedules of a time bucket which has not begun yet and holds them until the bucket
+// begins, or until their second with second precision, so that the read is done ahead of the firing.
+func (s ScheduleRetriever) PrefetchSchedules(appName string, partitionId int, timeBucket time.Time) error {
+	return s.getSchedules(appName, partitionId, timeBucket, nil, true)
+}
+
+// GetSchedulesInWindow fires the schedules of the time bucket whose schedule time is within [from, to).
+// Used by the adaptive poller, which polls a busy bucket several times a minute.
+func (s ScheduleRetriever) GetSchedulesInWindow(appName string, partitionId int, timeBucket time.Time, from time.Time, to time.Time) error {
+	return s.getSchedules(appName, partitionId, timeBucket, func(schedule store.Schedule) bool {
+		return schedule.ScheduleTime >= from.Unix() && schedule.ScheduleTime < to.Unix()
+	}, false)
+}
+
+// CountSchedules returns the number of schedules in each of the time buckets of the partition
+func (s ScheduleRetriever) CountSchedules(appName string, partitionId int, timeBuckets []time.Time) ([]int, error) {
+	return s.scheduleDao.CountSchedulesInBuckets(appName, partitionId, timeBuckets)
+}
+
+// getSchedules fires the schedules of the time bucket accepted by the filter, all of them when the filter is nil.
+// A prefetched bucket is held until it begins.
+func (s ScheduleRetriever) getSchedules(appName string, partitionId int, timeBucket time.Time, filter func(store.Schedule) bool, prefetch bool) (err error) {
+	// a standby keeps its copy of the schedules without firing them until it is promoted
+	if replication.Default().IsStandby() {
+		return nil
+	}
+
 	start := time.Now()
 
 	defer func(start time.Time) {
@@ -51,7 +99,7 @@ func (s ScheduleRetriever) GetSchedules(appName string, partitionId int, timeBuc
 			s.monitor.RecordTiming(constants.GetSchedulesByEntityDuration, map[string]string{"appId": appName, "partitionId": strconv.Itoa(partitionId)}, duration)
 		}
 		if r := recover(); r != nil {
-			glog.Errorf("Recovered in ScheduleRetrieverImplCassandra from error %s with stacktrace %s", r, string(debug.Stack()))
+			logger.Errorf("Recovered in ScheduleRetrieverImplCassandra from error %s with stacktrace %s", r, string(debug.Stack()))
 		}
 	}(start)
 
@@ -60,37 +108,73 @@ func (s ScheduleRetriever) GetSchedules(appName string, partitionId int, timeBuc
 		return err
 	}
 
+	// The schedules are streamed from the reader to the callbacks through a bounded buffer, so that a page and the
+	// buffer are all that is held in memory however many schedules are due in the bucket. The next page is only
+	// read once the callback workers have made room for it.
+	schedules := make(chan store.Schedule, s.streamBufferSize())
+	done := make(chan struct{})
+	defer close(done)
+
+	result := make(chan error, 1)
+	go func() {
+		defer close(schedules)
+		result <- s.readSchedules(appName, partitionId, timeBucket, filter, schedules, done)
+	}()
+
+	var due <-chan store.Schedule = schedules
+	if s.config.SecondPrecision || prefetch {
+		due = releaseWhenDue(schedules, done, s.maxPendingSchedules(), s.dueAt(timeBucket), func() {
+			s.recordPendingSchedulesFull(appName, partitionId)
+		})
+	}
+
+	totalSchedules := s.dispatchSchedules(app, due)
+	if err = <-result; err != nil {
+		return err
+	}
+
+	diagnostics.Default().RecordPoll(appName, partitionId, timeBucket, totalSchedules, clock.Now())
+	archive.Default().Polled(appName, partitionId, timeBucket)
+	return nil
+}
+
+// readSchedules reads the schedules of the time bucket page by page and sends the ones accepted by the filter,
+// until the bucket is exhausted, the max query limit is reached or the dispatch is done.
+func (s ScheduleRetriever) readSchedules(appName string, partitionId int, timeBucket time.Time, filter func(store.Schedule) bool, out chan<- store.Schedule, done <-chan struct{}) error {
 	pageState := []byte(nil)
 	queryCount := 0
-	totalSchedules := 0
 
 	for {
 		sch := store.Schedule{}
 		_map := make(map[string]interface{})
 		iter := s.scheduleDao.GetSchedulesForEntity(appName, partitionId, timeBucket, pageState)
-		scheduleCount := 0
 
 		for iter.MapScan(_map) {
 			if err := sch.CreateScheduleFromCassandraMap(_map); err != nil {
-				glog.Infof("Error while forming schedule from cassandra map: %+v, error: %s", _map, err.Error())
+				logger.Infof("Error while forming schedule from cassandra map: %+v, error: %s", _map, err.Error())
 				iter.Close()
 				return err
 			}
 
-			glog.V(constants.INFO).Infof("Got schedule: %+v, pageState: %+v", sch, iter.PageState())
-			sch.Callback.Invoke(store.ScheduleWrapper{Schedule: sch, App: app, IsReconciliation: false})
+			sch.Logger().Debugf("Got schedule: %+v, pageState: %+v", sch, iter.PageState())
+			if filter == nil || filter(sch) {
+				select {
+				case out <- sch:
+				case <-done:
+					iter.Close()
+					return nil
+				}
+			}
 
 			_map = make(map[string]interface{})
 			sch = store.Schedule{}
-			scheduleCount++
-			totalSchedules++
 		}
 
 		pageState = iter.PageState()
 		queryCount++
 
-		if err = iter.Close(); err != nil {
-			glog.Errorf("Error: %s while fetching schedules for app: %s, partitionId: %d, timeBucket: %v", err.Error(), appName, partitionId, timeBucket)
+		if err := iter.Close(); err != nil {
+			logger.Errorf("Error: %s while fetching schedules for app: %s, partitionId: %d, timeBucket: %v", err.Error(), appName, partitionId, timeBucket)
 			return err
 		}
 
@@ -100,13 +184,379 @@ func (s ScheduleRetriever) GetSchedules(appName string, partitionId int, timeBuc
 					"appId":       appName,
 					"partitionId": strconv.Itoa(partitionId),
 				}, 1)
-				glog.Errorf("Query count exceeded for app: %s, partitionId: %d, timeBucket: %v, with max Query limit: %v", appName, partitionId, timeBucket, s.config.MaxQueryLimit)
+				logger.Errorf("Query count exceeded for app: %s, partitionId: %d, timeBucket: %v, with max Query limit: %v", appName, partitionId, timeBucket, s.config.MaxQueryLimit)
 			}
-			break
+			return nil
 		}
 	}
+}
 
-	return nil
+// dueAt returns the epoch second the schedules of the time bucket are released at: their own second with second
+// precision, the beginning of the bucket otherwise
+func (s ScheduleRetriever) dueAt(timeBucket time.Time) func(store.Schedule) int64 {
+	if s.config.SecondPrecision {
+		return func(schedule store.Schedule) int64 {
+			return schedule.ScheduleTime
+		}
+	}
+	return func(store.Schedule) int64 {
+		return timeBucket.Unix()
+	}
+}
+
+// releaseWhenDue passes the streamed schedules on as they become due at the second returned by dueAt, holding the
+// ones due later until then. Schedules which are already due are passed on right away.
+// At most maxPending schedules are held: once reached the stream is not read until one of them is due, which pauses
+// the reading of the bucket, and full is called once. The schedules read after the pause fire late if already due.
+func releaseWhenDue(schedules <-chan store.Schedule, done <-chan struct{}, maxPending int, dueAt func(store.Schedule) int64, full func()) <-chan store.Schedule {
+	out := make(chan store.Schedule, cap(schedules))
+
+	go func() {
+		defer close(out)
+		pending := &pendingSchedules{}
+		reported := false
+
+		for {
+			for pending.Len() > 0 && dueAt((*pending)[0]) <= clock.Now().Unix() {
+				select {
+				case out <- heap.Pop(pending).(store.Schedule):
+				case <-done:
+					return
+				}
+			}
+			if schedules == nil && pending.Len() == 0 {
+				return
+			}
+
+			var timer clock.Timer
+			var wake <-chan time.Time
+			if pending.Len() > 0 {
+				timer = clock.NewTimer(clock.Until(time.Unix(dueAt((*pending)[0]), 0)))
+				wake = timer.C()
+			}
+
+			in := schedules
+			if pending.Len() >= maxPending {
+				in = nil
+				if !reported {
+					reported = true
+					full()
+				}
+			}
+
+			select {
+			case schedule, ok := <-in:
+				if ok {
+					heap.Push(pending, schedule)
+				} else {
+					schedules = nil
+				}
+			case <-wake:
+			case <-done:
+				return
+			}
+
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+	}()
+
+	return out
+}
+
+// pendingSchedules is a heap of the schedules held until they are due, the earliest first
+type pendingSchedules []store.Schedule
+
+func (p pendingSchedules) Len() int            { return len(p) }
+func (p pendingSchedules) Less(i, j int) bool  { return p[i].ScheduleTime < p[j].ScheduleTime }
+func (p pendingSchedules) Swap(i, j int)       { p[i], p[j] = p[j], p[i] }
+func (p *pendingSchedules) Push(x interface{}) { *p = append(*p, x.(store.Schedule)) }
+func (p *pendingSchedules) Pop() interface{} {
+	old := *p
+	n := len(old)
+	x := old[n-1]
+	*p = old[:n-1]
+	return x
+}
+
+// dispatchSchedules invokes the callbacks of the streamed schedules and returns their number.
+// The schedules waiting in the buffer are dispatched together, highest priority first.
+// A schedule rejected by the full queue of its callback type is left without a run, to be reported as missed.
+// With the intent log the admitted schedules are claimed before any of them is dispatched.
+// A schedule whose firing is turned off by a feature flag, one due within a blackout window which is not over yet and
+// one shed under overload are not fired, their runs are skipped or deferred.
+// A run of a group over its run limit is skipped, and a run due while the previous run of its recurring schedule is
+// still running follows the concurrency policy of the schedule.
+func (s ScheduleRetriever) dispatchSchedules(app store.App, schedules <-chan store.Schedule) int {
+	dispatched := 0
+	blackout := s.blackoutOf(app)
+	flags := store.GetFeatureFlags()
+	var shedding conf.SheddingConfig
+	if s.shedding != nil {
+		shedding = *s.shedding
+	}
+	batch := make([]store.Schedule, 0, cap(schedules)+1)
+
+	for schedule := range schedules {
+		batch = append(batch[:0], schedule)
+	drain:
+		for len(batch) < cap(batch) {
+			select {
+			case next, ok := <-schedules:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+
+		store.SortByPriority(batch)
+		admitted := make([]store.Schedule, 0, len(batch))
+		for _, sch := range batch {
+			if reason, off := flags.Disabled(app); off {
+				s.holdDisabledSchedule(app, sch, flags, reason)
+				continue
+			}
+			if end, policy, ok := blackout(sch); ok {
+				s.holdSchedule(app, sch, end, policy)
+				continue
+			}
+			if shed, ok := store.ShouldShed(shedding, app, sch, store.CallbackBacklog(), clock.Now()); ok {
+				s.shedSchedule(app, sch, shed, shedding.DeferSeconds)
+				continue
+			}
+			if !s.admitGroupRun(app, sch) {
+				continue
+			}
+			if !s.admitRun(app, sch) {
+				continue
+			}
+			admitted = append(admitted, sch)
+		}
+
+		s.claimSchedules(app, admitted)
+		for _, sch := range admitted {
+			if err := sch.Callback.Invoke(store.ScheduleWrapper{Schedule: sch, App: app, IsReconciliation: false}); err != nil {
+				store.Running().Finish(sch)
+				s.releaseClaim(sch)
+				s.recordRejectedCallback(sch, err)
+			}
+		}
+		dispatched += len(batch)
+	}
+
+	return dispatched
+}
+
+// blackoutOf returns a function telling whether a schedule of the app is due within a blackout window which is not
+// over yet, along with the end and the policy of the window
+func (s ScheduleRetriever) blackoutOf(app store.App) func(store.Schedule) (time.Time, store.BlackoutPolicy, bool) {
+	var global conf.BlackoutConfig
+	if s.blackout != nil {
+		global = *s.blackout
+	}
+	if len(global.Windows) == 0 && (app.Configuration.Blackout == nil || len(app.Configuration.Blackout.Windows) == 0) {
+		return func(store.Schedule) (time.Time, store.BlackoutPolicy, bool) {
+			return time.Time{}, "", false
+		}
+	}
+
+	type blackout struct {
+		end    time.Time
+		policy store.BlackoutPolicy
+		ok     bool
+	}
+	blackouts := make(map[int64]blackout)
+
+	return func(schedule store.Schedule) (time.Time, store.BlackoutPolicy, bool) {
+		b, found := blackouts[schedule.ScheduleTime]
+		if !found {
+			b.end, b.policy, b.ok = store.ActiveBlackout(app, global, time.Unix(schedule.ScheduleTime, 0))
+			blackouts[schedule.ScheduleTime] = b
+		}
+		return b.end, b.policy, b.ok && clock.Now().Before(b.end)
+	}
+}
+
+// holdSchedule keeps a schedule due within a blackout window from firing and records its run as skipped.
+// With the queue policy the run is deferred to the end of the window.
+func (s ScheduleRetriever) holdSchedule(app store.App, schedule store.Schedule, end time.Time, policy store.BlackoutPolicy) {
+	reason := fmt.Sprintf("held within a blackout window ending at %s", end.Format(time.RFC3339))
+	schedule.Status = store.Skipped
+	schedule.ErrorMessage = reason + ", skipped"
+
+	if policy == store.QueueBlackoutRuns {
+		schedule.ErrorMessage = s.deferSchedule(app, schedule, reason, end.Sub(clock.Now()))
+	}
+
+	schedule.Logger().Infof("Schedule %s held by a blackout window: %s", schedule.ScheduleId.String(), schedule.ErrorMessage)
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.BlackoutScheduleCount, map[string]string{
+			"appId":  schedule.AppId,
+			"policy": string(policy),
+		}, 1)
+	}
+	store.AggregationTaskQueue <- store.ScheduleWrapper{Schedule: schedule, App: app}
+}
+
+// holdDisabledSchedule keeps a schedule whose firing is turned off by a feature flag from firing and records its run as
+// skipped. With the defer policy the run is deferred by the defer delay, unless that defers it past the max defer.
+func (s ScheduleRetriever) holdDisabledSchedule(app store.App, schedule store.Schedule, flags *store.FeatureFlags, reason string) {
+	schedule.Status = store.Skipped
+	schedule.ErrorMessage = reason + ", skipped"
+
+	policy := flags.Policy()
+	if policy == store.DeferDisabledRuns {
+		if flags.CanDefer(schedule) {
+			schedule.ErrorMessage = s.deferSchedule(app, schedule, reason, flags.DeferDelay())
+		} else {
+			schedule.ErrorMessage = fmt.Sprintf("%s, skipped after being deferred for %s", reason, flags.MaxDefer())
+		}
+	}
+
+	schedule.Logger().Infof("Schedule %s not fired: %s", schedule.ScheduleId.String(), schedule.ErrorMessage)
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.DisabledScheduleCount, map[string]string{
+			"appId":  schedule.AppId,
+			"policy": string(policy),
+		}, 1)
+	}
+	store.AggregationTaskQueue <- store.ScheduleWrapper{Schedule: schedule, App: app}
+}
+
+// minShedDeferSeconds keeps a deferred schedule out of the time bucket being fired
+const minShedDeferSeconds = 60
+
+// shedSchedule keeps the callback of a schedule shed under overload from firing and records its run as skipped.
+// Unless the policy drops it, the run is deferred by deferSeconds.
+func (s ScheduleRetriever) shedSchedule(app store.App, schedule store.Schedule, shed store.Shedding, deferSeconds int) {
+	schedule.Status = store.Skipped
+	schedule.ErrorMessage = shed.Reason + ", dropped"
+
+	if shed.Policy != store.DropShedRuns {
+		shed.Policy = store.DeferShedRuns
+		if deferSeconds < minShedDeferSeconds {
+			deferSeconds = minShedDeferSeconds
+		}
+		schedule.ErrorMessage = s.deferSchedule(app, schedule, shed.Reason, time.Duration(deferSeconds)*time.Second)
+	}
+
+	schedule.Logger().Infof("Schedule %s shed under overload: %s", schedule.ScheduleId.String(), schedule.ErrorMessage)
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.ShedScheduleCount, map[string]string{
+			"appId":    schedule.AppId,
+			"priority": string(schedule.GetPriority()),
+			"policy":   string(shed.Policy),
+		}, 1)
+	}
+	store.PublishEvent(store.ScheduleShed, schedule)
+	store.AggregationTaskQueue <- store.ScheduleWrapper{Schedule: schedule, App: app}
+}
+
+// deferSchedule creates a one time schedule like the one held from firing, due after the delay, which remembers when
+// the run was first due. A run deferred into a minute which may already be prefetched is due when the next one begins.
+// Returns the error message of the held run, the reason it was held followed by where its run was deferred to.
+func (s ScheduleRetriever) deferSchedule(app store.App, schedule store.Schedule, reason string, delay time.Duration) string {
+	now := clock.Now()
+	at := now.Add(delay)
+	if polled := s.config.LastPolledBucket(now); !at.Truncate(time.Minute).After(polled) {
+		at = polled.Add(time.Minute)
+	}
+
+	deferred := schedule.CloneAsOneTime(at)
+	deferred.ParentScheduleId = gocql.UUID{}
+	deferred.DeferredFrom = schedule.FirstDueAt()
+	deferred.SetFields(app)
+
+	if _, err := s.scheduleDao.CreateSchedule(context.Background(), deferred, app); err != nil {
+		schedule.Logger().Errorf("Deferring schedule %s %s failed with error %s", schedule.ScheduleId.String(), reason, err.Error())
+		return reason + ", deferring failed"
+	}
+
+	store.PublishEvent(store.ScheduleCreated, deferred)
+	return fmt.Sprintf("%s, deferred as schedule %s at %s", reason, deferred.ScheduleId.String(), at.Format(time.RFC3339))
+}
+
+// admitRun applies the concurrency policy of the recurring schedule of an http run due while the previous run of the
+// schedule is still running, and records the run as running unless it is skipped.
+// Returns false when the run is skipped.
+func (s ScheduleRetriever) admitRun(app store.App, run store.Schedule) bool {
+	if !store.SupportsConcurrencyPolicy(run.Callback) {
+		return true
+	}
+
+	running := store.Running()
+	previous, ok := running.Previous(run)
+	if !ok {
+		running.Start(run)
+		return true
+	}
+
+	// the policy is only looked up when the runs of the schedule overlap
+	policy := store.AllowConcurrentRuns
+	if parent, err := s.scheduleDao.GetSchedule(run.ParentScheduleId); err != nil {
+		run.Logger().Errorf("Fetching the concurrency policy of cron %s failed with error %s", run.ParentScheduleId.String(), err.Error())
+	} else if parent.ConcurrencyPolicy != "" {
+		policy = parent.ConcurrencyPolicy
+	}
+
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.ConcurrentRunCount, map[string]string{
+			"appId":  run.AppId,
+			"policy": string(policy),
+		}, 1)
+	}
+
+	switch policy {
+	case store.ForbidConcurrentRuns:
+		run.Status = store.Skipped
+		run.ErrorMessage = fmt.Sprintf("skipped as the previous run %s is still running", previous.String())
+		run.Logger().Infof("Schedule %s skipped by its concurrency policy: %s", run.ScheduleId.String(), run.ErrorMessage)
+		store.AggregationTaskQueue <- store.ScheduleWrapper{Schedule: run, App: app}
+		return false
+	case store.ReplaceConcurrentRuns:
+		run.Logger().Infof("Schedule %s replaces the previous run %s of cron %s", run.ScheduleId.String(), previous.String(), run.ParentScheduleId.String())
+		running.Replace(run)
+	}
+	running.Start(run)
+	return true
+}
+
+// recordRejectedCallback records a schedule whose callback could not be handed to a worker
+func (s ScheduleRetriever) recordRejectedCallback(schedule store.Schedule, err error) {
+	schedule.Logger().Errorf("Callback of type %s for schedule id %s was rejected with error %s",
+		schedule.GetCallBackType(), schedule.ScheduleId.String(), err.Error())
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.RejectedCallbackCount, map[string]string{
+			"appId":        schedule.AppId,
+			"callbackType": schedule.GetCallBackType(),
+		}, 1)
+	}
+}
+
+func (s ScheduleRetriever) maxPendingSchedules() int {
+	if s.config.MaxPendingSchedules <= 0 {
+		return defaultMaxPendingSchedules
+	}
+	return s.config.MaxPendingSchedules
+}
+
+// recordPendingSchedulesFull counts the second precision polls whose reading was paused by the held schedules
+func (s ScheduleRetriever) recordPendingSchedulesFull(appName string, partitionId int) {
+	logger.Infof("Second precision poll of app: %s, partitionId: %d holds %d schedules, pausing the read of the bucket", appName, partitionId, s.maxPendingSchedules())
+	if s.monitor != nil {
+		s.monitor.IncCounter(constants.PendingSchedulesFull, map[string]string{"appId": appName, "partitionId": strconv.Itoa(partitionId)}, 1)
+	}
+}
+
+func (s ScheduleRetriever) streamBufferSize() int {
+	if s.config.StreamBufferSize <= 0 {
+		return defaultStreamBufferSize
+	}
+	return s.config.StreamBufferSize
 }
 
 // Fetches data from DB for a given appId, partitionId, scheduleTimeGroup in paginated way
@@ -114,9 +564,13 @@ func (s ScheduleRetriever) GetSchedules(appName string, partitionId int, timeBuc
 // Return error if there is any error while querying DB or enriching them with status
 // TODO: Abort the queries with MaxQueries if we find any issues with the query execution
 func (s ScheduleRetriever) BulkAction(app store.App, partitionId int, scheduleTimeGroup time.Time, status []store.Status, actionType store.ActionType) error {
+	if replication.Default().IsStandby() {
+		return nil
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
-			glog.Errorf("Recovered in %s from error %+v with stacktrace %s", string(actionType), r, string(debug.Stack()))
+			logger.Errorf("Recovered in %s from error %+v with stacktrace %s", string(actionType), r, string(debug.Stack()))
 		}
 	}()
 
@@ -132,12 +586,12 @@ func (s ScheduleRetriever) BulkAction(app store.App, partitionId int, scheduleTi
 
 		for iter.MapScan(_map) {
 			if err := sch.CreateScheduleFromCassandraMap(_map); err != nil {
-				glog.Infof("Error while forming schedule from cassandra map: %+v, error: %s", _map, err.Error())
+				logger.Infof("Error while forming schedule from cassandra map: %+v, error: %s", _map, err.Error())
 				iter.Close()
 				return err
 			}
 
-			glog.V(constants.INFO).Infof("Got schedule: %+v, pageState: %+v", sch, iter.PageState())
+			sch.Logger().Debugf("Got schedule: %+v, pageState: %+v", sch, iter.PageState())
 
 			batch = append(batch, sch)
 			counter++
@@ -158,7 +612,7 @@ func (s ScheduleRetriever) BulkAction(app store.App, partitionId int, scheduleTi
 		pageState = iter.PageState()
 
 		if err = iter.Close(); err != nil {
-			glog.Errorf("Error: %s while making query for app: %s, partitionId: %d, scheduleTimeGroup: %+v",
+			logger.Errorf("Error: %s while making query for app: %s, partitionId: %d, scheduleTimeGroup: %+v",
 				err.Error(),
 				app.AppId,
 				partitionId,
@@ -187,7 +641,7 @@ func (s ScheduleRetriever) BulkAction(app store.App, partitionId int, scheduleTi
 func contains(status []store.Status, sch store.Schedule) bool {
 	for _, v := range status {
 		switch v {
-		case store.Success, store.Failure, store.Miss, store.Scheduled:
+		case store.Success, store.Failure, store.Overrun, store.Miss, store.Scheduled, store.Skipped:
 			if v == sch.Status {
 				return true
 			}
@@ -208,11 +662,11 @@ func (s ScheduleRetriever) actionIfRequired(app store.App, schedules []store.Sch
 	enrichedSchedules, err := s.scheduleDao.OptimizedEnrichSchedule(schedules)
 	// we already logged the error, so no need to log it
 	if err != nil {
-		glog.Errorf("Schedule enrichment failed for appId: %s, partitionId: %d with error %s", schedules[0].AppId, schedules[0].PartitionId, err.Error())
+		logger.Errorf("Schedule enrichment failed for appId: %s, partitionId: %d with error %s", schedules[0].AppId, schedules[0].PartitionId, err.Error())
 		return err
 	}
 
-	glog.V(constants.INFO).Infof("Enriched schedules: %+v", enrichedSchedules)
+	logger.Debugf("Enriched schedules: %+v", enrichedSchedules)
 
 	for _, sch := range enrichedSchedules {
 		if contains(status, sch) {
@@ -0,0 +1,569 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package rrule implements the subset of RFC 5545 recurrence rules which can be expressed with minute precision.
+//
+// Supported rule parts are FREQ (YEARLY, MONTHLY, WEEKLY, DAILY, HOURLY, MINUTELY), INTERVAL, COUNT, UNTIL,
+// BYMONTH, BYMONTHDAY, BYDAY, BYHOUR, BYMINUTE, BYSETPOS and WKST. The start of the recurrence (DTSTART) is
+// supplied separately while parsing the rule.
+package rrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency represents the FREQ rule part.
+type Frequency int
+
+const (
+	Yearly Frequency = iota
+	Monthly
+	Weekly
+	Daily
+	Hourly
+	Minutely
+)
+
+var frequencies = map[string]Frequency{
+	"YEARLY":   Yearly,
+	"MONTHLY":  Monthly,
+	"WEEKLY":   Weekly,
+	"DAILY":    Daily,
+	"HOURLY":   Hourly,
+	"MINUTELY": Minutely,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule parts which are valid in RFC 5545 but not supported.
+var unsupported = map[string]string{
+	"FREQ=SECONDLY": "SECONDLY frequency is not supported, schedules have minute precision",
+	"BYSECOND":      "BYSECOND is not supported, schedules have minute precision",
+	"BYWEEKNO":      "BYWEEKNO is not supported",
+	"BYYEARDAY":     "BYYEARDAY is not supported",
+}
+
+// The maximum number of periods iterated while looking for an occurrence.
+// Guards against rules which never produce an occurrence, such as the 30th of February.
+const maxPeriods = 10000
+
+// WeekdayNum represents a BYDAY value. N is the optional ordinal of the weekday within the month or year,
+// negative values count from the end. A zero N matches every such weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule represents a parsed recurrence rule.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByMonth    []int
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+	ByHour     []int
+	ByMinute   []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+	Dtstart    time.Time
+}
+
+// Parse a RRULE string such as "FREQ=MONTHLY;BYDAY=TU;BYSETPOS=2,4" to a Rule starting at dtstart.
+// The optional "RRULE:" prefix is ignored. The start is truncated to the minute.
+// A non empty list of error messages is returned if the string cannot be parsed to a Rule.
+func Parse(s string, dtstart time.Time) (Rule, []string) {
+	rule := Rule{
+		Interval:  1,
+		WeekStart: time.Monday,
+		Dtstart:   dtstart.Truncate(time.Minute),
+	}
+
+	var errors []string
+	freqFound := false
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if len(s) == 0 {
+		return rule, []string{"RRULE cannot be empty"}
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			errors = append(errors, fmt.Sprintf("Invalid rule part %s", part))
+			continue
+		}
+
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		if message, ok := unsupported[key]; ok {
+			errors = append(errors, message)
+			continue
+		}
+		if message, ok := unsupported[key+"="+value]; ok {
+			errors = append(errors, message)
+			continue
+		}
+
+		var err string
+		switch key {
+		case "FREQ":
+			freq, ok := frequencies[value]
+			if !ok {
+				err = fmt.Sprintf("Invalid FREQ %s", value)
+			}
+			rule.Freq = freq
+			freqFound = true
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, value)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value, dtstart.Location())
+		case "BYMONTH":
+			rule.ByMonth, err = parseList(key, value, 1, 12, false)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseList(key, value, -31, 31, true)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYHOUR":
+			rule.ByHour, err = parseList(key, value, 0, 23, false)
+		case "BYMINUTE":
+			rule.ByMinute, err = parseList(key, value, 0, 59, false)
+		case "BYSETPOS":
+			rule.BySetPos, err = parseList(key, value, -366, 366, true)
+		case "WKST":
+			weekday, ok := weekdays[value]
+			if !ok {
+				err = fmt.Sprintf("Invalid WKST %s", value)
+			}
+			rule.WeekStart = weekday
+		default:
+			err = fmt.Sprintf("Unknown rule part %s", key)
+		}
+
+		if len(err) != 0 {
+			errors = append(errors, err)
+		}
+	}
+
+	if !freqFound {
+		errors = append(errors, "FREQ is required")
+	}
+
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		errors = append(errors, "COUNT and UNTIL cannot be used together")
+	}
+
+	if rule.Freq != Monthly && rule.Freq != Yearly {
+		for _, day := range rule.ByDay {
+			if day.N != 0 {
+				errors = append(errors, "Ordinal BYDAY values are only allowed with MONTHLY or YEARLY frequency")
+				break
+			}
+		}
+	}
+
+	if len(rule.BySetPos) > 0 && len(rule.ByMonth)+len(rule.ByMonthDay)+len(rule.ByDay)+len(rule.ByHour)+len(rule.ByMinute) == 0 {
+		errors = append(errors, "BYSETPOS should be used along with another BYxxx rule part")
+	}
+
+	return rule, errors
+}
+
+func parsePositive(key, value string) (int, string) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Sprintf("%s should be a positive integer", key)
+	}
+	return n, ""
+}
+
+func parseUntil(value string, location *time.Location) (time.Time, string) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, ""
+	}
+	for _, layout := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, ""
+		}
+	}
+	return time.Time{}, fmt.Sprintf("Cannot parse UNTIL %s", value)
+}
+
+func parseList(key, value string, min, max int, nonZero bool) ([]int, string) {
+	var list []int
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < min || n > max || (nonZero && n == 0) {
+			return nil, fmt.Sprintf("%s value %s should be between %d and %d", key, part, min, max)
+		}
+		list = append(list, n)
+	}
+	return list, ""
+}
+
+func parseByDay(value string) ([]WeekdayNum, string) {
+	var list []WeekdayNum
+	for _, part := range strings.Split(value, ",") {
+		if len(part) < 2 {
+			return nil, fmt.Sprintf("Invalid BYDAY %s", part)
+		}
+
+		weekday, ok := weekdays[part[len(part)-2:]]
+		if !ok {
+			return nil, fmt.Sprintf("Invalid BYDAY %s", part)
+		}
+
+		n := 0
+		if ordinal := part[:len(part)-2]; len(ordinal) > 0 {
+			var err error
+			if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Sprintf("Invalid BYDAY ordinal %s", part)
+			}
+		}
+
+		list = append(list, WeekdayNum{Weekday: weekday, N: n})
+	}
+	return list, ""
+}
+
+// Match checks if the rule has an occurrence at the time provided, with minute precision.
+func (r Rule) Match(t time.Time) bool {
+	t = t.In(r.Dtstart.Location()).Truncate(time.Minute)
+	if t.Before(r.Dtstart) || (!r.Until.IsZero() && t.After(r.Until)) {
+		return false
+	}
+
+	if r.Count > 0 {
+		matched := false
+		r.each(t, func(occurrence time.Time) bool {
+			matched = occurrence.Equal(t)
+			return false
+		})
+		return matched
+	}
+
+	start := r.periodStart(t)
+	if r.periodsBetween(r.periodStart(r.Dtstart), start)%r.Interval != 0 {
+		return false
+	}
+
+	for _, occurrence := range r.occurrences(start) {
+		if occurrence.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Next returns the first occurrence strictly after the time provided.
+// Returns false if the rule has no more occurrences.
+func (r Rule) Next(after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+
+	from := after.In(r.Dtstart.Location()).Truncate(time.Minute).Add(time.Minute)
+	r.each(from, func(occurrence time.Time) bool {
+		next, found = occurrence, true
+		return false
+	})
+
+	return next, found
+}
+
+// each calls fn in chronological order for the occurrences at or after from, until fn returns false
+// or the rule has no more occurrences.
+func (r Rule) each(from time.Time, fn func(time.Time) bool) {
+	start := r.periodStart(r.Dtstart)
+
+	// Occurrences are counted from the start of the rule when COUNT is set, otherwise skip to the period of from.
+	if r.Count == 0 && from.After(start) {
+		n := r.periodsBetween(start, r.periodStart(from))
+		start = r.addPeriods(start, n-n%r.Interval)
+	}
+
+	count := 0
+	for i := 0; i < maxPeriods; i++ {
+		for _, occurrence := range r.occurrences(start) {
+			if occurrence.Before(r.Dtstart) {
+				continue
+			}
+
+			if !r.Until.IsZero() && occurrence.After(r.Until) {
+				return
+			}
+
+			count++
+			if r.Count > 0 && count > r.Count {
+				return
+			}
+
+			if occurrence.Before(from) {
+				continue
+			}
+
+			if !fn(occurrence) {
+				return
+			}
+		}
+
+		start = r.addPeriods(start, r.Interval)
+	}
+}
+
+// occurrences returns the sorted occurrences of the rule within the period starting at start.
+// The occurrences are not filtered by DTSTART, UNTIL or COUNT.
+func (r Rule) occurrences(start time.Time) []time.Time {
+	var result []time.Time
+
+	for _, day := range r.days(start) {
+		for _, hour := range r.hours(start) {
+			for _, minute := range r.minutes(start) {
+				result = append(result, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()))
+			}
+		}
+	}
+
+	if len(r.BySetPos) == 0 {
+		return result
+	}
+
+	var selected []time.Time
+	for _, pos := range r.BySetPos {
+		index := pos - 1
+		if pos < 0 {
+			index = len(result) + pos
+		}
+		if index >= 0 && index < len(result) {
+			selected = append(selected, result[index])
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Before(selected[j]) })
+	return selected
+}
+
+// days returns the days of the period starting at start which match the rule.
+func (r Rule) days(start time.Time) []time.Time {
+	switch r.Freq {
+	case Yearly:
+		// Ordinal weekdays are counted within the year unless the months are restricted.
+		if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0 {
+			return r.matchingDays(start, start.AddDate(1, 0, -1))
+		}
+
+		var days []time.Time
+		for month := 1; month <= 12; month++ {
+			first := time.Date(start.Year(), time.Month(month), 1, 0, 0, 0, 0, start.Location())
+			days = append(days, r.matchingDays(first, first.AddDate(0, 1, -1))...)
+		}
+		return days
+	case Monthly:
+		return r.matchingDays(start, start.AddDate(0, 1, -1))
+	case Weekly:
+		return r.matchingDays(start, start.AddDate(0, 0, 6))
+	default:
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+		return r.matchingDays(day, day)
+	}
+}
+
+// matchingDays returns the days between first and last, both inclusive, which match the rule.
+// Ordinal weekdays are counted within first and last.
+func (r Rule) matchingDays(first, last time.Time) []time.Time {
+	var days []time.Time
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if r.matchDay(day, first, last) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r Rule) matchDay(day, first, last time.Time) bool {
+	if len(r.ByMonth) > 0 && !contains(r.ByMonth, int(day.Month())) {
+		return false
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		if !contains(r.ByMonthDay, day.Day()) && !contains(r.ByMonthDay, day.Day()-daysInMonth-1) {
+			return false
+		}
+	}
+
+	if len(r.ByDay) > 0 && !r.matchWeekday(day, first, last) {
+		return false
+	}
+
+	// Without any BYxxx day rules the day is taken from the start of the rule
+	switch r.Freq {
+	case Yearly:
+		if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+			return day.Day() == r.Dtstart.Day() && (len(r.ByMonth) > 0 || day.Month() == r.Dtstart.Month())
+		}
+	case Monthly:
+		if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+			return day.Day() == r.Dtstart.Day()
+		}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == r.Dtstart.Weekday()
+		}
+	}
+
+	return true
+}
+
+func (r Rule) matchWeekday(day, first, last time.Time) bool {
+	for _, weekday := range r.ByDay {
+		if weekday.Weekday != day.Weekday() {
+			continue
+		}
+
+		switch {
+		case weekday.N == 0:
+			return true
+		case weekday.N > 0 && daysBetween(first, day)/7+1 == weekday.N:
+			return true
+		case weekday.N < 0 && daysBetween(day, last)/7+1 == -weekday.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) hours(start time.Time) []int {
+	if r.Freq == Hourly || r.Freq == Minutely {
+		if len(r.ByHour) > 0 && !contains(r.ByHour, start.Hour()) {
+			return nil
+		}
+		return []int{start.Hour()}
+	}
+
+	if len(r.ByHour) > 0 {
+		return sorted(r.ByHour)
+	}
+	return []int{r.Dtstart.Hour()}
+}
+
+func (r Rule) minutes(start time.Time) []int {
+	if r.Freq == Minutely {
+		if len(r.ByMinute) > 0 && !contains(r.ByMinute, start.Minute()) {
+			return nil
+		}
+		return []int{start.Minute()}
+	}
+
+	if len(r.ByMinute) > 0 {
+		return sorted(r.ByMinute)
+	}
+	return []int{r.Dtstart.Minute()}
+}
+
+// periodStart returns the start of the period, as per the frequency of the rule, containing t.
+func (r Rule) periodStart(t time.Time) time.Time {
+	switch r.Freq {
+	case Yearly:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	case Monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case Weekly:
+		offset := (int(t.Weekday()) - int(r.WeekStart) + 7) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+	case Daily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case Hourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+	}
+}
+
+// periodsBetween returns the number of periods between the period starts a and b.
+func (r Rule) periodsBetween(a, b time.Time) int {
+	switch r.Freq {
+	case Yearly:
+		return b.Year() - a.Year()
+	case Monthly:
+		return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+	case Weekly:
+		return daysBetween(a, b) / 7
+	case Daily:
+		return daysBetween(a, b)
+	case Hourly:
+		return daysBetween(a, b)*24 + b.Hour() - a.Hour()
+	default:
+		return (daysBetween(a, b)*24+b.Hour()-a.Hour())*60 + b.Minute() - a.Minute()
+	}
+}
+
+// addPeriods adds n periods to the period start.
+func (r Rule) addPeriods(start time.Time, n int) time.Time {
+	switch r.Freq {
+	case Yearly:
+		return start.AddDate(n, 0, 0)
+	case Monthly:
+		return start.AddDate(0, n, 0)
+	case Weekly:
+		return start.AddDate(0, 0, 7*n)
+	case Daily:
+		return start.AddDate(0, 0, n)
+	case Hourly:
+		return time.Date(start.Year(), start.Month(), start.Day(), start.Hour()+n, 0, 0, 0, start.Location())
+	default:
+		return time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute()+n, 0, 0, start.Location())
+	}
+}
+
+// daysBetween returns the number of calendar days from a to b irrespective of daylight saving changes.
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+func contains(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sorted(list []int) []int {
+	output := append([]int{}, list...)
+	sort.Ints(output)
+	return output
+}
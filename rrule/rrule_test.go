@@ -0,0 +1,137 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	dtstart := date(2023, time.June, 1, 10, 0)
+
+	for _, test := range []struct {
+		Input    string
+		HasError bool
+	}{
+		{"FREQ=DAILY", false},
+		{"RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", false},
+		{"FREQ=MONTHLY;BYDAY=2TU,4TU", false},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;BYHOUR=9;BYMINUTE=30", false},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1;UNTIL=20300101T000000Z", false},
+		{"", true},
+		{"BYDAY=MO", true},
+		{"FREQ=SECONDLY", true},
+		{"FREQ=DAILY;BYSECOND=10", true},
+		{"FREQ=DAILY;INTERVAL=0", true},
+		{"FREQ=DAILY;COUNT=5;UNTIL=20300101", true},
+		{"FREQ=WEEKLY;BYDAY=2TU", true},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", true},
+		{"FREQ=MONTHLY;BYSETPOS=1", true},
+		{"FREQ=DAILY;FOO=BAR", true},
+	} {
+		if _, errs := Parse(test.Input, dtstart); test.HasError != (len(errs) != 0) {
+			t.Errorf("Input: %s, expected error: %t, got errors: %v", test.Input, test.HasError, errs)
+		}
+	}
+}
+
+func TestRule_Match(t *testing.T) {
+	dtstart := date(2023, time.June, 1, 10, 0)
+
+	for _, test := range []struct {
+		Rule     string
+		Time     time.Time
+		Expected bool
+	}{
+		// Last weekday of the month, June 30 2023 is a Friday
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", date(2023, time.June, 30, 10, 0), true},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", date(2023, time.June, 29, 10, 0), false},
+		// Last weekday of September 2023 is Friday the 29th since the 30th is a Saturday
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", date(2023, time.September, 29, 10, 0), true},
+		// 2nd and 4th Tuesday of June 2023 are the 13th and 27th
+		{"FREQ=MONTHLY;BYDAY=2TU,4TU", date(2023, time.June, 13, 10, 0), true},
+		{"FREQ=MONTHLY;BYDAY=2TU,4TU", date(2023, time.June, 27, 10, 0), true},
+		{"FREQ=MONTHLY;BYDAY=2TU,4TU", date(2023, time.June, 20, 10, 0), false},
+		{"FREQ=MONTHLY;BYDAY=2TU,4TU", date(2023, time.June, 13, 11, 0), false},
+		// Every other week on Monday and Wednesday at 09:30, the week of June 1 2023 starts on May 29
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;BYHOUR=9;BYMINUTE=30", date(2023, time.June, 12, 9, 30), true},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;BYHOUR=9;BYMINUTE=30", date(2023, time.June, 5, 9, 30), false},
+		// Last day of February
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1", date(2024, time.February, 29, 10, 0), true},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1", date(2024, time.February, 28, 10, 0), false},
+		// Before the start of the rule
+		{"FREQ=DAILY", date(2023, time.May, 31, 10, 0), false},
+		{"FREQ=DAILY", date(2023, time.June, 2, 10, 0), true},
+		// COUNT and UNTIL
+		{"FREQ=DAILY;COUNT=3", date(2023, time.June, 3, 10, 0), true},
+		{"FREQ=DAILY;COUNT=3", date(2023, time.June, 4, 10, 0), false},
+		{"FREQ=DAILY;UNTIL=20230603T100000Z", date(2023, time.June, 3, 10, 0), true},
+		{"FREQ=DAILY;UNTIL=20230603T100000Z", date(2023, time.June, 4, 10, 0), false},
+		// Every 90 minutes
+		{"FREQ=MINUTELY;INTERVAL=90", date(2023, time.June, 1, 11, 30), true},
+		{"FREQ=MINUTELY;INTERVAL=90", date(2023, time.June, 1, 12, 0), false},
+	} {
+		rule, errs := Parse(test.Rule, dtstart)
+		if len(errs) != 0 {
+			t.Fatalf("Rule: %s, unexpected errors: %v", test.Rule, errs)
+		}
+
+		if output := rule.Match(test.Time); output != test.Expected {
+			t.Errorf("Rule: %s, time: %v, expected: %t, got: %t", test.Rule, test.Time, test.Expected, output)
+		}
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	dtstart := date(2023, time.June, 1, 10, 0)
+
+	rule, _ := Parse("FREQ=MONTHLY;BYDAY=2TU,4TU", dtstart)
+
+	expected := []time.Time{
+		date(2023, time.June, 13, 10, 0),
+		date(2023, time.June, 27, 10, 0),
+		date(2023, time.July, 11, 10, 0),
+		date(2023, time.July, 25, 10, 0),
+	}
+
+	after := dtstart
+	for _, want := range expected {
+		next, ok := rule.Next(after)
+		if !ok || !next.Equal(want) {
+			t.Fatalf("Expected next occurrence after %v to be %v, got %v", after, want, next)
+		}
+		after = next
+	}
+
+	rule, _ = Parse("FREQ=DAILY;COUNT=2", dtstart)
+	if _, ok := rule.Next(date(2023, time.June, 2, 10, 0)); ok {
+		t.Errorf("Expected no occurrence after the COUNT is exhausted")
+	}
+
+	rule, _ = Parse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", dtstart)
+	if _, ok := rule.Next(dtstart); ok {
+		t.Errorf("Expected no occurrence for a rule which never matches")
+	}
+}
@@ -20,7 +20,6 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"github.com/myntra/goscheduler/conf"
 	"github.com/myntra/goscheduler/scheduler"
@@ -31,7 +30,6 @@ func main() {
 	fmt.Println("Welcome to Goscheduler.")
 	//Load all the configs
 	confFile, port, host := conf.ParseFlags()
-	_ = flag.Set("stderrthreshold", "INFO")
 
 	config := conf.InitConfig(confFile, port, host)
 	s := scheduler.New(config, map[string]store.Factory{})
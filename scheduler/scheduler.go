@@ -20,19 +20,28 @@
 package scheduler
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/archive"
 	"github.com/myntra/goscheduler/cassandra"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/cluster"
 	c "github.com/myntra/goscheduler/conf"
 	conn "github.com/myntra/goscheduler/connectors"
 	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/faults"
+	"github.com/myntra/goscheduler/logger"
 	m "github.com/myntra/goscheduler/monitoring"
 	"github.com/myntra/goscheduler/poller"
+	"github.com/myntra/goscheduler/replication"
 	r "github.com/myntra/goscheduler/retrievers"
 	"github.com/myntra/goscheduler/server"
 	s "github.com/myntra/goscheduler/service"
+	"github.com/myntra/goscheduler/sla"
 	st "github.com/myntra/goscheduler/store"
 )
 
@@ -47,6 +56,54 @@ type Scheduler struct {
 	Monitor    m.Monitor
 }
 
+// initLogger replaces the default logger with one writing in the configured format and level.
+func initLogger(conf *c.Configuration) {
+	l, err := logger.NewZap(os.Stderr, conf.LogConfig.Format, conf.LogConfig.Level)
+	if err != nil {
+		logger.Errorf("Invalid log config %+v, using the default logger: %+v", conf.LogConfig, err)
+		return
+	}
+	logger.SetDefault(l)
+}
+
+// initDiagnostics applies the slow query settings to the diagnostics recorder before any session is created.
+func initDiagnostics(conf *c.Configuration) {
+	diagnostics.Default().Configure(
+		time.Duration(conf.DiagnosticsConfig.SlowQueryThresholdMillis)*time.Millisecond,
+		conf.DiagnosticsConfig.SlowQueryLimit)
+}
+
+// initClock replaces the wall clock with a virtual one when it is enabled for the integration tests.
+// A start time which cannot be parsed stops the scheduler from starting.
+func initClock(conf *c.Configuration) {
+	if !conf.ClockConfig.Virtual {
+		return
+	}
+
+	start := time.Now()
+	if conf.ClockConfig.StartTime != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, conf.ClockConfig.StartTime); err != nil {
+			panic(fmt.Errorf("invalid start time of the virtual clock: %w", err))
+		}
+	}
+
+	logger.Warningf("Schedules are fired on a virtual clock starting at %s, which only moves when advanced", start.Format(time.RFC3339))
+	clock.SetDefault(clock.NewVirtual(start))
+}
+
+// initFaults starts injecting the faults of the resilience tests when fault injection is enabled.
+// Invalid rates stop the scheduler from starting.
+func initFaults(conf *c.Configuration, monitor m.Monitor) {
+	if err := faults.ValidateConfig(conf.FaultInjectionConfig); err != nil {
+		panic(err)
+	}
+	if conf.FaultInjectionConfig.Enabled {
+		logger.Warningf("Fault injection is enabled with %+v", conf.FaultInjectionConfig)
+	}
+	faults.Default().Configure(conf.FaultInjectionConfig, monitor)
+}
+
 // initCassandra initializes the Cassandra database with the given configuration and schema.
 func initCassandra(conf *c.Configuration, createSchema bool) {
 	if createSchema {
@@ -61,6 +118,11 @@ func initDAOs(conf *c.Configuration, monitor m.Monitor) (dao.ClusterDao, dao.Sch
 	return clusterDao, scheduleDao
 }
 
+// initTemplates resolves the callback templates referenced by the schedules from the cluster DAO.
+func initTemplates(clusterDao dao.ClusterDao) {
+	st.SetTemplateStore(clusterDao)
+}
+
 // initRetrievers initializes the retrievers for the schedules and clusters using the configuration provided.
 func initRetrievers(conf *c.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor m.Monitor) r.Retrievers {
 	return r.InitRetrievers(conf, clusterDao, scheduleDao, monitor)
@@ -68,10 +130,7 @@ func initRetrievers(conf *c.Configuration, clusterDao dao.ClusterDao, scheduleDa
 
 // initSupervisor creates a new Supervisor object that manages the cluster of nodes running the scheduler.
 func initSupervisor(conf *c.Configuration, retrievers r.Retrievers, clusterDao dao.ClusterDao, monitor m.Monitor) *cluster.Supervisor {
-	supervisor := cluster.NewSupervisor(
-		poller.NewPollerFactory(retrievers, conf.Poller, monitor),
-		clusterDao,
-		monitor,
+	opts := []cluster.Option{
 		cluster.WithClusterName(conf.Cluster.ClusterName),
 		cluster.WithAddress(conf.Cluster.Address),
 		cluster.WithBootStrapServers(conf.Cluster.BootStrapServers),
@@ -81,12 +140,62 @@ func initSupervisor(conf *c.Configuration, retrievers r.Retrievers, clusterDao d
 		cluster.WithReplicaPoints(conf.Cluster.ReplicaPoints),
 		cluster.WithReconciliationEnabled(conf.NodeCrashReconcile.NeedsReconcile),
 		cluster.WithReconciliationOffset(conf.NodeCrashReconcile.ReconcileOffset),
+	}
+
+	switch conf.Cluster.Coordination.Backend {
+	case "", "ringpop":
+	case cluster.CoordinationEtcd:
+		coordinator, err := cluster.NewEtcdCoordinator(conf.Cluster.Coordination.Etcd, conf.Cluster.ClusterName, conf.Cluster.Address)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, cluster.WithCoordinator(coordinator))
+	default:
+		panic(fmt.Sprintf("Unknown coordination backend %s", conf.Cluster.Coordination.Backend))
+	}
+
+	supervisor := cluster.NewSupervisor(
+		poller.NewPollerFactory(retrievers, conf.Poller, monitor),
+		clusterDao,
+		monitor,
+		opts...,
 	)
-	supervisor.InitRingPop()
+	supervisor.InitCoordination()
 	supervisor.Boot()
 	return supervisor
 }
 
+// initReplication resolves the replication role of the cluster and starts tailing the event stream of the
+// primary cluster when the cluster is a standby.
+func initReplication(conf *c.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor m.Monitor) {
+	replication.NewReplicator(conf, clusterDao, scheduleDao, monitor).Start()
+}
+
+// initArchive starts archiving the old time buckets of the partitions polled by the node when archiving is enabled.
+// An invalid archive configuration stops the scheduler from starting.
+func initArchive(conf *c.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor m.Monitor) {
+	if err := archive.ValidateConfig(conf.ArchiveConfig); err != nil {
+		panic(err)
+	}
+	if !conf.ArchiveConfig.Enabled {
+		archive.SetDefault(nil)
+		return
+	}
+
+	objects, err := archive.NewObjectStore(conf.ArchiveConfig)
+	if err != nil {
+		panic(err)
+	}
+	archiver := archive.NewArchiver(conf, objects, clusterDao, scheduleDao, monitor)
+	archiver.Start()
+	archive.SetDefault(archiver)
+}
+
+// initSLA starts alerting on the firing lag of the callbacks fired by the node when it is enabled.
+func initSLA(conf *c.Configuration, monitor m.Monitor) {
+	sla.NewWatcher(conf, monitor).Start()
+}
+
 // initConnectors creates the connector object used to communicate with the cluster nodes.
 func initConnectors(conf *c.Configuration, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor m.Monitor, callbackWorkers bool) *conn.Connector {
 	t := &st.Task{Conf: conf}
@@ -107,24 +216,127 @@ func initServer(conf *c.Configuration, router *mux.Router, service *s.Service) *
 }
 
 // initMonitoring initializes the monitoring component with the given configuration.
-func initMonitoring() m.Monitor {
-	return m.NewPrometheusMonitor()
+// The cardinality of the labels of its metrics is bounded by the label config.
+func initMonitoring(conf *c.Configuration) m.Monitor {
+	return m.NewLabelFilter(m.NewPrometheusMonitor(), conf.MonitoringConfig.Labels)
 }
 
 func initCallbackRegistry(registry map[string]st.Factory) {
 	st.InitializeCallbackRegistry(registry)
 }
 
+// initCallbackPlugins loads the Go plugins and registers the sidecar executors configured for callback plugins.
+// A plugin which fails to load stops the scheduler from starting.
+func initCallbackPlugins(conf *c.Configuration) {
+	for _, path := range conf.CallbackPluginConfig.Plugins {
+		callbackType, err := st.LoadPlugin(path)
+		if err != nil {
+			panic(err)
+		}
+		logger.Infof("Loaded callback plugin %s from %s", callbackType, path)
+	}
+
+	for callbackType, url := range conf.CallbackPluginConfig.Sidecars {
+		if err := st.RegisterSidecar(callbackType, url, conf.CallbackPluginConfig.TimeoutMillis*time.Millisecond); err != nil {
+			panic(err)
+		}
+		logger.Infof("Registered sidecar executor %s for callback type %s", url, callbackType)
+	}
+}
+
+// initEgress restricts the destinations of the callbacks to the egress policy of the configuration.
+// An invalid policy stops the scheduler from starting.
+func initEgress(conf *c.Configuration) {
+	policy, err := st.NewEgressPolicy(conf.EgressConfig)
+	if err != nil {
+		panic(err)
+	}
+	st.SetEgressPolicy(policy)
+}
+
+// initRegions routes the callbacks of the schedules of other regions through the egress proxies of the configuration.
+// An invalid configuration stops the scheduler from starting.
+func initRegions(conf *c.Configuration) {
+	regions, err := st.NewRegions(conf.RegionConfig)
+	if err != nil {
+		panic(err)
+	}
+	st.SetRegions(regions)
+}
+
+// initSecrets sets the provider the secret references of the callbacks are resolved from.
+// An invalid provider stops the scheduler from starting.
+func initSecrets(conf *c.Configuration) {
+	provider, err := st.NewSecretProvider(conf.SecretsConfig)
+	if err != nil {
+		panic(err)
+	}
+	st.SetSecretProvider(provider)
+}
+
+// initFeatureFlags gates the firing of the callbacks on the flags of the flag provider of the configuration and
+// starts refreshing them. An invalid provider stops the scheduler from starting.
+func initFeatureFlags(conf *c.Configuration) {
+	flags, err := st.NewFeatureFlags(conf.FeatureFlagConfig)
+	if err != nil {
+		panic(err)
+	}
+	if flags != nil {
+		flags.Start()
+	}
+	st.SetFeatureFlags(flags)
+}
+
+// initParking checks the configuration of parking the far future schedules.
+// An invalid configuration stops the scheduler from starting.
+func initParking(conf *c.Configuration) {
+	if err := st.ValidateParking(conf.ParkingConfig); err != nil {
+		panic(err)
+	}
+}
+
+// initPolling checks the configuration of the pollers.
+// Polling modes which cannot be used together stop the scheduler from starting.
+func initPolling(conf *c.Configuration) {
+	if err := poller.ValidateConfig(conf.Poller); err != nil {
+		panic(err)
+	}
+}
+
+// initDeliveryReceipts checks the configuration of the delivery receipts.
+// Receipts enabled without a signing key stop the scheduler from starting.
+func initDeliveryReceipts(conf *c.Configuration) {
+	if err := st.ValidateDeliveryReceipts(conf.DeliveryReceiptConfig); err != nil {
+		panic(err)
+	}
+}
+
 // New creates a new Scheduler instance with a given configuration and callback factories.
 // This is a base constructor that uses configuration and callback factory objects directly.
 func New(conf *c.Configuration, callbackFactories map[string]st.Factory) *Scheduler {
+	initLogger(conf)
+	initDiagnostics(conf)
+	initClock(conf)
 	initCassandra(conf, true)
 	initCallbackRegistry(callbackFactories)
-	monitor := initMonitoring()
+	initCallbackPlugins(conf)
+	initEgress(conf)
+	initRegions(conf)
+	initSecrets(conf)
+	initFeatureFlags(conf)
+	initParking(conf)
+	initPolling(conf)
+	initDeliveryReceipts(conf)
+	monitor := initMonitoring(conf)
+	initFaults(conf, monitor)
 	clusterDao, schedulerDao := initDAOs(conf, monitor)
+	initTemplates(clusterDao)
+	initReplication(conf, clusterDao, schedulerDao, monitor)
+	initArchive(conf, clusterDao, schedulerDao, monitor)
 	retrievers := initRetrievers(conf, clusterDao, schedulerDao, monitor)
 	supervisor := initSupervisor(conf, retrievers, clusterDao, monitor)
 	connectors := initConnectors(conf, clusterDao, schedulerDao, monitor, true)
+	initSLA(conf, monitor)
 	service := initService(conf, supervisor, clusterDao, schedulerDao, monitor)
 	router := mux.NewRouter().StrictSlash(true)
 	svr := initServer(conf, router, service)
@@ -143,11 +355,29 @@ func New(conf *c.Configuration, callbackFactories map[string]st.Factory) *Schedu
 // TODO: Reformat this constructor
 // NewScheduler creates a new Scheduler instance with a given params.
 func NewScheduler(conf *c.Configuration, callbackFactories map[string]st.Factory, clusterDao dao.ClusterDao, scheduleDao dao.ScheduleDao, monitor m.Monitor, createSchema bool, callbackWorkers bool) *Scheduler {
+	initLogger(conf)
+	initDiagnostics(conf)
+	initClock(conf)
+	initFaults(conf, monitor)
 	initCassandra(conf, createSchema)
 	initCallbackRegistry(callbackFactories)
+	initCallbackPlugins(conf)
+	initEgress(conf)
+	initRegions(conf)
+	initSecrets(conf)
+	initFeatureFlags(conf)
+	initParking(conf)
+	initPolling(conf)
+	initDeliveryReceipts(conf)
+	initTemplates(clusterDao)
+	initReplication(conf, clusterDao, scheduleDao, monitor)
+	initArchive(conf, clusterDao, scheduleDao, monitor)
 	retrievers := initRetrievers(conf, clusterDao, scheduleDao, monitor)
 	supervisor := initSupervisor(conf, retrievers, clusterDao, monitor)
 	connectors := initConnectors(conf, clusterDao, scheduleDao, monitor, callbackWorkers)
+	if callbackWorkers {
+		initSLA(conf, monitor)
+	}
 	service := initService(conf, supervisor, clusterDao, scheduleDao, monitor)
 	router := mux.NewRouter().StrictSlash(true)
 	initServer(conf, router, service)
@@ -0,0 +1,213 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+)
+
+const (
+	gzipEncoding = "gzip"
+	// Smallest response compressed, smaller ones not being worth the cost of the compression
+	gzipMinBytes = 1024
+)
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// compressionMiddleware decompresses the request bodies sent with Content-Encoding: gzip and compresses the responses
+// of at least gzipMinBytes for the clients sending Accept-Encoding: gzip.
+// The request bodies are decompressed as they are read, so the body size limits of the routes apply to their
+// decompressed size.
+func compressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(strings.TrimSpace(r.Header.Get("Content-Encoding")), gzipEncoding) {
+			r.Body = &gzipBody{body: r.Body}
+			r.Header.Del("Content-Encoding")
+			r.ContentLength = -1
+		}
+
+		if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w, code: http.StatusOK}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip tells whether an Accept-Encoding header accepts gzip, either by name or through * and with a non zero q
+func acceptsGzip(header string) bool {
+	accepted := false
+	for _, coding := range strings.Split(header, ",") {
+		parts := strings.Split(coding, ";")
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		if name != gzipEncoding && name != "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range parts[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(value, "q="), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		// an explicit gzip takes precedence over *
+		if name == gzipEncoding {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
+
+// gzipBody decompresses a request body, reading its gzip header on the first read rather than when the request is
+// received so that the read timeouts of the routes apply to it
+type gzipBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.reader == nil {
+		reader, err := gzip.NewReader(b.body)
+		if err != nil {
+			return 0, err
+		}
+		b.reader = reader
+	}
+	return b.reader.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// gzipResponseWriter buffers the start of a response until it is known to be worth compressing,
+// i.e. until it reaches gzipMinBytes or it is flushed
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+	buffer      bytes.Buffer
+	gzip        *gzip.Writer
+	decided     bool
+}
+
+func (gw *gzipResponseWriter) WriteHeader(code int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+	gw.code = code
+}
+
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	gw.wroteHeader = true
+	if gw.decided {
+		if gw.gzip != nil {
+			return gw.gzip.Write(b)
+		}
+		return gw.ResponseWriter.Write(b)
+	}
+
+	gw.buffer.Write(b)
+	if gw.buffer.Len() >= gzipMinBytes {
+		if err := gw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide writes the header of the response, compressing the response if asked unless its handler encoded it itself
+// or it is a partial one, and then writes what was buffered so far
+func (gw *gzipResponseWriter) decide(compress bool) error {
+	gw.decided = true
+	header := gw.Header()
+	if compress && header.Get("Content-Encoding") == "" && header.Get("Content-Range") == "" && compressible(gw.code) {
+		header.Set("Content-Encoding", gzipEncoding)
+		header.Del("Content-Length")
+		gw.gzip = gzipWriters.Get().(*gzip.Writer)
+		gw.gzip.Reset(gw.ResponseWriter)
+	}
+	gw.ResponseWriter.WriteHeader(gw.code)
+
+	if gw.buffer.Len() == 0 {
+		return nil
+	}
+	var err error
+	if gw.gzip != nil {
+		_, err = gw.gzip.Write(gw.buffer.Bytes())
+	} else {
+		_, err = gw.ResponseWriter.Write(gw.buffer.Bytes())
+	}
+	gw.buffer.Reset()
+	return err
+}
+
+// compressible tells whether a response of the status can be compressed, partial content and bodyless ones are sent as is
+func compressible(code int) bool {
+	return code != http.StatusNoContent && code != http.StatusNotModified && code != http.StatusPartialContent
+}
+
+// Flush compresses a streamed response, e.g. the events of an event stream, flushing every chunk compressed so far
+func (gw *gzipResponseWriter) Flush() {
+	if !gw.decided {
+		_ = gw.decide(true)
+	}
+	if gw.gzip != nil {
+		_ = gw.gzip.Flush()
+	}
+	if flusher, ok := gw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// close writes a response which never reached gzipMinBytes uncompressed and ends a compressed one
+func (gw *gzipResponseWriter) close() {
+	if !gw.decided {
+		if !gw.wroteHeader {
+			return
+		}
+		_ = gw.decide(false)
+	}
+	if gw.gzip != nil {
+		_ = gw.gzip.Close()
+		gw.gzip.Reset(nil)
+		gzipWriters.Put(gw.gzip)
+		gw.gzip = nil
+	}
+}
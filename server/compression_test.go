@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/klauspost/compress/gzip"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/service"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	_ = writer.Close()
+	return buffer.Bytes()
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, expected := range map[string]bool{
+		"":                      false,
+		"gzip":                  true,
+		"deflate, GZIP;q=0.5":   true,
+		"gzip;q=0":              false,
+		"*":                     true,
+		"*;q=0":                 false,
+		"gzip;q=0, *":           false,
+		"br, identity":          false,
+		"identity, *;q=0.1, br": true,
+	} {
+		if actual := acceptsGzip(header); actual != expected {
+			t.Errorf("%q: expected %v, got %v", header, expected, actual)
+		}
+	}
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	s := &Server{service: &service.Service{Config: conf.NewConfig(conf.WithHttpServerConfig(conf.HttpServerConfig{
+		Default: conf.RouteLimits{MaxBodyBytes: 4096},
+	}))}}
+	router := mux.NewRouter()
+	router.Use(compressionMiddleware)
+	router.Use(s.limitsMiddleware)
+	router.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(body)
+	}).Name("echo")
+
+	large := strings.Repeat("schedule ", 300)
+	for _, test := range []struct {
+		name           string
+		body           []byte
+		gzipRequest    bool
+		acceptEncoding string
+		Status         int
+		compressed     bool
+	}{
+		{"gzip request", gzipped(t, "small"), true, "", http.StatusOK, false},
+		{"small response", []byte("small"), false, "gzip", http.StatusOK, false},
+		{"large response", []byte(large), false, "gzip", http.StatusOK, true},
+		{"large response refused gzip", []byte(large), false, "gzip;q=0", http.StatusOK, false},
+		{"malformed gzip request", []byte("not gzip"), true, "", http.StatusBadRequest, false},
+		{"decompressed request above the limit", gzipped(t, strings.Repeat("x", 8192)), true, "", http.StatusRequestEntityTooLarge, false},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader(test.body))
+		if test.gzipRequest {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		req.Header.Set("Accept-Encoding", test.acceptEncoding)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != test.Status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.Status, rr.Code)
+			continue
+		}
+		if compressed := rr.Header().Get("Content-Encoding") == "gzip"; compressed != test.compressed {
+			t.Errorf("%s: expected compressed %v, got %v", test.name, test.compressed, compressed)
+			continue
+		}
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		body := rr.Body.Bytes()
+		if test.compressed {
+			reader, err := gzip.NewReader(rr.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body, err = ioutil.ReadAll(reader); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected := string(test.body)
+		if test.gzipRequest {
+			expected = "small"
+		}
+		if string(body) != expected {
+			t.Errorf("%s: expected body %q, got %q", test.name, expected, body)
+		}
+	}
+}
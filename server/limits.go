@@ -0,0 +1,239 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+)
+
+// limitsMiddleware enforces the limits of the route of a request:
+//   - the body is read upfront within the read timeout of the route, 408 past it, up to its max body size, 413 above it
+//   - the handler runs within the handler timeout of the route, 408 past it, with its context cancelled at the timeout
+func (s *Server) limitsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		if route := mux.CurrentRoute(r); route != nil {
+			name = route.GetName()
+		}
+		limits := s.routeLimits(name)
+
+		if err := readBody(r, limits); err != nil {
+			// the connection is closed once the error is written, which ends a read still pending
+			w.Header().Set("Connection", "close")
+			er.Handle(w, r, err.(er.AppError))
+			return
+		}
+
+		if limits.HandlerTimeoutMillis <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		serveWithin(w, r, next, time.Duration(limits.HandlerTimeoutMillis)*time.Millisecond)
+	})
+}
+
+// dueRunsMarginMillis is the time a long poll for due runs is given past its longest wait to lease the runs found and
+// write them, so that the runs it leased are not dropped with a timed out response
+const dueRunsMarginMillis = 10000
+
+// routeLimits returns the limits of the route. The handler timeout of a poll for due runs is never below the longest
+// wait of the poll plus dueRunsMarginMillis, so that a poll waiting until its end returns its runs or none with a 200.
+func (s *Server) routeLimits(name string) conf.RouteLimits {
+	config := s.service.Config
+	limits := config.HttpServerConfig.GetLimits(name)
+	if name == constants.GetDueRuns && limits.HandlerTimeoutMillis > 0 {
+		if longest := config.PullDeliveryConfig.MaxWaitSeconds*1000 + dueRunsMarginMillis; limits.HandlerTimeoutMillis < longest {
+			limits.HandlerTimeoutMillis = longest
+		}
+	}
+	return limits
+}
+
+// readBody reads the body of the request within the limits of its route and replaces it with the bytes read
+func readBody(r *http.Request, limits conf.RouteLimits) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	tooLarge := func(size int64) error {
+		return er.NewError(er.PayloadTooLarge, fmt.Errorf("request body of %d bytes exceeds the limit of %d bytes", size, limits.MaxBodyBytes))
+	}
+	if limits.MaxBodyBytes > 0 && r.ContentLength > limits.MaxBodyBytes {
+		return tooLarge(r.ContentLength)
+	}
+
+	reader := io.Reader(r.Body)
+	if limits.MaxBodyBytes > 0 {
+		reader = io.LimitReader(r.Body, limits.MaxBodyBytes+1)
+	}
+
+	type result struct {
+		body []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		body, err := ioutil.ReadAll(reader)
+		done <- result{body: body, err: err}
+	}()
+
+	var timeout <-chan time.Time
+	if limits.ReadTimeoutMillis > 0 {
+		timer := time.NewTimer(time.Duration(limits.ReadTimeoutMillis) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return er.NewError(er.UnmarshalErrorCode, res.err)
+		}
+		if limits.MaxBodyBytes > 0 && int64(len(res.body)) > limits.MaxBodyBytes {
+			return tooLarge(int64(len(res.body)))
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(res.body))
+		return nil
+	case <-timeout:
+		return er.NewError(er.RequestTimeout, fmt.Errorf("request body not read within %d ms", limits.ReadTimeoutMillis))
+	}
+}
+
+// serveWithin runs the handler within the timeout, buffering its response so that a 408 can still be written past it.
+// A response written by the handler after the timeout is dropped. A response flushed by the handler, e.g. a listing
+// streamed as ndjson, is written as it goes from the first flush on, and is cut short at the timeout instead.
+func serveWithin(w http.ResponseWriter, r *http.Request, next http.Handler, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	tw := &timeoutWriter{w: w, header: w.Header().Clone(), code: http.StatusOK}
+	done := make(chan struct{})
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicked <- p
+				return
+			}
+			close(done)
+		}()
+		next.ServeHTTP(tw, r.WithContext(ctx))
+	}()
+
+	select {
+	case p := <-panicked:
+		panic(p)
+	case <-done:
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		if !tw.committed {
+			tw.commit()
+		}
+	case <-ctx.Done():
+		tw.mu.Lock()
+		tw.timedOut = true
+		committed := tw.committed
+		tw.mu.Unlock()
+		if !committed {
+			er.Handle(w, r, er.NewError(er.RequestTimeout, fmt.Errorf("request not handled within %s", timeout)))
+		}
+	}
+}
+
+// timeoutWriter buffers the response of a handler run by serveWithin until it completes or flushes the response
+type timeoutWriter struct {
+	mu          sync.Mutex
+	w           http.ResponseWriter
+	header      http.Header
+	body        bytes.Buffer
+	code        int
+	wroteHeader bool
+	committed   bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	if tw.committed {
+		return tw.w.Write(b)
+	}
+	return tw.body.Write(b)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.code = code
+}
+
+// Flush writes the response buffered so far and flushes it, the rest of the response is written as it goes
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	if !tw.committed {
+		tw.commit()
+	}
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// commit writes the status, the headers and the body buffered of the response of the handler
+func (tw *timeoutWriter) commit() {
+	header := tw.w.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range tw.header {
+		header[key] = values
+	}
+	tw.w.WriteHeader(tw.code)
+	_, _ = tw.w.Write(tw.body.Bytes())
+	tw.body.Reset()
+	tw.committed = true
+}
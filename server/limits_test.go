@@ -0,0 +1,170 @@
+package server
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/service"
+)
+
+func limitsRouter(handlerCancelled chan bool) *mux.Router {
+	s := &Server{service: &service.Service{Config: conf.NewConfig(conf.WithHttpServerConfig(conf.HttpServerConfig{
+		Default: conf.RouteLimits{ReadTimeoutMillis: 100, HandlerTimeoutMillis: 100, MaxBodyBytes: 10},
+		Routes: map[string]conf.RouteLimits{
+			"bulk":   {MaxBodyBytes: 100},
+			"stream": {HandlerTimeoutMillis: -1},
+		},
+	}))}}
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Echo", "true")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			handlerCancelled <- true
+		case <-time.After(200 * time.Millisecond):
+			handlerCancelled <- false
+		}
+	}
+
+	router := mux.NewRouter()
+	router.Use(s.limitsMiddleware)
+	router.HandleFunc("/default", echo).Name("default")
+	router.HandleFunc("/bulk", echo).Name("bulk")
+	router.HandleFunc("/slow", slow).Name("slow")
+	router.HandleFunc("/stream", slow).Name("stream")
+	return router
+}
+
+func TestServer_limitsMiddleware(t *testing.T) {
+	cancelled := make(chan bool, 1)
+	router := limitsRouter(cancelled)
+
+	for _, test := range []struct {
+		name   string
+		path   string
+		body   io.Reader
+		Status int
+	}{
+		{"body within the limit", "/default", strings.NewReader("small"), http.StatusCreated},
+		{"declared body above the limit", "/default", strings.NewReader(strings.Repeat("x", 11)), http.StatusRequestEntityTooLarge},
+		{"chunked body above the limit", "/default", io.MultiReader(strings.NewReader(strings.Repeat("x", 11))), http.StatusRequestEntityTooLarge},
+		{"body within the limit of the route", "/bulk", strings.NewReader(strings.Repeat("x", 50)), http.StatusCreated},
+	} {
+		req := httptest.NewRequest(http.MethodPost, test.path, test.body)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != test.Status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.Status, rr.Code)
+			continue
+		}
+		if rr.Code == http.StatusCreated && rr.Header().Get("X-Echo") != "true" {
+			t.Errorf("%s: expected the headers of the handler", test.name)
+		}
+	}
+}
+
+func TestServer_limitsMiddlewareTimeouts(t *testing.T) {
+	cancelled := make(chan bool, 1)
+	router := limitsRouter(cancelled)
+
+	// a client sending its body too slowly
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/default", reader))
+	if rr.Code != http.StatusRequestTimeout || rr.Header().Get("Connection") != "close" {
+		t.Errorf("expected a slow body to time out, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rr.Code != http.StatusRequestTimeout {
+		t.Errorf("expected a slow handler to time out, got %d", rr.Code)
+	}
+	if !<-cancelled {
+		t.Errorf("expected the context of the handler to be cancelled")
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	if rr.Code != http.StatusOK || <-cancelled {
+		t.Errorf("expected no handler timeout on the route, got %d", rr.Code)
+	}
+}
+
+func TestServer_limitsMiddlewareDueRunsPoll(t *testing.T) {
+	config := conf.NewConfig(conf.WithHttpServerConfig(conf.HttpServerConfig{
+		Default: conf.RouteLimits{HandlerTimeoutMillis: 100},
+	}))
+	config.PullDeliveryConfig.MaxWaitSeconds = 1
+	s := &Server{service: &service.Service{
+		Config:      config,
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}}
+
+	router := mux.NewRouter()
+	router.Use(s.limitsMiddleware)
+	router.HandleFunc("/goscheduler/apps/{appId}/due", s.service.GetDueRuns).Name(constants.GetDueRuns)
+
+	// a poll waiting as long as it may outlives the default handler timeout and still ends with its empty runs
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/goscheduler/apps/empty/due?waitSeconds=1", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"runs":[]`) {
+		t.Errorf("expected the poll to end with no runs, got %d %s", rr.Code, rr.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the poll to wait for due runs, returned after %s", elapsed)
+	}
+}
+
+func TestServer_limitsMiddlewareFlushedResponse(t *testing.T) {
+	s := &Server{service: &service.Service{Config: conf.NewConfig(conf.WithHttpServerConfig(conf.HttpServerConfig{
+		Default: conf.RouteLimits{HandlerTimeoutMillis: 100},
+	}))}}
+	timedOut, written := make(chan struct{}), make(chan error, 1)
+	stream := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"id\": 1}\n"))
+		w.(http.Flusher).Flush()
+
+		<-timedOut
+		_, err := w.Write([]byte("{\"id\": 2}\n"))
+		written <- err
+	}
+
+	router := mux.NewRouter()
+	router.Use(s.limitsMiddleware)
+	router.HandleFunc("/stream", stream).Name("stream")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	close(timedOut)
+	if err := <-written; err != http.ErrHandlerTimeout {
+		t.Errorf("expected the writes past the timeout to fail, got %v", err)
+	}
+
+	// the records flushed are written as they go, and the response is cut short at the timeout rather than replaced
+	if rr.Code != http.StatusOK || !rr.Flushed || rr.Body.String() != "{\"id\": 1}\n" {
+		t.Errorf("expected the flushed record, got %d %q", rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("expected the headers of the handler, got %v", rr.Header())
+	}
+}
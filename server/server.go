@@ -22,11 +22,16 @@ package server
 import (
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/service"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/ui"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -46,6 +51,23 @@ func NewHTTPServer(port string, router *mux.Router, service *service.Service) *S
 	return server
 }
 
+// maxRequestIdLength bounds the client supplied request ids copied into every log line
+const maxRequestIdLength = 128
+
+// requestIDMiddleware propagates the X-Request-ID of the request, or a generated one, through the request context
+// so that every line logged for the request carries it, and echoes it in the response
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(logger.RequestIDHeader)
+		if requestId == "" || len(requestId) > maxRequestIdLength {
+			requestId = logger.NewRequestID()
+		}
+
+		w.Header().Set(logger.RequestIDHeader, requestId)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), requestId)))
+	})
+}
+
 func responseMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
@@ -68,97 +90,542 @@ func (s *Server) monitoringMiddleware(operationName string, next http.HandlerFun
 }
 
 func (s *Server) registerHTTPHandlers() {
+	s.router.Use(requestIDMiddleware)
 	s.router.Use(responseMiddleware)
+	s.router.Use(compressionMiddleware)
+	s.router.Use(service.FieldSelection)
+	s.router.Use(s.limitsMiddleware)
 
-	s.router.HandleFunc("/goscheduler/healthcheck", service.HealthCheck)
+	s.router.HandleFunc("/goscheduler/healthcheck", service.HealthCheck).Name(constants.HealthCheck)
+	s.router.HandleFunc("/goscheduler/readiness", s.service.Readiness).Methods("GET").Name(constants.Readiness)
 
 	s.router.HandleFunc("/goscheduler/schedules",
 		s.monitoringMiddleware(constants.CreateSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.Post(w, r)
 		}),
-	).Methods("POST")
+	).Methods("POST").Name(constants.CreateSchedule)
+
+	s.router.HandleFunc("/goscheduler/schedules/validate",
+		s.monitoringMiddleware(constants.ValidateSchedule, func(w http.ResponseWriter, r *http.Request) {
+			s.service.Validate(w, r)
+		}),
+	).Methods("POST").Name(constants.ValidateSchedule)
+
+	s.router.HandleFunc("/goscheduler/schedules/simulate",
+		s.monitoringMiddleware(constants.SimulateSchedule, func(w http.ResponseWriter, r *http.Request) {
+			s.service.Simulate(w, r)
+		}),
+	).Methods("POST").Name(constants.SimulateSchedule)
 
 	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}",
 		s.monitoringMiddleware(constants.GetSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.Get(w, r)
 		}),
-	).Methods("GET")
+	).Methods("GET").Name(constants.GetSchedule)
 
 	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/runs",
 		s.monitoringMiddleware(constants.GetScheduleRuns, func(w http.ResponseWriter, r *http.Request) {
 			s.service.GetRuns(w, r)
 		}),
-	).Methods("GET")
+	).Methods("GET").Name(constants.GetScheduleRuns)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/receipts",
+		s.monitoringMiddleware(constants.GetScheduleReceipts, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetReceipts(w, r)
+		}),
+	).Methods("GET").Name(constants.GetScheduleReceipts)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/attempts",
+		s.monitoringMiddleware(constants.GetScheduleAttempts, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetAttempts(w, r)
+		}),
+	).Methods("GET").Name(constants.GetScheduleAttempts)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/canary",
+		s.monitoringMiddleware(constants.GetCallbackCanary, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetCallbackCanary(w, r)
+		}),
+	).Methods("GET").Name(constants.GetCallbackCanary)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/versions",
+		s.monitoringMiddleware(constants.GetScheduleVersions, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetScheduleVersions(w, r)
+		}),
+	).Methods("GET").Name(constants.GetScheduleVersions)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/rollback/{version}",
+		s.monitoringMiddleware(constants.RollbackSchedule, func(w http.ResponseWriter, r *http.Request) {
+			s.service.RollbackSchedule(w, r)
+		}),
+	).Methods("POST").Name(constants.RollbackSchedule)
 
 	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/updateRecurringSchedule",
 		s.monitoringMiddleware(constants.UpdateRecurringSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.UpdateRecurringSchedule(w, r)
 		}),
-	).Methods("PUT")
+	).Methods("PUT").Name(constants.UpdateRecurringSchedule)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}",
+		s.monitoringMiddleware(constants.ReplaceSchedule, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ReplaceSchedule(w, r)
+		}),
+	).Methods("PUT").Name(constants.ReplaceSchedule)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}",
+		s.monitoringMiddleware(constants.PatchSchedule, func(w http.ResponseWriter, r *http.Request) {
+			s.service.PatchSchedule(w, r)
+		}),
+	).Methods("PATCH").Name(constants.PatchSchedule)
 
 	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/pause",
 		s.monitoringMiddleware(constants.PauseSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.PauseSchedule(w, r)
 		}),
-	).Methods("PUT")
+	).Methods("PUT").Name(constants.PauseSchedule)
 
 	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/resume",
 		s.monitoringMiddleware(constants.ResumeSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.ResumeSchedule(w, r)
 		}),
-	).Methods("PUT")
+	).Methods("PUT").Name(constants.ResumeSchedule)
+
+	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}/activate",
+		s.monitoringMiddleware(constants.ActivateSchedule, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ActivateSchedule(w, r)
+		}),
+	).Methods("PUT").Name(constants.ActivateSchedule)
 
 	s.router.HandleFunc("/goscheduler/apps/{appId}/schedules",
 		s.monitoringMiddleware(constants.GetAppSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.GetAppSchedules(w, r)
 		}),
-	).Methods("GET")
+	).Methods("GET").Name(constants.GetAppSchedule)
 
 	s.router.HandleFunc("/goscheduler/schedules/{scheduleId}",
 		s.monitoringMiddleware(constants.DeleteSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.CancelSchedule(w, r)
 		}),
-	).Methods("DELETE")
+	).Methods("DELETE").Name(constants.DeleteSchedule)
 
 	s.router.HandleFunc("/goscheduler/apps",
 		s.monitoringMiddleware(constants.RegisterApp, func(w http.ResponseWriter, r *http.Request) {
 			s.service.Register(w, r)
 		}),
-	).Methods("POST")
+	).Methods("POST").Name(constants.RegisterApp)
 
 	s.router.HandleFunc("/goscheduler/apps/{appId}/deactivate",
 		s.monitoringMiddleware(constants.DeactivateApp, func(w http.ResponseWriter, r *http.Request) {
 			s.service.Deactivate(w, r)
 		}),
-	).Methods("POST")
+	).Methods("POST").Name(constants.DeactivateApp)
 
 	s.router.HandleFunc("/goscheduler/apps/{appId}/activate",
 		s.monitoringMiddleware(constants.ActivateApp, func(w http.ResponseWriter, r *http.Request) {
 			s.service.Activate(w, r)
 		}),
-	).Methods("POST")
+	).Methods("POST").Name(constants.ActivateApp)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/clone",
+		s.monitoringMiddleware(constants.CloneApp, func(w http.ResponseWriter, r *http.Request) {
+			s.service.CloneApp(w, r)
+		}),
+	).Methods("POST").Name(constants.CloneApp)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/offboard",
+		s.monitoringMiddleware(constants.OffboardApp, func(w http.ResponseWriter, r *http.Request) {
+			s.service.WithApproval(store.DeleteAppOperation, w, r)
+		}),
+	).Methods("POST").Name(constants.OffboardApp)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/declared-schedules",
+		s.monitoringMiddleware(constants.ApplySchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ApplySchedules(w, r)
+		}),
+	).Methods("PUT").Name(constants.ApplySchedules)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/partitions",
+		s.monitoringMiddleware(constants.ResizeAppPartitions, func(w http.ResponseWriter, r *http.Request) {
+			s.service.WithApproval(store.ResizePartitionsOperation, w, r)
+		}),
+	).Methods("PUT").Name(constants.ResizeAppPartitions)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/partitions",
+		s.monitoringMiddleware(constants.GetPartitionMigration, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetPartitionMigration(w, r)
+		}),
+	).Methods("GET").Name(constants.GetPartitionMigration)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/export",
+		s.monitoringMiddleware(constants.ExportSchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ExportSchedules(w, r)
+		}),
+	).Methods("GET").Name(constants.ExportSchedules)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/import",
+		s.monitoringMiddleware(constants.ImportSchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ImportSchedules(w, r)
+		}),
+	).Methods("POST").Name(constants.ImportSchedules)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/usage",
+		s.monitoringMiddleware(constants.GetAppUsage, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetAppUsage(w, r)
+		}),
+	).Methods("GET").Name(constants.GetAppUsage)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/connections",
+		s.monitoringMiddleware(constants.GetAppConnections, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetAppConnections(w, r)
+		}),
+	).Methods("GET").Name(constants.GetAppConnections)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/connections",
+		s.monitoringMiddleware(constants.TuneAppConnections, func(w http.ResponseWriter, r *http.Request) {
+			s.service.TuneAppConnections(w, r)
+		}),
+	).Methods("PUT").Name(constants.TuneAppConnections)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/runs",
+		s.monitoringMiddleware(constants.GetAppRunStats, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetAppRunStats(w, r)
+		}),
+	).Methods("GET").Name(constants.GetAppRunStats)
+
+	s.router.HandleFunc("/goscheduler/graphql",
+		s.monitoringMiddleware(constants.GraphQLQuery, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GraphQL(w, r)
+		}),
+	).Methods("POST").Name(constants.GraphQLQuery)
+
+	s.router.HandleFunc("/goscheduler/graphql/schema",
+		s.monitoringMiddleware(constants.GetGraphQLSchema, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetGraphQLSchema(w, r)
+		}),
+	).Methods("GET").Name(constants.GetGraphQLSchema)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/due",
+		s.monitoringMiddleware(constants.GetDueRuns, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetDueRuns(w, r)
+		}),
+	).Methods("GET").Name(constants.GetDueRuns)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/due/{scheduleId}/ack",
+		s.monitoringMiddleware(constants.AckDueRun, func(w http.ResponseWriter, r *http.Request) {
+			s.service.AckDueRun(w, r)
+		}),
+	).Methods("POST").Name(constants.AckDueRun)
+
+	s.router.HandleFunc("/goscheduler/locks",
+		s.monitoringMiddleware(constants.AcquireLock, func(w http.ResponseWriter, r *http.Request) {
+			s.service.AcquireLock(w, r)
+		}),
+	).Methods("POST").Name(constants.AcquireLock)
+
+	s.router.HandleFunc("/goscheduler/locks/{appId}/{name}",
+		s.monitoringMiddleware(constants.ReleaseLock, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ReleaseLock(w, r)
+		}),
+	).Methods("DELETE").Name(constants.ReleaseLock)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/events/stream",
+		s.monitoringMiddleware(constants.StreamEvents, func(w http.ResponseWriter, r *http.Request) {
+			s.service.StreamEvents(w, r)
+		}),
+	).Methods("GET").Name(constants.StreamEvents)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/templates",
+		s.monitoringMiddleware(constants.CreateCallbackTemplate, func(w http.ResponseWriter, r *http.Request) {
+			s.service.CreateCallbackTemplate(w, r)
+		}),
+	).Methods("POST").Name(constants.CreateCallbackTemplate)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/templates",
+		s.monitoringMiddleware(constants.GetCallbackTemplates, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetCallbackTemplates(w, r)
+		}),
+	).Methods("GET").Name(constants.GetCallbackTemplates)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/templates/{name}",
+		s.monitoringMiddleware(constants.UpdateCallbackTemplate, func(w http.ResponseWriter, r *http.Request) {
+			s.service.UpdateCallbackTemplate(w, r)
+		}),
+	).Methods("PUT").Name(constants.UpdateCallbackTemplate)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/templates/{name}",
+		s.monitoringMiddleware(constants.GetCallbackTemplate, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetCallbackTemplate(w, r)
+		}),
+	).Methods("GET").Name(constants.GetCallbackTemplate)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups",
+		s.monitoringMiddleware(constants.CreateScheduleGroup, func(w http.ResponseWriter, r *http.Request) {
+			s.service.CreateScheduleGroup(w, r)
+		}),
+	).Methods("POST").Name(constants.CreateScheduleGroup)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups",
+		s.monitoringMiddleware(constants.GetScheduleGroups, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetScheduleGroups(w, r)
+		}),
+	).Methods("GET").Name(constants.GetScheduleGroups)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups/{name}",
+		s.monitoringMiddleware(constants.UpdateScheduleGroup, func(w http.ResponseWriter, r *http.Request) {
+			s.service.UpdateScheduleGroup(w, r)
+		}),
+	).Methods("PUT").Name(constants.UpdateScheduleGroup)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups/{name}",
+		s.monitoringMiddleware(constants.GetScheduleGroup, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetScheduleGroup(w, r)
+		}),
+	).Methods("GET").Name(constants.GetScheduleGroup)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups/{name}",
+		s.monitoringMiddleware(constants.DeleteScheduleGroup, func(w http.ResponseWriter, r *http.Request) {
+			s.service.DeleteScheduleGroup(w, r)
+		}),
+	).Methods("DELETE").Name(constants.DeleteScheduleGroup)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups/{name}/pause",
+		s.monitoringMiddleware(constants.PauseScheduleGroup, func(w http.ResponseWriter, r *http.Request) {
+			s.service.PauseScheduleGroup(w, r)
+		}),
+	).Methods("POST").Name(constants.PauseScheduleGroup)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/groups/{name}/resume",
+		s.monitoringMiddleware(constants.ResumeScheduleGroup, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ResumeScheduleGroup(w, r)
+		}),
+	).Methods("POST").Name(constants.ResumeScheduleGroup)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/suspended",
+		s.monitoringMiddleware(constants.GetSuspendedSchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetSuspendedSchedules(w, r)
+		}),
+	).Methods("GET").Name(constants.GetSuspendedSchedules)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/suspended/resume",
+		s.monitoringMiddleware(constants.ResumeSuspendedSchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ResumeSuspendedSchedules(w, r)
+		}),
+	).Methods("POST").Name(constants.ResumeSuspendedSchedules)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/approvals",
+		s.monitoringMiddleware(constants.GetApprovals, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetApprovals(w, r)
+		}),
+	).Methods("GET").Name(constants.GetApprovals)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/approvals/{approvalId}",
+		s.monitoringMiddleware(constants.GetApproval, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetApproval(w, r)
+		}),
+	).Methods("GET").Name(constants.GetApproval)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/approvals/{approvalId}/approve",
+		s.monitoringMiddleware(constants.ApproveRequest, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ApproveRequest(w, r)
+		}),
+	).Methods("POST").Name(constants.ApproveRequest)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/approvals/{approvalId}/reject",
+		s.monitoringMiddleware(constants.RejectRequest, func(w http.ResponseWriter, r *http.Request) {
+			s.service.RejectRequest(w, r)
+		}),
+	).Methods("POST").Name(constants.RejectRequest)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/verified-urls",
+		s.monitoringMiddleware(constants.VerifyCallbackUrl, func(w http.ResponseWriter, r *http.Request) {
+			s.service.VerifyCallbackUrl(w, r)
+		}),
+	).Methods("POST").Name(constants.VerifyCallbackUrl)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/verified-urls",
+		s.monitoringMiddleware(constants.GetVerifiedUrls, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetVerifiedUrls(w, r)
+		}),
+	).Methods("GET").Name(constants.GetVerifiedUrls)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/verified-urls",
+		s.monitoringMiddleware(constants.DeleteVerifiedUrl, func(w http.ResponseWriter, r *http.Request) {
+			s.service.DeleteVerifiedUrl(w, r)
+		}),
+	).Methods("DELETE").Name(constants.DeleteVerifiedUrl)
+
+	s.router.HandleFunc("/goscheduler/apps/{appId}/bulk-update",
+		s.monitoringMiddleware(constants.BulkUpdateSchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.BulkUpdateSchedules(w, r)
+		}),
+	).Methods("POST").Name(constants.BulkUpdateSchedules)
+
+	s.router.HandleFunc("/goscheduler/jobs/{jobId}",
+		s.monitoringMiddleware(constants.GetJob, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetJob(w, r)
+		}),
+	).Methods("GET").Name(constants.GetJob)
+
+	s.router.HandleFunc("/goscheduler/jobs/{jobId}/cancel",
+		s.monitoringMiddleware(constants.CancelJob, func(w http.ResponseWriter, r *http.Request) {
+			s.service.CancelJob(w, r)
+		}),
+	).Methods("POST").Name(constants.CancelJob)
+
+	s.router.HandleFunc("/goscheduler/jobs/{jobId}/retry",
+		s.monitoringMiddleware(constants.RetryJob, func(w http.ResponseWriter, r *http.Request) {
+			s.service.RetryJob(w, r)
+		}),
+	).Methods("POST").Name(constants.RetryJob)
 
 	s.router.HandleFunc("/goscheduler/apps/{appId}/bulk-action/{action}",
 		s.monitoringMiddleware(constants.BulkAction, func(w http.ResponseWriter, r *http.Request) {
-			s.service.BulkAction(w, r)
+			s.service.WithApproval(store.BulkDeleteOperation, w, r)
 		}),
-	).Methods("POST")
+	).Methods("POST").Name(constants.BulkAction)
 
 	s.router.HandleFunc("/goscheduler/apps",
 		s.monitoringMiddleware(constants.GetApps, func(w http.ResponseWriter, r *http.Request) {
 			s.service.GetApps(w, r)
 		}),
-	).Methods("GET")
+	).Methods("GET").Name(constants.GetApps)
 
 	s.router.HandleFunc("/goscheduler/crons/schedules",
 		s.monitoringMiddleware(constants.GetCronSchedule, func(w http.ResponseWriter, r *http.Request) {
 			s.service.GetCronSchedules(w, r)
 		}),
-	).Methods("GET")
+	).Methods("GET").Name(constants.GetCronSchedule)
+
+	s.router.HandleFunc("/goscheduler/admin/diagnostics",
+		s.monitoringMiddleware(constants.GetDiagnostics, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetDiagnostics(w, r)
+		}),
+	).Methods("GET").Name(constants.GetDiagnostics)
 
-	s.router.Handle("/metrics", promhttp.Handler())
+	s.router.HandleFunc("/goscheduler/cluster/nodes",
+		s.monitoringMiddleware(constants.GetClusterNodes, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetClusterNodes(w, r)
+		}),
+	).Methods("GET").Name(constants.GetClusterNodes)
+
+	s.router.HandleFunc("/goscheduler/cluster/partitions",
+		s.monitoringMiddleware(constants.GetClusterPartitions, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetClusterPartitions(w, r)
+		}),
+	).Methods("GET").Name(constants.GetClusterPartitions)
+
+	s.router.HandleFunc("/goscheduler/cluster/nodes/{node}/cordon",
+		s.monitoringMiddleware(constants.CordonNode, func(w http.ResponseWriter, r *http.Request) {
+			s.service.CordonNode(w, r)
+		}),
+	).Methods("PUT").Name(constants.CordonNode)
+
+	s.router.HandleFunc("/goscheduler/cluster/nodes/{node}/cordon",
+		s.monitoringMiddleware(constants.UncordonNode, func(w http.ResponseWriter, r *http.Request) {
+			s.service.UncordonNode(w, r)
+		}),
+	).Methods("DELETE").Name(constants.UncordonNode)
+
+	s.router.HandleFunc("/goscheduler/cluster/nodes/{node}/drain",
+		s.monitoringMiddleware(constants.DrainNode, func(w http.ResponseWriter, r *http.Request) {
+			s.service.DrainNode(w, r)
+		}),
+	).Methods("POST").Name(constants.DrainNode)
+
+	s.router.HandleFunc("/goscheduler/admin/replication",
+		s.monitoringMiddleware(constants.GetReplicationStatus, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetReplicationStatus(w, r)
+		}),
+	).Methods("GET").Name(constants.GetReplicationStatus)
+
+	s.router.HandleFunc("/goscheduler/admin/replication/promote",
+		s.monitoringMiddleware(constants.PromoteReplica, func(w http.ResponseWriter, r *http.Request) {
+			s.service.PromoteReplica(w, r)
+		}),
+	).Methods("POST").Name(constants.PromoteReplica)
+
+	s.router.HandleFunc("/goscheduler/admin/shadow",
+		s.monitoringMiddleware(constants.GetShadowReport, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetShadowReport(w, r)
+		}),
+	).Methods("GET").Name(constants.GetShadowReport)
+
+	s.router.HandleFunc("/goscheduler/admin/shadow/reconcile",
+		s.monitoringMiddleware(constants.ReconcileShadow, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ReconcileShadow(w, r)
+		}),
+	).Methods("POST").Name(constants.ReconcileShadow)
+
+	s.router.HandleFunc("/goscheduler/admin/clock",
+		s.monitoringMiddleware(constants.GetClock, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetClock(w, r)
+		}),
+	).Methods("GET").Name(constants.GetClock)
+
+	s.router.HandleFunc("/goscheduler/admin/clock/advance",
+		s.monitoringMiddleware(constants.AdvanceClock, func(w http.ResponseWriter, r *http.Request) {
+			s.service.AdvanceClock(w, r)
+		}),
+	).Methods("POST").Name(constants.AdvanceClock)
+
+	s.router.HandleFunc("/goscheduler/admin/apps/{appId}/reconcile",
+		s.monitoringMiddleware(constants.ReconcileMissedSchedules, func(w http.ResponseWriter, r *http.Request) {
+			s.service.ReconcileMissedSchedules(w, r)
+		}),
+	).Methods("POST").Name(constants.ReconcileMissedSchedules)
+
+	s.router.HandleFunc("/goscheduler/admin/apps/{appId}/duplicates",
+		s.monitoringMiddleware(constants.GetDuplicateFires, func(w http.ResponseWriter, r *http.Request) {
+			s.service.GetDuplicateFires(w, r)
+		}),
+	).Methods("GET").Name(constants.GetDuplicateFires)
+
+	s.registerOpenAPIHandler()
+
+	// OpenMetrics exposes the trace ids of the callback timings as exemplars to the scrapers asking for it
+	s.router.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
+
+	if s.service.Config != nil && s.service.Config.AdminUIConfig.Enabled {
+		s.router.Handle(strings.TrimSuffix(ui.Path, "/"), http.RedirectHandler(ui.Path, http.StatusMovedPermanently))
+		s.router.PathPrefix(ui.Path).Handler(ui.Handler()).Methods("GET")
+	}
+}
+
+// registerOpenAPIHandler serves the OpenAPI spec of the named routes registered so far,
+// so it has to be registered after the API routes
+func (s *Server) registerOpenAPIHandler() {
+	var spec []byte
+	s.router.HandleFunc("/goscheduler/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(spec)
+	}).Methods("GET").Name(constants.GetOpenAPISpec)
+
+	var routes []service.Route
+	_ = s.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if route.GetName() == "" || err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet}
+		}
+		routes = append(routes, service.Route{Name: route.GetName(), Path: path, Methods: methods})
+		return nil
+	})
+
+	var err error
+	if spec, err = service.OpenAPISpec(routes); err != nil {
+		logger.Errorf("Error generating the OpenAPI spec: %+v", err)
+	}
 }
 
 func (s *Server) StartServer() {
-	log.Fatal(http.ListenAndServe(":"+s.port, s.router))
+	config := s.service.Config.HttpServerConfig
+	server := &http.Server{
+		Addr:              ":" + s.port,
+		Handler:           s.router,
+		ReadHeaderTimeout: time.Duration(config.ReadHeaderTimeoutMillis) * time.Millisecond,
+		IdleTimeout:       time.Duration(config.IdleTimeoutMillis) * time.Millisecond,
+	}
+	log.Fatal(server.ListenAndServe())
 }
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// ActivateSchedule activates a recurring schedule created as a DRAFT by updating its status to SCHEDULED
+// The runs of the schedule are created from the next poll of the recurring schedules onwards
+func (s *Service) ActivateSchedule(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	uuid, err := gocql.ParseUUID(mux.Vars(r)["scheduleId"])
+	if err != nil {
+		s.recordRequestStatus(constants.ActivateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	schedule, err := s.ScheduleDao.GetSchedule(uuid)
+	if err != nil {
+		s.recordRequestStatus(constants.ActivateSchedule, constants.Fail)
+		if err == gocql.ErrNotFound {
+			er.Handle(w, r, er.NewError(er.DataNotFound, fmt.Errorf("Schedule with id: %s not found", uuid)))
+		} else {
+			log.Errorf("Error fetching schedule with id %s: %v", uuid, err)
+			er.Handle(w, r, er.NewError(er.DataPersistenceFailure, err))
+		}
+		return
+	}
+
+	schedule.RequestId = logger.RequestID(r.Context())
+
+	if !schedule.IsRecurring() {
+		s.recordRequestStatus(constants.ActivateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnprocessableEntity, fmt.Errorf("Schedule with id: %s is not a recurring schedule", uuid)))
+		return
+	}
+
+	if !schedule.IsDraft() {
+		s.recordRequestStatus(constants.ActivateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.Conflict, fmt.Errorf("Schedule with id: %s is not a draft", uuid)))
+		return
+	}
+
+	updatedSchedule, err := s.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Scheduled)
+	if err != nil {
+		log.Errorf("Error activating schedule with id %s: %v", uuid, err)
+		s.recordRequestStatus(constants.ActivateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataPersistenceFailure, err))
+		return
+	}
+
+	store.PublishEvent(store.ScheduleActivated, updatedSchedule)
+	log.Debugf("Schedule with id %s activated", uuid.String())
+	s.recordRequestStatus(constants.ActivateSchedule, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: "Schedule activated successfully",
+		StatusType:    constants.Success,
+		TotalCount:    1,
+	}
+	_ = json.NewEncoder(w).Encode(
+		ScheduleResponse{
+			Status: status,
+			Data:   ScheduleData{Schedule: updatedSchedule},
+		})
+}
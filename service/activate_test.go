@@ -0,0 +1,86 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+type MockScheduleDaoForActivate struct {
+	dao.DummyScheduleDaoImpl
+}
+
+func (m *MockScheduleDaoForActivate) GetSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	switch uuid.String() {
+	case "00000000-0000-0000-0000-000000000000":
+		return store.Schedule{}, gocql.ErrNotFound
+	case "11111111-1111-1111-1111-111111111111":
+		return store.Schedule{ScheduleId: uuid, AppId: "testApp", Status: store.Draft}, nil
+	case "22222222-2222-2222-2222-222222222222":
+		return store.Schedule{ScheduleId: uuid, AppId: "testApp", CronExpression: "0 0 * * *", Status: store.Scheduled}, nil
+	case "33333333-3333-3333-3333-333333333333":
+		return store.Schedule{ScheduleId: uuid, AppId: "testDbError", CronExpression: "0 0 * * *", Status: store.Draft}, nil
+	default:
+		return store.Schedule{ScheduleId: uuid, AppId: "testApp", CronExpression: "0 0 * * *", Status: store.Draft}, nil
+	}
+}
+
+func (m *MockScheduleDaoForActivate) UpdateRecurringScheduleStatus(schedule store.Schedule, status store.Status) (store.Schedule, error) {
+	UpdateRecurringScheduleStatusCallCount++
+	LastUpdateRecurringScheduleStatusArgs.Schedule = schedule
+	LastUpdateRecurringScheduleStatusArgs.Status = status
+
+	if schedule.AppId == "testDbError" {
+		return schedule, gocql.ErrNotFound
+	}
+	schedule.Status = status
+	return schedule, nil
+}
+
+func TestService_ActivateSchedule(t *testing.T) {
+	service := setupMocks()
+	service.ScheduleDao = &MockScheduleDaoForActivate{}
+
+	for _, test := range []struct {
+		name         string
+		scheduleID   string
+		wantStatus   int
+		shouldUpdate bool
+	}{
+		{"InvalidUUID", "invalid-uuid", http.StatusBadRequest, false},
+		{"NonExistentSchedule", "00000000-0000-0000-0000-000000000000", http.StatusNotFound, false},
+		{"NonRecurringSchedule", "11111111-1111-1111-1111-111111111111", http.StatusUnprocessableEntity, false},
+		{"NotDraft", "22222222-2222-2222-2222-222222222222", http.StatusConflict, false},
+		{"DatabaseError", "33333333-3333-3333-3333-333333333333", http.StatusInternalServerError, true},
+		{"Activated", "44444444-4444-4444-4444-444444444444", http.StatusOK, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			UpdateRecurringScheduleStatusCallCount = 0
+
+			req, err := http.NewRequest("PUT", "/goscheduler/schedules/{scheduleId}/activate", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"scheduleId": test.scheduleID})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.ActivateSchedule).ServeHTTP(rr, req)
+
+			if rr.Code != test.wantStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, test.wantStatus)
+			}
+
+			if updated := UpdateRecurringScheduleStatusCallCount > 0; updated != test.shouldUpdate {
+				t.Errorf("expected status update: %v, got %v", test.shouldUpdate, updated)
+			}
+			if test.shouldUpdate && LastUpdateRecurringScheduleStatusArgs.Status != store.Scheduled {
+				t.Errorf("expected status %s, got %s", store.Scheduled, LastUpdateRecurringScheduleStatusArgs.Status)
+			}
+		})
+	}
+}
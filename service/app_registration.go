@@ -22,11 +22,11 @@ package service
 import (
 	"encoding/json"
 	"errors"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	e "github.com/myntra/goscheduler/cluster_entity"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 	"io/ioutil"
 	"net/http"
@@ -83,8 +83,8 @@ func (s *Service) RegisterApp(input store.App) (store.App, error) {
 		return store.App{}, er.NewError(er.DataPersistenceFailure, err)
 	}
 
-	glog.Infof("Creating entities for app %s", input.AppId)
-	err = s.createEntities(input)
+	logger.Infof("Creating entities for app %s", input.AppId)
+	err = s.createEntities(input, 0)
 	if err != nil {
 		return store.App{}, err
 	}
@@ -92,8 +92,9 @@ func (s *Service) RegisterApp(input store.App) (store.App, error) {
 	return input, nil
 }
 
-func (s *Service) createEntities(input store.App) error {
-	for partition := uint32(0); partition < input.Partitions; partition++ {
+// createEntities creates and boots the pollers of the partitions of the app starting at from.
+func (s *Service) createEntities(input store.App, from uint32) error {
+	for partition := from; partition < input.Partitions; partition++ {
 		entity := e.EntityInfo{
 			Id:      input.AppId + constants.PollerKeySep + strconv.Itoa(int(partition)),
 			Node:    s.Config.Cluster.Address,
@@ -185,10 +186,24 @@ func (s *Service) ActivateApp(appId string) error {
 		return er.NewError(er.InvalidAppId, errors.New("unregistered App"))
 	}
 
-	if app.Active {
+	if app.Active && app.Offboarding == nil {
 		return er.NewError(er.ActivatedApp, errors.New("app is already activated"))
 	}
 
+	// An offboarded app is activated again once its offboarding job is over, its cancelled schedules are not restored
+	if app.Offboarding != nil {
+		if err = s.checkNotOffboarding(app); err != nil {
+			return err
+		}
+		if err = s.ClusterDao.UpdateAppOffboarding(appId, nil); err != nil {
+			return er.NewError(er.DataPersistenceFailure, err)
+		}
+		if app.Active {
+			s.Supervisor.UpdateApp(appId)
+			return nil
+		}
+	}
+
 	err = s.ClusterDao.UpdateAppActiveStatus(appId, true)
 	if err != nil {
 		return er.NewError(er.DataPersistenceFailure, err)
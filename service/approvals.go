@@ -0,0 +1,316 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// approvedKey marks the replayed request of an approved operation, which executes without being held again
+type approvedKey struct{}
+
+// decisionRequest is the optional body of the approve and reject APIs
+type decisionRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// WithApproval executes a destructive operation at once when approvals are disabled. Otherwise the request is held
+// until a second principal approves it through the approvals API, and only then executed as it was received.
+func (s *Service) WithApproval(operation store.ApprovalOperation, w http.ResponseWriter, r *http.Request) {
+	handler := s.approvalHandler(operation)
+	if !s.Config.ApprovalConfig.Enabled || !requiresApproval(operation, r) || r.Context().Value(approvedKey{}) != nil {
+		handler(w, r)
+		return
+	}
+
+	approval, err := s.holdForApproval(operation, r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.HoldForApproval, mux.Vars(r)["appId"], constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Held %s of app %s requested by %s for approval %s", operation, approval.AppId, approval.RequestedBy, approval.ApprovalId)
+	s.recordRequestAppStatus(constants.HoldForApproval, approval.AppId, constants.Success)
+
+	w.WriteHeader(http.StatusAccepted)
+	status := Status{StatusCode: constants.SuccessCode202, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(ApprovalResponse{Status: status, Data: approval})
+}
+
+// approvalHandler returns the handler executing an operation.
+// Every operation held for approval has to be added here.
+func (s *Service) approvalHandler(operation store.ApprovalOperation) http.HandlerFunc {
+	switch operation {
+	case store.DeleteAppOperation:
+		return s.OffboardApp
+	case store.BulkDeleteOperation:
+		return s.BulkAction
+	case store.ResizePartitionsOperation:
+		return s.ResizePartitions
+	default:
+		return func(w http.ResponseWriter, r *http.Request) {
+			er.Handle(w, r, er.NewError(er.UnprocessableEntity, fmt.Errorf("operation %s cannot be executed", operation)))
+		}
+	}
+}
+
+// requiresApproval tells whether the request of an operation is destructive, bulk actions only are when they delete
+func requiresApproval(operation store.ApprovalOperation, r *http.Request) bool {
+	if operation == store.BulkDeleteOperation {
+		return store.ActionType(mux.Vars(r)["action"]) == store.Delete
+	}
+	return true
+}
+
+// holdForApproval records the request of an operation of an app as pending approval.
+// The request is validated when it is executed.
+func (s *Service) holdForApproval(operation store.ApprovalOperation, r *http.Request) (store.Approval, error) {
+	config := s.Config.ApprovalConfig
+	vars := mux.Vars(r)
+
+	principal := r.Header.Get(config.PrincipalHeader)
+	if principal == "" {
+		return store.Approval{}, er.NewError(er.Forbidden, fmt.Errorf("%s needs approval, the requesting principal is required in the %s header", operation, config.PrincipalHeader))
+	}
+
+	if _, err := s.getActiveOrInactiveApp(vars["appId"]); err != nil {
+		return store.Approval{}, err
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return store.Approval{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	request := store.ApprovalRequest{Method: r.Method, Path: r.URL.RequestURI(), Vars: vars, Body: string(body)}
+	approval := store.NewApproval(vars["appId"], operation, request, principal, time.Now(), time.Duration(config.ExpiryPeriod)*time.Second)
+	if err = s.ClusterDao.CreateApproval(approval, config.RetentionPeriod); err != nil {
+		return store.Approval{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	return approval, nil
+}
+
+// GetApprovals returns the requests of an app held for approval, latest first, optionally filtered by status
+func (s *Service) GetApprovals(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+	status := store.ApprovalStatus(strings.ToUpper(r.URL.Query().Get("status")))
+
+	approvals, err := s.ClusterDao.GetApprovals(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetApprovals, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+
+	filtered := make([]store.Approval, 0, len(approvals))
+	for _, approval := range approvals {
+		if status == "" || approval.Status == status {
+			filtered = append(filtered, approval)
+		}
+	}
+
+	s.recordRequestAppStatus(constants.GetApprovals, appId, constants.Success)
+
+	response := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(filtered)}
+	_ = json.NewEncoder(w).Encode(ApprovalsResponse{Status: response, Data: filtered})
+}
+
+// GetApproval returns a request of an app held for approval along with its audit trail
+func (s *Service) GetApproval(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	approval, err := s.fetchApproval(appId, vars["approvalId"])
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetApproval, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetApproval, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(ApprovalResponse{Status: status, Data: approval})
+}
+
+// ApproveRequest approves a request held for approval, which is then executed and its result recorded
+func (s *Service) ApproveRequest(w http.ResponseWriter, r *http.Request) {
+	s.decide(w, r, constants.ApproveRequest, store.ApprovalApproved)
+}
+
+// RejectRequest rejects a request held for approval, or withdraws it when called by its requester
+func (s *Service) RejectRequest(w http.ResponseWriter, r *http.Request) {
+	s.decide(w, r, constants.RejectRequest, store.ApprovalRejected)
+}
+
+func (s *Service) decide(w http.ResponseWriter, r *http.Request, requestName string, decision store.ApprovalStatus) {
+	log := logger.FromContext(r.Context())
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	var input decisionRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil && len(body) > 0 {
+		err = json.Unmarshal(body, &input)
+	}
+	if err != nil {
+		s.recordRequestAppStatus(requestName, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	approval, err := s.fetchApproval(appId, vars["approvalId"])
+	if err == nil {
+		principal := r.Header.Get(s.Config.ApprovalConfig.PrincipalHeader)
+		approval, err = s.DecideApproval(r.Context(), approval, principal, decision, input.Comment)
+	}
+	if err != nil {
+		s.recordRequestAppStatus(requestName, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	log.Infof("Approval %s of %s of app %s %s by %s", approval.ApprovalId, approval.Operation, appId, approval.Status, approval.DecidedBy)
+	s.recordRequestAppStatus(requestName, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(ApprovalResponse{Status: status, Data: approval})
+}
+
+// DecideApproval records the decision of a principal on a pending request and executes the request once approved.
+// A request is decided on only once, even when two principals decide on it concurrently, and a request which was
+// not approved in time expires.
+func (s *Service) DecideApproval(ctx context.Context, approval store.Approval, principal string, decision store.ApprovalStatus, comment string) (store.Approval, error) {
+	config := s.Config.ApprovalConfig
+	now := time.Now()
+
+	if approval.Status != store.ApprovalPending {
+		return store.Approval{}, er.NewError(er.Conflict, fmt.Errorf("approval %s is %s", approval.ApprovalId, approval.Status))
+	}
+	if approval.Expired(now) {
+		expired := approval
+		expired.Decide("", store.ApprovalExpired, "", now)
+		if _, err := s.ClusterDao.UpdateApproval(expired, store.ApprovalPending, config.RetentionPeriod); err != nil {
+			logger.FromContext(ctx).Errorf("Expiring approval %s failed with error %s", approval.ApprovalId, err.Error())
+		}
+		return store.Approval{}, er.NewError(er.Conflict, fmt.Errorf("approval %s expired", approval.ApprovalId))
+	}
+	if err := approval.CheckDecision(principal, decision, config.Approvers); err != nil {
+		return store.Approval{}, er.NewError(er.Forbidden, err)
+	}
+
+	approval.Decide(principal, decision, comment, now)
+	switch decided, err := s.ClusterDao.UpdateApproval(approval, store.ApprovalPending, config.RetentionPeriod); {
+	case err != nil:
+		return store.Approval{}, er.NewError(er.DataPersistenceFailure, err)
+	case !decided:
+		return store.Approval{}, er.NewError(er.Conflict, fmt.Errorf("approval %s was decided on concurrently", approval.ApprovalId))
+	}
+
+	if decision != store.ApprovalApproved {
+		return approval, nil
+	}
+
+	approval.Finish(s.executeApproval(ctx, approval), time.Now())
+	if _, err := s.ClusterDao.UpdateApproval(approval, store.ApprovalApproved, config.RetentionPeriod); err != nil {
+		logger.FromContext(ctx).Errorf("Recording the result of approval %s failed with error %s", approval.ApprovalId, err.Error())
+	}
+	return approval, nil
+}
+
+// executeApproval replays the request of an approved operation and returns its response
+func (s *Service) executeApproval(ctx context.Context, approval store.Approval) store.ApprovalResult {
+	request := approval.Request
+	replay, err := http.NewRequest(request.Method, request.Path, strings.NewReader(request.Body))
+	if err != nil {
+		body, _ := json.Marshal(err.Error())
+		return store.ApprovalResult{StatusCode: http.StatusInternalServerError, Body: body}
+	}
+	replay = mux.SetURLVars(replay.WithContext(context.WithValue(ctx, approvedKey{}, approval.ApprovalId)), request.Vars)
+	replay.Header.Set(s.Config.ApprovalConfig.PrincipalHeader, approval.RequestedBy)
+
+	recorder := &approvalRecorder{header: http.Header{}}
+	s.approvalHandler(approval.Operation)(recorder, replay)
+
+	result := store.ApprovalResult{StatusCode: recorder.status, Body: bytes.TrimSpace(recorder.body.Bytes())}
+	if result.StatusCode == 0 {
+		result.StatusCode = http.StatusOK
+	}
+	if len(result.Body) > 0 && !json.Valid(result.Body) {
+		result.Body, _ = json.Marshal(string(result.Body))
+	}
+	return result
+}
+
+func (s *Service) fetchApproval(appId string, approvalId string) (store.Approval, error) {
+	id, err := gocql.ParseUUID(approvalId)
+	if err != nil {
+		return store.Approval{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	switch approval, err := s.ClusterDao.GetApproval(appId, id); {
+	case err == gocql.ErrNotFound:
+		return store.Approval{}, er.NewError(er.DataNotFound, errors.New(fmt.Sprintf("approval %s of app %s does not exist", approvalId, appId)))
+	case err != nil:
+		return store.Approval{}, er.NewError(er.DataFetchFailure, err)
+	default:
+		return approval, nil
+	}
+}
+
+// approvalRecorder records the response of the replayed request of an approved operation
+type approvalRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *approvalRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *approvalRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *approvalRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
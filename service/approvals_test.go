@@ -0,0 +1,231 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockClusterDaoForApprovals keeps the approvals in memory
+type MockClusterDaoForApprovals struct {
+	dao.DummyClusterDaoImpl
+	mu        sync.Mutex
+	approvals map[gocql.UUID]store.Approval
+}
+
+func (m *MockClusterDaoForApprovals) CreateApproval(approval store.Approval, ttl int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvals[approval.ApprovalId] = approval
+	return nil
+}
+
+func (m *MockClusterDaoForApprovals) GetApproval(appId string, approvalId gocql.UUID) (store.Approval, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	approval, ok := m.approvals[approvalId]
+	if !ok || approval.AppId != appId {
+		return store.Approval{}, gocql.ErrNotFound
+	}
+	return approval, nil
+}
+
+func (m *MockClusterDaoForApprovals) UpdateApproval(approval store.Approval, from store.ApprovalStatus, ttl int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.approvals[approval.ApprovalId].Status != from {
+		return false, nil
+	}
+	m.approvals[approval.ApprovalId] = approval
+	return true, nil
+}
+
+func setupMocksForApprovals() (*Service, *MockClusterDaoForApprovals) {
+	service := setupMocks()
+	clusterDao := &MockClusterDaoForApprovals{approvals: map[gocql.UUID]store.Approval{}}
+	service.ClusterDao = clusterDao
+	service.Config.ApprovalConfig = conf.ApprovalConfig{
+		Enabled:         true,
+		PrincipalHeader: "X-Principal",
+		Approvers:       []string{"alice", "bob"},
+		ExpiryPeriod:    3600,
+	}
+	return service, clusterDao
+}
+
+func heldResizeRequest(principal string, body string) *http.Request {
+	req, _ := http.NewRequest("PUT", "/goscheduler/apps/testApp/partitions", bytes.NewBufferString(body))
+	req = mux.SetURLVars(req, map[string]string{"appId": "testApp"})
+	if principal != "" {
+		req.Header.Set("X-Principal", principal)
+	}
+	return req
+}
+
+func decideRequest(action string, approvalId string, principal string) *http.Request {
+	req, _ := http.NewRequest("POST", "/goscheduler/apps/testApp/approvals/"+approvalId+"/"+action, bytes.NewBufferString(`{"comment":"checked"}`))
+	req = mux.SetURLVars(req, map[string]string{"appId": "testApp", "approvalId": approvalId})
+	req.Header.Set("X-Principal", principal)
+	return req
+}
+
+func holdResize(t *testing.T, service *Service, body string) store.Approval {
+	rr := httptest.NewRecorder()
+	service.WithApproval(store.ResizePartitionsOperation, rr, heldResizeRequest("alice", body))
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("expected the resize to be held with status %d, got %d with body %s", http.StatusAccepted, rr.Code, rr.Body.String())
+	}
+
+	var response ApprovalResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	return response.Data
+}
+
+func TestService_WithApprovalHoldsDestructiveOperations(t *testing.T) {
+	service, clusterDao := setupMocksForApprovals()
+
+	approval := holdResize(t, service, `{"partitions":0}`)
+	if approval.Status != store.ApprovalPending || approval.RequestedBy != "alice" || approval.Request.Body != `{"partitions":0}` {
+		t.Errorf("unexpected approval %+v", approval)
+	}
+	if _, ok := clusterDao.approvals[approval.ApprovalId]; !ok {
+		t.Error("expected the approval to be stored")
+	}
+
+	rr := httptest.NewRecorder()
+	service.WithApproval(store.ResizePartitionsOperation, rr, heldResizeRequest("", `{"partitions":0}`))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected a request without principal to be forbidden, got %d", rr.Code)
+	}
+
+	// bulk actions other than delete are not destructive
+	for action, destructive := range map[string]bool{"delete": true, "reconcile": false} {
+		req := mux.SetURLVars(httptest.NewRequest("POST", "/goscheduler/apps/testApp/bulk-action/"+action, nil), map[string]string{"appId": "testApp", "action": action})
+		if requiresApproval(store.BulkDeleteOperation, req) != destructive {
+			t.Errorf("%s: expected approval required %t", action, destructive)
+		}
+	}
+
+	service.Config.ApprovalConfig.Enabled = false
+	rr = httptest.NewRecorder()
+	service.WithApproval(store.ResizePartitionsOperation, rr, heldResizeRequest("", `{"partitions":0}`))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected the resize to execute when approvals are disabled, got %d with body %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestService_ApproveRequest(t *testing.T) {
+	service, clusterDao := setupMocksForApprovals()
+	approval := holdResize(t, service, `{"partitions":0}`)
+	id := approval.ApprovalId.String()
+
+	for _, test := range []struct {
+		principal string
+		status    int
+	}{
+		{"alice", http.StatusForbidden},
+		{"carol", http.StatusForbidden},
+		{"bob", http.StatusOK},
+		{"bob", http.StatusConflict},
+	} {
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.ApproveRequest).ServeHTTP(rr, decideRequest("approve", id, test.principal))
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.principal, test.status, rr.Code, rr.Body.String())
+		}
+	}
+
+	// the replayed resize is rejected as 0 partitions are invalid, which is recorded as the result of the approval
+	executed := clusterDao.approvals[approval.ApprovalId]
+	if executed.Status != store.ApprovalFailed || executed.DecidedBy != "bob" || executed.Result == nil || executed.Result.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected executed approval %+v", executed)
+	}
+	if len(executed.Events) != 3 || executed.Events[1].Comment != "checked" {
+		t.Errorf("unexpected audit trail %+v", executed.Events)
+	}
+}
+
+func TestService_RejectRequest(t *testing.T) {
+	service, clusterDao := setupMocksForApprovals()
+	approval := holdResize(t, service, `{"partitions":4}`)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.RejectRequest).ServeHTTP(rr, decideRequest("reject", approval.ApprovalId.String(), "alice"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected the requester to withdraw the request, got %d with body %s", rr.Code, rr.Body.String())
+	}
+	if rejected := clusterDao.approvals[approval.ApprovalId]; rejected.Status != store.ApprovalRejected || rejected.Result != nil {
+		t.Errorf("expected the request to be rejected without being executed, got %+v", rejected)
+	}
+}
+
+func TestService_ApproveExpiredRequest(t *testing.T) {
+	service, clusterDao := setupMocksForApprovals()
+	approval := store.NewApproval("testApp", store.ResizePartitionsOperation, store.ApprovalRequest{Method: "PUT"}, "alice", time.Now().Add(-2*time.Hour), time.Hour)
+	clusterDao.approvals[approval.ApprovalId] = approval
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.ApproveRequest).ServeHTTP(rr, decideRequest("approve", approval.ApprovalId.String(), "bob"))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected an expired request not to be approved, got %d with body %s", rr.Code, rr.Body.String())
+	}
+	if expired := clusterDao.approvals[approval.ApprovalId]; expired.Status != store.ApprovalExpired {
+		t.Errorf("expected the request to be expired, got %s", expired.Status)
+	}
+}
+
+func TestService_GetApproval(t *testing.T) {
+	service, _ := setupMocksForApprovals()
+	approval := holdResize(t, service, `{"partitions":4}`)
+
+	for _, test := range []struct {
+		approvalId string
+		status     int
+	}{
+		{approval.ApprovalId.String(), http.StatusOK},
+		{gocql.TimeUUID().String(), http.StatusNotFound},
+		{"invalid", http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/testApp/approvals/"+test.approvalId, nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": "testApp", "approvalId": test.approvalId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetApproval).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.approvalId, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_GetApprovals(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		status int
+	}{
+		{"testApp", http.StatusOK},
+		{"testGetApprovalsError", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/"+test.appId+"/approvals?status=pending", nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetApprovals).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.appId, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package service
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/diagnostics"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	overloadBacklog      = "backlog"
+	overloadQueryLatency = "query_latency"
+)
+
+// Load is the backlog of the callback workers and the recent Cassandra latency of the node,
+// compared against the backpressure thresholds
+type Load struct {
+	Backlog            int    `json:"backlog"`
+	QueryLatencyMillis int64  `json:"queryLatencyMillis"`
+	Overloaded         bool   `json:"overloaded"`
+	Reason             string `json:"reason,omitempty"`
+	RetryAfterSeconds  int    `json:"retryAfterSeconds,omitempty"`
+	cause              string
+}
+
+// load measures the load of the node and records it as gauges
+func (s *Service) load() Load {
+	latency := diagnostics.Default().QueryLatency()
+	load := Load{Backlog: store.CallbackBacklog(), QueryLatencyMillis: latency.Milliseconds()}
+	s.recordLoad(load.Backlog, latency)
+
+	config := s.Config.BackpressureConfig
+	if !config.Enabled {
+		return load
+	}
+
+	// ratio is how far the node is beyond the most exceeded threshold, overloaded from 1
+	ratio := 0.0
+	if config.MaxBacklog > 0 {
+		if r := float64(load.Backlog) / float64(config.MaxBacklog); r >= 1 && r > ratio {
+			ratio = r
+			load.cause = overloadBacklog
+			load.Reason = fmt.Sprintf("%d callbacks are waiting for a worker, the limit is %d", load.Backlog, config.MaxBacklog)
+		}
+	}
+	if config.MaxQueryLatencyMillis > 0 {
+		if r := float64(load.QueryLatencyMillis) / float64(config.MaxQueryLatencyMillis); r >= 1 && r > ratio {
+			ratio = r
+			load.cause = overloadQueryLatency
+			load.Reason = fmt.Sprintf("cassandra latency is %dms, the limit is %dms", load.QueryLatencyMillis, config.MaxQueryLatencyMillis)
+		}
+	}
+	if ratio == 0 {
+		return load
+	}
+
+	load.Overloaded = true
+	load.RetryAfterSeconds = retryAfter(config.RetryAfterSeconds, config.MaxRetryAfterSeconds, ratio)
+	return load
+}
+
+// retryAfter scales the Retry-After with the overload ratio, between 1 second and the max if one is set
+func retryAfter(base, max int, ratio float64) int {
+	seconds := int(math.Ceil(float64(base) * ratio))
+	if max > 0 && seconds > max {
+		seconds = max
+	}
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// shed rejects the request with 429 and a Retry-After when the node is overloaded, it returns whether the request was rejected
+func (s *Service) shed(w http.ResponseWriter, r *http.Request, name string) bool {
+	load := s.load()
+	if !load.Overloaded {
+		return false
+	}
+
+	if s.Monitor != nil {
+		s.Monitor.IncCounter(constants.ShedRequestCount, map[string]string{"request": name, "reason": load.cause}, 1)
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(load.RetryAfterSeconds))
+	er.Handle(w, r, er.NewError(er.TooManyRequests, fmt.Errorf("node is overloaded: %s", load.Reason)))
+	return true
+}
+
+func (s *Service) recordLoad(backlog int, latency time.Duration) {
+	if s.Monitor != nil {
+		s.Monitor.SetGauge(constants.CallbackBacklog, map[string]string{}, float64(backlog))
+		s.Monitor.SetGauge(constants.CassandraQueryLatency, map[string]string{}, latency.Seconds())
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+// fillBacklog pushes count schedules to the http task queue and returns a func taking them back
+func fillBacklog(t *testing.T, count int) func() {
+	queue := store.HttpTaskQueue
+	store.HttpTaskQueue = store.NewPriorityQueue()
+	for i := 0; i < count; i++ {
+		go store.HttpTaskQueue.Push(store.ScheduleWrapper{})
+	}
+	for deadline := time.Now().Add(time.Second); store.HttpTaskQueue.Len() < count; {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a backlog of %d, got %d", count, store.HttpTaskQueue.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	return func() {
+		for i := 0; i < count; i++ {
+			store.HttpTaskQueue.Pop()
+		}
+		store.HttpTaskQueue = queue
+	}
+}
+
+func newBackpressureService(config conf.BackpressureConfig) *Service {
+	return &Service{
+		Config:      &conf.Configuration{BackpressureConfig: config},
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+}
+
+func TestService_PostShedsWhenBacklogIsFull(t *testing.T) {
+	defer fillBacklog(t, 4)()
+
+	for _, test := range []struct {
+		config     conf.BackpressureConfig
+		status     int
+		retryAfter string
+	}{
+		{conf.BackpressureConfig{Enabled: false, MaxBacklog: 2, RetryAfterSeconds: 5}, http.StatusBadRequest, ""},
+		{conf.BackpressureConfig{Enabled: true, MaxBacklog: 5, RetryAfterSeconds: 5}, http.StatusBadRequest, ""},
+		{conf.BackpressureConfig{Enabled: true, MaxBacklog: 4, RetryAfterSeconds: 5}, http.StatusTooManyRequests, "5"},
+		{conf.BackpressureConfig{Enabled: true, MaxBacklog: 2, RetryAfterSeconds: 5}, http.StatusTooManyRequests, "10"},
+		{conf.BackpressureConfig{Enabled: true, MaxBacklog: 1, RetryAfterSeconds: 5, MaxRetryAfterSeconds: 8}, http.StatusTooManyRequests, "8"},
+	} {
+		service := newBackpressureService(test.config)
+
+		req, err := http.NewRequest("POST", "/goscheduler/schedules", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.Post)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.status {
+			t.Errorf("handler returned wrong status code for %+v: got %v want %v", test.config, status, test.status)
+		}
+		if retryAfter := rr.Header().Get("Retry-After"); retryAfter != test.retryAfter {
+			t.Errorf("expected Retry-After %q for %+v, got %q", test.retryAfter, test.config, retryAfter)
+		}
+	}
+}
+
+func TestService_Readiness(t *testing.T) {
+	defer fillBacklog(t, 3)()
+
+	for _, test := range []struct {
+		maxBacklog int
+		status     int
+	}{
+		{0, http.StatusOK},
+		{10, http.StatusOK},
+		{3, http.StatusServiceUnavailable},
+	} {
+		service := newBackpressureService(conf.BackpressureConfig{Enabled: true, MaxBacklog: test.maxBacklog, RetryAfterSeconds: 1})
+
+		req, err := http.NewRequest("GET", "/goscheduler/readiness", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.Readiness)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.status {
+			t.Errorf("handler returned wrong status code for max backlog %d: got %v want %v", test.maxBacklog, status, test.status)
+		}
+
+		var response ReadinessResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Data.Backlog != 3 || response.Data.Overloaded != (test.status != http.StatusOK) {
+			t.Errorf("unexpected load %+v for max backlog %d", response.Data, test.maxBacklog)
+		}
+	}
+}
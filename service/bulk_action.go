@@ -23,11 +23,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/dao"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 	"net/http"
 	"time"
@@ -47,7 +47,7 @@ func pushBulkActionQueries(app store.App, timeRange dao.Range, status store.Stat
 				ActionType:        actionType,
 			}
 			store.BulkActionQueue <- t
-			glog.Infof("Pushed %+v", t)
+			logger.Infof("Pushed %+v", t)
 		}
 	}
 	return nil
@@ -0,0 +1,135 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// BulkUpdateSchedules applies a JSON merge patch to every recurring schedule of an app matching a filter.
+// The schedules are updated in the background by a job, whose progress and per schedule results are returned by
+// the get job API.
+func (s *Service) BulkUpdateSchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.recordRequestAppStatus(constants.BulkUpdateSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	var input store.BulkUpdateRequest
+	if err = json.Unmarshal(b, &input); err != nil {
+		s.recordRequestAppStatus(constants.BulkUpdateSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		s.recordRequestAppStatus(constants.BulkUpdateSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	job, err := s.StartBulkUpdate(appId, input, b, logger.RequestID(r.Context()))
+	if err != nil {
+		s.recordRequestAppStatus(constants.BulkUpdateSchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	log.Infof("Started job %s updating %d schedules of app %s", job.JobId, job.Total, appId)
+	s.recordRequestAppStatus(constants.BulkUpdateSchedules, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: job.Total}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: JobData{Job: job}})
+}
+
+// StartBulkUpdate creates the job updating the recurring schedules of an app matching the filter of the request
+// and runs it in the background
+func (s *Service) StartBulkUpdate(appId string, input store.BulkUpdateRequest, request []byte, requestId string) (store.Job, error) {
+	app, err := s.getApp(appId)
+	if err != nil {
+		return store.Job{}, err
+	}
+
+	recurring, errs := s.ScheduleDao.GetCronSchedulesByApp(appId, input.Filter.Status)
+	if len(errs) != 0 {
+		return store.Job{}, er.NewError(er.DataFetchFailure, errors.New(strings.Join(errs, ",")))
+	}
+
+	var scheduleIds []string
+	for _, schedule := range recurring {
+		if schedule.AppId == appId && input.Filter.Matches(schedule) {
+			scheduleIds = append(scheduleIds, schedule.ScheduleId.String())
+		}
+	}
+
+	switch {
+	case len(scheduleIds) == 0:
+		return store.Job{}, er.NewError(er.DataNotFound, fmt.Errorf("no recurring schedule of app %s matches the filter", appId))
+	case len(scheduleIds) > store.MaxBulkUpdateSchedules:
+		return store.Job{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("%d schedules match the filter, a bulk update is limited to %d", len(scheduleIds), store.MaxBulkUpdateSchedules))
+	}
+
+	job := store.NewJob(store.BulkUpdateJob, appId, request, len(scheduleIds), time.Now())
+	if err = s.ScheduleDao.UpsertJob(job, s.Config.RetentionConfig.JobRetentionPeriod); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	go s.runJob(job, scheduleIds, s.bulkUpdateProcessor(app, input.Patch, requestId))
+	return job, nil
+}
+
+// bulkUpdateProcessor patches the schedules of a bulk update one by one.
+// A schedule deleted since the job started fails like any other invalid update.
+func (s *Service) bulkUpdateProcessor(app store.App, patch []byte, requestId string) jobProcessor {
+	return func(itemId string) store.JobItem {
+		item := store.JobItem{ItemId: itemId, Status: store.Success}
+
+		scheduleId, err := gocql.ParseUUID(itemId)
+		if err == nil {
+			var existing *store.Schedule
+			if existing, _, err = s.getUpdatableSchedule(scheduleId); err == nil {
+				_, err = s.updateSchedule(*existing, app, patch, s.patchSchedule, requestId)
+			}
+		}
+		if err != nil {
+			item.Status = store.Failure
+			item.Error = err.Error()
+		}
+		return item
+	}
+}
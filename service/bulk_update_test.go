@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockScheduleDaoForBulkUpdate lists the recurring schedules of testApp and records the jobs
+type MockScheduleDaoForBulkUpdate struct {
+	MockScheduleDaoForUpdate
+	schedules []store.Schedule
+	mu        sync.Mutex
+	jobs      []store.Job
+	items     []store.JobItem
+}
+
+func (m *MockScheduleDaoForBulkUpdate) GetCronSchedulesByApp(appId string, status store.Status) ([]store.Schedule, []string) {
+	return m.schedules, nil
+}
+
+func (m *MockScheduleDaoForBulkUpdate) UpsertJob(job store.Job, ttl int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+func (m *MockScheduleDaoForBulkUpdate) CreateJobItem(item store.JobItem, ttl int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = append(m.items, item)
+	return nil
+}
+
+func newBulkUpdateSchedule(id string, url string) store.Schedule {
+	scheduleId, _ := gocql.ParseUUID(id)
+	return store.Schedule{
+		ScheduleId:     scheduleId,
+		AppId:          "testApp",
+		CronExpression: "0 0 * * *",
+		Status:         store.Scheduled,
+		Callback:       &store.HttpCallback{Type: "http", Details: store.Details{Url: url, Method: "GET"}},
+	}
+}
+
+func TestService_BulkUpdateSchedules(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+	// schedules which are not found, so that the job started in the background leaves them alone
+	scheduleDao := &MockScheduleDaoForBulkUpdate{schedules: []store.Schedule{
+		newBulkUpdateSchedule("00000000-0000-0000-0000-000000000000", "http://example.com/v1"),
+		newBulkUpdateSchedule("00000000-0000-0000-0000-000000000000", "http://other.com/v1"),
+	}}
+	service.ScheduleDao = scheduleDao
+
+	tests := []struct {
+		name       string
+		appId      string
+		body       string
+		wantStatus int
+		wantTotal  int
+	}{
+		{"MalformedJSON", "testApp", `{bad json`, http.StatusBadRequest, 0},
+		{"EmptyPatch", "testApp", `{"patch":{}}`, http.StatusBadRequest, 0},
+		{"ImmutableField", "testApp", `{"patch":{"appId":"other"}}`, http.StatusBadRequest, 0},
+		{"InvalidStatus", "testApp", `{"filter":{"status":"DELETED"},"patch":{"payload":"{}"}}`, http.StatusBadRequest, 0},
+		{"AppNotRegistered", "nonExistentApp", `{"patch":{"payload":"{}"}}`, http.StatusBadRequest, 0},
+		{"NoMatch", "testApp", `{"filter":{"callbackUrl":"unknown.com"},"patch":{"payload":"{}"}}`, http.StatusNotFound, 0},
+		{"Filtered", "testApp", `{"filter":{"callbackUrl":"example.com"},"patch":{"payload":"{}"}}`, http.StatusOK, 1},
+		{"All", "testApp", `{"patch":{"payload":"{}"}}`, http.StatusOK, 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/goscheduler/apps/{appId}/bulk-update", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"appId": tc.appId})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.BulkUpdateSchedules).ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %v, want %v, body: %s", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response JobResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Data.Job.Total != tc.wantTotal || response.Data.Job.Status != store.JobRunning || response.Data.Job.Type != store.BulkUpdateJob {
+				t.Errorf("unexpected job %+v", response.Data.Job)
+			}
+		})
+	}
+}
+
+func TestService_RunBulkUpdate(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+	scheduleDao := &MockScheduleDaoForBulkUpdate{}
+	service.ScheduleDao = scheduleDao
+
+	scheduleIds := []string{"55555555-5555-5555-5555-555555555555", "88888888-8888-8888-8888-888888888888", "invalid-uuid"}
+	app := store.App{AppId: "testApp", Active: true, Partitions: 1}
+	job := store.NewJob(store.BulkUpdateJob, app.AppId, nil, len(scheduleIds), time.Now())
+
+	job = service.runJob(job, scheduleIds, service.bulkUpdateProcessor(app, []byte(`{"payload":"{\"bulk\":true}"}`), "request"))
+
+	if job.Status != store.JobCompleted || job.Succeeded != 1 || job.Failed != 2 {
+		t.Errorf("expected a completed job with two failed schedules, got %+v", job)
+	}
+	if lastUpdateRecurringScheduleInput.Payload != `{"bulk":true}` {
+		t.Errorf("expected the patch to be applied, got payload %q", lastUpdateRecurringScheduleInput.Payload)
+	}
+	if len(scheduleDao.items) != 3 || scheduleDao.items[0].Status != store.Success || scheduleDao.items[1].Status != store.Failure || scheduleDao.items[1].Error == "" || scheduleDao.items[2].Status != store.Failure {
+		t.Errorf("expected the result of every schedule to be recorded, got %+v", scheduleDao.items)
+	}
+	if last := scheduleDao.jobs[len(scheduleDao.jobs)-1]; last.Status != store.JobCompleted {
+		t.Errorf("expected the completed job to be saved, got %+v", last)
+	}
+}
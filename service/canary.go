@@ -0,0 +1,89 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// GetCallbackCanary returns the canary of the updated callback of a recurring schedule,
+// the results of the runs it fired and their comparison between the previous and the updated callback
+func (s *Service) GetCallbackCanary(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleId := vars["scheduleId"]
+
+	data, err := s.FetchCallbackCanary(scheduleId)
+	if err != nil {
+		s.recordRequestStatus(constants.GetCallbackCanary, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetCallbackCanary, data.Canary.AppId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(data.Results),
+	}
+	_ = json.NewEncoder(w).Encode(
+		GetCallbackCanaryResponse{
+			Status: status,
+			Data:   data,
+		})
+}
+
+func (s *Service) FetchCallbackCanary(uuid string) (GetCallbackCanaryData, error) {
+	scheduleId, err := gocql.ParseUUID(uuid)
+	if err != nil {
+		return GetCallbackCanaryData{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	canary, err := s.ScheduleDao.GetCallbackCanary(scheduleId)
+	switch {
+	case err == gocql.ErrNotFound:
+		return GetCallbackCanaryData{}, er.NewError(er.DataNotFound, fmt.Errorf("no callback canary found for schedule %s", uuid))
+	case err != nil:
+		return GetCallbackCanaryData{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	results, err := s.ScheduleDao.GetCanaryResults(scheduleId)
+	if err != nil {
+		return GetCallbackCanaryData{}, er.NewError(er.DataFetchFailure, err)
+	}
+	if results == nil {
+		results = []sch.CanaryResult{}
+	}
+
+	return GetCallbackCanaryData{
+		Canary:     canary,
+		Comparison: sch.CompareCanaryResults(results),
+		Results:    results,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/dao"
+)
+
+func TestService_GetCallbackCanary(t *testing.T) {
+	service := &Service{
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		uuid   string
+		Status int
+	}{
+		{
+			gocql.TimeUUID().String(),
+			http.StatusOK,
+		},
+		{
+			"00000000-0000-0000-0000-000000000000",
+			http.StatusInternalServerError,
+		},
+		{
+			"00000000-0000-0000-0000-000000000001",
+			http.StatusNotFound,
+		},
+		{
+			"00000000-0000-0000-0000-000000000002",
+			http.StatusInternalServerError,
+		},
+		{
+			"invalid-uuid",
+			http.StatusBadRequest,
+		},
+	} {
+		req, err := http.NewRequest("GET", "/goscheduler/schedules/{scheduleId}/canary", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req = mux.SetURLVars(req, map[string]string{
+			"scheduleId": test.uuid,
+		})
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.GetCallbackCanary)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.Status {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.uuid, status, test.Status)
+		}
+
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		var response GetCallbackCanaryResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Data.Comparison.Mismatches != 1 {
+			t.Errorf("expected the mirrored run to mismatch, got %+v", response.Data.Comparison)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+)
+
+// AdvanceClockRequest moves the virtual clock forward, either by a number of seconds or to a unix time
+type AdvanceClockRequest struct {
+	Seconds int64 `json:"seconds,omitempty"`
+	To      int64 `json:"to,omitempty"`
+}
+
+// GetClock returns the time of the clock the schedules are fired on by this node
+func (s *Service) GetClock(w http.ResponseWriter, r *http.Request) {
+	s.recordRequestStatus(constants.GetClock, constants.Success)
+	s.writeClock(w)
+}
+
+// AdvanceClock moves the virtual clock of this node forward, firing the schedules due on the way.
+// It is only available when the node fires the schedules on a virtual clock.
+func (s *Service) AdvanceClock(w http.ResponseWriter, r *http.Request) {
+	var request AdvanceClockRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.recordRequestStatus(constants.AdvanceClock, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	if err := s.advanceClock(request); err != nil {
+		s.recordRequestStatus(constants.AdvanceClock, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Advanced the virtual clock to %s", clock.Now().Format(time.RFC3339))
+	s.recordRequestStatus(constants.AdvanceClock, constants.Success)
+	s.writeClock(w)
+}
+
+func (s *Service) advanceClock(request AdvanceClockRequest) error {
+	virtual, ok := clock.Default().(*clock.Virtual)
+	if !ok {
+		return er.NewError(er.Conflict, errors.New("schedules are fired on the wall clock, which cannot be advanced"))
+	}
+
+	var err error
+	switch {
+	case request.Seconds > 0 && request.To == 0:
+		err = virtual.Advance(time.Duration(request.Seconds) * time.Second)
+	case request.To > 0 && request.Seconds == 0:
+		err = virtual.AdvanceTo(time.Unix(request.To, 0))
+	default:
+		return er.NewError(er.InvalidDataCode, errors.New("either a positive number of seconds or a unix time to advance to is expected"))
+	}
+	if err != nil {
+		return er.NewError(er.InvalidDataCode, err)
+	}
+	return nil
+}
+
+func (s *Service) writeClock(w http.ResponseWriter) {
+	_, virtual := clock.Default().(*clock.Virtual)
+	now := clock.Now()
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		ClockResponse{
+			Status: status,
+			Data: ClockData{
+				Now:     now.Unix(),
+				Time:    now.Format(time.RFC3339),
+				Virtual: virtual,
+			},
+		})
+}
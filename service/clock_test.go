@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestService_AdvanceClock(t *testing.T) {
+	defer clock.SetDefault(clock.Default())
+	service := &Service{Config: conf.NewConfig()}
+	start := time.Date(2024, time.February, 28, 23, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name    string
+		clock   clock.Clock
+		body    string
+		status  int
+		virtual bool
+		now     time.Time
+	}{
+		{"WallClock", clock.Real{}, `{"seconds": 60}`, http.StatusConflict, false, time.Time{}},
+		{"BySeconds", clock.NewVirtual(start), `{"seconds": 10800}`, http.StatusOK, true, start.Add(3 * time.Hour)},
+		{"ToTime", clock.NewVirtual(start), `{"to": 1709172000}`, http.StatusOK, true, time.Unix(1709172000, 0)},
+		{"Backwards", clock.NewVirtual(start), `{"to": 1609459200}`, http.StatusBadRequest, true, time.Time{}},
+		{"BothSecondsAndTime", clock.NewVirtual(start), `{"seconds": 60, "to": 1709172000}`, http.StatusBadRequest, true, time.Time{}},
+		{"InvalidBody", clock.NewVirtual(start), `{"seconds": "60"}`, http.StatusBadRequest, true, time.Time{}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			clock.SetDefault(test.clock)
+
+			req, err := http.NewRequest("POST", "/goscheduler/admin/clock/advance", bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.AdvanceClock).ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, test.status)
+			}
+			if test.status != http.StatusOK {
+				return
+			}
+
+			var response ClockResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if !response.Data.Virtual || response.Data.Now != test.now.Unix() || !clock.Now().Equal(test.now) {
+				t.Errorf("expected the virtual clock advanced to %v, got %+v", test.now, response.Data)
+			}
+		})
+	}
+}
+
+func TestService_GetClock(t *testing.T) {
+	service := &Service{Config: conf.NewConfig()}
+
+	req, err := http.NewRequest("GET", "/goscheduler/admin/clock", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GetClock).ServeHTTP(rr, req)
+
+	var response ClockResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if rr.Code != http.StatusOK || response.Data.Virtual || time.Since(time.Unix(response.Data.Now, 0)) > time.Minute {
+		t.Errorf("expected the wall clock, got %v with %+v", rr.Code, response.Data)
+	}
+}
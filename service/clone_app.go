@@ -0,0 +1,131 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// CloneApp registers a new app with the partitions, the configuration and the callback templates of an existing app,
+// e.g. to stamp out the staging equivalent of a production app
+func (s *Service) CloneApp(w http.ResponseWriter, r *http.Request) {
+	sourceId := mux.Vars(r)["appId"]
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.recordRequestAppStatus(constants.CloneApp, sourceId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	var input store.CloneAppRequest
+	if err = json.Unmarshal(b, &input); err != nil {
+		s.recordRequestAppStatus(constants.CloneApp, sourceId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	app, templates, err := s.CloneAppFrom(sourceId, input)
+	if err != nil {
+		s.recordRequestAppStatus(constants.CloneApp, sourceId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Cloned app %s to %s with %d callback templates", sourceId, app.AppId, len(templates))
+	s.recordRequestAppStatus(constants.CloneApp, sourceId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode201, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(CloneAppResponse{
+		Status: status,
+		Data: CloneAppData{
+			CreateAppData: CreateAppData{AppId: app.AppId, Partitions: app.Partitions, Active: app.Active, Configuration: app.Configuration},
+			ClonedFrom:    sourceId,
+			Templates:     templates,
+		},
+	})
+}
+
+// CloneAppFrom registers the app of the request with the configuration of the source app and copies the latest
+// version of every callback template of the source app to it. Returns the app and the names of the templates copied.
+func (s *Service) CloneAppFrom(sourceId string, input store.CloneAppRequest) (store.App, []string, error) {
+	if err := validateAppId(input.AppId); err != nil {
+		return store.App{}, nil, err
+	}
+	if input.AppId == sourceId {
+		return store.App{}, nil, er.NewError(er.InvalidDataCode, fmt.Errorf("app %s cannot be cloned to itself", sourceId))
+	}
+
+	source, err := s.getActiveOrInactiveApp(sourceId)
+	if err != nil {
+		return store.App{}, nil, err
+	}
+
+	switch existing, err := s.ClusterDao.GetApp(input.AppId); {
+	case err == gocql.ErrNotFound:
+	case err != nil:
+		return store.App{}, nil, er.NewError(er.DataFetchFailure, err)
+	case existing.AppId != "":
+		return store.App{}, nil, er.NewError(er.Conflict, fmt.Errorf("app %s is already registered", input.AppId))
+	}
+
+	templates, err := s.ClusterDao.GetCallbackTemplates(sourceId)
+	if err != nil {
+		return store.App{}, nil, er.NewError(er.DataFetchFailure, err)
+	}
+
+	partitions := input.Partitions
+	if partitions == 0 {
+		partitions = source.Partitions
+	}
+	app, err := s.RegisterApp(store.App{
+		AppId:         input.AppId,
+		Partitions:    partitions,
+		Active:        input.Active,
+		Configuration: source.Configuration,
+	})
+	if err != nil {
+		return store.App{}, nil, err
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		template.AppId = app.AppId
+		template.Version = 1
+		template.CreatedAt = time.Now()
+		if err = s.ClusterDao.CreateCallbackTemplate(template); err != nil {
+			return store.App{}, nil, er.NewError(er.DataPersistenceFailure, fmt.Errorf("app %s is registered but template %s could not be copied: %w", app.AppId, template.Name, err))
+		}
+		names = append(names, template.Name)
+	}
+
+	return app, names, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockClusterDaoForClone knows the apps of its map and records the apps and templates created
+type MockClusterDaoForClone struct {
+	dao.DummyClusterDaoImpl
+	apps      map[string]store.App
+	templates []store.CallbackTemplate
+	inserted  []store.App
+	created   []store.CallbackTemplate
+}
+
+func (m *MockClusterDaoForClone) GetApp(appName string) (store.App, error) {
+	if appName == "testGetAppError" {
+		return store.App{}, errors.New("error")
+	}
+	app, ok := m.apps[appName]
+	if !ok {
+		return store.App{}, gocql.ErrNotFound
+	}
+	return app, nil
+}
+
+func (m *MockClusterDaoForClone) InsertApp(app store.App) error {
+	m.inserted = append(m.inserted, app)
+	return nil
+}
+
+func (m *MockClusterDaoForClone) GetCallbackTemplates(appId string) ([]store.CallbackTemplate, error) {
+	return m.templates, nil
+}
+
+func (m *MockClusterDaoForClone) CreateCallbackTemplate(template store.CallbackTemplate) error {
+	m.created = append(m.created, template)
+	return nil
+}
+
+func TestService_CloneApp(t *testing.T) {
+	production := store.App{
+		AppId:         "production",
+		Partitions:    4,
+		Active:        true,
+		Configuration: store.Configuration{PayloadSize: 4096, HttpRetries: 5, Blackout: &store.Blackout{}},
+	}
+	templates := []store.CallbackTemplate{
+		{AppId: "production", Name: "notify", Version: 3, Callback: json.RawMessage(`{"type":"http"}`)},
+		{AppId: "production", Name: "refund", Version: 1, Callback: json.RawMessage(`{"type":"http"}`)},
+	}
+
+	tests := []struct {
+		name           string
+		source         string
+		body           string
+		wantStatus     int
+		wantPartitions uint32
+	}{
+		{"MalformedJSON", "production", `{bad json`, http.StatusBadRequest, 0},
+		{"NoAppId", "production", `{}`, http.StatusBadRequest, 0},
+		{"Itself", "production", `{"appId":"production"}`, http.StatusBadRequest, 0},
+		{"SourceNotRegistered", "unknown", `{"appId":"staging"}`, http.StatusBadRequest, 0},
+		{"AlreadyRegistered", "production", `{"appId":"existing"}`, http.StatusConflict, 0},
+		{"Clone", "production", `{"appId":"staging"}`, http.StatusOK, 4},
+		{"CloneWithPartitions", "production", `{"appId":"staging","partitions":1,"active":true}`, http.StatusOK, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := setupMocks()
+			clusterDao := &MockClusterDaoForClone{
+				apps:      map[string]store.App{"production": production, "existing": {AppId: "existing"}},
+				templates: templates,
+			}
+			service.ClusterDao = clusterDao
+
+			req, err := http.NewRequest("POST", "/goscheduler/apps/{appId}/clone", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"appId": tc.source})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.CloneApp).ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %v, want %v, body: %s", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+			if tc.wantStatus != http.StatusOK {
+				if len(clusterDao.inserted) != 0 {
+					t.Errorf("expected no app to be registered, got %+v", clusterDao.inserted)
+				}
+				return
+			}
+
+			var response CloneAppResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			data := response.Data
+			if data.AppId != "staging" || data.ClonedFrom != "production" || data.Partitions != tc.wantPartitions || len(data.Templates) != 2 {
+				t.Errorf("unexpected clone %+v", data)
+			}
+			if len(clusterDao.inserted) != 1 || clusterDao.inserted[0].Configuration.PayloadSize != 4096 || clusterDao.inserted[0].Configuration.Blackout == nil {
+				t.Errorf("expected the configuration to be copied, got %+v", clusterDao.inserted)
+			}
+			for _, template := range clusterDao.created {
+				if template.AppId != "staging" || template.Version != 1 {
+					t.Errorf("expected the templates to be copied as the first version of the clone, got %+v", template)
+				}
+			}
+		})
+	}
+}
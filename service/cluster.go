@@ -0,0 +1,168 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+)
+
+// GetClusterNodes returns the status of every node of the cluster: whether it answered, the partitions it polls,
+// their largest poll lag, the callbacks waiting for a worker and the version it runs
+func (s *Service) GetClusterNodes(w http.ResponseWriter, r *http.Request) {
+	s.recordRequestStatus(constants.GetClusterNodes, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+
+	_ = json.NewEncoder(w).Encode(
+		ClusterNodesResponse{
+			Status: status,
+			Data:   s.Supervisor.Nodes(),
+		})
+}
+
+// GetClusterPartitions returns the node running every partition and the node owning it, optionally for an app or
+// a node only
+func (s *Service) GetClusterPartitions(w http.ResponseWriter, r *http.Request) {
+	appId := r.URL.Query().Get("app_id")
+	node := r.URL.Query().Get("node")
+
+	partitions := make([]cluster.PartitionAssignment, 0)
+	for _, partition := range s.Supervisor.Partitions() {
+		if (appId == "" || partition.AppId == appId) && (node == "" || partition.Node == node) {
+			partitions = append(partitions, partition)
+		}
+	}
+
+	s.recordRequestStatus(constants.GetClusterPartitions, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+
+	_ = json.NewEncoder(w).Encode(
+		ClusterPartitionsResponse{
+			Status: status,
+			Data:   partitions,
+		})
+}
+
+// CordonNode stops assigning partitions to a node, for maintenance. The partitions it runs stay on it.
+func (s *Service) CordonNode(w http.ResponseWriter, r *http.Request) {
+	node := mux.Vars(r)["node"]
+	if err := s.maintainNode(s.Supervisor.Cordon, node); err != nil {
+		s.recordRequestStatus(constants.CordonNode, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Cordoned node %s", node)
+	s.recordRequestStatus(constants.CordonNode, constants.Success)
+	s.writeNodeMaintenance(w, node, true)
+}
+
+// UncordonNode lets partitions be assigned to a node again
+func (s *Service) UncordonNode(w http.ResponseWriter, r *http.Request) {
+	node := mux.Vars(r)["node"]
+	if err := s.maintainNode(s.Supervisor.Uncordon, node); err != nil {
+		s.recordRequestStatus(constants.UncordonNode, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Uncordoned node %s", node)
+	s.recordRequestStatus(constants.UncordonNode, constants.Success)
+	s.writeNodeMaintenance(w, node, false)
+}
+
+// DrainNode cordons a node and hands the partitions it runs over to the other nodes, so that the node can be
+// stopped without the partitions waiting for the cluster to notice it left
+func (s *Service) DrainNode(w http.ResponseWriter, r *http.Request) {
+	node := mux.Vars(r)["node"]
+	var partitions []cluster.DrainedPartition
+	err := s.maintainNode(func(node string) (err error) {
+		partitions, err = s.Supervisor.Drain(node)
+		return err
+	}, node)
+	if err != nil {
+		s.recordRequestStatus(constants.DrainNode, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	drained := true
+	for _, partition := range partitions {
+		drained = drained && partition.Error == ""
+	}
+	logger.FromContext(r.Context()).Infof("Drained node %s, %d partitions handed over, drained: %t", node, len(partitions), drained)
+	s.recordRequestStatus(constants.DrainNode, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		DrainNodeResponse{
+			Status: status,
+			Data:   DrainNodeData{Node: node, Drained: drained, Partitions: partitions},
+		})
+}
+
+// maintainNode applies a maintenance action to a node, telling a failure to persist it apart from a failure to
+// propagate it to the other nodes
+func (s *Service) maintainNode(action func(node string) error, node string) error {
+	if node == "" {
+		return er.NewError(er.InvalidDataCode, errors.New("node is required"))
+	}
+	if err := action(node); err != nil {
+		if errors.Is(err, cluster.ErrCordonNotPropagated) {
+			return er.NewError(er.EntityBootFailed, err)
+		}
+		return er.NewError(er.DataPersistenceFailure, err)
+	}
+	return nil
+}
+
+func (s *Service) writeNodeMaintenance(w http.ResponseWriter, node string, cordoned bool) {
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		NodeMaintenanceResponse{
+			Status: status,
+			Data:   NodeMaintenanceData{Node: node, Cordoned: cordoned},
+		})
+}
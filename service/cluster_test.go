@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+)
+
+func newClusterService() *Service {
+	return &Service{
+		Config:      conf.NewConfig(),
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+}
+
+func TestService_GetClusterNodes(t *testing.T) {
+	service := newClusterService()
+
+	req, err := http.NewRequest("GET", "/goscheduler/cluster/nodes", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GetClusterNodes).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	var response ClusterNodesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 1 || !response.Data[0].Healthy || len(response.Data[0].Partitions) != 1 {
+		t.Errorf("unexpected nodes %+v", response.Data)
+	}
+}
+
+func TestService_GetClusterPartitions(t *testing.T) {
+	service := newClusterService()
+
+	for _, test := range []struct {
+		query      string
+		partitions int
+	}{
+		{"", 2},
+		{"?app_id=test", 1},
+		{"?node=127.0.0.1:9091", 2},
+		{"?node=127.0.0.1:9092", 0},
+	} {
+		req, err := http.NewRequest("GET", "/goscheduler/cluster/partitions"+test.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetClusterPartitions).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.query, rr.Code, http.StatusOK)
+			continue
+		}
+
+		var response ClusterPartitionsResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Data) != test.partitions {
+			t.Errorf("expected %d partitions for %s, got %d", test.partitions, test.query, len(response.Data))
+		}
+	}
+}
+
+func TestService_CordonNode(t *testing.T) {
+	service := newClusterService()
+
+	for _, test := range []struct {
+		node   string
+		status int
+	}{
+		{"127.0.0.1:9092", http.StatusOK},
+		{"", http.StatusBadRequest},
+		{"testCordonError", http.StatusInternalServerError},
+	} {
+		req, err := http.NewRequest("PUT", "/goscheduler/cluster/nodes/"+test.node+"/cordon", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"node": test.node})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.CordonNode).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.node, rr.Code, test.status)
+			continue
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response NodeMaintenanceResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if !response.Data.Cordoned || response.Data.Node != test.node {
+			t.Errorf("unexpected maintenance %+v", response.Data)
+		}
+	}
+}
+
+func TestService_DrainNode(t *testing.T) {
+	service := newClusterService()
+
+	for _, test := range []struct {
+		node       string
+		status     int
+		partitions int
+	}{
+		{"127.0.0.1:9091", http.StatusOK, 1},
+		{"testDrainPropagationError", http.StatusInternalServerError, 0},
+	} {
+		req, err := http.NewRequest("POST", "/goscheduler/cluster/nodes/"+test.node+"/drain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"node": test.node})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.DrainNode).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.node, rr.Code, test.status)
+			continue
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response DrainNodeResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if !response.Data.Drained || len(response.Data.Partitions) != test.partitions {
+			t.Errorf("unexpected drain %+v", response.Data)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	sch "github.com/myntra/goscheduler/store"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProtobufContentType sends or receives the messages of docs/goscheduler.proto
+	ProtobufContentType = "application/x-protobuf"
+	// MsgpackContentType sends or receives the msgpack counterpart of the JSON documents
+	MsgpackContentType = "application/msgpack"
+)
+
+// wireFormats maps the media types accepted for the binary formats to their canonical content type
+var wireFormats = map[string]string{
+	ProtobufContentType:     ProtobufContentType,
+	"application/protobuf":  ProtobufContentType,
+	MsgpackContentType:      MsgpackContentType,
+	"application/x-msgpack": MsgpackContentType,
+}
+
+// protoResponse is a response which can be written as its message of docs/goscheduler.proto
+type protoResponse interface {
+	toProto() proto.Message
+}
+
+// wireFormat returns the binary format of a Content-Type or Accept header, empty for JSON.
+// Any other media type is read as JSON, as the APIs did before the binary formats were supported.
+func wireFormat(header string) string {
+	for _, mediaType := range strings.Split(header, ",") {
+		if format, ok := wireFormats[strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))]; ok {
+			return format
+		}
+	}
+	return ""
+}
+
+// readRequest reads the body of a request along with its binary format, empty for JSON
+func readRequest(r *http.Request) ([]byte, string, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	return body, wireFormat(r.Header.Get(constants.ContentType)), err
+}
+
+// invalidBody returns the error of a binary body which could not be decoded
+func invalidBody(r *http.Request, err error) error {
+	return fmt.Errorf("invalid %s body: %s", r.Header.Get(constants.ContentType), err.Error())
+}
+
+// readSchedule reads the schedule in the body of a request, in the format of its Content-Type
+func readSchedule(r *http.Request) (sch.Schedule, error) {
+	var schedule sch.Schedule
+	body, format, err := readRequest(r)
+	if err != nil {
+		return schedule, err
+	}
+
+	switch format {
+	case ProtobufContentType:
+		schedule, err = unmarshalProtoSchedule(body)
+	case MsgpackContentType:
+		err = unmarshalMsgpack(body, &schedule)
+	default:
+		return schedule, json.Unmarshal(body, &schedule)
+	}
+	if err != nil {
+		return schedule, invalidBody(r, err)
+	}
+	return schedule, nil
+}
+
+// writeResponse writes a successful response in the format of the Accept header of the request, JSON by default.
+// Errors are always returned as JSON, as is a response failing to be encoded.
+func writeResponse(w http.ResponseWriter, r *http.Request, response protoResponse) error {
+	var body []byte
+	var err error
+
+	format := wireFormat(r.Header.Get("Accept"))
+	switch format {
+	case ProtobufContentType:
+		body, err = proto.Marshal(response.toProto())
+	case MsgpackContentType:
+		body, err = marshalMsgpack(response)
+	default:
+		return json.NewEncoder(w).Encode(response)
+	}
+	if err != nil {
+		logger.FromContext(r.Context()).Errorf("Could not encode the response as %s: %s", format, err.Error())
+		return json.NewEncoder(w).Encode(response)
+	}
+
+	w.Header().Set(constants.ContentType, format)
+	_, err = w.Write(body)
+	return err
+}
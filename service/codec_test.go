@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/schedulerpb"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWireFormat(t *testing.T) {
+	for header, expected := range map[string]string{
+		"":                                       "",
+		"application/json":                       "",
+		"application/x-protobuf":                 ProtobufContentType,
+		"Application/Protobuf; charset=binary":   ProtobufContentType,
+		"application/x-msgpack":                  MsgpackContentType,
+		"text/html, application/msgpack;q=0.9":   MsgpackContentType,
+		"application/x-ndjson, application/json": "",
+	} {
+		if actual := wireFormat(header); actual != expected {
+			t.Errorf("%q: expected %q, got %q", header, expected, actual)
+		}
+	}
+}
+
+func TestService_PostBinary(t *testing.T) {
+	callback := `{"type": "http", "details": {"url": "https://dummy.url", "method": "POST"}}`
+	scheduleTime := time.Now().Add(time.Hour).Unix()
+	protobuf, err := proto.Marshal(&schedulerpb.Schedule{AppId: "test", Callback: []byte(callback), ScheduleTime: scheduleTime, Payload: "{}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgpackBody, err := msgpack.Marshal(map[string]interface{}{
+		"appId":        "test",
+		"callback":     map[string]interface{}{"type": "http", "details": map[string]interface{}{"url": "https://dummy.url", "method": "POST"}},
+		"scheduleTime": scheduleTime,
+		"payload":      "{}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		contentType string
+		body        []byte
+		Status      int
+	}{
+		{ProtobufContentType, protobuf, http.StatusOK},
+		{MsgpackContentType, msgpackBody, http.StatusOK},
+		{ProtobufContentType, []byte{0x0a, 0x05}, http.StatusBadRequest},
+		{MsgpackContentType, []byte{0xc1}, http.StatusBadRequest},
+		{MsgpackContentType, append(append([]byte{}, msgpackBody...), 0xc0), http.StatusBadRequest},
+	} {
+		service := setupMocks()
+		req := httptest.NewRequest(http.MethodPost, "/goscheduler/schedules", bytes.NewReader(test.body))
+		req.Header.Set(constants.ContentType, test.contentType)
+		req.Header.Set("Accept", test.contentType)
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.Post).ServeHTTP(rr, req)
+
+		if rr.Code != test.Status {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", test.contentType, rr.Code, test.Status)
+			continue
+		}
+		if test.Status != http.StatusOK {
+			if contentType := rr.Header().Get(constants.ContentType); contentType != constants.ApplicationJson {
+				t.Errorf("%s: expected the error as JSON, got %s", test.contentType, contentType)
+			}
+			continue
+		}
+		if contentType := rr.Header().Get(constants.ContentType); contentType != test.contentType {
+			t.Errorf("%s: expected the response as %s, got %s", test.contentType, test.contentType, contentType)
+		}
+
+		var resp CreateScheduleResponse
+		if test.contentType == ProtobufContentType {
+			var message schedulerpb.CreateScheduleResponse
+			if err = proto.Unmarshal(rr.Body.Bytes(), &message); err != nil {
+				t.Fatal(err)
+			}
+			resp.Status = Status{StatusCode: int(message.Status.StatusCode)}
+			if resp.Data.Schedule, err = scheduleFromProto(message.Data.Schedule); err != nil {
+				t.Fatal(err)
+			}
+		} else if err = unmarshalMsgpack(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Status.StatusCode != constants.SuccessCode201 || resp.Data.Schedule.AppId != "test" ||
+			resp.Data.Schedule.Callback == nil || resp.Data.Schedule.Payload != "{}" {
+			t.Errorf("%s: unexpected response %+v", test.contentType, resp)
+		}
+	}
+}
+
+func TestService_ImportSchedulesBinary(t *testing.T) {
+	callback := `{"type": "http", "details": {"url": "http://localhost:8080/callback", "method": "POST"}}`
+	scheduleId, scheduleTime := gocql.TimeUUID(), time.Now().Add(time.Hour).Unix()
+	protobuf, err := proto.Marshal(&schedulerpb.ScheduleSnapshot{
+		AppId: "source",
+		Schedules: []*schedulerpb.Schedule{
+			{ScheduleId: scheduleId.String(), AppId: "source", Payload: "{}", ScheduleTime: scheduleTime, Callback: []byte(callback)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgpackBody, err := msgpack.Marshal(map[string]interface{}{
+		"appId": "source",
+		"schedules": []interface{}{map[string]interface{}{
+			"scheduleId":   scheduleId.String(),
+			"appId":        "source",
+			"payload":      "{}",
+			"scheduleTime": scheduleTime,
+			"callback":     map[string]interface{}{"type": "http", "details": map[string]interface{}{"url": "http://localhost:8080/callback", "method": "POST"}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for contentType, body := range map[string][]byte{ProtobufContentType: protobuf, MsgpackContentType: msgpackBody} {
+		service := setupMocks()
+		service.ScheduleDao = &MockScheduleDaoForSnapshot{}
+
+		// the format query param is ignored by a binary snapshot
+		req := httptest.NewRequest(http.MethodPost, "/goscheduler/apps/test/import?format=ndjson", bytes.NewReader(body))
+		req.Header.Set(constants.ContentType, contentType)
+		req.Header.Set("Accept", contentType)
+		req = mux.SetURLVars(req, map[string]string{"appId": "test"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.ImportSchedules).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", contentType, rr.Code, http.StatusOK)
+			continue
+		}
+
+		var imported int
+		if contentType == ProtobufContentType {
+			var message schedulerpb.ImportSchedulesResponse
+			if err = proto.Unmarshal(rr.Body.Bytes(), &message); err != nil {
+				t.Fatal(err)
+			}
+			imported = int(message.Data.Imported)
+		} else {
+			var resp ImportSchedulesResponse
+			if err = unmarshalMsgpack(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			imported = resp.Data.Imported
+		}
+		if imported != 1 {
+			t.Errorf("%s: expected 1 schedule imported, got %d", contentType, imported)
+		}
+	}
+}
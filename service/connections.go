@@ -0,0 +1,127 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/diagnostics"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+// GetAppConnections returns the transport of the http callbacks of an app and how well their connections are reused
+// by host on this node: the share of the requests sent on a pooled connection, the dials and the TLS handshakes.
+func (s *Service) GetAppConnections(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	app, err := s.fetchConnectionsApp(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetAppConnections, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetAppConnections, appId, constants.Success)
+	s.writeAppConnections(w, app)
+}
+
+// TuneAppConnections resizes the connection pool of the client of the http callbacks of an app.
+// The client is rebuilt with the new sizing on its next callback once the app is read again by the nodes,
+// and the statistics of its connections start over then.
+func (s *Service) TuneAppConnections(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	app, err := s.tuneAppConnections(appId, r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.TuneAppConnections, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.TuneAppConnections, appId, constants.Success)
+	s.writeAppConnections(w, app)
+}
+
+func (s *Service) tuneAppConnections(appId string, r *http.Request) (store.App, error) {
+	var tuning store.ConnectionTuning
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return store.App{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+	if err = json.Unmarshal(body, &tuning); err != nil {
+		return store.App{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	app, err := s.fetchConnectionsApp(appId)
+	if err != nil {
+		return store.App{}, err
+	}
+
+	var transport store.HttpTransport
+	if app.Configuration.HttpTransport != nil {
+		transport = *app.Configuration.HttpTransport
+	}
+	if transport, err = tuning.Apply(transport); err != nil {
+		return store.App{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	// the configuration is written whole as an update keeps the existing value of the limits set back to zero
+	configuration := app.Configuration
+	configuration.HttpTransport = &transport
+	if _, err = s.ClusterDao.CreateConfigurations(appId, configuration); err != nil {
+		return store.App{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	s.ClusterDao.InvalidateSingleAppCache(appId)
+
+	app.Configuration = configuration
+	return app, nil
+}
+
+func (s *Service) fetchConnectionsApp(appId string) (store.App, error) {
+	switch app, err := s.ClusterDao.GetApp(appId); {
+	case err == gocql.ErrNotFound || (err == nil && len(app.AppId) == 0):
+		return store.App{}, er.NewError(er.InvalidAppId, errors.New(fmt.Sprintf("app Id %s is not registered", appId)))
+	case err != nil:
+		return store.App{}, er.NewError(er.DataFetchFailure, err)
+	default:
+		return app, nil
+	}
+}
+
+func (s *Service) writeAppConnections(w http.ResponseWriter, app store.App) {
+	hosts := diagnostics.Connections().Stats(app.AppId)
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(hosts)}
+	_ = json.NewEncoder(w).Encode(AppConnectionsResponse{
+		Status: status,
+		Data: AppConnectionsData{
+			AppId:     app.AppId,
+			Transport: app.Configuration.HttpTransport,
+			Hosts:     hosts,
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+type MockClusterDaoForConnections struct {
+	dao.DummyClusterDaoImpl
+	transport   *store.HttpTransport
+	saved       *store.Configuration
+	invalidated string
+}
+
+func (m *MockClusterDaoForConnections) GetApp(appName string) (store.App, error) {
+	app, err := m.DummyClusterDaoImpl.GetApp(appName)
+	app.Configuration.HttpTransport = m.transport
+	return app, err
+}
+
+func (m *MockClusterDaoForConnections) CreateConfigurations(appId string, configuration store.Configuration) (store.Configuration, error) {
+	m.saved = &configuration
+	return configuration, nil
+}
+
+func (m *MockClusterDaoForConnections) InvalidateSingleAppCache(appName string) {
+	m.invalidated = appName
+}
+
+func TestService_TuneAppConnections(t *testing.T) {
+	for _, test := range []struct {
+		appId     string
+		body      string
+		transport *store.HttpTransport
+		Status    int
+		expected  *store.HttpTransport
+	}{
+		{"test", `{"maxIdleConnsPerHost": 200, "maxConnsPerHost": 0}`, &store.HttpTransport{ProxyUrl: "http://proxy.internal:3128", MaxConnsPerHost: 100},
+			http.StatusOK, &store.HttpTransport{ProxyUrl: "http://proxy.internal:3128", MaxIdleConnsPerHost: 200}},
+		{"test", `{"idleConnTimeoutMillis": 120000}`, nil, http.StatusOK, &store.HttpTransport{IdleConnTimeoutMillis: 120000}},
+		{"test", `{}`, nil, http.StatusBadRequest, nil},
+		{"test", `{"maxConnsPerHost": -1}`, nil, http.StatusBadRequest, nil},
+		{"test", `{"maxConnsPerHost": "ten"}`, nil, http.StatusBadRequest, nil},
+		{"testGetAppErrorNotFound", `{"maxConnsPerHost": 10}`, nil, http.StatusBadRequest, nil},
+	} {
+		clusterDao := &MockClusterDaoForConnections{transport: test.transport}
+		service := setupMocks()
+		service.ClusterDao = clusterDao
+
+		req := httptest.NewRequest(http.MethodPut, "/goscheduler/apps/"+test.appId+"/connections", bytes.NewBufferString(test.body))
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.TuneAppConnections).ServeHTTP(rr, req)
+
+		if rr.Code != test.Status {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", test.body, rr.Code, test.Status)
+			continue
+		}
+		if test.expected == nil {
+			if clusterDao.saved != nil {
+				t.Errorf("%s: expected the configuration not to be saved", test.body)
+			}
+			continue
+		}
+
+		if clusterDao.saved == nil || *clusterDao.saved.HttpTransport != *test.expected || clusterDao.invalidated != test.appId {
+			t.Errorf("%s: expected the transport %+v to be saved, got %+v", test.body, test.expected, clusterDao.saved)
+		}
+		if clusterDao.saved != nil && clusterDao.saved.FutureScheduleCreationPeriod != 1000 {
+			t.Errorf("%s: expected the rest of the configuration to be kept, got %+v", test.body, clusterDao.saved)
+		}
+
+		var resp AppConnectionsResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Data.AppId != test.appId || resp.Data.Transport == nil || *resp.Data.Transport != *test.expected {
+			t.Errorf("%s: unexpected response %+v", test.body, resp.Data)
+		}
+	}
+}
+
+func TestService_GetAppConnections(t *testing.T) {
+	service := setupMocks()
+	service.ClusterDao = &MockClusterDaoForConnections{transport: &store.HttpTransport{MaxIdleConnsPerHost: 20}}
+
+	req := httptest.NewRequest(http.MethodGet, "/goscheduler/apps/test/connections", nil)
+	req = mux.SetURLVars(req, map[string]string{"appId": "test"})
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GetAppConnections).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var resp AppConnectionsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.Transport == nil || resp.Data.Transport.MaxIdleConnsPerHost != 20 || resp.Data.Hosts == nil {
+		t.Errorf("unexpected response %+v", resp.Data)
+	}
+}
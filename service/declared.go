@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// ApplySchedulesRequest is the desired set of the declared recurring schedules of an app
+type ApplySchedulesRequest struct {
+	Schedules []store.DeclaredSchedule `json:"schedules"`
+}
+
+// ApplySchedules reconciles the declared recurring schedules of an app with the desired set in the request. The
+// schedules declared for the first time are created, the ones whose definition changed are updated and the declared
+// schedules left out of the set are deleted. The schedules created through the other endpoints are left alone. With
+// the dry_run query param the changes are returned without being applied.
+func (s *Service) ApplySchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	dryRun, err := parseDryRun(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ApplySchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	var input ApplySchedulesRequest
+	b, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(b, &input)
+	}
+	if err != nil {
+		s.recordRequestAppStatus(constants.ApplySchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	data, err := s.applySchedules(r.Context(), appId, input.Schedules, dryRun)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ApplySchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.ApplySchedules, appId, constants.Success)
+	log.Infof("Applied the declared schedules of app %s: %d created, %d updated, %d deleted, dry run %t", appId, len(data.Created), len(data.Updated), len(data.Deleted), dryRun)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(data.Created) + len(data.Updated) + len(data.Deleted),
+	}
+	_ = json.NewEncoder(w).Encode(ApplySchedulesResponse{Status: status, Data: data})
+}
+
+// parseDryRun reads the dry_run query param
+func parseDryRun(r *http.Request) (bool, error) {
+	param := r.URL.Query().Get("dry_run")
+	if param == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, fmt.Errorf("invalid dry_run: %s, must be true or false", param)
+	}
+	return dryRun, nil
+}
+
+// applySchedules plans the changes bringing the declared schedules of an app to the desired set, and applies them
+// unless it is a dry run. The changes are applied in order, a failure stops them and applying the set again resumes.
+func (s *Service) applySchedules(ctx context.Context, appId string, declared []store.DeclaredSchedule, dryRun bool) (ApplySchedulesData, error) {
+	app, err := s.getApp(appId)
+	if err != nil {
+		return ApplySchedulesData{}, err
+	}
+
+	if err = store.ValidateDeclared(appId, declared); err != nil {
+		return ApplySchedulesData{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	var details []er.FieldError
+	for i := range declared {
+		declared[i].AppId = appId
+		for _, detail := range declared[i].ValidateFields(app, s.Config.AppLevelConfiguration) {
+			details = append(details, er.FieldError{Field: declared[i].Name + "." + detail.Field, Message: detail.Message})
+		}
+	}
+	if len(details) > 0 {
+		return ApplySchedulesData{}, er.NewValidationError(er.InvalidDataCode, details)
+	}
+
+	existing, errs := s.ScheduleDao.GetCronSchedulesByApp(appId, "")
+	if len(errs) > 0 {
+		return ApplySchedulesData{}, er.NewError(er.DataFetchFailure, fmt.Errorf("error fetching the recurring schedules of app %s: %v", appId, errs))
+	}
+
+	current := make(map[gocql.UUID]store.Schedule)
+	for _, schedule := range existing {
+		if schedule.IsDeclared() && schedule.Status != store.Deleted {
+			current[schedule.ScheduleId] = schedule
+		}
+	}
+
+	data := ApplySchedulesData{DryRun: dryRun, Created: []DeclaredChange{}, Updated: []DeclaredChange{}, Deleted: []DeclaredChange{}, Unchanged: []DeclaredChange{}}
+	for _, d := range declared {
+		change := DeclaredChange{Name: d.Name, ScheduleId: store.DeclaredScheduleId(appId, d.Name)}
+		schedule, found := current[change.ScheduleId]
+		delete(current, change.ScheduleId)
+
+		if !found {
+			data.Created = append(data.Created, change)
+			if !dryRun {
+				if _, err = s.createSchedule(ctx, d.Schedule, change.ScheduleId); err != nil {
+					return ApplySchedulesData{}, err
+				}
+			}
+			continue
+		}
+
+		same, err := d.SameDefinition(schedule)
+		if err != nil {
+			return ApplySchedulesData{}, er.NewError(er.DataFetchFailure, err)
+		}
+		if same {
+			data.Unchanged = append(data.Unchanged, change)
+			continue
+		}
+
+		data.Updated = append(data.Updated, change)
+		if !dryRun {
+			if _, err = s.updateSchedule(schedule, app, nil, declaredUpdate(d), logger.RequestID(ctx)); err != nil {
+				return ApplySchedulesData{}, err
+			}
+		}
+	}
+
+	for scheduleId := range current {
+		data.Deleted = append(data.Deleted, DeclaredChange{ScheduleId: scheduleId})
+	}
+	sort.Slice(data.Deleted, func(i, j int) bool {
+		return data.Deleted[i].ScheduleId.String() < data.Deleted[j].ScheduleId.String()
+	})
+	if !dryRun {
+		for _, change := range data.Deleted {
+			if _, err = s.DeleteSchedule(change.ScheduleId.String()); err != nil {
+				return ApplySchedulesData{}, err
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// declaredUpdate replaces an existing declared schedule with its declaration, keeping its anchor when left out
+func declaredUpdate(d store.DeclaredSchedule) scheduleUpdate {
+	return func(existing store.Schedule, _ []byte) (store.Schedule, error) {
+		schedule := d.Schedule
+		if schedule.Anchor == 0 {
+			schedule.Anchor = existing.Anchor
+		}
+		return schedule, nil
+	}
+}
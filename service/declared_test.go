@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/dao"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockScheduleDaoForDeclared holds the recurring schedules of an app and records the changes made to them
+type MockScheduleDaoForDeclared struct {
+	dao.DummyScheduleDaoImpl
+	existing []store.Schedule
+	created  []gocql.UUID
+	updated  []gocql.UUID
+	deleted  []gocql.UUID
+}
+
+func (m *MockScheduleDaoForDeclared) GetCronSchedulesByApp(string, store.Status) ([]store.Schedule, []string) {
+	return m.existing, nil
+}
+
+func (m *MockScheduleDaoForDeclared) CreateSchedule(_ context.Context, schedule store.Schedule, _ store.App) (store.Schedule, error) {
+	m.created = append(m.created, schedule.ScheduleId)
+	return schedule, nil
+}
+
+func (m *MockScheduleDaoForDeclared) UpdateVersionedRecurringSchedule(schedule store.Schedule, _ store.ScheduleVersion) (store.Schedule, error) {
+	m.updated = append(m.updated, schedule.ScheduleId)
+	return schedule, nil
+}
+
+func (m *MockScheduleDaoForDeclared) DeleteSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	m.deleted = append(m.deleted, uuid)
+	return store.Schedule{ScheduleId: uuid}, nil
+}
+
+func declaredSchedule(t *testing.T, appId, name, cronExpression string) store.Schedule {
+	var d store.DeclaredSchedule
+	body := `{"name": "` + name + `", "payload": "{}", "cronExpression": "` + cronExpression + `", "callback": {"type": "http", "details": {"url": "http://localhost/callback", "method": "POST"}}}`
+	if err := json.Unmarshal([]byte(body), &d); err != nil {
+		t.Fatal(err)
+	}
+	d.ScheduleId = store.DeclaredScheduleId(appId, name)
+	d.AppId = appId
+	d.Status = store.Scheduled
+	return d.Schedule
+}
+
+func TestService_ApplySchedules(t *testing.T) {
+	service := setupMocks()
+	stale := declaredSchedule(t, "test", "stale", "0 1 * * *")
+	imperative := declaredSchedule(t, "test", "imperative", "0 1 * * *")
+	imperative.ScheduleId = gocql.TimeUUID()
+	deleted := declaredSchedule(t, "test", "removed-earlier", "0 1 * * *")
+	deleted.Status = store.Deleted
+	scheduleDao := &MockScheduleDaoForDeclared{existing: []store.Schedule{
+		declaredSchedule(t, "test", "nightly", "0 2 * * *"),
+		declaredSchedule(t, "test", "hourly", "0 * * * *"),
+		stale,
+		imperative,
+		deleted,
+	}}
+	service.ScheduleDao = scheduleDao
+
+	body := []byte(`{"schedules": [
+		{"name": "nightly", "payload": "{}", "cronExpression": "0 2 * * *", "callback": {"type": "http", "details": {"url": "http://localhost/callback", "method": "POST"}}},
+		{"name": "hourly", "payload": "{}", "cronExpression": "30 * * * *", "callback": {"type": "http", "details": {"url": "http://localhost/callback", "method": "POST"}}},
+		{"name": "weekly", "payload": "{}", "cronExpression": "0 3 * * MON", "callback": {"type": "http", "details": {"url": "http://localhost/callback", "method": "POST"}}}
+	]}`)
+
+	apply := func(query string) ApplySchedulesData {
+		req := httptest.NewRequest(http.MethodPut, "/goscheduler/apps/test/declared-schedules"+query, bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"appId": "test"})
+		rr := httptest.NewRecorder()
+		service.ApplySchedules(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("handler returned wrong status code: got %v want %v, %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+
+		var response ApplySchedulesResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return response.Data
+	}
+
+	plan := apply("?dry_run=true")
+	if !plan.DryRun || len(scheduleDao.created)+len(scheduleDao.updated)+len(scheduleDao.deleted) != 0 {
+		t.Errorf("expected a dry run to change nothing, got %+v", scheduleDao)
+	}
+
+	for _, data := range []ApplySchedulesData{plan, apply("")} {
+		if len(data.Created) != 1 || data.Created[0].Name != "weekly" || data.Created[0].ScheduleId != store.DeclaredScheduleId("test", "weekly") {
+			t.Errorf("expected weekly to be created, got %+v", data.Created)
+		}
+		if len(data.Updated) != 1 || data.Updated[0].Name != "hourly" {
+			t.Errorf("expected hourly to be updated, got %+v", data.Updated)
+		}
+		if len(data.Unchanged) != 1 || data.Unchanged[0].Name != "nightly" {
+			t.Errorf("expected nightly to be unchanged, got %+v", data.Unchanged)
+		}
+		if len(data.Deleted) != 1 || data.Deleted[0].ScheduleId != stale.ScheduleId {
+			t.Errorf("expected only the stale declared schedule to be deleted, got %+v", data.Deleted)
+		}
+	}
+
+	if len(scheduleDao.created) != 1 || scheduleDao.created[0] != store.DeclaredScheduleId("test", "weekly") {
+		t.Errorf("expected weekly to be created with its name based id, got %v", scheduleDao.created)
+	}
+	if len(scheduleDao.updated) != 1 || scheduleDao.updated[0] != store.DeclaredScheduleId("test", "hourly") {
+		t.Errorf("expected hourly to be updated, got %v", scheduleDao.updated)
+	}
+	if len(scheduleDao.deleted) != 1 || scheduleDao.deleted[0] != stale.ScheduleId {
+		t.Errorf("expected stale to be deleted, got %v", scheduleDao.deleted)
+	}
+}
+
+func TestService_ApplySchedulesInvalid(t *testing.T) {
+	service := setupMocks()
+	service.ScheduleDao = &MockScheduleDaoForDeclared{}
+
+	for _, test := range []struct {
+		name string
+		body string
+		code string
+	}{
+		{"duplicate names", `{"schedules": [{"name": "a", "cronExpression": "* * * * *"}, {"name": "a", "cronExpression": "* * * * *"}]}`, "INVALID_DATA"},
+		{"one time schedule", `{"schedules": [{"name": "a", "scheduleTime": 1686820560}]}`, "INVALID_DATA"},
+		{"invalid field", `{"schedules": [{"name": "a", "cronExpression": "61 * * * *", "payload": "{}", "callback": {"type": "http", "details": {"url": "http://localhost/callback", "method": "POST"}}}]}`, "INVALID_DATA"},
+		{"malformed", `{"schedules": {}}`, "MALFORMED_REQUEST"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/goscheduler/apps/test/declared-schedules", bytes.NewReader([]byte(test.body)))
+			req = mux.SetURLVars(req, map[string]string{"appId": "test"})
+			rr := httptest.NewRecorder()
+			service.ApplySchedules(rr, req)
+
+			var response er.ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Error.Code != test.code {
+				t.Errorf("expected the code %s, got %+v", test.code, response.Error)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	sch "github.com/myntra/goscheduler/store"
 	"net/http"
 )
@@ -40,6 +41,8 @@ func (s *Service) CancelSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	schedule.RequestId = logger.RequestID(r.Context())
+	schedule.Logger().Infof("Schedule deleted")
 	s.recordRequestAppStatus(constants.DeleteSchedule, schedule.AppId, constants.Success)
 
 	status := Status{
@@ -68,6 +71,7 @@ func (s *Service) DeleteSchedule(uuid string) (sch.Schedule, error) {
 	case gocql.ErrNotFound:
 		return sch.Schedule{}, er.NewError(er.DataNotFound, err)
 	case nil:
+		sch.PublishEvent(sch.ScheduleDeleted, schedule)
 		return schedule, nil
 	default:
 		return sch.Schedule{}, er.NewError(er.DataFetchFailure, err)
@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/diagnostics"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/sla"
+)
+
+const (
+	defaultDiagnosticsMinutes = 5
+	maxDiagnosticsMinutes     = 15
+)
+
+// GetDiagnostics returns the slowest Cassandra queries and the poll lag of every partition polled by this node.
+// When an app_id is given, the pending schedule count of each of its partitions over the next few minutes is
+// included as well, largest first.
+func (s *Service) GetDiagnostics(w http.ResponseWriter, r *http.Request) {
+	data, err := s.fetchDiagnostics(r)
+	if err != nil {
+		s.recordRequestStatus(constants.GetDiagnostics, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestStatus(constants.GetDiagnostics, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		GetDiagnosticsResponse{
+			Status: status,
+			Data:   data,
+		})
+}
+
+func (s *Service) fetchDiagnostics(r *http.Request) (GetDiagnosticsData, error) {
+	recorder := diagnostics.Default()
+	data := GetDiagnosticsData{
+		SlowQueries: recorder.SlowQueries(),
+		PollLag:     recorder.PollLags(time.Now()),
+		FiringLag:   sla.Default().Lags(time.Now().Add(-time.Minute)),
+	}
+
+	appId := r.URL.Query().Get("app_id")
+	if len(appId) == 0 {
+		return data, nil
+	}
+
+	minutes, err := parseDiagnosticsMinutes(r)
+	if err != nil {
+		return GetDiagnosticsData{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	partitions, err := s.pendingSchedules(appId, minutes, time.Now())
+	if err != nil {
+		return GetDiagnosticsData{}, err
+	}
+	data.Partitions = partitions
+
+	return data, nil
+}
+
+// pendingSchedules counts the schedules stored in every partition of an app for the buckets of the next minutes.
+func (s *Service) pendingSchedules(appId string, minutes int, now time.Time) ([]PartitionSize, error) {
+	app, err := s.getApp(appId)
+	if err != nil {
+		return nil, err
+	}
+
+	start := now.Truncate(time.Minute)
+	partitions := make([]PartitionSize, 0, app.Partitions)
+	for partitionId := 0; partitionId < int(app.Partitions); partitionId++ {
+		size := PartitionSize{AppId: app.AppId, PartitionId: partitionId}
+		for i := 0; i < minutes; i++ {
+			count, err := s.ScheduleDao.CountSchedules(app.AppId, partitionId, start.Add(time.Duration(i)*time.Minute))
+			if err != nil {
+				return nil, er.NewError(er.DataFetchFailure, err)
+			}
+			size.PendingSchedules += count
+		}
+		partitions = append(partitions, size)
+	}
+
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return partitions[i].PendingSchedules > partitions[j].PendingSchedules
+	})
+
+	return partitions, nil
+}
+
+func parseDiagnosticsMinutes(r *http.Request) (int, error) {
+	minutesParam := r.URL.Query().Get("minutes")
+	if len(minutesParam) == 0 {
+		return defaultDiagnosticsMinutes, nil
+	}
+
+	minutes, err := strconv.Atoi(minutesParam)
+	if err != nil || minutes <= 0 || minutes > maxDiagnosticsMinutes {
+		return 0, errors.New(fmt.Sprintf("minutes should be an integer between 1 and %d", maxDiagnosticsMinutes))
+	}
+
+	return minutes, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/dao"
+)
+
+func TestService_GetDiagnostics(t *testing.T) {
+	service := &Service{
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		query      map[string]string
+		Status     int
+		partitions int
+		pending    int
+	}{
+		{map[string]string{}, http.StatusOK, 0, 0},
+		{map[string]string{"app_id": "test"}, http.StatusOK, 1, defaultDiagnosticsMinutes},
+		{map[string]string{"app_id": "test", "minutes": "10"}, http.StatusOK, 1, 10},
+		{map[string]string{"app_id": "test", "minutes": "16"}, http.StatusBadRequest, 0, 0},
+		{map[string]string{"app_id": "test", "minutes": "abc"}, http.StatusBadRequest, 0, 0},
+		{map[string]string{"app_id": "testGetAppErrorNotFound"}, http.StatusBadRequest, 0, 0},
+		{map[string]string{"app_id": "error"}, http.StatusInternalServerError, 0, 0},
+	} {
+		req, err := http.NewRequest("GET", "/goscheduler/admin/diagnostics", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		q := req.URL.Query()
+		for key, value := range test.query {
+			q.Add(key, value)
+		}
+		req.URL.RawQuery = q.Encode()
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.GetDiagnostics)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.Status {
+			t.Errorf("handler returned wrong status code for %v: got %v want %v", test.query, status, test.Status)
+			continue
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response GetDiagnosticsResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Data.Partitions) != test.partitions {
+			t.Errorf("expected %d partitions for %v, got %d", test.partitions, test.query, len(response.Data.Partitions))
+			continue
+		}
+		if test.partitions > 0 && response.Data.Partitions[0].PendingSchedules != test.pending {
+			t.Errorf("expected %d pending schedules for %v, got %d", test.pending, test.query, response.Data.Partitions[0].PendingSchedules)
+		}
+	}
+}
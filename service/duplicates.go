@@ -0,0 +1,167 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// GetDuplicateFires reports the schedules of an app due in a window whose callback was dispatched more than once,
+// along with the nodes which dispatched it. Re-fires of the reconciliation endpoint are not counted.
+func (s *Service) GetDuplicateFires(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	timeRange, err := parseScanWindow(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetDuplicateFires, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	limit, err := parseScanLimit(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetDuplicateFires, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	app, err := s.getActiveOrInactiveApp(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetDuplicateFires, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	data := DuplicateFiresData{
+		AppId:     app.AppId,
+		StartTime: timeRange.StartTime.Unix(),
+		EndTime:   timeRange.EndTime.Unix(),
+		Offenders: []DuplicateFire{},
+	}
+
+	err = s.scanWindow(app, timeRange, func(schedules []store.Schedule) (bool, error) {
+		return s.collectDuplicateFires(schedules, limit, &data)
+	})
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetDuplicateFires, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+
+	if data.Fired > 0 {
+		data.DuplicationRate = float64(data.Duplicated) / float64(data.Fired)
+	}
+
+	log.Infof("Scanned app %s from %s to %s for duplicate fires: %d fired, %d duplicated",
+		appId, timeRange.StartTime, timeRange.EndTime, data.Fired, data.Duplicated)
+	s.recordRequestAppStatus(constants.GetDuplicateFires, appId, constants.Success)
+	_ = json.NewEncoder(w).Encode(DuplicateFiresResponse{
+		Status: Status{
+			StatusCode:    constants.SuccessCode200,
+			StatusMessage: constants.Success,
+			StatusType:    constants.Success,
+			TotalCount:    len(data.Offenders),
+		},
+		Data: data,
+	})
+}
+
+// collectDuplicateFires reads the delivery receipts of the schedules and adds the ones dispatched more than once
+// to the offenders, up to the limit
+func (s *Service) collectDuplicateFires(schedules []store.Schedule, limit int, data *DuplicateFiresData) (bool, error) {
+	data.Scanned += len(schedules)
+	if len(schedules) == 0 {
+		return true, nil
+	}
+
+	ids := make([]gocql.UUID, 0, len(schedules))
+	for _, schedule := range schedules {
+		ids = append(ids, schedule.ScheduleId)
+	}
+
+	receipts, err := s.ScheduleDao.GetBulkDeliveryReceipts(ids)
+	if err != nil {
+		return false, err
+	}
+
+	dispatches := make(map[gocql.UUID][]store.DeliveryReceipt)
+	for _, receipt := range receipts {
+		if receipt.Reconciliation {
+			continue
+		}
+		dispatches[receipt.ScheduleId] = append(dispatches[receipt.ScheduleId], receipt)
+	}
+
+	for _, schedule := range schedules {
+		fires := dispatches[schedule.ScheduleId]
+		if len(fires) == 0 {
+			continue
+		}
+		data.Fired++
+		if len(fires) == 1 {
+			continue
+		}
+
+		data.Duplicated++
+		if len(data.Offenders) == limit {
+			data.Truncated = true
+			continue
+		}
+		data.Offenders = append(data.Offenders, newDuplicateFire(schedule, fires))
+	}
+
+	return true, nil
+}
+
+// newDuplicateFire describes the dispatches of a schedule in the order they happened
+func newDuplicateFire(schedule store.Schedule, fires []store.DeliveryReceipt) DuplicateFire {
+	sort.SliceStable(fires, func(i, j int) bool {
+		return fires[i].DispatchedAt < fires[j].DispatchedAt
+	})
+
+	duplicate := DuplicateFire{
+		ScheduleId:   schedule.ScheduleId,
+		ScheduleTime: schedule.ScheduleTime,
+		Fires:        len(fires),
+		Nodes:        []string{},
+	}
+	if schedule.ParentScheduleId != (gocql.UUID{}) {
+		duplicate.ParentScheduleId = schedule.ParentScheduleId.String()
+	}
+
+	seen := make(map[string]bool)
+	for _, fire := range fires {
+		duplicate.DispatchedAt = append(duplicate.DispatchedAt, fire.DispatchedAt)
+		if !seen[fire.Node] {
+			seen[fire.Node] = true
+			duplicate.Nodes = append(duplicate.Nodes, fire.Node)
+		}
+	}
+	return duplicate
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockScheduleDaoForDuplicates serves the delivery receipts recorded for the schedules of the buckets
+type MockScheduleDaoForDuplicates struct {
+	MockScheduleDaoForReconciliation
+	receipts []store.DeliveryReceipt
+}
+
+func (m *MockScheduleDaoForDuplicates) GetBulkDeliveryReceipts(uuids []gocql.UUID) ([]store.DeliveryReceipt, error) {
+	var receipts []store.DeliveryReceipt
+	for _, receipt := range m.receipts {
+		for _, id := range uuids {
+			if receipt.ScheduleId == id {
+				receipts = append(receipts, receipt)
+			}
+		}
+	}
+	return receipts, nil
+}
+
+func (m *MockScheduleDaoForDuplicates) fire(id gocql.UUID, node string, dispatchedAt int64, reconciliation bool) {
+	m.receipts = append(m.receipts, store.DeliveryReceipt{
+		ScheduleId:     id,
+		AppId:          "test",
+		DispatchedAt:   dispatchedAt,
+		Node:           node,
+		Reconciliation: reconciliation,
+	})
+}
+
+func TestService_GetDuplicateFires(t *testing.T) {
+	store.Registry[reconciliationCallbackType] = func() store.Callback { return &refiredCallback{Type: reconciliationCallbackType} }
+	defer delete(store.Registry, reconciliationCallbackType)
+
+	location, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the window of a scan is truncated to the minute
+	now := time.Now().In(location).Truncate(time.Minute)
+	start := now.Add(-30 * time.Minute)
+
+	scheduleDao := &MockScheduleDaoForDuplicates{}
+	once := scheduleDao.add(0, now.Add(-20*time.Minute), "once")
+	scheduleDao.fire(once, "node1", 1, false)
+	twice := scheduleDao.add(1, now.Add(-15*time.Minute), "twice")
+	scheduleDao.fire(twice, "node2", 3, false)
+	scheduleDao.fire(twice, "node1", 2, false)
+	thrice := scheduleDao.add(0, now.Add(-10*time.Minute), "thrice")
+	scheduleDao.fire(thrice, "node1", 4, false)
+	scheduleDao.fire(thrice, "node1", 5, false)
+	scheduleDao.fire(thrice, "node2", 6, false)
+	refired := scheduleDao.add(1, now.Add(-5*time.Minute), "refired")
+	scheduleDao.fire(refired, "node1", 7, false)
+	scheduleDao.fire(refired, "node2", 8, true)
+	scheduleDao.add(0, now.Add(-time.Minute), "unfired")
+
+	service := setupMocks()
+	service.ClusterDao = MockClusterDaoForResize{}
+	service.ScheduleDao = scheduleDao
+
+	window := url.Values{"start_time": {start.Format(dateTimeLayout)}, "end_time": {now.Format(dateTimeLayout)}}
+	limited := url.Values{"start_time": window["start_time"], "end_time": window["end_time"], "limit": {"1"}}
+
+	for _, test := range []struct {
+		appId     string
+		query     url.Values
+		status    int
+		offenders []DuplicateFire
+	}{
+		{"test", window, http.StatusOK, []DuplicateFire{
+			{ScheduleId: twice, Fires: 2, Nodes: []string{"node1", "node2"}, DispatchedAt: []int64{2, 3}},
+			{ScheduleId: thrice, Fires: 3, Nodes: []string{"node1", "node2"}, DispatchedAt: []int64{4, 5, 6}},
+		}},
+		{"test", limited, http.StatusOK, []DuplicateFire{
+			{ScheduleId: twice, Fires: 2, Nodes: []string{"node1", "node2"}, DispatchedAt: []int64{2, 3}},
+		}},
+		{"test", url.Values{"start_time": {now.Add(-25 * time.Hour).Format(dateTimeLayout)}, "end_time": {now.Format(dateTimeLayout)}}, http.StatusBadRequest, nil},
+		{"test", url.Values{"limit": {"0"}}, http.StatusBadRequest, nil},
+		{"testGetAppErrorNotFound", url.Values{}, http.StatusBadRequest, nil},
+	} {
+		req, err := http.NewRequest("GET", "/goscheduler/admin/apps/{appId}/duplicates?"+test.query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.GetDuplicateFires)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.status {
+			t.Errorf("handler returned wrong status code for %v: got %v want %v", test.query, status, test.status)
+			continue
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response DuplicateFiresResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		data := response.Data
+		if data.Scanned != 5 || data.Fired != 4 || data.Duplicated != 2 || data.DuplicationRate != 0.5 {
+			t.Errorf("unexpected counts for %v: %+v", test.query, data)
+		}
+		if data.Truncated != (test.query.Get("limit") != "") {
+			t.Errorf("unexpected truncation for %v: %+v", test.query, data)
+		}
+		if len(data.Offenders) != len(test.offenders) {
+			t.Fatalf("expected offenders %+v for %v, got %+v", test.offenders, test.query, data.Offenders)
+		}
+		for i, expected := range test.offenders {
+			offender := data.Offenders[i]
+			if offender.ScheduleId != expected.ScheduleId || offender.Fires != expected.Fires ||
+				len(offender.Nodes) != len(expected.Nodes) || len(offender.DispatchedAt) != len(expected.DispatchedAt) {
+				t.Errorf("expected offender %+v at %d for %v, got %+v", expected, i, test.query, offender)
+				continue
+			}
+			for j := range expected.Nodes {
+				if offender.Nodes[j] != expected.Nodes[j] {
+					t.Errorf("expected nodes %v for %v, got %v", expected.Nodes, test.query, offender.Nodes)
+				}
+			}
+			for j := range expected.DispatchedAt {
+				if offender.DispatchedAt[j] != expected.DispatchedAt[j] {
+					t.Errorf("expected dispatches %v for %v, got %v", expected.DispatchedAt, test.query, offender.DispatchedAt)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// defaultHeartbeatInterval keeps the idle streams open when no heartbeat interval is configured
+const defaultHeartbeatInterval = 15 * time.Second
+
+// StreamEvents streams the fire events of the runs of an app fired on this node as server-sent events, until the
+// client disconnects. The types query parameter narrows the stream down to a comma separated list of event types.
+func (s *Service) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	subscription, err := s.SubscribeEvents(w, r, appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.StreamEvents, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+	defer sch.FireEventStream.Unsubscribe(subscription)
+
+	s.recordRequestAppStatus(constants.StreamEvents, appId, constants.Success)
+
+	w.Header().Set(constants.ContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	flusher := w.(http.Flusher)
+	_, _ = fmt.Fprint(w, ": streaming events of app "+appId+"\n\n")
+	flusher.Flush()
+
+	interval := time.Duration(s.Config.EventStreamConfig.HeartbeatSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-subscription.Events:
+			if err = writeDroppedEvents(w, subscription); err == nil {
+				err = writeEvent(w, event)
+			}
+		case <-heartbeat.C:
+			if err = writeDroppedEvents(w, subscription); err == nil {
+				_, err = fmt.Fprint(w, ": heartbeat\n\n")
+			}
+		}
+		if err != nil {
+			logger.FromContext(r.Context()).Errorf("Streaming events of app %s failed with error %s", appId, err.Error())
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// SubscribeEvents validates the request for the event stream of an app and subscribes to the stream
+func (s *Service) SubscribeEvents(w http.ResponseWriter, r *http.Request, appId string) (*sch.EventSubscription, error) {
+	if _, ok := w.(http.Flusher); !ok {
+		return nil, er.NewError(er.InvalidDataCode, errors.New("streaming is not supported for this connection"))
+	}
+
+	types, err := sch.ParseFireEventTypes(r.URL.Query().Get("types"))
+	if err != nil {
+		return nil, er.NewError(er.InvalidDataCode, err)
+	}
+
+	if _, err = s.getApp(appId); err != nil {
+		return nil, err
+	}
+
+	subscription, err := sch.FireEventStream.Subscribe(appId, types)
+	if err != nil {
+		return nil, er.NewError(er.TooManyRequests, err)
+	}
+	return subscription, nil
+}
+
+// writeEvent writes the event as a server-sent event named after its type
+func writeEvent(w http.ResponseWriter, event sch.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.EventId, event.Type, data)
+	return err
+}
+
+// writeDroppedEvents tells the client how many events it missed since the last one it received, as it fell behind
+func writeDroppedEvents(w http.ResponseWriter, subscription *sch.EventSubscription) error {
+	dropped := subscription.Dropped()
+	if dropped == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "event: dropped\ndata: {\"count\":%d}\n\n", dropped)
+	return err
+}
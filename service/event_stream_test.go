@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_StreamEvents(t *testing.T) {
+	defer func(stream *store.EventStream) { store.FireEventStream = stream }(store.FireEventStream)
+	store.FireEventStream = store.NewEventStream(1, 10)
+
+	service := &Service{
+		Config:      conf.NewConfig(),
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/goscheduler/apps/{appId}/events/stream", service.StreamEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	for _, test := range []struct {
+		name   string
+		appId  string
+		query  string
+		status int
+	}{
+		{"AppNotRegistered", "testAppNotFound", "", http.StatusBadRequest},
+		{"InvalidType", "test", "?types=schedule.created", http.StatusBadRequest},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/goscheduler/apps/" + test.appId + "/events/stream" + test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != test.status {
+				t.Errorf("handler returned wrong status code: got %v want %v", resp.StatusCode, test.status)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/goscheduler/apps/test/events/stream?types=schedule.fired", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %v with content type %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	busy, err := http.Get(server.URL + "/goscheduler/apps/other/events/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = busy.Body.Close()
+	if busy.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the clients past the limit turned away, got %v", busy.StatusCode)
+	}
+
+	store.FireEventStream.Broadcast(store.Event{EventId: "failed", AppId: "test", Type: store.ScheduleFailed})
+	store.FireEventStream.Broadcast(store.Event{EventId: "fired", AppId: "test", Type: store.ScheduleFired})
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && !strings.HasPrefix(scanner.Text(), "data: ") {
+		lines = append(lines, scanner.Text())
+	}
+	if streamed := strings.Join(lines, "\n"); !strings.HasSuffix(streamed, "id: fired\nevent: schedule.fired") {
+		t.Errorf("expected only the fired event streamed, got %s", streamed)
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for store.FireEventStream.Subscribers("test") != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if store.FireEventStream.Subscribers("test") != 0 {
+		t.Error("expected the client unsubscribed once disconnected")
+	}
+}
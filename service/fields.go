@@ -0,0 +1,467 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	// NDJSONContentType streams the records of a listing one JSON document per line
+	NDJSONContentType = "application/x-ndjson"
+	// nextFireTimesField is computed for the recurring schedules of a response only when it is selected
+	nextFireTimesField = "nextFireTimes"
+	// Number of upcoming fire times computed for the nextFireTimes field
+	nextFireTimesCount = 5
+	// Number of records streamed between two flushes of an ndjson response
+	ndjsonFlushRecords = 100
+)
+
+// streamingRoutes write their responses as they go, e.g. the snapshot of an export or the events of an event stream,
+// so their responses are never held to select their fields
+var streamingRoutes = map[string]bool{
+	constants.ExportSchedules: true,
+	constants.StreamEvents:    true,
+}
+
+// FieldSelection shapes the successful responses of the GET APIs for their clients: ?fields=scheduleId,status,nextFireTimes
+// keeps only the listed fields of the records of the response, i.e. the schedules of a listing or the schedule of a
+// get, dropping the callbacks and payloads dominating large listings. nextFireTimes are the upcoming fire times of the
+// active recurring schedules, computed only when selected.
+//
+// A listing streamed as ndjson selects the fields of its records as it writes them, see writeListing, and is passed
+// through as is. Other responses and the streaming routes are returned as is.
+func FieldSelection(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := parseFields(r.URL.Query().Get("fields"))
+		if r.Method != http.MethodGet || len(fields) == 0 || streamingRoutes[routeName(r)] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{ResponseWriter: w}
+		next.ServeHTTP(buffered, r)
+		if buffered.streamed {
+			return
+		}
+
+		envelope, ok := buffered.envelope()
+		if !ok {
+			buffered.flush()
+			return
+		}
+
+		shapeRecords(envelope, fields, time.Now())
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			buffered.flush()
+			return
+		}
+		w.WriteHeader(buffered.statusCode())
+		_, _ = w.Write(append(body, '\n'))
+	})
+}
+
+func routeName(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		return route.GetName()
+	}
+	return ""
+}
+
+// parseFields returns the set of fields selected by the fields query parameter
+func parseFields(param string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(param, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = true
+		}
+	}
+	return fields
+}
+
+func acceptsNDJSON(accept string) bool {
+	for _, mediaType := range strings.Split(accept, ",") {
+		if strings.TrimSpace(strings.Split(mediaType, ";")[0]) == NDJSONContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// shapeRecords selects the fields of the records of the response in place
+func shapeRecords(envelope map[string]interface{}, fields map[string]bool, now time.Time) {
+	records, set := recordsOf(envelope)
+	for i, record := range records {
+		if object, ok := record.(map[string]interface{}); ok {
+			records[i] = selectFields(object, fields, now)
+		}
+	}
+	set(records)
+}
+
+// recordsOf returns the records of the data of a response along with the function replacing them: the data itself
+// when it is a list, the list of objects in it, e.g. the schedules of a listing, the single object in it, e.g. the
+// schedule of a get, or else the data itself
+func recordsOf(envelope map[string]interface{}) ([]interface{}, func([]interface{})) {
+	switch data := envelope["data"].(type) {
+	case []interface{}:
+		return data, func(records []interface{}) { envelope["data"] = records }
+	case map[string]interface{}:
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if list, ok := data[key].([]interface{}); ok && isObjectList(list) {
+				key := key
+				return list, func(records []interface{}) { data[key] = records }
+			}
+		}
+		if len(keys) == 1 {
+			if object, ok := data[keys[0]].(map[string]interface{}); ok {
+				key := keys[0]
+				return []interface{}{object}, func(records []interface{}) { data[key] = records[0] }
+			}
+		}
+		return []interface{}{data}, func(records []interface{}) { envelope["data"] = records[0] }
+	default:
+		return nil, func([]interface{}) {}
+	}
+}
+
+func isObjectList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// selectFields keeps the selected fields of a record, computing its nextFireTimes if selected
+func selectFields(record map[string]interface{}, fields map[string]bool, now time.Time) map[string]interface{} {
+	selected := make(map[string]interface{}, len(fields))
+	for field := range fields {
+		if value, ok := record[field]; ok {
+			selected[field] = value
+		}
+	}
+
+	if fields[nextFireTimesField] {
+		if times := scheduleOf(record).NextFireTimes(now, nextFireTimesCount); times != nil {
+			selected[nextFireTimesField] = times
+		}
+	}
+	return selected
+}
+
+// scheduleOf returns the recurrence and the status of the schedule of a record
+func scheduleOf(record map[string]interface{}) store.Schedule {
+	text := func(field string) string {
+		value, _ := record[field].(string)
+		return value
+	}
+
+	schedule := store.Schedule{
+		CronExpression: text("cronExpression"),
+		Every:          text("every"),
+		RRule:          text("rrule"),
+		Status:         store.Status(text("status")),
+	}
+	if anchor, ok := record["anchor"].(json.Number); ok {
+		schedule.Anchor, _ = anchor.Int64()
+	}
+	return schedule
+}
+
+// recordListing is a response listing records, which is streamed one record per line to the clients accepting ndjson
+type recordListing interface {
+	// eachRecord writes the records of the listing in order, stopping at the first error
+	eachRecord(write func(record interface{}) error) error
+	// listingFields returns the other fields of the listing, returned as headers of an ndjson response
+	listingFields() map[string]interface{}
+}
+
+// writeListing writes a successful listing, as ndjson to the clients accepting it and as JSON otherwise
+func writeListing(w http.ResponseWriter, r *http.Request, listing recordListing) error {
+	if !acceptsNDJSON(r.Header.Get("Accept")) {
+		return json.NewEncoder(w).Encode(listing)
+	}
+	return writeNDJSON(w, listing, parseFields(r.URL.Query().Get("fields")))
+}
+
+// writeNDJSON streams the records of a listing one per line with their selected fields, flushing them as they are
+// written, so that a single record is encoded at a time
+func writeNDJSON(w http.ResponseWriter, listing recordListing, fields map[string]bool) error {
+	for field, value := range listing.listingFields() {
+		w.Header().Set(headerOf(field), fmt.Sprint(value))
+	}
+	w.Header().Set(constants.ContentType, NDJSONContentType)
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	now, written := time.Now(), 0
+	err := listing.eachRecord(func(record interface{}) error {
+		if len(fields) > 0 {
+			selected, err := selectRecordFields(record, fields, now)
+			if err != nil {
+				return err
+			}
+			record = selected
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		if written++; flusher != nil && written%ndjsonFlushRecords == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return err
+}
+
+// selectRecordFields keeps the selected fields of a record of a listing
+func selectRecordFields(record interface{}, fields map[string]bool, now time.Time) (map[string]interface{}, error) {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err = decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	return selectFields(object, fields, now), nil
+}
+
+// headerOf returns the header carrying a field of the data of an ndjson response, e.g. X-Continuation-Token
+func headerOf(field string) string {
+	var header strings.Builder
+	header.WriteString("X")
+	for i, c := range field {
+		if i == 0 || unicode.IsUpper(c) {
+			header.WriteRune('-')
+		}
+		if i == 0 {
+			c = unicode.ToUpper(c)
+		}
+		header.WriteRune(c)
+	}
+	return header.String()
+}
+
+// bufferedResponse holds the response of a handler so that it can be shaped before being written.
+// An ndjson listing, which selects its fields itself, is passed through as it is written.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	streamed bool
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status != 0 {
+		return
+	}
+	b.status = status
+	if b.Header().Get(constants.ContentType) == NDJSONContentType {
+		b.streamed = true
+		b.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.WriteHeader(http.StatusOK)
+	}
+	if b.streamed {
+		return b.ResponseWriter.Write(p)
+	}
+	return b.body.Write(p)
+}
+
+// Flush flushes the records of a streamed listing written so far
+func (b *bufferedResponse) Flush() {
+	if flusher, ok := b.ResponseWriter.(http.Flusher); ok && b.streamed {
+		flusher.Flush()
+	}
+}
+
+func (b *bufferedResponse) statusCode() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// envelope parses a successful JSON response carrying data, keeping the precision of its numbers
+func (b *bufferedResponse) envelope() (map[string]interface{}, bool) {
+	if b.statusCode() != http.StatusOK {
+		return nil, false
+	}
+
+	var envelope map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b.body.Bytes()))
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil {
+		return nil, false
+	}
+	_, ok := envelope["data"]
+	return envelope, ok
+}
+
+// flush writes the response of the handler as is
+func (b *bufferedResponse) flush() {
+	b.ResponseWriter.WriteHeader(b.statusCode())
+	_, _ = b.ResponseWriter.Write(b.body.Bytes())
+}
+
+// The listings streamed as ndjson by writeListing
+
+func (r GetPaginatedAppSchedulesResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Schedules {
+		if err := write(&r.Data.Schedules[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetPaginatedAppSchedulesResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{
+		"totalCount":            r.Status.TotalCount,
+		"continuationToken":     r.Data.ContinuationToken,
+		"continuationStartTime": r.Data.ContinuationStartTime,
+	}
+}
+
+func (r GetCronSchedulesResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data {
+		if err := write(&r.Data[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetCronSchedulesResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{"totalCount": r.Status.TotalCount}
+}
+
+func (r GetPaginatedRunSchedulesResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Schedules {
+		if err := write(&r.Data.Schedules[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetPaginatedRunSchedulesResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{
+		"totalCount":        r.Status.TotalCount,
+		"continuationToken": r.Data.ContinuationToken,
+	}
+}
+
+func (r GetDeliveryReceiptsResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Receipts {
+		if err := write(&r.Data.Receipts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetDeliveryReceiptsResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{"totalCount": r.Status.TotalCount}
+}
+
+func (r GetCallbackAttemptsResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Attempts {
+		if err := write(&r.Data.Attempts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetCallbackAttemptsResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{"totalCount": r.Status.TotalCount}
+}
+
+func (r GetScheduleVersionsResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Versions {
+		if err := write(&r.Data.Versions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetScheduleVersionsResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{"totalCount": r.Status.TotalCount}
+}
+
+func (r GetAppsResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Apps {
+		if err := write(&r.Data.Apps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetAppsResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{"totalCount": r.Status.TotalCount}
+}
+
+func (r GetDueRunsResponse) eachRecord(write func(record interface{}) error) error {
+	for i := range r.Data.Runs {
+		if err := write(&r.Data.Runs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r GetDueRunsResponse) listingFields() map[string]interface{} {
+	return map[string]interface{}{"totalCount": r.Status.TotalCount}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+func listingHandler(w http.ResponseWriter, r *http.Request) {
+	schedules := []store.Schedule{
+		{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "large payload", CronExpression: "*/5 * * * *", Status: store.Scheduled},
+		{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "large payload", ScheduleTime: 1700000000, Status: store.Success},
+	}
+	status := Status{StatusCode: 200, StatusMessage: "Success", StatusType: "Success", TotalCount: len(schedules)}
+	_ = writeListing(w, r, GetPaginatedAppSchedulesResponse{
+		Status: status,
+		Data:   GetPaginatedAppSchedulesData{Schedules: schedules, ContinuationToken: "abc", ContinuationStartTime: 1700000000},
+	})
+}
+
+func TestFieldSelection_SelectsFields(t *testing.T) {
+	req := httptest.NewRequest("GET", "/goscheduler/apps/test/schedules?fields=scheduleId,status,nextFireTimes", nil)
+	rr := httptest.NewRecorder()
+	FieldSelection(http.HandlerFunc(listingHandler)).ServeHTTP(rr, req)
+
+	var response struct {
+		Status Status `json:"status"`
+		Data   struct {
+			Schedules         []map[string]interface{} `json:"schedules"`
+			ContinuationToken string                   `json:"continuationToken"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid response %s: %v", rr.Body.String(), err)
+	}
+
+	if response.Status.TotalCount != 2 || response.Data.ContinuationToken != "abc" || len(response.Data.Schedules) != 2 {
+		t.Fatalf("expected the envelope to be kept, got %s", rr.Body.String())
+	}
+	recurring, oneTime := response.Data.Schedules[0], response.Data.Schedules[1]
+	if len(recurring) != 3 || recurring["status"] != "SCHEDULED" {
+		t.Errorf("expected the selected fields of the recurring schedule, got %v", recurring)
+	}
+	if times, ok := recurring["nextFireTimes"].([]interface{}); !ok || len(times) != nextFireTimesCount {
+		t.Errorf("expected %d next fire times, got %v", nextFireTimesCount, recurring["nextFireTimes"])
+	}
+	if _, ok := oneTime["nextFireTimes"]; ok || oneTime["payload"] != nil || oneTime["scheduleId"] == nil {
+		t.Errorf("expected the one time schedule without payload nor next fire times, got %v", oneTime)
+	}
+}
+
+func TestFieldSelection_StreamsNDJSON(t *testing.T) {
+	for _, fields := range []string{"", "scheduleId"} {
+		req := httptest.NewRequest("GET", "/goscheduler/apps/test/schedules?fields="+fields, nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rr := httptest.NewRecorder()
+		FieldSelection(http.HandlerFunc(listingHandler)).ServeHTTP(rr, req)
+
+		if contentType := rr.Header().Get("Content-Type"); contentType != NDJSONContentType {
+			t.Errorf("expected content type %s, got %s", NDJSONContentType, contentType)
+		}
+		if token := rr.Header().Get("X-Continuation-Token"); token != "abc" {
+			t.Errorf("expected the continuation token in a header, got %q", token)
+		}
+		if total := rr.Header().Get("X-Total-Count"); total != "2" {
+			t.Errorf("expected the total count in a header, got %q", total)
+		}
+		// the listing is written straight to the client rather than held by the field selection
+		if !rr.Flushed {
+			t.Errorf("expected the records to be flushed")
+		}
+
+		lines := 0
+		scanner := bufio.NewScanner(strings.NewReader(rr.Body.String()))
+		for scanner.Scan() {
+			var record map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record["scheduleId"] == nil {
+				t.Errorf("unexpected line %s: %v", scanner.Text(), err)
+			}
+			if fields != "" && len(record) != 1 {
+				t.Errorf("expected only the selected field, got %v", record)
+			}
+			if fields == "" && record["payload"] != "large payload" {
+				t.Errorf("expected the whole schedule, got %v", record)
+			}
+			lines++
+		}
+		if lines != 2 {
+			t.Errorf("expected a line per schedule, got %d lines", lines)
+		}
+	}
+}
+
+func TestFieldSelection_StreamingRoutes(t *testing.T) {
+	events := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"data\": {\"payload\": \"large payload\"}}\n\n"))
+		w.(http.Flusher).Flush()
+	}
+
+	router := mux.NewRouter()
+	router.Use(FieldSelection)
+	router.HandleFunc("/goscheduler/events", events).Name(constants.StreamEvents)
+
+	req := httptest.NewRequest("GET", "/goscheduler/events?fields=scheduleId", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if !rr.Flushed || !strings.Contains(rr.Body.String(), "large payload") {
+		t.Errorf("expected the event stream to be written as is, got %s", rr.Body.String())
+	}
+}
+
+func TestFieldSelection_SingleRecord(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(GetScheduleResponse{
+			Status: Status{StatusCode: 200},
+			Data:   GetScheduleData{Schedule: store.Schedule{ScheduleId: gocql.TimeUUID(), Payload: "large payload", Every: "1h", Status: store.Paused}},
+		})
+	}
+
+	// a single record is not a listing, it is returned as JSON whatever the client accepts
+	req := httptest.NewRequest("GET", "/goscheduler/schedules/id?fields=every,nextFireTimes", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	rr := httptest.NewRecorder()
+	FieldSelection(http.HandlerFunc(handler)).ServeHTTP(rr, req)
+
+	var response struct {
+		Data struct {
+			Schedule map[string]interface{} `json:"schedule"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	// a paused schedule has no upcoming fire times
+	if len(response.Data.Schedule) != 1 || response.Data.Schedule["every"] != "1h" {
+		t.Errorf("unexpected schedule %v", response.Data.Schedule)
+	}
+}
+
+func TestFieldSelection_KeepsOtherResponses(t *testing.T) {
+	failing := func(w http.ResponseWriter, r *http.Request) {
+		er.Handle(w, r, er.NewError(er.DataNotFound, gocql.ErrNotFound))
+	}
+
+	for _, test := range []struct {
+		method  string
+		handler http.HandlerFunc
+		status  int
+	}{
+		{"GET", failing, http.StatusNotFound},
+		{"POST", listingHandler, http.StatusOK},
+	} {
+		req := httptest.NewRequest(test.method, "/goscheduler/apps/test/schedules?fields=scheduleId", nil)
+		expected := httptest.NewRecorder()
+		test.handler(expected, req)
+		rr := httptest.NewRecorder()
+		FieldSelection(test.handler).ServeHTTP(rr, req)
+
+		if rr.Code != test.status || (test.method == "GET" && rr.Body.String() != expected.Body.String()) {
+			t.Errorf("%s: expected the response as is, got %d %s", test.method, rr.Code, rr.Body.String())
+		}
+		if test.method == "POST" && !strings.Contains(rr.Body.String(), "large payload") {
+			t.Errorf("expected the fields of a POST not to be selected, got %s", rr.Body.String())
+		}
+	}
+}
@@ -23,10 +23,12 @@ import (
 	"encoding/json"
 	"github.com/gocql/gocql"
 	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/archive"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
 	sch "github.com/myntra/goscheduler/store"
 	"net/http"
+	"time"
 )
 
 func (s *Service) Get(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +44,10 @@ func (s *Service) Get(w http.ResponseWriter, r *http.Request) {
 
 	s.recordRequestStatus(constants.GetSchedule, constants.Success)
 
+	schedules := []sch.Schedule{schedule}
+	s.deriveHealth(schedules, time.Now())
+	schedule = schedules[0]
+
 	status := Status{
 		StatusCode:    constants.SuccessCode200,
 		StatusMessage: constants.Success,
@@ -64,7 +70,23 @@ func (s *Service) GetSchedule(uuid string) (sch.Schedule, error) {
 		return sch.Schedule{}, er.NewError(er.InvalidDataCode, err)
 	}
 
-	switch schedule, err := s.ScheduleDao.GetEnrichedSchedule(scheduleId); err {
+	value, err := s.hedgedRead(scheduleRead, scheduleId.String(), func() (interface{}, error) {
+		return s.ScheduleDao.GetEnrichedSchedule(scheduleId)
+	})
+	switch schedule, _ := value.(sch.Schedule); err {
+	case gocql.ErrNotFound:
+		return s.getArchivedSchedule(scheduleId)
+	case nil:
+		schedule.Describe()
+		return schedule, nil
+	default:
+		return sch.Schedule{}, er.NewError(er.DataFetchFailure, err)
+	}
+}
+
+// getArchivedSchedule looks up a schedule no longer in Cassandra in the archive
+func (s *Service) getArchivedSchedule(scheduleId gocql.UUID) (sch.Schedule, error) {
+	switch schedule, err := archive.Default().Lookup(scheduleId); err {
 	case gocql.ErrNotFound:
 		return sch.Schedule{}, er.NewError(er.DataNotFound, err)
 	case nil:
@@ -21,15 +21,14 @@ package service
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/myntra/goscheduler/constants"
 	"github.com/myntra/goscheduler/dao"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	sch "github.com/myntra/goscheduler/store"
 	"net/http"
 	"strconv"
@@ -62,15 +61,15 @@ func parse(r *http.Request) (int64, sch.Status, dao.Range, []byte, time.Time, er
 	status = sch.Status(query.Get("status"))
 	continuationStartTime, _ = strconv.ParseInt(query.Get("continuation_start_time"), 10, 64)
 
-	glog.Infof("continuationStartTime value: %+v", continuationStartTime)
+	logger.Infof("continuationStartTime value: %+v", continuationStartTime)
 
 	if continuationToken != "" {
-		glog.Infof("pageState received: %s", continuationToken)
+		logger.Infof("pageState received: %s", continuationToken)
 		pageState, err = hex.DecodeString(continuationToken)
 		if err != nil {
 			return size, status, timeRange, nil, time.Unix(continuationStartTime, 0), errors.New(fmt.Sprintf("Invalid page token: %s", continuationToken))
 		}
-		glog.Infof("pageState decoded: %+v", pageState)
+		logger.Infof("pageState decoded: %+v", pageState)
 	}
 
 	if len(sizeParam) == 0 {
@@ -129,7 +128,7 @@ func parseDate(date string) (time.Time, error) {
 		return parsedDate, err
 	}
 
-	glog.Infof("Date parsed:- %s", parsedDate)
+	logger.Infof("Date parsed:- %s", parsedDate)
 	temp := time.Unix((parsedDate.Unix()/60)*60, 0)
 	return temp, nil
 }
@@ -160,6 +159,7 @@ func parseDates(startTime string, endTime string) (dao.Range, error) {
 
 // get all the schedules of an app based on time range and status
 func (s *Service) GetAppSchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 	var errs []string
 
 	vars := mux.Vars(r)
@@ -195,13 +195,13 @@ func (s *Service) GetAppSchedules(w http.ResponseWriter, r *http.Request) {
 		data := GetPaginatedAppSchedulesData{
 			Schedules: schedules,
 			ContinuationToken: func() string {
-				glog.Infof("pageState string: %+v", pageState)
+				log.Infof("pageState string: %+v", pageState)
 				return hex.EncodeToString(pageState)
 			}(),
 			ContinuationStartTime: continuationStartTime.Unix(),
 		}
 
-		_ = json.NewEncoder(w).Encode(
+		_ = writeListing(w, r,
 			GetPaginatedAppSchedulesResponse{
 				Status: status,
 				Data:   data,
@@ -224,7 +224,7 @@ func (s *Service) FetchAppSchedules(appId string, timeRange dao.Range, size int6
 }
 
 func (s *Service) getActiveOrInactiveApp(appId string) (sch.App, error) {
-	app, err := s.ClusterDao.GetApp(appId)
+	app, err := s.readApp(appId)
 	switch {
 	case err == gocql.ErrNotFound:
 		return sch.App{}, er.NewError(er.InvalidAppId, errors.New(fmt.Sprintf("app id %s is not registered", appId)))
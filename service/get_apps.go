@@ -20,7 +20,6 @@
 package service
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/myntra/goscheduler/constants"
@@ -54,16 +53,28 @@ func (s *Service) GetApps(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := GetAppsData{
-		Apps: apps,
+		Apps: s.withLimits(apps),
 	}
 
-	_ = json.NewEncoder(w).Encode(
+	_ = writeListing(w, r,
 		GetAppsResponse{
 			Status: status,
 			Data:   data,
 		})
 }
 
+// withLimits adds to the apps the limits in force for their schedules
+func (s *Service) withLimits(apps []store.App) []AppWithLimits {
+	out := make([]AppWithLimits, 0, len(apps))
+	for _, app := range apps {
+		out = append(out, AppWithLimits{
+			App:    app,
+			Limits: AppLimits{MaxPayloadSize: app.MaxPayloadSize(s.Config.AppLevelConfiguration.PayloadSize)},
+		})
+	}
+	return out
+}
+
 func (s *Service) FetchApps(appId string) ([]store.App, error) {
 	switch apps, err := s.ClusterDao.GetApps(appId); {
 	case err != nil:
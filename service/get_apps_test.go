@@ -20,8 +20,10 @@
 package service
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -67,3 +69,30 @@ func TestService_GetApps(t *testing.T) {
 		}
 	}
 }
+
+func TestService_GetAppsLimits(t *testing.T) {
+	service := setupMocks()
+
+	req := httptest.NewRequest(http.MethodGet, "/goscheduler/apps", nil)
+	rr := httptest.NewRecorder()
+	service.GetApps(rr, req)
+
+	var resp GetAppsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the limit of the cluster applies to the apps without a payload size of their own
+	expected := map[string]int{"test1": 1024, "test2": 2048}
+	if len(resp.Data.Apps) != len(expected) {
+		t.Fatalf("Expected %d apps, got %d", len(expected), len(resp.Data.Apps))
+	}
+	for _, app := range resp.Data.Apps {
+		if app.Limits.MaxPayloadSize != expected[app.AppId] {
+			t.Errorf("Expected max payload size %d for %s, got %d", expected[app.AppId], app.AppId, app.Limits.MaxPayloadSize)
+		}
+	}
+	if !strings.Contains(rr.Body.String(), `"appId":"test1"`) || !strings.Contains(rr.Body.String(), `"limits":{"maxPayloadSize":1024}`) {
+		t.Errorf("Expected the limits alongside the fields of the app, got %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// GetAttempts returns the execution logs of the callback attempts of a schedule or a run of a recurring schedule,
+// with their bodies redacted by the rules of the app.
+func (s *Service) GetAttempts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleId := vars["scheduleId"]
+
+	attempts, err := s.FetchCallbackAttempts(scheduleId)
+	if err != nil {
+		s.recordRequestStatus(constants.GetScheduleAttempts, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetScheduleAttempts, attempts[0].AppId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(attempts),
+	}
+	_ = writeListing(w, r,
+		GetCallbackAttemptsResponse{
+			Status: status,
+			Data: GetCallbackAttemptsData{
+				Attempts: attempts,
+			},
+		})
+}
+
+func (s *Service) FetchCallbackAttempts(uuid string) ([]sch.CallbackAttempt, error) {
+	scheduleId, err := gocql.ParseUUID(uuid)
+	if err != nil {
+		return nil, er.NewError(er.InvalidDataCode, err)
+	}
+
+	switch attempts, err := s.ScheduleDao.GetCallbackAttempts(scheduleId); {
+	case err != nil:
+		return nil, er.NewError(er.DataFetchFailure, err)
+	case len(attempts) == 0:
+		return nil, er.NewError(er.DataNotFound, errors.New(fmt.Sprintf("No callback attempts found for schedule %s", uuid)))
+	default:
+		return attempts, nil
+	}
+}
@@ -18,7 +18,7 @@ func (s *Service) GetConfiguration(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	appId := vars["app_id"]
 
-	app, err := s.ClusterDao.GetApp(appId)
+	app, err := s.readApp(appId)
 
 	switch {
 	case err == gocql.ErrNotFound:
@@ -20,17 +20,22 @@
 package service
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	sch "github.com/myntra/goscheduler/store"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
-func parseCron(r *http.Request) (string, sch.Status, error) {
+// healthRoutines bounds the concurrent reads of the recent runs when deriving the health of the listed schedules
+const healthRoutines = 16
+
+func parseCron(r *http.Request) (string, sch.Status, sch.Health, error) {
 	var appId string
 	var status sch.Status
 
@@ -38,13 +43,23 @@ func parseCron(r *http.Request) (string, sch.Status, error) {
 	appId = query.Get("app_id")
 	status = sch.Status(query.Get("status"))
 
-	return appId, status, nil
+	health, err := sch.ParseHealth(query.Get("health"))
+	if err != nil {
+		return appId, status, "", er.NewError(er.InvalidDataCode, err)
+	}
+
+	return appId, status, health, nil
 }
 
 func (s *Service) GetCronSchedules(w http.ResponseWriter, r *http.Request) {
-	appId, status, _ := parseCron(r)
+	appId, status, health, err := parseCron(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetCronSchedule, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
 
-	cronSchedules, err := s.FetchCronSchedules(appId, status)
+	cronSchedules, err := s.FetchCronSchedules(appId, status, health)
 	if err != nil {
 		s.recordRequestAppStatus(constants.GetCronSchedule, appId, constants.Fail)
 		er.Handle(w, r, err.(er.AppError))
@@ -52,7 +67,7 @@ func (s *Service) GetCronSchedules(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.recordRequestAppStatus(constants.GetCronSchedule, appId, constants.Success)
-	_ = json.NewEncoder(w).Encode(
+	_ = writeListing(w, r,
 		GetCronSchedulesResponse{
 			Status: Status{
 				StatusCode:    constants.SuccessCode200,
@@ -63,13 +78,59 @@ func (s *Service) GetCronSchedules(w http.ResponseWriter, r *http.Request) {
 		})
 }
 
-func (s *Service) FetchCronSchedules(appId string, status sch.Status) ([]sch.Schedule, error) {
+// FetchCronSchedules returns the recurring schedules of an app with their derived health, only those of the given
+// health if any
+func (s *Service) FetchCronSchedules(appId string, status sch.Status, health sch.Health) ([]sch.Schedule, error) {
 	switch cronSchedules, errs := (s.ScheduleDao).GetCronSchedulesByApp(appId, status); {
 	case len(errs) != 0:
 		return []sch.Schedule{}, er.NewError(er.DataFetchFailure, errors.New(strings.Join(errs, ",")))
 	case len(cronSchedules) == 0:
 		return []sch.Schedule{}, er.NewError(er.DataNotFound, errors.New(fmt.Sprint("No cron schedules found")))
 	default:
-		return cronSchedules, nil
+		for i := range cronSchedules {
+			cronSchedules[i].Describe()
+		}
+		s.deriveHealth(cronSchedules, time.Now())
+
+		if health == "" {
+			return cronSchedules, nil
+		}
+		filtered := make([]sch.Schedule, 0, len(cronSchedules))
+		for _, schedule := range cronSchedules {
+			if schedule.Health == health {
+				filtered = append(filtered, schedule)
+			}
+		}
+		if len(filtered) == 0 {
+			return []sch.Schedule{}, er.NewError(er.DataNotFound, fmt.Errorf("No %s cron schedules found", strings.ToLower(string(health))))
+		}
+		return filtered, nil
+	}
+}
+
+// deriveHealth sets the health of the active recurring schedules from their recent runs, leaving it empty for the
+// schedules whose runs cannot be read
+func (s *Service) deriveHealth(schedules []sch.Schedule, now time.Time) {
+	slots := make(chan struct{}, healthRoutines)
+	var wg sync.WaitGroup
+	for i := range schedules {
+		if !schedules[i].IsRecurring() || schedules[i].Status != sch.Scheduled {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(schedule *sch.Schedule) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			runs, _, err := s.ScheduleDao.GetScheduleRuns(schedule.ScheduleId, sch.HealthRuns, "past", nil)
+			if err != nil {
+				logger.Errorf("Error getting the recent runs of schedule %s: %+v", schedule.ScheduleId, err)
+				return
+			}
+			schedule.Health = sch.DeriveHealth(runs, now)
+		}(&schedules[i])
 	}
+	wg.Wait()
 }
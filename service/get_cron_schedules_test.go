@@ -20,11 +20,49 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+var (
+	healthyCronId = gocql.UUID{1}
+	failingCronId = gocql.UUID{2}
+	brokenCronId  = gocql.UUID{3}
 )
 
+type MockScheduleDaoForHealth struct {
+	dao.DummyScheduleDaoImpl
+}
+
+func (m *MockScheduleDaoForHealth) GetCronSchedulesByApp(appId string, status store.Status) ([]store.Schedule, []string) {
+	return []store.Schedule{
+		{ScheduleId: healthyCronId, AppId: appId, CronExpression: "* * * * *", Status: store.Scheduled},
+		{ScheduleId: failingCronId, AppId: appId, CronExpression: "* * * * *", Status: store.Scheduled},
+		{ScheduleId: brokenCronId, AppId: appId, CronExpression: "* * * * *", Status: store.Scheduled},
+		{ScheduleId: gocql.UUID{4}, AppId: appId, CronExpression: "* * * * *", Status: store.Deleted},
+	}, nil
+}
+
+func (m *MockScheduleDaoForHealth) GetScheduleRuns(uuid gocql.UUID, size int64, when string, pageState []byte) ([]store.Schedule, []byte, error) {
+	past := time.Now().Add(-30 * time.Second).Unix()
+	switch uuid {
+	case failingCronId:
+		return []store.Schedule{{ScheduleTime: past, Status: store.Failure}}, nil, nil
+	case brokenCronId:
+		return nil, nil, errors.New("error")
+	default:
+		return []store.Schedule{{ScheduleTime: past, Status: store.Success}}, nil, nil
+	}
+}
+
 func TestService_GetCronSchedules(t *testing.T) {
 	service := setupMocks()
 
@@ -64,3 +102,48 @@ func TestService_GetCronSchedules(t *testing.T) {
 		}
 	}
 }
+
+func TestService_GetCronSchedulesHealth(t *testing.T) {
+	service := setupMocks()
+	service.ScheduleDao = &MockScheduleDaoForHealth{}
+
+	for _, test := range []struct {
+		name   string
+		health string
+		status int
+		want   map[gocql.UUID]store.Health
+	}{
+		{"NoFilter", "", http.StatusOK, map[gocql.UUID]store.Health{
+			healthyCronId: store.Healthy, failingCronId: store.Failing, brokenCronId: "", {4}: "",
+		}},
+		{"Failing", "failing", http.StatusOK, map[gocql.UUID]store.Health{failingCronId: store.Failing}},
+		{"NoneDegraded", "degraded", http.StatusNotFound, nil},
+		{"InvalidHealth", "sick", http.StatusBadRequest, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/goscheduler/crons/schedules?app_id=test&health="+test.health, nil)
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.GetCronSchedules).ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, test.status)
+			}
+			if test.want == nil {
+				return
+			}
+
+			var response GetCronSchedulesResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Data) != len(test.want) {
+				t.Fatalf("got %d schedules, want %d", len(response.Data), len(test.want))
+			}
+			for _, schedule := range response.Data {
+				if want, ok := test.want[schedule.ScheduleId]; !ok || schedule.Health != want {
+					t.Errorf("schedule %s has health %q, want %q", schedule.ScheduleId, schedule.Health, want)
+				}
+			}
+		})
+	}
+}
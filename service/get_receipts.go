@@ -0,0 +1,77 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// GetReceipts returns the signed delivery receipts recorded for a schedule or a run of a recurring schedule.
+func (s *Service) GetReceipts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleId := vars["scheduleId"]
+
+	receipts, err := s.FetchDeliveryReceipts(scheduleId)
+	if err != nil {
+		s.recordRequestStatus(constants.GetScheduleReceipts, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetScheduleReceipts, receipts[0].AppId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(receipts),
+	}
+	_ = writeListing(w, r,
+		GetDeliveryReceiptsResponse{
+			Status: status,
+			Data: GetDeliveryReceiptsData{
+				Receipts: receipts,
+			},
+		})
+}
+
+func (s *Service) FetchDeliveryReceipts(uuid string) ([]sch.DeliveryReceipt, error) {
+	scheduleId, err := gocql.ParseUUID(uuid)
+	if err != nil {
+		return nil, er.NewError(er.InvalidDataCode, err)
+	}
+
+	switch receipts, err := s.ScheduleDao.GetDeliveryReceipts(scheduleId); {
+	case err != nil:
+		return nil, er.NewError(er.DataFetchFailure, err)
+	case len(receipts) == 0:
+		return nil, er.NewError(er.DataNotFound, errors.New(fmt.Sprintf("No delivery receipts found for schedule %s", uuid)))
+	default:
+		return receipts, nil
+	}
+}
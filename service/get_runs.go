@@ -21,14 +21,14 @@ package service
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/archive"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	sch "github.com/myntra/goscheduler/store"
 	"net/http"
 	"strconv"
@@ -46,19 +46,19 @@ func parseQueryParams(r *http.Request) (int64, string, []byte, error) {
 		size = 15
 	} else {
 		if size, err = strconv.ParseInt(sizeParam, 10, 64); err != nil {
-			glog.Errorf("Cannot parse size %s to int", sizeParam)
+			logger.Errorf("Cannot parse size %s to int", sizeParam)
 		}
 	}
 
 	continuationToken = r.URL.Query().Get("continuation_token")
 	when = r.URL.Query().Get("when")
 	if continuationToken != "" {
-		glog.Infof("pageState received: %s", continuationToken)
+		logger.Infof("pageState received: %s", continuationToken)
 		pageState, err = hex.DecodeString(continuationToken)
 		if err != nil {
 			return size, when, nil, errors.New(fmt.Sprintf("Invalid page token: %s", continuationToken))
 		}
-		glog.Infof("pageState decoded: %+v", pageState)
+		logger.Infof("pageState decoded: %+v", pageState)
 	}
 	return size, when, pageState, err
 }
@@ -73,6 +73,7 @@ func (s *Service) recordGetRunsSuccess(schedules []sch.Schedule) {
 }
 
 func (s *Service) GetRuns(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 	vars := mux.Vars(r)
 	scheduleId := vars["scheduleId"]
 
@@ -101,12 +102,12 @@ func (s *Service) GetRuns(w http.ResponseWriter, r *http.Request) {
 	data := GetPaginatedRunSchedulesData{
 		Schedules: schedules,
 		ContinuationToken: func() string {
-			glog.Infof("pageState string: %+v", pageState)
+			log.Infof("pageState string: %+v", pageState)
 			return hex.EncodeToString(pageState)
 		}(),
 	}
 
-	_ = json.NewEncoder(w).Encode(
+	_ = writeListing(w, r,
 		GetPaginatedRunSchedulesResponse{
 			Status: status,
 			Data:   data,
@@ -122,9 +123,24 @@ func (s *Service) FetchCronRuns(uuid string, size int64, when string, pageState
 	switch schedules, pageState, err := (s.ScheduleDao).GetScheduleRuns(scheduleId, size, when, pageState); {
 	case err != nil:
 		return []sch.Schedule{}, nil, er.NewError(er.DataFetchFailure, err)
+	case len(schedules) == 0 && len(pageState) == 0:
+		return s.fetchArchivedRuns(scheduleId, size)
 	case len(schedules) == 0:
 		return []sch.Schedule{}, nil, er.NewError(er.DataNotFound, errors.New(fmt.Sprint("No cron runs found")))
 	default:
 		return schedules, pageState, nil
 	}
 }
+
+// fetchArchivedRuns looks up the latest runs of a recurring schedule no longer in Cassandra in the archive.
+// The archived runs are not paginated.
+func (s *Service) fetchArchivedRuns(scheduleId gocql.UUID, size int64) ([]sch.Schedule, []byte, error) {
+	switch schedules, err := archive.Default().LookupRuns(scheduleId, int(size)); {
+	case err != nil:
+		return []sch.Schedule{}, nil, er.NewError(er.DataFetchFailure, err)
+	case len(schedules) == 0:
+		return []sch.Schedule{}, nil, er.NewError(er.DataNotFound, errors.New(fmt.Sprint("No cron runs found")))
+	default:
+		return schedules, nil, nil
+	}
+}
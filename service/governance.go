@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// governanceClient posts the schedules to the governance service, the timeout is set per request
+var governanceClient = &http.Client{}
+
+// maxGovernanceResponseSize bounds the answer of the governance service read
+const maxGovernanceResponseSize = 1 << 20
+
+// govern submits a schedule to the governance service before its creation, and returns the schedule to create. A
+// rejected schedule fails with a Forbidden error. When the governance service fails, the schedule is created as
+// requested with the open failure policy, and fails with a GovernanceUnavailable error with the closed one.
+func (s *Service) govern(app sch.App, input sch.Schedule) (sch.Schedule, error) {
+	config := s.Config.GovernanceConfig
+	if config.Url == "" {
+		return input, nil
+	}
+
+	timeout := time.Duration(config.TimeoutMillis) * time.Millisecond
+	policy := sch.GovernanceFailurePolicy(config.FailurePolicy)
+	if governance := app.Configuration.Governance; governance != nil {
+		if governance.TimeoutMillis > 0 {
+			timeout = time.Duration(governance.TimeoutMillis) * time.Millisecond
+		}
+		if governance.FailurePolicy != "" {
+			policy = governance.FailurePolicy
+		}
+	}
+
+	response, err := s.callGovernance(config.Url, config.Headers, timeout, input)
+	if err != nil {
+		s.recordGovernance(input.AppId, "error")
+		if policy == sch.FailClosed {
+			return sch.Schedule{}, er.NewError(er.GovernanceUnavailable, fmt.Errorf("governance service failed: %w", err))
+		}
+		logger.Warningf("Creating schedule of app %s without governance, the governance service failed: %s", input.AppId, err.Error())
+		return input, nil
+	}
+
+	s.recordGovernance(input.AppId, string(response.Decision))
+	switch response.Decision {
+	case sch.RejectSchedule:
+		return sch.Schedule{}, er.NewError(er.Forbidden, fmt.Errorf("schedule rejected by governance: %s", response.Reason))
+	case sch.MutateSchedule:
+		mutated := *response.Schedule
+		mutated.RequestId = input.RequestId
+		return mutated, nil
+	default:
+		return input, nil
+	}
+}
+
+// callGovernance posts the schedule to the governance service and returns its valid answer
+func (s *Service) callGovernance(url string, headers map[string]string, timeout time.Duration, input sch.Schedule) (sch.GovernanceResponse, error) {
+	body, err := json.Marshal(sch.GovernanceRequest{AppId: input.AppId, Schedule: input})
+	if err != nil {
+		return sch.GovernanceResponse{}, err
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return sch.GovernanceResponse{}, err
+	}
+	request.Header.Set(constants.ContentType, constants.ApplicationJson)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	resp, err := governanceClient.Do(request)
+	if err != nil {
+		return sch.GovernanceResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return sch.GovernanceResponse{}, errors.New(fmt.Sprintf("governance service responded with status %d", resp.StatusCode))
+	}
+
+	b, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, maxGovernanceResponseSize))
+	if err != nil {
+		return sch.GovernanceResponse{}, err
+	}
+
+	var response sch.GovernanceResponse
+	if err = json.Unmarshal(b, &response); err != nil {
+		return sch.GovernanceResponse{}, err
+	}
+	if err = response.Validate(input.AppId); err != nil {
+		return sch.GovernanceResponse{}, err
+	}
+	return response, nil
+}
+
+func (s *Service) recordGovernance(appId, decision string) {
+	if s.Monitor != nil {
+		s.Monitor.IncCounter(constants.GovernanceDecisionCount, map[string]string{"appId": appId, "decision": decision}, 1)
+	}
+}
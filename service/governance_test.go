@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+func governanceServer(t *testing.T, answer func(request map[string]interface{}) (int, interface{})) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("expected a json body, got %v", err)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected the configured headers, got %v", r.Header)
+		}
+		status, body := answer(request)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+}
+
+func TestService_PostGovernance(t *testing.T) {
+	body := []byte(fmt.Sprintf(`{"appId": "test", "callback": {"type": "http", "details": {"url": "http://localhost/callback", "method": "POST"}}, "scheduleTime": %d, "payload": "{}"}`, time.Now().Add(time.Hour).Unix()))
+
+	for _, test := range []struct {
+		name    string
+		policy  string
+		status  int
+		answer  func(request map[string]interface{}) (int, interface{})
+		code    string
+		payload string
+	}{
+		{
+			name:   "approved",
+			status: http.StatusOK,
+			answer: func(map[string]interface{}) (int, interface{}) {
+				return http.StatusOK, sch.GovernanceResponse{Decision: sch.ApproveSchedule}
+			},
+			payload: "{}",
+		},
+		{
+			name:   "rejected",
+			status: http.StatusForbidden,
+			answer: func(map[string]interface{}) (int, interface{}) {
+				return http.StatusOK, sch.GovernanceResponse{Decision: sch.RejectSchedule, Reason: "external urls are banned"}
+			},
+			code: "FORBIDDEN",
+		},
+		{
+			name:   "mutated",
+			status: http.StatusOK,
+			answer: func(request map[string]interface{}) (int, interface{}) {
+				schedule := request["schedule"].(map[string]interface{})
+				schedule["payload"] = `{"owner": "payments"}`
+				return http.StatusOK, map[string]interface{}{"decision": "mutate", "schedule": schedule}
+			},
+			payload: `{"owner": "payments"}`,
+		},
+		{
+			name:   "mutated into another app",
+			policy: "closed",
+			status: http.StatusServiceUnavailable,
+			answer: func(request map[string]interface{}) (int, interface{}) {
+				schedule := request["schedule"].(map[string]interface{})
+				schedule["appId"] = "other"
+				return http.StatusOK, map[string]interface{}{"decision": "mutate", "schedule": schedule}
+			},
+			code: "GOVERNANCE_UNAVAILABLE",
+		},
+		{
+			name:    "failing open",
+			policy:  "open",
+			status:  http.StatusOK,
+			answer:  func(map[string]interface{}) (int, interface{}) { return http.StatusInternalServerError, nil },
+			payload: "{}",
+		},
+		{
+			name:   "failing closed",
+			policy: "closed",
+			status: http.StatusServiceUnavailable,
+			answer: func(map[string]interface{}) (int, interface{}) { return http.StatusInternalServerError, nil },
+			code:   "GOVERNANCE_UNAVAILABLE",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server := governanceServer(t, test.answer)
+			defer server.Close()
+
+			service := setupMocks()
+			service.Config.GovernanceConfig.Url = server.URL
+			service.Config.GovernanceConfig.Headers = map[string]string{"Authorization": "Bearer token"}
+			service.Config.GovernanceConfig.TimeoutMillis = 1000
+			service.Config.GovernanceConfig.FailurePolicy = test.policy
+
+			rr := httptest.NewRecorder()
+			service.Post(rr, httptest.NewRequest(http.MethodPost, "/goscheduler/schedules", bytes.NewReader(body)))
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v, %s", rr.Code, test.status, rr.Body.String())
+			}
+			if test.code != "" {
+				var response er.ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Error.Code != test.code {
+					t.Errorf("expected the code %s, got %+v", test.code, response.Error)
+				}
+				return
+			}
+
+			var response CreateScheduleResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Data.Schedule.Payload != test.payload {
+				t.Errorf("expected the payload %s, got %s", test.payload, response.Data.Schedule.Payload)
+			}
+		})
+	}
+}
+
+func TestService_GovernanceTimeoutOfApp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(sch.GovernanceResponse{Decision: sch.ApproveSchedule})
+	}))
+	defer server.Close()
+
+	service := setupMocks()
+	service.Config.GovernanceConfig.Url = server.URL
+	service.Config.GovernanceConfig.TimeoutMillis = 5000
+	app := sch.App{AppId: "test", Configuration: sch.Configuration{
+		Governance: &sch.Governance{TimeoutMillis: 20, FailurePolicy: sch.FailClosed},
+	}}
+
+	_, err := service.govern(app, sch.Schedule{AppId: "test"})
+	if appErr, ok := err.(er.AppError); !ok || appErr.Code != er.GovernanceUnavailable {
+		t.Errorf("expected the timeout of the app to fail the creation, got %v", err)
+	}
+}
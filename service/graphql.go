@@ -0,0 +1,620 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// defaultGraphQLRuns is the number of latest runs of a recurring schedule a query selects by default
+const defaultGraphQLRuns = 5
+
+// graphQLSchema is the schema of the GraphQL queries in the schema definition language
+const graphQLSchema = `"Any JSON value"
+scalar JSON
+
+type Query {
+  "A schedule or run by id, read from the archive once archived"
+  schedule(id: ID!): Schedule
+  app(id: ID!): App
+  apps: [App!]
+  "Schedules of an app in a time range"
+  schedules(appId: ID!, "Start of the time range as ` + dateTimeLayout + `" from: String!, "End of the time range as ` + dateTimeLayout + `" to: String!, status: String, size: Int = 20): [Schedule!]
+  "Runs of a recurring schedule"
+  runs(scheduleId: ID!, "Number of runs, latest first" last: Int = 5, "past or future, every run by default" when: String): [Schedule!]
+}
+
+"An app registered with the scheduler"
+type App {
+  appId: ID!
+  partitions: Int
+  active: Boolean
+  configuration: JSON
+  limits: AppLimits
+  schedules("Start of the time range as ` + dateTimeLayout + `" from: String!, "End of the time range as ` + dateTimeLayout + `" to: String!, status: String, size: Int = 20): [Schedule!]
+}
+
+type AppLimits {
+  "Largest payload in bytes of the schedules of the app"
+  maxPayloadSize: Int
+}
+
+"An attempt of the callback of a schedule or run, with its bodies redacted"
+type CallbackAttempt {
+  attempt: Int
+  "Epoch millis the attempt started at"
+  attemptedAt: Float
+  method: String
+  url: String
+  requestSnippet: String
+  responseStatus: Int
+  responseSnippet: String
+  error: String
+  latencyMillis: Int
+}
+
+"A one time or recurring schedule, or a run of a recurring schedule"
+type Schedule {
+  scheduleId: ID!
+  appId: String
+  partitionId: Int
+  payload: String
+  "Epoch seconds the schedule fires at"
+  scheduleTime: Int
+  callback: JSON
+  cronExpression: String
+  every: String
+  rrule: String
+  "Human readable description of the recurrence"
+  description: String
+  statusCallback: String
+  priority: String
+  region: String
+  traceId: String
+  pausePolicy: String
+  pausedAt: Int
+  status: String
+  errorMessage: String
+  responseSnippet: String
+  reconciliationHistory: JSON
+  archived: Boolean
+  "Recurring schedule of a run"
+  parentScheduleId: ID
+  app: App
+  "Runs of a recurring schedule"
+  runs("Number of runs, latest first" last: Int = 5, "past or future, every run by default" when: String): [Schedule!]
+  "Definitions of a recurring schedule, oldest first"
+  versions: [ScheduleVersion!]
+  attempts: [CallbackAttempt!]
+}
+
+"A definition of a recurring schedule, the audit trail of its updates"
+type ScheduleVersion {
+  version: Int
+  "Epoch millis of the update which replaced the definition"
+  replacedAt: Float
+  "Correlation id of the update which replaced the definition"
+  requestId: String
+  current: Boolean
+  definition: JSON
+}
+`
+
+// graphQLRequest is a query posted by a client
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQL executes a GraphQL query over the schedules, their runs, versions and callback attempts and the apps, so
+// that a client fetches the nested data it needs in a single request. Fields which fail are null with an error next
+// to the data, and a query which cannot be executed at all is rejected with a 400.
+func (s *Service) GraphQL(w http.ResponseWriter, r *http.Request) {
+	var request graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.recordRequestStatus(constants.GraphQLQuery, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+	if request.Query == "" {
+		s.recordRequestStatus(constants.GraphQLQuery, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, errors.New("query is required")))
+		return
+	}
+
+	schema := graphql.MustParseSchema(graphQLSchema, &graphQLResolver{s: s},
+		graphql.UseStringDescriptions(), graphql.MaxDepth(s.Config.GraphQLConfig.MaxDepth))
+	response := schema.Exec(r.Context(), request.Query, request.OperationName, request.Variables)
+
+	status := constants.Success
+	if len(response.Errors) > 0 {
+		status = constants.Fail
+		logger.FromContext(r.Context()).Errorf("GraphQL query %s failed with errors %+v", request.OperationName, response.Errors)
+	}
+	s.recordRequestStatus(constants.GraphQLQuery, status)
+
+	w.Header().Set(constants.ContentType, constants.ApplicationJson)
+	if response.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// GetGraphQLSchema returns the schema the GraphQL queries are executed against in the schema definition language
+func (s *Service) GetGraphQLSchema(w http.ResponseWriter, r *http.Request) {
+	s.recordRequestStatus(constants.GetGraphQLSchema, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(GraphQLSchemaResponse{Status: status, Data: GraphQLSchemaData{Schema: graphQLSchema}})
+}
+
+// graphQLJSON is the JSON scalar, a value served as its JSON representation
+type graphQLJSON struct {
+	value interface{}
+}
+
+func (graphQLJSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+func (j *graphQLJSON) UnmarshalGraphQL(input interface{}) error {
+	j.value = input
+	return nil
+}
+
+func (j graphQLJSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.value)
+}
+
+// graphQLResolver resolves the fields of the query
+type graphQLResolver struct {
+	s *Service
+}
+
+type graphQLRunsArgs struct {
+	Last int32
+	When *string
+}
+
+type graphQLSchedulesArgs struct {
+	From   string
+	To     string
+	Status *string
+	Size   int32
+}
+
+func (q *graphQLResolver) Schedule(args struct{ Id graphql.ID }) (*graphQLSchedule, error) {
+	schedule, err := q.s.GetSchedule(string(args.Id))
+	switch {
+	case isNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return &graphQLSchedule{s: q.s, schedule: schedule}, nil
+}
+
+func (q *graphQLResolver) App(args struct{ Id graphql.ID }) (*graphQLApp, error) {
+	return q.s.resolveApp(string(args.Id))
+}
+
+func (q *graphQLResolver) Apps() (*[]*graphQLApp, error) {
+	apps, err := q.s.FetchApps("")
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolved := make([]*graphQLApp, 0, len(apps))
+	for _, app := range apps {
+		resolved = append(resolved, &graphQLApp{s: q.s, app: app})
+	}
+	return &resolved, nil
+}
+
+func (q *graphQLResolver) Schedules(args struct {
+	AppId graphql.ID
+	graphQLSchedulesArgs
+}) (*[]*graphQLSchedule, error) {
+	return q.s.resolveAppSchedules(string(args.AppId), args.graphQLSchedulesArgs)
+}
+
+func (q *graphQLResolver) Runs(args struct {
+	ScheduleId graphql.ID
+	graphQLRunsArgs
+}) (*[]*graphQLSchedule, error) {
+	return q.s.resolveRuns(string(args.ScheduleId), args.graphQLRunsArgs)
+}
+
+// graphQLApp resolves the fields of an app
+type graphQLApp struct {
+	s   *Service
+	app store.App
+}
+
+func (a *graphQLApp) AppId() graphql.ID {
+	return graphql.ID(a.app.AppId)
+}
+
+func (a *graphQLApp) Partitions() *int32 {
+	return intValue(int64(a.app.Partitions))
+}
+
+func (a *graphQLApp) Active() *bool {
+	return &a.app.Active
+}
+
+func (a *graphQLApp) Configuration() *graphQLJSON {
+	return &graphQLJSON{value: a.app.Configuration}
+}
+
+func (a *graphQLApp) Limits() *graphQLAppLimits {
+	return &graphQLAppLimits{limits: a.s.withLimits([]store.App{a.app})[0].Limits}
+}
+
+func (a *graphQLApp) Schedules(args graphQLSchedulesArgs) (*[]*graphQLSchedule, error) {
+	return a.s.resolveAppSchedules(a.app.AppId, args)
+}
+
+type graphQLAppLimits struct {
+	limits AppLimits
+}
+
+func (l *graphQLAppLimits) MaxPayloadSize() *int32 {
+	return intValue(int64(l.limits.MaxPayloadSize))
+}
+
+// graphQLSchedule resolves the fields of a schedule or run. The optional fields left out of the JSON the REST API
+// returns for the schedule are null.
+type graphQLSchedule struct {
+	s        *Service
+	schedule store.Schedule
+}
+
+func (r *graphQLSchedule) ScheduleId() graphql.ID {
+	return graphql.ID(r.schedule.ScheduleId.String())
+}
+
+func (r *graphQLSchedule) AppId() *string {
+	return &r.schedule.AppId
+}
+
+func (r *graphQLSchedule) PartitionId() *int32 {
+	return intValue(int64(r.schedule.PartitionId))
+}
+
+func (r *graphQLSchedule) Payload() *string {
+	return &r.schedule.Payload
+}
+
+func (r *graphQLSchedule) ScheduleTime() *int32 {
+	return optionalInt(r.schedule.ScheduleTime)
+}
+
+func (r *graphQLSchedule) CronExpression() *string {
+	return optionalString(r.schedule.CronExpression)
+}
+
+func (r *graphQLSchedule) Every() *string {
+	return optionalString(r.schedule.Every)
+}
+
+func (r *graphQLSchedule) Rrule() *string {
+	return optionalString(r.schedule.RRule)
+}
+
+func (r *graphQLSchedule) StatusCallback() *string {
+	return optionalString(r.schedule.StatusCallback)
+}
+
+func (r *graphQLSchedule) Priority() *string {
+	return optionalString(string(r.schedule.Priority))
+}
+
+func (r *graphQLSchedule) Region() *string {
+	return optionalString(r.schedule.Region)
+}
+
+func (r *graphQLSchedule) TraceId() *string {
+	return optionalString(r.schedule.TraceId)
+}
+
+func (r *graphQLSchedule) PausePolicy() *string {
+	return optionalString(string(r.schedule.PausePolicy))
+}
+
+func (r *graphQLSchedule) PausedAt() *int32 {
+	return optionalInt(r.schedule.PausedAt)
+}
+
+func (r *graphQLSchedule) Status() *string {
+	return optionalString(string(r.schedule.Status))
+}
+
+func (r *graphQLSchedule) ErrorMessage() *string {
+	return optionalString(r.schedule.ErrorMessage)
+}
+
+func (r *graphQLSchedule) ResponseSnippet() *string {
+	return optionalString(r.schedule.ResponseSnippet)
+}
+
+func (r *graphQLSchedule) Callback() (*graphQLJSON, error) {
+	raw := r.schedule.CallbackRaw
+	if r.schedule.Callback != nil {
+		var err error
+		if raw, err = json.Marshal(r.schedule.Callback); err != nil {
+			return nil, err
+		}
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return &graphQLJSON{value: raw}, nil
+}
+
+func (r *graphQLSchedule) Description() *string {
+	schedule := r.schedule
+	schedule.Describe()
+	return optionalString(schedule.Description)
+}
+
+func (r *graphQLSchedule) ReconciliationHistory() *graphQLJSON {
+	if len(r.schedule.ReconciliationHistory) == 0 {
+		return nil
+	}
+	return &graphQLJSON{value: r.schedule.ReconciliationHistory}
+}
+
+func (r *graphQLSchedule) Archived() *bool {
+	if !r.schedule.Archived {
+		return nil
+	}
+	return &r.schedule.Archived
+}
+
+func (r *graphQLSchedule) ParentScheduleId() *graphql.ID {
+	if util.IsZeroUUID(r.schedule.ParentScheduleId) {
+		return nil
+	}
+	id := graphql.ID(r.schedule.ParentScheduleId.String())
+	return &id
+}
+
+func (r *graphQLSchedule) App() (*graphQLApp, error) {
+	return r.s.resolveApp(r.schedule.AppId)
+}
+
+func (r *graphQLSchedule) Runs(args graphQLRunsArgs) (*[]*graphQLSchedule, error) {
+	if !r.schedule.IsRecurring() {
+		return &[]*graphQLSchedule{}, nil
+	}
+	return r.s.resolveRuns(r.schedule.ScheduleId.String(), args)
+}
+
+func (r *graphQLSchedule) Versions() (*[]*graphQLScheduleVersion, error) {
+	resolved := []*graphQLScheduleVersion{}
+	if !r.schedule.IsRecurring() {
+		return &resolved, nil
+	}
+
+	versions, err := r.s.FetchScheduleVersions(r.schedule.ScheduleId.String())
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	for _, version := range versions {
+		resolved = append(resolved, &graphQLScheduleVersion{version: version})
+	}
+	return &resolved, nil
+}
+
+func (r *graphQLSchedule) Attempts() (*[]*graphQLCallbackAttempt, error) {
+	attempts, err := r.s.FetchCallbackAttempts(r.schedule.ScheduleId.String())
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolved := make([]*graphQLCallbackAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		resolved = append(resolved, &graphQLCallbackAttempt{attempt: attempt})
+	}
+	return &resolved, nil
+}
+
+// graphQLScheduleVersion resolves the fields of a definition of a recurring schedule
+type graphQLScheduleVersion struct {
+	version store.ScheduleVersion
+}
+
+func (v *graphQLScheduleVersion) Version() *int32 {
+	return intValue(int64(v.version.Version))
+}
+
+func (v *graphQLScheduleVersion) ReplacedAt() *float64 {
+	return optionalFloat(v.version.ReplacedAt)
+}
+
+func (v *graphQLScheduleVersion) RequestId() *string {
+	return optionalString(v.version.RequestId)
+}
+
+func (v *graphQLScheduleVersion) Current() *bool {
+	return &v.version.Current
+}
+
+func (v *graphQLScheduleVersion) Definition() *graphQLJSON {
+	return &graphQLJSON{value: v.version.Definition}
+}
+
+// graphQLCallbackAttempt resolves the fields of an attempt of a callback
+type graphQLCallbackAttempt struct {
+	attempt store.CallbackAttempt
+}
+
+func (a *graphQLCallbackAttempt) Attempt() *int32 {
+	return intValue(int64(a.attempt.Attempt))
+}
+
+func (a *graphQLCallbackAttempt) AttemptedAt() *float64 {
+	return optionalFloat(a.attempt.AttemptedAt)
+}
+
+func (a *graphQLCallbackAttempt) Method() *string {
+	return &a.attempt.Method
+}
+
+func (a *graphQLCallbackAttempt) Url() *string {
+	return &a.attempt.Url
+}
+
+func (a *graphQLCallbackAttempt) RequestSnippet() *string {
+	return optionalString(a.attempt.RequestSnippet)
+}
+
+func (a *graphQLCallbackAttempt) ResponseStatus() *int32 {
+	return optionalInt(int64(a.attempt.ResponseStatus))
+}
+
+func (a *graphQLCallbackAttempt) ResponseSnippet() *string {
+	return optionalString(a.attempt.ResponseSnippet)
+}
+
+func (a *graphQLCallbackAttempt) Error() *string {
+	return optionalString(a.attempt.Error)
+}
+
+func (a *graphQLCallbackAttempt) LatencyMillis() *int32 {
+	return intValue(a.attempt.LatencyMillis)
+}
+
+// optionalString resolves an empty string, left out of the JSON of the REST API, to null
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func intValue(value int64) *int32 {
+	n := int32(value)
+	return &n
+}
+
+// optionalInt resolves 0, left out of the JSON of the REST API, to null
+func optionalInt(value int64) *int32 {
+	if value == 0 {
+		return nil
+	}
+	return intValue(value)
+}
+
+// optionalFloat resolves 0, left out of the JSON of the REST API, to null
+func optionalFloat(value int64) *float64 {
+	if value == 0 {
+		return nil
+	}
+	f := float64(value)
+	return &f
+}
+
+func (s *Service) resolveApp(appId string) (*graphQLApp, error) {
+	app, err := s.getActiveOrInactiveApp(appId)
+	if err != nil {
+		if appErr, ok := err.(er.AppError); ok && appErr.Code == er.InvalidAppId {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &graphQLApp{s: s, app: app}, nil
+}
+
+func (s *Service) resolveRuns(scheduleId string, args graphQLRunsArgs) (*[]*graphQLSchedule, error) {
+	last, err := s.graphQLListSize(args.Last)
+	if err != nil {
+		return nil, err
+	}
+	var when string
+	if args.When != nil {
+		when = *args.When
+	}
+
+	runs, _, err := s.FetchCronRuns(scheduleId, int64(last), when, nil)
+	return s.graphQLSchedules(runs, err)
+}
+
+func (s *Service) resolveAppSchedules(appId string, args graphQLSchedulesArgs) (*[]*graphQLSchedule, error) {
+	size, err := s.graphQLListSize(args.Size)
+	if err != nil {
+		return nil, err
+	}
+	timeRange, err := parseDates(args.From, args.To)
+	switch {
+	case err != nil:
+		return nil, err
+	case timeRange.EndTime.Before(timeRange.StartTime):
+		return nil, fmt.Errorf("end time: %s cannot be before start time: %s", timeRange.EndTime, timeRange.StartTime)
+	case timeRange.EndTime.Sub(timeRange.StartTime).Seconds() > float64(defaultDays*24*60*60):
+		return nil, fmt.Errorf("time range of more than %d days is not allowed", defaultDays)
+	}
+	var status string
+	if args.Status != nil {
+		status = *args.Status
+	}
+
+	schedules, _, _, err := s.FetchAppSchedules(appId, timeRange, int64(size), store.Status(status), nil, timeRange.StartTime)
+	return s.graphQLSchedules(schedules, err)
+}
+
+// graphQLSchedules resolves the schedules of a list field, the ones which are not found to an empty list
+func (s *Service) graphQLSchedules(schedules []store.Schedule, err error) (*[]*graphQLSchedule, error) {
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	resolved := make([]*graphQLSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		resolved = append(resolved, &graphQLSchedule{s: s, schedule: schedule})
+	}
+	return &resolved, nil
+}
+
+// graphQLListSize checks the number of items a list field is asked for
+func (s *Service) graphQLListSize(size int32) (int, error) {
+	if max := s.Config.GraphQLConfig.MaxListSize; size < 1 || (max > 0 && int(size) > max) {
+		return 0, fmt.Errorf("a list can select from 1 to %d items, got %d", max, size)
+	}
+	return int(size), nil
+}
+
+func isNotFound(err error) bool {
+	appErr, ok := err.(er.AppError)
+	return err == gocql.ErrNotFound || (ok && appErr.Code == er.DataNotFound)
+}
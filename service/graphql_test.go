@@ -0,0 +1,149 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+var graphQLScheduleId, _ = gocql.ParseUUID("11111111-2222-3333-4444-555555555555")
+
+// MockScheduleDaoForGraphQL finds a single recurring schedule of the test app
+type MockScheduleDaoForGraphQL struct {
+	dao.DummyScheduleDaoImpl
+}
+
+func (m *MockScheduleDaoForGraphQL) recurring() store.Schedule {
+	return store.Schedule{
+		ScheduleId:     graphQLScheduleId,
+		AppId:          "test",
+		Payload:        `{"v":1}`,
+		CronExpression: "*/5 * * * *",
+		Status:         store.Scheduled,
+		Callback:       &store.HttpCallback{Type: "http", Details: store.Details{Url: "http://127.0.0.1/cb", Method: "POST"}},
+	}
+}
+
+func (m *MockScheduleDaoForGraphQL) GetEnrichedSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	if uuid != graphQLScheduleId {
+		return store.Schedule{}, gocql.ErrNotFound
+	}
+	return m.recurring(), nil
+}
+
+func (m *MockScheduleDaoForGraphQL) GetSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	return m.GetEnrichedSchedule(uuid)
+}
+
+func (m *MockScheduleDaoForGraphQL) GetScheduleRuns(uuid gocql.UUID, size int64, when string, pageState []byte) ([]store.Schedule, []byte, error) {
+	var runs []store.Schedule
+	for i := int64(0); i < size && i < 7; i++ {
+		runs = append(runs, store.Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", ParentScheduleId: uuid, Status: store.Success})
+	}
+	return runs, nil, nil
+}
+
+func graphQL(service *Service, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req, _ := http.NewRequest("POST", "/goscheduler/graphql", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GraphQL).ServeHTTP(rr, req)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(rr.Body.Bytes(), &response)
+	return rr, response
+}
+
+func TestService_GraphQL(t *testing.T) {
+	service := setupMocks()
+	service.Config.GraphQLConfig = conf.GraphQLConfig{MaxDepth: 4, MaxListSize: 10}
+	service.ScheduleDao = &MockScheduleDaoForGraphQL{}
+
+	rr, response := graphQL(service, `{
+		"query": "query S($id: ID!) { schedule(id: $id) { scheduleId status callback runs { parentScheduleId status } app { appId limits { maxPayloadSize } } versions { version current } } }",
+		"variables": {"id": "11111111-2222-3333-4444-555555555555"}
+	}`)
+	if rr.Code != http.StatusOK || response["errors"] != nil {
+		t.Fatalf("Expected the query to succeed, got %d with %s", rr.Code, rr.Body.String())
+	}
+
+	schedule := response["data"].(map[string]interface{})["schedule"].(map[string]interface{})
+	if schedule["scheduleId"] != graphQLScheduleId.String() || schedule["status"] != string(store.Scheduled) {
+		t.Errorf("Unexpected schedule %+v", schedule)
+	}
+	if callback, ok := schedule["callback"].(map[string]interface{}); !ok || callback["type"] != "http" {
+		t.Errorf("Expected the http callback, got %+v", schedule["callback"])
+	}
+	runs := schedule["runs"].([]interface{})
+	if len(runs) != defaultGraphQLRuns || runs[0].(map[string]interface{})["parentScheduleId"] != graphQLScheduleId.String() {
+		t.Errorf("Expected the 5 latest runs, got %+v", runs)
+	}
+	app := schedule["app"].(map[string]interface{})
+	if app["appId"] != "test" || app["limits"].(map[string]interface{})["maxPayloadSize"] != 1024.0 {
+		t.Errorf("Unexpected app %+v", app)
+	}
+	if versions := schedule["versions"].([]interface{}); len(versions) != 2 || versions[1].(map[string]interface{})["current"] != true {
+		t.Errorf("Expected the previous and current versions, got %+v", versions)
+	}
+	if !strings.HasPrefix(rr.Body.String(), `{"data":{"schedule":{"scheduleId":`) {
+		t.Errorf("Expected the fields in the order of the query, got %s", rr.Body.String())
+	}
+}
+
+func TestService_GraphQLErrors(t *testing.T) {
+	service := setupMocks()
+	service.Config.GraphQLConfig = conf.GraphQLConfig{MaxDepth: 3, MaxListSize: 10}
+	service.ScheduleDao = &MockScheduleDaoForGraphQL{}
+
+	for _, test := range []struct {
+		body    string
+		status  int
+		data    bool
+		message string
+	}{
+		{`{"query": "{ schedule(id: \"00000000-0000-0000-0000-000000000001\") { scheduleId } }"}`, http.StatusOK, true, ""},
+		{`{"query": "{ schedule(id: \"not-a-uuid\") { scheduleId } }"}`, http.StatusOK, true, "invalid UUID"},
+		{`{"query": "{ runs(scheduleId: \"11111111-2222-3333-4444-555555555555\", last: 50) { scheduleId } }"}`, http.StatusOK, true, "from 1 to 10 items"},
+		{`{"query": "{ app(id: \"testGetAppErrorNotFound\") { appId } }"}`, http.StatusOK, true, ""},
+		{`{"query": "{ schedule(id: \"11111111-2222-3333-4444-555555555555\") { runs { app { limits { maxPayloadSize } } } } }"}`, http.StatusBadRequest, false, "exceeds max depth 3"},
+		{`{"query": "{ schedule(id: \"11111111-2222-3333-4444-555555555555\") { cron } }"}`, http.StatusBadRequest, false, `Cannot query field \"cron\"`},
+		{`{"query": "mutation { schedule(id: \"1\") { scheduleId } }"}`, http.StatusBadRequest, false, "no mutations"},
+		{`{"query": ""}`, http.StatusBadRequest, false, "query is required"},
+		{`{"query": `, http.StatusBadRequest, false, ""},
+	} {
+		rr, response := graphQL(service, test.body)
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with %s", test.body, test.status, rr.Code, rr.Body.String())
+		}
+		if _, ok := response["data"]; ok != test.data {
+			t.Errorf("%s: expected data %v, got %s", test.body, test.data, rr.Body.String())
+		}
+		if test.message != "" && !strings.Contains(rr.Body.String(), test.message) {
+			t.Errorf("%s: expected an error containing %q, got %s", test.body, test.message, rr.Body.String())
+		}
+	}
+}
+
+func TestService_GetGraphQLSchema(t *testing.T) {
+	service := setupMocks()
+
+	req, _ := http.NewRequest("GET", "/goscheduler/graphql/schema", nil)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GetGraphQLSchema).ServeHTTP(rr, req)
+
+	var response GraphQLSchemaResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"type Query {", "schedule(id: ID!): Schedule", "last: Int = 5", "): [Schedule!]", "scalar JSON"} {
+		if !strings.Contains(response.Data.Schema, expected) {
+			t.Errorf("Expected the schema to contain %q, got\n%s", expected, response.Data.Schema)
+		}
+	}
+}
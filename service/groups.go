@@ -0,0 +1,355 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// groupRequest is the body of the group create and update APIs, the name is taken from the path on updates
+type groupRequest struct {
+	Name             string `json:"name,omitempty"`
+	Description      string `json:"description,omitempty"`
+	MaxRunsPerMinute int    `json:"maxRunsPerMinute,omitempty"`
+}
+
+// CreateScheduleGroup creates a group of an app, which recurring schedules join with their group field
+func (s *Service) CreateScheduleGroup(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	group, err := s.saveScheduleGroup(appId, "", r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.CreateScheduleGroup, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.CreateScheduleGroup, appId, constants.Success)
+
+	w.WriteHeader(http.StatusCreated)
+	status := Status{StatusCode: constants.SuccessCode201, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(ScheduleGroupResponse{Status: status, Data: group})
+}
+
+// UpdateScheduleGroup updates the description and the run limit of a group of an app
+func (s *Service) UpdateScheduleGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	group, err := s.saveScheduleGroup(appId, vars["name"], r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.UpdateScheduleGroup, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.UpdateScheduleGroup, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(ScheduleGroupResponse{Status: status, Data: group})
+}
+
+// saveScheduleGroup creates the group named in the body when name is empty, updates the named group otherwise
+func (s *Service) saveScheduleGroup(appId string, name string, r *http.Request) (store.ScheduleGroup, error) {
+	var input groupRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return store.ScheduleGroup{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+	if err = json.Unmarshal(body, &input); err != nil {
+		return store.ScheduleGroup{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	if _, err = s.getApp(appId); err != nil {
+		return store.ScheduleGroup{}, err
+	}
+
+	update := name != ""
+	if !update {
+		name = input.Name
+	}
+	now := time.Now()
+	group := store.ScheduleGroup{
+		AppId:            appId,
+		Name:             name,
+		Description:      input.Description,
+		MaxRunsPerMinute: input.MaxRunsPerMinute,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err = group.Validate(); err != nil {
+		return store.ScheduleGroup{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	if update {
+		switch updated, err := s.ClusterDao.UpdateScheduleGroup(group); {
+		case err != nil:
+			return store.ScheduleGroup{}, er.NewError(er.DataPersistenceFailure, err)
+		case !updated:
+			return store.ScheduleGroup{}, er.NewError(er.DataNotFound, fmt.Errorf("group %s of app %s does not exist", name, appId))
+		}
+		return s.fetchScheduleGroup(appId, name)
+	}
+
+	switch created, err := s.ClusterDao.CreateScheduleGroup(group); {
+	case err != nil:
+		return store.ScheduleGroup{}, er.NewError(er.DataPersistenceFailure, err)
+	case !created:
+		return store.ScheduleGroup{}, er.NewError(er.Conflict, fmt.Errorf("group %s of app %s already exists", name, appId))
+	}
+	return group, nil
+}
+
+// GetScheduleGroups returns the groups of an app
+func (s *Service) GetScheduleGroups(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	groups, err := s.ClusterDao.GetScheduleGroups(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetScheduleGroups, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+	if groups == nil {
+		groups = []store.ScheduleGroup{}
+	}
+
+	s.recordRequestAppStatus(constants.GetScheduleGroups, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(groups)}
+	_ = json.NewEncoder(w).Encode(ScheduleGroupsResponse{Status: status, Data: groups})
+}
+
+// GetScheduleGroup reports on a group of an app: its members by status and the latest run of each of them
+func (s *Service) GetScheduleGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	report, err := s.ReportScheduleGroup(appId, vars["name"])
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetScheduleGroup, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetScheduleGroup, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(report.Schedules)}
+	_ = json.NewEncoder(w).Encode(GroupReportResponse{Status: status, Data: report})
+}
+
+// ReportScheduleGroup reports on the members of a group along with the latest past run of each of them
+func (s *Service) ReportScheduleGroup(appId string, name string) (store.GroupReport, error) {
+	group, err := s.fetchScheduleGroup(appId, name)
+	if err != nil {
+		return store.GroupReport{}, err
+	}
+
+	members, err := s.groupMembers(appId, name)
+	if err != nil {
+		return store.GroupReport{}, err
+	}
+
+	lastRuns := make(map[gocql.UUID]store.Schedule)
+	for _, member := range members {
+		runs, _, err := s.ScheduleDao.GetScheduleRuns(member.ScheduleId, 1, "past", nil)
+		if err != nil {
+			return store.GroupReport{}, er.NewError(er.DataFetchFailure, err)
+		}
+		if len(runs) > 0 {
+			lastRuns[member.ScheduleId] = runs[0]
+		}
+	}
+
+	return store.NewGroupReport(group, members, lastRuns), nil
+}
+
+// DeleteScheduleGroup deletes a group of an app and starts a job deleting its schedules
+func (s *Service) DeleteScheduleGroup(w http.ResponseWriter, r *http.Request) {
+	s.groupAction(w, r, constants.DeleteScheduleGroup, store.DeleteGroup)
+}
+
+// PauseScheduleGroup starts a job pausing the schedules of a group of an app
+func (s *Service) PauseScheduleGroup(w http.ResponseWriter, r *http.Request) {
+	s.groupAction(w, r, constants.PauseScheduleGroup, store.PauseGroup)
+}
+
+// ResumeScheduleGroup starts a job resuming the paused schedules of a group of an app
+func (s *Service) ResumeScheduleGroup(w http.ResponseWriter, r *http.Request) {
+	s.groupAction(w, r, constants.ResumeScheduleGroup, store.ResumeGroup)
+}
+
+func (s *Service) groupAction(w http.ResponseWriter, r *http.Request, requestName string, action store.GroupAction) {
+	log := logger.FromContext(r.Context())
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	job, err := s.StartGroupAction(appId, store.GroupActionRequest{Group: vars["name"], Action: action})
+	if err != nil {
+		s.recordRequestAppStatus(requestName, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	log.Infof("Started job %s to %s %d schedules of group %s of app %s", job.JobId, action, job.Total, vars["name"], appId)
+	s.recordRequestAppStatus(requestName, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: job.Total}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: JobData{Job: job}})
+}
+
+// StartGroupAction creates the job applying the action to the schedules of a group and runs it in the background.
+// A deleted group is removed at once, before its schedules are deleted by the job.
+func (s *Service) StartGroupAction(appId string, input store.GroupActionRequest) (store.Job, error) {
+	if _, err := s.getApp(appId); err != nil {
+		return store.Job{}, err
+	}
+	if _, err := s.fetchScheduleGroup(appId, input.Group); err != nil {
+		return store.Job{}, err
+	}
+
+	members, err := s.groupMembers(appId, input.Group)
+	if err != nil {
+		return store.Job{}, err
+	}
+	if len(members) > store.MaxBulkUpdateSchedules {
+		return store.Job{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("group %s has %d schedules, a group action is limited to %d", input.Group, len(members), store.MaxBulkUpdateSchedules))
+	}
+
+	if input.Action == store.DeleteGroup {
+		if err = s.ClusterDao.DeleteScheduleGroup(appId, input.Group); err != nil {
+			return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+		}
+	}
+
+	scheduleIds := make([]string, 0, len(members))
+	for _, member := range members {
+		scheduleIds = append(scheduleIds, member.ScheduleId.String())
+	}
+
+	request, _ := json.Marshal(input)
+	job := store.NewJob(store.GroupActionJob, appId, request, len(scheduleIds), time.Now())
+	if err = s.ScheduleDao.UpsertJob(job, s.Config.RetentionConfig.JobRetentionPeriod); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	go s.runJob(job, scheduleIds, s.groupActionProcessor(input.Action))
+	return job, nil
+}
+
+// groupActionProcessor pauses, resumes or deletes the schedules of a group one by one.
+// A schedule already in the state the action leads to succeeds without being updated again.
+func (s *Service) groupActionProcessor(action store.GroupAction) jobProcessor {
+	return func(itemId string) store.JobItem {
+		item := store.JobItem{ItemId: itemId, Status: store.Success}
+
+		if err := s.applyGroupAction(itemId, action); err != nil {
+			item.Status = store.Failure
+			item.Error = err.Error()
+		}
+		return item
+	}
+}
+
+func (s *Service) applyGroupAction(itemId string, action store.GroupAction) error {
+	if action == store.DeleteGroup {
+		_, err := s.DeleteSchedule(itemId)
+		if appErr, ok := err.(er.AppError); ok && appErr.Code == er.DataNotFound {
+			return nil
+		}
+		return err
+	}
+
+	scheduleId, err := gocql.ParseUUID(itemId)
+	if err != nil {
+		return err
+	}
+	schedule, err := s.ScheduleDao.GetSchedule(scheduleId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case action == store.PauseGroup && schedule.Status == store.Scheduled:
+		_, err = s.pauseRecurringSchedule(schedule)
+	case action == store.ResumeGroup && schedule.Status == store.Paused:
+		_, _, _, err = s.resumeRecurringSchedule(schedule)
+	case schedule.Status == store.Deleted:
+		err = fmt.Errorf("schedule %s is deleted", itemId)
+	}
+	return err
+}
+
+// groupMembers returns the recurring schedules of a group of an app which are not deleted
+func (s *Service) groupMembers(appId string, name string) ([]store.Schedule, error) {
+	recurring, errs := s.ScheduleDao.GetCronSchedulesByApp(appId, "")
+	if len(errs) != 0 {
+		return nil, er.NewError(er.DataFetchFailure, errors.New(strings.Join(errs, ",")))
+	}
+
+	filter := store.ScheduleFilter{Group: name}
+	var members []store.Schedule
+	for _, schedule := range recurring {
+		if schedule.AppId == appId && filter.Matches(schedule) {
+			members = append(members, schedule)
+		}
+	}
+	return members, nil
+}
+
+func (s *Service) fetchScheduleGroup(appId string, name string) (store.ScheduleGroup, error) {
+	switch group, err := s.ClusterDao.GetScheduleGroup(appId, name); {
+	case err == gocql.ErrNotFound:
+		return store.ScheduleGroup{}, er.NewError(er.DataNotFound, fmt.Errorf("group %s of app %s does not exist", name, appId))
+	case err != nil:
+		return store.ScheduleGroup{}, er.NewError(er.DataFetchFailure, err)
+	default:
+		return group, nil
+	}
+}
+
+// checkScheduleGroup checks that the group a schedule joins exists
+func (s *Service) checkScheduleGroup(appId string, group string) error {
+	if group == "" {
+		return nil
+	}
+
+	switch _, err := s.ClusterDao.GetScheduleGroup(appId, group); {
+	case err == gocql.ErrNotFound:
+		return er.NewError(er.InvalidDataCode, fmt.Errorf("group %s of app %s does not exist", group, appId))
+	case err != nil:
+		return er.NewError(er.DataFetchFailure, err)
+	default:
+		return nil
+	}
+}
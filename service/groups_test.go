@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_CreateScheduleGroup(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		body   string
+		status int
+	}{
+		{"testApp", `{"name":"newGroup","description":"campaign","maxRunsPerMinute":100}`, http.StatusCreated},
+		{"testApp", `{"name":"testGroup"}`, http.StatusConflict},
+		{"testApp", `{"name":"bad name"}`, http.StatusBadRequest},
+		{"testApp", `{"name":"newGroup","maxRunsPerMinute":-1}`, http.StatusBadRequest},
+		{"testApp", `{"name":`, http.StatusBadRequest},
+		{"testDeactivated", `{"name":"newGroup"}`, http.StatusBadRequest},
+		{"testCreateGroupError", `{"name":"newGroup"}`, http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("POST", "/goscheduler/apps/"+test.appId+"/groups", bytes.NewBufferString(test.body))
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.CreateScheduleGroup).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d with body %s", test.appId, test.body, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_UpdateScheduleGroup(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		name   string
+		status int
+	}{
+		{"testApp", "testGroup", http.StatusOK},
+		{"testApp", "missingGroup", http.StatusNotFound},
+		{"testUpdateGroupError", "testGroup", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("PUT", "/goscheduler/apps/"+test.appId+"/groups/"+test.name, bytes.NewBufferString(`{"maxRunsPerMinute":10}`))
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId, "name": test.name})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.UpdateScheduleGroup).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d with body %s", test.appId, test.name, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_GetScheduleGroups(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		status int
+	}{
+		{"testApp", http.StatusOK},
+		{"testGetGroupError", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/"+test.appId+"/groups", nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetScheduleGroups).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.appId, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_GetScheduleGroup(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		name   string
+		status int
+	}{
+		{"testGroup", http.StatusOK},
+		{"missingGroup", http.StatusNotFound},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/testApp/groups/"+test.name, nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": "testApp", "name": test.name})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetScheduleGroup).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.name, test.status, rr.Code, rr.Body.String())
+			continue
+		}
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		var response GroupReportResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Data.Group.Name != "testGroup" || response.Data.Group.MaxRunsPerMinute != 10 {
+			t.Errorf("expected the report of testGroup, got %+v", response.Data.Group)
+		}
+	}
+}
+
+func TestService_PauseScheduleGroup(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		name   string
+		status int
+	}{
+		{"testApp", "testGroup", http.StatusOK},
+		{"testApp", "missingGroup", http.StatusNotFound},
+		{"testDeactivated", "testGroup", http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("POST", "/goscheduler/apps/"+test.appId+"/groups/"+test.name+"/pause", nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId, "name": test.name})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.PauseScheduleGroup).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d with body %s", test.appId, test.name, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_DeleteScheduleGroup(t *testing.T) {
+	service := setupMocks()
+
+	req, _ := http.NewRequest("DELETE", "/goscheduler/apps/testDeleteGroupError/groups/testGroup", nil)
+	req = mux.SetURLVars(req, map[string]string{"appId": "testDeleteGroupError", "name": "testGroup"})
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.DeleteScheduleGroup).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d with body %s", http.StatusInternalServerError, rr.Code, rr.Body.String())
+	}
+}
+
+func TestService_GroupActionProcessor(t *testing.T) {
+	service := setupMocks()
+
+	if item := service.groupActionProcessor(store.PauseGroup)("invalid"); item.Status != store.Failure {
+		t.Errorf("expected an invalid schedule id to fail, got %+v", item)
+	}
+	if item := service.groupActionProcessor(store.ResumeGroup)("84d0d5b8-d953-11ed-a827-aa665a372253"); item.Status != store.Success {
+		t.Errorf("expected a schedule which is not paused to be left as is, got %+v", item)
+	}
+}
+
+func TestService_CheckScheduleGroup(t *testing.T) {
+	service := setupMocks()
+
+	if err := service.checkScheduleGroup("testApp", "testGroup"); err != nil {
+		t.Errorf("expected an existing group to be accepted, got %v", err)
+	}
+	if err := service.checkScheduleGroup("testApp", "missingGroup"); err == nil {
+		t.Error("expected a missing group to be rejected")
+	}
+	if err := service.checkScheduleGroup("testApp", ""); err != nil {
+		t.Errorf("expected a schedule without group to be accepted, got %v", err)
+	}
+}
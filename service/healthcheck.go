@@ -35,3 +35,22 @@ func HealthCheck(w http.ResponseWriter, r *http.Request) {
 	jsonResponse, _ := json.Marshal(HealthCheckResponse{Status: constants.Success, Code: 200})
 	w.Write(jsonResponse)
 }
+
+type ReadinessResponse struct {
+	Status string `json:"status"`
+	Code   int    `json:"code"`
+	Data   Load   `json:"data"`
+}
+
+// Readiness reports whether the node can take new schedules, 503 when it sheds them
+func (s *Service) Readiness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	load := s.load()
+	response := ReadinessResponse{Status: constants.Success, Code: http.StatusOK, Data: load}
+	if load.Overloaded {
+		response = ReadinessResponse{Status: constants.Fail, Code: http.StatusServiceUnavailable, Data: load}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	jsonResponse, _ := json.Marshal(response)
+	w.Write(jsonResponse)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// Kinds of the hedged reads, the label of their metrics
+const (
+	scheduleRead = "schedule"
+	appRead      = "app"
+)
+
+// reads are the hedged reads in flight, shared by the concurrent requests reading the same row
+var reads = &readGroup{calls: make(map[string]*readCall)}
+
+// ReadDeadlineError is the error of a read not answered within its deadline
+type ReadDeadlineError struct {
+	Read     string
+	Deadline time.Duration
+}
+
+func (e ReadDeadlineError) Error() string {
+	return fmt.Sprintf("read of %s did not complete within %s", e.Read, e.Deadline)
+}
+
+// Timeout tells that the error is a timeout, the request fails as temporary rather than as a failure
+func (e ReadDeadlineError) Timeout() bool {
+	return true
+}
+
+// readResult is the outcome of a read
+type readResult struct {
+	value interface{}
+	err   error
+}
+
+// readCall is a read in flight, the requests joining it wait for done
+type readCall struct {
+	done   chan struct{}
+	result readResult
+}
+
+// readGroup deduplicates the concurrent reads of the same key
+type readGroup struct {
+	lock  sync.Mutex
+	calls map[string]*readCall
+}
+
+// do runs read once for the concurrent callers with the same key, and tells whether the result was shared
+func (g *readGroup) do(key string, read func() (interface{}, error)) (interface{}, error, bool) {
+	g.lock.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.lock.Unlock()
+		<-call.done
+		return call.result.value, call.result.err, true
+	}
+	call := &readCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.lock.Unlock()
+
+	call.result.value, call.result.err = read()
+
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+	close(call.done)
+	return call.result.value, call.result.err, false
+}
+
+// hedgedRead reads the row of the given kind and id through read. A read still running after the hedging delay is sent
+// again, and the first answer is used. A read not answered within the deadline fails with a ReadDeadlineError, and
+// the concurrent reads of the same row share a single read.
+func (s *Service) hedgedRead(kind, id string, read func() (interface{}, error)) (interface{}, error) {
+	if s.Config == nil {
+		return read()
+	}
+
+	config := s.Config.HedgedReadConfig
+	hedged := func() (interface{}, error) {
+		return s.hedge(kind, read, time.Duration(config.DelayMillis)*time.Millisecond, time.Duration(config.DeadlineMillis)*time.Millisecond)
+	}
+	if !config.SingleFlight {
+		return hedged()
+	}
+
+	value, err, shared := reads.do(kind+":"+id, hedged)
+	if shared && s.Monitor != nil {
+		s.Monitor.IncCounter(constants.SharedReadCount, map[string]string{"read": kind}, 1)
+	}
+	return value, err
+}
+
+// hedge runs read, again once if it is still running after delay, and returns the first answer, a row or its absence,
+// within the deadline. An error is returned once both reads failed. A zero delay or deadline disables it.
+func (s *Service) hedge(kind string, read func() (interface{}, error), delay, deadline time.Duration) (interface{}, error) {
+	if delay <= 0 && deadline <= 0 {
+		return read()
+	}
+
+	// buffered for both reads, so that the read losing the race does not block
+	results := make(chan readResult, 2)
+	attempt := func() {
+		value, err := read()
+		results <- readResult{value: value, err: err}
+	}
+
+	var hedgeAt, deadlineAt <-chan time.Time
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		hedgeAt = timer.C
+	}
+	if deadline > 0 {
+		timer := time.NewTimer(deadline)
+		defer timer.Stop()
+		deadlineAt = timer.C
+	}
+
+	go attempt()
+	pending := 1
+	for {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil || result.err == gocql.ErrNotFound || pending == 0 {
+				return result.value, result.err
+			}
+		case <-hedgeAt:
+			hedgeAt = nil
+			pending++
+			if s.Monitor != nil {
+				s.Monitor.IncCounter(constants.HedgedReadCount, map[string]string{"read": kind}, 1)
+			}
+			go attempt()
+		case <-deadlineAt:
+			return nil, ReadDeadlineError{Read: kind, Deadline: deadline}
+		}
+	}
+}
+
+// readApp reads an app through a hedged read
+func (s *Service) readApp(appId string) (sch.App, error) {
+	value, err := s.hedgedRead(appRead, appId, func() (interface{}, error) {
+		return s.ClusterDao.GetApp(appId)
+	})
+	app, _ := value.(sch.App)
+	return app, err
+}
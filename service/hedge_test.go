@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+	er "github.com/myntra/goscheduler/error"
+)
+
+func hedgingService(config conf.HedgedReadConfig) *Service {
+	return &Service{Config: &conf.Configuration{HedgedReadConfig: config}}
+}
+
+func TestService_HedgedRead(t *testing.T) {
+	s := hedgingService(conf.HedgedReadConfig{DelayMillis: 10, DeadlineMillis: 1000})
+
+	var attempts int32
+	start := time.Now()
+	value, err := s.hedgedRead(scheduleRead, "slow-replica", func() (interface{}, error) {
+		// the first read hits a slow replica, the hedged one a healthy one
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			time.Sleep(500 * time.Millisecond)
+			return "slow", nil
+		}
+		return "fast", nil
+	})
+
+	if err != nil || value != "fast" {
+		t.Errorf("expected the answer of the hedged read, got %v, %v", value, err)
+	}
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("expected the hedged read to answer before the slow one, took %s", elapsed)
+	}
+}
+
+func TestService_HedgedReadNotFound(t *testing.T) {
+	s := hedgingService(conf.HedgedReadConfig{DelayMillis: 100, DeadlineMillis: 1000})
+
+	var attempts int32
+	_, err := s.hedgedRead(scheduleRead, "missing", func() (interface{}, error) {
+		atomic.AddInt32(&attempts, 1)
+		return nil, gocql.ErrNotFound
+	})
+
+	if err != gocql.ErrNotFound {
+		t.Errorf("expected %v, got %v", gocql.ErrNotFound, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected a single read of a missing row, got %d", attempts)
+	}
+}
+
+func TestService_HedgedReadBothFail(t *testing.T) {
+	s := hedgingService(conf.HedgedReadConfig{DelayMillis: 10, DeadlineMillis: 1000})
+	failure := errors.New("unavailable")
+
+	var attempts int32
+	_, err := s.hedgedRead(appRead, "failing", func() (interface{}, error) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil, failure
+	})
+
+	if err != failure {
+		t.Errorf("expected %v, got %v", failure, err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected the read to be hedged once, got %d reads", attempts)
+	}
+}
+
+func TestService_HedgedReadDeadline(t *testing.T) {
+	s := hedgingService(conf.HedgedReadConfig{DeadlineMillis: 20})
+
+	_, err := s.hedgedRead(scheduleRead, "stuck", func() (interface{}, error) {
+		time.Sleep(200 * time.Millisecond)
+		return "late", nil
+	})
+
+	var deadline ReadDeadlineError
+	if !errors.As(err, &deadline) {
+		t.Fatalf("expected a ReadDeadlineError, got %v", err)
+	}
+
+	// a read past its deadline is reported as a data store timeout
+	rec := httptest.NewRecorder()
+	er.Handle(rec, httptest.NewRequest(http.MethodGet, "/goscheduler/schedules/stuck", nil), er.NewError(er.DataFetchFailure, err))
+	if rec.Code != er.HTTPStatus(er.DataStoreTimeout) {
+		t.Errorf("expected status %d, got %d", er.HTTPStatus(er.DataStoreTimeout), rec.Code)
+	}
+}
+
+func TestService_HedgedReadSingleFlight(t *testing.T) {
+	s := hedgingService(conf.HedgedReadConfig{SingleFlight: true})
+
+	var attempts int32
+	release := make(chan struct{})
+	read := func() (interface{}, error) {
+		atomic.AddInt32(&attempts, 1)
+		<-release
+		return "app", nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]interface{}, 5)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _ = s.hedgedRead(appRead, "shared", read)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if attempts != 1 {
+		t.Errorf("expected the concurrent reads to share a single read, got %d reads", attempts)
+	}
+	for i, value := range values {
+		if value != "app" {
+			t.Errorf("expected reader %d to get the shared answer, got %v", i, value)
+		}
+	}
+}
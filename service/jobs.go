@@ -0,0 +1,278 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Number of items a job processes between two checks of its cancellation and two checkpoints of its progress
+const jobChunkItems = 100
+
+// jobProcessor processes a single item of a job and returns its result
+type jobProcessor func(itemId string) store.JobItem
+
+// jobProcessorOf returns the processor of the items of a job, built from the request which started it.
+// Every type of job has to be added here to be retried.
+func (s *Service) jobProcessorOf(job store.Job, requestId string) (jobProcessor, error) {
+	switch job.Type {
+	case store.BulkUpdateJob:
+		var input store.BulkUpdateRequest
+		if err := json.Unmarshal(job.Request, &input); err != nil {
+			return nil, er.NewError(er.UnprocessableEntity, fmt.Errorf("request of job %s: %w", job.JobId, err))
+		}
+		app, err := s.getApp(job.AppId)
+		if err != nil {
+			return nil, err
+		}
+		return s.bulkUpdateProcessor(app, input.Patch, requestId), nil
+	case store.OffboardJob:
+		var input store.OffboardRequest
+		if err := json.Unmarshal(job.Request, &input); err != nil {
+			return nil, er.NewError(er.UnprocessableEntity, fmt.Errorf("request of job %s: %w", job.JobId, err))
+		}
+		app, err := s.getActiveOrInactiveApp(job.AppId)
+		if err != nil {
+			return nil, err
+		}
+		return s.offboardingProcessor(app, input.Purge), nil
+	case store.GroupActionJob:
+		var input store.GroupActionRequest
+		if err := json.Unmarshal(job.Request, &input); err != nil {
+			return nil, er.NewError(er.UnprocessableEntity, fmt.Errorf("request of job %s: %w", job.JobId, err))
+		}
+		return s.groupActionProcessor(input.Action), nil
+	case store.ResumeSuspendedJob:
+		return s.resumeSuspendedProcessor(job.AppId), nil
+	default:
+		return nil, er.NewError(er.UnprocessableEntity, fmt.Errorf("jobs of type %s cannot be retried", job.Type))
+	}
+}
+
+// runJob processes the items of a job in chunks, recording the result of each of them.
+// Between two chunks the progress is saved, so that it can be followed through the get job API, and the job stops
+// if its cancellation was requested.
+func (s *Service) runJob(job store.Job, itemIds []string, process jobProcessor) store.Job {
+	ttl := s.Config.RetentionConfig.JobRetentionPeriod
+
+	for start := 0; start < len(itemIds); start += jobChunkItems {
+		if s.jobCancelRequested(job.JobId) {
+			job.Status = store.JobCancelled
+			break
+		}
+
+		end := start + jobChunkItems
+		if end > len(itemIds) {
+			end = len(itemIds)
+		}
+
+		for _, itemId := range itemIds[start:end] {
+			item := process(itemId)
+			item.JobId = job.JobId
+			if err := s.ScheduleDao.CreateJobItem(item, ttl); err != nil {
+				logger.Errorf("Error: %s while saving the result of item %s of job %s", err.Error(), item.ItemId, job.JobId)
+			}
+			job.Record(item, time.Now())
+		}
+
+		if end < len(itemIds) {
+			if err := s.ScheduleDao.UpsertJob(job, ttl); err != nil {
+				logger.Errorf("Error: %s while saving the progress of job %s", err.Error(), job.JobId)
+			}
+		}
+	}
+
+	if job.Status == store.JobRunning {
+		job.Status = store.JobCompleted
+	}
+	if err := s.ScheduleDao.UpsertJob(job, ttl); err != nil {
+		logger.Errorf("Error: %s while finishing job %s", err.Error(), job.JobId)
+	}
+
+	logger.Infof("Job %s of app %s is %s, %d items succeeded and %d failed out of %d", job.JobId, job.AppId, job.Status, job.Succeeded, job.Failed, job.Total)
+	return job
+}
+
+// jobCancelRequested tells whether the cancellation of a job was requested.
+// The job goes on when the flag cannot be read, the check is made again after the next chunk.
+func (s *Service) jobCancelRequested(jobId gocql.UUID) bool {
+	job, err := s.ScheduleDao.GetJob(jobId)
+	if err != nil {
+		logger.Errorf("Error: %s while checking the cancellation of job %s", err.Error(), jobId)
+		return false
+	}
+	return job.CancelRequested
+}
+
+// GetJob returns the progress of an asynchronous job along with the results of the items it has processed
+func (s *Service) GetJob(w http.ResponseWriter, r *http.Request) {
+	data, err := s.FetchJob(mux.Vars(r)["jobId"])
+	if err != nil {
+		s.recordRequestStatus(constants.GetJob, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetJob, data.Job.AppId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(data.Items)}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: data})
+}
+
+func (s *Service) FetchJob(id string) (JobData, error) {
+	job, err := s.fetchJob(id)
+	if err != nil {
+		return JobData{}, err
+	}
+
+	items, err := s.ScheduleDao.GetJobItems(job.JobId)
+	if err != nil {
+		return JobData{}, er.NewError(er.DataFetchFailure, err)
+	}
+	if items == nil {
+		items = []store.JobItem{}
+	}
+
+	return JobData{Job: job, Items: items}, nil
+}
+
+// CancelJob requests a running job to stop. The job stops once the chunk of items it is processing is done,
+// the items processed until then are not reverted.
+func (s *Service) CancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.RequestJobCancellation(mux.Vars(r)["jobId"])
+	if err != nil {
+		s.recordRequestStatus(constants.CancelJob, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Requested the cancellation of job %s of app %s", job.JobId, job.AppId)
+	s.recordRequestAppStatus(constants.CancelJob, job.AppId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: job.Total}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: JobData{Job: job}})
+}
+
+func (s *Service) RequestJobCancellation(id string) (store.Job, error) {
+	job, err := s.fetchJob(id)
+	if err != nil {
+		return store.Job{}, err
+	}
+
+	if job.Finished() {
+		return store.Job{}, er.NewError(er.Conflict, fmt.Errorf("job %s is already %s", id, job.Status))
+	}
+
+	if err = s.ScheduleDao.SetJobCancelRequested(job.JobId, true, s.Config.RetentionConfig.JobRetentionPeriod); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	job.CancelRequested = true
+	return job, nil
+}
+
+// RetryJob runs a finished job again over the items which failed, in the background
+func (s *Service) RetryJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.RetryFailedItems(mux.Vars(r)["jobId"], logger.RequestID(r.Context()))
+	if err != nil {
+		s.recordRequestStatus(constants.RetryJob, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Retrying job %s of app %s", job.JobId, job.AppId)
+	s.recordRequestAppStatus(constants.RetryJob, job.AppId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: job.Total}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: JobData{Job: job}})
+}
+
+func (s *Service) RetryFailedItems(id string, requestId string) (store.Job, error) {
+	job, err := s.fetchJob(id)
+	if err != nil {
+		return store.Job{}, err
+	}
+
+	if !job.Finished() {
+		return store.Job{}, er.NewError(er.Conflict, fmt.Errorf("job %s is still %s", id, job.Status))
+	}
+
+	items, err := s.ScheduleDao.GetJobItems(job.JobId)
+	if err != nil {
+		return store.Job{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	var failed []string
+	for _, item := range items {
+		if item.Status != store.Success {
+			failed = append(failed, item.ItemId)
+		}
+	}
+	if len(failed) == 0 {
+		return store.Job{}, er.NewError(er.Conflict, fmt.Errorf("job %s has no failed item", id))
+	}
+
+	process, err := s.jobProcessorOf(job, requestId)
+	if err != nil {
+		return store.Job{}, err
+	}
+
+	ttl := s.Config.RetentionConfig.JobRetentionPeriod
+	if job.CancelRequested {
+		if err = s.ScheduleDao.SetJobCancelRequested(job.JobId, false, ttl); err != nil {
+			return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+		}
+	}
+
+	job.Retry(len(failed), time.Now())
+	if err = s.ScheduleDao.UpsertJob(job, ttl); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	go s.runJob(job, failed, process)
+	return job, nil
+}
+
+// fetchJob returns the job of the id
+func (s *Service) fetchJob(id string) (store.Job, error) {
+	jobId, err := gocql.ParseUUID(id)
+	if err != nil {
+		return store.Job{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	job, err := s.ScheduleDao.GetJob(jobId)
+	switch {
+	case err == gocql.ErrNotFound:
+		return store.Job{}, er.NewError(er.DataNotFound, fmt.Errorf("job %s not found", id))
+	case err != nil:
+		return store.Job{}, er.NewError(er.DataFetchFailure, err)
+	}
+	return job, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockScheduleDaoForJobs returns a single job and records the changes of its cancellation flag
+type MockScheduleDaoForJobs struct {
+	MockScheduleDaoForBulkUpdate
+	job             store.Job
+	jobItems        []store.JobItem
+	cancelRequested []bool
+}
+
+func (m *MockScheduleDaoForJobs) GetJob(uuid gocql.UUID) (store.Job, error) {
+	return m.job, nil
+}
+
+func (m *MockScheduleDaoForJobs) GetJobItems(uuid gocql.UUID) ([]store.JobItem, error) {
+	return m.jobItems, nil
+}
+
+func (m *MockScheduleDaoForJobs) SetJobCancelRequested(uuid gocql.UUID, cancelRequested bool, ttl int) error {
+	m.cancelRequested = append(m.cancelRequested, cancelRequested)
+	m.job.CancelRequested = cancelRequested
+	return nil
+}
+
+// lastJob returns the last state of the job saved
+func (m *MockScheduleDaoForJobs) lastJob() store.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.jobs) == 0 {
+		return store.Job{}
+	}
+	return m.jobs[len(m.jobs)-1]
+}
+
+func serveJob(handler http.HandlerFunc, method string, path string, jobId string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req = mux.SetURLVars(req, map[string]string{"jobId": jobId})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestService_GetJob(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		jobId  string
+		Status int
+	}{
+		{gocql.TimeUUID().String(), http.StatusOK},
+		{"00000000-0000-0000-0000-000000000000", http.StatusInternalServerError},
+		{"00000000-0000-0000-0000-000000000001", http.StatusNotFound},
+		{"00000000-0000-0000-0000-000000000002", http.StatusInternalServerError},
+		{"invalid-uuid", http.StatusBadRequest},
+	} {
+		rr := serveJob(service.GetJob, "GET", "/goscheduler/jobs/{jobId}", test.jobId)
+
+		if rr.Code != test.Status {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.jobId, rr.Code, test.Status)
+		}
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		var response JobResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Data.Job.JobId.String() != test.jobId || len(response.Data.Items) != 1 {
+			t.Errorf("unexpected job %+v", response.Data)
+		}
+	}
+}
+
+func TestService_CancelJob(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		jobId  string
+		Status int
+	}{
+		{gocql.TimeUUID().String(), http.StatusOK},
+		{"00000000-0000-0000-0000-000000000000", http.StatusInternalServerError},
+		{"00000000-0000-0000-0000-000000000001", http.StatusNotFound},
+		{"00000000-0000-0000-0000-000000000003", http.StatusInternalServerError},
+		{"invalid-uuid", http.StatusBadRequest},
+	} {
+		rr := serveJob(service.CancelJob, "POST", "/goscheduler/jobs/{jobId}/cancel", test.jobId)
+
+		if rr.Code != test.Status {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.jobId, rr.Code, test.Status)
+		}
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		var response JobResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		if !response.Data.Job.CancelRequested {
+			t.Errorf("expected the cancellation to be requested, got %+v", response.Data.Job)
+		}
+	}
+
+	service.ScheduleDao = &MockScheduleDaoForJobs{job: store.Job{JobId: gocql.TimeUUID(), Status: store.JobCompleted}}
+	if rr := serveJob(service.CancelJob, "POST", "/goscheduler/jobs/{jobId}/cancel", gocql.TimeUUID().String()); rr.Code != http.StatusConflict {
+		t.Errorf("expected a finished job not to be cancelled, got %v", rr.Code)
+	}
+}
+
+func TestService_RunJob_Cancelled(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+	job := store.NewJob(store.BulkUpdateJob, "testApp", nil, 2, time.Now())
+	scheduleDao := &MockScheduleDaoForJobs{job: store.Job{JobId: job.JobId, Status: store.JobRunning, CancelRequested: true}}
+	service.ScheduleDao = scheduleDao
+
+	processed := 0
+	job = service.runJob(job, []string{"1", "2"}, func(itemId string) store.JobItem {
+		processed++
+		return store.JobItem{ItemId: itemId, Status: store.Success}
+	})
+
+	if job.Status != store.JobCancelled || processed != 0 || len(scheduleDao.items) != 0 {
+		t.Errorf("expected the job to stop before its first chunk, got %+v after %d items", job, processed)
+	}
+	if last := scheduleDao.lastJob(); last.Status != store.JobCancelled {
+		t.Errorf("expected the cancelled job to be saved, got %+v", last)
+	}
+}
+
+func TestService_RetryJob(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+	service.ScheduleDao = &MockScheduleDaoForBulkUpdate{}
+
+	// dummy jobs are running
+	if rr := serveJob(service.RetryJob, "POST", "/goscheduler/jobs/{jobId}/retry", gocql.TimeUUID().String()); rr.Code != http.StatusConflict {
+		t.Errorf("expected a running job not to be retried, got %v", rr.Code)
+	}
+	if rr := serveJob(service.RetryJob, "POST", "/goscheduler/jobs/{jobId}/retry", "00000000-0000-0000-0000-000000000001"); rr.Code != http.StatusNotFound {
+		t.Errorf("expected a missing job not to be retried, got %v", rr.Code)
+	}
+
+	job := store.Job{
+		JobId:           gocql.TimeUUID(),
+		Type:            store.BulkUpdateJob,
+		AppId:           "testApp",
+		Status:          store.JobCancelled,
+		Request:         json.RawMessage(`{"patch":{"payload":"{}"}}`),
+		Total:           2,
+		Succeeded:       1,
+		Failed:          1,
+		CancelRequested: true,
+	}
+	succeeded := store.JobItem{JobId: job.JobId, ItemId: "88888888-8888-8888-8888-888888888888", Status: store.Success}
+	failed := store.JobItem{JobId: job.JobId, ItemId: "55555555-5555-5555-5555-555555555555", Status: store.Failure, Error: "timeout"}
+
+	scheduleDao := &MockScheduleDaoForJobs{job: job, jobItems: []store.JobItem{succeeded}}
+	service.ScheduleDao = scheduleDao
+	if rr := serveJob(service.RetryJob, "POST", "/goscheduler/jobs/{jobId}/retry", job.JobId.String()); rr.Code != http.StatusConflict {
+		t.Errorf("expected a job without failed items not to be retried, got %v", rr.Code)
+	}
+
+	scheduleDao.jobItems = []store.JobItem{succeeded, failed}
+	rr := serveJob(service.RetryJob, "POST", "/goscheduler/jobs/{jobId}/retry", job.JobId.String())
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: got %v, body: %s", rr.Code, rr.Body.String())
+	}
+
+	var response JobResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Data.Job.Status != store.JobRunning || response.Data.Job.Failed != 0 || response.Data.Job.CancelRequested {
+		t.Errorf("expected the job to run again, got %+v", response.Data.Job)
+	}
+	if len(scheduleDao.cancelRequested) != 1 || scheduleDao.cancelRequested[0] {
+		t.Errorf("expected the cancellation to be cleared, got %v", scheduleDao.cancelRequested)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !scheduleDao.lastJob().Finished() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if last := scheduleDao.lastJob(); last.Status != store.JobCompleted || last.Succeeded != 2 || last.Failed != 0 {
+		t.Errorf("expected the failed item to succeed on retry, got %+v", last)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// AcquireLock leases a lock of an app to the owner asking for it, so that the replicas of a consumer can agree on
+// the one processing a fire. The owner renews its lease by acquiring the lock again.
+func (s *Service) AcquireLock(w http.ResponseWriter, r *http.Request) {
+	var lock sch.Lock
+	if err := json.NewDecoder(r.Body).Decode(&lock); err != nil {
+		s.recordRequestStatus(constants.AcquireLock, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	acquired, err := s.Acquire(lock)
+	if err != nil {
+		s.recordRequestAppStatus(constants.AcquireLock, lock.AppId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.AcquireLock, lock.AppId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		LockResponse{
+			Status: status,
+			Data:   acquired,
+		})
+}
+
+// Acquire leases the lock to its owner, failing with a conflict while another owner holds it
+func (s *Service) Acquire(lock sch.Lock) (sch.Lock, error) {
+	if err := lock.Validate(); err != nil {
+		return sch.Lock{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	if _, err := s.getApp(lock.AppId); err != nil {
+		return sch.Lock{}, err
+	}
+
+	acquired, ok, err := s.ScheduleDao.AcquireLock(lock, time.Now())
+	switch {
+	case err != nil:
+		return sch.Lock{}, er.NewError(er.DataPersistenceFailure, err)
+	case !ok:
+		return sch.Lock{}, er.NewError(er.Conflict, fmt.Errorf("lock %s of app %s is held by %s", lock.Name, lock.AppId, acquired.Owner))
+	}
+	return acquired, nil
+}
+
+// ReleaseLock releases a lock of an app held by the owner in the query
+func (s *Service) ReleaseLock(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	lock := sch.Lock{AppId: vars["appId"], Name: vars["name"], Owner: r.URL.Query().Get("owner")}
+
+	if err := s.Release(lock); err != nil {
+		s.recordRequestAppStatus(constants.ReleaseLock, lock.AppId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.ReleaseLock, lock.AppId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		LockResponse{
+			Status: status,
+			Data:   lock,
+		})
+}
+
+// Release releases the lock held by its owner, failing with a conflict when another owner holds it
+func (s *Service) Release(lock sch.Lock) error {
+	if err := lock.Validate(); err != nil {
+		return er.NewError(er.InvalidDataCode, err)
+	}
+
+	current, ok, err := s.ScheduleDao.ReleaseLock(lock)
+	switch {
+	case err != nil:
+		return er.NewError(er.DataPersistenceFailure, err)
+	case ok:
+		return nil
+	case current.Owner == "":
+		return er.NewError(er.DataNotFound, fmt.Errorf("lock %s of app %s is not held", lock.Name, lock.AppId))
+	default:
+		return er.NewError(er.Conflict, fmt.Errorf("lock %s of app %s is held by %s", lock.Name, lock.AppId, current.Owner))
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_AcquireLock(t *testing.T) {
+	service := &Service{
+		Config:      conf.NewConfig(),
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		name   string
+		lock   store.Lock
+		status int
+	}{
+		{"Acquired", store.Lock{AppId: "test", Name: "run", Owner: "replica-1"}, http.StatusOK},
+		{"Held", store.Lock{AppId: "test", Name: "held", Owner: "replica-1"}, http.StatusConflict},
+		{"NoOwner", store.Lock{AppId: "test", Name: "run"}, http.StatusBadRequest},
+		{"AppNotRegistered", store.Lock{AppId: "testAppNotFound", Name: "run", Owner: "replica-1"}, http.StatusBadRequest},
+		{"PersistenceError", store.Lock{AppId: "test", Name: "error", Owner: "replica-1"}, http.StatusInternalServerError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			body, _ := json.Marshal(test.lock)
+			req, err := http.NewRequest("POST", "/goscheduler/locks", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.AcquireLock).ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, test.status)
+			}
+			if test.status != http.StatusOK {
+				return
+			}
+
+			var response LockResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Data.Owner != "replica-1" || response.Data.LeaseSeconds != store.DefaultLockLeaseSeconds || response.Data.ExpiresAt == 0 {
+				t.Errorf("Unexpected lock %+v", response.Data)
+			}
+		})
+	}
+}
+
+func TestService_ReleaseLock(t *testing.T) {
+	service := &Service{
+		Config:      conf.NewConfig(),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		name   string
+		lock   string
+		owner  string
+		status int
+	}{
+		{"Released", "run", "replica-1", http.StatusOK},
+		{"Held", "held", "replica-1", http.StatusConflict},
+		{"NotHeld", "missing", "replica-1", http.StatusNotFound},
+		{"NoOwner", "run", "", http.StatusBadRequest},
+		{"PersistenceError", "error", "replica-1", http.StatusInternalServerError},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("DELETE", "/goscheduler/locks/test/"+test.lock+"?owner="+test.owner, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"appId": "test", "name": test.lock})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.ReleaseLock).ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, test.status)
+			}
+		})
+	}
+}
@@ -20,8 +20,8 @@
 package service
 
 import (
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
 	s "github.com/myntra/goscheduler/store"
 )
 
@@ -69,7 +69,7 @@ func prefix(schedule s.Schedule, _type action) string {
 		return appId + constants.DOT + constants.GetCronSchedule
 
 	default:
-		glog.Errorf("Unknown action %s", _type)
+		logger.Errorf("Unknown action %s", _type)
 		return ""
 	}
 }
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	sch "github.com/myntra/goscheduler/store"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	msgpack.Register(sch.Schedule{}, encodeMsgpackSchedule, decodeMsgpackSchedule)
+	msgpack.Register(json.Number(""), encodeMsgpackNumber, nil)
+}
+
+// msgpackSchedule is the msgpack document of a schedule, whose callback is a map as in its JSON document
+type msgpackSchedule struct {
+	Callback     interface{} `json:"callback,omitempty"`
+	sch.Schedule `json:",inline"`
+}
+
+// marshalMsgpack encodes a document of the API as msgpack, with the field names of its JSON document and its maps
+// sorted by key
+func marshalMsgpack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	encoder.SetSortMapKeys(true)
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalMsgpack decodes a msgpack document of the API, with the field names of its JSON document
+func unmarshalMsgpack(data []byte, v interface{}) error {
+	reader := bytes.NewReader(data)
+	decoder := msgpack.NewDecoder(reader)
+	decoder.SetCustomStructTag("json")
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if reader.Len() > 0 {
+		return fmt.Errorf("msgpack: %d trailing bytes", reader.Len())
+	}
+	return nil
+}
+
+// encodeMsgpackSchedule encodes a schedule with its callback as a map
+func encodeMsgpackSchedule(encoder *msgpack.Encoder, v reflect.Value) error {
+	document := msgpackSchedule{Schedule: v.Interface().(sch.Schedule)}
+	if len(document.CallbackRaw) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(document.CallbackRaw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document.Callback); err != nil {
+			return err
+		}
+	}
+	return encoder.Encode(document)
+}
+
+// decodeMsgpackSchedule decodes a schedule whose callback is a map, the callback being parsed from its JSON document
+// as the callback of a JSON schedule is
+func decodeMsgpackSchedule(decoder *msgpack.Decoder, v reflect.Value) error {
+	var document msgpackSchedule
+	if err := decoder.Decode(&document); err != nil {
+		return err
+	}
+
+	if document.Callback != nil {
+		raw, err := json.Marshal(document.Callback)
+		if err != nil {
+			return fmt.Errorf("invalid callback: %s", err.Error())
+		}
+		document.CallbackRaw = raw
+		if document.Schedule.Callback, err = sch.ParseCallback(raw); err != nil {
+			return err
+		}
+	}
+	v.Set(reflect.ValueOf(document.Schedule))
+	return nil
+}
+
+// encodeMsgpackNumber encodes the numbers of the JSON documents embedded in the schedules, such as their callbacks,
+// as integers when they are whole
+func encodeMsgpackNumber(encoder *msgpack.Encoder, v reflect.Value) error {
+	number := json.Number(v.String())
+	if i, err := number.Int64(); err == nil {
+		return encoder.EncodeInt(i)
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeFloat64(f)
+}
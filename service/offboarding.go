@@ -0,0 +1,324 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Items of an offboarding job, the days of the partitions are added as <prefix>/<partition>/<day>
+const (
+	deactivateItem = "deactivate"
+	recurringItem  = "recurring"
+	pendingItem    = "pending"
+	historyItem    = "history"
+	appItem        = "app"
+	dayLayout      = "2006-01-02"
+)
+
+// offboardingCheckInterval is how often the cancellation of an offboarding job is checked while its schedules drain
+var offboardingCheckInterval = time.Minute
+
+// OffboardApp offboards an app: the creation and the update of its schedules are rejected at once, and a job
+// cancels its pending schedules, right away or once they drained over the grace period, and deactivates it.
+// With purge the job then deletes the app along with its schedules and their history.
+// The progress of the job is returned by the get job API.
+func (s *Service) OffboardApp(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.recordRequestAppStatus(constants.OffboardApp, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	var input store.OffboardRequest
+	if err = json.Unmarshal(b, &input); err != nil {
+		s.recordRequestAppStatus(constants.OffboardApp, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	if err = input.Validate(); err != nil {
+		s.recordRequestAppStatus(constants.OffboardApp, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	job, err := s.StartOffboarding(appId, input, b)
+	if err != nil {
+		s.recordRequestAppStatus(constants.OffboardApp, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	log.Infof("Started job %s offboarding app %s with policy %s", job.JobId, appId, input.Policy)
+	s.recordRequestAppStatus(constants.OffboardApp, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: job.Total}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: JobData{Job: job}})
+}
+
+// StartOffboarding records the offboarding of an app, so that its schedules can no longer be created or updated,
+// and runs its job in the background
+func (s *Service) StartOffboarding(appId string, input store.OffboardRequest, request []byte) (store.Job, error) {
+	app, err := s.getActiveOrInactiveApp(appId)
+	if err != nil {
+		return store.Job{}, err
+	}
+
+	if err = s.checkNotOffboarding(app); err != nil {
+		return store.Job{}, err
+	}
+
+	if !app.Active && input.Policy == store.DrainPolicy {
+		return store.Job{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("app %s is deactivated, its schedules cannot be drained", appId))
+	}
+
+	now := time.Now()
+	job := store.NewJob(store.OffboardJob, appId, request, 0, now)
+	if err = s.ScheduleDao.UpsertJob(job, s.Config.RetentionConfig.JobRetentionPeriod); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	offboarding := store.NewOffboarding(input, job.JobId, now)
+	if err = s.ClusterDao.UpdateAppOffboarding(appId, &offboarding); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	s.Supervisor.UpdateApp(appId)
+
+	app.Offboarding = &offboarding
+	go s.offboard(app, job)
+	return job, nil
+}
+
+// checkNotOffboarding fails if the app is being offboarded by a job which is still running.
+// An app whose offboarding job finished, or expired, can be offboarded again.
+func (s *Service) checkNotOffboarding(app store.App) error {
+	if app.Offboarding == nil {
+		return nil
+	}
+
+	job, err := s.ScheduleDao.GetJob(app.Offboarding.JobId)
+	switch {
+	case err == gocql.ErrNotFound:
+		return nil
+	case err != nil:
+		return er.NewError(er.DataFetchFailure, err)
+	case !job.Finished():
+		return er.NewError(er.Conflict, fmt.Errorf("app %s is being offboarded by job %s", app.AppId, job.JobId))
+	default:
+		return nil
+	}
+}
+
+// offboard waits for the pending schedules of the app to drain until the grace period ends, then runs the job
+// cancelling, or purging, them. The job stops while waiting if its cancellation is requested.
+func (s *Service) offboard(app store.App, job store.Job) {
+	for wait := time.Until(app.Offboarding.GraceEnd()); wait > 0; wait = time.Until(app.Offboarding.GraceEnd()) {
+		if wait > offboardingCheckInterval {
+			wait = offboardingCheckInterval
+		}
+		time.Sleep(wait)
+
+		if s.jobCancelRequested(job.JobId) {
+			job.Status = store.JobCancelled
+			job.UpdatedAt = time.Now().UnixNano() / int64(time.Millisecond)
+			if err := s.ScheduleDao.UpsertJob(job, s.Config.RetentionConfig.JobRetentionPeriod); err != nil {
+				logger.Errorf("Error: %s while cancelling job %s", err.Error(), job.JobId)
+			}
+			logger.Infof("Job %s offboarding app %s is cancelled before the end of the grace period", job.JobId, app.AppId)
+			return
+		}
+	}
+
+	items := s.offboardingItems(app, app.Offboarding.Purge, time.Now())
+	job.Total = len(items)
+	s.runJob(job, items, s.offboardingProcessor(app, app.Offboarding.Purge))
+}
+
+// offboardingItems lists the steps of the offboarding of an app in the order they run.
+// The app is deactivated first so that none of its schedules fire any more, then its recurring schedules and the
+// days of its partitions holding pending schedules are cancelled. With purge the days holding fired schedules are
+// purged too, and the app is deleted last.
+func (s *Service) offboardingItems(app store.App, purge bool, now time.Time) []string {
+	prefix := pendingItem
+	from := now.UTC().Truncate(24 * time.Hour)
+	if purge {
+		prefix = historyItem
+		from = from.Add(-time.Duration(app.GetBufferTTL(s.Config.AppLevelConfiguration.FiredScheduleRetentionPeriod)) * time.Second)
+	}
+	to := now.Add(time.Duration(app.GetMaxTTL(s.Config.AppLevelConfiguration.FutureScheduleCreationPeriod)) * time.Second)
+
+	items := []string{deactivateItem, recurringItem}
+	for day := from; !day.After(to); day = day.Add(24 * time.Hour) {
+		for partition := 0; partition < int(app.Partitions); partition++ {
+			items = append(items, fmt.Sprintf("%s/%d/%s", prefix, partition, day.Format(dayLayout)))
+		}
+	}
+	if purge {
+		items = append(items, appItem)
+	}
+	return items
+}
+
+// offboardingProcessor runs the steps of the offboarding of an app one by one
+func (s *Service) offboardingProcessor(app store.App, purge bool) jobProcessor {
+	return func(itemId string) store.JobItem {
+		item := store.JobItem{ItemId: itemId, Status: store.Success}
+
+		var err error
+		switch parts := strings.Split(itemId, "/"); {
+		case itemId == deactivateItem:
+			err = s.deactivateOffboardedApp(app.AppId)
+		case itemId == recurringItem:
+			err = s.cancelRecurringSchedules(app.AppId, purge)
+		case itemId == appItem:
+			err = s.deleteOffboardedApp(app)
+		case len(parts) == 3 && (parts[0] == pendingItem || parts[0] == historyItem):
+			err = s.clearDay(app, parts[0] == historyItem, parts[1], parts[2], time.Now())
+		default:
+			err = fmt.Errorf("unknown step %s", itemId)
+		}
+
+		if err != nil {
+			item.Status = store.Failure
+			item.Error = err.Error()
+		}
+		return item
+	}
+}
+
+// deactivateOffboardedApp stops the pollers of the app, unless it is already deactivated.
+// Stopping the pollers panics when a node cannot be reached, which fails the step instead of the node running it.
+func (s *Service) deactivateOffboardedApp(appId string) (err error) {
+	app, err := s.getActiveOrInactiveApp(appId)
+	if err != nil || !app.Active {
+		return err
+	}
+
+	if err = s.ClusterDao.UpdateAppActiveStatus(appId, false); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("stopping the pollers of app %s: %v", appId, r)
+		}
+	}()
+	s.Supervisor.DeactivateApp(app)
+	return nil
+}
+
+// cancelRecurringSchedules deletes the recurring schedules of the app along with their future runs.
+// With purge they are removed along with their runs and versions instead.
+func (s *Service) cancelRecurringSchedules(appId string, purge bool) error {
+	recurring, errs := s.ScheduleDao.GetCronSchedulesByApp(appId, "")
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, ","))
+	}
+
+	var failed int
+	var err error
+	for _, schedule := range recurring {
+		switch {
+		case schedule.AppId != appId:
+			continue
+		case purge:
+			err = s.ScheduleDao.PurgeRecurringSchedule(schedule)
+		case schedule.Status != store.Deleted:
+			_, err = s.ScheduleDao.DeleteSchedule(schedule.ScheduleId)
+		default:
+			continue
+		}
+
+		if err != nil {
+			logger.Errorf("Error: %s while cancelling recurring schedule %s of app %s", err.Error(), schedule.ScheduleId, appId)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d recurring schedules could not be cancelled", failed, len(recurring))
+	}
+	return nil
+}
+
+// clearDay cancels the pending schedules of a day of a partition of the app, from the current minute on.
+// With purge every schedule of the day is removed along with its history instead.
+func (s *Service) clearDay(app store.App, purge bool, partition string, day string, now time.Time) error {
+	partitionId, err := strconv.Atoi(partition)
+	if err != nil {
+		return fmt.Errorf("invalid partition %s", partition)
+	}
+	start, err := time.Parse(dayLayout, day)
+	if err != nil {
+		return fmt.Errorf("invalid day %s", day)
+	}
+	end := start.Add(24 * time.Hour)
+
+	if !purge && start.Before(now) {
+		start = now.Truncate(time.Minute)
+	}
+
+	for bucket := start; bucket.Before(end); bucket = bucket.Add(time.Minute) {
+		if purge {
+			_, err = s.ScheduleDao.PurgeBucket(app.AppId, partitionId, bucket)
+		} else {
+			err = s.ScheduleDao.BulkAction(app, partitionId, bucket, []store.Status{store.Scheduled}, store.Delete)
+		}
+		if err != nil {
+			return fmt.Errorf("bucket %s: %w", bucket.Format(time.RFC3339), err)
+		}
+	}
+	return nil
+}
+
+// deleteOffboardedApp removes what is left of the app once its schedules are purged: the statuses of its schedules,
+// its due runs, the entities of its partitions, its rollups, its callback templates and verified urls and the app
+// itself
+func (s *Service) deleteOffboardedApp(app store.App) error {
+	if err := s.ScheduleDao.PurgeAppRecords(app); err != nil {
+		return err
+	}
+	if err := s.ClusterDao.DeleteApp(app); err != nil {
+		return err
+	}
+
+	s.Supervisor.UpdateApp(app.AppId)
+	logger.Infof("App %s is deleted", app.AppId)
+	return nil
+}
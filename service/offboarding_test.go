@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/dao"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+// MockClusterDaoForOffboarding returns its app and records the offboardings
+type MockClusterDaoForOffboarding struct {
+	dao.DummyClusterDaoImpl
+	app          store.App
+	mu           sync.Mutex
+	offboardings []*store.Offboarding
+	deleted      []string
+}
+
+func (m *MockClusterDaoForOffboarding) GetApp(appName string) (store.App, error) {
+	if appName != m.app.AppId {
+		return store.App{}, gocql.ErrNotFound
+	}
+	return m.app, nil
+}
+
+func (m *MockClusterDaoForOffboarding) UpdateAppOffboarding(appName string, offboarding *store.Offboarding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.offboardings = append(m.offboardings, offboarding)
+	return nil
+}
+
+func (m *MockClusterDaoForOffboarding) DeleteApp(app store.App) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, app.AppId)
+	return nil
+}
+
+// MockScheduleDaoForOffboarding records the jobs and the buckets cancelled or purged
+type MockScheduleDaoForOffboarding struct {
+	dao.DummyScheduleDaoImpl
+	job       store.Job
+	recurring []store.Schedule
+	mu        sync.Mutex
+	items     []store.JobItem
+	cancelled []time.Time
+	purged    []time.Time
+	deleted   []gocql.UUID
+}
+
+func (m *MockScheduleDaoForOffboarding) GetJob(uuid gocql.UUID) (store.Job, error) {
+	if uuid != m.job.JobId {
+		return store.Job{}, gocql.ErrNotFound
+	}
+	return m.job, nil
+}
+
+func (m *MockScheduleDaoForOffboarding) CreateJobItem(item store.JobItem, ttl int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = append(m.items, item)
+	return nil
+}
+
+func (m *MockScheduleDaoForOffboarding) GetCronSchedulesByApp(appId string, status store.Status) ([]store.Schedule, []string) {
+	return m.recurring, nil
+}
+
+func (m *MockScheduleDaoForOffboarding) DeleteSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, uuid)
+	return store.Schedule{}, nil
+}
+
+func (m *MockScheduleDaoForOffboarding) BulkAction(app store.App, partitionId int, scheduleTimeGroup time.Time, status []store.Status, actionType store.ActionType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if actionType == store.Delete && len(status) == 1 && status[0] == store.Scheduled {
+		m.cancelled = append(m.cancelled, scheduleTimeGroup)
+	}
+	return nil
+}
+
+func (m *MockScheduleDaoForOffboarding) PurgeBucket(appId string, partitionId int, timeBucket time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.purged = append(m.purged, timeBucket)
+	return 0, nil
+}
+
+func setupMocksForOffboarding(app store.App, job store.Job) (*Service, *MockClusterDaoForOffboarding, *MockScheduleDaoForOffboarding) {
+	service := setupMocks()
+	clusterDao := &MockClusterDaoForOffboarding{app: app}
+	scheduleDao := &MockScheduleDaoForOffboarding{job: job}
+	service.ClusterDao = clusterDao
+	service.ScheduleDao = scheduleDao
+	return service, clusterDao, scheduleDao
+}
+
+func TestService_OffboardApp(t *testing.T) {
+	running := store.NewJob(store.OffboardJob, "offboarding", nil, 0, time.Now())
+	tests := []struct {
+		name       string
+		app        store.App
+		body       string
+		wantStatus int
+	}{
+		{"MalformedJSON", store.App{AppId: "testApp", Active: true}, `{bad json`, http.StatusBadRequest},
+		{"InvalidPolicy", store.App{AppId: "testApp", Active: true}, `{"policy":"keep"}`, http.StatusBadRequest},
+		{"DrainWithoutGracePeriod", store.App{AppId: "testApp", Active: true}, `{"policy":"drain"}`, http.StatusBadRequest},
+		{"AppNotRegistered", store.App{AppId: "otherApp", Active: true}, `{"policy":"cancel"}`, http.StatusBadRequest},
+		{"DrainDeactivated", store.App{AppId: "testApp"}, `{"policy":"drain","gracePeriodMinutes":10}`, http.StatusUnprocessableEntity},
+		{"AlreadyOffboarding", store.App{AppId: "testApp", Active: true, Offboarding: &store.Offboarding{JobId: running.JobId}}, `{"policy":"cancel"}`, http.StatusConflict},
+		{"Drain", store.App{AppId: "testApp", Active: true, Partitions: 1}, `{"policy":"drain","gracePeriodMinutes":10}`, http.StatusOK},
+		{"CancelDeactivated", store.App{AppId: "testApp", Partitions: 1}, `{"policy":"cancel","purge":true}`, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service, clusterDao, _ := setupMocksForOffboarding(tc.app, running)
+
+			req, err := http.NewRequest("POST", "/goscheduler/apps/{appId}/offboard", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"appId": "testApp"})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.OffboardApp).ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %v, want %v, body: %s", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response JobResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Data.Job.Type != store.OffboardJob || response.Data.Job.Status != store.JobRunning {
+				t.Errorf("unexpected job %+v", response.Data.Job)
+			}
+
+			clusterDao.mu.Lock()
+			defer clusterDao.mu.Unlock()
+			if len(clusterDao.offboardings) == 0 || clusterDao.offboardings[0].JobId != response.Data.Job.JobId {
+				t.Errorf("expected the offboarding of the job to be recorded, got %+v", clusterDao.offboardings)
+			}
+		})
+	}
+}
+
+func TestService_RunOffboarding(t *testing.T) {
+	app := store.App{AppId: "testApp", Partitions: 2, Configuration: store.Configuration{FutureScheduleCreationPeriod: 1, FiredScheduleRetentionPeriod: 1}}
+	recurring := []store.Schedule{
+		{ScheduleId: gocql.TimeUUID(), AppId: "testApp", CronExpression: "0 0 * * *", Status: store.Scheduled},
+		{ScheduleId: gocql.TimeUUID(), AppId: "testApp", CronExpression: "0 0 * * *", Status: store.Deleted},
+	}
+	now := time.Now()
+
+	t.Run("Cancel", func(t *testing.T) {
+		service, clusterDao, scheduleDao := setupMocksForOffboarding(app, store.Job{})
+		scheduleDao.recurring = recurring
+
+		items := service.offboardingItems(app, false, now)
+		// deactivate, recurring and today and tomorrow of both partitions, sometimes the day after tomorrow too
+		if len(items) < 6 || items[0] != deactivateItem || items[1] != recurringItem || !strings.HasPrefix(items[2], pendingItem+"/0/") {
+			t.Fatalf("unexpected steps %v", items)
+		}
+
+		job := store.NewJob(store.OffboardJob, app.AppId, nil, len(items), now)
+		job = service.runJob(job, items, service.offboardingProcessor(app, false))
+
+		if job.Status != store.JobCompleted || job.Failed != 0 || job.Succeeded != len(items) {
+			t.Errorf("expected every step to succeed, got %+v, items %+v", job, scheduleDao.items)
+		}
+		if len(scheduleDao.deleted) != 1 || scheduleDao.deleted[0] != recurring[0].ScheduleId {
+			t.Errorf("expected the recurring schedule which is not deleted to be deleted, got %v", scheduleDao.deleted)
+		}
+		if len(scheduleDao.cancelled) == 0 || scheduleDao.cancelled[0].Before(now.Truncate(time.Minute)) {
+			t.Errorf("expected the pending schedules to be cancelled from the current minute on, got %d buckets", len(scheduleDao.cancelled))
+		}
+		if len(scheduleDao.purged) != 0 || len(clusterDao.deleted) != 0 {
+			t.Errorf("expected nothing to be purged, got %d buckets and apps %v", len(scheduleDao.purged), clusterDao.deleted)
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		service, clusterDao, scheduleDao := setupMocksForOffboarding(app, store.Job{})
+
+		items := service.offboardingItems(app, true, now)
+		if items[len(items)-1] != appItem || !strings.HasPrefix(items[2], historyItem+"/0/") {
+			t.Fatalf("unexpected steps %v", items)
+		}
+
+		job := store.NewJob(store.OffboardJob, app.AppId, nil, len(items), now)
+		job = service.runJob(job, items, service.offboardingProcessor(app, true))
+
+		if job.Status != store.JobCompleted || job.Failed != 0 {
+			t.Errorf("expected every step to succeed, got %+v", job)
+		}
+		// every minute of every day of both partitions
+		if len(scheduleDao.purged) != (len(items)-3)*24*60 {
+			t.Errorf("expected every bucket to be purged, got %d", len(scheduleDao.purged))
+		}
+		if len(clusterDao.deleted) != 1 {
+			t.Errorf("expected the app to be deleted, got %v", clusterDao.deleted)
+		}
+	})
+
+	t.Run("UnknownStep", func(t *testing.T) {
+		service, _, _ := setupMocksForOffboarding(app, store.Job{})
+
+		item := service.offboardingProcessor(app, false)("pending/x/2023-01-01")
+		if item.Status != store.Failure || item.Error == "" {
+			t.Errorf("expected an invalid step to fail, got %+v", item)
+		}
+	})
+}
+
+func TestService_GetOffboardedApp(t *testing.T) {
+	service, _, _ := setupMocksForOffboarding(store.App{AppId: "testApp", Active: true, Offboarding: &store.Offboarding{}}, store.Job{})
+
+	_, err := service.getApp("testApp")
+	if appErr, ok := err.(er.AppError); !ok || appErr.Code != er.DeactivatedApp {
+		t.Errorf("expected the schedules of an app being offboarded to be rejected, got %v", err)
+	}
+}
+
+func TestService_ActivateOffboardedApp(t *testing.T) {
+	running := store.NewJob(store.OffboardJob, "testApp", nil, 0, time.Now())
+	finished := running
+	finished.Status = store.JobCancelled
+
+	tests := []struct {
+		name     string
+		job      store.Job
+		active   bool
+		wantCode int
+	}{
+		{"Running", running, true, er.Conflict},
+		{"DrainCancelled", finished, true, 0},
+		{"Deactivated", finished, false, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app := store.App{AppId: "testApp", Active: tc.active, Offboarding: &store.Offboarding{JobId: running.JobId}}
+			service, clusterDao, _ := setupMocksForOffboarding(app, tc.job)
+
+			err := service.ActivateApp("testApp")
+			if tc.wantCode != 0 {
+				if appErr, ok := err.(er.AppError); !ok || appErr.Code != tc.wantCode {
+					t.Errorf("expected error code %d, got %v", tc.wantCode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(clusterDao.offboardings) != 1 || clusterDao.offboardings[0] != nil {
+				t.Errorf("expected the offboarding to be cleared, got %+v", clusterDao.offboardings)
+			}
+		})
+	}
+}
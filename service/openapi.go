@@ -0,0 +1,791 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gocql/gocql"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	s "github.com/myntra/goscheduler/store"
+)
+
+const openAPIVersion = "3.0.3"
+
+// Route is a registered HTTP route, named after the operation it serves
+type Route struct {
+	Name    string
+	Path    string
+	Methods []string
+}
+
+// fieldsParam selects the fields of the records of the responses of the GET APIs, see FieldSelection
+var fieldsParam = queryParam{"fields", "string", "Comma separated fields of the records of the response to return, e.g. scheduleId,status,nextFireTimes"}
+
+type queryParam struct {
+	name        string
+	kind        string
+	description string
+}
+
+// operationDoc documents an operation, request and response are sample values whose types are converted to schemas
+type operationDoc struct {
+	summary         string
+	tag             string
+	query           []queryParam
+	request         interface{}
+	optionalRequest bool
+	response        interface{}
+}
+
+// errorDescription lists the machine readable codes an error response can carry
+var errorDescription = "Error, the code of the error is one of " + strings.Join(er.Names(), ", ") +
+	" and its details name the fields failing the validation"
+
+var (
+	sizeParam         = queryParam{"size", "integer", "Page size"}
+	continuationParam = queryParam{"continuation_token", "string", "Continuation token of the previous page"}
+	statusParam       = queryParam{"status", "string", "Filter by schedule status"}
+	appIdParam        = queryParam{"app_id", "string", "Filter by app"}
+	startTimeParam    = queryParam{"start_time", "string", "Start of the time range, yyyy-MM-dd HH:mm:ss"}
+	endTimeParam      = queryParam{"end_time", "string", "End of the time range, yyyy-MM-dd HH:mm:ss"}
+	formatParam       = queryParam{"format", "string", "json or ndjson, defaults to json"}
+	probeParam        = queryParam{"probe", "string", "warn or reject, probes the callback urls before creating the schedule"}
+)
+
+// operationDocs documents the operations served by the router, keyed by route name.
+// Routes without an entry are still listed in the spec but without schemas.
+var operationDocs = map[string]operationDoc{
+	constants.HealthCheck: {
+		summary:  "Health check",
+		tag:      "health",
+		response: HealthCheckResponse{},
+	},
+	constants.Readiness: {
+		summary:  "Readiness of the node to take new schedules, 503 while it sheds them",
+		tag:      "health",
+		response: ReadinessResponse{},
+	},
+	constants.GetOpenAPISpec: {
+		summary:  "OpenAPI specification of this API",
+		tag:      "health",
+		response: map[string]interface{}{},
+	},
+	constants.CreateSchedule: {
+		summary:  "Create a one time or recurring schedule",
+		tag:      "schedules",
+		query:    []queryParam{probeParam},
+		request:  s.Schedule{},
+		response: CreateScheduleResponse{},
+	},
+	constants.ValidateSchedule: {
+		summary:  "Validate a schedule and preview its next runs",
+		tag:      "schedules",
+		query:    []queryParam{{"count", "integer", "Number of runs to preview"}},
+		request:  s.Schedule{},
+		response: ValidateScheduleResponse{},
+	},
+	constants.SimulateSchedule: {
+		summary:  "Replay a recurrence over a past or future window and list the times it fires",
+		tag:      "schedules",
+		query:    []queryParam{{"limit", "integer", "Maximum number of runs listed, all of them are counted"}},
+		request:  s.Simulation{},
+		response: SimulateScheduleResponse{},
+	},
+	constants.GetSchedule: {
+		summary:  "Get a schedule",
+		tag:      "schedules",
+		response: GetScheduleResponse{},
+	},
+	constants.GetScheduleRuns: {
+		summary:  "Get the runs of a recurring schedule",
+		tag:      "schedules",
+		query:    []queryParam{sizeParam, continuationParam, {"when", "string", "past or future runs"}},
+		response: GetPaginatedRunSchedulesResponse{},
+	},
+	constants.GetScheduleReceipts: {
+		summary:  "Get the delivery receipts of a schedule",
+		tag:      "schedules",
+		response: GetDeliveryReceiptsResponse{},
+	},
+	constants.GetDueRuns: {
+		summary:  "Lease the due runs of the pull callbacks of an app, waiting for some when there are none",
+		tag:      "apps",
+		query:    []queryParam{sizeParam, {"waitSeconds", "integer", "Seconds to wait for due runs"}, {"leaseSeconds", "integer", "Seconds to acknowledge the runs within"}},
+		response: GetDueRunsResponse{},
+	},
+	constants.AckDueRun: {
+		summary:  "Acknowledge the outcome of a due run",
+		tag:      "apps",
+		request:  s.Acknowledgement{},
+		response: AckDueRunResponse{},
+	},
+	constants.AcquireLock: {
+		summary:  "Acquire or renew the lease of a lock of an app, so that a single replica of a consumer processes a fire",
+		tag:      "locks",
+		request:  s.Lock{},
+		response: LockResponse{},
+	},
+	constants.ReleaseLock: {
+		summary:  "Release a lock of an app held by the owner",
+		tag:      "locks",
+		query:    []queryParam{{"owner", "string", "Owner holding the lock"}},
+		response: LockResponse{},
+	},
+	constants.StreamEvents: {
+		summary:  "Stream the fire events of the runs of an app fired on the node as server-sent events",
+		tag:      "apps",
+		query:    []queryParam{{"types", "string", "Comma separated event types to stream, every fire event type by default"}},
+		response: s.Event{},
+	},
+	constants.GetScheduleAttempts: {
+		summary:  "Get the execution logs of the callback attempts of a schedule, with their bodies redacted",
+		tag:      "schedules",
+		response: GetCallbackAttemptsResponse{},
+	},
+	constants.GetCallbackCanary: {
+		summary:  "Get the canary of the updated callback of a recurring schedule with the comparison of its callbacks",
+		tag:      "schedules",
+		response: GetCallbackCanaryResponse{},
+	},
+	constants.GetScheduleVersions: {
+		summary:  "Get the versions of the definition of a recurring schedule, the current one last",
+		tag:      "schedules",
+		response: GetScheduleVersionsResponse{},
+	},
+	constants.RollbackSchedule: {
+		summary:  "Roll a recurring schedule back to a version of its definition",
+		tag:      "schedules",
+		response: UpdatedScheduleResponse{},
+	},
+	constants.UpdateRecurringSchedule: {
+		summary:  "Update a recurring schedule, deprecated in favour of PUT and PATCH on the schedule",
+		tag:      "schedules",
+		request:  s.Schedule{},
+		response: UpdatedScheduleResponse{},
+	},
+	constants.ReplaceSchedule: {
+		summary:  "Replace a recurring or pending one time schedule",
+		tag:      "schedules",
+		request:  s.Schedule{},
+		response: UpdatedScheduleResponse{},
+	},
+	constants.PatchSchedule: {
+		summary:  "Update a recurring or pending one time schedule with a JSON merge patch",
+		tag:      "schedules",
+		request:  map[string]interface{}{},
+		response: UpdatedScheduleResponse{},
+	},
+	constants.PauseSchedule: {
+		summary:         "Pause a recurring schedule now or at a future time",
+		tag:             "schedules",
+		request:         lifecycleRequest{},
+		optionalRequest: true,
+		response:        ScheduleResponse{},
+	},
+	constants.ResumeSchedule: {
+		summary:         "Resume a paused recurring schedule now or at a future time",
+		tag:             "schedules",
+		request:         lifecycleRequest{},
+		optionalRequest: true,
+		response:        ScheduleResponse{},
+	},
+	constants.ActivateSchedule: {
+		summary:  "Activate a recurring schedule created as a draft",
+		tag:      "schedules",
+		response: ScheduleResponse{},
+	},
+	constants.DeleteSchedule: {
+		summary:  "Delete a schedule",
+		tag:      "schedules",
+		response: DeleteScheduleResponse{},
+	},
+	constants.GetAppSchedule: {
+		summary:  "Get the schedules of an app",
+		tag:      "apps",
+		query:    []queryParam{sizeParam, continuationParam, statusParam, startTimeParam, endTimeParam, {"continuation_start_time", "integer", "Start time of the continuation page"}},
+		response: GetPaginatedAppSchedulesResponse{},
+	},
+	constants.RegisterApp: {
+		summary:  "Register an app",
+		tag:      "apps",
+		request:  s.App{},
+		response: CreateAppResponse{},
+	},
+	constants.GetApps: {
+		summary:  "Get the registered apps",
+		tag:      "apps",
+		query:    []queryParam{appIdParam},
+		response: GetAppsResponse{},
+	},
+	constants.DeactivateApp: {
+		summary:  "Deactivate an app",
+		tag:      "apps",
+		response: UpdateAppActiveStatusResponse{},
+	},
+	constants.ActivateApp: {
+		summary:  "Activate an app",
+		tag:      "apps",
+		response: UpdateAppActiveStatusResponse{},
+	},
+	constants.ResizeAppPartitions: {
+		summary:  "Increase the partition count of an app and migrate its pending schedules in the background",
+		tag:      "apps",
+		request:  resizeRequest{},
+		response: PartitionMigrationResponse{},
+	},
+	constants.ApplySchedules: {
+		summary:  "Create, update and delete the declared recurring schedules of an app to match the desired set",
+		tag:      "schedules",
+		query:    []queryParam{{"dry_run", "boolean", "Return the changes without applying them"}},
+		request:  ApplySchedulesRequest{},
+		response: ApplySchedulesResponse{},
+	},
+	constants.GetPartitionMigration: {
+		summary:  "Get the progress of the latest partition migration of an app",
+		tag:      "apps",
+		response: PartitionMigrationResponse{},
+	},
+	constants.ExportSchedules: {
+		summary:  "Export the recurring and pending one time schedules of an app as a snapshot",
+		tag:      "apps",
+		query:    []queryParam{formatParam, {"days", "integer", "Days ahead to export the one time schedules of, defaults to the furthest a schedule can be created at"}},
+		response: ScheduleSnapshot{},
+	},
+	constants.ImportSchedules: {
+		summary:  "Import a snapshot of schedules into an app",
+		tag:      "apps",
+		query:    []queryParam{formatParam, {"conflict", "string", "skip, overwrite or fail when a schedule with the same id exists, defaults to skip"}, {"remap_ids", "boolean", "Create the schedules with new ids"}},
+		request:  ScheduleSnapshot{},
+		response: ImportSchedulesResponse{},
+	},
+	constants.GetAppUsage: {
+		summary:  "Get the schedules created, callbacks fired, bytes delivered and retries of an app per day or month",
+		tag:      "apps",
+		query:    []queryParam{{"granularity", "string", "daily or monthly, defaults to daily"}, {"from", "string", "First day of the report as YYYY-MM-DD, defaults to 30 days or 12 months before to"}, {"to", "string", "Last day of the report as YYYY-MM-DD, defaults to today"}},
+		response: AppUsageResponse{},
+	},
+	constants.GetAppConnections: {
+		summary:  "Get the reuse of the connections of the http callbacks of an app by host on the node serving the request",
+		tag:      "apps",
+		response: AppConnectionsResponse{},
+	},
+	constants.TuneAppConnections: {
+		summary:  "Resize the connection pool of the client of the http callbacks of an app",
+		tag:      "apps",
+		request:  s.ConnectionTuning{},
+		response: AppConnectionsResponse{},
+	},
+	constants.GetAppRunStats: {
+		summary:  "Get the successful, failed and retried runs of an app per hour or day",
+		tag:      "apps",
+		query:    []queryParam{{"groupBy", "string", "hour or day, defaults to hour"}, {"from", "string", "Start of the report as YYYY-MM-DD HH:MM:SS in UTC, defaults to 24 hours or 30 days before to"}, {"to", "string", "End of the report as YYYY-MM-DD HH:MM:SS in UTC, defaults to now"}},
+		response: AppRunStatsResponse{},
+	},
+	constants.GraphQLQuery: {
+		summary:  "Execute a GraphQL query over the schedules, runs, versions, callback attempts and apps",
+		tag:      "graphql",
+		request:  graphQLRequest{},
+		response: graphql.Response{},
+	},
+	constants.GetGraphQLSchema: {
+		summary:  "Get the schema of the GraphQL queries in the schema definition language",
+		tag:      "graphql",
+		response: GraphQLSchemaResponse{},
+	},
+	constants.CreateCallbackTemplate: {
+		summary:  "Create a callback template of an app, which schedules reference with a template callback",
+		tag:      "templates",
+		request:  templateRequest{},
+		response: CallbackTemplateResponse{},
+	},
+	constants.GetCallbackTemplates: {
+		summary:  "Get the latest version of the callback templates of an app",
+		tag:      "templates",
+		response: CallbackTemplatesResponse{},
+	},
+	constants.UpdateCallbackTemplate: {
+		summary:  "Create the next version of a callback template, fired by the schedules referencing the latest version",
+		tag:      "templates",
+		request:  templateRequest{},
+		response: CallbackTemplateResponse{},
+	},
+	constants.GetCallbackTemplate: {
+		summary:  "Get a version of a callback template, the latest one by default",
+		tag:      "templates",
+		query:    []queryParam{{"version", "integer", "Version of the template, defaults to the latest"}},
+		response: CallbackTemplateResponse{},
+	},
+	constants.CreateScheduleGroup: {
+		summary:  "Create a group of an app, which recurring schedules join with their group field",
+		tag:      "groups",
+		request:  groupRequest{},
+		response: ScheduleGroupResponse{},
+	},
+	constants.GetScheduleGroups: {
+		summary:  "Get the groups of an app",
+		tag:      "groups",
+		response: ScheduleGroupsResponse{},
+	},
+	constants.UpdateScheduleGroup: {
+		summary:  "Update the description and the limit of runs per minute of a group",
+		tag:      "groups",
+		request:  groupRequest{},
+		response: ScheduleGroupResponse{},
+	},
+	constants.GetScheduleGroup: {
+		summary:  "Report on the schedules of a group by status along with their latest run",
+		tag:      "groups",
+		response: GroupReportResponse{},
+	},
+	constants.DeleteScheduleGroup: {
+		summary:  "Delete a group and start a job deleting its schedules",
+		tag:      "groups",
+		response: JobResponse{},
+	},
+	constants.PauseScheduleGroup: {
+		summary:  "Start a job pausing the schedules of a group",
+		tag:      "groups",
+		response: JobResponse{},
+	},
+	constants.ResumeScheduleGroup: {
+		summary:  "Start a job resuming the paused schedules of a group",
+		tag:      "groups",
+		response: JobResponse{},
+	},
+	constants.GetSuspendedSchedules: {
+		summary:  "Get the recurring schedules of an app suspended because their callback host was unreachable",
+		tag:      "schedules",
+		response: GetCronSchedulesResponse{},
+	},
+	constants.ResumeSuspendedSchedules: {
+		summary:         "Start a job resuming the suspended schedules of an app, all of them without schedule ids",
+		tag:             "schedules",
+		request:         s.ResumeSuspendedRequest{},
+		optionalRequest: true,
+		response:        JobResponse{},
+	},
+	constants.GetApprovals: {
+		summary:  "Get the destructive operations of an app held for approval, latest first",
+		tag:      "approvals",
+		query:    []queryParam{{"status", "string", "Status of the approvals, e.g. PENDING"}},
+		response: ApprovalsResponse{},
+	},
+	constants.GetApproval: {
+		summary:  "Get a destructive operation held for approval along with its audit trail",
+		tag:      "approvals",
+		response: ApprovalResponse{},
+	},
+	constants.ApproveRequest: {
+		summary:  "Approve a destructive operation requested by another principal, which is then executed",
+		tag:      "approvals",
+		request:  decisionRequest{},
+		response: ApprovalResponse{},
+	},
+	constants.RejectRequest: {
+		summary:  "Reject a destructive operation held for approval, or withdraw it as its requester",
+		tag:      "approvals",
+		request:  decisionRequest{},
+		response: ApprovalResponse{},
+	},
+	constants.VerifyCallbackUrl: {
+		summary:  "Verify a callback url of an app by sending it a challenge it has to echo",
+		tag:      "apps",
+		request:  verifyUrlRequest{},
+		response: VerifiedUrlResponse{},
+	},
+	constants.GetVerifiedUrls: {
+		summary:  "Get the verified callback urls of an app",
+		tag:      "apps",
+		response: VerifiedUrlsResponse{},
+	},
+	constants.DeleteVerifiedUrl: {
+		summary:  "Revoke the verification of a callback url of an app",
+		tag:      "apps",
+		query:    []queryParam{{"url", "string", "Callback url to revoke"}},
+		response: VerifiedUrlResponse{},
+	},
+	constants.BulkAction: {
+		summary:  "Reconcile or delete the schedules of an app in a time range",
+		tag:      "bulk",
+		query:    []queryParam{statusParam, startTimeParam, endTimeParam},
+		response: BulkActionResponse{},
+	},
+	constants.GetCronSchedule: {
+		summary:  "Get the recurring schedules",
+		tag:      "schedules",
+		query:    []queryParam{appIdParam, statusParam, {"health", "string", "healthy, degraded or failing, filters by the health derived from the recent runs"}},
+		response: GetCronSchedulesResponse{},
+	},
+	constants.GetDiagnostics: {
+		summary:  "Get the slowest queries, the poll lag per partition and the largest partitions of an app",
+		tag:      "admin",
+		query:    []queryParam{appIdParam, {"minutes", "integer", "Number of upcoming minutes counted for the partitions of the app"}},
+		response: GetDiagnosticsResponse{},
+	},
+	constants.GetReplicationStatus: {
+		summary:  "Get the replication role of the cluster and the progress of the replicated event stream",
+		tag:      "admin",
+		response: ReplicationStatusResponse{},
+	},
+	constants.PromoteReplica: {
+		summary:  "Promote a standby cluster to primary so that it starts firing its schedules",
+		tag:      "admin",
+		response: ReplicationStatusResponse{},
+	},
+	constants.GetClock: {
+		summary:  "Get the time of the clock the schedules are fired on by the node",
+		tag:      "admin",
+		response: ClockResponse{},
+	},
+	constants.AdvanceClock: {
+		summary:  "Advance the virtual clock of the node, firing the schedules due on the way",
+		tag:      "admin",
+		request:  AdvanceClockRequest{},
+		response: ClockResponse{},
+	},
+	constants.ReconcileMissedSchedules: {
+		summary: "Report the schedules of an app which were due in a window but never fired, and optionally re-fire them",
+		tag:     "admin",
+		query: []queryParam{
+			startTimeParam,
+			endTimeParam,
+			{"refire", "boolean", "Re-fire the missed schedules, defaults to false"},
+			{"limit", "integer", "Maximum number of missed schedules reported, defaults to 1000"},
+		},
+		response: ReconciliationResponse{},
+	},
+	constants.GetDuplicateFires: {
+		summary: "Report the schedules of an app due in a window whose callback was dispatched more than once",
+		tag:     "admin",
+		query: []queryParam{
+			startTimeParam,
+			endTimeParam,
+			{"limit", "integer", "Maximum number of offending schedules reported, defaults to 1000"},
+		},
+		response: DuplicateFiresResponse{},
+	},
+	constants.GetClusterNodes: {
+		summary:  "Get the health, partitions, poll lag, callback backlog and version of every node of the cluster",
+		tag:      "admin",
+		response: ClusterNodesResponse{},
+	},
+	constants.GetClusterPartitions: {
+		summary:  "Get the node running every partition and the node owning it",
+		tag:      "admin",
+		query:    []queryParam{appIdParam, {"node", "string", "Filter by the node running the partition"}},
+		response: ClusterPartitionsResponse{},
+	},
+	constants.CordonNode: {
+		summary:  "Cordon a node so that no partition is assigned to it, the partitions it runs staying on it",
+		tag:      "admin",
+		response: NodeMaintenanceResponse{},
+	},
+	constants.UncordonNode: {
+		summary:  "Uncordon a node so that partitions are assigned to it again",
+		tag:      "admin",
+		response: NodeMaintenanceResponse{},
+	},
+	constants.DrainNode: {
+		summary:  "Cordon a node and hand the partitions it runs over to the other nodes",
+		tag:      "admin",
+		response: DrainNodeResponse{},
+	},
+	constants.BulkUpdateSchedules: {
+		summary:  "Apply a JSON merge patch to the recurring schedules of an app matching a filter in the background",
+		tag:      "bulk",
+		request:  s.BulkUpdateRequest{},
+		response: JobResponse{},
+	},
+	constants.CloneApp: {
+		summary:  "Register a new app with the partitions, the configuration and the callback templates of an app",
+		tag:      "apps",
+		request:  s.CloneAppRequest{},
+		response: CloneAppResponse{},
+	},
+	constants.GetShadowReport: {
+		summary:  "Get the writes mirrored to the shadow cluster, the reads compared with it and the differences found",
+		tag:      "admin",
+		response: ShadowReportResponse{},
+	},
+	constants.ReconcileShadow: {
+		summary:  "Replay on the shadow cluster the writes which failed on it",
+		tag:      "admin",
+		response: ShadowReportResponse{},
+	},
+	constants.OffboardApp: {
+		summary:  "Stop the creation of the schedules of an app, drain or cancel its pending schedules, deactivate it and optionally purge it, in the background",
+		tag:      "apps",
+		request:  s.OffboardRequest{},
+		response: JobResponse{},
+	},
+	constants.GetJob: {
+		summary:  "Get the progress of an asynchronous job and the results of the items it has processed",
+		tag:      "jobs",
+		response: JobResponse{},
+	},
+	constants.CancelJob: {
+		summary:  "Stop a running job once the chunk of items it is processing is done",
+		tag:      "jobs",
+		response: JobResponse{},
+	},
+	constants.RetryJob: {
+		summary:  "Run a finished job again over the items which failed",
+		tag:      "jobs",
+		response: JobResponse{},
+	},
+}
+
+// callbackSchema documents the raw callback of a schedule, whose details depend on the callback type
+var callbackSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"type", "details"},
+	"properties": map[string]interface{}{
+		"type":    map[string]interface{}{"type": "string", "example": constants.DefaultCallback},
+		"details": map[string]interface{}{"type": "object", "additionalProperties": true},
+	},
+}
+
+var pathParamPattern = regexp.MustCompile(`{([^}:]+)(:[^}]+)?}`)
+
+// OpenAPISpec generates the OpenAPI 3 specification of the given routes
+func OpenAPISpec(routes []Route) ([]byte, error) {
+	g := schemaGenerator{
+		components: map[string]interface{}{"Callback": callbackSchema},
+		types:      map[string]reflect.Type{},
+	}
+	paths := map[string]interface{}{}
+
+	for _, route := range routes {
+		path := pathParamPattern.ReplaceAllString(route.Path, "{$1}")
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+
+		for _, method := range route.Methods {
+			doc := operationDocs[route.Name]
+			if method == http.MethodGet {
+				doc.query = append(doc.query[:len(doc.query):len(doc.query)], fieldsParam)
+			}
+			item[strings.ToLower(method)] = g.operation(route, doc)
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "goscheduler",
+			"version": "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.components},
+	})
+}
+
+func (g *schemaGenerator) operation(route Route, doc operationDoc) map[string]interface{} {
+	operation := map[string]interface{}{
+		"operationId": route.Name,
+		"summary":     doc.summary,
+		"responses": map[string]interface{}{
+			"200":     g.content("OK", doc.response),
+			"default": g.content(errorDescription, er.ErrorResponse{}),
+		},
+	}
+	if doc.summary == "" {
+		operation["summary"] = route.Name
+	}
+	if doc.tag != "" {
+		operation["tags"] = []string{doc.tag}
+	}
+	if doc.request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": !doc.optionalRequest,
+			"content":  g.body(doc.request),
+		}
+	}
+
+	var parameters []interface{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, param := range doc.query {
+		parameters = append(parameters, map[string]interface{}{
+			"name":        param.name,
+			"in":          "query",
+			"description": param.description,
+			"schema":      map[string]interface{}{"type": param.kind},
+		})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	return operation
+}
+
+// content describes a response with a JSON body of the type of the sample value
+func (g *schemaGenerator) content(description string, sample interface{}) map[string]interface{} {
+	response := map[string]interface{}{"description": description}
+	if sample != nil {
+		response["content"] = g.body(sample)
+	}
+	return response
+}
+
+func (g *schemaGenerator) body(sample interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		constants.ApplicationJson: map[string]interface{}{"schema": g.schema(reflect.TypeOf(sample))},
+	}
+}
+
+// schemaGenerator converts go types to JSON schemas following their json tags,
+// named structs are added to the components and referenced
+type schemaGenerator struct {
+	components map[string]interface{}
+	types      map[string]reflect.Type
+}
+
+var (
+	uuidType       = reflect.TypeOf(gocql.UUID{})
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object"}
+	if t.Name() == "" {
+		g.addProperties(schema, t)
+		return schema
+	}
+
+	name := g.componentName(t)
+	ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	if _, ok := g.components[name]; !ok {
+		// reserve the name before walking the fields so recursive types terminate
+		g.components[name] = schema
+		g.addProperties(schema, t)
+	}
+	return ref
+}
+
+func (g *schemaGenerator) addProperties(schema map[string]interface{}, t reflect.Type) {
+	properties := map[string]interface{}{}
+	g.collectProperties(properties, t)
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+}
+
+// collectProperties adds the schemas of the marshalled fields of the struct to properties
+func (g *schemaGenerator) collectProperties(properties map[string]interface{}, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		// the fields of an embedded struct are marshalled as fields of the struct embedding it
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			g.collectProperties(properties, field.Type)
+			continue
+		}
+
+		jsonName := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			jsonName = strings.Split(tag, ",")[0]
+		}
+		switch {
+		case jsonName == "-":
+			continue
+		case jsonName == "":
+			jsonName = field.Name
+		}
+
+		if field.Type == rawMessageType && jsonName == "callback" {
+			properties[jsonName] = map[string]interface{}{"$ref": "#/components/schemas/Callback"}
+			continue
+		}
+		properties[jsonName] = g.schema(field.Type)
+	}
+}
+
+// componentName names the schema of a struct after its type, prefixed with its package if another
+// package already used the name
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+
+	if other, ok := g.types[string(name)]; ok && other != t {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = append([]rune(strings.ToUpper(pkg[:1])+pkg[1:]), name...)
+	}
+	g.types[string(name)] = t
+	return string(name)
+}
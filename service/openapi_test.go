@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/myntra/goscheduler/constants"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	routes := []Route{
+		{Name: constants.CreateSchedule, Path: "/goscheduler/schedules", Methods: []string{"POST"}},
+		{Name: constants.GetSchedule, Path: "/goscheduler/schedules/{scheduleId}", Methods: []string{"GET"}},
+		{Name: constants.DeleteSchedule, Path: "/goscheduler/schedules/{scheduleId}", Methods: []string{"DELETE"}},
+		{Name: constants.GetScheduleRuns, Path: "/goscheduler/schedules/{scheduleId}/runs", Methods: []string{"GET"}},
+		{Name: "undocumented", Path: "/goscheduler/undocumented/{id:[0-9]+}", Methods: []string{"GET"}},
+	}
+
+	b, err := OpenAPISpec(routes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationId string                            `json:"operationId"`
+			Summary     string                            `json:"summary"`
+			Parameters  []map[string]interface{}          `json:"parameters"`
+			RequestBody map[string]interface{}            `json:"requestBody"`
+			Responses   map[string]map[string]interface{} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatalf("Invalid spec JSON: %v", err)
+	}
+
+	if spec.OpenAPI != openAPIVersion {
+		t.Errorf("Expected openapi %s, got %s", openAPIVersion, spec.OpenAPI)
+	}
+
+	schedule := spec.Paths["/goscheduler/schedules/{scheduleId}"]
+	if schedule["get"].OperationId != constants.GetSchedule || schedule["delete"].OperationId != constants.DeleteSchedule {
+		t.Errorf("Expected get and delete operations on the schedule path, got %+v", schedule)
+	}
+	if params := schedule["get"].Parameters; len(params) != 2 || params[0]["name"] != "scheduleId" || params[0]["in"] != "path" || params[1]["name"] != "fields" {
+		t.Errorf("Expected scheduleId path parameter and fields query parameter, got %+v", params)
+	}
+	if params := schedule["delete"].Parameters; len(params) != 1 {
+		t.Errorf("Expected only the scheduleId path parameter on delete, got %+v", params)
+	}
+
+	if create := spec.Paths["/goscheduler/schedules"]["post"]; create.RequestBody == nil || create.Responses["default"] == nil {
+		t.Errorf("Expected request body and error response for create, got %+v", create)
+	}
+
+	if params := spec.Paths["/goscheduler/schedules/{scheduleId}/runs"]["get"].Parameters; len(params) != 5 {
+		t.Errorf("Expected path, 3 query parameters and fields for runs, got %+v", params)
+	}
+
+	undocumented, ok := spec.Paths["/goscheduler/undocumented/{id}"]["get"]
+	if !ok || undocumented.Summary != "undocumented" {
+		t.Errorf("Expected undocumented route with its pattern stripped, got %+v", spec.Paths)
+	}
+
+	properties := spec.Components.Schemas["Schedule"].Properties
+	if properties["scheduleId"]["format"] != "uuid" {
+		t.Errorf("Expected scheduleId to be a uuid, got %+v", properties["scheduleId"])
+	}
+	if properties["callback"]["$ref"] != "#/components/schemas/Callback" {
+		t.Errorf("Expected callback to reference the Callback schema, got %+v", properties["callback"])
+	}
+	if _, ok := properties["Ttl"]; ok {
+		t.Errorf("Expected json:\"-\" fields to be skipped")
+	}
+}
+
+func TestOperationDocsHaveSummaries(t *testing.T) {
+	for name, doc := range operationDocs {
+		if doc.summary == "" || doc.tag == "" || doc.response == nil {
+			t.Errorf("Operation %s is missing its summary, tag or response", name)
+		}
+	}
+}
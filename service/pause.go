@@ -25,18 +25,22 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 )
 
 // PauseSchedule pauses a recurring schedule by updating its status to PAUSED
 // This will also delete all future executions of the schedule
+// The pause time is recorded so that the runs missed during the pause can be queued upon resume
+// A pauseAt in the request body schedules the pause for a future time instead, and a resumeAt schedules the resume
 func (s *Service) PauseSchedule(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 	var errs []string
 
 	vars := mux.Vars(r)
@@ -44,7 +48,7 @@ func (s *Service) PauseSchedule(w http.ResponseWriter, r *http.Request) {
 	uuid, err := gocql.ParseUUID(scheduleID)
 
 	if err != nil {
-		glog.Errorf("Cannot parse UUID from %s", scheduleID)
+		log.Errorf("Cannot parse UUID from %s", scheduleID)
 
 		errs = append(errs, err.Error())
 		s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
@@ -52,17 +56,24 @@ func (s *Service) PauseSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	request, err := readLifecycleRequest(r)
+	if err != nil {
+		s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
 	// First, get the schedule to ensure it exists and is recurring
 	schedule, err := s.ScheduleDao.GetSchedule(uuid)
 	if err != nil {
 		if err == gocql.ErrNotFound {
-			glog.Infof("No schedule with id :  %s found", uuid)
+			log.Infof("No schedule with id :  %s found", uuid)
 			s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
 
 			errs = append(errs, fmt.Sprintf("Schedule with id: %s not found", uuid))
 			er.Handle(w, r, er.NewError(er.DataNotFound, errors.New(strings.Join(errs, ","))))
 		} else {
-			glog.Errorf("Error fetching schedule with id %s", uuid)
+			log.Errorf("Error fetching schedule with id %s", uuid)
 			s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
 
 			errs = append(errs, err.Error())
@@ -71,69 +82,76 @@ func (s *Service) PauseSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	schedule.RequestId = logger.RequestID(r.Context())
+
 	// Check if the schedule is recurring
 	if !schedule.IsRecurring() {
 		s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
-		glog.Infof("schedule with id %s is not recurring", uuid)
+		log.Infof("schedule with id %s is not recurring", uuid)
 		errs = append(errs, fmt.Sprintf("Schedule with id: %s is not a recurring schedule", uuid))
 		er.Handle(w, r, er.NewError(er.UnprocessableEntity, errors.New(strings.Join(errs, ","))))
 		return
 	}
 
-	// Check if already paused
-	if schedule.Status == store.Paused {
-		s.recordRequestStatus(constants.PauseSchedule, constants.Success)
-
-		glog.Infof("Schedule with id %s is already paused", uuid)
-		status := Status{
-			StatusCode:    constants.SuccessCode200,
-			StatusMessage: "Schedule already paused",
-			StatusType:    constants.Success,
-			TotalCount:    1,
-		}
-		data := ScheduleData{
-			Schedule: schedule,
-		}
-		_ = json.NewEncoder(w).Encode(
-			ScheduleResponse{
-				Status: status,
-				Data:   data,
-			})
+	if err = request.validate(time.Now().Unix()); err != nil {
+		s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
 		return
 	}
 
-	// Check if schedule is scheduled
-	if schedule.Status != store.Scheduled {
+	var message string
+	switch {
+	case request.PauseAt != 0 && schedule.Status != store.Deleted:
+		message = "Schedule pause scheduled"
+	case schedule.Status == store.Paused:
+		// Check if already paused
+		log.Infof("Schedule with id %s is already paused", uuid)
+		message = "Schedule already paused"
+	case schedule.Status != store.Scheduled:
+		// Check if schedule is scheduled
 		s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
-		glog.Infof("Schedule with id %s is not scheduled", uuid)
+		log.Infof("Schedule with id %s is not scheduled", uuid)
 
 		errs = append(errs, fmt.Sprintf("Schedule with id: %s is not in Scheduled state", uuid))
 		er.Handle(w, r, er.NewError(er.UnprocessableEntity, errors.New(strings.Join(errs, ","))))
 		return
+	default:
+		// Update the schedule status to PAUSED
+		if schedule, err = s.pauseRecurringSchedule(schedule); err != nil {
+			log.Errorf("Error pausing schedule with id %s: %v", uuid, err)
+			s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
+			errs = append(errs, err.Error())
+			er.Handle(w, r, er.NewError(er.DataPersistenceFailure, errors.New(strings.Join(errs, ","))))
+			return
+		}
+		log.Debugf("Schedule with id %s paused", uuid.String())
+		message = "Schedule paused successfully"
 	}
 
-	// Update the schedule status to PAUSED
-	updatedSchedule, err := s.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Paused)
+	// Schedule the future dated pause and resume
+	actions, err := s.createLifecycleActions(r.Context(), schedule, request)
 	if err != nil {
-		glog.Errorf("Error pausing schedule with id %s: %v", uuid, err)
+		log.Errorf("Error scheduling pause or resume of schedule with id %s: %v", uuid, err)
 		s.recordRequestStatus(constants.PauseSchedule, constants.Fail)
-		errs = append(errs, err.Error())
-		er.Handle(w, r, er.NewError(er.DataPersistenceFailure, errors.New(strings.Join(errs, ","))))
+		er.Handle(w, r, err.(er.AppError))
 		return
 	}
+	if request.ResumeAt != 0 {
+		message += ", resume scheduled"
+	}
 
-	glog.V(constants.INFO).Infof("Schedule with id %s paused", uuid.String())
 	s.recordRequestStatus(constants.PauseSchedule, constants.Success)
 
 	status := Status{
 		StatusCode:    constants.SuccessCode200,
-		StatusMessage: "Schedule paused successfully",
+		StatusMessage: message,
 		StatusType:    constants.Success,
 		TotalCount:    1,
 	}
 
 	data := ScheduleData{
-		Schedule: updatedSchedule,
+		Schedule: schedule,
+		Actions:  actions,
 	}
 	_ = json.NewEncoder(w).Encode(
 		ScheduleResponse{
@@ -141,3 +159,15 @@ func (s *Service) PauseSchedule(w http.ResponseWriter, r *http.Request) {
 			Data:   data,
 		})
 }
+
+// pauseRecurringSchedule updates the status of a recurring schedule to PAUSED and records the pause time
+func (s *Service) pauseRecurringSchedule(schedule store.Schedule) (store.Schedule, error) {
+	schedule.PausedAt = time.Now().Unix()
+	updatedSchedule, err := s.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Paused)
+	if err != nil {
+		return updatedSchedule, err
+	}
+
+	store.PublishEvent(store.SchedulePaused, updatedSchedule)
+	return updatedSchedule, nil
+}
@@ -20,61 +20,102 @@
 package service
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/sla"
 	sch "github.com/myntra/goscheduler/store"
-	"io/ioutil"
+	"github.com/myntra/goscheduler/util"
 	"net/http"
-	"strings"
+	"time"
 )
 
 func (s *Service) Post(w http.ResponseWriter, r *http.Request) {
-	var input sch.Schedule
+	log := logger.FromContext(r.Context())
 
-	b, err := ioutil.ReadAll(r.Body)
-	if err != nil {
+	if s.shed(w, r, constants.CreateSchedule) {
 		s.recordRequestAppStatus(constants.CreateSchedule, getAppId(sch.Schedule{}), constants.Fail)
-		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
 		return
 	}
 
-	err = json.Unmarshal(b, &input)
+	input, err := readSchedule(r)
 	if err != nil {
 		s.recordRequestAppStatus(constants.CreateSchedule, getAppId(sch.Schedule{}), constants.Fail)
 		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
 		return
 	}
 
-	schedule, err := s.CreateSchedule(input)
+	probe, err := sch.ParseProbeMode(r.URL.Query().Get("probe"))
+	if err != nil {
+		s.recordRequestAppStatus(constants.CreateSchedule, getAppId(input), constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	// the schedule joins the trace of the request creating it, if the client sends one
+	if traceId, ok := sch.TraceIdOf(r.Header.Get(constants.TraceParentHeader)); ok {
+		input.TraceId = traceId
+	}
+	schedule, warnings, err := s.CreateScheduleProbing(r.Context(), input, probe)
 	if err != nil {
 		s.recordRequestAppStatus(constants.CreateSchedule, getAppId(sch.Schedule{}), constants.Fail)
 		er.Handle(w, r, err.(er.AppError))
 	} else {
 		s.recordRequestAppStatus(constants.CreateSchedule, getAppId(schedule), constants.Success)
-		glog.V(constants.INFO).Infof("Schedule created successfully. Schedule id is :  %s ", schedule.ScheduleId)
+		log.Debugf("Schedule created successfully. Schedule id is :  %s ", schedule.ScheduleId)
 		status := Status{StatusCode: constants.SuccessCode201, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
-		_ = json.NewEncoder(w).Encode(CreateScheduleResponse{Status: status, Data: CreateScheduleData{Schedule: schedule}})
+		_ = writeResponse(w, r, CreateScheduleResponse{Status: status, Data: CreateScheduleData{Schedule: schedule, Warnings: warnings}})
 	}
 
 }
 
-// CreateSchedule createSchedule creates a new schedule
-func (s *Service) CreateSchedule(input sch.Schedule) (sch.Schedule, error) {
+// CreateSchedule createSchedule creates a new schedule, persisted with the request id of the context
+func (s *Service) CreateSchedule(ctx context.Context, input sch.Schedule) (sch.Schedule, error) {
+	return s.createSchedule(ctx, input, gocql.UUID{})
+}
+
+// createSchedule creates a new schedule with the supplied id, a new id is generated if it is zero
+func (s *Service) createSchedule(ctx context.Context, input sch.Schedule, id gocql.UUID) (sch.Schedule, error) {
 	app, err := s.getApp(input.AppId)
 	if err != nil {
 		return sch.Schedule{}, err
 	}
 
-	errs := input.ValidateSchedule(app, s.Config.AppLevelConfiguration)
-	if errs != nil && len(errs) > 0 {
-		return sch.Schedule{}, er.NewError(er.InvalidDataCode, errors.New(strings.Join(errs, ",")))
+	// the schedule approved or mutated by the governance service goes through the validations like any other
+	if input, err = s.govern(app, input); err != nil {
+		return sch.Schedule{}, err
+	}
+
+	if err := input.ValidatePayloadSize(app, s.Config.AppLevelConfiguration); err != nil {
+		return sch.Schedule{}, er.NewError(er.PayloadTooLarge, err)
+	}
+
+	if details := input.ValidateFields(app, s.Config.AppLevelConfiguration); len(details) > 0 {
+		return sch.Schedule{}, er.NewValidationError(er.InvalidDataCode, details)
+	}
+
+	if err := s.checkTemplateCallback(input.AppId, input.Callback); err != nil {
+		return sch.Schedule{}, err
+	}
+
+	if err := s.checkScheduleGroup(input.AppId, input.Group); err != nil {
+		return sch.Schedule{}, err
+	}
+
+	if err := s.checkCallbackUrls(app, input.Callback, input.StatusCallback); err != nil {
+		return sch.Schedule{}, err
 	}
 
+	if input.IsDraft() && !input.IsRecurring() {
+		return sch.Schedule{}, er.NewError(er.InvalidDataCode, errors.New("only recurring schedules can be created as DRAFT"))
+	}
+
+	input.PayloadEncoding = app.Configuration.PayloadCompression
+
 	if input.IsRecurring() {
 		cronApp, err := s.getApp(s.Config.CronConfig.App)
 		if err != nil {
@@ -84,18 +125,32 @@ func (s *Service) CreateSchedule(input sch.Schedule) (sch.Schedule, error) {
 	}
 
 	input.SetFields(app)
+	if !util.IsZeroUUID(id) {
+		input.ScheduleId = id
+		input.PartitionId = input.PartitionFor(app.Partitions)
+	}
 
-	schedule, err := s.ScheduleDao.CreateSchedule(input, app)
+	// a one time schedule created into a minute the poller read ahead of time would never fire
+	if !input.IsRecurring() && s.bucketPrefetched(input.ScheduleGroup) {
+		return sch.Schedule{}, er.NewError(er.InvalidDataCode, fmt.Errorf("schedule time: %d is within a minute which may already be prefetched, it must be in a later minute", input.ScheduleTime))
+	}
+
+	schedule, err := s.ScheduleDao.CreateSchedule(ctx, input, app)
 	if err != nil {
 		return sch.Schedule{}, er.NewError(er.DataPersistenceFailure, err)
 	}
 
+	sch.PublishEvent(sch.ScheduleCreated, schedule)
+	sla.Anomalies().RecordCreate(schedule.AppId, time.Now())
+	if s.Config.UsageConfig.Enabled {
+		sch.Usages().Record(schedule.AppId, time.Now(), sch.Usage{SchedulesCreated: 1})
+	}
 	return schedule, nil
 }
 
 // getApp retrieves the app based on the provided app ID
 func (s *Service) getApp(appId string) (sch.App, error) {
-	app, err := s.ClusterDao.GetApp(appId)
+	app, err := s.readApp(appId)
 	switch {
 	case err == gocql.ErrNotFound:
 		return sch.App{}, er.NewError(er.InvalidAppId, errors.New(fmt.Sprintf("app Id %s is not registered", appId)))
@@ -105,6 +160,8 @@ func (s *Service) getApp(appId string) (sch.App, error) {
 		return sch.App{}, er.NewError(er.InvalidAppId, errors.New(fmt.Sprintf("app Id %s is not registered", appId)))
 	case !app.Active:
 		return sch.App{}, er.NewError(er.DeactivatedApp, errors.New(fmt.Sprintf("app Id %s is deactivated", app.AppId)))
+	case app.Offboarding != nil:
+		return sch.App{}, er.NewError(er.DeactivatedApp, errors.New(fmt.Sprintf("app Id %s is being offboarded", app.AppId)))
 	default:
 		return app, nil
 	}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"github.com/gocql/gocql"
 	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/clock"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -64,6 +65,16 @@ func TestService_Post(t *testing.T) {
 			[]byte(fmt.Sprintf(`{"AppId": "test", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST", "headers": {"header": "value"}}}, "CronExpression": "%s", "Payload":"{}"}`, "*/1 * 1 * * 12")),
 			http.StatusBadRequest,
 		},
+		{
+			gocql.TimeUUID().String(),
+			[]byte(fmt.Sprintf(`{"AppId": "test", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST", "headers": {"header": "value"}}}, "CronExpression": "%s", "Payload":"{}", "status": "DRAFT"}`, "*/1 * * * *")),
+			http.StatusOK,
+		},
+		{
+			gocql.TimeUUID().String(),
+			[]byte(fmt.Sprintf(`{"AppId": "test", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST", "headers": {"header": "value"}}}, "ScheduleTime":%d, "Payload":"{}", "status": "DRAFT"}`, time.Now().Add(90000000000).Unix())),
+			http.StatusBadRequest,
+		},
 		{
 			gocql.TimeUUID().String(),
 			[]byte(fmt.Sprintf(`{"AppId": "createScheduleFailureApp", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST", "headers": {"header": "value"}}}, "ScheduleTime":%d, "Payload":"{}"}`, time.Now().Add(90000000000).Unix())),
@@ -92,3 +103,71 @@ func TestService_Post(t *testing.T) {
 		}
 	}
 }
+
+func BenchmarkService_Post(b *testing.B) {
+	service := setupMocks()
+	body := []byte(fmt.Sprintf(`{"AppId": "test", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST", "headers": {"header": "value"}}}, "ScheduleTime":%d, "Payload":"{}"}`, time.Now().Add(time.Hour).Unix()))
+	handler := http.HandlerFunc(service.Post)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/goscheduler/schedules", bytes.NewReader(body))
+		req.Header.Add("x-myntra-client-id", "test")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			b.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestService_PostPayloadTooLarge(t *testing.T) {
+	service := setupMocks()
+	body := []byte(fmt.Sprintf(`{"AppId": "test", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST"}}, "ScheduleTime":%d, "Payload":"%s"}`, time.Now().Add(time.Hour).Unix(), bytes.Repeat([]byte("x"), 1025)))
+
+	req := httptest.NewRequest(http.MethodPost, "/goscheduler/schedules", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	service.Post(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+	if expected := "cannot be more than 1024 bytes, given payloadSize bytes: 1025"; !bytes.Contains(rr.Body.Bytes(), []byte(expected)) {
+		t.Errorf("Expected the limit and the size of the payload in the error, got %s", rr.Body.String())
+	}
+}
+
+func TestService_PostIntoPrefetchedBucket(t *testing.T) {
+	defer clock.SetDefault(clock.Default())
+	now := time.Now().Truncate(time.Minute).Add(55 * time.Second)
+	clock.SetDefault(clock.NewVirtual(now))
+
+	for _, test := range []struct {
+		prefetchSeconds int
+		scheduleTime    time.Time
+		status          int
+	}{
+		// the next minute begins within the prefetch and may already be read
+		{10, now.Add(30 * time.Second), http.StatusBadRequest},
+		{0, now.Add(30 * time.Second), http.StatusOK},
+		{10, now.Add(90 * time.Second), http.StatusOK},
+		// the current minute is read once it begins with or without prefetch
+		{10, now.Add(2 * time.Second), http.StatusOK},
+	} {
+		service := setupMocks()
+		service.Config.Poller.PrefetchSeconds = test.prefetchSeconds
+		body := []byte(fmt.Sprintf(`{"AppId": "test", "callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST"}}, "ScheduleTime":%d, "Payload":"{}"}`, test.scheduleTime.Unix()))
+
+		req := httptest.NewRequest(http.MethodPost, "/goscheduler/schedules", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+		service.Post(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("Expected status %d for a schedule at %v with prefetch of %ds, got %d: %s", test.status, test.scheduleTime, test.prefetchSeconds, rr.Code, rr.Body.String())
+		}
+		if test.status == http.StatusBadRequest && !bytes.Contains(rr.Body.Bytes(), []byte("may already be prefetched")) {
+			t.Errorf("Expected the prefetched minute in the error, got %s", rr.Body.String())
+		}
+	}
+}
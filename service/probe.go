@@ -0,0 +1,77 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+// CreateScheduleProbing creates a schedule after probing its callback urls. An unreachable url fails the creation
+// with ProbeReject and is returned as a warning of the created schedule with ProbeWarn.
+func (s *Service) CreateScheduleProbing(ctx context.Context, input sch.Schedule, mode sch.ProbeMode) (sch.Schedule, []string, error) {
+	var warnings []string
+	if mode != sch.NoProbe {
+		unreachable := s.probeCallbackUrls(input.Callback, input.StatusCallback)
+		if len(unreachable) > 0 && mode == sch.ProbeReject {
+			return sch.Schedule{}, nil, er.NewValidationError(er.UnprocessableEntity, unreachable)
+		}
+		warnings = er.Messages(unreachable)
+	}
+
+	schedule, err := s.CreateSchedule(ctx, input)
+	if err != nil {
+		return sch.Schedule{}, nil, err
+	}
+	return schedule, warnings, nil
+}
+
+// probeCallbackUrls returns the http callback and status callback urls which cannot be reached.
+// The invalid urls and the ones denied by the egress policy are left to the validation of the schedule.
+func (s *Service) probeCallbackUrls(callback sch.Callback, statusCallback string) []er.FieldError {
+	client := &http.Client{
+		Transport: sch.Egress().Transport(http.DefaultTransport.(*http.Transport)),
+		Timeout:   time.Duration(s.Config.UrlVerificationConfig.ProbeTimeoutMillis) * time.Millisecond,
+	}
+
+	var unreachable []er.FieldError
+	probe := func(field, rawUrl string) {
+		if _, err := sch.VerificationKey(rawUrl); err != nil {
+			return
+		}
+		if err := sch.ProbeUrl(client, rawUrl); err != nil && !errors.Is(err, sch.ErrEgressDenied) {
+			unreachable = append(unreachable, er.FieldError{Field: field, Message: fmt.Sprintf("%s %s is unreachable: %v", field, rawUrl, err)})
+		}
+	}
+
+	for _, rawUrl := range sch.CallbackUrls(callback, "") {
+		probe("callback", rawUrl)
+	}
+	if statusCallback != "" {
+		probe("statusCallback", statusCallback)
+	}
+	return unreachable
+}
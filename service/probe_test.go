@@ -0,0 +1,71 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	er "github.com/myntra/goscheduler/error"
+)
+
+func TestService_PostProbe(t *testing.T) {
+	service := setupMocks()
+	reachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer reachable.Close()
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+
+	body := func(url string) []byte {
+		return []byte(fmt.Sprintf(`{"appId": "test", "callback": {"type": "http", "details": {"url": "%s", "method": "POST"}}, "scheduleTime": %d, "payload": "{}"}`, url, time.Now().Add(time.Hour).Unix()))
+	}
+
+	for _, test := range []struct {
+		name     string
+		probe    string
+		url      string
+		status   int
+		warnings int
+	}{
+		{"reachable callback", "reject", reachable.URL, http.StatusOK, 0},
+		{"unreachable callback without a probe", "", unreachable.URL, http.StatusOK, 0},
+		{"unreachable callback with a warning", "warn", unreachable.URL, http.StatusOK, 1},
+		{"unreachable callback rejected", "reject", unreachable.URL, http.StatusUnprocessableEntity, 0},
+		{"invalid probe", "always", reachable.URL, http.StatusBadRequest, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/goscheduler/schedules?probe="+test.probe, bytes.NewReader(body(test.url)))
+			rr := httptest.NewRecorder()
+			service.Post(rr, req)
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v, %s", rr.Code, test.status, rr.Body.String())
+			}
+
+			if test.status == http.StatusUnprocessableEntity {
+				var response er.ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				if len(response.Error.Details) != 1 || response.Error.Details[0].Field != "callback" {
+					t.Errorf("expected the unreachable callback in the details, got %+v", response.Error)
+				}
+				return
+			}
+			if test.status == http.StatusOK {
+				var response CreateScheduleResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				if len(response.Data.Warnings) != test.warnings {
+					t.Errorf("expected %d warnings, got %v", test.warnings, response.Data.Warnings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/schedulerpb"
+	sch "github.com/myntra/goscheduler/store"
+	"google.golang.org/protobuf/proto"
+)
+
+// unmarshalProtoSchedule decodes a Schedule message into a schedule
+func unmarshalProtoSchedule(body []byte) (sch.Schedule, error) {
+	var message schedulerpb.Schedule
+	if err := proto.Unmarshal(body, &message); err != nil {
+		return sch.Schedule{}, err
+	}
+	return scheduleFromProto(&message)
+}
+
+// unmarshalProtoSnapshot decodes a ScheduleSnapshot message into the schedules of the snapshot
+func unmarshalProtoSnapshot(body []byte) ([]sch.Schedule, error) {
+	var message schedulerpb.ScheduleSnapshot
+	if err := proto.Unmarshal(body, &message); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]sch.Schedule, 0, len(message.Schedules))
+	for i, schedule := range message.Schedules {
+		decoded, err := scheduleFromProto(schedule)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %d: %s", i, err.Error())
+		}
+		schedules = append(schedules, decoded)
+	}
+	return schedules, nil
+}
+
+// scheduleFromProto converts a Schedule message into a schedule, as its JSON document would be decoded.
+// The callbacks are the JSON documents of the callbacks.
+func scheduleFromProto(message *schedulerpb.Schedule) (sch.Schedule, error) {
+	schedule := sch.Schedule{
+		Payload:           message.Payload,
+		AppId:             message.AppId,
+		ScheduleTime:      message.ScheduleTime,
+		PartitionId:       int(message.PartitionId),
+		ScheduleGroup:     message.ScheduleGroup,
+		CronExpression:    message.CronExpression,
+		Every:             message.Every,
+		RRule:             message.Rrule,
+		Anchor:            message.Anchor,
+		StatusCallback:    message.StatusCallback,
+		PausePolicy:       sch.PausePolicy(message.PausePolicy),
+		ConcurrencyPolicy: sch.ConcurrencyPolicy(message.ConcurrencyPolicy),
+		Group:             message.Group,
+		KeepPaused:        message.KeepPaused,
+		PausedAt:          message.PausedAt,
+		DeletedAt:         message.DeletedAt,
+		Priority:          sch.Priority(message.Priority),
+		Region:            message.Region,
+		TraceId:           message.TraceId,
+		DeferredFrom:      message.DeferredFrom,
+		Status:            sch.Status(message.Status),
+		ErrorMessage:      message.ErrorMessage,
+		ResponseSnippet:   message.ResponseSnippet,
+		Archived:          message.Archived,
+		Description:       message.Description,
+		Health:            sch.Health(message.Health),
+	}
+
+	var err error
+	if message.ScheduleId != "" {
+		if schedule.ScheduleId, err = gocql.ParseUUID(message.ScheduleId); err != nil {
+			return schedule, fmt.Errorf("invalid scheduleId: %s", err.Error())
+		}
+	}
+
+	for _, history := range message.ReconciliationHistory {
+		schedule.ReconciliationHistory = append(schedule.ReconciliationHistory, sch.ReconciliationHistory{
+			Status:       sch.Status(history.Status),
+			ErrorMessage: history.ErrorMessage,
+			CallbackOn:   history.CallbackOn,
+		})
+	}
+	if message.Canary != nil {
+		schedule.Canary = &sch.CanaryPolicy{Runs: int(message.Canary.Runs), Percent: int(message.Canary.Percent)}
+	}
+
+	if len(message.Callback) > 0 {
+		schedule.CallbackRaw = message.Callback
+		if schedule.Callback, err = sch.ParseCallback(message.Callback); err != nil {
+			return schedule, err
+		}
+		return schedule, nil
+	}
+	if len(message.HttpCallback) > 0 {
+		if err = json.Unmarshal(message.HttpCallback, &schedule.HttpCallback); err != nil {
+			return schedule, fmt.Errorf("invalid httpCallback: %s", err.Error())
+		}
+	}
+	if len(message.AirbusCallback) > 0 {
+		if err = json.Unmarshal(message.AirbusCallback, &schedule.AirbusCallback); err != nil {
+			return schedule, fmt.Errorf("invalid airbusCallback: %s", err.Error())
+		}
+	}
+	return schedule, nil
+}
+
+// scheduleToProto converts a schedule into its Schedule message, with the fields of its JSON document
+func scheduleToProto(schedule sch.Schedule) *schedulerpb.Schedule {
+	message := &schedulerpb.Schedule{
+		ScheduleId:        schedule.ScheduleId.String(),
+		Payload:           schedule.Payload,
+		AppId:             schedule.AppId,
+		ScheduleTime:      schedule.ScheduleTime,
+		PartitionId:       int32(schedule.PartitionId),
+		ScheduleGroup:     schedule.ScheduleGroup,
+		Callback:          schedule.CallbackRaw,
+		CronExpression:    schedule.CronExpression,
+		Every:             schedule.Every,
+		Rrule:             schedule.RRule,
+		Anchor:            schedule.Anchor,
+		StatusCallback:    schedule.StatusCallback,
+		PausePolicy:       string(schedule.PausePolicy),
+		ConcurrencyPolicy: string(schedule.ConcurrencyPolicy),
+		Group:             schedule.Group,
+		PausedAt:          schedule.PausedAt,
+		DeletedAt:         schedule.DeletedAt,
+		Priority:          string(schedule.Priority),
+		Region:            schedule.Region,
+		Status:            string(schedule.Status),
+		ErrorMessage:      schedule.ErrorMessage,
+		ResponseSnippet:   schedule.ResponseSnippet,
+		Archived:          schedule.Archived,
+		Description:       schedule.Description,
+		Health:            string(schedule.Health),
+		KeepPaused:        schedule.KeepPaused,
+		TraceId:           schedule.TraceId,
+		DeferredFrom:      schedule.DeferredFrom,
+	}
+
+	for _, history := range schedule.ReconciliationHistory {
+		message.ReconciliationHistory = append(message.ReconciliationHistory, &schedulerpb.ReconciliationHistory{
+			Status:       string(history.Status),
+			ErrorMessage: history.ErrorMessage,
+			CallbackOn:   history.CallbackOn,
+		})
+	}
+	if schedule.Canary != nil {
+		message.Canary = &schedulerpb.CanaryPolicy{Runs: int32(schedule.Canary.Runs), Percent: int32(schedule.Canary.Percent)}
+	}
+	if schedule.HttpCallback.Url != "" || len(schedule.HttpCallback.Headers) > 0 {
+		message.HttpCallback, _ = json.Marshal(schedule.HttpCallback)
+	}
+	if schedule.AirbusCallback.EventName != "" || schedule.AirbusCallback.AppName != "" || len(schedule.AirbusCallback.Headers) > 0 {
+		message.AirbusCallback, _ = json.Marshal(schedule.AirbusCallback)
+	}
+	return message
+}
+
+// statusToProto converts the status of a response into its Status message
+func statusToProto(status Status) *schedulerpb.Status {
+	return &schedulerpb.Status{
+		StatusCode:    int32(status.StatusCode),
+		StatusMessage: status.StatusMessage,
+		StatusType:    status.StatusType,
+		TotalCount:    int32(status.TotalCount),
+	}
+}
+
+func (c CreateScheduleResponse) toProto() proto.Message {
+	return &schedulerpb.CreateScheduleResponse{
+		Status: statusToProto(c.Status),
+		Data: &schedulerpb.CreateScheduleData{
+			Schedule: scheduleToProto(c.Data.Schedule),
+			Warnings: c.Data.Warnings,
+		},
+	}
+}
+
+func (i ImportSchedulesResponse) toProto() proto.Message {
+	data := &schedulerpb.ImportSchedulesData{
+		Imported:    int32(i.Data.Imported),
+		Overwritten: int32(i.Data.Overwritten),
+		Skipped:     int32(i.Data.Skipped),
+		Failed:      int32(i.Data.Failed),
+		IdMapping:   i.Data.IdMapping,
+	}
+	for _, importError := range i.Data.Errors {
+		data.Errors = append(data.Errors, &schedulerpb.ImportError{
+			Index:      int32(importError.Index),
+			ScheduleId: importError.ScheduleId,
+			Error:      importError.Error,
+		})
+	}
+	return &schedulerpb.ImportSchedulesResponse{Status: statusToProto(i.Status), Data: data}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/schedulerpb"
+	sch "github.com/myntra/goscheduler/store"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	protoMessagePattern = regexp.MustCompile(`(?s)message (\w+) \{(.*?)\n\}`)
+	protoFieldPattern   = regexp.MustCompile(`(?m)^\s*(repeated\s+)?(map<[^>]+>|[\w.]+)\s+(\w+)\s*=\s*(\d+);`)
+)
+
+// The generated messages have to be regenerated whenever the proto file changes
+func TestProtoMessagesMatchProtoFile(t *testing.T) {
+	source, err := ioutil.ReadFile("../docs/goscheduler.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := schedulerpb.File_goscheduler_proto.Messages()
+	declared := protoMessagePattern.FindAllStringSubmatch(string(source), -1)
+	if len(declared) != messages.Len() {
+		t.Fatalf("expected the %d messages of the proto file to be generated, got %d", len(declared), messages.Len())
+	}
+
+	for _, match := range declared {
+		message := messages.ByName(protoreflect.Name(match[1]))
+		if message == nil {
+			t.Errorf("message %s of the proto file is not generated", match[1])
+			continue
+		}
+
+		fields := protoFieldPattern.FindAllStringSubmatch(match[2], -1)
+		if len(fields) != message.Fields().Len() {
+			t.Errorf("%s: expected %d fields, got %d generated", match[1], len(fields), message.Fields().Len())
+		}
+		for _, field := range fields {
+			generated := message.Fields().ByName(protoreflect.Name(field[3]))
+			number, _ := strconv.Atoi(field[4])
+			switch {
+			case generated == nil:
+				t.Errorf("%s.%s is not generated", match[1], field[3])
+			case generated.Number() != protoreflect.FieldNumber(number):
+				t.Errorf("%s.%s: expected number %s, got %d", match[1], field[3], field[4], generated.Number())
+			case generated.IsList() != (field[1] != "") || generated.IsMap() != strings.HasPrefix(field[2], "map<"):
+				t.Errorf("%s.%s: cardinality differs from the proto file", match[1], field[3])
+			}
+		}
+	}
+}
+
+// Every field of the JSON document of a schedule is a field of the Schedule message with the same name
+func TestScheduleMessageHasJSONFields(t *testing.T) {
+	message := (&schedulerpb.Schedule{}).ProtoReflect().Descriptor()
+	typ := reflect.TypeOf(sch.Schedule{})
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if message.Fields().ByName(protoreflect.Name(name)) == nil {
+			t.Errorf("field %s of the schedule is missing from the Schedule message", name)
+		}
+	}
+}
+
+func testSchedule() sch.Schedule {
+	sch.Registry[constants.DefaultCallback] = func() sch.Callback { return &sch.HttpCallback{} }
+	callback := json.RawMessage(`{"type":"http","details":{"url":"http://localhost:8080/callback","method":"POST","headers":{"X-Test":"1"},"timeoutMillis":500}}`)
+	parsed, _ := sch.ParseCallback(callback)
+	return sch.Schedule{
+		ScheduleId:            gocql.TimeUUID(),
+		Payload:               `{"id": 1}`,
+		AppId:                 "test",
+		ScheduleTime:          1700000060,
+		PartitionId:           3,
+		ScheduleGroup:         1700000040,
+		Callback:              parsed,
+		CallbackRaw:           callback,
+		CronExpression:        "*/5 * * * *",
+		StatusCallback:        "http://localhost:8080/status",
+		PausePolicy:           sch.PausePolicy("skip"),
+		ConcurrencyPolicy:     sch.ConcurrencyPolicy("forbid"),
+		Group:                 "reports",
+		KeepPaused:            true,
+		PausedAt:              1700000000,
+		Priority:              sch.Priority("high"),
+		Region:                "eu",
+		TraceId:               "4bf92f3577b34da6a3ce929d0e0e4736",
+		DeferredFrom:          1699990000,
+		Status:                sch.Failure,
+		ErrorMessage:          "503 Service Unavailable",
+		ResponseSnippet:       "unavailable",
+		ReconciliationHistory: []sch.ReconciliationHistory{{Status: sch.Success, ErrorMessage: "", CallbackOn: "2023-11-14"}},
+		Archived:              true,
+		Description:           "Every 5 minutes",
+		Health:                sch.Health("degraded"),
+		Canary:                &sch.CanaryPolicy{Runs: 5, Percent: 20},
+	}
+}
+
+// jsonRoundTrip returns the schedule as the JSON API reads it back after writing it
+func jsonRoundTrip(t *testing.T, schedule sch.Schedule) sch.Schedule {
+	body, err := json.Marshal(schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded sch.Schedule
+	if err = json.Unmarshal(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestScheduleProtoRoundTrip(t *testing.T) {
+	schedule := testSchedule()
+	body, err := proto.Marshal(scheduleToProto(schedule))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := unmarshalProtoSchedule(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := jsonRoundTrip(t, schedule); !reflect.DeepEqual(expected, decoded) {
+		t.Errorf("expected the schedule read back as from JSON\n%+v\ngot\n%+v", expected, decoded)
+	}
+
+	deprecated := sch.Schedule{AppId: "test", HttpCallback: sch.HTTPCallback{Url: "http://localhost:8080/callback", Headers: map[string]string{"X-Test": "1"}}}
+	body, _ = proto.Marshal(scheduleToProto(deprecated))
+	if decoded, err = unmarshalProtoSchedule(body); err != nil || !reflect.DeepEqual(jsonRoundTrip(t, deprecated), decoded) {
+		t.Errorf("expected the deprecated callback read back, got %+v, %v", decoded, err)
+	}
+
+	if _, err = unmarshalProtoSchedule(mustMarshal(t, &schedulerpb.Schedule{ScheduleId: "not a uuid"})); err == nil {
+		t.Errorf("expected an invalid schedule id to fail")
+	}
+	if _, err = unmarshalProtoSchedule(mustMarshal(t, &schedulerpb.Schedule{Callback: []byte(`{"type": "carrier-pigeon"}`)})); err == nil {
+		t.Errorf("expected an unknown callback type to fail")
+	}
+}
+
+func TestScheduleMsgpackRoundTrip(t *testing.T) {
+	schedule := testSchedule()
+	body, err := marshalMsgpack(CreateScheduleData{Schedule: schedule})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document map[string]map[string]interface{}
+	if err = unmarshalMsgpack(body, &document); err != nil {
+		t.Fatal(err)
+	}
+	if callback, ok := document["schedule"]["callback"].(map[string]interface{}); !ok || callback["type"] != "http" {
+		t.Errorf("expected the callback encoded as a map, got %#v", document["schedule"]["callback"])
+	}
+
+	var decoded CreateScheduleData
+	if err = unmarshalMsgpack(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// The callback comes back from a map, with its keys sorted
+	expected := jsonRoundTrip(t, schedule)
+	assert.JSONEq(t, string(expected.CallbackRaw), string(decoded.Schedule.CallbackRaw))
+	expected.CallbackRaw, decoded.Schedule.CallbackRaw = nil, nil
+	if !reflect.DeepEqual(expected, decoded.Schedule) {
+		t.Errorf("expected the schedule read back as from JSON\n%+v\ngot\n%+v", expected, decoded.Schedule)
+	}
+}
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	body, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
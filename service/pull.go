@@ -0,0 +1,290 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+const (
+	defaultDueRunsSize = 10
+	maxDueRunsSize     = 100
+	// dueRunsScanFactor is how many due runs are read for each one handed out, as some are leased to other consumers
+	dueRunsScanFactor = 5
+)
+
+// duePoll is a poll of a consumer for the due runs of an app
+type duePoll struct {
+	size  int
+	wait  time.Duration
+	lease time.Duration
+}
+
+// GetDueRuns hands the due runs of the pull callbacks of an app to a consumer. Without due runs the request waits
+// for some up to waitSeconds. The runs are leased to the consumer for leaseSeconds, to be acknowledged within.
+func (s *Service) GetDueRuns(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	runs, err := s.PollDueRuns(r, appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetDueRuns, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetDueRuns, appId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(runs),
+	}
+	_ = writeListing(w, r,
+		GetDueRunsResponse{
+			Status: status,
+			Data: GetDueRunsData{
+				Runs: runs,
+			},
+		})
+}
+
+// PollDueRuns leases the due runs of the app to the consumer polling for them, looking for due runs until the
+// wait of the poll is over or the consumer goes away
+func (s *Service) PollDueRuns(r *http.Request, appId string) ([]sch.DueRun, error) {
+	app, err := s.getApp(appId)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := s.parseDuePoll(r)
+	if err != nil {
+		return nil, er.NewError(er.InvalidDataCode, err)
+	}
+
+	deadline := time.Now().Add(poll.wait)
+	interval := time.Duration(s.Config.PullDeliveryConfig.PollIntervalMillis) * time.Millisecond
+	for {
+		runs, err := s.leaseDueRuns(app, poll)
+		if err != nil {
+			return nil, er.NewError(er.DataFetchFailure, err)
+		}
+		if len(runs) > 0 || !time.Now().Before(deadline) {
+			return runs, nil
+		}
+
+		select {
+		case <-r.Context().Done():
+			return runs, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// leaseDueRuns leases up to the size of the poll of the due runs of the app which are not leased to another
+// consumer. The runs out of deliveries fail instead of being leased once more.
+func (s *Service) leaseDueRuns(app sch.App, poll duePoll) ([]sch.DueRun, error) {
+	candidates, err := s.ScheduleDao.GetDueRuns(app.AppId, poll.size*dueRunsScanFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	leasedUntil := now.Add(poll.lease)
+	runs := make([]sch.DueRun, 0, poll.size)
+	for _, run := range candidates {
+		if len(runs) == poll.size {
+			break
+		}
+		if !run.Leasable(now) {
+			continue
+		}
+		if run.Deliveries >= s.Config.PullDeliveryConfig.MaxDeliveries {
+			s.expireDueRun(app, run)
+			continue
+		}
+
+		ttl := sch.Schedule{ScheduleTime: run.ScheduleTime}.GetTTL(app, s.Config.AppLevelConfiguration.FiredScheduleRetentionPeriod)
+		leased, err := s.ScheduleDao.LeaseDueRun(run, leasedUntil, ttl)
+		if err != nil {
+			// the runs leased so far are handed out, the others are left for the next poll
+			if len(runs) > 0 {
+				break
+			}
+			return nil, err
+		}
+		if !leased {
+			continue
+		}
+
+		run.Deliveries++
+		run.LeasedUntil = leasedUntil.UnixNano() / int64(time.Millisecond)
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// expireDueRun fails a run left unacknowledged for all its deliveries
+func (s *Service) expireDueRun(app sch.App, run sch.DueRun) {
+	message := fmt.Sprintf("not acknowledged after %d deliveries", run.Deliveries)
+	if err := s.completeDueRun(app, run, sch.Acknowledgement{Status: sch.Failure, Error: message}); err != nil {
+		logger.Errorf("Due run %s of app %s could not be expired with error %s", run.ScheduleId.String(), app.AppId, err.Error())
+	}
+}
+
+func (s *Service) parseDuePoll(r *http.Request) (duePoll, error) {
+	config := s.Config.PullDeliveryConfig
+	query := r.URL.Query()
+
+	size, err := parseBoundedParam(query.Get("size"), defaultDueRunsSize, 1, maxDueRunsSize)
+	if err != nil {
+		return duePoll{}, fmt.Errorf("size %s", err.Error())
+	}
+	wait, err := parseBoundedParam(query.Get("waitSeconds"), 0, 0, config.MaxWaitSeconds)
+	if err != nil {
+		return duePoll{}, fmt.Errorf("waitSeconds %s", err.Error())
+	}
+	lease, err := parseBoundedParam(query.Get("leaseSeconds"), config.LeaseSeconds, 1, config.MaxLeaseSeconds)
+	if err != nil {
+		return duePoll{}, fmt.Errorf("leaseSeconds %s", err.Error())
+	}
+
+	return duePoll{
+		size:  size,
+		wait:  time.Duration(wait) * time.Second,
+		lease: time.Duration(lease) * time.Second,
+	}, nil
+}
+
+// parseBoundedParam parses an integer query parameter between min and max, the default when it is left out
+func parseBoundedParam(param string, defaultValue int, min int, max int) (int, error) {
+	if param == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(param)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("should be an integer between %d and %d", min, max)
+	}
+	return value, nil
+}
+
+// AckDueRun completes a due run handed to a consumer with the outcome the consumer acknowledges.
+func (s *Service) AckDueRun(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+	scheduleId := vars["scheduleId"]
+
+	var acknowledgement sch.Acknowledgement
+	if err := json.NewDecoder(r.Body).Decode(&acknowledgement); err != nil {
+		s.recordRequestAppStatus(constants.AckDueRun, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	if err := s.AcknowledgeDueRun(appId, scheduleId, acknowledgement); err != nil {
+		s.recordRequestAppStatus(constants.AckDueRun, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.AckDueRun, appId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		AckDueRunResponse{
+			Status: status,
+			Data: AckDueRunData{
+				ScheduleId: scheduleId,
+				Status:     acknowledgement.Status,
+			},
+		})
+}
+
+// AcknowledgeDueRun completes the due run of the app with the acknowledged outcome
+func (s *Service) AcknowledgeDueRun(appId string, id string, acknowledgement sch.Acknowledgement) error {
+	if err := acknowledgement.Validate(); err != nil {
+		return er.NewError(er.InvalidDataCode, err)
+	}
+
+	scheduleId, err := gocql.ParseUUID(id)
+	if err != nil {
+		return er.NewError(er.InvalidDataCode, err)
+	}
+
+	app, err := s.getApp(appId)
+	if err != nil {
+		return err
+	}
+
+	run, err := s.ScheduleDao.GetDueRun(app.AppId, scheduleId)
+	switch {
+	case err == gocql.ErrNotFound:
+		return er.NewError(er.DataNotFound, errors.New(fmt.Sprintf("No due run %s found for app %s", id, appId)))
+	case err != nil:
+		return er.NewError(er.DataFetchFailure, err)
+	}
+
+	return s.completeDueRun(app, run, acknowledgement)
+}
+
+// completeDueRun removes the due run and hands it to the workers completing the runs with the outcome of the
+// acknowledgement. A run removed by another acknowledgement in the meantime is not completed twice
+func (s *Service) completeDueRun(app sch.App, run sch.DueRun, acknowledgement sch.Acknowledgement) error {
+	deleted, err := s.ScheduleDao.DeleteDueRun(app.AppId, run.ScheduleId)
+	if err != nil {
+		return er.NewError(er.DataPersistenceFailure, err)
+	}
+	if !deleted {
+		return er.NewError(er.DataNotFound, errors.New(fmt.Sprintf("Due run %s was acknowledged already", run.ScheduleId.String())))
+	}
+
+	schedule, err := s.ScheduleDao.GetSchedule(run.ScheduleId)
+	if err != nil {
+		return er.NewError(er.DataFetchFailure, err)
+	}
+
+	sch.PullAckTaskQueue <- sch.PullAckTask{
+		Schedule:       schedule,
+		App:            app,
+		Status:         acknowledgement.Status,
+		ErrorMessage:   acknowledgement.Error,
+		Deliveries:     run.Deliveries,
+		AcknowledgedAt: time.Now(),
+	}
+	return nil
+}
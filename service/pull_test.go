@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_GetDueRuns(t *testing.T) {
+	service := &Service{
+		Config:      conf.NewConfig(),
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		name   string
+		appId  string
+		query  string
+		status int
+		runs   int
+	}{
+		{"Due", "test", "", http.StatusOK, 1},
+		{"NoneDue", "empty", "", http.StatusOK, 0},
+		{"WaitForDue", "empty", "?waitSeconds=1", http.StatusOK, 0},
+		{"FetchError", "error", "", http.StatusInternalServerError, 0},
+		{"AppNotRegistered", "testAppNotFound", "", http.StatusBadRequest, 0},
+		{"InvalidSize", "test", "?size=0", http.StatusBadRequest, 0},
+		{"InvalidLease", "test", "?leaseSeconds=100000", http.StatusBadRequest, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/goscheduler/apps/{appId}/due"+test.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.GetDueRuns).ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, test.status)
+			}
+			if test.status != http.StatusOK {
+				return
+			}
+
+			var response GetDueRunsResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Data.Runs) != test.runs {
+				t.Errorf("expected %d runs, got %d", test.runs, len(response.Data.Runs))
+			}
+			for _, run := range response.Data.Runs {
+				if run.Deliveries != 1 || run.LeasedUntil == 0 {
+					t.Errorf("expected a leased run, got %+v", run)
+				}
+			}
+		})
+	}
+}
+
+func TestService_AckDueRun(t *testing.T) {
+	service := &Service{
+		Config:      conf.NewConfig(),
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+	store.PullAckTaskQueue = make(chan store.PullAckTask, 10)
+
+	for _, test := range []struct {
+		name            string
+		scheduleId      string
+		acknowledgement interface{}
+		status          int
+	}{
+		{"Success", gocql.TimeUUID().String(), store.Acknowledgement{Status: store.Success}, http.StatusOK},
+		{"Failure", gocql.TimeUUID().String(), store.Acknowledgement{Status: store.Failure, Error: "unknown order"}, http.StatusOK},
+		{"InvalidStatus", gocql.TimeUUID().String(), store.Acknowledgement{Status: store.Scheduled}, http.StatusBadRequest},
+		{"InvalidBody", gocql.TimeUUID().String(), "SUCCESS", http.StatusBadRequest},
+		{"InvalidScheduleId", "invalid-uuid", store.Acknowledgement{Status: store.Success}, http.StatusBadRequest},
+		{"FetchError", "00000000-0000-0000-0000-000000000000", store.Acknowledgement{Status: store.Success}, http.StatusInternalServerError},
+		{"NotDue", "00000000-0000-0000-0000-000000000001", store.Acknowledgement{Status: store.Success}, http.StatusNotFound},
+		{"AcknowledgedAlready", "00000000-0000-0000-0000-000000000002", store.Acknowledgement{Status: store.Success}, http.StatusNotFound},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			body, _ := json.Marshal(test.acknowledgement)
+			req, err := http.NewRequest("POST", "/goscheduler/apps/{appId}/due/{scheduleId}/ack", bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"appId": "test", "scheduleId": test.scheduleId})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.AckDueRun).ServeHTTP(rr, req)
+
+			if rr.Code != test.status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, test.status)
+			}
+			if test.status != http.StatusOK {
+				return
+			}
+
+			select {
+			case task := <-store.PullAckTaskQueue:
+				if task.Status != test.acknowledgement.(store.Acknowledgement).Status || task.Deliveries != 1 {
+					t.Errorf("unexpected completion %+v", task)
+				}
+			default:
+				t.Errorf("expected the run to be handed for completion")
+			}
+		})
+	}
+}
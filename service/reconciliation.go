@@ -0,0 +1,231 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/dao"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/replication"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	// maxScanWindow bounds the window scanned by a request, as the scan is synchronous
+	maxScanWindow = 24 * time.Hour
+
+	defaultScanLimit = 1000
+	maxScanLimit     = 10000
+)
+
+type reconciliationOptions struct {
+	timeRange dao.Range
+	refire    bool
+	limit     int
+}
+
+// parseScanWindow reads the window of a scan, which ends at the latest now
+func parseScanWindow(r *http.Request) (dao.Range, error) {
+	_, _, timeRange, _, _, err := parse(r)
+	if err != nil {
+		return dao.Range{}, err
+	}
+
+	if now := time.Now(); timeRange.EndTime.After(now) {
+		timeRange.EndTime = now
+	}
+	if !timeRange.StartTime.Before(timeRange.EndTime) {
+		return dao.Range{}, fmt.Errorf("start time: %s must be before end time: %s and in the past", timeRange.StartTime, timeRange.EndTime)
+	}
+	if timeRange.EndTime.Sub(timeRange.StartTime) > maxScanWindow {
+		return dao.Range{}, fmt.Errorf("time range of more than %s is not allowed", maxScanWindow)
+	}
+	return timeRange, nil
+}
+
+// parseScanLimit reads the maximum number of schedules reported by a scan
+func parseScanLimit(r *http.Request) (int, error) {
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		return defaultScanLimit, nil
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 || n > maxScanLimit {
+		return 0, fmt.Errorf("invalid limit: %s, must be between 1 and %d", limit, maxScanLimit)
+	}
+	return n, nil
+}
+
+// parseReconciliationOptions reads the window, whether the missed schedules are re-fired and the maximum number of
+// missed schedules handled
+func parseReconciliationOptions(r *http.Request) (reconciliationOptions, error) {
+	var options reconciliationOptions
+	var err error
+
+	if options.timeRange, err = parseScanWindow(r); err != nil {
+		return options, err
+	}
+	if options.limit, err = parseScanLimit(r); err != nil {
+		return options, err
+	}
+	if refire := r.URL.Query().Get("refire"); refire != "" {
+		if options.refire, err = strconv.ParseBool(refire); err != nil {
+			return options, fmt.Errorf("invalid refire: %s", refire)
+		}
+	}
+	return options, nil
+}
+
+// scanWindow hands the schedules due in the window to visit, one partition bucket at a time from the oldest bucket,
+// until visit returns false
+func (s *Service) scanWindow(app store.App, timeRange dao.Range, visit func(schedules []store.Schedule) (bool, error)) error {
+	start, end := timeRange.StartTime, timeRange.EndTime
+
+	for bucket := start.Truncate(time.Minute); bucket.Before(end); bucket = bucket.Add(time.Minute) {
+		for partitionId := 0; partitionId < int(app.Partitions); partitionId++ {
+			schedules, err := s.getBucketSchedules(app.AppId, partitionId, bucket)
+			if err != nil {
+				return err
+			}
+
+			var due []store.Schedule
+			for _, schedule := range schedules {
+				if schedule.ScheduleTime >= start.Unix() && schedule.ScheduleTime < end.Unix() {
+					due = append(due, schedule)
+				}
+			}
+			sort.SliceStable(due, func(i, j int) bool {
+				return due[i].ScheduleTime < due[j].ScheduleTime
+			})
+
+			if next, err := visit(due); err != nil || !next {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReconcileMissedSchedules reports the schedules of an app which were due in a window but have no run record,
+// e.g. because the node polling their partition crashed, and re-fires them when refire is set
+func (s *Service) ReconcileMissedSchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	options, err := parseReconciliationOptions(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ReconcileMissedSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	app, err := s.getActiveOrInactiveApp(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ReconcileMissedSchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	if options.refire && replication.Default().IsStandby() {
+		s.recordRequestAppStatus(constants.ReconcileMissedSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.Conflict, errors.New("a standby cluster does not fire schedules")))
+		return
+	}
+
+	data, err := s.reconcileMissedSchedules(app, options)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ReconcileMissedSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+
+	log.Infof("Reconciled app %s from %s to %s: %d missed, %d re-fired, %d failed",
+		appId, options.timeRange.StartTime, options.timeRange.EndTime, data.Missed, data.Refired, data.Failed)
+	s.recordRequestAppStatus(constants.ReconcileMissedSchedules, appId, constants.Success)
+	_ = json.NewEncoder(w).Encode(ReconciliationResponse{
+		Status: Status{
+			StatusCode:    constants.SuccessCode200,
+			StatusMessage: constants.Success,
+			StatusType:    constants.Success,
+			TotalCount:    len(data.Schedules),
+		},
+		Data: data,
+	})
+}
+
+// reconcileMissedSchedules scans the window and collects the schedules enriched with the Miss status, up to the limit
+func (s *Service) reconcileMissedSchedules(app store.App, options reconciliationOptions) (ReconciliationData, error) {
+	data := ReconciliationData{
+		AppId:     app.AppId,
+		StartTime: options.timeRange.StartTime.Unix(),
+		EndTime:   options.timeRange.EndTime.Unix(),
+		Schedules: []store.Schedule{},
+	}
+
+	err := s.scanWindow(app, options.timeRange, func(schedules []store.Schedule) (bool, error) {
+		data.Scanned += len(schedules)
+
+		enriched, err := s.ScheduleDao.OptimizedEnrichSchedule(schedules)
+		if err != nil {
+			return false, err
+		}
+		sort.SliceStable(enriched, func(i, j int) bool {
+			return enriched[i].ScheduleTime < enriched[j].ScheduleTime
+		})
+
+		for _, schedule := range enriched {
+			if schedule.Status != store.Miss {
+				continue
+			}
+			if data.Missed == options.limit {
+				data.Truncated = true
+				return false, nil
+			}
+
+			data.Missed++
+			if options.refire {
+				s.refire(app, schedule, &data)
+			}
+			data.Schedules = append(data.Schedules, schedule)
+		}
+		return true, nil
+	})
+
+	return data, err
+}
+
+// refire hands a missed schedule to its callback as a reconciliation
+func (s *Service) refire(app store.App, schedule store.Schedule, data *ReconciliationData) {
+	if err := schedule.Callback.Invoke(store.ScheduleWrapper{Schedule: schedule, App: app, IsReconciliation: true}); err != nil {
+		schedule.Logger().Errorf("Error re-firing missed schedule %s: %+v", schedule.ScheduleId, err)
+		data.Failed++
+		return
+	}
+	data.Refired++
+}
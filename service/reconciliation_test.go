@@ -0,0 +1,184 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/store"
+)
+
+const reconciliationCallbackType = "reconciliation_test"
+
+// refiredCallback records the schedules re-fired by a reconciliation, failing for the "fail" payload
+type refiredCallback struct {
+	Type string `json:"type"`
+}
+
+var refired []gocql.UUID
+
+func (c *refiredCallback) GetType() string {
+	return reconciliationCallbackType
+}
+
+func (c *refiredCallback) GetDetails() (string, error) {
+	return "{}", nil
+}
+
+func (c *refiredCallback) Marshal(m map[string]interface{}) error {
+	return nil
+}
+
+func (c *refiredCallback) Validate() error {
+	return nil
+}
+
+func (c *refiredCallback) UnmarshalJSON(data []byte) error {
+	return nil
+}
+
+func (c *refiredCallback) Invoke(wrapper store.ScheduleWrapper) error {
+	if !wrapper.IsReconciliation {
+		return errors.New("expected a reconciliation")
+	}
+	if wrapper.Schedule.Payload == "fail" {
+		return errors.New("fail")
+	}
+	refired = append(refired, wrapper.Schedule.ScheduleId)
+	return nil
+}
+
+// MockScheduleDaoForReconciliation serves the rows of the buckets and marks the fired schedules as successful
+type MockScheduleDaoForReconciliation struct {
+	MockScheduleDaoForResize
+	rows  []map[string]interface{}
+	fired map[gocql.UUID]bool
+}
+
+func (m *MockScheduleDaoForReconciliation) GetSchedulesForEntity(appId string, partitionId int, timeBucket time.Time, pageState []byte) db_wrapper.IterInterface {
+	var rows []map[string]interface{}
+	for _, row := range m.rows {
+		if row["partition_id"].(int) == partitionId && row["schedule_time_group"].(time.Time).Equal(timeBucket) {
+			rows = append(rows, row)
+		}
+	}
+	return &fakeIter{rows: rows}
+}
+
+func (m *MockScheduleDaoForReconciliation) OptimizedEnrichSchedule(schedules []store.Schedule) ([]store.Schedule, error) {
+	var enriched []store.Schedule
+	for _, schedule := range schedules {
+		if m.fired[schedule.ScheduleId] {
+			schedule.Status = store.Success
+		} else {
+			schedule.SetUnknownStatus(60)
+		}
+		enriched = append(enriched, schedule)
+	}
+	return enriched, nil
+}
+
+func (m *MockScheduleDaoForReconciliation) add(partitionId int, scheduleTime time.Time, payload string) gocql.UUID {
+	id := gocql.TimeUUID()
+	m.rows = append(m.rows, map[string]interface{}{
+		"app_id":              "test",
+		"partition_id":        partitionId,
+		"callback_type":       reconciliationCallbackType,
+		"payload":             payload,
+		"schedule_time_group": scheduleTime.Truncate(time.Minute),
+		"schedule_time":       scheduleTime,
+		"schedule_id":         id,
+	})
+	return id
+}
+
+func TestService_ReconcileMissedSchedules(t *testing.T) {
+	store.Registry[reconciliationCallbackType] = func() store.Callback { return &refiredCallback{Type: reconciliationCallbackType} }
+	defer delete(store.Registry, reconciliationCallbackType)
+
+	location, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the window of a scan is truncated to the minute
+	now := time.Now().In(location).Truncate(time.Minute)
+	start := now.Add(-30 * time.Minute)
+
+	scheduleDao := &MockScheduleDaoForReconciliation{fired: map[gocql.UUID]bool{}}
+	missed := scheduleDao.add(0, now.Add(-20*time.Minute), "missed")
+	failing := scheduleDao.add(1, now.Add(-15*time.Minute), "fail")
+	scheduleDao.fired[scheduleDao.add(0, now.Add(-10*time.Minute), "fired")] = true
+	scheduleDao.add(1, now.Add(-10*time.Second), "due")
+	scheduleDao.add(0, start.Add(-time.Second), "before")
+
+	service := setupMocks()
+	service.ClusterDao = MockClusterDaoForResize{}
+	service.ScheduleDao = scheduleDao
+
+	for _, test := range []struct {
+		appId   string
+		query   url.Values
+		status  int
+		missed  []gocql.UUID
+		refired []gocql.UUID
+		failed  int
+	}{
+		{"test", url.Values{"start_time": {start.Format(dateTimeLayout)}, "end_time": {now.Format(dateTimeLayout)}}, http.StatusOK, []gocql.UUID{missed, failing}, nil, 0},
+		{"test", url.Values{"start_time": {start.Format(dateTimeLayout)}, "end_time": {now.Format(dateTimeLayout)}, "refire": {"true"}}, http.StatusOK, []gocql.UUID{missed, failing}, []gocql.UUID{missed}, 1},
+		{"test", url.Values{"start_time": {start.Format(dateTimeLayout)}, "end_time": {now.Format(dateTimeLayout)}, "limit": {"1"}}, http.StatusOK, []gocql.UUID{missed}, nil, 0},
+		{"test", url.Values{"start_time": {now.Add(-25 * time.Hour).Format(dateTimeLayout)}, "end_time": {now.Format(dateTimeLayout)}}, http.StatusBadRequest, nil, nil, 0},
+		{"test", url.Values{"start_time": {now.Add(time.Hour).Format(dateTimeLayout)}, "end_time": {now.Add(2 * time.Hour).Format(dateTimeLayout)}}, http.StatusBadRequest, nil, nil, 0},
+		{"test", url.Values{"refire": {"maybe"}}, http.StatusBadRequest, nil, nil, 0},
+		{"testGetAppErrorNotFound", url.Values{}, http.StatusBadRequest, nil, nil, 0},
+	} {
+		refired = nil
+
+		req, err := http.NewRequest("POST", "/goscheduler/admin/apps/{appId}/reconcile?"+test.query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.ReconcileMissedSchedules)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.status {
+			t.Errorf("handler returned wrong status code for %v: got %v want %v", test.query, status, test.status)
+			continue
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response ReconciliationResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		data := response.Data
+		if data.Scanned != 4 && !data.Truncated {
+			t.Errorf("expected the 4 schedules of the window to be scanned for %v, got %d", test.query, data.Scanned)
+		}
+		if len(data.Schedules) != len(test.missed) || data.Missed != len(test.missed) {
+			t.Fatalf("expected missed schedules %v for %v, got %+v", test.missed, test.query, data)
+		}
+		for i, id := range test.missed {
+			if data.Schedules[i].ScheduleId != id || data.Schedules[i].Status != store.Miss {
+				t.Errorf("expected missed schedule %s at %d for %v, got %+v", id, i, test.query, data.Schedules[i])
+			}
+		}
+		if data.Truncated != (test.query.Get("limit") != "") {
+			t.Errorf("unexpected truncation for %v: %+v", test.query, data)
+		}
+		if len(refired) != len(test.refired) || data.Refired != len(test.refired) || data.Failed != test.failed {
+			t.Errorf("expected re-fired %v and %d failures for %v, got %v and %+v", test.refired, test.failed, test.query, refired, data)
+		}
+	}
+}
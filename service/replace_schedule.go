@@ -0,0 +1,255 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+// scheduleUpdate applies the request body to the existing schedule and returns the updated schedule
+type scheduleUpdate func(existing store.Schedule, body []byte) (store.Schedule, error)
+
+// ReplaceSchedule replaces all the mutable fields of a schedule with the ones in the request,
+// fields missing in the request are cleared
+func (s *Service) ReplaceSchedule(w http.ResponseWriter, r *http.Request) {
+	s.updateScheduleWith(w, r, constants.ReplaceSchedule, s.replaceSchedule)
+}
+
+// PatchSchedule applies a JSON Merge Patch (RFC 7396) to a schedule,
+// fields set to null in the patch are cleared
+func (s *Service) PatchSchedule(w http.ResponseWriter, r *http.Request) {
+	s.updateScheduleWith(w, r, constants.PatchSchedule, s.patchSchedule)
+}
+
+// replaceSchedule takes the request body as the new schedule
+func (s *Service) replaceSchedule(existing store.Schedule, body []byte) (store.Schedule, error) {
+	var input store.Schedule
+	if err := json.Unmarshal(body, &input); err != nil {
+		return store.Schedule{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	if err := s.validateImmutableFields(input, existing); err != nil {
+		return store.Schedule{}, err
+	}
+
+	return input, nil
+}
+
+// patchSchedule merges the request body into the existing schedule
+func (s *Service) patchSchedule(existing store.Schedule, body []byte) (store.Schedule, error) {
+	if existing.CallbackRaw == nil && existing.Callback != nil {
+		raw, err := json.Marshal(existing.Callback)
+		if err != nil {
+			return store.Schedule{}, er.NewError(er.DataFetchFailure, err)
+		}
+		existing.CallbackRaw = raw
+	}
+
+	target, err := json.Marshal(existing)
+	if err != nil {
+		return store.Schedule{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	patched, err := util.MergePatch(target, body)
+	if err != nil {
+		return store.Schedule{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	var input store.Schedule
+	if err = json.Unmarshal(patched, &input); err != nil {
+		return store.Schedule{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	if err = s.validateImmutableFields(input, existing); err != nil {
+		return store.Schedule{}, err
+	}
+
+	return input, nil
+}
+
+// updateScheduleWith updates a schedule with the supplied update and persists it after validation.
+// The identity and status of the schedule are always kept from the existing schedule.
+// One time schedules can only be updated while they are pending.
+func (s *Service) updateScheduleWith(w http.ResponseWriter, r *http.Request, requestName string, update scheduleUpdate) {
+	log := logger.FromContext(r.Context())
+	uuid, err := validateScheduleID(mux.Vars(r)["scheduleId"])
+	if err != nil {
+		s.recordRequestStatus(requestName, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.recordRequestStatus(requestName, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	existing, app, err := s.getUpdatableSchedule(uuid)
+	if err != nil {
+		s.recordRequestStatus(requestName, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	updatedSchedule, err := s.updateSchedule(*existing, app, b, update, logger.RequestID(r.Context()))
+	if err != nil {
+		log.Errorf("%s: %v", requestName, err)
+		s.recordRequestStatus(requestName, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	log.Debugf("Schedule with id %s updated", uuid.String())
+	s.recordRequestStatus(requestName, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: "Schedule updated successfully",
+		StatusType:    constants.Success,
+		TotalCount:    1,
+	}
+	_ = json.NewEncoder(w).Encode(
+		UpdatedScheduleResponse{
+			Status: status,
+			Data:   UpdatedScheduleData{Schedule: updatedSchedule},
+		})
+}
+
+// updateSchedule applies an update to an existing schedule and persists it.
+// The replaced definition of a recurring schedule is kept as a version.
+func (s *Service) updateSchedule(existing store.Schedule, app store.App, body []byte, update scheduleUpdate, requestId string) (store.Schedule, error) {
+	schedule, err := update(existing, body)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	schedule.ScheduleId = existing.ScheduleId
+	schedule.RequestId = requestId
+	schedule.AppId = existing.AppId
+	schedule.PartitionId = existing.PartitionId
+	schedule.Status = existing.Status
+	schedule.PausedAt = existing.PausedAt
+	schedule.PayloadEncoding = existing.PayloadEncoding
+	schedule.TraceId = existing.TraceId
+	schedule.ScheduleGroup = 60 * (schedule.ScheduleTime / 60)
+	schedule.SetDefaultAnchor()
+
+	if err = validateReplacement(existing, schedule); err != nil {
+		return store.Schedule{}, er.NewError(er.UnprocessableEntity, err)
+	}
+
+	if err = s.validateUpdatedSchedule(&schedule, app); err != nil {
+		return store.Schedule{}, err
+	}
+
+	// a one time schedule moved into a bucket the poller already read would never fire
+	if !schedule.IsRecurring() && s.bucketPolled(schedule.ScheduleGroup) {
+		return store.Schedule{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("schedule time: %d is within a minute which may already be polled, it must be in a later minute", schedule.ScheduleTime))
+	}
+
+	var updatedSchedule store.Schedule
+	if schedule.IsRecurring() {
+		updatedSchedule, err = s.updateVersionedSchedule(existing, schedule, requestId)
+	} else {
+		updatedSchedule, err = s.ScheduleDao.UpdateOneTimeSchedule(existing, schedule, app)
+	}
+	if err != nil {
+		return store.Schedule{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	store.PublishEvent(store.ScheduleUpdated, updatedSchedule)
+	return updatedSchedule, nil
+}
+
+// getUpdatableSchedule fetches the schedule to be updated along with its app.
+// Deleted schedules and one time schedules which are no longer pending cannot be updated.
+func (s *Service) getUpdatableSchedule(uuid gocql.UUID) (*store.Schedule, store.App, error) {
+	existingSchedule, err := s.ScheduleDao.GetSchedule(uuid)
+	if err != nil {
+		if err == gocql.ErrNotFound {
+			return nil, store.App{}, er.NewError(er.DataNotFound, fmt.Errorf("schedule with id: %s not found", uuid))
+		}
+		return nil, store.App{}, er.NewError(er.DataPersistenceFailure, fmt.Errorf("error fetching existing schedule with id %s: %w", uuid, err))
+	}
+
+	if existingSchedule.Status == store.Deleted {
+		return nil, store.App{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("schedule with id: %s is deleted", uuid))
+	}
+
+	if !existingSchedule.IsRecurring() {
+		if err = s.ScheduleDao.EnrichSchedule(&existingSchedule); err != nil {
+			return nil, store.App{}, er.NewError(er.DataPersistenceFailure, fmt.Errorf("error fetching status of schedule with id %s: %w", uuid, err))
+		}
+
+		if existingSchedule.Status != store.Scheduled || s.bucketPolled(existingSchedule.ScheduleGroup) {
+			return nil, store.App{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("schedule with id: %s is not pending", uuid))
+		}
+	}
+
+	app, err := s.getApp(existingSchedule.AppId)
+	if err != nil {
+		return nil, store.App{}, err
+	}
+
+	return &existingSchedule, app, nil
+}
+
+// bucketPolled tells whether the poller may have read the minute bucket of the schedule group already.
+// The poller picks up a whole minute bucket at once, so a schedule in the current bucket may already be firing, and
+// with prefetch the next bucket is read up to PrefetchSeconds before it begins.
+func (s *Service) bucketPolled(scheduleGroup int64) bool {
+	return scheduleGroup <= s.Config.Poller.LastPolledBucket(clock.Now()).Unix()
+}
+
+// bucketPrefetched tells whether the minute bucket of the schedule group has not begun yet but may already have
+// been read ahead of its minute by the poller
+func (s *Service) bucketPrefetched(scheduleGroup int64) bool {
+	return scheduleGroup > clock.Now().Truncate(time.Minute).Unix() && s.bucketPolled(scheduleGroup)
+}
+
+// validateReplacement checks the fields which a replaced schedule cannot be validated without
+func validateReplacement(existing, schedule store.Schedule) error {
+	switch {
+	case existing.IsRecurring() && !schedule.IsRecurring():
+		return errors.New("one of 'cronExpression', 'every' or 'rrule' is required for a recurring schedule")
+	case !existing.IsRecurring() && schedule.IsRecurring():
+		return errors.New("a one time schedule cannot be changed into a recurring schedule")
+	}
+	if schedule.Callback == nil {
+		return errors.New("missing 'callback' parameter, cannot continue")
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+var updateOneTimeScheduleCallCount int
+var lastUpdateOneTimeScheduleInput store.Schedule
+
+func (m *MockScheduleDaoForUpdate) UpdateOneTimeSchedule(existing store.Schedule, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	updateOneTimeScheduleCallCount++
+	lastUpdateOneTimeScheduleInput = schedule
+	return schedule, nil
+}
+
+func TestService_ReplaceSchedule(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+
+	tests := []struct {
+		name       string
+		scheduleID string
+		body       []byte
+		wantStatus int
+	}{
+		{
+			name:       "FullReplacement",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"cronExpression":"*/10 * * * *","payload":"{}","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "MissingCallback",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"cronExpression":"*/10 * * * *"}`),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "MissingRecurrence",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "AppIdMismatch",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"appId":"differentApp","cronExpression":"*/10 * * * *","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "OneTimeScheduleNotPending",
+			scheduleID: "11111111-1111-1111-1111-111111111111",
+			body:       []byte(`{"cronExpression":"*/10 * * * *","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "OneTimeSchedule",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       []byte(fmt.Sprintf(`{"scheduleTime":%d,"payload":"{}","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`, time.Now().Unix()+7200)),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "OneTimeScheduleInPast",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       []byte(fmt.Sprintf(`{"scheduleTime":%d,"payload":"{}","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`, time.Now().Unix()-60)),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "OneTimeScheduleInPolledBucket",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       []byte(fmt.Sprintf(`{"scheduleTime":%d,"payload":"{}","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`, time.Now().Unix())),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "OneTimeScheduleToRecurring",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       []byte(`{"cronExpression":"*/10 * * * *","payload":"{}","callback":{"type":"http","details":{"url":"http://example.com","method":"POST"}}}`),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("PUT", "/goscheduler/schedules/{scheduleId}", bytes.NewBuffer(tc.body))
+			if err != nil {
+				t.Fatalf("could not create request: %v", err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"scheduleId": tc.scheduleID})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.ReplaceSchedule).ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("unexpected status code: got %v, want %v, body: %s", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestService_PatchSchedule(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+
+	tests := []struct {
+		name       string
+		scheduleID string
+		body       []byte
+		wantStatus int
+		check      func(t *testing.T)
+	}{
+		{
+			name:       "ChangePayload",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"payload":"{\"updated\":\"value\"}"}`),
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T) {
+				if lastUpdateRecurringScheduleInput.CronExpression != "0 0 * * *" {
+					t.Errorf("expected cron expression to be kept, got %q", lastUpdateRecurringScheduleInput.CronExpression)
+				}
+				if lastUpdateRecurringScheduleInput.GetCallBackType() != "http" {
+					t.Errorf("expected callback to be kept, got %q", lastUpdateRecurringScheduleInput.GetCallBackType())
+				}
+			},
+		},
+		{
+			name:       "SwitchToInterval",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"cronExpression":null,"every":"15m"}`),
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T) {
+				if lastUpdateRecurringScheduleInput.CronExpression != "" || lastUpdateRecurringScheduleInput.Every != "15m" {
+					t.Errorf("expected interval recurrence, got cron %q every %q",
+						lastUpdateRecurringScheduleInput.CronExpression, lastUpdateRecurringScheduleInput.Every)
+				}
+			},
+		},
+		{
+			name:       "MoveOneTimeSchedule",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       []byte(fmt.Sprintf(`{"scheduleTime":%d}`, time.Now().Unix()+7200)),
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T) {
+				if updateOneTimeScheduleCallCount != 1 {
+					t.Fatalf("expected UpdateOneTimeSchedule to be called once, got %d", updateOneTimeScheduleCallCount)
+				}
+				s := lastUpdateOneTimeScheduleInput
+				if s.ScheduleGroup != 60*(s.ScheduleTime/60) {
+					t.Errorf("expected schedule group %d, got %d", 60*(s.ScheduleTime/60), s.ScheduleGroup)
+				}
+				if s.Payload != `{"foo":"bar"}` {
+					t.Errorf("expected payload to be kept, got %q", s.Payload)
+				}
+			},
+		},
+		{
+			name:       "MoveOneTimeScheduleIntoPolledBucket",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       []byte(fmt.Sprintf(`{"scheduleTime":%d}`, time.Now().Unix())),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "ClearRecurrence",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"cronExpression":null}`),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "ClearCallback",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{"callback":null}`),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "MalformedJSON",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`{bad json`),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "NonObjectPatch",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       []byte(`[1]`),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updateRecurringScheduleCallCount = 0
+			updateOneTimeScheduleCallCount = 0
+
+			req, err := http.NewRequest("PATCH", "/goscheduler/schedules/{scheduleId}", bytes.NewBuffer(tc.body))
+			if err != nil {
+				t.Fatalf("could not create request: %v", err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"scheduleId": tc.scheduleID})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.PatchSchedule).ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("unexpected status code: got %v, want %v, body: %s", rr.Code, tc.wantStatus, rr.Body.String())
+			}
+			if tc.check != nil && rr.Code == http.StatusOK {
+				tc.check(t)
+			}
+		})
+	}
+}
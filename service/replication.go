@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/replication"
+)
+
+// GetReplicationStatus returns the replication role of this node and the progress of the replicated event stream
+func (s *Service) GetReplicationStatus(w http.ResponseWriter, r *http.Request) {
+	s.recordRequestStatus(constants.GetReplicationStatus, constants.Success)
+	s.writeReplicationStatus(w)
+}
+
+// PromoteReplica promotes a standby cluster to primary so that it starts firing its schedules.
+// The role is persisted for the cluster, the other nodes of the standby pick it up within the role refresh interval.
+func (s *Service) PromoteReplica(w http.ResponseWriter, r *http.Request) {
+	if err := s.promoteReplica(); err != nil {
+		s.recordRequestStatus(constants.PromoteReplica, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Promoted cluster %s to primary", s.Config.Cluster.ClusterName)
+	s.recordRequestStatus(constants.PromoteReplica, constants.Success)
+	s.writeReplicationStatus(w)
+}
+
+func (s *Service) promoteReplica() error {
+	state := replication.Default()
+	if !state.IsStandby() {
+		return er.NewError(er.Conflict, errors.New("cluster is not a replication standby"))
+	}
+
+	if err := s.ClusterDao.UpdateReplicationRole(s.Config.Cluster.ClusterName, replication.RolePrimary); err != nil {
+		return er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	state.Promote(time.Now())
+	return nil
+}
+
+func (s *Service) writeReplicationStatus(w http.ResponseWriter) {
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		ReplicationStatusResponse{
+			Status: status,
+			Data:   replication.Default().Status(),
+		})
+}
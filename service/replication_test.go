@@ -0,0 +1,65 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/replication"
+)
+
+func TestService_PromoteReplica(t *testing.T) {
+	defer replication.Default().SetRole("")
+
+	for _, test := range []struct {
+		clusterName string
+		role        string
+		Status      int
+		expected    string
+	}{
+		{"goscheduler", "", http.StatusConflict, ""},
+		{"goscheduler", replication.RolePrimary, http.StatusConflict, replication.RolePrimary},
+		{"testUpdateReplicationRoleError", replication.RoleStandby, http.StatusInternalServerError, replication.RoleStandby},
+		{"goscheduler", replication.RoleStandby, http.StatusOK, replication.RolePrimary},
+	} {
+		service := &Service{
+			Config:      &conf.Configuration{Cluster: conf.ClusterConfig{ClusterName: test.clusterName}},
+			Supervisor:  new(cluster.DummySupervisor),
+			ClusterDao:  new(dao.DummyClusterDaoImpl),
+			ScheduleDao: new(dao.DummyScheduleDaoImpl),
+		}
+		replication.Default().SetRole(test.role)
+
+		req, err := http.NewRequest("POST", "/goscheduler/admin/replication/promote", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.PromoteReplica)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.Status {
+			t.Errorf("handler returned wrong status code for role %q: got %v want %v", test.role, status, test.Status)
+			continue
+		}
+		if role := replication.Default().Role(); role != test.expected {
+			t.Errorf("expected role %q after promoting %q, got %q", test.expected, test.role, role)
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response ReplicationStatusResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Data.Role != replication.RolePrimary || response.Data.PromotedAt == 0 {
+			t.Errorf("unexpected replication status %+v", response.Data)
+		}
+	}
+}
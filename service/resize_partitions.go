@@ -0,0 +1,273 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	// Buckets starting within the margin are left in place, they are about to be polled from the old partitions
+	migrationSafetyMargin = 2 * time.Minute
+	// Number of buckets migrated between two checkpoints of the migration progress
+	migrationCheckpointBuckets = 60
+)
+
+type resizeRequest struct {
+	Partitions uint32 `json:"partitions"`
+}
+
+// ResizePartitions increases the partition count of an app and migrates its pending schedules to the new
+// layout in the background. Repeating the request with the same partition count resumes a migration
+// that was interrupted.
+func (s *Service) ResizePartitions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	var input resizeRequest
+	b, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(b, &input)
+	}
+	if err != nil {
+		s.recordRequestAppStatus(constants.ResizeAppPartitions, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	migration, err := s.StartPartitionMigration(appId, input.Partitions)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ResizeAppPartitions, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.ResizeAppPartitions, appId, constants.Success)
+	logger.FromContext(r.Context()).Infof("Resizing app %s from %d to %d partitions", appId, migration.FromPartitions, migration.ToPartitions)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success}
+	_ = json.NewEncoder(w).Encode(PartitionMigrationResponse{Status: status, Data: PartitionMigrationData{Migration: migration}})
+}
+
+// GetPartitionMigration returns the progress of the latest partition migration of an app.
+func (s *Service) GetPartitionMigration(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	migration, err := s.fetchPartitionMigration(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetPartitionMigration, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetPartitionMigration, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success}
+	_ = json.NewEncoder(w).Encode(PartitionMigrationResponse{Status: status, Data: PartitionMigrationData{Migration: migration}})
+}
+
+func (s *Service) fetchPartitionMigration(appId string) (store.PartitionMigration, error) {
+	switch migration, err := s.ClusterDao.GetPartitionMigration(appId); {
+	case err == gocql.ErrNotFound:
+		return store.PartitionMigration{}, er.NewError(er.DataNotFound, errors.New(fmt.Sprintf("app %s was never resized", appId)))
+	case err != nil:
+		return store.PartitionMigration{}, er.NewError(er.DataFetchFailure, err)
+	default:
+		return migration, nil
+	}
+}
+
+// StartPartitionMigration creates the pollers of the new partitions, switches the app to the new partition count
+// and starts moving the pending schedules in the background.
+// New schedules are spread over all the partitions as soon as the count is switched. Reads spanning the partitions
+// of an app use the new count, which includes every old partition, so a schedule is found whether it was moved or not.
+func (s *Service) StartPartitionMigration(appId string, partitions uint32) (store.PartitionMigration, error) {
+	if appId == s.Config.CronConfig.App {
+		return store.PartitionMigration{}, er.NewError(er.InvalidDataCode, errors.New("partitions of the cron app cannot be resized"))
+	}
+
+	app, err := s.getApp(appId)
+	if err != nil {
+		return store.PartitionMigration{}, err
+	}
+
+	existing, err := s.ClusterDao.GetPartitionMigration(appId)
+	switch {
+	case err == gocql.ErrNotFound:
+	case err != nil:
+		return store.PartitionMigration{}, er.NewError(er.DataFetchFailure, err)
+	case existing.Status == store.MigrationRunning && existing.ToPartitions != partitions:
+		return store.PartitionMigration{}, er.NewError(er.Conflict, errors.New(fmt.Sprintf("a migration to %d partitions is running for app %s", existing.ToPartitions, appId)))
+	case existing.Status != store.MigrationCompleted && existing.ToPartitions == partitions && app.Partitions == partitions:
+		existing.Status = store.MigrationRunning
+		existing.Error = ""
+		go s.migratePartitions(app, existing)
+		return existing, nil
+	}
+
+	if partitions <= app.Partitions {
+		return store.PartitionMigration{}, er.NewError(er.InvalidDataCode, errors.New(fmt.Sprintf("partitions can only be increased, app %s has %d partitions", appId, app.Partitions)))
+	}
+
+	now := time.Now()
+	migration := store.PartitionMigration{
+		AppId:          appId,
+		FromPartitions: app.Partitions,
+		ToPartitions:   partitions,
+		Status:         store.MigrationRunning,
+		StartedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.ClusterDao.UpsertPartitionMigration(migration); err != nil {
+		return store.PartitionMigration{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	resized := app
+	resized.Partitions = partitions
+
+	// The pollers are started before switching the count so that no schedule lands on an unpolled partition
+	if err := s.createEntities(resized, app.Partitions); err != nil {
+		return store.PartitionMigration{}, err
+	}
+
+	if err := s.ClusterDao.UpdateAppPartitions(appId, partitions); err != nil {
+		return store.PartitionMigration{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	s.Supervisor.UpdateApp(appId)
+
+	go s.migratePartitions(resized, migration)
+	return migration, nil
+}
+
+// migratePartitions moves the pending schedules of the partitions before the resize to the partition they belong to
+// in the new layout, bucket by bucket up to the furthest time a schedule can be created at.
+func (s *Service) migratePartitions(app store.App, migration store.PartitionMigration) {
+	start := time.Now().Add(migrationSafetyMargin).Truncate(time.Minute)
+	if migration.Cursor.After(start) {
+		start = migration.Cursor.Add(time.Minute)
+	}
+	end := time.Now().Add(time.Duration(app.GetMaxTTL(s.Config.AppLevelConfiguration.FutureScheduleCreationPeriod)) * time.Second)
+
+	migration = s.migrate(app, migration, start, end)
+	if err := s.ClusterDao.UpsertPartitionMigration(migration); err != nil {
+		logger.Errorf("Error: %s while saving migration %+v", err.Error(), migration)
+	}
+}
+
+// migrate moves the schedules of the buckets between start and end and returns the final state of the migration.
+// The progress is saved periodically so that an interrupted migration can be resumed.
+func (s *Service) migrate(app store.App, migration store.PartitionMigration, start time.Time, end time.Time) store.PartitionMigration {
+	buckets := 0
+	for bucket := start; !bucket.After(end); bucket = bucket.Add(time.Minute) {
+		if bucket.Before(time.Now().Add(migrationSafetyMargin)) {
+			continue
+		}
+
+		for partitionId := 0; partitionId < int(migration.FromPartitions); partitionId++ {
+			moved, err := s.migrateBucket(app, partitionId, bucket)
+			migration.Migrated += moved
+			if err != nil {
+				logger.Errorf("Error: %s while migrating partition %d of app %s for bucket %v", err.Error(), partitionId, app.AppId, bucket)
+				migration.Status = store.MigrationFailed
+				migration.Error = err.Error()
+				migration.UpdatedAt = time.Now()
+				return migration
+			}
+		}
+
+		migration.Cursor = bucket
+		if buckets++; buckets%migrationCheckpointBuckets == 0 {
+			migration.UpdatedAt = time.Now()
+			if err := s.ClusterDao.UpsertPartitionMigration(migration); err != nil {
+				logger.Errorf("Error: %s while saving progress of migration %+v", err.Error(), migration)
+			}
+		}
+	}
+
+	logger.Infof("Migrated %d schedules of app %s to %d partitions", migration.Migrated, app.AppId, migration.ToPartitions)
+	migration.Status = store.MigrationCompleted
+	migration.UpdatedAt = time.Now()
+	return migration
+}
+
+// migrateBucket moves the schedules of a partition in a bucket which belong to another partition in the new layout.
+// Returns the number of schedules moved.
+func (s *Service) migrateBucket(app store.App, partitionId int, bucket time.Time) (int, error) {
+	schedules, err := s.getBucketSchedules(app.AppId, partitionId, bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, schedule := range schedules {
+		target := schedule.PartitionFor(app.Partitions)
+		if target == partitionId {
+			continue
+		}
+
+		if _, err := s.ScheduleDao.MoveSchedule(schedule, target, app); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+// getBucketSchedules reads all the pages of the schedules of a partition in a bucket
+func (s *Service) getBucketSchedules(appId string, partitionId int, bucket time.Time) ([]store.Schedule, error) {
+	var schedules []store.Schedule
+	var pageState []byte
+
+	for {
+		iter := s.ScheduleDao.GetSchedulesForEntity(appId, partitionId, bucket, pageState)
+		_map := make(map[string]interface{})
+		for iter.MapScan(_map) {
+			schedule := store.Schedule{}
+			if err := schedule.CreateScheduleFromCassandraMap(_map); err != nil {
+				iter.Close()
+				return nil, err
+			}
+			schedules = append(schedules, schedule)
+			_map = make(map[string]interface{})
+		}
+
+		pageState = iter.PageState()
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+		if len(pageState) == 0 {
+			return schedules, nil
+		}
+	}
+}
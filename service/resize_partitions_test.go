@@ -0,0 +1,216 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/store"
+)
+
+// Custom mock implementation for resize tests, every app has 2 partitions and
+// accepts schedules for a day so that the background migration ends quickly
+type MockClusterDaoForResize struct {
+	dao.DummyClusterDaoImpl
+}
+
+func (m MockClusterDaoForResize) GetApp(appName string) (store.App, error) {
+	app, err := m.DummyClusterDaoImpl.GetApp(appName)
+	if err == nil && len(app.AppId) > 0 {
+		app.Partitions = 2
+		app.Configuration.FutureScheduleCreationPeriod = 1
+	}
+	return app, err
+}
+
+type MockScheduleDaoForResize struct {
+	dao.DummyScheduleDaoImpl
+	mu      sync.Mutex
+	buckets map[time.Time][]map[string]interface{}
+	moved   map[gocql.UUID]int
+	fail    bool
+}
+
+func (m *MockScheduleDaoForResize) GetSchedulesForEntity(appId string, partitionId int, timeBucket time.Time, pageState []byte) db_wrapper.IterInterface {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var rows []map[string]interface{}
+	for _, row := range m.buckets[timeBucket] {
+		if row["partition_id"].(int) == partitionId {
+			rows = append(rows, row)
+		}
+	}
+	return &fakeIter{rows: rows}
+}
+
+func (m *MockScheduleDaoForResize) MoveSchedule(schedule store.Schedule, partitionId int, app store.App) (store.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fail {
+		return schedule, errors.New("error")
+	}
+	if m.moved == nil {
+		m.moved = map[gocql.UUID]int{}
+	}
+	m.moved[schedule.ScheduleId] = partitionId
+	schedule.PartitionId = partitionId
+	return schedule, nil
+}
+
+type fakeIter struct {
+	rows []map[string]interface{}
+}
+
+func (i *fakeIter) Close() error { return nil }
+
+func (i *fakeIter) Scan(...interface{}) bool { return false }
+
+func (i *fakeIter) PageState() []byte { return nil }
+
+func (i *fakeIter) MapScan(m map[string]interface{}) bool {
+	if len(i.rows) == 0 {
+		return false
+	}
+	for key, value := range i.rows[0] {
+		m[key] = value
+	}
+	i.rows = i.rows[1:]
+	return true
+}
+
+func TestService_ResizePartitions(t *testing.T) {
+	service := setupMocks()
+	service.ClusterDao = MockClusterDaoForResize{}
+	service.ScheduleDao = &MockScheduleDaoForResize{}
+
+	for _, test := range []struct {
+		appId  string
+		body   string
+		Status int
+	}{
+		{"test", `{"partitions": 4}`, http.StatusOK},
+		{"test", `{"partitions": 2}`, http.StatusBadRequest},
+		{"test", `{"partitions": }`, http.StatusBadRequest},
+		{"Athena", `{"partitions": 4}`, http.StatusBadRequest},
+		{"testGetAppErrorNotFound", `{"partitions": 4}`, http.StatusBadRequest},
+		{"testMigrationRunning", `{"partitions": 8}`, http.StatusConflict},
+		{"testGetMigrationError", `{"partitions": 4}`, http.StatusInternalServerError},
+		{"testUpsertMigrationError", `{"partitions": 4}`, http.StatusInternalServerError},
+		{"testUpdateAppPartitionsError", `{"partitions": 4}`, http.StatusInternalServerError},
+	} {
+		req, err := http.NewRequest("PUT", "/goscheduler/apps/{appId}/partitions", bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.ResizePartitions)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.Status {
+			t.Errorf("handler returned wrong status code for %s %s: got %v want %v", test.appId, test.body, status, test.Status)
+		}
+	}
+}
+
+func TestService_GetPartitionMigration(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		Status int
+	}{
+		{"testMigrationCompleted", http.StatusOK},
+		{"test", http.StatusNotFound},
+		{"testGetMigrationError", http.StatusInternalServerError},
+	} {
+		req, err := http.NewRequest("GET", "/goscheduler/apps/{appId}/partitions", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(service.GetPartitionMigration)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != test.Status {
+			t.Errorf("handler returned wrong status code for %s: got %v want %v", test.appId, status, test.Status)
+		}
+	}
+}
+
+// scheduleIdFor returns a schedule id which is in partition 0 with 1 partition and in the given partition with 4
+func scheduleIdFor(partitionId int) gocql.UUID {
+	for {
+		schedule := store.Schedule{ScheduleId: gocql.TimeUUID()}
+		if schedule.PartitionFor(4) == partitionId {
+			return schedule.ScheduleId
+		}
+	}
+}
+
+func TestService_Migrate(t *testing.T) {
+	service := setupMocks()
+	app := store.App{AppId: "test", Partitions: 4, Active: true}
+
+	start := time.Now().Add(10 * time.Minute).Truncate(time.Minute)
+	stay, move := scheduleIdFor(0), scheduleIdFor(3)
+
+	row := func(id gocql.UUID) map[string]interface{} {
+		return map[string]interface{}{
+			"app_id":              "test",
+			"partition_id":        0,
+			"schedule_id":         id,
+			"schedule_time_group": start,
+			"schedule_time":       start,
+			"callback_type":       "http",
+			"callback_details":    `{"url":"http://localhost:8080/callback","method":"POST"}`,
+			"payload":             "{}",
+		}
+	}
+
+	for _, test := range []struct {
+		fail     bool
+		status   store.MigrationStatus
+		migrated int
+	}{
+		{false, store.MigrationCompleted, 1},
+		{true, store.MigrationFailed, 0},
+	} {
+		scheduleDao := &MockScheduleDaoForResize{
+			buckets: map[time.Time][]map[string]interface{}{start: {row(stay), row(move)}},
+			fail:    test.fail,
+		}
+		service.ScheduleDao = scheduleDao
+
+		migration := service.migrate(app, store.PartitionMigration{AppId: "test", FromPartitions: 1, ToPartitions: 4}, start, start.Add(2*time.Minute))
+
+		if migration.Status != test.status || migration.Migrated != test.migrated {
+			t.Errorf("expected status %s with %d migrated, got %+v", test.status, test.migrated, migration)
+		}
+		if test.fail {
+			continue
+		}
+		if !migration.Cursor.Equal(start.Add(2 * time.Minute)) {
+			t.Errorf("expected cursor %v, got %v", start.Add(2*time.Minute), migration.Cursor)
+		}
+		if _, found := scheduleDao.moved[stay]; found {
+			t.Errorf("schedule %s already in its partition was moved", stay)
+		}
+		if partitionId := scheduleDao.moved[move]; partitionId != 3 {
+			t.Errorf("expected schedule %s to move to partition 3, got %d", move, partitionId)
+		}
+	}
+}
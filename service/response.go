@@ -21,6 +21,11 @@ package service
 
 import (
 	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/db_wrapper"
+	"github.com/myntra/goscheduler/diagnostics"
+	"github.com/myntra/goscheduler/replication"
+	"github.com/myntra/goscheduler/sla"
 	s "github.com/myntra/goscheduler/store"
 )
 
@@ -36,6 +41,41 @@ type CreateAppData struct {
 	Configuration s.Configuration `json:"configuration"`
 }
 
+// CloneAppResponse contains the app registered from another app
+type CloneAppResponse struct {
+	Status Status       `json:"status"`
+	Data   CloneAppData `json:"data"`
+}
+
+// CloneAppData is the app registered along with the app it was cloned from and the callback templates copied to it
+type CloneAppData struct {
+	CreateAppData
+	ClonedFrom string   `json:"clonedFrom"`
+	Templates  []string `json:"templates"`
+}
+
+// ApplySchedulesResponse contains the changes made to the declared schedules of an app
+type ApplySchedulesResponse struct {
+	Status Status             `json:"status"`
+	Data   ApplySchedulesData `json:"data"`
+}
+
+// ApplySchedulesData lists the declared schedules created, updated, deleted and left unchanged, or which would be on a
+// dry run
+type ApplySchedulesData struct {
+	DryRun    bool             `json:"dryRun"`
+	Created   []DeclaredChange `json:"created"`
+	Updated   []DeclaredChange `json:"updated"`
+	Deleted   []DeclaredChange `json:"deleted"`
+	Unchanged []DeclaredChange `json:"unchanged"`
+}
+
+// DeclaredChange is a declared schedule, the name of a deleted schedule is no longer known
+type DeclaredChange struct {
+	Name       string     `json:"name,omitempty"`
+	ScheduleId gocql.UUID `json:"scheduleId"`
+}
+
 type UpdateAppActiveStatusResponse struct {
 	Status Status                    `json:"status"`
 	Data   UpdateAppActiveStatusData `json:"data"`
@@ -133,7 +173,19 @@ type GetConfigurationData struct {
 }
 
 type GetAppsData struct {
-	Apps []s.App `json:"apps"`
+	Apps []AppWithLimits `json:"apps"`
+}
+
+// AppWithLimits is a registered app along with the limits its schedules are held to
+type AppWithLimits struct {
+	s.App
+	Limits AppLimits `json:"limits"`
+}
+
+// AppLimits are the limits in force for the schedules of an app, the ones of its configuration or else the ones of
+// the cluster
+type AppLimits struct {
+	MaxPayloadSize int `json:"maxPayloadSize"`
 }
 
 type GetConfigurationResponse struct {
@@ -148,6 +200,7 @@ type CreateScheduleResponse struct {
 
 type CreateScheduleData struct {
 	Schedule s.Schedule `json:"schedule"`
+	Warnings []string   `json:"warnings,omitempty"` // callback urls found unreachable by a probe in warn mode
 }
 
 type CreateConfigurationData struct {
@@ -196,8 +249,10 @@ type ScheduleResponse struct {
 }
 
 // Used for pause and resume schedule responses
+// Actions holds the one time schedules created for a future dated pause or resume
 type ScheduleData struct {
-	Schedule s.Schedule `json:"schedule"`
+	Schedule s.Schedule   `json:"schedule"`
+	Actions  []s.Schedule `json:"actions,omitempty"`
 }
 
 // UpdatedScheduleResponse is the response structure for the updateRecurringSchedule endpoint
@@ -210,3 +265,434 @@ type UpdatedScheduleResponse struct {
 type UpdatedScheduleData struct {
 	Schedule s.Schedule `json:"schedule"`
 }
+
+// GetDeliveryReceiptsResponse is the response structure for the receipts endpoint
+type GetDeliveryReceiptsResponse struct {
+	Status Status                  `json:"status"`
+	Data   GetDeliveryReceiptsData `json:"data"`
+}
+
+// GetDeliveryReceiptsData contains the signed delivery receipts of a schedule
+type GetDeliveryReceiptsData struct {
+	Receipts []s.DeliveryReceipt `json:"receipts"`
+}
+
+// GetDueRunsResponse is the response structure for the due runs endpoint
+type GetDueRunsResponse struct {
+	Status Status         `json:"status"`
+	Data   GetDueRunsData `json:"data"`
+}
+
+// GetDueRunsData contains the due runs leased to the consumer
+type GetDueRunsData struct {
+	Runs []s.DueRun `json:"runs"`
+}
+
+// AckDueRunResponse is the response structure for the due run acknowledgement endpoint
+type AckDueRunResponse struct {
+	Status Status        `json:"status"`
+	Data   AckDueRunData `json:"data"`
+}
+
+// AckDueRunData contains the acknowledged outcome of the due run
+type AckDueRunData struct {
+	ScheduleId string   `json:"scheduleId"`
+	Status     s.Status `json:"status"`
+}
+
+// LockResponse is the response structure for the lock endpoints
+type LockResponse struct {
+	Status Status `json:"status"`
+	Data   s.Lock `json:"data"`
+}
+
+// GetCallbackAttemptsResponse is the response structure for the callback attempts endpoint
+type GetCallbackAttemptsResponse struct {
+	Status Status                  `json:"status"`
+	Data   GetCallbackAttemptsData `json:"data"`
+}
+
+// GetCallbackAttemptsData contains the execution logs of the callback attempts of a schedule
+type GetCallbackAttemptsData struct {
+	Attempts []s.CallbackAttempt `json:"attempts"`
+}
+
+// GetScheduleVersionsResponse is the response structure for the schedule versions endpoint
+type GetScheduleVersionsResponse struct {
+	Status Status                  `json:"status"`
+	Data   GetScheduleVersionsData `json:"data"`
+}
+
+// GetScheduleVersionsData contains the versions of the definition of a recurring schedule
+type GetScheduleVersionsData struct {
+	Versions []s.ScheduleVersion `json:"versions"`
+}
+
+// GetCallbackCanaryResponse is the response structure for the callback canary endpoint
+type GetCallbackCanaryResponse struct {
+	Status Status                `json:"status"`
+	Data   GetCallbackCanaryData `json:"data"`
+}
+
+// GetCallbackCanaryData contains the canary of the callback of a schedule with the comparison of its callbacks
+type GetCallbackCanaryData struct {
+	Canary     s.CallbackCanary   `json:"canary"`
+	Comparison s.CanaryComparison `json:"comparison"`
+	Results    []s.CanaryResult   `json:"results"`
+}
+
+// SimulateScheduleResponse is the response structure for the simulate endpoint
+type SimulateScheduleResponse struct {
+	Status Status             `json:"status"`
+	Data   s.SimulationResult `json:"data"`
+}
+
+// ValidateScheduleResponse is the response structure for the validate endpoint
+type ValidateScheduleResponse struct {
+	Status Status               `json:"status"`
+	Data   ValidateScheduleData `json:"data"`
+}
+
+// ValidateScheduleData contains the upcoming run times of a valid schedule in epoch seconds
+type ValidateScheduleData struct {
+	Valid    bool    `json:"valid"`
+	NextRuns []int64 `json:"nextRuns"`
+}
+
+// GetDiagnosticsResponse is the response structure for the diagnostics endpoint
+type GetDiagnosticsResponse struct {
+	Status Status             `json:"status"`
+	Data   GetDiagnosticsData `json:"data"`
+}
+
+// GetDiagnosticsData contains the slowest queries, the poll lag and the firing lag of the last minute per partition
+// and, when requested for an app, its partitions ordered by pending schedule count
+type GetDiagnosticsData struct {
+	SlowQueries []diagnostics.SlowQuery `json:"slowQueries"`
+	PollLag     []diagnostics.PollLag   `json:"pollLag"`
+	FiringLag   []sla.FiringLag         `json:"firingLag"`
+	Partitions  []PartitionSize         `json:"partitions,omitempty"`
+}
+
+// PartitionSize is the number of schedules pending in a partition of an app
+type PartitionSize struct {
+	AppId            string `json:"appId"`
+	PartitionId      int    `json:"partitionId"`
+	PendingSchedules int    `json:"pendingSchedules"`
+}
+
+// PartitionMigrationResponse is the response structure for the partition resize endpoints
+type PartitionMigrationResponse struct {
+	Status Status                 `json:"status"`
+	Data   PartitionMigrationData `json:"data"`
+}
+
+// PartitionMigrationData contains the state of the partition migration of an app
+type PartitionMigrationData struct {
+	Migration s.PartitionMigration `json:"migration"`
+}
+
+// ImportSchedulesResponse is the response structure for the schedule import endpoint
+type ImportSchedulesResponse struct {
+	Status Status              `json:"status"`
+	Data   ImportSchedulesData `json:"data"`
+}
+
+// ImportSchedulesData contains the outcome of a schedule import
+type ImportSchedulesData struct {
+	Imported    int               `json:"imported"`
+	Overwritten int               `json:"overwritten"`
+	Skipped     int               `json:"skipped"`
+	Failed      int               `json:"failed"`
+	Errors      []ImportError     `json:"errors,omitempty"`
+	IdMapping   map[string]string `json:"idMapping,omitempty"`
+}
+
+// ImportError describes a schedule of the snapshot which could not be imported
+type ImportError struct {
+	Index      int    `json:"index"`
+	ScheduleId string `json:"scheduleId,omitempty"`
+	Error      string `json:"error"`
+}
+
+// ClusterNodesResponse is the response structure for the cluster nodes endpoint
+type ClusterNodesResponse struct {
+	Status Status              `json:"status"`
+	Data   []cluster.NodeState `json:"data"`
+}
+
+// ClusterPartitionsResponse is the response structure for the cluster partitions endpoint
+type ClusterPartitionsResponse struct {
+	Status Status                        `json:"status"`
+	Data   []cluster.PartitionAssignment `json:"data"`
+}
+
+// NodeMaintenanceResponse is the response structure for the cordon endpoints
+type NodeMaintenanceResponse struct {
+	Status Status              `json:"status"`
+	Data   NodeMaintenanceData `json:"data"`
+}
+
+// NodeMaintenanceData is whether a node is cordoned
+type NodeMaintenanceData struct {
+	Node     string `json:"node"`
+	Cordoned bool   `json:"cordoned"`
+}
+
+// DrainNodeResponse is the response structure for the drain endpoint
+type DrainNodeResponse struct {
+	Status Status        `json:"status"`
+	Data   DrainNodeData `json:"data"`
+}
+
+// DrainNodeData lists the partitions handed over by a drained node. Drained is false when some of them failed and
+// the drain has to be retried.
+type DrainNodeData struct {
+	Node       string                     `json:"node"`
+	Drained    bool                       `json:"drained"`
+	Partitions []cluster.DrainedPartition `json:"partitions"`
+}
+
+// ReplicationStatusResponse is the response structure for the replication endpoints
+type ReplicationStatusResponse struct {
+	Status Status             `json:"status"`
+	Data   replication.Status `json:"data"`
+}
+
+// ShadowReportResponse is the response structure for the shadow cluster endpoints
+type ShadowReportResponse struct {
+	Status Status           `json:"status"`
+	Data   ShadowReportData `json:"data"`
+}
+
+// ShadowReportData contains the comparison of the shadow cluster with the primary one, and the outcome of the
+// reconciliation when one was run
+type ShadowReportData struct {
+	Enabled        bool                  `json:"enabled"`
+	Reconciliation *ShadowReconciliation `json:"reconciliation,omitempty"`
+	db_wrapper.MirrorReport
+}
+
+// ShadowReconciliation contains the number of failed writes replayed on the shadow cluster, and of the ones failing again
+type ShadowReconciliation struct {
+	Replayed int `json:"replayed"`
+	Failed   int `json:"failed"`
+}
+
+// ClockResponse is the response structure for the clock endpoints
+type ClockResponse struct {
+	Status Status    `json:"status"`
+	Data   ClockData `json:"data"`
+}
+
+// ClockData contains the time of the clock the schedules are fired on
+type ClockData struct {
+	Now     int64  `json:"now"`
+	Time    string `json:"time"`
+	Virtual bool   `json:"virtual"`
+}
+
+// ReconciliationResponse is the response structure for the reconciliation endpoint
+type ReconciliationResponse struct {
+	Status Status             `json:"status"`
+	Data   ReconciliationData `json:"data"`
+}
+
+// ReconciliationData lists the schedules of an app which were due in a window but were never fired
+type ReconciliationData struct {
+	AppId     string       `json:"appId"`
+	StartTime int64        `json:"startTime"`
+	EndTime   int64        `json:"endTime"`
+	Scanned   int          `json:"scanned"`
+	Missed    int          `json:"missed"`
+	Refired   int          `json:"refired"`
+	Failed    int          `json:"failed"`
+	Truncated bool         `json:"truncated"`
+	Schedules []s.Schedule `json:"schedules"`
+}
+
+// DuplicateFiresResponse is the response structure for the duplicate fires endpoint
+type DuplicateFiresResponse struct {
+	Status Status             `json:"status"`
+	Data   DuplicateFiresData `json:"data"`
+}
+
+// DuplicateFiresData lists the schedules of an app which were due in a window and dispatched more than once
+type DuplicateFiresData struct {
+	AppId           string          `json:"appId"`
+	StartTime       int64           `json:"startTime"`
+	EndTime         int64           `json:"endTime"`
+	Scanned         int             `json:"scanned"`
+	Fired           int             `json:"fired"`
+	Duplicated      int             `json:"duplicated"`
+	DuplicationRate float64         `json:"duplicationRate"`
+	Truncated       bool            `json:"truncated"`
+	Offenders       []DuplicateFire `json:"offenders"`
+}
+
+// DuplicateFire describes the dispatches of a schedule fired more than once
+type DuplicateFire struct {
+	ScheduleId       gocql.UUID `json:"scheduleId"`
+	ParentScheduleId string     `json:"parentScheduleId,omitempty"`
+	ScheduleTime     int64      `json:"scheduleTime"`
+	Fires            int        `json:"fires"`
+	Nodes            []string   `json:"nodes"`
+	DispatchedAt     []int64    `json:"dispatchedAt"`
+}
+
+// AppConnectionsResponse contains the connection pool of the http callbacks of an app and its use
+type AppConnectionsResponse struct {
+	Status Status             `json:"status"`
+	Data   AppConnectionsData `json:"data"`
+}
+
+// AppConnectionsData is the transport of the http callbacks of an app and the use of its connections by host.
+// A nil transport is the shared callback client.
+type AppConnectionsData struct {
+	AppId     string                        `json:"appId"`
+	Transport *s.HttpTransport              `json:"transport"`
+	Hosts     []diagnostics.ConnectionStats `json:"hosts"`
+}
+
+// AppUsageResponse contains the usage of an app
+type AppUsageResponse struct {
+	Status Status       `json:"status"`
+	Data   AppUsageData `json:"data"`
+}
+
+// AppUsageData is the usage of an app between two days, per day or month and in total
+type AppUsageData struct {
+	AppId       string        `json:"appId"`
+	Granularity string        `json:"granularity"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Total       UsagePeriod   `json:"total"`
+	Usage       []UsagePeriod `json:"usage"`
+}
+
+// UsagePeriod is the usage of an app over a day, as YYYY-MM-DD, or a month, as YYYY-MM.
+// The period of the total is empty.
+type UsagePeriod struct {
+	Period           string `json:"period,omitempty"`
+	SchedulesCreated int64  `json:"schedulesCreated"`
+	CallbacksFired   int64  `json:"callbacksFired"`
+	BytesDelivered   int64  `json:"bytesDelivered"`
+	Retries          int64  `json:"retries"`
+}
+
+// Add adds the counters of the daily usage
+func (u *UsagePeriod) Add(usage s.Usage) {
+	u.SchedulesCreated += usage.SchedulesCreated
+	u.CallbacksFired += usage.CallbacksFired
+	u.BytesDelivered += usage.BytesDelivered
+	u.Retries += usage.Retries
+}
+
+// AppRunStatsResponse contains the run stats of an app
+type AppRunStatsResponse struct {
+	Status Status          `json:"status"`
+	Data   AppRunStatsData `json:"data"`
+}
+
+// AppRunStatsData is the outcome of the runs of an app between two times, per hour or day and in total
+type AppRunStatsData struct {
+	AppId   string           `json:"appId"`
+	GroupBy string           `json:"groupBy"`
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Total   RunStatsBucket   `json:"total"`
+	Buckets []RunStatsBucket `json:"buckets"`
+}
+
+// RunStatsBucket is the outcome of the runs of an app fired over the hour or the day starting at the bucket, in UTC.
+// The bucket of the total is empty.
+type RunStatsBucket struct {
+	Bucket    string `json:"bucket,omitempty"`
+	Successes int64  `json:"successes"`
+	Failures  int64  `json:"failures"`
+	Retries   int64  `json:"retries"`
+}
+
+// Add adds the counters of the hourly run stats
+func (b *RunStatsBucket) Add(stats s.RunStats) {
+	b.Successes += stats.Successes
+	b.Failures += stats.Failures
+	b.Retries += stats.Retries
+}
+
+// GraphQLSchemaResponse contains the schema of the GraphQL queries
+type GraphQLSchemaResponse struct {
+	Status Status            `json:"status"`
+	Data   GraphQLSchemaData `json:"data"`
+}
+
+// GraphQLSchemaData is the schema of the GraphQL queries in the schema definition language
+type GraphQLSchemaData struct {
+	Schema string `json:"schema"`
+}
+
+// CallbackTemplateResponse contains a version of a callback template
+type CallbackTemplateResponse struct {
+	Status Status             `json:"status"`
+	Data   s.CallbackTemplate `json:"data"`
+}
+
+// CallbackTemplatesResponse contains the latest version of the callback templates of an app
+type CallbackTemplatesResponse struct {
+	Status Status               `json:"status"`
+	Data   []s.CallbackTemplate `json:"data"`
+}
+
+// ScheduleGroupResponse contains a group of an app
+type ScheduleGroupResponse struct {
+	Status Status          `json:"status"`
+	Data   s.ScheduleGroup `json:"data"`
+}
+
+// ScheduleGroupsResponse contains the groups of an app
+type ScheduleGroupsResponse struct {
+	Status Status            `json:"status"`
+	Data   []s.ScheduleGroup `json:"data"`
+}
+
+// GroupReportResponse contains the report on the schedules of a group
+type GroupReportResponse struct {
+	Status Status        `json:"status"`
+	Data   s.GroupReport `json:"data"`
+}
+
+// ApprovalResponse contains a destructive operation held for approval
+type ApprovalResponse struct {
+	Status Status     `json:"status"`
+	Data   s.Approval `json:"data"`
+}
+
+// ApprovalsResponse contains the destructive operations of an app held for approval
+type ApprovalsResponse struct {
+	Status Status       `json:"status"`
+	Data   []s.Approval `json:"data"`
+}
+
+// VerifiedUrlResponse contains a verified callback url of an app
+type VerifiedUrlResponse struct {
+	Status Status        `json:"status"`
+	Data   s.VerifiedUrl `json:"data"`
+}
+
+// VerifiedUrlsResponse contains the verified callback urls of an app
+type VerifiedUrlsResponse struct {
+	Status Status          `json:"status"`
+	Data   []s.VerifiedUrl `json:"data"`
+}
+
+// JobResponse is the response structure for the endpoints starting or reading an asynchronous job
+type JobResponse struct {
+	Status Status  `json:"status"`
+	Data   JobData `json:"data"`
+}
+
+// JobData contains an asynchronous job with the results of the items it has processed so far
+type JobData struct {
+	Job   s.Job       `json:"job"`
+	Items []s.JobItem `json:"items,omitempty"`
+}
@@ -20,22 +20,28 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 )
 
-// ResumeSchedule resumes a paused recurring schedule by updating its status to SCHEDULED
+// ResumeSchedule resumes a paused or suspended recurring schedule by updating its status to SCHEDULED
+// Runs missed during the pause are queued as per the pause policy of the schedule
+// A resumeAt in the request body schedules the resume for a future time instead
 func (s *Service) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 	var errs []string
 
 	vars := mux.Vars(r)
@@ -43,7 +49,7 @@ func (s *Service) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
 	uuid, err := gocql.ParseUUID(scheduleID)
 
 	if err != nil {
-		glog.Errorf("Cannot parse UUID from %s", scheduleID)
+		log.Errorf("Cannot parse UUID from %s", scheduleID)
 
 		errs = append(errs, err.Error())
 		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
@@ -51,17 +57,24 @@ func (s *Service) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	request, err := readLifecycleRequest(r)
+	if err != nil {
+		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
 	// First, get the schedule to ensure it exists and is recurring
 	schedule, err := s.ScheduleDao.GetSchedule(uuid)
 	if err != nil {
 		if err == gocql.ErrNotFound {
-			glog.Infof("No schedule with id %s found", uuid)
+			log.Infof("No schedule with id %s found", uuid)
 			s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
 
 			errs = append(errs, fmt.Sprintf("Schedule with id: %s not found", uuid))
 			er.Handle(w, r, er.NewError(er.DataNotFound, errors.New(strings.Join(errs, ","))))
 		} else {
-			glog.Errorf("Error fetching schedule with id %s", uuid)
+			log.Errorf("Error fetching schedule with id %s", uuid)
 			s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
 
 			errs = append(errs, err.Error())
@@ -70,40 +83,52 @@ func (s *Service) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	schedule.RequestId = logger.RequestID(r.Context())
+
 	// Check if the schedule is recurring
 	if !schedule.IsRecurring() {
-		glog.Infof("schedule with id %s is not recurring", uuid)
+		log.Infof("schedule with id %s is not recurring", uuid)
 		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
 		errs = append(errs, fmt.Sprintf("Schedule with id: %s is not a recurring schedule", uuid))
 		er.Handle(w, r, er.NewError(er.UnprocessableEntity, errors.New(strings.Join(errs, ","))))
 		return
 	}
 
+	if request.ResumeAt != 0 {
+		s.scheduleResume(w, r, schedule, request)
+		return
+	}
+
 	// Check if not paused
-	if schedule.Status != store.Paused {
-		glog.Infof("schedule with id %s is not paused", uuid)
+	if schedule.Status != store.Paused && schedule.Status != store.Suspended {
+		log.Infof("schedule with id %s is not paused", uuid)
 		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
-		errs = append(errs, fmt.Sprintf("Schedule with id: %s is not paused", uuid))
+		errs = append(errs, fmt.Sprintf("Schedule with id: %s is not paused or suspended", uuid))
 		er.Handle(w, r, er.NewError(er.Conflict, errors.New(strings.Join(errs, ","))))
 		return
 	}
 
 	// Update the schedule status to SCHEDULED
-	updatedSchedule, err := s.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Scheduled)
+	updatedSchedule, missed, queued, err := s.resumeRecurringSchedule(schedule)
 	if err != nil {
-		glog.Errorf("Error resuming schedule with id %s: %v", uuid, err)
+		log.Errorf("Error resuming schedule with id %s: %v", uuid, err)
 		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
 		errs = append(errs, err.Error())
 		er.Handle(w, r, er.NewError(er.DataPersistenceFailure, errors.New(strings.Join(errs, ","))))
 		return
 	}
 
-	glog.V(constants.INFO).Infof("Schedule with id %s resumed", uuid.String())
+	log.Debugf("Schedule with id %s resumed", uuid.String())
 	s.recordRequestStatus(constants.ResumeSchedule, constants.Success)
 
+	message := "Schedule resumed successfully"
+	if missed > 0 {
+		message = fmt.Sprintf("%s, %d of %d missed runs queued", message, queued, missed)
+	}
+
 	status := Status{
 		StatusCode:    constants.SuccessCode200,
-		StatusMessage: "Schedule resumed successfully",
+		StatusMessage: message,
 		StatusType:    constants.Success,
 		TotalCount:    1,
 	}
@@ -116,3 +141,99 @@ func (s *Service) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
 			Data:   data,
 		})
 }
+
+// scheduleResume schedules the resume of a recurring schedule at the resumeAt of the request
+func (s *Service) scheduleResume(w http.ResponseWriter, r *http.Request, schedule store.Schedule, request lifecycleRequest) {
+	log := logger.FromContext(r.Context())
+	if request.PauseAt != 0 {
+		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, errors.New("pauseAt cannot be set on resume")))
+		return
+	}
+
+	if err := request.validate(time.Now().Unix()); err != nil {
+		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	if schedule.Status == store.Deleted {
+		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnprocessableEntity, fmt.Errorf("Schedule with id: %s is deleted", schedule.ScheduleId)))
+		return
+	}
+
+	actions, err := s.createLifecycleActions(r.Context(), schedule, request)
+	if err != nil {
+		log.Errorf("Error scheduling resume of schedule with id %s: %v", schedule.ScheduleId, err)
+		s.recordRequestStatus(constants.ResumeSchedule, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestStatus(constants.ResumeSchedule, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: "Schedule resume scheduled",
+		StatusType:    constants.Success,
+		TotalCount:    1,
+	}
+	data := ScheduleData{
+		Schedule: schedule,
+		Actions:  actions,
+	}
+	_ = json.NewEncoder(w).Encode(
+		ScheduleResponse{
+			Status: status,
+			Data:   data,
+		})
+}
+
+// resumeRecurringSchedule updates the status of a paused recurring schedule to SCHEDULED
+// and queues the runs missed during the pause as per its pause policy.
+// Returns the resumed schedule along with the number of runs missed and queued.
+func (s *Service) resumeRecurringSchedule(schedule store.Schedule) (store.Schedule, int, int, error) {
+	// Find the runs missed during the pause before the pause time is cleared
+	missedRuns := schedule.MissedRuns(time.Now())
+
+	updatedSchedule, err := s.ScheduleDao.UpdateRecurringScheduleStatus(schedule, store.Scheduled)
+	if err != nil {
+		return updatedSchedule, 0, 0, err
+	}
+
+	store.PublishEvent(store.ScheduleResumed, updatedSchedule)
+
+	queued := 0
+	if len(missedRuns) > 0 {
+		queued = s.queueMissedRuns(updatedSchedule, missedRuns)
+	}
+
+	return updatedSchedule, len(missedRuns), queued, nil
+}
+
+// queueMissedRuns creates a run at the next minute for each of the runs missed during the pause of a schedule.
+// Returns the number of runs queued.
+func (s *Service) queueMissedRuns(schedule store.Schedule, missedRuns []time.Time) int {
+	app, err := s.getApp(schedule.AppId)
+	if err != nil {
+		schedule.Logger().Errorf("Error queueing missed runs of schedule %s: %v", schedule.ScheduleId, err)
+		return 0
+	}
+
+	// The current minute, and the next one with prefetch, may already have been polled
+	fireAt := s.Config.Poller.LastPolledBucket(clock.Now()).Add(time.Minute)
+
+	queued := 0
+	for _, missed := range missedRuns {
+		run := schedule.CloneAsOneTime(fireAt)
+		run.SetFields(app)
+		if _, err = s.ScheduleDao.CreateRun(context.Background(), run, app); err != nil {
+			schedule.Logger().Errorf("Error queueing run missed at %v of schedule %s: %v", missed, schedule.ScheduleId, err)
+			continue
+		}
+		queued++
+	}
+
+	return queued
+}
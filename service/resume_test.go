@@ -20,9 +20,11 @@
 package service
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/gorilla/mux"
@@ -68,6 +70,20 @@ func (m *MockScheduleDaoForResume) GetSchedule(uuid gocql.UUID) (store.Schedule,
 			Status:         store.Paused,
 		}, nil
 
+	case "44444444-4444-4444-4444-444444444444":
+		// Paused three hours ago with runs queued upon resume
+		return store.Schedule{
+			ScheduleId:  uuid,
+			AppId:       "testApp",
+			Every:       "1h",
+			Anchor:      time.Now().Add(-3*time.Hour - 29*time.Minute).Unix(),
+			Payload:     "{}",
+			Callback:    &store.HttpCallback{Type: "http", Details: store.Details{Url: "http://example.com", Method: "GET"}},
+			Status:      store.Paused,
+			PausePolicy: store.QueuePausedRuns,
+			PausedAt:    time.Now().Add(-3*time.Hour + time.Minute).Unix(),
+		}, nil
+
 	default:
 		// Default is a valid paused recurring schedule
 		return store.Schedule{
@@ -95,6 +111,13 @@ func (m *MockScheduleDaoForResume) UpdateRecurringScheduleStatus(schedule store.
 	}
 }
 
+var createRunCallCount int
+
+func (m *MockScheduleDaoForResume) CreateRun(_ context.Context, schedule store.Schedule, app store.App) (store.Schedule, error) {
+	createRunCallCount++
+	return schedule, nil
+}
+
 // Add a function to get a properly mocked service handler for resume tests
 func setupMocksForResumeTests() *Service {
 	// Setup basic service structure
@@ -118,6 +141,7 @@ func TestService_ResumeSchedule(t *testing.T) {
 		description        string
 		shouldUpdateStatus bool         // Whether UpdateRecurringScheduleStatus should be called
 		expectedNewStatus  store.Status // Expected status to be set
+		expectedRuns       int          // Expected number of missed runs queued
 	}{
 		{
 			name:               "InvalidUUID",
@@ -163,12 +187,22 @@ func TestService_ResumeSchedule(t *testing.T) {
 			shouldUpdateStatus: true,
 			expectedNewStatus:  store.Scheduled,
 		},
+		{
+			name:               "QueueMissedRuns",
+			scheduleID:         "44444444-4444-4444-4444-444444444444",
+			wantStatus:         http.StatusOK,
+			description:        "Should queue the runs missed during the pause",
+			shouldUpdateStatus: true,
+			expectedNewStatus:  store.Scheduled,
+			expectedRuns:       3,
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			// Reset the call count for each test
 			UpdateRecurringScheduleStatusCallCount = 0
+			createRunCallCount = 0
 
 			req, err := http.NewRequest("PUT", "/goscheduler/schedules/{scheduleId}/resume", nil)
 			if err != nil {
@@ -190,6 +224,10 @@ func TestService_ResumeSchedule(t *testing.T) {
 					status, tc.wantStatus, tc.description)
 			}
 
+			if createRunCallCount != tc.expectedRuns {
+				t.Errorf("expected %d missed runs to be queued, got %d", tc.expectedRuns, createRunCallCount)
+			}
+
 			// Verify if UpdateRecurringScheduleStatus was called as expected
 			if tc.shouldUpdateStatus {
 				if UpdateRecurringScheduleStatusCallCount == 0 {
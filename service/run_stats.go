@@ -0,0 +1,174 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	hourlyRunStats = "hour"
+	dailyRunStats  = "day"
+	// maxRunStatsBuckets caps the buckets covered by a run stats report
+	maxRunStatsBuckets = 744
+)
+
+// GetAppRunStats returns the successful, failed and retried runs of an app per hour or per day between two times,
+// so that the delivery health of the app can be plotted without exporting its history.
+func (s *Service) GetAppRunStats(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	data, err := s.fetchAppRunStats(appId, r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetAppRunStats, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetAppRunStats, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(data.Buckets)}
+	_ = json.NewEncoder(w).Encode(AppRunStatsResponse{Status: status, Data: data})
+}
+
+func (s *Service) fetchAppRunStats(appId string, r *http.Request) (AppRunStatsData, error) {
+	groupBy, from, to, err := parseRunStatsRange(r, time.Now())
+	if err != nil {
+		return AppRunStatsData{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	switch app, err := s.ClusterDao.GetApp(appId); {
+	case err == gocql.ErrNotFound || (err == nil && len(app.AppId) == 0):
+		return AppRunStatsData{}, er.NewError(er.InvalidAppId, errors.New(fmt.Sprintf("app Id %s is not registered", appId)))
+	case err != nil:
+		return AppRunStatsData{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	hours, err := s.ClusterDao.GetRunStats(appId, from, to)
+	if err != nil {
+		return AppRunStatsData{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	return rollUpRunStats(appId, groupBy, from, to, hours), nil
+}
+
+// runStatsBucket returns the start of the bucket of t
+func runStatsBucket(groupBy string, t time.Time) time.Time {
+	if groupBy == dailyRunStats {
+		return store.UsageDay(t)
+	}
+	return store.RunStatsHour(t)
+}
+
+// runStatsStep returns the start of the bucket following the one starting at bucket
+func runStatsStep(groupBy string, bucket time.Time) time.Time {
+	if groupBy == dailyRunStats {
+		return bucket.AddDate(0, 0, 1)
+	}
+	return bucket.Add(time.Hour)
+}
+
+// parseRunStatsRange parses the grouping and the time range of the report, as date times in UTC.
+// The report ends now by default and covers the last 24 hours when grouped by hour, the last 30 days by day.
+func parseRunStatsRange(r *http.Request, now time.Time) (string, time.Time, time.Time, error) {
+	query := r.URL.Query()
+
+	groupBy := query.Get("groupBy")
+	switch groupBy {
+	case "":
+		groupBy = hourlyRunStats
+	case hourlyRunStats, dailyRunStats:
+	default:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("invalid groupBy %s, must be one of hour or day", groupBy)
+	}
+
+	to := now.UTC()
+	if value := query.Get("to"); value != "" {
+		t, err := time.Parse(dateTimeLayout, value)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid to %s, must be a date time as %s", value, dateTimeLayout)
+		}
+		to = t
+	}
+
+	from := to.Add(-23 * time.Hour)
+	if groupBy == dailyRunStats {
+		from = to.AddDate(0, 0, -29)
+	}
+	if value := query.Get("from"); value != "" {
+		t, err := time.Parse(dateTimeLayout, value)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid from %s, must be a date time as %s", value, dateTimeLayout)
+		}
+		from = t
+	}
+
+	from, to = runStatsBucket(groupBy, from), runStatsBucket(groupBy, to)
+	buckets := int(to.Sub(from)/time.Hour) + 1
+	if groupBy == dailyRunStats {
+		buckets = int(to.Sub(from)/(24*time.Hour)) + 1
+	}
+
+	switch {
+	case from.After(to):
+		return "", time.Time{}, time.Time{}, fmt.Errorf("from %s is after to %s", from.Format(dateTimeLayout), to.Format(dateTimeLayout))
+	case buckets > maxRunStatsBuckets:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("run stats can be reported for at most %d buckets", maxRunStatsBuckets)
+	}
+	return groupBy, from, to, nil
+}
+
+// rollUpRunStats sums the hourly run stats into the buckets of the grouping and into the total. Every bucket between
+// from and to is returned, oldest first, those without any run with zero counts so that the series can be plotted.
+func rollUpRunStats(appId string, groupBy string, from time.Time, to time.Time, hours []store.RunStats) AppRunStatsData {
+	data := AppRunStatsData{
+		AppId:   appId,
+		GroupBy: groupBy,
+		From:    from.Format(dateTimeLayout),
+		To:      to.Format(dateTimeLayout),
+		Buckets: []RunStatsBucket{},
+	}
+
+	index := make(map[time.Time]int)
+	for bucket := from; !bucket.After(to); bucket = runStatsStep(groupBy, bucket) {
+		index[bucket] = len(data.Buckets)
+		data.Buckets = append(data.Buckets, RunStatsBucket{Bucket: bucket.Format(dateTimeLayout)})
+	}
+
+	for _, hour := range hours {
+		i, ok := index[runStatsBucket(groupBy, hour.Hour)]
+		if !ok {
+			continue
+		}
+		data.Buckets[i].Add(hour)
+		data.Total.Add(hour)
+	}
+	return data
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_GetAppRunStats(t *testing.T) {
+	service := &Service{
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		appId  string
+		query  string
+		status int
+	}{
+		{"testApp", "", http.StatusOK},
+		{"testApp", "?groupBy=day", http.StatusOK},
+		{"testApp", "?from=2023-01-01+00:00:00&to=2023-01-01+23:59:59", http.StatusOK},
+		{"testApp", "?groupBy=week", http.StatusBadRequest},
+		{"testApp", "?from=2023-01-02+00:00:00&to=2023-01-01+00:00:00", http.StatusBadRequest},
+		{"testApp", "?from=2023-01-01+00:00:00&to=2023-03-01+00:00:00", http.StatusBadRequest},
+		{"testApp", "?to=2023-01-01", http.StatusBadRequest},
+		{"testGetAppErrorNotFound", "", http.StatusBadRequest},
+		{"testGetAppError", "", http.StatusInternalServerError},
+		{"testGetRunStatsError", "", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/"+test.appId+"/runs"+test.query, nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetAppRunStats).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s%s: expected status %d, got %d with body %s", test.appId, test.query, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_GetAppRunStatsBuckets(t *testing.T) {
+	service := &Service{
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	req, _ := http.NewRequest("GET", "/goscheduler/apps/testRunStats/runs?from=2023-01-01+10:30:00&to=2023-01-01+13:00:00", nil)
+	req = mux.SetURLVars(req, map[string]string{"appId": "testRunStats"})
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GetAppRunStats).ServeHTTP(rr, req)
+
+	var response AppRunStatsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	data := response.Data
+	if len(data.Buckets) != 4 || data.From != "2023-01-01 10:00:00" || data.To != "2023-01-01 13:00:00" {
+		t.Fatalf("Expected the 4 hours from 10:00 to 13:00, got %+v", data)
+	}
+	if data.Buckets[0] != (RunStatsBucket{Bucket: "2023-01-01 10:00:00", Successes: 3, Failures: 1, Retries: 2}) ||
+		data.Buckets[1] != (RunStatsBucket{Bucket: "2023-01-01 11:00:00"}) ||
+		data.Buckets[2] != (RunStatsBucket{Bucket: "2023-01-01 12:00:00", Successes: 1}) {
+		t.Errorf("Unexpected buckets %+v", data.Buckets)
+	}
+	if data.Total != (RunStatsBucket{Successes: 4, Failures: 1, Retries: 2}) {
+		t.Errorf("Unexpected total %+v", data.Total)
+	}
+}
+
+func TestParseRunStatsRange(t *testing.T) {
+	now := time.Date(2023, 3, 15, 10, 20, 0, 0, time.UTC)
+
+	req, _ := http.NewRequest("GET", "/runs", nil)
+	groupBy, from, to, err := parseRunStatsRange(req, now)
+	if err != nil || groupBy != hourlyRunStats || !from.Equal(time.Date(2023, 3, 14, 11, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2023, 3, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected hourly default %s %s %s %v", groupBy, from, to, err)
+	}
+
+	req, _ = http.NewRequest("GET", "/runs?groupBy=day", nil)
+	groupBy, from, to, err = parseRunStatsRange(req, now)
+	if err != nil || groupBy != dailyRunStats || !from.Equal(time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected daily default %s %s %s %v", groupBy, from, to, err)
+	}
+}
+
+func TestRollUpRunStats(t *testing.T) {
+	day := time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC)
+	hours := []store.RunStats{
+		{Hour: day.Add(time.Hour), Successes: 1, Retries: 1},
+		{Hour: day.Add(23 * time.Hour), Failures: 2},
+		{Hour: day.Add(26 * time.Hour), Successes: 4},
+	}
+
+	daily := rollUpRunStats("app", dailyRunStats, day, day.AddDate(0, 0, 2), hours)
+	if len(daily.Buckets) != 3 || daily.Buckets[0] != (RunStatsBucket{Bucket: "2023-01-30 00:00:00", Successes: 1, Failures: 2, Retries: 1}) ||
+		daily.Buckets[1] != (RunStatsBucket{Bucket: "2023-01-31 00:00:00", Successes: 4}) ||
+		daily.Buckets[2] != (RunStatsBucket{Bucket: "2023-02-01 00:00:00"}) {
+		t.Errorf("Unexpected daily run stats %+v", daily.Buckets)
+	}
+	if daily.Total != (RunStatsBucket{Successes: 5, Failures: 2, Retries: 1}) {
+		t.Errorf("Unexpected total %+v", daily.Total)
+	}
+}
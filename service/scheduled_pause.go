@@ -0,0 +1,223 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+const (
+	pauseAction  = "pause"
+	resumeAction = "resume"
+)
+
+// lifecycleRequest is the optional body of the pause and resume requests to pause or resume at a future time
+type lifecycleRequest struct {
+	PauseAt  int64 `json:"pauseAt,omitempty"`
+	ResumeAt int64 `json:"resumeAt,omitempty"`
+}
+
+// readLifecycleRequest reads the optional body of the pause and resume requests
+func readLifecycleRequest(r *http.Request) (lifecycleRequest, error) {
+	var request lifecycleRequest
+	if r.Body == nil {
+		return request, nil
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(bytes.TrimSpace(b)) == 0 {
+		return request, err
+	}
+
+	err = json.Unmarshal(b, &request)
+	return request, err
+}
+
+// validate checks that the pause and resume times are in the future and that the resume comes after the pause
+func (request lifecycleRequest) validate(now int64) error {
+	switch {
+	case request.PauseAt != 0 && request.PauseAt <= now:
+		return fmt.Errorf("pauseAt: %d must be in the future", request.PauseAt)
+	case request.ResumeAt != 0 && request.ResumeAt <= now:
+		return fmt.Errorf("resumeAt: %d must be in the future", request.ResumeAt)
+	case request.PauseAt != 0 && request.ResumeAt != 0 && request.ResumeAt <= request.PauseAt:
+		return fmt.Errorf("resumeAt: %d must be after pauseAt: %d", request.ResumeAt, request.PauseAt)
+	default:
+		return nil
+	}
+}
+
+// createLifecycleActions creates the one time schedules which pause and resume the schedule at the times requested.
+// Deleting an action schedule cancels the pause or resume.
+func (s *Service) createLifecycleActions(ctx context.Context, schedule store.Schedule, request lifecycleRequest) ([]store.Schedule, error) {
+	var actions []store.Schedule
+
+	for _, action := range []struct {
+		name string
+		at   int64
+	}{
+		{name: pauseAction, at: request.PauseAt},
+		{name: resumeAction, at: request.ResumeAt},
+	} {
+		if action.at == 0 {
+			continue
+		}
+
+		created, err := s.createLifecycleAction(ctx, schedule, action.name, action.at)
+		if err != nil {
+			return actions, err
+		}
+		actions = append(actions, created)
+	}
+
+	return actions, nil
+}
+
+// createLifecycleAction creates a one time schedule in the app of the schedule which performs the action at the time supplied
+func (s *Service) createLifecycleAction(ctx context.Context, schedule store.Schedule, action string, at int64) (store.Schedule, error) {
+	raw, err := json.Marshal(lifecycleCallback{
+		Type:    constants.LifecycleCallback,
+		Details: lifecycleDetails{ScheduleId: schedule.ScheduleId, Action: action},
+	})
+	if err != nil {
+		return store.Schedule{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	callback, err := store.CreateCallbackFromRawMessage(raw)
+	if err != nil {
+		return store.Schedule{}, er.NewError(er.InvalidCallbackType, err)
+	}
+
+	return s.CreateSchedule(ctx, store.Schedule{
+		AppId:        schedule.AppId,
+		Payload:      string(raw),
+		ScheduleTime: at,
+		Callback:     callback,
+		CallbackRaw:  raw,
+	})
+}
+
+// lifecycleDetails identifies the recurring schedule to be paused or resumed
+type lifecycleDetails struct {
+	ScheduleId gocql.UUID `json:"scheduleId"`
+	Action     string     `json:"action"`
+}
+
+// lifecycleCallback is the internal callback of the schedules created for a future dated pause or resume.
+// It is executed as a plugin and pauses or resumes a recurring schedule of the same app when fired.
+type lifecycleCallback struct {
+	Type    string           `json:"type"`
+	Details lifecycleDetails `json:"details"`
+
+	service *Service
+}
+
+// registerLifecycleCallback registers the internal lifecycle callback type backed by the service
+func (s *Service) registerLifecycleCallback() {
+	if err := store.RegisterPlugin(constants.LifecycleCallback, func() store.Plugin {
+		return &lifecycleCallback{Type: constants.LifecycleCallback, service: s}
+	}); err != nil {
+		logger.Errorf("Error registering %s callback: %v", constants.LifecycleCallback, err)
+	}
+}
+
+func (l *lifecycleCallback) GetType() string {
+	return l.Type
+}
+
+func (l *lifecycleCallback) GetDetails() (string, error) {
+	details, err := json.Marshal(l.Details)
+	return string(details), err
+}
+
+func (l *lifecycleCallback) Marshal(m map[string]interface{}) error {
+	details, ok := m["callback_details"].(string)
+	if !ok {
+		return errors.New("wrong type for callback_details")
+	}
+
+	return json.Unmarshal([]byte(details), &l.Details)
+}
+
+// UnmarshalJSON Implement UnmarshalJSON for lifecycleCallback
+func (l *lifecycleCallback) UnmarshalJSON(data []byte) error {
+	type Alias lifecycleCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(l),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+func (l *lifecycleCallback) Validate() error {
+	if util.IsZeroUUID(l.Details.ScheduleId) {
+		return errors.New("details.scheduleId cannot be empty")
+	}
+
+	if l.Details.Action != pauseAction && l.Details.Action != resumeAction {
+		return fmt.Errorf("invalid details.action: %s, must be one of pause or resume", l.Details.Action)
+	}
+
+	return nil
+}
+
+// Execute pauses or resumes the recurring schedule.
+// Only schedules of the same app can be paused or resumed, and a schedule already in the requested state is left as is.
+func (l *lifecycleCallback) Execute(schedule store.Schedule) error {
+	if l.service == nil {
+		return errors.New("lifecycle callback is not registered")
+	}
+
+	target, err := l.service.ScheduleDao.GetSchedule(l.Details.ScheduleId)
+	if err != nil {
+		return fmt.Errorf("error fetching schedule with id %s: %w", l.Details.ScheduleId, err)
+	}
+
+	if target.AppId != schedule.AppId || !target.IsRecurring() {
+		return fmt.Errorf("schedule with id %s is not a recurring schedule of app %s", l.Details.ScheduleId, schedule.AppId)
+	}
+
+	switch {
+	case l.Details.Action == pauseAction && target.Status == store.Scheduled:
+		_, err = l.service.pauseRecurringSchedule(target)
+	case l.Details.Action == resumeAction && target.Status == store.Paused:
+		_, _, _, err = l.service.resumeRecurringSchedule(target)
+	case target.Status == store.Deleted:
+		err = fmt.Errorf("schedule with id %s is deleted", l.Details.ScheduleId)
+	default:
+		target.Logger().Infof("Schedule with id %s is already %s, skipping %s", target.ScheduleId, target.Status, l.Details.Action)
+	}
+
+	return err
+}
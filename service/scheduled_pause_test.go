@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestLifecycleRequest_Validate(t *testing.T) {
+	now := time.Now().Unix()
+
+	for _, test := range []struct {
+		request lifecycleRequest
+		valid   bool
+	}{
+		{request: lifecycleRequest{}, valid: true},
+		{request: lifecycleRequest{PauseAt: now + 60}, valid: true},
+		{request: lifecycleRequest{PauseAt: now + 60, ResumeAt: now + 120}, valid: true},
+		{request: lifecycleRequest{PauseAt: now - 60}, valid: false},
+		{request: lifecycleRequest{ResumeAt: now}, valid: false},
+		{request: lifecycleRequest{PauseAt: now + 120, ResumeAt: now + 60}, valid: false},
+	} {
+		if err := test.request.validate(now); (err == nil) != test.valid {
+			t.Errorf("validate(%+v) returned %v, expected valid %v", test.request, err, test.valid)
+		}
+	}
+}
+
+func TestService_PauseScheduleAt(t *testing.T) {
+	service := setupMocksForPauseTests()
+	service.registerLifecycleCallback()
+
+	now := time.Now().Unix()
+	for _, test := range []struct {
+		name        string
+		scheduleID  string
+		body        string
+		wantStatus  int
+		wantActions int
+		wantPaused  bool
+	}{
+		{
+			name:        "PauseAndResumeAt",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        fmt.Sprintf(`{"pauseAt":%d,"resumeAt":%d}`, now+3600, now+7200),
+			wantStatus:  http.StatusOK,
+			wantActions: 2,
+		},
+		{
+			name:        "PauseNowResumeAt",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        fmt.Sprintf(`{"resumeAt":%d}`, now+7200),
+			wantStatus:  http.StatusOK,
+			wantActions: 1,
+			wantPaused:  true,
+		},
+		{
+			name:       "PauseAtInPast",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       fmt.Sprintf(`{"pauseAt":%d}`, now-60),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "ResumeBeforePause",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			body:       fmt.Sprintf(`{"pauseAt":%d,"resumeAt":%d}`, now+7200, now+3600),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "DeletedSchedule",
+			scheduleID: "66666666-6666-6666-6666-666666666666",
+			body:       fmt.Sprintf(`{"pauseAt":%d}`, now+3600),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			UpdateRecurringScheduleStatusCallCount = 0
+
+			req, err := http.NewRequest("PUT", "/goscheduler/schedules/{scheduleId}/pause", bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"scheduleId": test.scheduleID})
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.PauseSchedule).ServeHTTP(rr, req)
+
+			if rr.Code != test.wantStatus {
+				t.Fatalf("unexpected status code: got %v, want %v, body: %s", rr.Code, test.wantStatus, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var response ScheduleResponse
+			if err = json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if len(response.Data.Actions) != test.wantActions {
+				t.Errorf("expected %d actions, got %d", test.wantActions, len(response.Data.Actions))
+			}
+			if paused := UpdateRecurringScheduleStatusCallCount == 1; paused != test.wantPaused {
+				t.Errorf("expected paused %v, got %v", test.wantPaused, paused)
+			}
+		})
+	}
+}
+
+func TestLifecycleCallback_Execute(t *testing.T) {
+	service := setupMocksForPauseTests()
+
+	for _, test := range []struct {
+		name       string
+		scheduleID string
+		action     string
+		appId      string
+		wantErr    bool
+		wantStatus store.Status
+	}{
+		{
+			name:       "Pause",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			action:     pauseAction,
+			appId:      "testApp",
+			wantStatus: store.Paused,
+		},
+		{
+			name:       "AlreadyPaused",
+			scheduleID: "22222222-2222-2222-2222-222222222222",
+			action:     pauseAction,
+			appId:      "testApp",
+		},
+		{
+			name:       "Resume",
+			scheduleID: "22222222-2222-2222-2222-222222222222",
+			action:     resumeAction,
+			appId:      "testApp",
+			wantStatus: store.Scheduled,
+		},
+		{
+			name:       "OtherApp",
+			scheduleID: "55555555-5555-5555-5555-555555555555",
+			action:     pauseAction,
+			appId:      "otherApp",
+			wantErr:    true,
+		},
+		{
+			name:       "NotFound",
+			scheduleID: "00000000-0000-0000-0000-000000000000",
+			action:     pauseAction,
+			appId:      "testApp",
+			wantErr:    true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			UpdateRecurringScheduleStatusCallCount = 0
+
+			uuid, _ := gocql.ParseUUID(test.scheduleID)
+			callback := &lifecycleCallback{
+				Type:    constants.LifecycleCallback,
+				Details: lifecycleDetails{ScheduleId: uuid, Action: test.action},
+				service: service,
+			}
+
+			err := callback.Execute(store.Schedule{AppId: test.appId})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Execute returned %v, expected error %v", err, test.wantErr)
+			}
+
+			if test.wantStatus == "" {
+				if UpdateRecurringScheduleStatusCallCount != 0 {
+					t.Errorf("expected status not to be updated")
+				}
+			} else if LastUpdateRecurringScheduleStatusArgs.Status != test.wantStatus {
+				t.Errorf("expected status %s, got %s", test.wantStatus, LastUpdateRecurringScheduleStatusArgs.Status)
+			}
+		})
+	}
+}
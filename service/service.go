@@ -36,13 +36,15 @@ type Service struct {
 }
 
 func NewService(config *c.Configuration, supervisor cluster.SupervisorHandler, clusterDao dao.ClusterDao, scheduleDAO dao.ScheduleDao, monitor monitoring.Monitor) *Service {
-	return &Service{
+	service := &Service{
 		Config:      config,
 		Supervisor:  supervisor,
 		ClusterDao:  clusterDao,
 		ScheduleDao: scheduleDAO,
 		Monitor:     monitor,
 	}
+	service.registerLifecycleCallback()
+	return service
 }
 
 func (s *Service) recordRequestStatus(name, status string) {
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/db_wrapper"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+)
+
+// GetShadowReport returns the comparison of the shadow cluster with the primary one, the writes mirrored and the reads
+// compared per query, the writes which failed on the shadow cluster and the rows read differently
+func (s *Service) GetShadowReport(w http.ResponseWriter, r *http.Request) {
+	s.recordRequestStatus(constants.GetShadowReport, constants.Success)
+	s.writeShadowReport(w, nil)
+}
+
+// ReconcileShadow replays on the shadow cluster the writes which failed on it
+func (s *Service) ReconcileShadow(w http.ResponseWriter, r *http.Request) {
+	reconciliation, err := s.reconcileShadow()
+	if err != nil {
+		s.recordRequestStatus(constants.ReconcileShadow, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	logger.FromContext(r.Context()).Infof("Replayed %d writes on the shadow cluster, %d failed again", reconciliation.Replayed, reconciliation.Failed)
+	s.recordRequestStatus(constants.ReconcileShadow, constants.Success)
+	s.writeShadowReport(w, &reconciliation)
+}
+
+func (s *Service) reconcileShadow() (ShadowReconciliation, error) {
+	if !s.Config.ShadowWriteConfig.Enabled {
+		return ShadowReconciliation{}, er.NewError(er.Conflict, errors.New("shadow writes are not enabled"))
+	}
+
+	replayed, failed := db_wrapper.DefaultMirror().Reconcile()
+	return ShadowReconciliation{Replayed: replayed, Failed: failed}, nil
+}
+
+func (s *Service) writeShadowReport(w http.ResponseWriter, reconciliation *ShadowReconciliation) {
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+	}
+	_ = json.NewEncoder(w).Encode(
+		ShadowReportResponse{
+			Status: status,
+			Data: ShadowReportData{
+				Enabled:        s.Config.ShadowWriteConfig.Enabled,
+				Reconciliation: reconciliation,
+				MirrorReport:   db_wrapper.DefaultMirror().Report(),
+			},
+		})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+const (
+	defaultSimulatedRuns = 1000
+	maxSimulatedRuns     = 10000
+)
+
+// Simulate replays a recurrence over a window of time, past or future, and returns the times at which it would have
+// fired. The number of runs listed can be set with the limit query param, all of them are counted.
+// With an app id the blackout windows of the app are excluded along with the global ones.
+func (s *Service) Simulate(w http.ResponseWriter, r *http.Request) {
+	var input sch.Simulation
+
+	limit, err := parseSimulatedRuns(r)
+	if err != nil {
+		s.recordRequestStatus(constants.SimulateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.recordRequestStatus(constants.SimulateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	if err = json.Unmarshal(b, &input); err != nil {
+		s.recordRequestStatus(constants.SimulateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	result, err := s.simulate(input, limit)
+	if err != nil {
+		s.recordRequestStatus(constants.SimulateSchedule, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.SimulateSchedule, getAppId(sch.Schedule{AppId: input.AppId}), constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    result.Count,
+	}
+	_ = json.NewEncoder(w).Encode(SimulateScheduleResponse{Status: status, Data: result})
+}
+
+func (s *Service) simulate(input sch.Simulation, limit int) (sch.SimulationResult, error) {
+	var app sch.App
+	if len(input.AppId) > 0 {
+		var err error
+		if app, err = s.getApp(input.AppId); err != nil {
+			return sch.SimulationResult{}, err
+		}
+	}
+
+	result, errs := sch.Simulate(input, app, s.Config.BlackoutConfig, limit)
+	if len(errs) > 0 {
+		return sch.SimulationResult{}, er.NewError(er.InvalidDataCode, errors.New(strings.Join(errs, ",")))
+	}
+	return result, nil
+}
+
+func parseSimulatedRuns(r *http.Request) (int, error) {
+	limitParam := r.URL.Query().Get("limit")
+	if len(limitParam) == 0 {
+		return defaultSimulatedRuns, nil
+	}
+
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit < 0 || limit > maxSimulatedRuns {
+		return 0, errors.New(fmt.Sprintf("limit should be an integer between 0 and %d", maxSimulatedRuns))
+	}
+
+	return limit, nil
+}
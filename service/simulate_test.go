@@ -0,0 +1,54 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestService_Simulate(t *testing.T) {
+	service := setupMocks()
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	window := fmt.Sprintf(`"from": %d, "to": %d, "timezone": "UTC"`, from, from+24*3600)
+
+	for _, test := range []struct {
+		name   string
+		query  string
+		body   string
+		status int
+		count  int
+		runs   int
+	}{
+		{"cron", "", `{"cronExpression": "0 * * * *", ` + window + `}`, http.StatusOK, 24, 24},
+		{"limited", "?limit=5", `{"every": "30m", ` + window + `}`, http.StatusOK, 48, 5},
+		{"app blackout", "", `{"appId": "test", "cronExpression": "0 * * * *", ` + window + `}`, http.StatusOK, 24, 24},
+		{"unknown app", "", `{"appId": "testGetAppErrorNotFound", "cronExpression": "0 * * * *", ` + window + `}`, http.StatusBadRequest, 0, 0},
+		{"invalid window", "", `{"cronExpression": "0 * * * *", "from": 10, "to": 5}`, http.StatusBadRequest, 0, 0},
+		{"invalid limit", "?limit=-1", `{"cronExpression": "0 * * * *", ` + window + `}`, http.StatusBadRequest, 0, 0},
+		{"invalid body", "", `{"cronExpression":`, http.StatusBadRequest, 0, 0},
+	} {
+		req, _ := http.NewRequest("POST", "/goscheduler/schedules/simulate"+test.query, bytes.NewBufferString(test.body))
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.Simulate).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.name, test.status, rr.Code, rr.Body.String())
+			continue
+		}
+		if rr.Code != http.StatusOK {
+			continue
+		}
+
+		var response SimulateScheduleResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+		if response.Data.Count != test.count || len(response.Data.Runs) != test.runs || response.Status.TotalCount != test.count {
+			t.Errorf("%s: expected %d runs with %d listed, got %+v", test.name, test.count, test.runs, response.Data)
+		}
+	}
+}
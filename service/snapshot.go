@@ -0,0 +1,485 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+	"github.com/myntra/goscheduler/util"
+)
+
+const (
+	snapshotFormatJSON   = "json"
+	snapshotFormatNDJSON = "ndjson"
+
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictFail      = "fail"
+)
+
+// ScheduleSnapshot is the portable export of the schedules of an app
+// In the ndjson format the snapshot is written as one schedule per line instead
+type ScheduleSnapshot struct {
+	AppId      string           `json:"appId"`
+	ExportedAt int64            `json:"exportedAt"`
+	Schedules  []store.Schedule `json:"schedules"`
+}
+
+// parseSnapshotFormat reads the format query param of the export and import endpoints
+func parseSnapshotFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", snapshotFormatJSON:
+		return snapshotFormatJSON, nil
+	case snapshotFormatNDJSON:
+		return snapshotFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("invalid format: %s, must be json or ndjson", format)
+	}
+}
+
+// ExportSchedules streams the recurring and the pending one time schedules of an app as a snapshot.
+// The runs of the recurring schedules are left out as they are created again from the recurring schedules.
+// The one time schedules are exported up to the days query param, by default the furthest a schedule can be created at.
+func (s *Service) ExportSchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	format, err := parseSnapshotFormat(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ExportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	app, err := s.getActiveOrInactiveApp(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ExportSchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	maxDays := app.GetMaxTTL(s.Config.AppLevelConfiguration.FutureScheduleCreationPeriod) / (24 * 60 * 60)
+	days, err := parseExportDays(r.URL.Query().Get("days"), maxDays)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ExportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	recurring, errs := s.ScheduleDao.GetCronSchedulesByApp(appId, "")
+	if len(errs) != 0 {
+		s.recordRequestAppStatus(constants.ExportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, errors.New(strings.Join(errs, ","))))
+		return
+	}
+
+	writer := newSnapshotWriter(w, format, appId)
+	for _, schedule := range recurring {
+		if schedule.Status == store.Deleted {
+			continue
+		}
+		if err = writer.write(schedule); err != nil {
+			break
+		}
+	}
+
+	// The current minute may already have been polled
+	start := time.Now().Truncate(time.Minute).Add(time.Minute)
+	end := start.Add(time.Duration(days) * 24 * time.Hour)
+	for bucket := start; err == nil && bucket.Before(end); bucket = bucket.Add(time.Minute) {
+		err = s.exportBucket(app, bucket, writer)
+	}
+
+	if err == nil {
+		err = writer.close()
+	}
+
+	// The snapshot is streamed, a failure after the first write can only truncate it
+	if err != nil {
+		log.Errorf("Error: %s while exporting schedules of app %s, %d schedules exported", err.Error(), appId, writer.count)
+		s.recordRequestAppStatus(constants.ExportSchedules, appId, constants.Fail)
+		if !writer.started {
+			er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		}
+		return
+	}
+
+	log.Infof("Exported %d schedules of app %s", writer.count, appId)
+	s.recordRequestAppStatus(constants.ExportSchedules, appId, constants.Success)
+}
+
+// parseExportDays parses the number of days ahead to export the one time schedules of
+func parseExportDays(days string, maxDays int) (int, error) {
+	if days == "" {
+		return maxDays, nil
+	}
+
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 0 || n > maxDays {
+		return 0, fmt.Errorf("invalid days: %s, must be between 0 and %d", days, maxDays)
+	}
+	return n, nil
+}
+
+// exportBucket writes the one time schedules of all the partitions of an app in a bucket
+func (s *Service) exportBucket(app store.App, bucket time.Time, writer *snapshotWriter) error {
+	for partitionId := 0; partitionId < int(app.Partitions); partitionId++ {
+		schedules, err := s.getBucketSchedules(app.AppId, partitionId, bucket)
+		if err != nil {
+			return err
+		}
+
+		for _, schedule := range schedules {
+			if !util.IsZeroUUID(schedule.ParentScheduleId) {
+				continue
+			}
+			if err = writer.write(schedule); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotWriter streams the schedules of a snapshot in the json or ndjson format
+type snapshotWriter struct {
+	w       http.ResponseWriter
+	format  string
+	appId   string
+	started bool
+	count   int
+}
+
+func newSnapshotWriter(w http.ResponseWriter, format string, appId string) *snapshotWriter {
+	return &snapshotWriter{w: w, format: format, appId: appId}
+}
+
+// start writes the headers and, for json, the opening of the snapshot
+func (sw *snapshotWriter) start() error {
+	sw.started = true
+	if sw.format == snapshotFormatNDJSON {
+		sw.w.Header().Set("Content-Type", "application/x-ndjson")
+		return nil
+	}
+
+	sw.w.Header().Set("Content-Type", "application/json")
+	appId, err := json.Marshal(sw.appId)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(sw.w, `{"appId":%s,"exportedAt":%d,"schedules":[`, appId, time.Now().Unix())
+	return err
+}
+
+func (sw *snapshotWriter) write(schedule store.Schedule) error {
+	if !sw.started {
+		if err := sw.start(); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case sw.format == snapshotFormatNDJSON:
+		b = append(b, '\n')
+	case sw.count > 0:
+		b = append([]byte{','}, b...)
+	}
+
+	if _, err = sw.w.Write(b); err != nil {
+		return err
+	}
+	sw.count++
+	return nil
+}
+
+// close completes the snapshot
+func (sw *snapshotWriter) close() error {
+	if !sw.started {
+		if err := sw.start(); err != nil {
+			return err
+		}
+	}
+
+	if sw.format == snapshotFormatNDJSON {
+		return nil
+	}
+	_, err := io.WriteString(sw.w, "]}\n")
+	return err
+}
+
+// importOptions are the options of a schedule import
+type importOptions struct {
+	format   string
+	conflict string
+	remapIds bool
+}
+
+func parseImportOptions(r *http.Request) (importOptions, error) {
+	var options importOptions
+	var err error
+
+	if options.format, err = parseSnapshotFormat(r); err != nil {
+		return options, err
+	}
+
+	query := r.URL.Query()
+	switch options.conflict = query.Get("conflict"); options.conflict {
+	case "":
+		options.conflict = conflictSkip
+	case conflictSkip, conflictOverwrite, conflictFail:
+	default:
+		return options, fmt.Errorf("invalid conflict: %s, must be skip, overwrite or fail", options.conflict)
+	}
+
+	if remapIds := query.Get("remap_ids"); remapIds != "" {
+		if options.remapIds, err = strconv.ParseBool(remapIds); err != nil {
+			return options, fmt.Errorf("invalid remap_ids: %s", remapIds)
+		}
+	}
+
+	return options, nil
+}
+
+// readImportedSchedules reads the schedules of the snapshot in the body of an import request. A binary snapshot is the
+// counterpart of the json format whatever the format query param, the others are read in the format of the param.
+func readImportedSchedules(r *http.Request, format string) ([]store.Schedule, error) {
+	body, wire, err := readRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []store.Schedule
+	switch wire {
+	case ProtobufContentType:
+		schedules, err = unmarshalProtoSnapshot(body)
+	case MsgpackContentType:
+		var snapshot ScheduleSnapshot
+		err = unmarshalMsgpack(body, &snapshot)
+		schedules = snapshot.Schedules
+	default:
+		return readSnapshot(body, format)
+	}
+	if err != nil {
+		return nil, invalidBody(r, err)
+	}
+	return schedules, nil
+}
+
+// readSnapshot reads the schedules of a snapshot in the json or ndjson format
+func readSnapshot(body []byte, format string) ([]store.Schedule, error) {
+	if format == snapshotFormatJSON {
+		var snapshot ScheduleSnapshot
+		if err := json.Unmarshal(body, &snapshot); err != nil {
+			return nil, err
+		}
+		return snapshot.Schedules, nil
+	}
+
+	var schedules []store.Schedule
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), len(body)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var schedule store.Schedule
+		if err := json.Unmarshal(scanner.Bytes(), &schedule); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, scanner.Err()
+}
+
+// ImportSchedules creates the schedules of a snapshot in an app.
+// The schedules keep their ids unless remap_ids is set, and a schedule whose id already exists is
+// skipped, overwritten or fails the whole import as per the conflict query param.
+func (s *Service) ImportSchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	if s.shed(w, r, constants.ImportSchedules) {
+		s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Fail)
+		return
+	}
+
+	options, err := parseImportOptions(r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	schedules, err := readImportedSchedules(r, options.format)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	if _, err = s.getApp(appId); err != nil {
+		s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	conflicts, err := s.findImportConflicts(schedules, options)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+
+	if options.conflict == conflictFail && len(conflicts) > 0 {
+		var ids []string
+		for id := range conflicts {
+			ids = append(ids, id.String())
+		}
+		s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.Conflict, fmt.Errorf("schedules already exist: %s", strings.Join(ids, ","))))
+		return
+	}
+
+	data := s.importSchedules(r, appId, schedules, conflicts, options)
+	log.Infof("Imported schedules into app %s: %+v", appId, data)
+	s.recordRequestAppStatus(constants.ImportSchedules, appId, constants.Success)
+
+	_ = writeResponse(w, r,
+		ImportSchedulesResponse{
+			Status: Status{
+				StatusCode:    constants.SuccessCode200,
+				StatusMessage: constants.Success,
+				StatusType:    constants.Success,
+				TotalCount:    len(schedules),
+			},
+			Data: data,
+		})
+}
+
+// findImportConflicts finds the schedules of the snapshot whose ids already exist.
+// Deleted recurring schedules are not conflicts as they are overwritten on creation.
+func (s *Service) findImportConflicts(schedules []store.Schedule, options importOptions) (map[gocql.UUID]bool, error) {
+	conflicts := make(map[gocql.UUID]bool)
+	if options.remapIds {
+		return conflicts, nil
+	}
+
+	for _, schedule := range schedules {
+		if util.IsZeroUUID(schedule.ScheduleId) {
+			continue
+		}
+
+		existing, err := s.ScheduleDao.GetSchedule(schedule.ScheduleId)
+		switch {
+		case err == gocql.ErrNotFound:
+		case err != nil:
+			return nil, err
+		case existing.Status != store.Deleted:
+			conflicts[schedule.ScheduleId] = true
+		}
+	}
+	return conflicts, nil
+}
+
+// importSchedules creates the schedules of the snapshot in the app and returns the outcome per schedule
+func (s *Service) importSchedules(r *http.Request, appId string, schedules []store.Schedule, conflicts map[gocql.UUID]bool, options importOptions) ImportSchedulesData {
+	data := ImportSchedulesData{}
+	if options.remapIds {
+		data.IdMapping = make(map[string]string)
+	}
+
+	fail := func(index int, id gocql.UUID, err error) {
+		data.Failed++
+		data.Errors = append(data.Errors, ImportError{Index: index, ScheduleId: id.String(), Error: err.Error()})
+	}
+
+	for i, schedule := range schedules {
+		id := schedule.ScheduleId
+		status := schedule.Status
+
+		schedule.AppId = appId
+		schedule.Status = ""
+		if status == store.Draft {
+			schedule.Status = store.Draft
+		}
+
+		if conflicts[id] {
+			if options.conflict == conflictSkip {
+				data.Skipped++
+				continue
+			}
+			if _, err := s.ScheduleDao.DeleteSchedule(id); err != nil {
+				fail(i, id, err)
+				continue
+			}
+		}
+
+		keep := id
+		if options.remapIds {
+			keep = gocql.UUID{}
+		}
+
+		created, err := s.createSchedule(r.Context(), schedule, keep)
+		if err != nil {
+			fail(i, id, err)
+			continue
+		}
+
+		// A paused recurring schedule stays paused in the target
+		if status == store.Paused && created.IsRecurring() {
+			if created, err = s.ScheduleDao.UpdateRecurringScheduleStatus(created, store.Paused); err != nil {
+				fail(i, id, err)
+				continue
+			}
+		}
+
+		if conflicts[id] {
+			data.Overwritten++
+		} else {
+			data.Imported++
+		}
+		if options.remapIds {
+			data.IdMapping[id.String()] = created.ScheduleId.String()
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+type MockScheduleDaoForSnapshot struct {
+	MockScheduleDaoForResize
+	existing gocql.UUID
+	deleted  []gocql.UUID
+	paused   int
+}
+
+func (m *MockScheduleDaoForSnapshot) GetCronSchedulesByApp(appId string, status store.Status) ([]store.Schedule, []string) {
+	callback := &store.HttpCallback{Type: "http", Details: store.Details{Url: "http://localhost:8080/callback", Method: "POST"}}
+	return []store.Schedule{
+		{ScheduleId: gocql.TimeUUID(), AppId: appId, Payload: "{}", CronExpression: "0 * * * *", Callback: callback, Status: store.Scheduled},
+		{ScheduleId: gocql.TimeUUID(), AppId: appId, Payload: "{}", CronExpression: "0 * * * *", Callback: callback, Status: store.Deleted},
+	}, nil
+}
+
+func (m *MockScheduleDaoForSnapshot) GetSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	if uuid == m.existing {
+		return store.Schedule{ScheduleId: uuid, AppId: "test", Status: store.Scheduled}, nil
+	}
+	return store.Schedule{}, gocql.ErrNotFound
+}
+
+func (m *MockScheduleDaoForSnapshot) DeleteSchedule(uuid gocql.UUID) (store.Schedule, error) {
+	m.deleted = append(m.deleted, uuid)
+	return store.Schedule{ScheduleId: uuid}, nil
+}
+
+func (m *MockScheduleDaoForSnapshot) UpdateRecurringScheduleStatus(schedule store.Schedule, status store.Status) (store.Schedule, error) {
+	if status == store.Paused {
+		m.paused++
+	}
+	schedule.Status = status
+	return schedule, nil
+}
+
+func TestService_ExportSchedules(t *testing.T) {
+	service := setupMocks()
+	service.ClusterDao = MockClusterDaoForResize{}
+
+	bucket := time.Now().Add(10 * time.Minute).Truncate(time.Minute)
+	row := func(partitionId int, parent gocql.UUID) map[string]interface{} {
+		return map[string]interface{}{
+			"app_id":              "test",
+			"partition_id":        partitionId,
+			"schedule_id":         gocql.TimeUUID(),
+			"schedule_time_group": bucket,
+			"schedule_time":       bucket,
+			"callback_type":       "http",
+			"callback_details":    `{"url":"http://localhost:8080/callback","method":"POST"}`,
+			"payload":             "{}",
+			"parent_schedule_id":  parent,
+		}
+	}
+	service.ScheduleDao = &MockScheduleDaoForSnapshot{
+		MockScheduleDaoForResize: MockScheduleDaoForResize{
+			buckets: map[time.Time][]map[string]interface{}{
+				bucket: {row(0, gocql.UUID{}), row(1, gocql.UUID{}), row(1, gocql.TimeUUID())},
+			},
+		},
+	}
+
+	for _, test := range []struct {
+		query     string
+		Status    int
+		schedules int
+	}{
+		{"", http.StatusOK, 3},
+		{"?format=ndjson", http.StatusOK, 3},
+		{"?days=0", http.StatusOK, 1},
+		{"?days=2", http.StatusBadRequest, 0},
+		{"?format=xml", http.StatusBadRequest, 0},
+	} {
+		req, err := http.NewRequest("GET", "/goscheduler/apps/{appId}/export"+test.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"appId": "test"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.ExportSchedules).ServeHTTP(rr, req)
+
+		if rr.Code != test.Status {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", test.query, rr.Code, test.Status)
+			continue
+		}
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		var schedules []store.Schedule
+		if strings.Contains(test.query, "ndjson") {
+			schedules, err = readSnapshot(rr.Body.Bytes(), snapshotFormatNDJSON)
+		} else {
+			schedules, err = readSnapshot(rr.Body.Bytes(), snapshotFormatJSON)
+		}
+		if err != nil {
+			t.Fatalf("%s: invalid snapshot %s: %v", test.query, rr.Body.String(), err)
+		}
+		if len(schedules) != test.schedules {
+			t.Errorf("%s: expected %d schedules, got %d", test.query, test.schedules, len(schedules))
+		}
+	}
+}
+
+func TestService_ImportSchedules(t *testing.T) {
+	existing := gocql.TimeUUID()
+	future := time.Now().Add(time.Hour).Unix()
+	callback := `"callback": {"type": "http", "details": {"url": "http://localhost:8080/callback", "method": "POST"}}`
+	schedules := []string{
+		fmt.Sprintf(`{"scheduleId": "%s", "appId": "source", "payload": "{}", "scheduleTime": %d, %s}`, gocql.TimeUUID(), future, callback),
+		fmt.Sprintf(`{"scheduleId": "%s", "appId": "source", "payload": "{}", "scheduleTime": %d, %s}`, existing, future, callback),
+		fmt.Sprintf(`{"scheduleId": "%s", "appId": "source", "payload": "{}", "cronExpression": "0 * * * *", "status": "PAUSED", %s}`, gocql.TimeUUID(), callback),
+		fmt.Sprintf(`{"scheduleId": "%s", "appId": "source", "payload": "{}", "scheduleTime": %d, %s}`, gocql.TimeUUID(), time.Now().Add(-time.Hour).Unix(), callback),
+	}
+	jsonBody := fmt.Sprintf(`{"appId": "source", "schedules": [%s]}`, strings.Join(schedules, ","))
+	ndjsonBody := strings.Join(schedules, "\n") + "\n"
+
+	for _, test := range []struct {
+		query    string
+		body     string
+		Status   int
+		expected ImportSchedulesData
+		deleted  int
+	}{
+		{"", jsonBody, http.StatusOK, ImportSchedulesData{Imported: 2, Skipped: 1, Failed: 1}, 0},
+		{"?format=ndjson&conflict=overwrite", ndjsonBody, http.StatusOK, ImportSchedulesData{Imported: 2, Overwritten: 1, Failed: 1}, 1},
+		{"?remap_ids=true", jsonBody, http.StatusOK, ImportSchedulesData{Imported: 3, Failed: 1}, 0},
+		{"?conflict=fail", jsonBody, http.StatusConflict, ImportSchedulesData{}, 0},
+		{"?conflict=merge", jsonBody, http.StatusBadRequest, ImportSchedulesData{}, 0},
+		{"", `{"schedules": [}`, http.StatusBadRequest, ImportSchedulesData{}, 0},
+	} {
+		scheduleDao := &MockScheduleDaoForSnapshot{existing: existing}
+		service := setupMocks()
+		service.ScheduleDao = scheduleDao
+
+		req, err := http.NewRequest("POST", "/goscheduler/apps/{appId}/import"+test.query, bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"appId": "test"})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.ImportSchedules).ServeHTTP(rr, req)
+
+		if rr.Code != test.Status {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", test.query, rr.Code, test.Status)
+			continue
+		}
+		if len(scheduleDao.deleted) != test.deleted {
+			t.Errorf("%s: expected %d schedules deleted, got %d", test.query, test.deleted, len(scheduleDao.deleted))
+		}
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		var resp ImportSchedulesResponse
+		if err = json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		data := resp.Data
+		if data.Imported != test.expected.Imported || data.Overwritten != test.expected.Overwritten ||
+			data.Skipped != test.expected.Skipped || data.Failed != test.expected.Failed {
+			t.Errorf("%s: expected %+v, got %+v", test.query, test.expected, data)
+		}
+		if scheduleDao.paused != 1 {
+			t.Errorf("%s: expected the paused schedule to stay paused", test.query)
+		}
+		if strings.Contains(test.query, "remap_ids") && len(data.IdMapping) != 3 {
+			t.Errorf("%s: expected 3 remapped ids, got %v", test.query, data.IdMapping)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// GetSuspendedSchedules returns the recurring schedules of an app suspended because their callback host was unreachable
+func (s *Service) GetSuspendedSchedules(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	schedules, err := s.suspendedSchedules(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetSuspendedSchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+	if schedules == nil {
+		schedules = []store.Schedule{}
+	}
+
+	s.recordRequestAppStatus(constants.GetSuspendedSchedules, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(schedules)}
+	_ = writeListing(w, r, GetCronSchedulesResponse{Status: status, Data: schedules})
+}
+
+// ResumeSuspendedSchedules starts a job resuming the suspended schedules of an app given in the body, or all of them
+func (s *Service) ResumeSuspendedSchedules(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	appId := mux.Vars(r)["appId"]
+
+	var input store.ResumeSuspendedRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil && len(bytes.TrimSpace(body)) != 0 {
+		err = json.Unmarshal(body, &input)
+	}
+	if err != nil {
+		s.recordRequestAppStatus(constants.ResumeSuspendedSchedules, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	job, err := s.StartResumeSuspended(appId, input)
+	if err != nil {
+		s.recordRequestAppStatus(constants.ResumeSuspendedSchedules, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	log.Infof("Started job %s to resume %d suspended schedules of app %s", job.JobId, job.Total, appId)
+	s.recordRequestAppStatus(constants.ResumeSuspendedSchedules, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: job.Total}
+	_ = json.NewEncoder(w).Encode(JobResponse{Status: status, Data: JobData{Job: job}})
+}
+
+// StartResumeSuspended creates the job resuming the suspended schedules of the request and runs it in the background
+func (s *Service) StartResumeSuspended(appId string, input store.ResumeSuspendedRequest) (store.Job, error) {
+	if _, err := s.getApp(appId); err != nil {
+		return store.Job{}, err
+	}
+
+	scheduleIds := input.ScheduleIds
+	if len(scheduleIds) == 0 {
+		suspended, err := s.suspendedSchedules(appId)
+		if err != nil {
+			return store.Job{}, err
+		}
+		for _, schedule := range suspended {
+			scheduleIds = append(scheduleIds, schedule.ScheduleId.String())
+		}
+	}
+	if len(scheduleIds) > store.MaxBulkUpdateSchedules {
+		return store.Job{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("%d schedules to resume, a job is limited to %d", len(scheduleIds), store.MaxBulkUpdateSchedules))
+	}
+
+	request, _ := json.Marshal(input)
+	job := store.NewJob(store.ResumeSuspendedJob, appId, request, len(scheduleIds), time.Now())
+	if err := s.ScheduleDao.UpsertJob(job, s.Config.RetentionConfig.JobRetentionPeriod); err != nil {
+		return store.Job{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	go s.runJob(job, scheduleIds, s.resumeSuspendedProcessor(appId))
+	return job, nil
+}
+
+// resumeSuspendedProcessor resumes the suspended schedules of an app one by one
+func (s *Service) resumeSuspendedProcessor(appId string) jobProcessor {
+	return func(itemId string) store.JobItem {
+		item := store.JobItem{ItemId: itemId, Status: store.Success}
+
+		if err := s.resumeSuspendedSchedule(appId, itemId); err != nil {
+			item.Status = store.Failure
+			item.Error = err.Error()
+		}
+		return item
+	}
+}
+
+func (s *Service) resumeSuspendedSchedule(appId string, itemId string) error {
+	scheduleId, err := gocql.ParseUUID(itemId)
+	if err != nil {
+		return err
+	}
+	schedule, err := s.ScheduleDao.GetSchedule(scheduleId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case schedule.AppId != appId:
+		return fmt.Errorf("schedule %s does not belong to app %s", itemId, appId)
+	case schedule.Status != store.Suspended:
+		return fmt.Errorf("schedule %s is not suspended", itemId)
+	}
+	_, _, _, err = s.resumeRecurringSchedule(schedule)
+	return err
+}
+
+// suspendedSchedules returns the suspended recurring schedules of an app
+func (s *Service) suspendedSchedules(appId string) ([]store.Schedule, error) {
+	if _, err := s.getApp(appId); err != nil {
+		return nil, err
+	}
+
+	recurring, errs := s.ScheduleDao.GetCronSchedulesByApp(appId, store.Suspended)
+	if len(errs) != 0 {
+		return nil, er.NewError(er.DataFetchFailure, errors.New(strings.Join(errs, ",")))
+	}
+
+	var suspended []store.Schedule
+	for _, schedule := range recurring {
+		if schedule.AppId == appId && schedule.Status == store.Suspended {
+			suspended = append(suspended, schedule)
+		}
+	}
+	return suspended, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_GetSuspendedSchedules(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		status int
+	}{
+		{"testApp", http.StatusOK},
+		{"testDeactivated", http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/"+test.appId+"/suspended", nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetSuspendedSchedules).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d with body %s", test.appId, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_ResumeSuspendedSchedules(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		body   string
+		status int
+	}{
+		{"testApp", "", http.StatusOK},
+		{"testApp", `{"scheduleIds":["84d0d5b8-d953-11ed-a827-aa665a372253"]}`, http.StatusOK},
+		{"testApp", `{"scheduleIds":`, http.StatusBadRequest},
+		{"testDeactivated", "", http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("POST", "/goscheduler/apps/"+test.appId+"/suspended/resume", bytes.NewBufferString(test.body))
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.ResumeSuspendedSchedules).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d with body %s", test.appId, test.body, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_ResumeSuspendedProcessor(t *testing.T) {
+	service := setupMocks()
+
+	if item := service.resumeSuspendedProcessor("testApp")("invalid"); item.Status != store.Failure {
+		t.Errorf("expected an invalid schedule id to fail, got %+v", item)
+	}
+	if item := service.resumeSuspendedProcessor("testApp")("84d0d5b8-d953-11ed-a827-aa665a372253"); item.Status != store.Failure {
+		t.Errorf("expected a schedule of another app to fail, got %+v", item)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+// templateVersions serializes the versions created by the node, concurrent updates of a template on different
+// nodes can still write the same version, the last one wins
+var templateVersions sync.Mutex
+
+// templateRequest is the body of the template create and update APIs, the name is taken from the path on updates
+type templateRequest struct {
+	Name     string          `json:"name,omitempty"`
+	Callback json.RawMessage `json:"callback"`
+}
+
+// CreateCallbackTemplate creates the first version of a template of an app
+func (s *Service) CreateCallbackTemplate(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	template, err := s.saveCallbackTemplate(appId, "", r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.CreateCallbackTemplate, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.CreateCallbackTemplate, appId, constants.Success)
+
+	w.WriteHeader(http.StatusCreated)
+	status := Status{StatusCode: constants.SuccessCode201, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(CallbackTemplateResponse{Status: status, Data: template})
+}
+
+// UpdateCallbackTemplate creates the next version of a template of an app.
+// The schedules referencing the latest version of the template fire the new version from then on.
+func (s *Service) UpdateCallbackTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	template, err := s.saveCallbackTemplate(appId, vars["name"], r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.UpdateCallbackTemplate, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.UpdateCallbackTemplate, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(CallbackTemplateResponse{Status: status, Data: template})
+}
+
+// saveCallbackTemplate creates the first version of the template named in the body when name is empty,
+// the next version of the named template otherwise
+func (s *Service) saveCallbackTemplate(appId string, name string, r *http.Request) (store.CallbackTemplate, error) {
+	var input templateRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return store.CallbackTemplate{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+	if err = json.Unmarshal(body, &input); err != nil {
+		return store.CallbackTemplate{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	app, err := s.getApp(appId)
+	if err != nil {
+		return store.CallbackTemplate{}, err
+	}
+
+	update := name != ""
+	if !update {
+		name = input.Name
+	}
+	template := store.CallbackTemplate{
+		AppId:     appId,
+		Name:      name,
+		Version:   1,
+		Callback:  input.Callback,
+		CreatedAt: time.Now(),
+	}
+	if err = template.Validate(); err != nil {
+		return store.CallbackTemplate{}, er.NewError(er.InvalidDataCode, err)
+	}
+	callback, _ := template.GetCallback()
+	if err = s.checkCallbackUrls(app, callback, ""); err != nil {
+		return store.CallbackTemplate{}, err
+	}
+
+	templateVersions.Lock()
+	defer templateVersions.Unlock()
+
+	switch latest, err := s.ClusterDao.GetCallbackTemplate(appId, name, 0); {
+	case err == gocql.ErrNotFound && update:
+		return store.CallbackTemplate{}, er.NewError(er.DataNotFound, fmt.Errorf("template %s of app %s does not exist", name, appId))
+	case err == gocql.ErrNotFound:
+	case err != nil:
+		return store.CallbackTemplate{}, er.NewError(er.DataFetchFailure, err)
+	case !update:
+		return store.CallbackTemplate{}, er.NewError(er.Conflict, fmt.Errorf("template %s of app %s already exists", name, appId))
+	default:
+		template.Version = latest.Version + 1
+	}
+
+	if err = s.ClusterDao.CreateCallbackTemplate(template); err != nil {
+		return store.CallbackTemplate{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	store.InvalidateTemplate(appId, name)
+	return template, nil
+}
+
+// GetCallbackTemplates returns the latest version of every template of an app
+func (s *Service) GetCallbackTemplates(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	templates, err := s.ClusterDao.GetCallbackTemplates(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetCallbackTemplates, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+	if templates == nil {
+		templates = []store.CallbackTemplate{}
+	}
+
+	s.recordRequestAppStatus(constants.GetCallbackTemplates, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(templates)}
+	_ = json.NewEncoder(w).Encode(CallbackTemplatesResponse{Status: status, Data: templates})
+}
+
+// GetCallbackTemplate returns a version of a template of an app, the latest one unless a version is given
+func (s *Service) GetCallbackTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	appId := vars["appId"]
+
+	template, err := s.fetchCallbackTemplate(appId, vars["name"], r.URL.Query().Get("version"))
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetCallbackTemplate, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetCallbackTemplate, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(CallbackTemplateResponse{Status: status, Data: template})
+}
+
+func (s *Service) fetchCallbackTemplate(appId string, name string, versionParam string) (store.CallbackTemplate, error) {
+	version := 0
+	if versionParam != "" {
+		var err error
+		if version, err = strconv.Atoi(versionParam); err != nil || version <= 0 {
+			return store.CallbackTemplate{}, er.NewError(er.InvalidDataCode, fmt.Errorf("invalid version %s, must be a positive integer", versionParam))
+		}
+	}
+
+	switch template, err := s.ClusterDao.GetCallbackTemplate(appId, name, version); {
+	case err == gocql.ErrNotFound:
+		return store.CallbackTemplate{}, er.NewError(er.DataNotFound, fmt.Errorf("template %s of app %s does not exist", name, appId))
+	case err != nil:
+		return store.CallbackTemplate{}, er.NewError(er.DataFetchFailure, err)
+	default:
+		return template, nil
+	}
+}
+
+// checkTemplateCallback checks that the template referenced by the callback of a schedule of the app exists
+func (s *Service) checkTemplateCallback(appId string, callback store.Callback) error {
+	ref, ok := callback.(*store.TemplateCallback)
+	if !ok {
+		return nil
+	}
+
+	switch _, err := s.ClusterDao.GetCallbackTemplate(appId, ref.Details.Name, ref.Details.Version); {
+	case err == gocql.ErrNotFound:
+		return er.NewError(er.InvalidDataCode, errors.New(fmt.Sprintf("template %s of app %s does not exist", ref.Details.Name, appId)))
+	case err != nil:
+		return er.NewError(er.DataFetchFailure, err)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/store"
+)
+
+const testTemplateCallback = `{"type":"http","details":{"url":"http://localhost:8080/test","method":"POST","headers":{}}}`
+
+func newTemplateService() *Service {
+	store.Registry[constants.TemplateCallback] = func() store.Callback { return &store.TemplateCallback{} }
+	return setupMocks()
+}
+
+func TestService_CreateCallbackTemplate(t *testing.T) {
+	service := newTemplateService()
+
+	for _, test := range []struct {
+		appId  string
+		body   string
+		status int
+	}{
+		{"testApp", `{"name":"newTemplate","callback":` + testTemplateCallback + `}`, http.StatusCreated},
+		{"testApp", `{"name":"testTemplate","callback":` + testTemplateCallback + `}`, http.StatusConflict},
+		{"testApp", `{"name":"bad name","callback":` + testTemplateCallback + `}`, http.StatusBadRequest},
+		{"testApp", `{"name":"newTemplate","callback":{"type":"template","details":{"name":"testTemplate"}}}`, http.StatusBadRequest},
+		{"testApp", `{"name":`, http.StatusBadRequest},
+		{"testDeactivated", `{"name":"newTemplate","callback":` + testTemplateCallback + `}`, http.StatusBadRequest},
+		{"testGetTemplateError", `{"name":"newTemplate","callback":` + testTemplateCallback + `}`, http.StatusInternalServerError},
+		{"testCreateTemplateError", `{"name":"newTemplate","callback":` + testTemplateCallback + `}`, http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("POST", "/goscheduler/apps/"+test.appId+"/templates", bytes.NewBufferString(test.body))
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.CreateCallbackTemplate).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d with body %s", test.appId, test.body, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_UpdateCallbackTemplate(t *testing.T) {
+	service := newTemplateService()
+
+	for _, test := range []struct {
+		name    string
+		status  int
+		version string
+	}{
+		{"testTemplate", http.StatusOK, `"version":3`},
+		{"missingTemplate", http.StatusNotFound, ""},
+	} {
+		req, _ := http.NewRequest("PUT", "/goscheduler/apps/testApp/templates/"+test.name, bytes.NewBufferString(`{"callback":`+testTemplateCallback+`}`))
+		req = mux.SetURLVars(req, map[string]string{"appId": "testApp", "name": test.name})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.UpdateCallbackTemplate).ServeHTTP(rr, req)
+
+		if rr.Code != test.status || !bytes.Contains(rr.Body.Bytes(), []byte(test.version)) {
+			t.Errorf("%s: expected status %d with %s, got %d with body %s", test.name, test.status, test.version, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestService_GetCallbackTemplate(t *testing.T) {
+	service := newTemplateService()
+
+	for _, test := range []struct {
+		appId  string
+		name   string
+		query  string
+		status int
+	}{
+		{"testApp", "testTemplate", "", http.StatusOK},
+		{"testApp", "testTemplate", "?version=1", http.StatusOK},
+		{"testApp", "testTemplate", "?version=3", http.StatusNotFound},
+		{"testApp", "testTemplate", "?version=latest", http.StatusBadRequest},
+		{"testApp", "missingTemplate", "", http.StatusNotFound},
+		{"testGetTemplateError", "testTemplate", "", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/"+test.appId+"/templates/"+test.name+test.query, nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId, "name": test.name})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetCallbackTemplate).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s%s: expected status %d, got %d", test.appId, test.name, test.query, test.status, rr.Code)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/goscheduler/apps/testApp/templates", nil)
+	req = mux.SetURLVars(req, map[string]string{"appId": "testApp"})
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.GetCallbackTemplates).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !bytes.Contains(rr.Body.Bytes(), []byte(`"name":"testTemplate"`)) {
+		t.Errorf("Expected the templates of the app, got %d with body %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestService_CheckTemplateCallback(t *testing.T) {
+	service := newTemplateService()
+
+	if err := service.checkTemplateCallback("testApp", &store.TemplateCallback{Details: store.TemplateRef{Name: "testTemplate"}}); err != nil {
+		t.Errorf("Expected an existing template to be accepted, got %v", err)
+	}
+	if err := service.checkTemplateCallback("testApp", &store.TemplateCallback{Details: store.TemplateRef{Name: "missingTemplate"}}); err == nil {
+		t.Errorf("Expected a missing template to be rejected")
+	}
+	if err := service.checkTemplateCallback("testApp", &store.HttpCallback{}); err != nil {
+		t.Errorf("Expected other callbacks to be accepted, got %v", err)
+	}
+}
@@ -25,36 +25,36 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/myntra/goscheduler/constants"
 	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/store"
 	"github.com/myntra/goscheduler/util"
 )
 
 // validateImmutableFields ensures that immutable fields (appId, scheduleId, partitionId)
-// are not being modified in the update request
+// are not being modified in the update request, the modified fields are returned as an InvalidDataCode error
 func (s *Service) validateImmutableFields(inputSchedule, existing store.Schedule) error {
-	var errs []string
+	var errs []er.FieldError
 
 	// Verify that appId is not being modified (only if provided in inputSchedule)
 	if inputSchedule.AppId != "" && inputSchedule.AppId != existing.AppId {
-		glog.Infof("Cannot modify appId for schedule with id %s", existing.ScheduleId)
-		errs = append(errs, "Cannot modify appId for an existing schedule")
+		logger.Infof("Cannot modify appId for schedule with id %s", existing.ScheduleId)
+		errs = append(errs, er.FieldError{Field: "appId", Message: "Cannot modify appId for an existing schedule"})
 	}
 
 	// Verify that scheduleId is not being modified (only if provided in inputSchedule)
 	if !util.IsZeroUUID(inputSchedule.ScheduleId) && inputSchedule.ScheduleId != existing.ScheduleId {
-		glog.Infof("Cannot modify scheduleId for schedule with id %s", existing.ScheduleId)
-		errs = append(errs, "Cannot modify scheduleId for an existing schedule")
+		logger.Infof("Cannot modify scheduleId for schedule with id %s", existing.ScheduleId)
+		errs = append(errs, er.FieldError{Field: "scheduleId", Message: "Cannot modify scheduleId for an existing schedule"})
 	}
 
 	if len(errs) > 0 {
-		return errors.New(strings.Join(errs, ","))
+		return er.NewValidationError(er.InvalidDataCode, errs)
 	}
 
 	return nil
@@ -101,12 +101,45 @@ func (s *Service) validateExistingScheduleAndExtractApp(uuid gocql.UUID) (*store
 // updateScheduleFields updates the allowed fields in the existing schedule
 func updateScheduleFields(existingSchedule *store.Schedule, inputSchedule store.Schedule) error {
 	// Update allowed fields
+	// Switching between cron, interval and RRULE recurrence replaces the others
 	if inputSchedule.CronExpression != "" {
 		existingSchedule.CronExpression = inputSchedule.CronExpression
+		existingSchedule.Every = ""
+		existingSchedule.RRule = ""
+		existingSchedule.Anchor = 0
 	}
+	if inputSchedule.Every != "" {
+		existingSchedule.Every = inputSchedule.Every
+		existingSchedule.CronExpression = ""
+		existingSchedule.RRule = ""
+	}
+	if inputSchedule.RRule != "" {
+		existingSchedule.RRule = inputSchedule.RRule
+		existingSchedule.CronExpression = ""
+		existingSchedule.Every = ""
+	}
+	if inputSchedule.Anchor != 0 {
+		existingSchedule.Anchor = inputSchedule.Anchor
+	}
+	existingSchedule.SetDefaultAnchor()
 	if inputSchedule.Payload != "" {
 		existingSchedule.Payload = inputSchedule.Payload
 	}
+	if inputSchedule.StatusCallback != "" {
+		existingSchedule.StatusCallback = inputSchedule.StatusCallback
+	}
+	if inputSchedule.PausePolicy != "" {
+		existingSchedule.PausePolicy = inputSchedule.PausePolicy
+	}
+	if inputSchedule.ConcurrencyPolicy != "" {
+		existingSchedule.ConcurrencyPolicy = inputSchedule.ConcurrencyPolicy
+	}
+	if inputSchedule.Group != "" {
+		existingSchedule.Group = inputSchedule.Group
+	}
+	if inputSchedule.Region != "" {
+		existingSchedule.Region = inputSchedule.Region
+	}
 	if inputSchedule.CallbackRaw != nil {
 		existingSchedule.CallbackRaw = inputSchedule.CallbackRaw
 		// Create Callback from CallbackRaw
@@ -119,25 +152,48 @@ func updateScheduleFields(existingSchedule *store.Schedule, inputSchedule store.
 	return nil
 }
 
+// validateCanaryPolicy checks the canary asked for by an update, which must update the callback
+func validateCanaryPolicy(inputSchedule store.Schedule) error {
+	if inputSchedule.CallbackRaw == nil {
+		return errors.New("a canary needs an updated callback")
+	}
+	return inputSchedule.Canary.Validate()
+}
+
 // validateUpdatedSchedule validates the schedule after updates
+// A payload over the limit of the app is reported as too large, any other invalid field as unprocessable.
 func (s *Service) validateUpdatedSchedule(schedule *store.Schedule, app store.App) error {
-	validationErrs := schedule.ValidateSchedule(app, s.Config.AppLevelConfiguration)
-	if len(validationErrs) > 0 {
-		return fmt.Errorf("validation errors: %s", strings.Join(validationErrs, ","))
+	if err := schedule.ValidatePayloadSize(app, s.Config.AppLevelConfiguration); err != nil {
+		return er.NewError(er.PayloadTooLarge, err)
+	}
+
+	if details := schedule.ValidateFields(app, s.Config.AppLevelConfiguration); len(details) > 0 {
+		err := er.NewValidationError(er.UnprocessableEntity, details)
+		err.Err = fmt.Errorf("validation errors: %s", err.Err)
+		return err
+	}
+	if err := s.checkScheduleGroup(schedule.AppId, schedule.Group); err != nil {
+		return err
+	}
+	if err := s.checkCallbackUrls(app, schedule.Callback, schedule.StatusCallback); err != nil {
+		return er.NewError(er.UnprocessableEntity, err)
 	}
 	return nil
 }
 
 // UpdateRecurringSchedule updates the existing recurring schedule with new values
-// It supports updating cron expression, payload, headers, callback_type, call_back_url
+// It supports updating cron expression, interval or RRULE, payload, headers, callback_type, call_back_url
+// An updated callback can be canaried against the previous one on the next runs with canary
+// Deprecated: fields cannot be cleared with it, use PatchSchedule instead
 func (s *Service) UpdateRecurringSchedule(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 	vars := mux.Vars(r)
 	scheduleID := vars["scheduleId"]
 
 	// Step 1: Validate and parse schedule ID
 	uuid, err := validateScheduleID(scheduleID)
 	if err != nil {
-		glog.Errorf("UpdateRecurringSchedule: %v", err)
+		log.Errorf("UpdateRecurringSchedule: %v", err)
 		s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
 		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
 		return
@@ -147,7 +203,7 @@ func (s *Service) UpdateRecurringSchedule(w http.ResponseWriter, r *http.Request
 	var inputSchedule store.Schedule
 	b, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		glog.Errorf("UpdateRecurringSchedule: Error reading request body: %v", err)
+		log.Errorf("UpdateRecurringSchedule: Error reading request body: %v", err)
 		s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
 		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
 		return
@@ -176,37 +232,64 @@ func (s *Service) UpdateRecurringSchedule(w http.ResponseWriter, r *http.Request
 	// Step 4: Validate immutable fields
 	if err := s.validateImmutableFields(inputSchedule, *existingSchedule); err != nil {
 		s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
-		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		er.Handle(w, r, err.(er.AppError))
 		return
 	}
 
-	// Step 5: Update allowed fields
+	// Step 5: A canary needs an updated callback to compare with the current one
+	previous := *existingSchedule
+	if inputSchedule.Canary != nil {
+		if err := validateCanaryPolicy(inputSchedule); err != nil {
+			s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
+			er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+			return
+		}
+	}
+
+	// Step 6: Update allowed fields
 	if err := updateScheduleFields(existingSchedule, inputSchedule); err != nil {
-		glog.Errorf("UpdateRecurringSchedule: %v", err)
+		log.Errorf("UpdateRecurringSchedule: %v", err)
 		s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
 		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
 		return
 	}
 
-	// Step 6: Validate updated schedule
+	// Step 7: Validate updated schedule
 	if err := s.validateUpdatedSchedule(existingSchedule, app); err != nil {
-		glog.Errorf("UpdateRecurringSchedule: %v", err)
+		log.Errorf("UpdateRecurringSchedule: %v", err)
 		s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
-		er.Handle(w, r, er.NewError(er.UnprocessableEntity, err))
+		er.Handle(w, r, err.(er.AppError))
 		return
 	}
 
-	// Step 7: Persist the update
-	updatedSchedule, err := s.ScheduleDao.UpdateRecurringSchedule(*existingSchedule)
+	// Step 8: Persist the canary of the updated callback, before the update recreates the future runs
+	if inputSchedule.Canary != nil {
+		canary, err := store.NewCallbackCanary(*existingSchedule, previous.Callback, *inputSchedule.Canary, time.Now())
+		if err != nil {
+			s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
+			er.Handle(w, r, er.NewError(er.UnprocessableEntity, err))
+			return
+		}
+		if err := s.ScheduleDao.CreateCallbackCanary(canary); err != nil {
+			log.Errorf("UpdateRecurringSchedule: %v", err)
+			s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
+			er.Handle(w, r, er.NewError(er.DataPersistenceFailure, err))
+			return
+		}
+	}
+
+	// Step 9: Persist the update, keeping the definition it replaces as a version
+	updatedSchedule, err := s.updateVersionedSchedule(previous, *existingSchedule, logger.RequestID(r.Context()))
 	if err != nil {
-		glog.Errorf("UpdateRecurringSchedule: %v", err)
+		log.Errorf("UpdateRecurringSchedule: %v", err)
 		s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Fail)
 		er.Handle(w, r, er.NewError(er.DataPersistenceFailure, err))
 		return
 	}
 
-	// Step 8: Send success response
-	glog.V(constants.INFO).Infof("Recurring schedule with id %s updated", uuid.String())
+	// Step 10: Send success response
+	log.Debugf("Recurring schedule with id %s updated", uuid.String())
+	store.PublishEvent(store.ScheduleUpdated, updatedSchedule)
 	s.recordRequestStatus(constants.UpdateRecurringSchedule, constants.Success)
 	status := Status{
 		StatusCode:    constants.SuccessCode200,
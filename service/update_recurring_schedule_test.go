@@ -3,9 +3,11 @@ package service
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gocql/gocql"
 	"github.com/gorilla/mux"
@@ -64,6 +66,25 @@ func (m *MockScheduleDaoForUpdate) GetSchedule(uuid gocql.UUID) (store.Schedule,
 			},
 			Status: store.Scheduled,
 		}, nil
+	case "66666666-6666-6666-6666-666666666666":
+		// Pending one time schedule an hour from now
+		scheduleTime := time.Now().Unix() + 3600
+		return store.Schedule{
+			ScheduleId:    uuid,
+			AppId:         "testApp",
+			Payload:       `{"foo":"bar"}`,
+			ScheduleTime:  scheduleTime,
+			ScheduleGroup: 60 * (scheduleTime / 60),
+			PartitionId:   0,
+			Callback: &store.HttpCallback{
+				Type: "http",
+				Details: store.Details{
+					Url:    "http://example.com",
+					Method: "GET",
+				},
+			},
+			Status: store.Scheduled,
+		}, nil
 	default:
 		// U-01: Valid recurring schedule for happy path
 		return store.Schedule{
@@ -96,6 +117,18 @@ func (m *MockScheduleDaoForUpdate) UpdateRecurringSchedule(schedule store.Schedu
 	return schedule, nil
 }
 
+func (m *MockScheduleDaoForUpdate) UpdateVersionedRecurringSchedule(schedule store.Schedule, version store.ScheduleVersion) (store.Schedule, error) {
+	// Failure of the batch writing the version along with the update
+	if version.ScheduleId.String() == "88888888-8888-8888-8888-888888888888" {
+		return store.Schedule{}, errors.New("batch failure")
+	}
+	if version.Version != 2 {
+		return store.Schedule{}, fmt.Errorf("expected the replaced definition to be numbered 2, got %d", version.Version)
+	}
+
+	return m.UpdateRecurringSchedule(schedule)
+}
+
 // MockClusterDaoForUpdate to handle app-related test cases
 type MockClusterDaoForUpdate struct {
 	dao.DummyClusterDaoImpl
@@ -200,6 +233,14 @@ func TestService_UpdateRecurringSchedule(t *testing.T) {
 			wantStatus:  http.StatusUnprocessableEntity,
 			description: "Invalid cron expression",
 		},
+		{
+			name:        "U-10_PayloadTooLarge",
+			testID:      "U-10",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        []byte(fmt.Sprintf(`{"payload":"%s"}`, bytes.Repeat([]byte("x"), 1025))),
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			description: "Payload over the limit of the app",
+		},
 		{
 			name:        "U-09_CassandraTimeout",
 			testID:      "U-09",
@@ -224,6 +265,34 @@ func TestService_UpdateRecurringSchedule(t *testing.T) {
 			wantStatus:  http.StatusOK,
 			description: "Update callback_type and details",
 		},
+		{
+			name:        "CanaryCallback",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        []byte(`{"callback":{"type":"http","details":{"url":"http://newurl.com","method":"POST"}},"canary":{"runs":3,"percent":20}}`),
+			wantStatus:  http.StatusOK,
+			description: "Canary of the updated callback",
+		},
+		{
+			name:        "CanaryWithoutCallback",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        []byte(`{"cronExpression":"*/10 * * * *","canary":{"runs":3}}`),
+			wantStatus:  http.StatusBadRequest,
+			description: "Canary without an updated callback",
+		},
+		{
+			name:        "CanaryInvalidRuns",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        []byte(`{"callback":{"type":"http","details":{"url":"http://newurl.com","method":"POST"}},"canary":{"runs":0}}`),
+			wantStatus:  http.StatusBadRequest,
+			description: "Canary without runs",
+		},
+		{
+			name:        "CanaryUnchangedCallback",
+			scheduleID:  "55555555-5555-5555-5555-555555555555",
+			body:        []byte(`{"callback":{"type":"http","details":{"url":"http://example.com","method":"GET"}},"canary":{"runs":3}}`),
+			wantStatus:  http.StatusUnprocessableEntity,
+			description: "Canary of a callback identical to the current one",
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,190 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+// verifyUrlRequest is the body of the url verification API
+type verifyUrlRequest struct {
+	Url string `json:"url"`
+}
+
+// VerifyCallbackUrl sends a challenge to a callback url of an app and records the url as verified once it
+// echoes the challenge
+func (s *Service) VerifyCallbackUrl(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	verified, err := s.verifyCallbackUrl(appId, r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.VerifyCallbackUrl, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.VerifyCallbackUrl, appId, constants.Success)
+
+	w.WriteHeader(http.StatusCreated)
+	status := Status{StatusCode: constants.SuccessCode201, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(VerifiedUrlResponse{Status: status, Data: verified})
+}
+
+func (s *Service) verifyCallbackUrl(appId string, r *http.Request) (store.VerifiedUrl, error) {
+	var input verifyUrlRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+	if err = json.Unmarshal(body, &input); err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.UnmarshalErrorCode, err)
+	}
+
+	if _, err = s.getApp(appId); err != nil {
+		return store.VerifiedUrl{}, err
+	}
+
+	key, err := store.VerificationKey(input.Url)
+	if err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.InvalidDataCode, err)
+	}
+	if err = store.Egress().CheckUrl(r.Context(), input.Url); err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	challenge, err := store.NewChallenge()
+	if err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	client := &http.Client{
+		Transport: store.Egress().Transport(http.DefaultTransport.(*http.Transport)),
+		Timeout:   time.Duration(s.Config.UrlVerificationConfig.TimeoutMillis) * time.Millisecond,
+	}
+	if err = store.VerifyUrl(client, appId, input.Url, challenge); err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.UnprocessableEntity, fmt.Errorf("verification of %s failed: %v", input.Url, err))
+	}
+
+	verified := store.VerifiedUrl{AppId: appId, Url: key, VerifiedAt: time.Now()}
+	if err = s.ClusterDao.CreateVerifiedUrl(verified); err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	return verified, nil
+}
+
+// GetVerifiedUrls returns the verified callback urls of an app
+func (s *Service) GetVerifiedUrls(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	urls, err := s.ClusterDao.GetVerifiedUrls(appId)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetVerifiedUrls, appId, constants.Fail)
+		er.Handle(w, r, er.NewError(er.DataFetchFailure, err))
+		return
+	}
+	if urls == nil {
+		urls = []store.VerifiedUrl{}
+	}
+
+	s.recordRequestAppStatus(constants.GetVerifiedUrls, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(urls)}
+	_ = json.NewEncoder(w).Encode(VerifiedUrlsResponse{Status: status, Data: urls})
+}
+
+// DeleteVerifiedUrl revokes the verification of the callback url given by the url query parameter.
+// The existing schedules delivering to the url keep firing, only new and updated ones are rejected.
+func (s *Service) DeleteVerifiedUrl(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	verified, err := s.deleteVerifiedUrl(appId, r.URL.Query().Get("url"))
+	if err != nil {
+		s.recordRequestAppStatus(constants.DeleteVerifiedUrl, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.DeleteVerifiedUrl, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: 1}
+	_ = json.NewEncoder(w).Encode(VerifiedUrlResponse{Status: status, Data: verified})
+}
+
+func (s *Service) deleteVerifiedUrl(appId string, rawUrl string) (store.VerifiedUrl, error) {
+	key, err := store.VerificationKey(rawUrl)
+	if err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	verified, err := s.ClusterDao.GetVerifiedUrl(appId, key)
+	switch {
+	case err == gocql.ErrNotFound:
+		return store.VerifiedUrl{}, er.NewError(er.DataNotFound, fmt.Errorf("url %s of app %s is not verified", key, appId))
+	case err != nil:
+		return store.VerifiedUrl{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	if err = s.ClusterDao.DeleteVerifiedUrl(appId, key); err != nil {
+		return store.VerifiedUrl{}, er.NewError(er.DataPersistenceFailure, err)
+	}
+	return verified, nil
+}
+
+// checkCallbackUrls checks that the egress policy allows the urls a schedule of the app delivers to, and that the
+// urls are verified when the app or the configuration requires the verification
+func (s *Service) checkCallbackUrls(app store.App, callback store.Callback, statusCallback string) error {
+	urls := store.CallbackUrls(callback, statusCallback)
+	for _, rawUrl := range urls {
+		if err := store.Egress().CheckUrl(context.Background(), rawUrl); err != nil {
+			return er.NewError(er.InvalidDataCode, err)
+		}
+	}
+
+	if !app.Configuration.VerifyCallbackUrls && !s.Config.UrlVerificationConfig.Required {
+		return nil
+	}
+
+	for _, rawUrl := range urls {
+		key, err := store.VerificationKey(rawUrl)
+		if err != nil {
+			return er.NewError(er.InvalidDataCode, err)
+		}
+
+		switch _, err = s.ClusterDao.GetVerifiedUrl(app.AppId, key); {
+		case err == gocql.ErrNotFound:
+			return er.NewError(er.InvalidDataCode, errors.New(fmt.Sprintf("url %s is not verified for app %s", key, app.AppId)))
+		case err != nil:
+			return er.NewError(er.DataFetchFailure, err)
+		}
+	}
+	return nil
+}
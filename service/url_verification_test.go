@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_VerifyCallbackUrl(t *testing.T) {
+	service := setupMocks()
+	service.Config.UrlVerificationConfig.TimeoutMillis = 1000
+
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var challenge store.UrlVerification
+		_ = json.NewDecoder(r.Body).Decode(&challenge)
+		if r.URL.Path == "/echo" {
+			_, _ = w.Write([]byte(challenge.Challenge))
+		}
+	}))
+	defer endpoint.Close()
+
+	for _, test := range []struct {
+		appId  string
+		body   string
+		status int
+	}{
+		{"testApp", `{"url":"` + endpoint.URL + `/echo?id=1"}`, http.StatusCreated},
+		{"testApp", `{"url":"` + endpoint.URL + `/silent"}`, http.StatusUnprocessableEntity},
+		{"testApp", `{"url":"localhost/echo"}`, http.StatusBadRequest},
+		{"testApp", `{"url":`, http.StatusBadRequest},
+		{"testGetAppErrorNotFound", `{"url":"` + endpoint.URL + `/echo"}`, http.StatusBadRequest},
+		{"testCreateVerifiedUrlError", `{"url":"` + endpoint.URL + `/echo"}`, http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("POST", "/goscheduler/apps/"+test.appId+"/verified-urls", bytes.NewBufferString(test.body))
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.VerifyCallbackUrl).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d with body %s", test.appId, test.body, test.status, rr.Code, rr.Body.String())
+		}
+		if rr.Code == http.StatusCreated && !bytes.Contains(rr.Body.Bytes(), []byte(`"url":"`+endpoint.URL+`/echo"`)) {
+			t.Errorf("Expected the url to be verified without its query, got %s", rr.Body.String())
+		}
+	}
+}
+
+func TestService_DeleteVerifiedUrl(t *testing.T) {
+	service := setupMocks()
+
+	for _, test := range []struct {
+		appId  string
+		url    string
+		status int
+	}{
+		{"testApp", "http://localhost:8080/verified", http.StatusOK},
+		{"testApp", "http://localhost:8080/unknown", http.StatusNotFound},
+		{"testApp", "", http.StatusBadRequest},
+		{"testGetVerifiedUrlError", "http://localhost:8080/verified", http.StatusInternalServerError},
+		{"testDeleteVerifiedUrlError", "http://localhost:8080/verified", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("DELETE", "/goscheduler/apps/"+test.appId+"/verified-urls?url="+test.url, nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.DeleteVerifiedUrl).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d", test.appId, test.url, test.status, rr.Code)
+		}
+	}
+}
+
+func TestService_CheckCallbackUrls(t *testing.T) {
+	service := setupMocks()
+	verified := &store.HttpCallback{Details: store.Details{Url: "http://localhost:8080/verified?id=1"}}
+	unverified := &store.HttpCallback{Details: store.Details{Url: "http://localhost:8080/unverified"}}
+
+	for _, test := range []struct {
+		app            store.App
+		required       bool
+		callback       store.Callback
+		statusCallback string
+		valid          bool
+	}{
+		{store.App{AppId: "testApp"}, false, unverified, "", true},
+		{store.App{AppId: "testApp"}, true, unverified, "", false},
+		{store.App{AppId: "testApp", Configuration: store.Configuration{VerifyCallbackUrls: true}}, false, verified, "", true},
+		{store.App{AppId: "testApp", Configuration: store.Configuration{VerifyCallbackUrls: true}}, false, unverified, "", false},
+		{store.App{AppId: "testApp", Configuration: store.Configuration{VerifyCallbackUrls: true}}, false, verified, "http://localhost:8080/status", false},
+		{store.App{AppId: "testGetVerifiedUrlError"}, true, verified, "", false},
+	} {
+		service.Config.UrlVerificationConfig.Required = test.required
+		if err := service.checkCallbackUrls(test.app, test.callback, test.statusCallback); (err == nil) != test.valid {
+			t.Errorf("%+v required %v: expected valid %v, got %v", test.app, test.required, test.valid, err)
+		}
+	}
+}
+
+func TestService_CheckCallbackUrlsEgress(t *testing.T) {
+	service := setupMocks()
+	policy, _ := store.NewEgressPolicy(conf.EgressConfig{Enabled: true, DeniedCIDRs: []string{"169.254.0.0/16"}})
+	store.SetEgressPolicy(policy)
+	defer store.SetEgressPolicy(nil)
+
+	allowed := &store.HttpCallback{Details: store.Details{Url: "http://127.0.0.1:8080/callback"}}
+	denied := &store.HttpCallback{Details: store.Details{Url: "http://169.254.169.254/latest/meta-data"}}
+
+	if err := service.checkCallbackUrls(store.App{AppId: "testApp"}, allowed, ""); err != nil {
+		t.Errorf("Expected the callback to be allowed, got %v", err)
+	}
+	if err := service.checkCallbackUrls(store.App{AppId: "testApp"}, denied, ""); err == nil {
+		t.Errorf("Expected the callback to the metadata address to be denied")
+	}
+	if err := service.checkCallbackUrls(store.App{AppId: "testApp"}, allowed, "http://169.254.169.254/status"); err == nil {
+		t.Errorf("Expected the status callback to the metadata address to be denied")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/store"
+)
+
+const (
+	usageDateLayout  = "2006-01-02"
+	usageMonthLayout = "2006-01"
+	dailyUsage       = "daily"
+	monthlyUsage     = "monthly"
+	// maxUsageDays caps the days covered by a usage report
+	maxUsageDays = 731
+)
+
+// GetAppUsage returns the usage of an app per day or per month between two days, for chargeback.
+// The usage of a deactivated app is still reported.
+func (s *Service) GetAppUsage(w http.ResponseWriter, r *http.Request) {
+	appId := mux.Vars(r)["appId"]
+
+	data, err := s.fetchAppUsage(appId, r)
+	if err != nil {
+		s.recordRequestAppStatus(constants.GetAppUsage, appId, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.GetAppUsage, appId, constants.Success)
+
+	status := Status{StatusCode: constants.SuccessCode200, StatusMessage: constants.Success, StatusType: constants.Success, TotalCount: len(data.Usage)}
+	_ = json.NewEncoder(w).Encode(AppUsageResponse{Status: status, Data: data})
+}
+
+func (s *Service) fetchAppUsage(appId string, r *http.Request) (AppUsageData, error) {
+	granularity, from, to, err := parseUsageRange(r, time.Now())
+	if err != nil {
+		return AppUsageData{}, er.NewError(er.InvalidDataCode, err)
+	}
+
+	switch app, err := s.ClusterDao.GetApp(appId); {
+	case err == gocql.ErrNotFound || (err == nil && len(app.AppId) == 0):
+		return AppUsageData{}, er.NewError(er.InvalidAppId, errors.New(fmt.Sprintf("app Id %s is not registered", appId)))
+	case err != nil:
+		return AppUsageData{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	days, err := s.ClusterDao.GetUsage(appId, from, to)
+	if err != nil {
+		return AppUsageData{}, er.NewError(er.DataFetchFailure, err)
+	}
+
+	return rollUpUsage(appId, granularity, from, to, days), nil
+}
+
+// parseUsageRange parses the granularity and the days of the report.
+// The report ends today by default and covers 30 days when daily, the current and the 11 previous months when monthly.
+func parseUsageRange(r *http.Request, now time.Time) (string, time.Time, time.Time, error) {
+	query := r.URL.Query()
+
+	granularity := query.Get("granularity")
+	switch granularity {
+	case "":
+		granularity = dailyUsage
+	case dailyUsage, monthlyUsage:
+	default:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("invalid granularity %s, must be one of daily or monthly", granularity)
+	}
+
+	to := store.UsageDay(now)
+	if value := query.Get("to"); value != "" {
+		day, err := time.Parse(usageDateLayout, value)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid to %s, must be a date as YYYY-MM-DD", value)
+		}
+		to = day
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if granularity == monthlyUsage {
+		from = time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if value := query.Get("from"); value != "" {
+		day, err := time.Parse(usageDateLayout, value)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("invalid from %s, must be a date as YYYY-MM-DD", value)
+		}
+		from = day
+	}
+
+	switch {
+	case from.After(to):
+		return "", time.Time{}, time.Time{}, fmt.Errorf("from %s is after to %s", from.Format(usageDateLayout), to.Format(usageDateLayout))
+	case to.Sub(from) >= maxUsageDays*24*time.Hour:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("usage can be reported for at most %d days", maxUsageDays)
+	}
+	return granularity, from, to, nil
+}
+
+// rollUpUsage sums the daily usage into the periods of the granularity, oldest first, and into the total.
+// Periods without any usage are left out.
+func rollUpUsage(appId string, granularity string, from time.Time, to time.Time, days []store.Usage) AppUsageData {
+	data := AppUsageData{
+		AppId:       appId,
+		Granularity: granularity,
+		From:        from.Format(usageDateLayout),
+		To:          to.Format(usageDateLayout),
+		Usage:       []UsagePeriod{},
+	}
+
+	for _, day := range days {
+		data.Total.Add(day)
+
+		period := day.Day.Format(usageDateLayout)
+		if granularity == monthlyUsage {
+			period = day.Day.Format(usageMonthLayout)
+		}
+
+		if n := len(data.Usage); n > 0 && data.Usage[n-1].Period == period {
+			data.Usage[n-1].Add(day)
+			continue
+		}
+		usage := UsagePeriod{Period: period}
+		usage.Add(day)
+		data.Usage = append(data.Usage, usage)
+	}
+	return data
+}
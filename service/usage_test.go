@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/cluster"
+	"github.com/myntra/goscheduler/dao"
+	"github.com/myntra/goscheduler/store"
+)
+
+func TestService_GetAppUsage(t *testing.T) {
+	service := &Service{
+		Supervisor:  new(cluster.DummySupervisor),
+		ClusterDao:  new(dao.DummyClusterDaoImpl),
+		ScheduleDao: new(dao.DummyScheduleDaoImpl),
+	}
+
+	for _, test := range []struct {
+		appId  string
+		query  string
+		status int
+	}{
+		{"testApp", "", http.StatusOK},
+		{"testDeactivated", "?granularity=monthly", http.StatusOK},
+		{"testApp", "?from=2023-01-01&to=2023-01-31", http.StatusOK},
+		{"testApp", "?granularity=weekly", http.StatusBadRequest},
+		{"testApp", "?from=2023-02-01&to=2023-01-31", http.StatusBadRequest},
+		{"testApp", "?from=2020-01-01&to=2023-01-31", http.StatusBadRequest},
+		{"testApp", "?to=31-01-2023", http.StatusBadRequest},
+		{"testGetAppErrorNotFound", "", http.StatusBadRequest},
+		{"testGetAppError", "", http.StatusInternalServerError},
+		{"testGetUsageError", "", http.StatusInternalServerError},
+	} {
+		req, _ := http.NewRequest("GET", "/goscheduler/apps/"+test.appId+"/usage"+test.query, nil)
+		req = mux.SetURLVars(req, map[string]string{"appId": test.appId})
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetAppUsage).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s%s: expected status %d, got %d with body %s", test.appId, test.query, test.status, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestParseUsageRange(t *testing.T) {
+	now := time.Date(2023, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	req, _ := http.NewRequest("GET", "/usage", nil)
+	granularity, from, to, err := parseUsageRange(req, now)
+	if err != nil || granularity != dailyUsage || from.Format(usageDateLayout) != "2023-02-14" || to.Format(usageDateLayout) != "2023-03-15" {
+		t.Errorf("Unexpected daily default %s %s %s %v", granularity, from, to, err)
+	}
+
+	req, _ = http.NewRequest("GET", "/usage?granularity=monthly", nil)
+	granularity, from, to, err = parseUsageRange(req, now)
+	if err != nil || granularity != monthlyUsage || from.Format(usageDateLayout) != "2022-04-01" || to.Format(usageDateLayout) != "2023-03-15" {
+		t.Errorf("Unexpected monthly default %s %s %s %v", granularity, from, to, err)
+	}
+}
+
+func TestRollUpUsage(t *testing.T) {
+	day := func(value string) time.Time {
+		parsed, _ := time.Parse(usageDateLayout, value)
+		return parsed
+	}
+	days := []store.Usage{
+		{Day: day("2023-01-30"), SchedulesCreated: 1, CallbacksFired: 2},
+		{Day: day("2023-01-31"), SchedulesCreated: 3, BytesDelivered: 100},
+		{Day: day("2023-02-01"), CallbacksFired: 4, Retries: 1},
+	}
+
+	monthly := rollUpUsage("app", monthlyUsage, day("2023-01-01"), day("2023-02-28"), days)
+	if len(monthly.Usage) != 2 || monthly.Usage[0] != (UsagePeriod{Period: "2023-01", SchedulesCreated: 4, CallbacksFired: 2, BytesDelivered: 100}) ||
+		monthly.Usage[1] != (UsagePeriod{Period: "2023-02", CallbacksFired: 4, Retries: 1}) {
+		t.Errorf("Unexpected monthly usage %+v", monthly.Usage)
+	}
+	if monthly.Total != (UsagePeriod{SchedulesCreated: 4, CallbacksFired: 6, BytesDelivered: 100, Retries: 1}) {
+		t.Errorf("Unexpected total %+v", monthly.Total)
+	}
+
+	daily := rollUpUsage("app", dailyUsage, day("2023-01-01"), day("2023-02-28"), days)
+	if len(daily.Usage) != 3 || daily.Usage[2].Period != "2023-02-01" || daily.Total != monthly.Total {
+		t.Errorf("Unexpected daily usage %+v", daily)
+	}
+}
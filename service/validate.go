@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	sch "github.com/myntra/goscheduler/store"
+)
+
+const (
+	defaultPreviewCount = 5
+	maxPreviewCount     = 100
+)
+
+// Validate validates a schedule without creating it.
+// For recurring schedules the upcoming run times are returned as a preview, the number of runs can be set
+// with the count query param.
+func (s *Service) Validate(w http.ResponseWriter, r *http.Request) {
+	var input sch.Schedule
+
+	count, err := parsePreviewCount(r)
+	if err != nil {
+		s.recordRequestStatus(constants.ValidateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.InvalidDataCode, err))
+		return
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.recordRequestStatus(constants.ValidateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	if err = json.Unmarshal(b, &input); err != nil {
+		s.recordRequestStatus(constants.ValidateSchedule, constants.Fail)
+		er.Handle(w, r, er.NewError(er.UnmarshalErrorCode, err))
+		return
+	}
+
+	nextRuns, err := s.ValidateSchedule(input, count)
+	if err != nil {
+		s.recordRequestStatus(constants.ValidateSchedule, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestAppStatus(constants.ValidateSchedule, getAppId(input), constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(nextRuns),
+	}
+	_ = json.NewEncoder(w).Encode(
+		ValidateScheduleResponse{
+			Status: status,
+			Data: ValidateScheduleData{
+				Valid:    true,
+				NextRuns: nextRuns,
+			},
+		})
+}
+
+// ValidateSchedule validates the schedule against the app and returns upto count upcoming run times
+// in epoch seconds if the schedule is recurring.
+func (s *Service) ValidateSchedule(input sch.Schedule, count int) ([]int64, error) {
+	app, err := s.getApp(input.AppId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := input.ValidatePayloadSize(app, s.Config.AppLevelConfiguration); err != nil {
+		return nil, er.NewError(er.PayloadTooLarge, err)
+	}
+
+	if details := input.ValidateFields(app, s.Config.AppLevelConfiguration); len(details) > 0 {
+		return nil, er.NewValidationError(er.InvalidDataCode, details)
+	}
+
+	if !input.IsRecurring() {
+		return []int64{input.ScheduleTime}, nil
+	}
+
+	input.SetDefaultAnchor()
+	recurrence, errs := input.GetRecurrence()
+	if len(errs) > 0 {
+		return nil, er.NewError(er.InvalidDataCode, errors.New(strings.Join(errs, ",")))
+	}
+
+	nextRuns := []int64{}
+	for _, t := range sch.Preview(recurrence, time.Now(), count) {
+		nextRuns = append(nextRuns, t.Unix())
+	}
+
+	return nextRuns, nil
+}
+
+func parsePreviewCount(r *http.Request) (int, error) {
+	countParam := r.URL.Query().Get("count")
+	if len(countParam) == 0 {
+		return defaultPreviewCount, nil
+	}
+
+	count, err := strconv.Atoi(countParam)
+	if err != nil || count <= 0 || count > maxPreviewCount {
+		return 0, errors.New(fmt.Sprintf("count should be an integer between 1 and %d", maxPreviewCount))
+	}
+
+	return count, nil
+}
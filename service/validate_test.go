@@ -0,0 +1,129 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestService_Validate(t *testing.T) {
+	service := setupMocks()
+
+	callback := `"callback": {"type": "http", "details": {"url": "https://dummy.url", "method": "POST", "headers": {"header": "value"}}}`
+
+	for _, test := range []struct {
+		name     string
+		query    string
+		body     []byte
+		Status   int
+		NextRuns int
+	}{
+		{
+			"one time schedule",
+			"",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "scheduleTime":%d, "payload":"{}"}`, callback, time.Now().Add(time.Hour).Unix())),
+			http.StatusOK,
+			1,
+		},
+		{
+			"cron schedule",
+			"?count=3",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "cronExpression": "*/5 * * * *", "payload":"{}"}`, callback)),
+			http.StatusOK,
+			3,
+		},
+		{
+			"interval schedule",
+			"",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "every": "90m", "payload":"{}"}`, callback)),
+			http.StatusOK,
+			defaultPreviewCount,
+		},
+		{
+			"rrule schedule",
+			"?count=2",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "rrule": "FREQ=MONTHLY;BYDAY=2TU,4TU", "payload":"{}"}`, callback)),
+			http.StatusOK,
+			2,
+		},
+		{
+			"invalid rrule",
+			"",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "rrule": "FREQ=SECONDLY", "payload":"{}"}`, callback)),
+			http.StatusBadRequest,
+			0,
+		},
+		{
+			"multiple recurrences",
+			"",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "rrule": "FREQ=DAILY", "every": "90m", "payload":"{}"}`, callback)),
+			http.StatusBadRequest,
+			0,
+		},
+		{
+			"invalid count",
+			"?count=1000",
+			[]byte(fmt.Sprintf(`{"appId": "test", %s, "every": "90m", "payload":"{}"}`, callback)),
+			http.StatusBadRequest,
+			0,
+		},
+		{
+			"unregistered app",
+			"",
+			[]byte(fmt.Sprintf(`{"appId": "testAppNotFound", %s, "every": "90m", "payload":"{}"}`, callback)),
+			http.StatusBadRequest,
+			0,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/goscheduler/schedules/validate"+test.query, bytes.NewBuffer(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(service.Validate)
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != test.Status {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, test.Status)
+			}
+
+			if test.Status != http.StatusOK {
+				return
+			}
+
+			var response ValidateScheduleResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(response.Data.NextRuns) != test.NextRuns {
+				t.Errorf("expected %d next runs, got %d", test.NextRuns, len(response.Data.NextRuns))
+			}
+		})
+	}
+}
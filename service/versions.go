@@ -0,0 +1,184 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gorilla/mux"
+	"github.com/myntra/goscheduler/constants"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/store"
+)
+
+// updateVersionedSchedule persists the update of a recurring schedule together with the version of the definition
+// it replaces, numbered after the latest version of the schedule
+func (s *Service) updateVersionedSchedule(previous, schedule store.Schedule, requestId string) (store.Schedule, error) {
+	versions, err := s.ScheduleDao.GetScheduleVersions(previous.ScheduleId)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	previous.RequestId = requestId
+	version, err := store.NewScheduleVersion(previous, store.NextVersion(versions), time.Now())
+	if err != nil {
+		return store.Schedule{}, err
+	}
+	return s.ScheduleDao.UpdateVersionedRecurringSchedule(schedule, version)
+}
+
+// GetScheduleVersions returns the versions of the definition of a recurring schedule, the current one last
+func (s *Service) GetScheduleVersions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleId := vars["scheduleId"]
+
+	versions, err := s.FetchScheduleVersions(scheduleId)
+	if err != nil {
+		s.recordRequestStatus(constants.GetScheduleVersions, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	s.recordRequestStatus(constants.GetScheduleVersions, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: constants.Success,
+		StatusType:    constants.Success,
+		TotalCount:    len(versions),
+	}
+	_ = writeListing(w, r,
+		GetScheduleVersionsResponse{
+			Status: status,
+			Data: GetScheduleVersionsData{
+				Versions: versions,
+			},
+		})
+}
+
+func (s *Service) FetchScheduleVersions(uuid string) ([]store.ScheduleVersion, error) {
+	scheduleId, err := gocql.ParseUUID(uuid)
+	if err != nil {
+		return nil, er.NewError(er.InvalidDataCode, err)
+	}
+
+	schedule, err := s.ScheduleDao.GetSchedule(scheduleId)
+	switch {
+	case err == gocql.ErrNotFound:
+		return nil, er.NewError(er.DataNotFound, fmt.Errorf("schedule with id: %s not found", uuid))
+	case err != nil:
+		return nil, er.NewError(er.DataFetchFailure, err)
+	case !schedule.IsRecurring():
+		return nil, er.NewError(er.UnprocessableEntity, fmt.Errorf("schedule with id: %s is not a recurring schedule", uuid))
+	}
+
+	versions, err := s.ScheduleDao.GetScheduleVersions(scheduleId)
+	if err != nil {
+		return nil, er.NewError(er.DataFetchFailure, err)
+	}
+
+	current, err := store.CurrentVersion(schedule, versions)
+	if err != nil {
+		return nil, er.NewError(er.DataFetchFailure, err)
+	}
+
+	return append(versions, current), nil
+}
+
+// RollbackSchedule replaces the definition of a recurring schedule with one of its versions.
+// The definition being replaced is kept as a version, so a rollback can be rolled back too.
+func (s *Service) RollbackSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	schedule, version, err := s.rollbackSchedule(r, vars["scheduleId"], vars["version"])
+	if err != nil {
+		logger.FromContext(r.Context()).Errorf("RollbackSchedule: %v", err)
+		s.recordRequestStatus(constants.RollbackSchedule, constants.Fail)
+		er.Handle(w, r, err.(er.AppError))
+		return
+	}
+
+	store.PublishEvent(store.ScheduleUpdated, schedule)
+	s.recordRequestAppStatus(constants.RollbackSchedule, schedule.AppId, constants.Success)
+
+	status := Status{
+		StatusCode:    constants.SuccessCode200,
+		StatusMessage: fmt.Sprintf("Schedule rolled back to version %d", version),
+		StatusType:    constants.Success,
+		TotalCount:    1,
+	}
+	_ = json.NewEncoder(w).Encode(
+		UpdatedScheduleResponse{
+			Status: status,
+			Data:   UpdatedScheduleData{Schedule: schedule},
+		})
+}
+
+func (s *Service) rollbackSchedule(r *http.Request, uuid string, versionParam string) (store.Schedule, int, error) {
+	scheduleId, err := validateScheduleID(uuid)
+	if err != nil {
+		return store.Schedule{}, 0, er.NewError(er.InvalidDataCode, err)
+	}
+
+	version, err := strconv.Atoi(versionParam)
+	if err != nil || version <= 0 {
+		return store.Schedule{}, 0, er.NewError(er.InvalidDataCode, fmt.Errorf("invalid version %s", versionParam))
+	}
+
+	existing, app, err := s.validateExistingScheduleAndExtractApp(scheduleId)
+	if err != nil {
+		return store.Schedule{}, 0, err
+	}
+
+	versions, err := s.ScheduleDao.GetScheduleVersions(scheduleId)
+	if err != nil {
+		return store.Schedule{}, 0, er.NewError(er.DataFetchFailure, err)
+	}
+	if version == store.NextVersion(versions) {
+		return store.Schedule{}, 0, er.NewError(er.UnprocessableEntity, fmt.Errorf("version %d is the current definition of schedule %s", version, uuid))
+	}
+	target, ok := store.FindVersion(versions, version)
+	if !ok {
+		return store.Schedule{}, 0, er.NewError(er.DataNotFound, fmt.Errorf("version %d of schedule %s not found", version, uuid))
+	}
+
+	schedule := *existing
+	if err = target.Definition.Apply(&schedule); err != nil {
+		return store.Schedule{}, 0, er.NewError(er.UnprocessableEntity, fmt.Errorf("version %d of schedule %s cannot be restored: %w", version, uuid, err))
+	}
+	schedule.RequestId = logger.RequestID(r.Context())
+
+	if err = s.validateUpdatedSchedule(&schedule, app); err != nil {
+		return store.Schedule{}, 0, err
+	}
+
+	updated, err := s.updateVersionedSchedule(*existing, schedule, schedule.RequestId)
+	if err != nil {
+		return store.Schedule{}, 0, er.NewError(er.DataPersistenceFailure, err)
+	}
+
+	return updated, version, nil
+}
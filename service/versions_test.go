@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestService_GetScheduleVersions(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+
+	for _, test := range []struct {
+		uuid   string
+		status int
+	}{
+		{"55555555-5555-5555-5555-555555555555", http.StatusOK},
+		{"invalid-uuid", http.StatusBadRequest},
+		{"00000000-0000-0000-0000-000000000000", http.StatusNotFound},
+		{"22222222-2222-2222-2222-222222222222", http.StatusInternalServerError},
+		{"11111111-1111-1111-1111-111111111111", http.StatusUnprocessableEntity},
+		{"77777777-7777-7777-7777-777777777777", http.StatusInternalServerError},
+	} {
+		req := httptest.NewRequest("GET", "/goscheduler/schedules/{scheduleId}/versions", nil)
+		req = mux.SetURLVars(req, map[string]string{"scheduleId": test.uuid})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.GetScheduleVersions).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: got status %d, want %d, body %s", test.uuid, rr.Code, test.status, rr.Body.String())
+			continue
+		}
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		var response GetScheduleVersionsResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		versions := response.Data.Versions
+		if len(versions) != 2 || versions[0].Current || !versions[1].Current || versions[1].Version != 2 {
+			t.Errorf("expected a replaced version followed by the current one, got %+v", versions)
+		}
+	}
+}
+
+func TestService_RollbackSchedule(t *testing.T) {
+	service := setupMocksForUpdateRecurringSchedule()
+
+	for _, test := range []struct {
+		name    string
+		uuid    string
+		version string
+		status  int
+	}{
+		{"rollback", "55555555-5555-5555-5555-555555555555", "1", http.StatusOK},
+		{"current version", "55555555-5555-5555-5555-555555555555", "2", http.StatusUnprocessableEntity},
+		{"unknown version", "55555555-5555-5555-5555-555555555555", "3", http.StatusNotFound},
+		{"invalid version", "55555555-5555-5555-5555-555555555555", "latest", http.StatusBadRequest},
+		{"invalid uuid", "invalid-uuid", "1", http.StatusBadRequest},
+		{"not recurring", "11111111-1111-1111-1111-111111111111", "1", http.StatusUnprocessableEntity},
+		{"versions fetch failure", "77777777-7777-7777-7777-777777777777", "1", http.StatusInternalServerError},
+		{"version persistence failure", "88888888-8888-8888-8888-888888888888", "1", http.StatusInternalServerError},
+	} {
+		updateRecurringScheduleCallCount = 0
+
+		req := httptest.NewRequest("POST", "/goscheduler/schedules/{scheduleId}/rollback/{version}", nil)
+		req = mux.SetURLVars(req, map[string]string{"scheduleId": test.uuid, "version": test.version})
+
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(service.RollbackSchedule).ServeHTTP(rr, req)
+
+		if rr.Code != test.status {
+			t.Errorf("%s: got status %d, want %d, body %s", test.name, rr.Code, test.status, rr.Body.String())
+			continue
+		}
+		if test.status != http.StatusOK {
+			if updateRecurringScheduleCallCount != 0 {
+				t.Errorf("%s: expected the schedule not to be updated", test.name)
+			}
+			continue
+		}
+
+		if updateRecurringScheduleCallCount != 1 {
+			t.Fatalf("%s: expected the schedule to be updated once, got %d", test.name, updateRecurringScheduleCallCount)
+		}
+		restored := lastUpdateRecurringScheduleInput
+		if restored.CronExpression != "*/5 * * * *" || restored.Payload != `{"foo":"baz"}` || restored.AppId != "testApp" {
+			t.Errorf("%s: expected version 1 to be restored, got %+v", test.name, restored)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sla
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+const (
+	Noop      = "noop"
+	Webhook   = "webhook"
+	Slack     = "slack"
+	PagerDuty = "pagerduty"
+
+	pagerDutyEventsUrl = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// Alerter delivers the SLA alerts
+type Alerter interface {
+	Alert(alert Alert) error
+}
+
+// Factory creates an alerter from the SLA configuration
+type Factory func(config conf.SLAConfig) (Alerter, error)
+
+// Registry holds the alerter factories by alert type.
+// Custom alerters can be added with Register before the scheduler is created.
+var Registry = map[string]Factory{
+	Noop:      func(config conf.SLAConfig) (Alerter, error) { return NoopAlerter{}, nil },
+	Webhook:   func(config conf.SLAConfig) (Alerter, error) { return newPoster(config, webhookPayload) },
+	Slack:     func(config conf.SLAConfig) (Alerter, error) { return newPoster(config, slackPayload) },
+	PagerDuty: func(config conf.SLAConfig) (Alerter, error) { return newPagerDutyAlerter(config) },
+}
+
+// Register adds or replaces the factory of an alert type
+func Register(alertType string, factory Factory) {
+	Registry[alertType] = factory
+}
+
+// NewAlerter creates the alerter configured in the supplied configuration.
+// An empty type creates a noop alerter.
+func NewAlerter(config conf.SLAConfig) (Alerter, error) {
+	alertType := config.AlertType
+	if alertType == "" {
+		alertType = Noop
+	}
+
+	factory, ok := Registry[alertType]
+	if !ok {
+		return nil, fmt.Errorf("unknown sla alert type: %s", alertType)
+	}
+	return factory(config)
+}
+
+// NoopAlerter discards all the alerts
+type NoopAlerter struct{}
+
+func (NoopAlerter) Alert(alert Alert) error {
+	return nil
+}
+
+// Summary describes the alert in a line
+func (a Alert) Summary() string {
+	switch {
+	case a.Kind == CreationSpike && a.State == Resolved:
+		return fmt.Sprintf("goscheduler schedule creations of app %s are back to %.0f a minute", a.AppId, a.Value)
+	case a.Kind == CreationSpike:
+		return fmt.Sprintf("goscheduler schedule creations of app %s spiked to %.0f a minute, against a baseline of %.1f",
+			a.AppId, a.Value, a.Baseline)
+	case a.Kind == FailureRateJump && a.State == Resolved:
+		return fmt.Sprintf("goscheduler callback failure rate of app %s is back to %.1f%%", a.AppId, 100*a.Value)
+	case a.Kind == FailureRateJump:
+		return fmt.Sprintf("goscheduler callback failure rate of app %s jumped to %.1f%%, against a baseline of %.1f%%",
+			a.AppId, 100*a.Value, 100*a.Baseline)
+	case a.Kind == Overrun && a.State == Resolved:
+		return fmt.Sprintf("goscheduler callbacks of app %s are back within their deadlines", a.AppId)
+	case a.Kind == Overrun:
+		return fmt.Sprintf("goscheduler callbacks of app %s overran their deadline %.0f times in a minute, schedules %v",
+			a.AppId, a.Value, a.ScheduleIds)
+	case a.State == Resolved:
+		return fmt.Sprintf("goscheduler firing lag of app %s is back within the SLA of %dms after %d minutes",
+			a.AppId, a.ThresholdMillis, a.ConsecutiveMinutes)
+	default:
+		return fmt.Sprintf("goscheduler firing lag of app %s is %dms, above the SLA of %dms for %d consecutive minutes, partitions %v",
+			a.AppId, a.LagMillis, a.ThresholdMillis, a.ConsecutiveMinutes, a.Partitions)
+	}
+}
+
+// poster posts the alerts as json to a url
+type poster struct {
+	url     string
+	payload func(Alert) interface{}
+	client  *http.Client
+}
+
+func newPoster(config conf.SLAConfig, payload func(Alert) interface{}) (*poster, error) {
+	if config.AlertUrl == "" {
+		return nil, errors.New("sla alert url is required")
+	}
+	if _, err := url.ParseRequestURI(config.AlertUrl); err != nil {
+		return nil, fmt.Errorf("invalid sla alert url %s: %w", config.AlertUrl, err)
+	}
+
+	return &poster{
+		url:     config.AlertUrl,
+		payload: payload,
+		client:  &http.Client{Timeout: config.TimeoutMillis * time.Millisecond},
+	}, nil
+}
+
+func (p *poster) Alert(alert Alert) error {
+	body, err := json.Marshal(p.payload(alert))
+	if err != nil {
+		return err
+	}
+
+	response, err := p.client.Post(p.url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("sla alert responded with %s", response.Status)
+	}
+	return nil
+}
+
+func webhookPayload(alert Alert) interface{} {
+	return alert
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func slackPayload(alert Alert) interface{} {
+	return slackMessage{Text: alert.Summary()}
+}
+
+// pagerDutyEvent is an event of the PagerDuty Events API v2.
+// The alerts of a kind of an app share a dedup key so that the resolution closes the incident of the trigger.
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string `json:"summary"`
+	Source        string `json:"source"`
+	Severity      string `json:"severity"`
+	Component     string `json:"component"`
+	CustomDetails Alert  `json:"custom_details"`
+}
+
+func newPagerDutyAlerter(config conf.SLAConfig) (*poster, error) {
+	if config.RoutingKey == "" {
+		return nil, errors.New("pagerduty sla alerts require a routing key")
+	}
+	if config.AlertUrl == "" {
+		config.AlertUrl = pagerDutyEventsUrl
+	}
+
+	return newPoster(config, func(alert Alert) interface{} {
+		event := pagerDutyEvent{
+			RoutingKey:  config.RoutingKey,
+			EventAction: "trigger",
+			DedupKey:    "goscheduler-" + alert.Kind + "-" + alert.AppId,
+		}
+		if alert.State == Resolved {
+			event.EventAction = "resolve"
+			return event
+		}
+
+		source := alert.Node
+		if source == "" {
+			source = "goscheduler"
+		}
+		severity := "critical"
+		if alert.Kind != LagBreach {
+			severity = "warning"
+		}
+		event.Payload = &pagerDutyPayload{
+			Summary:       alert.Summary(),
+			Source:        source,
+			Severity:      severity,
+			Component:     alert.AppId,
+			CustomDetails: alert,
+		}
+		return event
+	})
+}
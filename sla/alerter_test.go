@@ -0,0 +1,64 @@
+package sla
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestNewAlerter(t *testing.T) {
+	alerter, err := NewAlerter(conf.SLAConfig{})
+	if err != nil {
+		t.Fatalf("Expected noop alerter, got error %v", err)
+	}
+	if _, ok := alerter.(NoopAlerter); !ok {
+		t.Errorf("Expected NoopAlerter, got %T", alerter)
+	}
+
+	for _, config := range []conf.SLAConfig{
+		{AlertType: "unknown"},
+		{AlertType: Webhook},
+		{AlertType: Slack, AlertUrl: "not a url"},
+		{AlertType: PagerDuty},
+	} {
+		if _, err = NewAlerter(config); err == nil {
+			t.Errorf("Expected error for config %+v", config)
+		}
+	}
+}
+
+func TestAlerterPayloads(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = map[string]interface{}{}
+		_ = json.Unmarshal(data, &body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	alert := Alert{State: Triggered, Kind: LagBreach, AppId: "app", LagMillis: 9000, ThresholdMillis: 5000, ConsecutiveMinutes: 3, Partitions: []int{1}}
+
+	webhook, _ := NewAlerter(conf.SLAConfig{AlertType: Webhook, AlertUrl: server.URL})
+	if err := webhook.Alert(alert); err != nil || body["appId"] != "app" || body["state"] != Triggered {
+		t.Errorf("Unexpected webhook body %v and error %v", body, err)
+	}
+
+	slack, _ := NewAlerter(conf.SLAConfig{AlertType: Slack, AlertUrl: server.URL})
+	if err := slack.Alert(alert); err != nil || body["text"] != alert.Summary() {
+		t.Errorf("Unexpected slack body %v and error %v", body, err)
+	}
+
+	pagerDuty, _ := NewAlerter(conf.SLAConfig{AlertType: PagerDuty, AlertUrl: server.URL, RoutingKey: "key"})
+	if err := pagerDuty.Alert(alert); err != nil || body["event_action"] != "trigger" || body["dedup_key"] != "goscheduler-lag_breach-app" || body["payload"] == nil {
+		t.Errorf("Unexpected pagerduty trigger %v and error %v", body, err)
+	}
+	alert.State = Resolved
+	if err := pagerDuty.Alert(alert); err != nil || body["event_action"] != "resolve" || body["routing_key"] != "key" || body["payload"] != nil {
+		t.Errorf("Unexpected pagerduty resolve %v and error %v", body, err)
+	}
+}
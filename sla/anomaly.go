@@ -0,0 +1,241 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sla
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// CreationSpike is raised when an app creates far more schedules in a minute than its baseline
+	CreationSpike = "creation_spike"
+	// FailureRateJump is raised when the share of failed callbacks of an app jumps above its baseline
+	FailureRateJump = "failure_rate_jump"
+
+	// minFailureRateDeviation keeps an app which never fails from alerting on its first few failures
+	minFailureRateDeviation = 0.02
+	// idleBaseline is the mean creation rate below which the baseline of an app is dropped
+	idleBaseline = 0.01
+)
+
+// AnomalyOptions tune the anomaly detector
+type AnomalyOptions struct {
+	// Sensitivity is the number of standard deviations above the baseline at which a minute is anomalous
+	Sensitivity float64
+	// BaselineMinutes is the span of the exponentially weighted baseline
+	BaselineMinutes int
+	// WarmupMinutes is the number of minutes observed before the baseline of an app is trusted
+	WarmupMinutes int
+	// MinCreations is the number of creations in a minute below which a spike is ignored
+	MinCreations int
+	// MinCallbacks is the number of callbacks in a minute below which the failure rate is not evaluated
+	MinCallbacks int
+}
+
+// baseline is the exponentially weighted mean and variance of a value observed once a minute
+type baseline struct {
+	mean     float64
+	variance float64
+	minutes  int
+	alerting bool
+	since    time.Time
+}
+
+func (b *baseline) observe(value float64, weight float64) {
+	if b.minutes == 0 {
+		b.mean = value
+	} else {
+		delta := value - b.mean
+		b.mean += weight * delta
+		b.variance = (1 - weight) * (b.variance + weight*delta*delta)
+	}
+	b.minutes++
+}
+
+// anomalous tells whether the value is above the baseline by more than sensitivity standard deviations,
+// the deviation being at least floor
+func (b *baseline) anomalous(value float64, sensitivity float64, floor float64) bool {
+	return value > b.mean+sensitivity*math.Max(math.Sqrt(b.variance), floor)
+}
+
+type appCounts struct {
+	creations int
+	callbacks int
+	failures  int
+}
+
+type anomalyKey struct {
+	appId string
+	kind  string
+}
+
+// Detector counts the creations and the callback outcomes of every app per minute and compares them
+// with the rolling baseline of the app
+type Detector struct {
+	mu        sync.Mutex
+	minutes   map[time.Time]map[string]*appCounts
+	baselines map[anomalyKey]*baseline
+}
+
+var detector = NewDetector()
+
+// Anomalies returns the detector counting the schedules created and the callbacks fired by the node
+func Anomalies() *Detector {
+	return detector
+}
+
+func NewDetector() *Detector {
+	return &Detector{
+		minutes:   map[time.Time]map[string]*appCounts{},
+		baselines: map[anomalyKey]*baseline{},
+	}
+}
+
+// counts returns the counts of the app in the minute of t, the lock must be held
+func (d *Detector) counts(appId string, t time.Time) *appCounts {
+	minute := t.Truncate(time.Minute)
+	apps, ok := d.minutes[minute]
+	if !ok {
+		apps = map[string]*appCounts{}
+		d.minutes[minute] = apps
+		for m := range d.minutes {
+			if m.Before(minute.Add(-lagRetention)) {
+				delete(d.minutes, m)
+			}
+		}
+	}
+
+	counts, ok := apps[appId]
+	if !ok {
+		counts = &appCounts{}
+		apps[appId] = counts
+	}
+	return counts
+}
+
+// RecordCreate records a schedule of the app created at t
+func (d *Detector) RecordCreate(appId string, t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.counts(appId, t).creations++
+}
+
+// RecordCallback records the outcome of a callback of the app fired at t
+func (d *Detector) RecordCallback(appId string, failed bool, t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	counts := d.counts(appId, t)
+	counts.callbacks++
+	if failed {
+		counts.failures++
+	}
+}
+
+// Evaluate closes the given minute and returns the anomalies it raises or resolves.
+// Every app with a baseline is evaluated, an app without creations in the minute counts as zero creations.
+// The minute then becomes part of the baseline.
+func (d *Detector) Evaluate(minute time.Time, options AnomalyOptions) []Alert {
+	minute = minute.Truncate(time.Minute)
+	weight := 2 / (float64(options.BaselineMinutes) + 1)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	apps := d.minutes[minute]
+	delete(d.minutes, minute)
+
+	appIds := map[string]bool{}
+	for appId := range apps {
+		appIds[appId] = true
+	}
+	for key := range d.baselines {
+		appIds[key.appId] = true
+	}
+
+	var alerts []Alert
+	evaluate := func(key anomalyKey, value float64, floor float64, significant bool) {
+		b, ok := d.baselines[key]
+		if !ok {
+			b = &baseline{}
+			d.baselines[key] = b
+		}
+
+		anomalous := significant && b.minutes >= options.WarmupMinutes && b.anomalous(value, options.Sensitivity, floor)
+		alert := Alert{
+			Kind:     key.kind,
+			AppId:    key.appId,
+			Value:    value,
+			Baseline: b.mean,
+			Minute:   minute,
+		}
+		switch {
+		case anomalous && !b.alerting:
+			b.alerting, b.since = true, minute
+			alert.State, alert.Since = Triggered, minute
+			alerts = append(alerts, alert)
+		case !anomalous && b.alerting:
+			alert.State, alert.Since = Resolved, b.since
+			b.alerting = false
+			alerts = append(alerts, alert)
+		}
+		b.observe(value, weight)
+	}
+
+	for appId := range appIds {
+		counts := apps[appId]
+		if counts == nil {
+			counts = &appCounts{}
+		}
+
+		creations := float64(counts.creations)
+		key := anomalyKey{appId, CreationSpike}
+		if b, ok := d.baselines[key]; counts.creations > 0 || ok {
+			var floor float64
+			if ok {
+				floor = math.Sqrt(b.mean) + 1
+			}
+			evaluate(key, creations, floor, counts.creations >= options.MinCreations)
+			if b, ok = d.baselines[key]; ok && !b.alerting && b.mean < idleBaseline {
+				delete(d.baselines, key)
+			}
+		}
+
+		key = anomalyKey{appId, FailureRateJump}
+		if counts.callbacks > 0 && counts.callbacks >= options.MinCallbacks {
+			rate := float64(counts.failures) / float64(counts.callbacks)
+			evaluate(key, rate, minFailureRateDeviation, true)
+		} else if b, ok := d.baselines[key]; ok && b.alerting {
+			// too few callbacks to tell, the jump is considered over
+			b.alerting = false
+			alerts = append(alerts, Alert{State: Resolved, Kind: FailureRateJump, AppId: appId, Baseline: b.mean, Since: b.since, Minute: minute})
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].AppId != alerts[j].AppId {
+			return alerts[i].AppId < alerts[j].AppId
+		}
+		return alerts[i].Kind < alerts[j].Kind
+	})
+	return alerts
+}
@@ -0,0 +1,74 @@
+package sla
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectorEvaluate(t *testing.T) {
+	detector := NewDetector()
+	start := time.Unix(1700000040, 0)
+	options := AnomalyOptions{Sensitivity: 4, BaselineMinutes: 10, WarmupMinutes: 5, MinCreations: 20, MinCallbacks: 50}
+
+	minute := 0
+	record := func(creations int, callbacks int, failures int) []Alert {
+		at := start.Add(time.Duration(minute) * time.Minute)
+		for i := 0; i < creations; i++ {
+			detector.RecordCreate("app", at)
+		}
+		for i := 0; i < callbacks; i++ {
+			detector.RecordCallback("app", i < failures, at)
+		}
+		minute++
+		return detector.Evaluate(at, options)
+	}
+
+	if alerts := record(40, 0, 0); len(alerts) != 0 {
+		t.Fatalf("Expected no alert before the baseline is warmed up, got %+v", alerts)
+	}
+	for i := 0; i < 20; i++ {
+		if alerts := record(10, 100, 1); len(alerts) != 0 {
+			t.Fatalf("Expected no alert for a steady app, got %+v", alerts)
+		}
+	}
+
+	alerts := record(100, 100, 1)
+	if len(alerts) != 1 || alerts[0].Kind != CreationSpike || alerts[0].State != Triggered || alerts[0].Value != 100 {
+		t.Fatalf("Expected a creation spike, got %+v", alerts)
+	}
+	if alerts[0].Baseline < 10 || alerts[0].Baseline > 20 {
+		t.Errorf("Expected a baseline close to 10 creations, got %f", alerts[0].Baseline)
+	}
+
+	alerts = record(10, 100, 30)
+	if len(alerts) != 2 || alerts[0].Kind != CreationSpike || alerts[0].State != Resolved ||
+		alerts[1].Kind != FailureRateJump || alerts[1].State != Triggered || alerts[1].Value != 0.3 {
+		t.Fatalf("Expected the spike to be resolved and a failure rate jump, got %+v", alerts)
+	}
+
+	alerts = record(10, 10, 10)
+	if len(alerts) != 1 || alerts[0].Kind != FailureRateJump || alerts[0].State != Resolved {
+		t.Fatalf("Expected the jump to be resolved when too few callbacks fire, got %+v", alerts)
+	}
+
+	if alerts = record(15, 0, 0); len(alerts) != 0 {
+		t.Errorf("Expected a small increase not to alert, got %+v", alerts)
+	}
+}
+
+func TestDetectorDropsIdleApps(t *testing.T) {
+	detector := NewDetector()
+	start := time.Unix(1700000040, 0)
+	options := AnomalyOptions{Sensitivity: 4, BaselineMinutes: 1, WarmupMinutes: 1, MinCreations: 1, MinCallbacks: 1}
+
+	detector.RecordCreate("app", start)
+	detector.Evaluate(start, options)
+	if len(detector.baselines) != 1 {
+		t.Fatalf("Expected the baseline of the app, got %+v", detector.baselines)
+	}
+
+	detector.Evaluate(start.Add(time.Minute), options)
+	if len(detector.baselines) != 0 {
+		t.Errorf("Expected the baseline of an idle app to be dropped, got %+v", detector.baselines)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package sla tracks how late the callbacks of every app fire and raises an alert when the lag of an app stays
+// above the configured SLA for a number of consecutive minutes. It also raises alerts when the creation rate or the
+// callback failure rate of an app deviates from its rolling baseline.
+package sla
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// lagRetention drops the lag of the minutes which were not evaluated, e.g. when alerting is disabled
+const lagRetention = 5 * time.Minute
+
+const (
+	Triggered = "triggered"
+	Resolved  = "resolved"
+)
+
+// LagBreach is raised when the firing lag of an app stays above the SLA
+const LagBreach = "lag_breach"
+
+// Overrun is raised when callbacks of an app asking for it overran their execution deadline in a minute,
+// and resolved on the first minute without any
+const Overrun = "overrun"
+
+// maxOverrunScheduleIds bounds the schedules listed in an overrun alert
+const maxOverrunScheduleIds = 10
+
+// FiringLag is the worst firing lag of a partition in a minute
+type FiringLag struct {
+	AppId       string    `json:"appId"`
+	PartitionId int       `json:"partitionId"`
+	Minute      time.Time `json:"minute"`
+	LagMillis   int64     `json:"lagMillis"`
+	Fires       int       `json:"fires"`
+}
+
+// Alert is raised when the firing lag of an app breaches the SLA for the configured number of consecutive minutes,
+// and resolved on the first minute within the SLA. Anomalies carry the value of the minute and the baseline instead.
+type Alert struct {
+	State              string    `json:"state"`
+	Kind               string    `json:"kind"`
+	AppId              string    `json:"appId"`
+	LagMillis          int64     `json:"lagMillis,omitempty"`
+	ThresholdMillis    int64     `json:"thresholdMillis,omitempty"`
+	ConsecutiveMinutes int       `json:"consecutiveMinutes,omitempty"`
+	Value              float64   `json:"value,omitempty"`
+	Baseline           float64   `json:"baseline,omitempty"`
+	Partitions         []int     `json:"partitions,omitempty"`
+	ScheduleIds        []string  `json:"scheduleIds,omitempty"`
+	Since              time.Time `json:"since"`
+	Minute             time.Time `json:"minute"`
+	Node               string    `json:"node,omitempty"`
+}
+
+type partitionKey struct {
+	appId       string
+	partitionId int
+}
+
+// breach is the run of consecutive minutes over the SLA of an app
+type breach struct {
+	since     time.Time
+	minutes   int
+	triggered bool
+}
+
+// Tracker records the firing lag of the callbacks per partition and minute
+type Tracker struct {
+	mu          sync.Mutex
+	minutes     map[time.Time]map[partitionKey]FiringLag
+	breaches    map[string]*breach
+	overruns    map[time.Time]map[string]*overruns
+	overrunning map[string]time.Time
+}
+
+// overruns are the callbacks of an app which overran their deadline in a minute
+type overruns struct {
+	count       int
+	scheduleIds []string
+}
+
+var tracker = NewTracker()
+
+// Default returns the tracker recording the callbacks fired by the node
+func Default() *Tracker {
+	return tracker
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		minutes:     map[time.Time]map[partitionKey]FiringLag{},
+		breaches:    map[string]*breach{},
+		overruns:    map[time.Time]map[string]*overruns{},
+		overrunning: map[string]time.Time{},
+	}
+}
+
+// RecordFire records the callback of a schedule of the partition due at scheduledAt and fired at firedAt
+func (t *Tracker) RecordFire(appId string, partitionId int, scheduledAt time.Time, firedAt time.Time) {
+	minute := firedAt.Truncate(time.Minute)
+	lag := firedAt.Sub(scheduledAt)
+	if lag < 0 {
+		lag = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lags, ok := t.minutes[minute]
+	if !ok {
+		lags = map[partitionKey]FiringLag{}
+		t.minutes[minute] = lags
+		t.prune(minute.Add(-lagRetention))
+	}
+
+	key := partitionKey{appId, partitionId}
+	current := lags[key]
+	if current.Fires == 0 {
+		current = FiringLag{AppId: appId, PartitionId: partitionId, Minute: minute}
+	}
+	current.Fires++
+	if lag.Milliseconds() > current.LagMillis {
+		current.LagMillis = lag.Milliseconds()
+	}
+	lags[key] = current
+}
+
+// RecordOverrun records the callback of a schedule of the app cancelled at its deadline at t
+func (t *Tracker) RecordOverrun(appId string, scheduleId string, at time.Time) {
+	minute := at.Truncate(time.Minute)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	apps, ok := t.overruns[minute]
+	if !ok {
+		apps = map[string]*overruns{}
+		t.overruns[minute] = apps
+		t.prune(minute.Add(-lagRetention))
+	}
+
+	current, ok := apps[appId]
+	if !ok {
+		current = &overruns{}
+		apps[appId] = current
+	}
+	current.count++
+	if len(current.scheduleIds) < maxOverrunScheduleIds {
+		current.scheduleIds = append(current.scheduleIds, scheduleId)
+	}
+}
+
+// prune drops the minutes before the given one
+func (t *Tracker) prune(before time.Time) {
+	for minute := range t.minutes {
+		if minute.Before(before) {
+			delete(t.minutes, minute)
+		}
+	}
+	for minute := range t.overruns {
+		if minute.Before(before) {
+			delete(t.overruns, minute)
+		}
+	}
+}
+
+// EvaluateOverruns closes the given minute and returns the overrun alerts it raises or resolves.
+// Every minute with overruns raises an alert for the app, the first minute without resolves it.
+func (t *Tracker) EvaluateOverruns(minute time.Time) []Alert {
+	minute = minute.Truncate(time.Minute)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	apps := t.overruns[minute]
+	delete(t.overruns, minute)
+
+	var alerts []Alert
+	for appId, current := range apps {
+		since, ok := t.overrunning[appId]
+		if !ok {
+			since = minute
+			t.overrunning[appId] = since
+		}
+		alerts = append(alerts, Alert{
+			State:       Triggered,
+			Kind:        Overrun,
+			AppId:       appId,
+			Value:       float64(current.count),
+			ScheduleIds: current.scheduleIds,
+			Since:       since,
+			Minute:      minute,
+		})
+	}
+
+	for appId, since := range t.overrunning {
+		if _, ok := apps[appId]; ok {
+			continue
+		}
+		alerts = append(alerts, Alert{State: Resolved, Kind: Overrun, AppId: appId, Since: since, Minute: minute})
+		delete(t.overrunning, appId)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].AppId < alerts[j].AppId
+	})
+	return alerts
+}
+
+// Lags returns the firing lag of the partitions in the given minute, most lagging first
+func (t *Tracker) Lags(minute time.Time) []FiringLag {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lags := make([]FiringLag, 0, len(t.minutes[minute.Truncate(time.Minute)]))
+	for _, lag := range t.minutes[minute.Truncate(time.Minute)] {
+		lags = append(lags, lag)
+	}
+	sort.Slice(lags, func(i, j int) bool {
+		if lags[i].LagMillis != lags[j].LagMillis {
+			return lags[i].LagMillis > lags[j].LagMillis
+		}
+		if lags[i].AppId != lags[j].AppId {
+			return lags[i].AppId < lags[j].AppId
+		}
+		return lags[i].PartitionId < lags[j].PartitionId
+	})
+	return lags
+}
+
+// Evaluate closes the given minute and returns the alerts it raises or resolves.
+// A minute breaches the SLA of an app when the lag of any of its partitions is above the threshold, a minute in
+// which nothing fired for the app is within the SLA.
+func (t *Tracker) Evaluate(minute time.Time, threshold time.Duration, consecutive int) []Alert {
+	minute = minute.Truncate(time.Minute)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	worst := map[string]int64{}
+	breaching := map[string][]int{}
+	for key, lag := range t.minutes[minute] {
+		if lag.LagMillis > worst[key.appId] {
+			worst[key.appId] = lag.LagMillis
+		}
+		if lag.LagMillis > threshold.Milliseconds() {
+			breaching[key.appId] = append(breaching[key.appId], key.partitionId)
+		}
+	}
+
+	var alerts []Alert
+	newAlert := func(state string, appId string, b *breach) Alert {
+		sort.Ints(breaching[appId])
+		return Alert{
+			State:              state,
+			Kind:               LagBreach,
+			AppId:              appId,
+			LagMillis:          worst[appId],
+			ThresholdMillis:    threshold.Milliseconds(),
+			ConsecutiveMinutes: b.minutes,
+			Partitions:         breaching[appId],
+			Since:              b.since,
+			Minute:             minute,
+		}
+	}
+
+	for appId := range breaching {
+		b, ok := t.breaches[appId]
+		if !ok {
+			b = &breach{since: minute}
+			t.breaches[appId] = b
+		}
+		b.minutes++
+		if !b.triggered && b.minutes >= consecutive {
+			b.triggered = true
+			alerts = append(alerts, newAlert(Triggered, appId, b))
+		}
+	}
+
+	for appId, b := range t.breaches {
+		if _, ok := breaching[appId]; ok {
+			continue
+		}
+		if b.triggered {
+			alerts = append(alerts, newAlert(Resolved, appId, b))
+		}
+		delete(t.breaches, appId)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].AppId < alerts[j].AppId
+	})
+	return alerts
+}
@@ -0,0 +1,108 @@
+package sla
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrackerEvaluate(t *testing.T) {
+	tracker := NewTracker()
+	start := time.Unix(1700000040, 0)
+	fire := func(minute int, partitionId int, lag time.Duration) {
+		firedAt := start.Add(time.Duration(minute)*time.Minute + 10*time.Second)
+		tracker.RecordFire("app", partitionId, firedAt.Add(-lag), firedAt)
+	}
+	evaluate := func(minute int) []Alert {
+		return tracker.Evaluate(start.Add(time.Duration(minute)*time.Minute), 5*time.Second, 2)
+	}
+
+	fire(0, 0, time.Second)
+	fire(0, 1, 8*time.Second)
+	if alerts := evaluate(0); len(alerts) != 0 {
+		t.Fatalf("Expected no alert after a single breaching minute, got %+v", alerts)
+	}
+
+	fire(1, 0, 6*time.Second)
+	fire(1, 1, 9*time.Second)
+	fire(1, 1, 2*time.Second)
+	alerts := evaluate(1)
+	if len(alerts) != 1 {
+		t.Fatalf("Expected an alert after two breaching minutes, got %+v", alerts)
+	}
+	if alerts[0].State != Triggered || alerts[0].LagMillis != 9000 || alerts[0].ConsecutiveMinutes != 2 ||
+		!reflect.DeepEqual(alerts[0].Partitions, []int{0, 1}) || !alerts[0].Since.Equal(start.Truncate(time.Minute)) {
+		t.Errorf("Unexpected alert %+v", alerts[0])
+	}
+
+	fire(2, 1, 7*time.Second)
+	if alerts = evaluate(2); len(alerts) != 0 {
+		t.Fatalf("Expected the alert not to be raised again, got %+v", alerts)
+	}
+
+	fire(3, 0, time.Second)
+	alerts = evaluate(3)
+	if len(alerts) != 1 || alerts[0].State != Resolved || alerts[0].ConsecutiveMinutes != 3 {
+		t.Fatalf("Expected the alert to be resolved, got %+v", alerts)
+	}
+
+	fire(4, 0, 6*time.Second)
+	if alerts = evaluate(4); len(alerts) != 0 {
+		t.Errorf("Expected a new run of breaching minutes to start over, got %+v", alerts)
+	}
+	if alerts = evaluate(5); len(alerts) != 0 {
+		t.Errorf("Expected an idle minute to end the breach without an alert, got %+v", alerts)
+	}
+}
+
+func TestTrackerLags(t *testing.T) {
+	tracker := NewTracker()
+	now := time.Unix(1700000040, 0)
+	tracker.RecordFire("app", 0, now.Add(-time.Second), now)
+	tracker.RecordFire("app", 1, now.Add(-3*time.Second), now)
+	tracker.RecordFire("app", 1, now.Add(time.Second), now)
+	tracker.RecordFire("late", 0, now.Add(-time.Hour), now.Add(-time.Hour))
+	tracker.RecordFire("app", 0, now, now.Add(lagRetention))
+
+	if lags := tracker.Lags(now.Add(-time.Hour)); len(lags) != 0 {
+		t.Errorf("Expected the minutes past the retention to be dropped, got %+v", lags)
+	}
+
+	lags := tracker.Lags(now)
+	if len(lags) != 2 {
+		t.Fatalf("Expected the lag of 2 partitions, got %+v", lags)
+	}
+	if lags[0].PartitionId != 1 || lags[0].LagMillis != 3000 || lags[0].Fires != 2 {
+		t.Errorf("Expected partition 1 to lag the most, got %+v", lags[0])
+	}
+	if lags[1].PartitionId != 0 || lags[1].LagMillis != 1000 || lags[1].Fires != 1 {
+		t.Errorf("Unexpected lag of partition 0 %+v", lags[1])
+	}
+}
+
+func TestTrackerOverruns(t *testing.T) {
+	tracker := NewTracker()
+	minute := time.Unix(1700000040, 0).Truncate(time.Minute)
+	for i := 0; i < maxOverrunScheduleIds+2; i++ {
+		tracker.RecordOverrun("app", "schedule", minute.Add(time.Second))
+	}
+	tracker.RecordOverrun("other", "other-schedule", minute.Add(time.Second))
+	tracker.RecordOverrun("other", "other-schedule", minute.Add(time.Minute))
+
+	alerts := tracker.EvaluateOverruns(minute)
+	if len(alerts) != 2 || alerts[0].AppId != "app" || alerts[0].Kind != Overrun || alerts[0].State != Triggered {
+		t.Fatalf("Expected an overrun alert for each app, got %+v", alerts)
+	}
+	if alerts[0].Value != float64(maxOverrunScheduleIds+2) || len(alerts[0].ScheduleIds) != maxOverrunScheduleIds {
+		t.Errorf("Expected all the overruns counted and the listed schedules bounded, got %+v", alerts[0])
+	}
+
+	alerts = tracker.EvaluateOverruns(minute.Add(time.Minute))
+	if len(alerts) != 2 || alerts[0].AppId != "app" || alerts[0].State != Resolved ||
+		alerts[1].AppId != "other" || alerts[1].State != Triggered || !alerts[1].Since.Equal(minute) {
+		t.Errorf("Expected app to resolve and other to keep overrunning since the first minute, got %+v", alerts)
+	}
+	if alerts = tracker.EvaluateOverruns(minute.Add(3 * time.Minute)); len(alerts) != 1 || alerts[0].State != Resolved {
+		t.Errorf("Expected other to resolve, got %+v", alerts)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sla
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/monitoring"
+)
+
+// Watcher evaluates the firing lag recorded by the tracker and the rates counted by the anomaly detector at the end
+// of every minute and delivers the alerts
+type Watcher struct {
+	config   conf.SLAConfig
+	anomaly  conf.AnomalyConfig
+	node     string
+	tracker  *Tracker
+	detector *Detector
+	monitor  monitoring.Monitor
+}
+
+func NewWatcher(config *conf.Configuration, monitor monitoring.Monitor) *Watcher {
+	return &Watcher{
+		config:   config.SLAConfig,
+		anomaly:  config.AnomalyConfig,
+		node:     config.Cluster.Address,
+		tracker:  Default(),
+		detector: Anomalies(),
+		monitor:  monitor,
+	}
+}
+
+// Start starts evaluating the SLA and the anomalies once a minute if either is enabled
+func (w *Watcher) Start() {
+	if !w.config.Enabled && !w.anomaly.Enabled {
+		return
+	}
+
+	alerter, err := NewAlerter(w.config)
+	if err != nil {
+		logger.Fatal("Invalid sla configuration", err)
+	}
+	go w.run(alerter)
+}
+
+func (w *Watcher) run(alerter Alerter) {
+	for {
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		time.Sleep(next.Sub(now))
+		w.evaluate(alerter, next.Add(-time.Minute))
+	}
+}
+
+// evaluate closes the minute and delivers the alerts it raises
+func (w *Watcher) evaluate(alerter Alerter, minute time.Time) {
+	var alerts []Alert
+	if w.config.Enabled {
+		threshold := time.Duration(w.config.LagThresholdMillis) * time.Millisecond
+		alerts = append(alerts, w.tracker.Evaluate(minute, threshold, w.config.ConsecutiveMinutes)...)
+		alerts = append(alerts, w.tracker.EvaluateOverruns(minute)...)
+	}
+	if w.anomaly.Enabled {
+		anomalies := w.detector.Evaluate(minute, AnomalyOptions{
+			Sensitivity:     w.anomaly.Sensitivity,
+			BaselineMinutes: w.anomaly.BaselineMinutes,
+			WarmupMinutes:   w.anomaly.WarmupMinutes,
+			MinCreations:    w.anomaly.MinCreations,
+			MinCallbacks:    w.anomaly.MinCallbacks,
+		})
+		for _, anomaly := range anomalies {
+			if anomaly.State == Triggered && w.monitor != nil {
+				w.monitor.IncCounter(constants.AnomalyCount, map[string]string{"appId": anomaly.AppId, "kind": anomaly.Kind}, 1)
+			}
+		}
+		alerts = append(alerts, anomalies...)
+	}
+
+	for _, alert := range alerts {
+		alert.Node = w.node
+		status := constants.Success
+		if err := alerter.Alert(alert); err != nil {
+			status = constants.Fail
+			logger.Errorf("SLA alert %s %s for app %s failed with error %s", alert.Kind, alert.State, alert.AppId, err.Error())
+		} else {
+			logger.Infof("%s", alert.Summary())
+		}
+
+		if w.monitor != nil {
+			w.monitor.IncCounter(constants.SLAAlertCount, map[string]string{
+				"appId":  alert.AppId,
+				"kind":   alert.Kind,
+				"state":  alert.State,
+				"status": status,
+			}, 1)
+		}
+	}
+}
@@ -24,6 +24,7 @@ type App struct {
 	Partitions    uint32        `json:"partitions"`
 	Active        bool          `json:"active"`
 	Configuration Configuration `json:"configuration"`
+	Offboarding   *Offboarding  `json:"offboarding,omitempty"`
 }
 
 type AppErrorResponse struct {
@@ -47,3 +48,29 @@ func (a App) GetBufferTTL(bufferTTL int) int {
 
 	return 60 * 60 * 24 * a.Configuration.FiredScheduleRetentionPeriod
 }
+
+// MaxPayloadSize gets the largest payload in bytes the schedules of the app can have
+func (a App) MaxPayloadSize(payloadSize int) int {
+	if a.Configuration.PayloadSize == 0 {
+		return payloadSize
+	}
+
+	return a.Configuration.PayloadSize
+}
+
+// GetDeletedRetention gets the period deleted recurring schedules are kept for in seconds
+func (a App) GetDeletedRetention(deletedRetention int) int {
+	if a.Configuration.DeletedScheduleRetentionPeriod == 0 {
+		return 60 * 60 * 24 * deletedRetention
+	}
+
+	return 60 * 60 * 24 * a.Configuration.DeletedScheduleRetentionPeriod
+}
+
+// CloneAppRequest registers a new app with the configuration and the callback templates of an existing app.
+// The partitions of the existing app are used unless others are given.
+type CloneAppRequest struct {
+	AppId      string `json:"appId"`
+	Partitions uint32 `json:"partitions,omitempty"`
+	Active     bool   `json:"active"`
+}
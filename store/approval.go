@@ -0,0 +1,165 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// ApprovalOperation is a destructive operation which needs the approval of a second principal when approvals are
+// enabled
+type ApprovalOperation string
+
+const (
+	// DeleteAppOperation offboards an app
+	DeleteAppOperation ApprovalOperation = "deleteApp"
+	// BulkDeleteOperation deletes the schedules of an app in a time range
+	BulkDeleteOperation ApprovalOperation = "bulkDelete"
+	// ResizePartitionsOperation changes the number of partitions of an app
+	ResizePartitionsOperation ApprovalOperation = "resizePartitions"
+)
+
+// ApprovalStatus is the state of a request waiting for, or given, its approval
+type ApprovalStatus string
+
+const (
+	// ApprovalPending requests wait for a second principal to approve or reject them
+	ApprovalPending ApprovalStatus = "PENDING"
+	// ApprovalApproved requests were approved and are executing
+	ApprovalApproved ApprovalStatus = "APPROVED"
+	// ApprovalRejected requests were rejected, or withdrawn by their requester, and never executed
+	ApprovalRejected ApprovalStatus = "REJECTED"
+	// ApprovalExpired requests were not approved in time and never executed
+	ApprovalExpired ApprovalStatus = "EXPIRED"
+	// ApprovalExecuted requests were approved and executed successfully
+	ApprovalExecuted ApprovalStatus = "EXECUTED"
+	// ApprovalFailed requests were approved but their execution failed
+	ApprovalFailed ApprovalStatus = "FAILED"
+)
+
+// ApprovalRequest is the http request of a destructive operation, replayed as is once approved
+type ApprovalRequest struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Vars   map[string]string `json:"vars,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// ApprovalResult is the response of the execution of an approved request
+type ApprovalResult struct {
+	StatusCode int             `json:"statusCode"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// ApprovalEvent is an entry of the audit trail of a request: who requested, approved, rejected or executed it and when
+type ApprovalEvent struct {
+	At        int64          `json:"at"`
+	Principal string         `json:"principal,omitempty"`
+	Status    ApprovalStatus `json:"status"`
+	Comment   string         `json:"comment,omitempty"`
+}
+
+// Approval is a destructive operation requested by a principal, executed only once a second principal approves it
+type Approval struct {
+	ApprovalId  gocql.UUID        `json:"approvalId"`
+	AppId       string            `json:"appId"`
+	Operation   ApprovalOperation `json:"operation"`
+	Request     ApprovalRequest   `json:"request"`
+	Status      ApprovalStatus    `json:"status"`
+	RequestedBy string            `json:"requestedBy"`
+	RequestedAt int64             `json:"requestedAt"`
+	ExpiresAt   int64             `json:"expiresAt"`
+	DecidedBy   string            `json:"decidedBy,omitempty"`
+	DecidedAt   int64             `json:"decidedAt,omitempty"`
+	Result      *ApprovalResult   `json:"result,omitempty"`
+	Events      []ApprovalEvent   `json:"events"`
+}
+
+// NewApproval creates a pending request of an operation of an app, which expires if it is not approved in time
+func NewApproval(appId string, operation ApprovalOperation, request ApprovalRequest, principal string, now time.Time, expiry time.Duration) Approval {
+	millis := now.UnixNano() / int64(time.Millisecond)
+	return Approval{
+		ApprovalId:  gocql.TimeUUID(),
+		AppId:       appId,
+		Operation:   operation,
+		Request:     request,
+		Status:      ApprovalPending,
+		RequestedBy: principal,
+		RequestedAt: millis,
+		ExpiresAt:   millis + int64(expiry/time.Millisecond),
+		Events:      []ApprovalEvent{{At: millis, Principal: principal, Status: ApprovalPending}},
+	}
+}
+
+// Expired tells whether a pending request can no longer be approved
+func (a Approval) Expired(now time.Time) bool {
+	return a.Status == ApprovalPending && now.UnixNano()/int64(time.Millisecond) >= a.ExpiresAt
+}
+
+// CheckDecision checks that the principal can take the decision on the request.
+// Only the approvers can approve a request and the requester cannot approve its own request, while the requester or
+// an approver can reject it.
+func (a Approval) CheckDecision(principal string, status ApprovalStatus, approvers []string) error {
+	if principal == "" {
+		return errors.New("the principal deciding on a request is required")
+	}
+
+	approver := false
+	for _, p := range approvers {
+		if p == principal {
+			approver = true
+		}
+	}
+
+	switch {
+	case status == ApprovalApproved && principal == a.RequestedBy:
+		return fmt.Errorf("%s cannot approve its own request", principal)
+	case status == ApprovalApproved && !approver:
+		return fmt.Errorf("%s is not an approver", principal)
+	case status == ApprovalRejected && !approver && principal != a.RequestedBy:
+		return fmt.Errorf("%s is neither an approver nor the requester", principal)
+	default:
+		return nil
+	}
+}
+
+// Decide records the decision of a principal, or the expiry of the request when the principal is empty
+func (a *Approval) Decide(principal string, status ApprovalStatus, comment string, now time.Time) {
+	millis := now.UnixNano() / int64(time.Millisecond)
+	a.Status = status
+	a.DecidedBy = principal
+	a.DecidedAt = millis
+	a.Events = append(a.Events, ApprovalEvent{At: millis, Principal: principal, Status: status, Comment: comment})
+}
+
+// Finish records the result of the execution of an approved request
+func (a *Approval) Finish(result ApprovalResult, now time.Time) {
+	a.Status = ApprovalExecuted
+	if result.StatusCode < 200 || result.StatusCode >= 300 {
+		a.Status = ApprovalFailed
+	}
+	a.Result = &result
+	a.Events = append(a.Events, ApprovalEvent{At: now.UnixNano() / int64(time.Millisecond), Status: a.Status})
+}
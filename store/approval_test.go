@@ -0,0 +1,60 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApproval_CheckDecision(t *testing.T) {
+	approval := NewApproval("app", DeleteAppOperation, ApprovalRequest{Method: "POST", Path: "/goscheduler/apps/app/offboard"}, "alice", time.Now(), time.Hour)
+	approvers := []string{"alice", "bob"}
+
+	for _, test := range []struct {
+		principal string
+		decision  ApprovalStatus
+		allowed   bool
+	}{
+		{"bob", ApprovalApproved, true},
+		{"alice", ApprovalApproved, false},
+		{"carol", ApprovalApproved, false},
+		{"", ApprovalApproved, false},
+		{"bob", ApprovalRejected, true},
+		{"alice", ApprovalRejected, true},
+		{"carol", ApprovalRejected, false},
+	} {
+		if err := approval.CheckDecision(test.principal, test.decision, approvers); (err == nil) != test.allowed {
+			t.Errorf("%s %s: expected allowed %t, got %v", test.principal, test.decision, test.allowed, err)
+		}
+	}
+}
+
+func TestApproval_Lifecycle(t *testing.T) {
+	now := time.Now()
+	approval := NewApproval("app", ResizePartitionsOperation, ApprovalRequest{Method: "PUT"}, "alice", now, time.Hour)
+
+	if approval.Status != ApprovalPending || len(approval.Events) != 1 || approval.Events[0].Principal != "alice" {
+		t.Fatalf("expected a pending approval requested by alice, got %+v", approval)
+	}
+	if approval.Expired(now.Add(59 * time.Minute)) {
+		t.Error("expected the approval not to expire before its expiry period")
+	}
+	if !approval.Expired(now.Add(time.Hour)) {
+		t.Error("expected the approval to expire after its expiry period")
+	}
+
+	approval.Decide("bob", ApprovalApproved, "planned maintenance", now)
+	approval.Finish(ApprovalResult{StatusCode: 200}, now)
+	if approval.Status != ApprovalExecuted || approval.DecidedBy != "bob" || len(approval.Events) != 3 {
+		t.Errorf("expected an approval executed after bob approved it, got %+v", approval)
+	}
+	if approval.Expired(now.Add(2 * time.Hour)) {
+		t.Error("expected a decided approval never to expire")
+	}
+
+	failed := NewApproval("app", ResizePartitionsOperation, ApprovalRequest{Method: "PUT"}, "alice", now, time.Hour)
+	failed.Decide("bob", ApprovalApproved, "", now)
+	failed.Finish(ApprovalResult{StatusCode: 400}, now)
+	if failed.Status != ApprovalFailed {
+		t.Errorf("expected an approval whose execution failed to be failed, got %s", failed.Status)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/util"
+)
+
+// ArchivedSchedule locates a schedule moved to the archive, by which it is looked up once its rows are gone
+type ArchivedSchedule struct {
+	ScheduleId       gocql.UUID
+	AppId            string
+	ParentScheduleId gocql.UUID
+	ScheduleTime     int64
+	ObjectKey        string
+}
+
+// ArchiveRecord is a schedule as written to an archive object, one JSON record per line
+type ArchiveRecord struct {
+	Schedule         Schedule `json:"schedule"`
+	ParentScheduleId string   `json:"parentScheduleId,omitempty"`
+	ArchivedAt       int64    `json:"archivedAt"`
+}
+
+// ArchiveObjectKey names the object the schedules of a time bucket of a partition are archived to, under the app and
+// the UTC day of the bucket so that the archive of an app can be browsed by day
+func ArchiveObjectKey(appId string, partitionId int, timeBucket time.Time) string {
+	return fmt.Sprintf("%s/%s/%d.ndjson.gz", url.PathEscape(appId), timeBucket.UTC().Format("2006/01/02/1504"), partitionId)
+}
+
+// EncodeArchive writes the schedules as gzipped newline delimited JSON records
+func EncodeArchive(schedules []Schedule, archivedAt time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, schedule := range schedules {
+		record := ArchiveRecord{Schedule: schedule, ArchivedAt: archivedAt.Unix()}
+		if !util.IsZeroUUID(schedule.ParentScheduleId) {
+			record.ParentScheduleId = schedule.ParentScheduleId.String()
+		}
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+
+	codec, err := GetCodec(Gzip)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Compress(buf.Bytes())
+}
+
+// DecodeArchive reads the records of an archive object written by EncodeArchive
+func DecodeArchive(data []byte) ([]ArchiveRecord, error) {
+	codec, err := GetCodec(Gzip)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := codec.Decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []ArchiveRecord
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(raw)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record ArchiveRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+		if record.ParentScheduleId != "" {
+			if record.Schedule.ParentScheduleId, err = gocql.ParseUUID(record.ParentScheduleId); err != nil {
+				return nil, err
+			}
+		}
+		record.Schedule.Archived = true
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// FindArchived returns the schedule with the id among the records of an archive object
+func FindArchived(records []ArchiveRecord, id gocql.UUID) (Schedule, bool) {
+	for _, record := range records {
+		if record.Schedule.ScheduleId == id {
+			return record.Schedule, true
+		}
+	}
+	return Schedule{}, false
+}
+
+// IndexArchive returns the entries by which the schedules archived to the object are looked up
+func IndexArchive(schedules []Schedule, objectKey string) []ArchivedSchedule {
+	archived := make([]ArchivedSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		archived = append(archived, ArchivedSchedule{
+			ScheduleId:       schedule.ScheduleId,
+			AppId:            schedule.AppId,
+			ParentScheduleId: schedule.ParentScheduleId,
+			ScheduleTime:     schedule.ScheduleTime,
+			ObjectKey:        objectKey,
+		})
+	}
+	return archived
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+)
+
+func TestArchiveObjectKey(t *testing.T) {
+	timeBucket := time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC)
+
+	if key := ArchiveObjectKey("app", 3, timeBucket); key != "app/2026/03/04/0506/3.ndjson.gz" {
+		t.Errorf("Expected key app/2026/03/04/0506/3.ndjson.gz, got %s", key)
+	}
+	if key := ArchiveObjectKey("../app", 0, timeBucket); strings.Contains(key, "../") {
+		t.Errorf("Expected the app id to be escaped, got %s", key)
+	}
+}
+
+func TestEncodeDecodeArchive(t *testing.T) {
+	InitializeCallbackRegistry(nil)
+
+	parent := gocql.TimeUUID()
+	schedules := []Schedule{
+		{
+			ScheduleId:   gocql.TimeUUID(),
+			AppId:        "app",
+			Payload:      `{"v":1}`,
+			ScheduleTime: 1700000000,
+			Status:       Success,
+			Callback:     &HttpCallback{Type: constants.DefaultCallback, Details: Details{Url: "http://127.0.0.1/cb", Method: "POST"}},
+		},
+		{
+			ScheduleId:       gocql.TimeUUID(),
+			AppId:            "app",
+			ParentScheduleId: parent,
+			ScheduleTime:     1700000060,
+			Status:           Failure,
+			Callback:         &HttpCallback{Type: constants.DefaultCallback, Details: Details{Url: "http://127.0.0.1/cb", Method: "POST"}},
+		},
+	}
+
+	data, err := EncodeArchive(schedules, time.Unix(1700100000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := DecodeArchive(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	run, ok := FindArchived(records, schedules[1].ScheduleId)
+	if !ok {
+		t.Fatal("Expected the run to be found")
+	}
+	if run.ParentScheduleId != parent || run.Status != Failure || !run.Archived {
+		t.Errorf("Expected the archived run of %s with status %s, got %+v", parent, Failure, run)
+	}
+	if records[0].ArchivedAt != 1700100000 || records[0].Schedule.Payload != `{"v":1}` {
+		t.Errorf("Expected the archived schedule with its payload, got %+v", records[0])
+	}
+
+	if _, ok = FindArchived(records, gocql.TimeUUID()); ok {
+		t.Error("Expected an unknown schedule not to be found")
+	}
+}
+
+func TestIndexArchive(t *testing.T) {
+	schedules := []Schedule{{ScheduleId: gocql.TimeUUID(), AppId: "app", ScheduleTime: 1700000000}}
+
+	index := IndexArchive(schedules, "key")
+	if len(index) != 1 || index[0].ScheduleId != schedules[0].ScheduleId || index[0].ObjectKey != "key" || index[0].ScheduleTime != 1700000000 {
+		t.Errorf("Unexpected index %+v", index)
+	}
+}
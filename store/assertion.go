@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ResponseAssertion decides from its body whether a 2xx response of an http callback is a success.
+// With JsonPath the field at the path must be present and not null, and match Equals or Regex when set.
+// Without JsonPath the whole body must match Regex.
+type ResponseAssertion struct {
+	// Path of a field of the json body, a dotted list of fields and array indexes such as $.data.items[0].state
+	JsonPath string `json:"jsonPath,omitempty"`
+	// Expected value of the field, strings are compared without their quotes and other values in their json form
+	Equals string `json:"equals,omitempty"`
+	// Regular expression the field, or the body without a path, must match
+	Regex string `json:"regex,omitempty"`
+}
+
+// pathStep is a field or an array index of a json path
+type pathStep struct {
+	field   string
+	index   int
+	isIndex bool
+}
+
+// Validate checks that the assertion has a path or a regex, and that both are well formed
+func (a ResponseAssertion) Validate() error {
+	if a.JsonPath == "" && a.Regex == "" {
+		return errors.New("assertion must have a jsonPath or a regex")
+	}
+	if a.JsonPath == "" && a.Equals != "" {
+		return errors.New("assertion with equals must have a jsonPath")
+	}
+	if a.JsonPath != "" && a.Equals != "" && a.Regex != "" {
+		return errors.New("assertion cannot have both equals and regex")
+	}
+	if a.JsonPath != "" {
+		if _, err := parseJsonPath(a.JsonPath); err != nil {
+			return err
+		}
+	}
+	if a.Regex != "" {
+		if _, err := regexp.Compile(a.Regex); err != nil {
+			return fmt.Errorf("invalid assertion regex: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// Check returns an error telling why the body fails the assertion, nil if it passes
+func (a ResponseAssertion) Check(body []byte) error {
+	value := string(body)
+
+	if a.JsonPath != "" {
+		steps, err := parseJsonPath(a.JsonPath)
+		if err != nil {
+			return err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			return fmt.Errorf("response is not json: %s", err.Error())
+		}
+
+		field, ok := lookupJsonPath(document, steps)
+		if !ok || field == nil {
+			return fmt.Errorf("%s not found in the response", a.JsonPath)
+		}
+		if value, err = jsonValue(field); err != nil {
+			return err
+		}
+
+		if a.Equals != "" && value != a.Equals {
+			return fmt.Errorf("%s is %s, expected %s", a.JsonPath, value, a.Equals)
+		}
+	}
+
+	if a.Regex != "" {
+		re, err := regexp.Compile(a.Regex)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(value) {
+			if a.JsonPath != "" {
+				return fmt.Errorf("%s is %s, expected to match %s", a.JsonPath, value, a.Regex)
+			}
+			return fmt.Errorf("response does not match %s", a.Regex)
+		}
+	}
+	return nil
+}
+
+// parseJsonPath splits the path, with an optional leading $, into its fields and array indexes
+func parseJsonPath(path string) ([]pathStep, error) {
+	invalid := fmt.Errorf("invalid assertion jsonPath: %s", path)
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if rest == "" {
+		return nil, invalid
+	}
+
+	var steps []pathStep
+	for _, token := range strings.Split(rest, ".") {
+		field := token
+		var indexes []string
+		if open := strings.Index(token, "["); open >= 0 {
+			field = token[:open]
+			for _, index := range strings.Split(token[open:], "]") {
+				if index == "" {
+					continue
+				}
+				if !strings.HasPrefix(index, "[") {
+					return nil, invalid
+				}
+				indexes = append(indexes, index[1:])
+			}
+			if !strings.HasSuffix(token, "]") {
+				return nil, invalid
+			}
+		}
+
+		if field == "" && len(indexes) == 0 {
+			return nil, invalid
+		}
+		if field != "" {
+			steps = append(steps, pathStep{field: field})
+		}
+		for _, index := range indexes {
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 {
+				return nil, invalid
+			}
+			steps = append(steps, pathStep{index: n, isIndex: true})
+		}
+	}
+	return steps, nil
+}
+
+// lookupJsonPath returns the value at the steps of the decoded json document
+func lookupJsonPath(document interface{}, steps []pathStep) (interface{}, bool) {
+	current := document
+	for _, step := range steps {
+		if step.isIndex {
+			array, ok := current.([]interface{})
+			if !ok || step.index >= len(array) {
+				return nil, false
+			}
+			current = array[step.index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[step.field]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// jsonValue returns strings without their quotes and the other values in their json form
+func jsonValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
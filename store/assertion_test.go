@@ -0,0 +1,56 @@
+package store
+
+import "testing"
+
+func TestResponseAssertion_Validate(t *testing.T) {
+	for _, test := range []struct {
+		assertion ResponseAssertion
+		valid     bool
+	}{
+		{ResponseAssertion{JsonPath: "$.status"}, true},
+		{ResponseAssertion{JsonPath: "data.items[0].state", Equals: "done"}, true},
+		{ResponseAssertion{JsonPath: "$.matrix[0][1]", Regex: "^[0-9]+$"}, true},
+		{ResponseAssertion{Regex: "ok"}, true},
+		{ResponseAssertion{}, false},
+		{ResponseAssertion{Equals: "done"}, false},
+		{ResponseAssertion{JsonPath: "$.status", Equals: "done", Regex: "done"}, false},
+		{ResponseAssertion{JsonPath: "$"}, false},
+		{ResponseAssertion{JsonPath: "$.items[a]"}, false},
+		{ResponseAssertion{JsonPath: "$.items[0"}, false},
+		{ResponseAssertion{JsonPath: "$.a..b"}, false},
+		{ResponseAssertion{Regex: "("}, false},
+	} {
+		if err := test.assertion.Validate(); (err == nil) != test.valid {
+			t.Errorf("assertion %+v: expected valid %v, got %v", test.assertion, test.valid, err)
+		}
+	}
+}
+
+func TestResponseAssertion_Check(t *testing.T) {
+	body := []byte(`{"status": "done", "count": 3, "ok": true, "empty": null, "items": [{"state": "queued"}, {"state": "sent"}]}`)
+
+	for _, test := range []struct {
+		assertion ResponseAssertion
+		body      []byte
+		pass      bool
+	}{
+		{ResponseAssertion{JsonPath: "$.status"}, body, true},
+		{ResponseAssertion{JsonPath: "$.status", Equals: "done"}, body, true},
+		{ResponseAssertion{JsonPath: "$.status", Equals: "failed"}, body, false},
+		{ResponseAssertion{JsonPath: "count", Equals: "3"}, body, true},
+		{ResponseAssertion{JsonPath: "$.ok", Equals: "true"}, body, true},
+		{ResponseAssertion{JsonPath: "$.items[1].state", Regex: "^(sent|delivered)$"}, body, true},
+		{ResponseAssertion{JsonPath: "$.items[0].state", Regex: "^(sent|delivered)$"}, body, false},
+		{ResponseAssertion{JsonPath: "$.items[2].state"}, body, false},
+		{ResponseAssertion{JsonPath: "$.empty"}, body, false},
+		{ResponseAssertion{JsonPath: "$.missing"}, body, false},
+		{ResponseAssertion{JsonPath: "$.status.code"}, body, false},
+		{ResponseAssertion{JsonPath: "$.status"}, []byte("OK"), false},
+		{ResponseAssertion{Regex: `"status":\s*"done"`}, body, true},
+		{ResponseAssertion{Regex: "error"}, body, false},
+	} {
+		if err := test.assertion.Check(test.body); (err == nil) != test.pass {
+			t.Errorf("assertion %+v: expected pass %v, got %v", test.assertion, test.pass, err)
+		}
+	}
+}
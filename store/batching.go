@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultBatchLinger is how long the first run of a batch waits for more runs by default
+const defaultBatchLinger = time.Second
+
+// maxBatchItems and maxBatchLinger bound the batches of the http callbacks
+const (
+	maxBatchItems  = 1000
+	maxBatchLinger = time.Minute
+)
+
+// CallbackBatching delivers the http callbacks of an app due together for the same target, its method, url and
+// headers, in a single request carrying the payloads of all the runs
+type CallbackBatching struct {
+	// Most runs delivered in a request, a full batch is delivered right away
+	MaxItems int `json:"maxItems"`
+	// How long the first run of a batch waits for more runs, 1 second when left out
+	LingerMillis int `json:"lingerMillis,omitempty"`
+}
+
+// BatchedCallback is the item of a run in the body of a batched callback request
+type BatchedCallback struct {
+	ScheduleId       string `json:"scheduleId"`
+	ParentScheduleId string `json:"parentScheduleId,omitempty"`
+	Payload          string `json:"payload"`
+}
+
+// BatchAcknowledgement is the outcome of a run in the response to a batched callback request
+type BatchAcknowledgement struct {
+	ScheduleId string `json:"scheduleId"`
+	Status     Status `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// batchResponse is the body of a response to a batched callback request acknowledging its runs one by one
+type batchResponse struct {
+	Results []BatchAcknowledgement `json:"results"`
+}
+
+// Validate checks that a batch holds at least two runs and that the bounds are within their limits
+func (b *CallbackBatching) Validate() error {
+	if b.MaxItems < 2 || b.MaxItems > maxBatchItems {
+		return fmt.Errorf("maxItems of callback batching must be between 2 and %d, got %d", maxBatchItems, b.MaxItems)
+	}
+	if b.LingerMillis < 0 || time.Duration(b.LingerMillis)*time.Millisecond > maxBatchLinger {
+		return fmt.Errorf("lingerMillis of callback batching must be between 0 and %d, got %d", maxBatchLinger.Milliseconds(), b.LingerMillis)
+	}
+	return nil
+}
+
+// Linger returns how long the first run of a batch waits for more runs
+func (b *CallbackBatching) Linger() time.Duration {
+	if b.LingerMillis == 0 {
+		return defaultBatchLinger
+	}
+	return time.Duration(b.LingerMillis) * time.Millisecond
+}
+
+// BatchKey returns the key of the batch of the http callback of a schedule. The callbacks of an app with the same
+// method, url, headers and region share a batch
+func BatchKey(schedule Schedule) string {
+	details := schedule.Callback.(*HttpCallback).Details
+
+	headers := make([]string, 0, len(details.Headers))
+	for header, value := range details.Headers {
+		headers = append(headers, header+":"+value)
+	}
+	sort.Strings(headers)
+
+	return strings.Join([]string{schedule.AppId, schedule.Region, details.Method, details.Url, strings.Join(headers, "\n")}, "\n")
+}
+
+// ParseBatchAcknowledgements returns the outcomes of the runs acknowledged one by one by the json body of a response
+// to a batched callback request, by the id of their schedule
+func ParseBatchAcknowledgements(body []byte) map[string]BatchAcknowledgement {
+	var response batchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+
+	acknowledgements := make(map[string]BatchAcknowledgement, len(response.Results))
+	for _, acknowledgement := range response.Results {
+		acknowledgements[acknowledgement.ScheduleId] = acknowledgement
+	}
+	return acknowledgements
+}
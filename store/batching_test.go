@@ -0,0 +1,69 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCallbackBatching_Validate(t *testing.T) {
+	for _, test := range []struct {
+		batching CallbackBatching
+		valid    bool
+	}{
+		{CallbackBatching{MaxItems: 100}, true},
+		{CallbackBatching{MaxItems: 2, LingerMillis: 60000}, true},
+		{CallbackBatching{MaxItems: 1}, false},
+		{CallbackBatching{MaxItems: 1001}, false},
+		{CallbackBatching{MaxItems: 100, LingerMillis: -1}, false},
+		{CallbackBatching{MaxItems: 100, LingerMillis: 60001}, false},
+	} {
+		if err := test.batching.Validate(); (err == nil) != test.valid {
+			t.Errorf("batching %+v: expected valid %v, got %v", test.batching, test.valid, err)
+		}
+	}
+}
+
+func TestCallbackBatching_Linger(t *testing.T) {
+	if linger := (&CallbackBatching{MaxItems: 10}).Linger(); linger != time.Second {
+		t.Errorf("expected the default linger of 1s, got %v", linger)
+	}
+	if linger := (&CallbackBatching{MaxItems: 10, LingerMillis: 250}).Linger(); linger != 250*time.Millisecond {
+		t.Errorf("expected a linger of 250ms, got %v", linger)
+	}
+}
+
+func TestBatchKey(t *testing.T) {
+	schedule := func(appId string, url string, headers map[string]string) Schedule {
+		return Schedule{
+			AppId: appId,
+			Callback: &HttpCallback{
+				Type:    "http",
+				Details: Details{Url: url, Method: "POST", Headers: headers},
+			},
+		}
+	}
+
+	base := BatchKey(schedule("test", "http://localhost:8080/batch", map[string]string{"a": "1", "b": "2"}))
+	if key := BatchKey(schedule("test", "http://localhost:8080/batch", map[string]string{"b": "2", "a": "1"})); key != base {
+		t.Errorf("expected the same key whatever the order of the headers")
+	}
+	for _, other := range []Schedule{
+		schedule("other", "http://localhost:8080/batch", map[string]string{"a": "1", "b": "2"}),
+		schedule("test", "http://localhost:8080/other", map[string]string{"a": "1", "b": "2"}),
+		schedule("test", "http://localhost:8080/batch", map[string]string{"a": "1"}),
+	} {
+		if BatchKey(other) == base {
+			t.Errorf("expected a key of its own for %+v", other)
+		}
+	}
+}
+
+func TestParseBatchAcknowledgements(t *testing.T) {
+	acknowledgements := ParseBatchAcknowledgements([]byte(`{"results":[{"scheduleId":"a","status":"SUCCESS"},{"scheduleId":"b","status":"FAILURE","error":"unknown order"}]}`))
+	if len(acknowledgements) != 2 || acknowledgements["a"].Status != Success || acknowledgements["b"].Error != "unknown order" {
+		t.Errorf("unexpected acknowledgements %+v", acknowledgements)
+	}
+	if acknowledgements := ParseBatchAcknowledgements([]byte("OK")); acknowledgements != nil {
+		t.Errorf("expected no acknowledgements of a body which is not json, got %+v", acknowledgements)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/cron"
+)
+
+// BlackoutPolicy decides what happens to the schedules which fall due within a blackout window.
+type BlackoutPolicy string
+
+const (
+	// SkipBlackoutRuns drops the schedules due within the window, this is the default
+	SkipBlackoutRuns BlackoutPolicy = "skip"
+	// QueueBlackoutRuns fires the schedules due within the window once it is over
+	QueueBlackoutRuns BlackoutPolicy = "queue"
+)
+
+const (
+	// maxBlackoutMinutes caps the duration of a recurring blackout window
+	maxBlackoutMinutes = 24 * 60
+	// maxBlackoutSpan caps how far chained windows push the end of a blackout
+	maxBlackoutSpan = 7 * 24 * time.Hour
+)
+
+// Blackout lists the windows during which the callbacks of an app do not fire.
+type Blackout struct {
+	Windows []conf.BlackoutWindow `json:"windows"`
+	Policy  BlackoutPolicy        `json:"policy,omitempty"`
+}
+
+// Validate checks the policy and the windows of the blackout
+func (b Blackout) Validate() error {
+	switch b.Policy {
+	case "", SkipBlackoutRuns, QueueBlackoutRuns:
+	default:
+		return fmt.Errorf("invalid blackout policy: %s, must be one of skip or queue", b.Policy)
+	}
+
+	for _, window := range b.Windows {
+		if err := ValidateBlackoutWindow(window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateBlackoutWindow checks that the window is either a cron with a duration or a time range
+func ValidateBlackoutWindow(window conf.BlackoutWindow) error {
+	switch {
+	case window.Cron != "" && (window.StartTime != 0 || window.EndTime != 0):
+		return fmt.Errorf("blackout window can have either a cron or a start and end time")
+	case window.Cron != "":
+		if _, errs := cron.Default().Parse(window.Cron); len(errs) != 0 {
+			return fmt.Errorf("invalid blackout window cron %s: %v", window.Cron, errs)
+		}
+		if window.DurationMinutes <= 0 || window.DurationMinutes > maxBlackoutMinutes {
+			return fmt.Errorf("invalid blackout window duration: %d, must be between 1 and %d minutes", window.DurationMinutes, maxBlackoutMinutes)
+		}
+	case window.StartTime >= window.EndTime:
+		return fmt.Errorf("blackout window start time: %d must be before end time: %d", window.StartTime, window.EndTime)
+	}
+	return nil
+}
+
+// blackoutWindowEnd returns the end of the occurrence of the window containing t, false if t is outside the window
+func blackoutWindowEnd(window conf.BlackoutWindow, t time.Time) (time.Time, bool) {
+	if window.Cron == "" {
+		if t.Unix() >= window.StartTime && t.Unix() < window.EndTime {
+			return time.Unix(window.EndTime, 0), true
+		}
+		return time.Time{}, false
+	}
+
+	expression, errs := cron.Default().Parse(window.Cron)
+	if len(errs) != 0 {
+		return time.Time{}, false
+	}
+
+	minute := t.UTC().Truncate(time.Minute)
+	for i := 0; i < window.DurationMinutes; i++ {
+		start := minute.Add(time.Duration(-i) * time.Minute)
+		if expression.Match(start) {
+			return start.Add(time.Duration(window.DurationMinutes) * time.Minute), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// blackoutEnd returns the time from which t is no longer within any of the windows, following windows which overlap
+// or adjoin one another, false if t is outside all the windows
+func blackoutEnd(windows []conf.BlackoutWindow, t time.Time) (time.Time, bool) {
+	end, found := t, false
+	for {
+		extended := false
+		for _, window := range windows {
+			if windowEnd, ok := blackoutWindowEnd(window, end); ok && windowEnd.After(end) {
+				end, found, extended = windowEnd, true, true
+			}
+		}
+		if !extended || end.Sub(t) > maxBlackoutSpan {
+			return end, found
+		}
+	}
+}
+
+// ActiveBlackout returns the end and the policy of the blackout of the app at t, looking at the windows of the app
+// and the global ones. The policy of the app applies when one of its windows contains t.
+// Returns false if t is outside every window.
+func ActiveBlackout(app App, global conf.BlackoutConfig, t time.Time) (time.Time, BlackoutPolicy, bool) {
+	var appWindows []conf.BlackoutWindow
+	appPolicy := SkipBlackoutRuns
+	if app.Configuration.Blackout != nil {
+		appWindows = app.Configuration.Blackout.Windows
+		if app.Configuration.Blackout.Policy != "" {
+			appPolicy = app.Configuration.Blackout.Policy
+		}
+	}
+
+	windows := append(append([]conf.BlackoutWindow{}, appWindows...), global.Windows...)
+	end, ok := blackoutEnd(windows, t)
+	if !ok {
+		return time.Time{}, "", false
+	}
+
+	if _, inApp := blackoutEnd(appWindows, t); inApp {
+		return end, appPolicy, true
+	}
+	if global.Policy == string(QueueBlackoutRuns) {
+		return end, QueueBlackoutRuns, true
+	}
+	return end, SkipBlackoutRuns, true
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestBlackout_Validate(t *testing.T) {
+	for _, test := range []struct {
+		blackout Blackout
+		valid    bool
+	}{
+		{Blackout{}, true},
+		{Blackout{Policy: QueueBlackoutRuns, Windows: []conf.BlackoutWindow{{Cron: "0 2 * * *", DurationMinutes: 60}}}, true},
+		{Blackout{Windows: []conf.BlackoutWindow{{StartTime: 100, EndTime: 200}}}, true},
+		{Blackout{Policy: "defer"}, false},
+		{Blackout{Windows: []conf.BlackoutWindow{{Cron: "0 2 * * *"}}}, false},
+		{Blackout{Windows: []conf.BlackoutWindow{{Cron: "0 2 * * *", DurationMinutes: 1441}}}, false},
+		{Blackout{Windows: []conf.BlackoutWindow{{Cron: "not a cron", DurationMinutes: 10}}}, false},
+		{Blackout{Windows: []conf.BlackoutWindow{{Cron: "0 2 * * *", DurationMinutes: 10, StartTime: 100, EndTime: 200}}}, false},
+		{Blackout{Windows: []conf.BlackoutWindow{{StartTime: 200, EndTime: 200}}}, false},
+	} {
+		if err := test.blackout.Validate(); (err == nil) != test.valid {
+			t.Errorf("blackout %+v: expected valid %v, got %v", test.blackout, test.valid, err)
+		}
+	}
+}
+
+func TestActiveBlackout(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, _ := time.Parse(time.RFC3339, value)
+		return parsed
+	}
+	nightly := conf.BlackoutWindow{Cron: "0 2 * * *", DurationMinutes: 60}
+	release := conf.BlackoutWindow{StartTime: at("2023-01-01T02:30:00Z").Unix(), EndTime: at("2023-01-01T04:00:00Z").Unix()}
+
+	app := App{AppId: "app", Configuration: Configuration{Blackout: &Blackout{Windows: []conf.BlackoutWindow{nightly}, Policy: QueueBlackoutRuns}}}
+	global := conf.BlackoutConfig{Windows: []conf.BlackoutWindow{release}, Policy: string(SkipBlackoutRuns)}
+
+	for _, test := range []struct {
+		time   string
+		end    string
+		policy BlackoutPolicy
+		active bool
+	}{
+		{time: "2023-01-01T01:59:59Z"},
+		{time: "2023-01-01T02:00:00Z", end: "2023-01-01T04:00:00Z", policy: QueueBlackoutRuns, active: true},
+		{time: "2023-01-01T02:59:00Z", end: "2023-01-01T04:00:00Z", policy: QueueBlackoutRuns, active: true},
+		{time: "2023-01-01T03:30:00Z", end: "2023-01-01T04:00:00Z", policy: SkipBlackoutRuns, active: true},
+		{time: "2023-01-01T04:00:00Z"},
+		{time: "2023-01-02T02:30:00Z", end: "2023-01-02T03:00:00Z", policy: QueueBlackoutRuns, active: true},
+	} {
+		end, policy, active := ActiveBlackout(app, global, at(test.time))
+		if active != test.active || policy != test.policy || (active && !end.Equal(at(test.end))) {
+			t.Errorf("at %s: expected %v %s until %s, got %v %s until %s", test.time, test.active, test.policy, test.end, active, policy, end)
+		}
+	}
+}
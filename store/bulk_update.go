@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+// MaxBulkUpdateSchedules caps the number of schedules a single bulk update can match.
+const MaxBulkUpdateSchedules = 10000
+
+// ScheduleFilter selects recurring schedules of an app, every criterion which is set must match.
+// Deleted schedules never match.
+type ScheduleFilter struct {
+	Status          Status       `json:"status,omitempty"`
+	ScheduleIds     []gocql.UUID `json:"scheduleIds,omitempty"`
+	CallbackType    string       `json:"callbackType,omitempty"`
+	CallbackUrl     string       `json:"callbackUrl,omitempty"` // Part of the url of http callbacks
+	CronExpression  string       `json:"cronExpression,omitempty"`
+	PayloadContains string       `json:"payloadContains,omitempty"`
+	Group           string       `json:"group,omitempty"`
+}
+
+// Validate checks the status of the filter
+func (f ScheduleFilter) Validate() error {
+	switch f.Status {
+	case "", Scheduled, Paused, Draft:
+		return nil
+	default:
+		return fmt.Errorf("invalid status %s, a filter can only select %s, %s or %s schedules", f.Status, Scheduled, Paused, Draft)
+	}
+}
+
+// Matches tells whether the schedule is selected by the filter
+func (f ScheduleFilter) Matches(schedule Schedule) bool {
+	if !schedule.IsRecurring() || schedule.Status == Deleted {
+		return false
+	}
+	if f.Status != "" && schedule.Status != f.Status {
+		return false
+	}
+	if len(f.ScheduleIds) > 0 && !containsUUID(f.ScheduleIds, schedule.ScheduleId) {
+		return false
+	}
+	if f.CallbackType != "" && (schedule.Callback == nil || schedule.Callback.GetType() != f.CallbackType) {
+		return false
+	}
+	if f.CallbackUrl != "" {
+		callback, ok := schedule.Callback.(*HttpCallback)
+		if !ok || !strings.Contains(callback.Details.Url, f.CallbackUrl) {
+			return false
+		}
+	}
+	if f.CronExpression != "" && schedule.CronExpression != f.CronExpression {
+		return false
+	}
+	if f.PayloadContains != "" && !strings.Contains(schedule.Payload, f.PayloadContains) {
+		return false
+	}
+	if f.Group != "" && schedule.Group != f.Group {
+		return false
+	}
+	return true
+}
+
+func containsUUID(uuids []gocql.UUID, uuid gocql.UUID) bool {
+	for _, id := range uuids {
+		if id == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkUpdateRequest applies a JSON merge patch to every recurring schedule of an app matching the filter
+type BulkUpdateRequest struct {
+	Filter ScheduleFilter  `json:"filter"`
+	Patch  json.RawMessage `json:"patch"`
+}
+
+// Validate checks the filter and that the patch is a JSON object which leaves the identity of the schedules alone
+func (r BulkUpdateRequest) Validate() error {
+	if err := r.Filter.Validate(); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(r.Patch, &fields); err != nil || len(fields) == 0 {
+		return errors.New("patch must be a non empty JSON object")
+	}
+	for _, field := range []string{"appId", "scheduleId"} {
+		if _, ok := fields[field]; ok {
+			return fmt.Errorf("%s cannot be updated in bulk", field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestScheduleFilter_Matches(t *testing.T) {
+	schedule := Schedule{
+		ScheduleId:     gocql.TimeUUID(),
+		AppId:          "app",
+		Payload:        `{"region":"eu"}`,
+		CronExpression: "0 2 * * *",
+		Status:         Scheduled,
+		Group:          "orders",
+		Callback:       &HttpCallback{Type: "http", Details: Details{Url: "http://orders.internal/v1/callback", Method: "POST"}},
+	}
+
+	for _, test := range []struct {
+		name   string
+		filter ScheduleFilter
+		match  bool
+	}{
+		{"Empty", ScheduleFilter{}, true},
+		{"Status", ScheduleFilter{Status: Scheduled}, true},
+		{"OtherStatus", ScheduleFilter{Status: Paused}, false},
+		{"ScheduleIds", ScheduleFilter{ScheduleIds: []gocql.UUID{gocql.TimeUUID(), schedule.ScheduleId}}, true},
+		{"OtherScheduleIds", ScheduleFilter{ScheduleIds: []gocql.UUID{gocql.TimeUUID()}}, false},
+		{"CallbackType", ScheduleFilter{CallbackType: "http"}, true},
+		{"OtherCallbackType", ScheduleFilter{CallbackType: "airbus"}, false},
+		{"CallbackUrl", ScheduleFilter{CallbackUrl: "orders.internal/v1"}, true},
+		{"OtherCallbackUrl", ScheduleFilter{CallbackUrl: "orders.internal/v2"}, false},
+		{"CronExpression", ScheduleFilter{CronExpression: "0 2 * * *"}, true},
+		{"PayloadContains", ScheduleFilter{PayloadContains: `"region":"eu"`}, true},
+		{"Group", ScheduleFilter{Group: "orders"}, true},
+		{"OtherGroup", ScheduleFilter{Group: "returns"}, false},
+		{"AllCriteria", ScheduleFilter{Status: Scheduled, CallbackUrl: "orders", PayloadContains: "us"}, false},
+	} {
+		if match := test.filter.Matches(schedule); match != test.match {
+			t.Errorf("%s: expected match %t, got %t", test.name, test.match, match)
+		}
+	}
+
+	deleted := schedule
+	deleted.Status = Deleted
+	if (ScheduleFilter{}).Matches(deleted) {
+		t.Errorf("Expected deleted schedules not to match")
+	}
+
+	oneTime := schedule
+	oneTime.CronExpression = ""
+	if (ScheduleFilter{}).Matches(oneTime) {
+		t.Errorf("Expected one time schedules not to match")
+	}
+}
+
+func TestBulkUpdateRequest_Validate(t *testing.T) {
+	for _, test := range []struct {
+		request BulkUpdateRequest
+		valid   bool
+	}{
+		{BulkUpdateRequest{Patch: json.RawMessage(`{"payload":"{}"}`)}, true},
+		{BulkUpdateRequest{Filter: ScheduleFilter{Status: Paused}, Patch: json.RawMessage(`{"callback":{"details":{"url":"http://new"}}}`)}, true},
+		{BulkUpdateRequest{}, false},
+		{BulkUpdateRequest{Patch: json.RawMessage(`{}`)}, false},
+		{BulkUpdateRequest{Patch: json.RawMessage(`["payload"]`)}, false},
+		{BulkUpdateRequest{Patch: json.RawMessage(`{"scheduleId":"00000000-0000-0000-0000-000000000000"}`)}, false},
+		{BulkUpdateRequest{Filter: ScheduleFilter{Status: Deleted}, Patch: json.RawMessage(`{"payload":"{}"}`)}, false},
+	} {
+		if err := test.request.Validate(); (err == nil) != test.valid {
+			t.Errorf("Expected valid %t for %s with filter %+v, got %v", test.valid, test.request.Patch, test.request.Filter, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+// CallbackAttempt is the execution log of an attempt of an http callback. The bodies are redacted by the rules of
+// the app and cut to the snippet size before the attempt is persisted.
+type CallbackAttempt struct {
+	ScheduleId gocql.UUID `json:"scheduleId"`
+	AppId      string     `json:"appId"`
+	// Attempt is the number of the attempt within the run, starting at 1
+	Attempt int `json:"attempt"`
+	// AttemptedAt is the time in milliseconds the attempt started at
+	AttemptedAt int64  `json:"attemptedAt"`
+	Method      string `json:"method"`
+	// Url is the callback url with its secret references left unresolved
+	Url             string `json:"url"`
+	RequestSnippet  string `json:"requestSnippet,omitempty"`
+	ResponseStatus  int    `json:"responseStatus,omitempty"`
+	ResponseSnippet string `json:"responseSnippet,omitempty"`
+	Error           string `json:"error,omitempty"`
+	LatencyMillis   int64  `json:"latencyMillis"`
+}
+
+// Snippet returns up to size bytes of the body, dropping a character cut in half
+func Snippet(body string, size int) string {
+	if size <= 0 {
+		return ""
+	}
+	if len(body) > size {
+		body = body[:size]
+	}
+	return strings.ToValidUTF8(body, "")
+}
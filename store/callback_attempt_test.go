@@ -0,0 +1,20 @@
+package store
+
+import "testing"
+
+func TestSnippet(t *testing.T) {
+	for _, test := range []struct {
+		body     string
+		size     int
+		expected string
+	}{
+		{"payload", 0, ""},
+		{"payload", 3, "pay"},
+		{"payload", 20, "payload"},
+		{"héllo", 2, "h"},
+	} {
+		if actual := Snippet(test.body, test.size); actual != test.expected {
+			t.Errorf("snippet of %q to %d: expected %q, got %q", test.body, test.size, test.expected, actual)
+		}
+	}
+}
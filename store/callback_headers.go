@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/myntra/goscheduler/constants"
+)
+
+// maxCallbackHeaders bounds the headers an app adds to its callbacks
+const maxCallbackHeaders = 50
+
+// CallbackHeaders are the headers an app adds to every http callback of its schedules.
+// The headers of a schedule take precedence over the ones of its app.
+type CallbackHeaders map[string]string
+
+// Validate checks that the headers are well formed and do not replace the headers set by the scheduler
+func (h CallbackHeaders) Validate() error {
+	if len(h) > maxCallbackHeaders {
+		return fmt.Errorf("at most %d callback headers can be configured, got %d", maxCallbackHeaders, len(h))
+	}
+
+	for name, value := range h {
+		if !isHeaderName(name) {
+			return fmt.Errorf("invalid callback header name: %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("value of callback header %s cannot contain line breaks", name)
+		}
+		if err := ValidateSecretRefs(value); err != nil {
+			return err
+		}
+		switch http.CanonicalHeaderKey(name) {
+		case constants.ScheduleIdHeader, constants.ParentScheduleId:
+			return fmt.Errorf("callback header %s is set by the scheduler", name)
+		}
+	}
+	return nil
+}
+
+// isHeaderName tells whether the name is a token as required of http header names
+func isHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package store
+
+import "testing"
+
+func TestCallbackHeaders_Validate(t *testing.T) {
+	for _, test := range []struct {
+		headers CallbackHeaders
+		valid   bool
+	}{
+		{CallbackHeaders{}, true},
+		{CallbackHeaders{"Authorization": "Bearer token", "X-Route-Hint": "blue"}, true},
+		{CallbackHeaders{"": "value"}, false},
+		{CallbackHeaders{"X Route": "value"}, false},
+		{CallbackHeaders{"X-Route:": "value"}, false},
+		{CallbackHeaders{"X-Route": "blue\r\nX-Injected: true"}, false},
+		{CallbackHeaders{"schedule-id": "value"}, false},
+		{CallbackHeaders{"Parent-Schedule-Id": "value"}, false},
+	} {
+		if err := test.headers.Validate(); (err == nil) != test.valid {
+			t.Errorf("headers %v: expected valid %v, got %v", test.headers, test.valid, err)
+		}
+	}
+}
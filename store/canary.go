@@ -0,0 +1,341 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+)
+
+// MaxCanaryRuns caps the number of runs a callback canary can fire.
+const MaxCanaryRuns = 100
+
+// CanaryTarget tells which callback a run of a callback canary is fired to.
+type CanaryTarget string
+
+const (
+	// CanaryBaseline is the callback of the schedule before the update
+	CanaryBaseline CanaryTarget = "baseline"
+	// CanaryCandidate is the updated callback of the schedule
+	CanaryCandidate CanaryTarget = "candidate"
+	// CanaryMirror fires the run to the baseline and mirrors it to the candidate
+	CanaryMirror CanaryTarget = "mirror"
+)
+
+// CanaryPolicy asks for the next runs of a recurring schedule whose callback is updated to be fired to its previous callback too.
+// Without a percent every run is fired to both callbacks, otherwise percent of the runs are fired to the new callback only
+// and the others to the previous one.
+type CanaryPolicy struct {
+	Runs    int `json:"runs"`
+	Percent int `json:"percent,omitempty"`
+}
+
+// Validate checks the number of runs and the percent of the policy
+func (p CanaryPolicy) Validate() error {
+	if p.Runs <= 0 || p.Runs > MaxCanaryRuns {
+		return fmt.Errorf("canary runs must be between 1 and %d", MaxCanaryRuns)
+	}
+	if p.Percent < 0 || p.Percent >= 100 {
+		return errors.New("canary percent must be between 0 and 99")
+	}
+	return nil
+}
+
+// CallbackCanary is the canary of an updated callback of a recurring schedule, Fired counts the runs it has fired so far.
+type CallbackCanary struct {
+	ScheduleId gocql.UUID      `json:"scheduleId"`
+	AppId      string          `json:"appId"`
+	Runs       int             `json:"runs"`
+	Percent    int             `json:"percent,omitempty"`
+	Fired      int             `json:"fired"`
+	Baseline   json.RawMessage `json:"baseline"`
+	Candidate  json.RawMessage `json:"candidate"`
+	CreatedAt  int64           `json:"createdAt"`
+}
+
+// NewCallbackCanary creates the canary of the updated callback of a schedule against its previous callback.
+// Both callbacks must be http callbacks and they must differ
+func NewCallbackCanary(schedule Schedule, previous Callback, policy CanaryPolicy, now time.Time) (CallbackCanary, error) {
+	if err := policy.Validate(); err != nil {
+		return CallbackCanary{}, err
+	}
+	if !isHttpCallback(previous) || !isHttpCallback(schedule.Callback) {
+		return CallbackCanary{}, errors.New("a canary is only supported between http callbacks")
+	}
+
+	baseline, err := json.Marshal(previous)
+	if err != nil {
+		return CallbackCanary{}, err
+	}
+	candidate, err := json.Marshal(schedule.Callback)
+	if err != nil {
+		return CallbackCanary{}, err
+	}
+	if bytes.Equal(baseline, candidate) {
+		return CallbackCanary{}, errors.New("a canary needs a callback different from the current one")
+	}
+
+	return CallbackCanary{
+		ScheduleId: schedule.ScheduleId,
+		AppId:      schedule.AppId,
+		Runs:       policy.Runs,
+		Percent:    policy.Percent,
+		Baseline:   baseline,
+		Candidate:  candidate,
+		CreatedAt:  now.UnixNano() / int64(time.Millisecond),
+	}, nil
+}
+
+func isHttpCallback(callback Callback) bool {
+	_, ok := callback.(*HttpCallback)
+	return ok
+}
+
+// Applies tells whether the next run of the schedule is to be fired by the canary, which is no longer the case
+// once all its runs are fired or the callback of the schedule has been updated again
+func (c CallbackCanary) Applies(parent Schedule) bool {
+	if c.Fired >= c.Runs || parent.Callback == nil {
+		return false
+	}
+
+	current, err := json.Marshal(parent.Callback)
+	return err == nil && bytes.Equal(current, c.Candidate)
+}
+
+// Next returns the target of the next run of the canary and counts the run as fired.
+// The runs fired to the candidate are spread evenly, the first run always being one of them
+func (c *CallbackCanary) Next() CanaryTarget {
+	fired := c.Fired
+	c.Fired++
+
+	if c.Percent == 0 {
+		return CanaryMirror
+	}
+	if ((fired+1)*c.Percent+99)/100 > (fired*c.Percent+99)/100 {
+		return CanaryCandidate
+	}
+	return CanaryBaseline
+}
+
+// Wrap replaces the callback of a run with the canary callback firing it to the target
+func (c CallbackCanary) Wrap(run *Schedule, target CanaryTarget) {
+	run.Callback = &CanaryCallback{
+		Type: constants.CanaryCallback,
+		Details: CanaryDetails{
+			Target:    target,
+			Baseline:  c.Baseline,
+			Candidate: c.Candidate,
+		},
+	}
+}
+
+type CanaryDetails struct {
+	Target    CanaryTarget    `json:"target"`
+	Baseline  json.RawMessage `json:"baseline"`
+	Candidate json.RawMessage `json:"candidate"`
+}
+
+// CanaryCallback is the callback of the runs fired by a callback canary
+type CanaryCallback struct {
+	Type    string        `json:"type"`
+	Details CanaryDetails `json:"details"`
+}
+
+func (c *CanaryCallback) GetType() string {
+	return c.Type
+}
+
+func (c *CanaryCallback) GetDetails() (string, error) {
+	details, err := json.Marshal(c.Details)
+	return string(details), err
+}
+
+func (c *CanaryCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	callbackDetailsJSON, ok := m["callback_details"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_details")
+	}
+
+	var details CanaryDetails
+	if err := json.Unmarshal([]byte(callbackDetailsJSON), &details); err != nil {
+		return err
+	}
+
+	c.Type = callbackType
+	c.Details = details
+	return nil
+}
+
+func (c *CanaryCallback) UnmarshalJSON(data []byte) error {
+	type Alias CanaryCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+// Invoke fires the run to the callback of its target. A mirrored run is fired to the baseline and a copy of it,
+// marked as a shadow, to the candidate. The outcome of the shadow is only recorded for the comparison
+func (c CanaryCallback) Invoke(wrapper ScheduleWrapper) error {
+	baseline, candidate, err := c.callbacks()
+	if err != nil {
+		return err
+	}
+
+	switch c.Details.Target {
+	case CanaryBaseline:
+		return pushCanary(wrapper, baseline, CanaryBaseline, false)
+	case CanaryCandidate:
+		return pushCanary(wrapper, candidate, CanaryCandidate, false)
+	default:
+		if err := pushCanary(wrapper, baseline, CanaryBaseline, false); err != nil {
+			return err
+		}
+		if err := pushCanary(wrapper, candidate, CanaryCandidate, true); err != nil {
+			wrapper.Schedule.Logger().Errorf("Mirroring schedule %s to the canary callback failed with error %s", wrapper.Schedule.ScheduleId, err.Error())
+		}
+		return nil
+	}
+}
+
+func pushCanary(wrapper ScheduleWrapper, callback *HttpCallback, target CanaryTarget, shadow bool) error {
+	wrapper.Schedule.Callback = callback
+	wrapper.Canary = target
+	wrapper.Shadow = shadow
+	return HttpTaskQueue.Push(wrapper)
+}
+
+func (c *CanaryCallback) Validate() error {
+	switch c.Details.Target {
+	case CanaryBaseline, CanaryCandidate, CanaryMirror:
+	default:
+		return fmt.Errorf("invalid canary target %s", c.Details.Target)
+	}
+
+	baseline, candidate, err := c.callbacks()
+	if err != nil {
+		return err
+	}
+	if err := baseline.Validate(); err != nil {
+		return fmt.Errorf("canary baseline: %w", err)
+	}
+	if err := candidate.Validate(); err != nil {
+		return fmt.Errorf("canary candidate: %w", err)
+	}
+	return nil
+}
+
+// callbacks returns the http callbacks of the baseline and the candidate
+func (c CanaryCallback) callbacks() (*HttpCallback, *HttpCallback, error) {
+	baseline := &HttpCallback{}
+	if err := json.Unmarshal(c.Details.Baseline, baseline); err != nil {
+		return nil, nil, fmt.Errorf("invalid canary baseline: %w", err)
+	}
+	candidate := &HttpCallback{}
+	if err := json.Unmarshal(c.Details.Candidate, candidate); err != nil {
+		return nil, nil, fmt.Errorf("invalid canary candidate: %w", err)
+	}
+	return baseline, candidate, nil
+}
+
+// CanaryResult is the outcome of a run of a callback canary fired to one of its callbacks
+type CanaryResult struct {
+	ScheduleId     gocql.UUID   `json:"scheduleId"`
+	RunId          gocql.UUID   `json:"runId"`
+	ScheduleTime   int64        `json:"scheduleTime"`
+	Target         CanaryTarget `json:"target"`
+	Status         Status       `json:"status"`
+	ResponseStatus int          `json:"responseStatus,omitempty"`
+	ErrorMessage   string       `json:"errorMessage,omitempty"`
+	LatencyMillis  int64        `json:"latencyMillis"`
+	FiredAt        int64        `json:"firedAt"`
+}
+
+type CanaryStats struct {
+	Fired            int   `json:"fired"`
+	Succeeded        int   `json:"succeeded"`
+	Failed           int   `json:"failed"`
+	AvgLatencyMillis int64 `json:"avgLatencyMillis"`
+}
+
+// CanaryComparison compares the outcomes of the runs fired to the previous and the updated callback of a canary.
+// Mismatches counts the mirrored runs which succeeded on one callback and failed on the other
+type CanaryComparison struct {
+	Baseline   CanaryStats `json:"baseline"`
+	Candidate  CanaryStats `json:"candidate"`
+	Mismatches int         `json:"mismatches"`
+}
+
+// CompareCanaryResults aggregates the results of a canary per callback
+func CompareCanaryResults(results []CanaryResult) CanaryComparison {
+	var comparison CanaryComparison
+	latencies := map[CanaryTarget]int64{}
+	outcomes := map[gocql.UUID]map[CanaryTarget]Status{}
+
+	for _, result := range results {
+		stats := &comparison.Baseline
+		if result.Target == CanaryCandidate {
+			stats = &comparison.Candidate
+		}
+
+		stats.Fired++
+		if result.Status == Success {
+			stats.Succeeded++
+		} else {
+			stats.Failed++
+		}
+		latencies[result.Target] += result.LatencyMillis
+
+		if outcomes[result.RunId] == nil {
+			outcomes[result.RunId] = map[CanaryTarget]Status{}
+		}
+		outcomes[result.RunId][result.Target] = result.Status
+	}
+
+	if comparison.Baseline.Fired > 0 {
+		comparison.Baseline.AvgLatencyMillis = latencies[CanaryBaseline] / int64(comparison.Baseline.Fired)
+	}
+	if comparison.Candidate.Fired > 0 {
+		comparison.Candidate.AvgLatencyMillis = latencies[CanaryCandidate] / int64(comparison.Candidate.Fired)
+	}
+
+	for _, outcome := range outcomes {
+		baseline, mirrored := outcome[CanaryBaseline]
+		candidate, ok := outcome[CanaryCandidate]
+		if mirrored && ok && baseline != candidate {
+			comparison.Mismatches++
+		}
+	}
+
+	return comparison
+}
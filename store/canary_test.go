@@ -0,0 +1,144 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+)
+
+func canaryHttpCallback(url string) *HttpCallback {
+	return &HttpCallback{Type: constants.DefaultCallback, Details: Details{Url: url, Method: "POST"}}
+}
+
+func TestNewCallbackCanary(t *testing.T) {
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), AppId: "app", Callback: canaryHttpCallback("http://127.0.0.1/new")}
+
+	for _, test := range []struct {
+		name     string
+		previous Callback
+		policy   CanaryPolicy
+		valid    bool
+	}{
+		{"mirror", canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{Runs: 3}, true},
+		{"split", canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{Runs: 3, Percent: 10}, true},
+		{"no runs", canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{}, false},
+		{"too many runs", canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{Runs: MaxCanaryRuns + 1}, false},
+		{"every run to the new callback", canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{Runs: 3, Percent: 100}, false},
+		{"same callback", canaryHttpCallback("http://127.0.0.1/new"), CanaryPolicy{Runs: 3}, false},
+		{"not http", &TemplateCallback{Type: constants.TemplateCallback}, CanaryPolicy{Runs: 3}, false},
+	} {
+		_, err := NewCallbackCanary(schedule, test.previous, test.policy, time.Now())
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestCallbackCanary_Applies(t *testing.T) {
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), AppId: "app", Callback: canaryHttpCallback("http://127.0.0.1/new")}
+	canary, err := NewCallbackCanary(schedule, canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{Runs: 2}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !canary.Applies(schedule) {
+		t.Error("expected the canary to apply to the updated schedule")
+	}
+
+	updated := schedule
+	updated.Callback = canaryHttpCallback("http://127.0.0.1/newer")
+	if canary.Applies(updated) {
+		t.Error("expected the canary not to apply once the callback is updated again")
+	}
+
+	canary.Next()
+	canary.Next()
+	if canary.Applies(schedule) {
+		t.Error("expected the canary not to apply once all its runs are fired")
+	}
+}
+
+func TestCallbackCanary_Next(t *testing.T) {
+	for _, test := range []struct {
+		percent  int
+		runs     int
+		expected int
+	}{
+		{0, 4, 0},
+		{10, 5, 1},
+		{50, 10, 5},
+		{25, 8, 2},
+	} {
+		canary := CallbackCanary{Runs: test.runs, Percent: test.percent}
+		candidates := 0
+		for i := 0; i < test.runs; i++ {
+			switch target := canary.Next(); target {
+			case CanaryCandidate:
+				candidates++
+			case CanaryMirror:
+				if test.percent != 0 {
+					t.Errorf("unexpected mirrored run with percent %d", test.percent)
+				}
+			}
+		}
+		if candidates != test.expected {
+			t.Errorf("percent %d of %d runs: expected %d runs to the candidate, got %d", test.percent, test.runs, test.expected, candidates)
+		}
+	}
+
+	canary := CallbackCanary{Runs: 5, Percent: 10}
+	if target := canary.Next(); target != CanaryCandidate {
+		t.Errorf("expected the first run to go to the candidate, got %s", target)
+	}
+}
+
+func TestCanaryCallback_Invoke(t *testing.T) {
+	queue := HttpTaskQueue
+	HttpTaskQueue = NewBoundedPriorityQueue(2)
+	defer func() { HttpTaskQueue = queue }()
+
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), AppId: "app", Callback: canaryHttpCallback("http://127.0.0.1/new")}
+	canary, err := NewCallbackCanary(schedule, canaryHttpCallback("http://127.0.0.1/old"), CanaryPolicy{Runs: 1}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := schedule.CloneAsOneTime(time.Now())
+	canary.Wrap(&run, canary.Next())
+	if err := run.Callback.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := run.Callback.Invoke(ScheduleWrapper{Schedule: run}); err != nil {
+		t.Fatal(err)
+	}
+
+	primary, shadow := HttpTaskQueue.Pop(), HttpTaskQueue.Pop()
+	if primary.Canary != CanaryBaseline || primary.Shadow || primary.Schedule.Callback.(*HttpCallback).Details.Url != "http://127.0.0.1/old" {
+		t.Errorf("expected the run to be fired to the baseline, got %+v", primary)
+	}
+	if shadow.Canary != CanaryCandidate || !shadow.Shadow || shadow.Schedule.Callback.(*HttpCallback).Details.Url != "http://127.0.0.1/new" {
+		t.Errorf("expected the run to be mirrored to the candidate, got %+v", shadow)
+	}
+}
+
+func TestCompareCanaryResults(t *testing.T) {
+	mirrored, matching, split := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	comparison := CompareCanaryResults([]CanaryResult{
+		{RunId: mirrored, Target: CanaryBaseline, Status: Success, LatencyMillis: 10},
+		{RunId: mirrored, Target: CanaryCandidate, Status: Failure, LatencyMillis: 30},
+		{RunId: matching, Target: CanaryBaseline, Status: Success, LatencyMillis: 20},
+		{RunId: matching, Target: CanaryCandidate, Status: Success, LatencyMillis: 10},
+		{RunId: split, Target: CanaryCandidate, Status: Failure, LatencyMillis: 20},
+	})
+
+	expected := CanaryComparison{
+		Baseline:   CanaryStats{Fired: 2, Succeeded: 2, AvgLatencyMillis: 15},
+		Candidate:  CanaryStats{Fired: 3, Succeeded: 1, Failed: 2, AvgLatencyMillis: 20},
+		Mismatches: 1,
+	}
+	if comparison != expected {
+		t.Errorf("expected %+v, got %+v", expected, comparison)
+	}
+}
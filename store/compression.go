@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// Codec compresses payloads, the encoding is used both to persist the payloads and as the Content-Encoding of callbacks
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// codecs holds the supported payload compressions by encoding
+var codecs = map[string]Codec{
+	Gzip: gzipCodec{},
+	Zstd: zstdCodec{},
+}
+
+// RegisterCodec adds or replaces the codec of an encoding, e.g. to support brotli.
+// Codecs must be registered before the scheduler is created.
+func RegisterCodec(encoding string, codec Codec) {
+	codecs[encoding] = codec
+}
+
+// GetCodec returns the codec of the supplied encoding
+func GetCodec(encoding string) (Codec, error) {
+	codec, ok := codecs[encoding]
+	if !ok {
+		return nil, fmt.Errorf("unsupported payload compression %s", encoding)
+	}
+	return codec, nil
+}
+
+// EncodePayload compresses the payload with the codec of the encoding.
+// The compressed payload is base64 encoded so that it can be stored as text.
+// An empty encoding returns the payload as is.
+func EncodePayload(payload string, encoding string) (string, error) {
+	if encoding == "" {
+		return payload, nil
+	}
+
+	codec, err := GetCodec(encoding)
+	if err != nil {
+		return "", err
+	}
+
+	compressed, err := codec.Compress([]byte(payload))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(compressed), nil
+}
+
+// DecodePayload reverses EncodePayload
+func DecodePayload(payload string, encoding string) (string, error) {
+	if encoding == "" {
+		return payload, nil
+	}
+
+	codec, err := GetCodec(encoding)
+	if err != nil {
+		return "", err
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := codec.Decompress(compressed)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// zstdEncoder and zstdDecoder are shared by all the payloads, EncodeAll and DecodeAll are safe for concurrent use
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+type zstdCodec struct{}
+
+func (zstdCodec) Compress(data []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+func (zstdCodec) Decompress(data []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(data, nil)
+}
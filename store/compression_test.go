@@ -0,0 +1,59 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodePayload(t *testing.T) {
+	payload := "{\"items\":[" + strings.Repeat("{\"sku\":\"1234\",\"quantity\":1},", 100) + "{}]}"
+
+	for _, encoding := range []string{Gzip, Zstd} {
+		encoded, err := EncodePayload(payload, encoding)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", encoding, err)
+		}
+		if len(encoded) >= len(payload) {
+			t.Errorf("%s: expected encoded payload of %d bytes to be smaller than %d bytes", encoding, len(encoded), len(payload))
+		}
+
+		decoded, err := DecodePayload(encoded, encoding)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", encoding, err)
+		}
+		if decoded != payload {
+			t.Errorf("%s: expected decoded payload to match the original", encoding)
+		}
+	}
+
+	if encoded, _ := EncodePayload(payload, ""); encoded != payload {
+		t.Errorf("Expected payload to be unchanged without encoding")
+	}
+
+	if _, err := EncodePayload(payload, "br"); err == nil {
+		t.Errorf("Expected error for unregistered encoding")
+	}
+
+	if _, err := DecodePayload("not base64!", Gzip); err == nil {
+		t.Errorf("Expected error for corrupt payload")
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/util"
+)
+
+// ConcurrencyPolicy decides what happens to a run of a recurring schedule due while its previous run is still running.
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrentRuns fires the run alongside the previous one, this is the default
+	AllowConcurrentRuns ConcurrencyPolicy = "allow"
+	// ForbidConcurrentRuns skips the run, recording it as skipped
+	ForbidConcurrentRuns ConcurrencyPolicy = "forbid"
+	// ReplaceConcurrentRuns cancels the callback of the previous run and fires the new one
+	ReplaceConcurrentRuns ConcurrencyPolicy = "replace"
+)
+
+// ErrReplaced is the error of a callback cancelled as a newer run of its schedule replaced it
+var ErrReplaced = errors.New("replaced by a newer run of the schedule")
+
+// validateConcurrencyPolicy checks that the optional concurrency policy is one of the supported policies, and that
+// a policy other than allow is only set with a callback it can be applied to
+func validateConcurrencyPolicy(policy ConcurrencyPolicy, callback Callback) string {
+	switch policy {
+	case "", AllowConcurrentRuns:
+		return ""
+	case ForbidConcurrentRuns, ReplaceConcurrentRuns:
+		if !SupportsConcurrencyPolicy(callback) {
+			return fmt.Sprintf("concurrencyPolicy: %s is only supported with an http callback", policy)
+		}
+		return ""
+	default:
+		return fmt.Sprintf("invalid concurrencyPolicy: %s, must be one of allow, forbid or replace", policy)
+	}
+}
+
+// SupportsConcurrencyPolicy tells whether the concurrency policy of a recurring schedule can be applied to the runs of
+// its callback. Only an http callback records its run as executing, so that a replaced run can be cancelled.
+func SupportsConcurrencyPolicy(callback Callback) bool {
+	_, ok := callback.(*HttpCallback)
+	return ok
+}
+
+// runningRun is the run of a recurring schedule fired by the node and not completed yet
+type runningRun struct {
+	runId  gocql.UUID
+	cancel context.CancelFunc
+}
+
+// RunningRuns tracks the running runs of the recurring schedules fired by the node, the latest one per schedule.
+// The runs of a schedule all belong to its partition, so they are all fired by the node owning it.
+type RunningRuns struct {
+	mu       sync.Mutex
+	runs     map[gocql.UUID]*runningRun
+	replaced map[gocql.UUID]bool
+}
+
+var runningRuns = NewRunningRuns()
+
+// Running returns the runs running on the node
+func Running() *RunningRuns {
+	return runningRuns
+}
+
+func NewRunningRuns() *RunningRuns {
+	return &RunningRuns{
+		runs:     map[gocql.UUID]*runningRun{},
+		replaced: map[gocql.UUID]bool{},
+	}
+}
+
+// Previous returns the run of the recurring schedule of the run which is still running
+func (r *RunningRuns) Previous(run Schedule) (gocql.UUID, bool) {
+	if util.IsZeroUUID(run.ParentScheduleId) {
+		return gocql.UUID{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	running, ok := r.runs[run.ParentScheduleId]
+	if !ok {
+		return gocql.UUID{}, false
+	}
+	return running.runId, true
+}
+
+// Start records the run as the running run of its recurring schedule
+func (r *RunningRuns) Start(run Schedule) {
+	if util.IsZeroUUID(run.ParentScheduleId) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[run.ParentScheduleId] = &runningRun{runId: run.ScheduleId}
+}
+
+// Replace cancels the running run of the recurring schedule of the run, right away if its callback is executing or
+// as soon as it starts otherwise
+func (r *RunningRuns) Replace(run Schedule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	running, ok := r.runs[run.ParentScheduleId]
+	if !ok {
+		return
+	}
+
+	delete(r.runs, run.ParentScheduleId)
+	if running.cancel != nil {
+		running.cancel()
+	} else {
+		r.replaced[running.runId] = true
+	}
+}
+
+// Executing records the cancellation of the callback of the run, which is cancelled right away if the run was
+// replaced while waiting for a callback worker
+func (r *RunningRuns) Executing(run Schedule, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replaced[run.ScheduleId] {
+		delete(r.replaced, run.ScheduleId)
+		cancel()
+		return
+	}
+	if running, ok := r.runs[run.ParentScheduleId]; ok && running.runId == run.ScheduleId {
+		running.cancel = cancel
+	}
+}
+
+// Finish forgets the run once completed, unless a newer run of its schedule already took its place
+func (r *RunningRuns) Finish(run Schedule) {
+	if util.IsZeroUUID(run.ParentScheduleId) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.replaced, run.ScheduleId)
+	if running, ok := r.runs[run.ParentScheduleId]; ok && running.runId == run.ScheduleId {
+		delete(r.runs, run.ParentScheduleId)
+	}
+}
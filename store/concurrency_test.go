@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestValidateConcurrencyPolicy(t *testing.T) {
+	for _, policy := range []ConcurrencyPolicy{"", AllowConcurrentRuns, ForbidConcurrentRuns, ReplaceConcurrentRuns} {
+		if message := validateConcurrencyPolicy(policy, &HttpCallback{}); message != "" {
+			t.Errorf("Expected %q to be valid, got %s", policy, message)
+		}
+	}
+	if message := validateConcurrencyPolicy("queue", &HttpCallback{}); message == "" {
+		t.Errorf("Expected queue to be invalid")
+	}
+
+	// the runs of other callbacks are not tracked, so only allow applies to them
+	for _, policy := range []ConcurrencyPolicy{"", AllowConcurrentRuns} {
+		if message := validateConcurrencyPolicy(policy, &PullCallback{}); message != "" {
+			t.Errorf("Expected %q to be valid with a pull callback, got %s", policy, message)
+		}
+	}
+	for _, policy := range []ConcurrencyPolicy{ForbidConcurrentRuns, ReplaceConcurrentRuns} {
+		if message := validateConcurrencyPolicy(policy, &PullCallback{}); message == "" {
+			t.Errorf("Expected %q to be rejected with a pull callback", policy)
+		}
+	}
+}
+
+func TestRunningRuns(t *testing.T) {
+	running := NewRunningRuns()
+	parent := gocql.TimeUUID()
+	first := Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: parent}
+	second := Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: parent}
+
+	if _, ok := running.Previous(Schedule{ScheduleId: gocql.TimeUUID()}); ok {
+		t.Errorf("Expected one time schedules to have no previous run")
+	}
+
+	running.Start(first)
+	if previous, ok := running.Previous(second); !ok || previous != first.ScheduleId {
+		t.Fatalf("Expected the first run to be running, got %v %v", previous, ok)
+	}
+
+	// the first run is replaced while waiting for a worker, it is cancelled as soon as it executes
+	running.Replace(second)
+	running.Start(second)
+	ctx, cancel := context.WithCancel(context.Background())
+	running.Executing(first, cancel)
+	if ctx.Err() == nil {
+		t.Errorf("Expected the replaced run to be cancelled when it executes")
+	}
+	running.Finish(first)
+	if previous, ok := running.Previous(first); !ok || previous != second.ScheduleId {
+		t.Errorf("Expected the completion of the replaced run to keep the second run, got %v %v", previous, ok)
+	}
+
+	// the second run is replaced while executing
+	ctx, cancel = context.WithCancel(context.Background())
+	running.Executing(second, cancel)
+	running.Replace(Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: parent})
+	if ctx.Err() == nil {
+		t.Errorf("Expected the executing run to be cancelled")
+	}
+
+	running.Finish(second)
+	if _, ok := running.Previous(second); ok {
+		t.Errorf("Expected no run to be running")
+	}
+}
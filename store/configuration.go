@@ -6,4 +6,40 @@ type Configuration struct {
 	PayloadSize                  int `json:"payloadSize,omitempty"`
 	HttpRetries                  int `json:"httpRetries,omitempty"`
 	HttpTimeout                  int `json:"httpTimeout,omitempty"`
+	// Days deleted recurring schedules are kept for before they are purged
+	DeletedScheduleRetentionPeriod int `json:"deletedScheduleRetentionPeriod,omitempty"`
+	// Compression of the payloads at rest, empty for uncompressed payloads
+	PayloadCompression string `json:"payloadCompression,omitempty"`
+	// Deliver http callbacks with the payload compressed and the Content-Encoding header set
+	CompressCallbacks bool `json:"compressCallbacks,omitempty"`
+	// Client tuning of the http callbacks, nil to share the default callback client
+	HttpTransport *HttpTransport `json:"httpTransport,omitempty"`
+	// Windows during which the callbacks of the app do not fire, on top of the global ones
+	Blackout *Blackout `json:"blackout,omitempty"`
+	// Only accept the http callback and status callback urls which passed the verification handshake
+	VerifyCallbackUrls bool `json:"verifyCallbackUrls,omitempty"`
+	// What happens to the callbacks of the app shed under overload, overriding the policy of the cluster
+	LoadShedding ShedPolicy `json:"loadShedding,omitempty"`
+	// Headers added to every http callback of the app, behind a pointer to keep the configuration comparable
+	CallbackHeaders *CallbackHeaders `json:"callbackHeaders,omitempty"`
+	// Fields and patterns masked in the callback bodies before they are logged or kept with the runs
+	Redaction *Redaction `json:"redaction,omitempty"`
+	// Outcomes of the http callbacks retried and the backoffs between the attempts, nil to retry every failure
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Deliver the http callbacks due together for the same target in a single request, nil to deliver them one by one
+	CallbackBatching *CallbackBatching `json:"callbackBatching,omitempty"`
+	// Timeout and failure policy of the governance hook for the app, nil to use the ones of the cluster
+	Governance *Governance `json:"governance,omitempty"`
+	// Region the callbacks of the schedules of the app are delivered from, the region of the cluster if empty
+	Region string `json:"region,omitempty"`
+	// Google Cloud project and credentials the pubsub callbacks of the app publish with
+	PubSub *PubSubConnection `json:"pubSub,omitempty"`
+	// Azure Service Bus namespace and shared access policy the servicebus callbacks of the app send with
+	ServiceBus *ServiceBusConnection `json:"serviceBus,omitempty"`
+	// Alert, and optionally pause, the recurring schedules of the app whose runs fail a number of times in a row
+	FailureNotification *FailureNotification `json:"failureNotification,omitempty"`
+	// Delete, or alert on, the recurring schedules of the app paused for longer than a number of days
+	StalePause *StalePausePolicy `json:"stalePause,omitempty"`
+	// Flag of the flag provider of the cluster turning off the firing of the schedules of the app while it is false
+	FeatureFlag string `json:"featureFlag,omitempty"`
 }
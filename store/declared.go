@@ -0,0 +1,154 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/gocql/gocql"
+)
+
+// declaredNamespace is the namespace of the name based ids of the declared schedules, it must never change as the ids
+// of the declared schedules are derived from it
+var declaredNamespace = gocql.UUID{0x6f, 0x1d, 0x3a, 0x52, 0x8c, 0x4e, 0x4b, 0x7a, 0x9d, 0x21, 0x5e, 0x0b, 0x3c, 0x77, 0xa4, 0x19}
+
+// declaredNamePattern restricts the names of the declared schedules to the characters of a Terraform resource name
+var declaredNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// DeclaredSchedule is a recurring schedule of an app managed declaratively, identified by its name. Its id is derived
+// from its app and its name, so that declaring the same name again always designates the same schedule.
+type DeclaredSchedule struct {
+	Name string `json:"name"`
+	Schedule
+}
+
+// UnmarshalJSON reads the name along with the schedule, whose own unmarshalling would otherwise hide it
+func (d *DeclaredSchedule) UnmarshalJSON(data []byte) error {
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return err
+	}
+	d.Name = named.Name
+	return d.Schedule.UnmarshalJSON(data)
+}
+
+// DeclaredScheduleId returns the id of the declared schedule of an app with the given name, a version 5 uuid
+func DeclaredScheduleId(appId, name string) gocql.UUID {
+	h := sha1.New()
+	h.Write(declaredNamespace[:])
+	h.Write([]byte(appId + "/" + name))
+
+	var id gocql.UUID
+	copy(id[:], h.Sum(nil))
+	id[6] = (id[6] & 0x0f) | 0x50
+	id[8] = (id[8] & 0x3f) | 0x80
+	return id
+}
+
+// IsDeclared tells whether the schedule was created declaratively, its id being name based rather than time based
+func (s Schedule) IsDeclared() bool {
+	return s.ScheduleId.Version() == 5
+}
+
+// ValidateDeclared checks that the declared schedules of an app are recurring schedules of the app with unique names
+func ValidateDeclared(appId string, declared []DeclaredSchedule) error {
+	names := make(map[string]bool, len(declared))
+	for _, d := range declared {
+		switch {
+		case !declaredNamePattern.MatchString(d.Name):
+			return fmt.Errorf("invalid name '%s', must be 1 to 128 letters, digits, '.', '_' or '-'", d.Name)
+		case names[d.Name]:
+			return fmt.Errorf("name '%s' is declared more than once", d.Name)
+		case d.AppId != "" && d.AppId != appId:
+			return fmt.Errorf("schedule '%s' belongs to app %s, not %s", d.Name, d.AppId, appId)
+		case !d.IsRecurring():
+			return fmt.Errorf("schedule '%s' is not recurring, one of 'cronExpression', 'every' or 'rrule' is required", d.Name)
+		case d.IsDraft():
+			return fmt.Errorf("schedule '%s' can't be declared as DRAFT", d.Name)
+		}
+		names[d.Name] = true
+	}
+	return nil
+}
+
+// definition is the part of a recurring schedule a declaration sets
+type definition struct {
+	Payload           string            `json:"payload"`
+	Callback          json.RawMessage   `json:"callback"`
+	CronExpression    string            `json:"cronExpression"`
+	Every             string            `json:"every"`
+	RRule             string            `json:"rrule"`
+	Anchor            int64             `json:"anchor"`
+	StatusCallback    string            `json:"statusCallback"`
+	Priority          Priority          `json:"priority"`
+	PausePolicy       PausePolicy       `json:"pausePolicy"`
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy"`
+	Group             string            `json:"group"`
+	KeepPaused        bool              `json:"keepPaused"`
+	Region            string            `json:"region"`
+}
+
+// SameDefinition tells whether the declared schedule defines the existing schedule as it is. An anchor left out of the
+// declaration keeps the anchor of the existing schedule.
+func (d DeclaredSchedule) SameDefinition(existing Schedule) (bool, error) {
+	declared := d.Schedule
+	if declared.Anchor == 0 {
+		declared.Anchor = existing.Anchor
+	}
+
+	a, err := declared.definition()
+	if err != nil {
+		return false, err
+	}
+	b, err := existing.definition()
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(a, b), nil
+}
+
+// definition returns the json of the definition of the schedule
+func (s Schedule) definition() ([]byte, error) {
+	callback, err := json.Marshal(s.Callback)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(definition{
+		Payload:           s.Payload,
+		Callback:          callback,
+		CronExpression:    s.CronExpression,
+		Every:             s.Every,
+		RRule:             s.RRule,
+		Anchor:            s.Anchor,
+		StatusCallback:    s.StatusCallback,
+		Priority:          s.Priority,
+		PausePolicy:       s.PausePolicy,
+		ConcurrencyPolicy: s.ConcurrencyPolicy,
+		Group:             s.Group,
+		KeepPaused:        s.KeepPaused,
+		Region:            s.Region,
+	})
+}
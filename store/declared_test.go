@@ -0,0 +1,76 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestDeclaredScheduleId(t *testing.T) {
+	id := DeclaredScheduleId("test", "nightly")
+	if id != DeclaredScheduleId("test", "nightly") {
+		t.Errorf("expected the id of a name to be stable")
+	}
+	if id == DeclaredScheduleId("other", "nightly") || id == DeclaredScheduleId("test", "hourly") {
+		t.Errorf("expected the ids of different apps and names to differ")
+	}
+	if !(Schedule{ScheduleId: id}).IsDeclared() {
+		t.Errorf("expected %s to be a declared id", id)
+	}
+	if (Schedule{ScheduleId: gocql.TimeUUID()}).IsDeclared() {
+		t.Errorf("expected a time based id not to be a declared id")
+	}
+}
+
+func TestDeclaredSchedule_UnmarshalJSON(t *testing.T) {
+	Registry["http"] = func() Callback { return &HttpCallback{} }
+
+	var d DeclaredSchedule
+	body := `{"name": "nightly", "cronExpression": "0 2 * * *", "payload": "{}", "callback": {"type": "http", "details": {"url": "http://localhost", "method": "POST"}}}`
+	if err := json.Unmarshal([]byte(body), &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "nightly" || d.CronExpression != "0 2 * * *" || d.Callback == nil {
+		t.Errorf("expected the name and the schedule, got %+v", d)
+	}
+}
+
+func TestDeclaredSchedule_SameDefinition(t *testing.T) {
+	existing := Schedule{Payload: "{}", Every: "1h", Anchor: 1686820560, Status: Scheduled}
+
+	for _, test := range []struct {
+		name     string
+		declared Schedule
+		same     bool
+	}{
+		{"anchor left out", Schedule{Payload: "{}", Every: "1h"}, true},
+		{"same anchor", Schedule{Payload: "{}", Every: "1h", Anchor: 1686820560}, true},
+		{"other anchor", Schedule{Payload: "{}", Every: "1h", Anchor: 1686820620}, false},
+		{"other payload", Schedule{Payload: `{"a": 1}`, Every: "1h"}, false},
+		{"other recurrence", Schedule{Payload: "{}", Every: "2h"}, false},
+	} {
+		same, err := DeclaredSchedule{Name: "a", Schedule: test.declared}.SameDefinition(existing)
+		if err != nil || same != test.same {
+			t.Errorf("%s: expected same to be %v, got %v, %v", test.name, test.same, same, err)
+		}
+	}
+}
+
+func TestValidateDeclared(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		declared []DeclaredSchedule
+		valid    bool
+	}{
+		{"valid", []DeclaredSchedule{{Name: "nightly-report.v2", Schedule: Schedule{CronExpression: "0 2 * * *"}}}, true},
+		{"empty name", []DeclaredSchedule{{Schedule: Schedule{CronExpression: "0 2 * * *"}}}, false},
+		{"invalid name", []DeclaredSchedule{{Name: "nightly report", Schedule: Schedule{CronExpression: "0 2 * * *"}}}, false},
+		{"other app", []DeclaredSchedule{{Name: "a", Schedule: Schedule{AppId: "other", CronExpression: "0 2 * * *"}}}, false},
+		{"draft", []DeclaredSchedule{{Name: "a", Schedule: Schedule{CronExpression: "0 2 * * *", Status: Draft}}}, false},
+	} {
+		if err := ValidateDeclared("test", test.declared); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid to be %v, got %v", test.name, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// ErrEgressDenied is the error of the callback destinations outside the egress policy of the cluster
+var ErrEgressDenied = errors.New("egress denied")
+
+// egressPolicy is the egress policy of the callbacks of the node, nil when egress is unrestricted
+var egressPolicy struct {
+	sync.RWMutex
+	policy *EgressPolicy
+}
+
+// SetEgressPolicy sets the egress policy applied to the callbacks of the node, nil lifts the restrictions
+func SetEgressPolicy(policy *EgressPolicy) {
+	egressPolicy.Lock()
+	defer egressPolicy.Unlock()
+	egressPolicy.policy = policy
+}
+
+// Egress returns the egress policy applied to the callbacks of the node, nil when egress is unrestricted.
+// The methods of the policy can be called on nil, which allows every destination.
+func Egress() *EgressPolicy {
+	egressPolicy.RLock()
+	defer egressPolicy.RUnlock()
+	return egressPolicy.policy
+}
+
+// EgressPolicy restricts the destinations of the callbacks. A destination is allowed when none of its addresses is
+// in a denied range and either its host is an allowed domain or all its addresses are in allowed ranges. Without any
+// allowed domain or range only the denied ranges apply.
+type EgressPolicy struct {
+	allowedNets    []*net.IPNet
+	deniedNets     []*net.IPNet
+	allowedDomains []string
+	lookup         func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewEgressPolicy builds the egress policy of the configuration, nil when the policy is disabled
+func NewEgressPolicy(config conf.EgressConfig) (*EgressPolicy, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	allowed, err := parseCIDRs(config.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	denied, err := parseCIDRs(config.DeniedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	var domains []string
+	for _, domain := range config.AllowedDomains {
+		domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+		if domain == "" || domain == "*" || strings.Contains(strings.TrimPrefix(domain, "*."), "*") {
+			return nil, fmt.Errorf("invalid allowed domain: %q, must be a domain or *.<domain>", domain)
+		}
+		domains = append(domains, domain)
+	}
+
+	return &EgressPolicy{
+		allowedNets:    allowed,
+		deniedNets:     denied,
+		allowedDomains: domains,
+		lookup:         net.DefaultResolver.LookupIPAddr,
+	}, nil
+}
+
+// parseCIDRs parses the ranges of the policy, a plain address is a range of its own
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		if ip := net.ParseIP(cidr); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress range: %s", cidr)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// allowsDomain checks whether the host is an allowed domain, *.<domain> allows the subdomains of the domain
+func (p *EgressPolicy) allowsDomain(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range p.allowedDomains {
+		if strings.HasPrefix(domain, "*.") {
+			if strings.HasSuffix(host, domain[1:]) {
+				return true
+			}
+		} else if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// contains checks whether the address is in any of the ranges
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve resolves the host and returns its addresses once the policy allows them.
+// The addresses of an unrestricted policy are not resolved.
+func (p *EgressPolicy) Resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var ips []net.IP
+	domainAllowed := false
+	if ip := net.ParseIP(host); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		addrs, err := p.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+		domainAllowed = p.allowsDomain(host)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no address found for %s", host)
+	}
+
+	restricted := len(p.allowedNets) > 0 || len(p.allowedDomains) > 0
+	for _, ip := range ips {
+		switch {
+		case contains(p.deniedNets, ip):
+			return nil, fmt.Errorf("%w: %s resolves to the denied address %s", ErrEgressDenied, host, ip)
+		case restricted && !domainAllowed && !contains(p.allowedNets, ip):
+			return nil, fmt.Errorf("%w: %s is not an allowed domain and resolves to %s outside the allowed ranges", ErrEgressDenied, host, ip)
+		}
+	}
+	return ips, nil
+}
+
+// CheckUrl checks that the policy allows the host of the url
+func (p *EgressPolicy) CheckUrl(ctx context.Context, rawUrl string) error {
+	if p == nil {
+		return nil
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+	_, err = p.Resolve(ctx, u.Hostname())
+	return err
+}
+
+// DialContext dials one of the addresses of the host allowed by the policy. The checked addresses are dialed, so
+// the host cannot resolve to another address between the check and the connection.
+func (p *EgressPolicy) DialContext(dialer *net.Dialer) func(ctx context.Context, network string, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := p.Resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ip := range ips {
+			var conn net.Conn
+			if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// Transport applies the policy to a copy of the transport. The addresses dialed by the transport are checked, which
+// include the address of its proxy, and the requests sent through a proxy are checked on their url as well, in which
+// case the proxy resolves the host again. The transport itself is returned by an unrestricted policy.
+func (p *EgressPolicy) Transport(base *http.Transport) http.RoundTripper {
+	if p == nil {
+		return base
+	}
+
+	transport := base.Clone()
+	transport.DialContext = p.DialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	if transport.Proxy == nil {
+		return transport
+	}
+	return egressRoundTripper{policy: p, next: transport}
+}
+
+// egressRoundTripper checks the url of the requests sent through a proxy
+type egressRoundTripper struct {
+	policy *EgressPolicy
+	next   *http.Transport
+}
+
+func (e egressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy, err := e.next.Proxy(req)
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		if _, err = e.policy.Resolve(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	return e.next.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport when its client is asked to
+func (e egressRoundTripper) CloseIdleConnections() {
+	e.next.CloseIdleConnections()
+}
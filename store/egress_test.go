@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func newTestEgressPolicy(t *testing.T, config conf.EgressConfig) *EgressPolicy {
+	config.Enabled = true
+	policy, err := NewEgressPolicy(config)
+	if err != nil {
+		t.Fatalf("Unexpected error building the policy: %v", err)
+	}
+	policy.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch strings.ToLower(strings.TrimSuffix(host, ".")) {
+		case "api.partner.com", "partner.com":
+			return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("10.1.2.3")}}, nil
+		case "rebound.partner.com":
+			return []net.IPAddr{{IP: net.ParseIP("169.254.169.254")}}, nil
+		case "mixed.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("10.1.2.3")}, {IP: net.ParseIP("192.0.2.1")}}, nil
+		default:
+			return nil, errors.New("no such host")
+		}
+	}
+	return policy
+}
+
+func TestNewEgressPolicy(t *testing.T) {
+	if policy, err := NewEgressPolicy(conf.EgressConfig{AllowedCIDRs: []string{"bad"}}); policy != nil || err != nil {
+		t.Errorf("Expected no policy when disabled, got %v and %v", policy, err)
+	}
+
+	for _, config := range []conf.EgressConfig{
+		{Enabled: true, AllowedCIDRs: []string{"10.0.0.0/33"}},
+		{Enabled: true, DeniedCIDRs: []string{"localhost"}},
+		{Enabled: true, AllowedDomains: []string{"*"}},
+		{Enabled: true, AllowedDomains: []string{"api.*.com"}},
+		{Enabled: true, AllowedDomains: []string{" "}},
+	} {
+		if _, err := NewEgressPolicy(config); err == nil {
+			t.Errorf("Expected %+v to be invalid", config)
+		}
+	}
+}
+
+func TestEgressPolicy_Resolve(t *testing.T) {
+	policy := newTestEgressPolicy(t, conf.EgressConfig{
+		AllowedCIDRs:   []string{"10.0.0.0/8", "198.51.100.7"},
+		AllowedDomains: []string{"*.partner.com"},
+		DeniedCIDRs:    []string{"169.254.0.0/16"},
+	})
+
+	for _, test := range []struct {
+		host    string
+		allowed bool
+	}{
+		{"api.partner.com", true},
+		{"API.Partner.com.", true},
+		{"partner.com", false},
+		{"internal.example.com", true},
+		{"mixed.example.com", false},
+		{"rebound.partner.com", false},
+		{"10.0.0.1", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"169.254.169.254", false},
+		{"::ffff:10.0.0.1", true},
+		{"unknown.example.com", false},
+	} {
+		_, err := policy.Resolve(context.Background(), test.host)
+		if (err == nil) != test.allowed {
+			t.Errorf("%s: expected allowed %v, got %v", test.host, test.allowed, err)
+		}
+	}
+
+	denyOnly := newTestEgressPolicy(t, conf.EgressConfig{DeniedCIDRs: []string{"169.254.0.0/16"}})
+	if _, err := denyOnly.Resolve(context.Background(), "192.0.2.1"); err != nil {
+		t.Errorf("Expected the addresses outside the denied ranges to be allowed without allow lists, got %v", err)
+	}
+	if err := denyOnly.CheckUrl(context.Background(), "http://169.254.169.254/latest/meta-data"); !errors.Is(err, ErrEgressDenied) {
+		t.Errorf("Expected the metadata address to be denied, got %v", err)
+	}
+
+	var unrestricted *EgressPolicy
+	if err := unrestricted.CheckUrl(context.Background(), "http://169.254.169.254/"); err != nil {
+		t.Errorf("Expected an unrestricted policy to allow every url, got %v", err)
+	}
+}
+
+func TestEgressPolicy_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		name    string
+		config  conf.EgressConfig
+		proxy   bool
+		allowed bool
+	}{
+		{"allowed", conf.EgressConfig{AllowedCIDRs: []string{"127.0.0.0/8"}}, false, true},
+		{"denied", conf.EgressConfig{DeniedCIDRs: []string{"127.0.0.0/8"}}, false, false},
+		{"outside", conf.EgressConfig{AllowedCIDRs: []string{"10.0.0.0/8"}}, false, false},
+		{"proxied target denied", conf.EgressConfig{AllowedCIDRs: []string{"127.0.0.0/8"}, DeniedCIDRs: []string{"169.254.0.0/16"}}, true, false},
+	} {
+		policy := newTestEgressPolicy(t, test.config)
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.Proxy = nil
+		target := server.URL
+		if test.proxy {
+			proxy, _ := url.Parse(server.URL)
+			base.Proxy = http.ProxyURL(proxy)
+			target = "http://169.254.169.254/latest/meta-data"
+		}
+
+		client := &http.Client{Transport: policy.Transport(base)}
+		response, err := client.Get(target)
+		if err == nil {
+			response.Body.Close()
+		}
+		if (err == nil) != test.allowed || (err != nil && !errors.Is(err, ErrEgressDenied)) {
+			t.Errorf("%s: expected allowed %v, got %v", test.name, test.allowed, err)
+		}
+	}
+
+	var unrestricted *EgressPolicy
+	base := http.DefaultTransport.(*http.Transport)
+	if unrestricted.Transport(base) != base {
+		t.Errorf("Expected an unrestricted policy to keep the transport")
+	}
+}
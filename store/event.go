@@ -0,0 +1,130 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/logger"
+	"github.com/myntra/goscheduler/util"
+)
+
+// EventType is the type of lifecycle change of a schedule
+type EventType string
+
+const (
+	ScheduleCreated   EventType = "schedule.created"
+	ScheduleUpdated   EventType = "schedule.updated"
+	SchedulePaused    EventType = "schedule.paused"
+	ScheduleResumed   EventType = "schedule.resumed"
+	ScheduleActivated EventType = "schedule.activated"
+	ScheduleDeleted   EventType = "schedule.deleted"
+	ScheduleFired     EventType = "schedule.fired"
+	ScheduleFailed    EventType = "schedule.failed"
+	ScheduleShed      EventType = "schedule.shed"
+	// ScheduleDeadLettered follows schedule.failed for a run whose failure is final, once its retries ran out or when
+	// the failure is not retried
+	ScheduleDeadLettered EventType = "schedule.dead_lettered"
+	// ScheduleSuspended is published for a recurring schedule whose callback target stayed unreachable, the error of
+	// its last run is carried in the event
+	ScheduleSuspended EventType = "schedule.suspended"
+	// ScheduleStale is published for a recurring schedule paused for longer than the stale pause policy of its app
+	ScheduleStale EventType = "schedule.stale"
+)
+
+// EventVersion is the version of the event envelope, bumped on incompatible changes
+const EventVersion = 1
+
+// Event is the envelope in which the lifecycle changes of schedules are published
+type Event struct {
+	EventId          string    `json:"eventId"`
+	Type             EventType `json:"type"`
+	Version          int       `json:"version"`
+	OccurredAt       int64     `json:"occurredAt"`
+	AppId            string    `json:"appId"`
+	ScheduleId       string    `json:"scheduleId"`
+	ParentScheduleId string    `json:"parentScheduleId,omitempty"`
+	TraceId          string    `json:"traceId,omitempty"`
+	Status           Status    `json:"status,omitempty"`
+	ErrorMessage     string    `json:"errorMessage,omitempty"`
+	Schedule         *Schedule `json:"schedule,omitempty"`
+}
+
+// NewEvent creates an event of the supplied type for a schedule.
+// The schedule is embedded in the event for every type other than fired, failed and dead-lettered runs.
+func NewEvent(eventType EventType, schedule Schedule) Event {
+	event := Event{
+		EventId:      gocql.TimeUUID().String(),
+		Type:         eventType,
+		Version:      EventVersion,
+		OccurredAt:   time.Now().UnixNano() / int64(time.Millisecond),
+		AppId:        schedule.AppId,
+		ScheduleId:   schedule.ScheduleId.String(),
+		TraceId:      schedule.TraceId,
+		Status:       schedule.Status,
+		ErrorMessage: schedule.ErrorMessage,
+	}
+
+	if !util.IsZeroUUID(schedule.ParentScheduleId) {
+		event.ParentScheduleId = schedule.ParentScheduleId.String()
+	}
+
+	if eventType != ScheduleFired && eventType != ScheduleFailed && eventType != ScheduleDeadLettered {
+		event.Schedule = &schedule
+	}
+
+	return event
+}
+
+var eventDropRecorder struct {
+	sync.RWMutex
+	record func(EventType)
+}
+
+// SetEventDropRecorder sets the function counting the events dropped because the queue was full
+func SetEventDropRecorder(record func(EventType)) {
+	eventDropRecorder.Lock()
+	defer eventDropRecorder.Unlock()
+	eventDropRecorder.record = record
+}
+
+// PublishEvent queues a lifecycle event of the schedule for publishing, and streams the fire events to the clients
+// subscribed to the app on this node.
+// Events are best effort, the event is dropped and counted if the queue is full or not initialised so that requests
+// are never blocked.
+func PublishEvent(eventType EventType, schedule Schedule) {
+	event := NewEvent(eventType, schedule)
+	if IsFireEvent(eventType) {
+		FireEventStream.Broadcast(event)
+	}
+
+	select {
+	case EventTaskQueue <- event:
+	default:
+		logger.Errorf("Event queue full, dropping event %s for schedule id %s", eventType, schedule.ScheduleId.String())
+		eventDropRecorder.RLock()
+		defer eventDropRecorder.RUnlock()
+		if eventDropRecorder.record != nil {
+			eventDropRecorder.record(eventType)
+		}
+	}
+}
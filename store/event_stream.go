@@ -0,0 +1,166 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrTooManySubscribers is returned when the node already streams events to as many clients as it allows
+var ErrTooManySubscribers = errors.New("too many clients are streaming events from this node")
+
+// FireEventStream fans the fire events of the runs of this node out to the clients streaming them
+var FireEventStream *EventStream
+
+// fireEventTypes are the types of the events streamed to the clients, one for every run fired
+var fireEventTypes = []EventType{ScheduleFired, ScheduleFailed, ScheduleDeadLettered}
+
+// IsFireEvent tells whether the event is about a fired run of a schedule
+func IsFireEvent(eventType EventType) bool {
+	for _, fireEventType := range fireEventTypes {
+		if eventType == fireEventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFireEventTypes parses a comma separated list of fire event types, all of them for an empty list
+func ParseFireEventTypes(param string) ([]EventType, error) {
+	if strings.TrimSpace(param) == "" {
+		return fireEventTypes, nil
+	}
+
+	var types []EventType
+	for _, value := range strings.Split(param, ",") {
+		eventType := EventType(strings.TrimSpace(value))
+		if !IsFireEvent(eventType) {
+			return nil, fmt.Errorf("unsupported event type %s, expected one of %s, %s or %s", eventType, ScheduleFired, ScheduleFailed, ScheduleDeadLettered)
+		}
+		types = append(types, eventType)
+	}
+	return types, nil
+}
+
+// EventSubscription is a client streaming the events of an app
+type EventSubscription struct {
+	dropped int64
+	AppId   string
+	Events  chan Event
+	types   map[EventType]bool
+}
+
+// Dropped returns the number of events dropped for the client since the last call, as it was falling behind
+func (s *EventSubscription) Dropped() int64 {
+	return atomic.SwapInt64(&s.dropped, 0)
+}
+
+// EventStream keeps the clients streaming the events of every app.
+// Events are best effort, a client whose buffer is full misses the event rather than slowing the callbacks down.
+type EventStream struct {
+	mu             sync.RWMutex
+	subscriptions  map[string]map[*EventSubscription]bool
+	count          int
+	maxSubscribers int
+	bufferSize     int
+}
+
+// NewEventStream creates a stream for up to maxSubscribers clients, each buffering up to bufferSize events
+func NewEventStream(maxSubscribers int, bufferSize int) *EventStream {
+	return &EventStream{
+		subscriptions:  make(map[string]map[*EventSubscription]bool),
+		maxSubscribers: maxSubscribers,
+		bufferSize:     bufferSize,
+	}
+}
+
+// Subscribe starts streaming the events of the supplied types of an app to a client
+func (s *EventStream) Subscribe(appId string, types []EventType) (*EventSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.count >= s.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+
+	subscription := &EventSubscription{
+		AppId:  appId,
+		Events: make(chan Event, s.bufferSize),
+		types:  make(map[EventType]bool, len(types)),
+	}
+	for _, eventType := range types {
+		subscription.types[eventType] = true
+	}
+
+	if s.subscriptions[appId] == nil {
+		s.subscriptions[appId] = make(map[*EventSubscription]bool)
+	}
+	s.subscriptions[appId][subscription] = true
+	s.count++
+	return subscription, nil
+}
+
+// Unsubscribe stops streaming events to the client
+func (s *EventStream) Unsubscribe(subscription *EventSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscriptions, exists := s.subscriptions[subscription.AppId]
+	if !exists || !subscriptions[subscription] {
+		return
+	}
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(s.subscriptions, subscription.AppId)
+	}
+	s.count--
+}
+
+// Subscribers returns the number of clients streaming the events of an app
+func (s *EventStream) Subscribers(appId string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subscriptions[appId])
+}
+
+// Broadcast hands the event to every client streaming events of its app and type, without ever blocking
+func (s *EventStream) Broadcast(event Event) {
+	if s == nil {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for subscription := range s.subscriptions[event.AppId] {
+		if !subscription.types[event.Type] {
+			continue
+		}
+		select {
+		case subscription.Events <- event:
+		default:
+			atomic.AddInt64(&subscription.dropped, 1)
+		}
+	}
+}
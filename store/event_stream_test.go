@@ -0,0 +1,87 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestEventStream_BroadcastsToSubscribersOfAppAndType(t *testing.T) {
+	stream := NewEventStream(10, 10)
+	fired, _ := stream.Subscribe("test", []EventType{ScheduleFired})
+	all, _ := stream.Subscribe("test", fireEventTypes)
+	other, _ := stream.Subscribe("other", fireEventTypes)
+
+	stream.Broadcast(Event{AppId: "test", Type: ScheduleFired})
+	stream.Broadcast(Event{AppId: "test", Type: ScheduleFailed})
+
+	if len(fired.Events) != 1 || len(all.Events) != 2 || len(other.Events) != 0 {
+		t.Errorf("expected the events streamed by app and type, got %d, %d and %d", len(fired.Events), len(all.Events), len(other.Events))
+	}
+}
+
+func TestEventStream_DropsEventsForSlowSubscribers(t *testing.T) {
+	stream := NewEventStream(10, 1)
+	subscription, _ := stream.Subscribe("test", fireEventTypes)
+
+	stream.Broadcast(Event{AppId: "test", Type: ScheduleFired})
+	stream.Broadcast(Event{AppId: "test", Type: ScheduleFired})
+	stream.Broadcast(Event{AppId: "test", Type: ScheduleFired})
+
+	if dropped := subscription.Dropped(); dropped != 2 {
+		t.Errorf("expected 2 events dropped, got %d", dropped)
+	}
+	if dropped := subscription.Dropped(); dropped != 0 {
+		t.Errorf("expected the dropped events reset once reported, got %d", dropped)
+	}
+}
+
+func TestEventStream_LimitsSubscribers(t *testing.T) {
+	stream := NewEventStream(1, 1)
+	subscription, err := stream.Subscribe("test", fireEventTypes)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err = stream.Subscribe("other", fireEventTypes); err != ErrTooManySubscribers {
+		t.Errorf("expected %v, got %v", ErrTooManySubscribers, err)
+	}
+
+	stream.Unsubscribe(subscription)
+	stream.Unsubscribe(subscription)
+	if _, err = stream.Subscribe("other", fireEventTypes); err != nil {
+		t.Errorf("expected a subscription once the first client left, got %v", err)
+	}
+	if stream.Subscribers("test") != 0 {
+		t.Errorf("expected no subscribers left for the app, got %d", stream.Subscribers("test"))
+	}
+}
+
+func TestParseFireEventTypes(t *testing.T) {
+	types, err := ParseFireEventTypes("")
+	if err != nil || len(types) != 3 {
+		t.Errorf("expected every fire event type, got %v, %v", types, err)
+	}
+
+	types, err = ParseFireEventTypes("schedule.failed, schedule.dead_lettered")
+	if err != nil || len(types) != 2 || types[0] != ScheduleFailed || types[1] != ScheduleDeadLettered {
+		t.Errorf("expected the listed event types, got %v, %v", types, err)
+	}
+
+	if _, err = ParseFireEventTypes("schedule.created"); err == nil {
+		t.Error("expected an error for an event type which is not about a fired run")
+	}
+}
+
+func TestPublishEvent_StreamsFireEvents(t *testing.T) {
+	defer func(queue chan Event, stream *EventStream) { EventTaskQueue, FireEventStream = queue, stream }(EventTaskQueue, FireEventStream)
+
+	EventTaskQueue = make(chan Event, 2)
+	FireEventStream = NewEventStream(1, 2)
+	subscription, _ := FireEventStream.Subscribe("test", fireEventTypes)
+
+	PublishEvent(ScheduleCreated, Schedule{AppId: "test"})
+	PublishEvent(ScheduleFired, Schedule{AppId: "test"})
+
+	if len(subscription.Events) != 1 || (<-subscription.Events).Type != ScheduleFired {
+		t.Error("expected only the fire event streamed")
+	}
+}
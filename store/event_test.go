@@ -0,0 +1,51 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestNewEvent_DeadLetteredRunLeavesOutSchedule(t *testing.T) {
+	run := Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Status: Failure, ErrorMessage: "503 Service Unavailable"}
+
+	event := NewEvent(ScheduleDeadLettered, run)
+	if event.Schedule != nil || event.Status != Failure || event.ErrorMessage != run.ErrorMessage {
+		t.Errorf("expected a dead-lettered run without its schedule, got %+v", event)
+	}
+}
+
+func TestPublishEvent_CountsDroppedEvents(t *testing.T) {
+	defer func(queue chan Event) { EventTaskQueue = queue }(EventTaskQueue)
+	defer SetEventDropRecorder(nil)
+
+	var dropped []EventType
+	SetEventDropRecorder(func(eventType EventType) { dropped = append(dropped, eventType) })
+
+	EventTaskQueue = make(chan Event, 1)
+	PublishEvent(ScheduleCreated, Schedule{})
+	PublishEvent(ScheduleDeleted, Schedule{})
+
+	if len(EventTaskQueue) != 1 || len(dropped) != 1 || dropped[0] != ScheduleDeleted {
+		t.Errorf("expected the event overflowing the queue to be dropped and counted, got %v", dropped)
+	}
+}
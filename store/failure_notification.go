@@ -0,0 +1,150 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Channels the failure notifications of an app are delivered to
+const (
+	NotifyWebhook = "webhook"
+	NotifyEmail   = "email"
+)
+
+// maxConsecutiveFailures bounds the runs read to count the failures of a recurring schedule in a row
+const maxConsecutiveFailures = 100
+
+// FailureNotification alerts when the runs of a recurring schedule of an app fail a number of times in a row, and
+// optionally pauses the schedule. A schedule is notified once per streak of failures, a successful run ends the streak.
+type FailureNotification struct {
+	// Failed runs in a row the schedule is notified after
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// Channel of the notification, webhook or email
+	Channel string `json:"channel"`
+	// Url the notification is posted to as json by the webhook channel
+	Url string `json:"url,omitempty"`
+	// Recipients of the email channel
+	Emails []string `json:"emails,omitempty"`
+	// Pause the schedule along with the notification
+	AutoPause bool `json:"autoPause,omitempty"`
+}
+
+// FailureAlert is the notification of a recurring schedule whose latest runs failed in a row
+type FailureAlert struct {
+	AppId               string `json:"appId"`
+	ScheduleId          string `json:"scheduleId"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	LastRunId           string `json:"lastRunId"`
+	LastRunTime         int64  `json:"lastRunTime"`
+	LastStatus          Status `json:"lastStatus"`
+	ErrorMessage        string `json:"errorMessage,omitempty"`
+	Paused              bool   `json:"paused"`
+	OccurredAt          int64  `json:"occurredAt"`
+}
+
+// FailureNotificationTask is a failed run of a recurring schedule of an app with a failure notification
+type FailureNotificationTask struct {
+	Run Schedule
+	App App
+}
+
+// Validate checks the threshold and the recipients of the channel
+func (n *FailureNotification) Validate() error {
+	if n.ConsecutiveFailures < 1 || n.ConsecutiveFailures > maxConsecutiveFailures {
+		return fmt.Errorf("consecutiveFailures: %d must be between 1 and %d", n.ConsecutiveFailures, maxConsecutiveFailures)
+	}
+
+	switch n.Channel {
+	case NotifyWebhook:
+		if u, err := url.ParseRequestURI(n.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid failure notification url: %s", n.Url)
+		}
+	case NotifyEmail:
+		if len(n.Emails) == 0 {
+			return errors.New("failure notification emails are required")
+		}
+		for _, email := range n.Emails {
+			if _, err := mail.ParseAddress(email); err != nil {
+				return fmt.Errorf("invalid failure notification email %s: %w", email, err)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid failure notification channel: %s, must be one of webhook or email", n.Channel)
+	}
+	return nil
+}
+
+// Runs is the number of latest runs read to tell whether a failed run completes a streak of the notification
+func (n *FailureNotification) Runs() int64 {
+	return int64(n.ConsecutiveFailures) + 1
+}
+
+// Notifies tells whether the failed run completes a streak of the notification, from the latest runs of its
+// schedule which may or may not include it yet. The runs still waiting to fire and the skipped runs are not counted.
+func (n *FailureNotification) Notifies(run Schedule, runs []Schedule) bool {
+	if !run.Status.failed() {
+		return false
+	}
+
+	latest := []Schedule{run}
+	for _, r := range runs {
+		if r.ScheduleId != run.ScheduleId {
+			latest = append(latest, r)
+		}
+	}
+	sort.SliceStable(latest, func(i, j int) bool {
+		return latest[i].ScheduleTime > latest[j].ScheduleTime
+	})
+
+	streak := 0
+	for _, r := range latest {
+		switch {
+		case r.Status.failed():
+			streak++
+		case r.Status == Success:
+			return streak == n.ConsecutiveFailures
+		}
+		if streak > n.ConsecutiveFailures {
+			return false
+		}
+	}
+	return streak == n.ConsecutiveFailures
+}
+
+// NewFailureAlert creates the notification of the failed run completing a streak
+func NewFailureAlert(run Schedule, consecutiveFailures int, paused bool, now time.Time) FailureAlert {
+	return FailureAlert{
+		AppId:               run.AppId,
+		ScheduleId:          run.ParentScheduleId.String(),
+		ConsecutiveFailures: consecutiveFailures,
+		LastRunId:           run.ScheduleId.String(),
+		LastRunTime:         run.ScheduleTime,
+		LastStatus:          run.Status,
+		ErrorMessage:        run.ErrorMessage,
+		Paused:              paused,
+		OccurredAt:          now.Unix(),
+	}
+}
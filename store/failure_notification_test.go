@@ -0,0 +1,61 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestFailureNotificationValidate(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		notification FailureNotification
+		valid        bool
+	}{
+		{"Webhook", FailureNotification{ConsecutiveFailures: 3, Channel: NotifyWebhook, Url: "https://alerts.example.com"}, true},
+		{"Email", FailureNotification{ConsecutiveFailures: 3, Channel: NotifyEmail, Emails: []string{"oncall@example.com"}}, true},
+		{"NoThreshold", FailureNotification{Channel: NotifyWebhook, Url: "https://alerts.example.com"}, false},
+		{"HighThreshold", FailureNotification{ConsecutiveFailures: 101, Channel: NotifyWebhook, Url: "https://alerts.example.com"}, false},
+		{"InvalidUrl", FailureNotification{ConsecutiveFailures: 3, Channel: NotifyWebhook, Url: "ftp://alerts.example.com"}, false},
+		{"NoEmails", FailureNotification{ConsecutiveFailures: 3, Channel: NotifyEmail}, false},
+		{"InvalidEmail", FailureNotification{ConsecutiveFailures: 3, Channel: NotifyEmail, Emails: []string{"oncall"}}, false},
+		{"InvalidChannel", FailureNotification{ConsecutiveFailures: 3, Channel: "sms"}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.notification.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestFailureNotificationNotifies(t *testing.T) {
+	notification := FailureNotification{ConsecutiveFailures: 3, Channel: NotifyWebhook, Url: "https://alerts.example.com"}
+	run := func(scheduleTime int64, status Status) Schedule {
+		return Schedule{ScheduleId: gocql.TimeUUID(), ScheduleTime: scheduleTime, Status: status}
+	}
+	failed := run(100, Failure)
+	stored := failed
+	stored.Status = Scheduled
+
+	for _, test := range []struct {
+		name string
+		run  Schedule
+		runs []Schedule
+		want bool
+	}{
+		{"ThirdFailure", failed, []Schedule{run(90, Failure), run(80, Miss), run(70, Success)}, true},
+		{"StoredRunNotUpdated", failed, []Schedule{stored, run(90, Failure), run(80, Overrun)}, true},
+		{"SkippedRunsIgnored", failed, []Schedule{run(90, Skipped), run(80, Failure), run(70, Failure)}, true},
+		{"SecondFailure", failed, []Schedule{run(90, Failure), run(80, Success)}, false},
+		{"FourthFailure", failed, []Schedule{run(90, Failure), run(80, Failure), run(70, Failure)}, false},
+		{"FirstRuns", failed, []Schedule{run(90, Error), run(80, Failure)}, true},
+		{"Success", run(100, Success), []Schedule{run(90, Failure), run(80, Failure)}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := notification.Notifies(test.run, test.runs); got != test.want {
+				t.Errorf("Notifies() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/conf"
+	"github.com/myntra/goscheduler/logger"
+)
+
+// minFlagDeferSeconds keeps a run deferred while its flag is off out of the time bucket being fired
+const minFlagDeferSeconds = 60
+
+// flagNamePattern matches the names of the flags the firing can be gated on
+var flagNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._:/-]{0,127}$`)
+
+// FlagPolicy decides what happens to the runs due while the flag gating their firing is off
+type FlagPolicy string
+
+const (
+	// SkipDisabledRuns records the runs as skipped, this is the default
+	SkipDisabledRuns FlagPolicy = "skip"
+	// DeferDisabledRuns fires the runs again after the defer period, when they are gated on the flags again
+	DeferDisabledRuns FlagPolicy = "defer"
+)
+
+// Validate checks that the flag policy is one of the supported policies
+func (p FlagPolicy) Validate() error {
+	switch p {
+	case "", SkipDisabledRuns, DeferDisabledRuns:
+		return nil
+	default:
+		return fmt.Errorf("invalid feature flag policy: %s, must be one of skip or defer", p)
+	}
+}
+
+// ValidateFlagName checks the name of a flag gating the firing, an empty name gating nothing
+func ValidateFlagName(name string) error {
+	if name != "" && !flagNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid feature flag name %q, must be at most 128 letters, digits or ._:/- characters", name)
+	}
+	return nil
+}
+
+// FlagProvider reads the current values of the feature flags by name
+type FlagProvider interface {
+	GetFlags() (map[string]bool, error)
+}
+
+// NewFlagProvider builds the flag provider of the configuration, nil when no provider is configured
+func NewFlagProvider(config conf.FeatureFlagConfig) (FlagProvider, error) {
+	switch config.Provider {
+	case "":
+		return nil, nil
+	case "http":
+		if config.Url == "" {
+			return nil, errors.New("url of the http flag provider cannot be empty")
+		}
+		return &httpFlags{
+			url:     config.Url,
+			headers: config.Headers,
+			client:  &http.Client{Timeout: config.TimeoutMillis * time.Millisecond},
+		}, nil
+	case "file":
+		if config.Path == "" {
+			return nil, errors.New("path of the file flag provider cannot be empty")
+		}
+		return fileFlags{path: config.Path}, nil
+	default:
+		return nil, fmt.Errorf("unknown flag provider %s, must be one of http or file", config.Provider)
+	}
+}
+
+// httpFlags reads the flags from an endpoint answering a JSON object of flag names to booleans
+type httpFlags struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (h *httpFlags) GetFlags() (map[string]bool, error) {
+	req, err := http.NewRequest(http.MethodGet, h.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range h.headers {
+		req.Header.Set(key, value)
+	}
+
+	response, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("flag endpoint responded with %s", response.Status)
+	}
+
+	var flags map[string]bool
+	if err = json.NewDecoder(response.Body).Decode(&flags); err != nil {
+		return nil, fmt.Errorf("invalid flags from the flag endpoint: %w", err)
+	}
+	return flags, nil
+}
+
+// fileFlags reads the flags from a JSON file of flag names to booleans, like a config map mounted in a container
+type fileFlags struct {
+	path string
+}
+
+func (f fileFlags) GetFlags() (map[string]bool, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags map[string]bool
+	if err = json.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("invalid flags in %s: %w", f.path, err)
+	}
+	return flags, nil
+}
+
+// FeatureFlags keeps the flags last read from a provider, which the firing of the callbacks is gated on.
+// The firing of every app is off while the kill switch is false, and the firing of an app while the flag named by
+// its configuration is false. A flag missing from the provider is on, and the last flags read are kept while the
+// provider fails, so that an unreachable provider does not stop the firing.
+type FeatureFlags struct {
+	provider     FlagProvider
+	killSwitch   string
+	policy       FlagPolicy
+	deferSeconds int
+	maxDefer     time.Duration
+	refresh      time.Duration
+
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+// NewFeatureFlags builds the flags of the configuration, nil when no provider is configured
+func NewFeatureFlags(config conf.FeatureFlagConfig) (*FeatureFlags, error) {
+	provider, err := NewFlagProvider(config)
+	if err != nil || provider == nil {
+		return nil, err
+	}
+
+	policy := FlagPolicy(config.Policy)
+	if err = policy.Validate(); err != nil {
+		return nil, err
+	}
+	if err = ValidateFlagName(config.KillSwitch); err != nil {
+		return nil, err
+	}
+	switch {
+	case config.RefreshSeconds <= 0:
+		return nil, fmt.Errorf("refresh of the feature flags must be positive, got %d seconds", config.RefreshSeconds)
+	case policy == DeferDisabledRuns && config.DeferSeconds < minFlagDeferSeconds:
+		return nil, fmt.Errorf("defer of the runs whose flag is off must be at least %d seconds, got %d", minFlagDeferSeconds, config.DeferSeconds)
+	case policy == DeferDisabledRuns && config.MaxDeferSeconds < config.DeferSeconds:
+		return nil, fmt.Errorf("max defer of the runs whose flag is off must be at least their defer of %d seconds, got %d", config.DeferSeconds, config.MaxDeferSeconds)
+	}
+	if policy == "" {
+		policy = SkipDisabledRuns
+	}
+
+	return &FeatureFlags{
+		provider:     provider,
+		killSwitch:   config.KillSwitch,
+		policy:       policy,
+		deferSeconds: config.DeferSeconds,
+		maxDefer:     time.Duration(config.MaxDeferSeconds) * time.Second,
+		refresh:      time.Duration(config.RefreshSeconds) * time.Second,
+		flags:        map[string]bool{},
+	}, nil
+}
+
+// Start reads the flags and keeps refreshing them in the background
+func (f *FeatureFlags) Start() {
+	if err := f.Refresh(); err != nil {
+		logger.Errorf("Reading the feature flags failed with error %s, firing every app until they are read", err.Error())
+	}
+	go func() {
+		ticker := time.NewTicker(f.refresh)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := f.Refresh(); err != nil {
+				logger.Errorf("Refreshing the feature flags failed with error %s, keeping the last flags read", err.Error())
+			}
+		}
+	}()
+}
+
+// Refresh reads the flags from the provider, the last flags read are kept if it fails
+func (f *FeatureFlags) Refresh() error {
+	flags, err := f.provider.GetFlags()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	wasOff := f.isOff(f.killSwitch)
+	f.flags = flags
+	isOff := f.isOff(f.killSwitch)
+	f.mu.Unlock()
+
+	if isOff != wasOff {
+		logger.Warningf("Kill switch %s turned the firing of every app %s", f.killSwitch, map[bool]string{true: "off", false: "back on"}[isOff])
+	}
+	return nil
+}
+
+// Disabled tells whether the firing of the schedules of the app is off, along with the reason
+func (f *FeatureFlags) Disabled(app App) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.isOff(f.killSwitch) {
+		return fmt.Sprintf("firing turned off by the kill switch %s", f.killSwitch), true
+	}
+	if flag := app.Configuration.FeatureFlag; f.isOff(flag) {
+		return fmt.Sprintf("firing of app %s turned off by the flag %s", app.AppId, flag), true
+	}
+	return "", false
+}
+
+// Policy returns what happens to the runs due while their flag is off
+func (f *FeatureFlags) Policy() FlagPolicy {
+	return f.policy
+}
+
+// DeferDelay returns the delay a run due while its flag is off is deferred by with the defer policy
+func (f *FeatureFlags) DeferDelay() time.Duration {
+	return time.Duration(f.deferSeconds) * time.Second
+}
+
+// CanDefer tells whether a run due while its flag is off can be deferred once more with the defer policy.
+// A run is deferred until the max defer past the time it was first due, and skipped after.
+func (f *FeatureFlags) CanDefer(run Schedule) bool {
+	deferredUntil := clock.Now().Add(f.DeferDelay())
+	return deferredUntil.Sub(time.Unix(run.FirstDueAt(), 0)) <= f.maxDefer
+}
+
+// MaxDefer returns the longest a run due while its flag is off is deferred past the time it was first due
+func (f *FeatureFlags) MaxDefer() time.Duration {
+	return f.maxDefer
+}
+
+// isOff tells whether the named flag is false, a flag without a name or missing from the provider being on
+func (f *FeatureFlags) isOff(name string) bool {
+	if name == "" {
+		return false
+	}
+	on, ok := f.flags[name]
+	return ok && !on
+}
+
+// featureFlags are the flags the firing of the node is gated on, nil when the firing is not gated
+var featureFlags struct {
+	sync.RWMutex
+	flags *FeatureFlags
+}
+
+// SetFeatureFlags sets the flags the firing of the callbacks is gated on
+func SetFeatureFlags(flags *FeatureFlags) {
+	featureFlags.Lock()
+	defer featureFlags.Unlock()
+	featureFlags.flags = flags
+}
+
+// GetFeatureFlags returns the flags the firing of the callbacks is gated on, nil when the firing is not gated
+func GetFeatureFlags() *FeatureFlags {
+	featureFlags.RLock()
+	defer featureFlags.RUnlock()
+	return featureFlags.flags
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestNewFeatureFlags(t *testing.T) {
+	for _, test := range []struct {
+		config conf.FeatureFlagConfig
+		valid  bool
+	}{
+		{conf.FeatureFlagConfig{}, true},
+		{conf.FeatureFlagConfig{Provider: "file", Path: "flags.json", RefreshSeconds: 10}, true},
+		{conf.FeatureFlagConfig{Provider: "http", Url: "http://flags", RefreshSeconds: 10, KillSwitch: "scheduler.firing", Policy: "defer", DeferSeconds: 60, MaxDeferSeconds: 3600}, true},
+		{conf.FeatureFlagConfig{Provider: "launchdarkly", RefreshSeconds: 10}, false},
+		{conf.FeatureFlagConfig{Provider: "file", RefreshSeconds: 10}, false},
+		{conf.FeatureFlagConfig{Provider: "http", RefreshSeconds: 10}, false},
+		{conf.FeatureFlagConfig{Provider: "file", Path: "flags.json"}, false},
+		{conf.FeatureFlagConfig{Provider: "file", Path: "flags.json", RefreshSeconds: 10, Policy: "drop"}, false},
+		{conf.FeatureFlagConfig{Provider: "file", Path: "flags.json", RefreshSeconds: 10, Policy: "defer", DeferSeconds: 30}, false},
+		{conf.FeatureFlagConfig{Provider: "file", Path: "flags.json", RefreshSeconds: 10, Policy: "defer", DeferSeconds: 300, MaxDeferSeconds: 60}, false},
+		{conf.FeatureFlagConfig{Provider: "file", Path: "flags.json", RefreshSeconds: 10, KillSwitch: "kill switch"}, false},
+	} {
+		if _, err := NewFeatureFlags(test.config); (err == nil) != test.valid {
+			t.Errorf("config %+v: expected valid %v, got %v", test.config, test.valid, err)
+		}
+	}
+}
+
+func TestFeatureFlags_Disabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	flags, err := NewFeatureFlags(conf.FeatureFlagConfig{Provider: "file", Path: path, RefreshSeconds: 10, KillSwitch: "firing"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	gated := App{AppId: "gated", Configuration: Configuration{FeatureFlag: "gated.firing"}}
+	other := App{AppId: "other", Configuration: Configuration{FeatureFlag: "other.firing"}}
+	plain := App{AppId: "plain"}
+
+	for _, test := range []struct {
+		flags    string
+		disabled map[string]bool
+	}{
+		{`{}`, map[string]bool{}},
+		{`{"gated.firing": false, "other.firing": true}`, map[string]bool{"gated": true}},
+		{`{"firing": false, "gated.firing": true}`, map[string]bool{"gated": true, "other": true, "plain": true}},
+		{`{"firing": true}`, map[string]bool{}},
+	} {
+		if err = ioutil.WriteFile(path, []byte(test.flags), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err = flags.Refresh(); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		for _, app := range []App{gated, other, plain} {
+			if _, off := flags.Disabled(app); off != test.disabled[app.AppId] {
+				t.Errorf("flags %s: expected app %s disabled %v, got %v", test.flags, app.AppId, test.disabled[app.AppId], off)
+			}
+		}
+	}
+
+	// the last flags read are kept while the provider fails
+	if err = ioutil.WriteFile(path, []byte(`{"gated.firing": false}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_ = flags.Refresh()
+	if err = ioutil.WriteFile(path, []byte(`not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = flags.Refresh(); err == nil {
+		t.Errorf("expected an error reading invalid flags")
+	}
+	if _, off := flags.Disabled(gated); !off {
+		t.Errorf("expected the flags read before the failure to be kept")
+	}
+
+	var none *FeatureFlags
+	if _, off := none.Disabled(gated); off {
+		t.Errorf("expected the firing not to be gated without flags")
+	}
+}
+
+func TestHttpFlags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"firing": false}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewFlagProvider(conf.FeatureFlagConfig{Provider: "http", Url: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}, TimeoutMillis: 1000})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if flags, err := provider.GetFlags(); err != nil || len(flags) != 1 || flags["firing"] {
+		t.Errorf("expected the firing flag off, got %v, %v", flags, err)
+	}
+
+	provider, _ = NewFlagProvider(conf.FeatureFlagConfig{Provider: "http", Url: server.URL, TimeoutMillis: 1000})
+	if _, err := provider.GetFlags(); err == nil {
+		t.Errorf("expected an error for an unauthorized flag request")
+	}
+}
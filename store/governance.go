@@ -0,0 +1,110 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxGovernanceTimeout bounds the time the creation of a schedule waits for the governance service
+const maxGovernanceTimeout = 10 * time.Second
+
+// GovernanceDecision is the answer of the governance service to the creation of a schedule
+type GovernanceDecision string
+
+const (
+	// ApproveSchedule creates the schedule as requested
+	ApproveSchedule GovernanceDecision = "approve"
+	// MutateSchedule creates the schedule returned by the governance service instead
+	MutateSchedule GovernanceDecision = "mutate"
+	// RejectSchedule fails the creation of the schedule
+	RejectSchedule GovernanceDecision = "reject"
+)
+
+// GovernanceFailurePolicy tells what happens to the creation of a schedule when the governance service fails
+type GovernanceFailurePolicy string
+
+const (
+	// FailOpen creates the schedule as requested, this is the default
+	FailOpen GovernanceFailurePolicy = "open"
+	// FailClosed fails the creation of the schedule
+	FailClosed GovernanceFailurePolicy = "closed"
+)
+
+// Validate checks that the failure policy is one of the supported policies
+func (p GovernanceFailurePolicy) Validate() error {
+	switch p {
+	case "", FailOpen, FailClosed:
+		return nil
+	default:
+		return fmt.Errorf("invalid governance failure policy: %s, must be one of open or closed", p)
+	}
+}
+
+// Governance tunes for an app the governance hook the schedules are submitted to before their creation
+type Governance struct {
+	// Timeout of the governance request, the timeout of the cluster when left out
+	TimeoutMillis int `json:"timeoutMillis,omitempty"`
+	// What happens when the governance service fails, the policy of the cluster when left out
+	FailurePolicy GovernanceFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// Validate checks the timeout and the failure policy of the governance of an app
+func (g *Governance) Validate() error {
+	if g.TimeoutMillis < 0 || time.Duration(g.TimeoutMillis)*time.Millisecond > maxGovernanceTimeout {
+		return fmt.Errorf("timeoutMillis of governance must be between 0 and %d, got %d", maxGovernanceTimeout.Milliseconds(), g.TimeoutMillis)
+	}
+	return g.FailurePolicy.Validate()
+}
+
+// GovernanceRequest is the body posted to the governance service for a schedule to create
+type GovernanceRequest struct {
+	AppId    string   `json:"appId"`
+	Schedule Schedule `json:"schedule"`
+}
+
+// GovernanceResponse is the body of the answer of the governance service
+type GovernanceResponse struct {
+	Decision GovernanceDecision `json:"decision"`
+	// Why the schedule is rejected or mutated, returned to the client on a rejection
+	Reason string `json:"reason,omitempty"`
+	// Schedule to create instead of the requested one on a mutation
+	Schedule *Schedule `json:"schedule,omitempty"`
+}
+
+// Validate checks that the answer is a known decision, and that a mutation keeps the schedule in its app
+func (r GovernanceResponse) Validate(appId string) error {
+	switch r.Decision {
+	case ApproveSchedule, RejectSchedule:
+		return nil
+	case MutateSchedule:
+		if r.Schedule == nil {
+			return errors.New("mutate decision without a schedule")
+		}
+		if r.Schedule.AppId != appId {
+			return fmt.Errorf("mutate decision moving the schedule from app %s to %s", appId, r.Schedule.AppId)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown governance decision: %s", r.Decision)
+	}
+}
@@ -0,0 +1,39 @@
+package store
+
+import "testing"
+
+func TestGovernance_Validate(t *testing.T) {
+	for _, test := range []struct {
+		governance Governance
+		valid      bool
+	}{
+		{Governance{}, true},
+		{Governance{TimeoutMillis: 2000, FailurePolicy: FailClosed}, true},
+		{Governance{TimeoutMillis: -1}, false},
+		{Governance{TimeoutMillis: 20000}, false},
+		{Governance{FailurePolicy: "ignore"}, false},
+	} {
+		if err := test.governance.Validate(); (err == nil) != test.valid {
+			t.Errorf("expected %+v valid to be %v, got %v", test.governance, test.valid, err)
+		}
+	}
+}
+
+func TestGovernanceResponse_Validate(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		response GovernanceResponse
+		valid    bool
+	}{
+		{"approve", GovernanceResponse{Decision: ApproveSchedule}, true},
+		{"reject", GovernanceResponse{Decision: RejectSchedule, Reason: "banned url"}, true},
+		{"mutate", GovernanceResponse{Decision: MutateSchedule, Schedule: &Schedule{AppId: "test"}}, true},
+		{"mutate without a schedule", GovernanceResponse{Decision: MutateSchedule}, false},
+		{"mutate into another app", GovernanceResponse{Decision: MutateSchedule, Schedule: &Schedule{AppId: "other"}}, false},
+		{"unknown decision", GovernanceResponse{Decision: "defer"}, false},
+	} {
+		if err := test.response.Validate("test"); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid to be %v, got %v", test.name, test.valid, err)
+		}
+	}
+}
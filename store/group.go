@@ -0,0 +1,160 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const maxGroupDescriptionLength = 1024
+
+var groupNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,127}$`)
+
+// ScheduleGroup is a named set of recurring schedules of an app, such as the schedules of a campaign, which are
+// paused, resumed, deleted and reported on as a unit. Schedules join a group with the group field.
+type ScheduleGroup struct {
+	AppId       string `json:"appId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Runs of the schedules of the group fired per minute across the cluster, the runs above it are skipped.
+	// 0 for no limit.
+	MaxRunsPerMinute int       `json:"maxRunsPerMinute,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// ValidateGroupName checks that the name can be referenced by schedules
+func ValidateGroupName(name string) error {
+	if !groupNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid group name %s, must be up to 128 letters, digits, dots, underscores or hyphens", name)
+	}
+	return nil
+}
+
+// Validate checks the name, the description and the run limit of the group
+func (g ScheduleGroup) Validate() error {
+	if err := ValidateGroupName(g.Name); err != nil {
+		return err
+	}
+	if len(g.Description) > maxGroupDescriptionLength {
+		return fmt.Errorf("description cannot be longer than %d characters", maxGroupDescriptionLength)
+	}
+	if g.MaxRunsPerMinute < 0 {
+		return errors.New("maxRunsPerMinute cannot be negative")
+	}
+	return nil
+}
+
+// validateGroup checks the group a schedule joins, only recurring schedules can be members of a group
+func validateGroup(group string, recurring bool) string {
+	switch {
+	case group == "":
+		return ""
+	case !recurring:
+		return "only recurring schedules can be members of a group"
+	default:
+		if err := ValidateGroupName(group); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+}
+
+// GroupAction is an operation applied to every schedule of a group
+type GroupAction string
+
+const (
+	PauseGroup  GroupAction = "pause"
+	ResumeGroup GroupAction = "resume"
+	DeleteGroup GroupAction = "delete"
+)
+
+// GroupActionJob pauses, resumes or deletes the schedules of a group
+const GroupActionJob JobType = "groupAction"
+
+// GroupActionRequest is the request of a group action job
+type GroupActionRequest struct {
+	Group  string      `json:"group"`
+	Action GroupAction `json:"action"`
+}
+
+// GroupMember is a schedule of a group along with its latest run in a group report
+type GroupMember struct {
+	ScheduleId     gocql.UUID `json:"scheduleId"`
+	Status         Status     `json:"status"`
+	CronExpression string     `json:"cronExpression,omitempty"`
+	Every          string     `json:"every,omitempty"`
+	RRule          string     `json:"rrule,omitempty"`
+	LastRun        *GroupRun  `json:"lastRun,omitempty"`
+}
+
+// GroupRun is the latest run of a member of a group
+type GroupRun struct {
+	ScheduleId   gocql.UUID `json:"scheduleId"`
+	ScheduleTime int64      `json:"scheduleTime"`
+	Status       Status     `json:"status,omitempty"`
+}
+
+// GroupReport reports on the schedules of a group as a unit
+type GroupReport struct {
+	Group ScheduleGroup `json:"group"`
+	// Number of members by status
+	Members map[Status]int `json:"members"`
+	// Number of members by the status of their latest run
+	LastRuns  map[Status]int `json:"lastRuns"`
+	Schedules []GroupMember  `json:"schedules"`
+}
+
+// NewGroupReport reports on the members of the group, along with the latest run of each of them when known
+func NewGroupReport(group ScheduleGroup, members []Schedule, lastRuns map[gocql.UUID]Schedule) GroupReport {
+	report := GroupReport{
+		Group:     group,
+		Members:   map[Status]int{},
+		LastRuns:  map[Status]int{},
+		Schedules: make([]GroupMember, 0, len(members)),
+	}
+
+	for _, schedule := range members {
+		member := GroupMember{
+			ScheduleId:     schedule.ScheduleId,
+			Status:         schedule.Status,
+			CronExpression: schedule.CronExpression,
+			Every:          schedule.Every,
+			RRule:          schedule.RRule,
+		}
+		report.Members[schedule.Status]++
+
+		if run, ok := lastRuns[schedule.ScheduleId]; ok {
+			member.LastRun = &GroupRun{ScheduleId: run.ScheduleId, ScheduleTime: run.ScheduleTime, Status: run.Status}
+			status := run.Status
+			if status == "" {
+				status = Scheduled
+			}
+			report.LastRuns[status]++
+		}
+		report.Schedules = append(report.Schedules, member)
+	}
+	return report
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+)
+
+func TestScheduleGroup_Validate(t *testing.T) {
+	for _, test := range []struct {
+		group ScheduleGroup
+		valid bool
+	}{
+		{ScheduleGroup{Name: "diwali-sale.2024_emails"}, true},
+		{ScheduleGroup{Name: "campaign", MaxRunsPerMinute: 100}, true},
+		{ScheduleGroup{Name: ""}, false},
+		{ScheduleGroup{Name: "-campaign"}, false},
+		{ScheduleGroup{Name: "big campaign"}, false},
+		{ScheduleGroup{Name: strings.Repeat("a", 129)}, false},
+		{ScheduleGroup{Name: "campaign", Description: strings.Repeat("a", 1025)}, false},
+		{ScheduleGroup{Name: "campaign", MaxRunsPerMinute: -1}, false},
+	} {
+		if err := test.group.Validate(); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid %t, got %v", test.group, test.valid, err)
+		}
+	}
+}
+
+func TestValidateGroup(t *testing.T) {
+	if msg := validateGroup("", false); msg != "" {
+		t.Errorf("expected no group to be valid, got %s", msg)
+	}
+	if msg := validateGroup("campaign", true); msg != "" {
+		t.Errorf("expected a recurring schedule to join a group, got %s", msg)
+	}
+	if msg := validateGroup("campaign", false); msg == "" {
+		t.Error("expected a one time schedule not to join a group")
+	}
+	if msg := validateGroup("bad name", true); msg == "" {
+		t.Error("expected an invalid group name to be rejected")
+	}
+}
+
+func TestNewGroupReport(t *testing.T) {
+	first, second, third := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	members := []Schedule{
+		{ScheduleId: first, Status: Scheduled, CronExpression: "* * * * *"},
+		{ScheduleId: second, Status: Scheduled, Every: "5m"},
+		{ScheduleId: third, Status: Paused, CronExpression: "0 * * * *"},
+	}
+	lastRuns := map[gocql.UUID]Schedule{
+		first:  {ScheduleId: gocql.TimeUUID(), ScheduleTime: 10, Status: Success},
+		second: {ScheduleId: gocql.TimeUUID(), ScheduleTime: 20, Status: Failure},
+	}
+
+	report := NewGroupReport(ScheduleGroup{Name: "campaign"}, members, lastRuns)
+
+	if report.Members[Scheduled] != 2 || report.Members[Paused] != 1 {
+		t.Errorf("unexpected members by status %v", report.Members)
+	}
+	if report.LastRuns[Success] != 1 || report.LastRuns[Failure] != 1 {
+		t.Errorf("unexpected last runs by status %v", report.LastRuns)
+	}
+	if len(report.Schedules) != 3 || report.Schedules[0].LastRun == nil || report.Schedules[2].LastRun != nil {
+		t.Errorf("unexpected schedules %+v", report.Schedules)
+	}
+}
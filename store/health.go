@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Health is the rollup of the recent runs of an active recurring schedule, derived when the schedule is read
+type Health string
+
+const (
+	// Healthy schedules fire on time and their recent runs succeeded
+	Healthy Health = "HEALTHY"
+	// Degraded schedules had some of their recent runs fail or fire late
+	Degraded Health = "DEGRADED"
+	// Failing schedules failed their latest runs or stopped firing
+	Failing Health = "FAILING"
+)
+
+const (
+	// HealthRuns is the number of recent runs the health of a schedule is derived from
+	HealthRuns = 10
+	// failingStreak is the number of latest runs failing in a row from which a schedule is failing
+	failingStreak = 3
+	// degradedLag and failingLag are how late a run still waiting to fire makes a schedule degraded or failing
+	degradedLag = time.Minute
+	failingLag  = 5 * time.Minute
+)
+
+// ParseHealth parses the health filter of a listing, case insensitively, empty for no filter
+func ParseHealth(value string) (Health, error) {
+	switch health := Health(strings.ToUpper(value)); health {
+	case "", Healthy, Degraded, Failing:
+		return health, nil
+	default:
+		return "", fmt.Errorf("invalid health: %s, must be one of healthy, degraded or failing", value)
+	}
+}
+
+// failed tells whether a run ended in failure, the skipped runs being neither failed nor successful
+func (s Status) failed() bool {
+	return s == Failure || s == Miss || s == Error || s == Overrun
+}
+
+// DeriveHealth derives the health of an active recurring schedule from its recent runs at now:
+//   - failing if its latest failingStreak completed runs, or all of them if fewer, failed, or if a run still waits to
+//     fire failingLag after its time
+//   - degraded if any of the recent runs failed, or if a run still waits to fire degradedLag after its time
+//   - healthy otherwise, including when it has not run yet
+func DeriveHealth(runs []Schedule, now time.Time) Health {
+	runs = append([]Schedule{}, runs...)
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].ScheduleTime > runs[j].ScheduleTime
+	})
+
+	var completed, failures, streak int
+	var lag time.Duration
+	for _, run := range runs {
+		switch {
+		case run.Status == Scheduled:
+			if late := now.Sub(time.Unix(run.ScheduleTime, 0)); late > lag {
+				lag = late
+			}
+		case run.Status.failed():
+			if failures == completed {
+				streak++
+			}
+			completed++
+			failures++
+		case run.Status == Success:
+			completed++
+		}
+	}
+
+	switch {
+	case lag >= failingLag || (completed > 0 && (streak >= failingStreak || streak == completed)):
+		return Failing
+	case lag >= degradedLag || failures > 0:
+		return Degraded
+	default:
+		return Healthy
+	}
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeriveHealth(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	run := func(minutesAgo int, status Status) Schedule {
+		return Schedule{ScheduleTime: now.Add(-time.Duration(minutesAgo) * time.Minute).Unix(), Status: status}
+	}
+
+	for _, test := range []struct {
+		name string
+		runs []Schedule
+		want Health
+	}{
+		{"NoRuns", nil, Healthy},
+		{"AllSucceeded", []Schedule{run(1, Success), run(2, Success), run(3, Success)}, Healthy},
+		{"SkippedRuns", []Schedule{run(1, Skipped), run(2, Success)}, Healthy},
+		{"OldFailure", []Schedule{run(1, Success), run(2, Failure), run(3, Success)}, Degraded},
+		{"LatestFailures", []Schedule{run(3, Miss), run(1, Failure), run(2, Error), run(4, Success)}, Failing},
+		{"OnlyFailures", []Schedule{run(1, Failure), run(2, Overrun)}, Failing},
+		{"TwoLatestFailures", []Schedule{run(1, Failure), run(2, Failure), run(3, Success)}, Degraded},
+		{"SlightlyLate", []Schedule{run(2, Scheduled), run(3, Success)}, Degraded},
+		{"StoppedFiring", []Schedule{run(10, Scheduled), run(11, Success)}, Failing},
+		{"DueNow", []Schedule{run(0, Scheduled), run(1, Success)}, Healthy},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DeriveHealth(test.runs, now); got != test.want {
+				t.Errorf("DeriveHealth() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseHealth(t *testing.T) {
+	for value, want := range map[string]Health{"": "", "failing": Failing, "Degraded": Degraded, "HEALTHY": Healthy} {
+		if got, err := ParseHealth(value); err != nil || got != want {
+			t.Errorf("ParseHealth(%q) = %s, %v, want %s", value, got, err, want)
+		}
+	}
+	if _, err := ParseHealth("sick"); err == nil {
+		t.Errorf("ParseHealth(sick) should fail")
+	}
+}
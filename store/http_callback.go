@@ -25,12 +25,58 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type Details struct {
 	Url     string            `json:"url"`
 	Method  string            `json:"method"`
 	Headers map[string]string `json:"headers"`
+	// Assertion on the body of a 2xx response, which must pass for the callback to succeed
+	Assertion *ResponseAssertion `json:"assertion,omitempty"`
+	// Schedule a follow-up run when a successful response asks for one with reRunAfterSeconds
+	RescheduleFromResponse bool `json:"rescheduleFromResponse,omitempty"`
+	// Maximum duration of the execution of the callback, retries included, after which it is cancelled as overrun
+	DeadlineMillis int64 `json:"deadlineMillis,omitempty"`
+	// Raise an SLA alert for the app when the callback overruns its deadline
+	AlertOnOverrun bool `json:"alertOnOverrun,omitempty"`
+}
+
+// maxDeadline bounds the execution deadline of a callback, a worker is held for as long
+const maxDeadline = time.Hour
+
+// ErrOverrun is the error of a callback cancelled as its execution outlasted the deadline of its schedule
+var ErrOverrun = errors.New("execution exceeded the deadline")
+
+// ExecutionDeadline returns the maximum duration of the execution of the http callback of the schedule,
+// zero when it has none
+func (s Schedule) ExecutionDeadline() time.Duration {
+	callback, ok := s.Callback.(*HttpCallback)
+	if !ok {
+		return 0
+	}
+	return time.Duration(callback.Details.DeadlineMillis) * time.Millisecond
+}
+
+// AlertsOnOverrun tells whether an overrun of the http callback of the schedule raises an SLA alert
+func (s Schedule) AlertsOnOverrun() bool {
+	callback, ok := s.Callback.(*HttpCallback)
+	return ok && callback.Details.AlertOnOverrun
+}
+
+// reRunDirective is the part of a callback response asking for a follow-up run
+type reRunDirective struct {
+	ReRunAfterSeconds int64 `json:"reRunAfterSeconds"`
+}
+
+// ParseReRunAfter returns the delay of the follow-up run asked for by a json response body with a positive
+// reRunAfterSeconds, false if the body asks for none
+func ParseReRunAfter(body []byte) (time.Duration, bool) {
+	var directive reRunDirective
+	if err := json.Unmarshal(body, &directive); err != nil || directive.ReRunAfterSeconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(directive.ReRunAfterSeconds) * time.Second, true
 }
 
 type HttpCallback struct {
@@ -82,8 +128,7 @@ func (h *HttpCallback) UnmarshalJSON(data []byte) error {
 }
 
 func (h HttpCallback) Invoke(wrapper ScheduleWrapper) error {
-	HttpTaskQueue <- wrapper
-	return nil
+	return HttpTaskQueue.Push(wrapper)
 }
 
 func (h *HttpCallback) Validate() error {
@@ -92,11 +137,22 @@ func (h *HttpCallback) Validate() error {
 		return errors.New("url cannot be empty")
 	}
 
-	// Checking if the URL is valid
-	_, err := url.ParseRequestURI(h.Details.Url)
+	// Checking if the URL is valid, secrets can only be referenced in its path and query
+	u, err := url.ParseRequestURI(h.Details.Url)
 	if err != nil {
 		return errors.New("invalid url")
 	}
+	if HasSecretRefs(u.Scheme + u.Host) {
+		return errors.New("secrets cannot be referenced in the scheme or host of the url")
+	}
+	if err := ValidateSecretRefs(h.Details.Url); err != nil {
+		return err
+	}
+	for _, value := range h.Details.Headers {
+		if err := ValidateSecretRefs(value); err != nil {
+			return err
+		}
+	}
 
 	// Checking if method is empty
 	if h.Details.Method == "" {
@@ -108,6 +164,21 @@ func (h *HttpCallback) Validate() error {
 		return errors.New(fmt.Sprintf("Invalid http callback method %s", h.Details.Method))
 	}
 
+	// Checking if the response assertion is valid
+	if h.Details.Assertion != nil {
+		if err := h.Details.Assertion.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Checking if the deadline is valid
+	if h.Details.DeadlineMillis < 0 || time.Duration(h.Details.DeadlineMillis)*time.Millisecond > maxDeadline {
+		return fmt.Errorf("deadlineMillis must be between 0 and %d", maxDeadline.Milliseconds())
+	}
+	if h.Details.AlertOnOverrun && h.Details.DeadlineMillis == 0 {
+		return errors.New("alertOnOverrun requires a deadlineMillis")
+	}
+
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 )
 
 // Test for GetType method
@@ -135,6 +136,42 @@ func TestValidate(t *testing.T) {
 			},
 			want: errors.New("Invalid http callback method INVALID"),
 		},
+		{
+			name: "Malformed Secret Reference",
+			details: Details{
+				Url:     "https://example.com",
+				Method:  "GET",
+				Headers: map[string]string{"Authorization": "Bearer {{secret:TOKEN"},
+			},
+			want: errors.New(`invalid secret reference in "Bearer {{secret:TOKEN", must be {{secret:NAME}}`),
+		},
+		{
+			name: "Secret Reference In Query",
+			details: Details{
+				Url:     "https://example.com/hook?key={{secret:API_KEY}}",
+				Method:  "GET",
+				Headers: map[string]string{"Authorization": "Bearer {{secret:TOKEN}}"},
+			},
+			want: nil,
+		},
+		{
+			name: "Deadline Too Long",
+			details: Details{
+				Url:            "https://example.com",
+				Method:         "GET",
+				DeadlineMillis: 2 * 60 * 60 * 1000,
+			},
+			want: errors.New("deadlineMillis must be between 0 and 3600000"),
+		},
+		{
+			name: "Alert On Overrun Without Deadline",
+			details: Details{
+				Url:            "https://example.com",
+				Method:         "GET",
+				AlertOnOverrun: true,
+			},
+			want: errors.New("alertOnOverrun requires a deadlineMillis"),
+		},
 		{
 			name: "Valid HttpCallback",
 			details: Details{
@@ -160,3 +197,27 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+// Test for ParseReRunAfter function
+func TestParseReRunAfter(t *testing.T) {
+	tests := []struct {
+		body  string
+		delay time.Duration
+		ok    bool
+	}{
+		{`{"reRunAfterSeconds": 300}`, 5 * time.Minute, true},
+		{`{"status": "pending", "reRunAfterSeconds": 60}`, time.Minute, true},
+		{`{"reRunAfterSeconds": 0}`, 0, false},
+		{`{"reRunAfterSeconds": -5}`, 0, false},
+		{`{"reRunAfterSeconds": "300"}`, 0, false},
+		{`{"status": "done"}`, 0, false},
+		{`OK`, 0, false},
+	}
+
+	for _, test := range tests {
+		delay, ok := ParseReRunAfter([]byte(test.body))
+		if delay != test.delay || ok != test.ok {
+			t.Errorf("body %s: expected %v and %v, got %v and %v", test.body, test.delay, test.ok, delay, ok)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+)
+
+// tlsVersions maps the supported minimum TLS versions to their crypto/tls values
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// HttpTransport tunes the client delivering the http callbacks of an app.
+// The zero value of a field keeps the default of the shared callback client.
+type HttpTransport struct {
+	// Forward proxy the callbacks go through, an http, https or socks5 url
+	ProxyUrl string `json:"proxyUrl,omitempty"`
+	// Maximum idle connections kept per callback host
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	// Maximum connections per callback host, unlimited if zero
+	MaxConnsPerHost int `json:"maxConnsPerHost,omitempty"`
+	// Time an idle connection is kept for in milliseconds
+	IdleConnTimeoutMillis int `json:"idleConnTimeoutMillis,omitempty"`
+	// Minimum TLS version of the callback hosts, one of 1.0, 1.1, 1.2 or 1.3
+	TLSMinVersion string `json:"tlsMinVersion,omitempty"`
+	// Open a new connection for every callback
+	DisableKeepAlives bool `json:"disableKeepAlives,omitempty"`
+	// Stick to HTTP/1.1 even when the callback host supports HTTP/2
+	DisableHttp2 bool `json:"disableHttp2,omitempty"`
+}
+
+// Validate checks the proxy url, the TLS version and the connection limits of the transport
+func (t HttpTransport) Validate() error {
+	if t.ProxyUrl != "" {
+		proxy, err := url.Parse(t.ProxyUrl)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %s", t.ProxyUrl)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("invalid proxy url: %s, the scheme must be one of http, https or socks5", t.ProxyUrl)
+		}
+		if proxy.Host == "" {
+			return fmt.Errorf("invalid proxy url: %s, the host cannot be empty", t.ProxyUrl)
+		}
+	}
+
+	if _, err := t.GetTLSMinVersion(); err != nil {
+		return err
+	}
+
+	if t.MaxIdleConnsPerHost < 0 || t.MaxConnsPerHost < 0 || t.IdleConnTimeoutMillis < 0 {
+		return fmt.Errorf("connection limits and timeouts cannot be negative")
+	}
+	return nil
+}
+
+// GetTLSMinVersion returns the crypto/tls value of the minimum TLS version, 0 for the default
+func (t HttpTransport) GetTLSMinVersion() (uint16, error) {
+	if t.TLSMinVersion == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[t.TLSMinVersion]
+	if !ok {
+		return 0, fmt.Errorf("invalid tls min version: %s, must be one of 1.0, 1.1, 1.2 or 1.3", t.TLSMinVersion)
+	}
+	return version, nil
+}
+
+// ConnectionTuning resizes the connection pool of the callback client of an app, the fields left out keep their value
+type ConnectionTuning struct {
+	MaxIdleConnsPerHost   *int  `json:"maxIdleConnsPerHost,omitempty"`
+	MaxConnsPerHost       *int  `json:"maxConnsPerHost,omitempty"`
+	IdleConnTimeoutMillis *int  `json:"idleConnTimeoutMillis,omitempty"`
+	DisableKeepAlives     *bool `json:"disableKeepAlives,omitempty"`
+}
+
+// Apply returns the transport resized by the tuning, or an error if the tuning changes nothing or leaves the
+// transport invalid
+func (t ConnectionTuning) Apply(transport HttpTransport) (HttpTransport, error) {
+	if t.MaxIdleConnsPerHost == nil && t.MaxConnsPerHost == nil && t.IdleConnTimeoutMillis == nil && t.DisableKeepAlives == nil {
+		return transport, fmt.Errorf("at least one of maxIdleConnsPerHost, maxConnsPerHost, idleConnTimeoutMillis or disableKeepAlives must be set")
+	}
+
+	if t.MaxIdleConnsPerHost != nil {
+		transport.MaxIdleConnsPerHost = *t.MaxIdleConnsPerHost
+	}
+	if t.MaxConnsPerHost != nil {
+		transport.MaxConnsPerHost = *t.MaxConnsPerHost
+	}
+	if t.IdleConnTimeoutMillis != nil {
+		transport.IdleConnTimeoutMillis = *t.IdleConnTimeoutMillis
+	}
+	if t.DisableKeepAlives != nil {
+		transport.DisableKeepAlives = *t.DisableKeepAlives
+	}
+	return transport, transport.Validate()
+}
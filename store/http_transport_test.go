@@ -0,0 +1,55 @@
+package store
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestHttpTransport_Validate(t *testing.T) {
+	for _, test := range []struct {
+		transport HttpTransport
+		valid     bool
+	}{
+		{HttpTransport{}, true},
+		{HttpTransport{ProxyUrl: "http://proxy.internal:3128", MaxIdleConnsPerHost: 10, TLSMinVersion: "1.2", DisableHttp2: true}, true},
+		{HttpTransport{ProxyUrl: "socks5://proxy.internal:1080"}, true},
+		{HttpTransport{ProxyUrl: "ftp://proxy.internal"}, false},
+		{HttpTransport{ProxyUrl: "http://"}, false},
+		{HttpTransport{TLSMinVersion: "1.4"}, false},
+		{HttpTransport{MaxConnsPerHost: -1}, false},
+	} {
+		if err := test.transport.Validate(); (err == nil) != test.valid {
+			t.Errorf("transport %+v: expected valid %v, got %v", test.transport, test.valid, err)
+		}
+	}
+}
+
+func TestHttpTransport_GetTLSMinVersion(t *testing.T) {
+	if version, err := (HttpTransport{}).GetTLSMinVersion(); err != nil || version != 0 {
+		t.Errorf("expected the default version, got %d and %v", version, err)
+	}
+	if version, err := (HttpTransport{TLSMinVersion: "1.3"}).GetTLSMinVersion(); err != nil || version != tls.VersionTLS13 {
+		t.Errorf("expected TLS 1.3, got %d and %v", version, err)
+	}
+}
+
+func TestConnectionTuning_Apply(t *testing.T) {
+	idle, conns, negative, disabled := 50, 0, -1, true
+	transport := HttpTransport{ProxyUrl: "http://proxy.internal:3128", MaxIdleConnsPerHost: 10, MaxConnsPerHost: 20}
+
+	tuned, err := ConnectionTuning{MaxIdleConnsPerHost: &idle, MaxConnsPerHost: &conns, DisableKeepAlives: &disabled}.Apply(transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := HttpTransport{ProxyUrl: "http://proxy.internal:3128", MaxIdleConnsPerHost: 50, DisableKeepAlives: true}
+	if tuned != expected {
+		t.Errorf("expected %+v, got %+v", expected, tuned)
+	}
+
+	if _, err = (ConnectionTuning{}).Apply(transport); err == nil {
+		t.Errorf("expected an empty tuning to fail")
+	}
+	if _, err = (ConnectionTuning{IdleConnTimeoutMillis: &negative}).Apply(transport); err == nil {
+		t.Errorf("expected a negative idle timeout to fail")
+	}
+}
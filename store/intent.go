@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// FireIntent is the claim of the fire of a schedule by a node, written to the intent log before the callback of the
+// schedule is dispatched and removed once the outcome of the fire is written. A claim left behind by a node which
+// crashed in between is recovered by the poller of the partition, which fires the schedule again.
+type FireIntent struct {
+	AppId       string     `json:"appId"`
+	PartitionId int        `json:"partitionId"`
+	ScheduleId  gocql.UUID `json:"scheduleId"`
+	Node        string     `json:"node"`      // Node which claimed the fire
+	Attempt     int        `json:"attempt"`   // Claims of the fire of the schedule, 1 for the first fire
+	ClaimedAt   int64      `json:"claimedAt"` // Epoch second of the claim
+}
+
+// IntentOutcome is what the recovery of a claim does with its schedule
+type IntentOutcome string
+
+const (
+	IntentAcknowledged IntentOutcome = "acknowledged" // The outcome of the fire was written, the claim is dropped
+	IntentRecovered    IntentOutcome = "recovered"    // The schedule is fired again under a new claim
+	IntentAbandoned    IntentOutcome = "abandoned"    // Out of attempts, left to the missed schedule reconciliation
+)
+
+// NewFireIntent creates the claim of the fire of the schedule by the node at the supplied time
+func NewFireIntent(schedule Schedule, node string, attempt int, now time.Time) FireIntent {
+	return FireIntent{
+		AppId:       schedule.AppId,
+		PartitionId: schedule.PartitionId,
+		ScheduleId:  schedule.ScheduleId,
+		Node:        node,
+		Attempt:     attempt,
+		ClaimedAt:   now.Unix(),
+	}
+}
+
+// IntentOf returns the claim of the fire of the schedule, false if the schedule was fired unclaimed
+func IntentOf(schedule Schedule) (FireIntent, bool) {
+	if schedule.ClaimedAt == 0 {
+		return FireIntent{}, false
+	}
+	return FireIntent{
+		AppId:       schedule.AppId,
+		PartitionId: schedule.PartitionId,
+		ScheduleId:  schedule.ScheduleId,
+		ClaimedAt:   schedule.ClaimedAt,
+	}, true
+}
+
+// Bucket returns the minute of the claim, the claims of a partition are read minute by minute
+func (i FireIntent) Bucket() time.Time {
+	return time.Unix(i.ClaimedAt, 0).Truncate(time.Minute)
+}
+
+// Recover tells what the recovery of the claim does with its schedule, read with its status.
+// A schedule without an outcome is fired again unless it was claimed maxAttempts times already.
+func (i FireIntent) Recover(schedule Schedule, maxAttempts int) IntentOutcome {
+	switch {
+	case schedule.Status != Scheduled && schedule.Status != Miss:
+		return IntentAcknowledged
+	case i.Attempt >= maxAttempts:
+		return IntentAbandoned
+	default:
+		return IntentRecovered
+	}
+}
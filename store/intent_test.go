@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestFireIntent_Recover(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		status  Status
+		attempt int
+		want    IntentOutcome
+	}{
+		{"Fired", Success, 1, IntentAcknowledged},
+		{"Failed", Failure, 3, IntentAcknowledged},
+		{"Skipped", Skipped, 1, IntentAcknowledged},
+		{"Missed", Miss, 1, IntentRecovered},
+		{"NotDueYet", Scheduled, 2, IntentRecovered},
+		{"OutOfAttempts", Miss, 3, IntentAbandoned},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			intent := FireIntent{Attempt: test.attempt}
+			if got := intent.Recover(Schedule{Status: test.status}, 3); got != test.want {
+				t.Errorf("Recover() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIntentOf(t *testing.T) {
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", PartitionId: 2}
+	if _, ok := IntentOf(schedule); ok {
+		t.Errorf("expected no claim for a schedule fired unclaimed")
+	}
+
+	claimedAt := time.Date(2023, 1, 1, 10, 5, 42, 0, time.UTC)
+	intent := NewFireIntent(schedule, "127.0.0.1:9091", 1, claimedAt)
+	schedule.ClaimedAt = intent.ClaimedAt
+
+	acknowledged, ok := IntentOf(schedule)
+	if !ok || acknowledged.ScheduleId != intent.ScheduleId || acknowledged.PartitionId != 2 ||
+		!acknowledged.Bucket().Equal(intent.Bucket()) {
+		t.Errorf("IntentOf() = %+v, want the key of %+v", acknowledged, intent)
+	}
+	if want := time.Date(2023, 1, 1, 10, 5, 0, 0, time.UTC); !intent.Bucket().Equal(want) {
+		t.Errorf("Bucket() = %v, want %v", intent.Bucket(), want)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// JobType is the kind of operation an asynchronous job runs
+type JobType string
+
+const (
+	// BulkUpdateJob applies a partial update to the recurring schedules of an app matching a filter
+	BulkUpdateJob JobType = "bulkUpdate"
+)
+
+// JobStatus is the state of an asynchronous job
+type JobStatus string
+
+const (
+	// JobRunning jobs are still processing their items
+	JobRunning JobStatus = "RUNNING"
+	// JobCompleted jobs have processed all their items, some of which may have failed
+	JobCompleted JobStatus = "COMPLETED"
+	// JobCancelled jobs were stopped before processing all their items
+	JobCancelled JobStatus = "CANCELLED"
+)
+
+// Job is an operation running asynchronously over many items, whose progress and per item results are tracked
+type Job struct {
+	JobId     gocql.UUID      `json:"jobId"`
+	Type      JobType         `json:"type"`
+	AppId     string          `json:"appId"`
+	Status    JobStatus       `json:"status"`
+	Request   json.RawMessage `json:"request,omitempty"`
+	Total     int             `json:"total"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	CreatedAt int64           `json:"createdAt"`
+	UpdatedAt int64           `json:"updatedAt"`
+	// CancelRequested is set by the cancel job API and checked by the node running the job between two chunks
+	CancelRequested bool `json:"cancelRequested,omitempty"`
+}
+
+// NewJob creates a running job of an app over total items
+func NewJob(jobType JobType, appId string, request json.RawMessage, total int, now time.Time) Job {
+	millis := now.UnixNano() / int64(time.Millisecond)
+	return Job{
+		JobId:     gocql.TimeUUID(),
+		Type:      jobType,
+		AppId:     appId,
+		Status:    JobRunning,
+		Request:   request,
+		Total:     total,
+		CreatedAt: millis,
+		UpdatedAt: millis,
+	}
+}
+
+// Record counts the result of an item of the job
+func (j *Job) Record(item JobItem, now time.Time) {
+	if item.Status == Success {
+		j.Succeeded++
+	} else {
+		j.Failed++
+	}
+	j.UpdatedAt = now.UnixNano() / int64(time.Millisecond)
+}
+
+// Retry makes a finished job run again over its failed items, whose previous results are discounted
+func (j *Job) Retry(failed int, now time.Time) {
+	j.Failed -= failed
+	j.Status = JobRunning
+	j.CancelRequested = false
+	j.UpdatedAt = now.UnixNano() / int64(time.Millisecond)
+}
+
+// Finished tells whether the job is no longer processing its items
+func (j Job) Finished() bool {
+	return j.Status == JobCompleted || j.Status == JobCancelled
+}
+
+// Processed returns the number of items of the job processed so far
+func (j Job) Processed() int {
+	return j.Succeeded + j.Failed
+}
+
+// JobItem is the result of a job for one of its items
+type JobItem struct {
+	JobId  gocql.UUID `json:"-"`
+	ItemId string     `json:"itemId"`
+	Status Status     `json:"status"`
+	Error  string     `json:"error,omitempty"`
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJob_Record(t *testing.T) {
+	createdAt := time.Unix(1700000000, 0)
+	job := NewJob(BulkUpdateJob, "app", nil, 3, createdAt)
+	if job.Status != JobRunning || job.CreatedAt != 1700000000000 || job.UpdatedAt != job.CreatedAt {
+		t.Fatalf("Unexpected new job %+v", job)
+	}
+
+	job.Record(JobItem{ItemId: "1", Status: Success}, createdAt.Add(time.Second))
+	job.Record(JobItem{ItemId: "2", Status: Failure, Error: "schedule is deleted"}, createdAt.Add(2*time.Second))
+
+	if job.Succeeded != 1 || job.Failed != 1 || job.Processed() != 2 {
+		t.Errorf("Expected one succeeded and one failed item, got %+v", job)
+	}
+	if job.UpdatedAt != 1700000002000 {
+		t.Errorf("Expected the job to be updated at the last item, got %d", job.UpdatedAt)
+	}
+}
+
+func TestJob_Retry(t *testing.T) {
+	createdAt := time.Unix(1700000000, 0)
+	job := NewJob(BulkUpdateJob, "app", nil, 3, createdAt)
+	job.Record(JobItem{ItemId: "1", Status: Success}, createdAt)
+	job.Record(JobItem{ItemId: "2", Status: Failure}, createdAt)
+	job.Status = JobCancelled
+	job.CancelRequested = true
+
+	if !job.Finished() {
+		t.Fatalf("Expected a cancelled job to be finished")
+	}
+
+	job.Retry(1, createdAt.Add(time.Second))
+
+	if job.Status != JobRunning || job.CancelRequested || job.Finished() {
+		t.Errorf("Expected the retried job to run again, got %+v", job)
+	}
+	if job.Succeeded != 1 || job.Failed != 0 || job.UpdatedAt != 1700000001000 {
+		t.Errorf("Expected the failed item to be discounted, got %+v", job)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultLockLeaseSeconds is the lease of a lock whose request asks for none
+	DefaultLockLeaseSeconds = 60
+	// MaxLockLeaseSeconds is the upper bound of the lease of a lock
+	MaxLockLeaseSeconds = 24 * 60 * 60
+	// maxLockNameLength bounds the name and the owner of a lock
+	maxLockNameLength = 256
+)
+
+// Lock is a lease on a name of an app, e.g. the id of a run, held by a single owner until it is released or its lease
+// runs out. The owner renews the lease by acquiring the lock again.
+type Lock struct {
+	AppId        string `json:"appId"`
+	Name         string `json:"name"`
+	Owner        string `json:"owner"`
+	LeaseSeconds int    `json:"leaseSeconds,omitempty"`
+	// AcquiredAt is the time in milliseconds the owner first acquired the lock, kept across renewals
+	AcquiredAt int64 `json:"acquiredAt,omitempty"`
+	// ExpiresAt is the time in milliseconds the lease runs out unless it is renewed
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// Validate checks the lock and defaults its lease
+func (l *Lock) Validate() error {
+	switch {
+	case l.AppId == "":
+		return errors.New("appId cannot be empty")
+	case l.Name == "" || len(l.Name) > maxLockNameLength:
+		return fmt.Errorf("name must be between 1 and %d characters", maxLockNameLength)
+	case l.Owner == "" || len(l.Owner) > maxLockNameLength:
+		return fmt.Errorf("owner must be between 1 and %d characters", maxLockNameLength)
+	case l.LeaseSeconds < 0 || l.LeaseSeconds > MaxLockLeaseSeconds:
+		return fmt.Errorf("leaseSeconds must be between 1 and %d", MaxLockLeaseSeconds)
+	}
+
+	if l.LeaseSeconds == 0 {
+		l.LeaseSeconds = DefaultLockLeaseSeconds
+	}
+	return nil
+}
+
+// Lease returns the lock acquired at now, keeping the time it was first acquired at if it is a renewal
+func (l Lock) Lease(now time.Time) Lock {
+	millis := now.UnixNano() / int64(time.Millisecond)
+	if l.AcquiredAt == 0 {
+		l.AcquiredAt = millis
+	}
+	l.ExpiresAt = millis + int64(l.LeaseSeconds)*1000
+	return l
+}
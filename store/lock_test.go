@@ -0,0 +1,45 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		lock  Lock
+		valid bool
+	}{
+		{"Valid", Lock{AppId: "test", Name: "run", Owner: "replica-1"}, true},
+		{"NoApp", Lock{Name: "run", Owner: "replica-1"}, false},
+		{"NoName", Lock{AppId: "test", Owner: "replica-1"}, false},
+		{"NoOwner", Lock{AppId: "test", Name: "run"}, false},
+		{"LeaseTooLong", Lock{AppId: "test", Name: "run", Owner: "replica-1", LeaseSeconds: MaxLockLeaseSeconds + 1}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lock := test.lock
+			if err := lock.Validate(); (err == nil) != test.valid {
+				t.Errorf("Expected valid %v, got %v", test.valid, err)
+			}
+			if test.valid && lock.LeaseSeconds != DefaultLockLeaseSeconds {
+				t.Errorf("Expected the default lease, got %d", lock.LeaseSeconds)
+			}
+		})
+	}
+}
+
+func TestLockLease(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	lock := Lock{AppId: "test", Name: "run", Owner: "replica-1", LeaseSeconds: 30}.Lease(now)
+	if lock.AcquiredAt != 1700000000000 || lock.ExpiresAt != 1700000030000 {
+		t.Errorf("Unexpected lease %+v", lock)
+	}
+
+	renewed := lock.Lease(now.Add(10 * time.Second))
+	if renewed.AcquiredAt != lock.AcquiredAt || renewed.ExpiresAt != 1700000040000 {
+		t.Errorf("Expected the renewal to keep the acquisition time and extend the lease, got %+v", renewed)
+	}
+}
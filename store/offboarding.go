@@ -0,0 +1,96 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// MaxOffboardingGracePeriod caps the minutes the pending schedules of an app can be drained for, a week.
+const MaxOffboardingGracePeriod = 7 * 24 * 60
+
+// OffboardingPolicy decides what happens to the pending schedules of an app being offboarded
+type OffboardingPolicy string
+
+const (
+	// DrainPolicy keeps firing the pending schedules until the grace period ends, those left are then cancelled
+	DrainPolicy OffboardingPolicy = "drain"
+	// CancelPolicy stops firing the schedules and cancels the pending ones right away
+	CancelPolicy OffboardingPolicy = "cancel"
+)
+
+// OffboardJob offboards an app: it deactivates the app and cancels, or purges, its schedules
+const OffboardJob JobType = "offboard"
+
+// OffboardRequest offboards an app. Purge deletes the schedules, their history and the callback templates
+// and verified urls of the app, and the app itself, once its schedules are cancelled.
+type OffboardRequest struct {
+	Policy             OffboardingPolicy `json:"policy"`
+	GracePeriodMinutes int               `json:"gracePeriodMinutes,omitempty"`
+	Purge              bool              `json:"purge,omitempty"`
+}
+
+// Validate checks the policy and that a grace period is set for the schedules to be drained over, and only then
+func (r OffboardRequest) Validate() error {
+	switch r.Policy {
+	case DrainPolicy:
+		if r.GracePeriodMinutes < 1 || r.GracePeriodMinutes > MaxOffboardingGracePeriod {
+			return fmt.Errorf("gracePeriodMinutes must be from 1 to %d to drain the schedules, got %d", MaxOffboardingGracePeriod, r.GracePeriodMinutes)
+		}
+	case CancelPolicy:
+		if r.GracePeriodMinutes != 0 {
+			return errors.New("gracePeriodMinutes cannot be set when the schedules are cancelled")
+		}
+	default:
+		return fmt.Errorf("invalid policy %s, the pending schedules can only be drained (%s) or cancelled (%s)", r.Policy, DrainPolicy, CancelPolicy)
+	}
+	return nil
+}
+
+// Offboarding is the offboarding of an app, whose progress is tracked by its job.
+// An app being offboarded rejects the creation and the update of its schedules.
+type Offboarding struct {
+	JobId       gocql.UUID        `json:"jobId"`
+	Policy      OffboardingPolicy `json:"policy"`
+	Purge       bool              `json:"purge,omitempty"`
+	StartedAt   int64             `json:"startedAt"`
+	GraceEndsAt int64             `json:"graceEndsAt"`
+}
+
+// NewOffboarding starts the offboarding of the request, tracked by the job
+func NewOffboarding(request OffboardRequest, jobId gocql.UUID, now time.Time) Offboarding {
+	millis := now.UnixNano() / int64(time.Millisecond)
+	return Offboarding{
+		JobId:       jobId,
+		Policy:      request.Policy,
+		Purge:       request.Purge,
+		StartedAt:   millis,
+		GraceEndsAt: millis + int64(request.GracePeriodMinutes)*int64(time.Minute/time.Millisecond),
+	}
+}
+
+// GraceEnd returns the time the pending schedules stop being drained at
+func (o Offboarding) GraceEnd() time.Time {
+	return time.Unix(0, o.GraceEndsAt*int64(time.Millisecond))
+}
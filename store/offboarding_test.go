@@ -0,0 +1,52 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestOffboardRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request OffboardRequest
+		wantErr bool
+	}{
+		{"Drain", OffboardRequest{Policy: DrainPolicy, GracePeriodMinutes: 60}, false},
+		{"DrainAndPurge", OffboardRequest{Policy: DrainPolicy, GracePeriodMinutes: MaxOffboardingGracePeriod, Purge: true}, false},
+		{"Cancel", OffboardRequest{Policy: CancelPolicy}, false},
+		{"DrainWithoutGracePeriod", OffboardRequest{Policy: DrainPolicy}, true},
+		{"DrainTooLong", OffboardRequest{Policy: DrainPolicy, GracePeriodMinutes: MaxOffboardingGracePeriod + 1}, true},
+		{"CancelWithGracePeriod", OffboardRequest{Policy: CancelPolicy, GracePeriodMinutes: 10}, true},
+		{"UnknownPolicy", OffboardRequest{Policy: "keep"}, true},
+		{"NoPolicy", OffboardRequest{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.request.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewOffboarding(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	jobId := gocql.TimeUUID()
+
+	offboarding := NewOffboarding(OffboardRequest{Policy: DrainPolicy, GracePeriodMinutes: 90, Purge: true}, jobId, now)
+
+	if offboarding.JobId != jobId || offboarding.Policy != DrainPolicy || !offboarding.Purge {
+		t.Errorf("unexpected offboarding %+v", offboarding)
+	}
+	if !offboarding.GraceEnd().Equal(now.Add(90 * time.Minute)) {
+		t.Errorf("expected the grace period to end at %v, got %v", now.Add(90*time.Minute), offboarding.GraceEnd())
+	}
+
+	cancelled := NewOffboarding(OffboardRequest{Policy: CancelPolicy}, jobId, now)
+	if !cancelled.GraceEnd().Equal(now) {
+		t.Errorf("expected no grace period when cancelling, got %v", cancelled.GraceEnd())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+const secondsPerDay = 60 * 60 * 24
+
+// ValidateParking checks that parked schedules are promoted before they are due, which needs a promotion lead
+// shorter than the horizon of parking
+func ValidateParking(config conf.ParkingConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	switch {
+	case config.HorizonDays < 1:
+		return fmt.Errorf("parking horizon of %d days must be at least a day", config.HorizonDays)
+	case config.PromotionDays < 1 || config.PromotionDays >= config.HorizonDays:
+		return fmt.Errorf("parking promotion of %d days must be at least a day and less than the horizon of %d days", config.PromotionDays, config.HorizonDays)
+	case config.Shards < 1:
+		return fmt.Errorf("parking shards %d must be at least 1", config.Shards)
+	}
+	return nil
+}
+
+// ShouldPark tells whether a one time schedule is beyond the horizon of parking
+func ShouldPark(config conf.ParkingConfig, scheduleTime int64, now time.Time) bool {
+	return config.Enabled && scheduleTime > now.Unix()+int64(config.HorizonDays)*secondsPerDay
+}
+
+// ShouldPromote tells whether a parked schedule is close enough to be moved to the time bucketed tables
+func ShouldPromote(config conf.ParkingConfig, scheduleTime int64, now time.Time) bool {
+	return scheduleTime <= now.Unix()+int64(config.PromotionDays)*secondsPerDay
+}
+
+// ParkingDay returns the start of the UTC day of the schedule time, by which the parked schedules are bucketed
+func ParkingDay(scheduleTime int64) int64 {
+	return scheduleTime - scheduleTime%secondsPerDay
+}
+
+// ParkingShard returns the shard of the day bucket to which the schedule is parked
+func (s Schedule) ParkingShard(shards int) int {
+	if shards < 1 {
+		shards = 1
+	}
+	return s.PartitionFor(uint32(shards))
+}
+
+// PromotionDays returns the day buckets searched for schedules to promote, from the day before now so that the
+// schedules left behind by an outage around midnight are still promoted, up to the promotion lead
+func PromotionDays(config conf.ParkingConfig, now time.Time) []time.Time {
+	var days []time.Time
+	last := ParkingDay(now.Unix() + int64(config.PromotionDays)*secondsPerDay)
+	for day := ParkingDay(now.Unix() - secondsPerDay); day <= last; day += secondsPerDay {
+		days = append(days, time.Unix(day, 0))
+	}
+	return days
+}
+
+// ParkingShards returns the shards of parked schedules promoted by a partition of the cron app
+func ParkingShards(shards int, partitionId int, partitions uint32) []int {
+	if partitions == 0 {
+		partitions = 1
+	}
+	var owned []int
+	for shard := 0; shard < shards; shard++ {
+		if shard%int(partitions) == partitionId {
+			owned = append(owned, shard)
+		}
+	}
+	return owned
+}
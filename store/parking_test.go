@@ -0,0 +1,85 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestValidateParking(t *testing.T) {
+	for _, test := range []struct {
+		config conf.ParkingConfig
+		valid  bool
+	}{
+		{conf.ParkingConfig{}, true},
+		{conf.ParkingConfig{Enabled: true, HorizonDays: 30, PromotionDays: 7, Shards: 16}, true},
+		{conf.ParkingConfig{Enabled: true, HorizonDays: 0, PromotionDays: 7, Shards: 16}, false},
+		{conf.ParkingConfig{Enabled: true, HorizonDays: 7, PromotionDays: 7, Shards: 16}, false},
+		{conf.ParkingConfig{Enabled: true, HorizonDays: 30, PromotionDays: 0, Shards: 16}, false},
+		{conf.ParkingConfig{Enabled: true, HorizonDays: 30, PromotionDays: 7, Shards: 0}, false},
+	} {
+		if err := ValidateParking(test.config); (err == nil) != test.valid {
+			t.Errorf("expected %+v to be valid: %t, got %v", test.config, test.valid, err)
+		}
+	}
+}
+
+func TestShouldParkAndPromote(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	config := conf.ParkingConfig{Enabled: true, HorizonDays: 30, PromotionDays: 7, Shards: 4}
+	day := int64(secondsPerDay)
+
+	if ShouldPark(config, now.Unix()+30*day, now) {
+		t.Error("expected a schedule at the horizon not to be parked")
+	}
+	if !ShouldPark(config, now.Unix()+30*day+1, now) {
+		t.Error("expected a schedule beyond the horizon to be parked")
+	}
+	if ShouldPark(conf.ParkingConfig{HorizonDays: 30}, now.Unix()+365*day, now) {
+		t.Error("expected no schedule to be parked with parking disabled")
+	}
+	if !ShouldPromote(config, now.Unix()+7*day, now) || ShouldPromote(config, now.Unix()+7*day+1, now) {
+		t.Error("expected schedules to be promoted within the promotion lead only")
+	}
+}
+
+func TestPromotionDays(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	days := PromotionDays(conf.ParkingConfig{PromotionDays: 2}, now)
+
+	expected := []time.Time{
+		time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC),
+	}
+	if len(days) != len(expected) {
+		t.Fatalf("expected %d days, got %v", len(expected), days)
+	}
+	for i := range expected {
+		if !days[i].Equal(expected[i]) {
+			t.Errorf("expected day %d to be %s, got %s", i, expected[i], days[i].UTC())
+		}
+	}
+}
+
+func TestParkingShards(t *testing.T) {
+	owners := make(map[int]int)
+	for partition := 0; partition < 3; partition++ {
+		for _, shard := range ParkingShards(8, partition, 3) {
+			owners[shard]++
+		}
+	}
+	for shard := 0; shard < 8; shard++ {
+		if owners[shard] != 1 {
+			t.Errorf("expected shard %d to be owned by one partition, got %d", shard, owners[shard])
+		}
+	}
+
+	schedule := Schedule{ScheduleId: gocql.TimeUUID()}
+	if shard := schedule.ParkingShard(8); shard < 0 || shard >= 8 {
+		t.Errorf("expected a shard within 8 shards, got %d", shard)
+	}
+}
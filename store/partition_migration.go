@@ -0,0 +1,44 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import "time"
+
+type MigrationStatus string
+
+const (
+	MigrationRunning   MigrationStatus = "RUNNING"
+	MigrationCompleted MigrationStatus = "COMPLETED"
+	MigrationFailed    MigrationStatus = "FAILED"
+)
+
+// PartitionMigration tracks the move of the pending schedules of an app to a larger partition count.
+// Cursor is the last time bucket fully migrated, a resumed migration starts after it.
+type PartitionMigration struct {
+	AppId          string          `json:"appId"`
+	FromPartitions uint32          `json:"fromPartitions"`
+	ToPartitions   uint32          `json:"toPartitions"`
+	Status         MigrationStatus `json:"status"`
+	Migrated       int             `json:"migrated"`
+	Cursor         time.Time       `json:"cursor"`
+	StartedAt      time.Time       `json:"startedAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	Error          string          `json:"error,omitempty"`
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// PausePolicy decides what happens to the runs of a recurring schedule which fall within a pause.
+type PausePolicy string
+
+const (
+	// SkipPausedRuns drops the runs which fall within the pause, this is the default
+	SkipPausedRuns PausePolicy = "skip"
+	// QueuePausedRuns fires every run which fell within the pause upon resume
+	QueuePausedRuns PausePolicy = "queue"
+	// CollapsePausedRuns fires a single run upon resume if any run fell within the pause
+	CollapsePausedRuns PausePolicy = "collapse"
+)
+
+// MaxQueuedRuns caps the number of runs queued upon resume of a schedule with QueuePausedRuns policy.
+const MaxQueuedRuns = 100
+
+// validatePausePolicy checks that the optional pause policy is one of the supported policies
+func validatePausePolicy(policy PausePolicy) string {
+	switch policy {
+	case "", SkipPausedRuns, QueuePausedRuns, CollapsePausedRuns:
+		return ""
+	default:
+		return fmt.Sprintf("invalid pausePolicy: %s, must be one of skip, queue or collapse", policy)
+	}
+}
+
+// MissedRuns returns the times of the runs which were missed between the pause of the schedule and until,
+// as per its pause policy.
+// Returns nil if the runs are to be skipped or the pause time of the schedule is not known.
+func (s Schedule) MissedRuns(until time.Time) []time.Time {
+	if s.PausedAt == 0 || (s.PausePolicy != QueuePausedRuns && s.PausePolicy != CollapsePausedRuns) {
+		return nil
+	}
+
+	recurrence, errs := s.GetRecurrence()
+	if len(errs) != 0 {
+		return nil
+	}
+
+	var missed []time.Time
+	for _, t := range Preview(recurrence, time.Unix(s.PausedAt, 0), MaxQueuedRuns) {
+		if !t.Before(until) {
+			break
+		}
+		missed = append(missed, t)
+	}
+
+	if s.PausePolicy == CollapsePausedRuns && len(missed) > 1 {
+		return missed[len(missed)-1:]
+	}
+
+	return missed
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_MissedRuns(t *testing.T) {
+	pausedAt := time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC)
+	until := time.Date(2023, 6, 1, 13, 30, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name     string
+		schedule Schedule
+		expected []time.Time
+	}{
+		{
+			name:     "skip by default",
+			schedule: Schedule{CronExpression: "0 * * * *", PausedAt: pausedAt.Unix()},
+		},
+		{
+			name:     "unknown pause time",
+			schedule: Schedule{CronExpression: "0 * * * *", PausePolicy: QueuePausedRuns},
+		},
+		{
+			name:     "queue",
+			schedule: Schedule{Every: "1h", Anchor: pausedAt.Add(-30 * time.Minute).Unix(), PausePolicy: QueuePausedRuns, PausedAt: pausedAt.Unix()},
+			expected: []time.Time{
+				time.Date(2023, 6, 1, 11, 0, 0, 0, time.UTC),
+				time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2023, 6, 1, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "collapse",
+			schedule: Schedule{Every: "1h", Anchor: pausedAt.Add(-30 * time.Minute).Unix(), PausePolicy: CollapsePausedRuns, PausedAt: pausedAt.Unix()},
+			expected: []time.Time{time.Date(2023, 6, 1, 13, 0, 0, 0, time.UTC)},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			missed := test.schedule.MissedRuns(until)
+			if len(missed) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, missed)
+			}
+			for i := range missed {
+				if !missed[i].Equal(test.expected[i]) {
+					t.Errorf("expected %v, got %v", test.expected[i], missed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePausePolicy(t *testing.T) {
+	for policy, valid := range map[PausePolicy]bool{
+		"":                 true,
+		SkipPausedRuns:     true,
+		QueuePausedRuns:    true,
+		CollapsePausedRuns: true,
+		"replay":           false,
+	} {
+		if got := validatePausePolicy(policy) == ""; got != valid {
+			t.Errorf("validatePausePolicy(%q) valid = %v, expected %v", policy, got, valid)
+		}
+	}
+}
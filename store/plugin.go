@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"plugin"
+)
+
+// Plugin is the interface to be implemented by callback types that deliver the schedules themselves.
+// Plugins are registered with RegisterPlugin and executed on the worker pool of their type, the error returned
+// by Execute decides the status of the run.
+type Plugin interface {
+	GetType() string
+	GetDetails() (string, error)
+	Marshal(map[string]interface{}) error
+	Validate() error
+	Execute(schedule Schedule) error
+	json.Unmarshaler
+}
+
+// AppPlugin is implemented by the plugins that execute with the configuration of the app of the schedule,
+// such as the connections of the cloud messaging callbacks. ExecuteFor is called instead of Execute.
+type AppPlugin interface {
+	ExecuteFor(schedule Schedule, app App) error
+}
+
+// PluginFactory creates a new instance of a plugin
+type PluginFactory func() Plugin
+
+// pluginCallback adapts a plugin to the callback interface
+type pluginCallback struct {
+	Plugin
+}
+
+func (p *pluginCallback) Invoke(wrapper ScheduleWrapper) error {
+	return pluginQueue(p.GetType()).Push(wrapper)
+}
+
+func (p *pluginCallback) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Plugin)
+}
+
+// RegisterCallback adds or replaces the factory of a callback type.
+// Callbacks must be registered before the scheduler is created.
+func RegisterCallback(callbackType string, factory Factory) error {
+	if callbackType == "" {
+		return errors.New("callback type cannot be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("nil factory for callback type %s", callbackType)
+	}
+
+	Registry[callbackType] = factory
+	return nil
+}
+
+// RegisterPlugin adds or replaces the factory of a plugin callback type.
+// Plugins must be registered before the scheduler is created.
+func RegisterPlugin(callbackType string, factory PluginFactory) error {
+	if factory == nil {
+		return fmt.Errorf("nil factory for plugin type %s", callbackType)
+	}
+
+	return RegisterCallback(callbackType, func() Callback {
+		return &pluginCallback{Plugin: factory()}
+	})
+}
+
+// LoadPlugin opens the Go plugin at the supplied path and registers the plugin callback type exported by it.
+// The plugin must export a function NewPlugin of type func() store.Plugin, the callback type is taken from GetType.
+func LoadPlugin(path string) (string, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening plugin %s: %w", path, err)
+	}
+
+	symbol, err := p.Lookup("NewPlugin")
+	if err != nil {
+		return "", fmt.Errorf("error looking up NewPlugin in plugin %s: %w", path, err)
+	}
+
+	factory, ok := symbol.(func() Plugin)
+	if !ok {
+		return "", fmt.Errorf("NewPlugin in plugin %s is of type %T, expected func() store.Plugin", path, symbol)
+	}
+
+	callbackType := factory().GetType()
+	return callbackType, RegisterPlugin(callbackType, factory)
+}
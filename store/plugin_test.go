@@ -0,0 +1,175 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestRegisterCallback(t *testing.T) {
+	if err := RegisterCallback("", func() Callback { return &HttpCallback{} }); err == nil {
+		t.Errorf("Expected error for empty callback type")
+	}
+
+	if err := RegisterCallback("nilFactory", nil); err == nil {
+		t.Errorf("Expected error for nil factory")
+	}
+
+	if err := RegisterPlugin("nilPlugin", nil); err == nil {
+		t.Errorf("Expected error for nil plugin factory")
+	}
+}
+
+func TestRegisterSidecar(t *testing.T) {
+	var received sidecarRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		if received.Payload == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if err := RegisterSidecar("sqs", server.URL, time.Second); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer delete(Registry, "sqs")
+
+	callback, err := CreateCallbackFromRawMessage(json.RawMessage(`{"type":"sqs","details":{"queue":"orders"}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if callback.GetType() != "sqs" {
+		t.Errorf("Expected callback type sqs, got %s", callback.GetType())
+	}
+	if err = callback.Validate(); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+	if details, _ := callback.GetDetails(); details != `{"queue":"orders"}` {
+		t.Errorf("Unexpected details %s", details)
+	}
+
+	raw, err := json.Marshal(callback)
+	if err != nil || string(raw) != `{"type":"sqs","details":{"queue":"orders"}}` {
+		t.Errorf("Unexpected json %s with error %v", raw, err)
+	}
+
+	plugin, ok := callback.(Plugin)
+	if !ok {
+		t.Fatalf("Expected sidecar callback to be a plugin")
+	}
+
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), AppId: "test", Payload: "{}", Callback: callback}
+	if err = plugin.Execute(schedule); err != nil {
+		t.Errorf("Unexpected execution error %v", err)
+	}
+	if received.ScheduleId != schedule.ScheduleId.String() || string(received.Details) != `{"queue":"orders"}` {
+		t.Errorf("Unexpected sidecar request %+v", received)
+	}
+
+	schedule.Payload = "fail"
+	if err = plugin.Execute(schedule); err == nil {
+		t.Errorf("Expected execution error for non 2xx response")
+	}
+}
+
+func TestSidecarCallback_FromCassandraMap(t *testing.T) {
+	if err := RegisterSidecar("sqs", "http://localhost:9000/execute", time.Second); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer delete(Registry, "sqs")
+
+	m := map[string]interface{}{
+		"app_id":              "test",
+		"partition_id":        0,
+		"callback_type":       "sqs",
+		"callback_details":    `{"queue":"orders"}`,
+		"payload":             "{}",
+		"schedule_time_group": time.Now(),
+		"schedule_time":       time.Now(),
+		"schedule_id":         gocql.TimeUUID(),
+	}
+
+	s := &Schedule{}
+	if err := s.CreateScheduleFromCassandraMap(m); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if string(s.CallbackRaw) != `{"type":"sqs","details":{"queue":"orders"}}` {
+		t.Errorf("Unexpected callback raw %s", s.CallbackRaw)
+	}
+}
+
+func TestSidecarCallback_Validate(t *testing.T) {
+	callback := &SidecarCallback{Type: "sqs"}
+	if err := callback.Validate(); err == nil {
+		t.Errorf("Expected error for empty details")
+	}
+
+	callback.Details = json.RawMessage(`{"queue"`)
+	if err := callback.Validate(); err == nil {
+		t.Errorf("Expected error for invalid details")
+	}
+}
+
+func TestPluginTaskQueues(t *testing.T) {
+	registry := Registry
+	Registry = map[string]Factory{"http": func() Callback { return &HttpCallback{} }}
+	defer func() { Registry = registry }()
+
+	for _, callbackType := range []string{"sqs", "kafka"} {
+		if err := RegisterSidecar(callbackType, "http://localhost:9000/execute", time.Second); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+	}
+
+	task := &Task{Conf: conf.NewConfig(conf.WithCallbackPluginConfig(conf.CallbackPluginConfig{
+		Routines: 1,
+		Pools:    map[string]conf.CallbackPoolConfig{"kafka": {Routines: 1, QueueSize: 1}},
+	}))}
+	task.InitTaskQueues()
+
+	if len(PluginTaskQueues) != 2 || PluginTaskQueues["sqs"] == nil || PluginTaskQueues["kafka"] == nil {
+		t.Fatalf("Expected a queue for each plugin type, got %v", PluginTaskQueues)
+	}
+
+	kafka := Schedule{Callback: Registry["kafka"]()}
+	if err := kafka.Callback.Invoke(ScheduleWrapper{Schedule: kafka}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := kafka.Callback.Invoke(ScheduleWrapper{Schedule: kafka}); err != ErrQueueFull {
+		t.Errorf("Expected the kafka queue to be full, got %v", err)
+	}
+	if PluginTaskQueues["kafka"].Len() != 1 || PluginTaskQueues["sqs"].Len() != 0 || PluginTaskQueue.Len() != 0 {
+		t.Errorf("Expected the kafka schedule in the kafka queue only")
+	}
+	if CallbackBacklog() != 1 {
+		t.Errorf("Expected a backlog of 1, got %d", CallbackBacklog())
+	}
+}
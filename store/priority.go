@@ -0,0 +1,174 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Priority decides the order in which the callbacks of the schedules due at the same time are executed.
+type Priority string
+
+const (
+	// HighPriority callbacks are executed first and taken ahead of the others when the workers are busy
+	HighPriority Priority = "high"
+	// NormalPriority is the default
+	NormalPriority Priority = "normal"
+	// LowPriority callbacks are executed once no other callback is waiting
+	LowPriority Priority = "low"
+)
+
+// Priorities lists the priorities from the highest to the lowest
+var Priorities = []Priority{HighPriority, NormalPriority, LowPriority}
+
+// validatePriority checks that the optional priority is one of the supported priorities
+func validatePriority(priority Priority) string {
+	switch priority {
+	case "", HighPriority, NormalPriority, LowPriority:
+		return ""
+	default:
+		return fmt.Sprintf("invalid priority: %s, must be one of high, normal or low", priority)
+	}
+}
+
+// GetPriority returns the priority of the schedule, normal if none was set
+func (s Schedule) GetPriority() Priority {
+	if s.Priority == "" {
+		return NormalPriority
+	}
+	return s.Priority
+}
+
+// rank orders the priorities, lower is executed first
+func (p Priority) rank() int {
+	switch p {
+	case HighPriority:
+		return 0
+	case LowPriority:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SortByPriority orders the schedules from the highest to the lowest priority,
+// keeping the order of the schedules with the same priority.
+func SortByPriority(schedules []Schedule) {
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].GetPriority().rank() < schedules[j].GetPriority().rank()
+	})
+}
+
+// ErrQueueFull is returned when a schedule is pushed to a bounded queue which is already full
+var ErrQueueFull = errors.New("callback queue is full")
+
+// PriorityQueue hands the schedules to the callback workers, higher priorities first.
+// Push blocks until a worker takes the schedule, so when every worker is busy the next free worker
+// takes a waiting high priority schedule before any normal or low priority one.
+// A bounded queue instead holds up to its limit of schedules without blocking and rejects the others.
+type PriorityQueue struct {
+	high   chan ScheduleWrapper
+	normal chan ScheduleWrapper
+	low    chan ScheduleWrapper
+	// waiting counts the schedules pushed and not yet taken by a worker
+	waiting int64
+	// limit bounds the waiting schedules of a bounded queue, 0 for an unbuffered queue
+	limit int64
+}
+
+// NewPriorityQueue creates an unbuffered priority queue
+func NewPriorityQueue() *PriorityQueue {
+	return NewBoundedPriorityQueue(0)
+}
+
+// NewBoundedPriorityQueue creates a priority queue holding up to limit waiting schedules,
+// an unbuffered one if the limit is not positive
+func NewBoundedPriorityQueue(limit int) *PriorityQueue {
+	if limit < 0 {
+		limit = 0
+	}
+	return &PriorityQueue{
+		high:   make(chan ScheduleWrapper, limit),
+		normal: make(chan ScheduleWrapper, limit),
+		low:    make(chan ScheduleWrapper, limit),
+		limit:  int64(limit),
+	}
+}
+
+// Push hands the schedule of the wrapper to a worker. It waits for a worker to take it unless the queue is
+// bounded, in which case it returns ErrQueueFull if the limit of waiting schedules is reached.
+func (q *PriorityQueue) Push(wrapper ScheduleWrapper) error {
+	wrapper.EnqueuedAt = time.Now()
+	if waiting := atomic.AddInt64(&q.waiting, 1); q.limit > 0 && waiting > q.limit {
+		atomic.AddInt64(&q.waiting, -1)
+		return ErrQueueFull
+	}
+
+	switch wrapper.Schedule.GetPriority() {
+	case HighPriority:
+		q.high <- wrapper
+	case LowPriority:
+		q.low <- wrapper
+	default:
+		q.normal <- wrapper
+	}
+	return nil
+}
+
+// Len returns the number of schedules waiting for a worker
+func (q *PriorityQueue) Len() int {
+	return int(atomic.LoadInt64(&q.waiting))
+}
+
+// Pop waits for a schedule, preferring the highest priority among the waiting ones
+func (q *PriorityQueue) Pop() ScheduleWrapper {
+	wrapper := q.pop()
+	atomic.AddInt64(&q.waiting, -1)
+	return wrapper
+}
+
+func (q *PriorityQueue) pop() ScheduleWrapper {
+	select {
+	case wrapper := <-q.high:
+		return wrapper
+	default:
+	}
+
+	select {
+	case wrapper := <-q.high:
+		return wrapper
+	case wrapper := <-q.normal:
+		return wrapper
+	default:
+	}
+
+	select {
+	case wrapper := <-q.high:
+		return wrapper
+	case wrapper := <-q.normal:
+		return wrapper
+	case wrapper := <-q.low:
+		return wrapper
+	}
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidatePriority(t *testing.T) {
+	for _, test := range []struct {
+		priority Priority
+		valid    bool
+	}{
+		{"", true},
+		{HighPriority, true},
+		{NormalPriority, true},
+		{LowPriority, true},
+		{"urgent", false},
+	} {
+		if got := validatePriority(test.priority) == ""; got != test.valid {
+			t.Errorf("priority %q: expected valid %v, got %v", test.priority, test.valid, got)
+		}
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	schedules := []Schedule{
+		{Payload: "low", Priority: LowPriority},
+		{Payload: "default"},
+		{Payload: "high-1", Priority: HighPriority},
+		{Payload: "normal", Priority: NormalPriority},
+		{Payload: "high-2", Priority: HighPriority},
+	}
+
+	SortByPriority(schedules)
+
+	expected := []string{"high-1", "high-2", "default", "normal", "low"}
+	for i, schedule := range schedules {
+		if schedule.Payload != expected[i] {
+			t.Errorf("position %d: expected %s, got %s", i, expected[i], schedule.Payload)
+		}
+	}
+}
+
+func TestPriorityQueue_PrefersHigherPriority(t *testing.T) {
+	queue := NewPriorityQueue()
+
+	for _, priority := range []Priority{LowPriority, NormalPriority, HighPriority} {
+		go queue.Push(ScheduleWrapper{Schedule: Schedule{Priority: priority}})
+	}
+
+	// wait for all the producers to block on the queue
+	time.Sleep(50 * time.Millisecond)
+
+	for _, expected := range []Priority{HighPriority, NormalPriority, LowPriority} {
+		wrapper := queue.Pop()
+		if wrapper.Schedule.Priority != expected {
+			t.Errorf("expected %s, got %s", expected, wrapper.Schedule.Priority)
+		}
+		if wrapper.EnqueuedAt.IsZero() {
+			t.Errorf("expected enqueue time to be set for %s", expected)
+		}
+	}
+}
+
+func TestBoundedPriorityQueue(t *testing.T) {
+	queue := NewBoundedPriorityQueue(2)
+
+	for _, priority := range []Priority{LowPriority, HighPriority} {
+		if err := queue.Push(ScheduleWrapper{Schedule: Schedule{Priority: priority}}); err != nil {
+			t.Fatalf("expected %s to be queued without a worker, got %v", priority, err)
+		}
+	}
+	if err := queue.Push(ScheduleWrapper{}); err != ErrQueueFull {
+		t.Fatalf("expected a full queue, got %v", err)
+	}
+	if queue.Len() != 2 {
+		t.Errorf("expected 2 waiting schedules, got %d", queue.Len())
+	}
+
+	if wrapper := queue.Pop(); wrapper.Schedule.Priority != HighPriority {
+		t.Errorf("expected %s, got %s", HighPriority, wrapper.Schedule.Priority)
+	}
+	if err := queue.Push(ScheduleWrapper{}); err != nil {
+		t.Errorf("expected room after a pop, got %v", err)
+	}
+	if queue.Len() != 2 {
+		t.Errorf("expected 2 waiting schedules, got %d", queue.Len())
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	queue := NewBoundedPriorityQueue(b.N)
+	priorities := []Priority{LowPriority, NormalPriority, HighPriority}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = queue.Push(ScheduleWrapper{Schedule: Schedule{Priority: priorities[i%len(priorities)]}})
+	}
+	for i := 0; i < b.N; i++ {
+		queue.Pop()
+	}
+}
+
+func BenchmarkSortByPriority(b *testing.B) {
+	priorities := []Priority{LowPriority, NormalPriority, HighPriority}
+	schedules := make([]Schedule, 1000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for j := range schedules {
+			schedules[j].Priority = priorities[(i+j)%len(priorities)]
+		}
+		b.StartTimer()
+		SortByPriority(schedules)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ProbeMode tells what a failed reachability probe of the callback urls of a schedule being created does
+type ProbeMode string
+
+const (
+	NoProbe     ProbeMode = ""
+	ProbeWarn   ProbeMode = "warn"   // the schedule is created and the unreachable urls are returned as warnings
+	ProbeReject ProbeMode = "reject" // the schedule is rejected
+)
+
+// ParseProbeMode parses the probe parameter of a create request
+func ParseProbeMode(s string) (ProbeMode, error) {
+	switch mode := ProbeMode(s); mode {
+	case NoProbe, ProbeWarn, ProbeReject:
+		return mode, nil
+	default:
+		return NoProbe, fmt.Errorf("invalid probe %s, it must be one of warn or reject", s)
+	}
+}
+
+// ProbeUrl sends a HEAD request to the url to find out whether it can be reached.
+// Any response, whatever its status, counts as reachable: the probe catches unknown hosts, refused connections and
+// timeouts rather than endpoints not answering HEAD. Redirects are not followed.
+func ProbeUrl(client *http.Client, rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("not an absolute http(s) url")
+	}
+
+	req, err := http.NewRequest(http.MethodHead, rawUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := noRedirects.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
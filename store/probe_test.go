@@ -0,0 +1,43 @@
+package store
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseProbeMode(t *testing.T) {
+	for _, value := range []string{"", "warn", "reject"} {
+		if mode, err := ParseProbeMode(value); err != nil || string(mode) != value {
+			t.Errorf("expected %q to parse, got %q %v", value, mode, err)
+		}
+	}
+	if _, err := ParseProbeMode("always"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
+
+func TestProbeUrl(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	client := &http.Client{Timeout: time.Second}
+
+	if err := ProbeUrl(client, server.URL+"/callback"); err != nil {
+		t.Errorf("expected a url answering with any status to be reachable, got %v", err)
+	}
+	if method != http.MethodHead {
+		t.Errorf("expected a HEAD request, got %s", method)
+	}
+
+	server.Close()
+	if err := ProbeUrl(client, server.URL+"/callback"); err == nil {
+		t.Errorf("expected a closed server to be unreachable")
+	}
+	if err := ProbeUrl(client, "callback"); err == nil {
+		t.Errorf("expected a relative url to be rejected")
+	}
+}
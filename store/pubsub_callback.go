@@ -0,0 +1,286 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/myntra/goscheduler/util"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const maxOrderingKeyLength = 1024
+
+// pubSubTopicPattern matches the names of the Pub/Sub topics
+var pubSubTopicPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9._~%+-]{2,254}$`)
+
+// PubSubConnection is the Google Cloud project and the credentials the pubsub callbacks of an app publish with
+type PubSubConnection struct {
+	ProjectId string `json:"projectId"`
+	// Credentials of the connection: metadata (the default), serviceAccount or token
+	Credentials string `json:"credentials,omitempty"`
+	// Secret reference of the json key of the service account, or of the access token
+	Secret string `json:"secret,omitempty"`
+	// Endpoint of the Pub/Sub API, a regional endpoint to keep the order of the ordered messages or the http url of an
+	// emulator
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// Validate checks the project, the credentials and the endpoint of the connection
+func (c *PubSubConnection) Validate() error {
+	if c.ProjectId == "" {
+		return errors.New("pubSub projectId cannot be empty")
+	}
+
+	switch c.Credentials {
+	case "", MetadataCredentials:
+		if c.Secret != "" {
+			return errors.New("pubSub metadata credentials take no secret")
+		}
+	case ServiceAccountCredentials, TokenCredentials:
+		if !HasSecretRefs(c.Secret) {
+			return fmt.Errorf("pubSub %s credentials must reference a secret as {{secret:NAME}}", c.Credentials)
+		}
+		if err := ValidateSecretRefs(c.Secret); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid pubSub credentials %s, must be one of %s, %s or %s",
+			c.Credentials, MetadataCredentials, ServiceAccountCredentials, TokenCredentials)
+	}
+
+	if c.Endpoint != "" {
+		if _, err := url.ParseRequestURI(c.Endpoint); err != nil {
+			return fmt.Errorf("invalid pubSub endpoint %s", c.Endpoint)
+		}
+	}
+	return nil
+}
+
+// secret resolves the json key of the service account or the access token of the connection, none for the metadata
+// credentials
+func (c *PubSubConnection) secret() (string, error) {
+	if c.Credentials == ServiceAccountCredentials || c.Credentials == TokenCredentials {
+		return ResolveSecrets(c.Secret)
+	}
+	return "", nil
+}
+
+// options returns the options of the client of the connection. An http endpoint is an emulator, which is reached
+// without credentials.
+func (c *PubSubConnection) options(secret string) []option.ClientOption {
+	var options []option.ClientOption
+	if c.Endpoint != "" {
+		endpoint, _ := url.Parse(c.Endpoint)
+		if endpoint.Scheme == "http" {
+			return []option.ClientOption{
+				option.WithEndpoint(endpoint.Host),
+				option.WithoutAuthentication(),
+				option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+			}
+		}
+		host := endpoint.Host
+		if endpoint.Port() == "" {
+			host += ":443"
+		}
+		options = append(options, option.WithEndpoint(host))
+	}
+
+	switch c.Credentials {
+	case ServiceAccountCredentials:
+		return append(options, option.WithCredentialsJSON([]byte(secret)))
+	case TokenCredentials:
+		return append(options, option.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: secret})))
+	default:
+		return append(options, option.WithTokenSource(google.ComputeTokenSource("", pubsub.ScopePubSub)))
+	}
+}
+
+// pubSubClient is the client of a connection, along with the topics published to through it
+type pubSubClient struct {
+	secret string
+	client *pubsub.Client
+	topics map[string]*pubsub.Topic
+}
+
+func (c *pubSubClient) close() {
+	for _, topic := range c.topics {
+		topic.Stop()
+	}
+	_ = c.client.Close()
+}
+
+// pubSubClients keeps a client per connection, replaced once the secret of the connection is rotated
+var pubSubClients = struct {
+	sync.Mutex
+	clients map[PubSubConnection]*pubSubClient
+}{clients: map[PubSubConnection]*pubSubClient{}}
+
+// topic returns the topic of the connection, with the messages of every ordering key published in order
+func (c *PubSubConnection) topic(name string) (*pubsub.Topic, error) {
+	secret, err := c.secret()
+	if err != nil {
+		return nil, err
+	}
+
+	pubSubClients.Lock()
+	defer pubSubClients.Unlock()
+
+	cached, ok := pubSubClients.clients[*c]
+	if ok && cached.secret != secret {
+		cached.close()
+		ok = false
+	}
+	if !ok {
+		client, err := pubsub.NewClient(context.Background(), c.ProjectId, c.options(secret)...)
+		if err != nil {
+			return nil, err
+		}
+		cached = &pubSubClient{secret: secret, client: client, topics: make(map[string]*pubsub.Topic)}
+		pubSubClients.clients[*c] = cached
+	}
+
+	topic, ok := cached.topics[name]
+	if !ok {
+		topic = cached.client.Topic(name)
+		topic.EnableMessageOrdering = true
+		cached.topics[name] = topic
+	}
+	return topic, nil
+}
+
+// PubSubDetails are the topic of a pubsub callback and the ordering key and attributes of its messages
+type PubSubDetails struct {
+	Topic string `json:"topic"`
+	// Messages with the same ordering key are delivered in order to the subscriptions with message ordering
+	OrderingKey string            `json:"orderingKey,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+// PubSubCallback publishes the payload of every run to a Google Cloud Pub/Sub topic of the project of the app
+type PubSubCallback struct {
+	Type    string        `json:"type"`
+	Details PubSubDetails `json:"details"`
+}
+
+func (p *PubSubCallback) GetType() string {
+	return p.Type
+}
+
+func (p *PubSubCallback) GetDetails() (string, error) {
+	details, err := json.Marshal(p.Details)
+	return string(details), err
+}
+
+func (p *PubSubCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	details, ok := m["callback_details"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_details")
+	}
+
+	p.Type = callbackType
+	return json.Unmarshal([]byte(details), &p.Details)
+}
+
+// UnmarshalJSON Implement UnmarshalJSON for PubSubCallback
+func (p *PubSubCallback) UnmarshalJSON(data []byte) error {
+	type Alias PubSubCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+func (p *PubSubCallback) Validate() error {
+	if !pubSubTopicPattern.MatchString(p.Details.Topic) || strings.HasPrefix(p.Details.Topic, "goog") {
+		return fmt.Errorf("invalid pubsub topic %s", p.Details.Topic)
+	}
+	if len(p.Details.OrderingKey) > maxOrderingKeyLength {
+		return fmt.Errorf("orderingKey cannot be longer than %d bytes", maxOrderingKeyLength)
+	}
+	for key := range p.Details.Attributes {
+		if key == "" || strings.HasPrefix(key, "goog") {
+			return fmt.Errorf("invalid pubsub attribute %q", key)
+		}
+	}
+	return nil
+}
+
+// Execute fails as the pubsub callbacks publish with the connection of their app
+func (p *PubSubCallback) Execute(schedule Schedule) error {
+	return errors.New("pubsub callbacks are executed with the connection of their app")
+}
+
+// ExecuteFor publishes the payload of the run to the topic with the connection of the app, along with the ids of the
+// run in its attributes
+func (p *PubSubCallback) ExecuteFor(schedule Schedule, app App) error {
+	connection := app.Configuration.PubSub
+	if connection == nil {
+		return fmt.Errorf("app %s has no pubSub connection", app.AppId)
+	}
+
+	attributes := map[string]string{"scheduleId": schedule.ScheduleId.String(), "appId": schedule.AppId}
+	if !util.IsZeroUUID(schedule.ParentScheduleId) {
+		attributes["parentScheduleId"] = schedule.ParentScheduleId.String()
+	}
+	for key, value := range p.Details.Attributes {
+		attributes[key] = value
+	}
+
+	topic, err := connection.topic(p.Details.Topic)
+	if err != nil {
+		return fmt.Errorf("error getting the pubsub client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cloudTimeout)
+	defer cancel()
+	result := topic.Publish(ctx, &pubsub.Message{
+		Data:        []byte(schedule.Payload),
+		Attributes:  attributes,
+		OrderingKey: p.Details.OrderingKey,
+	})
+	if _, err := result.Get(ctx); err != nil {
+		// the failed publish pauses its ordering key until it is resumed
+		if p.Details.OrderingKey != "" {
+			topic.ResumePublish(p.Details.OrderingKey)
+		}
+		return fmt.Errorf("pubsub publish to topic %s failed: %w", p.Details.Topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/pubsub/apiv1/pubsubpb"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/gocql/gocql"
+)
+
+func TestPubSubConnection_Validate(t *testing.T) {
+	for _, test := range []struct {
+		connection PubSubConnection
+		valid      bool
+	}{
+		{PubSubConnection{ProjectId: "orders"}, true},
+		{PubSubConnection{ProjectId: "orders", Credentials: MetadataCredentials}, true},
+		{PubSubConnection{ProjectId: "orders", Credentials: ServiceAccountCredentials, Secret: "{{secret:PUBSUB_KEY}}"}, true},
+		{PubSubConnection{ProjectId: "orders", Credentials: TokenCredentials, Secret: "{{secret:PUBSUB_TOKEN}}", Endpoint: "http://localhost:8085"}, true},
+		{PubSubConnection{}, false},
+		{PubSubConnection{ProjectId: "orders", Secret: "{{secret:PUBSUB_KEY}}"}, false},
+		{PubSubConnection{ProjectId: "orders", Credentials: ServiceAccountCredentials, Secret: "key"}, false},
+		{PubSubConnection{ProjectId: "orders", Credentials: "apiKey"}, false},
+		{PubSubConnection{ProjectId: "orders", Endpoint: "pubsub"}, false},
+	} {
+		if err := test.connection.Validate(); (err == nil) != test.valid {
+			t.Errorf("connection %+v: expected valid %v, got %v", test.connection, test.valid, err)
+		}
+	}
+}
+
+func TestPubSubCallback_Validate(t *testing.T) {
+	for _, test := range []struct {
+		details PubSubDetails
+		valid   bool
+	}{
+		{PubSubDetails{Topic: "order-reminders"}, true},
+		{PubSubDetails{Topic: "order-reminders", OrderingKey: "order-42", Attributes: map[string]string{"kind": "reminder"}}, true},
+		{PubSubDetails{}, false},
+		{PubSubDetails{Topic: "or"}, false},
+		{PubSubDetails{Topic: "google-reminders"}, false},
+		{PubSubDetails{Topic: "projects/orders/topics/reminders"}, false},
+		{PubSubDetails{Topic: "order-reminders", OrderingKey: strings.Repeat("k", maxOrderingKeyLength+1)}, false},
+		{PubSubDetails{Topic: "order-reminders", Attributes: map[string]string{"googclient_id": "1"}}, false},
+	} {
+		callback := PubSubCallback{Type: "pubsub", Details: test.details}
+		if err := callback.Validate(); (err == nil) != test.valid {
+			t.Errorf("details %+v: expected valid %v, got %v", test.details, test.valid, err)
+		}
+	}
+}
+
+func TestPubSubCallback_ExecuteFor(t *testing.T) {
+	defer SetSecretProvider(nil)
+	secrets := &countingSecrets{secrets: map[string]string{"PUBSUB_TOKEN": "ya29.token"}}
+	SetSecretProvider(secrets)
+
+	server := pstest.NewServer()
+	defer server.Close()
+	if _, err := server.GServer.CreateTopic(context.Background(), &pubsubpb.Topic{Name: "projects/shop/topics/order-reminders"}); err != nil {
+		t.Fatal(err)
+	}
+
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: gocql.TimeUUID(), AppId: "orders", Payload: `{"orderId":42}`}
+	callback := PubSubCallback{Type: "pubsub", Details: PubSubDetails{Topic: "order-reminders", OrderingKey: "order-42", Attributes: map[string]string{"kind": "reminder"}}}
+
+	if err := callback.ExecuteFor(schedule, App{AppId: "orders"}); err == nil {
+		t.Error("expected an error for an app without a pubSub connection")
+	}
+
+	connection := &PubSubConnection{
+		ProjectId:   "shop",
+		Credentials: TokenCredentials,
+		Secret:      "{{secret:PUBSUB_TOKEN}}",
+		Endpoint:    "http://" + server.Addr,
+	}
+	app := App{AppId: "orders", Configuration: Configuration{PubSub: connection}}
+	if err := callback.ExecuteFor(schedule, app); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("expected one message, got %+v", messages)
+	}
+	message := messages[0]
+	if string(message.Data) != schedule.Payload {
+		t.Errorf("expected the payload as the data, got %q", message.Data)
+	}
+	if message.OrderingKey != "order-42" || message.Attributes["kind"] != "reminder" ||
+		message.Attributes["scheduleId"] != schedule.ScheduleId.String() ||
+		message.Attributes["parentScheduleId"] != schedule.ParentScheduleId.String() {
+		t.Errorf("unexpected message %+v", message)
+	}
+
+	// the client is kept until the secret of the connection is rotated
+	client := pubSubClients.clients[*connection]
+	if _, err := connection.topic("order-reminders"); err != nil || pubSubClients.clients[*connection] != client {
+		t.Errorf("expected the client to be reused, got error %v", err)
+	}
+	secrets.secrets["PUBSUB_TOKEN"] = "ya29.rotated"
+	if _, err := connection.topic("order-reminders"); err != nil || pubSubClients.clients[*connection] == client {
+		t.Errorf("expected a new client for the rotated secret, got error %v", err)
+	}
+
+	callback.Details.Topic = "missing-reminders"
+	if err := callback.ExecuteFor(schedule, app); err == nil {
+		t.Error("expected an error for a missing topic")
+	}
+}
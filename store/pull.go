@@ -0,0 +1,133 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/util"
+)
+
+// PullCallback is the callback of the schedules whose consumers poll for their due runs and acknowledge them,
+// instead of being called back. The due runs are kept until they are acknowledged or run out of deliveries.
+type PullCallback struct {
+	Type string `json:"type"`
+}
+
+// DueRun is a run of a pull callback kept for the consumers of its app
+type DueRun struct {
+	AppId            string     `json:"appId"`
+	ScheduleId       gocql.UUID `json:"scheduleId"`
+	ParentScheduleId string     `json:"parentScheduleId,omitempty"`
+	Payload          string     `json:"payload"`
+	// ScheduleTime is the time in seconds the run was due at
+	ScheduleTime int64 `json:"scheduleTime"`
+	// Deliveries is the number of times the run was handed to a consumer
+	Deliveries int `json:"deliveries"`
+	// LeasedUntil is the time in milliseconds until which the consumer the run was handed to can acknowledge it
+	LeasedUntil int64 `json:"leasedUntil,omitempty"`
+}
+
+// PullAckTask completes a run of a pull callback with the outcome acknowledged by its consumer
+type PullAckTask struct {
+	Schedule       Schedule
+	App            App
+	Status         Status
+	ErrorMessage   string
+	Deliveries     int
+	AcknowledgedAt time.Time
+}
+
+// Acknowledgement is the outcome of a due run reported by its consumer
+type Acknowledgement struct {
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewDueRun returns the due run of a schedule
+func NewDueRun(schedule Schedule) DueRun {
+	run := DueRun{
+		AppId:        schedule.AppId,
+		ScheduleId:   schedule.ScheduleId,
+		Payload:      schedule.Payload,
+		ScheduleTime: schedule.ScheduleTime,
+	}
+	if !util.IsZeroUUID(schedule.ParentScheduleId) {
+		run.ParentScheduleId = schedule.ParentScheduleId.String()
+	}
+	return run
+}
+
+// Leasable tells whether the run can be handed to a consumer at the time, it is not leased or its lease is over
+func (r DueRun) Leasable(now time.Time) bool {
+	return r.LeasedUntil <= now.UnixNano()/int64(time.Millisecond)
+}
+
+// Validate checks that the acknowledgement is a success or a failure
+func (a Acknowledgement) Validate() error {
+	if a.Status != Success && a.Status != Failure {
+		return fmt.Errorf("status must be %s or %s, got %q", Success, Failure, a.Status)
+	}
+	return nil
+}
+
+func (p *PullCallback) GetType() string {
+	return p.Type
+}
+
+func (p *PullCallback) GetDetails() (string, error) {
+	return "{}", nil
+}
+
+func (p *PullCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	p.Type = callbackType
+	return nil
+}
+
+func (p *PullCallback) UnmarshalJSON(data []byte) error {
+	type Alias PullCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+// Invoke hands the run to the workers storing the due runs for the consumers of the app
+func (p *PullCallback) Invoke(wrapper ScheduleWrapper) error {
+	if PullTaskQueue == nil {
+		return errors.New("pull delivery is not initialised")
+	}
+	return PullTaskQueue.Push(wrapper)
+}
+
+func (p *PullCallback) Validate() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestNewDueRun(t *testing.T) {
+	schedule := Schedule{
+		ScheduleId:   gocql.TimeUUID(),
+		AppId:        "test",
+		Payload:      "{}",
+		ScheduleTime: 1686676947,
+	}
+
+	run := NewDueRun(schedule)
+	if run.ScheduleId != schedule.ScheduleId || run.AppId != "test" || run.Payload != "{}" || run.ScheduleTime != 1686676947 {
+		t.Errorf("unexpected due run %+v", run)
+	}
+	if run.ParentScheduleId != "" {
+		t.Errorf("expected no parent schedule id, got %s", run.ParentScheduleId)
+	}
+
+	schedule.ParentScheduleId = gocql.TimeUUID()
+	if run := NewDueRun(schedule); run.ParentScheduleId != schedule.ParentScheduleId.String() {
+		t.Errorf("expected the parent schedule id %s, got %s", schedule.ParentScheduleId, run.ParentScheduleId)
+	}
+}
+
+func TestDueRun_Leasable(t *testing.T) {
+	now := time.Now()
+	millis := now.UnixNano() / int64(time.Millisecond)
+
+	for _, test := range []struct {
+		leasedUntil int64
+		expected    bool
+	}{
+		{0, true},
+		{millis - 1, true},
+		{millis + 1000, false},
+	} {
+		if actual := (DueRun{LeasedUntil: test.leasedUntil}).Leasable(now); actual != test.expected {
+			t.Errorf("leased until %d: expected leasable %v, got %v", test.leasedUntil, test.expected, actual)
+		}
+	}
+}
+
+func TestAcknowledgement_Validate(t *testing.T) {
+	for _, test := range []struct {
+		acknowledgement Acknowledgement
+		valid           bool
+	}{
+		{Acknowledgement{Status: Success}, true},
+		{Acknowledgement{Status: Failure, Error: "unknown order"}, true},
+		{Acknowledgement{}, false},
+		{Acknowledgement{Status: Scheduled}, false},
+	} {
+		if err := test.acknowledgement.Validate(); (err == nil) != test.valid {
+			t.Errorf("acknowledgement %+v: expected valid %v, got %v", test.acknowledgement, test.valid, err)
+		}
+	}
+}
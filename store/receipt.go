@@ -0,0 +1,112 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+)
+
+// DeliveryReceipt is a scheduler signed proof that a callback for a run was dispatched
+// at a given time with a given payload. Receipts can be verified by any holder of the signing key
+// independent of the consumer's own logs.
+type DeliveryReceipt struct {
+	ScheduleId     gocql.UUID `json:"scheduleId"`
+	AppId          string     `json:"appId"`
+	CallbackType   string     `json:"callbackType"`
+	DispatchedAt   int64      `json:"dispatchedAt"`
+	PayloadHash    string     `json:"payloadHash"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	KeyId          string     `json:"keyId,omitempty"`
+	Signature      string     `json:"signature"`
+	// Node and Reconciliation tell which node dispatched the callback and whether it was a reconciliation,
+	// they are not signed so that receipts recorded before them still verify
+	Node           string `json:"node,omitempty"`
+	Reconciliation bool   `json:"reconciliation,omitempty"`
+}
+
+// ValidateDeliveryReceipts checks that enabled receipts have a signing key, anyone could forge a receipt signed
+// with an empty key
+func ValidateDeliveryReceipts(config conf.DeliveryReceiptConfig) error {
+	if config.Enabled && config.SigningKey == "" {
+		return errors.New("delivery receipts are enabled without a signing key")
+	}
+	return nil
+}
+
+// NewDeliveryReceipt creates an unsigned receipt for the schedule dispatched at the supplied time.
+func NewDeliveryReceipt(schedule Schedule, dispatchedAt time.Time, responseStatus int) DeliveryReceipt {
+	receipt := DeliveryReceipt{
+		ScheduleId:     schedule.ScheduleId,
+		AppId:          schedule.AppId,
+		DispatchedAt:   dispatchedAt.UnixNano() / int64(time.Millisecond),
+		PayloadHash:    HashPayload(schedule.Payload),
+		ResponseStatus: responseStatus,
+	}
+
+	if schedule.Callback != nil {
+		receipt.CallbackType = schedule.Callback.GetType()
+	}
+
+	return receipt
+}
+
+// HashPayload returns the hex encoded SHA-256 digest of the payload.
+func HashPayload(payload string) string {
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonical returns the string over which the receipt signature is computed.
+// The format is scheduleId|appId|callbackType|dispatchedAt|payloadHash|responseStatus|keyId
+func (r DeliveryReceipt) canonical() string {
+	return fmt.Sprintf("%s|%s|%s|%d|%s|%d|%s",
+		r.ScheduleId.String(),
+		r.AppId,
+		r.CallbackType,
+		r.DispatchedAt,
+		r.PayloadHash,
+		r.ResponseStatus,
+		r.KeyId)
+}
+
+// Sign sets the key id and the hex encoded HMAC-SHA256 signature of the receipt.
+func (r *DeliveryReceipt) Sign(keyId string, key []byte) {
+	r.KeyId = keyId
+	r.Signature = r.sign(key)
+}
+
+// Verify checks if the receipt signature matches the one computed with the supplied key.
+func (r DeliveryReceipt) Verify(key []byte) bool {
+	return hmac.Equal([]byte(r.Signature), []byte(r.sign(key)))
+}
+
+func (r DeliveryReceipt) sign(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(r.canonical()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestDeliveryReceipt_SignAndVerify(t *testing.T) {
+	schedule := Schedule{
+		ScheduleId: gocql.TimeUUID(),
+		AppId:      "testApp",
+		Payload:    "{\"key\":\"value\"}",
+		Callback:   &HttpCallback{Type: "http"},
+	}
+
+	receipt := NewDeliveryReceipt(schedule, time.Now(), 200)
+	receipt.Sign("v1", []byte("secret"))
+
+	if receipt.PayloadHash != HashPayload(schedule.Payload) {
+		t.Errorf("Expected payload hash %s, got %s", HashPayload(schedule.Payload), receipt.PayloadHash)
+	}
+
+	if receipt.CallbackType != "http" {
+		t.Errorf("Expected callback type http, got %s", receipt.CallbackType)
+	}
+
+	if !receipt.Verify([]byte("secret")) {
+		t.Errorf("Expected receipt to be verified with the signing key")
+	}
+
+	if receipt.Verify([]byte("other")) {
+		t.Errorf("Expected receipt verification to fail with a different key")
+	}
+
+	tampered := receipt
+	tampered.PayloadHash = HashPayload("tampered")
+	if tampered.Verify([]byte("secret")) {
+		t.Errorf("Expected receipt verification to fail for a tampered payload hash")
+	}
+}
+
+func TestValidateDeliveryReceipts(t *testing.T) {
+	for _, test := range []struct {
+		config conf.DeliveryReceiptConfig
+		valid  bool
+	}{
+		{conf.DeliveryReceiptConfig{Enabled: false}, true},
+		{conf.DeliveryReceiptConfig{Enabled: true, KeyId: "v1"}, false},
+		{conf.DeliveryReceiptConfig{Enabled: true, KeyId: "v1", SigningKey: "secret"}, true},
+	} {
+		if err := ValidateDeliveryReceipts(test.config); (err == nil) != test.valid {
+			t.Errorf("config %+v: expected valid %v, got error %v", test.config, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/clock"
+	"github.com/myntra/goscheduler/cron"
+	"github.com/myntra/goscheduler/rrule"
+)
+
+// The duration upto which recurrences are scanned while previewing the runs.
+const previewHorizon = 366 * 24 * time.Hour
+
+// Recurrence decides the times at which runs of a recurring schedule are created.
+type Recurrence interface {
+	Match(time time.Time) bool
+}
+
+// GetRecurrence parses the recurrence of the schedule.
+// Returns a non empty list of error messages if the recurrence is invalid.
+func (s Schedule) GetRecurrence() (Recurrence, []string) {
+	switch {
+	case s.recurrenceCount() > 1:
+		return nil, []string{"Only one of 'cronExpression', 'every' or 'rrule' can be provided"}
+	case len(s.CronExpression) > 0:
+		expression, errs := cron.Default().Parse(s.CronExpression)
+		if len(errs) != 0 {
+			return nil, errs
+		}
+		return expression, nil
+	case len(s.Every) > 0:
+		interval, errs := cron.ParseInterval(s.Every, time.Unix(s.Anchor, 0))
+		if len(errs) != 0 {
+			return nil, errs
+		}
+		return interval, nil
+	case len(s.RRule) > 0:
+		rule, errs := rrule.Parse(s.RRule, time.Unix(s.Anchor, 0))
+		if len(errs) != 0 {
+			return nil, errs
+		}
+		return rule, nil
+	default:
+		return nil, []string{"Schedule is not recurring"}
+	}
+}
+
+// Describe sets the description of the recurrence of a recurring schedule whose recurrence can describe itself
+func (s *Schedule) Describe() {
+	if !s.IsRecurring() {
+		return
+	}
+	recurrence, errs := s.GetRecurrence()
+	if len(errs) != 0 {
+		return
+	}
+	if describer, ok := recurrence.(cron.Describer); ok {
+		s.Description = describer.Describe()
+	}
+}
+
+// recurrenceField returns the field of the schedule an error message of GetRecurrence is about,
+// the invalid field of a cron expression is named as cronExpression.minute
+func (s Schedule) recurrenceField(message string) string {
+	switch {
+	case s.recurrenceCount() > 1:
+		return "recurrence"
+	case len(s.CronExpression) > 0:
+		if field := cron.Field(message); field != "" {
+			return "cronExpression." + field
+		}
+		return "cronExpression"
+	case len(s.Every) > 0:
+		return "every"
+	default:
+		return "rrule"
+	}
+}
+
+// recurrenceCount returns the number of recurrence styles set on the schedule.
+func (s Schedule) recurrenceCount() int {
+	count := 0
+	for _, recurrence := range []string{s.CronExpression, s.Every, s.RRule} {
+		if len(recurrence) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Preview returns upto count times after the supplied time at which the recurrence creates runs.
+// Recurrences which cannot compute their next occurrence are scanned minute by minute upto previewHorizon.
+func Preview(recurrence Recurrence, after time.Time, count int) []time.Time {
+	var times []time.Time
+
+	if next, ok := recurrence.(interface {
+		Next(after time.Time) (time.Time, bool)
+	}); ok {
+		for len(times) < count {
+			t, found := next.Next(after)
+			if !found {
+				break
+			}
+			times = append(times, t)
+			after = t
+		}
+		return times
+	}
+
+	until := after.Add(previewHorizon)
+	for t := after.Truncate(time.Minute).Add(time.Minute); len(times) < count && t.Before(until); t = t.Add(time.Minute) {
+		if recurrence.Match(t) {
+			times = append(times, t)
+		}
+	}
+
+	return times
+}
+
+// NextFireTimes returns upto count times after the supplied time, in epoch seconds, at which the recurring schedule
+// creates runs. Returns nil if the schedule is not recurring, its recurrence is invalid or it is not active.
+func (s Schedule) NextFireTimes(after time.Time, count int) []int64 {
+	if !s.IsRecurring() || (s.Status != "" && s.Status != Scheduled) {
+		return nil
+	}
+	recurrence, errs := s.GetRecurrence()
+	if len(errs) != 0 {
+		return nil
+	}
+
+	times := []int64{}
+	for _, t := range Preview(recurrence, after, count) {
+		times = append(times, t.Unix())
+	}
+	return times
+}
+
+// SetDefaultAnchor anchors an interval or RRULE recurrence at the current minute if no anchor is provided.
+func (s *Schedule) SetDefaultAnchor() {
+	if (len(s.Every) > 0 || len(s.RRule) > 0) && s.Anchor == 0 {
+		s.Anchor = _60seconds * (clock.Now().Unix() / _60seconds)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetRecurrence(t *testing.T) {
+	anchor := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		Name     string
+		Schedule Schedule
+		HasError bool
+		Match    time.Time
+	}{
+		{
+			Name:     "cron expression",
+			Schedule: Schedule{CronExpression: "*/5 * * * *"},
+			Match:    anchor.Add(5 * time.Minute),
+		},
+		{
+			Name:     "interval",
+			Schedule: Schedule{Every: "90m", Anchor: anchor.Unix()},
+			Match:    anchor.Add(90 * time.Minute),
+		},
+		{
+			Name:     "rrule",
+			Schedule: Schedule{RRule: "FREQ=MONTHLY;BYDAY=2TU,4TU", Anchor: anchor.Unix()},
+			Match:    time.Date(2023, 6, 13, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:     "invalid rrule",
+			Schedule: Schedule{RRule: "FREQ=WEEKLY;BYDAY=2TU"},
+			HasError: true,
+		},
+		{
+			Name:     "both cron expression and interval",
+			Schedule: Schedule{CronExpression: "*/5 * * * *", Every: "90m"},
+			HasError: true,
+		},
+		{
+			Name:     "invalid interval",
+			Schedule: Schedule{Every: "90s"},
+			HasError: true,
+		},
+		{
+			Name:     "not recurring",
+			Schedule: Schedule{},
+			HasError: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			recurrence, errs := test.Schedule.GetRecurrence()
+			if test.HasError {
+				if len(errs) == 0 {
+					t.Errorf("Expected errors, got none")
+				}
+				return
+			}
+
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %v", errs)
+			}
+
+			if !recurrence.Match(test.Match) {
+				t.Errorf("Expected recurrence to match %v", test.Match)
+			}
+		})
+	}
+}
+
+func TestSetDefaultAnchor(t *testing.T) {
+	schedule := Schedule{Every: "2h"}
+	schedule.SetDefaultAnchor()
+
+	if schedule.Anchor == 0 || schedule.Anchor%_60seconds != 0 {
+		t.Errorf("Expected anchor to be set to the current minute, got %d", schedule.Anchor)
+	}
+
+	cronSchedule := Schedule{CronExpression: "* * * * *"}
+	cronSchedule.SetDefaultAnchor()
+
+	if cronSchedule.Anchor != 0 {
+		t.Errorf("Expected anchor to be unset for cron schedules, got %d", cronSchedule.Anchor)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	for _, test := range []struct {
+		schedule    Schedule
+		description string
+	}{
+		{Schedule{CronExpression: "30 2 * * MON-FRI"}, "At 02:30 on weekdays"},
+		{Schedule{CronExpression: "@daily"}, "At 00:00"},
+		{Schedule{Every: "90m", Anchor: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC).Unix()}, "Every 1h30m from 2023-06-01 10:00 UTC"},
+		{Schedule{CronExpression: "61 * * * *"}, ""},
+		{Schedule{ScheduleTime: 1}, ""},
+	} {
+		test.schedule.Describe()
+		if test.schedule.Description != test.description {
+			t.Errorf("expected %q, got %q", test.description, test.schedule.Description)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	anchor := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		Name     string
+		Schedule Schedule
+		Expected []time.Time
+	}{
+		{
+			Name:     "cron expression",
+			Schedule: Schedule{CronExpression: "0 */6 * * *"},
+			Expected: []time.Time{anchor.Add(2 * time.Hour), anchor.Add(8 * time.Hour)},
+		},
+		{
+			Name:     "interval",
+			Schedule: Schedule{Every: "90m", Anchor: anchor.Unix()},
+			Expected: []time.Time{anchor.Add(90 * time.Minute), anchor.Add(180 * time.Minute)},
+		},
+		{
+			Name:     "rrule",
+			Schedule: Schedule{RRule: "FREQ=DAILY;COUNT=2", Anchor: anchor.Unix()},
+			Expected: []time.Time{anchor.AddDate(0, 0, 1)},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			recurrence, errs := test.Schedule.GetRecurrence()
+			if len(errs) != 0 {
+				t.Fatalf("Expected no errors, got %v", errs)
+			}
+
+			output := Preview(recurrence, anchor, 2)
+			if len(output) != len(test.Expected) {
+				t.Fatalf("Expected %d runs, got %v", len(test.Expected), output)
+			}
+
+			for i := range output {
+				if !output[i].Equal(test.Expected[i]) {
+					t.Errorf("Expected run %v, got %v", test.Expected[i], output[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_NextFireTimes(t *testing.T) {
+	after := time.Date(2024, 1, 1, 10, 2, 0, 0, time.UTC)
+
+	times := Schedule{CronExpression: "*/15 * * * *", Status: Scheduled}.NextFireTimes(after, 3)
+	expected := []int64{
+		time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC).Unix(),
+		time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC).Unix(),
+	}
+	if len(times) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, times)
+	}
+	for i := range expected {
+		if times[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, times)
+		}
+	}
+
+	for _, schedule := range []Schedule{
+		{CronExpression: "*/15 * * * *", Status: Paused},
+		{ScheduleTime: after.Unix()},
+		{CronExpression: "invalid"},
+	} {
+		if times := schedule.NextFireTimes(after, 3); times != nil {
+			t.Errorf("%+v: expected no fire times, got %v", schedule, times)
+		}
+	}
+}
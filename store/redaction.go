@@ -0,0 +1,237 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RedactionMask replaces the redacted values
+const RedactionMask = "****"
+
+// maxRedactionRules bounds the fields and the patterns an app redacts
+const maxRedactionRules = 50
+
+// Redaction lists what is masked in the bodies of the callback requests and responses of an app before they are
+// logged or kept with the runs, so that the debugging data does not leak personal data.
+type Redaction struct {
+	// JSONPath of the fields masked in the JSON bodies, e.g. $.user.email, $.cards[*].number or $..password
+	Fields []string `json:"fields,omitempty"`
+	// Regular expressions whose matches are masked in any body
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// pathSegment is a step of a field path: a key, an index or any child, looked up at any depth when descending
+type pathSegment struct {
+	key      string
+	index    int
+	wildcard bool
+	descend  bool
+}
+
+// compiledPatterns caches the compiled patterns of the redactions, which are applied to every callback attempt
+var compiledPatterns sync.Map
+
+// Validate checks that the fields are supported paths and the patterns valid regular expressions
+func (r *Redaction) Validate() error {
+	if len(r.Fields)+len(r.Patterns) > maxRedactionRules {
+		return fmt.Errorf("at most %d redacted fields and patterns can be configured, got %d", maxRedactionRules, len(r.Fields)+len(r.Patterns))
+	}
+	for _, field := range r.Fields {
+		if _, err := parseFieldPath(field); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range r.Patterns {
+		if _, err := compilePattern(pattern); err != nil {
+			return fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Apply masks the fields and the matches of the patterns of the redaction in the body. The fields are masked when
+// the body is a JSON document; a body which looks like one but cannot be decoded, e.g. because it was cut, is
+// masked entirely since its fields cannot be found. Apply can be called on nil, which leaves the body as it is.
+func (r *Redaction) Apply(body string) string {
+	if r == nil || body == "" {
+		return body
+	}
+
+	if len(r.Fields) > 0 {
+		if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			redacted, err := r.redactFields(trimmed)
+			if err != nil {
+				return RedactionMask
+			}
+			body = redacted
+		}
+	}
+
+	for _, pattern := range r.Patterns {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			// an invalid pattern is rejected with the configuration, mask rather than leak if one got through
+			return RedactionMask
+		}
+		body = re.ReplaceAllString(body, RedactionMask)
+	}
+	return body
+}
+
+// redactFields masks the fields of the JSON document
+func (r *Redaction) redactFields(body string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return "", err
+	}
+	if decoder.More() {
+		return "", fmt.Errorf("trailing data after the JSON document")
+	}
+
+	for _, field := range r.Fields {
+		path, err := parseFieldPath(field)
+		if err != nil {
+			return "", err
+		}
+		document = redactPath(document, path)
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// redactPath replaces the values of the value found at the path with the mask
+func redactPath(value interface{}, path []pathSegment) interface{} {
+	if len(path) == 0 {
+		return RedactionMask
+	}
+
+	segment, rest := path[0], path[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if segment.wildcard || (segment.index < 0 && key == segment.key) {
+				v[key] = redactPath(child, rest)
+			} else if segment.descend {
+				v[key] = redactPath(child, path)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if segment.wildcard || i == segment.index {
+				v[i] = redactPath(child, rest)
+			} else if segment.descend {
+				v[i] = redactPath(child, path)
+			}
+		}
+	}
+	return value
+}
+
+// parseFieldPath parses the supported subset of JSONPath: $.key, $['key'], $[0], $[*], $.* and $..key,
+// the leading $ being optional
+func parseFieldPath(field string) ([]pathSegment, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(field), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var path []pathSegment
+	for rest != "" {
+		segment := pathSegment{index: -1}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			segment.descend = true
+			rest = rest[2:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		}
+
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid redacted field %q: unclosed bracket", field)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case selector == "*":
+				segment.wildcard = true
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				segment.key = selector[1 : len(selector)-1]
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid redacted field %q: unsupported selector [%s]", field, selector)
+				}
+				segment.index = index
+			}
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid redacted field %q: empty key", field)
+			case "*":
+				segment.wildcard = true
+			default:
+				segment.key = name
+			}
+		}
+		path = append(path, segment)
+	}
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid redacted field %q: no key", field)
+	}
+	return path, nil
+}
+
+// compilePattern returns the compiled pattern, compiling it on its first use
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := compiledPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiledPatterns.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,50 @@
+package store
+
+import "testing"
+
+func TestRedaction_Validate(t *testing.T) {
+	for _, test := range []struct {
+		redaction Redaction
+		valid     bool
+	}{
+		{Redaction{}, true},
+		{Redaction{Fields: []string{"$.user.email", "cards[*].number", "$..password", "$['api-key']", "$.items[0]"}}, true},
+		{Redaction{Patterns: []string{`\d{16}`, `(?i)bearer \S+`}}, true},
+		{Redaction{Fields: []string{"$."}}, false},
+		{Redaction{Fields: []string{"$.items[0"}}, false},
+		{Redaction{Fields: []string{"$.items[-1]"}}, false},
+		{Redaction{Fields: []string{"$"}}, false},
+		{Redaction{Patterns: []string{`(unclosed`}}, false},
+	} {
+		if err := test.redaction.Validate(); (err == nil) != test.valid {
+			t.Errorf("redaction %v: expected valid %v, got %v", test.redaction, test.valid, err)
+		}
+	}
+}
+
+func TestRedaction_Apply(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		redaction *Redaction
+		body      string
+		expected  string
+	}{
+		{"Nil", nil, `{"email":"a@b.c"}`, `{"email":"a@b.c"}`},
+		{"Field", &Redaction{Fields: []string{"$.user.email"}}, `{"user":{"email":"a@b.c","id":7}}`, `{"user":{"email":"****","id":7}}`},
+		{"MissingField", &Redaction{Fields: []string{"$.user.phone"}}, `{"user":{"id":7}}`, `{"user":{"id":7}}`},
+		{"Wildcard", &Redaction{Fields: []string{"$.cards[*].number"}}, `{"cards":[{"number":"4111"},{"number":"5500"}]}`, `{"cards":[{"number":"****"},{"number":"****"}]}`},
+		{"Index", &Redaction{Fields: []string{"$[1]"}}, `["a","b"]`, `["a","****"]`},
+		{"Descendants", &Redaction{Fields: []string{"$..password"}}, `{"password":"x","nested":[{"password":"y"}]}`, `{"nested":[{"password":"****"}],"password":"****"}`},
+		{"Numbers", &Redaction{Fields: []string{"$.pin"}}, `{"amount":12345678901234567890,"pin":1234}`, `{"amount":12345678901234567890,"pin":"****"}`},
+		{"NotJson", &Redaction{Fields: []string{"$.email"}}, `email=a@b.c`, `email=a@b.c`},
+		{"TruncatedJson", &Redaction{Fields: []string{"$.email"}}, `{"email":"a@b`, RedactionMask},
+		{"Pattern", &Redaction{Patterns: []string{`\d{4}-\d{4}`}}, `card 1234-5678 used`, `card **** used`},
+		{"FieldAndPattern", &Redaction{Fields: []string{"$.email"}, Patterns: []string{`secret-\w+`}}, `{"email":"a@b.c","note":"secret-abc"}`, `{"email":"****","note":"****"}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.redaction.Apply(test.body); actual != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
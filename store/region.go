@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// ErrRegionUnroutable is the error of the callbacks of a region the cluster has no egress proxy for, in strict mode
+var ErrRegionUnroutable = errors.New("region unroutable")
+
+// regionPattern is the format of the regions, such as ap-south-1
+var regionPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// regionRouting is the routing of the callbacks of the regions of the node, nil when the cluster is not region aware
+var regionRouting struct {
+	sync.RWMutex
+	regions *Regions
+}
+
+// SetRegions sets the routing of the callbacks of the regions of the node, nil makes the node region unaware
+func SetRegions(regions *Regions) {
+	regionRouting.Lock()
+	defer regionRouting.Unlock()
+	regionRouting.regions = regions
+}
+
+// GetRegions returns the routing of the callbacks of the regions of the node, nil when the cluster is not region aware.
+// The methods of the routing can be called on nil, which delivers every callback from the node.
+func GetRegions() *Regions {
+	regionRouting.RLock()
+	defer regionRouting.RUnlock()
+	return regionRouting.regions
+}
+
+// Regions routes the callbacks of the schedules of other regions than the one of the cluster through the egress proxy
+// of their region
+type Regions struct {
+	local   string
+	proxies map[string]string
+	strict  bool
+}
+
+// NewRegions builds the routing of the regions of the configuration, nil when the cluster has no region
+func NewRegions(config conf.RegionConfig) (*Regions, error) {
+	if config.Region == "" {
+		if len(config.EgressProxies) > 0 {
+			return nil, errors.New("egress proxies of the regions require the region of the cluster")
+		}
+		return nil, nil
+	}
+	if message := validateRegion(config.Region); message != "" {
+		return nil, errors.New(message)
+	}
+
+	proxies := make(map[string]string, len(config.EgressProxies))
+	for region, proxy := range config.EgressProxies {
+		if message := validateRegion(region); message != "" {
+			return nil, errors.New(message)
+		}
+		if err := (HttpTransport{ProxyUrl: proxy}).Validate(); err != nil {
+			return nil, fmt.Errorf("egress proxy of region %s: %w", region, err)
+		}
+		if region != config.Region {
+			proxies[region] = proxy
+		}
+	}
+
+	return &Regions{local: config.Region, proxies: proxies, strict: config.Strict}, nil
+}
+
+// Local returns the region of the cluster, empty when it is not region aware
+func (r *Regions) Local() string {
+	if r == nil {
+		return ""
+	}
+	return r.local
+}
+
+// Route returns the egress proxy the callbacks of the region go through, empty when they are delivered from the node.
+// The callbacks of a region without an egress proxy are delivered from the node unless the routing is strict.
+func (r *Regions) Route(region string) (string, error) {
+	if r == nil || region == "" || region == r.local {
+		return "", nil
+	}
+	if proxy, ok := r.proxies[region]; ok {
+		return proxy, nil
+	}
+	if r.strict {
+		return "", fmt.Errorf("%w: no egress proxy for region %s in region %s", ErrRegionUnroutable, region, r.local)
+	}
+	return "", nil
+}
+
+// Validate checks the format of the region and, when the routing is strict, that its callbacks can be routed
+func (r *Regions) Validate(region string) string {
+	if region == "" {
+		return ""
+	}
+	if message := validateRegion(region); message != "" {
+		return message
+	}
+	if _, err := r.Route(region); err != nil {
+		return fmt.Sprintf("region %s is not served by this cluster, the regions served are %s and those of the egress proxies", region, r.local)
+	}
+	return ""
+}
+
+func validateRegion(region string) string {
+	if !regionPattern.MatchString(region) {
+		return fmt.Sprintf("invalid region: %s, must be lower case letters, digits and dashes, e.g. ap-south-1", region)
+	}
+	return ""
+}
+
+// GetRegion returns the region of the schedule, the one of its app when the schedule has none
+func (s Schedule) GetRegion(app App) string {
+	if s.Region != "" {
+		return s.Region
+	}
+	return app.Configuration.Region
+}
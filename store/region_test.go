@@ -0,0 +1,82 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestNewRegions(t *testing.T) {
+	regions, err := NewRegions(conf.RegionConfig{})
+	if err != nil || regions != nil {
+		t.Fatalf("Expected no routing without a region, got %v, %v", regions, err)
+	}
+
+	for _, config := range []conf.RegionConfig{
+		{EgressProxies: map[string]string{"eu-west-1": "http://proxy.eu:3128"}},
+		{Region: "AP South"},
+		{Region: "ap-south-1", EgressProxies: map[string]string{"eu-west-1": "ftp://proxy.eu"}},
+		{Region: "ap-south-1", EgressProxies: map[string]string{"EU": "http://proxy.eu:3128"}},
+	} {
+		if _, err := NewRegions(config); err == nil {
+			t.Errorf("Expected an error for %+v", config)
+		}
+	}
+}
+
+func TestRegionsRoute(t *testing.T) {
+	config := conf.RegionConfig{
+		Region:        "ap-south-1",
+		EgressProxies: map[string]string{"eu-west-1": "http://proxy.eu:3128", "ap-south-1": "http://proxy.local:3128"},
+	}
+	regions, err := NewRegions(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for region, expected := range map[string]string{
+		"":           "",
+		"ap-south-1": "",
+		"eu-west-1":  "http://proxy.eu:3128",
+		"us-east-1":  "",
+	} {
+		if proxy, err := regions.Route(region); err != nil || proxy != expected {
+			t.Errorf("Expected %q for region %q, got %q, %v", expected, region, proxy, err)
+		}
+	}
+	if message := regions.Validate("us-east-1"); message != "" {
+		t.Errorf("Expected a region without an egress proxy to be accepted, got %s", message)
+	}
+
+	config.Strict = true
+	regions, _ = NewRegions(config)
+	if _, err := regions.Route("us-east-1"); !errors.Is(err, ErrRegionUnroutable) {
+		t.Errorf("Expected ErrRegionUnroutable, got %v", err)
+	}
+	if message := regions.Validate("us-east-1"); !strings.Contains(message, "not served") {
+		t.Errorf("Expected the region to be rejected, got %q", message)
+	}
+	if message := regions.Validate("eu-west-1"); message != "" {
+		t.Errorf("Unexpected message %s", message)
+	}
+
+	var unaware *Regions
+	if proxy, err := unaware.Route("eu-west-1"); err != nil || proxy != "" {
+		t.Errorf("Expected a region unaware node to deliver every callback, got %q, %v", proxy, err)
+	}
+	if message := unaware.Validate("EU"); message == "" {
+		t.Errorf("Expected the format of the region to be checked")
+	}
+}
+
+func TestScheduleGetRegion(t *testing.T) {
+	app := App{Configuration: Configuration{Region: "eu-west-1"}}
+	if region := (Schedule{}).GetRegion(app); region != "eu-west-1" {
+		t.Errorf("Expected the region of the app, got %s", region)
+	}
+	if region := (Schedule{Region: "us-east-1"}).GetRegion(app); region != "us-east-1" {
+		t.Errorf("Expected the region of the schedule, got %s", region)
+	}
+}
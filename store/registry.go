@@ -28,7 +28,16 @@ var Registry = map[string]Factory{}
 func InitializeCallbackRegistry(clientCallbacks map[string]Factory) {
 	// default implementations
 	defaultCallbacks := map[string]Factory{
-		constants.DefaultCallback: func() Callback { return &HttpCallback{} },
+		constants.DefaultCallback:  func() Callback { return &HttpCallback{} },
+		constants.TemplateCallback: func() Callback { return &TemplateCallback{} },
+		constants.CanaryCallback:   func() Callback { return &CanaryCallback{} },
+		constants.PullCallback:     func() Callback { return &PullCallback{} },
+		constants.PubSubCallback: func() Callback {
+			return &pluginCallback{Plugin: &PubSubCallback{}}
+		},
+		constants.ServiceBusCallback: func() Callback {
+			return &pluginCallback{Plugin: &ServiceBusCallback{}}
+		},
 	}
 
 	// First, register all client-provided callbacks
@@ -0,0 +1,236 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrAssertionFailed is the error of the 2xx responses failing the assertion of their callback
+var ErrAssertionFailed = errors.New("response assertion failed")
+
+// Kinds of the callback errors a retry policy can retry
+const (
+	RetryOnTimeout           = "timeout"
+	RetryOnConnectionRefused = "connection_refused"
+	RetryOnConnectionReset   = "connection_reset"
+	RetryOnDNS               = "dns"
+	RetryOnEOF               = "eof"
+	RetryOnAssertion         = "assertion"
+)
+
+// maxRetryBackoff bounds the backoff between two attempts of a callback, which holds a callback worker while it waits
+const maxRetryBackoff = 30 * time.Second
+
+// RetryPolicy tells which outcomes of the http callbacks of an app are retried, any other outcome is terminal.
+// Without a policy every error and non 2xx response is retried right away.
+type RetryPolicy struct {
+	// Retried status codes, single codes such as 429, ranges such as 502-504 or classes such as 5xx
+	StatusCodes []string `json:"statusCodes,omitempty"`
+	// Retried errors: timeout, connection_refused, connection_reset, dns, eof and assertion for the 2xx responses
+	// failing the assertion of their callback
+	Errors []string `json:"errors,omitempty"`
+	// Backoff before the first retry, doubled after every retry
+	BackoffMillis int `json:"backoffMillis,omitempty"`
+	// Upper bound of the backoff between retries
+	MaxBackoffMillis int `json:"maxBackoffMillis,omitempty"`
+	// Backoff before the first retry of the status codes, ranges or classes, overriding BackoffMillis
+	BackoffOverrides map[string]int `json:"backoffOverrides,omitempty"`
+}
+
+// Validate checks the status codes, the errors and the backoffs of the policy
+func (p *RetryPolicy) Validate() error {
+	for _, codes := range p.StatusCodes {
+		if _, _, err := parseStatusCodes(codes); err != nil {
+			return err
+		}
+	}
+	for _, kind := range p.Errors {
+		switch kind {
+		case RetryOnTimeout, RetryOnConnectionRefused, RetryOnConnectionReset, RetryOnDNS, RetryOnEOF, RetryOnAssertion:
+		default:
+			return fmt.Errorf("unknown retried error %q", kind)
+		}
+	}
+	if err := validateBackoff("backoffMillis", p.BackoffMillis); err != nil {
+		return err
+	}
+	if err := validateBackoff("maxBackoffMillis", p.MaxBackoffMillis); err != nil {
+		return err
+	}
+	for codes, backoff := range p.BackoffOverrides {
+		if _, _, err := parseStatusCodes(codes); err != nil {
+			return err
+		}
+		if err := validateBackoff("backoff of "+codes, backoff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBackoff checks that the backoff is within 0 and maxRetryBackoff
+func validateBackoff(name string, millis int) error {
+	if millis < 0 || time.Duration(millis)*time.Millisecond > maxRetryBackoff {
+		return fmt.Errorf("%s must be between 0 and %d, got %d", name, maxRetryBackoff.Milliseconds(), millis)
+	}
+	return nil
+}
+
+// Retryable tells whether a callback which got the status code, 0 without a response, and the error is retried.
+// Retryable can be called on nil, which retries every error and non 2xx status code.
+func (p *RetryPolicy) Retryable(statusCode int, err error) bool {
+	if p == nil {
+		return err != nil || statusCode < 200 || statusCode > 299
+	}
+	if err != nil {
+		kind := ErrorKind(err)
+		for _, retried := range p.Errors {
+			if retried == kind {
+				return true
+			}
+		}
+		return false
+	}
+	return matchStatusCode(p.StatusCodes, statusCode)
+}
+
+// Backoff returns the wait before the retry following the given attempts of a callback which got the status code.
+// Backoff can be called on nil, which retries right away.
+func (p *RetryPolicy) Backoff(statusCode int, attempts int) time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	// the narrowest override of the status code wins, so that 429 overrides 4xx
+	backoff := time.Duration(p.BackoffMillis) * time.Millisecond
+	narrowest := -1
+	for codes, millis := range p.BackoffOverrides {
+		low, high, err := parseStatusCodes(codes)
+		if err != nil || statusCode < low || statusCode > high {
+			continue
+		}
+		if narrowest < 0 || high-low < narrowest {
+			backoff, narrowest = time.Duration(millis)*time.Millisecond, high-low
+		}
+	}
+
+	limit := maxRetryBackoff
+	if p.MaxBackoffMillis > 0 {
+		limit = time.Duration(p.MaxBackoffMillis) * time.Millisecond
+	}
+	for i := 1; i < attempts && backoff < limit; i++ {
+		backoff *= 2
+	}
+	if backoff > limit {
+		return limit
+	}
+	return backoff
+}
+
+// ParseRetryAfter returns the wait asked for by a Retry-After header, in seconds or as an http date
+func ParseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// ErrorKind returns the kind of a callback error a retry policy can retry, empty for the other errors
+func ErrorKind(err error) string {
+	var dnsError *net.DNSError
+	var netError net.Error
+
+	switch {
+	case errors.Is(err, ErrAssertionFailed):
+		return RetryOnAssertion
+	case errors.As(err, &dnsError):
+		return RetryOnDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return RetryOnConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return RetryOnConnectionReset
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netError) && netError.Timeout():
+		return RetryOnTimeout
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return RetryOnEOF
+	default:
+		return ""
+	}
+}
+
+// matchStatusCode checks if the status code is one of the codes, ranges or classes
+func matchStatusCode(statusCodes []string, statusCode int) bool {
+	for _, codes := range statusCodes {
+		if low, high, err := parseStatusCodes(codes); err == nil && statusCode >= low && statusCode <= high {
+			return true
+		}
+	}
+	return false
+}
+
+// parseStatusCodes returns the bounds of a status code such as 429, a range such as 502-504 or a class such as 5xx
+func parseStatusCodes(codes string) (int, int, error) {
+	var low, high int
+	var err error
+
+	switch {
+	case len(codes) == 3 && strings.HasSuffix(codes, "xx"):
+		if low, err = strconv.Atoi(codes[:1]); err == nil {
+			low, high = low*100, low*100+99
+		}
+	case strings.Contains(codes, "-"):
+		bounds := strings.SplitN(codes, "-", 2)
+		if low, err = strconv.Atoi(strings.TrimSpace(bounds[0])); err == nil {
+			high, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+		}
+	default:
+		low, err = strconv.Atoi(strings.TrimSpace(codes))
+		high = low
+	}
+
+	if err != nil || low < 100 || high > 599 || low > high {
+		return 0, 0, fmt.Errorf("invalid retried status codes %q", codes)
+	}
+	return low, high, nil
+}
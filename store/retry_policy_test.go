@@ -0,0 +1,136 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	for _, test := range []struct {
+		policy RetryPolicy
+		valid  bool
+	}{
+		{RetryPolicy{}, true},
+		{RetryPolicy{StatusCodes: []string{"429", "502-504", "5xx"}, Errors: []string{RetryOnTimeout, RetryOnConnectionReset}}, true},
+		{RetryPolicy{BackoffMillis: 100, MaxBackoffMillis: 2000, BackoffOverrides: map[string]int{"429": 5000}}, true},
+		{RetryPolicy{StatusCodes: []string{"99"}}, false},
+		{RetryPolicy{StatusCodes: []string{"504-502"}}, false},
+		{RetryPolicy{StatusCodes: []string{"6xx"}}, false},
+		{RetryPolicy{StatusCodes: []string{"abc"}}, false},
+		{RetryPolicy{Errors: []string{"connection_lost"}}, false},
+		{RetryPolicy{BackoffMillis: -1}, false},
+		{RetryPolicy{MaxBackoffMillis: 60000}, false},
+		{RetryPolicy{BackoffOverrides: map[string]int{"4x": 100}}, false},
+		{RetryPolicy{BackoffOverrides: map[string]int{"429": 60000}}, false},
+	} {
+		if err := test.policy.Validate(); (err == nil) != test.valid {
+			t.Errorf("policy %+v: expected valid %v, got %v", test.policy, test.valid, err)
+		}
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	policy := &RetryPolicy{
+		StatusCodes: []string{"429", "502-504"},
+		Errors:      []string{RetryOnConnectionReset, RetryOnAssertion},
+	}
+	reset := &url.Error{Op: "Post", URL: "http://localhost", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}
+
+	for _, test := range []struct {
+		name       string
+		policy     *RetryPolicy
+		statusCode int
+		err        error
+		expected   bool
+	}{
+		{"NoPolicySuccess", nil, 200, nil, false},
+		{"NoPolicyFailure", nil, 400, nil, true},
+		{"NoPolicyError", nil, 0, errors.New("error"), true},
+		{"RetriedCode", policy, 429, nil, true},
+		{"RetriedRange", policy, 503, nil, true},
+		{"TerminalCode", policy, 401, nil, false},
+		{"OutsideRange", policy, 500, nil, false},
+		{"RetriedError", policy, 0, reset, true},
+		{"TerminalError", policy, 0, &url.Error{Op: "Post", URL: "http://localhost", Err: syscall.ECONNREFUSED}, false},
+		{"RetriedAssertion", policy, 200, fmt.Errorf("%w: field missing", ErrAssertionFailed), true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.policy.Retryable(test.statusCode, test.err); actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		BackoffMillis:    100,
+		MaxBackoffMillis: 1000,
+		BackoffOverrides: map[string]int{"4xx": 200, "429": 500},
+	}
+
+	for _, test := range []struct {
+		policy     *RetryPolicy
+		statusCode int
+		attempts   int
+		expected   time.Duration
+	}{
+		{nil, 503, 1, 0},
+		{policy, 503, 1, 100 * time.Millisecond},
+		{policy, 503, 2, 200 * time.Millisecond},
+		{policy, 503, 5, time.Second},
+		{policy, 400, 1, 200 * time.Millisecond},
+		{policy, 429, 1, 500 * time.Millisecond},
+		{policy, 429, 2, time.Second},
+	} {
+		if actual := test.policy.Backoff(test.statusCode, test.attempts); actual != test.expected {
+			t.Errorf("backoff of %d after %d attempts: expected %v, got %v", test.statusCode, test.attempts, test.expected, actual)
+		}
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	for _, test := range []struct {
+		err      error
+		expected string
+	}{
+		{&url.Error{Op: "Post", URL: "http://localhost", Err: &net.DNSError{Err: "no such host", Name: "localhost"}}, RetryOnDNS},
+		{&url.Error{Op: "Post", URL: "http://localhost", Err: syscall.ECONNREFUSED}, RetryOnConnectionRefused},
+		{&url.Error{Op: "Post", URL: "http://localhost", Err: io.EOF}, RetryOnEOF},
+		{fmt.Errorf("%w: status", ErrAssertionFailed), RetryOnAssertion},
+		{errors.New("error"), ""},
+	} {
+		if actual := ErrorKind(test.err); actual != test.expected {
+			t.Errorf("kind of %v: expected %q, got %q", test.err, test.expected, actual)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 6, 13, 10, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Tue, 13 Jun 2023 10:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 13 Jun 2023 09:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		actual, ok := ParseRetryAfter(test.header, now)
+		if actual != test.expected || ok != test.ok {
+			t.Errorf("retry after %q: expected %v %v, got %v %v", test.header, test.expected, test.ok, actual, ok)
+		}
+	}
+}
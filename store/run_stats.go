@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RunStats is the outcome of the runs of an app fired over an hour, rolled up for delivery health
+type RunStats struct {
+	AppId     string    `json:"appId,omitempty"`
+	Hour      time.Time `json:"hour"`
+	Successes int64     `json:"successes"`
+	Failures  int64     `json:"failures"`
+	Retries   int64     `json:"retries"`
+}
+
+// Add adds the counters of other to the stats
+func (r *RunStats) Add(other RunStats) {
+	r.Successes += other.Successes
+	r.Failures += other.Failures
+	r.Retries += other.Retries
+}
+
+// IsZero tells whether none of the counters is set
+func (r RunStats) IsZero() bool {
+	return r.Successes == 0 && r.Failures == 0 && r.Retries == 0
+}
+
+// RunStatsHour returns the UTC hour of t that run stats are rolled up to
+func RunStatsHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+type runStatsKey struct {
+	appId string
+	hour  time.Time
+}
+
+// RunStatsCounter accumulates the run stats of the apps in memory until they are flushed to the rollup table
+type RunStatsCounter struct {
+	mu      sync.Mutex
+	pending map[runStatsKey]*RunStats
+}
+
+var runStatsCounter = NewRunStatsCounter()
+
+// RunStatistics returns the counter accumulating the run stats recorded by the node
+func RunStatistics() *RunStatsCounter {
+	return runStatsCounter
+}
+
+func NewRunStatsCounter() *RunStatsCounter {
+	return &RunStatsCounter{pending: map[runStatsKey]*RunStats{}}
+}
+
+// Record adds the stats to the hour of t of the app
+func (c *RunStatsCounter) Record(appId string, t time.Time, stats RunStats) {
+	if appId == "" || stats.IsZero() {
+		return
+	}
+
+	key := runStatsKey{appId, RunStatsHour(t)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[key]
+	if !ok {
+		pending = &RunStats{AppId: appId, Hour: key.hour}
+		c.pending[key] = pending
+	}
+	pending.Add(stats)
+}
+
+// Drain returns the accumulated stats ordered by app and hour, and resets the counter
+func (c *RunStatsCounter) Drain() []RunStats {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = map[runStatsKey]*RunStats{}
+	c.mu.Unlock()
+
+	stats := make([]RunStats, 0, len(pending))
+	for _, s := range pending {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].AppId != stats[j].AppId {
+			return stats[i].AppId < stats[j].AppId
+		}
+		return stats[i].Hour.Before(stats[j].Hour)
+	})
+	return stats
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunStatsCounter(t *testing.T) {
+	counter := NewRunStatsCounter()
+	hour := time.Date(2023, 1, 2, 10, 59, 0, 0, time.UTC)
+
+	counter.Record("b", hour, RunStats{Failures: 1})
+	counter.Record("a", hour, RunStats{Successes: 1, Retries: 2})
+	counter.Record("a", hour.Add(time.Minute), RunStats{Successes: 1})
+	counter.Record("a", hour, RunStats{Failures: 1})
+	counter.Record("a", hour, RunStats{})
+	counter.Record("", hour, RunStats{Successes: 1})
+
+	stats := counter.Drain()
+	if len(stats) != 3 {
+		t.Fatalf("Expected the stats of 3 app hours, got %+v", stats)
+	}
+	if stats[0].AppId != "a" || !stats[0].Hour.Equal(RunStatsHour(hour)) || stats[0].Successes != 1 || stats[0].Failures != 1 || stats[0].Retries != 2 {
+		t.Errorf("Unexpected stats of the first hour of a %+v", stats[0])
+	}
+	if stats[1].AppId != "a" || !stats[1].Hour.Equal(RunStatsHour(hour).Add(time.Hour)) || stats[1].Successes != 1 {
+		t.Errorf("Unexpected stats of the second hour of a %+v", stats[1])
+	}
+	if stats[2].AppId != "b" || stats[2].Failures != 1 {
+		t.Errorf("Unexpected stats of b %+v", stats[2])
+	}
+
+	if stats = counter.Drain(); len(stats) != 0 {
+		t.Errorf("Expected the counter to be reset by the drain, got %+v", stats)
+	}
+}
+
+func TestRunStatsHour(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	if hour := RunStatsHour(time.Date(2023, 1, 2, 3, 45, 0, 0, ist)); !hour.Equal(time.Date(2023, 1, 1, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the UTC hour, got %s", hour)
+	}
+}
@@ -24,13 +24,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/gocql/gocql"
-	"github.com/golang/glog"
+	"github.com/myntra/goscheduler/clock"
 	"github.com/myntra/goscheduler/conf"
-	"github.com/myntra/goscheduler/constants"
-	"github.com/myntra/goscheduler/cron"
+	er "github.com/myntra/goscheduler/error"
+	"github.com/myntra/goscheduler/logger"
 	"github.com/myntra/goscheduler/util"
 )
 
@@ -51,6 +52,10 @@ const (
 	Miss      Status     = "MISS"
 	Error     Status     = "ERROR"
 	Paused    Status     = "PAUSED"
+	Draft     Status     = "DRAFT"
+	Suspended Status     = "SUSPENDED"
+	Skipped   Status     = "SKIPPED"
+	Overrun   Status     = "OVERRUN"
 	Reconcile ActionType = "reconcile"
 	Delete    ActionType = "delete"
 )
@@ -65,10 +70,34 @@ type Schedule struct {
 	Callback              Callback                `json:"-"`
 	CallbackRaw           json.RawMessage         `json:"callback,omitempty"`
 	CronExpression        string                  `json:"cronExpression,omitempty"`
+	Every                 string                  `json:"every,omitempty"`
+	RRule                 string                  `json:"rrule,omitempty"`
+	Anchor                int64                   `json:"anchor,omitempty"`
+	StatusCallback        string                  `json:"statusCallback,omitempty"`
+	PayloadEncoding       string                  `json:"-"`
+	PausePolicy           PausePolicy             `json:"pausePolicy,omitempty"`
+	ConcurrencyPolicy     ConcurrencyPolicy       `json:"concurrencyPolicy,omitempty"`
+	Group                 string                  `json:"group,omitempty"`      // Group of the recurring schedule, none if empty
+	KeepPaused            bool                    `json:"keepPaused,omitempty"` // Exempts the recurring schedule from the stale pause policy of its app
+	PausedAt              int64                   `json:"pausedAt,omitempty"`
+	DeletedAt             int64                   `json:"deletedAt,omitempty"`
+	Priority              Priority                `json:"priority,omitempty"`
+	Region                string                  `json:"region,omitempty"`       // Region the callbacks are delivered from, the one of the app if empty
+	TraceId               string                  `json:"traceId,omitempty"`      // Trace id assigned at creation and inherited by the runs of a recurring schedule
+	DeferredFrom          int64                   `json:"deferredFrom,omitempty"` // Epoch second a deferred run was first due, 0 if the run was not deferred
 	Status                Status                  `json:"status,omitempty"`
 	ErrorMessage          string                  `json:"errorMessage,omitempty"`
+	ResponseSnippet       string                  `json:"responseSnippet,omitempty"` // Truncated body of the last callback response
 	ParentScheduleId      gocql.UUID              `json:"-"`
 	ReconciliationHistory []ReconciliationHistory `json:"reconciliationHistory,omitempty"`
+	RequestId             string                  `json:"-"`                     // Correlation id of the request which created or last updated a one time schedule
+	Parked                bool                    `json:"-"`                     // Whether the schedule is in the parking table, not yet promoted
+	Archived              bool                    `json:"archived,omitempty"`    // Whether the schedule was read from the archive, not persisted
+	Description           string                  `json:"description,omitempty"` // Human readable description of the recurrence, not persisted
+	Health                Health                  `json:"health,omitempty"`      // Derived from the recent runs of an active recurring schedule, not persisted
+	ClaimedAt             int64                   `json:"-"`                     // Epoch second the fire was claimed in the intent log, 0 if unclaimed, not persisted
+	// Canary of an updated callback, only read by updates and not persisted
+	Canary *CanaryPolicy `json:"canary,omitempty"`
 	//Deprecated
 	Ttl int `json:"-"`
 	//Deprecated
@@ -98,6 +127,9 @@ type ScheduleWrapper struct {
 	Schedule         Schedule
 	App              App
 	IsReconciliation bool
+	EnqueuedAt       time.Time    // Time the schedule was handed to the callback workers
+	Canary           CanaryTarget // Callback a run of a callback canary is fired to, empty for the other runs
+	Shadow           bool         // Whether the run is the copy of a mirrored canary run, whose outcome is only recorded
 }
 
 type BulkActionTask struct {
@@ -129,7 +161,7 @@ func (s Schedule) GetCallbackDetails() string {
 }
 
 func (s *Schedule) CreateScheduleFromCassandraMap(m map[string]interface{}) error {
-	glog.V(constants.INFO).Infof("Map: %+v", m)
+	logger.Debugf("Map: %+v", m)
 	if len(m) == 0 {
 		return nil
 	}
@@ -165,9 +197,68 @@ func (s *Schedule) CreateScheduleFromCassandraMap(m map[string]interface{}) erro
 	s.CallbackRaw = raw
 
 	s.Payload = m["payload"].(string)
+	if encoding, ok := m["payload_encoding"].(string); ok && encoding != "" {
+		payload, err := DecodePayload(s.Payload, encoding)
+		if err != nil {
+			return err
+		}
+		s.Payload = payload
+		s.PayloadEncoding = encoding
+	}
+
+	if statusCallback, ok := m["status_callback"].(string); ok {
+		s.StatusCallback = statusCallback
+	}
+
+	if priority, ok := m["priority"].(string); ok {
+		s.Priority = Priority(priority)
+	}
+
+	if region, ok := m["region"].(string); ok {
+		s.Region = region
+	}
+
+	if traceId, ok := m["trace_id"].(string); ok {
+		s.TraceId = traceId
+	}
+
+	if deferredFrom, ok := m["deferred_from"].(time.Time); ok && !deferredFrom.IsZero() {
+		s.DeferredFrom = deferredFrom.Unix()
+	}
+
+	if requestId, ok := m["request_id"].(string); ok {
+		s.RequestId = requestId
+	}
 
 	if cronExpr, ok := m["cron_expression"]; ok {
 		s.CronExpression = cronExpr.(string)
+		if every, ok := m["every"].(string); ok {
+			s.Every = every
+		}
+		if rrule, ok := m["rrule"].(string); ok {
+			s.RRule = rrule
+		}
+		if anchor, ok := m["anchor"].(time.Time); ok && !anchor.IsZero() {
+			s.Anchor = anchor.Unix()
+		}
+		if policy, ok := m["pause_policy"].(string); ok {
+			s.PausePolicy = PausePolicy(policy)
+		}
+		if policy, ok := m["concurrency_policy"].(string); ok {
+			s.ConcurrencyPolicy = ConcurrencyPolicy(policy)
+		}
+		if group, ok := m["group_name"].(string); ok {
+			s.Group = group
+		}
+		if keepPaused, ok := m["keep_paused"].(bool); ok {
+			s.KeepPaused = keepPaused
+		}
+		if pausedAt, ok := m["paused_at"].(time.Time); ok && !pausedAt.IsZero() {
+			s.PausedAt = pausedAt.Unix()
+		}
+		if deletedAt, ok := m["deleted_at"].(time.Time); ok && !deletedAt.IsZero() {
+			s.DeletedAt = deletedAt.Unix()
+		}
 	} else {
 		s.ScheduleGroup = m["schedule_time_group"].(time.Time).Unix()
 		s.ScheduleTime = m["schedule_time"].(time.Time).Unix()
@@ -243,7 +334,20 @@ func CreateCallbackFromRawMessage(rawMessage json.RawMessage) (Callback, error)
 }
 
 func (s Schedule) IsRecurring() bool {
-	return len(s.CronExpression) > 0
+	return len(s.CronExpression) > 0 || len(s.Every) > 0 || len(s.RRule) > 0
+}
+
+// IsDraft tells if the schedule is staged to be activated later, no runs are created for a draft
+func (s Schedule) IsDraft() bool {
+	return s.Status == Draft
+}
+
+// FirstDueAt returns the epoch second the run was first due, before it was deferred if it was
+func (s Schedule) FirstDueAt() int64 {
+	if s.DeferredFrom != 0 {
+		return s.DeferredFrom
+	}
+	return s.ScheduleTime
 }
 
 // CloneAsOneTime Clones a given recurring schedule to one time schedule at a supplied time.:w
@@ -267,33 +371,72 @@ func (s Schedule) CloneAsOneTime(at time.Time) Schedule {
 		}
 	}
 	clone.Payload = s.Payload
+	clone.StatusCallback = s.StatusCallback
+	clone.PayloadEncoding = s.PayloadEncoding
+	clone.Priority = s.Priority
+	clone.Region = s.Region
+	clone.TraceId = s.TraceId
 	clone.ParentScheduleId = s.ScheduleId
+	clone.RequestId = s.RequestId
 
 	return clone
 }
 
+// Logger returns the default logger with the schedule id, app id, trace id and request id of the schedule added to
+// every line
+func (s Schedule) Logger() logger.Logger {
+	fields := logger.Fields{
+		logger.ScheduleIdField: s.ScheduleId.String(),
+		logger.AppIdField:      s.AppId,
+	}
+	if s.TraceId != "" {
+		fields[logger.TraceIdField] = s.TraceId
+	}
+	if s.RequestId != "" {
+		fields[logger.RequestIdField] = s.RequestId
+	}
+	return logger.WithFields(fields)
+}
+
 // CheckUntriggeredCallback checks if the current time is already past the schedule time group of the schedule
 // with gap of more than a minute plus flush period
 func (s Schedule) CheckUntriggeredCallback(flushPeriod int) bool {
 	scheduleTimeGroup := s.ScheduleGroup
-	now := time.Now()
+	now := clock.Now()
 	return time.Unix(scheduleTimeGroup, 0).Before(now) &&
 		(now.Sub(time.Unix(scheduleTimeGroup, 0)).Seconds() > float64(_60seconds+flushPeriod))
 }
 
 // GetTTL TTL will be set at schedule level
-// ttl = scheduleTime - now
+// ttl = scheduleTime - now + retention of the fired schedules of the app.
+// Rows already past their retention get the shortest ttl, as a ttl of 0 would keep them forever.
 func (s Schedule) GetTTL(app App, bufferTTL int) int {
-	return int(s.ScheduleTime-time.Now().Unix()) + app.GetBufferTTL(bufferTTL)
+	ttl := int(s.ScheduleTime-clock.Now().Unix()) + app.GetBufferTTL(bufferTTL)
+	if ttl < 1 {
+		return 1
+	}
+	return ttl
+}
+
+// IsPurgeable tells whether the schedule was deleted longer than the deleted schedule retention of the app ago.
+// Schedules deleted before their deletion time was recorded are purgeable.
+func (s Schedule) IsPurgeable(app App, deletedRetention int, now time.Time) bool {
+	if s.Status != Deleted {
+		return false
+	}
+	return s.DeletedAt+int64(app.GetDeletedRetention(deletedRetention)) <= now.Unix()
 }
 
-// Set status, error_msg and reconciliation_history of the schedule from map
+// Set status, error_msg, response_snippet and reconciliation_history of the schedule from map
 func (s *Schedule) SetStatus(m map[string]interface{}) error {
 	if len(m) == 0 {
 		return nil
 	}
 	s.Status = Status(m["schedule_status"].(string))
 	s.ErrorMessage = m["error_msg"].(string)
+	if snippet, ok := m["response_snippet"].(string); ok {
+		s.ResponseSnippet = snippet
+	}
 
 	if m["reconciliation_history"].(string) == "" {
 		s.ReconciliationHistory = []ReconciliationHistory{}
@@ -301,7 +444,7 @@ func (s *Schedule) SetStatus(m map[string]interface{}) error {
 	}
 
 	if err := json.Unmarshal([]byte(m["reconciliation_history"].(string)), &s.ReconciliationHistory); err != nil {
-		glog.Infof("Error unmarshalling: %v", err)
+		logger.Infof("Error unmarshalling: %v", err)
 		return err
 	}
 
@@ -325,7 +468,7 @@ func (s *Schedule) SetUnknownStatus(flushPeriod int) {
 // If the reconciliation history contains more than "HistorySize" reconciliations
 // then consider the latest "HistorySize" reconciliations
 func (s *Schedule) UpdateReconciliationHistory(status Status, errMsg string) {
-	glog.Infof("Found reconciliations: %+v", s.ReconciliationHistory)
+	logger.Infof("Found reconciliations: %+v", s.ReconciliationHistory)
 
 	s.ReconciliationHistory = append(s.ReconciliationHistory, ReconciliationHistory{
 		Status:       status,
@@ -357,24 +500,10 @@ func (s *Schedule) UnmarshalJSON(data []byte) error {
 
 	// Check if CallbackRaw is present and use specific logic
 	if len(s.CallbackRaw) > 0 {
-		var callbackData struct {
-			Type string `json:"type"`
-		}
-
-		if err := json.Unmarshal(s.CallbackRaw, &callbackData); err != nil {
-			return err
-		}
-
-		factoryFunc, ok := Registry[callbackData.Type]
-		if !ok {
-			return fmt.Errorf("unknown callback type: %s", callbackData.Type)
-		}
-
-		callback := factoryFunc()
-		if err := json.Unmarshal(s.CallbackRaw, callback); err != nil {
+		callback, err := ParseCallback(s.CallbackRaw)
+		if err != nil {
 			return err
 		}
-
 		s.Callback = callback
 	} else {
 		// Use the HttpCallbackData or AirbusCallbackData
@@ -389,43 +518,84 @@ func (s *Schedule) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (s *Schedule) ValidateSchedule(app App, conf conf.AppLevelConfiguration) []string {
-	glog.V(constants.INFO).Infof("ValidateSchedule: %+v", s)
-	var errs []string
+// ParseCallback parses the JSON document of a callback into a callback of the type registered for its type field
+func ParseCallback(raw json.RawMessage) (Callback, error) {
+	var callbackData struct {
+		Type string `json:"type"`
+	}
 
-	if errStr := validateField(s.AppId, "appId"); errStr != "" {
-		errs = append(errs, errStr)
+	if err := json.Unmarshal(raw, &callbackData); err != nil {
+		return nil, err
 	}
 
-	if errStr := validateField(s.Payload, "payload"); errStr != "" {
-		errs = append(errs, errStr)
+	factoryFunc, ok := Registry[callbackData.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown callback type: %s", callbackData.Type)
 	}
 
-	if errStr := validatePayloadSize(s.Payload, app, conf.PayloadSize); errStr != "" {
-		errs = append(errs, errStr)
+	callback := factoryFunc()
+	if err := json.Unmarshal(raw, callback); err != nil {
+		return nil, err
 	}
+	return callback, nil
+}
+
+// ValidateSchedule returns the messages of the fields failing the validation of the schedule
+func (s *Schedule) ValidateSchedule(app App, conf conf.AppLevelConfiguration) []string {
+	return er.Messages(s.ValidateFields(app, conf))
+}
 
-	if errStr := validateCallback(s.Callback); errStr != "" {
-		errs = append(errs, errStr)
+// ValidateFields validates the schedule against the app and returns the fields failing the validation
+func (s *Schedule) ValidateFields(app App, conf conf.AppLevelConfiguration) []er.FieldError {
+	logger.Debugf("ValidateSchedule: %+v", s)
+	var errs []er.FieldError
+
+	add := func(field, message string) {
+		if message != "" {
+			errs = append(errs, er.FieldError{Field: field, Message: message})
+		}
 	}
 
-	if len(s.CronExpression) > 0 {
-		if er := validateCronExpression(s.CronExpression); len(er) > 0 {
-			errs = append(errs, er...)
+	add("appId", validateField(s.AppId, "appId"))
+	add("payload", validateField(s.Payload, "payload"))
+	if err := s.ValidatePayloadSize(app, conf); err != nil {
+		add("payload", err.Error())
+	}
+	add("callback", validateCallback(s.Callback))
+	add("statusCallback", validateStatusCallback(s.StatusCallback))
+	add("pausePolicy", validatePausePolicy(s.PausePolicy))
+	add("concurrencyPolicy", validateConcurrencyPolicy(s.ConcurrencyPolicy, s.Callback))
+	add("group", validateGroup(s.Group, s.IsRecurring()))
+	add("priority", validatePriority(s.Priority))
+	add("region", GetRegions().Validate(s.Region))
+	add("traceId", validateTraceId(s.TraceId))
+
+	if s.IsRecurring() {
+		_, messages := s.GetRecurrence()
+		for _, message := range messages {
+			add(s.recurrenceField(message), message)
 		}
 	} else {
-		if errStr := validateScheduleTime(s.ScheduleTime, app, conf.FutureScheduleCreationPeriod); errStr != "" {
-			errs = append(errs, errStr)
-		}
+		add("scheduleTime", validateScheduleTime(s.ScheduleTime, app, conf.FutureScheduleCreationPeriod))
 	}
 
 	return errs
 }
 
+// A schedule without a valid trace id is assigned a new one, the clones of a schedule keep its trace id.
 func (s *Schedule) SetFields(app App) {
 	s.ScheduleId = gocql.TimeUUID()
+	if !ValidTraceId(s.TraceId) {
+		s.TraceId = NewTraceId()
+	}
 	s.PartitionId = int(uuidToPartition(s.ScheduleId, app.Partitions))
 	s.ScheduleGroup = 60 * (s.ScheduleTime / 60)
+	s.SetDefaultAnchor()
+}
+
+// PartitionFor returns the partition of the schedule in an app with the given number of partitions
+func (s Schedule) PartitionFor(partitions uint32) int {
+	return int(uuidToPartition(s.ScheduleId, partitions))
 }
 
 func uuidToPartition(uuid gocql.UUID, partitions uint32) uint64 {
@@ -444,7 +614,7 @@ func validateField(fieldData string, field string) string {
 }
 
 func validateScheduleTime(scheduleTime int64, app App, maxTTL int) string {
-	now := time.Now().Unix()
+	now := clock.Now().Unix()
 	if scheduleTime < now {
 		return fmt.Sprintf("schedule time : %d is less than current time: %d for app: %s. Time cannot be in past.",
 			scheduleTime,
@@ -460,33 +630,44 @@ func validateScheduleTime(scheduleTime int64, app App, maxTTL int) string {
 	return ""
 }
 
-func validatePayloadSize(payload string, app App, maxPayload int) string {
-	var maxPayloadSize int
-
-	if app.Configuration.PayloadSize == 0 {
-		maxPayloadSize = maxPayload
-	} else {
-		maxPayloadSize = app.Configuration.PayloadSize
-	}
-
-	if len(payload) > maxPayloadSize {
-		errMsg := fmt.Sprintf("PayloadSize for app: %s cannot be more than %d bytes, given payloadSize bytes: %d", app.AppId, maxPayloadSize, len(payload))
-		glog.Errorf(errMsg)
-		return errMsg
-	}
+// PayloadTooLargeError is returned for a payload larger than the payload size limit of its app
+type PayloadTooLargeError struct {
+	AppId string
+	Limit int
+	Size  int
+}
 
-	return ""
+func (e PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("PayloadSize for app: %s cannot be more than %d bytes, given payloadSize bytes: %d", e.AppId, e.Limit, e.Size)
 }
 
-func validateCronExpression(cronExpression string) []string {
-	if _, err := cron.Parse(cronExpression); err != nil {
+// ValidatePayloadSize checks the payload against the limit of the app, the payloadSize of its configuration or
+// the limit of the cluster when unset
+func (s *Schedule) ValidatePayloadSize(app App, conf conf.AppLevelConfiguration) error {
+	limit := app.MaxPayloadSize(conf.PayloadSize)
+	if len(s.Payload) > limit {
+		err := PayloadTooLargeError{AppId: app.AppId, Limit: limit, Size: len(s.Payload)}
+		logger.Errorf(err.Error())
 		return err
 	}
 	return nil
 }
 
+// validateStatusCallback checks that the optional status callback is an absolute http(s) url
+func validateStatusCallback(statusCallback string) string {
+	if statusCallback == "" {
+		return ""
+	}
+
+	u, err := url.ParseRequestURI(statusCallback)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Sprintf("invalid statusCallback url: %s", statusCallback)
+	}
+	return ""
+}
+
 func validateCallback(callback Callback) string {
-	glog.Infof("Callback Data: %+v", callback)
+	logger.Infof("Callback Data: %+v", callback)
 	if err := callback.Validate(); err != nil {
 		return err.Error()
 	}
@@ -588,8 +588,9 @@ func TestUnmarshalJSON(t *testing.T) {
 func TestSetStatus(t *testing.T) {
 	// Prepare the map input
 	m := map[string]interface{}{
-		"schedule_status": "Scheduled",
-		"error_msg":       "Test Error",
+		"schedule_status":  "Scheduled",
+		"error_msg":        "Test Error",
+		"response_snippet": `{"status":"queued"}`,
 		"reconciliation_history": `[{
 			"status": "Scheduled",
 			"errorMessage": "Test History Error",
@@ -612,6 +613,10 @@ func TestSetStatus(t *testing.T) {
 		t.Errorf("Expected ErrorMessage 'Test Error', got '%s'", s.ErrorMessage)
 	}
 
+	if s.ResponseSnippet != `{"status":"queued"}` {
+		t.Errorf("Expected ResponseSnippet '{\"status\":\"queued\"}', got '%s'", s.ResponseSnippet)
+	}
+
 	if len(s.ReconciliationHistory) != 1 {
 		t.Fatalf("Expected ReconciliationHistory of length 1, got %d", len(s.ReconciliationHistory))
 	}
@@ -630,3 +635,150 @@ func TestSetStatus(t *testing.T) {
 		t.Errorf("Expected ReconciliationHistory[0].CallbackOn '2023-06-12T14:00:00Z', got '%v'", history.CallbackOn)
 	}
 }
+
+func TestGetTTL(t *testing.T) {
+	now := time.Now().Unix()
+	app := App{Configuration: Configuration{FiredScheduleRetentionPeriod: 2}}
+
+	tests := []struct {
+		scheduleTime int64
+		app          App
+		min, max     int
+	}{
+		{now + 60, App{}, 60*60*24 + 59, 60*60*24 + 60},
+		{now + 60, app, 2*60*60*24 + 59, 2*60*60*24 + 60},
+		{now - 60*60*24, App{}, 0, 1},
+		{now - 3*60*60*24, app, 1, 1},
+	}
+
+	for _, test := range tests {
+		ttl := Schedule{ScheduleTime: test.scheduleTime}.GetTTL(test.app, 1)
+		if ttl < test.min || ttl > test.max || ttl < 1 {
+			t.Errorf("GetTTL(%d, %+v) = %d, want a positive ttl between %d and %d", test.scheduleTime, test.app.Configuration, ttl, test.min, test.max)
+		}
+	}
+}
+
+func TestIsPurgeable(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	day := int64(60 * 60 * 24)
+	app := App{Configuration: Configuration{DeletedScheduleRetentionPeriod: 3}}
+
+	tests := []struct {
+		schedule Schedule
+		app      App
+		want     bool
+	}{
+		{Schedule{Status: Scheduled}, App{}, false},
+		{Schedule{Status: Paused, PausedAt: now.Unix() - 30*day}, App{}, false},
+		{Schedule{Status: Deleted}, App{}, true},
+		{Schedule{Status: Deleted, DeletedAt: now.Unix() - day}, App{}, true},
+		{Schedule{Status: Deleted, DeletedAt: now.Unix() - day + 1}, App{}, false},
+		{Schedule{Status: Deleted, DeletedAt: now.Unix() - 2*day}, app, false},
+		{Schedule{Status: Deleted, DeletedAt: now.Unix() - 3*day}, app, true},
+	}
+
+	for _, test := range tests {
+		if got := test.schedule.IsPurgeable(test.app, 1, now); got != test.want {
+			t.Errorf("IsPurgeable(%+v, %+v) = %v, want %v", test.schedule, test.app.Configuration, got, test.want)
+		}
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	config := conf2.AppLevelConfiguration{FutureScheduleCreationPeriod: 7, PayloadSize: 1024}
+	app := App{AppId: "app"}
+
+	for _, test := range []struct {
+		name     string
+		schedule Schedule
+		fields   []string
+	}{
+		{
+			name:     "valid schedule",
+			schedule: Schedule{AppId: "app", Payload: "{}", Callback: &MockCallback{Field: "success"}, CronExpression: "*/5 * * * *"},
+		},
+		{
+			name:     "missing fields",
+			schedule: Schedule{Callback: &MockCallback{Field: "success"}, CronExpression: "*/5 * * * *"},
+			fields:   []string{"appId", "payload"},
+		},
+		{
+			name:     "invalid callback and schedule time",
+			schedule: Schedule{AppId: "app", Payload: "{}", Callback: &MockCallback{}, ScheduleTime: 1},
+			fields:   []string{"callback", "scheduleTime"},
+		},
+		{
+			name:     "invalid cron fields",
+			schedule: Schedule{AppId: "app", Payload: "{}", Callback: &MockCallback{Field: "success"}, CronExpression: "61 * * 13 *"},
+			fields:   []string{"cronExpression.minute", "cronExpression.month"},
+		},
+		{
+			name:     "invalid cron expression",
+			schedule: Schedule{AppId: "app", Payload: "{}", Callback: &MockCallback{Field: "success"}, CronExpression: "* *"},
+			fields:   []string{"cronExpression"},
+		},
+		{
+			name:     "invalid interval",
+			schedule: Schedule{AppId: "app", Payload: "{}", Callback: &MockCallback{Field: "success"}, Every: "soon"},
+			fields:   []string{"every"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			errs := test.schedule.ValidateFields(app, config)
+			var fields []string
+			for _, err := range errs {
+				if err.Message == "" {
+					t.Errorf("missing message of the field %s", err.Field)
+				}
+				fields = append(fields, err.Field)
+			}
+			if len(fields) != len(test.fields) {
+				t.Fatalf("expected fields %v, got %v", test.fields, fields)
+			}
+			for i := range fields {
+				if fields[i] != test.fields[i] {
+					t.Errorf("expected fields %v, got %v", test.fields, fields)
+				}
+			}
+			if len(test.schedule.ValidateSchedule(app, config)) != len(errs) {
+				t.Errorf("expected a message per field")
+			}
+		})
+	}
+}
+
+func TestValidatePayloadSize(t *testing.T) {
+	config := conf2.AppLevelConfiguration{PayloadSize: 4}
+
+	for _, test := range []struct {
+		name    string
+		app     App
+		payload string
+		limit   int
+	}{
+		{"within the limit of the cluster", App{AppId: "app"}, "1234", 0},
+		{"over the limit of the cluster", App{AppId: "app"}, "12345", 4},
+		{"within the limit of the app", App{AppId: "app", Configuration: Configuration{PayloadSize: 8}}, "12345", 0},
+		{"over the limit of the app", App{AppId: "app", Configuration: Configuration{PayloadSize: 2}}, "123", 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Schedule{Payload: test.payload}
+			err := s.ValidatePayloadSize(test.app, config)
+			if test.limit == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			tooLarge, ok := err.(PayloadTooLargeError)
+			if !ok {
+				t.Fatalf("expected PayloadTooLargeError, got %v", err)
+			}
+			if tooLarge.Limit != test.limit || tooLarge.Size != len(test.payload) || tooLarge.AppId != "app" {
+				t.Errorf("unexpected error %+v", tooLarge)
+			}
+		})
+	}
+}
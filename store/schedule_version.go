@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// ScheduleDefinition is the part of a recurring schedule which is versioned on every update
+type ScheduleDefinition struct {
+	Payload           string            `json:"payload"`
+	Callback          json.RawMessage   `json:"callback"`
+	CronExpression    string            `json:"cronExpression,omitempty"`
+	Every             string            `json:"every,omitempty"`
+	RRule             string            `json:"rrule,omitempty"`
+	Anchor            int64             `json:"anchor,omitempty"`
+	StatusCallback    string            `json:"statusCallback,omitempty"`
+	Priority          Priority          `json:"priority,omitempty"`
+	PausePolicy       PausePolicy       `json:"pausePolicy,omitempty"`
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	Group             string            `json:"group,omitempty"`
+	KeepPaused        bool              `json:"keepPaused,omitempty"`
+	Region            string            `json:"region,omitempty"`
+}
+
+// ScheduleVersion is a definition of a recurring schedule. The versions are numbered from 1 in the order they were
+// replaced, and the current definition of the schedule comes last. The number of a version is stored with it, so it
+// does not change when older versions expire.
+type ScheduleVersion struct {
+	ScheduleId gocql.UUID         `json:"scheduleId"`
+	Version    int                `json:"version"`
+	VersionId  gocql.UUID         `json:"-"`                    // Time based id of the version, which orders the versions
+	ReplacedAt int64              `json:"replacedAt,omitempty"` // Epoch millis of the update which replaced the definition
+	RequestId  string             `json:"requestId,omitempty"`  // Correlation id of the update which replaced the definition
+	Current    bool               `json:"current,omitempty"`
+	Definition ScheduleDefinition `json:"definition"`
+}
+
+// Definition returns the versioned definition of the schedule
+func (s Schedule) Definition() (ScheduleDefinition, error) {
+	callback := s.CallbackRaw
+	if s.Callback != nil {
+		raw, err := json.Marshal(s.Callback)
+		if err != nil {
+			return ScheduleDefinition{}, err
+		}
+		callback = raw
+	}
+
+	return ScheduleDefinition{
+		Payload:           s.Payload,
+		Callback:          callback,
+		CronExpression:    s.CronExpression,
+		Every:             s.Every,
+		RRule:             s.RRule,
+		Anchor:            s.Anchor,
+		StatusCallback:    s.StatusCallback,
+		Priority:          s.Priority,
+		PausePolicy:       s.PausePolicy,
+		ConcurrencyPolicy: s.ConcurrencyPolicy,
+		Group:             s.Group,
+		KeepPaused:        s.KeepPaused,
+		Region:            s.Region,
+	}, nil
+}
+
+// Apply replaces the definition of the schedule with the one of the version
+func (d ScheduleDefinition) Apply(schedule *Schedule) error {
+	if len(d.Callback) == 0 {
+		return errors.New("the version has no callback")
+	}
+	callback, err := CreateCallbackFromRawMessage(d.Callback)
+	if err != nil {
+		return err
+	}
+
+	schedule.Payload = d.Payload
+	schedule.Callback = callback
+	schedule.CallbackRaw = d.Callback
+	schedule.CronExpression = d.CronExpression
+	schedule.Every = d.Every
+	schedule.RRule = d.RRule
+	schedule.Anchor = d.Anchor
+	schedule.StatusCallback = d.StatusCallback
+	schedule.Priority = d.Priority
+	schedule.PausePolicy = d.PausePolicy
+	schedule.ConcurrencyPolicy = d.ConcurrencyPolicy
+	schedule.Group = d.Group
+	schedule.KeepPaused = d.KeepPaused
+	schedule.Region = d.Region
+	return nil
+}
+
+// NewScheduleVersion creates the numbered version of the definition of a schedule which is about to be replaced
+func NewScheduleVersion(schedule Schedule, version int, replacedAt time.Time) (ScheduleVersion, error) {
+	definition, err := schedule.Definition()
+	if err != nil {
+		return ScheduleVersion{}, err
+	}
+
+	return ScheduleVersion{
+		ScheduleId: schedule.ScheduleId,
+		Version:    version,
+		VersionId:  gocql.UUIDFromTime(replacedAt),
+		ReplacedAt: replacedAt.UnixNano() / int64(time.Millisecond),
+		RequestId:  schedule.RequestId,
+		Definition: definition,
+	}, nil
+}
+
+// CurrentVersion returns the current definition of a schedule as the version following its replaced versions
+func CurrentVersion(schedule Schedule, versions []ScheduleVersion) (ScheduleVersion, error) {
+	definition, err := schedule.Definition()
+	if err != nil {
+		return ScheduleVersion{}, err
+	}
+
+	return ScheduleVersion{
+		ScheduleId: schedule.ScheduleId,
+		Version:    NextVersion(versions),
+		Current:    true,
+		Definition: definition,
+	}, nil
+}
+
+// NextVersion returns the number following the latest of the versions, ordered oldest first
+func NextVersion(versions []ScheduleVersion) int {
+	if len(versions) == 0 {
+		return 1
+	}
+	return versions[len(versions)-1].Version + 1
+}
+
+// FindVersion returns the version with the number among the versions
+func FindVersion(versions []ScheduleVersion, version int) (ScheduleVersion, bool) {
+	for _, v := range versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return ScheduleVersion{}, false
+}
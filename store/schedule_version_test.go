@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+)
+
+func TestScheduleDefinition_Apply(t *testing.T) {
+	InitializeCallbackRegistry(nil)
+
+	previous := Schedule{
+		ScheduleId:     gocql.TimeUUID(),
+		AppId:          "app",
+		Payload:        `{"v":1}`,
+		CronExpression: "0 2 * * *",
+		Priority:       HighPriority,
+		Callback:       &HttpCallback{Type: constants.DefaultCallback, Details: Details{Url: "http://127.0.0.1/v1", Method: "POST"}},
+	}
+	version, err := NewScheduleVersion(previous, 1, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := previous
+	current.Payload = `{"v":2}`
+	current.CronExpression = ""
+	current.Every = "1h"
+	current.Priority = ""
+	current.Callback = &HttpCallback{Type: constants.DefaultCallback, Details: Details{Url: "http://127.0.0.1/v2", Method: "GET"}}
+
+	if err := version.Definition.Apply(&current); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := current.Definition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(restored.Callback) != string(version.Definition.Callback) {
+		t.Errorf("expected callback %s, got %s", version.Definition.Callback, restored.Callback)
+	}
+	restored.Callback, version.Definition.Callback = nil, nil
+	if restored.Payload != version.Definition.Payload || restored.CronExpression != "0 2 * * *" || restored.Every != "" ||
+		restored.Priority != HighPriority {
+		t.Errorf("expected definition %+v, got %+v", version.Definition, restored)
+	}
+	if current.ScheduleId != previous.ScheduleId || current.AppId != previous.AppId {
+		t.Error("expected the identity of the schedule to be kept")
+	}
+}
+
+func TestScheduleDefinition_ApplyWithoutCallback(t *testing.T) {
+	var schedule Schedule
+	if err := (ScheduleDefinition{Payload: "{}"}).Apply(&schedule); err == nil {
+		t.Error("expected a version without callback to be rejected")
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	if next := NextVersion(nil); next != 1 {
+		t.Errorf("expected the first version to be 1, got %d", next)
+	}
+
+	// Versions 1 and 2 have expired
+	versions := []ScheduleVersion{{Version: 3}, {Version: 4}}
+	if next := NextVersion(versions); next != 5 {
+		t.Errorf("expected the version after 4 to be 5, got %d", next)
+	}
+	if _, ok := FindVersion(versions, 2); ok {
+		t.Error("expected an expired version not to be found")
+	}
+	if version, ok := FindVersion(versions, 4); !ok || version.Version != 4 {
+		t.Errorf("expected version 4 to be found, got %+v", version)
+	}
+}
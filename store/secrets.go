@@ -0,0 +1,251 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// secretRefPattern matches the {{secret:NAME}} references of the callbacks
+var secretRefPattern = regexp.MustCompile(`\{\{secret:([a-zA-Z0-9_][a-zA-Z0-9._-]{0,127})\}\}`)
+
+// secretRefPrefix starts every secret reference, well formed or not
+const secretRefPrefix = "{{secret:"
+
+// ErrSecretsUnavailable is the error of resolving a secret reference on a node without a secrets provider
+var ErrSecretsUnavailable = errors.New("no secrets provider is configured")
+
+// SecretProvider reads the secrets referenced by the callbacks
+type SecretProvider interface {
+	GetSecret(name string) (string, error)
+}
+
+// secretProvider is the secrets provider of the node, nil when secret references are not resolved
+var secretProvider struct {
+	sync.RWMutex
+	provider SecretProvider
+}
+
+// SetSecretProvider sets the provider the secret references of the callbacks are resolved from
+func SetSecretProvider(provider SecretProvider) {
+	secretProvider.Lock()
+	defer secretProvider.Unlock()
+	secretProvider.provider = provider
+}
+
+// Secrets returns the provider the secret references of the callbacks are resolved from, nil when there is none
+func Secrets() SecretProvider {
+	secretProvider.RLock()
+	defer secretProvider.RUnlock()
+	return secretProvider.provider
+}
+
+// HasSecretRefs tells whether the value references a secret
+func HasSecretRefs(value string) bool {
+	return strings.Contains(value, secretRefPrefix)
+}
+
+// ValidateSecretRefs checks that every secret reference of the value is well formed
+func ValidateSecretRefs(value string) error {
+	rest := secretRefPattern.ReplaceAllString(value, "")
+	if HasSecretRefs(rest) {
+		return fmt.Errorf("invalid secret reference in %q, must be {{secret:NAME}}", value)
+	}
+	return nil
+}
+
+// ResolveSecrets replaces the secret references of the value with the secrets of the provider of the node
+func ResolveSecrets(value string) (string, error) {
+	if !HasSecretRefs(value) {
+		return value, nil
+	}
+
+	provider := Secrets()
+	if provider == nil {
+		return "", ErrSecretsUnavailable
+	}
+
+	var resolveErr error
+	resolved := secretRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := secretRefPattern.FindStringSubmatch(ref)[1]
+		secret, err := provider.GetSecret(name)
+		if err != nil && resolveErr == nil {
+			resolveErr = fmt.Errorf("error resolving secret %s: %w", name, err)
+		}
+		return secret
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// NewSecretProvider builds the secrets provider of the configuration, nil when no provider is configured
+func NewSecretProvider(config conf.SecretsConfig) (SecretProvider, error) {
+	var provider SecretProvider
+	switch config.Provider {
+	case "":
+		return nil, nil
+	case "env":
+		provider = envSecrets{prefix: config.EnvPrefix}
+	case "file":
+		if config.Directory == "" {
+			return nil, errors.New("directory of the file secrets provider cannot be empty")
+		}
+		provider = fileSecrets{directory: config.Directory}
+	case "vault":
+		if config.VaultAddress == "" || config.VaultPath == "" {
+			return nil, errors.New("address and path of the vault secrets provider cannot be empty")
+		}
+		provider = &vaultSecrets{
+			address:  strings.TrimSuffix(config.VaultAddress, "/"),
+			path:     strings.Trim(config.VaultPath, "/"),
+			tokenEnv: config.VaultTokenEnv,
+			client:   &http.Client{Timeout: 5 * time.Second},
+		}
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %s, must be one of env, file or vault", config.Provider)
+	}
+
+	if config.CacheSeconds <= 0 {
+		return provider, nil
+	}
+	return newCachedSecrets(provider, time.Duration(config.CacheSeconds)*time.Second), nil
+}
+
+// envSecrets reads the secrets from the environment variables of the node
+type envSecrets struct {
+	prefix string
+}
+
+func (e envSecrets) GetSecret(name string) (string, error) {
+	secret, ok := os.LookupEnv(e.prefix + name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s%s is not set", e.prefix, name)
+	}
+	return secret, nil
+}
+
+// fileSecrets reads the secrets from the files of a directory, like the secrets mounted in a container
+type fileSecrets struct {
+	directory string
+}
+
+func (f fileSecrets) GetSecret(name string) (string, error) {
+	secret, err := ioutil.ReadFile(filepath.Join(f.directory, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(secret), "\r\n"), nil
+}
+
+// vaultSecrets reads the secrets from the keys of a secret of a vault key value engine
+type vaultSecrets struct {
+	address  string
+	path     string
+	tokenEnv string
+	client   *http.Client
+}
+
+func (v *vaultSecrets) GetSecret(name string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, v.address+"/v1/"+v.path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", os.Getenv(v.tokenEnv))
+
+	response, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded with %s", response.Status)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&secret); err != nil {
+		return "", err
+	}
+
+	// version 2 of the key value engine nests the keys of the secret under data
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[name].(string)
+	if !ok {
+		return "", fmt.Errorf("key %s not found in vault secret %s", name, v.path)
+	}
+	return value, nil
+}
+
+// cachedSecret is a secret read from a provider
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// cachedSecrets reuses the secrets read from a provider until they expire, so the provider is not read on every
+// callback while a rotated secret is still picked up
+type cachedSecrets struct {
+	sync.Mutex
+	provider SecretProvider
+	ttl      time.Duration
+	cache    map[string]cachedSecret
+	now      func() time.Time
+}
+
+func newCachedSecrets(provider SecretProvider, ttl time.Duration) *cachedSecrets {
+	return &cachedSecrets{provider: provider, ttl: ttl, cache: map[string]cachedSecret{}, now: time.Now}
+}
+
+func (c *cachedSecrets) GetSecret(name string) (string, error) {
+	c.Lock()
+	secret, ok := c.cache[name]
+	c.Unlock()
+	if ok && c.now().Before(secret.expiresAt) {
+		return secret.value, nil
+	}
+
+	value, err := c.provider.GetSecret(name)
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	c.cache[name] = cachedSecret{value: value, expiresAt: c.now().Add(c.ttl)}
+	c.Unlock()
+	return value, nil
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+type countingSecrets struct {
+	secrets map[string]string
+	reads   int
+}
+
+func (c *countingSecrets) GetSecret(name string) (string, error) {
+	c.reads++
+	secret, ok := c.secrets[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func TestValidateSecretRefs(t *testing.T) {
+	for _, test := range []struct {
+		value string
+		valid bool
+	}{
+		{"Bearer token", true},
+		{"Bearer {{secret:PARTNER_TOKEN}}", true},
+		{"{{secret:user}}:{{secret:password}}", true},
+		{"Bearer {{secret:}}", false},
+		{"Bearer {{secret:PARTNER TOKEN}}", false},
+		{"Bearer {{secret:../token}}", false},
+		{"Bearer {{secret:PARTNER_TOKEN", false},
+	} {
+		if err := ValidateSecretRefs(test.value); (err == nil) != test.valid {
+			t.Errorf("value %q: expected valid %v, got %v", test.value, test.valid, err)
+		}
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	defer SetSecretProvider(nil)
+
+	SetSecretProvider(nil)
+	if value, err := ResolveSecrets("Bearer token"); err != nil || value != "Bearer token" {
+		t.Errorf("expected the value without references unchanged, got %q and %v", value, err)
+	}
+	if _, err := ResolveSecrets("Bearer {{secret:TOKEN}}"); err != ErrSecretsUnavailable {
+		t.Errorf("expected %v without a provider, got %v", ErrSecretsUnavailable, err)
+	}
+
+	SetSecretProvider(&countingSecrets{secrets: map[string]string{"USER": "scheduler", "PASSWORD": "s3cret"}})
+	if value, err := ResolveSecrets("{{secret:USER}}:{{secret:PASSWORD}}"); err != nil || value != "scheduler:s3cret" {
+		t.Errorf("expected the references resolved, got %q and %v", value, err)
+	}
+	if _, err := ResolveSecrets("Bearer {{secret:MISSING}}"); err == nil {
+		t.Error("expected an error for a missing secret")
+	}
+}
+
+func TestNewSecretProvider(t *testing.T) {
+	if provider, err := NewSecretProvider(conf.SecretsConfig{}); provider != nil || err != nil {
+		t.Errorf("expected no provider, got %v and %v", provider, err)
+	}
+	for _, config := range []conf.SecretsConfig{
+		{Provider: "file"},
+		{Provider: "vault", VaultAddress: "http://vault:8200"},
+		{Provider: "kms"},
+	} {
+		if _, err := NewSecretProvider(config); err == nil {
+			t.Errorf("expected an error for %+v", config)
+		}
+	}
+}
+
+func TestEnvSecrets(t *testing.T) {
+	os.Setenv("GOSCHEDULER_SECRET_TEST_TOKEN", "token")
+	defer os.Unsetenv("GOSCHEDULER_SECRET_TEST_TOKEN")
+
+	provider, err := NewSecretProvider(conf.SecretsConfig{Provider: "env", EnvPrefix: "GOSCHEDULER_SECRET_"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if secret, err := provider.GetSecret("TEST_TOKEN"); err != nil || secret != "token" {
+		t.Errorf("expected token, got %q and %v", secret, err)
+	}
+	if _, err := provider.GetSecret("TEST_MISSING"); err == nil {
+		t.Error("expected an error for an unset variable")
+	}
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "TOKEN"), []byte("token\n"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	provider, err := NewSecretProvider(conf.SecretsConfig{Provider: "file", Directory: dir})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if secret, err := provider.GetSecret("TOKEN"); err != nil || secret != "token" {
+		t.Errorf("expected token, got %q and %v", secret, err)
+	}
+}
+
+func TestVaultSecrets(t *testing.T) {
+	os.Setenv("TEST_VAULT_TOKEN", "root")
+	defer os.Unsetenv("TEST_VAULT_TOKEN")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/goscheduler" || r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data": {"data": {"TOKEN": "token"}, "metadata": {"version": 3}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewSecretProvider(conf.SecretsConfig{
+		Provider:      "vault",
+		VaultAddress:  server.URL + "/",
+		VaultPath:     "/secret/data/goscheduler",
+		VaultTokenEnv: "TEST_VAULT_TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if secret, err := provider.GetSecret("TOKEN"); err != nil || secret != "token" {
+		t.Errorf("expected token, got %q and %v", secret, err)
+	}
+	if _, err := provider.GetSecret("MISSING"); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+}
+
+func TestCachedSecrets(t *testing.T) {
+	source := &countingSecrets{secrets: map[string]string{"TOKEN": "v1"}}
+	cached := newCachedSecrets(source, time.Minute)
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+
+	cached.GetSecret("TOKEN")
+	source.secrets["TOKEN"] = "v2"
+	if secret, _ := cached.GetSecret("TOKEN"); secret != "v1" || source.reads != 1 {
+		t.Errorf("expected the cached secret, got %q after %d reads", secret, source.reads)
+	}
+
+	now = now.Add(time.Minute)
+	if secret, _ := cached.GetSecret("TOKEN"); secret != "v2" || source.reads != 2 {
+		t.Errorf("expected the rotated secret, got %q after %d reads", secret, source.reads)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/util"
+)
+
+// ServiceBusConnection is the Azure Service Bus namespace and the shared access policy the servicebus callbacks of an
+// app send with, given either as a connection string or as the namespace with the name and the key of the policy
+type ServiceBusConnection struct {
+	// Secret reference of the connection string of the namespace
+	ConnectionString string `json:"connectionString,omitempty"`
+	Namespace        string `json:"namespace,omitempty"`
+	KeyName          string `json:"keyName,omitempty"`
+	// Secret reference of the key of the shared access policy
+	Key string `json:"key,omitempty"`
+}
+
+// Validate checks that the connection has either a connection string or a namespace with a shared access policy
+func (c *ServiceBusConnection) Validate() error {
+	if c.ConnectionString != "" {
+		if c.Namespace != "" || c.KeyName != "" || c.Key != "" {
+			return errors.New("serviceBus connectionString cannot be combined with a namespace, keyName or key")
+		}
+		return validateSecret("serviceBus connectionString", c.ConnectionString)
+	}
+
+	if c.Namespace == "" || c.KeyName == "" {
+		return errors.New("serviceBus needs either a connectionString or a namespace, keyName and key")
+	}
+	if strings.ContainsAny(c.Namespace, "/:") {
+		return fmt.Errorf("invalid serviceBus namespace %s", c.Namespace)
+	}
+	return validateSecret("serviceBus key", c.Key)
+}
+
+func validateSecret(name string, value string) error {
+	if !HasSecretRefs(value) {
+		return fmt.Errorf("%s must reference a secret as {{secret:NAME}}", name)
+	}
+	return ValidateSecretRefs(value)
+}
+
+// connectionString resolves the connection string of the namespace, made up of the namespace and the shared access
+// policy when the connection has none
+func (c *ServiceBusConnection) connectionString() (string, error) {
+	if c.ConnectionString != "" {
+		return ResolveSecrets(c.ConnectionString)
+	}
+
+	key, err := ResolveSecrets(c.Key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Endpoint=sb://%s/;SharedAccessKeyName=%s;SharedAccessKey=%s", c.namespaceHost(), c.KeyName, key), nil
+}
+
+// serviceBusClient is the client of a connection, along with the senders of the entities sent to through it
+type serviceBusClient struct {
+	connectionString string
+	client           *azservicebus.Client
+	senders          map[string]*azservicebus.Sender
+}
+
+func (c *serviceBusClient) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), cloudTimeout)
+	defer cancel()
+	for _, sender := range c.senders {
+		_ = sender.Close(ctx)
+	}
+	_ = c.client.Close(ctx)
+}
+
+// serviceBusClients keeps a client per connection, replaced once the secret of the connection is rotated
+var serviceBusClients = struct {
+	sync.Mutex
+	clients map[ServiceBusConnection]*serviceBusClient
+}{clients: map[ServiceBusConnection]*serviceBusClient{}}
+
+// sender returns the sender of the entity of the connection
+func (c *ServiceBusConnection) sender(entity string) (*azservicebus.Sender, error) {
+	connectionString, err := c.connectionString()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceBusClients.Lock()
+	defer serviceBusClients.Unlock()
+
+	cached, ok := serviceBusClients.clients[*c]
+	if ok && cached.connectionString != connectionString {
+		cached.close()
+		ok = false
+	}
+	if !ok {
+		client, err := azservicebus.NewClientFromConnectionString(connectionString, nil)
+		if err != nil {
+			return nil, err
+		}
+		cached = &serviceBusClient{connectionString: connectionString, client: client, senders: make(map[string]*azservicebus.Sender)}
+		serviceBusClients.clients[*c] = cached
+	}
+
+	sender, ok := cached.senders[entity]
+	if !ok {
+		if sender, err = cached.client.NewSender(entity, nil); err != nil {
+			return nil, err
+		}
+		cached.senders[entity] = sender
+	}
+	return sender, nil
+}
+
+func (c *ServiceBusConnection) namespaceHost() string {
+	if strings.Contains(c.Namespace, ".") {
+		return c.Namespace
+	}
+	return c.Namespace + ".servicebus.windows.net"
+}
+
+// ServiceBusDetails are the queue or topic of a servicebus callback and the session and properties of its messages
+type ServiceBusDetails struct {
+	// Entity is the queue or the topic the messages are sent to
+	Entity string `json:"entity"`
+	// Messages with the same session id are delivered in order to the session enabled queues and subscriptions
+	SessionId  string            `json:"sessionId,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// ServiceBusCallback sends the payload of every run to an Azure Service Bus queue or topic of the namespace of the app
+type ServiceBusCallback struct {
+	Type    string            `json:"type"`
+	Details ServiceBusDetails `json:"details"`
+}
+
+func (s *ServiceBusCallback) GetType() string {
+	return s.Type
+}
+
+func (s *ServiceBusCallback) GetDetails() (string, error) {
+	details, err := json.Marshal(s.Details)
+	return string(details), err
+}
+
+func (s *ServiceBusCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	details, ok := m["callback_details"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_details")
+	}
+
+	s.Type = callbackType
+	return json.Unmarshal([]byte(details), &s.Details)
+}
+
+// UnmarshalJSON Implement UnmarshalJSON for ServiceBusCallback
+func (s *ServiceBusCallback) UnmarshalJSON(data []byte) error {
+	type Alias ServiceBusCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+func (s *ServiceBusCallback) Validate() error {
+	if s.Details.Entity == "" || len(s.Details.Entity) > 260 || strings.HasPrefix(s.Details.Entity, "/") {
+		return fmt.Errorf("invalid servicebus entity %s", s.Details.Entity)
+	}
+	if len(s.Details.SessionId) > 128 {
+		return errors.New("sessionId cannot be longer than 128 characters")
+	}
+	for key := range s.Details.Properties {
+		if key == "" || strings.ContainsAny(key, " :\r\n") {
+			return fmt.Errorf("invalid servicebus property %q", key)
+		}
+	}
+	return nil
+}
+
+// Execute fails as the servicebus callbacks send with the connection of their app
+func (s *ServiceBusCallback) Execute(schedule Schedule) error {
+	return errors.New("servicebus callbacks are executed with the connection of their app")
+}
+
+// ExecuteFor sends the payload of the run to the entity with the connection of the app. The id of the run is the
+// message id, so that the duplicate detection of the entity drops the resent runs
+func (s *ServiceBusCallback) ExecuteFor(schedule Schedule, app App) error {
+	connection := app.Configuration.ServiceBus
+	if connection == nil {
+		return fmt.Errorf("app %s has no serviceBus connection", app.AppId)
+	}
+
+	sender, err := connection.sender(s.Details.Entity)
+	if err != nil {
+		return fmt.Errorf("error getting the servicebus client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cloudTimeout)
+	defer cancel()
+	if err := sender.SendMessage(ctx, s.message(schedule), nil); err != nil {
+		return fmt.Errorf("servicebus send to %s failed: %w", s.Details.Entity, err)
+	}
+	return nil
+}
+
+// message is the message of the run, with the app and the recurring schedule of the run in its properties
+func (s *ServiceBusCallback) message(schedule Schedule) *azservicebus.Message {
+	messageId, contentType := schedule.ScheduleId.String(), constants.ApplicationJson
+	message := &azservicebus.Message{
+		Body:                  []byte(schedule.Payload),
+		MessageID:             &messageId,
+		ContentType:           &contentType,
+		ApplicationProperties: map[string]interface{}{"appId": schedule.AppId},
+	}
+	if s.Details.SessionId != "" {
+		sessionId := s.Details.SessionId
+		message.SessionID = &sessionId
+	}
+	if !util.IsZeroUUID(schedule.ParentScheduleId) {
+		message.ApplicationProperties["parentScheduleId"] = schedule.ParentScheduleId.String()
+	}
+	for name, value := range s.Details.Properties {
+		message.ApplicationProperties[name] = value
+	}
+	return message
+}
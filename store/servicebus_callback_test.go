@@ -0,0 +1,100 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestServiceBusConnection_Validate(t *testing.T) {
+	for _, test := range []struct {
+		connection ServiceBusConnection
+		valid      bool
+	}{
+		{ServiceBusConnection{ConnectionString: "{{secret:SERVICEBUS_CONNECTION}}"}, true},
+		{ServiceBusConnection{Namespace: "shop", KeyName: "RootManageSharedAccessKey", Key: "{{secret:SERVICEBUS_KEY}}"}, true},
+		{ServiceBusConnection{}, false},
+		{ServiceBusConnection{ConnectionString: "Endpoint=sb://shop.servicebus.windows.net/"}, false},
+		{ServiceBusConnection{ConnectionString: "{{secret:SERVICEBUS_CONNECTION}}", Namespace: "shop"}, false},
+		{ServiceBusConnection{Namespace: "shop", KeyName: "RootManageSharedAccessKey", Key: "key"}, false},
+		{ServiceBusConnection{Namespace: "sb://shop", KeyName: "RootManageSharedAccessKey", Key: "{{secret:SERVICEBUS_KEY}}"}, false},
+		{ServiceBusConnection{Namespace: "shop", Key: "{{secret:SERVICEBUS_KEY}}"}, false},
+	} {
+		if err := test.connection.Validate(); (err == nil) != test.valid {
+			t.Errorf("connection %+v: expected valid %v, got %v", test.connection, test.valid, err)
+		}
+	}
+}
+
+func TestServiceBusCallback_Validate(t *testing.T) {
+	for _, test := range []struct {
+		details ServiceBusDetails
+		valid   bool
+	}{
+		{ServiceBusDetails{Entity: "reminders"}, true},
+		{ServiceBusDetails{Entity: "reminders", SessionId: "order-42", Properties: map[string]string{"kind": "reminder"}}, true},
+		{ServiceBusDetails{}, false},
+		{ServiceBusDetails{Entity: "/reminders"}, false},
+		{ServiceBusDetails{Entity: "reminders", SessionId: strings.Repeat("s", 129)}, false},
+		{ServiceBusDetails{Entity: "reminders", Properties: map[string]string{"order kind": "reminder"}}, false},
+	} {
+		callback := ServiceBusCallback{Type: "servicebus", Details: test.details}
+		if err := callback.Validate(); (err == nil) != test.valid {
+			t.Errorf("details %+v: expected valid %v, got %v", test.details, test.valid, err)
+		}
+	}
+}
+
+func TestServiceBusConnection_ConnectionString(t *testing.T) {
+	defer SetSecretProvider(nil)
+	SetSecretProvider(&countingSecrets{secrets: map[string]string{
+		"SERVICEBUS_CONNECTION": "Endpoint=sb://shop.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=c2VjcmV0",
+		"SERVICEBUS_KEY":        "c2VjcmV0",
+	}})
+
+	for _, test := range []struct {
+		connection ServiceBusConnection
+		expected   string
+	}{
+		{ServiceBusConnection{ConnectionString: "{{secret:SERVICEBUS_CONNECTION}}"}, "Endpoint=sb://shop.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=c2VjcmV0"},
+		{ServiceBusConnection{Namespace: "shop", KeyName: "send", Key: "{{secret:SERVICEBUS_KEY}}"}, "Endpoint=sb://shop.servicebus.windows.net/;SharedAccessKeyName=send;SharedAccessKey=c2VjcmV0"},
+		{ServiceBusConnection{Namespace: "shop.servicebus.chinacloudapi.cn", KeyName: "send", Key: "{{secret:SERVICEBUS_KEY}}"}, "Endpoint=sb://shop.servicebus.chinacloudapi.cn/;SharedAccessKeyName=send;SharedAccessKey=c2VjcmV0"},
+	} {
+		if connectionString, err := test.connection.connectionString(); err != nil || connectionString != test.expected {
+			t.Errorf("connection %+v: expected %s, got %s with error %v", test.connection, test.expected, connectionString, err)
+		}
+	}
+}
+
+func TestServiceBusCallback_ExecuteFor(t *testing.T) {
+	defer SetSecretProvider(nil)
+	SetSecretProvider(&countingSecrets{secrets: map[string]string{
+		"SERVICEBUS_CONNECTION": "Endpoint=sb://127.0.0.1/;SharedAccessKeyName=send;SharedAccessKey=c2VjcmV0",
+	}})
+
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: gocql.TimeUUID(), AppId: "orders", Payload: `{"orderId":42}`}
+	callback := ServiceBusCallback{Type: "servicebus", Details: ServiceBusDetails{Entity: "reminders", SessionId: "order-42", Properties: map[string]string{"kind": "reminder"}}}
+
+	if err := callback.ExecuteFor(schedule, App{AppId: "orders"}); err == nil {
+		t.Error("expected an error for an app without a serviceBus connection")
+	}
+
+	// nothing listens for the AMQP connection of the namespace
+	defer func(timeout time.Duration) { cloudTimeout = timeout }(cloudTimeout)
+	cloudTimeout = time.Second
+	app := App{AppId: "orders", Configuration: Configuration{ServiceBus: &ServiceBusConnection{ConnectionString: "{{secret:SERVICEBUS_CONNECTION}}"}}}
+	if err := callback.ExecuteFor(schedule, app); err == nil {
+		t.Error("expected an error for an unreachable namespace")
+	}
+
+	message := callback.message(schedule)
+	if string(message.Body) != schedule.Payload || *message.MessageID != schedule.ScheduleId.String() || *message.SessionID != "order-42" {
+		t.Errorf("unexpected message %+v", message)
+	}
+	if message.ApplicationProperties["kind"] != "reminder" || message.ApplicationProperties["appId"] != "orders" ||
+		message.ApplicationProperties["parentScheduleId"] != schedule.ParentScheduleId.String() {
+		t.Errorf("unexpected properties %v", message.ApplicationProperties)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+// ShedPolicy decides what happens to the callbacks shed while the callback workers of a node are overloaded.
+type ShedPolicy string
+
+const (
+	// DeferShedRuns fires the shed schedules again after the defer period, this is the default
+	DeferShedRuns ShedPolicy = "defer"
+	// DropShedRuns drops the shed schedules, which are only reported by an event
+	DropShedRuns ShedPolicy = "drop"
+	// NeverShed keeps the callbacks of an app from being shed
+	NeverShed ShedPolicy = "never"
+)
+
+// Validate checks that the shed policy of an app is one of the supported policies
+func (p ShedPolicy) Validate() error {
+	switch p {
+	case "", DeferShedRuns, DropShedRuns, NeverShed:
+		return nil
+	default:
+		return fmt.Errorf("invalid load shedding policy: %s, must be one of defer, drop or never", p)
+	}
+}
+
+// Shedding is the decision to shed the callback of a schedule
+type Shedding struct {
+	Policy ShedPolicy
+	Reason string
+}
+
+// ShouldShed decides whether the callback of a schedule of the app is shed given the number of callbacks waiting for a
+// worker. High priority callbacks are never shed. Low priority callbacks are shed while the node is overloaded or once
+// they are far overdue, and normal priority callbacks only when both hold.
+func ShouldShed(config conf.SheddingConfig, app App, schedule Schedule, backlog int, now time.Time) (Shedding, bool) {
+	policy := app.Configuration.LoadShedding
+	if policy == "" {
+		policy = ShedPolicy(config.Policy)
+	}
+	if !config.Enabled || policy == NeverShed {
+		return Shedding{}, false
+	}
+	if policy == "" {
+		policy = DeferShedRuns
+	}
+
+	priority := schedule.GetPriority()
+	if priority == HighPriority {
+		return Shedding{}, false
+	}
+
+	overloaded := config.MaxBacklog > 0 && backlog >= config.MaxBacklog
+	overdue := now.Unix() - schedule.ScheduleTime
+	farOverdue := config.OverdueSeconds > 0 && overdue >= int64(config.OverdueSeconds)
+
+	var reason string
+	switch {
+	case overloaded && farOverdue:
+		reason = fmt.Sprintf("%d callbacks are waiting for a worker and the schedule is %ds overdue", backlog, overdue)
+	case priority == LowPriority && overloaded:
+		reason = fmt.Sprintf("%d callbacks are waiting for a worker", backlog)
+	case priority == LowPriority && farOverdue:
+		reason = fmt.Sprintf("the schedule is %ds overdue", overdue)
+	default:
+		return Shedding{}, false
+	}
+
+	return Shedding{Policy: policy, Reason: fmt.Sprintf("%s priority callback shed, %s", priority, reason)}, true
+}
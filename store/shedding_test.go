@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestShedPolicy_Validate(t *testing.T) {
+	for _, policy := range []ShedPolicy{"", DeferShedRuns, DropShedRuns, NeverShed} {
+		if err := policy.Validate(); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", policy, err)
+		}
+	}
+	if err := ShedPolicy("later").Validate(); err == nil {
+		t.Errorf("Expected an unknown policy to be invalid")
+	}
+}
+
+func TestShouldShed(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	config := conf.SheddingConfig{Enabled: true, MaxBacklog: 100, OverdueSeconds: 600}
+	onTime := now.Unix()
+	overdue := now.Add(-time.Hour).Unix()
+
+	for _, test := range []struct {
+		name      string
+		config    conf.SheddingConfig
+		app       App
+		priority  Priority
+		scheduled int64
+		backlog   int
+		shed      bool
+		policy    ShedPolicy
+	}{
+		{"low under overload", config, App{}, LowPriority, onTime, 100, true, DeferShedRuns},
+		{"low far overdue", config, App{}, LowPriority, overdue, 0, true, DeferShedRuns},
+		{"low on time", config, App{}, LowPriority, onTime, 99, false, ""},
+		{"normal under overload", config, App{}, NormalPriority, onTime, 500, false, ""},
+		{"normal far overdue", config, App{}, "", overdue, 0, false, ""},
+		{"normal far overdue under overload", config, App{}, "", overdue, 100, true, DeferShedRuns},
+		{"high far overdue under overload", config, App{}, HighPriority, overdue, 500, false, ""},
+		{"disabled", conf.SheddingConfig{MaxBacklog: 100}, App{}, LowPriority, overdue, 500, false, ""},
+		{"checks disabled", conf.SheddingConfig{Enabled: true}, App{}, LowPriority, overdue, 500, false, ""},
+		{"cluster policy", conf.SheddingConfig{Enabled: true, MaxBacklog: 100, Policy: "drop"}, App{}, LowPriority, onTime, 100, true, DropShedRuns},
+		{"app policy", config, App{Configuration: Configuration{LoadShedding: DropShedRuns}}, LowPriority, onTime, 100, true, DropShedRuns},
+		{"app never shed", config, App{Configuration: Configuration{LoadShedding: NeverShed}}, LowPriority, overdue, 500, false, ""},
+	} {
+		schedule := Schedule{Priority: test.priority, ScheduleTime: test.scheduled}
+		shed, ok := ShouldShed(test.config, test.app, schedule, test.backlog, now)
+		if ok != test.shed || shed.Policy != test.policy {
+			t.Errorf("%s: expected shed %v with policy %q, got %v with %+v", test.name, test.shed, test.policy, ok, shed)
+		}
+		if ok && shed.Reason == "" {
+			t.Errorf("%s: expected the reason of the shedding", test.name)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+	"github.com/myntra/goscheduler/util"
+)
+
+// SidecarCallback is a plugin which delegates the delivery of the schedule to an executor running as a sidecar.
+// The details of the callback are opaque to the scheduler and are passed on to the executor as is.
+type SidecarCallback struct {
+	Type    string          `json:"type"`
+	Details json.RawMessage `json:"details"`
+
+	url    string
+	client *http.Client
+}
+
+// sidecarRequest is the request posted to the sidecar executor for every run
+type sidecarRequest struct {
+	ScheduleId       string          `json:"scheduleId"`
+	ParentScheduleId string          `json:"parentScheduleId,omitempty"`
+	AppId            string          `json:"appId"`
+	ScheduleTime     int64           `json:"scheduleTime"`
+	Payload          string          `json:"payload"`
+	Details          json.RawMessage `json:"details"`
+}
+
+// RegisterSidecar registers a callback type whose runs are posted to the executor at the supplied url
+func RegisterSidecar(callbackType string, url string, timeout time.Duration) error {
+	if url == "" {
+		return fmt.Errorf("empty executor url for sidecar type %s", callbackType)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	return RegisterPlugin(callbackType, func() Plugin {
+		return &SidecarCallback{Type: callbackType, url: url, client: client}
+	})
+}
+
+func (s *SidecarCallback) GetType() string {
+	return s.Type
+}
+
+func (s *SidecarCallback) GetDetails() (string, error) {
+	return string(s.Details), nil
+}
+
+func (s *SidecarCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	details, ok := m["callback_details"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_details")
+	}
+
+	s.Type = callbackType
+	s.Details = json.RawMessage(details)
+	return nil
+}
+
+// UnmarshalJSON Implement UnmarshalJSON for SidecarCallback
+func (s *SidecarCallback) UnmarshalJSON(data []byte) error {
+	type Alias SidecarCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+func (s *SidecarCallback) Validate() error {
+	if len(s.Details) == 0 {
+		return errors.New("details cannot be empty")
+	}
+
+	if !json.Valid(s.Details) {
+		return errors.New("details must be valid json")
+	}
+
+	return nil
+}
+
+// Execute posts the run to the sidecar executor, any non 2xx response is considered a failure
+func (s *SidecarCallback) Execute(schedule Schedule) error {
+	request := sidecarRequest{
+		ScheduleId:   schedule.ScheduleId.String(),
+		AppId:        schedule.AppId,
+		ScheduleTime: schedule.ScheduleTime,
+		Payload:      schedule.Payload,
+		Details:      s.Details,
+	}
+	if !util.IsZeroUUID(schedule.ParentScheduleId) {
+		request.ParentScheduleId = schedule.ParentScheduleId.String()
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(constants.ContentType, constants.ApplicationJson)
+	req.Header.Set(constants.ScheduleIdHeader, request.ScheduleId)
+
+	response, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < constants.HttpResponseSuccessStatusCodeLowerBound ||
+		response.StatusCode > constants.HttpResponseSuccessStatusCodeHigherBound {
+		return fmt.Errorf("sidecar executor responded with %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+const (
+	// maxSimulationSpan caps the window of a simulation
+	maxSimulationSpan = 366 * 24 * time.Hour
+	// dayLayout is the layout of the days of a simulation
+	dayLayout = "2006-01-02"
+)
+
+// Simulation is a recurrence replayed over a window of time, with the days and the windows excluded from it.
+type Simulation struct {
+	AppId          string `json:"appId,omitempty"`
+	CronExpression string `json:"cronExpression,omitempty"`
+	Every          string `json:"every,omitempty"`
+	RRule          string `json:"rrule,omitempty"`
+	// Anchor of an interval or RRULE recurrence in epoch seconds, the start of the window by default
+	Anchor int64 `json:"anchor,omitempty"`
+	// Time zone in which the cron expression is evaluated and the days are counted, the zone of the node by default
+	Timezone string `json:"timezone,omitempty"`
+	// Window of the simulation in epoch seconds, from included and to excluded
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// Days without any run as YYYY-MM-DD in the time zone of the simulation
+	ExcludeDates []string `json:"excludeDates,omitempty"`
+	// Windows without any run, on top of the blackout windows of the app
+	Exclusions []conf.BlackoutWindow `json:"exclusions,omitempty"`
+}
+
+// SimulationResult lists the fire times of a simulation in epoch seconds, up to a limit, and counts them per day
+type SimulationResult struct {
+	Runs      []int64    `json:"runs"`
+	Count     int        `json:"count"`
+	Excluded  int        `json:"excluded"`
+	Truncated bool       `json:"truncated"`
+	PerDay    []DayCount `json:"perDay"`
+}
+
+// DayCount is the number of runs of a day of a simulation
+type DayCount struct {
+	Day   string `json:"day"`
+	Count int    `json:"count"`
+}
+
+// zoned evaluates a recurrence in a time zone
+type zoned struct {
+	recurrence Recurrence
+	location   *time.Location
+}
+
+func (z zoned) Match(t time.Time) bool {
+	return z.recurrence.Match(t.In(z.location))
+}
+
+// Simulate returns the times at which the recurrence of the simulation fires within its window, leaving out the
+// excluded days, the exclusion windows and the blackout windows of the app and the global ones. At most limit runs are
+// listed, all of them are counted. Returns a non empty list of error messages if the simulation is invalid.
+func Simulate(simulation Simulation, app App, global conf.BlackoutConfig, limit int) (SimulationResult, []string) {
+	location, recurrence, errs := simulation.parse()
+	if len(errs) > 0 {
+		return SimulationResult{}, errs
+	}
+
+	excludedDays := make(map[string]bool)
+	for _, day := range simulation.ExcludeDates {
+		excludedDays[day] = true
+	}
+	windows := append([]conf.BlackoutWindow{}, simulation.Exclusions...)
+	if app.Configuration.Blackout != nil {
+		windows = append(windows, app.Configuration.Blackout.Windows...)
+	}
+	windows = append(windows, global.Windows...)
+
+	result := SimulationResult{Runs: []int64{}, PerDay: []DayCount{}}
+	from, to := time.Unix(simulation.From, 0), time.Unix(simulation.To, 0)
+	eachOccurrence(recurrence, from, to, func(t time.Time) {
+		day := t.In(location).Format(dayLayout)
+		if _, blackout := blackoutEnd(windows, t); blackout || excludedDays[day] {
+			result.Excluded++
+			return
+		}
+
+		result.Count++
+		if len(result.Runs) < limit {
+			result.Runs = append(result.Runs, t.Unix())
+		} else {
+			result.Truncated = true
+		}
+		if n := len(result.PerDay); n > 0 && result.PerDay[n-1].Day == day {
+			result.PerDay[n-1].Count++
+		} else {
+			result.PerDay = append(result.PerDay, DayCount{Day: day, Count: 1})
+		}
+	})
+	return result, nil
+}
+
+// parse validates the simulation and returns its time zone and recurrence
+func (s Simulation) parse() (*time.Location, Recurrence, []string) {
+	var errs []string
+	location := time.Local
+	if s.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(s.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid timezone: %s", s.Timezone))
+		}
+	}
+
+	switch span := time.Unix(s.To, 0).Sub(time.Unix(s.From, 0)); {
+	case s.From <= 0 || span <= 0:
+		errs = append(errs, "from must be a positive epoch before to")
+	case span > maxSimulationSpan:
+		errs = append(errs, fmt.Sprintf("the window cannot be longer than %d days", maxSimulationSpan/(24*time.Hour)))
+	}
+
+	for _, day := range s.ExcludeDates {
+		if _, err := time.Parse(dayLayout, day); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid excluded date: %s, must be YYYY-MM-DD", day))
+		}
+	}
+	if err := (Blackout{Windows: s.Exclusions}).Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	anchor := s.Anchor
+	if anchor == 0 {
+		anchor = s.From
+	}
+	recurrence, recurrenceErrs := Schedule{CronExpression: s.CronExpression, Every: s.Every, RRule: s.RRule, Anchor: anchor}.GetRecurrence()
+	errs = append(errs, recurrenceErrs...)
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+
+	if len(s.CronExpression) > 0 {
+		recurrence = zoned{recurrence: recurrence, location: location}
+	}
+	return location, recurrence, nil
+}
+
+// eachOccurrence calls f with the occurrences of the recurrence from the start of the window up to its end, in order.
+// Recurrences which cannot compute their next occurrence are matched minute by minute.
+func eachOccurrence(recurrence Recurrence, from time.Time, to time.Time, f func(time.Time)) {
+	if next, ok := recurrence.(interface {
+		Next(after time.Time) (time.Time, bool)
+	}); ok {
+		for t, found := next.Next(from.Add(-time.Minute)); found && t.Before(to); t, found = next.Next(t) {
+			if !t.Before(from) {
+				f(t)
+			}
+		}
+		return
+	}
+
+	start := from.Truncate(time.Minute)
+	if start.Before(from) {
+		start = start.Add(time.Minute)
+	}
+	for t := start; t.Before(to); t = t.Add(time.Minute) {
+		if recurrence.Match(t) {
+			f(t)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/myntra/goscheduler/conf"
+)
+
+func TestSimulate(t *testing.T) {
+	from := time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * 24 * time.Hour)
+
+	for _, test := range []struct {
+		name       string
+		simulation Simulation
+		app        App
+		global     conf.BlackoutConfig
+		limit      int
+		runs       []int64
+		count      int
+		excluded   int
+		perDay     []DayCount
+	}{
+		{
+			name:       "cron in a time zone",
+			simulation: Simulation{CronExpression: "0 9 * * *", Timezone: "Asia/Kolkata", From: from.Unix(), To: to.Unix()},
+			limit:      10,
+			runs:       []int64{from.Add(3*time.Hour + 30*time.Minute).Unix(), from.Add(27*time.Hour + 30*time.Minute).Unix(), from.Add(51*time.Hour + 30*time.Minute).Unix()},
+			count:      3,
+			perDay:     []DayCount{{"2023-12-24", 1}, {"2023-12-25", 1}, {"2023-12-26", 1}},
+		},
+		{
+			name:       "excluded dates",
+			simulation: Simulation{CronExpression: "0 9 * * *", Timezone: "UTC", From: from.Unix(), To: to.Unix(), ExcludeDates: []string{"2023-12-25"}},
+			limit:      10,
+			runs:       []int64{from.Add(9 * time.Hour).Unix(), from.Add(57 * time.Hour).Unix()},
+			count:      2,
+			excluded:   1,
+			perDay:     []DayCount{{"2023-12-24", 1}, {"2023-12-26", 1}},
+		},
+		{
+			name: "exclusion and blackout windows",
+			simulation: Simulation{Every: "12h", From: from.Unix(), To: to.Unix(), Timezone: "UTC",
+				Exclusions: []conf.BlackoutWindow{{StartTime: from.Unix(), EndTime: from.Add(time.Hour).Unix()}}},
+			app:      App{Configuration: Configuration{Blackout: &Blackout{Windows: []conf.BlackoutWindow{{Cron: "0 12 25 12 *", DurationMinutes: 60}}}}},
+			global:   conf.BlackoutConfig{Windows: []conf.BlackoutWindow{{StartTime: to.Add(-time.Hour).Unix(), EndTime: to.Unix()}}},
+			limit:    2,
+			runs:     []int64{from.Add(12 * time.Hour).Unix(), from.Add(24 * time.Hour).Unix()},
+			count:    4,
+			excluded: 2,
+			perDay:   []DayCount{{"2023-12-24", 1}, {"2023-12-25", 1}, {"2023-12-26", 2}},
+		},
+		{
+			name:       "rrule anchored before the window",
+			simulation: Simulation{RRule: "FREQ=DAILY;BYHOUR=6;BYMINUTE=0", Anchor: from.Add(-30 * 24 * time.Hour).Unix(), From: from.Unix(), To: to.Unix(), Timezone: "UTC"},
+			limit:      0,
+			runs:       []int64{},
+			count:      3,
+			perDay:     []DayCount{{"2023-12-24", 1}, {"2023-12-25", 1}, {"2023-12-26", 1}},
+		},
+	} {
+		result, errs := Simulate(test.simulation, test.app, test.global, test.limit)
+		if len(errs) > 0 {
+			t.Fatalf("%s: unexpected errors %v", test.name, errs)
+		}
+		if !reflect.DeepEqual(result.Runs, test.runs) || result.Count != test.count || result.Excluded != test.excluded ||
+			result.Truncated != (test.count > len(test.runs)) || !reflect.DeepEqual(result.PerDay, test.perDay) {
+			t.Errorf("%s: unexpected result %+v", test.name, result)
+		}
+	}
+}
+
+func TestSimulate_Invalid(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	for _, simulation := range []Simulation{
+		{CronExpression: "0 9 * * *", From: from, To: from},
+		{CronExpression: "0 9 * * *", From: from, To: from + 400*24*3600},
+		{CronExpression: "0 9 * * *", From: from, To: from + 3600, Timezone: "Mars/Olympus"},
+		{CronExpression: "0 9 * * *", From: from, To: from + 3600, ExcludeDates: []string{"25/12/2023"}},
+		{CronExpression: "0 9 * * *", From: from, To: from + 3600, Exclusions: []conf.BlackoutWindow{{StartTime: from, EndTime: from}}},
+		{From: from, To: from + 3600},
+		{CronExpression: "0 9 * * *", Every: "1h", From: from, To: from + 3600},
+	} {
+		if _, errs := Simulate(simulation, App{}, conf.BlackoutConfig{}, 10); len(errs) == 0 {
+			t.Errorf("Expected %+v to be invalid", simulation)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// Actions taken on the recurring schedules of an app paused longer than its stale pause policy allows
+const (
+	StaleDelete = "delete"
+	StaleAlert  = "alert"
+)
+
+// maxStalePausedDays bounds the days a stale pause policy lets a schedule stay paused for
+const maxStalePausedDays = 3650
+
+// StalePausePolicy deletes, or alerts on, the recurring schedules of an app which have been paused for more than a
+// number of days. Schedules with keepPaused set are exempted, as are the ones paused before their pause time was recorded.
+type StalePausePolicy struct {
+	// Days a schedule can stay paused before it is stale
+	PausedDays int `json:"pausedDays"`
+	// Action taken on the stale schedules, delete or alert
+	Action string `json:"action"`
+}
+
+// Validate checks the days and the action of the policy
+func (p *StalePausePolicy) Validate() error {
+	if p.PausedDays < 1 || p.PausedDays > maxStalePausedDays {
+		return fmt.Errorf("pausedDays: %d must be between 1 and %d", p.PausedDays, maxStalePausedDays)
+	}
+	if p.Action != StaleDelete && p.Action != StaleAlert {
+		return fmt.Errorf("invalid stale pause action: %s, must be one of delete or alert", p.Action)
+	}
+	return nil
+}
+
+// Stale tells whether the schedule has been paused for longer than the policy allows at now
+func (p *StalePausePolicy) Stale(schedule Schedule, now time.Time) bool {
+	if schedule.Status != Paused || schedule.KeepPaused || schedule.PausedAt == 0 {
+		return false
+	}
+	return now.Sub(time.Unix(schedule.PausedAt, 0)) >= time.Duration(p.PausedDays)*24*time.Hour
+}
+
+type staleAlertKey struct {
+	scheduleId gocql.UUID
+	pausedAt   int64
+}
+
+// StaleAlerts remembers the stale schedules the node alerted on, so that a schedule is alerted on once per pause
+type StaleAlerts struct {
+	mu      sync.Mutex
+	alerted map[staleAlertKey]bool
+}
+
+var staleAlerts = NewStaleAlerts()
+
+// StalePauseAlerts returns the stale schedules alerted on by the node
+func StalePauseAlerts() *StaleAlerts {
+	return staleAlerts
+}
+
+func NewStaleAlerts() *StaleAlerts {
+	return &StaleAlerts{alerted: map[staleAlertKey]bool{}}
+}
+
+// Alert tells whether the stale schedule is to be alerted on, only the first time for a pause
+func (a *StaleAlerts) Alert(schedule Schedule) bool {
+	key := staleAlertKey{schedule.ScheduleId, schedule.PausedAt}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.alerted[key] {
+		return false
+	}
+	a.alerted[key] = true
+	return true
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestStalePausePolicyValidate(t *testing.T) {
+	for _, test := range []struct {
+		policy StalePausePolicy
+		valid  bool
+	}{
+		{StalePausePolicy{PausedDays: 30, Action: StaleDelete}, true},
+		{StalePausePolicy{PausedDays: 30, Action: StaleAlert}, true},
+		{StalePausePolicy{Action: StaleDelete}, false},
+		{StalePausePolicy{PausedDays: 3651, Action: StaleDelete}, false},
+		{StalePausePolicy{PausedDays: 30, Action: "archive"}, false},
+	} {
+		if err := test.policy.Validate(); (err == nil) != test.valid {
+			t.Errorf("Validate() of %+v = %v, want valid %v", test.policy, err, test.valid)
+		}
+	}
+}
+
+func TestStalePausePolicyStale(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	policy := StalePausePolicy{PausedDays: 7, Action: StaleAlert}
+	pausedDaysAgo := func(days int) int64 {
+		return now.Add(-time.Duration(days) * 24 * time.Hour).Unix()
+	}
+
+	for _, test := range []struct {
+		name     string
+		schedule Schedule
+		want     bool
+	}{
+		{"Stale", Schedule{Status: Paused, PausedAt: pausedDaysAgo(7)}, true},
+		{"Recent", Schedule{Status: Paused, PausedAt: pausedDaysAgo(6)}, false},
+		{"Exempt", Schedule{Status: Paused, PausedAt: pausedDaysAgo(30), KeepPaused: true}, false},
+		{"PauseTimeUnknown", Schedule{Status: Paused}, false},
+		{"Scheduled", Schedule{Status: Scheduled, PausedAt: pausedDaysAgo(30)}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := policy.Stale(test.schedule, now); got != test.want {
+				t.Errorf("Stale() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStaleAlertsAlert(t *testing.T) {
+	alerts := NewStaleAlerts()
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), Status: Paused, PausedAt: 1700000000}
+
+	if !alerts.Alert(schedule) {
+		t.Errorf("A stale schedule should be alerted on the first time")
+	}
+	if alerts.Alert(schedule) {
+		t.Errorf("A stale schedule should be alerted on once per pause")
+	}
+	schedule.PausedAt++
+	if !alerts.Alert(schedule) {
+		t.Errorf("A schedule paused again should be alerted on again")
+	}
+}
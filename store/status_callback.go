@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"time"
+
+	"github.com/myntra/goscheduler/util"
+)
+
+// StatusCallbackEvent is the outcome of a single run that is posted to the status callback url of a schedule.
+type StatusCallbackEvent struct {
+	ScheduleId       string `json:"scheduleId"`
+	ParentScheduleId string `json:"parentScheduleId,omitempty"`
+	TraceId          string `json:"traceId,omitempty"`
+	AppId            string `json:"appId"`
+	ScheduleTime     int64  `json:"scheduleTime"`
+	Status           Status `json:"status"`
+	StatusCode       int    `json:"statusCode,omitempty"`
+	ErrorMessage     string `json:"errorMessage,omitempty"`
+	LatencyMillis    int64  `json:"latencyMillis"`
+	Attempt          int    `json:"attempt"`
+	FiredAt          int64  `json:"firedAt"`
+}
+
+// StatusCallbackTask is a status callback event along with the url it has to be posted to.
+type StatusCallbackTask struct {
+	Url   string
+	Event StatusCallbackEvent
+}
+
+// NewStatusCallbackEvent creates the status callback event for a run from the schedule with its outcome set.
+func NewStatusCallbackEvent(run Schedule, statusCode int, attempt int, firedAt time.Time, latency time.Duration) StatusCallbackEvent {
+	event := StatusCallbackEvent{
+		ScheduleId:    run.ScheduleId.String(),
+		TraceId:       run.TraceId,
+		AppId:         run.AppId,
+		ScheduleTime:  run.ScheduleTime,
+		Status:        run.Status,
+		StatusCode:    statusCode,
+		ErrorMessage:  run.ErrorMessage,
+		LatencyMillis: int64(latency / time.Millisecond),
+		Attempt:       attempt,
+		FiredAt:       firedAt.Unix(),
+	}
+
+	if !util.IsZeroUUID(run.ParentScheduleId) {
+		event.ParentScheduleId = run.ParentScheduleId.String()
+	}
+
+	return event
+}
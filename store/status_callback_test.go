@@ -0,0 +1,77 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestNewStatusCallbackEvent(t *testing.T) {
+	parent := gocql.TimeUUID()
+	run := Schedule{
+		ScheduleId:       gocql.TimeUUID(),
+		ParentScheduleId: parent,
+		AppId:            "testApp",
+		ScheduleTime:     1700000000,
+		Status:           Failure,
+		ErrorMessage:     "500 Internal Server Error",
+	}
+	firedAt := time.Unix(1700000001, 0)
+
+	event := NewStatusCallbackEvent(run, 500, 3, firedAt, 1500*time.Millisecond)
+
+	if event.ScheduleId != run.ScheduleId.String() || event.ParentScheduleId != parent.String() {
+		t.Errorf("Unexpected ids in event %+v", event)
+	}
+	if event.Status != Failure || event.StatusCode != 500 || event.ErrorMessage != run.ErrorMessage {
+		t.Errorf("Unexpected outcome in event %+v", event)
+	}
+	if event.LatencyMillis != 1500 || event.Attempt != 3 || event.FiredAt != firedAt.Unix() {
+		t.Errorf("Unexpected timings in event %+v", event)
+	}
+
+	run.ParentScheduleId = gocql.UUID{}
+	if event := NewStatusCallbackEvent(run, 0, 1, firedAt, 0); event.ParentScheduleId != "" {
+		t.Errorf("Expected empty parent schedule id, got %s", event.ParentScheduleId)
+	}
+}
+
+func TestValidateStatusCallback(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"", true},
+		{"https://example.com/status", true},
+		{"http://localhost:8080/status", true},
+		{"ftp://example.com/status", false},
+		{"/status", false},
+		{"not a url", false},
+	}
+
+	for _, test := range tests {
+		if errStr := validateStatusCallback(test.url); (errStr == "") != test.valid {
+			t.Errorf("validateStatusCallback(%q) = %q, expected valid: %v", test.url, errStr, test.valid)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ResumeSuspendedJob resumes the suspended recurring schedules of an app
+const ResumeSuspendedJob JobType = "resumeSuspended"
+
+// ResumeSuspendedRequest is the request of a resume suspended job, all the suspended schedules of the app are resumed
+// when no schedule ids are given
+type ResumeSuspendedRequest struct {
+	ScheduleIds []string `json:"scheduleIds,omitempty"`
+}
+
+// Unreachable tells whether a callback failed because its target no longer exists: its host is not found by the
+// dns or it refuses the connection. Timeouts and error responses are not counted, the target may only be down.
+func Unreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) {
+		return dnsError.IsNotFound
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// CallbackHost returns the host the http callback of the schedule is sent to, empty for the other callbacks
+func CallbackHost(schedule Schedule) string {
+	callback, ok := schedule.Callback.(*HttpCallback)
+	if !ok {
+		return ""
+	}
+
+	u, err := url.Parse(callback.Details.Url)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// unreachableHost is the streak of unreachable callbacks of a host
+type unreachableHost struct {
+	since    time.Time
+	failures int
+}
+
+// UnreachableHosts tracks in memory the hosts whose callbacks fired by the node keep failing as unreachable
+type UnreachableHosts struct {
+	mu    sync.Mutex
+	hosts map[string]*unreachableHost
+}
+
+var unreachableHosts = NewUnreachableHosts()
+
+// Unreachables returns the hosts tracked by the node
+func Unreachables() *UnreachableHosts {
+	return unreachableHosts
+}
+
+func NewUnreachableHosts() *UnreachableHosts {
+	return &UnreachableHosts{hosts: map[string]*unreachableHost{}}
+}
+
+// Record counts the outcome of a callback to the host at now, any callback which is not unreachable ends the streak
+// of the host. Returns true if the host has been unreachable for at least failures callbacks over the window.
+func (u *UnreachableHosts) Record(host string, unreachable bool, now time.Time, window time.Duration, failures int) bool {
+	if host == "" {
+		return false
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !unreachable {
+		delete(u.hosts, host)
+		return false
+	}
+
+	streak, ok := u.hosts[host]
+	if !ok {
+		streak = &unreachableHost{since: now}
+		u.hosts[host] = streak
+	}
+	streak.failures++
+	return streak.failures >= failures && now.Sub(streak.since) >= window
+}
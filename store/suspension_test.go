@@ -0,0 +1,67 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestUnreachable(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"NoError", nil, false},
+		{"NotFound", &net.DNSError{Err: "no such host", Name: "gone.example.com", IsNotFound: true}, true},
+		{"DnsTimeout", &net.DNSError{Err: "i/o timeout", Name: "slow.example.com", IsTimeout: true}, false},
+		{"Refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{"Wrapped", fmt.Errorf("post: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), true},
+		{"Reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, false},
+		{"Other", errors.New("503 Service Unavailable"), false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Unreachable(test.err); got != test.want {
+				t.Errorf("Unreachable() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnreachableHostsRecord(t *testing.T) {
+	hosts := NewUnreachableHosts()
+	start := time.Unix(1700000000, 0)
+	window := time.Hour
+
+	if hosts.Record("gone.example.com", true, start, window, 2) {
+		t.Errorf("A first failure should not make the host unreachable")
+	}
+	if hosts.Record("gone.example.com", true, start.Add(time.Minute), window, 2) {
+		t.Errorf("Failures within the window should not make the host unreachable")
+	}
+	if !hosts.Record("gone.example.com", true, start.Add(window), window, 2) {
+		t.Errorf("Failures over the window should make the host unreachable")
+	}
+	if hosts.Record("other.example.com", true, start.Add(window), window, 1) {
+		t.Errorf("The streak of a host should not count for another")
+	}
+
+	hosts.Record("gone.example.com", false, start.Add(window), window, 2)
+	if hosts.Record("gone.example.com", true, start.Add(2*window), window, 1) {
+		t.Errorf("A reachable callback should end the streak of the host")
+	}
+}
+
+func TestCallbackHost(t *testing.T) {
+	schedule := Schedule{Callback: &HttpCallback{Type: "http", Details: Details{Url: "http://orders.example.com:8080/reconcile"}}}
+	if got := CallbackHost(schedule); got != "orders.example.com:8080" {
+		t.Errorf("CallbackHost() = %s, want orders.example.com:8080", got)
+	}
+	if got := CallbackHost(Schedule{}); got != "" {
+		t.Errorf("CallbackHost() = %s, want empty", got)
+	}
+}
@@ -29,8 +29,15 @@ type Task struct {
 
 var (
 	OldHttpTaskQueue chan ScheduleWrapper
-	HttpTaskQueue    chan ScheduleWrapper
-	AirbusTaskQueue  chan ScheduleWrapper
+	// HttpTaskQueue hands the schedules of http callbacks to the http workers, higher priorities first
+	HttpTaskQueue   *PriorityQueue
+	AirbusTaskQueue chan ScheduleWrapper
+	// PluginTaskQueue hands the schedules of plugin callbacks without a worker pool of their own to the plugin
+	// workers, higher priorities first
+	PluginTaskQueue *PriorityQueue
+	// PluginTaskQueues hands the schedules of each plugin callback type registered at startup to the worker pool
+	// of the type, so that a slow or failing plugin does not hold up the others
+	PluginTaskQueues map[string]*PriorityQueue
 	// CronTaskQueue Channel sends the tasks to convert a recurring schedule to one time schedules
 	CronTaskQueue chan CreateScheduleTask
 	// AggregationTaskQueue Channel aggregates the schedules and forward to status update
@@ -39,16 +46,74 @@ var (
 	StatusTaskQueue chan StatusTask
 	// BulkActionQueue Channel used to perform actions in bulk (Ex. Reconcile/Delete etc)
 	BulkActionQueue chan BulkActionTask
+	// StatusCallbackTaskQueue Channel posts the outcome of runs to the status callback urls of schedules
+	StatusCallbackTaskQueue chan StatusCallbackTask
+	// EventTaskQueue Channel publishes the lifecycle events of schedules
+	EventTaskQueue chan Event
+	// FailureNotificationTaskQueue Channel checks the failed runs of recurring schedules against the failure
+	// notification of their app
+	FailureNotificationTaskQueue chan FailureNotificationTask
+	// PullTaskQueue hands the due runs of pull callbacks to the workers storing them for their consumers
+	PullTaskQueue *PriorityQueue
+	// PullAckTaskQueue Channel completes the runs of pull callbacks acknowledged by their consumers
+	PullAckTaskQueue chan PullAckTask
 )
 
+// CallbackBacklog returns the number of schedules waiting for a http or plugin callback worker
+func CallbackBacklog() int {
+	backlog := 0
+	queues := []*PriorityQueue{HttpTaskQueue, PluginTaskQueue}
+	for _, queue := range PluginTaskQueues {
+		queues = append(queues, queue)
+	}
+	for _, queue := range queues {
+		if queue != nil {
+			backlog += queue.Len()
+		}
+	}
+	return backlog
+}
+
+// pluginQueue returns the queue of the worker pool of the plugin callback type
+func pluginQueue(callbackType string) *PriorityQueue {
+	if queue, ok := PluginTaskQueues[callbackType]; ok {
+		return queue
+	}
+	return PluginTaskQueue
+}
+
+// isPlugin tells whether the callback type is registered as a plugin
+func isPlugin(factory Factory) bool {
+	_, ok := factory().(*pluginCallback)
+	return ok
+}
+
 func (t *Task) InitTaskQueues() {
 	OldHttpTaskQueue = make(chan ScheduleWrapper)
-	HttpTaskQueue = make(chan ScheduleWrapper)
+	HttpTaskQueue = NewBoundedPriorityQueue(t.Conf.HttpConnector.QueueSize)
 	AirbusTaskQueue = make(chan ScheduleWrapper)
+	PluginTaskQueue = NewBoundedPriorityQueue(t.Conf.CallbackPluginConfig.QueueSize)
+	//every plugin type registered by now gets a queue, and a worker pool, of its own
+	PluginTaskQueues = make(map[string]*PriorityQueue)
+	for callbackType, factory := range Registry {
+		if isPlugin(factory) {
+			PluginTaskQueues[callbackType] = NewBoundedPriorityQueue(t.Conf.CallbackPluginConfig.GetPool(callbackType).QueueSize)
+		}
+	}
 	CronTaskQueue = make(chan CreateScheduleTask)
 	//making the channel buffered in order to regulate the flow in a better way
 	AggregationTaskQueue = make(chan ScheduleWrapper, t.Conf.AggregateSchedulesConfig.BufferSize)
 	StatusTaskQueue = make(chan StatusTask)
 	//making the channel buffered in order to regulate the flow in a better way
 	BulkActionQueue = make(chan BulkActionTask, t.Conf.BulkActionConfig.BufferSize)
+	//status callbacks are best effort, the buffer decouples them from the callback workers
+	StatusCallbackTaskQueue = make(chan StatusCallbackTask, t.Conf.StatusCallbackConfig.BufferSize)
+	//lifecycle events are best effort, the buffer decouples them from the requests and callbacks
+	EventTaskQueue = make(chan Event, t.Conf.EventPublisherConfig.BufferSize)
+	//failure notifications are best effort, the buffer decouples them from the callback workers
+	FailureNotificationTaskQueue = make(chan FailureNotificationTask, t.Conf.NotificationConfig.BufferSize)
+	PullTaskQueue = NewBoundedPriorityQueue(t.Conf.PullDeliveryConfig.QueueSize)
+	FireEventStream = NewEventStream(t.Conf.EventStreamConfig.MaxSubscribers, t.Conf.EventStreamConfig.BufferSize)
+	//acknowledgements are completed in the background, the buffer decouples them from the requests
+	PullAckTaskQueue = make(chan PullAckTask, t.Conf.PullDeliveryConfig.BufferSize)
 }
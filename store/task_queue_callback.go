@@ -0,0 +1,158 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/myntra/goscheduler/util"
+)
+
+// QueueTask is the work a task queue callback enqueues for a run
+type QueueTask struct {
+	// Id of the run, the idempotency key of the task so that a resent run is not processed twice
+	Id               string
+	AppId            string
+	ParentScheduleId string
+	ScheduleTime     int64
+	Queue            string
+	// Name of the task or the workflow started by the consumers of the queue
+	Name    string
+	Payload string
+	Headers map[string]string
+}
+
+// TaskQueueClient enqueues tasks with the Go client of a task queue or workflow system.
+// Enqueue must return once the task is durably accepted, an error fails the run.
+type TaskQueueClient interface {
+	Enqueue(ctx context.Context, task QueueTask) error
+}
+
+// TaskQueueDetails are the queue and the name of the task a task queue callback enqueues
+type TaskQueueDetails struct {
+	Queue   string            `json:"queue"`
+	Task    string            `json:"task"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// TaskQueueCallback enqueues a task with the payload of every run to the task queue of its callback type
+type TaskQueueCallback struct {
+	Type    string           `json:"type"`
+	Details TaskQueueDetails `json:"details"`
+	client  TaskQueueClient
+	timeout time.Duration
+}
+
+// RegisterTaskQueue registers a callback type whose runs are enqueued with the supplied client, the enqueue
+// being cancelled after timeout
+func RegisterTaskQueue(callbackType string, client TaskQueueClient, timeout time.Duration) error {
+	if client == nil {
+		return fmt.Errorf("nil client for task queue type %s", callbackType)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("invalid timeout %v for task queue type %s", timeout, callbackType)
+	}
+
+	return RegisterPlugin(callbackType, func() Plugin {
+		return &TaskQueueCallback{Type: callbackType, client: client, timeout: timeout}
+	})
+}
+
+func (t *TaskQueueCallback) GetType() string {
+	return t.Type
+}
+
+func (t *TaskQueueCallback) GetDetails() (string, error) {
+	details, err := json.Marshal(t.Details)
+	return string(details), err
+}
+
+func (t *TaskQueueCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	details, ok := m["callback_details"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_details")
+	}
+
+	t.Type = callbackType
+	return json.Unmarshal([]byte(details), &t.Details)
+}
+
+// UnmarshalJSON Implement UnmarshalJSON for TaskQueueCallback
+func (t *TaskQueueCallback) UnmarshalJSON(data []byte) error {
+	type Alias TaskQueueCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(t),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+func (t *TaskQueueCallback) Validate() error {
+	if t.Details.Queue == "" {
+		return errors.New("task queue callback queue cannot be empty")
+	}
+	if t.Details.Task == "" {
+		return errors.New("task queue callback task cannot be empty")
+	}
+	for name := range t.Details.Headers {
+		if name == "" {
+			return errors.New("task queue callback header names cannot be empty")
+		}
+	}
+	return nil
+}
+
+// Execute enqueues the task of the run with the client of the callback type
+func (t *TaskQueueCallback) Execute(schedule Schedule) error {
+	if t.client == nil {
+		return fmt.Errorf("no task queue client is registered for callback type %s", t.Type)
+	}
+
+	task := QueueTask{
+		Id:           schedule.ScheduleId.String(),
+		AppId:        schedule.AppId,
+		ScheduleTime: schedule.ScheduleTime,
+		Queue:        t.Details.Queue,
+		Name:         t.Details.Task,
+		Payload:      schedule.Payload,
+		Headers:      t.Details.Headers,
+	}
+	if !util.IsZeroUUID(schedule.ParentScheduleId) {
+		task.ParentScheduleId = schedule.ParentScheduleId.String()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	if err := t.client.Enqueue(ctx, task); err != nil {
+		return fmt.Errorf("error enqueuing task %s to queue %s: %w", t.Details.Task, t.Details.Queue, err)
+	}
+	return nil
+}
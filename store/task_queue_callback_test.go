@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// memoryQueue is an example TaskQueueClient keeping the tasks in memory. A real client wraps the Go client of the
+// task queue, e.g. starting a workflow with the task id as the workflow id.
+type memoryQueue struct {
+	sync.Mutex
+	tasks map[string][]QueueTask
+}
+
+func (m *memoryQueue) Enqueue(ctx context.Context, task QueueTask) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	for _, queued := range m.tasks[task.Queue] {
+		if queued.Id == task.Id {
+			return nil
+		}
+	}
+	m.tasks[task.Queue] = append(m.tasks[task.Queue], task)
+	return nil
+}
+
+type failingQueue struct{}
+
+func (failingQueue) Enqueue(ctx context.Context, task QueueTask) error {
+	return errors.New("queue unavailable")
+}
+
+func ExampleRegisterTaskQueue() {
+	queue := &memoryQueue{tasks: map[string][]QueueTask{}}
+	if err := RegisterTaskQueue("example-queue", queue, 5*time.Second); err != nil {
+		panic(err)
+	}
+
+	callback := Registry["example-queue"]().(*pluginCallback)
+	callback.Plugin.(*TaskQueueCallback).Details = TaskQueueDetails{Queue: "settlements", Task: "SettleOrders"}
+	_ = callback.Execute(Schedule{ScheduleId: gocql.TimeUUID(), AppId: "payments", Payload: `{"batch":42}`})
+
+	task := queue.tasks["settlements"][0]
+	fmt.Println(task.Name, task.Payload)
+	// Output: SettleOrders {"batch":42}
+}
+
+func TestRegisterTaskQueue(t *testing.T) {
+	if err := RegisterTaskQueue("nilQueue", nil, time.Second); err == nil {
+		t.Error("expected an error for a nil client")
+	}
+	if err := RegisterTaskQueue("noTimeoutQueue", &memoryQueue{}, 0); err == nil {
+		t.Error("expected an error for a zero timeout")
+	}
+}
+
+func TestTaskQueueCallback_Validate(t *testing.T) {
+	for _, test := range []struct {
+		details TaskQueueDetails
+		valid   bool
+	}{
+		{TaskQueueDetails{Queue: "settlements", Task: "SettleOrders"}, true},
+		{TaskQueueDetails{Queue: "settlements", Task: "SettleOrders", Headers: map[string]string{"tenant": "shop"}}, true},
+		{TaskQueueDetails{Task: "SettleOrders"}, false},
+		{TaskQueueDetails{Queue: "settlements"}, false},
+		{TaskQueueDetails{Queue: "settlements", Task: "SettleOrders", Headers: map[string]string{"": "shop"}}, false},
+	} {
+		callback := TaskQueueCallback{Type: "taskqueue", Details: test.details}
+		if err := callback.Validate(); (err == nil) != test.valid {
+			t.Errorf("details %+v: expected valid %v, got %v", test.details, test.valid, err)
+		}
+	}
+}
+
+func TestTaskQueueCallback_Execute(t *testing.T) {
+	queue := &memoryQueue{tasks: map[string][]QueueTask{}}
+	callback := TaskQueueCallback{
+		Type:    "taskqueue",
+		Details: TaskQueueDetails{Queue: "settlements", Task: "SettleOrders", Headers: map[string]string{"tenant": "shop"}},
+		client:  queue,
+		timeout: time.Second,
+	}
+	schedule := Schedule{ScheduleId: gocql.TimeUUID(), ParentScheduleId: gocql.TimeUUID(), AppId: "payments", Payload: "{}", ScheduleTime: 1686676947}
+
+	for i := 0; i < 2; i++ {
+		if err := callback.Execute(schedule); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if len(queue.tasks["settlements"]) != 1 {
+		t.Fatalf("expected the resent run enqueued once, got %+v", queue.tasks)
+	}
+	task := queue.tasks["settlements"][0]
+	if task.Id != schedule.ScheduleId.String() || task.ParentScheduleId != schedule.ParentScheduleId.String() ||
+		task.AppId != "payments" || task.ScheduleTime != 1686676947 || task.Headers["tenant"] != "shop" {
+		t.Errorf("unexpected task %+v", task)
+	}
+
+	callback.client = failingQueue{}
+	if err := callback.Execute(schedule); err == nil {
+		t.Error("expected the error of the client")
+	}
+
+	callback.client = nil
+	if err := callback.Execute(schedule); err == nil {
+		t.Error("expected an error without a client")
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/myntra/goscheduler/constants"
+)
+
+// templateCacheTTL bounds how long a node keeps firing an old version after a template is updated on another node
+const templateCacheTTL = 30 * time.Second
+
+var templateNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,127}$`)
+
+// ErrTemplatesUnavailable is returned when templates are resolved before a template store is set
+var ErrTemplatesUnavailable = errors.New("callback templates are not available")
+
+// CallbackTemplate is a named and versioned callback of an app which schedules reference instead of
+// embedding the callback. Every update creates a new version.
+type CallbackTemplate struct {
+	AppId     string          `json:"appId"`
+	Name      string          `json:"name"`
+	Version   int             `json:"version"`
+	Callback  json.RawMessage `json:"callback"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// ValidateTemplateName checks that the name can be referenced by schedules
+func ValidateTemplateName(name string) error {
+	if !templateNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %s, must be up to 128 letters, digits, dots, underscores or hyphens", name)
+	}
+	return nil
+}
+
+// Validate checks the name of the template and its callback, which cannot reference another template
+func (t CallbackTemplate) Validate() error {
+	if err := ValidateTemplateName(t.Name); err != nil {
+		return err
+	}
+
+	callback, err := t.GetCallback()
+	if err != nil {
+		return err
+	}
+	if callback.GetType() == constants.TemplateCallback {
+		return errors.New("a template callback cannot reference another template")
+	}
+	return callback.Validate()
+}
+
+// GetCallback creates the callback of the template from its json
+func (t CallbackTemplate) GetCallback() (Callback, error) {
+	if len(t.Callback) == 0 {
+		return nil, errors.New("template callback cannot be empty")
+	}
+
+	var callbackType struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(t.Callback, &callbackType); err != nil {
+		return nil, fmt.Errorf("invalid template callback: %w", err)
+	}
+
+	factory, ok := Registry[callbackType.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown callback type: %s", callbackType.Type)
+	}
+
+	callback := factory()
+	if err := json.Unmarshal(t.Callback, callback); err != nil {
+		return nil, fmt.Errorf("invalid template callback: %w", err)
+	}
+	return callback, nil
+}
+
+// TemplateRef references a template of the app of the schedule, the latest version when the version is 0
+type TemplateRef struct {
+	Name    string `json:"name"`
+	Version int    `json:"version,omitempty"`
+}
+
+// TemplateCallback is the callback of the schedules referencing a template.
+// The template is resolved when the schedule fires, so the schedules referencing the latest version pick up the
+// updates of the template while the ones pinned to a version keep firing it.
+type TemplateCallback struct {
+	Type    string      `json:"type"`
+	Details TemplateRef `json:"details"`
+}
+
+func (t *TemplateCallback) GetType() string {
+	return t.Type
+}
+
+func (t *TemplateCallback) GetDetails() (string, error) {
+	details, err := json.Marshal(t.Details)
+	return string(details), err
+}
+
+func (t *TemplateCallback) Marshal(m map[string]interface{}) error {
+	callbackType, ok := m["callback_type"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_type")
+	}
+
+	callbackDetailsJSON, ok := m["callback_details"].(string)
+	if !ok {
+		return fmt.Errorf("wrong type for callback_details")
+	}
+
+	var details TemplateRef
+	if err := json.Unmarshal([]byte(callbackDetailsJSON), &details); err != nil {
+		return err
+	}
+
+	t.Type = callbackType
+	t.Details = details
+	return nil
+}
+
+func (t *TemplateCallback) UnmarshalJSON(data []byte) error {
+	type Alias TemplateCallback
+	aux := &struct {
+		*Alias
+	}{
+		Alias: (*Alias)(t),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+// Invoke resolves the template and invokes its callback in place of the template callback
+func (t TemplateCallback) Invoke(wrapper ScheduleWrapper) error {
+	callback, err := ResolveTemplate(wrapper.Schedule.AppId, t.Details)
+	if err != nil {
+		return fmt.Errorf("template %s of app %s could not be resolved: %w", t.Details.Name, wrapper.Schedule.AppId, err)
+	}
+
+	wrapper.Schedule.Callback = callback
+	return callback.Invoke(wrapper)
+}
+
+func (t *TemplateCallback) Validate() error {
+	if err := ValidateTemplateName(t.Details.Name); err != nil {
+		return err
+	}
+	if t.Details.Version < 0 {
+		return fmt.Errorf("invalid template version %d", t.Details.Version)
+	}
+	return nil
+}
+
+// TemplateStore reads the templates of the apps, version 0 reads the latest version.
+// Returns gocql.ErrNotFound if the template or the version does not exist.
+type TemplateStore interface {
+	GetCallbackTemplate(appId string, name string, version int) (CallbackTemplate, error)
+}
+
+type templateKey struct {
+	appId   string
+	name    string
+	version int
+}
+
+type cachedTemplate struct {
+	callback Callback
+	cachedAt time.Time
+}
+
+// templateResolver resolves the templates of the schedules, caching them for the templateCacheTTL
+type templateResolver struct {
+	mu    sync.Mutex
+	store TemplateStore
+	cache map[templateKey]cachedTemplate
+}
+
+var templates = &templateResolver{cache: map[templateKey]cachedTemplate{}}
+
+// SetTemplateStore sets the store the templates are resolved from
+func SetTemplateStore(store TemplateStore) {
+	templates.mu.Lock()
+	defer templates.mu.Unlock()
+	templates.store = store
+	templates.cache = map[templateKey]cachedTemplate{}
+}
+
+// ResolveTemplate returns the callback of the referenced template of the app
+func ResolveTemplate(appId string, ref TemplateRef) (Callback, error) {
+	key := templateKey{appId, ref.Name, ref.Version}
+
+	templates.mu.Lock()
+	store := templates.store
+	cached, ok := templates.cache[key]
+	templates.mu.Unlock()
+
+	if ok && time.Since(cached.cachedAt) < templateCacheTTL {
+		return cached.callback, nil
+	}
+	if store == nil {
+		return nil, ErrTemplatesUnavailable
+	}
+
+	template, err := store.GetCallbackTemplate(appId, ref.Name, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	callback, err := template.GetCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	templates.mu.Lock()
+	templates.cache[key] = cachedTemplate{callback: callback, cachedAt: time.Now()}
+	templates.mu.Unlock()
+	return callback, nil
+}
+
+// InvalidateTemplate drops the cached latest version of the template, so that the node fires an update right away
+func InvalidateTemplate(appId string, name string) {
+	templates.mu.Lock()
+	defer templates.mu.Unlock()
+	delete(templates.cache, templateKey{appId, name, 0})
+}
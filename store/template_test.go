@@ -0,0 +1,120 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/myntra/goscheduler/constants"
+)
+
+type fakeTemplateStore struct {
+	templates map[string][]CallbackTemplate
+	reads     int
+}
+
+func (f *fakeTemplateStore) GetCallbackTemplate(appId string, name string, version int) (CallbackTemplate, error) {
+	f.reads++
+	versions := f.templates[appId+"/"+name]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if version == 0 || versions[i].Version == version {
+			return versions[i], nil
+		}
+	}
+	return CallbackTemplate{}, gocql.ErrNotFound
+}
+
+func httpTemplate(name string, version int, url string) CallbackTemplate {
+	return CallbackTemplate{
+		AppId:    "app",
+		Name:     name,
+		Version:  version,
+		Callback: json.RawMessage(`{"type":"http","details":{"url":"` + url + `","method":"POST","headers":{}}}`),
+	}
+}
+
+func TestCallbackTemplate_Validate(t *testing.T) {
+	Registry[constants.DefaultCallback] = func() Callback { return &HttpCallback{} }
+	Registry[constants.TemplateCallback] = func() Callback { return &TemplateCallback{} }
+
+	for _, test := range []struct {
+		template CallbackTemplate
+		valid    bool
+	}{
+		{httpTemplate("payment-webhook", 1, "http://localhost:8080/payments"), true},
+		{httpTemplate("payment webhook", 1, "http://localhost:8080/payments"), false},
+		{httpTemplate("", 1, "http://localhost:8080/payments"), false},
+		{httpTemplate("payment-webhook", 1, "not a url"), false},
+		{CallbackTemplate{Name: "empty"}, false},
+		{CallbackTemplate{Name: "unknown", Callback: json.RawMessage(`{"type":"unknown"}`)}, false},
+		{CallbackTemplate{Name: "nested", Callback: json.RawMessage(`{"type":"template","details":{"name":"payment-webhook"}}`)}, false},
+	} {
+		if err := test.template.Validate(); (err == nil) != test.valid {
+			t.Errorf("template %s %s: expected valid %v, got %v", test.template.Name, test.template.Callback, test.valid, err)
+		}
+	}
+}
+
+func TestTemplateCallback_Marshal(t *testing.T) {
+	callback := &TemplateCallback{}
+	err := callback.Marshal(map[string]interface{}{
+		"callback_type":    constants.TemplateCallback,
+		"callback_details": `{"name":"payment-webhook","version":3}`,
+	})
+	if err != nil || callback.Details != (TemplateRef{Name: "payment-webhook", Version: 3}) {
+		t.Fatalf("Unexpected callback %+v and error %v", callback, err)
+	}
+
+	details, err := callback.GetDetails()
+	if err != nil || details != `{"name":"payment-webhook","version":3}` {
+		t.Errorf("Unexpected details %s and error %v", details, err)
+	}
+
+	if err = (&TemplateCallback{Details: TemplateRef{Name: "payment-webhook", Version: -1}}).Validate(); err == nil {
+		t.Errorf("Expected a negative version to be invalid")
+	}
+}
+
+func TestResolveTemplate(t *testing.T) {
+	Registry[constants.DefaultCallback] = func() Callback { return &HttpCallback{} }
+	defer SetTemplateStore(nil)
+
+	SetTemplateStore(nil)
+	if _, err := ResolveTemplate("app", TemplateRef{Name: "payment-webhook"}); err != ErrTemplatesUnavailable {
+		t.Fatalf("Expected templates to be unavailable without a store, got %v", err)
+	}
+
+	templateStore := &fakeTemplateStore{templates: map[string][]CallbackTemplate{
+		"app/payment-webhook": {httpTemplate("payment-webhook", 1, "http://localhost/v1")},
+	}}
+	SetTemplateStore(templateStore)
+
+	url := func(ref TemplateRef) string {
+		callback, err := ResolveTemplate("app", ref)
+		if err != nil {
+			t.Fatalf("Unexpected error resolving %+v: %v", ref, err)
+		}
+		return callback.(*HttpCallback).Details.Url
+	}
+
+	if got := url(TemplateRef{Name: "payment-webhook"}); got != "http://localhost/v1" {
+		t.Errorf("Expected the latest version, got %s", got)
+	}
+
+	templateStore.templates["app/payment-webhook"] = append(templateStore.templates["app/payment-webhook"], httpTemplate("payment-webhook", 2, "http://localhost/v2"))
+	if got := url(TemplateRef{Name: "payment-webhook"}); got != "http://localhost/v1" || templateStore.reads != 1 {
+		t.Errorf("Expected the cached version, got %s after %d reads", got, templateStore.reads)
+	}
+
+	InvalidateTemplate("app", "payment-webhook")
+	if got := url(TemplateRef{Name: "payment-webhook"}); got != "http://localhost/v2" {
+		t.Errorf("Expected the update after the invalidation, got %s", got)
+	}
+	if got := url(TemplateRef{Name: "payment-webhook", Version: 1}); got != "http://localhost/v1" {
+		t.Errorf("Expected the pinned version, got %s", got)
+	}
+
+	if _, err := ResolveTemplate("app", TemplateRef{Name: "missing"}); err != gocql.ErrNotFound {
+		t.Errorf("Expected a missing template not to resolve, got %v", err)
+	}
+}
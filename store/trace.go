@@ -0,0 +1,91 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// zeroTraceId is the invalid trace id of the W3C trace context
+const zeroTraceId = "00000000000000000000000000000000"
+
+// NewTraceId generates the random trace id of a schedule, 16 bytes in lowercase hex like a W3C trace id
+func NewTraceId() string {
+	return randomHex(16)
+}
+
+// ValidTraceId checks that the trace id is a W3C trace id: 32 lowercase hex characters, not all zeros
+func ValidTraceId(traceId string) bool {
+	return len(traceId) == 32 && traceId != zeroTraceId && isLowerHex(traceId)
+}
+
+// TraceIdOf returns the trace id of a W3C traceparent header, false if the header is missing or malformed.
+// The header is version-traceId-parentId-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func TraceIdOf(traceParent string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || !isLowerHex(parts[0]) ||
+		len(parts[2]) != 16 || !isLowerHex(parts[2]) {
+		return "", false
+	}
+	if !ValidTraceId(parts[1]) {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// TraceParent returns the W3C traceparent header of a span of the trace, such as a callback attempt, with a new
+// span id. Returns an empty string if the trace id is not valid
+func TraceParent(traceId string) string {
+	if !ValidTraceId(traceId) {
+		return ""
+	}
+	return "00-" + traceId + "-" + randomHex(8) + "-01"
+}
+
+// validateTraceId checks the trace id supplied with a schedule, a schedule created without one is assigned a new one
+func validateTraceId(traceId string) string {
+	if traceId == "" || ValidTraceId(traceId) {
+		return ""
+	}
+	return "traceId should be 32 lowercase hex characters, not all zeros"
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size)
+	for {
+		_, _ = rand.Read(b)
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTraceIdOf(t *testing.T) {
+	for _, test := range []struct {
+		header  string
+		traceId string
+		valid   bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{" 01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra ", "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"", "", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", "", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01", "", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736", "", false},
+	} {
+		traceId, valid := TraceIdOf(test.header)
+		if traceId != test.traceId || valid != test.valid {
+			t.Errorf("TraceIdOf(%q) = %q, %v, want %q, %v", test.header, traceId, valid, test.traceId, test.valid)
+		}
+	}
+}
+
+func TestTraceParent(t *testing.T) {
+	traceId := NewTraceId()
+	if !ValidTraceId(traceId) {
+		t.Fatalf("NewTraceId() = %q is not a valid trace id", traceId)
+	}
+
+	first, second := TraceParent(traceId), TraceParent(traceId)
+	if got, ok := TraceIdOf(first); !ok || got != traceId {
+		t.Errorf("TraceIdOf(%q) = %q, %v, want %q", first, got, ok, traceId)
+	}
+	if !strings.HasPrefix(first, "00-"+traceId+"-") || !strings.HasSuffix(first, "-01") {
+		t.Errorf("TraceParent(%q) = %q, want a sampled version 00 header", traceId, first)
+	}
+	if first == second {
+		t.Errorf("TraceParent(%q) reused the span id %q", traceId, first)
+	}
+	if got := TraceParent("not-a-trace-id"); got != "" {
+		t.Errorf("TraceParent of an invalid trace id = %q, want empty", got)
+	}
+}
+
+func TestSetFieldsTraceId(t *testing.T) {
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	schedule := Schedule{TraceId: traceId}
+	schedule.SetFields(App{AppId: "app", Partitions: 1})
+	if schedule.TraceId != traceId {
+		t.Errorf("supplied trace id was replaced with %q", schedule.TraceId)
+	}
+
+	schedule = Schedule{}
+	schedule.SetFields(App{AppId: "app", Partitions: 1})
+	if !ValidTraceId(schedule.TraceId) {
+		t.Errorf("schedule created without a trace id was assigned %q", schedule.TraceId)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UrlVerificationType is the type of the challenge posted to a callback url being verified
+const UrlVerificationType = "url_verification"
+
+// maxChallengeResponse is the number of bytes of the challenge response which are read
+const maxChallengeResponse = 4096
+
+// UrlVerification is the challenge posted to a callback url being verified, the url has to echo the challenge
+type UrlVerification struct {
+	Type      string `json:"type"`
+	AppId     string `json:"appId"`
+	Challenge string `json:"challenge"`
+}
+
+// VerifiedUrl is a callback url of an app which passed the verification handshake
+type VerifiedUrl struct {
+	AppId      string    `json:"appId"`
+	Url        string    `json:"url"`
+	VerifiedAt time.Time `json:"verifiedAt"`
+}
+
+// VerificationKey returns the url a callback url is verified under, its scheme, host and path.
+// The query of a url is left out, so that a verified endpoint can be called with any parameters.
+func VerificationKey(rawUrl string) (string, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%s is not an absolute http(s) url", rawUrl)
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + path, nil
+}
+
+// NewChallenge returns a random token for the verification of a callback url
+func NewChallenge() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// VerifyUrl posts the challenge to the url and checks that the url echoes it, either as the body of the response
+// or as the challenge field of a json body. Redirects are not followed, the url itself has to answer.
+func VerifyUrl(client *http.Client, appId string, rawUrl string, challenge string) error {
+	body, err := json.Marshal(UrlVerification{Type: UrlVerificationType, AppId: appId, Challenge: challenge})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, rawUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := noRedirects.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("challenge was answered with status %d", resp.StatusCode)
+	}
+
+	answer, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxChallengeResponse))
+	if err != nil {
+		return err
+	}
+	if echoesChallenge(answer, challenge) {
+		return nil
+	}
+	return errors.New("challenge was not echoed by the url")
+}
+
+// echoesChallenge checks whether a challenge response carries the challenge
+func echoesChallenge(answer []byte, challenge string) bool {
+	if strings.TrimSpace(string(answer)) == challenge {
+		return true
+	}
+
+	var echo struct {
+		Challenge string `json:"challenge"`
+	}
+	return json.Unmarshal(answer, &echo) == nil && echo.Challenge == challenge
+}
+
+// CallbackUrls returns the urls a schedule with the callback and status callback delivers to
+func CallbackUrls(callback Callback, statusCallback string) []string {
+	var urls []string
+	switch c := callback.(type) {
+	case *HttpCallback:
+		urls = append(urls, c.Details.Url)
+	case *HTTPCallback:
+		urls = append(urls, c.Url)
+	}
+	if statusCallback != "" {
+		urls = append(urls, statusCallback)
+	}
+	return urls
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVerificationKey(t *testing.T) {
+	for _, test := range []struct {
+		url   string
+		key   string
+		valid bool
+	}{
+		{"http://localhost:8080/callback", "http://localhost:8080/callback", true},
+		{"HTTPS://Example.com/Callback?id=1#run", "https://example.com/Callback", true},
+		{"https://example.com", "https://example.com/", true},
+		{"ftp://example.com/callback", "", false},
+		{"/callback", "", false},
+		{"://bad", "", false},
+	} {
+		key, err := VerificationKey(test.url)
+		if (err == nil) != test.valid || key != test.key {
+			t.Errorf("%s: expected key %q valid %v, got %q and %v", test.url, test.key, test.valid, key, err)
+		}
+	}
+}
+
+func TestVerifyUrl(t *testing.T) {
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		var challenge UrlVerification
+		_ = json.NewDecoder(r.Body).Decode(&challenge)
+		if challenge.Type != UrlVerificationType || challenge.AppId != "app" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(challenge.Challenge))
+	}
+	echoJSON := func(w http.ResponseWriter, r *http.Request) {
+		var challenge UrlVerification
+		_ = json.NewDecoder(r.Body).Decode(&challenge)
+		_ = json.NewEncoder(w).Encode(map[string]string{"challenge": challenge.Challenge})
+	}
+	ignore := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+	fail := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	redirect := httptest.NewServer(http.HandlerFunc(echo))
+	defer redirect.Close()
+	redirecting := func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirect.URL, http.StatusTemporaryRedirect)
+	}
+
+	for name, test := range map[string]struct {
+		handler  http.HandlerFunc
+		verified bool
+	}{
+		"echo":     {echo, true},
+		"json":     {echoJSON, true},
+		"ignore":   {ignore, false},
+		"fail":     {fail, false},
+		"redirect": {redirecting, false},
+	} {
+		server := httptest.NewServer(test.handler)
+		err := VerifyUrl(&http.Client{Timeout: time.Second}, "app", server.URL+"/callback", "token")
+		if (err == nil) != test.verified {
+			t.Errorf("%s: expected verified %v, got %v", name, test.verified, err)
+		}
+		server.Close()
+	}
+}
+
+func TestNewChallenge(t *testing.T) {
+	first, err := NewChallenge()
+	if err != nil || len(first) != 32 {
+		t.Fatalf("Unexpected challenge %q and error %v", first, err)
+	}
+	if second, _ := NewChallenge(); second == first {
+		t.Errorf("Expected distinct challenges, got %s twice", first)
+	}
+}
+
+func TestCallbackUrls(t *testing.T) {
+	for _, test := range []struct {
+		callback       Callback
+		statusCallback string
+		urls           []string
+	}{
+		{&HttpCallback{Details: Details{Url: "http://a/callback"}}, "http://b/status", []string{"http://a/callback", "http://b/status"}},
+		{&HTTPCallback{Url: "http://a/callback"}, "", []string{"http://a/callback"}},
+		{&TemplateCallback{}, "http://b/status", []string{"http://b/status"}},
+		{&TemplateCallback{}, "", nil},
+	} {
+		if urls := CallbackUrls(test.callback, test.statusCallback); !reflect.DeepEqual(urls, test.urls) {
+			t.Errorf("Expected urls %v, got %v", test.urls, urls)
+		}
+	}
+}
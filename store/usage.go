@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Myntra Designs Private Limited.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Usage is the activity of an app over a day, rolled up for chargeback
+type Usage struct {
+	AppId            string    `json:"appId,omitempty"`
+	Day              time.Time `json:"day"`
+	SchedulesCreated int64     `json:"schedulesCreated"`
+	CallbacksFired   int64     `json:"callbacksFired"`
+	BytesDelivered   int64     `json:"bytesDelivered"`
+	Retries          int64     `json:"retries"`
+}
+
+// Add adds the counters of other to the usage
+func (u *Usage) Add(other Usage) {
+	u.SchedulesCreated += other.SchedulesCreated
+	u.CallbacksFired += other.CallbacksFired
+	u.BytesDelivered += other.BytesDelivered
+	u.Retries += other.Retries
+}
+
+// IsZero tells whether none of the counters is set
+func (u Usage) IsZero() bool {
+	return u.SchedulesCreated == 0 && u.CallbacksFired == 0 && u.BytesDelivered == 0 && u.Retries == 0
+}
+
+// UsageDay returns the UTC day of t that usage is rolled up to
+func UsageDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+type usageKey struct {
+	appId string
+	day   time.Time
+}
+
+// UsageCounter accumulates the usage of the apps in memory until it is flushed to the rollup table
+type UsageCounter struct {
+	mu      sync.Mutex
+	pending map[usageKey]*Usage
+}
+
+var usageCounter = NewUsageCounter()
+
+// Usages returns the counter accumulating the usage recorded by the node
+func Usages() *UsageCounter {
+	return usageCounter
+}
+
+func NewUsageCounter() *UsageCounter {
+	return &UsageCounter{pending: map[usageKey]*Usage{}}
+}
+
+// Record adds the usage to the day of t of the app
+func (c *UsageCounter) Record(appId string, t time.Time, usage Usage) {
+	if appId == "" || usage.IsZero() {
+		return
+	}
+
+	key := usageKey{appId, UsageDay(t)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.pending[key]
+	if !ok {
+		pending = &Usage{AppId: appId, Day: key.day}
+		c.pending[key] = pending
+	}
+	pending.Add(usage)
+}
+
+// Drain returns the accumulated usage ordered by app and day, and resets the counter
+func (c *UsageCounter) Drain() []Usage {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = map[usageKey]*Usage{}
+	c.mu.Unlock()
+
+	usages := make([]Usage, 0, len(pending))
+	for _, usage := range pending {
+		usages = append(usages, *usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].AppId != usages[j].AppId {
+			return usages[i].AppId < usages[j].AppId
+		}
+		return usages[i].Day.Before(usages[j].Day)
+	})
+	return usages
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageCounter(t *testing.T) {
+	counter := NewUsageCounter()
+	day := time.Date(2023, 1, 2, 23, 59, 0, 0, time.UTC)
+
+	counter.Record("b", day, Usage{SchedulesCreated: 1})
+	counter.Record("a", day, Usage{CallbacksFired: 1, BytesDelivered: 10, Retries: 2})
+	counter.Record("a", day.Add(time.Minute), Usage{CallbacksFired: 1, BytesDelivered: 5})
+	counter.Record("a", day, Usage{CallbacksFired: 1, BytesDelivered: 20})
+	counter.Record("a", day, Usage{})
+	counter.Record("", day, Usage{SchedulesCreated: 1})
+
+	usages := counter.Drain()
+	if len(usages) != 3 {
+		t.Fatalf("Expected the usage of 3 app days, got %+v", usages)
+	}
+	if usages[0].AppId != "a" || !usages[0].Day.Equal(UsageDay(day)) || usages[0].CallbacksFired != 2 || usages[0].BytesDelivered != 30 || usages[0].Retries != 2 {
+		t.Errorf("Unexpected usage of the first day of a %+v", usages[0])
+	}
+	if usages[1].AppId != "a" || !usages[1].Day.Equal(UsageDay(day).AddDate(0, 0, 1)) || usages[1].CallbacksFired != 1 {
+		t.Errorf("Unexpected usage of the second day of a %+v", usages[1])
+	}
+	if usages[2].AppId != "b" || usages[2].SchedulesCreated != 1 {
+		t.Errorf("Unexpected usage of b %+v", usages[2])
+	}
+
+	if usages = counter.Drain(); len(usages) != 0 {
+		t.Errorf("Expected the counter to be reset by the drain, got %+v", usages)
+	}
+}
+
+func TestUsageDay(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	if day := UsageDay(time.Date(2023, 1, 2, 3, 0, 0, 0, ist)); !day.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the UTC day, got %s", day)
+	}
+}
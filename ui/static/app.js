@@ -0,0 +1,295 @@
+// goscheduler admin dashboard, backed by the service API of the server it is served from
+(function () {
+    "use strict";
+
+    var api = "/goscheduler";
+    var current = null;
+    var page = {};
+
+    function $(id) {
+        return document.getElementById(id);
+    }
+
+    function request(method, path, body) {
+        var options = {method: method, headers: {"Accept": "application/json"}};
+        if (body !== undefined) {
+            options.headers["Content-Type"] = "application/json";
+            options.body = JSON.stringify(body);
+        }
+        return fetch(api + path, options).then(function (response) {
+            return response.json().catch(function () {
+                return {};
+            }).then(function (json) {
+                if (!response.ok) {
+                    var status = json.status || {};
+                    throw new Error(status.statusMessage || response.statusText);
+                }
+                return json;
+            });
+        });
+    }
+
+    function notify(text, isError) {
+        var message = $("message");
+        message.textContent = text;
+        message.className = isError ? "error" : "";
+        message.hidden = !text;
+    }
+
+    function fail(error) {
+        notify(error.message, true);
+    }
+
+    function query(form) {
+        var params = new URLSearchParams();
+        Array.prototype.forEach.call(form.elements, function (element) {
+            if (element.name && element.value && element.name !== "appId") {
+                params.set(element.name, element.value);
+            }
+        });
+        return params;
+    }
+
+    function formatTime(seconds) {
+        return seconds ? new Date(seconds * 1000).toLocaleString() : "";
+    }
+
+    function cell(row, text, className) {
+        var td = document.createElement("td");
+        td.textContent = text === undefined || text === null ? "" : text;
+        if (className) {
+            td.className = className;
+        }
+        row.appendChild(td);
+        return td;
+    }
+
+    function scheduleLink(row, id) {
+        var td = cell(row, "");
+        var link = document.createElement("a");
+        link.textContent = id;
+        link.addEventListener("click", function () {
+            openSchedule(id);
+        });
+        td.appendChild(link);
+    }
+
+    function callbackOf(schedule) {
+        var callback = schedule.callback || {};
+        var details = callback.details || {};
+        return [callback.type, details.method, details.url].filter(Boolean).join(" ");
+    }
+
+    function recurrenceOf(schedule) {
+        return schedule.cronExpression || (schedule.every && "every " + schedule.every) || schedule.rrule || "";
+    }
+
+    function scheduleRows(tbody, schedules, append) {
+        if (!append) {
+            tbody.innerHTML = "";
+        }
+        (schedules || []).forEach(function (schedule) {
+            var row = document.createElement("tr");
+            scheduleLink(row, schedule.scheduleId);
+            cell(row, formatTime(schedule.scheduleTime));
+            cell(row, callbackOf(schedule));
+            cell(row, schedule.status, "status-" + schedule.status);
+            cell(row, schedule.errorMessage);
+            tbody.appendChild(row);
+        });
+    }
+
+    function show(view) {
+        Array.prototype.forEach.call(document.querySelectorAll(".view"), function (section) {
+            section.hidden = section.id !== view;
+        });
+        Array.prototype.forEach.call(document.querySelectorAll("nav a"), function (link) {
+            link.className = link.getAttribute("data-view") === view ? "active" : "";
+        });
+        notify("");
+    }
+
+    function loadApps() {
+        request("GET", "/apps").then(function (json) {
+            var tbody = $("apps-rows");
+            tbody.innerHTML = "";
+            ((json.data || {}).apps || []).forEach(function (app) {
+                var row = document.createElement("tr");
+                cell(row, app.appId);
+                cell(row, app.partitions);
+                cell(row, app.active ? "yes" : "no");
+                var td = cell(row, "");
+                var link = document.createElement("a");
+                link.textContent = "Schedules";
+                link.addEventListener("click", function () {
+                    $("schedules-form").elements.appId.value = app.appId;
+                    location.hash = "schedules";
+                    searchSchedules(false);
+                });
+                td.appendChild(link);
+                tbody.appendChild(row);
+            });
+        }).catch(fail);
+    }
+
+    function searchSchedules(more) {
+        var form = $("schedules-form");
+        var params = query(form);
+        if (more && page.token) {
+            params.set("continuation_token", page.token);
+            params.set("continuation_start_time", page.startTime);
+        }
+        request("GET", "/apps/" + encodeURIComponent(form.elements.appId.value) + "/schedules?" + params).then(function (json) {
+            var data = json.data || {};
+            scheduleRows($("schedules-rows"), data.schedules, more);
+            page = {token: data.continuationToken, startTime: data.continuationStartTime};
+            $("schedules-more").hidden = !data.continuationToken;
+        }).catch(fail);
+    }
+
+    function openSchedule(id) {
+        request("GET", "/schedules/" + encodeURIComponent(id)).then(function (json) {
+            current = (json.data || {}).schedule;
+            location.hash = "schedule";
+            show("schedule");
+            $("schedule-id").textContent = current.scheduleId;
+            $("schedule-details").textContent = JSON.stringify(current, null, 2);
+            var recurring = Boolean(recurrenceOf(current));
+            $("pause").hidden = !recurring || current.status !== "SCHEDULED";
+            $("resume").hidden = !recurring || current.status !== "PAUSED";
+            $("runs-when").parentNode.hidden = !recurring;
+            if (recurring) {
+                loadRuns();
+            } else {
+                $("runs-rows").innerHTML = "";
+            }
+        }).catch(fail);
+    }
+
+    function loadRuns() {
+        var when = $("runs-when").value;
+        request("GET", "/schedules/" + current.scheduleId + "/runs?size=50&when=" + when).then(function (json) {
+            var tbody = $("runs-rows");
+            tbody.innerHTML = "";
+            ((json.data || {}).schedules || []).forEach(function (run) {
+                var row = document.createElement("tr");
+                scheduleLink(row, run.scheduleId);
+                cell(row, formatTime(run.scheduleTime));
+                cell(row, run.status, "status-" + run.status);
+                cell(row, run.errorMessage);
+                tbody.appendChild(row);
+            });
+        }).catch(fail);
+    }
+
+    function action(method, suffix, confirmation) {
+        if (!current || (confirmation && !window.confirm(confirmation))) {
+            return;
+        }
+        request(method, "/schedules/" + current.scheduleId + suffix).then(function (json) {
+            var id = current.scheduleId;
+            openSchedule(id);
+            notify((json.status || {}).statusMessage || "Done");
+        }).catch(fail);
+    }
+
+    function searchCrons(event) {
+        event.preventDefault();
+        request("GET", "/crons/schedules?" + query($("crons-form"))).then(function (json) {
+            var tbody = $("crons-rows");
+            tbody.innerHTML = "";
+            (json.data || []).forEach(function (schedule) {
+                var row = document.createElement("tr");
+                scheduleLink(row, schedule.scheduleId);
+                cell(row, schedule.appId);
+                cell(row, recurrenceOf(schedule));
+                cell(row, schedule.status, "status-" + schedule.status);
+                tbody.appendChild(row);
+            });
+        }).catch(fail);
+    }
+
+    function preview(event) {
+        event.preventDefault();
+        var form = $("preview-form");
+        var schedule = {
+            appId: form.elements.appId.value,
+            payload: "{}",
+            callback: {type: "http", details: {url: "http://localhost", method: "GET"}}
+        };
+        schedule[form.elements.kind.value] = form.elements.expression.value;
+        request("POST", "/schedules/validate?count=" + form.elements.count.value, schedule).then(function (json) {
+            var list = $("preview-runs");
+            list.innerHTML = "";
+            ((json.data || {}).nextRuns || []).forEach(function (run) {
+                var item = document.createElement("li");
+                item.textContent = formatTime(run);
+                list.appendChild(item);
+            });
+            notify("");
+        }).catch(fail);
+    }
+
+    function failures(event) {
+        event.preventDefault();
+        var form = $("failures-form");
+        var params = query(form);
+        params.set("status", "FAILURE");
+        params.set("size", "100");
+        request("GET", "/apps/" + encodeURIComponent(form.elements.appId.value) + "/schedules?" + params).then(function (json) {
+            scheduleRows($("failures-rows"), (json.data || {}).schedules, false);
+        }).catch(fail);
+    }
+
+    function redrive() {
+        var form = $("failures-form");
+        if (!form.reportValidity() || !window.confirm("Fire all the failed schedules of the app in the time range again?")) {
+            return;
+        }
+        var params = query(form);
+        params.set("status", "FAILURE");
+        request("POST", "/apps/" + encodeURIComponent(form.elements.appId.value) + "/bulk-action/reconcile?" + params).then(function (json) {
+            notify(json.remarks || "Re-drive initiated");
+        }).catch(fail);
+    }
+
+    function route() {
+        var view = location.hash.slice(1) || "apps";
+        if (view === "schedule" && !current) {
+            view = "schedules";
+        }
+        show(view);
+        if (view === "apps") {
+            loadApps();
+        }
+    }
+
+    $("schedules-form").addEventListener("submit", function (event) {
+        event.preventDefault();
+        searchSchedules(false);
+    });
+    $("schedules-more").addEventListener("click", function () {
+        searchSchedules(true);
+    });
+    $("schedule-form").addEventListener("submit", function (event) {
+        event.preventDefault();
+        openSchedule(event.target.elements.scheduleId.value.trim());
+    });
+    $("runs-when").addEventListener("change", loadRuns);
+    $("pause").addEventListener("click", function () {
+        action("PUT", "/pause");
+    });
+    $("resume").addEventListener("click", function () {
+        action("PUT", "/resume");
+    });
+    $("delete").addEventListener("click", function () {
+        action("DELETE", "", "Delete this schedule?");
+    });
+    $("crons-form").addEventListener("submit", searchCrons);
+    $("preview-form").addEventListener("submit", preview);
+    $("failures-form").addEventListener("submit", failures);
+    $("redrive").addEventListener("click", redrive);
+    window.addEventListener("hashchange", route);
+
+    route();
+})();
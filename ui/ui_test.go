@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	handler := Handler()
+
+	for _, test := range []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{path: Path, status: http.StatusOK, contentType: "text/html"},
+		{path: Path + "app.js", status: http.StatusOK, contentType: "javascript"},
+		{path: Path + "style.css", status: http.StatusOK, contentType: "text/css"},
+		{path: Path + "missing.js", status: http.StatusNotFound},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			rr.Header().Set("Content-Type", "application/json")
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if rr.Code != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, rr.Code)
+			}
+			if contentType := rr.Header().Get("Content-Type"); !strings.Contains(contentType, test.contentType) {
+				t.Errorf("expected content type %s, got %s", test.contentType, contentType)
+			}
+		})
+	}
+}